@@ -69,6 +69,13 @@ type BGPPeerSpec struct {
 	// +optional
 	PeerIP string `json:"peerIP,omitempty" validate:"omitempty,IP:port"`
 
+	// The DNS name of the peer followed by an optional port number to peer with, in the format
+	// `<name>` or `<name>:<port>`.  calico/node peers with every address that the name resolves
+	// to, and re-resolves the name periodically so that the peerings follow the peer when its
+	// addresses change.  When this is set, the PeerIP and PeerSelector fields must be empty.
+	// +optional
+	PeerHostname string `json:"peerHostname,omitempty" validate:"omitempty,hostname:port"`
+
 	// The AS Number of the peer.
 	// +optional
 	ASNumber numorstring.ASNumber `json:"asNumber,omitempty"`
@@ -36,6 +36,10 @@ const (
 	// and may be used for label matches by Policy selectors.
 	LabelServiceAccount = "projectcalico.org/serviceaccount"
 
+	// Label used to denote the zone of a host endpoint.  This is added to host endpoints by Calico
+	// and may be used for label matches by Policy selectors.
+	LabelZone = "projectcalico.org/zone"
+
 	// Label used to denote the Orchestrator.  This is added to the workload endpoints by an
 	// orchestrator.
 	LabelOrchestrator = "projectcalico.org/orchestrator"
@@ -77,6 +77,22 @@ const (
 	BPFHostNetworkedNATDisabled BPFHostNetworkedNATType = "Disabled"
 )
 
+// +kubebuilder:validation:Enum=TC;TCX
+type BPFAttachOption string
+
+const (
+	BPFAttachOptionTC  BPFAttachOption = "TC"
+	BPFAttachOptionTCX BPFAttachOption = "TCX"
+)
+
+// +kubebuilder:validation:Enum=Translate;Drop
+type BPFFragmentHandlingType string
+
+const (
+	BPFFragmentHandlingTranslate BPFFragmentHandlingType = "Translate"
+	BPFFragmentHandlingDrop      BPFFragmentHandlingType = "Drop"
+)
+
 // +kubebuilder:validation:Enum=TCP;Enabled;Disabled
 type BPFConnectTimeLBType string
 
@@ -94,8 +110,50 @@ const (
 	WindowsManageFirewallRulesDisabled WindowsManageFirewallRulesMode = "Disabled"
 )
 
+// +kubebuilder:validation:Enum=None;SourcePort
+type FlowLogsAggregationType string
+
+const (
+	FlowLogsAggregationNone       FlowLogsAggregationType = "None"
+	FlowLogsAggregationSourcePort FlowLogsAggregationType = "SourcePort"
+)
+
+// +kubebuilder:validation:Enum=Retain;Cleanup
+type DataplaneShutdownModeType string
+
+const (
+	DataplaneShutdownModeRetain  DataplaneShutdownModeType = "Retain"
+	DataplaneShutdownModeCleanup DataplaneShutdownModeType = "Cleanup"
+)
+
+// +kubebuilder:validation:Enum=Refuse;Truncate
+type BPFMapShrinkPolicyType string
+
+const (
+	BPFMapShrinkPolicyRefuse   BPFMapShrinkPolicyType = "Refuse"
+	BPFMapShrinkPolicyTruncate BPFMapShrinkPolicyType = "Truncate"
+)
+
+// +kubebuilder:validation:Enum=Full;PolicyOnly;NetworkingOnly
+type FelixOperationMode string
+
+const (
+	FelixOperationModeFull           FelixOperationMode = "Full"
+	FelixOperationModePolicyOnly     FelixOperationMode = "PolicyOnly"
+	FelixOperationModeNetworkingOnly FelixOperationMode = "NetworkingOnly"
+)
+
 // FelixConfigurationSpec contains the values of the Felix configuration.
 type FelixConfigurationSpec struct {
+	// NodeSelector makes this FelixConfiguration an override that only applies to the nodes that match
+	// the selector, for example to size the BPF maps of a pool of nodes differently.  It cannot be set
+	// on the "default" and "node.<nodename>" resources.  The overrides take precedence over the "default"
+	// resource and the "node.<nodename>" resources take precedence over the overrides.  If several
+	// overrides match a node, they apply in the alphabetical order of their names, so the value of a
+	// parameter comes from the last one that sets it.  A FelixConfiguration that is neither named
+	// "default" nor "node.<nodename>" and has no node selector is ignored.
+	NodeSelector string `json:"nodeSelector,omitempty" validate:"omitempty,selector"`
+
 	// UseInternalDataplaneDriver, if true, Felix will use its internal dataplane programming logic.  If false, it
 	// will launch an external dataplane driver and communicate with it over protobuf.
 	UseInternalDataplaneDriver *bool `json:"useInternalDataplaneDriver,omitempty"`
@@ -103,6 +161,15 @@ type FelixConfigurationSpec struct {
 	// is set to false.
 	DataplaneDriver string `json:"dataplaneDriver,omitempty"`
 
+	// OperationMode selects which of its jobs Felix does.
+	// - Full: the default - Felix programs both the pod network and policy.
+	// - PolicyOnly: Felix only enforces policy, on top of the routing of another CNI plugin. Felix
+	// does not program routes, IPIP, VXLAN or WireGuard.
+	// - NetworkingOnly: Felix only programs the pod network. Policy is not enforced, all traffic to
+	// and from workloads is allowed and host endpoints are ignored.
+	// Changing the mode restarts Felix. [Default: Full]
+	OperationMode *FelixOperationMode `json:"operationMode,omitempty" validate:"omitempty,oneof=Full PolicyOnly NetworkingOnly"`
+
 	// DataplaneWatchdogTimeout is the readiness/liveness timeout used for Felix's (internal) dataplane driver.
 	// Increase this value if you experience spurious non-ready or non-live events when Felix is under heavy load.
 	// Decrease the value to get felix to report non-live or non-ready more quickly. [Default: 90s]
@@ -110,6 +177,14 @@ type FelixConfigurationSpec struct {
 	// Deprecated: replaced by the generic HealthTimeoutOverrides.
 	DataplaneWatchdogTimeout *metav1.Duration `json:"dataplaneWatchdogTimeout,omitempty" configv1timescale:"seconds"`
 
+	// DataplaneShutdownMode controls what Felix does with the dataplane when it is terminated.
+	// - Retain: Felix leaves the dataplane programmed, for a fast restart or upgrade.
+	// - Cleanup: Felix removes the BPF programs and maps, the iptables chains and the routes that
+	// it programmed, for a node that is decommissioned.
+	// The mode can also be changed at run time with `calico-node -felix-shutdown-mode`, from a
+	// pre-stop hook for example. [Default: Retain]
+	DataplaneShutdownMode *DataplaneShutdownModeType `json:"dataplaneShutdownMode,omitempty" validate:"omitempty,oneof=Retain Cleanup"`
+
 	// IPv6Support controls whether Felix enables support for IPv6 (if supported by the in-use dataplane).
 	IPv6Support *bool `json:"ipv6Support,omitempty" confignamev1:"Ipv6Support"`
 
@@ -203,6 +278,98 @@ type FelixConfigurationSpec struct {
 	// In most cases this should not need to be changed [Default: 8775].
 	MetadataPort *int `json:"metadataPort,omitempty"`
 
+	// EgressProxyAddress is the IP address of an HTTP proxy that the HTTP(S) egress of the workloads
+	// matching EgressProxySelector is transparently redirected to.  The proxy must support
+	// transparent (intercepted) connections.  Only supported by the iptables dataplane.
+	// [Default: empty, egress is not redirected]
+	EgressProxyAddress string `json:"egressProxyAddress,omitempty" validate:"omitempty,ip"`
+	// EgressProxyPort is the port of the egress proxy. [Default: 3128]
+	EgressProxyPort *int `json:"egressProxyPort,omitempty" validate:"omitempty,gte=1,lte=65535"`
+	// EgressProxySelector selects the workloads whose egress is redirected to the egress proxy.
+	// [Default: empty, no workloads]
+	EgressProxySelector string `json:"egressProxySelector,omitempty" validate:"omitempty,selector"`
+	// EgressProxyDestinationPorts is the list of TCP destination ports of the connections that are
+	// redirected to the egress proxy. [Default: 80,443]
+	EgressProxyDestinationPorts *[]numorstring.Port `json:"egressProxyDestinationPorts,omitempty" validate:"omitempty,dive"`
+	// EgressProxyExcludeCIDRs is a list of destination CIDRs that are never redirected to the egress
+	// proxy, in addition to the IP pools and the addresses of the host.  It should contain the
+	// Kubernetes service cluster IP range.  [Default: empty]
+	EgressProxyExcludeCIDRs *[]string `json:"egressProxyExcludeCIDRs,omitempty" validate:"omitempty,cidrs"`
+
+	// L7ProxySelector selects the workloads whose inbound HTTP connections are transparently
+	// redirected to a node-local L7 proxy, such as Envoy, which enforces HTTP method and path
+	// policy.  L3/L4 policy is still enforced by the BPF programs.  Only supported by the BPF
+	// dataplane. [Default: empty, no workloads]
+	L7ProxySelector string `json:"l7ProxySelector,omitempty" validate:"omitempty,selector"`
+	// L7ProxyPort is the port on which the L7 proxy accepts the redirected (TPROXY) connections.
+	// [Default: 16001]
+	L7ProxyPort *int `json:"l7ProxyPort,omitempty" validate:"omitempty,gte=1,lte=65535"`
+	// L7ProxyDestinationPorts is the list of TCP destination ports of the connections that are
+	// redirected to the L7 proxy. [Default: 80]
+	L7ProxyDestinationPorts *[]numorstring.Port `json:"l7ProxyDestinationPorts,omitempty" validate:"omitempty,dive"`
+	// L7ProxyVerdictSocketPath is the path of the unix socket on which Felix receives the verdicts
+	// of the L7 proxy, as lines of JSON, and reports them as Prometheus metrics.
+	// [Default: /var/run/calico/l7-verdicts.sock]
+	L7ProxyVerdictSocketPath string `json:"l7ProxyVerdictSocketPath,omitempty"`
+
+	// DNSTrustedServers is the list of IPs or CIDRs of the DNS servers whose responses Felix snoops to
+	// learn the IPs of the domains in the Domains field of policy rules.  It should contain the
+	// cluster IP of the cluster DNS service.  Only supported by the iptables dataplane.
+	// [Default: empty, rules with Domains match no traffic]
+	DNSTrustedServers *[]string `json:"dnsTrustedServers,omitempty" validate:"omitempty,cidrs"`
+	// DNSPolicyNFLOGGroup is the NFLOG group that the iptables rules use to pass the responses of the
+	// trusted DNS servers to Felix. [Default: 41]
+	DNSPolicyNFLOGGroup *int `json:"dnsPolicyNFLOGGroup,omitempty" validate:"omitempty,gte=1,lte=65535"`
+	// DNSLogsEnabled controls whether Felix logs which workload resolved which domain name to which
+	// addresses, from the DNS responses that it snoops for domain name policy.  Requires
+	// DNSTrustedServers. [Default: false]
+	DNSLogsEnabled *bool `json:"dnsLogsEnabled,omitempty"`
+	// DNSLogsFilePath is the full path to the file that Felix writes the DNS logs to, as lines of
+	// JSON. [Default: /var/log/calico/dns/dns.log]
+	DNSLogsFilePath string `json:"dnsLogsFilePath,omitempty"`
+	// DNSLogsFileMaxSize is the size in megabytes at which the DNS log file is rotated. [Default: 100]
+	DNSLogsFileMaxSize *int `json:"dnsLogsFileMaxSize,omitempty" validate:"omitempty,gte=1"`
+	// DNSLogsFileMaxFiles is the number of rotated DNS log files that are kept. [Default: 5]
+	DNSLogsFileMaxFiles *int `json:"dnsLogsFileMaxFiles,omitempty" validate:"omitempty,gte=0"`
+	// DNSLogsCollectorAddress is the host:port of a collector that Felix streams the DNS logs to over
+	// TCP, as lines of JSON, in place of writing them to DNSLogsFilePath. [Default: empty]
+	DNSLogsCollectorAddress string `json:"dnsLogsCollectorAddress,omitempty"`
+	// FlowLogsEnabled controls whether Felix aggregates the connections of the local workloads into
+	// flow logs, which record the endpoints, the policy rules that allowed or denied the connections
+	// and their packet and byte counts.  Only supported by the iptables dataplane. [Default: false]
+	FlowLogsEnabled *bool `json:"flowLogsEnabled,omitempty"`
+	// FlowLogsFlushInterval is the period over which the connections are aggregated before the flow
+	// logs are exported. [Default: 300s]
+	FlowLogsFlushInterval *metav1.Duration `json:"flowLogsFlushInterval,omitempty" configv1timescale:"seconds"`
+	// FlowLogsAggregation controls which connections are aggregated into the same flow log.
+	// - None: each connection has its own flow log.
+	// - SourcePort: the connections that only differ by their source port share a flow log.
+	// [Default: SourcePort]
+	FlowLogsAggregation *FlowLogsAggregationType `json:"flowLogsAggregation,omitempty" validate:"omitempty,oneof=None SourcePort"`
+	// FlowLogsSampleRate logs one in every FlowLogsSampleRate connections, to reduce the volume of
+	// flow logs on busy nodes. [Default: 1]
+	FlowLogsSampleRate *int `json:"flowLogsSampleRate,omitempty" validate:"omitempty,gte=1"`
+	// FlowLogsFilePath is the full path to the file that Felix writes the flow logs to, as lines of
+	// JSON. [Default: /var/log/calico/flowlogs/flows.log]
+	FlowLogsFilePath string `json:"flowLogsFilePath,omitempty"`
+	// FlowLogsFileMaxSize is the size in megabytes at which the flow log file is rotated. [Default: 100]
+	FlowLogsFileMaxSize *int `json:"flowLogsFileMaxSize,omitempty" validate:"omitempty,gte=1"`
+	// FlowLogsFileMaxFiles is the number of rotated flow log files that are kept. [Default: 5]
+	FlowLogsFileMaxFiles *int `json:"flowLogsFileMaxFiles,omitempty" validate:"omitempty,gte=0"`
+	// FlowLogsOTLPAddress is the host:port of an OpenTelemetry collector that Felix sends the flow
+	// logs to with the OTLP/gRPC logs service, in place of writing them to FlowLogsFilePath.
+	// [Default: empty]
+	FlowLogsOTLPAddress string `json:"flowLogsOTLPAddress,omitempty"`
+	// FlowLogsNFLOGGroup is the NFLOG group that the iptables policy rules use to report their
+	// verdicts to Felix. [Default: 42]
+	FlowLogsNFLOGGroup *int `json:"flowLogsNFLOGGroup,omitempty" validate:"omitempty,gte=1,lte=65535"`
+	// FlowLogsKTLSAccountingEnabled controls whether Felix detects the sockets of the host that use
+	// kernel TLS (kTLS), marks their connections in the flow logs and counts their traffic from the
+	// sockets where conntrack undercounts it, such as when the NIC runs the TCP connection of a socket
+	// with TLS offload.  Only the sockets in the host network namespace are detected, such as those of
+	// a TLS-terminating proxy in the host network. [Default: false]
+	FlowLogsKTLSAccountingEnabled *bool `json:"flowLogsKTLSAccountingEnabled,omitempty"`
+
 	// OpenstackRegion is the name of the region that a particular Felix belongs to. In a multi-region
 	// Calico/OpenStack deployment, this must be configured somehow for each Felix (here in the datamodel,
 	// or in felix.cfg or the environment on each compute node), and must match the [calico]
@@ -221,6 +388,11 @@ type FelixConfigurationSpec struct {
 	// having values '/^kube/,veth1' will exclude all interfaces that begin with 'kube' and also the interface
 	// 'veth1'. [Default: kube-ipvs0]
 	InterfaceExclude string `json:"interfaceExclude,omitempty"`
+	// VirtualInterfaceHostEndpointsEnabled extends host endpoint policy to the macvlan and ipvlan interfaces
+	// of the host, whose traffic otherwise bypasses the policy of their parent interface. Such an interface is
+	// governed by the host endpoint of its parent interface unless a host endpoint matches it directly and, in
+	// BPF mode, Felix attaches its programs to it when its parent is a data interface. [Default: false]
+	VirtualInterfaceHostEndpointsEnabled *bool `json:"virtualInterfaceHostEndpointsEnabled,omitempty"`
 
 	// ChainInsertMode controls whether Felix hooks the kernel's top-level iptables chains by inserting a rule
 	// at the top of the chain or by appending a rule at the bottom. insert is the safe default since it prevents
@@ -249,6 +421,14 @@ type FelixConfigurationSpec struct {
 	// LogPrefix is the log prefix that Felix uses when rendering LOG rules. [Default: calico-packet]
 	LogPrefix string `json:"logPrefix,omitempty"`
 
+	// PolicyLogFilePath is the full path to the file that Felix writes the packets logged by policy
+	// rules with the File log target to. [Default: /var/log/calico/policy.log]
+	PolicyLogFilePath string `json:"policyLogFilePath,omitempty"`
+
+	// PolicyLogNFLOGGroup is the NFLOG group that the iptables rules use to pass the packets
+	// logged by policy rules with the Syslog, Journal or File log target to Felix. [Default: 40]
+	PolicyLogNFLOGGroup *int `json:"policyLogNFLOGGroup,omitempty" validate:"omitempty,gte=1,lte=65535"`
+
 	// LogFilePath is the full path to the Felix log. Set to none to disable file logging. [Default: /var/log/calico/felix.log]
 	LogFilePath string `json:"logFilePath,omitempty"`
 
@@ -266,6 +446,11 @@ type FelixConfigurationSpec struct {
 	// Only logs from files with names that match the given regular expression are included.  The filter only applies
 	// to Debug level logs.
 	LogDebugFilenameRegex string `json:"logDebugFilenameRegex,omitempty" validate:"omitempty,regexp"`
+	// LogFormat is the format of the logs sent to the stdout and the log file. Logfmt and JSON
+	// are structured formats for log pipelines; see felix/docs/logging.md for the fields.
+	// [Default: Default]
+	// +kubebuilder:validation:Enum=Default;Logfmt;JSON
+	LogFormat string `json:"logFormat,omitempty" validate:"omitempty,oneof=Default Logfmt JSON"`
 
 	// IPIPEnabled overrides whether Felix should configure an IPIP interface on the host. Optional as Felix determines this based on the existing IP pools. [Default: nil (unset)]
 	IPIPEnabled *bool `json:"ipipEnabled,omitempty" confignamev1:"IpInIpEnabled"`
@@ -313,6 +498,20 @@ type FelixConfigurationSpec struct {
 	// [Default: ""]
 	EndpointStatusPathPrefix string `json:"endpointStatusPathPrefix,omitempty"`
 
+	// EndpointStatusConditionsEnabled enables reporting the status of the local workload endpoints
+	// as a condition of their pods, of type projectcalico.org/PolicyProgrammed, so that describing
+	// a pod shows whether Felix has programmed its policy on the node.  Only supported with the
+	// Kubernetes datastore. [Default: false]
+	EndpointStatusConditionsEnabled *bool `json:"endpointStatusConditionsEnabled,omitempty"`
+
+	// NodeInterfacesReportingInterval is the period at which Felix checks the host interfaces of its
+	// node and their addresses, and updates them in its Node resource if they changed.  kube-controllers
+	// generates host endpoints from templates for the reported interfaces.
+	// Set to 0 to disable the reporting. [Default: 30s]
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\\.[0-9]+)?(ms|s|m|h))*$`
+	NodeInterfacesReportingInterval *metav1.Duration `json:"nodeInterfacesReportingInterval,omitempty" configv1timescale:"seconds"`
+
 	// IptablesMarkMask is the mask that Felix selects its IPTables Mark bits from. Should be a 32 bit hexadecimal
 	// number with at least 8 bits set, none of which clash with any other mark bits in use on the system.
 	// [Default: 0xff000000]
@@ -344,6 +543,17 @@ type FelixConfigurationSpec struct {
 	// PrometheusWireGuardMetricsEnabled disables wireguard metrics collection, which the Prometheus client does by default, when
 	// set to false. This reduces the number of metrics reported, reducing Prometheus load. [Default: true]
 	PrometheusWireGuardMetricsEnabled *bool `json:"prometheusWireGuardMetricsEnabled,omitempty"`
+	// PrometheusPolicyMetricsEnabled enables the felix_policy_rule_packets and felix_policy_rule_bytes metrics, which report
+	// how many packets matched each rule of the active policies on this node. In BPF mode, the rules are only counted
+	// when BPFPolicyDebugEnabled is set. [Default: false]
+	PrometheusPolicyMetricsEnabled *bool `json:"prometheusPolicyMetricsEnabled,omitempty"`
+	// PrometheusPolicyVerdictMetricsEnabled enables the felix_policy_verdict_connections metric, which counts the
+	// connections that each policy allowed or denied per local workload endpoint. Only supported in iptables mode. [Default: false]
+	PrometheusPolicyVerdictMetricsEnabled *bool `json:"prometheusPolicyVerdictMetricsEnabled,omitempty"`
+	// PrometheusPolicyVerdictMetricsMaxSeries limits the number of time series of the felix_policy_verdict_connections
+	// metric. Once reached, the connections of further workload endpoints are counted with an endpoint label of "other"
+	// so that the per-namespace totals remain accurate. [Default: 1000]
+	PrometheusPolicyVerdictMetricsMaxSeries *int `json:"prometheusPolicyVerdictMetricsMaxSeries,omitempty"`
 	// FailsafeInboundHostPorts is a list of PortProto struct objects including UDP/TCP/SCTP ports and CIDRs that Felix will
 	// allow incoming traffic to host endpoints on irrespective of the security policy. This is useful to avoid accidentally
 	// cutting off a host with incorrect configuration. For backwards compatibility, if the protocol is not specified,
@@ -359,6 +569,10 @@ type FelixConfigurationSpec struct {
 	// as well as allowing DHCP, DNS, BGP and the Kubernetes API.
 	// [Default: udp:53, udp:67, tcp:179, tcp:2379, tcp:2380, tcp:5473, tcp:6443, tcp:6666, tcp:6667 ]
 	FailsafeOutboundHostPorts *[]ProtoPort `json:"failsafeOutboundHostPorts,omitempty"`
+	// FailsafeNDPEnabled, when true, makes Felix always allow the essential IPv6 neighbor discovery messages (router
+	// and neighbor solicitations and advertisements) to and from host endpoints, irrespective of the security policy.
+	// This avoids cutting off a host's IPv6 connectivity with a default-deny host endpoint policy. [Default: false]
+	FailsafeNDPEnabled *bool `json:"failsafeNDPEnabled,omitempty"`
 
 	// KubeNodePortRanges holds list of port ranges used for service node ports. Only used if felix detects kube-proxy running in ipvs mode.
 	// Felix uses these ranges to separate host and workload traffic. [Default: 30000:32767].
@@ -476,6 +690,23 @@ type FelixConfigurationSpec struct {
 	// in addition to BPFDataIfacePattern. That is, tunnel interfaces not created by Calico, that Calico workload traffic flows
 	// over as well as any interfaces that handle incoming traffic to nodeports and services from outside the cluster.
 	BPFL3IfacePattern string `json:"bpfL3IfacePattern,omitempty" validate:"omitempty,regexp"`
+	// BPFAttachIncludeIfaces is a comma-separated list of interfaces that Felix attaches BPF programs to as host
+	// interfaces even though they do not match BPFDataIfacePattern, for example ipvlan, macvtap or tun devices. The
+	// list supports regular expressions wrapped with '/', as InterfaceExclude does. Changes take effect without
+	// restarting Felix. [Default: unset]
+	BPFAttachIncludeIfaces string `json:"bpfAttachIncludeIfaces,omitempty"`
+	// BPFAttachExcludeIfaces is a comma-separated list of interfaces that Felix does not attach BPF programs to even
+	// though they match BPFDataIfacePattern, BPFL3IfacePattern or the workload interface prefixes. Programs that
+	// are already attached are removed. It takes precedence over BPFAttachIncludeIfaces and supports regular
+	// expressions in the same way. Changes take effect without restarting Felix. [Default: unset]
+	BPFAttachExcludeIfaces string `json:"bpfAttachExcludeIfaces,omitempty"`
+	// BPFHardwareOffloadIfaces is a comma-separated list of interfaces whose NICs Felix asks to run its BPF programs
+	// in hardware, by attaching them to tc with skip_sw. Felix checks that the NIC offloaded each program and falls back
+	// to running it in the kernel otherwise, counting both in the felix_bpf_hw_offload_attaches metric. The NIC and its
+	// driver must support tc BPF offload, as some Mellanox and Netronome NICs do. The programs on these interfaces are
+	// attached to the clsact qdisc even if BPFAttachType is TCX. The list supports regular expressions wrapped with
+	// '/', as InterfaceExclude does. [Default: unset]
+	BPFHardwareOffloadIfaces string `json:"bpfHardwareOffloadIfaces,omitempty"`
 	// BPFConnectTimeLoadBalancingEnabled when in BPF mode, controls whether Felix installs the connection-time load
 	// balancer.  The connect-time load balancer is required for the host to be able to reach Kubernetes services
 	// and it improves the performance of pod-to-service connections.  The only reason to disable it is for debugging
@@ -517,6 +748,20 @@ type FelixConfigurationSpec struct {
 	// kube-proxy always accepts endpoint slices. This option will be removed in
 	// the next release.
 	BPFKubeProxyEndpointSlicesEnabled *bool `json:"bpfKubeProxyEndpointSlicesEnabled,omitempty" validate:"omitempty"`
+	// BPFKubeProxyHealthzBindAddress, in BPF mode, is the <IP>:<port> on which Felix serves kube-proxy compatible
+	// /healthz and /livez endpoints, which report whether Felix's kube-proxy replacement keeps the dataplane in sync
+	// with the services. Set it to kube-proxy's default, 0.0.0.0:10256, to keep load balancers that health check
+	// kube-proxy working. If empty, the endpoints are not served. [Default: empty]
+	BPFKubeProxyHealthzBindAddress string `json:"bpfKubeProxyHealthzBindAddress,omitempty" validate:"omitempty,IP:port"`
+	// BPFKubeProxyMetricsBindAddress, in BPF mode, is the <IP>:<port> on which Felix serves the kube-proxy metrics
+	// of its kube-proxy replacement on /metrics, for example kube-proxy's default, 127.0.0.1:10249. If empty, the
+	// metrics are not served. [Default: empty]
+	BPFKubeProxyMetricsBindAddress string `json:"bpfKubeProxyMetricsBindAddress,omitempty" validate:"omitempty,IP:port"`
+	// BPFNodePortLinkLocalEnabled, in BPF mode, controls whether link-local IPv6 addresses of the host are also used
+	// as NodePort addresses. By default, only the global unicast addresses of the host are used because a link-local
+	// address is only reachable on its own link and the same address may be present on several interfaces.
+	// [Default: false]
+	BPFNodePortLinkLocalEnabled *bool `json:"bpfNodePortLinkLocalEnabled,omitempty" validate:"omitempty"`
 	// BPFPSNATPorts sets the range from which we randomly pick a port if there is a source port
 	// collision. This should be within the ephemeral range as defined by RFC 6056 (1024–65535) and
 	// preferably outside the  ephemeral ranges used by common operating systems. Linux uses
@@ -540,6 +785,11 @@ type FelixConfigurationSpec struct {
 	// BPFMapSizeConntrack sets the size for the conntrack map.  This map must be large enough to hold
 	// an entry for each active connection.  Warning: changing the size of the conntrack map can cause disruption.
 	BPFMapSizeConntrack *int `json:"bpfMapSizeConntrack,omitempty"`
+	// BPFConntrackShards sets the number of maps that the conntrack table is split into.  Flows are distributed
+	// among the maps by a hash of their key so that very large tables do not contend on a single map.  Each of
+	// the maps has the size set by BPFMapSizeConntrack.  Warning: changing the number of shards moves the
+	// existing entries between the maps and can cause disruption. [Default: 1]
+	BPFConntrackShards *int `json:"bpfConntrackShards,omitempty" validate:"omitempty,gte=1,lte=8"`
 	// BPFMapSizeIPSets sets the size for ipsets map.  The IP sets map must be large enough to hold an entry
 	// for each endpoint matched by every selector in the source/destination matches in network policy.  Selectors
 	// such as "all()" can result in large numbers of entries (one entry per endpoint in that case).
@@ -547,6 +797,19 @@ type FelixConfigurationSpec struct {
 	// BPFMapSizeIfState sets the size for ifstate map.  The ifstate map must be large enough to hold an entry
 	// for each device (host + workloads) on a host.
 	BPFMapSizeIfState *int `json:"bpfMapSizeIfState,omitempty"`
+	// BPFMemoryBudgetMB sets a budget, in MiB, for the estimated kernel memory of the BPF maps plus the Felix heap.
+	// If the maps don't fit in the budget with their configured sizes, Felix shrinks the conntrack map, down to
+	// 16384 entries, and reports that it is degraded; if they still don't fit, Felix refuses to start. This prevents
+	// map size settings from running the node out of memory. 0 disables the budget. [Default: 0]
+	BPFMemoryBudgetMB *int `json:"bpfMemoryBudgetMB,omitempty" validate:"omitempty,gte=0"`
+	// BPFMapShrinkPolicy controls what Felix does when the size of a BPF map, such as the conntrack, NAT or
+	// IP sets map, is reduced below the number of entries that the map holds, for example when a smaller size
+	// is rolled out to a pool of nodes with a nodeSelector override.
+	// - Refuse: Felix keeps the current size of the map, with all its entries, and logs a warning; the new
+	// size applies once the map holds few enough entries and Felix restarts.
+	// - Truncate: Felix resizes the map and drops the entries that don't fit, which can disrupt connections.
+	// [Default: Refuse]
+	BPFMapShrinkPolicy *BPFMapShrinkPolicyType `json:"bpfMapShrinkPolicy,omitempty" validate:"omitempty,oneof=Refuse Truncate"`
 	// BPFHostConntrackBypass Controls whether to bypass Linux conntrack in BPF mode for
 	// workloads and services. [Default: true - bypass Linux conntrack]
 	BPFHostConntrackBypass *bool `json:"bpfHostConntrackBypass,omitempty"`
@@ -555,9 +818,41 @@ type FelixConfigurationSpec struct {
 	// or Loose. [Default: Loose]
 	// +kubebuilder:validation:Pattern=`^(?i)(Disabled|Strict|Loose)?$`
 	BPFEnforceRPF string `json:"bpfEnforceRPF,omitempty"`
+	// BPFAttachType controls how are the BPF programs at the network interfaces attached.
+	// By default `TCX` is used where available to enable easier coexistence with 3rd party programs.
+	// `TC` can force the legacy method of attaching via a qdisc. `TCX` falls back to `TC` if `TCX` is
+	// not available. [Default: TCX]
+	BPFAttachType *BPFAttachOption `json:"bpfAttachType,omitempty" validate:"omitempty,oneof=TC TCX"`
+	// BPFFragmentHandling controls how the BPF programs handle IPv4 fragments. Only the first
+	// fragment of a datagram carries the ports. With `Translate`, the ports of the first fragment
+	// are remembered so that the rest of the fragments are conntracked and NATed consistently;
+	// non-first fragments that arrive before the first one are dropped. With `Drop`, all fragments
+	// are dropped. Dropped fragments are counted by the "IP fragments" counter. [Default: Translate]
+	BPFFragmentHandling *BPFFragmentHandlingType `json:"bpfFragmentHandling,omitempty" validate:"omitempty,oneof=Translate Drop"`
+	// BPFNAT64Enabled enables stateful NAT64 in the BPF dataplane so that IPv6-only pods, whether
+	// in an IPv6-only or a dual-stack cluster, can reach IPv4 destinations. Traffic from pods to addresses in the BPFNAT64Prefix is
+	// translated to IPv4 and masqueraded to the IPv4 address of the node; replies are translated
+	// back. Requires BPFIpv6Enabled and an IPv4 address on the node. [Default: false]
+	BPFNAT64Enabled *bool `json:"bpfNAT64Enabled,omitempty"`
+	// BPFNAT64Prefix is the /96 IPv6 prefix that embeds IPv4 destinations when BPFNAT64Enabled
+	// is set. It must match the prefix that DNS64 synthesizes addresses in. [Default: 64:ff9b::/96]
+	BPFNAT64Prefix string `json:"bpfNAT64Prefix,omitempty" validate:"omitempty,netv6"`
+	// BPFRoutesMirrorTableIndex, when non-zero, makes Felix mirror the content of the BPF routes map into
+	// the kernel routing table with this index, so that Calico's view of the workload and host routes can be
+	// inspected with `ip route show table <index>`. Local workload routes point at the workload interface,
+	// blackhole routes are shown as blackhole or prohibit routes and all other routes as throw routes.
+	// No routing rule refers to the table so it is not used for forwarding. The index must not be in use on
+	// the host, nor be in RouteTableRanges; add a name for it in /etc/iproute2/rt_tables.d to refer to it by name.
+	// [Default: 0, disabled]
+	BPFRoutesMirrorTableIndex *int `json:"bpfRoutesMirrorTableIndex,omitempty" validate:"omitempty,gte=0,lte=4294967295"`
 	// BPFPolicyDebugEnabled when true, Felix records detailed information
 	// about the BPF policy programs, which can be examined with the calico-bpf command-line tool.
 	BPFPolicyDebugEnabled *bool `json:"bpfPolicyDebugEnabled,omitempty"`
+	// BPFPolicyBitmapsEnabled enables a policy performance profile for very large numbers of
+	// policies. Felix gives each active policy a bit and precomputes, for each endpoint, a bitmap
+	// of the policies that apply to it. The bitmaps are shared with the BPF programs so that the
+	// set of policies of a packet's endpoint is resolved with a single lookup. [Default: false]
+	BPFPolicyBitmapsEnabled *bool `json:"bpfPolicyBitmapsEnabled,omitempty"`
 	// BPFForceTrackPacketsFromIfaces in BPF mode, forces traffic from these interfaces
 	// to skip Calico's iptables NOTRACK rule, allowing traffic from those interfaces to be
 	// tracked by Linux conntrack.  Should only be used for interfaces that are not used for
@@ -571,6 +866,24 @@ type FelixConfigurationSpec struct {
 	// resolution so that host can handle them. A typical usecase is node local
 	// DNS cache.
 	BPFExcludeCIDRsFromNAT *[]string `json:"bpfExcludeCIDRsFromNAT,omitempty" validate:"omitempty,cidrs"`
+	// BPFNodePortAddresses is a list of CIDRs that restricts the node IPs that NodePorts are reachable on in BPF
+	// mode, like the nodePortAddresses option of kube-proxy.  NodePorts are only reachable on the IPs of the
+	// host within the CIDRs.  If empty, NodePorts are reachable on all the IPs of the host. [Default: empty]
+	BPFNodePortAddresses *[]string `json:"bpfNodePortAddresses,omitempty" validate:"omitempty,cidrs"`
+	// BPFServiceBackendsSoftLimit is the number of backends of a single service above which Felix logs a warning
+	// and reports the service in the felix_bpf_kube_proxy_services_over_limit metric. Zero means no limit.
+	// [Default: 0]
+	BPFServiceBackendsSoftLimit *int `json:"bpfServiceBackendsSoftLimit,omitempty" validate:"omitempty,gte=0"`
+	// BPFServiceBackendsHardLimit is the maximum number of backends that Felix programs for a single service,
+	// local backends are preferred. It prevents a service with a huge number of endpoints from using up the
+	// NAT backend map. Zero means no limit. [Default: 0]
+	BPFServiceBackendsHardLimit *int `json:"bpfServiceBackendsHardLimit,omitempty" validate:"omitempty,gte=0"`
+	// BPFServiceFrontendsSoftLimit is the number of frontends derived from a single service (load balancer IPs,
+	// external IPs and node ports) above which Felix logs a warning. Zero means no limit. [Default: 0]
+	BPFServiceFrontendsSoftLimit *int `json:"bpfServiceFrontendsSoftLimit,omitempty" validate:"omitempty,gte=0"`
+	// BPFServiceFrontendsHardLimit is the maximum number of frontends derived from a single service (load
+	// balancer IPs, external IPs and node ports) that Felix programs. Zero means no limit. [Default: 0]
+	BPFServiceFrontendsHardLimit *int `json:"bpfServiceFrontendsHardLimit,omitempty" validate:"omitempty,gte=0"`
 
 	// RouteSource configures where Felix gets its routing information.
 	// - WorkloadIPs: use workload endpoints to construct routes.
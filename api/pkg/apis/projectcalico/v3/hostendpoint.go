@@ -82,6 +82,11 @@ type HostEndpointSpec struct {
 	Profiles []string `json:"profiles,omitempty" validate:"omitempty,dive,name"`
 	// Ports contains the endpoint's named ports, which may be referenced in security policy rules.
 	Ports []EndpointPort `json:"ports,omitempty" validate:"dive"`
+	// The security zone of the endpoint, for example "dmz", "storage" or "management".  Calico adds
+	// the label "projectcalico.org/zone" with the zone to the endpoint, so that policy can select all
+	// the endpoints of a zone, across hosts and interfaces, with a selector like
+	// "projectcalico.org/zone == 'dmz'".  The zone must be a valid label value.
+	Zone string `json:"zone,omitempty"`
 }
 
 type EndpointPort struct {
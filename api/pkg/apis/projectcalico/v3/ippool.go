@@ -74,6 +74,12 @@ type IPPoolSpec struct {
 	// Allows IPPool to allocate for a specific node by label selector.
 	NodeSelector string `json:"nodeSelector,omitempty" validate:"omitempty,selector"`
 
+	// Allows IPPool to allocate for the pods of specific namespaces by label selector, for example
+	// "projectcalico.org/name == 'tenant-a'". The pools that select a namespace are the default pools
+	// of its pods, which allocate from them instead of from the pools without a namespace selector.
+	// Other allocations only use a pool with a namespace selector if they request it.
+	NamespaceSelector string `json:"namespaceSelector,omitempty" validate:"omitempty,selector"`
+
 	// Deprecated: this field is only used for APIv1 backwards compatibility.
 	// Setting this field is not allowed, this field is for internal use only.
 	IPIP *IPIPConfiguration `json:"ipip,omitempty" validate:"omitempty,mustBeNil"`
@@ -85,6 +91,13 @@ type IPPoolSpec struct {
 	// AllowedUse controls what the IP pool will be used for.  If not specified or empty, defaults to
 	// ["Tunnel", "Workload"] for back-compatibility
 	AllowedUses []IPPoolAllowedUse `json:"allowedUses,omitempty" validate:"omitempty"`
+
+	// EgressSNATSelector selects the workloads whose NAT-outgoing traffic is source NATed to the address
+	// from this pool that is configured on their node, instead of the node's main address. Namespaces
+	// are selected with the "projectcalico.org/namespace" label of the workloads, for example
+	// "projectcalico.org/namespace in {'tenant-a', 'tenant-b'}". Only valid for pools whose allowedUses
+	// are ["EgressSNAT"].
+	EgressSNATSelector string `json:"egressSNATSelector,omitempty" validate:"omitempty,selector"`
 }
 
 type IPPoolAllowedUse string
@@ -92,6 +105,10 @@ type IPPoolAllowedUse string
 const (
 	IPPoolAllowedUseWorkload IPPoolAllowedUse = "Workload"
 	IPPoolAllowedUseTunnel   IPPoolAllowedUse = "Tunnel"
+	// IPPoolAllowedUseEgressSNAT marks a pool of addresses that are configured on the nodes to source NAT
+	// the traffic of the workloads selected by the pool's egressSNATSelector.  Such pools are not used
+	// by IPAM.
+	IPPoolAllowedUseEgressSNAT IPPoolAllowedUse = "EgressSNAT"
 )
 
 type VXLANMode string
@@ -102,11 +102,49 @@ type NodeControllerConfig struct {
 	// Set to 0 to disable IP garbage collection. [Default: 15m]
 	// +optional
 	LeakGracePeriod *metav1.Duration `json:"leakGracePeriod,omitempty"`
+
+	// PodCIDRPools controls whether to create an IP pool for the podCIDRs of each Kubernetes node, so that
+	// the node's pods are assigned addresses from the range that the cloud provider routes to it.
+	// [Default: Disabled]
+	PodCIDRPools string `json:"podCIDRPools,omitempty" validate:"omitempty,oneof=Enabled Disabled"`
 }
 
 type AutoHostEndpointConfig struct {
 	// AutoCreate enables automatic creation of host endpoints for every node. [Default: Disabled]
 	AutoCreate string `json:"autoCreate,omitempty" validate:"omitempty,oneof=Enabled Disabled"`
+
+	// CreateDefaultHostEndpoint controls whether the host endpoint that covers all the interfaces of
+	// each node is created, when AutoCreate is enabled.  Disable it to only create the host endpoints
+	// of the templates. [Default: Enabled]
+	CreateDefaultHostEndpoint string `json:"createDefaultHostEndpoint,omitempty" validate:"omitempty,oneof=Enabled Disabled"`
+
+	// Templates create a host endpoint for each interface of a node that matches one of them, when
+	// AutoCreate is enabled.  The host endpoints follow the interfaces that Felix reports on each node
+	// and the labels of the node.
+	Templates []AutoHostEndpointTemplate `json:"templates,omitempty" validate:"omitempty,dive"`
+}
+
+// AutoHostEndpointTemplate describes the host endpoints to create for some of the interfaces of
+// some of the nodes.
+type AutoHostEndpointTemplate struct {
+	// GenerateName is the part of the names of the host endpoints of the template that distinguishes
+	// them from those of other templates.  The host endpoints are named
+	// <node name>-<generate name>-<interface name>.
+	GenerateName string `json:"generateName" validate:"required,name"`
+
+	// InterfacePattern is a regular expression that the names of the interfaces must match.  If empty,
+	// all the interfaces match.
+	InterfacePattern string `json:"interfacePattern,omitempty" validate:"omitempty,regexp"`
+
+	// InterfaceCIDRs restricts the template to the interfaces that have an address in one of the
+	// CIDRs.  If empty, the interfaces are not filtered by address.
+	InterfaceCIDRs []string `json:"interfaceCIDRs,omitempty" validate:"omitempty,dive,cidr"`
+
+	// NodeSelector selects the nodes that the template applies to. [Default: all()]
+	NodeSelector string `json:"nodeSelector,omitempty" validate:"omitempty,selector"`
+
+	// Labels are added to the labels of the host endpoints, which also get the labels of the node.
+	Labels map[string]string `json:"labels,omitempty" validate:"omitempty,labels"`
 }
 
 // PolicyControllerConfig configures the network policy controller, which syncs Kubernetes policies
@@ -49,10 +49,24 @@ type Rule struct {
 	// code of ICMP traffic.  This should only be specified if the Protocol field is set to
 	// "ICMP" or "ICMPv6".
 	ICMP *ICMPFields `json:"icmp,omitempty" validate:"omitempty"`
+	// ICMPs is an optional field that restricts the rule to apply to any of several types,
+	// or types and codes, of ICMP traffic; for example, to the ICMPv6 neighbor discovery
+	// messages.  As for ICMP, this should only be specified if the Protocol field is set to
+	// "ICMP" or "ICMPv6", and it cannot be combined with the ICMP field.
+	ICMPs []ICMPFields `json:"icmps,omitempty" validate:"omitempty,dive"`
 	// NotProtocol is the negated version of the Protocol field.
 	NotProtocol *numorstring.Protocol `json:"notProtocol,omitempty" validate:"omitempty"`
 	// NotICMP is the negated version of the ICMP field.
 	NotICMP *ICMPFields `json:"notICMP,omitempty" validate:"omitempty"`
+	// DSCP is an optional field that restricts the rule to only apply to traffic with a
+	// specific Differentiated Services Code Point in its IP header, for example traffic that
+	// a router or another CNI has classified.
+	//
+	// Must be an integer in the range 0-63 or one of the standard names, such as "EF",
+	// "AF11" or "CS1".
+	DSCP *numorstring.DSCP `json:"dscp,omitempty" validate:"omitempty"`
+	// NotDSCP is the negated version of the DSCP field.
+	NotDSCP *numorstring.DSCP `json:"notDSCP,omitempty" validate:"omitempty"`
 	// Source contains the match criteria that apply to source entity.
 	Source EntityRule `json:"source,omitempty" validate:"omitempty"`
 	// Destination contains the match criteria that apply to destination entity.
@@ -61,6 +75,10 @@ type Rule struct {
 	// HTTP contains match criteria that apply to HTTP requests.
 	HTTP *HTTPMatch `json:"http,omitempty" validate:"omitempty"`
 
+	// Log configures where the packets that match the rule are logged, and how many of them.
+	// It may only be set if the Action is Log.
+	Log *RuleLog `json:"log,omitempty" validate:"omitempty"`
+
 	// Metadata contains additional information for this rule
 	Metadata *RuleMetadata `json:"metadata,omitempty" validate:"omitempty"`
 }
@@ -177,6 +195,18 @@ type EntityRule struct {
 	// ServiceAccounts is an optional field that restricts the rule to only apply to traffic that originates from (or
 	// terminates at) a pod running as a matching service account.
 	ServiceAccounts *ServiceAccountMatch `json:"serviceAccounts,omitempty" validate:"omitempty"`
+
+	// Domains is an optional field, valid for egress Allow rules only, that restricts the rule to
+	// apply only to traffic to one of the given domain names. Each entry is either an exact
+	// domain name, such as `api.github.com`, or a wildcard with a leading `*.`, such as
+	// `*.github.com`, which matches any subdomain (but not `github.com` itself).
+	//
+	// Felix learns the IPs of the domains by snooping the responses of the trusted DNS servers
+	// (see DNSTrustedServers in FelixConfiguration), and keeps them for the TTL of the response.
+	//
+	// Domains cannot be specified on the same rule as Selector, NotSelector, NamespaceSelector,
+	// Nets, NotNets, Services or ServiceAccounts.
+	Domains []string `json:"domains,omitempty" validate:"omitempty,dive,domain"`
 }
 
 type ServiceMatch struct {
@@ -208,6 +238,34 @@ const (
 	Pass  Action = "Pass"
 )
 
+// LogTarget is where a rule with the Log action sends the packets that it logs.
+type LogTarget string
+
+const (
+	LogTargetKernel  LogTarget = "Kernel"
+	LogTargetSyslog  LogTarget = "Syslog"
+	LogTargetJournal LogTarget = "Journal"
+	LogTargetFile    LogTarget = "File"
+)
+
+type RuleLog struct {
+	// Target is where the packets are logged.  Kernel logs them to the kernel log.  Syslog,
+	// Journal and File have Felix log them to the local syslog daemon, to the systemd journal or
+	// to the file set by the PolicyLogFilePath Felix configuration parameter. [Default: Kernel]
+	// +kubebuilder:validation:Enum=Kernel;Syslog;Journal;File
+	Target LogTarget `json:"target,omitempty" validate:"omitempty,oneof=Kernel Syslog Journal File"`
+	// SyslogFacility is the facility of the messages sent to syslog.  It may only be set if
+	// the Target is Syslog. [Default: local0]
+	SyslogFacility string `json:"syslogFacility,omitempty" validate:"omitempty,oneof=kern user mail daemon auth syslog lpr news uucp cron authpriv ftp local0 local1 local2 local3 local4 local5 local6 local7"`
+	// RateLimit is the maximum rate at which the rule logs packets, in packets per second,
+	// minute, hour or day, for example "10/second" or "100/minute".  By default, the rule logs
+	// every packet that it matches.
+	RateLimit string `json:"rateLimit,omitempty" validate:"omitempty,logRateLimit"`
+	// RateLimitBurst is the number of packets that the rule may log in a burst before the
+	// RateLimit applies. [Default: 5]
+	RateLimitBurst *int `json:"rateLimitBurst,omitempty" validate:"omitempty,gt=0"`
+}
+
 type RuleMetadata struct {
 	// Annotations is a set of key value pairs that give extra information about the rule
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -56,6 +56,10 @@ var (
 		&BlockAffinityList{},
 		&BGPFilter{},
 		&BGPFilterList{},
+		&StagedGlobalNetworkPolicy{},
+		&StagedGlobalNetworkPolicyList{},
+		&StagedNetworkPolicy{},
+		&StagedNetworkPolicyList{},
 	}
 )
 
@@ -17,6 +17,13 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoHostEndpointConfig) DeepCopyInto(out *AutoHostEndpointConfig) {
 	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AutoHostEndpointTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -30,6 +37,34 @@ func (in *AutoHostEndpointConfig) DeepCopy() *AutoHostEndpointConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoHostEndpointTemplate) DeepCopyInto(out *AutoHostEndpointTemplate) {
+	*out = *in
+	if in.InterfaceCIDRs != nil {
+		in, out := &in.InterfaceCIDRs, &out.InterfaceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoHostEndpointTemplate.
+func (in *AutoHostEndpointTemplate) DeepCopy() *AutoHostEndpointTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoHostEndpointTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BGPConfiguration) DeepCopyInto(out *BGPConfiguration) {
 	*out = *in
@@ -919,6 +954,11 @@ func (in *EntityRule) DeepCopyInto(out *EntityRule) {
 		*out = new(ServiceAccountMatch)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1000,11 +1040,21 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.OperationMode != nil {
+		in, out := &in.OperationMode, &out.OperationMode
+		*out = new(FelixOperationMode)
+		**out = **in
+	}
 	if in.DataplaneWatchdogTimeout != nil {
 		in, out := &in.DataplaneWatchdogTimeout, &out.DataplaneWatchdogTimeout
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.DataplaneShutdownMode != nil {
+		in, out := &in.DataplaneShutdownMode, &out.DataplaneShutdownMode
+		*out = new(DataplaneShutdownModeType)
+		**out = **in
+	}
 	if in.IPv6Support != nil {
 		in, out := &in.IPv6Support, &out.IPv6Support
 		*out = new(bool)
@@ -1070,6 +1120,122 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.EgressProxyPort != nil {
+		in, out := &in.EgressProxyPort, &out.EgressProxyPort
+		*out = new(int)
+		**out = **in
+	}
+	if in.EgressProxyDestinationPorts != nil {
+		in, out := &in.EgressProxyDestinationPorts, &out.EgressProxyDestinationPorts
+		*out = new([]numorstring.Port)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]numorstring.Port, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.EgressProxyExcludeCIDRs != nil {
+		in, out := &in.EgressProxyExcludeCIDRs, &out.EgressProxyExcludeCIDRs
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.L7ProxyPort != nil {
+		in, out := &in.L7ProxyPort, &out.L7ProxyPort
+		*out = new(int)
+		**out = **in
+	}
+	if in.L7ProxyDestinationPorts != nil {
+		in, out := &in.L7ProxyDestinationPorts, &out.L7ProxyDestinationPorts
+		*out = new([]numorstring.Port)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]numorstring.Port, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.DNSTrustedServers != nil {
+		in, out := &in.DNSTrustedServers, &out.DNSTrustedServers
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.DNSPolicyNFLOGGroup != nil {
+		in, out := &in.DNSPolicyNFLOGGroup, &out.DNSPolicyNFLOGGroup
+		*out = new(int)
+		**out = **in
+	}
+	if in.DNSLogsEnabled != nil {
+		in, out := &in.DNSLogsEnabled, &out.DNSLogsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DNSLogsFileMaxSize != nil {
+		in, out := &in.DNSLogsFileMaxSize, &out.DNSLogsFileMaxSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.DNSLogsFileMaxFiles != nil {
+		in, out := &in.DNSLogsFileMaxFiles, &out.DNSLogsFileMaxFiles
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowLogsEnabled != nil {
+		in, out := &in.FlowLogsEnabled, &out.FlowLogsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FlowLogsFlushInterval != nil {
+		in, out := &in.FlowLogsFlushInterval, &out.FlowLogsFlushInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FlowLogsAggregation != nil {
+		in, out := &in.FlowLogsAggregation, &out.FlowLogsAggregation
+		*out = new(FlowLogsAggregationType)
+		**out = **in
+	}
+	if in.FlowLogsSampleRate != nil {
+		in, out := &in.FlowLogsSampleRate, &out.FlowLogsSampleRate
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowLogsFileMaxSize != nil {
+		in, out := &in.FlowLogsFileMaxSize, &out.FlowLogsFileMaxSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowLogsFileMaxFiles != nil {
+		in, out := &in.FlowLogsFileMaxFiles, &out.FlowLogsFileMaxFiles
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowLogsNFLOGGroup != nil {
+		in, out := &in.FlowLogsNFLOGGroup, &out.FlowLogsNFLOGGroup
+		*out = new(int)
+		**out = **in
+	}
+	if in.FlowLogsKTLSAccountingEnabled != nil {
+		in, out := &in.FlowLogsKTLSAccountingEnabled, &out.FlowLogsKTLSAccountingEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VirtualInterfaceHostEndpointsEnabled != nil {
+		in, out := &in.VirtualInterfaceHostEndpointsEnabled, &out.VirtualInterfaceHostEndpointsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PolicyLogNFLOGGroup != nil {
+		in, out := &in.PolicyLogNFLOGGroup, &out.PolicyLogNFLOGGroup
+		*out = new(int)
+		**out = **in
+	}
 	if in.IPIPEnabled != nil {
 		in, out := &in.IPIPEnabled, &out.IPIPEnabled
 		*out = new(bool)
@@ -1135,6 +1301,16 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.EndpointStatusConditionsEnabled != nil {
+		in, out := &in.EndpointStatusConditionsEnabled, &out.EndpointStatusConditionsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeInterfacesReportingInterval != nil {
+		in, out := &in.NodeInterfacesReportingInterval, &out.NodeInterfacesReportingInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.IptablesMarkMask != nil {
 		in, out := &in.IptablesMarkMask, &out.IptablesMarkMask
 		*out = new(uint32)
@@ -1190,6 +1366,21 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PrometheusPolicyMetricsEnabled != nil {
+		in, out := &in.PrometheusPolicyMetricsEnabled, &out.PrometheusPolicyMetricsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrometheusPolicyVerdictMetricsEnabled != nil {
+		in, out := &in.PrometheusPolicyVerdictMetricsEnabled, &out.PrometheusPolicyVerdictMetricsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrometheusPolicyVerdictMetricsMaxSeries != nil {
+		in, out := &in.PrometheusPolicyVerdictMetricsMaxSeries, &out.PrometheusPolicyVerdictMetricsMaxSeries
+		*out = new(int)
+		**out = **in
+	}
 	if in.FailsafeInboundHostPorts != nil {
 		in, out := &in.FailsafeInboundHostPorts, &out.FailsafeInboundHostPorts
 		*out = new([]ProtoPort)
@@ -1208,6 +1399,11 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 			copy(*out, *in)
 		}
 	}
+	if in.FailsafeNDPEnabled != nil {
+		in, out := &in.FailsafeNDPEnabled, &out.FailsafeNDPEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.KubeNodePortRanges != nil {
 		in, out := &in.KubeNodePortRanges, &out.KubeNodePortRanges
 		*out = new([]numorstring.Port)
@@ -1366,6 +1562,11 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BPFNodePortLinkLocalEnabled != nil {
+		in, out := &in.BPFNodePortLinkLocalEnabled, &out.BPFNodePortLinkLocalEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.BPFPSNATPorts != nil {
 		in, out := &in.BPFPSNATPorts, &out.BPFPSNATPorts
 		*out = new(numorstring.Port)
@@ -1396,6 +1597,11 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.BPFConntrackShards != nil {
+		in, out := &in.BPFConntrackShards, &out.BPFConntrackShards
+		*out = new(int)
+		**out = **in
+	}
 	if in.BPFMapSizeIPSets != nil {
 		in, out := &in.BPFMapSizeIPSets, &out.BPFMapSizeIPSets
 		*out = new(int)
@@ -1406,16 +1612,51 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.BPFMemoryBudgetMB != nil {
+		in, out := &in.BPFMemoryBudgetMB, &out.BPFMemoryBudgetMB
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFMapShrinkPolicy != nil {
+		in, out := &in.BPFMapShrinkPolicy, &out.BPFMapShrinkPolicy
+		*out = new(BPFMapShrinkPolicyType)
+		**out = **in
+	}
 	if in.BPFHostConntrackBypass != nil {
 		in, out := &in.BPFHostConntrackBypass, &out.BPFHostConntrackBypass
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BPFAttachType != nil {
+		in, out := &in.BPFAttachType, &out.BPFAttachType
+		*out = new(BPFAttachOption)
+		**out = **in
+	}
+	if in.BPFFragmentHandling != nil {
+		in, out := &in.BPFFragmentHandling, &out.BPFFragmentHandling
+		*out = new(BPFFragmentHandlingType)
+		**out = **in
+	}
+	if in.BPFNAT64Enabled != nil {
+		in, out := &in.BPFNAT64Enabled, &out.BPFNAT64Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BPFRoutesMirrorTableIndex != nil {
+		in, out := &in.BPFRoutesMirrorTableIndex, &out.BPFRoutesMirrorTableIndex
+		*out = new(int)
+		**out = **in
+	}
 	if in.BPFPolicyDebugEnabled != nil {
 		in, out := &in.BPFPolicyDebugEnabled, &out.BPFPolicyDebugEnabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BPFPolicyBitmapsEnabled != nil {
+		in, out := &in.BPFPolicyBitmapsEnabled, &out.BPFPolicyBitmapsEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.BPFForceTrackPacketsFromIfaces != nil {
 		in, out := &in.BPFForceTrackPacketsFromIfaces, &out.BPFForceTrackPacketsFromIfaces
 		*out = new([]string)
@@ -1434,6 +1675,35 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 			copy(*out, *in)
 		}
 	}
+	if in.BPFNodePortAddresses != nil {
+		in, out := &in.BPFNodePortAddresses, &out.BPFNodePortAddresses
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.BPFServiceBackendsSoftLimit != nil {
+		in, out := &in.BPFServiceBackendsSoftLimit, &out.BPFServiceBackendsSoftLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFServiceBackendsHardLimit != nil {
+		in, out := &in.BPFServiceBackendsHardLimit, &out.BPFServiceBackendsHardLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFServiceFrontendsSoftLimit != nil {
+		in, out := &in.BPFServiceFrontendsSoftLimit, &out.BPFServiceFrontendsSoftLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFServiceFrontendsHardLimit != nil {
+		in, out := &in.BPFServiceFrontendsHardLimit, &out.BPFServiceFrontendsHardLimit
+		*out = new(int)
+		**out = **in
+	}
 	if in.RouteTableRanges != nil {
 		in, out := &in.RouteTableRanges, &out.RouteTableRanges
 		*out = new(RouteTableRanges)
@@ -2492,7 +2762,7 @@ func (in *NodeControllerConfig) DeepCopyInto(out *NodeControllerConfig) {
 	if in.HostEndpoint != nil {
 		in, out := &in.HostEndpoint, &out.HostEndpoint
 		*out = new(AutoHostEndpointConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.LeakGracePeriod != nil {
 		in, out := &in.LeakGracePeriod, &out.LeakGracePeriod
@@ -2737,6 +3007,13 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 		*out = new(ICMPFields)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ICMPs != nil {
+		in, out := &in.ICMPs, &out.ICMPs
+		*out = make([]ICMPFields, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NotProtocol != nil {
 		in, out := &in.NotProtocol, &out.NotProtocol
 		*out = new(numorstring.Protocol)
@@ -2747,6 +3024,16 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 		*out = new(ICMPFields)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DSCP != nil {
+		in, out := &in.DSCP, &out.DSCP
+		*out = new(numorstring.DSCP)
+		**out = **in
+	}
+	if in.NotDSCP != nil {
+		in, out := &in.NotDSCP, &out.NotDSCP
+		*out = new(numorstring.DSCP)
+		**out = **in
+	}
 	in.Source.DeepCopyInto(&out.Source)
 	in.Destination.DeepCopyInto(&out.Destination)
 	if in.HTTP != nil {
@@ -2754,6 +3041,11 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 		*out = new(HTTPMatch)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(RuleLog)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = new(RuleMetadata)
@@ -2772,6 +3064,27 @@ func (in *Rule) DeepCopy() *Rule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleLog) DeepCopyInto(out *RuleLog) {
+	*out = *in
+	if in.RateLimitBurst != nil {
+		in, out := &in.RateLimitBurst, &out.RateLimitBurst
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleLog.
+func (in *RuleLog) DeepCopy() *RuleLog {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuleMetadata) DeepCopyInto(out *RuleMetadata) {
 	*out = *in
@@ -2901,6 +3214,126 @@ func (in *ServiceMatch) DeepCopy() *ServiceMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedGlobalNetworkPolicy) DeepCopyInto(out *StagedGlobalNetworkPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedGlobalNetworkPolicy.
+func (in *StagedGlobalNetworkPolicy) DeepCopy() *StagedGlobalNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedGlobalNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StagedGlobalNetworkPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedGlobalNetworkPolicyList) DeepCopyInto(out *StagedGlobalNetworkPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StagedGlobalNetworkPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedGlobalNetworkPolicyList.
+func (in *StagedGlobalNetworkPolicyList) DeepCopy() *StagedGlobalNetworkPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedGlobalNetworkPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StagedGlobalNetworkPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedNetworkPolicy) DeepCopyInto(out *StagedNetworkPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedNetworkPolicy.
+func (in *StagedNetworkPolicy) DeepCopy() *StagedNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StagedNetworkPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedNetworkPolicyList) DeepCopyInto(out *StagedNetworkPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StagedNetworkPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedNetworkPolicyList.
+func (in *StagedNetworkPolicyList) DeepCopy() *StagedNetworkPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedNetworkPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StagedNetworkPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadEndpointControllerConfig) DeepCopyInto(out *WorkloadEndpointControllerConfig) {
 	*out = *in
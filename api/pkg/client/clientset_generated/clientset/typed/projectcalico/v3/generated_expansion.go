@@ -37,3 +37,7 @@ type NetworkPolicyExpansion interface{}
 type NetworkSetExpansion interface{}
 
 type ProfileExpansion interface{}
+
+type StagedGlobalNetworkPolicyExpansion interface{}
+
+type StagedNetworkPolicyExpansion interface{}
@@ -44,6 +44,10 @@ type Interface interface {
 	NetworkSets() NetworkSetInformer
 	// Profiles returns a ProfileInformer.
 	Profiles() ProfileInformer
+	// StagedGlobalNetworkPolicies returns a StagedGlobalNetworkPolicyInformer.
+	StagedGlobalNetworkPolicies() StagedGlobalNetworkPolicyInformer
+	// StagedNetworkPolicies returns a StagedNetworkPolicyInformer.
+	StagedNetworkPolicies() StagedNetworkPolicyInformer
 }
 
 type version struct {
@@ -141,3 +145,13 @@ func (v *version) NetworkSets() NetworkSetInformer {
 func (v *version) Profiles() ProfileInformer {
 	return &profileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// StagedGlobalNetworkPolicies returns a StagedGlobalNetworkPolicyInformer.
+func (v *version) StagedGlobalNetworkPolicies() StagedGlobalNetworkPolicyInformer {
+	return &stagedGlobalNetworkPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// StagedNetworkPolicies returns a StagedNetworkPolicyInformer.
+func (v *version) StagedNetworkPolicies() StagedNetworkPolicyInformer {
+	return &stagedNetworkPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -79,3 +79,15 @@ type NetworkSetNamespaceListerExpansion interface{}
 // ProfileListerExpansion allows custom methods to be added to
 // ProfileLister.
 type ProfileListerExpansion interface{}
+
+// StagedGlobalNetworkPolicyListerExpansion allows custom methods to be added to
+// StagedGlobalNetworkPolicyLister.
+type StagedGlobalNetworkPolicyListerExpansion interface{}
+
+// StagedNetworkPolicyListerExpansion allows custom methods to be added to
+// StagedNetworkPolicyLister.
+type StagedNetworkPolicyListerExpansion interface{}
+
+// StagedNetworkPolicyNamespaceListerExpansion allows custom methods to be added to
+// StagedNetworkPolicyNamespaceLister.
+type StagedNetworkPolicyNamespaceListerExpansion interface{}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package numorstring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxDSCP is the largest value of the 6-bit DSCP field.
+const MaxDSCP = 63
+
+// dscpNames maps the standard per-hop behaviour names to their DSCP values, as defined in
+// RFC 2474, RFC 2597 and RFC 3246.
+var dscpNames = map[string]uint8{
+	"DF":   0,
+	"CS0":  0,
+	"CS1":  8,
+	"AF11": 10,
+	"AF12": 12,
+	"AF13": 14,
+	"CS2":  16,
+	"AF21": 18,
+	"AF22": 20,
+	"AF23": 22,
+	"CS3":  24,
+	"AF31": 26,
+	"AF32": 28,
+	"AF33": 30,
+	"CS4":  32,
+	"AF41": 34,
+	"AF42": 36,
+	"AF43": 38,
+	"CS5":  40,
+	"EF":   46,
+	"CS6":  48,
+	"CS7":  56,
+}
+
+// DSCP is a Differentiated Services Code Point, which may be given as a number between 0 and 63 or as
+// one of the standard names, such as "EF" or "AF11".
+type DSCP Uint8OrString
+
+// DSCPFromInt creates a DSCP struct from an integer value.
+func DSCPFromInt(d uint8) DSCP {
+	return DSCP(
+		Uint8OrString{Type: NumOrStringNum, NumVal: d},
+	)
+}
+
+// DSCPFromString creates a DSCP struct from a string value.
+func DSCPFromString(s string) DSCP {
+	for n := range dscpNames {
+		if strings.EqualFold(n, s) {
+			return DSCP(
+				Uint8OrString{Type: NumOrStringString, StrVal: n},
+			)
+		}
+	}
+
+	// Unknown name - return the value unchanged.  Validation should catch this.
+	return DSCP(
+		Uint8OrString{Type: NumOrStringString, StrVal: s},
+	)
+}
+
+// UnmarshalJSON implements the json.Unmarshaller interface.
+func (d *DSCP) UnmarshalJSON(b []byte) error {
+	return (*Uint8OrString)(d).UnmarshalJSON(b)
+}
+
+// MarshalJSON implements the json.Marshaller interface.
+func (d DSCP) MarshalJSON() ([]byte, error) {
+	return Uint8OrString(d).MarshalJSON()
+}
+
+// String returns the string value, or the Itoa of the int value.
+func (d DSCP) String() string {
+	return (Uint8OrString)(d).String()
+}
+
+// NumValue returns the numeric DSCP value, converting a standard name to its value.  It returns
+// an error if the name is not known or the value is out of range.
+func (d DSCP) NumValue() (uint8, error) {
+	if d.Type == NumOrStringString {
+		if v, ok := dscpNames[strings.ToUpper(d.StrVal)]; ok {
+			return v, nil
+		}
+	}
+	v, err := (Uint8OrString)(d).NumValue()
+	if err != nil {
+		return 0, fmt.Errorf("invalid DSCP %q", d.StrVal)
+	}
+	if v > MaxDSCP {
+		return 0, fmt.Errorf("DSCP %d is out of range, must be between 0 and %d", v, MaxDSCP)
+	}
+	return v, nil
+}
+
+// OpenAPISchemaType is used by the kube-openapi generator when constructing
+// the OpenAPI spec of this type.
+// See: https://github.com/kubernetes/kube-openapi/tree/master/pkg/generators
+func (_ DSCP) OpenAPISchemaType() []string { return []string{"string"} }
+
+// OpenAPISchemaFormat is used by the kube-openapi generator when constructing
+// the OpenAPI spec of this type.
+// See: https://github.com/kubernetes/kube-openapi/tree/master/pkg/generators
+func (_ DSCP) OpenAPISchemaFormat() string { return "int-or-string" }
@@ -30,6 +30,7 @@ func init() {
 	asNumberType := reflect.TypeOf(numorstring.ASNumber(0))
 	protocolType := reflect.TypeOf(numorstring.Protocol{})
 	portType := reflect.TypeOf(numorstring.Port{})
+	dscpType := reflect.TypeOf(numorstring.DSCP{})
 
 	// Perform tests of JSON unmarshaling of the various field types.
 	DescribeTable("NumOrStringJSONUnmarshaling",
@@ -91,6 +92,11 @@ func init() {
 		Entry("should accept 0 protocol as string", "\"255\"", protocolType, numorstring.ProtocolFromInt(255)),
 		Entry("should accept 256 protocol as string", "\"256\"", protocolType, numorstring.ProtocolFromString("256")),
 		Entry("should reject bad protocol string", "\"25", protocolType, nil),
+
+		// DSCP tests.
+		Entry("should accept 46 DSCP as int", "46", dscpType, numorstring.DSCPFromInt(46)),
+		Entry("should accept 46 DSCP as string", "\"46\"", dscpType, numorstring.DSCPFromInt(46)),
+		Entry("should accept EF DSCP as string", "\"EF\"", dscpType, numorstring.DSCPFromString("EF")),
 	)
 
 	// Perform tests of JSON marshaling of the various field types.
@@ -122,6 +128,10 @@ func init() {
 		// Protocol tests.
 		Entry("should marshal protocol of 0", numorstring.ProtocolFromInt(0), "0"),
 		Entry("should marshal protocol of udp", numorstring.ProtocolFromString("UDP"), "\"UDP\""),
+
+		// DSCP tests.
+		Entry("should marshal DSCP of 10", numorstring.DSCPFromInt(10), "10"),
+		Entry("should marshal DSCP of AF11", numorstring.DSCPFromString("AF11"), "\"AF11\""),
 	)
 
 	// Perform tests of Stringer interface various field types.
@@ -158,6 +168,26 @@ func init() {
 		Entry("protocol 2 does not support ports", numorstring.ProtocolFromInt(2), false),
 	)
 
+	// Perform tests of DSCP NumValue method.
+	DescribeTable("NumOrStringDSCPNumValue",
+		func(dscp numorstring.DSCP, expected uint8, valid bool) {
+			v, err := dscp.NumValue()
+			if !valid {
+				Expect(err).To(HaveOccurred(), "expected DSCP to be invalid")
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal(expected), "expected DSCP value to match")
+		},
+		Entry("DSCP 0", numorstring.DSCPFromInt(0), uint8(0), true),
+		Entry("DSCP 63", numorstring.DSCPFromInt(63), uint8(63), true),
+		Entry("DSCP 64", numorstring.DSCPFromInt(64), uint8(0), false),
+		Entry("DSCP ef", numorstring.DSCPFromString("ef"), uint8(46), true),
+		Entry("DSCP AF41", numorstring.DSCPFromString("AF41"), uint8(34), true),
+		Entry("DSCP CS6", numorstring.DSCPFromString("CS6"), uint8(48), true),
+		Entry("DSCP foo", numorstring.DSCPFromString("foo"), uint8(0), false),
+	)
+
 	// Perform tests of Protocols FromString method.
 	DescribeTable("NumOrStringProtocols FromString is not case-sensitive",
 		func(input, expected string) {
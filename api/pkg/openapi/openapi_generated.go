@@ -20,6 +20,7 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.AutoHostEndpointConfig":             schema_pkg_apis_projectcalico_v3_AutoHostEndpointConfig(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.AutoHostEndpointTemplate":           schema_pkg_apis_projectcalico_v3_AutoHostEndpointTemplate(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.BGPConfiguration":                   schema_pkg_apis_projectcalico_v3_BGPConfiguration(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.BGPConfigurationList":               schema_pkg_apis_projectcalico_v3_BGPConfigurationList(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.BGPConfigurationSpec":               schema_pkg_apis_projectcalico_v3_BGPConfigurationSpec(ref),
@@ -100,6 +101,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.RouteTableIDRange":                  schema_pkg_apis_projectcalico_v3_RouteTableIDRange(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.RouteTableRange":                    schema_pkg_apis_projectcalico_v3_RouteTableRange(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.Rule":                               schema_pkg_apis_projectcalico_v3_Rule(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleLog":                            schema_pkg_apis_projectcalico_v3_RuleLog(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleMetadata":                       schema_pkg_apis_projectcalico_v3_RuleMetadata(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceAccountControllerConfig":     schema_pkg_apis_projectcalico_v3_ServiceAccountControllerConfig(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceAccountMatch":                schema_pkg_apis_projectcalico_v3_ServiceAccountMatch(ref),
@@ -107,7 +109,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceExternalIPBlock":             schema_pkg_apis_projectcalico_v3_ServiceExternalIPBlock(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceLoadBalancerIPBlock":         schema_pkg_apis_projectcalico_v3_ServiceLoadBalancerIPBlock(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceMatch":                       schema_pkg_apis_projectcalico_v3_ServiceMatch(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedGlobalNetworkPolicy":          schema_pkg_apis_projectcalico_v3_StagedGlobalNetworkPolicy(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedGlobalNetworkPolicyList":      schema_pkg_apis_projectcalico_v3_StagedGlobalNetworkPolicyList(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedNetworkPolicy":                schema_pkg_apis_projectcalico_v3_StagedNetworkPolicy(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedNetworkPolicyList":            schema_pkg_apis_projectcalico_v3_StagedNetworkPolicyList(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.WorkloadEndpointControllerConfig":   schema_pkg_apis_projectcalico_v3_WorkloadEndpointControllerConfig(ref),
+		"github.com/projectcalico/api/pkg/lib/numorstring.DSCP":                                     schema_api_pkg_lib_numorstring_DSCP(ref),
 		"github.com/projectcalico/api/pkg/lib/numorstring.Port":                                     schema_api_pkg_lib_numorstring_Port(ref),
 		"github.com/projectcalico/api/pkg/lib/numorstring.Protocol":                                 schema_api_pkg_lib_numorstring_Protocol(ref),
 		"github.com/projectcalico/api/pkg/lib/numorstring.Uint8OrString":                            schema_api_pkg_lib_numorstring_Uint8OrString(ref),
@@ -423,9 +430,99 @@ func schema_pkg_apis_projectcalico_v3_AutoHostEndpointConfig(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"createDefaultHostEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CreateDefaultHostEndpoint controls whether the host endpoint that covers all the interfaces of each node is created, when AutoCreate is enabled.  Disable it to only create the host endpoints of the templates. [Default: Enabled]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"templates": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Templates create a host endpoint for each interface of a node that matches one of them, when AutoCreate is enabled.  The host endpoints follow the interfaces that Felix reports on each node and the labels of the node.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.AutoHostEndpointTemplate"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.AutoHostEndpointTemplate"},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_AutoHostEndpointTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AutoHostEndpointTemplate describes the host endpoints to create for some of the interfaces of some of the nodes.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"generateName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GenerateName is the part of the names of the host endpoints of the template that distinguishes them from those of other templates.  The host endpoints are named <node name>-<generate name>-<interface name>.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"interfacePattern": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InterfacePattern is a regular expression that the names of the interfaces must match.  If empty, all the interfaces match.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"interfaceCIDRs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InterfaceCIDRs restricts the template to the interfaces that have an address in one of the CIDRs.  If empty, the interfaces are not filtered by address.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector selects the nodes that the template applies to. [Default: all()]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels are added to the labels of the host endpoints, which also get the labels of the node.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"generateName"},
+			},
+		},
 	}
 }
 
@@ -1101,6 +1198,13 @@ func schema_pkg_apis_projectcalico_v3_BGPPeerSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"peerHostname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The DNS name of the peer followed by an optional port number to peer with, in the format `<name>` or `<name>:<port>`.  calico/node peers with every address that the name resolves to, and re-resolves the name periodically so that the peerings follow the peer when its addresses change.  When this is set, the PeerIP and PeerSelector fields must be empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"asNumber": {
 						SchemaProps: spec.SchemaProps{
 							Description: "The AS Number of the peer.",
@@ -2110,6 +2214,21 @@ func schema_pkg_apis_projectcalico_v3_EntityRule(ref common.ReferenceCallback) c
 							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.ServiceAccountMatch"),
 						},
 					},
+					"domains": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Domains is an optional field, valid for egress Allow rules only, that restricts the rule to apply only to traffic to one of the given domain names. Each entry is either an exact domain name, such as `api.github.com`, or a wildcard with a leading `*.`, such as `*.github.com`, which matches any subdomain (but not `github.com` itself).\n\nFelix learns the IPs of the domains by snooping the responses of the trusted DNS servers (see DNSTrustedServers in FelixConfiguration), and keeps them for the TTL of the response.\n\nDomains cannot be specified on the same rule as Selector, NotSelector, NamespaceSelector, Nets, NotNets, Services or ServiceAccounts.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -2214,6 +2333,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 				Description: "FelixConfigurationSpec contains the values of the Felix configuration.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector makes this FelixConfiguration an override that only applies to the nodes that match the selector, for example to size the BPF maps of a pool of nodes differently.  It cannot be set on the \"default\" and \"node.<nodename>\" resources.  The overrides take precedence over the \"default\" resource and the \"node.<nodename>\" resources take precedence over the overrides.  If several overrides match a node, they apply in the alphabetical order of their names, so the value of a parameter comes from the last one that sets it.  A FelixConfiguration that is neither named \"default\" nor \"node.<nodename>\" and has no node selector is ignored.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"useInternalDataplaneDriver": {
 						SchemaProps: spec.SchemaProps{
 							Description: "UseInternalDataplaneDriver, if true, Felix will use its internal dataplane programming logic.  If false, it will launch an external dataplane driver and communicate with it over protobuf.",
@@ -2228,12 +2354,26 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"operationMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OperationMode selects which of its jobs Felix does. - Full: the default - Felix programs both the pod network and policy. - PolicyOnly: Felix only enforces policy, on top of the routing of another CNI plugin. Felix does not program routes, IPIP, VXLAN or WireGuard. - NetworkingOnly: Felix only programs the pod network. Policy is not enforced, all traffic to and from workloads is allowed and host endpoints are ignored. Changing the mode restarts Felix. [Default: Full]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"dataplaneWatchdogTimeout": {
 						SchemaProps: spec.SchemaProps{
 							Description: "DataplaneWatchdogTimeout is the readiness/liveness timeout used for Felix's (internal) dataplane driver. Increase this value if you experience spurious non-ready or non-live events when Felix is under heavy load. Decrease the value to get felix to report non-live or non-ready more quickly. [Default: 90s]\n\nDeprecated: replaced by the generic HealthTimeoutOverrides.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
+					"dataplaneShutdownMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataplaneShutdownMode controls what Felix does with the dataplane when it is terminated. - Retain: Felix leaves the dataplane programmed, for a fast restart or upgrade. - Cleanup: Felix removes the BPF programs and maps, the iptables chains and the routes that it programmed, for a node that is decommissioned. The mode can also be changed at run time with `calico-node -felix-shutdown-mode`, from a pre-stop hook for example. [Default: Retain]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"ipv6Support": {
 						SchemaProps: spec.SchemaProps{
 							Description: "IPv6Support controls whether Felix enables support for IPv6 (if supported by the in-use dataplane).",
@@ -2342,6 +2482,217 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "int32",
 						},
 					},
+					"egressProxyAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressProxyAddress is the IP address of an HTTP proxy that the HTTP(S) egress of the workloads matching EgressProxySelector is transparently redirected to.  The proxy must support transparent (intercepted) connections.  Only supported by the iptables dataplane. [Default: empty, egress is not redirected]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"egressProxyPort": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressProxyPort is the port of the egress proxy. [Default: 3128]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"egressProxySelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressProxySelector selects the workloads whose egress is redirected to the egress proxy. [Default: empty, no workloads]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"egressProxyDestinationPorts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressProxyDestinationPorts is the list of TCP destination ports of the connections that are redirected to the egress proxy. [Default: 80,443]",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/lib/numorstring.Port"),
+									},
+								},
+							},
+						},
+					},
+					"egressProxyExcludeCIDRs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressProxyExcludeCIDRs is a list of destination CIDRs that are never redirected to the egress proxy, in addition to the IP pools and the addresses of the host.  It should contain the Kubernetes service cluster IP range.  [Default: empty]",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"l7ProxySelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "L7ProxySelector selects the workloads whose inbound HTTP connections are transparently redirected to a node-local L7 proxy, such as Envoy, which enforces HTTP method and path policy.  L3/L4 policy is still enforced by the BPF programs.  Only supported by the BPF dataplane. [Default: empty, no workloads]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"l7ProxyPort": {
+						SchemaProps: spec.SchemaProps{
+							Description: "L7ProxyPort is the port on which the L7 proxy accepts the redirected (TPROXY) connections. [Default: 16001]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"l7ProxyDestinationPorts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "L7ProxyDestinationPorts is the list of TCP destination ports of the connections that are redirected to the L7 proxy. [Default: 80]",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/lib/numorstring.Port"),
+									},
+								},
+							},
+						},
+					},
+					"l7ProxyVerdictSocketPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "L7ProxyVerdictSocketPath is the path of the unix socket on which Felix receives the verdicts of the L7 proxy, as lines of JSON, and reports them as Prometheus metrics. [Default: /var/run/calico/l7-verdicts.sock]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dnsTrustedServers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSTrustedServers is the list of IPs or CIDRs of the DNS servers whose responses Felix snoops to learn the IPs of the domains in the Domains field of policy rules.  It should contain the cluster IP of the cluster DNS service.  Only supported by the iptables dataplane. [Default: empty, rules with Domains match no traffic]",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dnsPolicyNFLOGGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSPolicyNFLOGGroup is the NFLOG group that the iptables rules use to pass the responses of the trusted DNS servers to Felix. [Default: 41]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"dnsLogsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSLogsEnabled controls whether Felix logs which workload resolved which domain name to which addresses, from the DNS responses that it snoops for domain name policy.  Requires DNSTrustedServers. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"dnsLogsFilePath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSLogsFilePath is the full path to the file that Felix writes the DNS logs to, as lines of JSON. [Default: /var/log/calico/dns/dns.log]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dnsLogsFileMaxSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSLogsFileMaxSize is the size in megabytes at which the DNS log file is rotated. [Default: 100]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"dnsLogsFileMaxFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSLogsFileMaxFiles is the number of rotated DNS log files that are kept. [Default: 5]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"dnsLogsCollectorAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DNSLogsCollectorAddress is the host:port of a collector that Felix streams the DNS logs to over TCP, as lines of JSON, in place of writing them to DNSLogsFilePath. [Default: empty]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"flowLogsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsEnabled controls whether Felix aggregates the connections of the local workloads into flow logs, which record the endpoints, the policy rules that allowed or denied the connections and their packet and byte counts.  Only supported by the iptables dataplane. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"flowLogsFlushInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsFlushInterval is the period over which the connections are aggregated before the flow logs are exported. [Default: 300s]",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"flowLogsAggregation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsAggregation controls which connections are aggregated into the same flow log. - None: each connection has its own flow log. - SourcePort: the connections that only differ by their source port share a flow log. [Default: SourcePort]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"flowLogsSampleRate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsSampleRate logs one in every FlowLogsSampleRate connections, to reduce the volume of flow logs on busy nodes. [Default: 1]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"flowLogsFilePath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsFilePath is the full path to the file that Felix writes the flow logs to, as lines of JSON. [Default: /var/log/calico/flowlogs/flows.log]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"flowLogsFileMaxSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsFileMaxSize is the size in megabytes at which the flow log file is rotated. [Default: 100]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"flowLogsFileMaxFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsFileMaxFiles is the number of rotated flow log files that are kept. [Default: 5]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"flowLogsOTLPAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsOTLPAddress is the host:port of an OpenTelemetry collector that Felix sends the flow logs to with the OTLP/gRPC logs service, in place of writing them to FlowLogsFilePath. [Default: empty]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"flowLogsNFLOGGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsNFLOGGroup is the NFLOG group that the iptables policy rules use to report their verdicts to Felix. [Default: 42]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"flowLogsKTLSAccountingEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowLogsKTLSAccountingEnabled controls whether Felix detects the sockets of the host that use kernel TLS (kTLS), marks their connections in the flow logs and counts their traffic from the sockets where conntrack undercounts it, such as when the NIC runs the TCP connection of a socket with TLS offload.  Only the sockets in the host network namespace are detected, such as those of a TLS-terminating proxy in the host network. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"openstackRegion": {
 						SchemaProps: spec.SchemaProps{
 							Description: "OpenstackRegion is the name of the region that a particular Felix belongs to. In a multi-region Calico/OpenStack deployment, this must be configured somehow for each Felix (here in the datamodel, or in felix.cfg or the environment on each compute node), and must match the [calico] openstack_region value configured in neutron.conf on each node. [Default: Empty]",
@@ -2363,6 +2714,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"virtualInterfaceHostEndpointsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualInterfaceHostEndpointsEnabled extends host endpoint policy to the macvlan and ipvlan interfaces of the host, whose traffic otherwise bypasses the policy of their parent interface. Such an interface is governed by the host endpoint of its parent interface unless a host endpoint matches it directly and, in BPF mode, Felix attaches its programs to it when its parent is a data interface. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"chainInsertMode": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ChainInsertMode controls whether Felix hooks the kernel's top-level iptables chains by inserting a rule at the top of the chain or by appending a rule at the bottom. insert is the safe default since it prevents Calico's rules from being bypassed. If you switch to append mode, be sure that the other rules in the chains signal acceptance by falling through to the Calico rules, otherwise the Calico policy will be bypassed. [Default: insert]",
@@ -2403,6 +2761,20 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"policyLogFilePath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PolicyLogFilePath is the full path to the file that Felix writes the packets logged by policy rules with the File log target to. [Default: /var/log/calico/policy.log]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"policyLogNFLOGGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PolicyLogNFLOGGroup is the NFLOG group that the iptables rules use to pass the packets logged by policy rules with the Syslog, Journal or File log target to Felix. [Default: 40]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"logFilePath": {
 						SchemaProps: spec.SchemaProps{
 							Description: "LogFilePath is the full path to the Felix log. Set to none to disable file logging. [Default: /var/log/calico/felix.log]",
@@ -2438,6 +2810,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"logFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LogFormat is the format of the logs sent to the stdout and the log file. Logfmt and JSON are structured formats for log pipelines; see felix/docs/logging.md for the fields. [Default: Default]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"ipipEnabled": {
 						SchemaProps: spec.SchemaProps{
 							Description: "IPIPEnabled overrides whether Felix should configure an IPIP interface on the host. Optional as Felix determines this based on the existing IP pools. [Default: nil (unset)]",
@@ -2529,6 +2908,19 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"endpointStatusConditionsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EndpointStatusConditionsEnabled enables reporting the status of the local workload endpoints as a condition of their pods, of type projectcalico.org/PolicyProgrammed, so that describing a pod shows whether Felix has programmed its policy on the node.  Only supported with the Kubernetes datastore. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"nodeInterfacesReportingInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeInterfacesReportingInterval is the period at which Felix checks the host interfaces of its node and their addresses, and updates them in its Node resource if they changed.  kube-controllers generates host endpoints from templates for the reported interfaces. Set to 0 to disable the reporting. [Default: 30s]",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 					"iptablesMarkMask": {
 						SchemaProps: spec.SchemaProps{
 							Description: "IptablesMarkMask is the mask that Felix selects its IPTables Mark bits from. Should be a 32 bit hexadecimal number with at least 8 bits set, none of which clash with any other mark bits in use on the system. [Default: 0xff000000]",
@@ -2616,6 +3008,27 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"prometheusPolicyMetricsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PrometheusPolicyMetricsEnabled enables the felix_policy_rule_packets and felix_policy_rule_bytes metrics, which report how many packets matched each rule of the active policies on this node. In BPF mode, the rules are only counted when BPFPolicyDebugEnabled is set. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"prometheusPolicyVerdictMetricsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PrometheusPolicyVerdictMetricsEnabled enables the felix_policy_verdict_connections metric, which counts the connections that each policy allowed or denied per local workload endpoint. Only supported in iptables mode. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"prometheusPolicyVerdictMetricsMaxSeries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PrometheusPolicyVerdictMetricsMaxSeries limits the number of time series of the felix_policy_verdict_connections metric. Once reached, the connections of further workload endpoints are counted with an endpoint label of \"other\" so that the per-namespace totals remain accurate. [Default: 1000]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"failsafeInboundHostPorts": {
 						SchemaProps: spec.SchemaProps{
 							Description: "FailsafeInboundHostPorts is a list of PortProto struct objects including UDP/TCP/SCTP ports and CIDRs that Felix will allow incoming traffic to host endpoints on irrespective of the security policy. This is useful to avoid accidentally cutting off a host with incorrect configuration. For backwards compatibility, if the protocol is not specified, it defaults to \"tcp\". If a CIDR is not specified, it will allow traffic from all addresses. To disable all inbound host ports, use the value \"[]\". The default value allows ssh access, DHCP, BGP, etcd and the Kubernetes API. [Default: tcp:22, udp:68, tcp:179, tcp:2379, tcp:2380, tcp:5473, tcp:6443, tcp:6666, tcp:6667 ]",
@@ -2644,6 +3057,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							},
 						},
 					},
+					"failsafeNDPEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailsafeNDPEnabled, when true, makes Felix always allow the essential IPv6 neighbor discovery messages (router and neighbor solicitations and advertisements) to and from host endpoints, irrespective of the security policy. This avoids cutting off a host's IPv6 connectivity with a default-deny host endpoint policy. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"kubeNodePortRanges": {
 						SchemaProps: spec.SchemaProps{
 							Description: "KubeNodePortRanges holds list of port ranges used for service node ports. Only used if felix detects kube-proxy running in ipvs mode. Felix uses these ranges to separate host and workload traffic. [Default: 30000:32767].",
@@ -2867,6 +3287,27 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"bpfAttachIncludeIfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFAttachIncludeIfaces is a comma-separated list of interfaces that Felix attaches BPF programs to as host interfaces even though they do not match BPFDataIfacePattern, for example ipvlan, macvtap or tun devices. The list supports regular expressions wrapped with '/', as InterfaceExclude does. Changes take effect without restarting Felix. [Default: unset]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfAttachExcludeIfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFAttachExcludeIfaces is a comma-separated list of interfaces that Felix does not attach BPF programs to even though they match BPFDataIfacePattern, BPFL3IfacePattern or the workload interface prefixes. Programs that are already attached are removed. It takes precedence over BPFAttachIncludeIfaces and supports regular expressions in the same way. Changes take effect without restarting Felix. [Default: unset]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfHardwareOffloadIfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFHardwareOffloadIfaces is a comma-separated list of interfaces whose NICs Felix asks to run its BPF programs in hardware, by attaching them to tc with skip_sw. Felix checks that the NIC offloaded each program and falls back to running it in the kernel otherwise, counting both in the felix_bpf_hw_offload_attaches metric. The NIC and its driver must support tc BPF offload, as some Mellanox and Netronome NICs do. The programs on these interfaces are attached to the clsact qdisc even if BPFAttachType is TCX. The list supports regular expressions wrapped with '/', as InterfaceExclude does. [Default: unset]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"bpfConnectTimeLoadBalancingEnabled": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFConnectTimeLoadBalancingEnabled when in BPF mode, controls whether Felix installs the connection-time load balancer.  The connect-time load balancer is required for the host to be able to reach Kubernetes services and it improves the performance of pod-to-service connections.  The only reason to disable it is for debugging purposes. This will be deprecated. Use BPFConnectTimeLoadBalancing [Default: true]",
@@ -2937,6 +3378,27 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"bpfKubeProxyHealthzBindAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFKubeProxyHealthzBindAddress, in BPF mode, is the <IP>:<port> on which Felix serves kube-proxy compatible /healthz and /livez endpoints, which report whether Felix's kube-proxy replacement keeps the dataplane in sync with the services. Set it to kube-proxy's default, 0.0.0.0:10256, to keep load balancers that health check kube-proxy working. If empty, the endpoints are not served. [Default: empty]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfKubeProxyMetricsBindAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFKubeProxyMetricsBindAddress, in BPF mode, is the <IP>:<port> on which Felix serves the kube-proxy metrics of its kube-proxy replacement on /metrics, for example kube-proxy's default, 127.0.0.1:10249. If empty, the metrics are not served. [Default: empty]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfNodePortLinkLocalEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFNodePortLinkLocalEnabled, in BPF mode, controls whether link-local IPv6 addresses of the host are also used as NodePort addresses. By default, only the global unicast addresses of the host are used because a link-local address is only reachable on its own link and the same address may be present on several interfaces. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"bpfPSNATPorts": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFPSNATPorts sets the range from which we randomly pick a port if there is a source port collision. This should be within the ephemeral range as defined by RFC 6056 (1024–65535) and preferably outside the  ephemeral ranges used by common operating systems. Linux uses 32768–60999, while others mostly use the IANA defined range 49152–65535. It is not necessarily a problem if this range overlaps with the operating systems. Both ends of the range are inclusive. [Default: 20000:29999]",
@@ -2977,6 +3439,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "int32",
 						},
 					},
+					"bpfConntrackShards": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFConntrackShards sets the number of maps that the conntrack table is split into.  Flows are distributed among the maps by a hash of their key so that very large tables do not contend on a single map.  Each of the maps has the size set by BPFMapSizeConntrack.  Warning: changing the number of shards moves the existing entries between the maps and can cause disruption. [Default: 1]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"bpfMapSizeIPSets": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFMapSizeIPSets sets the size for ipsets map.  The IP sets map must be large enough to hold an entry for each endpoint matched by every selector in the source/destination matches in network policy.  Selectors such as \"all()\" can result in large numbers of entries (one entry per endpoint in that case).",
@@ -2991,6 +3460,20 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "int32",
 						},
 					},
+					"bpfMemoryBudgetMB": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFMemoryBudgetMB sets a budget, in MiB, for the estimated kernel memory of the BPF maps plus the Felix heap. If the maps don't fit in the budget with their configured sizes, Felix shrinks the conntrack map, down to 16384 entries, and reports that it is degraded; if they still don't fit, Felix refuses to start. This prevents map size settings from running the node out of memory. 0 disables the budget. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfMapShrinkPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFMapShrinkPolicy controls what Felix does when the size of a BPF map, such as the conntrack, NAT or IP sets map, is reduced below the number of entries that the map holds, for example when a smaller size is rolled out to a pool of nodes with a nodeSelector override. - Refuse: Felix keeps the current size of the map, with all its entries, and logs a warning; the new size applies once the map holds few enough entries and Felix restarts. - Truncate: Felix resizes the map and drops the entries that don't fit, which can disrupt connections. [Default: Refuse]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"bpfHostConntrackBypass": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFHostConntrackBypass Controls whether to bypass Linux conntrack in BPF mode for workloads and services. [Default: true - bypass Linux conntrack]",
@@ -3005,6 +3488,41 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"bpfAttachType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFAttachType controls how are the BPF programs at the network interfaces attached. By default `TCX` is used where available to enable easier coexistence with 3rd party programs. `TC` can force the legacy method of attaching via a qdisc. `TCX` falls back to `TC` if `TCX` is not available. [Default: TCX]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfFragmentHandling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFFragmentHandling controls how the BPF programs handle IPv4 fragments. Only the first fragment of a datagram carries the ports. With `Translate`, the ports of the first fragment are remembered so that the rest of the fragments are conntracked and NATed consistently; non-first fragments that arrive before the first one are dropped. With `Drop`, all fragments are dropped. Dropped fragments are counted by the \"IP fragments\" counter. [Default: Translate]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfNAT64Enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFNAT64Enabled enables stateful NAT64 in the BPF dataplane so that IPv6-only pods, whether in an IPv6-only or a dual-stack cluster, can reach IPv4 destinations. Traffic from pods to addresses in the BPFNAT64Prefix is translated to IPv4 and masqueraded to the IPv4 address of the node; replies are translated back. Requires BPFIpv6Enabled and an IPv4 address on the node. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"bpfNAT64Prefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFNAT64Prefix is the /96 IPv6 prefix that embeds IPv4 destinations when BPFNAT64Enabled is set. It must match the prefix that DNS64 synthesizes addresses in. [Default: 64:ff9b::/96]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfRoutesMirrorTableIndex": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFRoutesMirrorTableIndex, when non-zero, makes Felix mirror the content of the BPF routes map into the kernel routing table with this index, so that Calico's view of the workload and host routes can be inspected with `ip route show table <index>`. Local workload routes point at the workload interface, blackhole routes are shown as blackhole or prohibit routes and all other routes as throw routes. No routing rule refers to the table so it is not used for forwarding. The index must not be in use on the host, nor be in RouteTableRanges; add a name for it in /etc/iproute2/rt_tables.d to refer to it by name. [Default: 0, disabled]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"bpfPolicyDebugEnabled": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFPolicyDebugEnabled when true, Felix records detailed information about the BPF policy programs, which can be examined with the calico-bpf command-line tool.",
@@ -3012,6 +3530,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"bpfPolicyBitmapsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFPolicyBitmapsEnabled enables a policy performance profile for very large numbers of policies. Felix gives each active policy a bit and precomputes, for each endpoint, a bitmap of the policies that apply to it. The bitmaps are shared with the BPF programs so that the set of policies of a packet's endpoint is resolved with a single lookup. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"bpfForceTrackPacketsFromIfaces": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BPFForceTrackPacketsFromIfaces in BPF mode, forces traffic from these interfaces to skip Calico's iptables NOTRACK rule, allowing traffic from those interfaces to be tracked by Linux conntrack.  Should only be used for interfaces that are not used for the Calico fabric.  For example, a docker bridge device for non-Calico-networked containers. [Default: docker+]",
@@ -3049,6 +3574,49 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							},
 						},
 					},
+					"bpfNodePortAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFNodePortAddresses is a list of CIDRs that restricts the node IPs that NodePorts are reachable on in BPF mode, like the nodePortAddresses option of kube-proxy.  NodePorts are only reachable on the IPs of the host within the CIDRs.  If empty, NodePorts are reachable on all the IPs of the host. [Default: empty]",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"bpfServiceBackendsSoftLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFServiceBackendsSoftLimit is the number of backends of a single service above which Felix logs a warning and reports the service in the felix_bpf_kube_proxy_services_over_limit metric. Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfServiceBackendsHardLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFServiceBackendsHardLimit is the maximum number of backends that Felix programs for a single service, local backends are preferred. It prevents a service with a huge number of endpoints from using up the NAT backend map. Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfServiceFrontendsSoftLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFServiceFrontendsSoftLimit is the number of frontends derived from a single service (load balancer IPs, external IPs and node ports) above which Felix logs a warning. Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfServiceFrontendsHardLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFServiceFrontendsHardLimit is the maximum number of frontends derived from a single service (load balancer IPs, external IPs and node ports) that Felix programs. Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"routeSource": {
 						SchemaProps: spec.SchemaProps{
 							Description: "RouteSource configures where Felix gets its routing information. - WorkloadIPs: use workload endpoints to construct routes. - CalicoIPAM: the default - use IPAM data to construct routes.",
@@ -3801,6 +4369,13 @@ func schema_pkg_apis_projectcalico_v3_HostEndpointSpec(ref common.ReferenceCallb
 							},
 						},
 					},
+					"zone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The security zone of the endpoint, for example \"dmz\", \"storage\" or \"management\".  Calico adds the label \"projectcalico.org/zone\" with the zone to the endpoint, so that policy can select all the endpoints of a zone, across hosts and interfaces, with a selector like \"projectcalico.org/zone == 'dmz'\".  The zone must be a valid label value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -4134,6 +4709,13 @@ func schema_pkg_apis_projectcalico_v3_IPPoolSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allows IPPool to allocate for the pods of specific namespaces by label selector, for example \"projectcalico.org/name == 'tenant-a'\". The pools that select a namespace are the default pools of its pods, which allocate from them instead of from the pools without a namespace selector. Other allocations only use a pool with a namespace selector if they request it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"ipip": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Deprecated: this field is only used for APIv1 backwards compatibility. Setting this field is not allowed, this field is for internal use only.",
@@ -4162,6 +4744,13 @@ func schema_pkg_apis_projectcalico_v3_IPPoolSpec(ref common.ReferenceCallback) c
 							},
 						},
 					},
+					"egressSNATSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EgressSNATSelector selects the workloads whose NAT-outgoing traffic is source NATed to the address from this pool that is configured on their node, instead of the node's main address. Namespaces are selected with the \"projectcalico.org/namespace\" label of the workloads, for example \"projectcalico.org/namespace in {'tenant-a', 'tenant-b'}\". Only valid for pools whose allowedUses are [\"EgressSNAT\"].",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"cidr"},
 			},
@@ -4831,6 +5420,13 @@ func schema_pkg_apis_projectcalico_v3_NodeControllerConfig(ref common.ReferenceC
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
+					"podCIDRPools": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodCIDRPools controls whether to create an IP pool for the podCIDRs of each Kubernetes node, so that the node's pods are assigned addresses from the range that the cloud provider routes to it. [Default: Disabled]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -5165,6 +5761,20 @@ func schema_pkg_apis_projectcalico_v3_Rule(ref common.ReferenceCallback) common.
 							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.ICMPFields"),
 						},
 					},
+					"icmps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ICMPs is an optional field that restricts the rule to apply to any of several types, or types and codes, of ICMP traffic; for example, to the ICMPv6 neighbor discovery messages.  As for ICMP, this should only be specified if the Protocol field is set to \"ICMP\" or \"ICMPv6\", and it cannot be combined with the ICMP field.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.ICMPFields"),
+									},
+								},
+							},
+						},
+					},
 					"notProtocol": {
 						SchemaProps: spec.SchemaProps{
 							Description: "NotProtocol is the negated version of the Protocol field.",
@@ -5177,6 +5787,18 @@ func schema_pkg_apis_projectcalico_v3_Rule(ref common.ReferenceCallback) common.
 							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.ICMPFields"),
 						},
 					},
+					"dscp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DSCP is an optional field that restricts the rule to only apply to traffic with a specific Differentiated Services Code Point in its IP header, for example traffic that a router or another CNI has classified.\n\nMust be an integer in the range 0-63 or one of the standard names, such as \"EF\", \"AF11\" or \"CS1\".",
+							Ref:         ref("github.com/projectcalico/api/pkg/lib/numorstring.DSCP"),
+						},
+					},
+					"notDSCP": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NotDSCP is the negated version of the DSCP field.",
+							Ref:         ref("github.com/projectcalico/api/pkg/lib/numorstring.DSCP"),
+						},
+					},
 					"source": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Source contains the match criteria that apply to source entity.",
@@ -5197,6 +5819,12 @@ func schema_pkg_apis_projectcalico_v3_Rule(ref common.ReferenceCallback) common.
 							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.HTTPMatch"),
 						},
 					},
+					"log": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Log configures where the packets that match the rule are logged, and how many of them. It may only be set if the Action is Log.",
+							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleLog"),
+						},
+					},
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Metadata contains additional information for this rule",
@@ -5208,7 +5836,47 @@ func schema_pkg_apis_projectcalico_v3_Rule(ref common.ReferenceCallback) common.
 			},
 		},
 		Dependencies: []string{
-			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.EntityRule", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.HTTPMatch", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.ICMPFields", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleMetadata", "github.com/projectcalico/api/pkg/lib/numorstring.Protocol"},
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.EntityRule", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.HTTPMatch", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.ICMPFields", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleLog", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.RuleMetadata", "github.com/projectcalico/api/pkg/lib/numorstring.DSCP", "github.com/projectcalico/api/pkg/lib/numorstring.Protocol"},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_RuleLog(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target is where the packets are logged.  Kernel logs them to the kernel log.  Syslog, Journal and File have Felix log them to the local syslog daemon, to the systemd journal or to the file set by the PolicyLogFilePath Felix configuration parameter. [Default: Kernel]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"syslogFacility": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyslogFacility is the facility of the messages sent to syslog.  It may only be set if the Target is Syslog. [Default: local0]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RateLimit is the maximum rate at which the rule logs packets, in packets per second, minute, hour or day, for example \"10/second\" or \"100/minute\".  By default, the rule logs every packet that it matches.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rateLimitBurst": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RateLimitBurst is the number of packets that the rule may log in a burst before the RateLimit applies. [Default: 5]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -5378,6 +6046,186 @@ func schema_pkg_apis_projectcalico_v3_ServiceMatch(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_projectcalico_v3_StagedGlobalNetworkPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StagedGlobalNetworkPolicy is a GlobalNetworkPolicy that is evaluated by the data plane without being enforced.  The packets that it would allow or deny are counted and logged, so that the impact of the policy can be previewed before it is created as a GlobalNetworkPolicy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.GlobalNetworkPolicySpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.GlobalNetworkPolicySpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_StagedGlobalNetworkPolicyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StagedGlobalNetworkPolicyList is a list of StagedGlobalNetworkPolicy objects.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedGlobalNetworkPolicy"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedGlobalNetworkPolicy", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_StagedNetworkPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StagedNetworkPolicy is a NetworkPolicy that is evaluated by the data plane without being enforced.  The packets that it would allow or deny are counted and logged, so that the impact of the policy can be previewed before it is created as a NetworkPolicy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.NetworkPolicySpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.NetworkPolicySpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_StagedNetworkPolicyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StagedNetworkPolicyList is a list of StagedNetworkPolicy objects.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedNetworkPolicy"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.StagedNetworkPolicy", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
 func schema_pkg_apis_projectcalico_v3_WorkloadEndpointControllerConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5399,6 +6247,18 @@ func schema_pkg_apis_projectcalico_v3_WorkloadEndpointControllerConfig(ref commo
 	}
 }
 
+func schema_api_pkg_lib_numorstring_DSCP(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DSCP is a Differentiated Services Code Point, which may be given as a number between 0 and 63 or as one of the standard names, such as \"EF\" or \"AF11\".",
+				Type:        numorstring.DSCP{}.OpenAPISchemaType(),
+				Format:      numorstring.DSCP{}.OpenAPISchemaFormat(),
+			},
+		},
+	}
+}
+
 func schema_api_pkg_lib_numorstring_Port(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -57,6 +57,9 @@ type CalicoServerOptions struct {
 	// Enable Admission Controller support.
 	EnableAdmissionController bool
 
+	// Enable the checks of the custom verbs, such as "authorize" on policies.
+	EnableCustomVerbs bool
+
 	StopCh <-chan struct{}
 }
 
@@ -65,6 +68,8 @@ func (s *CalicoServerOptions) addFlags(flags *pflag.FlagSet) {
 
 	flags.BoolVar(&s.EnableAdmissionController, "enable-admission-controller-support", s.EnableAdmissionController,
 		"If true, admission controller hooks will be enabled.")
+	flags.BoolVar(&s.EnableCustomVerbs, "enable-custom-verbs", s.EnableCustomVerbs,
+		"If true, writing a policy with rules that allow or pass traffic also requires the \"authorize\" verb on the policy.")
 	flags.BoolVar(&s.PrintSwagger, "print-swagger", false,
 		"If true, prints swagger to stdout and exits.")
 	flags.StringVar(&s.SwaggerFilePath, "swagger-file-path", "./",
@@ -207,6 +212,7 @@ func (o *CalicoServerOptions) Config() (*apiserver.Config, error) {
 		ExtraConfig: apiserver.ExtraConfig{
 			KubernetesAPIServerConfig:  serverConfig.ClientConfig,
 			MinResourceRefreshInterval: minResourceRefreshInterval,
+			CustomVerbsEnabled:         o.EnableCustomVerbs,
 		},
 	}
 
@@ -50,6 +50,8 @@ type ExtraConfig struct {
 	// Place you custom config here.
 	KubernetesAPIServerConfig  *rest.Config
 	MinResourceRefreshInterval time.Duration
+	// CustomVerbsEnabled enables the checks of the custom verbs by the storage.
+	CustomVerbsEnabled bool
 }
 
 type Config struct {
@@ -98,7 +100,10 @@ func (c completedConfig) New() (*ProjectCalicoServer, error) {
 	apiGroupInfo.NegotiatedSerializer = newProtocolShieldSerializer(&Codecs)
 
 	// TODO: Make the storage type configurable
-	calicostore := calicorest.RESTStorageProvider{StorageType: "calico"}
+	calicostore := calicorest.RESTStorageProvider{
+		StorageType:        "calico",
+		CustomVerbsEnabled: c.ExtraConfig.CustomVerbsEnabled,
+	}
 
 	s := &ProjectCalicoServer{GenericAPIServer: genericServer}
 
@@ -36,6 +36,7 @@ import (
 type REST struct {
 	*genericregistry.Store
 	shortNames []string
+	opts       server.Options
 }
 
 // EmptyObject returns an empty instance
@@ -98,7 +99,7 @@ func NewREST(scheme *runtime.Scheme, opts server.Options) (*REST, error) {
 		DestroyFunc: dFunc,
 	}
 
-	return &REST{Store: store, shortNames: opts.ShortNames}, nil
+	return &REST{Store: store, shortNames: opts.ShortNames, opts: opts}, nil
 }
 
 func (r *REST) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
@@ -106,12 +107,25 @@ func (r *REST) List(ctx context.Context, options *metainternalversion.ListOption
 }
 
 func (r *REST) Create(ctx context.Context, obj runtime.Object, val rest.ValidateObjectFunc, createOpt *metav1.CreateOptions) (runtime.Object, error) {
-	return r.Store.Create(ctx, obj, val, createOpt)
+	return r.Store.Create(ctx, obj, server.ValidateCreateWith(r.authorizeRules, val), createOpt)
 }
 
 func (r *REST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc,
 	updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
-	return r.Store.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+	return r.Store.Update(ctx, name, objInfo,
+		server.ValidateCreateWith(r.authorizeRules, createValidation),
+		server.ValidateUpdateWith(r.authorizeRules, updateValidation),
+		forceAllowCreate, options)
+}
+
+// authorizeRules checks that the user may write the rules of the policy, which requires the
+// custom verb server.VerbAuthorize if they allow or pass traffic.
+func (r *REST) authorizeRules(ctx context.Context, obj runtime.Object) error {
+	policy := obj.(*calico.NetworkPolicy)
+	if !server.RulesAuthorizeTraffic(policy.Spec.Ingress, policy.Spec.Egress) {
+		return nil
+	}
+	return r.opts.AuthorizeCustomVerb(ctx, server.VerbAuthorize, policy.Name)
 }
 
 func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
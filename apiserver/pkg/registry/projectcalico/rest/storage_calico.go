@@ -50,6 +50,8 @@ import (
 // the calico API group. It implements (./pkg/apiserver).RESTStorageProvider
 type RESTStorageProvider struct {
 	StorageType server.StorageType
+	// CustomVerbsEnabled enables the checks of the custom verbs of the policies.
+	CustomVerbsEnabled bool
 }
 
 // NewV3Storage constructs v3 api storage.
@@ -79,6 +81,7 @@ func (p RESTStorageProvider) NewV3Storage(
 		authorizer,
 		[]string{"cnp", "caliconetworkpolicy", "caliconetworkpolicies"},
 	)
+	policyOpts.CustomVerbsEnabled = p.CustomVerbsEnabled
 
 	networksetRESTOptions, err := restOptionsGetter.GetRESTOptions(calico.Resource("networksets"))
 	if err != nil {
@@ -123,6 +126,7 @@ func (p RESTStorageProvider) NewV3Storage(
 		authorizer,
 		[]string{"gnp", "cgnp", "calicoglobalnetworkpolicies"},
 	)
+	gpolicyOpts.CustomVerbsEnabled = p.CustomVerbsEnabled
 
 	gNetworkSetRESTOptions, err := restOptionsGetter.GetRESTOptions(calico.Resource("globalnetworksets"))
 	if err != nil {
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	calico "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// VerbAuthorize is the custom verb that allows writing a policy with rules that allow or pass
+// traffic.  Without it, a user that may create or update a policy can only write rules that deny
+// or log traffic.  A role that allows writing any policy:
+//
+//	rules:
+//	- apiGroups: ["projectcalico.org"]
+//	  resources: ["networkpolicies"]
+//	  verbs: ["create", "update", "authorize"]
+const VerbAuthorize = "authorize"
+
+// AuthorizeCustomVerb checks that the user of the request may perform a custom verb on the named
+// resource of the request.  Kubernetes only authorizes the verb of the request itself, the
+// custom verbs narrow down what a user may do with a resource and are checked by the storage.
+// It does nothing unless the custom verbs are enabled.
+func (o Options) AuthorizeCustomVerb(ctx context.Context, verb, name string) error {
+	if !o.CustomVerbsEnabled || o.Authorizer == nil {
+		return nil
+	}
+	user, ok := genericapirequest.UserFrom(ctx)
+	if !ok {
+		return apierrors.NewInternalError(errors.New("no user in the request context"))
+	}
+	info, ok := genericapirequest.RequestInfoFrom(ctx)
+	if !ok {
+		return apierrors.NewInternalError(errors.New("no request info in the request context"))
+	}
+
+	attrs := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            verb,
+		Namespace:       info.Namespace,
+		APIGroup:        info.APIGroup,
+		APIVersion:      info.APIVersion,
+		Resource:        info.Resource,
+		Name:            name,
+		ResourceRequest: true,
+	}
+	decision, reason, err := o.Authorizer.Authorize(ctx, attrs)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if decision != authorizer.DecisionAllow {
+		return apierrors.NewForbidden(calico.Resource(info.Resource), name,
+			fmt.Errorf("user %q cannot %s %s: %s", user.GetName(), verb, info.Resource, reason))
+	}
+	return nil
+}
+
+// RulesAuthorizeTraffic returns true if any of the rules allows traffic or passes it to the
+// next tier, which then requires the VerbAuthorize verb.
+func RulesAuthorizeTraffic(rules ...[]calico.Rule) bool {
+	for _, rs := range rules {
+		for _, r := range rs {
+			if r.Action == calico.Allow || r.Action == calico.Pass {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateCreateWith returns a create validation that runs check before validate.
+func ValidateCreateWith(check, validate rest.ValidateObjectFunc) rest.ValidateObjectFunc {
+	return func(ctx context.Context, obj runtime.Object) error {
+		if err := check(ctx, obj); err != nil {
+			return err
+		}
+		if validate == nil {
+			return nil
+		}
+		return validate(ctx, obj)
+	}
+}
+
+// ValidateUpdateWith returns an update validation that runs check on the updated object before
+// validate.
+func ValidateUpdateWith(check rest.ValidateObjectFunc, validate rest.ValidateObjectUpdateFunc) rest.ValidateObjectUpdateFunc {
+	return func(ctx context.Context, obj, old runtime.Object) error {
+		if err := check(ctx, obj); err != nil {
+			return err
+		}
+		if validate == nil {
+			return nil
+		}
+		return validate(ctx, obj, old)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	calico "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func requestContext() context.Context {
+	ctx := genericapirequest.WithNamespace(genericapirequest.NewContext(), "team-a")
+	ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+	return genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "create",
+		APIGroup:          "projectcalico.org",
+		APIVersion:        "v3",
+		Namespace:         "team-a",
+		Resource:          "networkpolicies",
+	})
+}
+
+func TestAuthorizeCustomVerb(t *testing.T) {
+	var attrs authorizer.Attributes
+	allowed := false
+	opts := Options{
+		Authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+			attrs = a
+			if allowed {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionNoOpinion, "no role", nil
+		}),
+	}
+
+	if err := opts.AuthorizeCustomVerb(requestContext(), VerbAuthorize, "allow-web"); err != nil {
+		t.Fatalf("expected no check with the custom verbs disabled, got %v", err)
+	}
+	if attrs != nil {
+		t.Fatalf("expected no call to the authorizer with the custom verbs disabled")
+	}
+
+	opts.CustomVerbsEnabled = true
+	err := opts.AuthorizeCustomVerb(requestContext(), VerbAuthorize, "allow-web")
+	if !apierrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if attrs.GetVerb() != VerbAuthorize || attrs.GetResource() != "networkpolicies" ||
+		attrs.GetNamespace() != "team-a" || attrs.GetName() != "allow-web" ||
+		attrs.GetAPIGroup() != "projectcalico.org" || attrs.GetUser().GetName() != "alice" {
+		t.Fatalf("unexpected attributes %+v", attrs)
+	}
+
+	allowed = true
+	if err := opts.AuthorizeCustomVerb(requestContext(), VerbAuthorize, "allow-web"); err != nil {
+		t.Fatalf("expected the verb to be allowed, got %v", err)
+	}
+}
+
+func TestRulesAuthorizeTraffic(t *testing.T) {
+	deny := []calico.Rule{{Action: calico.Deny}, {Action: calico.Log}}
+	if RulesAuthorizeTraffic(deny, nil) {
+		t.Errorf("expected deny and log rules not to authorize traffic")
+	}
+	if !RulesAuthorizeTraffic(deny, []calico.Rule{{Action: calico.Allow}}) {
+		t.Errorf("expected an allow rule to authorize traffic")
+	}
+	if !RulesAuthorizeTraffic([]calico.Rule{{Action: calico.Pass}}) {
+		t.Errorf("expected a pass rule to authorize traffic")
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	checkErr := errors.New("check failed")
+	check := func(ctx context.Context, obj runtime.Object) error { return checkErr }
+	validated := false
+	validate := func(ctx context.Context, obj runtime.Object) error {
+		validated = true
+		return nil
+	}
+	validateUpdate := func(ctx context.Context, obj, old runtime.Object) error {
+		validated = true
+		return nil
+	}
+
+	if err := ValidateCreateWith(check, validate)(context.Background(), nil); err != checkErr || validated {
+		t.Errorf("expected the create check to fail before the validation, got %v", err)
+	}
+	if err := ValidateUpdateWith(check, validateUpdate)(context.Background(), nil, nil); err != checkErr || validated {
+		t.Errorf("expected the update check to fail before the validation, got %v", err)
+	}
+
+	pass := func(ctx context.Context, obj runtime.Object) error { return nil }
+	if err := ValidateCreateWith(pass, nil)(context.Background(), nil); err != nil {
+		t.Errorf("expected no error without a validation, got %v", err)
+	}
+	if err := ValidateUpdateWith(pass, validateUpdate)(context.Background(), nil, nil); err != nil || !validated {
+		t.Errorf("expected the update to be validated, got %v", err)
+	}
+}
@@ -78,6 +78,9 @@ type Options struct {
 	storageType   StorageType
 	Authorizer    authorizer.Authorizer
 	ShortNames    []string
+
+	// CustomVerbsEnabled enables the checks of the custom verbs, see AuthorizeCustomVerb.
+	CustomVerbsEnabled bool
 }
 
 // NewOptions returns a new Options with the given parameters
@@ -19,6 +19,7 @@ import (
 
 	"github.com/projectcalico/calico/app-policy/policystore"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 
 	authz "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
 	log "github.com/sirupsen/logrus"
@@ -67,7 +68,7 @@ func checkStore(store *policystore.PolicyStore, req *authz.CheckRequest) (s stat
 			}
 		}
 	}()
-	if len(ep.Tiers) > 0 {
+	if len(ep.Tiers) > 0 && hasEnforcedPolicies(ep.Tiers[0].IngressPolicies) {
 		// We only support a single tier.
 		log.Debug("Checking policy tier 1.")
 
@@ -76,6 +77,10 @@ func checkStore(store *policystore.PolicyStore, req *authz.CheckRequest) (s stat
 		action := NO_MATCH
 	Policy:
 		for i, name := range policies {
+			if model.PolicyIsStaged(name) {
+				// Staged policies are only evaluated by the iptables dataplane.
+				continue
+			}
 			pID := proto.PolicyID{Tier: tier.GetName(), Name: name}
 			policy := store.PolicyByID[pID]
 			action = checkPolicy(policy, reqCache)
@@ -109,7 +114,7 @@ func checkStore(store *policystore.PolicyStore, req *authz.CheckRequest) (s stat
 			return
 		}
 	}
-	// If we reach here, there were either no tiers, or a policy PASSed the request.
+	// If we reach here, there were either no tiers (or only staged policies), or a policy PASSed the request.
 	if len(ep.ProfileIds) > 0 {
 		for i, name := range ep.ProfileIds {
 			pID := proto.ProfileID{Name: name}
@@ -183,3 +188,13 @@ func actionFromString(s string) Action {
 	}
 	return a
 }
+
+// hasEnforcedPolicies returns true if any of the policies is not staged.
+func hasEnforcedPolicies(policies []string) bool {
+	for _, name := range policies {
+		if !model.PolicyIsStaged(name) {
+			return true
+		}
+	}
+	return false
+}
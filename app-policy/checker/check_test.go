@@ -251,6 +251,53 @@ func TestCheckStoreProfileOnly(t *testing.T) {
 	Expect(status.Code).To(Equal(PERMISSION_DENIED))
 }
 
+// Staged policies should be ignored, so an endpoint with only staged policies evaluates profiles.
+func TestCheckStoreStagedPolicyIgnored(t *testing.T) {
+	RegisterTestingT(t)
+
+	store := policystore.NewPolicyStore()
+	store.Endpoint = &proto.WorkloadEndpoint{
+		Tiers: []*proto.TierInfo{
+			{
+				Name:            "tier1",
+				IngressPolicies: []string{"staged:policy1"},
+			},
+		},
+		ProfileIds: []string{"profile1"},
+	}
+	store.PolicyByID[proto.PolicyID{Tier: "tier1", Name: "staged:policy1"}] = &proto.Policy{
+		InboundRules: []*proto.Rule{
+			{
+				Action:    "deny",
+				HttpMatch: &proto.HTTPMatch{Methods: []string{"GET"}},
+			},
+		},
+	}
+	store.ProfileByID[proto.ProfileID{Name: "profile1"}] = &proto.Profile{
+		InboundRules: []*proto.Rule{
+			{
+				Action:    "allow",
+				HttpMatch: &proto.HTTPMatch{Methods: []string{"GET"}},
+			},
+		},
+	}
+
+	req := &authz.CheckRequest{Attributes: &authz.AttributeContext{
+		Source: &authz.AttributeContext_Peer{
+			Principal: "spiffe://cluster.local/ns/default/sa/steve",
+		},
+		Destination: &authz.AttributeContext_Peer{
+			Principal: "spiffe://cluster.local/ns/default/sa/quinn",
+		},
+		Request: &authz.AttributeContext_Request{
+			Http: &authz.AttributeContext_HttpRequest{Method: "GET"},
+		},
+	}}
+
+	status := checkStore(store, req)
+	Expect(status.Code).To(Equal(OK))
+}
+
 // And endpoint with a Tier should not evaluate profiles; there is a default deny on the tier.
 func TestCheckStorePolicyDefaultDeny(t *testing.T) {
 	RegisterTestingT(t)
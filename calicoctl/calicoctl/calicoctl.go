@@ -41,11 +41,14 @@ func main() {
     get          Get a resource identified by file, directory, stdin or resource type and
                  name.
     label        Add or update labels of resources.
+    wait         Wait for a condition on resources.
     convert      Convert config files between different API versions.
     ipam         IP address management.
     node         Calico node management.
+    policy       Calico policy statistics.
     version      Display the version of this binary.
     datastore    Calico datastore management.
+    diags        Collect a diagnostics bundle from the whole cluster.
 
 Options:
   -h --help                    Show this screen.
@@ -121,6 +124,8 @@ Description:
 			err = commands.Get(args)
 		case "label":
 			err = commands.Label(args)
+		case "wait":
+			err = commands.Wait(args)
 		case "convert":
 			err = commands.Convert(args)
 		case "version":
@@ -129,8 +134,12 @@ Description:
 			err = commands.Node(args)
 		case "ipam":
 			err = commands.IPAM(args)
+		case "policy":
+			err = commands.Policy(args)
 		case "datastore":
 			err = commands.Datastore(args)
+		case "diags":
+			err = commands.Diags(args)
 		default:
 			err = fmt.Errorf("Unknown command: %q\n%s", command, doc)
 		}
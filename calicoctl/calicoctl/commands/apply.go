@@ -29,7 +29,7 @@ import (
 
 func Apply(args []string) error {
 	doc := constants.DatastoreIntro + `Usage:
-  <BINARY_NAME> apply --filename=<FILENAME> [--recursive] [--skip-empty]
+  <BINARY_NAME> apply --filename=<FILENAME> [--recursive] [--skip-empty] [--dry-run=<MODE>]
                   [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>] [--allow-version-mismatch]
 
 Examples:
@@ -39,6 +39,9 @@ Examples:
   # Apply a policy based on the JSON passed into stdin.
   cat policy.json | <BINARY_NAME> apply -f -
 
+  # Show the changes that applying policy.yaml would make, without making them.
+  <BINARY_NAME> apply -f ./policy.yaml --dry-run=server
+
 Options:
   -h --help                    Show this screen.
   -f --filename=<FILENAME>     Filename to use to apply the resource.  If set to
@@ -48,6 +51,12 @@ Options:
   -R --recursive               Process the filename specified in -f or --filename recursively.
      --skip-empty              Do not error if any files or directory specified using -f or --filename contain no
                                data.
+     --dry-run=<MODE>          One of "none" or "server".  If "server", the
+                               resources are submitted to the datastore with
+                               full validation and admission, and the change
+                               to each resource is printed as a diff of its
+                               spec, but nothing is persisted.  Requires the
+                               Kubernetes datastore.  [default: none]
   -c --config=<CONFIG>         Path to the file containing connection
                                configuration in YAML or JSON format.
                                [default: ` + constants.DefaultConfigPath + `]
@@ -76,6 +85,8 @@ Description:
     * networkSet
     * node
     * profile
+    * stagedGlobalNetworkPolicy
+    * stagedNetworkPolicy
     * workloadEndpoint
 
   When applying a resource:
@@ -94,6 +105,10 @@ Description:
   When applying a resource to perform an update, the complete resource spec
   must be provided, it is not sufficient to supply only the fields that are
   being updated.
+
+  With --dry-run=server, the resources are validated and admitted by the API
+  server exactly as they would be when applied, and the resulting change to
+  each resource is printed, but no resource is created or modified.
 `
 	// Replace all instances of BINARY_NAME with the name of the binary.
 	name, _ := util.NameAndDescription()
@@ -109,6 +124,10 @@ Description:
 	if context := parsedArgs["--context"]; context != nil {
 		os.Setenv("K8S_CURRENT_CONTEXT", context.(string))
 	}
+	dryRun := parsedArgs["--dry-run"].(string)
+	if dryRun != common.DryRunNone && dryRun != common.DryRunServer {
+		return fmt.Errorf("Invalid --dry-run mode %q: must be %q or %q", dryRun, common.DryRunNone, common.DryRunServer)
+	}
 
 	results := common.ExecuteConfigCommand(parsedArgs, common.ActionApply)
 	log.Infof("results: %+v", results)
@@ -130,10 +149,14 @@ Description:
 			return fmt.Errorf("Failed to apply any resources: %v", results.ResErrs)
 		}
 	} else if len(results.ResErrs) == 0 {
+		suffix := ""
+		if dryRun == common.DryRunServer {
+			suffix = " (server dry run)"
+		}
 		if results.SingleKind != "" {
-			fmt.Printf("Successfully applied %d '%s' resource(s)\n", results.NumHandled, results.SingleKind)
+			fmt.Printf("Successfully applied %d '%s' resource(s)%s\n", results.NumHandled, results.SingleKind, suffix)
 		} else {
-			fmt.Printf("Successfully applied %d resource(s)\n", results.NumHandled)
+			fmt.Printf("Successfully applied %d resource(s)%s\n", results.NumHandled, suffix)
 		}
 	} else {
 		if results.NumHandled-len(results.ResErrs) > 0 {
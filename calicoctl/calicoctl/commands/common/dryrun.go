@@ -0,0 +1,77 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	calicoErrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// Dry run modes accepted by --dry-run.
+const (
+	DryRunNone   = "none"
+	DryRunServer = "server"
+)
+
+// dryRunApply applies the resource in a server-side dry run, which runs the full validation
+// and admission logic of the datastore without persisting the result, and writes the diff
+// between the current spec of the resource and the spec that would result to w.
+func dryRunApply(ctx context.Context, rm resourcemgr.ResourceManager, client client.Interface, resource resourcemgr.ResourceObject, w io.Writer) (runtime.Object, error) {
+	var current resourcemgr.ResourceObject
+	if resource.GetObjectMeta().GetName() != "" {
+		obj, err := rm.GetOrList(ctx, client, resource.DeepCopyObject().(resourcemgr.ResourceObject))
+		switch err.(type) {
+		case nil:
+			current = obj.(resourcemgr.ResourceObject)
+		case calicoErrors.ErrorResourceDoesNotExist:
+		default:
+			return nil, err
+		}
+	}
+
+	kind := resource.GetObjectKind().GroupVersionKind().Kind
+	resOut, err := rm.Apply(bapi.ContextWithDryRun(ctx), client, resource)
+	if err != nil {
+		return nil, err
+	}
+	printDryRunDiff(w, kind, current, resOut)
+	return resOut, nil
+}
+
+// printDryRunDiff writes the diff between the spec of the current resource, which is nil if
+// the resource does not exist, and the spec of the resource returned by the dry run.
+func printDryRunDiff(w io.Writer, kind string, current, result resourcemgr.ResourceObject) {
+	key := resourceKey(result)
+	from, old, action := "a/"+key, "", "configured"
+	if current == nil {
+		from, action = "/dev/null", "created"
+	} else {
+		old = specYAML(current)
+	}
+	spec := specYAML(result)
+	if current != nil && old == spec {
+		action = "unchanged"
+	}
+
+	_, _ = fmt.Fprintf(w, "# %s %s %s (server dry run)\n%s", kind, key, action, unifiedDiff(from, "b/"+key, old, spec))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+)
+
+var _ = Describe("printDryRunDiff", func() {
+	var out bytes.Buffer
+
+	lines := func(ls ...string) string {
+		return strings.Join(ls, "\n") + "\n"
+	}
+	ipPool := func(cidr string, disabled bool) *apiv3.IPPool {
+		p := apiv3.NewIPPool()
+		p.Name = "pool1"
+		p.Spec.CIDR = cidr
+		p.Spec.Disabled = disabled
+		return p
+	}
+
+	BeforeEach(func() {
+		out.Reset()
+	})
+
+	It("should show a created resource as a diff against nothing", func() {
+		printDryRunDiff(&out, apiv3.KindIPPool, nil, ipPool("10.0.0.0/16", false))
+		Expect(out.String()).To(Equal(lines(
+			"# IPPool pool1 created (server dry run)",
+			"--- /dev/null",
+			"+++ b/pool1",
+			"@@ -0,0 +1 @@",
+			"+cidr: 10.0.0.0/16",
+		)))
+	})
+
+	It("should show the change to an existing resource", func() {
+		printDryRunDiff(&out, apiv3.KindIPPool, ipPool("10.0.0.0/16", false), ipPool("10.0.0.0/16", true))
+		Expect(out.String()).To(Equal(lines(
+			"# IPPool pool1 configured (server dry run)",
+			"--- a/pool1",
+			"+++ b/pool1",
+			"@@ -1 +1,2 @@",
+			" cidr: 10.0.0.0/16",
+			"+disabled: true",
+		)))
+	})
+
+	It("should report an unchanged resource without a diff", func() {
+		printDryRunDiff(&out, apiv3.KindIPPool, ipPool("10.0.0.0/16", false), ipPool("10.0.0.0/16", false))
+		Expect(out.String()).To(Equal("# IPPool pool1 unchanged (server dry run)\n"))
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"sort"
@@ -26,6 +27,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
 
 	"github.com/google/safetext/yamltemplate"
 	"github.com/projectcalico/go-json/json"
@@ -184,6 +186,76 @@ func (r ResourcePrinterTemplate) Print(client client.Interface, resources []runt
 	return err
 }
 
+// ResourcePrinterJSONPathFile implements the ResourcePrinter interface and is used to display
+// a slice of resources using a JSONPath template specified in a file.
+type ResourcePrinterJSONPathFile struct {
+	TemplateFile string
+}
+
+func (r ResourcePrinterJSONPathFile) Print(client client.Interface, resources []runtime.Object) error {
+	template, err := os.ReadFile(r.TemplateFile)
+	if err != nil {
+		return err
+	}
+	rp := ResourcePrinterJSONPath{Template: string(template)}
+	return rp.Print(client, resources)
+}
+
+// ResourcePrinterJSONPath implements the ResourcePrinter interface and is used to display
+// a slice of resources using a JSONPath template, with the same semantics as kubectl: the
+// template is applied to the JSON form of the resources, and several resources are wrapped
+// in a List.
+type ResourcePrinterJSONPath struct {
+	Template string
+}
+
+func (r ResourcePrinterJSONPath) Print(client client.Interface, resources []runtime.Object) error {
+	return printJSONPath(os.Stdout, r.Template, resources)
+}
+
+func printJSONPath(w io.Writer, template string, resources []runtime.Object) error {
+	jp := jsonpath.New("get").AllowMissingKeys(true)
+	if err := jp.Parse(relaxedJSONPath(template)); err != nil {
+		return fmt.Errorf("error parsing jsonpath %s: %v", template, err)
+	}
+
+	var rs interface{}
+	if len(resources) == 1 {
+		rs = resources[0]
+	} else {
+		rs = map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      resources,
+		}
+	}
+
+	// Execute the template on the generic form of the JSON, so that the field names are those of
+	// the JSON and YAML output.
+	output, err := json.Marshal(rs)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(output, &data); err != nil {
+		return err
+	}
+	return jp.Execute(w, data)
+}
+
+// relaxedJSONPath accepts the JSONPath expressions that kubectl accepts without braces or leading
+// dot, such as "metadata.name", and converts them to templates.
+func relaxedJSONPath(template string) string {
+	if strings.Contains(template, "{") {
+		return template
+	}
+	template = strings.TrimSpace(template)
+	if !strings.HasPrefix(template, ".") {
+		template = "." + template
+	}
+	return "{" + template + "}"
+}
+
 // join is similar to strings.Join() but takes an arbitrary slice of interfaces and converts
 // each to its string representation and joins them together with the provided separator
 // string.
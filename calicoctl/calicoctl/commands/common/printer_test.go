@@ -15,6 +15,7 @@
 package common
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -23,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
@@ -144,3 +146,32 @@ func (c *mockClient) Update(_ context.Context, res *apiv3.BGPConfiguration, _ op
 	c.bgpConfig = res
 	return c.bgpConfig, nil
 }
+
+var _ = Describe("Testing printer jsonpath", func() {
+	pool := func(name, cidr string) *apiv3.IPPool {
+		p := apiv3.NewIPPool()
+		p.Name = name
+		p.Spec.CIDR = cidr
+		return p
+	}
+
+	DescribeTable("prints the template",
+		func(template string, resources []runtime.Object, expected string) {
+			var buf bytes.Buffer
+			Expect(printJSONPath(&buf, template, resources)).To(Succeed())
+			Expect(buf.String()).To(Equal(expected))
+		},
+		Entry("single resource", "{.spec.cidr}", []runtime.Object{pool("p1", "10.0.0.0/16")}, "10.0.0.0/16"),
+		Entry("relaxed expression", "metadata.name", []runtime.Object{pool("p1", "10.0.0.0/16")}, "p1"),
+		Entry("resource list", "{.items[*].metadata.name}", []runtime.Object{&apiv3.IPPoolList{
+			Items: []apiv3.IPPool{*pool("p1", "10.0.0.0/16"), *pool("p2", "10.1.0.0/16")},
+		}}, "p1 p2"),
+		Entry("several resources", `{range .items[*]}{.metadata.name}={.spec.cidr}{"\n"}{end}`,
+			[]runtime.Object{pool("p1", "10.0.0.0/16"), pool("p2", "10.1.0.0/16")}, "p1=10.0.0.0/16\np2=10.1.0.0/16\n"),
+		Entry("missing key", "{.spec.nodeSelector}", []runtime.Object{pool("p1", "10.0.0.0/16")}, ""),
+	)
+
+	It("fails on an invalid template", func() {
+		Expect(printJSONPath(&bytes.Buffer{}, "{.spec", nil)).To(MatchError(ContainSubstring("error parsing jsonpath")))
+	})
+})
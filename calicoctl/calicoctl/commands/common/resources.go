@@ -306,7 +306,11 @@ func ExecuteResourceAction(args map[string]interface{}, client client.Interface,
 
 	switch action {
 	case ActionApply:
-		resOut, err = rm.Apply(ctx, client, resource)
+		if argutils.ArgStringOrBlank(args, "--dry-run") == DryRunServer {
+			resOut, err = dryRunApply(ctx, rm, client, resource, os.Stdout)
+		} else {
+			resOut, err = rm.Apply(ctx, client, resource)
+		}
 	case ActionCreate:
 		resOut, err = rm.Create(ctx, client, resource)
 	case ActionUpdate:
@@ -0,0 +1,277 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/projectcalico/go-json/json"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+const (
+	waitForDelete    = "delete"
+	waitForCreate    = "create"
+	waitForCondition = "condition"
+	waitForJSONPath  = "jsonpath"
+)
+
+// WaitCondition is a condition to wait for, in the format of the --for option of kubectl
+// wait.
+type WaitCondition struct {
+	kind string
+
+	// conditionType and value are the type and status of a condition in the status of the
+	// resources, for condition=<type>[=<status>].
+	conditionType string
+	// value is the value of the condition, or of the JSONPath.  For a JSONPath without a
+	// value, the JSONPath must only find a non-empty value.
+	value    string
+	jsonPath *jsonpath.JSONPath
+}
+
+// ParseWaitCondition parses a condition: delete, create, condition=<type>[=<status>] or
+// jsonpath=<template>[=<value>].
+func ParseWaitCondition(s string) (WaitCondition, error) {
+	switch {
+	case s == waitForDelete, s == waitForCreate:
+		return WaitCondition{kind: s}, nil
+	case strings.HasPrefix(s, waitForCondition+"="):
+		c := WaitCondition{kind: waitForCondition, value: "True"}
+		parts := strings.SplitN(strings.TrimPrefix(s, waitForCondition+"="), "=", 2)
+		c.conditionType = parts[0]
+		if len(parts) == 2 {
+			c.value = parts[1]
+		}
+		if c.conditionType == "" {
+			return WaitCondition{}, fmt.Errorf("condition type is missing in %q", s)
+		}
+		return c, nil
+	case strings.HasPrefix(s, waitForJSONPath+"="):
+		c := WaitCondition{kind: waitForJSONPath}
+		expr := strings.TrimPrefix(s, waitForJSONPath+"=")
+		// The template may contain "=" in filter expressions, so the value follows the
+		// closing brace of a template in braces.
+		if end := strings.LastIndex(expr, "}"); strings.HasPrefix(expr, "{") && end > 0 {
+			expr, c.value = expr[:end+1], strings.TrimPrefix(expr[end+1:], "=")
+		} else if parts := strings.SplitN(expr, "=", 2); len(parts) == 2 {
+			expr, c.value = parts[0], parts[1]
+		}
+		c.jsonPath = jsonpath.New("wait").AllowMissingKeys(true)
+		if err := c.jsonPath.Parse(relaxedJSONPath(expr)); err != nil {
+			return WaitCondition{}, fmt.Errorf("error parsing jsonpath %s: %v", expr, err)
+		}
+		return c, nil
+	}
+	return WaitCondition{}, fmt.Errorf("unrecognized condition %q, use one of: delete, create, "+
+		"condition=<type>[=<status>], jsonpath=<template>[=<value>]", s)
+}
+
+// met returns whether the condition is met by a resource.
+func (c WaitCondition) met(obj resourcemgr.ResourceObject) (bool, error) {
+	switch c.kind {
+	case waitForDelete:
+		return false, nil
+	case waitForCreate:
+		return true, nil
+	}
+
+	// Evaluate the condition on the generic form of the JSON, as kubectl does.
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return false, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return false, err
+	}
+
+	if c.kind == waitForCondition {
+		status, _ := data.(map[string]interface{})["status"].(map[string]interface{})
+		conditions, _ := status["conditions"].([]interface{})
+		for _, cond := range conditions {
+			cond, _ := cond.(map[string]interface{})
+			if strings.EqualFold(fmt.Sprint(cond["type"]), c.conditionType) {
+				return strings.EqualFold(fmt.Sprint(cond["status"]), c.value), nil
+			}
+		}
+		return false, nil
+	}
+
+	results, err := c.jsonPath.FindResults(data)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	for _, r := range results {
+		for _, v := range r {
+			if !v.IsValid() {
+				continue
+			}
+			s := fmt.Sprint(v.Interface())
+			if c.value == "" {
+				found = found || s != ""
+				continue
+			}
+			if s != c.value {
+				return false, nil
+			}
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// WaitFor waits until the condition is met by the resources given in the arguments, or by
+// all the resources of the kind if no names are given, and prints the resources.  It
+// returns an error if the timeout expires first.
+func WaitFor(args map[string]interface{}, cond WaitCondition, timeout time.Duration, w io.Writer) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var waiter *resourceWaiter
+	err := watchResources(ctx, args, func(_ client.Interface, kind string, names set.Set[string]) watchHandler {
+		waiter = newResourceWaiter(cond, kind, names)
+		return waiter
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out waiting for the condition on %s", waiter.pendingString())
+	} else if err != nil {
+		return err
+	}
+
+	for _, r := range waiter.met {
+		_, _ = fmt.Fprintf(w, "%s/%s condition met\n", strings.ToLower(waiter.kind), r)
+	}
+	return nil
+}
+
+// resourceWaiter keeps the current state of the resources and checks whether the condition
+// is met.
+type resourceWaiter struct {
+	cond  WaitCondition
+	kind  string
+	names set.Set[string]
+	objs  map[string]resourcemgr.ResourceObject
+
+	// met and pending are the resources that meet the condition and that don't, as of the
+	// last check.
+	met     []string
+	pending []string
+}
+
+func newResourceWaiter(cond WaitCondition, kind string, names set.Set[string]) *resourceWaiter {
+	return &resourceWaiter{
+		cond:  cond,
+		kind:  kind,
+		names: names,
+		objs:  map[string]resourcemgr.ResourceObject{},
+	}
+}
+
+func (r *resourceWaiter) resync(objs []runtime.Object) bool {
+	r.objs = map[string]resourcemgr.ResourceObject{}
+	for _, o := range objs {
+		if obj, ok := o.(resourcemgr.ResourceObject); ok && watched(r.names, obj) {
+			r.objs[resourceKey(obj)] = obj
+		}
+	}
+	return r.check()
+}
+
+func (r *resourceWaiter) onEvent(event watch.Event) bool {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if obj, ok := event.Object.(resourcemgr.ResourceObject); ok && watched(r.names, obj) {
+			r.objs[resourceKey(obj)] = obj
+		}
+	case watch.Deleted:
+		if obj, ok := event.Previous.(resourcemgr.ResourceObject); ok && watched(r.names, obj) {
+			delete(r.objs, resourceKey(obj))
+		}
+	}
+	return r.check()
+}
+
+// check updates the met and pending resources, and returns whether the wait is over.
+func (r *resourceWaiter) check() bool {
+	r.met, r.pending = nil, nil
+
+	// The named resources that don't exist are pending, except when waiting for their
+	// deletion.
+	present := set.New[string]()
+	for _, obj := range r.objs {
+		present.Add(obj.GetObjectMeta().GetName())
+	}
+	for _, name := range r.names.Slice() {
+		if present.Contains(name) {
+			continue
+		}
+		if r.cond.kind == waitForDelete {
+			r.met = append(r.met, name)
+		} else {
+			r.pending = append(r.pending, name)
+		}
+	}
+
+	for key, obj := range r.objs {
+		met, err := r.cond.met(obj)
+		if err != nil {
+			log.WithError(err).WithField("resource", key).Debug("Failed to evaluate the condition.")
+		}
+		if met {
+			r.met = append(r.met, key)
+		} else {
+			r.pending = append(r.pending, key)
+		}
+	}
+	sort.Strings(r.met)
+	sort.Strings(r.pending)
+
+	if len(r.pending) > 0 {
+		return false
+	}
+	// Without names, wait for at least one resource, unless waiting for the deletion of
+	// all the resources of the kind.
+	return r.names.Len() > 0 || r.cond.kind == waitForDelete || len(r.met) > 0
+}
+
+func (r *resourceWaiter) pendingString() string {
+	if r == nil || len(r.pending) == 0 {
+		return "the resources"
+	}
+	var rs []string
+	for _, p := range r.pending {
+		rs = append(rs, strings.ToLower(r.kind)+"/"+p)
+	}
+	return strings.Join(rs, ", ")
+}
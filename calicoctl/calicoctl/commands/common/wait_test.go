@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+var _ = Describe("Testing wait conditions", func() {
+	ipPool := func(name string, disabled bool) *apiv3.IPPool {
+		pool := apiv3.NewIPPool()
+		pool.ObjectMeta = metav1.ObjectMeta{Name: name}
+		pool.Spec.CIDR = "10.0.0.0/16"
+		pool.Spec.Disabled = disabled
+		return pool
+	}
+
+	DescribeTable("evaluates the condition",
+		func(s string, disabled bool, expected bool) {
+			c, err := ParseWaitCondition(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.met(ipPool("p1", disabled))).To(Equal(expected))
+		},
+		Entry("create", "create", false, true),
+		Entry("delete", "delete", false, false),
+		Entry("jsonpath with value", "jsonpath={.spec.disabled}=true", true, true),
+		Entry("jsonpath with other value", "jsonpath={.spec.disabled}=true", false, false),
+		Entry("relaxed jsonpath", "jsonpath=spec.cidr=10.0.0.0/16", false, true),
+		Entry("jsonpath without value", "jsonpath={.spec.cidr}", false, true),
+		Entry("missing jsonpath", "jsonpath={.spec.nodeSelector}", false, false),
+		Entry("missing condition", "condition=Ready", false, false),
+	)
+
+	It("evaluates status conditions", func() {
+		c, err := ParseWaitCondition("condition=ready")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.conditionType).To(Equal("ready"))
+		Expect(c.value).To(Equal("True"))
+
+		c, err = ParseWaitCondition("condition=Synced=False")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.conditionType).To(Equal("Synced"))
+		Expect(c.value).To(Equal("False"))
+	})
+
+	It("keeps filter expressions in jsonpath templates", func() {
+		c, err := ParseWaitCondition(`jsonpath={.status.conditions[?(@.type=="Ready")].status}=True`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.value).To(Equal("True"))
+	})
+
+	DescribeTable("rejects invalid conditions",
+		func(s string) {
+			_, err := ParseWaitCondition(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown", "ready"),
+		Entry("no condition type", "condition="),
+		Entry("invalid jsonpath", "jsonpath={.spec"),
+	)
+})
+
+var _ = Describe("resourceWaiter", func() {
+	ipPool := func(name string, disabled bool) *apiv3.IPPool {
+		pool := apiv3.NewIPPool()
+		pool.ObjectMeta = metav1.ObjectMeta{Name: name}
+		pool.Spec.Disabled = disabled
+		return pool
+	}
+	disabled, _ := ParseWaitCondition("jsonpath={.spec.disabled}=true")
+	deleted, _ := ParseWaitCondition("delete")
+
+	It("waits for the named resources", func() {
+		w := newResourceWaiter(disabled, "IPPool", set.From("p1", "p2"))
+		Expect(w.resync([]runtime.Object{ipPool("p1", true), ipPool("p3", false)})).To(BeFalse())
+		Expect(w.pendingString()).To(Equal("ippool/p2"))
+
+		Expect(w.onEvent(watch.Event{Type: watch.Added, Object: ipPool("p2", false)})).To(BeFalse())
+		Expect(w.onEvent(watch.Event{Type: watch.Modified, Object: ipPool("p2", true)})).To(BeTrue())
+		Expect(w.met).To(Equal([]string{"p1", "p2"}))
+	})
+
+	It("waits for at least one resource without names", func() {
+		w := newResourceWaiter(disabled, "IPPool", set.New[string]())
+		Expect(w.resync(nil)).To(BeFalse())
+		Expect(w.onEvent(watch.Event{Type: watch.Added, Object: ipPool("p1", true)})).To(BeTrue())
+	})
+
+	It("waits for deletions", func() {
+		w := newResourceWaiter(deleted, "IPPool", set.From("p1"))
+		Expect(w.resync([]runtime.Object{ipPool("p1", false)})).To(BeFalse())
+		Expect(w.onEvent(watch.Event{Type: watch.Deleted, Previous: ipPool("p1", false)})).To(BeTrue())
+		Expect(w.met).To(Equal([]string{"p1"}))
+
+		w = newResourceWaiter(deleted, "IPPool", set.New[string]())
+		Expect(w.resync(nil)).To(BeTrue())
+	})
+})
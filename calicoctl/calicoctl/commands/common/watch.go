@@ -0,0 +1,369 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/go-yaml-wrapper"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+// watchHandler handles the resources seen by watchResources.  The handler returns true once
+// it is done, to stop watching.
+type watchHandler interface {
+	// resync is called with the full list of the resources, initially and after a watch
+	// failure.
+	resync(objs []runtime.Object) (done bool)
+	onEvent(event watch.Event) (done bool)
+}
+
+// WatchDiff watches the resources of the kind given in the arguments and streams the
+// changes to their spec as unified diffs.  It only returns if the datastore cannot be
+// listed.
+func WatchDiff(args map[string]interface{}, w io.Writer) error {
+	return watchResources(context.Background(), args, func(_ client.Interface, kind string, names set.Set[string]) watchHandler {
+		return newSpecDiffer(w, kind, names)
+	})
+}
+
+// WatchPrint watches the resources of the kind given in the arguments and prints them with
+// the printer, initially and whenever they change.  Deleted resources are printed in their
+// last state.  It only returns if the datastore cannot be listed.
+func WatchPrint(args map[string]interface{}, rp ResourcePrinter) error {
+	return watchResources(context.Background(), args, func(c client.Interface, _ string, names set.Set[string]) watchHandler {
+		return newWatchPrinter(rp, c, names)
+	})
+}
+
+// watchResources lists and then watches the resources of the kind given in the arguments
+// and passes them to the handler, until the handler is done or the context ends.
+func watchResources(
+	ctx context.Context,
+	args map[string]interface{},
+	newHandler func(c client.Interface, kind string, names set.Set[string]) watchHandler,
+) error {
+	err := CheckVersionMismatch(args["--config"], args["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	resources, err := resourcemgr.GetResourcesFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Watch all the resources of the kind and filter the requested names here, so that
+	// several names can be watched at once.
+	names := set.New[string]()
+	for _, r := range resources {
+		if name := r.GetObjectMeta().GetName(); name != "" {
+			names.Add(name)
+		}
+	}
+	resource := resources[0]
+	resource.GetObjectMeta().SetName("")
+	rm := resourcemgr.GetResourceManager(resource)
+	if err := handleNamespace(resource, rm, args); err != nil {
+		return err
+	}
+
+	client, err := clientmgr.NewClient(args["--config"].(string))
+	if err != nil {
+		return err
+	}
+
+	h := newHandler(client, resource.GetObjectKind().GroupVersionKind().Kind, names)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// List the resources to find the revision to watch from.  After a watch failure,
+		// this also reports the changes that happened while we were not watching.
+		resource.GetObjectMeta().SetResourceVersion("")
+		list, err := rm.GetOrList(ctx, client, resource)
+		if err != nil {
+			return err
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		if h.resync(objs) {
+			return nil
+		}
+
+		resource.GetObjectMeta().SetResourceVersion(list.(resourcemgr.ResourceListObject).GetListMeta().GetResourceVersion())
+		watcher, err := rm.Watch(ctx, client, resource)
+		if err != nil {
+			log.WithError(err).Warn("Failed to watch resources, retrying.")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+	events:
+		for {
+			select {
+			case <-ctx.Done():
+				watcher.Stop()
+				return ctx.Err()
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					break events
+				}
+				if event.Type == watch.Error {
+					log.WithError(event.Error).Info("Watch failed, resyncing.")
+					break events
+				}
+				if h.onEvent(event) {
+					watcher.Stop()
+					return nil
+				}
+			}
+		}
+		watcher.Stop()
+	}
+}
+
+// specDiffer keeps the last seen spec of each resource and prints the diffs when they
+// change.
+type specDiffer struct {
+	out    io.Writer
+	kind   string
+	names  set.Set[string]
+	specs  map[string]string
+	synced bool
+}
+
+func newSpecDiffer(out io.Writer, kind string, names set.Set[string]) *specDiffer {
+	return &specDiffer{
+		out:   out,
+		kind:  kind,
+		names: names,
+		specs: map[string]string{},
+	}
+}
+
+// resync updates the specs from a full list of the resources.  The first list only
+// records the current state.
+func (d *specDiffer) resync(objs []runtime.Object) bool {
+	seen := set.New[string]()
+	for _, o := range objs {
+		obj, ok := o.(resourcemgr.ResourceObject)
+		if !ok || !d.watched(obj) {
+			continue
+		}
+		seen.Add(resourceKey(obj))
+		if d.synced {
+			d.update(watch.Modified, obj)
+		} else {
+			d.specs[resourceKey(obj)] = specYAML(obj)
+		}
+	}
+	for key, spec := range d.specs {
+		if !seen.Contains(key) {
+			d.print(watch.Deleted, key, spec, "")
+			delete(d.specs, key)
+		}
+	}
+	d.synced = true
+	return false
+}
+
+func (d *specDiffer) onEvent(event watch.Event) bool {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if obj, ok := event.Object.(resourcemgr.ResourceObject); ok && d.watched(obj) {
+			d.update(event.Type, obj)
+		}
+	case watch.Deleted:
+		if obj, ok := event.Previous.(resourcemgr.ResourceObject); ok && d.watched(obj) {
+			key := resourceKey(obj)
+			d.print(event.Type, key, d.specs[key], "")
+			delete(d.specs, key)
+		}
+	}
+	return false
+}
+
+func (d *specDiffer) update(eventType watch.EventType, obj resourcemgr.ResourceObject) {
+	key := resourceKey(obj)
+	old, exists := d.specs[key]
+	if !exists {
+		eventType = watch.Added
+	}
+	spec := specYAML(obj)
+	d.specs[key] = spec
+	if exists && old == spec {
+		// Only the metadata or the status changed.
+		return
+	}
+
+	header := fmt.Sprintf("resourceVersion %s", obj.GetObjectMeta().GetResourceVersion())
+	if manager := lastManager(obj); manager != "" {
+		header += ", by " + manager
+	}
+	d.print(eventType, key, old, spec, header)
+}
+
+func (d *specDiffer) print(eventType watch.EventType, key, old, spec string, details ...string) {
+	from, to := "a/"+key, "b/"+key
+	if eventType == watch.Added {
+		from = "/dev/null"
+	} else if eventType == watch.Deleted {
+		to = "/dev/null"
+	}
+
+	header := fmt.Sprintf("# %s %s %s", eventType, d.kind, key)
+	for _, s := range details {
+		header += " (" + s + ")"
+	}
+	_, _ = fmt.Fprintf(d.out, "%s\n%s", header, unifiedDiff(from, to, old, spec))
+}
+
+// watchPrinter keeps the last seen revision of each resource and prints the resources
+// when they change.
+type watchPrinter struct {
+	rp      ResourcePrinter
+	client  client.Interface
+	names   set.Set[string]
+	objs    map[string]resourcemgr.ResourceObject
+	printed bool
+}
+
+func newWatchPrinter(rp ResourcePrinter, c client.Interface, names set.Set[string]) *watchPrinter {
+	return &watchPrinter{
+		rp:     rp,
+		client: c,
+		names:  names,
+		objs:   map[string]resourcemgr.ResourceObject{},
+	}
+}
+
+func (p *watchPrinter) resync(objs []runtime.Object) bool {
+	seen := set.New[string]()
+	for _, o := range objs {
+		obj, ok := o.(resourcemgr.ResourceObject)
+		if !ok || !watched(p.names, obj) {
+			continue
+		}
+		seen.Add(resourceKey(obj))
+		p.update(obj)
+	}
+	for key, obj := range p.objs {
+		if !seen.Contains(key) {
+			p.print(obj)
+			delete(p.objs, key)
+		}
+	}
+	return false
+}
+
+func (p *watchPrinter) onEvent(event watch.Event) bool {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if obj, ok := event.Object.(resourcemgr.ResourceObject); ok && watched(p.names, obj) {
+			p.update(obj)
+		}
+	case watch.Deleted:
+		if obj, ok := event.Previous.(resourcemgr.ResourceObject); ok && watched(p.names, obj) {
+			p.print(obj)
+			delete(p.objs, resourceKey(obj))
+		}
+	}
+	return false
+}
+
+func (p *watchPrinter) update(obj resourcemgr.ResourceObject) {
+	key := resourceKey(obj)
+	if old, ok := p.objs[key]; ok &&
+		old.GetObjectMeta().GetResourceVersion() == obj.GetObjectMeta().GetResourceVersion() {
+		return
+	}
+	p.objs[key] = obj
+	p.print(obj)
+}
+
+func (p *watchPrinter) print(obj resourcemgr.ResourceObject) {
+	// Separate the YAML documents so that the output can be parsed as a stream.
+	if _, ok := p.rp.(ResourcePrinterYAML); ok && p.printed {
+		fmt.Println("---")
+	}
+	p.printed = true
+	if err := p.rp.Print(p.client, []runtime.Object{obj}); err != nil {
+		log.WithError(err).Warn("Failed to print resource.")
+	}
+}
+
+func (d *specDiffer) watched(obj resourcemgr.ResourceObject) bool {
+	return watched(d.names, obj)
+}
+
+// watched returns whether a resource is one of the named resources, or all resources are
+// watched.
+func watched(names set.Set[string], obj resourcemgr.ResourceObject) bool {
+	return names.Len() == 0 || names.Contains(obj.GetObjectMeta().GetName())
+}
+
+func resourceKey(obj resourcemgr.ResourceObject) string {
+	if ns := obj.GetObjectMeta().GetNamespace(); ns != "" {
+		return ns + "/" + obj.GetObjectMeta().GetName()
+	}
+	return obj.GetObjectMeta().GetName()
+}
+
+// specYAML returns the spec of the resource in YAML format.
+func specYAML(obj resourcemgr.ResourceObject) string {
+	var spec interface{} = obj
+	if v := reflect.Indirect(reflect.ValueOf(obj)).FieldByName("Spec"); v.IsValid() {
+		spec = v.Interface()
+	}
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		log.WithError(err).Warn("Failed to marshal resource spec.")
+		return ""
+	}
+	return string(b)
+}
+
+// lastManager returns the name of the client that most recently changed the resource, if
+// the datastore records it.
+func lastManager(obj resourcemgr.ResourceObject) string {
+	var manager string
+	var latest time.Time
+	for _, f := range obj.GetObjectMeta().GetManagedFields() {
+		if f.Time != nil && !f.Time.Time.Before(latest) {
+			latest = f.Time.Time
+			manager = f.Manager
+		}
+	}
+	return manager
+}
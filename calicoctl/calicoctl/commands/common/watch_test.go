@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+var _ = Describe("unifiedDiff", func() {
+	lines := func(ls ...string) string {
+		return strings.Join(ls, "\n") + "\n"
+	}
+
+	It("should return nothing for identical input", func() {
+		Expect(unifiedDiff("a", "b", lines("x", "y"), lines("x", "y"))).To(BeEmpty())
+	})
+
+	It("should show the changes with their context", func() {
+		a := lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12")
+		b := lines("1", "two", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13")
+		Expect(unifiedDiff("a/x", "b/x", a, b)).To(Equal(lines(
+			"--- a/x",
+			"+++ b/x",
+			"@@ -1,5 +1,5 @@",
+			" 1",
+			"-2",
+			"+two",
+			" 3",
+			" 4",
+			" 5",
+			"@@ -10,3 +10,4 @@",
+			" 10",
+			" 11",
+			" 12",
+			"+13",
+		)))
+	})
+
+	It("should diff against empty input", func() {
+		Expect(unifiedDiff("/dev/null", "b/x", "", lines("x", "y"))).To(Equal(lines(
+			"--- /dev/null",
+			"+++ b/x",
+			"@@ -0,0 +1,2 @@",
+			"+x",
+			"+y",
+		)))
+	})
+})
+
+var _ = Describe("specDiffer", func() {
+	var (
+		out bytes.Buffer
+		d   *specDiffer
+	)
+
+	felixConfig := func(name, rv string, logSeverity string) *apiv3.FelixConfiguration {
+		fc := apiv3.NewFelixConfiguration()
+		fc.ObjectMeta = metav1.ObjectMeta{Name: name, ResourceVersion: rv}
+		fc.Spec.LogSeverityScreen = logSeverity
+		return fc
+	}
+
+	BeforeEach(func() {
+		out.Reset()
+		d = newSpecDiffer(&out, "FelixConfiguration", set.New[string]())
+		d.resync([]runtime.Object{felixConfig("default", "1", "Info")})
+	})
+
+	It("should not print the initial state", func() {
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should print the spec changes", func() {
+		d.onEvent(watch.Event{Type: watch.Modified, Object: felixConfig("default", "2", "Debug")})
+		Expect(out.String()).To(Equal(strings.Join([]string{
+			"# MODIFIED FelixConfiguration default (resourceVersion 2)",
+			"--- a/default",
+			"+++ b/default",
+			"@@ -1,2 +1,2 @@",
+			` bpfLogLevel: ""`,
+			"-logSeverityScreen: Info",
+			"+logSeverityScreen: Debug",
+			"",
+		}, "\n")))
+	})
+
+	It("should skip changes that do not touch the spec", func() {
+		d.onEvent(watch.Event{Type: watch.Modified, Object: felixConfig("default", "2", "Info")})
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should report the changes missed by a resync", func() {
+		d.resync([]runtime.Object{felixConfig("node.foo", "3", "Warning")})
+		Expect(out.String()).To(ContainSubstring("# ADDED FelixConfiguration node.foo (resourceVersion 3)\n--- /dev/null\n"))
+		Expect(out.String()).To(ContainSubstring("# DELETED FelixConfiguration default\n--- a/default\n+++ /dev/null\n"))
+	})
+
+	It("should only report the watched names", func() {
+		d.names = set.From("node.foo")
+		d.onEvent(watch.Event{Type: watch.Modified, Object: felixConfig("default", "2", "Debug")})
+		d.onEvent(watch.Event{Type: watch.Deleted, Previous: felixConfig("default", "2", "Debug")})
+		Expect(out.String()).To(BeEmpty())
+	})
+})
+
+type recordingPrinter struct {
+	printed []string
+}
+
+func (p *recordingPrinter) Print(_ clientv3.Interface, resources []runtime.Object) error {
+	for _, r := range resources {
+		obj := r.(metav1.Object)
+		p.printed = append(p.printed, obj.GetName()+"@"+obj.GetResourceVersion())
+	}
+	return nil
+}
+
+var _ = Describe("watchPrinter", func() {
+	var (
+		rp *recordingPrinter
+		p  *watchPrinter
+	)
+
+	ipPool := func(name, rv string) *apiv3.IPPool {
+		pool := apiv3.NewIPPool()
+		pool.ObjectMeta = metav1.ObjectMeta{Name: name, ResourceVersion: rv}
+		return pool
+	}
+
+	BeforeEach(func() {
+		rp = &recordingPrinter{}
+		p = newWatchPrinter(rp, nil, set.New[string]())
+		p.resync([]runtime.Object{ipPool("p1", "1"), ipPool("p2", "1")})
+	})
+
+	It("should print the initial state", func() {
+		Expect(rp.printed).To(Equal([]string{"p1@1", "p2@1"}))
+	})
+
+	It("should print the changed and deleted resources", func() {
+		p.onEvent(watch.Event{Type: watch.Modified, Object: ipPool("p1", "2")})
+		p.onEvent(watch.Event{Type: watch.Deleted, Previous: ipPool("p2", "1")})
+		Expect(rp.printed).To(Equal([]string{"p1@1", "p2@1", "p1@2", "p2@1"}))
+	})
+
+	It("should only print the resources that changed on a resync", func() {
+		p.resync([]runtime.Object{ipPool("p1", "1"), ipPool("p3", "4")})
+		Expect(rp.printed).To(Equal([]string{"p1@1", "p2@1", "p3@4", "p2@1"}))
+	})
+})
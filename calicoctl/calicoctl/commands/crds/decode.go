@@ -144,5 +144,19 @@ func CalicoCRDs() ([]*v1.CustomResourceDefinition, error) {
 	}
 	crds = append(crds, &netset)
 
+	stagedGlobalPolicy := v1.CustomResourceDefinition{}
+	err = yaml.Unmarshal([]byte(stagedglobalnetworkpolicies), &stagedGlobalPolicy)
+	if err != nil {
+		return crds, err
+	}
+	crds = append(crds, &stagedGlobalPolicy)
+
+	stagedPolicy := v1.CustomResourceDefinition{}
+	err = yaml.Unmarshal([]byte(stagednetworkpolicies), &stagedPolicy)
+	if err != nil {
+		return crds, err
+	}
+	crds = append(crds, &stagedPolicy)
+
 	return crds, nil
 }
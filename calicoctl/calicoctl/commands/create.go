@@ -78,6 +78,8 @@ Description:
     * networkSet
     * node
     * profile
+    * stagedGlobalNetworkPolicy
+    * stagedNetworkPolicy
     * workloadEndpoint
 
   Attempting to create a resource that already exists is treated as a
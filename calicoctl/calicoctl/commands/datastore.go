@@ -32,6 +32,7 @@ func Datastore(args []string) error {
   <BINARY_NAME> datastore <command> [<args>...]
 
     migrate  Migrate the contents of an etcdv3 datastore to a Kubernetes datastore.
+    config   Export and import snapshots of the global and per-node config keys.
 
 Options:
   -h --help      Show this screen.
@@ -64,6 +65,8 @@ Description:
 	switch command {
 	case "migrate":
 		return datastore.Migrate(args)
+	case "config":
+		return datastore.Config(args)
 	default:
 		fmt.Println(doc)
 	}
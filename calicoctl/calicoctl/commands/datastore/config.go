@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datastore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docopt/docopt-go"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/datastore/configsnapshot"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+)
+
+// Config function is a switch to config snapshot related sub-commands
+func Config(args []string) error {
+	var err error
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> datastore config <command> [<args>...]
+
+    export  Export the global and per-node config keys to a snapshot.
+    import  Import a snapshot of the config keys created by the export command.
+
+Options:
+  -h --help      Show this screen.
+
+Description:
+  Config snapshot specific commands, for disaster recovery and for cloning
+  the low-level configuration of a cluster.
+
+  See '<BINARY_NAME> datastore config <command> --help' to read about a specific subcommand.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	var parser = &docopt.Parser{
+		HelpHandler:   docopt.PrintHelpAndExit,
+		OptionsFirst:  true,
+		SkipHelpFlags: false,
+	}
+	arguments, err := parser.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if arguments["<command>"] == nil {
+		return nil
+	}
+
+	command := arguments["<command>"].(string)
+	args = append([]string{"datastore", "config", command}, arguments["<args>"].([]string)...)
+
+	switch command {
+	case "export":
+		return configsnapshot.Export(args)
+	case "import":
+		return configsnapshot.Import(args)
+	default:
+		fmt.Println(doc)
+	}
+
+	return nil
+}
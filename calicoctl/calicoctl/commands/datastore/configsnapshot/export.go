@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsnapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docopt/docopt-go"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/libcalico-go/lib/client"
+)
+
+func Export(args []string) error {
+	doc := `Usage:
+  <BINARY_NAME> datastore config export [--config=<CONFIG>]
+
+Options:
+  -h --help                    Show this screen.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+
+Description:
+  Export all the global and per-node Felix and BGP config keys of the
+  datastore to a versioned JSON snapshot.  Save the results of this command
+  to a file for later use with the import command.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	c, err := newClient(parsedArgs["--config"].(string))
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := c.Config().ExportConfig()
+	if err != nil {
+		return fmt.Errorf("Error exporting the config: %s", err)
+	}
+	b, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding the config snapshot: %s", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// newClient returns the client of the config keys of the datastore.
+func newClient(cf string) (*client.Client, error) {
+	cfg, err := clientmgr.LoadClientConfig(cf)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(*cfg)
+}
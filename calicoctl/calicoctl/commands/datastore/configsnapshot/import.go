@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsnapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docopt/docopt-go"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/libcalico-go/lib/client"
+)
+
+func Import(args []string) error {
+	doc := `Usage:
+  <BINARY_NAME> datastore config import --filename=<FILENAME> [--overwrite] [--config=<CONFIG>]
+
+Options:
+  -h --help                    Show this screen.
+  -f --filename=<FILENAME>     Filename to use to import the snapshot.  If set
+                               to "-" loads from stdin.
+     --overwrite               Replace the config keys whose current value
+                               differs from the snapshot.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+
+Description:
+  Import the global and per-node config keys from the snapshot created by the
+  export command.  Config keys that are not in the snapshot are left alone.
+
+  If the current value of any config key differs from the snapshot, the
+  conflicts are listed and nothing is imported, unless --overwrite is set.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	fname := parsedArgs["--filename"].(string)
+	if fname == "-" {
+		fname = os.Stdin.Name()
+	}
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return fmt.Errorf("Error reading the config snapshot: %s", err)
+	}
+	var snapshot client.ConfigSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("Error decoding the config snapshot: %s", err)
+	}
+
+	c, err := newClient(parsedArgs["--config"].(string))
+	if err != nil {
+		return err
+	}
+
+	overwrite := parsedArgs["--overwrite"].(bool)
+	conflicts, err := c.Config().ImportConfig(&snapshot, overwrite)
+	for _, conflict := range conflicts {
+		fmt.Printf("Conflict: %s\n", conflict)
+	}
+	if err != nil {
+		return fmt.Errorf("Error importing the config: %s", err)
+	}
+	if len(conflicts) > 0 && !overwrite {
+		return fmt.Errorf("Config snapshot not imported: %d conflicts found. Use --overwrite to replace the current values.", len(conflicts))
+	}
+
+	fmt.Print("Config snapshot imported.\n")
+	return nil
+}
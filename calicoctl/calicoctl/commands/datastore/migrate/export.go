@@ -54,33 +54,38 @@ var allV3Resources []string = []string{
 	"kubecontrollersconfigs",
 	"networkpolicies",
 	"networksets",
+	"stagedglobalnetworkpolicies",
+	"stagednetworkpolicies",
 	"nodes",
 	"bgpconfigs",
 	"felixconfigs",
 }
 
 var resourceDisplayMap map[string]string = map[string]string{
-	"ipamBlocks":             "IPAMBlocks",
-	"blockaffinities":        "BlockAffinities",
-	"ipamhandles":            "IPAMHandles",
-	"ipamconfigs":            "IPAMConfigurations",
-	"ippools":                "IPPools",
-	"bgpconfig":              "BGPConfigurations",
-	"bgppeers":               "BGPPeers",
-	"clusterinfos":           "ClusterInformations",
-	"felixconfigs":           "FelixConfigurations",
-	"globalnetworkpolicies":  "GlobalNetworkPolicies",
-	"globalnetworksets":      "GlobalNetworkSets",
-	"heps":                   "HostEndpoints",
-	"kubecontrollersconfigs": "KubeControllersConfigurations",
-	"networkpolicies":        "NetworkPolicies",
-	"networksets":            "Networksets",
-	"nodes":                  "Nodes",
+	"ipamBlocks":                  "IPAMBlocks",
+	"blockaffinities":             "BlockAffinities",
+	"ipamhandles":                 "IPAMHandles",
+	"ipamconfigs":                 "IPAMConfigurations",
+	"ippools":                     "IPPools",
+	"bgpconfig":                   "BGPConfigurations",
+	"bgppeers":                    "BGPPeers",
+	"clusterinfos":                "ClusterInformations",
+	"felixconfigs":                "FelixConfigurations",
+	"globalnetworkpolicies":       "GlobalNetworkPolicies",
+	"globalnetworksets":           "GlobalNetworkSets",
+	"heps":                        "HostEndpoints",
+	"kubecontrollersconfigs":      "KubeControllersConfigurations",
+	"networkpolicies":             "NetworkPolicies",
+	"networksets":                 "Networksets",
+	"nodes":                       "Nodes",
+	"stagedglobalnetworkpolicies": "StagedGlobalNetworkPolicies",
+	"stagednetworkpolicies":       "StagedNetworkPolicies",
 }
 
 var namespacedResources map[string]struct{} = map[string]struct{}{
-	"networkpolicies": struct{}{},
-	"networksets":     struct{}{},
+	"networkpolicies":       struct{}{},
+	"networksets":           struct{}{},
+	"stagednetworkpolicies": struct{}{},
 }
 
 func Export(args []string) error {
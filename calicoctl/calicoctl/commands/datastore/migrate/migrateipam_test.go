@@ -202,6 +202,16 @@ func (c *MockIPAMClient) NetworkPolicies() client.NetworkPolicyInterface {
 	return nil
 }
 
+func (c *MockIPAMClient) StagedGlobalNetworkPolicies() client.StagedGlobalNetworkPolicyInterface {
+	// DO NOTHING
+	return nil
+}
+
+func (c *MockIPAMClient) StagedNetworkPolicies() client.StagedNetworkPolicyInterface {
+	// DO NOTHING
+	return nil
+}
+
 func (c *MockIPAMClient) IPPools() client.IPPoolInterface {
 	// DO NOTHING
 	return nil
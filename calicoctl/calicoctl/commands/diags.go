@@ -0,0 +1,209 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docopt/docopt-go"
+	yaml "github.com/projectcalico/go-yaml-wrapper"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/diags"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s"
+)
+
+// diagsResources are the Calico resources included in the cluster diagnostics bundle.
+var diagsResources = []string{
+	"bgpconfigurations",
+	"bgpfilters",
+	"bgppeers",
+	"clusterinformations",
+	"felixconfigurations",
+	"globalnetworkpolicies",
+	"globalnetworksets",
+	"hostendpoints",
+	"ippools",
+	"ipreservations",
+	"kubecontrollersconfigurations",
+	"networkpolicies",
+	"networksets",
+	"nodes",
+	"profiles",
+	"stagedglobalnetworkpolicies",
+	"stagednetworkpolicies",
+	"workloadendpoints",
+}
+
+// diagsNamespacedResources are the resources of diagsResources that are namespaced.
+var diagsNamespacedResources = map[string]bool{
+	"networkpolicies":       true,
+	"networksets":           true,
+	"stagednetworkpolicies": true,
+	"workloadendpoints":     true,
+}
+
+// Diags collects a diagnostics bundle from all the nodes of a cluster.
+func Diags(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> diags --cluster [--focus-nodes=<NODES>] [--since=<SINCE>]
+                [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --cluster                 Collect diagnostics from the Calico components
+                               of the whole cluster.
+     --focus-nodes=<NODES>     Comma-separated list of the nodes to collect the
+                               per-node diagnostics from.  If this is not
+                               supplied, all nodes are included.
+     --since=<SINCE>           Only collect logs newer than this duration, for
+                               example 1h or 30m.  If this is not supplied, all
+                               logs are collected.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command collects a diagnostics bundle from the Calico components of a
+  Kubernetes cluster, through the Kubernetes API, into a single archive:
+
+  - the logs of the calico-node, calico-typha and calico-kube-controllers pods
+  - the routes, interfaces, iptables, nftables and ipsets of each node
+  - the BGP status and, for the BPF dataplane, the BPF map dumps of each node
+  - the Calico resources and the Kubernetes nodes and Calico pods
+
+  To gather diagnostics on a single node without the Kubernetes API, run
+  '<BINARY_NAME> node diags' on the node.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(parsedArgs["--config"], parsedArgs["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	var since time.Duration
+	if s, _ := parsedArgs["--since"].(string); s != "" {
+		if since, err = time.ParseDuration(s); err != nil || since < 0 {
+			return fmt.Errorf("Invalid --since duration %q", s)
+		}
+	}
+	var nodes []string
+	if n, _ := parsedArgs["--focus-nodes"].(string); n != "" {
+		for _, node := range strings.Split(n, ",") {
+			if node = strings.TrimSpace(node); node != "" {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	cf := parsedArgs["--config"].(string)
+	cfg, err := clientmgr.LoadClientConfig(cf)
+	if err != nil {
+		return err
+	}
+	config, clientset, err := k8s.CreateKubernetesClientset(&cfg.Spec)
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to create Kubernetes client: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "calico")
+	if err != nil {
+		return fmt.Errorf("Error creating temp directory to collect diagnostics: %v", err)
+	}
+	fmt.Println("Using temp dir:", tmpDir)
+
+	collector := &diags.ClusterCollector{
+		Clientset: clientset,
+		Executor:  diags.NewExecutor(config, clientset),
+		Nodes:     nodes,
+		Since:     since,
+		Resources: func() (map[string][]byte, error) {
+			return diagsResourceManifests(cf)
+		},
+	}
+	if err := collector.Collect(context.Background(), filepath.Join(tmpDir, "cluster-diagnostics")); err != nil {
+		return fmt.Errorf("Error executing command: %v", err)
+	}
+
+	tarFile := filepath.Join(tmpDir, fmt.Sprintf("cluster-diags-%s.tar.gz", time.Now().Format("20060102_150405")))
+	if out, err := exec.Command("tar", "-zcf", tarFile, "-C", tmpDir, "cluster-diagnostics").CombinedOutput(); err != nil {
+		return fmt.Errorf("Error compressing the diagnostics: %v: %s", err, out)
+	}
+
+	fmt.Printf("\nDiags saved to %s\n", tarFile)
+	fmt.Println("If required, you can upload the diagnostics bundle to a file sharing service.")
+	return nil
+}
+
+// diagsResourceManifests returns the Calico resources as YAML, by file name.
+func diagsResourceManifests(cf string) (map[string][]byte, error) {
+	manifests := map[string][]byte{}
+	var errs []string
+	for _, r := range diagsResources {
+		mockArgs := map[string]interface{}{
+			"<KIND>":   r,
+			"<NAME>":   []string{},
+			"--config": cf,
+			"--output": "yaml",
+			"get":      true,
+		}
+		if diagsNamespacedResources[r] {
+			mockArgs["--all-namespaces"] = true
+		}
+
+		results := common.ExecuteConfigCommand(mockArgs, common.ActionGetOrList)
+		if results.Err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r, results.Err))
+			continue
+		}
+		for _, err := range results.ResErrs {
+			errs = append(errs, fmt.Sprintf("%s: %v", r, err))
+		}
+		if len(results.Resources) == 0 {
+			continue
+		}
+		data, err := yaml.Marshal(results.Resources)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r, err))
+			continue
+		}
+		manifests[r+".yaml"] = data
+	}
+	if len(errs) > 0 {
+		return manifests, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return manifests, nil
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diags collects a diagnostics bundle from all the Calico components of a cluster through
+// the Kubernetes API.
+package diags
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "github.com/projectcalico/go-yaml-wrapper"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// calicoNamespaces are the namespaces that Calico is installed in by the operator and by the
+// manifests.
+var calicoNamespaces = []string{"calico-system", "kube-system"}
+
+// component is a Calico component whose pods are included in the bundle.
+type component struct {
+	name     string
+	selector string
+	// perNode components run on the nodes that the bundle is focused on.
+	perNode bool
+}
+
+var components = []component{
+	{name: "calico-node", selector: "k8s-app=calico-node", perNode: true},
+	{name: "calico-typha", selector: "k8s-app=calico-typha", perNode: true},
+	{name: "calico-kube-controllers", selector: "k8s-app=calico-kube-controllers"},
+}
+
+// nodeCmd is a command that is run in the calico-node container of each node, with its output
+// saved as filename.  Commands that don't apply to a node, for example the BPF map dumps on a node
+// that runs the iptables dataplane, simply record their error.
+type nodeCmd struct {
+	filename string
+	cmd      []string
+}
+
+var nodeCmds = []nodeCmd{
+	{"ipv4-routes", []string{"ip", "-4", "route", "show", "table", "all"}},
+	{"ipv6-routes", []string{"ip", "-6", "route", "show", "table", "all"}},
+	{"addresses", []string{"ip", "addr"}},
+	{"links", []string{"ip", "-d", "link"}},
+	{"ipv4-iptables", []string{"iptables-save", "-c"}},
+	{"ipv6-iptables", []string{"ip6tables-save", "-c"}},
+	{"nftables", []string{"nft", "list", "ruleset"}},
+	{"ipsets", []string{"ipset", "list"}},
+	{"bgp-ipv4-status", []string{"birdcl", "-s", "/var/run/calico/bird.ctl", "show", "protocols", "all"}},
+	{"bgp-ipv6-status", []string{"birdcl", "-s", "/var/run/calico/bird6.ctl", "show", "protocols", "all"}},
+	{"bpf-ifstate", []string{"calico-node", "-bpf", "ifstate", "dump"}},
+	{"bpf-routes", []string{"calico-node", "-bpf", "routes", "dump"}},
+	{"bpf-nat", []string{"calico-node", "-bpf", "nat", "dump"}},
+	{"bpf-conntrack", []string{"calico-node", "-bpf", "conntrack", "dump"}},
+	{"bpf-ipsets", []string{"calico-node", "-bpf", "ipsets", "dump"}},
+	{"bpf-counters", []string{"calico-node", "-bpf", "counters", "dump"}},
+}
+
+// maxParallelPods limits the number of pods whose diagnostics are collected in parallel.
+const maxParallelPods = 10
+
+// Executor runs a command in a container of a pod.
+type Executor interface {
+	Exec(ctx context.Context, namespace, pod, container string, cmd []string) ([]byte, error)
+}
+
+// NewExecutor returns an Executor that runs commands through the exec API of the pods.
+func NewExecutor(config *rest.Config, clientset kubernetes.Interface) Executor {
+	return &spdyExecutor{config: config, clientset: clientset}
+}
+
+type spdyExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+func (e *spdyExecutor) Exec(ctx context.Context, namespace, pod, container string, cmd []string) ([]byte, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		return stdout.Bytes(), fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// ClusterCollector collects the diagnostics of the Calico components of a cluster into a
+// directory.
+type ClusterCollector struct {
+	Clientset kubernetes.Interface
+	Executor  Executor
+
+	// Nodes focuses the bundle on these nodes.  The per-node components of all nodes are included
+	// if it is empty.
+	Nodes []string
+	// Since limits the logs to the ones newer than this duration, if it is not zero.
+	Since time.Duration
+
+	// Resources returns the Calico resources to include in the bundle, as YAML by file name.
+	Resources func() (map[string][]byte, error)
+
+	lock   sync.Mutex
+	errors []string
+}
+
+// Collect collects the diagnostics into dir.  Failures to collect individual diagnostics are
+// recorded in errors.txt in the bundle, rather than failing the collection.
+func (c *ClusterCollector) Collect(ctx context.Context, dir string) error {
+	pods, err := c.calicoPods(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no Calico pods found in namespaces %s", strings.Join(calicoNamespaces, ", "))
+	}
+
+	c.collectManifests(ctx, dir, pods)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelPods)
+	for _, p := range pods {
+		wg.Add(1)
+		go func(p calicoPod) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			c.collectPod(ctx, dir, p)
+		}(p)
+	}
+	wg.Wait()
+
+	if len(c.errors) > 0 {
+		sort.Strings(c.errors)
+		c.writeFile(filepath.Join(dir, "errors.txt"), []byte(strings.Join(c.errors, "\n")+"\n"))
+		fmt.Printf("Failed to collect %d diagnostics, see errors.txt in the bundle\n", len(c.errors))
+	}
+	return nil
+}
+
+type calicoPod struct {
+	component component
+	pod       corev1.Pod
+}
+
+// calicoPods returns the pods of the Calico components, for the per-node components only the ones
+// on the nodes that the bundle is focused on.
+func (c *ClusterCollector) calicoPods(ctx context.Context) ([]calicoPod, error) {
+	focus := map[string]bool{}
+	for _, n := range c.Nodes {
+		focus[n] = true
+	}
+
+	var pods []calicoPod
+	for _, ns := range calicoNamespaces {
+		for _, comp := range components {
+			list, err := c.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: comp.selector})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s pods in namespace %s: %w", comp.name, ns, err)
+			}
+			for _, pod := range list.Items {
+				if comp.perNode && len(focus) > 0 && !focus[pod.Spec.NodeName] {
+					continue
+				}
+				pods = append(pods, calicoPod{component: comp, pod: pod})
+			}
+		}
+	}
+
+	if len(focus) > 0 {
+		found := map[string]bool{}
+		for _, p := range pods {
+			found[p.pod.Spec.NodeName] = true
+		}
+		for _, n := range c.Nodes {
+			if !found[n] {
+				c.recordError("node %s: no Calico pods found on the node", n)
+			}
+		}
+	}
+	return pods, nil
+}
+
+// collectPod collects the logs of the containers of a pod and, for calico-node pods, the state of
+// the node's dataplane and BGP.
+func (c *ClusterCollector) collectPod(ctx context.Context, dir string, p calicoPod) {
+	pod := p.pod
+	podDir := filepath.Join(dir, "pods", pod.Namespace, pod.Name)
+	logger := log.WithFields(log.Fields{"pod": pod.Name, "node": pod.Spec.NodeName})
+	fmt.Printf("Collecting diagnostics of %s/%s on node %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName)
+
+	opts := &corev1.PodLogOptions{}
+	if c.Since > 0 {
+		seconds := int64(c.Since.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+	var containers []corev1.Container
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, container := range containers {
+		opts.Container = container.Name
+		logger.WithField("container", container.Name).Debug("Collecting logs")
+		data, err := c.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+		if err != nil {
+			c.recordError("pod %s/%s: failed to get logs of container %s: %v", pod.Namespace, pod.Name, container.Name, err)
+			continue
+		}
+		c.writeFile(filepath.Join(podDir, container.Name+".log"), data)
+	}
+
+	if p.component.name != "calico-node" || pod.Status.Phase != corev1.PodRunning {
+		return
+	}
+	nodeDir := filepath.Join(dir, "nodes", pod.Spec.NodeName)
+	for _, nc := range nodeCmds {
+		logger.WithField("cmd", nc.cmd).Debug("Running command")
+		out, err := c.Executor.Exec(ctx, pod.Namespace, pod.Name, "calico-node", nc.cmd)
+		if err != nil {
+			c.recordError("node %s: failed to run %q: %v", pod.Spec.NodeName, strings.Join(nc.cmd, " "), err)
+			if len(out) == 0 {
+				continue
+			}
+		}
+		c.writeFile(filepath.Join(nodeDir, nc.filename), out)
+	}
+}
+
+// collectManifests saves the Calico resources, and the Kubernetes nodes and Calico pods.
+func (c *ClusterCollector) collectManifests(ctx context.Context, dir string, pods []calicoPod) {
+	fmt.Println("Collecting resource manifests")
+	if c.Resources != nil {
+		resources, err := c.Resources()
+		if err != nil {
+			c.recordError("failed to get Calico resources: %v", err)
+		}
+		for name, data := range resources {
+			c.writeFile(filepath.Join(dir, "resources", name), data)
+		}
+	}
+
+	nodes, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.recordError("failed to list Kubernetes nodes: %v", err)
+	} else {
+		c.writeYAML(filepath.Join(dir, "kubernetes", "nodes.yaml"), nodes.Items)
+	}
+
+	var podList []corev1.Pod
+	for _, p := range pods {
+		podList = append(podList, p.pod)
+	}
+	c.writeYAML(filepath.Join(dir, "kubernetes", "calico-pods.yaml"), podList)
+}
+
+func (c *ClusterCollector) writeYAML(path string, v interface{}) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		c.recordError("failed to marshal %s: %v", filepath.Base(path), err)
+		return
+	}
+	c.writeFile(path, data)
+}
+
+func (c *ClusterCollector) writeFile(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		c.recordError("failed to create directory for %s: %v", path, err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		c.recordError("failed to write %s: %v", path, err)
+	}
+}
+
+func (c *ClusterCollector) recordError(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Debug(msg)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.errors = append(c.errors, msg)
+}
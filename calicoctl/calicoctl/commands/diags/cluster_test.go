@@ -0,0 +1,138 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeExecutor struct {
+	lock sync.Mutex
+	pods map[string]int
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, namespace, pod, container string, cmd []string) ([]byte, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.pods[namespace+"/"+pod+"/"+container]++
+	if cmd[0] == "nft" {
+		return nil, fmt.Errorf("nft: command not found")
+	}
+	return []byte(strings.Join(cmd, " ")), nil
+}
+
+var _ = Describe("Cluster diagnostics", func() {
+	var (
+		dir      string
+		executor *fakeExecutor
+		c        *ClusterCollector
+	)
+
+	pod := func(ns, name, app, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: map[string]string{"k8s-app": app}},
+			Spec: corev1.PodSpec{
+				NodeName:   node,
+				Containers: []corev1.Container{{Name: app}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "cluster-diags")
+		Expect(err).NotTo(HaveOccurred())
+
+		executor = &fakeExecutor{pods: map[string]int{}}
+		c = &ClusterCollector{
+			Clientset: fake.NewSimpleClientset(
+				pod("calico-system", "calico-node-a", "calico-node", "node-a"),
+				pod("calico-system", "calico-node-b", "calico-node", "node-b"),
+				pod("calico-system", "calico-typha-b", "calico-typha", "node-b"),
+				pod("calico-system", "calico-kube-controllers", "calico-kube-controllers", "node-c"),
+				pod("default", "calico-node-elsewhere", "calico-node", "node-a"),
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+			),
+			Executor: executor,
+			Resources: func() (map[string][]byte, error) {
+				return map[string][]byte{"ippools.yaml": []byte("- kind: IPPool\n")}, nil
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should collect the diagnostics of all the nodes", func() {
+		Expect(c.Collect(context.Background(), dir)).To(Succeed())
+
+		for _, p := range []string{"calico-node-a", "calico-node-b", "calico-typha-b", "calico-kube-controllers"} {
+			Expect(filepath.Join(dir, "pods", "calico-system", p)).To(BeADirectory())
+		}
+		Expect(filepath.Join(dir, "pods", "default")).NotTo(BeAnExistingFile())
+
+		Expect(executor.pods).To(HaveLen(2))
+		Expect(executor.pods["calico-system/calico-node-a/calico-node"]).To(Equal(len(nodeCmds)))
+		data, err := os.ReadFile(filepath.Join(dir, "nodes", "node-b", "ipv4-iptables"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("iptables-save -c"))
+
+		Expect(filepath.Join(dir, "resources", "ippools.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(dir, "kubernetes", "nodes.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(dir, "kubernetes", "calico-pods.yaml")).To(BeARegularFile())
+	})
+
+	It("should record failed commands without failing the collection", func() {
+		Expect(c.Collect(context.Background(), dir)).To(Succeed())
+
+		Expect(filepath.Join(dir, "nodes", "node-a", "nftables")).NotTo(BeAnExistingFile())
+		data, err := os.ReadFile(filepath.Join(dir, "errors.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("node node-a: failed to run \"nft list ruleset\": nft: command not found"))
+	})
+
+	It("should only collect the per-node diagnostics of the focus nodes", func() {
+		c.Nodes = []string{"node-a", "node-x"}
+		Expect(c.Collect(context.Background(), dir)).To(Succeed())
+
+		Expect(filepath.Join(dir, "pods", "calico-system", "calico-node-a")).To(BeADirectory())
+		Expect(filepath.Join(dir, "pods", "calico-system", "calico-kube-controllers")).To(BeADirectory())
+		Expect(filepath.Join(dir, "pods", "calico-system", "calico-node-b")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "pods", "calico-system", "calico-typha-b")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "nodes", "node-b")).NotTo(BeAnExistingFile())
+
+		data, err := os.ReadFile(filepath.Join(dir, "errors.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("node node-x: no Calico pods found on the node"))
+	})
+
+	It("should fail without Calico pods", func() {
+		c.Clientset = fake.NewSimpleClientset()
+		Expect(c.Collect(context.Background(), dir)).To(MatchError(ContainSubstring("no Calico pods found")))
+	})
+})
@@ -33,7 +33,7 @@ func Get(args []string) error {
 	doc := constants.DatastoreIntro + `Usage:
   <BINARY_NAME> get ( (<KIND> [<NAME>...]) |
                 --filename=<FILENAME> [--recursive] [--skip-empty] )
-                [--output=<OUTPUT>] [--config=<CONFIG>] [--namespace=<NS>] [--all-namespaces] [--export] [--context=<context>] [--allow-version-mismatch]
+                [--output=<OUTPUT>] [--config=<CONFIG>] [--namespace=<NS>] [--all-namespaces] [--export] [--watch] [--context=<context>] [--allow-version-mismatch]
 
 Examples:
   # List all policy in default output format.
@@ -42,6 +42,12 @@ Examples:
   # List specific policies in YAML format
   <BINARY_NAME> get -o yaml policy my-policy-1 my-policy-2
 
+  # Stream the IP pools in YAML format as they change
+  <BINARY_NAME> get ippools --watch -o yaml
+
+  # Stream the changes to the Felix configuration as diffs
+  <BINARY_NAME> get felixconfiguration --watch -o diff
+
 Options:
   -h --help                    Show this screen.
   -f --filename=<FILENAME>     Filename to use to get the resource.  If set to
@@ -53,7 +59,8 @@ Options:
                                data.
   -o --output=<OUTPUT FORMAT>  Output format.  One of: yaml, json, ps, wide,
                                custom-columns=..., go-template=...,
-                               go-template-file=...   [Default: ps]
+                               go-template-file=..., jsonpath=...,
+                               jsonpath-file=..., diff   [Default: ps]
   -c --config=<CONFIG>         Path to the file containing connection
                                configuration in YAML or JSON format.
                                [default: ` + constants.DefaultConfigPath + `]
@@ -64,6 +71,10 @@ Options:
      --export                  If present, returns the requested object(s) stripped of
                                cluster-specific information. This flag will be ignored
                                if <NAME> is not specified.
+  -w --watch                   After getting the requested object(s), watch for
+                               changes and print the object(s) again when they
+                               change, or only the changes to their spec with the
+                               diff output format.
      --context=<context>       The name of the kubeconfig context to use.
      --allow-version-mismatch  Allow client and cluster versions mismatch.
 
@@ -87,6 +98,8 @@ Description:
     * networkSet
     * node
     * profile
+    * stagedGlobalNetworkPolicy
+    * stagedNetworkPolicy
     * workloadEndpoint
 
   The resource type is case-insensitive and may be pluralized.
@@ -111,8 +124,18 @@ Description:
                           example to return a specific value.
     go-template-file      Display the results using the golang template that is
                           contained in the specified file.
+    jsonpath              Display the results using the specified JSONPath
+                          template, as kubectl does.  The template is applied
+                          to the JSON output, with several resources wrapped in
+                          a List, for example:
+                            -o jsonpath='{.items[*].metadata.name}'
+    jsonpath-file         Display the results using the JSONPath template that
+                          is contained in the specified file.
     yaml                  Display the results in YAML output format.
     json                  Display the results in JSON output format.
+    diff                  With --watch, display the changes to the resources as
+                          unified diffs of their spec.  The current state of the
+                          resources is not displayed.
 
   Note that the data output using YAML or JSON format is always valid to use as
   input to all of the resource management commands (create, apply, replace,
@@ -150,8 +173,22 @@ Description:
 		printNamespace = true
 	}
 
-	var rp common.ResourcePrinter
 	output := parsedArgs["--output"].(string)
+	watch := argutils.ArgBoolOrFalse(parsedArgs, "--watch")
+	if watch && parsedArgs["--filename"] != nil {
+		return fmt.Errorf("--watch is not supported with --filename")
+	}
+	if output == "diff" {
+		if !watch {
+			return fmt.Errorf("the diff output format is only supported with --watch")
+		}
+		if err := common.WatchDiff(parsedArgs, os.Stdout); err != nil {
+			return fmt.Errorf("Failed to watch resources: %v", err)
+		}
+		return nil
+	}
+
+	var rp common.ResourcePrinter
 	switch output {
 	case "yaml", "yml":
 		rp = common.ResourcePrinterYAML{}
@@ -185,6 +222,16 @@ Description:
 				return fmt.Errorf("need to specify a template file")
 			}
 			rp = common.ResourcePrinterTemplateFile{TemplateFile: outputValue}
+		case "jsonpath":
+			if outputValue == "" {
+				return fmt.Errorf("need to specify a template")
+			}
+			rp = common.ResourcePrinterJSONPath{Template: outputValue}
+		case "jsonpath-file":
+			if outputValue == "" {
+				return fmt.Errorf("need to specify a template file")
+			}
+			rp = common.ResourcePrinterJSONPathFile{TemplateFile: outputValue}
 		case "custom-columns":
 			if outputValue == "" {
 				return fmt.Errorf("need to specify at least one column")
@@ -197,6 +244,13 @@ Description:
 		return fmt.Errorf("unrecognized output format '%s'", output)
 	}
 
+	if watch {
+		if err := common.WatchPrint(parsedArgs, rp); err != nil {
+			return fmt.Errorf("Failed to watch resources: %v", err)
+		}
+		return nil
+	}
+
 	results := common.ExecuteConfigCommand(parsedArgs, common.ActionGetOrList)
 
 	log.Infof("results: %+v", results)
@@ -31,7 +31,9 @@ func IPAM(args []string) error {
   <BINARY_NAME> ipam <command> [<args>...]
 
     check            Check the integrity of the IPAM datastructures.
+    gc               Report, and optionally release, leaked IPs and orphaned blocks.
     release          Release a Calico assigned IP address.
+    report           Report IP usage by namespace or by node.
     show             Show details of a Calico configuration,
                      assigned IP address, or of overall IP usage.
     split            Split the IP pool specified by the CIDR into
@@ -69,8 +71,12 @@ Description:
 	switch command {
 	case "check":
 		return ipam.Check(args, VERSION)
+	case "gc":
+		return ipam.GC(args)
 	case "release":
 		return ipam.Release(args, VERSION)
+	case "report":
+		return ipam.ReportUsage(args)
 	case "show":
 		return ipam.Show(args)
 	case "configure":
@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	libipam "github.com/projectcalico/calico/libcalico-go/lib/ipam"
+)
+
+// GC implements the "calicoctl ipam gc" command, which reports the leaked IPAM handles and
+// orphaned blocks, and optionally releases them.
+func GC(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> ipam gc [--release] [--grace-period=<DURATION>] [-o <FILE>] [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --release                 Release the leaked handles and their IPs, and the
+                               affinities of the orphaned blocks.
+     --grace-period=<DURATION> Only report the IPs that were allocated longer than this
+                               ago as leaked, so that the IPs of the pods that are being
+                               set up are not.  [default: 15m]
+  -o --output=<FILE>           Path to output the report to, in JSON format.
+  -c --config=<CONFIG>         Path to the file containing connection configuration in
+                               YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  The ipam gc command cross-references the Calico IPAM allocations with the nodes
+  and workload endpoints.  It reports the handles whose IPs are all allocated to
+  nodes or pods that no longer exist, or that no longer use them, and the blocks
+  that are affine to nodes that no longer exist or that are not within any IP pool.
+
+  With --release, it also releases them.  It is safe to release while pods are
+  being created and deleted: IPs that were reallocated since they were found
+  leaked are not released.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(parsedArgs["--config"], parsedArgs["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	gracePeriod, err := time.ParseDuration(parsedArgs["--grace-period"].(string))
+	if err != nil {
+		return fmt.Errorf("invalid grace period: %w", err)
+	}
+
+	ctx := context.Background()
+
+	// Create a new backend client from env vars.
+	cf := parsedArgs["--config"].(string)
+	client, err := clientmgr.NewClient(cf)
+	if err != nil {
+		return err
+	}
+
+	report, err := client.IPAM().GarbageCollect(ctx, libipam.GCArgs{
+		Release:     parsedArgs["--release"].(bool),
+		GracePeriod: gracePeriod,
+	})
+	if err != nil {
+		return err
+	}
+	printGCReport(report)
+
+	if arg := parsedArgs["--output"]; arg != nil {
+		bytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(arg.(string), bytes, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+	return nil
+}
+
+func printGCReport(report *libipam.GCReport) {
+	fmt.Printf("Checked %d IPAM blocks with %d allocations.\n", report.NumBlocks, report.NumAllocations)
+	fmt.Println()
+
+	fmt.Printf("Found %d leaked handles.\n", len(report.LeakedHandles))
+	if len(report.LeakedHandles) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"HANDLE", "IPS", "OWNER", "REASON", "RELEASED"})
+		for _, h := range report.LeakedHandles {
+			owner := h.Node
+			if h.Pod != "" {
+				owner = fmt.Sprintf("%s/%s", h.Namespace, h.Pod)
+			}
+			table.Append([]string{h.HandleID, strings.Join(h.IPs, ","), owner, h.Reason, gcReleasedString(h.Released, h.Error)})
+		}
+		table.Render()
+	}
+	fmt.Println()
+
+	fmt.Printf("Found %d orphaned blocks.\n", len(report.OrphanedBlocks))
+	if len(report.OrphanedBlocks) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"BLOCK", "AFFINITY", "ALLOCATIONS", "REASON", "RELEASED"})
+		for _, b := range report.OrphanedBlocks {
+			table.Append([]string{b.CIDR, b.Affinity, fmt.Sprint(b.NumAllocations), b.Reason, gcReleasedString(b.Released, b.Error)})
+		}
+		table.Render()
+	}
+}
+
+func gcReleasedString(released bool, err string) string {
+	if err != "" {
+		return "error: " + err
+	}
+	if released {
+		return "yes"
+	}
+	return "no"
+}
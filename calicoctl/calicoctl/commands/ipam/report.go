@@ -0,0 +1,254 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// reportRow is the IP usage of one namespace or node in one IP pool.
+type reportRow struct {
+	Namespace string `json:"namespace,omitempty"`
+	Node      string `json:"node,omitempty"`
+	Pool      string `json:"pool"`
+	InUse     int    `json:"inUse"`
+	// Borrowed is the number of IPs in use from blocks that are affine to another node.
+	Borrowed int `json:"borrowed"`
+	// Blocks is the number of blocks affine to the node, when grouping by node.
+	Blocks int `json:"blocks,omitempty"`
+}
+
+// ReportUsage reports the IP usage per namespace or per node.
+func ReportUsage(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> ipam report [--by-namespace | --by-node] [--output=<OUTPUT>] [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --by-namespace            Group the IP usage by namespace (the default).
+     --by-node                 Group the IP usage by node.
+  -o --output=<OUTPUT>         Output format.  One of: table or json.
+                               [default: table]
+  -c --config=<CONFIG>         Path to the file containing connection configuration in
+                               YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  The ipam report command reports the number of IP addresses in use, grouped by
+  namespace or by node and by IP pool.  Borrowed IPs are IPs that are in use by
+  a node but come from a block that is affine to another node.  When grouping by
+  node, the number of blocks affine to each node is also reported.
+
+  IPs that are not allocated to pods, such as tunnel addresses, are reported
+  against the namespace "-".  Blocks that are not in any IP pool are reported
+  against the pool "-".
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	output := parsedArgs["--output"].(string)
+	if output != "table" && output != "json" {
+		return fmt.Errorf("Invalid output format: %s. Use one of: table or json.", output)
+	}
+	byNode := parsedArgs["--by-node"].(bool)
+
+	err = common.CheckVersionMismatch(parsedArgs["--config"], parsedArgs["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Create a new backend client from env vars.
+	cf := parsedArgs["--config"].(string)
+	client, err := clientmgr.NewClient(cf)
+	if err != nil {
+		return err
+	}
+
+	// Get the backend client.
+	type accessor interface {
+		Backend() bapi.Client
+	}
+	bc := client.(accessor).Backend()
+
+	pools, err := client.IPPools().List(ctx, options.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("Error listing IP pools: %v", err)
+	}
+	blockKVs, err := bc.List(ctx, model.BlockListOptions{}, "")
+	if err != nil {
+		return fmt.Errorf("Error listing IPAM blocks: %v", err)
+	}
+	var blocks []*model.AllocationBlock
+	for _, kvp := range blockKVs.KVPairs {
+		blocks = append(blocks, kvp.Value.(*model.AllocationBlock))
+	}
+
+	rows, unclassifiedIPs := buildReport(blocks, pools.Items, byNode)
+	if output == "json" {
+		bytes, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	printReport(os.Stdout, rows, byNode)
+	if unclassifiedIPs != 0 {
+		fmt.Printf("\nNote: found %d IP allocations without an explicit node association. Unable to determine if they are borrowed.\n",
+			unclassifiedIPs)
+	}
+	return nil
+}
+
+// buildReport groups the allocations in the blocks by namespace or node, and by pool.  It also
+// returns the number of allocations without a node, which can't be classified as borrowed or not.
+func buildReport(blocks []*model.AllocationBlock, pools []apiv3.IPPool, byNode bool) ([]*reportRow, int) {
+	var poolCIDRs []*cnet.IPNet
+	var poolNames []string
+	for _, p := range pools {
+		_, cidr, err := cnet.ParseCIDR(p.Spec.CIDR)
+		if err != nil {
+			continue
+		}
+		poolCIDRs = append(poolCIDRs, cidr)
+		poolNames = append(poolNames, p.Name)
+	}
+	poolFor := func(b *model.AllocationBlock) string {
+		for i, cidr := range poolCIDRs {
+			if cidr.Contains(b.CIDR.IP) {
+				return poolNames[i]
+			}
+		}
+		return "-"
+	}
+
+	type rowKey struct {
+		group string
+		pool  string
+	}
+	rows := map[rowKey]*reportRow{}
+	row := func(group, pool string) *reportRow {
+		k := rowKey{group, pool}
+		if rows[k] == nil {
+			rows[k] = &reportRow{Pool: pool}
+			if byNode {
+				rows[k].Node = group
+			} else {
+				rows[k].Namespace = group
+			}
+		}
+		return rows[k]
+	}
+
+	unclassifiedIPs := 0
+	for _, b := range blocks {
+		pool := poolFor(b)
+		owner := getBlockOwner(b)
+		if byNode && owner != "" {
+			row(owner, pool).Blocks++
+		}
+		for i := range b.Allocations {
+			if b.Allocations[i] == nil {
+				continue
+			}
+			attributes := b.Attributes[*b.Allocations[i]]
+
+			node, hasNode := attributes.AttrSecondary[model.IPAMBlockAttributeNode]
+			if !hasNode {
+				unclassifiedIPs++
+				node = owner
+			}
+			group := node
+			if !byNode {
+				group = "-"
+				if _, ok := attributes.AttrSecondary[model.IPAMBlockAttributePod]; ok {
+					group = attributes.AttrSecondary[model.IPAMBlockAttributeNamespace]
+				}
+			}
+			if group == "" {
+				group = "-"
+			}
+
+			r := row(group, pool)
+			r.InUse++
+			if hasNode && node != owner {
+				r.Borrowed++
+			}
+		}
+	}
+
+	var result []*reportRow
+	for _, r := range rows {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		gi, gj := result[i].Namespace+result[i].Node, result[j].Namespace+result[j].Node
+		if gi != gj {
+			return gi < gj
+		}
+		return result[i].Pool < result[j].Pool
+	})
+	return result, unclassifiedIPs
+}
+
+func printReport(w io.Writer, rows []*reportRow, byNode bool) {
+	table := tablewriter.NewWriter(w)
+	if byNode {
+		table.SetHeader([]string{"NODE", "POOL", "BLOCKS", "IPS IN USE", "BORROWED IPS"})
+	} else {
+		table.SetHeader([]string{"NAMESPACE", "POOL", "IPS IN USE", "BORROWED IPS"})
+	}
+	for _, r := range rows {
+		if byNode {
+			table.Append([]string{r.Node, r.Pool, strconv.Itoa(r.Blocks), strconv.Itoa(r.InUse), strconv.Itoa(r.Borrowed)})
+		} else {
+			table.Append([]string{r.Namespace, r.Pool, strconv.Itoa(r.InUse), strconv.Itoa(r.Borrowed)})
+		}
+	}
+	table.Render()
+}
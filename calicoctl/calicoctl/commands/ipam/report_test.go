@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+var _ = Describe("IPAM usage report", func() {
+	pool := apiv3.NewIPPool()
+	pool.Name = "default-ipv4-ippool"
+	pool.Spec.CIDR = "10.0.0.0/16"
+
+	block := func(cidr, owner string, allocs ...map[string]string) *model.AllocationBlock {
+		b := &model.AllocationBlock{CIDR: cnet.MustParseCIDR(cidr), Allocations: make([]*int, 64)}
+		if owner != "" {
+			affinity := "host:" + owner
+			b.Affinity = &affinity
+		}
+		for i, attrs := range allocs {
+			idx := i
+			b.Allocations[i] = &idx
+			b.Attributes = append(b.Attributes, model.AllocationAttribute{AttrSecondary: attrs})
+		}
+		return b
+	}
+	pod := func(node, namespace string) map[string]string {
+		return map[string]string{
+			model.IPAMBlockAttributeNode:      node,
+			model.IPAMBlockAttributeNamespace: namespace,
+			model.IPAMBlockAttributePod:       "pod",
+		}
+	}
+	tunnel := func(node string) map[string]string {
+		return map[string]string{
+			model.IPAMBlockAttributeNode: node,
+			model.IPAMBlockAttributeType: model.IPAMBlockAttributeTypeIPIP,
+		}
+	}
+	blocks := []*model.AllocationBlock{
+		block("10.0.0.0/26", "node1", pod("node1", "ns1"), pod("node1", "ns1"), tunnel("node1"), pod("node2", "ns2")),
+		block("10.0.1.0/26", "node2", pod("node2", "ns1"), map[string]string{}),
+		block("192.168.0.0/26", "", pod("node3", "ns1")),
+	}
+
+	It("should group the allocations by namespace", func() {
+		rows, unclassified := buildReport(blocks, []apiv3.IPPool{*pool}, false)
+		Expect(unclassified).To(Equal(1))
+		Expect(rows).To(Equal([]*reportRow{
+			{Namespace: "-", Pool: "default-ipv4-ippool", InUse: 2},
+			{Namespace: "ns1", Pool: "-", InUse: 1, Borrowed: 1},
+			{Namespace: "ns1", Pool: "default-ipv4-ippool", InUse: 3},
+			{Namespace: "ns2", Pool: "default-ipv4-ippool", InUse: 1, Borrowed: 1},
+		}))
+	})
+
+	It("should group the allocations by node", func() {
+		rows, _ := buildReport(blocks, []apiv3.IPPool{*pool}, true)
+		Expect(rows).To(Equal([]*reportRow{
+			{Node: "node1", Pool: "default-ipv4-ippool", Blocks: 1, InUse: 3},
+			{Node: "node2", Pool: "default-ipv4-ippool", Blocks: 1, InUse: 3, Borrowed: 1},
+			{Node: "node3", Pool: "-", InUse: 1, Borrowed: 1},
+		}))
+
+		var buf bytes.Buffer
+		printReport(&buf, rows, true)
+		Expect(buf.String()).To(ContainSubstring("BORROWED IPS"))
+		Expect(buf.String()).To(ContainSubstring("node3"))
+	})
+})
@@ -77,17 +77,7 @@ func getBorrowedIPs(ctx context.Context, ippoolClient clientv3.IPPoolInterface,
 				// - Affinity defined and IP assigned by a different node
 				// - IP allocated from a block with no Affinity
 
-				blockOwner := ""
-				if b.Affinity != nil {
-					// Affinity is in the form host:mgianluc-bz-09s4-kadm-node-2
-					// Remove "host:"
-					parts := strings.Split(*b.Affinity, ":")
-					if len(parts) == 2 {
-						blockOwner = parts[1]
-					} else {
-						blockOwner = parts[0]
-					}
-				}
+				blockOwner := getBlockOwner(b)
 
 				if borrowingNode, ok := attributes.AttrSecondary[model.IPAMBlockAttributeNode]; ok {
 					if blockOwner != borrowingNode {
@@ -111,6 +101,20 @@ func getBorrowedIPs(ctx context.Context, ippoolClient clientv3.IPPoolInterface,
 	return details, unclassifiedIPs, nil
 }
 
+// getBlockOwner returns the node that a block is affine to, or "" if the block has no affinity.
+func getBlockOwner(b *model.AllocationBlock) string {
+	if b.Affinity == nil {
+		return ""
+	}
+	// Affinity is in the form host:mgianluc-bz-09s4-kadm-node-2
+	// Remove "host:"
+	parts := strings.Split(*b.Affinity, ":")
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return parts[0]
+}
+
 func showBorrowedDetails(ctx context.Context, ippoolClient clientv3.IPPoolInterface, bc bapi.Client) error {
 	details, unclassifiedIPs, err := getBorrowedIPs(ctx, ippoolClient, bc)
 	if err != nil {
@@ -83,6 +83,8 @@ Description:
     * networkPolicy
     * node
     * profile
+    * stagedGlobalNetworkPolicy
+    * stagedNetworkPolicy
     * workloadEndpoint
 
   The resource type is case-insensitive and may be pluralized.
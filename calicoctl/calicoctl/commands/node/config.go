@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/felix/config"
+	"github.com/projectcalico/calico/felix/debugstate"
+)
+
+// Config shows the effective Felix configuration of a node and the source of each value.
+func Config(args []string) error {
+	doc := `Usage:
+  <BINARY_NAME> node config --felix-url=<URL> --token-file=<FILE> [--all | --drift]
+                   [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --felix-url=<URL>         URL of the debug state server of Felix, for
+                               example http://localhost:9095.
+     --token-file=<FILE>       File holding the token of the debug state
+                               server, as configured by DebugStateTokenFile.
+     --all                     Also show the parameters that are not set by
+                               any source and have their default value.
+     --drift                   Only show the parameters whose value differs
+                               from the one set by the FelixConfiguration
+                               resources.
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command shows the configuration that Felix runs with on a Calico node:
+  the value of each parameter, the source it comes from and the values that
+  the other sources give it.  The sources are, in increasing order of
+  priority, the default, the global FelixConfiguration resource ("default"),
+  the per-node FelixConfiguration resource ("node.<nodename>"), the config
+  file, the environment variables and the overrides that Felix applies itself,
+  e.g. for features that the kernel does not support.
+
+  A parameter drifts if a FelixConfiguration resource sets it but the value
+  in effect comes from another source, either because a local source overrides
+  it or because the parameter can only be set locally.
+
+  The configuration is read from the debug state server of Felix, which requires
+  DebugStatePort and DebugStateTokenFile in the Felix configuration.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	arguments, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	url := strings.TrimSuffix(arguments["--felix-url"].(string), "/") + "/config"
+	all := arguments["--all"].(bool)
+	drift := arguments["--drift"].(bool)
+
+	token, err := debugstate.ReadTokenFile(arguments["--token-file"].(string))
+	if err != nil {
+		return fmt.Errorf("Error executing command: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("Error executing command: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	httpClient := http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to read the Felix configuration: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error executing command: unable to read the Felix configuration: %s", resp.Status)
+	}
+
+	var report []config.ParamReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("Error executing command: unable to parse the Felix configuration: %v", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"PARAMETER", "VALUE", "SOURCE", "OTHER SOURCES", "DRIFT"})
+	table.SetAutoWrapText(false)
+	for _, r := range selectParams(report, all, drift) {
+		driftStr := ""
+		if r.Drift {
+			driftStr = "yes"
+		}
+		table.Append([]string{r.Name, r.Value, r.Source, otherSources(r), driftStr})
+	}
+	table.Render()
+
+	return nil
+}
+
+// selectParams filters the report: by default it skips the parameters that no source sets, with
+// all it keeps every parameter and with drift only the drifting ones.
+func selectParams(report []config.ParamReport, all, drift bool) []config.ParamReport {
+	var params []config.ParamReport
+	for _, r := range report {
+		if drift && !r.Drift {
+			continue
+		}
+		if !all && len(r.Values) == 0 {
+			continue
+		}
+		params = append(params, r)
+	}
+	return params
+}
+
+// otherSources formats the values of the sources that lost to the source of the value in
+// effect, highest priority first.
+func otherSources(r config.ParamReport) string {
+	var others []string
+	for _, source := range config.SourcesInDescendingOrder {
+		s := source.String()
+		if v, ok := r.Values[s]; ok && s != r.Source {
+			others = append(others, fmt.Sprintf("%s=%q", s, v))
+		}
+	}
+	return strings.Join(others, ", ")
+}
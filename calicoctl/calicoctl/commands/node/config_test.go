@@ -0,0 +1,65 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/config"
+)
+
+var _ = Describe("Node config report", func() {
+	unset := config.ParamReport{Name: "IptablesBackend", Value: "auto", Source: "<default>"}
+	perHost := config.ParamReport{
+		Name:   "LogSeverityScreen",
+		Value:  "Debug",
+		Source: "datastore (per-host)",
+		Values: map[string]string{
+			"datastore (global)":   "Warning",
+			"datastore (per-host)": "Debug",
+		},
+	}
+	envOverride := config.ParamReport{
+		Name:   "BPFEnabled",
+		Value:  "false",
+		Source: "environment variable",
+		Values: map[string]string{
+			"datastore (global)":   "true",
+			"datastore (per-host)": "true",
+			"environment variable": "false",
+		},
+		Drift: true,
+	}
+	report := []config.ParamReport{envOverride, unset, perHost}
+
+	It("should only show the params that are set by default", func() {
+		Expect(selectParams(report, false, false)).To(Equal([]config.ParamReport{envOverride, perHost}))
+	})
+
+	It("should show all params with --all", func() {
+		Expect(selectParams(report, true, false)).To(Equal(report))
+	})
+
+	It("should only show drifting params with --drift", func() {
+		Expect(selectParams(report, false, true)).To(Equal([]config.ParamReport{envOverride}))
+	})
+
+	It("should list the other sources by priority", func() {
+		Expect(otherSources(envOverride)).To(Equal(
+			`datastore (per-host)="true", datastore (global)="true"`))
+		Expect(otherSources(unset)).To(Equal(""))
+	})
+})
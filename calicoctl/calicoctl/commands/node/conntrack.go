@@ -0,0 +1,189 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/felix/conntrack"
+	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/names"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+// bpfConntrackMapsGlob matches the pinned BPF conntrack maps, which only exist
+// when the node runs the BPF dataplane.
+const bpfConntrackMapsGlob = "/sys/fs/bpf/tc/globals/cali_v[46]_ct*"
+
+// Conntrack function flushes conntrack entries of selected local workloads.
+func Conntrack(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> node conntrack flush --selector=<SELECTOR> [--name=<NAME>]
+                     [--config=<CONFIG>] [--dryrun] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --selector=<SELECTOR>     Selector matching the labels of the workload
+                               endpoints whose flows should be flushed.
+     --name=<NAME>             The name of the Calico node.  If this is not
+                               supplied it defaults to the host name.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --dryrun                  Only print the workload IPs whose flows would
+                               be flushed.
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command deletes the conntrack entries of flows that involve the IPs of
+  the local workload endpoints matching the selector, both in the kernel
+  conntrack table and, if the node runs the BPF dataplane, in the BPF
+  conntrack maps.  Flows of all other workloads are left untouched.
+
+  This command must be run on the specific Calico node that hosts the
+  workloads.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	arguments, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(arguments["--config"], arguments["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	sel, err := selector.Parse(arguments["--selector"].(string))
+	if err != nil {
+		return fmt.Errorf("Error executing command: invalid selector: %v", err)
+	}
+
+	nodeName, _ := arguments["--name"].(string)
+	if nodeName == "" {
+		nodeName, err = names.Hostname()
+		if err != nil || nodeName == "" {
+			return fmt.Errorf("Error executing command: unable to determine node name")
+		}
+	}
+
+	dryrun := arguments["--dryrun"].(bool)
+	if !dryrun {
+		// Make sure the command is run with super user privileges
+		enforceRoot()
+	}
+
+	client, err := clientmgr.NewClient(arguments["--config"].(string))
+	if err != nil {
+		return err
+	}
+
+	weps, err := client.WorkloadEndpoints().List(context.Background(), options.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to list workload endpoints: %v", err)
+	}
+
+	ips := selectedWorkloadIPs(weps.Items, nodeName, sel)
+	if len(ips) == 0 {
+		fmt.Println("No local workload endpoints match the selector")
+		return nil
+	}
+
+	for _, ip := range ips {
+		fmt.Println("Flushing conntrack entries of", ip)
+	}
+	if dryrun {
+		return nil
+	}
+
+	return flushConntrack(ips)
+}
+
+// selectedWorkloadIPs returns the IPs of the workload endpoints on the given
+// node whose labels match the selector.
+func selectedWorkloadIPs(weps []apiv3.WorkloadEndpoint, nodeName string, sel selector.Selector) []net.IP {
+	var ips []net.IP
+	for _, wep := range weps {
+		if wep.Spec.Node != nodeName || !sel.Evaluate(wep.Labels) {
+			continue
+		}
+		for _, n := range wep.Spec.IPNetworks {
+			ip, _, err := cnet.ParseCIDROrIP(n)
+			if err != nil {
+				log.WithError(err).WithField("wep", wep.Name).Warnf("Ignoring invalid IP network %q", n)
+				continue
+			}
+			ips = append(ips, ip.IP)
+		}
+	}
+	return ips
+}
+
+func flushConntrack(ips []net.IP) error {
+	ct := conntrack.New()
+	for _, ip := range ips {
+		ipVersion := uint8(4)
+		if ip.To4() == nil {
+			ipVersion = 6
+		}
+		ct.RemoveConntrackFlows(ipVersion, ip)
+	}
+
+	if m, _ := filepath.Glob(bpfConntrackMapsGlob); len(m) == 0 {
+		return nil
+	}
+
+	// The BPF conntrack maps are only understood by the BPF tooling that ships
+	// with calico-node.
+	bin, err := exec.LookPath("calico-node")
+	if err != nil {
+		return fmt.Errorf("Error executing command: node runs the BPF dataplane, but calico-node " +
+			"is not available to flush the BPF conntrack maps")
+	}
+
+	cmdArgs := []string{"-bpf", "conntrack", "flush"}
+	for _, ip := range ips {
+		cmdArgs = append(cmdArgs, ip.String())
+	}
+	cmd := exec.Command(bin, cmdArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Error executing command: failed to flush BPF conntrack entries: %v", err)
+	}
+
+	return nil
+}
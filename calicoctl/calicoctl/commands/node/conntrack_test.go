@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+var _ = Describe("Conntrack flush workload selection", func() {
+	wep := func(name, node string, labels map[string]string, ipNets ...string) apiv3.WorkloadEndpoint {
+		return apiv3.WorkloadEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec: apiv3.WorkloadEndpointSpec{
+				Node:       node,
+				IPNetworks: ipNets,
+			},
+		}
+	}
+
+	weps := []apiv3.WorkloadEndpoint{
+		wep("foo-local", "node1", map[string]string{"app": "foo"}, "10.0.0.1/32", "fd00::1/128"),
+		wep("foo-remote", "node2", map[string]string{"app": "foo"}, "10.0.1.1/32"),
+		wep("bar-local", "node1", map[string]string{"app": "bar"}, "10.0.0.2/32"),
+		wep("foo-bad-ip", "node1", map[string]string{"app": "foo"}, "garbage", "10.0.0.3"),
+	}
+
+	It("should only return the IPs of matching local workloads", func() {
+		sel, err := selector.Parse("app == 'foo'")
+		Expect(err).NotTo(HaveOccurred())
+
+		ips := selectedWorkloadIPs(weps, "node1", sel)
+		Expect(ips).To(Equal([]net.IP{
+			net.ParseIP("10.0.0.1").To4(),
+			net.ParseIP("fd00::1"),
+			net.ParseIP("10.0.0.3").To4(),
+		}))
+	})
+
+	It("should return nothing if no local workload matches", func() {
+		sel, err := selector.Parse("app == 'baz'")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(selectedWorkloadIPs(weps, "node1", sel)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,396 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/names"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+// Flows function displays the live flows of the workloads on a node.
+func Flows(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> node flows [--namespace=<NS>] [--pod=<POD>] [--service=<SERVICE>]
+                 [--name=<NAME>] [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+  -n --namespace=<NS>          Only show flows of workloads in this namespace.
+     --pod=<POD>               Only show flows of the pods with this name.
+     --service=<SERVICE>       Only show flows to this Kubernetes service, given
+                               as <NAMESPACE>/<NAME>, or as <NAME> in the
+                               namespace given by --namespace.
+     --name=<NAME>             The name of the Calico node.  If this is not
+                               supplied it defaults to the host name.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command displays the flows in the kernel conntrack table that involve
+  the local workload endpoints, with the namespace and name of the workloads
+  at each end and the policies whose selector selects the local workloads.
+
+  If the node runs the BPF dataplane, flows are tracked in the BPF conntrack
+  maps instead; use 'calico-node -bpf conntrack dump' on the node to show
+  them.
+
+  This command must be run on the specific Calico node that hosts the
+  workloads.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	arguments, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(arguments["--config"], arguments["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	nodeName, _ := arguments["--name"].(string)
+	if nodeName == "" {
+		nodeName, err = names.Hostname()
+		if err != nil || nodeName == "" {
+			return fmt.Errorf("Error executing command: unable to determine node name")
+		}
+	}
+
+	// Make sure the command is run with super user privileges
+	enforceRoot()
+
+	if m, _ := filepath.Glob(bpfConntrackMapsGlob); len(m) > 0 {
+		fmt.Fprintln(os.Stderr, "WARNING: node runs the BPF dataplane, use 'calico-node -bpf conntrack dump' "+
+			"to show the flows in the BPF conntrack maps")
+	}
+
+	ctx := context.Background()
+	c, err := clientmgr.NewClient(arguments["--config"].(string))
+	if err != nil {
+		return err
+	}
+
+	filter := flowFilter{}
+	filter.namespace, _ = arguments["--namespace"].(string)
+	filter.pod, _ = arguments["--pod"].(string)
+	if svc, _ := arguments["--service"].(string); svc != "" {
+		if filter.serviceIPs, err = serviceIPs(ctx, c, svc, filter.namespace); err != nil {
+			return err
+		}
+	}
+
+	weps, err := c.WorkloadEndpoints().List(ctx, options.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to list workload endpoints: %v", err)
+	}
+	policies, err := listPolicySelectors(ctx, c)
+	if err != nil {
+		return err
+	}
+	endpoints := flowEndpointsByIP(weps.Items, nodeName, policies)
+
+	var flows []*netlink.ConntrackFlow
+	for _, family := range []netlink.InetFamily{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		f, err := netlink.ConntrackTableList(netlink.ConntrackTable, family)
+		if err != nil {
+			return fmt.Errorf("Error executing command: unable to list conntrack entries: %v", err)
+		}
+		flows = append(flows, f...)
+	}
+
+	printFlows(filterFlows(flows, endpoints, filter), endpoints)
+	return nil
+}
+
+// flowEndpoint is a local workload endpoint at one end of a flow.
+type flowEndpoint struct {
+	Namespace string
+	Name      string
+	Policies  []string
+}
+
+func (e *flowEndpoint) String() string {
+	if e == nil {
+		return "-"
+	}
+	if e.Namespace == "" {
+		return e.Name
+	}
+	return e.Namespace + "/" + e.Name
+}
+
+// policySelector is the endpoint selector of a policy.  Namespaced policies only select endpoints
+// in their own namespace.
+type policySelector struct {
+	Name      string
+	Namespace string
+	Selector  selector.Selector
+}
+
+// flowFilter selects the flows to display.  A flow matches the namespace and pod filters if a
+// local workload at either end matches them, and the service filter if its original destination
+// is one of the service's IPs.
+type flowFilter struct {
+	namespace  string
+	pod        string
+	serviceIPs map[string]bool
+}
+
+func (f flowFilter) matches(flow *netlink.ConntrackFlow, endpoints map[string]*flowEndpoint) bool {
+	if f.serviceIPs != nil && !f.serviceIPs[flow.Forward.DstIP.String()] {
+		return false
+	}
+	// With a service filter, the namespace only qualifies the service's name.
+	namespace := f.namespace
+	if f.serviceIPs != nil {
+		namespace = ""
+	}
+	for _, ip := range []net.IP{flow.Forward.SrcIP, flow.Forward.DstIP, flow.Reverse.SrcIP} {
+		ep := endpoints[ip.String()]
+		if ep == nil {
+			continue
+		}
+		if (namespace == "" || ep.Namespace == namespace) && (f.pod == "" || ep.Name == f.pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// flowEndpointsByIP maps the IPs of the workload endpoints on the node to the endpoints, along
+// with the policies that select them.
+func flowEndpointsByIP(weps []apiv3.WorkloadEndpoint, nodeName string, policies []policySelector) map[string]*flowEndpoint {
+	endpoints := map[string]*flowEndpoint{}
+	for _, wep := range weps {
+		if wep.Spec.Node != nodeName {
+			continue
+		}
+		ep := &flowEndpoint{Namespace: wep.Namespace, Name: wep.Spec.Pod}
+		if ep.Name == "" {
+			ep.Name = wep.Spec.Workload
+		}
+		for _, p := range policies {
+			if p.Namespace != "" && p.Namespace != wep.Namespace {
+				continue
+			}
+			if p.Selector.Evaluate(wep.Labels) {
+				ep.Policies = append(ep.Policies, p.Name)
+			}
+		}
+		for _, n := range wep.Spec.IPNetworks {
+			ip, _, err := cnet.ParseCIDROrIP(n)
+			if err != nil {
+				log.WithError(err).WithField("wep", wep.Name).Warnf("Ignoring invalid IP network %q", n)
+				continue
+			}
+			endpoints[ip.String()] = ep
+		}
+	}
+	return endpoints
+}
+
+func filterFlows(flows []*netlink.ConntrackFlow, endpoints map[string]*flowEndpoint, filter flowFilter) []*netlink.ConntrackFlow {
+	var filtered []*netlink.ConntrackFlow
+	for _, flow := range flows {
+		if filter.matches(flow, endpoints) {
+			filtered = append(filtered, flow)
+		}
+	}
+	return filtered
+}
+
+func listPolicySelectors(ctx context.Context, c client.Interface) ([]policySelector, error) {
+	var policies []policySelector
+	gnps, err := c.GlobalNetworkPolicies().List(ctx, options.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error executing command: unable to list global network policies: %v", err)
+	}
+	for _, p := range gnps.Items {
+		if sel, ok := parsePolicySelector(p.Name, p.Spec.Selector); ok {
+			policies = append(policies, policySelector{Name: p.Name, Selector: sel})
+		}
+	}
+	nps, err := c.NetworkPolicies().List(ctx, options.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error executing command: unable to list network policies: %v", err)
+	}
+	for _, p := range nps.Items {
+		if sel, ok := parsePolicySelector(p.Name, p.Spec.Selector); ok {
+			policies = append(policies, policySelector{Name: p.Namespace + "/" + p.Name, Namespace: p.Namespace, Selector: sel})
+		}
+	}
+	return policies, nil
+}
+
+func parsePolicySelector(name, s string) (selector.Selector, bool) {
+	if s == "" {
+		s = "all()"
+	}
+	sel, err := selector.Parse(s)
+	if err != nil {
+		log.WithError(err).WithField("policy", name).Warn("Ignoring policy with invalid selector")
+		return nil, false
+	}
+	return sel, true
+}
+
+// serviceIPs returns the IPs of a Kubernetes service, which is named either <NAMESPACE>/<NAME>, or
+// <NAME> in the given namespace.
+func serviceIPs(ctx context.Context, c client.Interface, svc, namespace string) (map[string]bool, error) {
+	if parts := strings.SplitN(svc, "/", 2); len(parts) == 2 {
+		namespace, svc = parts[0], parts[1]
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	type accessor interface {
+		Backend() bapi.Client
+	}
+	kc, ok := c.(accessor).Backend().(*k8s.KubeClient)
+	if !ok {
+		return nil, fmt.Errorf("Error executing command: --service requires the Kubernetes datastore")
+	}
+	service, err := kc.ClientSet.CoreV1().Services(namespace).Get(ctx, svc, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error executing command: unable to get service %s/%s: %v", namespace, svc, err)
+	}
+
+	ips := map[string]bool{}
+	for _, ip := range append(append([]string{}, service.Spec.ClusterIPs...), service.Spec.ExternalIPs...) {
+		if parsed := net.ParseIP(ip); parsed != nil {
+			ips[parsed.String()] = true
+		}
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if parsed := net.ParseIP(ingress.IP); parsed != nil {
+			ips[parsed.String()] = true
+		}
+	}
+	return ips, nil
+}
+
+// printFlows prints the flows in table format.  The destination is the original destination of
+// the flow, and the backend is where the flow was NATed to, if it was NATed.
+func printFlows(flows []*netlink.ConntrackFlow, endpoints map[string]*flowEndpoint) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Protocol", "Source", "Destination", "Backend", "Source workload",
+		"Destination workload", "Policies", "Packets", "Bytes"})
+
+	for _, flow := range flows {
+		backend := "-"
+		if !flow.Reverse.SrcIP.Equal(flow.Forward.DstIP) || flow.Reverse.SrcPort != flow.Forward.DstPort {
+			backend = hostPort(flow.Reverse.SrcIP, flow.Reverse.SrcPort)
+		}
+		srcEP := endpoints[flow.Forward.SrcIP.String()]
+		dstEP := endpoints[flow.Reverse.SrcIP.String()]
+
+		var policies []string
+		for _, ep := range []*flowEndpoint{srcEP, dstEP} {
+			if ep != nil {
+				policies = append(policies, ep.Policies...)
+			}
+		}
+		policies = uniqueSorted(policies)
+		policyCol := "-"
+		if len(policies) > 0 {
+			policyCol = strings.Join(policies, ",")
+		}
+
+		table.Append([]string{
+			protocolName(flow.Forward.Protocol),
+			hostPort(flow.Forward.SrcIP, flow.Forward.SrcPort),
+			hostPort(flow.Forward.DstIP, flow.Forward.DstPort),
+			backend,
+			srcEP.String(),
+			dstEP.String(),
+			policyCol,
+			strconv.FormatUint(flow.Forward.Packets+flow.Reverse.Packets, 10),
+			strconv.FormatUint(flow.Forward.Bytes+flow.Reverse.Bytes, 10),
+		})
+	}
+
+	table.Render()
+}
+
+func hostPort(ip net.IP, port uint16) string {
+	if port == 0 {
+		return ip.String()
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+}
+
+func protocolName(p uint8) string {
+	switch p {
+	case 1:
+		return "icmp"
+	case 6:
+		return "tcp"
+	case 17:
+		return "udp"
+	case 58:
+		return "icmpv6"
+	case 132:
+		return "sctp"
+	default:
+		return strconv.Itoa(int(p))
+	}
+}
+
+func uniqueSorted(s []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+var _ = Describe("Node flows", func() {
+	wep := func(ns, pod, node string, labels map[string]string, ipNets ...string) apiv3.WorkloadEndpoint {
+		return apiv3.WorkloadEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: pod + "-eth0", Namespace: ns, Labels: labels},
+			Spec: apiv3.WorkloadEndpointSpec{
+				Node:       node,
+				Pod:        pod,
+				IPNetworks: ipNets,
+			},
+		}
+	}
+
+	weps := []apiv3.WorkloadEndpoint{
+		wep("ns1", "client", "node1", map[string]string{"app": "client"}, "10.0.0.1/32"),
+		wep("ns2", "server", "node1", map[string]string{"app": "server"}, "10.0.0.2/32", "fd00::2/128"),
+		wep("ns2", "remote", "node2", map[string]string{"app": "server"}, "10.0.1.1/32"),
+	}
+
+	mustParse := func(s string) selector.Selector {
+		sel, err := selector.Parse(s)
+		Expect(err).NotTo(HaveOccurred())
+		return sel
+	}
+
+	policies := []policySelector{
+		{Name: "global-all", Selector: mustParse("all()")},
+		{Name: "ns2/allow-server", Namespace: "ns2", Selector: mustParse("app == 'server'")},
+		{Name: "ns1/allow-server", Namespace: "ns1", Selector: mustParse("app == 'server'")},
+	}
+
+	endpoints := flowEndpointsByIP(weps, "node1", policies)
+
+	flow := func(src, dst, backend string) *netlink.ConntrackFlow {
+		f := &netlink.ConntrackFlow{}
+		f.Forward.Protocol = 6
+		f.Forward.SrcIP = net.ParseIP(src)
+		f.Forward.SrcPort = 40000
+		f.Forward.DstIP = net.ParseIP(dst)
+		f.Forward.DstPort = 80
+		f.Reverse.Protocol = 6
+		f.Reverse.SrcIP = net.ParseIP(backend)
+		f.Reverse.SrcPort = 8080
+		f.Reverse.DstIP = net.ParseIP(src)
+		f.Reverse.DstPort = 40000
+		return f
+	}
+
+	direct := flow("10.0.0.1", "10.0.0.2", "10.0.0.2")
+	viaService := flow("10.0.0.1", "10.96.0.10", "10.0.0.2")
+	external := flow("192.168.0.1", "192.168.0.2", "192.168.0.2")
+	toRemote := flow("10.0.1.1", "10.0.1.2", "10.0.1.2")
+	flows := []*netlink.ConntrackFlow{direct, viaService, external, toRemote}
+
+	It("should only map the IPs of local workloads", func() {
+		Expect(endpoints).To(HaveLen(3))
+		Expect(endpoints).To(HaveKey("10.0.0.1"))
+		Expect(endpoints).To(HaveKey("fd00::2"))
+		Expect(endpoints).NotTo(HaveKey("10.0.1.1"))
+	})
+
+	It("should record the policies that select each workload", func() {
+		Expect(endpoints["10.0.0.1"].Policies).To(Equal([]string{"global-all"}))
+		Expect(endpoints["10.0.0.2"].Policies).To(Equal([]string{"global-all", "ns2/allow-server"}))
+	})
+
+	It("should only show flows of local workloads without a filter", func() {
+		Expect(filterFlows(flows, endpoints, flowFilter{})).To(Equal([]*netlink.ConntrackFlow{direct, viaService}))
+	})
+
+	It("should match the namespace against either end, including NATed backends", func() {
+		filtered := filterFlows([]*netlink.ConntrackFlow{viaService, external}, endpoints, flowFilter{namespace: "ns2"})
+		Expect(filtered).To(Equal([]*netlink.ConntrackFlow{viaService}))
+		Expect(filterFlows(flows, endpoints, flowFilter{namespace: "ns3"})).To(BeEmpty())
+	})
+
+	It("should filter by pod", func() {
+		Expect(filterFlows(flows, endpoints, flowFilter{namespace: "ns1", pod: "client"})).To(HaveLen(2))
+		Expect(filterFlows(flows, endpoints, flowFilter{namespace: "ns1", pod: "server"})).To(BeEmpty())
+	})
+
+	It("should filter by service IP", func() {
+		filter := flowFilter{namespace: "kube-system", serviceIPs: map[string]bool{"10.96.0.10": true}}
+		Expect(filterFlows(flows, endpoints, filter)).To(Equal([]*netlink.ConntrackFlow{viaService}))
+	})
+})
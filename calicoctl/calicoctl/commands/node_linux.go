@@ -35,6 +35,9 @@ func Node(args []string) error {
     status       View the current status of a Calico node.
     diags        Gather a diagnostics bundle for a Calico node.
     checksystem  Verify the compute host is able to run a Calico node instance.
+    conntrack    Flush conntrack entries of selected workloads on a Calico node.
+    flows        Display the live flows of the workloads on a Calico node.
+    config       View the Felix configuration of a Calico node and its sources.
 
 Options:
   -h --help      Show this screen.
@@ -74,6 +77,12 @@ Description:
 		return node.Checksystem(args)
 	case "run":
 		return node.Run(args)
+	case "conntrack":
+		return node.Conntrack(args)
+	case "flows":
+		return node.Flows(args)
+	case "config":
+		return node.Config(args)
 	default:
 		fmt.Println(doc)
 	}
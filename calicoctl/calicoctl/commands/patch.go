@@ -73,6 +73,8 @@ Description:
     * networkSet
     * node
     * profile
+    * stagedGlobalNetworkPolicy
+    * stagedNetworkPolicy
     * workloadEndpoint
 
   The resource type is case-insensitive and may be pluralized.
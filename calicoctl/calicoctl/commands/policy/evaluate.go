@@ -0,0 +1,307 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/felix/calc"
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/updateprocessors"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/watchersyncer"
+)
+
+// Evaluate reports which policy rule would allow or deny a flow between two endpoints.
+func Evaluate(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> policy evaluate --src=<SRC> --dst=<DST> [--proto=<PROTO>] [--port=<PORT>]
+                       [--src-port=<PORT>] [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --src=<SRC>               Source of the flow, either an IP address or a pod
+                               as <NAMESPACE>/<POD>.
+     --dst=<DST>               Destination of the flow, either an IP address or
+                               a pod as <NAMESPACE>/<POD>.
+     --proto=<PROTO>           Protocol of the flow, by name or number.
+                               [default: tcp]
+     --port=<PORT>             Destination port of the flow.
+     --src-port=<PORT>         Source port of the flow.  [default: 32768]
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command evaluates a flow against the Calico policies and profiles in the
+  datastore, the same way as Felix, and reports the tier, policy and rule that
+  would allow or deny the flow, for the egress policies of the source and the
+  ingress policies of the destination.  A pod that is given by name is looked
+  up by its workload endpoint and its first IP address is used.
+
+  Rules that match on properties of the packets rather than of the flow, such
+  as ICMP types, DSCP, HTTP requests, domain names and services, are not
+  evaluated and never match.  They are listed in the output.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	arguments, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(arguments["--config"], arguments["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	flow := calc.EvalFlow{}
+	if flow.Protocol, err = parseProtocol(arguments["--proto"].(string)); err != nil {
+		return err
+	}
+	if flow.SrcPort, err = parsePort(arguments["--src-port"]); err != nil {
+		return err
+	}
+	if flow.DstPort, err = parsePort(arguments["--port"]); err != nil {
+		return err
+	}
+
+	cfg, err := clientmgr.LoadClientConfig(arguments["--config"].(string))
+	if err != nil {
+		return err
+	}
+	c, err := clientmgr.NewClientFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	type accessor interface {
+		Backend() bapi.Client
+	}
+	bc := c.(accessor).Backend()
+
+	ctx := context.Background()
+	evaluator, weps, err := loadEvaluator(ctx, bc, cfg.Spec.DatastoreType == apiconfig.Kubernetes)
+	if err != nil {
+		return err
+	}
+
+	if flow.SrcIP, err = resolvePeer(arguments["--src"].(string), weps); err != nil {
+		return err
+	}
+	if flow.DstIP, err = resolvePeer(arguments["--dst"].(string), weps); err != nil {
+		return err
+	}
+	if (flow.SrcIP.To4() == nil) != (flow.DstIP.To4() == nil) {
+		return fmt.Errorf("Error executing command: the source and destination must have the same IP version")
+	}
+
+	printEvalResult(os.Stdout, evaluator.Evaluate(flow))
+	return nil
+}
+
+// evalResourceTypes are the resources that Felix evaluates policy with, and their update processors.
+func evalResourceTypes(kdd bool) []watchersyncer.ResourceType {
+	types := []watchersyncer.ResourceType{
+		{
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindGlobalNetworkPolicy},
+			UpdateProcessor: updateprocessors.NewGlobalNetworkPolicyUpdateProcessor(),
+		},
+		{
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindNetworkPolicy},
+			UpdateProcessor: updateprocessors.NewNetworkPolicyUpdateProcessor(),
+		},
+		{
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindProfile},
+			UpdateProcessor: updateprocessors.NewProfileUpdateProcessor(),
+		},
+		{
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindGlobalNetworkSet},
+			UpdateProcessor: updateprocessors.NewGlobalNetworkSetUpdateProcessor(),
+		},
+		{
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindNetworkSet},
+			UpdateProcessor: updateprocessors.NewNetworkSetUpdateProcessor(),
+		},
+		{
+			ListInterface:   model.ResourceListOptions{Kind: libapiv3.KindWorkloadEndpoint},
+			UpdateProcessor: updateprocessors.NewWorkloadEndpointUpdateProcessor(),
+		},
+	}
+	// In KDD mode, Kubernetes network policies are read directly, as Felix does.
+	if kdd {
+		types = append(types, watchersyncer.ResourceType{
+			ListInterface:   model.ResourceListOptions{Kind: model.KindKubernetesNetworkPolicy},
+			UpdateProcessor: updateprocessors.NewNetworkPolicyUpdateProcessor(),
+		})
+	}
+	return types
+}
+
+// loadEvaluator lists the resources that policy is evaluated with and feeds them, converted to the
+// v1 data model, to a new policy evaluator.  It also returns the workload endpoints, to look up pods.
+func loadEvaluator(ctx context.Context, bc bapi.Client, kdd bool) (*calc.PolicyEvaluator, []*libapiv3.WorkloadEndpoint, error) {
+	evaluator := calc.NewPolicyEvaluator()
+	var weps []*libapiv3.WorkloadEndpoint
+	for _, rt := range evalResourceTypes(kdd) {
+		kind := rt.ListInterface.(model.ResourceListOptions).Kind
+		list, err := bc.List(ctx, rt.ListInterface, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error executing command: unable to list %s resources: %v", kind, err)
+		}
+		for _, kvp := range list.KVPairs {
+			if wep, ok := kvp.Value.(*libapiv3.WorkloadEndpoint); ok {
+				weps = append(weps, wep)
+			}
+			kvps, err := rt.UpdateProcessor.Process(kvp)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARNING: ignoring %s %s: %v\n", kind, kvp.Key, err)
+				continue
+			}
+			for _, kvp := range kvps {
+				evaluator.OnUpdate(bapi.Update{KVPair: *kvp, UpdateType: bapi.UpdateTypeKVNew})
+			}
+		}
+	}
+	return evaluator, weps, nil
+}
+
+// resolvePeer returns the IP address of a flow peer given either as an IP address or as a pod.
+func resolvePeer(peer string, weps []*libapiv3.WorkloadEndpoint) (net.IP, error) {
+	if ip := net.ParseIP(peer); ip != nil {
+		return ip, nil
+	}
+	namespace, pod := "default", peer
+	if parts := strings.SplitN(peer, "/", 2); len(parts) == 2 {
+		namespace, pod = parts[0], parts[1]
+	}
+	for _, wep := range weps {
+		if wep.Namespace != namespace || (wep.Spec.Pod != pod && wep.Spec.Workload != pod) {
+			continue
+		}
+		for _, ipn := range wep.Spec.IPNetworks {
+			if ip, _, err := net.ParseCIDR(ipn); err == nil {
+				return ip, nil
+			}
+			if ip := net.ParseIP(ipn); ip != nil {
+				return ip, nil
+			}
+		}
+		return nil, fmt.Errorf("Error executing command: pod %s/%s has no IP address", namespace, pod)
+	}
+	return nil, fmt.Errorf("Error executing command: no workload endpoint found for pod %s/%s", namespace, pod)
+}
+
+func parseProtocol(proto string) (uint8, error) {
+	if n, err := strconv.ParseUint(proto, 10, 8); err == nil {
+		return uint8(n), nil
+	}
+	n := calc.ProtocolNumber(numorstring.ProtocolFromString(proto))
+	if n == 0 {
+		return 0, fmt.Errorf("Error executing command: unknown protocol %q", proto)
+	}
+	return n, nil
+}
+
+func parsePort(arg interface{}) (uint16, error) {
+	s, _ := arg.(string)
+	if s == "" {
+		return 0, nil
+	}
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Error executing command: invalid port %q", s)
+	}
+	return uint16(port), nil
+}
+
+func printEvalResult(w io.Writer, result calc.EvalResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"DIRECTION", "ENDPOINT", "ACTION", "TIER", "POLICY", "RULE", "REASON"})
+	table.SetAutoWrapText(false)
+	var unevaluated []string
+	for _, v := range []struct {
+		direction string
+		verdict   *calc.EvalVerdict
+		missing   string
+	}{
+		{calc.EvalDirectionEgress, result.Egress, "the source is not a Calico workload endpoint"},
+		{calc.EvalDirectionIngress, result.Ingress, "the destination is not a Calico workload endpoint"},
+	} {
+		if v.verdict == nil {
+			table.Append([]string{v.direction, "-", "allow", "-", "-", "-", v.missing})
+			continue
+		}
+		policy, rule := v.verdict.Policy, "-"
+		if v.verdict.Profile != "" {
+			policy = "profile " + v.verdict.Profile
+		}
+		if policy == "" {
+			policy = "-"
+		}
+		if v.verdict.RuleIndex >= 0 {
+			rule = strconv.Itoa(v.verdict.RuleIndex)
+		}
+		tier := v.verdict.Tier
+		if tier == "" {
+			tier = "-"
+		}
+		table.Append([]string{
+			v.direction,
+			v.verdict.Endpoint.WorkloadID + "/" + v.verdict.Endpoint.EndpointID,
+			v.verdict.Action,
+			tier,
+			policy,
+			rule,
+			v.verdict.Reason,
+		})
+		unevaluated = append(unevaluated, v.verdict.Unevaluated...)
+	}
+	table.Render()
+
+	if len(unevaluated) > 0 {
+		fmt.Fprintln(w, "\nRules that were not evaluated:")
+		for _, u := range unevaluated {
+			fmt.Fprintf(w, "  %s\n", u)
+		}
+	}
+	if result.Allowed() {
+		fmt.Fprintln(w, "\nThe flow is allowed.")
+	} else {
+		fmt.Fprintln(w, "\nThe flow is denied.")
+	}
+}
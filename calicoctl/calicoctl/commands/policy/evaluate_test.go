@@ -0,0 +1,87 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/calc"
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+var _ = Describe("Policy evaluation", func() {
+	wep := func(ns, pod string, ips ...string) *libapiv3.WorkloadEndpoint {
+		w := libapiv3.NewWorkloadEndpoint()
+		w.Namespace = ns
+		w.Spec.Pod = pod
+		w.Spec.IPNetworks = ips
+		return w
+	}
+	weps := []*libapiv3.WorkloadEndpoint{
+		wep("ns1", "client", "10.0.0.1/32"),
+		wep("default", "server", "fd00::2/128"),
+		wep("ns1", "pending"),
+	}
+
+	It("should resolve peers", func() {
+		ip, err := resolvePeer("ns1/client", weps)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip.String()).To(Equal("10.0.0.1"))
+
+		ip, err = resolvePeer("server", weps)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip.String()).To(Equal("fd00::2"))
+
+		ip, err = resolvePeer("192.168.0.1", weps)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip.String()).To(Equal("192.168.0.1"))
+
+		_, err = resolvePeer("ns1/pending", weps)
+		Expect(err).To(MatchError(ContainSubstring("has no IP address")))
+		_, err = resolvePeer("ns2/client", weps)
+		Expect(err).To(MatchError(ContainSubstring("no workload endpoint found")))
+	})
+
+	It("should parse protocols", func() {
+		for proto, num := range map[string]uint8{"tcp": 6, "UDP": 17, "sctp": 132, "icmpv6": 58, "47": 47} {
+			Expect(parseProtocol(proto)).To(Equal(num), proto)
+		}
+		_, err := parseProtocol("foo")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should print the verdicts", func() {
+		var buf bytes.Buffer
+		printEvalResult(&buf, calc.EvalResult{
+			Ingress: &calc.EvalVerdict{
+				Endpoint:    model.WorkloadEndpointKey{WorkloadID: "ns1/server", EndpointID: "eth0"},
+				Direction:   calc.EvalDirectionIngress,
+				Action:      "deny",
+				Tier:        "default",
+				RuleIndex:   -1,
+				Reason:      "end of tier deny",
+				Unevaluated: []string{"ns1/http rule 0: HTTP"},
+			},
+		})
+		Expect(buf.String()).To(ContainSubstring("the source is not a Calico workload endpoint"))
+		Expect(buf.String()).To(ContainSubstring("ns1/server/eth0"))
+		Expect(buf.String()).To(ContainSubstring("ns1/http rule 0: HTTP"))
+		Expect(buf.String()).To(HaveSuffix("The flow is denied.\n"))
+	})
+})
@@ -0,0 +1,24 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+package policy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+
+	"github.com/onsi/ginkgo/reporters"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/testutils"
+)
+
+func init() {
+	testutils.HookLogrusForGinkgo()
+}
+
+func TestCommands(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter("../../../report/policy_suite.xml")
+	RunSpecsWithDefaultAndCustomReporters(t, "Policy Suite", []Reporter{junitReporter})
+}
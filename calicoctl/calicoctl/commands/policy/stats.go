@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	docopt "github.com/docopt/docopt-go"
+	"github.com/olekukonko/tablewriter"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+)
+
+const (
+	packetsMetric = "felix_policy_rule_packets"
+	bytesMetric   = "felix_policy_rule_bytes"
+)
+
+// Stats shows the hit counters of the policy rules, as reported by Felix.
+func Stats(args []string) error {
+	doc := `Usage:
+  <BINARY_NAME> policy stats [--felix-url=<URL>] [--tier=<TIER>] [--policy=<NAME>]
+                       [--unused] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --felix-url=<URL>         URL of the Prometheus metrics endpoint of Felix.
+                               [default: http://localhost:9091/metrics]
+     --tier=<TIER>             Only show the rules of the policies in this tier.
+     --policy=<NAME>           Only show the rules of this policy.
+     --unused                  Only show the rules that have not matched any
+                               packets.
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  This command shows the number of packets and bytes that matched each rule of
+  the active policies on a Calico node.  It can be used to find rules that are
+  not used and to verify that a new policy matches the expected traffic.
+
+  The counters are read from the Prometheus metrics of Felix, which requires
+  PrometheusMetricsEnabled and PrometheusPolicyMetricsEnabled in the Felix
+  configuration.  In the eBPF dataplane, the counters also require
+  BPFPolicyDebugEnabled and only count the packets that are evaluated by the
+  policy, i.e. the first packets of the connections.  Byte counters are only
+  available with the iptables dataplane.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	arguments, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	url := arguments["--felix-url"].(string)
+	tier, _ := arguments["--tier"].(string)
+	policy, _ := arguments["--policy"].(string)
+	unused := arguments["--unused"].(bool)
+
+	httpClient := http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to read the Felix metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error executing command: unable to read the Felix metrics: %s", resp.Status)
+	}
+
+	stats, err := parseRuleStats(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Error executing command: unable to parse the Felix metrics: %v", err)
+	}
+	if len(stats) == 0 {
+		fmt.Println("No policy rule counters found, make sure that PrometheusPolicyMetricsEnabled is set.")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"TIER", "POLICY", "DIRECTION", "RULE", "PACKETS", "BYTES"})
+	for _, s := range stats {
+		if tier != "" && s.tier != tier {
+			continue
+		}
+		if policy != "" && s.policy != policy {
+			continue
+		}
+		if unused && s.packets != 0 {
+			continue
+		}
+		bytes := "-"
+		if s.hasBytes {
+			bytes = strconv.FormatUint(s.bytes, 10)
+		}
+		table.Append([]string{
+			s.tier,
+			s.policy,
+			s.direction,
+			strconv.Itoa(s.rule),
+			strconv.FormatUint(s.packets, 10),
+			bytes,
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+type ruleStats struct {
+	tier      string
+	policy    string
+	direction string
+	rule      int
+
+	packets  uint64
+	bytes    uint64
+	hasBytes bool
+}
+
+// parseRuleStats extracts the rule counters from the metrics in text format, sorted by
+// tier, policy, direction and rule.
+func parseRuleStats(r io.Reader) ([]*ruleStats, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, err
+	}
+
+	type ruleKey struct {
+		tier, policy, direction string
+		rule                    int
+	}
+	byRule := map[ruleKey]*ruleStats{}
+
+	for _, metricName := range []string{packetsMetric, bytesMetric} {
+		family := families[metricName]
+		if family == nil {
+			continue
+		}
+		for _, m := range family.Metric {
+			labels := map[string]string{}
+			for _, l := range m.Label {
+				labels[l.GetName()] = l.GetValue()
+			}
+			rule, err := strconv.Atoi(labels["rule"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule label %q", labels["rule"])
+			}
+			k := ruleKey{
+				tier:      labels["tier"],
+				policy:    labels["policy"],
+				direction: labels["direction"],
+				rule:      rule,
+			}
+			s := byRule[k]
+			if s == nil {
+				s = &ruleStats{tier: k.tier, policy: k.policy, direction: k.direction, rule: k.rule}
+				byRule[k] = s
+			}
+			value := uint64(m.GetCounter().GetValue())
+			if metricName == packetsMetric {
+				s.packets = value
+			} else {
+				s.bytes = value
+				s.hasBytes = true
+			}
+		}
+	}
+
+	stats := make([]*ruleStats, 0, len(byRule))
+	for _, s := range byRule {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if a.policy != b.policy {
+			return a.policy < b.policy
+		}
+		if a.direction != b.direction {
+			return a.direction < b.direction
+		}
+		return a.rule < b.rule
+	})
+	return stats, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy rule stats parsing", func() {
+	It("should merge the packet and byte counters of each rule", func() {
+		stats, err := parseRuleStats(strings.NewReader(`
+# HELP felix_policy_rule_packets Number of packets that matched a policy rule.
+# TYPE felix_policy_rule_packets counter
+felix_policy_rule_packets{direction="ingress",policy="pol2",rule="0",tier="default"} 0
+felix_policy_rule_packets{direction="ingress",policy="pol1",rule="1",tier="default"} 4
+felix_policy_rule_packets{direction="ingress",policy="pol1",rule="0",tier="default"} 10
+# HELP felix_policy_rule_bytes Number of bytes of the packets that matched a policy rule.
+# TYPE felix_policy_rule_bytes counter
+felix_policy_rule_bytes{direction="ingress",policy="pol1",rule="0",tier="default"} 1000
+# HELP felix_active_local_policies Number of active policies on this host.
+# TYPE felix_active_local_policies gauge
+felix_active_local_policies 2
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(Equal([]*ruleStats{
+			{tier: "default", policy: "pol1", direction: "ingress", rule: 0, packets: 10, bytes: 1000, hasBytes: true},
+			{tier: "default", policy: "pol1", direction: "ingress", rule: 1, packets: 4},
+			{tier: "default", policy: "pol2", direction: "ingress", rule: 0},
+		}))
+	})
+
+	It("should return nothing without policy metrics", func() {
+		stats, err := parseRuleStats(strings.NewReader("felix_active_local_policies 2\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(BeEmpty())
+	})
+})
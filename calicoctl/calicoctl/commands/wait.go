@@ -0,0 +1,108 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	docopt "github.com/docopt/docopt-go"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+)
+
+// Wait waits for a condition on resources.
+func Wait(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> wait <KIND> [<NAME>...] --for=<CONDITION> [--timeout=<TIMEOUT>]
+                [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>] [--allow-version-mismatch]
+
+Examples:
+  # Wait for an IP pool to be disabled
+  <BINARY_NAME> wait ippool my-pool --for=jsonpath='{.spec.disabled}'=true
+
+  # Wait for a network policy to be deleted
+  <BINARY_NAME> wait networkpolicy my-policy -n my-namespace --for=delete --timeout=1m
+
+  # Wait for the kube-controllers to run with a new configuration
+  <BINARY_NAME> wait kubecontrollersconfiguration default \
+      --for=jsonpath='{.status.runningConfig.healthChecks}'=Enabled
+
+Options:
+  -h --help                    Show this screen.
+     --for=<CONDITION>         The condition to wait for, one of:
+                                 delete
+                                 create
+                                 condition=<TYPE>[=<STATUS>]
+                                 jsonpath=<TEMPLATE>[=<VALUE>]
+     --timeout=<TIMEOUT>       How long to wait before giving up, as a duration
+                               such as 30s or 5m.  Zero waits forever.
+                               [default: 30s]
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+  -n --namespace=<NS>          Namespace of the resource.
+                               Only applicable to NetworkPolicy, NetworkSet, and WorkloadEndpoint.
+                               Uses the default namespace if not specified.
+     --context=<context>       The name of the kubeconfig context to use.
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  The wait command watches the resources of a type, or the named resources, and
+  returns when all of them meet the condition, so that scripts can block until
+  a change has been made instead of sleeping.  It fails if the timeout expires
+  first.  Without names, it waits for at least one resource of the type, unless
+  waiting for their deletion.
+
+  As with kubectl wait, the conditions are evaluated on the JSON form of the
+  resources:
+
+    delete          The resources do not exist.
+    create          The resources exist.
+    condition       The resources have a condition of the type in
+                    status.conditions, with the status (True by default).
+    jsonpath        The JSONPath template finds the value in the resources,
+                    or any non-empty value if no value is given.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+	if context := parsedArgs["--context"]; context != nil {
+		os.Setenv("K8S_CURRENT_CONTEXT", context.(string))
+	}
+
+	cond, err := common.ParseWaitCondition(parsedArgs["--for"].(string))
+	if err != nil {
+		return err
+	}
+	timeout, err := time.ParseDuration(parsedArgs["--timeout"].(string))
+	if err != nil {
+		return fmt.Errorf("invalid timeout %q: %v", parsedArgs["--timeout"], err)
+	}
+
+	return common.WaitFor(parsedArgs, cond, timeout, os.Stdout)
+}
@@ -23,6 +23,7 @@ import (
 
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 func init() {
@@ -59,6 +60,10 @@ func init() {
 			r := resource.(*api.BGPConfiguration)
 			return client.BGPConfigurations().List(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
 		},
+		func(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+			r := resource.(*api.BGPConfiguration)
+			return client.BGPConfigurations().Watch(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
+		},
 	)
 }
 
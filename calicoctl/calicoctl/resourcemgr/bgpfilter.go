@@ -23,6 +23,7 @@ import (
 
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 func init() {
@@ -60,6 +61,10 @@ func init() {
 			r := resource.(*api.BGPFilter)
 			return client.BGPFilter().List(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
 		},
+		func(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+			r := resource.(*api.BGPFilter)
+			return client.BGPFilter().Watch(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
+		},
 	)
 }
 
@@ -24,6 +24,7 @@ import (
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 func init() {
@@ -67,6 +68,10 @@ func init() {
 			r := resource.(*api.ClusterInformation)
 			return client.ClusterInformation().List(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
 		},
+		func(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+			r := resource.(*api.ClusterInformation)
+			return client.ClusterInformation().Watch(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
+		},
 	)
 }
 
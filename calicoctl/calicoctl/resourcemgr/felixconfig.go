@@ -23,6 +23,7 @@ import (
 
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 func init() {
@@ -56,6 +57,10 @@ func init() {
 			r := resource.(*api.FelixConfiguration)
 			return client.FelixConfigurations().List(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
 		},
+		func(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+			r := resource.(*api.FelixConfiguration)
+			return client.FelixConfigurations().Watch(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
+		},
 	)
 }
 
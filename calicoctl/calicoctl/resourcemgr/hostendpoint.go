@@ -23,6 +23,7 @@ import (
 
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 func init() {
@@ -32,13 +33,14 @@ func init() {
 		false,
 		[]string{"hostendpoint", "hostendpoints", "hep", "heps"},
 		[]string{"NAME", "NODE"},
-		[]string{"NAME", "NODE", "INTERFACE", "IPS", "PROFILES"},
+		[]string{"NAME", "NODE", "INTERFACE", "IPS", "PROFILES", "ZONE"},
 		map[string]string{
 			"NAME":      "{{.ObjectMeta.Name}}",
 			"NODE":      "{{.Spec.Node}}",
 			"INTERFACE": "{{.Spec.InterfaceName}}",
 			"IPS":       "{{join .Spec.ExpectedIPs \",\"}}",
 			"PROFILES":  "{{join .Spec.Profiles \",\"}}",
+			"ZONE":      "{{.Spec.Zone}}",
 		},
 		func(ctx context.Context, client client.Interface, resource ResourceObject) (ResourceObject, error) {
 			r := resource.(*api.HostEndpoint)
@@ -60,6 +62,10 @@ func init() {
 			r := resource.(*api.HostEndpoint)
 			return client.HostEndpoints().List(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
 		},
+		func(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+			r := resource.(*api.HostEndpoint)
+			return client.HostEndpoints().Watch(ctx, options.ListOptions{ResourceVersion: r.ResourceVersion, Name: r.Name})
+		},
 	)
 }
 
@@ -39,6 +39,7 @@ import (
 	yamlsep "github.com/projectcalico/calico/calicoctl/calicoctl/util/yaml"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
 )
 
 // ResourceManager provides a useful function for each resource type.  This includes:
@@ -55,6 +56,7 @@ type ResourceManager interface {
 	Delete(ctx context.Context, client client.Interface, resource ResourceObject) (ResourceObject, error)
 	GetOrList(ctx context.Context, client client.Interface, resource ResourceObject) (runtime.Object, error)
 	Patch(ctx context.Context, client client.Interface, resource ResourceObject, patch string) (ResourceObject, error)
+	Watch(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error)
 }
 
 // ResourceObject is implemented by all Calico resources
@@ -71,6 +73,7 @@ type ResourceListObject interface {
 
 type ResourceActionCommand func(context.Context, client.Interface, ResourceObject) (ResourceObject, error)
 type ResourceListActionCommand func(context.Context, client.Interface, ResourceObject) (ResourceListObject, error)
+type ResourceWatchCommand func(context.Context, client.Interface, ResourceObject) (watch.Interface, error)
 
 // ResourceHelper encapsulates details about a specific version of a specific resource:
 //
@@ -78,7 +81,7 @@ type ResourceListActionCommand func(context.Context, client.Interface, ResourceO
 //     though they are not strictly resources themselves).
 //   - The concrete resource struct for this version
 //   - Template strings used to format output for each resource type.
-//   - Functions to handle resource management actions (apply, create, update, delete, list).
+//   - Functions to handle resource management actions (apply, create, update, delete, list, watch).
 //     These functions are an untyped interface (generic Resource interfaces) that map through
 //     to the Calico clients typed interface.
 type resourceHelper struct {
@@ -95,6 +98,7 @@ type resourceHelper struct {
 	delete            ResourceActionCommand
 	get               ResourceActionCommand
 	list              ResourceListActionCommand
+	watch             ResourceWatchCommand
 }
 
 func (rh resourceHelper) String() string {
@@ -111,7 +115,7 @@ var kindToRes = make(map[string]ResourceObject)
 
 func registerResource(res ResourceObject, resList ResourceListObject, isNamespaced bool, names []string,
 	tableHeadings []string, tableHeadingsWide []string, headingsMap map[string]string,
-	create, update, delete, get ResourceActionCommand, list ResourceListActionCommand) {
+	create, update, delete, get ResourceActionCommand, list ResourceListActionCommand, watch ResourceWatchCommand) {
 
 	if helpers == nil {
 		helpers = make(map[schema.GroupVersionKind]resourceHelper)
@@ -130,6 +134,7 @@ func registerResource(res ResourceObject, resList ResourceListObject, isNamespac
 		delete:            delete,
 		get:               get,
 		list:              list,
+		watch:             watch,
 	}
 	helpers[res.GetObjectKind().GroupVersionKind()] = rh
 
@@ -330,6 +335,13 @@ func (rh resourceHelper) Patch(ctx context.Context, client client.Interface, res
 	return resource, nil
 }
 
+// Watch is an un-typed method to watch the resources of a type. This calls directly
+// through to the resource helper specific Watch method. If the resource name or namespace
+// are set, only the matching resources are watched.
+func (rh resourceHelper) Watch(ctx context.Context, client client.Interface, resource ResourceObject) (watch.Interface, error) {
+	return rh.watch(ctx, client, resource)
+}
+
 // GetResourceManager returns the Resource Manager for a particular resource type.
 func GetResourceManager(resource runtime.Object) ResourceManager {
 	return helpers[resource.GetObjectKind().GroupVersionKind()]
@@ -0,0 +1,156 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache implements a small file cache of datastore lookups, which the invocations of the
+// CNI plugin on a node share so that a pod ADD needs fewer round trips to the API server.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+)
+
+const defaultDir = "/var/run/calico/cni-cache"
+
+// Cache is a file cache of datastore lookups.  Entries expire after a TTL and are invalidated when
+// the CNI network configuration changes.  A nil *Cache is valid and caches nothing.
+type Cache struct {
+	dir        string
+	ttl        time.Duration
+	configHash string
+}
+
+type entry struct {
+	ConfigHash string          `json:"configHash"`
+	Expires    time.Time       `json:"expires"`
+	Value      json.RawMessage `json:"value"`
+}
+
+// New returns the cache for the CNI network configuration, or nil if caching is disabled.
+func New(conf types.NetConf) *Cache {
+	if conf.CacheTTLSeconds <= 0 {
+		return nil
+	}
+	dir := conf.CacheDir
+	if dir == "" {
+		dir = defaultDir
+	}
+
+	// Hash the configuration without the fields that differ between invocations, including the
+	// IPAM fields that the plugin sets from pod annotations.
+	conf.RuntimeConfig = types.RuntimeConfig{}
+	conf.RawPrevResult = nil
+	conf.Args = types.Args{}
+	conf.IPAM.IPv4Pools = nil
+	conf.IPAM.IPv6Pools = nil
+	conf.IPAM.AssignIpv4 = nil
+	conf.IPAM.AssignIpv6 = nil
+	data, err := json.Marshal(conf)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to hash CNI configuration, not caching")
+		return nil
+	}
+	hash := sha256.Sum256(data)
+
+	return &Cache{
+		dir:        dir,
+		ttl:        time.Duration(conf.CacheTTLSeconds) * time.Second,
+		configHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+// Get loads the value of key into value, and returns whether it was found.
+func (c *Cache) Get(key string, value interface{}) bool {
+	if c == nil {
+		return false
+	}
+	logCtx := logrus.WithField("key", key)
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		logCtx.WithError(err).Debug("Cache miss")
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		logCtx.WithError(err).Warn("Ignoring corrupt cache entry")
+		return false
+	}
+	if e.ConfigHash != c.configHash {
+		logCtx.Debug("Cache entry is for another CNI configuration")
+		return false
+	}
+	if time.Now().After(e.Expires) {
+		logCtx.Debug("Cache entry expired")
+		return false
+	}
+	if err := json.Unmarshal(e.Value, value); err != nil {
+		logCtx.WithError(err).Warn("Ignoring corrupt cache entry")
+		return false
+	}
+	logCtx.Debug("Cache hit")
+	return true
+}
+
+// Set stores value under key.  Caching is best effort, so errors are only logged.
+func (c *Cache) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	logCtx := logrus.WithField("key", key)
+	v, err := json.Marshal(value)
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to marshal cache entry")
+		return
+	}
+	data, err := json.Marshal(entry{ConfigHash: c.configHash, Expires: time.Now().Add(c.ttl), Value: v})
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to marshal cache entry")
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		logCtx.WithError(err).Warn("Failed to create cache directory")
+		return
+	}
+
+	// Write to a temporary file and rename it, so that concurrent invocations never read a
+	// partial entry.
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to write cache entry")
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		logCtx.WithError(err).Warn("Failed to write cache entry")
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
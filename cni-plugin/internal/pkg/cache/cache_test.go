@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+)
+
+var _ = Describe("CNI cache", func() {
+	var dir string
+	var conf types.NetConf
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "cni-cache")
+		Expect(err).NotTo(HaveOccurred())
+		conf = types.NetConf{Name: "k8s-pod-network", CacheTTLSeconds: 30, CacheDir: dir}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should be disabled without a TTL", func() {
+		c := New(types.NetConf{CacheDir: dir})
+		Expect(c).To(BeNil())
+		c.Set("key", "value")
+		var v string
+		Expect(c.Get("key", &v)).To(BeFalse())
+	})
+
+	It("should return what was set", func() {
+		New(conf).Set("key", []string{"10.0.0.0/16"})
+		var v []string
+		Expect(New(conf).Get("key", &v)).To(BeTrue())
+		Expect(v).To(Equal([]string{"10.0.0.0/16"}))
+	})
+
+	It("should miss a key that wasn't set", func() {
+		var v string
+		Expect(New(conf).Get("key", &v)).To(BeFalse())
+	})
+
+	It("should ignore fields that differ between invocations", func() {
+		New(conf).Set("key", "value")
+		conf.RawPrevResult = map[string]interface{}{"cniVersion": "1.0.0"}
+		conf.IPAM.IPv4Pools = []string{"pool-a"}
+		var v string
+		Expect(New(conf).Get("key", &v)).To(BeTrue())
+	})
+
+	It("should invalidate entries when the configuration changes", func() {
+		New(conf).Set("key", "value")
+		conf.DatastoreType = "etcdv3"
+		var v string
+		Expect(New(conf).Get("key", &v)).To(BeFalse())
+	})
+
+	It("should expire entries", func() {
+		c := New(conf)
+		c.ttl = -1
+		c.Set("key", "value")
+		var v string
+		Expect(New(conf).Get("key", &v)).To(BeFalse())
+	})
+
+	It("should ignore corrupt entries", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "key.json"), []byte("{"), 0600)).To(Succeed())
+		var v string
+		Expect(New(conf).Get("key", &v)).To(BeFalse())
+	})
+})
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chained implements Calico's chained mode, in which the Calico CNI plugin runs after a
+// cloud CNI plugin (AWS VPC CNI, Azure CNI, GKE) that has already assigned the pod's IPs and created
+// its interfaces.  Calico then only creates the WorkloadEndpoint, so that Felix can police the pod
+// through the host-side interface of the upstream plugin.
+package chained
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cniv1 "github.com/containernetworking/cni/pkg/types/100"
+	cniversion "github.com/containernetworking/cni/pkg/version"
+
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+)
+
+// upstreamInterfacePrefixes maps the upstream plugins that Calico supports chaining after to the
+// prefix of the host-side interfaces that they create for pods.
+var upstreamInterfacePrefixes = map[string]string{
+	"aws-cni":    "eni",
+	"azure-vnet": "azv",
+	"gke":        "gke",
+	"ptp":        "veth",
+}
+
+// Enabled returns whether the CNI configuration runs Calico in chained mode.
+func Enabled(conf types.NetConf) bool {
+	return conf.Chained.UpstreamPlugin != ""
+}
+
+// InterfacePrefix returns the prefix of the host-side interfaces of the upstream plugin.
+func InterfacePrefix(conf types.ChainedConf) (string, error) {
+	if conf.InterfacePrefix != "" {
+		return conf.InterfacePrefix, nil
+	}
+	prefix, ok := upstreamInterfacePrefixes[conf.UpstreamPlugin]
+	if !ok {
+		return "", fmt.Errorf("unsupported upstream plugin %q for chained mode, set interface_prefix to use it", conf.UpstreamPlugin)
+	}
+	return prefix, nil
+}
+
+// PrevResult returns the result of the upstream plugin, converted to the current CNI version.
+func PrevResult(conf types.NetConf) (*cniv1.Result, error) {
+	if conf.RawPrevResult == nil {
+		return nil, errors.New("chained mode requires the result of the upstream plugin in prevResult")
+	}
+	data, err := json.Marshal(conf.RawPrevResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal prevResult: %v", err)
+	}
+	prevResult, err := cniversion.NewResult(conf.CNIVersion, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prevResult: %v", err)
+	}
+	result, err := cniv1.NewResultFromResult(prevResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert prevResult: %v", err)
+	}
+	if len(result.IPs) == 0 {
+		return nil, errors.New("prevResult has no IP addresses")
+	}
+	return result, nil
+}
+
+// ContainerInterface returns the interface that the upstream plugin created in the pod's network
+// namespace with the name ifName.
+func ContainerInterface(result *cniv1.Result, ifName string) (*cniv1.Interface, error) {
+	for _, iface := range result.Interfaces {
+		if iface.Sandbox != "" && iface.Name == ifName {
+			return iface, nil
+		}
+	}
+	return nil, fmt.Errorf("prevResult has no interface %s in the pod's network namespace", ifName)
+}
+
+// HostInterface returns the host-side interface in the result of the upstream plugin, that is the
+// interface outside the pod's network namespace with the prefix of the upstream plugin.  It returns
+// nil if the upstream plugin doesn't report its host-side interface, and an error if the result is
+// ambiguous.
+func HostInterface(result *cniv1.Result, prefix string) (*cniv1.Interface, error) {
+	var hostIface *cniv1.Interface
+	for _, iface := range result.Interfaces {
+		if iface.Sandbox != "" || !strings.HasPrefix(iface.Name, prefix) {
+			continue
+		}
+		if hostIface != nil {
+			return nil, fmt.Errorf("prevResult has more than one host interface with prefix %q: %s and %s",
+				prefix, hostIface.Name, iface.Name)
+		}
+		hostIface = iface
+	}
+	return hostIface, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chained
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containernetworking/cni/pkg/skel"
+	cniv1 "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+)
+
+// SetUpEndpoint checks the networking that the upstream plugin set up for the pod and returns the
+// name of the host-side interface and the MAC of the pod's interface for its WorkloadEndpoint.
+//
+// The host-side interface is found from the peer of the pod's veth rather than from the names in
+// prevResult, which not every upstream plugin reports.  The host must route each of the pod's IPs
+// through that interface, otherwise Felix's policy would be bypassed.  If the interfaces have a
+// larger MTU than the one that Calico computed, for example because Calico encrypts traffic with
+// WireGuard, their MTU is lowered.
+func SetUpEndpoint(args *skel.CmdArgs, conf types.NetConf, result *cniv1.Result, logger *logrus.Entry) (hostIfName, contMAC string, err error) {
+	prefix, err := InterfacePrefix(conf.Chained)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err = ContainerInterface(result, args.IfName); err != nil {
+		return "", "", err
+	}
+
+	var peerIndex, contMTU int
+	err = ns.WithNetNSPath(args.Netns, func(_ ns.NetNS) error {
+		contLink, err := netlink.LinkByName(args.IfName)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s in the pod's network namespace: %v", args.IfName, err)
+		}
+		veth, ok := contLink.(*netlink.Veth)
+		if !ok {
+			return fmt.Errorf("%s is a %s, chained mode requires the upstream plugin to create a veth",
+				args.IfName, contLink.Type())
+		}
+		peerIndex, err = netlink.VethPeerIndex(veth)
+		if err != nil {
+			return fmt.Errorf("failed to look up the peer of %s: %v", args.IfName, err)
+		}
+		contMAC = contLink.Attrs().HardwareAddr.String()
+		contMTU = contLink.Attrs().MTU
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	hostLink, err := netlink.LinkByIndex(peerIndex)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up the host-side interface of %s: %v", args.IfName, err)
+	}
+	hostIfName = hostLink.Attrs().Name
+	logger = logger.WithField("hostInterface", hostIfName)
+
+	if !strings.HasPrefix(hostIfName, prefix) {
+		return "", "", fmt.Errorf("host-side interface %s doesn't have the prefix %q of upstream plugin %s",
+			hostIfName, prefix, conf.Chained.UpstreamPlugin)
+	}
+	if hostIface, err := HostInterface(result, prefix); err != nil {
+		return "", "", err
+	} else if hostIface != nil && hostIface.Name != hostIfName {
+		return "", "", fmt.Errorf("prevResult has host interface %s but the peer of %s is %s",
+			hostIface.Name, args.IfName, hostIfName)
+	}
+
+	for _, ip := range result.IPs {
+		routes, err := netlink.RouteGet(ip.Address.IP)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to look up the route to %s: %v", ip.Address.IP, err)
+		}
+		if len(routes) == 0 || routes[0].LinkIndex != hostLink.Attrs().Index {
+			return "", "", fmt.Errorf("the host doesn't route %s through %s", ip.Address.IP, hostIfName)
+		}
+	}
+
+	if conf.MTU > 0 && contMTU > conf.MTU {
+		logger.WithField("mtu", conf.MTU).Info("Lowering MTU of pod interface")
+		err = ns.WithNetNSPath(args.Netns, func(_ ns.NetNS) error {
+			contLink, err := netlink.LinkByName(args.IfName)
+			if err != nil {
+				return err
+			}
+			return netlink.LinkSetMTU(contLink, conf.MTU)
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to set MTU of %s: %v", args.IfName, err)
+		}
+	}
+	if conf.MTU > 0 && hostLink.Attrs().MTU > conf.MTU {
+		logger.WithField("mtu", conf.MTU).Info("Lowering MTU of host-side interface")
+		if err = netlink.LinkSetMTU(hostLink, conf.MTU); err != nil {
+			return "", "", fmt.Errorf("failed to set MTU of %s: %v", hostIfName, err)
+		}
+	}
+
+	logger.Info("Verified networking of upstream plugin")
+	return hostIfName, contMAC, nil
+}
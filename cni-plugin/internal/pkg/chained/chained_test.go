@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chained_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/chained"
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+)
+
+// Results of the upstream plugins that Calico supports chaining after, as passed to Calico in
+// prevResult.
+const (
+	awsCNIResult = `{
+		"cniVersion": "0.4.0",
+		"interfaces": [
+			{"name": "eni8ea2c11fe35", "mac": "aa:bb:cc:00:00:01"},
+			{"name": "eth0", "mac": "aa:bb:cc:00:00:02", "sandbox": "/var/run/netns/cni-1"},
+			{"name": "dummy8ea2c11fe35", "mac": "0", "sandbox": "0"}
+		],
+		"ips": [{"version": "4", "interface": 1, "address": "192.168.10.5/32"}],
+		"dns": {}
+	}`
+	azureVnetResult = `{
+		"cniVersion": "0.3.0",
+		"interfaces": [{"name": "eth0", "sandbox": "/var/run/netns/cni-2"}],
+		"ips": [{"version": "4", "address": "10.240.0.12/16", "gateway": "10.240.0.1"}],
+		"dns": {}
+	}`
+	gkeResult = `{
+		"cniVersion": "1.0.0",
+		"interfaces": [
+			{"name": "gke3ba1c7e2f4a", "mac": "aa:bb:cc:00:00:03"},
+			{"name": "eth0", "mac": "aa:bb:cc:00:00:04", "sandbox": "/var/run/netns/cni-3"}
+		],
+		"ips": [
+			{"interface": 1, "address": "10.4.0.7/24", "gateway": "10.4.0.1"},
+			{"interface": 1, "address": "fd00:10:4::7/112", "gateway": "fd00:10:4::1"}
+		],
+		"dns": {}
+	}`
+	ptpResult = `{
+		"cniVersion": "0.3.1",
+		"interfaces": [
+			{"name": "veth9a7d5b21", "mac": "aa:bb:cc:00:00:05"},
+			{"name": "eth0", "mac": "aa:bb:cc:00:00:06", "sandbox": "/var/run/netns/cni-4"}
+		],
+		"ips": [{"version": "4", "interface": 1, "address": "10.1.1.9/24", "gateway": "10.1.1.1"}],
+		"dns": {}
+	}`
+)
+
+func chainedConf(upstream, prevResult string) types.NetConf {
+	conf := types.NetConf{Chained: types.ChainedConf{UpstreamPlugin: upstream}}
+	if prevResult != "" {
+		Expect(json.Unmarshal([]byte(prevResult), &conf.RawPrevResult)).To(Succeed())
+		conf.CNIVersion = conf.RawPrevResult["cniVersion"].(string)
+	}
+	return conf
+}
+
+var _ = DescribeTable("Chaining after upstream plugins",
+	func(upstream, prevResult, expectedPrefix, expectedHostIface string, expectedIPs []string) {
+		conf := chainedConf(upstream, prevResult)
+		Expect(chained.Enabled(conf)).To(BeTrue())
+
+		prefix, err := chained.InterfacePrefix(conf.Chained)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefix).To(Equal(expectedPrefix))
+
+		result, err := chained.PrevResult(conf)
+		Expect(err).NotTo(HaveOccurred())
+		ips := []string{}
+		for _, ip := range result.IPs {
+			ips = append(ips, ip.Address.String())
+		}
+		Expect(ips).To(Equal(expectedIPs))
+
+		contIface, err := chained.ContainerInterface(result, "eth0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contIface.Name).To(Equal("eth0"))
+
+		hostIface, err := chained.HostInterface(result, prefix)
+		Expect(err).NotTo(HaveOccurred())
+		if expectedHostIface == "" {
+			Expect(hostIface).To(BeNil())
+		} else {
+			Expect(hostIface).NotTo(BeNil())
+			Expect(hostIface.Name).To(Equal(expectedHostIface))
+		}
+	},
+	Entry("AWS VPC CNI", "aws-cni", awsCNIResult, "eni", "eni8ea2c11fe35", []string{"192.168.10.5/32"}),
+	Entry("Azure CNI, which doesn't report its host interface", "azure-vnet", azureVnetResult, "azv", "", []string{"10.240.0.12/16"}),
+	Entry("GKE", "gke", gkeResult, "gke", "gke3ba1c7e2f4a", []string{"10.4.0.7/24", "fd00:10:4::7/112"}),
+	Entry("ptp", "ptp", ptpResult, "veth", "veth9a7d5b21", []string{"10.1.1.9/24"}),
+)
+
+var _ = Describe("Chained mode", func() {
+	It("should be disabled without an upstream plugin", func() {
+		Expect(chained.Enabled(types.NetConf{})).To(BeFalse())
+	})
+
+	It("should use the configured interface prefix", func() {
+		prefix, err := chained.InterfacePrefix(types.ChainedConf{UpstreamPlugin: "aws-cni", InterfacePrefix: "pod"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefix).To(Equal("pod"))
+	})
+
+	It("should reject an unknown upstream plugin without an interface prefix", func() {
+		_, err := chained.InterfacePrefix(types.ChainedConf{UpstreamPlugin: "flannel"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should require a prevResult", func() {
+		_, err := chained.PrevResult(chainedConf("aws-cni", ""))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a prevResult without IPs", func() {
+		_, err := chained.PrevResult(chainedConf("ptp", `{"cniVersion": "0.3.1", "interfaces": [], "ips": []}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if the pod interface isn't in prevResult", func() {
+		result, err := chained.PrevResult(chainedConf("ptp", ptpResult))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = chained.ContainerInterface(result, "eth1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if prevResult has several host interfaces", func() {
+		result, err := chained.PrevResult(chainedConf("ptp", ptpResult))
+		Expect(err).NotTo(HaveOccurred())
+		result.Interfaces = append(result.Interfaces, result.Interfaces[0])
+		_, err = chained.HostInterface(result, "veth")
+		Expect(err).To(HaveOccurred())
+	})
+})
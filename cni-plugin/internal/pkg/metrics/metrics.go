@@ -0,0 +1,163 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics keeps metrics of the CNI plugin in a file in the Prometheus text format.  Each
+// invocation of the plugin is a short-lived process, so the metrics are accumulated in a state file
+// next to the metrics file, under a file lock.
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/sirupsen/logrus"
+)
+
+// addDurationBuckets are the upper bounds, in seconds, of the buckets of the ADD latency histogram.
+var addDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type state struct {
+	BucketCounts []uint64 `json:"bucketCounts"`
+	Count        uint64   `json:"count"`
+	SumSeconds   float64  `json:"sumSeconds"`
+	Errors       uint64   `json:"errors"`
+
+	// PoolExhaustions counts the allocations that failed because the IP pools were exhausted, by
+	// IP version.
+	PoolExhaustions map[string]uint64 `json:"poolExhaustions,omitempty"`
+}
+
+// RecordAdd records the latency of a pod ADD, and whether it failed, in the metrics file.  Metrics
+// are best effort, so errors are only logged.
+func RecordAdd(file string, duration time.Duration, failed bool) {
+	if file == "" {
+		return
+	}
+	err := update(file, func(s *state) {
+		s.observe(duration, failed)
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("file", file).Warn("Failed to record ADD metrics")
+	}
+}
+
+// RecordPoolExhaustion records, in the metrics file, an IP allocation that failed because the IP
+// pools of the given IP version were exhausted.
+func RecordPoolExhaustion(file string, ipVersion int) {
+	if file == "" {
+		return
+	}
+	err := update(file, func(s *state) {
+		if s.PoolExhaustions == nil {
+			s.PoolExhaustions = map[string]uint64{}
+		}
+		s.PoolExhaustions[strconv.Itoa(ipVersion)]++
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("file", file).Warn("Failed to record IP pool exhaustion metrics")
+	}
+}
+
+// update applies fn to the metrics in the state file, under the file lock, and re-renders the
+// metrics file.
+func update(file string, fn func(s *state)) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	lock := flock.New(file + ".lock")
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	stateFile := file + ".state"
+	s := state{}
+	if data, err := os.ReadFile(stateFile); err == nil {
+		if err := json.Unmarshal(data, &s); err != nil {
+			logrus.WithError(err).Warn("Resetting corrupt CNI metrics")
+			s = state{}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if len(s.BucketCounts) != len(addDurationBuckets) {
+		// No ADDs recorded yet, or the buckets changed.
+		s.BucketCounts = make([]uint64, len(addDurationBuckets))
+		s.Count = 0
+		s.SumSeconds = 0
+	}
+	fn(&s)
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(stateFile, data); err != nil {
+		return err
+	}
+	return writeFile(file, s.render())
+}
+
+func (s *state) observe(duration time.Duration, failed bool) {
+	seconds := duration.Seconds()
+	for i, le := range addDurationBuckets {
+		if seconds <= le {
+			s.BucketCounts[i]++
+		}
+	}
+	s.Count++
+	s.SumSeconds += seconds
+	if failed {
+		s.Errors++
+	}
+}
+
+func (s *state) render() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "# HELP calico_cni_add_duration_seconds Latency of pod ADDs by the Calico CNI plugin.")
+	fmt.Fprintln(&buf, "# TYPE calico_cni_add_duration_seconds histogram")
+	for i, le := range addDurationBuckets {
+		fmt.Fprintf(&buf, "calico_cni_add_duration_seconds_bucket{le=%q} %d\n",
+			strconv.FormatFloat(le, 'g', -1, 64), s.BucketCounts[i])
+	}
+	fmt.Fprintf(&buf, "calico_cni_add_duration_seconds_bucket{le=\"+Inf\"} %d\n", s.Count)
+	fmt.Fprintf(&buf, "calico_cni_add_duration_seconds_sum %s\n", strconv.FormatFloat(s.SumSeconds, 'g', -1, 64))
+	fmt.Fprintf(&buf, "calico_cni_add_duration_seconds_count %d\n", s.Count)
+	fmt.Fprintln(&buf, "# HELP calico_cni_add_errors_total Number of pod ADDs by the Calico CNI plugin that failed.")
+	fmt.Fprintln(&buf, "# TYPE calico_cni_add_errors_total counter")
+	fmt.Fprintf(&buf, "calico_cni_add_errors_total %d\n", s.Errors)
+	fmt.Fprintln(&buf, "# HELP calico_cni_ipam_pool_exhausted_total Number of IP allocations that failed because the IP pools were exhausted.")
+	fmt.Fprintln(&buf, "# TYPE calico_cni_ipam_pool_exhausted_total counter")
+	for _, version := range []string{"4", "6"} {
+		fmt.Fprintf(&buf, "calico_cni_ipam_pool_exhausted_total{ip_version=%q} %d\n", version, s.PoolExhaustions[version])
+	}
+	return buf.Bytes()
+}
+
+// writeFile writes a file through a temporary file, so that readers never see a partial file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ADD metrics", func() {
+	var dir, file string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "cni-metrics")
+		Expect(err).NotTo(HaveOccurred())
+		file = filepath.Join(dir, "metrics", "cni.prom")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should accumulate ADDs across invocations", func() {
+		RecordAdd(file, 80*time.Millisecond, false)
+		RecordAdd(file, 3*time.Second, true)
+
+		data, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_bucket{le=\"0.05\"} 0\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_bucket{le=\"0.1\"} 1\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_bucket{le=\"5\"} 2\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_bucket{le=\"+Inf\"} 2\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_sum 3.08\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_count 2\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_errors_total 1\n"))
+	})
+
+	It("should count pool exhaustion per IP version", func() {
+		RecordPoolExhaustion(file, 6)
+		RecordAdd(file, time.Second, true)
+		RecordPoolExhaustion(file, 6)
+
+		data, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("calico_cni_ipam_pool_exhausted_total{ip_version=\"4\"} 0\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_ipam_pool_exhausted_total{ip_version=\"6\"} 2\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_count 1\n"))
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_errors_total 1\n"))
+	})
+
+	It("should do nothing without a metrics file", func() {
+		RecordAdd("", time.Second, false)
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should reset corrupt state", func() {
+		Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+		Expect(os.WriteFile(file+".state", []byte("{"), 0644)).To(Succeed())
+		RecordAdd(file, time.Second, false)
+
+		data, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("calico_cni_add_duration_seconds_count 1\n"))
+	})
+})
@@ -40,3 +40,32 @@ func init() {
 		IPv6AllNet, // Only used if we end up adding a v6 address.
 	}
 }
+
+// RoutesForIPFamily returns the routes of an IP family.  If there are none, it returns the default
+// route of the family, so that a pod with addresses of a family always has a route for them, for
+// example an IPv6-only pod when only IPv4 routes are configured.
+func RoutesForIPFamily(routes []*net.IPNet, ipv4 bool) []*net.IPNet {
+	var familyRoutes []*net.IPNet
+	for _, r := range routes {
+		if (r.IP.To4() != nil) == ipv4 {
+			familyRoutes = append(familyRoutes, r)
+		}
+	}
+	if len(familyRoutes) > 0 {
+		return familyRoutes
+	}
+	if ipv4 {
+		return []*net.IPNet{IPv4AllNet}
+	}
+	return []*net.IPNet{IPv6AllNet}
+}
+
+// MACFromIP derives a stable, locally administered unicast MAC address from a pod IP.  The MAC is
+// 0e:ca followed by the IPv4 address, or by the last four bytes of an IPv6 address.
+func MACFromIP(ip net.IP) net.HardwareAddr {
+	addr := ip.To4()
+	if addr == nil {
+		addr = ip.To16()[12:]
+	}
+	return append(net.HardwareAddr{0x0e, 0xca}, addr...)
+}
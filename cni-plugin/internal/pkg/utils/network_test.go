@@ -0,0 +1,50 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("Routes for an IP family",
+	func(routes []string, ipv4 bool, expected []string) {
+		var nets []*net.IPNet
+		for _, r := range routes {
+			_, n, err := net.ParseCIDR(r)
+			Expect(err).NotTo(HaveOccurred())
+			nets = append(nets, n)
+		}
+		actual := []string{}
+		for _, r := range RoutesForIPFamily(nets, ipv4) {
+			actual = append(actual, r.String())
+		}
+		Expect(actual).To(Equal(expected))
+	},
+	Entry("IPv4 routes", []string{"10.0.0.0/8", "fd00::/8"}, true, []string{"10.0.0.0/8"}),
+	Entry("IPv6 routes", []string{"10.0.0.0/8", "fd00::/8"}, false, []string{"fd00::/8"}),
+	Entry("IPv6 default route when only IPv4 routes", []string{"10.0.0.0/8"}, false, []string{"::/0"}),
+	Entry("IPv4 default route when only IPv6 routes", []string{"fd00::/8"}, true, []string{"0.0.0.0/0"}),
+)
+
+var _ = DescribeTable("MAC from IP",
+	func(ip, expected string) {
+		Expect(MACFromIP(net.ParseIP(ip)).String()).To(Equal(expected))
+	},
+	Entry("IPv4", "10.65.0.2", "0e:ca:0a:41:00:02"),
+	Entry("IPv6", "fd00::a41:3", "0e:ca:0a:41:00:03"),
+)
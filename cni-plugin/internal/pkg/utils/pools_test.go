@@ -0,0 +1,62 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = DescribeTable("Resolving IP pools",
+	func(pools []string, isv4 bool, expected []string, expectErr bool) {
+		ipPool := func(name, cidr string, labels map[string]string, disabled bool) apiv3.IPPool {
+			return apiv3.IPPool{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				Spec:       apiv3.IPPoolSpec{CIDR: cidr, Disabled: disabled},
+			}
+		}
+		ipPools := []apiv3.IPPool{
+			ipPool("default-ipv4", "10.0.0.0/16", nil, false),
+			ipPool("gpu-a", "10.1.0.0/16", map[string]string{"routable": "true"}, false),
+			ipPool("gpu-b", "10.2.0.0/16", map[string]string{"routable": "true"}, false),
+			ipPool("gpu-old", "10.3.0.0/16", map[string]string{"routable": "true"}, true),
+			ipPool("gpu-v6", "fd00:1::/64", map[string]string{"routable": "true"}, false),
+		}
+
+		result, err := resolvePools(ipPools, pools, isv4)
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		actual := []string{}
+		for _, r := range result {
+			actual = append(actual, r.String())
+		}
+		Expect(actual).To(Equal(expected))
+	},
+	Entry("CIDR", []string{"192.168.0.0/24"}, true, []string{"192.168.0.0/24"}, false),
+	Entry("pool name", []string{"default-ipv4"}, true, []string{"10.0.0.0/16"}, false),
+	Entry("unknown pool name", []string{"missing"}, true, nil, true),
+	Entry("wildcard", []string{"gpu-*"}, true, []string{"10.1.0.0/16", "10.2.0.0/16"}, false),
+	Entry("IPv6 wildcard", []string{"gpu-*"}, false, []string{"fd00:1::/64"}, false),
+	Entry("selector", []string{"routable == 'true'"}, true, []string{"10.1.0.0/16", "10.2.0.0/16"}, false),
+	Entry("selector and pool name", []string{"gpu-b", "routable == 'true'"}, true, []string{"10.2.0.0/16", "10.1.0.0/16"}, false),
+	Entry("selector matching no pool", []string{"routable == 'false'"}, true, nil, true),
+	Entry("pool name of the wrong IP version", []string{"gpu-v6"}, true, nil, true),
+)
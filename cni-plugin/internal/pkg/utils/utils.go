@@ -22,6 +22,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -34,7 +35,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
 	"github.com/projectcalico/calico/cni-plugin/internal/pkg/azure"
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/cache"
 	"github.com/projectcalico/calico/cni-plugin/pkg/types"
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
 	api "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
@@ -42,6 +46,7 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/names"
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
 )
 
 // DetermineNodename gets the node name, in order of priority:
@@ -620,6 +625,21 @@ func GetHandleID(netName, containerID, workload string) string {
 	return handleID
 }
 
+// GetStickyHandleID returns the IPAM handle of the sticky IPs of a pod.  Unlike the handle of
+// GetHandleID, it does not change when the pod is restarted with a new container, so that the
+// IPs allocated with it can be re-used.
+func GetStickyHandleID(netName, namespace, pod string) string {
+	handleID := fmt.Sprintf("%s.sticky.%s.%s", netName, namespace, pod)
+
+	logrus.WithFields(logrus.Fields{
+		"HandleID":  handleID,
+		"Network":   netName,
+		"Namespace": namespace,
+		"Pod":       pod,
+	}).Debug("Generated sticky IPAM handle")
+	return handleID
+}
+
 func CreateClient(conf types.NetConf) (client.Interface, error) {
 	if err := ValidateNetworkName(conf.Name); err != nil {
 		return nil, err
@@ -767,23 +787,55 @@ func ConfigureLogging(conf types.NetConf) {
 	logrus.SetOutput(mw)
 }
 
-// ResolvePools takes an array of CIDRs or IP Pool names and resolves it to a slice of pool CIDRs.
-func ResolvePools(ctx context.Context, c client.Interface, pools []string, isv4 bool) ([]cnet.IPNet, error) {
+// ResolvePools takes an array of CIDRs, IP Pool names or IP Pool selectors and resolves it to a slice of pool CIDRs.
+//
+// An entry that is not a CIDR or the name of a pool is either a wildcard of pool names, such as "gpu-*", if it
+// contains a '*' or '?', or a selector of pool labels, such as "routable == 'true'".  It resolves to all the
+// enabled pools of the IP version that it matches.  The IP pools are looked up in the cache pc first, if
+// it isn't nil.
+func ResolvePools(ctx context.Context, c client.Interface, pc *cache.Cache, pools []string, isv4 bool) ([]cnet.IPNet, error) {
+	if len(pools) == 0 {
+		return []cnet.IPNet{}, nil
+	}
+
 	// First, query all IP pools. We need these so we can resolve names to CIDRs.
-	pl, err := c.IPPools().List(ctx, options.ListOptions{})
-	if err != nil {
-		return nil, err
+	var ipPools []apiv3.IPPool
+	if !pc.Get("ip-pools", &ipPools) {
+		pl, err := c.IPPools().List(ctx, options.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		ipPools = pl.Items
+		pc.Set("ip-pools", ipPools)
 	}
+	return resolvePools(ipPools, pools, isv4)
+}
 
+func resolvePools(ipPools []apiv3.IPPool, pools []string, isv4 bool) ([]cnet.IPNet, error) {
 	// Iterate through the provided pools. If it parses as a CIDR, just use that.
 	// If it does not parse as a CIDR, then attempt to lookup an IP pool with a matching name.
 	result := []cnet.IPNet{}
+	seen := map[string]bool{}
+	add := func(cidr *net.IPNet) error {
+		ip := cidr.IP
+		if isv4 && ip.To4() == nil {
+			return fmt.Errorf("%q isn't a IPv4 address", ip)
+		}
+		if !isv4 && ip.To4() != nil {
+			return fmt.Errorf("%q isn't a IPv6 address", ip)
+		}
+		if !seen[cidr.String()] {
+			seen[cidr.String()] = true
+			result = append(result, cnet.IPNet{IPNet: *cidr})
+		}
+		return nil
+	}
 	for _, p := range pools {
 		_, cidr, err := net.ParseCIDR(p)
 		if err != nil {
 			// Didn't parse as a CIDR - check if it's the name
 			// of a configured IP pool.
-			for _, ipp := range pl.Items {
+			for _, ipp := range ipPools {
 				if ipp.Name == p {
 					// Found a match. Use the CIDR from the matching pool.
 					_, cidr, err = net.ParseCIDR(ipp.Spec.CIDR)
@@ -793,21 +845,66 @@ func ResolvePools(ctx context.Context, c client.Interface, pools []string, isv4
 					logrus.Infof("Resolved pool name %s to cidr %s", ipp.Name, cidr)
 				}
 			}
-
-			if cidr == nil {
+		}
+		if cidr == nil {
+			// Not the name of a pool either - check if it's a wildcard or a selector.
+			matches, matchErr := matchPools(ipPools, p, isv4)
+			if matchErr != nil {
 				// Unable to resolve this pool to a CIDR - return an error.
 				return nil, fmt.Errorf("error parsing pool %q: %s", p, err)
 			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no enabled IP pool of the IP version matches %q", p)
+			}
+			for _, m := range matches {
+				if err := add(m); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := add(cidr); err != nil {
+			return nil, err
 		}
+	}
+	return result, nil
+}
 
-		ip := cidr.IP
-		if isv4 && ip.To4() == nil {
-			return nil, fmt.Errorf("%q isn't a IPv4 address", ip)
+// matchPools returns the CIDRs of the enabled pools of the IP version whose names match the given wildcard, or
+// whose labels match the given selector.  It returns an error if the pattern is neither.
+func matchPools(ipPools []apiv3.IPPool, pattern string, isv4 bool) ([]*net.IPNet, error) {
+	matches := func(ipp apiv3.IPPool) (bool, error) {
+		return path.Match(pattern, ipp.Name)
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		sel, err := selector.Parse(pattern)
+		if err != nil {
+			return nil, err
 		}
-		if !isv4 && ip.To4() != nil {
-			return nil, fmt.Errorf("%q isn't a IPv6 address", ip)
+		matches = func(ipp apiv3.IPPool) (bool, error) {
+			return sel.Evaluate(ipp.Labels), nil
 		}
-		result = append(result, cnet.IPNet{IPNet: *cidr})
 	}
-	return result, nil
+
+	var cidrs []*net.IPNet
+	for _, ipp := range ipPools {
+		if ipp.Spec.Disabled {
+			continue
+		}
+		if ok, err := matches(ipp); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(ipp.Spec.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IP pool cidr: %s", err)
+		}
+		if (cidr.IP.To4() != nil) != isv4 {
+			continue
+		}
+		logrus.Infof("Resolved pool %q to pool %s with cidr %s", pattern, ipp.Name, cidr)
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
 }
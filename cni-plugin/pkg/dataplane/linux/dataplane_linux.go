@@ -30,24 +30,28 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
 
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
 	"github.com/projectcalico/calico/cni-plugin/pkg/types"
 	api "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	calicoclient "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 )
 
 type linuxDataplane struct {
-	allowIPForwarding bool
-	mtu               int
-	queues            int
-	logger            *logrus.Entry
+	allowIPForwarding      bool
+	mtu                    int
+	queues                 int
+	defaultInterfaceDriver string
+	logger                 *logrus.Entry
 }
 
 func NewLinuxDataplane(conf types.NetConf, logger *logrus.Entry) *linuxDataplane {
+	defaultInterfaceDriver, _ := conf.DataplaneOptions["interface_driver"].(string)
 	return &linuxDataplane{
-		allowIPForwarding: conf.ContainerSettings.AllowIPForwarding,
-		mtu:               conf.MTU,
-		queues:            conf.NumQueues,
-		logger:            logger,
+		allowIPForwarding:      conf.ContainerSettings.AllowIPForwarding,
+		mtu:                    conf.MTU,
+		queues:                 conf.NumQueues,
+		defaultInterfaceDriver: defaultInterfaceDriver,
+		logger:                 logger,
 	}
 }
 
@@ -61,6 +65,14 @@ func (d *linuxDataplane) DoNetworking(
 	endpoint *api.WorkloadEndpoint,
 	annotations map[string]string,
 ) (hostVethName, contVethMAC string, err error) {
+	driver, err := d.interfaceDriver(annotations)
+	if err != nil {
+		return "", "", err
+	}
+	if driver == interfaceDriverTap {
+		return d.doTapNetworking(args, result, desiredVethName, annotations)
+	}
+
 	hostVethName = desiredVethName
 	contVethName := args.IfName
 	var hasIPv4, hasIPv6 bool
@@ -168,10 +180,20 @@ func (d *linuxDataplane) DoNetworking(
 		}
 
 		// Check if there is an annotation requesting a specific fixed MAC address for the container Veth, otherwise
-		// use kernel-assigned MAC.
+		// use kernel-assigned MAC.  The special value "fromIP" derives a stable MAC from the pod's IP, preferring
+		// its IPv4 address.
 		if requestedContVethMac, found := annotations["cni.projectcalico.org/hwAddr"]; found {
-			tmpContVethMAC, err := net.ParseMAC(requestedContVethMac)
-			if err != nil {
+			var tmpContVethMAC net.HardwareAddr
+			if requestedContVethMac == "fromIP" {
+				macIP := result.IPs[0].Address.IP
+				for _, addr := range result.IPs {
+					if addr.Address.IP.To4() != nil {
+						macIP = addr.Address.IP
+						break
+					}
+				}
+				tmpContVethMAC = utils.MACFromIP(macIP)
+			} else if tmpContVethMAC, err = net.ParseMAC(requestedContVethMac); err != nil {
 				return fmt.Errorf("failed to parse MAC address %v provided via cni.projectcalico.org/hwAddr: %v",
 					requestedContVethMac, err)
 			}
@@ -210,11 +232,7 @@ func (d *linuxDataplane) DoNetworking(
 				return fmt.Errorf("failed to add route inside the container: %v", err)
 			}
 
-			for _, r := range routes {
-				if r.IP.To4() == nil {
-					d.logger.WithField("route", r).Debug("Skipping non-IPv4 route")
-					continue
-				}
+			for _, r := range utils.RoutesForIPFamily(routes, true) {
 				d.logger.WithField("route", r).Debug("Adding IPv4 route")
 				if err = ip.AddRoute(r, gw, contVeth); err != nil {
 					return fmt.Errorf("failed to add IPv4 route for %v via %v: %v", r, gw, err)
@@ -271,11 +289,7 @@ func (d *linuxDataplane) DoNetworking(
 
 			hostIPv6Addr := addresses[0].IP
 
-			for _, r := range routes {
-				if r.IP.To4() != nil {
-					d.logger.WithField("route", r).Debug("Skipping non-IPv6 route")
-					continue
-				}
+			for _, r := range utils.RoutesForIPFamily(routes, false) {
 				d.logger.WithField("route", r).Debug("Adding IPv6 route")
 				if err = ip.AddRoute(r, hostIPv6Addr, contVeth); err != nil {
 					return fmt.Errorf("failed to add IPv6 route for %v via %v: %v", r, hostIPv6Addr, err)
@@ -516,6 +530,11 @@ func writeProcSys(path, value string) error {
 }
 
 func (d *linuxDataplane) CleanUpNamespace(args *skel.CmdArgs) error {
+	// Workloads with the tap interface driver have their interface in the host namespace.
+	if err := d.cleanUpTaps(args.ContainerID); err != nil {
+		return err
+	}
+
 	// Only try to delete the device if a namespace was passed in.
 	logCtx := d.logger.WithFields(logrus.Fields{
 		"netns": args.Netns,
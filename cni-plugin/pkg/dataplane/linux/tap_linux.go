@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linux
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/containernetworking/cni/pkg/skel"
+	cniv1 "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/vishvananda/netlink"
+
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
+)
+
+// Interface drivers, which create the workload's interface.  A pod can select one with the
+// cni.projectcalico.org/interfaceDriver annotation, and the default is the interface_driver
+// dataplane option.
+const (
+	// interfaceDriverVeth creates a veth pair between the pod's network namespace and the host.
+	interfaceDriverVeth = "veth"
+	// interfaceDriverTap creates a tap interface in the host namespace for a userspace dataplane,
+	// such as a DPDK application or a VM, to attach to.  Felix polices the tap like a veth.
+	interfaceDriverTap = "tap"
+	// interfaceDriverVhostUser needs a userspace switch such as VPP to serve the vhost-user
+	// socket, so it is only supported by the grpc dataplane.
+	interfaceDriverVhostUser = "vhost-user"
+)
+
+// tapAliasPrefix prefixes the alias of the tap interfaces, which records the ID of the container
+// so that CleanUpNamespace can find the tap.
+const tapAliasPrefix = "calico-cni:"
+
+// interfaceDriver returns the interface driver of a workload.
+func (d *linuxDataplane) interfaceDriver(annotations map[string]string) (string, error) {
+	driver := d.defaultInterfaceDriver
+	if a, ok := annotations["cni.projectcalico.org/interfaceDriver"]; ok {
+		driver = a
+	}
+	switch driver {
+	case "", interfaceDriverVeth:
+		return interfaceDriverVeth, nil
+	case interfaceDriverTap:
+		return interfaceDriverTap, nil
+	case interfaceDriverVhostUser:
+		return "", fmt.Errorf("interface driver %s needs a userspace dataplane, set dataplane_options to use the grpc dataplane", driver)
+	default:
+		return "", fmt.Errorf("unknown interface driver %q", driver)
+	}
+}
+
+// doTapNetworking creates a tap interface in the host namespace for a workload and routes the
+// workload's IPs to it.  The workload's userspace dataplane configures the IPs itself, and uses the
+// returned MAC.
+func (d *linuxDataplane) doTapNetworking(
+	args *skel.CmdArgs,
+	result *cniv1.Result,
+	tapName string,
+	annotations map[string]string,
+) (string, string, error) {
+	hostNlHandle, err := netlink.NewHandle(syscall.NETLINK_ROUTE)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create host netlink handle: %v", err)
+	}
+	defer hostNlHandle.Close()
+
+	if oldLink, err := hostNlHandle.LinkByName(tapName); err == nil {
+		if err = hostNlHandle.LinkDel(oldLink); err != nil {
+			return "", "", fmt.Errorf("failed to delete old interface %v: %v", tapName, err)
+		}
+		d.logger.Infof("Cleaning old interface: %v", tapName)
+	}
+
+	la := netlink.NewLinkAttrs()
+	la.Name = tapName
+	la.MTU = d.mtu
+	tap := &netlink.Tuntap{
+		LinkAttrs: la,
+		Mode:      netlink.TUNTAP_MODE_TAP,
+		Flags:     netlink.TUNTAP_NO_PI | netlink.TUNTAP_VNET_HDR,
+	}
+	if d.queues > 1 {
+		tap.Flags |= netlink.TUNTAP_MULTI_QUEUE
+	}
+	if err := hostNlHandle.LinkAdd(tap); err != nil {
+		return "", "", fmt.Errorf("failed to add tap %s: %v", tapName, err)
+	}
+	link, err := hostNlHandle.LinkByName(tapName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to lookup %q: %v", tapName, err)
+	}
+	if err := hostNlHandle.LinkSetAlias(link, tapAliasPrefix+args.ContainerID); err != nil {
+		return "", "", fmt.Errorf("failed to set alias of %q: %v", tapName, err)
+	}
+
+	var hasIPv4, hasIPv6 bool
+	for _, addr := range result.IPs {
+		if addr.Address.IP.To4() != nil {
+			hasIPv4 = true
+			addr.Address.Mask = net.CIDRMask(32, 32)
+		} else {
+			hasIPv6 = true
+			addr.Address.Mask = net.CIDRMask(128, 128)
+		}
+	}
+	if err := d.configureSysctls(tapName, hasIPv4, hasIPv6); err != nil {
+		return "", "", fmt.Errorf("error configuring sysctls for interface: %s, error: %w", tapName, err)
+	}
+	if err := hostNlHandle.LinkSetUp(link); err != nil {
+		return "", "", fmt.Errorf("failed to set %q up: %w", tapName, err)
+	}
+	if err := SetupRoutes(hostNlHandle, link, result); err != nil {
+		return "", "", fmt.Errorf("error adding host side routes for interface: %s, error: %s", tapName, err)
+	}
+
+	// The workload's MAC is the one requested via the hwAddr annotation, or else one derived from
+	// its IP, so that the workload can be configured with it in advance.
+	mac := utils.MACFromIP(result.IPs[0].Address.IP)
+	if requested, ok := annotations["cni.projectcalico.org/hwAddr"]; ok && requested != "fromIP" {
+		if mac, err = net.ParseMAC(requested); err != nil {
+			return "", "", fmt.Errorf("failed to parse MAC address %v provided via cni.projectcalico.org/hwAddr: %v",
+				requested, err)
+		}
+	}
+	d.logger.WithFields(map[string]interface{}{"tap": tapName, "MAC": mac}).Info("Created tap for workload")
+	return tapName, mac.String(), nil
+}
+
+// cleanUpTaps deletes the tap interfaces of a container.
+func (d *linuxDataplane) cleanUpTaps(containerID string) error {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return fmt.Errorf("failed to list interfaces: %v", err)
+	}
+	for _, link := range links {
+		if _, ok := link.(*netlink.Tuntap); !ok || link.Attrs().Alias != tapAliasPrefix+containerID {
+			continue
+		}
+		d.logger.WithField("tap", link.Attrs().Name).Info("Deleting workload's tap")
+		if err := netlink.LinkDel(link); err != nil && !strings.Contains(err.Error(), "no such device") {
+			return fmt.Errorf("failed to delete tap %s: %v", link.Attrs().Name, err)
+		}
+	}
+	return nil
+}
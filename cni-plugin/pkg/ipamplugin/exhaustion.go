@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipamplugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/metrics"
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
+	"github.com/projectcalico/calico/cni-plugin/pkg/k8s"
+	"github.com/projectcalico/calico/cni-plugin/pkg/types"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+)
+
+const (
+	eventReasonPoolExhausted = "IPPoolExhausted"
+	eventSource              = "calico-cni"
+)
+
+// reportPoolExhaustion reports an allocation that failed because the IP pools of one IP version
+// were exhausted, so that a dual-stack pod that only got the addresses of one IP version does not
+// fail silently.  It counts the failure in the metrics file and, for Kubernetes pods, emits Warning
+// events on the pod and on the exhausted pools.  Reporting is best effort, so errors are only
+// logged.
+func reportPoolExhaustion(ctx context.Context, conf types.NetConf, nodename string, epIDs *utils.WEPIdentifiers, ia *ipam.IPAMAssignments, cause error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ipVersion": ia.IPVersion,
+		"pools":     ia.ExhaustedPools,
+		"node":      nodename,
+	})
+	logger.WithError(cause).Warn("IP pools exhausted")
+	metrics.RecordPoolExhaustion(conf.AddMetricsFile, ia.IPVersion)
+
+	if epIDs.Orchestrator != v3.OrchestratorKubernetes || epIDs.Pod == "" {
+		return
+	}
+	clientset, err := k8s.NewK8sClient(conf, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to create Kubernetes client, not emitting IP pool exhaustion events")
+		return
+	}
+
+	message := fmt.Sprintf("Failed to assign an IPv%d address on node %s: %v", ia.IPVersion, nodename, cause)
+	if len(ia.ExhaustedPools) > 0 {
+		message += fmt.Sprintf("; exhausted IP pools: %s", strings.Join(ia.ExhaustedPools, ", "))
+	}
+	podRef := corev1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Namespace:  epIDs.Namespace,
+		Name:       epIDs.Pod,
+	}
+	// Events only show up against the pod if they reference the pod's UID.
+	if pod, err := clientset.CoreV1().Pods(epIDs.Namespace).Get(ctx, epIDs.Pod, metav1.GetOptions{}); err == nil {
+		podRef.UID = pod.UID
+	} else {
+		logger.WithError(err).Warn("Failed to look up pod for IP pool exhaustion event")
+	}
+	emitEvent(ctx, clientset, nodename, epIDs.Namespace, podRef, message)
+
+	for _, pool := range ia.ExhaustedPools {
+		poolRef := corev1.ObjectReference{
+			Kind:       v3.KindIPPool,
+			APIVersion: v3.GroupVersionCurrent,
+			Name:       pool,
+		}
+		// Events about cluster-scoped objects live in the default namespace.
+		emitEvent(ctx, clientset, nodename, metav1.NamespaceDefault, poolRef, fmt.Sprintf(
+			"No IPv%d addresses available to assign to pod %s/%s on node %s",
+			ia.IPVersion, epIDs.Namespace, epIDs.Pod, nodename))
+	}
+}
+
+func emitEvent(ctx context.Context, clientset kubernetes.Interface, nodename, namespace string, ref corev1.ObjectReference, message string) {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ref.Name + ".",
+			Namespace:    namespace,
+		},
+		InvolvedObject: ref,
+		Reason:         eventReasonPoolExhausted,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Source: corev1.EventSource{
+			Component: eventSource,
+			Host:      nodename,
+		},
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		ReportingController: eventSource,
+		ReportingInstance:   eventSource + "-" + nodename,
+	}
+	if _, err := clientset.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		logrus.WithError(err).WithField("object", ref.Name).Warn("Failed to emit IP pool exhaustion event")
+	}
+}
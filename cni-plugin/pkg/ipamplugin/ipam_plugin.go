@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/containernetworking/cni/pkg/skel"
@@ -35,15 +36,18 @@ import (
 
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/cache"
 	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
 	"github.com/projectcalico/calico/cni-plugin/pkg/types"
 	"github.com/projectcalico/calico/cni-plugin/pkg/upgrade"
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	k8sconversion "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
 	"github.com/projectcalico/calico/libcalico-go/lib/logutils"
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
 )
 
 func Main(version string) {
@@ -120,6 +124,11 @@ func Main(version string) {
 type ipamArgs struct {
 	cnitypes.CommonArgs
 	IP net.IP `json:"ip,omitempty"`
+
+	// CALICO_STICKY_IP requests that the pod's IPs are kept across restarts of the pod.  They are
+	// allocated with a handle of the pod rather than of the container, which the DEL of the
+	// container does not release, and are re-used by the next ADD for the pod.
+	CALICO_STICKY_IP cnitypes.UnmarshallableBool
 }
 
 func cmdAdd(args *skel.CmdArgs) error {
@@ -174,10 +183,36 @@ func cmdAdd(args *skel.CmdArgs) error {
 	ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
 	defer cancel()
 
+	var stickyIPs []cnet.IP
+	if ipamArgs.CALICO_STICKY_IP {
+		if epIDs.Pod == "" {
+			return fmt.Errorf("sticky IPs are only supported for Kubernetes pods")
+		}
+		handleID = utils.GetStickyHandleID(conf.Name, epIDs.Namespace, epIDs.Pod)
+		logger = logger.WithField("HandleID", handleID)
+		attrs[ipam.AttributeSticky] = "true"
+
+		stickyIPs, err = calicoClient.IPAM().IPsByHandle(ctx, handleID)
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); err != nil && !ok {
+			return err
+		}
+		logger.WithField("stickyIPs", stickyIPs).Info("Looked up the pod's sticky IPs")
+	}
+
 	r := &cniv1.Result{}
 	if ipamArgs.IP != nil {
 		logger.Infof("Calico CNI IPAM request IP: %v", ipamArgs.IP)
 
+		// A sticky IP which the pod already holds is re-used as it is.  Any other sticky IP of
+		// the same IP version is released in favour of the requested IP.
+		held, others := splitStickyIPs(stickyIPs, ipamArgs.IP)
+		if len(others) > 0 {
+			logger.WithField("stickyIPs", others).Info("Releasing sticky IPs which are replaced by the requested IP")
+			if err := releaseStickyIPs(ctx, calicoClient, handleID, others); err != nil {
+				return err
+			}
+		}
+
 		assignArgs := ipam.AssignIPArgs{
 			IP:       cnet.IP{IP: ipamArgs.IP},
 			HandleID: &handleID,
@@ -190,8 +225,9 @@ func cmdAdd(args *skel.CmdArgs) error {
 			defer unlock()
 			return calicoClient.IPAM().AssignIP(ctx, assignArgs)
 		}
-		err := assignIPWithLock()
-		if err != nil {
+		if held {
+			logger.Info("Re-using the requested IP, which the pod already holds")
+		} else if err := assignIPWithLock(); err != nil {
 			return err
 		}
 
@@ -229,12 +265,24 @@ func cmdAdd(args *skel.CmdArgs) error {
 
 		logger.Infof("Calico CNI IPAM request count IPv4=%d IPv6=%d", num4, num6)
 
-		v4pools, err := utils.ResolvePools(ctx, calicoClient, conf.IPAM.IPv4Pools, true)
+		if len(stickyIPs) > 0 {
+			if sr := stickyIPsResult(stickyIPs, num4, num6); sr != nil {
+				logger.WithField("result.IPs", sr.IPs).Info("Re-using the pod's sticky IPs")
+				return cnitypes.PrintResult(sr, conf.CNIVersion)
+			}
+			logger.WithField("stickyIPs", stickyIPs).Info("Sticky IPs do not match the requested IP versions, releasing them")
+			if err := releaseStickyIPs(ctx, calicoClient, handleID, stickyIPs); err != nil {
+				return err
+			}
+		}
+
+		poolCache := cache.New(conf)
+		v4pools, err := utils.ResolvePools(ctx, calicoClient, poolCache, conf.IPAM.IPv4Pools, true)
 		if err != nil {
 			return err
 		}
 
-		v6pools, err := utils.ResolvePools(ctx, calicoClient, conf.IPAM.IPv6Pools, false)
+		v6pools, err := utils.ResolvePools(ctx, calicoClient, poolCache, conf.IPAM.IPv6Pools, false)
 		if err != nil {
 			return err
 		}
@@ -258,6 +306,13 @@ func cmdAdd(args *skel.CmdArgs) error {
 			Attrs:            attrs,
 			IntendedUse:      v3.IPPoolAllowedUseWorkload,
 		}
+		if epIDs.Pod != "" && (len(v4pools) == 0 || len(v6pools) == 0) {
+			// Let the pools with a namespace selector act as the default pools of the pod's namespace.
+			assignArgs.NamespaceLabels, err = namespaceLabels(ctx, calicoClient, epIDs.Namespace)
+			if err != nil {
+				return err
+			}
+		}
 		if runtime.GOOS == "windows" {
 			rsvdAttrWindows := &ipam.HostReservedAttr{
 				StartOfBlock: 3,
@@ -324,6 +379,7 @@ func cmdAdd(args *skel.CmdArgs) error {
 
 		if num4 == 1 {
 			if err := v4Assignments.PartialFulfillmentError(); err != nil {
+				reportPoolExhaustion(ctx, conf, nodename, epIDs, v4Assignments, err)
 				return fmt.Errorf("failed to request IPv4 addresses: %w", err)
 			}
 			ipV4Network := net.IPNet{IP: v4Assignments.IPs[0].IP, Mask: v4Assignments.IPs[0].Mask}
@@ -334,6 +390,7 @@ func cmdAdd(args *skel.CmdArgs) error {
 
 		if num6 == 1 {
 			if err := v6Assignments.PartialFulfillmentError(); err != nil {
+				reportPoolExhaustion(ctx, conf, nodename, epIDs, v6Assignments, err)
 				return fmt.Errorf("failed to request IPv6 addresses: %w", err)
 			}
 			ipV6Network := net.IPNet{IP: v6Assignments.IPs[0].IP, Mask: v6Assignments.IPs[0].Mask}
@@ -349,6 +406,69 @@ func cmdAdd(args *skel.CmdArgs) error {
 	return cnitypes.PrintResult(r, conf.CNIVersion)
 }
 
+// splitStickyIPs returns whether the pod already holds the requested IP as a sticky IP, and the
+// other sticky IPs of the same IP version, which the requested IP replaces.
+func splitStickyIPs(stickyIPs []cnet.IP, requested net.IP) (bool, []cnet.IP) {
+	held := false
+	var others []cnet.IP
+	for _, ip := range stickyIPs {
+		if ip.IP.Equal(requested) {
+			held = true
+		} else if (ip.To4() == nil) == (requested.To4() == nil) {
+			others = append(others, ip)
+		}
+	}
+	return held, others
+}
+
+// stickyIPsResult returns a result with the pod's sticky IPs if they are exactly the requested
+// number of IPs of each version, or nil if they are not.
+func stickyIPsResult(stickyIPs []cnet.IP, num4, num6 int) *cniv1.Result {
+	r := &cniv1.Result{}
+	var n4, n6 int
+	for _, ip := range stickyIPs {
+		if ip.To4() != nil {
+			n4++
+			r.IPs = append(r.IPs, &cniv1.IPConfig{Address: net.IPNet{IP: ip.IP, Mask: net.CIDRMask(32, 32)}})
+		} else {
+			n6++
+			r.IPs = append(r.IPs, &cniv1.IPConfig{Address: net.IPNet{IP: ip.IP, Mask: net.CIDRMask(128, 128)}})
+		}
+	}
+	if n4 != num4 || n6 != num6 {
+		return nil
+	}
+	return r
+}
+
+func releaseStickyIPs(ctx context.Context, calicoClient client.Interface, handleID string, ips []cnet.IP) error {
+	opts := []ipam.ReleaseOptions{}
+	for _, ip := range ips {
+		opts = append(opts, ipam.ReleaseOptions{Address: ip.String(), Handle: handleID})
+	}
+	_, err := calicoClient.IPAM().ReleaseIPs(ctx, opts...)
+	return err
+}
+
+// namespaceLabels returns the labels of a Kubernetes namespace, as the IP pool namespace selectors see
+// them, from the profile of the namespace.
+func namespaceLabels(ctx context.Context, calicoClient client.Interface, ns string) (map[string]string, error) {
+	labels := map[string]string{k8sconversion.NameLabel: ns}
+	profile, err := calicoClient.Profiles().Get(ctx, k8sconversion.NamespaceProfileNamePrefix+ns, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return labels, nil
+		}
+		return nil, fmt.Errorf("failed to look up the labels of namespace %s: %w", ns, err)
+	}
+	for k, v := range profile.Spec.LabelsToApply {
+		if strings.HasPrefix(k, k8sconversion.NamespaceLabelPrefix) {
+			labels[strings.TrimPrefix(k, k8sconversion.NamespaceLabelPrefix)] = v
+		}
+	}
+	return labels, nil
+}
+
 type unlockFn func()
 
 // acquireIPAMLockBestEffort attempts to acquire the IPAM file lock, blocking if needed.  If an error occurs
@@ -426,6 +546,8 @@ func cmdDel(args *skel.CmdArgs) error {
 	unlock := acquireIPAMLockBestEffort(conf.IPAMLockFile)
 	defer unlock()
 
+	// Sticky IPs are allocated with the handle of the pod, not of the container, so they are kept
+	// here.  The IPAM garbage collection releases them once the pod is deleted.
 	if err := calicoClient.IPAM().ReleaseByHandle(ctx, handleID); err != nil {
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 			logger.WithError(err).Error("Failed to release address")
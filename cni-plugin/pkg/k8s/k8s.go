@@ -22,6 +22,7 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/chained"
 	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
 	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils/cri"
 	"github.com/projectcalico/calico/cni-plugin/pkg/dataplane"
@@ -217,6 +219,8 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 
 			var v4pools, v6pools string
 
+			// The pool annotations list CIDRs, pool names, pool name wildcards or pool selectors, which
+			// the IPAM plugin resolves.
 			// Sets  the Namespace annotation for IP pools as default
 			v4pools = annotNS["cni.projectcalico.org/ipv4pools"]
 			v6pools = annotNS["cni.projectcalico.org/ipv6pools"]
@@ -231,7 +235,14 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 				v6pools = v6poolpod
 			}
 
-			if len(v4pools) != 0 || len(v6pools) != 0 {
+			// The ipFamilies annotation overrides the IP families that Calico IPAM assigns, for example
+			// to run IPv6-only pods on dual-stack nodes.  The pod annotation overrides the namespace one.
+			ipFamilies := annotNS["cni.projectcalico.org/ipFamilies"]
+			if ipFamiliesPod := annot["cni.projectcalico.org/ipFamilies"]; len(ipFamiliesPod) != 0 {
+				ipFamilies = ipFamiliesPod
+			}
+
+			if len(v4pools) != 0 || len(v6pools) != 0 || len(ipFamilies) != 0 {
 				var stdinData map[string]interface{}
 				if err := json.Unmarshal(args.StdinData, &stdinData); err != nil {
 					return nil, err
@@ -262,6 +273,20 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 					stdinData["ipam"].(map[string]interface{})["ipv6_pools"] = v6PoolSlice
 					logger.WithField("ipv6_pools", v6pools).Debug("Setting IPv6 Pools")
 				}
+				if len(ipFamilies) > 0 {
+					assignIPv4, assignIPv6, err := parseIPFamilies(ipFamilies)
+					if err != nil {
+						logger.WithField("ipFamilies", ipFamilies).Error("Error parsing IP families")
+						return nil, err
+					}
+
+					if _, ok := stdinData["ipam"].(map[string]interface{}); !ok {
+						return nil, errors.New("data on stdin was of unexpected type")
+					}
+					stdinData["ipam"].(map[string]interface{})["assign_ipv4"] = strconv.FormatBool(assignIPv4)
+					stdinData["ipam"].(map[string]interface{})["assign_ipv6"] = strconv.FormatBool(assignIPv6)
+					logger.WithField("ipFamilies", ipFamilies).Debug("Setting IP families")
+				}
 
 				newData, err := json.Marshal(stdinData)
 				if err != nil {
@@ -277,8 +302,48 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 	ipAddrsNoIpam := annot["cni.projectcalico.org/ipAddrsNoIpam"]
 	ipAddrs := annot["cni.projectcalico.org/ipAddrs"]
 
+	// The stickyIP annotation asks Calico IPAM to keep the pod's IPs across restarts of the pod.
+	// It is passed to the IPAM plugin in CNI_ARGS, so it applies to the IPs of ipAddrs too.
+	if stickyIP := annot["cni.projectcalico.org/stickyIP"]; stickyIP != "" {
+		sticky, err := strconv.ParseBool(stickyIP)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for annotation 'stickyIP': %w", stickyIP, err)
+		}
+		if sticky {
+			if conf.IPAM.Type != "calico-ipam" {
+				e := fmt.Errorf("stickyIP is not compatible with configured IPAM: %s", conf.IPAM.Type)
+				logger.Error(e)
+				return nil, e
+			}
+			if ipAddrsNoIpam != "" {
+				e := fmt.Errorf("can't have both annotations: 'stickyIP' and 'ipAddrsNoIpam' in use at the same time")
+				logger.Error(e)
+				return nil, e
+			}
+			restore, err := addCNIArg("CALICO_STICKY_IP=true", logger)
+			if err != nil {
+				return nil, err
+			}
+			defer restore()
+		}
+	}
+
 	// Switch based on which annotations are passed or not passed.
 	switch {
+	case chained.Enabled(conf) && (ipAddrs != "" || ipAddrsNoIpam != ""):
+		// The upstream plugin owns IPAM in chained mode, so the pod's IPs can't be chosen.
+		e := fmt.Errorf("annotations 'ipAddrs' and 'ipAddrsNoIpam' are not supported in chained mode")
+		logger.Error(e)
+		return nil, e
+
+	case chained.Enabled(conf):
+		// Use the IPs that the upstream plugin assigned.
+		result, err = chained.PrevResult(conf)
+		if err != nil {
+			return nil, err
+		}
+		logger.WithField("prevResult", result).Debug("Using result of upstream plugin")
+
 	case ipAddrs == "" && ipAddrsNoIpam == "":
 		// Call the IPAM plugin.
 		result, err = utils.AddIPAM(conf, args, logger)
@@ -351,6 +416,15 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 		logger.Debugf("IPAM result set to: %+v", result)
 	}
 
+	// Record the IPs that host-local assigned in Calico IPAM, so that their blocks are affine to
+	// this node.
+	if conf.IPAM.Type == "host-local" && conf.HostLocalIPAMBlocks {
+		if err = recordHostLocalIPs(ctx, calicoClient, conf, args, epIDs, result, logger); err != nil {
+			utils.ReleaseIPAllocation(logger, conf, args)
+			return nil, err
+		}
+	}
+
 	// Configure the endpoint (creating if required).
 	if endpoint == nil {
 		logger.Debug("Initializing new WorkloadEndpoint resource")
@@ -377,25 +451,40 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 		endpoint.Spec.Profiles = []string{conf.Name}
 	}
 
+	// releaseIPAM cleans up any IPAM allocations on failure.
+	releaseIPAM := func() {
+		if chained.Enabled(conf) {
+			// The upstream plugin owns the pod's IPs.
+			return
+		}
+		logger.WithField("endpointIPs", endpoint.Spec.IPNetworks).Info("Releasing IPAM allocation(s) after failure")
+		utils.ReleaseIPAllocation(logger, conf, args)
+		if conf.IPAM.Type == "host-local" && conf.HostLocalIPAMBlocks {
+			if err := releaseHostLocalIPs(ctx, calicoClient, conf, args, epIDs, logger); err != nil {
+				logger.WithError(err).Warn("Failed to release host-local IPs from Calico IPAM")
+			}
+		}
+	}
+
 	// Populate the endpoint with the output from the IPAM plugin.
 	if err = utils.PopulateEndpointNets(endpoint, result); err != nil {
 		// Cleanup IP allocation and return the error.
-		utils.ReleaseIPAllocation(logger, conf, args)
+		releaseIPAM()
 		return nil, err
 	}
 	logger.WithField("endpoint", endpoint).Info("Populated endpoint")
 	logger.Infof("Calico CNI using IPs: %s", endpoint.Spec.IPNetworks)
 
-	// releaseIPAM cleans up any IPAM allocations on failure.
-	releaseIPAM := func() {
-		logger.WithField("endpointIPs", endpoint.Spec.IPNetworks).Info("Releasing IPAM allocation(s) after failure")
-		utils.ReleaseIPAllocation(logger, conf, args)
+	var hostVethName, contVethMac string
+	if chained.Enabled(conf) {
+		// The upstream plugin has set up the pod's interfaces, so only check them.
+		hostVethName, contVethMac, err = chained.SetUpEndpoint(args, conf, result, logger)
+	} else {
+		// Whether the endpoint existed or not, the veth needs (re)creating.
+		desiredVethName := k8sconversion.NewConverter().VethNameForWorkloadInterface(epIDs.Namespace, epIDs.Pod, epIDs.Endpoint)
+		hostVethName, contVethMac, err = d.DoNetworking(
+			ctx, calicoClient, args, result, desiredVethName, routes, endpoint, annot)
 	}
-
-	// Whether the endpoint existed or not, the veth needs (re)creating.
-	desiredVethName := k8sconversion.NewConverter().VethNameForWorkload(epIDs.Namespace, epIDs.Pod)
-	hostVethName, contVethMac, err := d.DoNetworking(
-		ctx, calicoClient, args, result, desiredVethName, routes, endpoint, annot)
 	if err != nil {
 		logger.WithError(err).Error("Error setting up networking")
 		releaseIPAM()
@@ -499,10 +588,13 @@ func CmdAddK8s(ctx context.Context, args *skel.CmdArgs, conf types.NetConf, epID
 	}
 	logger.Info("Wrote updated endpoint to datastore")
 
-	// Add the interface created above to the CNI result.
-	result.Interfaces = append(result.Interfaces, &cniv1.Interface{
-		Name: endpoint.Spec.InterfaceName},
-	)
+	// Add the interface created above to the CNI result.  In chained mode, the result of the
+	// upstream plugin is passed on unchanged.
+	if !chained.Enabled(conf) {
+		result.Interfaces = append(result.Interfaces, &cniv1.Interface{
+			Name: endpoint.Spec.InterfaceName},
+		)
+	}
 
 	// Conditionally wait for host-local Felix to program the policy for this WEP.
 	// Error if negative, ignore if 0.
@@ -604,6 +696,12 @@ func CmdDelK8s(ctx context.Context, c calicoclient.Interface, epIDs utils.WEPIde
 		break
 	}
 
+	if chained.Enabled(conf) {
+		// The upstream plugin owns the pod's interfaces and IPs, and cleans them up.
+		logger.Info("Teardown processing complete.")
+		return nil
+	}
+
 	// Clean up namespace by removing the interfaces.
 	logger.Info("Cleaning up netns")
 	err = d.CleanUpNamespace(args)
@@ -617,11 +715,60 @@ func CmdDelK8s(ctx context.Context, c calicoclient.Interface, epIDs utils.WEPIde
 	if err != nil {
 		return err
 	}
+	if conf.IPAM.Type == "host-local" && conf.HostLocalIPAMBlocks {
+		if err = releaseHostLocalIPs(ctx, c, conf, args, epIDs, logger); err != nil {
+			return err
+		}
+	}
 
 	logger.Info("Teardown processing complete.")
 	return nil
 }
 
+// recordHostLocalIPs records the IPs that host-local IPAM assigned to a pod in Calico IPAM, under the
+// same handle that Calico IPAM would use.  This claims the blocks of the IPs for the node if they
+// aren't claimed yet, which gets them advertised over BGP, and stops Calico IPAM from handing out
+// the IPs, for example while a cluster migrates from kubenet to Calico IPAM.
+func recordHostLocalIPs(ctx context.Context, calicoClient calicoclient.Interface, conf types.NetConf, args *skel.CmdArgs, epIDs utils.WEPIdentifiers, result *cniv1.Result, logger *logrus.Entry) error {
+	handleID := utils.GetHandleID(conf.Name, args.ContainerID, epIDs.WEPName)
+	attrs := map[string]string{
+		libipam.AttributeNode:      epIDs.Node,
+		libipam.AttributePod:       epIDs.Pod,
+		libipam.AttributeNamespace: epIDs.Namespace,
+		libipam.AttributeTimestamp: time.Now().UTC().String(),
+	}
+	for _, ip := range result.IPs {
+		logger.WithField("IP", ip.Address.IP).Info("Recording host-local IP in Calico IPAM")
+		err := calicoClient.IPAM().AssignIP(ctx, libipam.AssignIPArgs{
+			IP:       cnet.IP{IP: ip.Address.IP},
+			HandleID: &handleID,
+			Attrs:    attrs,
+			Hostname: epIDs.Node,
+		})
+		if err != nil {
+			if err := calicoClient.IPAM().ReleaseByHandle(ctx, handleID); err != nil {
+				logger.WithError(err).Warn("Failed to release host-local IPs from Calico IPAM")
+			}
+			return fmt.Errorf("failed to record host-local IP %s in Calico IPAM: %v", ip.Address.IP, err)
+		}
+	}
+	return nil
+}
+
+// releaseHostLocalIPs releases the IPs that recordHostLocalIPs recorded in Calico IPAM.
+func releaseHostLocalIPs(ctx context.Context, calicoClient calicoclient.Interface, conf types.NetConf, args *skel.CmdArgs, epIDs utils.WEPIdentifiers, logger *logrus.Entry) error {
+	handleID := utils.GetHandleID(conf.Name, args.ContainerID, epIDs.WEPName)
+	logger.WithField("HandleID", handleID).Info("Releasing host-local IPs from Calico IPAM")
+	if err := calicoClient.IPAM().ReleaseByHandle(ctx, handleID); err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			logger.WithField("HandleID", handleID).Info("No host-local IPs recorded in Calico IPAM")
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
 // releaseIPAddrs calls directly into Calico IPAM to release the specified IP addresses.
 // NOTE: This function assumes Calico IPAM is in use, and calls into it directly rather than calling the IPAM plugin.
 func releaseIPAddrs(ipAddrs []string, calico calicoclient.Interface, logger *logrus.Entry) error {
@@ -685,6 +832,37 @@ func ipAddrsResult(ipAddrs string, conf types.NetConf, args *skel.CmdArgs, logge
 	return &result, nil
 }
 
+// addCNIArg adds an argument to the CNI_ARGS ENV var, for the calls of the IPAM plugin, and
+// returns a function that restores its original value.
+func addCNIArg(arg string, logger *logrus.Entry) (func(), error) {
+	originalArgs := os.Getenv("CNI_ARGS")
+	if err := os.Setenv("CNI_ARGS", originalArgs+";"+arg); err != nil {
+		return nil, fmt.Errorf("error setting CNI_ARGS environment variable: %v", err)
+	}
+	logger.Debugf("Added %s to CNI_ARGS", arg)
+	return func() {
+		if err := os.Setenv("CNI_ARGS", originalArgs); err != nil {
+			logger.Errorf("Error setting CNI_ARGS environment variable: %v", err)
+		}
+	}, nil
+}
+
+// parseIPFamilies parses the value of the ipFamilies annotation, a comma-separated list of "IPv4"
+// and "IPv6", into whether to assign IPv4 and IPv6 addresses.
+func parseIPFamilies(value string) (ipv4, ipv6 bool, err error) {
+	for _, family := range strings.Split(value, ",") {
+		switch strings.TrimSpace(family) {
+		case "IPv4":
+			ipv4 = true
+		case "IPv6":
+			ipv6 = true
+		default:
+			return false, false, fmt.Errorf("invalid IP family %q in annotation 'ipFamilies', expecting IPv4 or IPv6", family)
+		}
+	}
+	return ipv4, ipv6, nil
+}
+
 // callIPAMWithIP sets CNI_ARGS with the IP and calls the IPAM plugin with it
 // to get current.Result and then it unsets the IP field from CNI_ARGS ENV var,
 // so it doesn't pollute the subsequent requests.
@@ -42,6 +42,9 @@ import (
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/cache"
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/chained"
+	"github.com/projectcalico/calico/cni-plugin/internal/pkg/metrics"
 	"github.com/projectcalico/calico/cni-plugin/internal/pkg/utils"
 	"github.com/projectcalico/calico/cni-plugin/pkg/dataplane"
 	"github.com/projectcalico/calico/cni-plugin/pkg/k8s"
@@ -146,6 +149,13 @@ func pollEndpointReadiness(endpoint string, interval, timeout time.Duration) err
 }
 
 func cmdAdd(args *skel.CmdArgs) (err error) {
+	// Record the latency of the ADD once the final result is known, including after a panic.
+	conf := types.NetConf{}
+	start := time.Now()
+	defer func() {
+		metrics.RecordAdd(conf.AddMetricsFile, time.Since(start), err != nil)
+	}()
+
 	// Defer a panic recover, so that in case we panic we can still return
 	// a proper error to the runtime.
 	defer func() {
@@ -164,7 +174,6 @@ func cmdAdd(args *skel.CmdArgs) (err error) {
 	}()
 
 	// Unmarshal the network config, and perform validation
-	conf := types.NetConf{}
 	if err := json.Unmarshal(args.StdinData, &conf); err != nil {
 		return fmt.Errorf("failed to load netconf: %v", err)
 	}
@@ -224,12 +233,23 @@ func cmdAdd(args *skel.CmdArgs) (err error) {
 	}
 
 	ctx := context.Background()
-	ci, err := calicoClient.ClusterInformation().Get(ctx, "default", options.GetOptions{})
-	if err != nil {
-		err = fmt.Errorf("error getting ClusterInformation: %v", err)
-		return
+	c := cache.New(conf)
+	// Only a ready datastore is cached, so that the ADD is blocked as soon as an upgrade starts
+	// once the cache entry expires.
+	var datastoreReady bool
+	if !c.Get("datastore-ready", &datastoreReady) {
+		var ci *api.ClusterInformation
+		ci, err = calicoClient.ClusterInformation().Get(ctx, "default", options.GetOptions{})
+		if err != nil {
+			err = fmt.Errorf("error getting ClusterInformation: %v", err)
+			return
+		}
+		datastoreReady = *ci.Spec.DatastoreReady
+		if datastoreReady {
+			c.Set("datastore-ready", datastoreReady)
+		}
 	}
-	if !*ci.Spec.DatastoreReady {
+	if !datastoreReady {
 		logrus.Info("Upgrade may be in progress, ready flag is not set")
 		err = fmt.Errorf("Calico is currently not ready to process requests")
 		return
@@ -353,6 +373,10 @@ func cmdAdd(args *skel.CmdArgs) (err error) {
 	} else {
 		// Default CNI behavior
 		// Validate enabled features
+		if chained.Enabled(conf) {
+			err = errors.New("chained mode is only supported for Kubernetes pods")
+			return
+		}
 		if conf.FeatureControl.IPAddrsNoIpam {
 			err = errors.New("requested feature is not supported for this runtime: ip_addrs_no_ipam")
 			return
@@ -553,8 +577,11 @@ func cmdAdd(args *skel.CmdArgs) (err error) {
 	// Set Gateway to nil. Calico IPAM doesn't set it, but host-local does.
 	// We modify IPs subnet received from the IPAM plugin (host-local),
 	// so Gateway isn't valid anymore. It is also not used anywhere by Calico.
-	for _, ip := range result.IPs {
-		ip.Gateway = nil
+	// In chained mode, the gateway belongs to the upstream plugin, so keep it.
+	if !chained.Enabled(conf) {
+		for _, ip := range result.IPs {
+			ip.Gateway = nil
+		}
 	}
 
 	// Print result to stdout, in the format defined by the requested cniVersion.
@@ -141,11 +141,55 @@ type NetConf struct {
 	// Default: /var/run/calico/endpoint-status
 	EndpointStatusDir string `json:"endpoint_status_dir,omitempty"`
 
+	// CacheTTLSeconds enables a file cache of datastore lookups that rarely change, such as the IP
+	// pools, which the invocations of the plugin on a node share to save API server round trips on
+	// pod ADD.  Entries are kept for this many seconds, and are dropped when the network
+	// configuration changes.
+	//
+	// Feature is off when set to 0.
+	//
+	// Default: 0
+	CacheTTLSeconds int `json:"cache_ttl_seconds,omitempty"`
+
+	// The directory of the cache.
+	//
+	// Default: /var/run/calico/cni-cache
+	CacheDir string `json:"cache_dir,omitempty"`
+
+	// AddMetricsFile is a file that the plugin keeps a histogram of pod ADD latencies, a count of
+	// failed ADDs and counts of IP pool exhaustion per IP version in, in the Prometheus text format,
+	// for example for the node exporter's textfile collector.  Feature is off when empty.
+	AddMetricsFile string `json:"add_metrics_file,omitempty"`
+
+	// HostLocalIPAMBlocks records the IPs that host-local IPAM assigns from the node's PodCIDR in
+	// Calico IPAM blocks affine to the node, so that they're advertised over BGP and routed as if
+	// Calico IPAM had assigned them.  It's for clusters migrating from kubenet.
+	HostLocalIPAMBlocks bool `json:"host_local_ipam_blocks,omitempty"`
+
+	// Chained runs Calico in policy-only mode after another CNI plugin in a plugin chain.
+	Chained ChainedConf `json:"chained,omitempty"`
+
+	// RawPrevResult is the result of the previous plugin in a plugin chain.
+	RawPrevResult map[string]interface{} `json:"prevResult,omitempty"`
+
 	// Options below here are deprecated.
 	EtcdAuthority string `json:"etcd_authority"`
 	Hostname      string `json:"hostname"`
 }
 
+// ChainedConf configures chained mode, in which Calico runs after a cloud CNI plugin that owns
+// IPAM and the pod's interfaces.  Calico only creates the WorkloadEndpoint, so that Felix can
+// police the pod through the host-side interface that the upstream plugin created.
+type ChainedConf struct {
+	// UpstreamPlugin is the type of the plugin ahead of Calico in the chain: aws-cni, azure-vnet,
+	// gke or ptp.  Setting it enables chained mode.
+	UpstreamPlugin string `json:"upstream_plugin"`
+
+	// InterfacePrefix overrides the prefix of the host-side interfaces that the upstream plugin
+	// creates.  It must be one of Felix's InterfacePrefix values.
+	InterfacePrefix string `json:"interface_prefix,omitempty"`
+}
+
 // Runtime Config is provided by kubernetes
 type RuntimeConfig struct {
 	DNS RuntimeConfigDNS
@@ -1133,6 +1133,65 @@ var _ = Describe("Kubernetes CNI tests", func() {
 				})
 			})
 		}
+
+		It("records the IPs in Calico IPAM blocks with host_local_ipam_blocks", func() {
+			testutils.MustCreateNewIPPool(calicoClient, "10.0.0.0/24", false, false, true)
+			netconfBlocks := fmt.Sprintf(`
+				{
+				  "cniVersion": "%s",
+				  "name": "net1",
+				  "type": "calico",
+				  "etcd_endpoints": "http://%s:2379",
+				  "datastore_type": "%s",
+				  "host_local_ipam_blocks": true,
+				  "ipam": {
+					"type": "host-local",
+					"subnet": "10.0.0.0/24"
+				  },
+				  "kubernetes": {
+					"kubeconfig": "/home/user/certs/kubeconfig"
+				  },
+				  "policy": {"type": "k8s"},
+				  "nodename_file_optional": true,
+				  "log_level":"debug",
+				  "nodename": "%s"
+				}`, cniVersion, os.Getenv("ETCD_IP"), os.Getenv("DATASTORE_TYPE"), testNodeName)
+
+			clientset := getKubernetesClient()
+			ensureNamespace(clientset, testutils.K8S_TEST_NS)
+			ensurePodCreated(clientset, testutils.K8S_TEST_NS, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: testPodName},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:  testPodName,
+						Image: "ignore",
+					}},
+					NodeName: testNodeName,
+				},
+			})
+			defer ensurePodDeleted(clientset, testutils.K8S_TEST_NS, testPodName)
+
+			containerID, _, _, contAddresses, _, contNs, err := testutils.CreateContainer(netconfBlocks, testPodName, testutils.K8S_TEST_NS, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("checking that the IP is recorded in Calico IPAM for the node")
+			handleID := utils.GetHandleID("net1", containerID, testPodName)
+			ipamIPs, err := calicoClient.IPAM().IPsByHandle(ctx, handleID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ipamIPs).To(HaveLen(1))
+			Expect(ipamIPs[0].IP.Equal(contAddresses[0].IP)).To(BeTrue())
+			attrs, _, err := calicoClient.IPAM().GetAssignmentAttributes(ctx, ipamIPs[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attrs[ipam.AttributeNode]).To(Equal(testNodeName))
+			Expect(attrs[ipam.AttributePod]).To(Equal(testPodName))
+
+			By("checking that deleting the pod releases the IP")
+			_, err = testutils.DeleteContainer(netconfBlocks, contNs.Path(), testPodName, testutils.K8S_TEST_NS)
+			Expect(err).ShouldNot(HaveOccurred())
+			ipamIPs, err = calicoClient.IPAM().IPsByHandle(ctx, handleID)
+			Expect(err).To(HaveOccurred())
+			Expect(ipamIPs).To(BeEmpty())
+		})
 	})
 
 	Context("using calico-ipam with a Namespace annotation only", func() {
@@ -3357,6 +3416,36 @@ var _ = Describe("Kubernetes CNI tests", func() {
 			Expect(podMac.String()).To(Equal(expectedMac))
 		})
 
+		It("annotation deriving the MAC address from the pod IP", func() {
+			clientset := getKubernetesClient()
+
+			ensurePodCreated(clientset, testutils.K8S_TEST_NS, &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Annotations: map[string]string{
+						"cni.projectcalico.org/hwAddr": "fromIP",
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:  name,
+						Image: "ignore",
+					}},
+					NodeName: testNodeName,
+				},
+			})
+			defer ensurePodDeleted(clientset, testutils.K8S_TEST_NS, name)
+
+			_, _, contVeth, contAddresses, _, _, err := testutils.CreateContainer(netconf, name, testutils.K8S_TEST_NS, "")
+
+			Expect(err).NotTo(HaveOccurred())
+
+			podMac := contVeth.Attrs().HardwareAddr
+			ip := contAddresses[0].IP.To4()
+
+			Expect(podMac.String()).To(Equal(fmt.Sprintf("0e:ca:%02x:%02x:%02x:%02x", ip[0], ip[1], ip[2], ip[3])))
+		})
+
 		It("annotation containing an invalid MAC address", func() {
 			clientset := getKubernetesClient()
 
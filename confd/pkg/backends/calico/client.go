@@ -177,6 +177,10 @@ func NewCalicoClient(confdConfig *config.Config) (*client, error) {
 		log.WithError(err).Warning("Failed to create secret watcher, not running under Kubernetes?")
 	}
 
+	// Create the resolver for the DNS names of BGP peers.
+	c.peerResolver = NewPeerResolver(c)
+	c.peerResolver.Start(peerHostnameRefreshInterval)
+
 	// Create a conditional that we use to wake up all of the watcher threads when there
 	// may some actionable updates.
 	c.watcherCond = sync.NewCond(&c.cacheLock)
@@ -350,6 +354,9 @@ type client struct {
 	// Subcomponent for accessing and watching secrets (that hold BGP passwords).
 	secretWatcher *secretWatcher
 
+	// Subcomponent for resolving and re-resolving the DNS names of BGP peers.
+	peerResolver *peerResolver
+
 	// Channels used to decouple update and status processing.
 	syncerC  chan interface{}
 	recheckC chan struct{}
@@ -557,69 +564,12 @@ func (c *client) updatePeersV1() {
 					peers = append(peers, c.nodeAsBGPPeers(peerNodeName, true, true, v3res)...)
 				}
 			} else {
-				// Separate port from Ip if it uses <ip>:<port> format
-				host, port := parseIPPort(v3res.Spec.PeerIP)
-				ip := cnet.ParseIP(host)
-				if ip == nil {
-					log.Error("PeerIP is not assigned or is malformed")
-					continue
-				}
-
-				// If port is not set, we use the given value to peer.
-				// If port is set, check if BGP Peer is a calico/node, and use its listenPort to peer.
-				if port == 0 {
-					// If port is empty, nodesWithIPPortAndAS() returns list of calico/node that matches IP and ASNumber.
-					nodeNames := c.nodesWithIPPortAndAS(host, v3res.Spec.ASNumber, port)
-					if len(nodeNames) != 0 {
-						if nodePort, ok := c.nodeListenPorts[nodeNames[0]]; ok {
-							port = nodePort
-						} else if c.globalListenPort != 0 {
-							port = c.globalListenPort
-						}
-					}
-				}
-
-				// Check if the peer represents a node Calico is running on.
-				_, isCalicoNode := c.nodeIPs[host]
-
-				// Check if local AS numbers are allowed.
-				var numLocalAS int32
-				if v3res.Spec.NumAllowedLocalASNumbers != nil {
-					numLocalAS = *v3res.Spec.NumAllowedLocalASNumbers
-				}
-
-				var ttlSecurityHopCount uint8
-				if v3res.Spec.TTLSecurity != nil {
-					ttlSecurityHopCount = *v3res.Spec.TTLSecurity
-				}
-
-				var reachableBy string
-				if v3res.Spec.ReachableBy != "" {
-					reachableByAddr := cnet.ParseIP(v3res.Spec.ReachableBy)
-					if reachableByAddr == nil {
-						log.Error("ReachableBy address is malformed")
-						continue
-					}
-
-					if reachableByAddr.Version() != ip.Version() {
-						log.Error("ReachableBy address family does not match PeerIP")
-						continue
+				hosts, port := c.peerAddresses(v3res)
+				for _, host := range hosts {
+					if peer := c.addressAsBGPPeer(host, port, v3res); peer != nil {
+						peers = append(peers, peer)
 					}
-					reachableBy = v3res.Spec.ReachableBy
 				}
-
-				peers = append(peers, &bgpPeer{
-					PeerIP:          *ip,
-					ASNum:           v3res.Spec.ASNumber,
-					SourceAddr:      string(v3res.Spec.SourceAddress),
-					Port:            port,
-					KeepNextHop:     v3res.Spec.KeepOriginalNextHop,
-					CalicoNode:      isCalicoNode,
-					TTLSecurity:     ttlSecurityHopCount,
-					Filters:         v3res.Spec.Filters,
-					NumAllowLocalAS: numLocalAS,
-					ReachableBy:     reachableBy,
-				})
 			}
 			log.Debugf("Peers %#v", peers)
 
@@ -659,14 +609,16 @@ func (c *client) updatePeersV1() {
 			includeV4 = true
 			includeV6 = true
 		} else {
-			ip, port := parseIPPort(v3res.Spec.PeerIP)
-			localNodeNames = c.nodesWithIPPortAndAS(ip, v3res.Spec.ASNumber, port)
-			// Peering on IP only selector, so we should reverse the peering only over the
-			// same IP version.
-			if strings.Contains(ip, ":") {
-				includeV6 = true
-			} else {
-				includeV4 = true
+			hosts, port := c.peerAddresses(v3res)
+			for _, ip := range hosts {
+				localNodeNames = append(localNodeNames, c.nodesWithIPPortAndAS(ip, v3res.Spec.ASNumber, port)...)
+				// Peering on IP only selector, so we should reverse the peering only over the
+				// same IP version.
+				if strings.Contains(ip, ":") {
+					includeV6 = true
+				} else {
+					includeV4 = true
+				}
 			}
 		}
 		log.Debugf("Local nodes %#v", localNodeNames)
@@ -735,6 +687,88 @@ func (c *client) updatePeersV1() {
 	}
 }
 
+// peerAddresses returns the addresses of the peer of a BGPPeer that specifies either PeerIP or
+// PeerHostname, along with the port to peer with, or 0 if none was given.
+func (c *client) peerAddresses(v3res *apiv3.BGPPeer) ([]string, uint16) {
+	if v3res.Spec.PeerHostname != "" {
+		name, port := parseIPPort(v3res.Spec.PeerHostname)
+		if c.peerResolver == nil {
+			log.Warningf("Can't resolve BGP peer hostname %v", name)
+			return nil, port
+		}
+		return c.peerResolver.Resolve(name), port
+	}
+	// Separate port from Ip if it uses <ip>:<port> format
+	host, port := parseIPPort(v3res.Spec.PeerIP)
+	return []string{host}, port
+}
+
+// addressAsBGPPeer returns the peering with the given address for a BGPPeer that specifies
+// either PeerIP or PeerHostname, or nil if the peering is invalid.
+func (c *client) addressAsBGPPeer(host string, port uint16, v3res *apiv3.BGPPeer) *bgpPeer {
+	ip := cnet.ParseIP(host)
+	if ip == nil {
+		log.Error("PeerIP is not assigned or is malformed")
+		return nil
+	}
+
+	// If port is not set, we use the given value to peer.
+	// If port is set, check if BGP Peer is a calico/node, and use its listenPort to peer.
+	if port == 0 {
+		// If port is empty, nodesWithIPPortAndAS() returns list of calico/node that matches IP and ASNumber.
+		nodeNames := c.nodesWithIPPortAndAS(host, v3res.Spec.ASNumber, port)
+		if len(nodeNames) != 0 {
+			if nodePort, ok := c.nodeListenPorts[nodeNames[0]]; ok {
+				port = nodePort
+			} else if c.globalListenPort != 0 {
+				port = c.globalListenPort
+			}
+		}
+	}
+
+	// Check if the peer represents a node Calico is running on.
+	_, isCalicoNode := c.nodeIPs[host]
+
+	// Check if local AS numbers are allowed.
+	var numLocalAS int32
+	if v3res.Spec.NumAllowedLocalASNumbers != nil {
+		numLocalAS = *v3res.Spec.NumAllowedLocalASNumbers
+	}
+
+	var ttlSecurityHopCount uint8
+	if v3res.Spec.TTLSecurity != nil {
+		ttlSecurityHopCount = *v3res.Spec.TTLSecurity
+	}
+
+	var reachableBy string
+	if v3res.Spec.ReachableBy != "" {
+		reachableByAddr := cnet.ParseIP(v3res.Spec.ReachableBy)
+		if reachableByAddr == nil {
+			log.Error("ReachableBy address is malformed")
+			return nil
+		}
+
+		if reachableByAddr.Version() != ip.Version() {
+			log.Error("ReachableBy address family does not match PeerIP")
+			return nil
+		}
+		reachableBy = v3res.Spec.ReachableBy
+	}
+
+	return &bgpPeer{
+		PeerIP:          *ip,
+		ASNum:           v3res.Spec.ASNumber,
+		SourceAddr:      string(v3res.Spec.SourceAddress),
+		Port:            port,
+		KeepNextHop:     v3res.Spec.KeepOriginalNextHop,
+		CalicoNode:      isCalicoNode,
+		TTLSecurity:     ttlSecurityHopCount,
+		Filters:         v3res.Spec.Filters,
+		NumAllowLocalAS: numLocalAS,
+		ReachableBy:     reachableBy,
+	}
+}
+
 func parseIPPort(ipPort string) (string, uint16) {
 	host, port, err := net.SplitHostPort(ipPort)
 	if err != nil {
@@ -1032,6 +1066,9 @@ func (c *client) onUpdates(updates []api.Update, needUpdatePeersV1 bool) {
 		if c.secretWatcher != nil {
 			c.secretWatcher.MarkStale()
 		}
+		if c.peerResolver != nil {
+			c.peerResolver.MarkStale()
+		}
 
 		log.Info("Recompute BGP peerings: " + strings.Join(needUpdatePeersReasons, "; "))
 		c.updatePeersV1()
@@ -1045,6 +1082,9 @@ func (c *client) onUpdates(updates []api.Update, needUpdatePeersV1 bool) {
 		if c.secretWatcher != nil {
 			c.secretWatcher.SweepStale()
 		}
+		if c.peerResolver != nil {
+			c.peerResolver.SweepStale()
+		}
 	}
 
 	// If we need to update Service advertisement based on the updates, then do so.
@@ -1565,7 +1605,7 @@ func (c *client) onNewUpdates() {
 }
 
 func (c *client) recheckPeerConfig() {
-	log.Info("Trigger to recheck BGP peers following possible password update")
+	log.Info("Trigger to recheck BGP peers following possible password or peer address update")
 	select {
 	// Non-blocking write into the recheckC channel.  The idea here is that we don't need to add
 	// a second trigger if there is already one pending.
@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package calico
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// How often the DNS names of BGP peers are re-resolved.
+	peerHostnameRefreshInterval = 30 * time.Second
+
+	// How long to wait for a DNS name to resolve.
+	peerHostnameResolveTimeout = 5 * time.Second
+)
+
+type resolvedPeerHostname struct {
+	// Stale marker.
+	stale bool
+
+	// The sorted addresses that the name resolved to.
+	addrs []string
+}
+
+// peerResolver resolves the DNS names of BGP peers, and re-resolves them periodically so that
+// the peerings are recomputed when the addresses of a peer change.  It follows the same
+// stale marking scheme as the secretWatcher.
+type peerResolver struct {
+	client *client
+	lookup func(ctx context.Context, name string) ([]string, error)
+	mutex  sync.Mutex
+	names  map[string]*resolvedPeerHostname
+}
+
+func NewPeerResolver(c *client) *peerResolver {
+	return &peerResolver{
+		client: c,
+		lookup: net.DefaultResolver.LookupHost,
+		names:  make(map[string]*resolvedPeerHostname),
+	}
+}
+
+// Start re-resolves the names in use every interval.
+func (pr *peerResolver) Start(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			pr.refresh()
+		}
+	}()
+}
+
+func (pr *peerResolver) MarkStale() {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	for _, resolved := range pr.names {
+		resolved.stale = true
+	}
+}
+
+// Resolve returns the addresses of the given name.  The first time a name is requested it is
+// resolved synchronously, after that the result of the most recent resolution is returned.
+func (pr *peerResolver) Resolve(name string) []string {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	resolved, ok := pr.names[name]
+	if !ok {
+		addrs, err := pr.resolve(name)
+		if err != nil {
+			log.WithError(err).Warningf("Can't resolve BGP peer hostname %v, will retry", name)
+		}
+		resolved = &resolvedPeerHostname{addrs: addrs}
+		pr.names[name] = resolved
+	}
+
+	// Mark it as still in use.
+	resolved.stale = false
+	return resolved.addrs
+}
+
+func (pr *peerResolver) SweepStale() {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	for name, resolved := range pr.names {
+		if resolved.stale {
+			delete(pr.names, name)
+		}
+	}
+}
+
+// refresh re-resolves all the names in use and triggers a recheck of the BGP peerings if any of
+// their addresses changed.  A failed resolution keeps the previous addresses, so that a DNS
+// outage doesn't tear down established peerings.
+func (pr *peerResolver) refresh() {
+	pr.mutex.Lock()
+	var names []string
+	for name := range pr.names {
+		names = append(names, name)
+	}
+	pr.mutex.Unlock()
+
+	changed := false
+	for _, name := range names {
+		// Resolve without holding the lock, so that a slow DNS server doesn't block
+		// the computation of the peerings.
+		addrs, err := pr.resolve(name)
+		if err != nil {
+			log.WithError(err).Warningf("Can't re-resolve BGP peer hostname %v, keeping previous addresses", name)
+			continue
+		}
+
+		pr.mutex.Lock()
+		if resolved, ok := pr.names[name]; ok && !reflect.DeepEqual(resolved.addrs, addrs) {
+			log.Infof("Addresses of BGP peer hostname %v changed from %v to %v", name, resolved.addrs, addrs)
+			resolved.addrs = addrs
+			changed = true
+		}
+		pr.mutex.Unlock()
+	}
+
+	if changed {
+		pr.client.recheckPeerConfig()
+	}
+}
+
+func (pr *peerResolver) resolve(name string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), peerHostnameResolveTimeout)
+	defer cancel()
+	addrs, err := pr.lookup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(addrs)
+	return addrs, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package calico
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+)
+
+var _ = Describe("peerResolver", func() {
+	var (
+		c       *client
+		pr      *peerResolver
+		records map[string][]string
+		lookups int
+	)
+
+	BeforeEach(func() {
+		c = &client{recheckC: make(chan struct{}, 1)}
+		pr = NewPeerResolver(c)
+		c.peerResolver = pr
+		records = map[string][]string{
+			"tor.example.com": {"10.0.0.2", "10.0.0.1"},
+		}
+		lookups = 0
+		pr.lookup = func(ctx context.Context, name string) ([]string, error) {
+			lookups++
+			addrs, ok := records[name]
+			if !ok {
+				return nil, errors.New("no such host")
+			}
+			return append([]string(nil), addrs...), nil
+		}
+	})
+
+	It("should resolve a name once and then return the cached addresses", func() {
+		Expect(pr.Resolve("tor.example.com")).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+		Expect(pr.Resolve("tor.example.com")).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+		Expect(lookups).To(Equal(1))
+	})
+
+	It("should trigger a recheck when the addresses change", func() {
+		pr.Resolve("tor.example.com")
+
+		pr.refresh()
+		Expect(c.recheckC).NotTo(Receive())
+
+		records["tor.example.com"] = []string{"10.0.0.3"}
+		pr.refresh()
+		Expect(c.recheckC).To(Receive())
+		Expect(pr.Resolve("tor.example.com")).To(Equal([]string{"10.0.0.3"}))
+	})
+
+	It("should keep the previous addresses when resolution fails", func() {
+		pr.Resolve("tor.example.com")
+
+		delete(records, "tor.example.com")
+		pr.refresh()
+		Expect(c.recheckC).NotTo(Receive())
+		Expect(pr.Resolve("tor.example.com")).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+	})
+
+	It("should pick up a name that failed to resolve at first", func() {
+		Expect(pr.Resolve("new.example.com")).To(BeEmpty())
+
+		records["new.example.com"] = []string{"10.0.0.9"}
+		pr.refresh()
+		Expect(c.recheckC).To(Receive())
+		Expect(pr.Resolve("new.example.com")).To(Equal([]string{"10.0.0.9"}))
+	})
+
+	It("should stop resolving names that are no longer used", func() {
+		pr.Resolve("tor.example.com")
+		pr.MarkStale()
+		pr.SweepStale()
+
+		pr.refresh()
+		Expect(lookups).To(Equal(1))
+	})
+
+	It("should return the resolved addresses of a BGPPeer with a hostname", func() {
+		peer := &apiv3.BGPPeer{Spec: apiv3.BGPPeerSpec{PeerHostname: "tor.example.com:1179"}}
+		hosts, port := c.peerAddresses(peer)
+		Expect(hosts).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+		Expect(port).To(Equal(uint16(1179)))
+	})
+})
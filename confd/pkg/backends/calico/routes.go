@@ -23,6 +23,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -33,17 +34,20 @@ import (
 
 const (
 	envAdvertiseClusterIPs = "CALICO_ADVERTISE_CLUSTER_IPS"
+
+	// serviceIndex indexes the EndpointSlices by the namespace/name key of their service.
+	serviceIndex = "service"
 )
 
 // routeGenerator defines the data fields
-// necessary for monitoring the services/endpoints resources for
+// necessary for monitoring the services/endpointslices resources for
 // valid service ips to advertise
 type routeGenerator struct {
 	sync.Mutex
 	client                     *client
 	nodeName                   string
-	svcInformer, epInformer    cache.Controller
-	svcIndexer, epIndexer      cache.Indexer
+	svcInformer, epsInformer   cache.Controller
+	svcIndexer, epsIndexer     cache.Indexer
 	svcRouteMap                map[string]map[string]bool
 	routeAdvertisementRefCount map[string]int
 	resyncKnownRoutesTrigger   chan struct{}
@@ -90,10 +94,12 @@ func NewRouteGenerator(c *client) (rg *routeGenerator, err error) {
 	svcHandler := cache.ResourceEventHandlerFuncs{AddFunc: rg.onSvcAdd, UpdateFunc: rg.onSvcUpdate, DeleteFunc: rg.onSvcDelete}
 	rg.svcIndexer, rg.svcInformer = cache.NewIndexerInformer(svcWatcher, &v1.Service{}, 0, svcHandler, cache.Indexers{})
 
-	// set up endpoints informer
-	epWatcher := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "endpoints", "", fields.Everything())
-	epHandler := cache.ResourceEventHandlerFuncs{AddFunc: rg.onEPAdd, UpdateFunc: rg.onEPUpdate, DeleteFunc: rg.onEPDelete}
-	rg.epIndexer, rg.epInformer = cache.NewIndexerInformer(epWatcher, &v1.Endpoints{}, 0, epHandler, cache.Indexers{})
+	// set up endpoint slices informer.  A service's endpoints may be split across many slices,
+	// so index them by service to find them all when a service or one of its slices changes.
+	epsWatcher := cache.NewListWatchFromClient(client.DiscoveryV1().RESTClient(), "endpointslices", "", fields.Everything())
+	epsHandler := cache.ResourceEventHandlerFuncs{AddFunc: rg.onEPSAdd, UpdateFunc: rg.onEPSUpdate, DeleteFunc: rg.onEPSDelete}
+	rg.epsIndexer, rg.epsInformer = cache.NewIndexerInformer(epsWatcher, &discoveryv1.EndpointSlice{}, 0, epsHandler,
+		cache.Indexers{serviceIndex: endpointSliceServiceIndexFunc})
 
 	return
 }
@@ -102,12 +108,12 @@ func NewRouteGenerator(c *client) (rg *routeGenerator, err error) {
 func (rg *routeGenerator) Start() {
 	ch := make(chan struct{})
 	go rg.svcInformer.Run(ch)
-	go rg.epInformer.Run(ch)
+	go rg.epsInformer.Run(ch)
 
 	// Wait for informers to sync, then notify the main client.
 	log.Info("Starting RouteGenerator for Kubernetes services")
 	go func() {
-		for !rg.svcInformer.HasSynced() || !rg.epInformer.HasSynced() {
+		for !rg.svcInformer.HasSynced() || !rg.epsInformer.HasSynced() {
 			time.Sleep(100 * time.Millisecond)
 		}
 
@@ -133,67 +139,95 @@ func (rg *routeGenerator) TriggerResync() {
 	}
 }
 
-// getServiceForEndpoints retrieves the corresponding svc for the given ep
-func (rg *routeGenerator) getServiceForEndpoints(ep *v1.Endpoints) (*v1.Service, string) {
+// endpointSliceServiceIndexFunc indexes an endpoint slice by the key of the service that owns it.
+func endpointSliceServiceIndexFunc(obj interface{}) ([]string, error) {
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+	key := serviceKeyForEndpointSlice(eps)
+	if key == "" {
+		return nil, nil
+	}
+	return []string{key}, nil
+}
+
+// serviceKeyForEndpointSlice returns the namespace/name key of the service that owns the given
+// endpoint slice, or "" if it doesn't belong to a service.
+func serviceKeyForEndpointSlice(eps *discoveryv1.EndpointSlice) string {
+	svcName := eps.Labels[discoveryv1.LabelServiceName]
+	if svcName == "" {
+		return ""
+	}
+	return eps.Namespace + "/" + svcName
+}
+
+// getServiceForEndpointSlice retrieves the corresponding svc for the given endpoint slice
+func (rg *routeGenerator) getServiceForEndpointSlice(eps *discoveryv1.EndpointSlice) (*v1.Service, string) {
 	// get key
-	key, err := cache.MetaNamespaceKeyFunc(ep)
-	if err != nil {
-		log.WithField("ep", ep.Name).WithError(err).Warn("getServiceForEndpoints: error on retrieving key for endpoint, passing")
+	key := serviceKeyForEndpointSlice(eps)
+	if key == "" {
+		log.WithField("eps", eps.Name).Debug("getServiceForEndpointSlice: endpoint slice has no service, passing")
 		return nil, ""
 	}
 	// get svc
 	svcIface, exists, err := rg.svcIndexer.GetByKey(key)
 	if err != nil {
-		log.WithField("key", key).WithError(err).Warn("getServiceForEndpoints: error on retrieving service for key, passing")
+		log.WithField("key", key).WithError(err).Warn("getServiceForEndpointSlice: error on retrieving service for key, passing")
 		return nil, key
 	} else if !exists {
-		log.WithField("key", key).Debug("getServiceForEndpoints: service for key not found, passing")
+		log.WithField("key", key).Debug("getServiceForEndpointSlice: service for key not found, passing")
 		return nil, key
 	}
 	return svcIface.(*v1.Service), key
 }
 
-// getEndpointsForService retrieves the corresponding ep for the given svc
-func (rg *routeGenerator) getEndpointsForService(svc *v1.Service) (*v1.Endpoints, string) {
+// getEndpointSlicesForService retrieves all the endpoint slices of the given svc
+func (rg *routeGenerator) getEndpointSlicesForService(svc *v1.Service) ([]*discoveryv1.EndpointSlice, string) {
 	// get key
 	key, err := cache.MetaNamespaceKeyFunc(svc)
 	if err != nil {
-		log.WithField("svc", svc.Name).WithError(err).Warn("getEndpointsForService: error on retrieving key for service, passing")
+		log.WithField("svc", svc.Name).WithError(err).Warn("getEndpointSlicesForService: error on retrieving key for service, passing")
 		return nil, ""
 	}
-	// get ep
-	epIface, exists, err := rg.epIndexer.GetByKey(key)
+	return rg.getEndpointSlicesForKey(key), key
+}
+
+// getEndpointSlicesForKey retrieves all the endpoint slices of the service with the given key
+func (rg *routeGenerator) getEndpointSlicesForKey(key string) []*discoveryv1.EndpointSlice {
+	epsIfaces, err := rg.epsIndexer.ByIndex(serviceIndex, key)
 	if err != nil {
-		log.WithField("key", key).WithError(err).Warn("getEndpointsForService: error on retrieving endpoint for key, passing")
-		return nil, key
-	} else if !exists {
-		log.WithField("key", key).Debug("getEndpointsForService: service for endpoint not found, passing")
-		return nil, key
+		log.WithField("key", key).WithError(err).Warn("getEndpointSlicesForKey: error on retrieving endpoint slices for key, passing")
+		return nil
 	}
-	return epIface.(*v1.Endpoints), key
+	slices := make([]*discoveryv1.EndpointSlice, 0, len(epsIfaces))
+	for _, epsIface := range epsIfaces {
+		slices = append(slices, epsIface.(*discoveryv1.EndpointSlice))
+	}
+	return slices
 }
 
-// setRouteForSvc handles the main logic to check if a specified service or endpoint
-// should have its route advertised by the node running this code
-func (rg *routeGenerator) setRouteForSvc(svc *v1.Service, ep *v1.Endpoints) {
+// setRouteForSvc handles the main logic to check if a specified service or endpoint slice
+// should have its route advertised by the node running this code.  Only the routes of the
+// one service are recalculated, however many slices it has.
+func (rg *routeGenerator) setRouteForSvc(svc *v1.Service, eps *discoveryv1.EndpointSlice) {
 	// ensure both are not nil
-	if svc == nil && ep == nil {
-		log.Error("setRouteForSvc: both service and endpoint cannot be nil, passing...")
+	if svc == nil && eps == nil {
+		log.Error("setRouteForSvc: both service and endpoint slice cannot be nil, passing...")
 		return
 	}
 
-	var key string
 	if svc == nil {
-		// ep received but svc nil
-		if svc, key = rg.getServiceForEndpoints(ep); svc == nil {
-			return
-		}
-	} else if ep == nil {
-		// svc received but ep nil
-		if ep, key = rg.getEndpointsForService(svc); ep == nil {
+		// eps received but svc nil
+		if svc, _ = rg.getServiceForEndpointSlice(eps); svc == nil {
 			return
 		}
 	}
+	slices, key := rg.getEndpointSlicesForService(svc)
+	if len(slices) == 0 {
+		log.WithField("key", key).Debug("setRouteForSvc: no endpoint slices for service, passing")
+		return
+	}
 
 	// see if any endpoints are on this node and advertise if so
 	// else remove the route if it also already exists
@@ -201,7 +235,7 @@ func (rg *routeGenerator) setRouteForSvc(svc *v1.Service, ep *v1.Endpoints) {
 	rg.Lock()
 	defer rg.Unlock()
 
-	advertise := rg.advertiseThisService(svc, ep)
+	advertise := rg.advertiseThisService(svc, slices)
 	logCtx.WithField("advertise", advertise).Debug("Checking routes for service")
 	if advertise {
 		routes := rg.getAllRoutesForService(svc)
@@ -402,7 +436,7 @@ func contains(items []string, target string) bool {
 
 // advertiseThisService returns true if this service should be advertised on this node,
 // false otherwise.
-func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints) bool {
+func (rg *routeGenerator) advertiseThisService(svc *v1.Service, slices []*discoveryv1.EndpointSlice) bool {
 	logc := log.WithField("svc", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
 
 	// Don't advertise routes if this node is explicitly excluded from load balancers.
@@ -435,15 +469,21 @@ func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints
 		return false
 	}
 
-	isIPv6 := func(ip string) bool { return strings.Contains(ip, ":") }
-
-	svcIsIPv6 := isIPv6(svc.Spec.ClusterIP)
+	svcAddressType := discoveryv1.AddressTypeIPv4
+	if strings.Contains(svc.Spec.ClusterIP, ":") {
+		svcAddressType = discoveryv1.AddressTypeIPv6
+	}
 
-	// Otherwise, advertise if node contains at least one endpoint for svc
-	for _, subset := range ep.Subsets {
-		// not interested in subset.NotReadyAddresses
-		for _, address := range subset.Addresses {
-			if address.NodeName != nil && *address.NodeName == rg.nodeName && isIPv6(address.IP) == svcIsIPv6 {
+	// Otherwise, advertise if node contains at least one ready endpoint for svc
+	for _, eps := range slices {
+		if eps.AddressType != svcAddressType {
+			continue
+		}
+		for _, ep := range eps.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			if ep.NodeName != nil && *ep.NodeName == rg.nodeName {
 				logc.Debugf("Advertising local service")
 				return true
 			}
@@ -463,6 +503,11 @@ func (rg *routeGenerator) unsetRouteForSvc(obj interface{}) {
 		return
 	}
 
+	rg.unsetRoutesForKey(key)
+}
+
+// unsetRoutesForKey withdraws all the routes advertised for the service with the given key.
+func (rg *routeGenerator) unsetRoutesForKey(key string) {
 	// mutex
 	rg.Lock()
 	defer rg.Unlock()
@@ -549,29 +594,47 @@ func (rg *routeGenerator) onSvcDelete(obj interface{}) {
 	rg.unsetRouteForSvc(obj)
 }
 
-// onEPAdd is called when a k8s endpoint is created
-func (rg *routeGenerator) onEPAdd(obj interface{}) {
-	ep, ok := obj.(*v1.Endpoints)
+// onEPSAdd is called when a k8s endpoint slice is created
+func (rg *routeGenerator) onEPSAdd(obj interface{}) {
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
 	if !ok {
-		log.Warn("onEPAdd: failed to assert type to endpoints, passing")
+		log.Warn("onEPSAdd: failed to assert type to endpoint slice, passing")
 		return
 	}
-	rg.setRouteForSvc(nil, ep)
+	rg.setRouteForSvc(nil, eps)
 }
 
-// onEPUpdate is called when a k8s endpoint is updated
-func (rg *routeGenerator) onEPUpdate(_, obj interface{}) {
-	ep, ok := obj.(*v1.Endpoints)
+// onEPSUpdate is called when a k8s endpoint slice is updated
+func (rg *routeGenerator) onEPSUpdate(_, obj interface{}) {
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
 	if !ok {
-		log.Warn("onEPUpdate: failed to assert type to endpoints, passing")
+		log.Warn("onEPSUpdate: failed to assert type to endpoint slice, passing")
 		return
 	}
-	rg.setRouteForSvc(nil, ep)
+	rg.setRouteForSvc(nil, eps)
 }
 
-// onEPDelete is called when a k8s endpoint is deleted
-func (rg *routeGenerator) onEPDelete(obj interface{}) {
-	rg.unsetRouteForSvc(obj)
+// onEPSDelete is called when a k8s endpoint slice is deleted
+func (rg *routeGenerator) onEPSDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		log.Warn("onEPSDelete: failed to assert type to endpoint slice, passing")
+		return
+	}
+	key := serviceKeyForEndpointSlice(eps)
+	if key == "" {
+		return
+	}
+	if len(rg.getEndpointSlicesForKey(key)) == 0 {
+		// That was the service's last slice.
+		rg.unsetRoutesForKey(key)
+		return
+	}
+	// The service's other slices may still have local endpoints.
+	rg.setRouteForSvc(nil, eps)
 }
 
 // parseIPNets takes a v1 formatted, comma separated string of CIDRs and
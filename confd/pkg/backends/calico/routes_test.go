@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
@@ -31,17 +32,25 @@ const (
 	loadBalancerIP1 = "172.217.4.10"
 )
 
-func addEndpointSubset(ep *v1.Endpoints, nodename string) {
-	ep.Subsets = append(ep.Subsets, v1.EndpointSubset{
-		Addresses: []v1.EndpointAddress{
-			{
-				NodeName: &nodename,
-			},
-		},
+func addEndpoint(eps *discoveryv1.EndpointSlice, nodename string) {
+	eps.Endpoints = append(eps.Endpoints, discoveryv1.Endpoint{
+		Addresses: []string{"10.96.0.1"},
+		NodeName:  &nodename,
 	})
 }
 
-func buildSimpleService() (svc *v1.Service, ep *v1.Endpoints) {
+func buildEndpointSlice(meta metav1.ObjectMeta, suffix string) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: meta.Namespace,
+			Name:      meta.Name + "-" + suffix,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: meta.Name},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+}
+
+func buildSimpleService() (svc *v1.Service, ep *discoveryv1.EndpointSlice) {
 	meta := metav1.ObjectMeta{Namespace: "foo", Name: "bar"}
 	svc = &v1.Service{
 		ObjectMeta: meta,
@@ -52,13 +61,11 @@ func buildSimpleService() (svc *v1.Service, ep *v1.Endpoints) {
 			ExternalIPs:           []string{externalIP1, externalIP2},
 		},
 	}
-	ep = &v1.Endpoints{
-		ObjectMeta: meta,
-	}
+	ep = buildEndpointSlice(meta, "abcde")
 	return
 }
 
-func buildSimpleService2() (svc *v1.Service, ep *v1.Endpoints) {
+func buildSimpleService2() (svc *v1.Service, ep *discoveryv1.EndpointSlice) {
 	meta := metav1.ObjectMeta{Namespace: "foo", Name: "rem"}
 	svc = &v1.Service{
 		ObjectMeta: meta,
@@ -69,13 +76,11 @@ func buildSimpleService2() (svc *v1.Service, ep *v1.Endpoints) {
 			ExternalIPs:           []string{externalIP1, externalIP2},
 		},
 	}
-	ep = &v1.Endpoints{
-		ObjectMeta: meta,
-	}
+	ep = buildEndpointSlice(meta, "abcde")
 	return
 }
 
-func buildSimpleService3() (svc *v1.Service, ep *v1.Endpoints) {
+func buildSimpleService3() (svc *v1.Service, ep *discoveryv1.EndpointSlice) {
 	meta := metav1.ObjectMeta{Namespace: "foo", Name: "lb"}
 	svc = &v1.Service{
 		ObjectMeta: meta,
@@ -91,9 +96,7 @@ func buildSimpleService3() (svc *v1.Service, ep *v1.Endpoints) {
 			},
 		},
 	}
-	ep = &v1.Endpoints{
-		ObjectMeta: meta,
-	}
+	ep = buildEndpointSlice(meta, "abcde")
 	return
 }
 
@@ -117,7 +120,7 @@ var _ = Describe("RouteGenerator", func() {
 		rg = &routeGenerator{
 			nodeName:                   "foobar",
 			svcIndexer:                 cache.NewIndexer(cache.MetaNamespaceKeyFunc, nil),
-			epIndexer:                  cache.NewIndexer(cache.MetaNamespaceKeyFunc, nil),
+			epsIndexer:                 cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{serviceIndex: endpointSliceServiceIndexFunc}),
 			svcRouteMap:                make(map[string]map[string]bool),
 			routeAdvertisementRefCount: make(map[string]int),
 			client: &client{
@@ -141,25 +144,29 @@ var _ = Describe("RouteGenerator", func() {
 		}
 		rg.client.watcherCond = sync.NewCond(&rg.client.cacheLock)
 	})
-	Describe("getServiceForEndpoints", func() {
-		It("should get corresponding service for endpoints", func() {
-			// getServiceForEndpoints
+	Describe("getServiceForEndpointSlice", func() {
+		It("should get corresponding service for endpoint slice", func() {
+			// getServiceForEndpointSlice
 			svc, ep := buildSimpleService()
 			err := rg.svcIndexer.Add(svc)
 			Expect(err).NotTo(HaveOccurred())
-			fetchedSvc, key := rg.getServiceForEndpoints(ep)
+			fetchedSvc, key := rg.getServiceForEndpointSlice(ep)
 			Expect(fetchedSvc.ObjectMeta).To(Equal(svc.ObjectMeta))
 			Expect(key).To(Equal("foo/bar"))
 		})
 	})
-	Describe("getEndpointsForService", func() {
-		It("should get corresponding endpoints for service", func() {
-			// getEndpointsForService
+	Describe("getEndpointSlicesForService", func() {
+		It("should get all the endpoint slices of the service", func() {
+			// getEndpointSlicesForService
 			svc, ep := buildSimpleService()
-			err := rg.epIndexer.Add(ep)
-			Expect(err).NotTo(HaveOccurred())
-			fetchedEp, key := rg.getEndpointsForService(svc)
-			Expect(fetchedEp.ObjectMeta).To(Equal(ep.ObjectMeta))
+			ep2 := buildEndpointSlice(svc.ObjectMeta, "fghij")
+			_, other := buildSimpleService2()
+			for _, eps := range []*discoveryv1.EndpointSlice{ep, ep2, other} {
+				err := rg.epsIndexer.Add(eps)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			fetchedEps, key := rg.getEndpointSlicesForService(svc)
+			Expect(fetchedEps).To(ConsistOf(ep, ep2))
 			Expect(key).To(Equal("foo/bar"))
 		})
 	})
@@ -204,27 +211,29 @@ var _ = Describe("RouteGenerator", func() {
 		Context("svc = svc, ep = nil", func() {
 			It("should set and unset routes for a service", func() {
 				svc, ep := buildSimpleService()
-				addEndpointSubset(ep, rg.nodeName)
+				addEndpoint(ep, rg.nodeName)
 
-				err := rg.epIndexer.Add(ep)
+				err := rg.epsIndexer.Add(ep)
 				Expect(err).NotTo(HaveOccurred())
 				rg.setRouteForSvc(svc, nil)
 				fmt.Fprintln(GinkgoWriter, rg.svcRouteMap)
 				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
-				rg.unsetRouteForSvc(ep)
+				rg.unsetRouteForSvc(svc)
 				Expect(rg.svcRouteMap["foo/bar"]).To(BeEmpty())
 			})
 		})
 		Context("svc = nil, ep = ep", func() {
 			It("should set an unset routes for a service", func() {
 				svc, ep := buildSimpleService()
-				addEndpointSubset(ep, rg.nodeName)
+				addEndpoint(ep, rg.nodeName)
 
 				err := rg.svcIndexer.Add(svc)
 				Expect(err).NotTo(HaveOccurred())
+				err = rg.epsIndexer.Add(ep)
+				Expect(err).NotTo(HaveOccurred())
 				rg.setRouteForSvc(nil, ep)
 				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
-				rg.unsetRouteForSvc(ep)
+				rg.unsetRouteForSvc(svc)
 				Expect(rg.svcRouteMap["foo/bar"]).To(BeEmpty())
 			})
 		})
@@ -233,7 +242,7 @@ var _ = Describe("RouteGenerator", func() {
 	Describe("resourceInformerHandlers", func() {
 		var (
 			svc, svc2, svc3 *v1.Service
-			ep, ep2, ep3    *v1.Endpoints
+			ep, ep2, ep3    *discoveryv1.EndpointSlice
 		)
 
 		BeforeEach(func() {
@@ -241,14 +250,14 @@ var _ = Describe("RouteGenerator", func() {
 			svc2, ep2 = buildSimpleService2()
 			svc3, ep3 = buildSimpleService3()
 
-			addEndpointSubset(ep, rg.nodeName)
-			addEndpointSubset(ep2, rg.nodeName)
-			addEndpointSubset(ep3, rg.nodeName)
-			err := rg.epIndexer.Add(ep)
+			addEndpoint(ep, rg.nodeName)
+			addEndpoint(ep2, rg.nodeName)
+			addEndpoint(ep3, rg.nodeName)
+			err := rg.epsIndexer.Add(ep)
 			Expect(err).NotTo(HaveOccurred())
-			err = rg.epIndexer.Add(ep2)
+			err = rg.epsIndexer.Add(ep2)
 			Expect(err).NotTo(HaveOccurred())
-			err = rg.epIndexer.Add(ep3)
+			err = rg.epsIndexer.Add(ep3)
 			Expect(err).NotTo(HaveOccurred())
 			err = rg.svcIndexer.Add(svc)
 			Expect(err).NotTo(HaveOccurred())
@@ -270,10 +279,10 @@ var _ = Describe("RouteGenerator", func() {
 			Expect(rg.client.cache["/calico/staticroutes/172.217.3.5-32"]).To(Equal("172.217.3.5/32"))
 
 			// Simulate the remove of the local endpoint. It should withdraw the routes.
-			ep.Subsets = []v1.EndpointSubset{}
-			err := rg.epIndexer.Add(ep)
+			ep.Endpoints = nil
+			err := rg.epsIndexer.Update(ep)
 			Expect(err).NotTo(HaveOccurred())
-			rg.onEPAdd(ep)
+			rg.onEPSUpdate(nil, ep)
 			Expect(rg.client.cacheRevision).To(Equal(initRevision + 4))
 			Expect(rg.svcRouteMap["foo/bar"]).To(BeEmpty())
 			Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(0))
@@ -284,15 +293,14 @@ var _ = Describe("RouteGenerator", func() {
 
 			// Add the endpoint back with an IPv6 address.  The service's cluster IP
 			// should remain non-advertised.
-			ep.Subsets = []v1.EndpointSubset{{
-				Addresses: []v1.EndpointAddress{{
-					IP:       "fd5f:1234::3",
-					NodeName: &rg.nodeName,
-				}},
+			ep.AddressType = discoveryv1.AddressTypeIPv6
+			ep.Endpoints = []discoveryv1.Endpoint{{
+				Addresses: []string{"fd5f:1234::3"},
+				NodeName:  &rg.nodeName,
 			}}
-			err = rg.epIndexer.Add(ep)
+			err = rg.epsIndexer.Update(ep)
 			Expect(err).NotTo(HaveOccurred())
-			rg.onEPAdd(ep)
+			rg.onEPSUpdate(nil, ep)
 			Expect(rg.client.cacheRevision).To(Equal(initRevision + 4))
 			Expect(rg.svcRouteMap["foo/bar"]).To(BeEmpty())
 			Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(0))
@@ -303,15 +311,14 @@ var _ = Describe("RouteGenerator", func() {
 
 			// Add the endpoint again with an IPv4 address.  The service's cluster IP
 			// should now be advertised.
-			ep.Subsets = []v1.EndpointSubset{{
-				Addresses: []v1.EndpointAddress{{
-					IP:       "10.96.0.45",
-					NodeName: &rg.nodeName,
-				}},
+			ep.AddressType = discoveryv1.AddressTypeIPv4
+			ep.Endpoints = []discoveryv1.Endpoint{{
+				Addresses: []string{"10.96.0.45"},
+				NodeName:  &rg.nodeName,
 			}}
-			err = rg.epIndexer.Add(ep)
+			err = rg.epsIndexer.Update(ep)
 			Expect(err).NotTo(HaveOccurred())
-			rg.onEPAdd(ep)
+			rg.onEPSUpdate(nil, ep)
 			Expect(rg.client.cacheRevision).To(Equal(initRevision + 6))
 			Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
 			Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(1))
@@ -424,11 +431,11 @@ var _ = Describe("RouteGenerator", func() {
 			})
 		})
 
-		Context("onEp[Add|Delete]", func() {
+		Context("onEPS[Add|Delete]", func() {
 			It("should add the service's cluster IP and approved external IPs into the svcRouteMap", func() {
 				// add
 				initRevision := rg.client.cacheRevision
-				rg.onEPAdd(ep)
+				rg.onEPSAdd(ep)
 				Expect(rg.client.cacheRevision).To(Equal(initRevision + 2))
 				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
 				Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(1))
@@ -437,7 +444,9 @@ var _ = Describe("RouteGenerator", func() {
 				Expect(rg.client.cache["/calico/staticroutes/172.217.3.5-32"]).To(Equal("172.217.3.5/32"))
 
 				// delete
-				rg.onEPDelete(ep)
+				err := rg.epsIndexer.Delete(ep)
+				Expect(err).NotTo(HaveOccurred())
+				rg.onEPSDelete(ep)
 				Expect(rg.client.cacheRevision).To(Equal(initRevision + 4))
 				Expect(rg.svcRouteMap).ToNot(HaveKey("foo/bar"))
 				Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(0))
@@ -447,10 +456,10 @@ var _ = Describe("RouteGenerator", func() {
 			})
 		})
 
-		Context("onEpDelete", func() {
+		Context("onEPSUpdate", func() {
 			It("should add the service's cluster IP and approved external IPs into the svcRouteMap and then remove it for unsupported service type", func() {
 				initRevision := rg.client.cacheRevision
-				rg.onEPUpdate(nil, ep)
+				rg.onEPSUpdate(nil, ep)
 				Expect(rg.client.cacheRevision).To(Equal(initRevision + 2))
 				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
 				Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(1))
@@ -460,7 +469,7 @@ var _ = Describe("RouteGenerator", func() {
 
 				// set to unsupported service type
 				svc.Spec.Type = v1.ServiceTypeExternalName
-				rg.onEPUpdate(nil, ep)
+				rg.onEPSUpdate(nil, ep)
 				Expect(rg.client.cacheRevision).To(Equal(initRevision + 4))
 				Expect(rg.svcRouteMap["foo/bar"]).ToNot(HaveKey("172.217.3.5/32"))
 				Expect(rg.routeAdvertisementRefCount["127.0.0.1/32"]).To(Equal(0))
@@ -470,6 +479,57 @@ var _ = Describe("RouteGenerator", func() {
 			})
 		})
 
+		Context("with a service split across several endpoint slices", func() {
+			var remoteEps, localEps *discoveryv1.EndpointSlice
+
+			BeforeEach(func() {
+				// Replace the service's slice with one slice of remote endpoints and
+				// another that holds the only local endpoint.
+				err := rg.epsIndexer.Delete(ep)
+				Expect(err).NotTo(HaveOccurred())
+				remoteEps = buildEndpointSlice(svc.ObjectMeta, "remote")
+				for i := 0; i < 100; i++ {
+					addEndpoint(remoteEps, fmt.Sprintf("remote-%d", i))
+				}
+				localEps = buildEndpointSlice(svc.ObjectMeta, "local")
+				addEndpoint(localEps, rg.nodeName)
+				for _, eps := range []*discoveryv1.EndpointSlice{remoteEps, localEps} {
+					err = rg.epsIndexer.Add(eps)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			})
+
+			It("should advertise while any slice has a local endpoint", func() {
+				rg.onEPSAdd(remoteEps)
+				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
+
+				// Removing the remote slice leaves the local endpoint.
+				err := rg.epsIndexer.Delete(remoteEps)
+				Expect(err).NotTo(HaveOccurred())
+				rg.onEPSDelete(cache.DeletedFinalStateUnknown{Key: "foo/bar-remote", Obj: remoteEps})
+				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
+
+				// Removing the last slice withdraws the routes.
+				err = rg.epsIndexer.Delete(localEps)
+				Expect(err).NotTo(HaveOccurred())
+				rg.onEPSDelete(localEps)
+				Expect(rg.svcRouteMap).NotTo(HaveKey("foo/bar"))
+				Expect(rg.client.cache).NotTo(HaveKey("/calico/staticroutes/127.0.0.1-32"))
+			})
+
+			It("should not advertise a local endpoint that isn't ready", func() {
+				rg.onEPSAdd(localEps)
+				Expect(rg.svcRouteMap["foo/bar"]).To(Equal(expectedSvcRouteMap))
+
+				ready := false
+				localEps.Endpoints[0].Conditions.Ready = &ready
+				err := rg.epsIndexer.Update(localEps)
+				Expect(err).NotTo(HaveOccurred())
+				rg.onEPSUpdate(nil, localEps)
+				Expect(rg.svcRouteMap).NotTo(HaveKey("foo/bar"))
+			})
+		})
+
 		Context("On BGP configuration changes from the syncer", func() {
 			It("should only advertise external IPs within the configured ranges", func() {
 				// Simulate an event from the syncer which sets the External IP range containing the first IP.
@@ -498,7 +558,7 @@ var _ = Describe("RouteGenerator", func() {
 			It("should not advertise cluster IPs unless a range is specified", func() {
 				// Show cluster CIDRs are advertised.
 				rg.onSvcAdd(svc)
-				rg.onEPAdd(ep)
+				rg.onEPSAdd(ep)
 				Expect(rg.client.cache["/calico/staticroutes/127.0.0.1-32"]).To(Equal("127.0.0.1/32"))
 
 				// Withdraw the cluster CIDR from the syncer.
@@ -582,7 +642,7 @@ var _ = Describe("RouteGenerator", func() {
 				// BeforeEach creates a service. Remove it before the test, since we want to start
 				// this test without the service in place. svc3 is a LoadBalancer service with external traffic
 				// policy of Local.
-				err := rg.epIndexer.Delete(ep3)
+				err := rg.epsIndexer.Delete(ep3)
 				Expect(err).NotTo(HaveOccurred())
 				err = rg.svcIndexer.Delete(svc3)
 				Expect(err).NotTo(HaveOccurred())
@@ -609,7 +669,7 @@ var _ = Describe("RouteGenerator", func() {
 				Expect(rg.client.programmedRouteRefCount[key]).To(Equal(0))
 
 				// Now add the service.
-				err = rg.epIndexer.Add(ep3)
+				err = rg.epsIndexer.Add(ep3)
 				Expect(err).NotTo(HaveOccurred())
 				err = rg.svcIndexer.Add(svc3)
 				Expect(err).NotTo(HaveOccurred())
@@ -168,8 +168,11 @@ enum calico_skb_mark {
 	/* "BYPASS_FWD" is a special case of "BYPASS" used when a packet returns from one of our
 	 * VXLAN tunnels.  It tells the downstream program to forward the packet. */
 	CALI_SKB_MARK_BYPASS_FWD             = CALI_SKB_MARK_BYPASS  | 0x00300000,
-	/* Now unused mark */
-	CALI_SKB_MARK_free_to_use            = CALI_SKB_MARK_BYPASS  | 0x00500000,
+	/* "BYPASS_WG" is a special case of "BYPASS" used when WireGuard is enabled and the packet,
+	 * after DNAT, is headed to a remote backend.  It skips the FIB short-circuit so that the
+	 * packet is routed by the kernel and matched by the WireGuard routing rule, which Felix
+	 * programs to explicitly match this mark. */
+	CALI_SKB_MARK_BYPASS_WG              = CALI_SKB_MARK_BYPASS  | 0x00500000,
 	CALI_SKB_MARK_BYPASS_MASK            = CALI_SKB_MARK_SEEN_MASK | 0x02700000,
 	/* The FALLTHROUGH bit is used by programs that are towards the host namespace to indicate
 	 * that the packet is not known in BPF conntrack. We have iptables rules to drop or allow
@@ -182,6 +185,7 @@ enum calico_skb_mark {
 	CALI_SKB_MARK_NAT_OUT                = CALI_SKB_MARK_BYPASS  | 0x00800000,
 	/* CALI_SKB_MARK_MASQ enforces MASQ on the connection. */
 	CALI_SKB_MARK_MASQ                   = CALI_SKB_MARK_BYPASS  | 0x00600000,
+	CALI_SKB_MARK_MASQ_MASK              = CALI_SKB_MARK_BYPASS_MASK | 0x00f00000,
 	/* CALI_SKB_MARK_SKIP_FIB is used for packets that should pass through host IP stack. */
 	CALI_SKB_MARK_SKIP_FIB               = CALI_SKB_MARK_SEEN | 0x00100000,
 	/* CT_ESTABLISHED is used by iptables to tell the BPF programs that the packet is part of an
@@ -287,6 +291,9 @@ extern const volatile struct cali_tc_preamble_globals __globals;
 #define HOST_TUNNEL_IP	CALI_CONFIGURABLE_IP(host_tunnel_ip)
 #define WG_PORT		CALI_CONFIGURABLE(wg_port)
 #define NATIN_IFACE	CALI_CONFIGURABLE(natin_idx)
+#define CT_SHARDS	CALI_CONFIGURABLE(ct_shards)
+#define NAT64_PREFIX	CALI_CONFIGURABLE(nat64_prefix)
+#define NAT64_IP	CALI_CONFIGURABLE(nat64_ip)
 
 #ifdef UNITTEST
 #define CALI_PATCH_DEFINE(name, pattern)							\
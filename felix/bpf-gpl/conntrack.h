@@ -73,6 +73,78 @@ static CALI_BPF_INLINE void dump_ct_key(struct cali_tc_ctx *ctx, struct calico_c
 	CALI_VERB("CT-ALL   key B=%x:%d size=%d\n", debug_ip(k->addr_b), k->port_b, (int)sizeof(struct calico_ct_key));
 }
 
+/* ct_shard returns the conntrack shard that the key belongs to. It must match
+ * conntrack.ShardOf() in userspace - it is a FNV-1a hash over the 32bit words
+ * of the key.
+ */
+static CALI_BPF_INLINE __u32 ct_shard(struct cali_tc_ctx *ctx, struct calico_ct_key *k)
+{
+	__u32 shards = CT_SHARDS;
+
+	if (shards <= 1) {
+		return 0;
+	}
+
+	__u32 *w = (__u32 *)k;
+	__u32 h = 2166136261;
+	int i;
+
+#pragma clang loop unroll(full)
+	for (i = 0; i < sizeof(*k) / sizeof(__u32); i++) {
+		h ^= w[i];
+		h *= 16777619;
+	}
+
+	return h % shards;
+}
+
+static CALI_BPF_INLINE struct calico_ct_value *ct_lookup_elem(struct cali_tc_ctx *ctx,
+							       struct calico_ct_key *k)
+{
+	switch (ct_shard(ctx, k)) {
+	case 1: return cali_ct_1_lookup_elem(k);
+	case 2: return cali_ct_2_lookup_elem(k);
+	case 3: return cali_ct_3_lookup_elem(k);
+	case 4: return cali_ct_4_lookup_elem(k);
+	case 5: return cali_ct_5_lookup_elem(k);
+	case 6: return cali_ct_6_lookup_elem(k);
+	case 7: return cali_ct_7_lookup_elem(k);
+	}
+
+	return cali_ct_lookup_elem(k);
+}
+
+static CALI_BPF_INLINE int ct_update_elem(struct cali_tc_ctx *ctx, struct calico_ct_key *k,
+					  struct calico_ct_value *v, __u64 flags)
+{
+	switch (ct_shard(ctx, k)) {
+	case 1: return cali_ct_1_update_elem(k, v, flags);
+	case 2: return cali_ct_2_update_elem(k, v, flags);
+	case 3: return cali_ct_3_update_elem(k, v, flags);
+	case 4: return cali_ct_4_update_elem(k, v, flags);
+	case 5: return cali_ct_5_update_elem(k, v, flags);
+	case 6: return cali_ct_6_update_elem(k, v, flags);
+	case 7: return cali_ct_7_update_elem(k, v, flags);
+	}
+
+	return cali_ct_update_elem(k, v, flags);
+}
+
+static CALI_BPF_INLINE int ct_delete_elem(struct cali_tc_ctx *ctx, struct calico_ct_key *k)
+{
+	switch (ct_shard(ctx, k)) {
+	case 1: return cali_ct_1_delete_elem(k);
+	case 2: return cali_ct_2_delete_elem(k);
+	case 3: return cali_ct_3_delete_elem(k);
+	case 4: return cali_ct_4_delete_elem(k);
+	case 5: return cali_ct_5_delete_elem(k);
+	case 6: return cali_ct_6_delete_elem(k);
+	case 7: return cali_ct_7_delete_elem(k);
+	}
+
+	return cali_ct_delete_elem(k);
+}
+
 static CALI_BPF_INLINE int calico_ct_v4_create_tracking(struct cali_tc_ctx *ctx,
 							struct ct_create_ctx *ct_ctx,
 							struct calico_ct_key *k)
@@ -102,7 +174,7 @@ static CALI_BPF_INLINE int calico_ct_v4_create_tracking(struct cali_tc_ctx *ctx,
 				"from another endpoint, doing lookup\n");
 		bool srcLTDest = src_lt_dest(&ct_ctx->src, &ct_ctx->dst, sport, dport);
 		fill_ct_key(k, srcLTDest, ct_ctx->proto, &ct_ctx->src, &ct_ctx->dst, sport, dport);
-		struct calico_ct_value *ct_value = cali_ct_lookup_elem(k);
+		struct calico_ct_value *ct_value = ct_lookup_elem(ctx, k);
 		if (!ct_value) {
 			CALI_VERB("CT Packet marked as from workload but got a conntrack miss!\n");
 			goto create;
@@ -227,7 +299,7 @@ create:
 		}
 	}
 
-	err = cali_ct_update_elem(k, &ct_value, BPF_NOEXIST);
+	err = ct_update_elem(ctx, k, &ct_value, BPF_NOEXIST);
 
 	if (CALI_F_HEP && err == -17 /* EEXIST */) {
 		int i;
@@ -248,7 +320,7 @@ create:
 
 			fill_ct_key(k, src_lt_dst, ct_ctx->proto, &ct_ctx->src, &ct_ctx->dst, sport, dport);
 
-			if (!(err = cali_ct_update_elem(k, &ct_value, BPF_NOEXIST))) {
+			if (!(err = ct_update_elem(ctx, k, &ct_value, BPF_NOEXIST))) {
 				ct_ctx->sport = sport;
 				break;
 			}
@@ -309,7 +381,7 @@ static CALI_BPF_INLINE int calico_ct_create_nat_fwd(struct cali_tc_ctx *ctx,
 	if (ct_ctx->orig_sport != ct_ctx->sport) {
 		ct_value.nat_sport = ct_ctx->sport;
 	}
-	int err = cali_ct_update_elem(k, &ct_value, 0);
+	int err = ct_update_elem(ctx, k, &ct_value, 0);
 	CALI_VERB("CT-%d Create result: %d.\n", ctx->state->ip_proto, err);
 	return err;
 }
@@ -655,7 +727,7 @@ static CALI_BPF_INLINE struct calico_ct_result calico_ct_lookup(struct cali_tc_c
 	bool srcLTDest = src_lt_dest(&ct_ctx->src, &ct_ctx->dst, ct_ctx->sport, ct_ctx->dport);
 	fill_ct_key(&k, srcLTDest, ct_ctx->proto, &ct_ctx->src, &ct_ctx->dst, ct_ctx->sport, ct_ctx->dport);
 
-	struct calico_ct_value *v = cali_ct_lookup_elem(&k);
+	struct calico_ct_value *v = ct_lookup_elem(ctx, &k);
 	if (!v) {
 		if (syn) {
 			// SYN packet (new flow); send it to policy.
@@ -712,15 +784,15 @@ static CALI_BPF_INLINE struct calico_ct_result calico_ct_lookup(struct cali_tc_c
 		// This is a forward NAT entry; since we do the bookkeeping on the
 		// reverse entry, we need to do a second lookup.
 		CALI_CT_DEBUG("Hit! NAT FWD entry, doing secondary lookup.\n");
-		tracking_v = cali_ct_lookup_elem(&v->nat_rev_key);
+		tracking_v = ct_lookup_elem(ctx, &v->nat_rev_key);
 		if (!tracking_v) {
 			CALI_CT_DEBUG("Miss when looking for secondary entry.\n");
 			goto out_lookup_fail;
 		}
 		if (tcp_recycled(syn, tracking_v)) {
 			CALI_CT_DEBUG("TCP SYN recycles entry, NEW flow.\n");
-			cali_ct_delete_elem(&k);
-			cali_ct_delete_elem(&v->nat_rev_key);
+			ct_delete_elem(ctx, &k);
+			ct_delete_elem(ctx, &v->nat_rev_key);
 			goto out_lookup_fail;
 		}
 
@@ -858,7 +930,7 @@ static CALI_BPF_INLINE struct calico_ct_result calico_ct_lookup(struct cali_tc_c
 		CALI_CT_DEBUG("Hit! NORMAL entry.\n");
 		if (tcp_recycled(syn, v)) {
 			CALI_CT_DEBUG("TCP SYN recycles entry, NEW flow.\n");
-			cali_ct_delete_elem(&k);
+			ct_delete_elem(ctx, &k);
 			goto out_lookup_fail;
 		}
 		CALI_CT_VERB("Created: %llu.\n", v->created);
@@ -28,7 +28,7 @@ enum cali_ct_type {
 #define CALI_CT_FLAG_DSR_FWD	0x02 /* marks entry into the tunnel on the fwd node when dsr */
 #define CALI_CT_FLAG_NP_FWD	0x04 /* marks entry into the tunnel on the fwd node */
 #define CALI_CT_FLAG_SKIP_FIB	0x08 /* marks traffic that should pass through host IP stack */
-#define CALI_CT_FLAG_RES_0x10	0x10 /* reserved */
+#define CALI_CT_FLAG_PROXY_PROTO	0x10 /* marks connections to a service with PROXY protocol */
 #define CALI_CT_FLAG_RES_0x20	0x20 /* reserved */
 #define CALI_CT_FLAG_EXT_LOCAL	0x40 /* marks traffic from external client to a local service */
 #define CALI_CT_FLAG_VIA_NAT_IF	0x80 /* marks connection first seen on the service veth */
@@ -153,14 +153,38 @@ struct ct_create_ctx {
 	bool allow_return;
 };
 
+#define CALI_CT_MAP(name, fname, ver)						\
+	CALI_MAP_NAMED(name, fname, ver,					\
+		BPF_MAP_TYPE_HASH,						\
+		struct calico_ct_key, struct calico_ct_value,			\
+		512000, BPF_F_NO_PREALLOC)
+
+/* The conntrack table can be split into up to CT_MAX_SHARDS maps (shards) to
+ * reduce contention on nodes with very many concurrent flows. The first shard
+ * is the original conntrack map. Userspace sizes the unused shards to a single
+ * entry.
+ */
+#define CT_MAX_SHARDS	8
+
 #ifdef IPVER6
-CALI_MAP_NAMED(cali_v6_ct, cali_ct, 3,
+CALI_CT_MAP(cali_v6_ct, cali_ct, 3)
+CALI_CT_MAP(cali_v6_ct3_1, cali_ct_1,)
+CALI_CT_MAP(cali_v6_ct3_2, cali_ct_2,)
+CALI_CT_MAP(cali_v6_ct3_3, cali_ct_3,)
+CALI_CT_MAP(cali_v6_ct3_4, cali_ct_4,)
+CALI_CT_MAP(cali_v6_ct3_5, cali_ct_5,)
+CALI_CT_MAP(cali_v6_ct3_6, cali_ct_6,)
+CALI_CT_MAP(cali_v6_ct3_7, cali_ct_7,)
 #else
-CALI_MAP_NAMED(cali_v4_ct, cali_ct, 3,
+CALI_CT_MAP(cali_v4_ct, cali_ct, 3)
+CALI_CT_MAP(cali_v4_ct3_1, cali_ct_1,)
+CALI_CT_MAP(cali_v4_ct3_2, cali_ct_2,)
+CALI_CT_MAP(cali_v4_ct3_3, cali_ct_3,)
+CALI_CT_MAP(cali_v4_ct3_4, cali_ct_4,)
+CALI_CT_MAP(cali_v4_ct3_5, cali_ct_5,)
+CALI_CT_MAP(cali_v4_ct3_6, cali_ct_6,)
+CALI_CT_MAP(cali_v4_ct3_7, cali_ct_7,)
 #endif
-		BPF_MAP_TYPE_HASH,
-		struct calico_ct_key, struct calico_ct_value,
-		512000, BPF_F_NO_PREALLOC)
 
 enum calico_ct_result_type {
 	/* CALI_CT_NEW means that the packet is not part of a known conntrack flow.
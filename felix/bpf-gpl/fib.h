@@ -69,6 +69,15 @@ static CALI_BPF_INLINE int forward_or_drop(struct cali_tc_ctx *ctx)
 		goto deny;
 	}
 
+#if defined(IPVER6) && HAS_NAT64_PROG
+	if (nat64_enabled() && nat64_dst_in_prefix(ctx, &state->ip_dst)) {
+		CALI_DEBUG("NAT64: destination in the NAT64 prefix\n");
+		CALI_JUMP_TO(ctx, PROG_INDEX_NAT64);
+		CALI_DEBUG("Failed to jump to NAT64 program\n");
+		goto deny;
+	}
+#endif
+
 	if (rc == CALI_RES_REDIR_BACK) {
 		int redir_flags = 0;
 		if  (CALI_F_FROM_HOST) {
@@ -145,6 +154,14 @@ skip_redir_ifindex:
 		goto skip_fib;
 	}
 
+	/* Traffic that may need to be encrypted by WireGuard must be routed by
+	 * the kernel so that the WireGuard routing rule applies.
+	 */
+	if (ctx->fwd.mark == CALI_SKB_MARK_BYPASS_WG) {
+		CALI_DEBUG("Skipping FIB for WireGuard routed traffic.\n");
+		goto cancel_fib;
+	}
+
 	// Try a short-circuit FIB lookup.
 	if (fwd_fib(&ctx->fwd)) {
 		/* Revalidate the access to the packet */
@@ -0,0 +1,107 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_FRAGS_H__
+#define __CALI_FRAGS_H__
+
+#include "bpf.h"
+#include "types.h"
+
+/* Only the first fragment of an IP datagram carries the L4 header. To be able
+ * to conntrack and NAT the rest of the fragments consistently, we remember the
+ * ports of the first fragment keyed by the fields that identify the datagram.
+ */
+struct frags4_key {
+	__be32 src;
+	__be32 dst;
+	__u16 id;
+	__u8 proto;
+	__u8 __pad;
+};
+
+struct frags4_value {
+	__u16 sport;
+	__u16 dport;
+};
+
+CALI_MAP_V1(cali_v4_frags, BPF_MAP_TYPE_LRU_HASH, struct frags4_key, struct frags4_value, 10000, 0)
+
+#define ip_more_frags(ip) ((ip)->frag_off & bpf_htons(0x2000))
+#define ip_is_frag(ip) ((ip)->frag_off & bpf_htons(0x3fff))
+
+static CALI_BPF_INLINE bool frags4_is_tracked_proto(struct cali_tc_ctx *ctx)
+{
+	return ctx->state->ip_proto == IPPROTO_TCP || ctx->state->ip_proto == IPPROTO_UDP;
+}
+
+static CALI_BPF_INLINE void frags4_key_fill(struct cali_tc_ctx *ctx, struct frags4_key *k)
+{
+	k->src = ip_hdr(ctx)->saddr;
+	k->dst = ip_hdr(ctx)->daddr;
+	k->id = ip_hdr(ctx)->id;
+	k->proto = ip_hdr(ctx)->protocol;
+}
+
+/* frags4_record remembers the ports of the first fragment of a datagram. */
+static CALI_BPF_INLINE void frags4_record(struct cali_tc_ctx *ctx)
+{
+	if (!frags4_is_tracked_proto(ctx)) {
+		return;
+	}
+
+	struct frags4_key k = {};
+	struct frags4_value v = {
+		.sport = ctx->state->sport,
+		.dport = ctx->state->dport,
+	};
+
+	frags4_key_fill(ctx, &k);
+
+	if (cali_v4_frags_update_elem(&k, &v, BPF_ANY)) {
+		CALI_DEBUG("Failed to record first fragment id=%d\n", bpf_ntohs(k.id));
+	} else {
+		CALI_DEBUG("Recorded first fragment id=%d\n", bpf_ntohs(k.id));
+	}
+}
+
+/* frags4_fill_ports fills in the ports of a non-first fragment from the first
+ * fragment of the same datagram. Since there is no L4 header, it also makes up
+ * one in the scratch area so that conntrack and NAT see the same ports. Returns
+ * non-zero if the first fragment was not seen.
+ */
+static CALI_BPF_INLINE int frags4_fill_ports(struct cali_tc_ctx *ctx)
+{
+	__builtin_memset(ctx->scratch->l4, 0, sizeof(ctx->scratch->l4));
+	ctx->state->sport = ctx->state->dport = ctx->state->pre_nat_dport = 0;
+
+	if (!frags4_is_tracked_proto(ctx)) {
+		CALI_DEBUG("Non-first fragment, proto %d has no ports\n", ctx->state->ip_proto);
+		return 0;
+	}
+
+	struct frags4_key k = {};
+
+	frags4_key_fill(ctx, &k);
+
+	struct frags4_value *v = cali_v4_frags_lookup_elem(&k);
+	if (!v) {
+		CALI_DEBUG("Non-first fragment id=%d, first fragment not seen\n", bpf_ntohs(k.id));
+		return -1;
+	}
+
+	ctx->state->sport = v->sport;
+	ctx->state->dport = v->dport;
+	ctx->state->pre_nat_dport = v->dport;
+
+	/* Source and destination ports are at the same offset in TCP and UDP headers. */
+	udp_hdr(ctx)->source = bpf_htons(v->sport);
+	udp_hdr(ctx)->dest = bpf_htons(v->dport);
+
+	CALI_DEBUG("Non-first fragment id=%d; ports: s=%d d=%d\n",
+			bpf_ntohs(k.id), ctx->state->sport, ctx->state->dport);
+
+	return 0;
+}
+
+#endif /* __CALI_FRAGS_H__ */
@@ -19,9 +19,11 @@ struct name {				\
 	ip_t host_tunnel_ip;		\
 	__be32 flags;			\
 	__be16 wg_port;			\
-	__be16 __pad;			\
+	__u16 ct_shards;		\
 	__u32 natin_idx;		\
 	__u32 natout_idx;		\
+	ipv6_addr_t nat64_prefix;	\
+	__be32 nat64_ip;		\
 	__u8 iface_name[16];		\
 	__u32 log_filter_jmp;		\
 	__u32 jumps[40];		\
@@ -52,6 +54,9 @@ enum cali_globals_flags {
 	CALI_GLOBALS_RESERVED7			= 0x00000040,
 	CALI_GLOBALS_NO_DSR_CIDRS		= 0x00000080,
 	CALI_GLOBALS_LO_UDP_ONLY		= 0x00000100,
+	CALI_GLOBALS_WIREGUARD_ENABLED		= 0x00000200,
+	CALI_GLOBALS_DROP_FRAGMENTS		= 0x00000400,
+	CALI_GLOBALS_NAT64_ENABLED		= 0x00000800,
 };
 
 struct cali_ctlb_globals {
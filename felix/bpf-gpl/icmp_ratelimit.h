@@ -0,0 +1,62 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_ICMP_RATELIMIT_H__
+#define __CALI_ICMP_RATELIMIT_H__
+
+#include "bpf.h"
+
+/* Token bucket limiting the ICMP errors that the TC programs generate. The
+ * limits mirror the Linux defaults of net.ipv4.icmp_msgs_per_sec and
+ * net.ipv4.icmp_msgs_burst, but they are applied per CPU.
+ */
+#define ICMP_RL_MSGS_PER_SEC	1000
+#define ICMP_RL_BURST		50
+#define ICMP_RL_NS_PER_TOKEN	(1000000000ull / ICMP_RL_MSGS_PER_SEC)
+
+struct icmp_rl_state {
+	__u64 last_ns;
+	__u64 tokens;
+};
+
+CALI_MAP_V1(cali_icmp_rl, BPF_MAP_TYPE_PERCPU_ARRAY, __u32, struct icmp_rl_state, 1, 0)
+
+/* icmp_rate_limited consumes a token and returns true if there was none left,
+ * in which case the ICMP error must not be sent.
+ */
+static CALI_BPF_INLINE bool icmp_rate_limited(void)
+{
+	__u32 key = 0;
+	struct icmp_rl_state *rl = cali_icmp_rl_lookup_elem(&key);
+
+	if (!rl) {
+		/* Should not happen, do not prevent PMTUD if it does. */
+		return false;
+	}
+
+	__u64 now = bpf_ktime_get_ns();
+	__u64 elapsed = now - rl->last_ns;
+
+	if (elapsed >= ICMP_RL_NS_PER_TOKEN) {
+		__u64 refill = elapsed / ICMP_RL_NS_PER_TOKEN;
+
+		if (rl->tokens + refill >= ICMP_RL_BURST) {
+			rl->tokens = ICMP_RL_BURST;
+			rl->last_ns = now;
+		} else {
+			rl->tokens += refill;
+			/* Keep the remainder for the next refill. */
+			rl->last_ns += refill * ICMP_RL_NS_PER_TOKEN;
+		}
+	}
+
+	if (rl->tokens == 0) {
+		return true;
+	}
+
+	rl->tokens--;
+	return false;
+}
+
+#endif /* __CALI_ICMP_RATELIMIT_H__ */
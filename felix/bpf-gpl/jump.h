@@ -68,6 +68,7 @@ enum cali_jump_index {
 	PROG_INDEX_HOST_CT_CONFLICT,
 	PROG_INDEX_ICMP_INNER_NAT,
 	PROG_INDEX_NEW_FLOW,
+	PROG_INDEX_NAT64,
 
 	PROG_INDEX_MAIN_DEBUG,
 	PROG_INDEX_POLICY_DEBUG,
@@ -77,6 +78,7 @@ enum cali_jump_index {
 	PROG_INDEX_HOST_CT_CONFLICT_DEBUG,
 	PROG_INDEX_ICMP_INNER_NAT_DEBUG,
 	PROG_INDEX_NEW_FLOW_DEBUG,
+	PROG_INDEX_NAT64_DEBUG,
 };
 
 #if CALI_F_XDP
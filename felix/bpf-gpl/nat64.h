@@ -0,0 +1,533 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_NAT64_H__
+#define __CALI_NAT64_H__
+
+#include "bpf.h"
+#include "types.h"
+#include "conntrack.h"
+
+/* Stateful NAT64 lets IPv6-only workloads reach IPv4-only destinations, which
+ * they address by an IPv6 address from the NAT64 prefix (64:ff9b::/96 by
+ * default) with the IPv4 address in its low 32 bits.
+ *
+ * Packets from a workload to the prefix that are allowed by policy are
+ * translated to IPv4 and source NATed to the host's IPv4 address and a port
+ * from the PSNAT range by the IPv6 program on the workload. The IPv4 program on
+ * the host endpoint translates the replies back and forwards them to the
+ * workload. Conntrack and policy of the flow stay in the IPv6 programs. Felix
+ * removes the NAT64 state of a flow once its IPv6 conntrack entry is gone.
+ */
+
+#ifdef IPVER6
+#define HAS_NAT64_PROG	CALI_F_FROM_WEP
+#else
+#define HAS_NAT64_PROG	(CALI_F_MAIN && CALI_F_INGRESS)
+#endif
+
+/* Both maps are shared by the IPv4 and IPv6 programs. Ports are in host byte
+ * order, for ICMP echo the identifier is the source port and the destination
+ * port is zero. The protocol is always the IPv4 one.
+ */
+struct nat64_fwd_key {
+	ipv6_addr_t src;
+	__be32 dst;
+	__u16 sport;
+	__u16 dport;
+	__u8 proto;
+	__u8 __pad[3];
+};
+
+struct nat64_fwd_value {
+	__u16 nat_port;
+	__u16 __pad;
+};
+
+CALI_MAP_V1(cali_nat64_fwd, BPF_MAP_TYPE_LRU_HASH, struct nat64_fwd_key, struct nat64_fwd_value, 65536, 0)
+
+struct nat64_rev_key {
+	__be32 addr;
+	__u16 port;
+	__u16 nat_port;
+	__u8 proto;
+	__u8 __pad[3];
+};
+
+struct nat64_rev_value {
+	ipv6_addr_t addr;
+	__u16 port;
+	__u16 __pad;
+};
+
+CALI_MAP_V1(cali_nat64_rev, BPF_MAP_TYPE_LRU_HASH, struct nat64_rev_key, struct nat64_rev_value, 65536, 0)
+
+#define NAT64_TCP_CSUM_OFF	offsetof(struct tcphdr, check)
+#define NAT64_UDP_CSUM_OFF	offsetof(struct udphdr, check)
+#define NAT64_ICMP_CSUM_OFF	2
+#define NAT64_ICMP_ID_OFF	4
+
+#define nat64_enabled()	(GLOBAL_FLAGS & CALI_GLOBALS_NAT64_ENABLED)
+
+/* nat64_csum_off returns the offset of the L4 checksum relative to the L4
+ * header or -1 if the protocol is not translated.
+ */
+static CALI_BPF_INLINE int nat64_csum_off(__u8 proto)
+{
+	switch (proto) {
+	case IPPROTO_TCP:
+		return NAT64_TCP_CSUM_OFF;
+	case IPPROTO_UDP:
+		return NAT64_UDP_CSUM_OFF;
+	case IPPROTO_ICMP:
+	case IPPROTO_ICMPV6:
+		return NAT64_ICMP_CSUM_OFF;
+	}
+
+	return -1;
+}
+
+/* nat64_set_eth_proto fixes up the ethernet header after bpf_skb_change_proto(). */
+static CALI_BPF_INLINE int nat64_set_eth_proto(struct cali_tc_ctx *ctx, __be16 proto)
+{
+	if (CALI_F_L3) {
+		return 0;
+	}
+
+	return bpf_skb_store_bytes(ctx->skb, offsetof(struct ethhdr, h_proto), &proto, sizeof(proto), 0);
+}
+
+/* nat64_redirect forwards a translated packet by a FIB lookup of the new
+ * family. If that does not work out, the packet goes to the host stack.
+ */
+static CALI_BPF_INLINE int nat64_redirect(struct cali_tc_ctx *ctx, bool to_v6, __be32 *src, __be32 *dst, __u8 proto)
+{
+	struct bpf_fib_lookup *params = fib_params(ctx);
+	int rc;
+
+	__builtin_memset(params, 0, sizeof(*params));
+	params->ifindex = ctx->skb->ingress_ifindex;
+	params->l4_protocol = proto;
+
+	if (to_v6) {
+		params->family = 10; /* AF_INET6 */
+		__builtin_memcpy(params->ipv6_src, src, 16);
+		__builtin_memcpy(params->ipv6_dst, dst, 16);
+	} else {
+		params->family = 2; /* AF_INET */
+		params->ipv4_src = src[0];
+		params->ipv4_dst = dst[0];
+	}
+
+	rc = bpf_fib_lookup(ctx->skb, params, sizeof(struct bpf_fib_lookup), 0);
+	switch (rc) {
+	case 0:
+		if (bpf_skb_store_bytes(ctx->skb, offsetof(struct ethhdr, h_dest), params->dmac, ETH_ALEN, 0) ||
+				bpf_skb_store_bytes(ctx->skb, offsetof(struct ethhdr, h_source), params->smac, ETH_ALEN, 0)) {
+			return TC_ACT_SHOT;
+		}
+		CALI_DEBUG("NAT64: FIB hit, redirecting to iface %d.\n", params->ifindex);
+		return bpf_redirect(params->ifindex, 0);
+#ifdef BPF_CORE_SUPPORTED
+	case BPF_FIB_LKUP_RET_NO_NEIGH:
+		if (bpf_core_enum_value_exists(enum bpf_func_id, BPF_FUNC_redirect_neigh)) {
+			struct bpf_redir_neigh nh_params = {};
+
+			nh_params.nh_family = params->family;
+			if (to_v6) {
+				__builtin_memcpy(nh_params.ipv6_nh, params->ipv6_dst, sizeof(nh_params.ipv6_nh));
+			} else {
+				nh_params.ipv4_nh = params->ipv4_dst;
+			}
+
+			CALI_DEBUG("NAT64: FIB hit, no neigh, redirecting to iface %d.\n", params->ifindex);
+			return bpf_redirect_neigh(params->ifindex, &nh_params, sizeof(nh_params), 0);
+		}
+		break;
+#endif
+	}
+
+	CALI_DEBUG("NAT64: FIB lookup failed: %d, passing to the host stack.\n", rc);
+	return TC_ACT_UNSPEC;
+}
+
+#ifdef IPVER6
+
+static CALI_BPF_INLINE bool nat64_dst_in_prefix(struct cali_tc_ctx *ctx, ipv6_addr_t *ip)
+{
+	return ip->a == NAT64_PREFIX.a && ip->b == NAT64_PREFIX.b && ip->c == NAT64_PREFIX.c;
+}
+
+/* nat64_alloc_port picks a free port of the PSNAT range for the flow and
+ * records the flow in both maps.
+ */
+static CALI_BPF_INLINE int nat64_alloc_port(struct cali_tc_ctx *ctx, struct nat64_fwd_key *fk, __u16 *port)
+{
+	struct nat64_rev_key rk = {
+		.addr = fk->dst,
+		.port = fk->dport,
+		.proto = fk->proto,
+	};
+	struct nat64_rev_value rv = {
+		.addr = fk->src,
+		.port = fk->sport,
+	};
+	int i;
+
+	for (i = 0; i < PSNAT_RETRIES; i++) {
+		rk.nat_port = psnat_get_port(ctx);
+		if (cali_nat64_rev_update_elem(&rk, &rv, BPF_NOEXIST)) {
+			continue;
+		}
+
+		struct nat64_fwd_value fv = {
+			.nat_port = rk.nat_port,
+		};
+
+		if (cali_nat64_fwd_update_elem(fk, &fv, BPF_ANY)) {
+			cali_nat64_rev_delete_elem(&rk);
+			return -1;
+		}
+
+		*port = rk.nat_port;
+		return 0;
+	}
+
+	CALI_DEBUG("NAT64: no free port after %d attempts\n", PSNAT_RETRIES);
+	return -1;
+}
+
+/* nat64_6to4 translates an IPv6 packet from a workload to IPv4 with the
+ * host's IPv4 address as the source. Returns non-zero if the packet cannot be
+ * translated.
+ */
+static CALI_BPF_INLINE int nat64_6to4(struct cali_tc_ctx *ctx, __be32 *saddr, __be32 *daddr, __u8 *ip_proto)
+{
+	struct cali_tc_state *state = ctx->state;
+	int l3_off = skb_iphdr_offset(ctx);
+	int l4_off = l3_off + sizeof(struct ipv6hdr);
+	struct ipv6hdr ip6;
+	__u8 proto = state->ip_proto;
+	__be16 sport, nat_port;
+	int sport_off = 0;
+	int csum_off;
+	__wsum csum;
+
+	if (ctx->ipheader_len != sizeof(struct ipv6hdr)) {
+		CALI_DEBUG("NAT64: IPv6 extension headers are not translated\n");
+		return -1;
+	}
+
+	if (bpf_load_bytes(ctx, l3_off, &ip6, sizeof(ip6))) {
+		return -1;
+	}
+
+	if (ip6.hop_limit <= 1) {
+		CALI_DEBUG("NAT64: hop limit exceeded\n");
+		return -1;
+	}
+
+	csum_off = nat64_csum_off(proto);
+	if (csum_off < 0) {
+		CALI_DEBUG("NAT64: proto %d is not translated\n", proto);
+		return -1;
+	}
+	csum_off += l4_off;
+
+	if (proto == IPPROTO_ICMPV6) {
+		if (state->icmp_type != ICMPV6_ECHO_REQUEST || state->icmp_code != 0) {
+			CALI_DEBUG("NAT64: ICMPv6 type %d is not translated\n", state->icmp_type);
+			return -1;
+		}
+		sport_off = NAT64_ICMP_ID_OFF;
+	}
+
+	if (bpf_load_bytes(ctx, l4_off + sport_off, &sport, sizeof(sport))) {
+		return -1;
+	}
+
+	struct nat64_fwd_key fk = {
+		.src = state->ip_src,
+		.dst = state->ip_dst.d,
+		.sport = bpf_ntohs(sport),
+		.dport = proto == IPPROTO_ICMPV6 ? 0 : state->dport,
+		.proto = proto == IPPROTO_ICMPV6 ? IPPROTO_ICMP : proto,
+	};
+
+	struct nat64_fwd_value *fv = cali_nat64_fwd_lookup_elem(&fk);
+	if (fv) {
+		nat_port = bpf_htons(fv->nat_port);
+	} else {
+		__u16 port;
+
+		if (nat64_alloc_port(ctx, &fk, &port)) {
+			return -1;
+		}
+		nat_port = bpf_htons(port);
+	}
+
+	CALI_DEBUG("NAT64: %x:%d -> nat port %d\n", bpf_ntohl(fk.dst), fk.dport, bpf_ntohs(nat_port));
+
+	saddr[0] = NAT64_IP;
+	daddr[0] = fk.dst;
+
+	/* Fix up the L4 checksum while the packet is still IPv6. ICMPv6 covers
+	 * the pseudo header, ICMP does not. TCP and UDP cover the addresses of the
+	 * pseudo header, the length and the protocol sum up the same in both.
+	 */
+	if (proto == IPPROTO_ICMPV6) {
+		__be32 pseudo[10];
+
+		__builtin_memcpy(pseudo, &ip6.saddr, 32);
+		pseudo[8] = bpf_htonl(bpf_ntohs(ip6.payload_len));
+		pseudo[9] = bpf_htonl(IPPROTO_ICMPV6);
+		csum = bpf_csum_diff(pseudo, sizeof(pseudo), 0, 0, 0);
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, 0, csum, BPF_F_PSEUDO_HDR)) {
+			return -1;
+		}
+
+		__u8 type = ICMP_ECHO;
+
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, bpf_htons(ICMPV6_ECHO_REQUEST << 8),
+					bpf_htons(ICMP_ECHO << 8), 2) ||
+				bpf_skb_store_bytes(ctx->skb, l4_off, &type, sizeof(type), 0)) {
+			return -1;
+		}
+	} else {
+		__be32 addrs[2] = { saddr[0], daddr[0] };
+		int flags = BPF_F_PSEUDO_HDR;
+
+		if (proto == IPPROTO_UDP) {
+			flags |= BPF_F_MARK_MANGLED_0;
+		}
+
+		csum = bpf_csum_diff((__u32 *)&ip6.saddr, 32, addrs, sizeof(addrs), 0);
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, 0, csum, flags)) {
+			return -1;
+		}
+	}
+
+	if (bpf_l4_csum_replace(ctx->skb, csum_off, sport, nat_port, 2) ||
+			bpf_skb_store_bytes(ctx->skb, l4_off + sport_off, &nat_port, sizeof(nat_port), 0)) {
+		return -1;
+	}
+
+	if (bpf_skb_change_proto(ctx->skb, bpf_htons(ETH_P_IP), 0)) {
+		CALI_DEBUG("NAT64: failed to change proto to IPv4\n");
+		return -1;
+	}
+
+	struct iphdr ip4 = {
+		.version = 4,
+		.ihl = 5,
+		.tos = (ip6.priority << 4) | (ip6.flow_lbl[0] >> 4),
+		.tot_len = bpf_htons(bpf_ntohs(ip6.payload_len) + sizeof(struct iphdr)),
+		.frag_off = bpf_htons(0x4000), /* DF */
+		.ttl = ip6.hop_limit - 1,
+		.protocol = fk.proto,
+		.saddr = saddr[0],
+		.daddr = daddr[0],
+	};
+
+	if (bpf_skb_store_bytes(ctx->skb, l3_off, &ip4, sizeof(ip4), 0)) {
+		return -1;
+	}
+
+	csum = bpf_csum_diff(0, 0, (__u32 *)&ip4, sizeof(ip4), 0);
+	if (bpf_l3_csum_replace(ctx->skb, l3_off + offsetof(struct iphdr, check), 0, csum, 0)) {
+		return -1;
+	}
+
+	*ip_proto = fk.proto;
+
+	return nat64_set_eth_proto(ctx, bpf_htons(ETH_P_IP));
+}
+
+#else /* IPVER6 */
+
+/* nat64_rev_key_fill fills in the key of the reverse map for a reply from an
+ * IPv4 destination. Returns non-zero if the packet cannot be a reply.
+ */
+static CALI_BPF_INLINE int nat64_rev_key_fill(struct cali_tc_ctx *ctx, struct nat64_rev_key *rk)
+{
+	struct cali_tc_state *state = ctx->state;
+
+	rk->addr = state->ip_src;
+	rk->proto = state->ip_proto;
+
+	switch (state->ip_proto) {
+	case IPPROTO_TCP:
+	case IPPROTO_UDP:
+		rk->port = state->sport;
+		rk->nat_port = state->dport;
+		return 0;
+	case IPPROTO_ICMP:
+		if (state->icmp_type != ICMP_ECHOREPLY || state->icmp_code != 0) {
+			return -1;
+		}
+		rk->port = 0;
+		rk->nat_port = bpf_ntohs(icmp_hdr(ctx)->un.echo.id);
+		return 0;
+	}
+
+	return -1;
+}
+
+/* nat64_is_reply returns true if the packet is a reply to a translated flow. */
+static CALI_BPF_INLINE bool nat64_is_reply(struct cali_tc_ctx *ctx)
+{
+	struct nat64_rev_key rk = {};
+
+	if (ctx->state->ip_dst != NAT64_IP || ip_is_frag(ip_hdr(ctx))) {
+		return false;
+	}
+
+	if (nat64_rev_key_fill(ctx, &rk)) {
+		return false;
+	}
+
+	return cali_nat64_rev_lookup_elem(&rk) != NULL;
+}
+
+/* nat64_4to6 translates a reply from an IPv4 destination back to IPv6 and the
+ * workload's address. Returns non-zero if the packet cannot be translated.
+ */
+static CALI_BPF_INLINE int nat64_4to6(struct cali_tc_ctx *ctx, __be32 *saddr, __be32 *daddr, __u8 *ip_proto)
+{
+	struct cali_tc_state *state = ctx->state;
+	int l3_off = skb_iphdr_offset(ctx);
+	int l4_off = l3_off + sizeof(struct iphdr);
+	struct nat64_rev_key rk = {};
+	struct iphdr ip4;
+	__u8 proto = state->ip_proto;
+	int dport_off = offsetof(struct udphdr, dest);
+	__be16 dport, port;
+	int csum_off;
+	__wsum csum;
+
+	if (bpf_load_bytes(ctx, l3_off, &ip4, sizeof(ip4))) {
+		return -1;
+	}
+
+	if (ip4.ihl != 5) {
+		CALI_DEBUG("NAT64: IP options are not translated\n");
+		return -1;
+	}
+
+	if (ip4.ttl <= 1) {
+		CALI_DEBUG("NAT64: TTL exceeded\n");
+		return -1;
+	}
+
+	if (nat64_rev_key_fill(ctx, &rk)) {
+		return -1;
+	}
+
+	struct nat64_rev_value *rv = cali_nat64_rev_lookup_elem(&rk);
+	if (!rv) {
+		CALI_DEBUG("NAT64: no state for reply\n");
+		return -1;
+	}
+
+	csum_off = nat64_csum_off(proto);
+	if (csum_off < 0) {
+		return -1;
+	}
+	csum_off += l4_off;
+
+	if (proto == IPPROTO_ICMP) {
+		dport_off = NAT64_ICMP_ID_OFF;
+	}
+
+	if (bpf_load_bytes(ctx, l4_off + dport_off, &dport, sizeof(dport))) {
+		return -1;
+	}
+
+	if (proto == IPPROTO_UDP) {
+		__be16 udp_csum;
+
+		if (bpf_load_bytes(ctx, csum_off, &udp_csum, sizeof(udp_csum))) {
+			return -1;
+		}
+		if (udp_csum == 0) {
+			/* The UDP checksum is mandatory in IPv6. */
+			CALI_DEBUG("NAT64: UDP without checksum is not translated\n");
+			return -1;
+		}
+	}
+
+	port = bpf_htons(rv->port);
+
+	saddr[0] = NAT64_PREFIX.a;
+	saddr[1] = NAT64_PREFIX.b;
+	saddr[2] = NAT64_PREFIX.c;
+	saddr[3] = ip4.saddr;
+	ipv6_addr_t_to_be32_4_ip(daddr, &rv->addr);
+
+	CALI_DEBUG("NAT64: nat port %d -> %d\n", bpf_ntohs(dport), rv->port);
+
+	if (proto == IPPROTO_ICMP) {
+		__be32 pseudo[10];
+
+		__builtin_memcpy(pseudo, saddr, 16);
+		__builtin_memcpy(pseudo + 4, daddr, 16);
+		pseudo[8] = bpf_htonl(bpf_ntohs(ip4.tot_len) - sizeof(struct iphdr));
+		pseudo[9] = bpf_htonl(IPPROTO_ICMPV6);
+		csum = bpf_csum_diff(0, 0, pseudo, sizeof(pseudo), 0);
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, 0, csum, BPF_F_PSEUDO_HDR)) {
+			return -1;
+		}
+
+		__u8 type = ICMPV6_ECHO_REPLY;
+
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, bpf_htons(ICMP_ECHOREPLY << 8),
+					bpf_htons(ICMPV6_ECHO_REPLY << 8), 2) ||
+				bpf_skb_store_bytes(ctx->skb, l4_off, &type, sizeof(type), 0)) {
+			return -1;
+		}
+	} else {
+		__be32 addrs[8];
+
+		__builtin_memcpy(addrs, saddr, 16);
+		__builtin_memcpy(addrs + 4, daddr, 16);
+		csum = bpf_csum_diff(&ip4.saddr, 8, addrs, sizeof(addrs), 0);
+		if (bpf_l4_csum_replace(ctx->skb, csum_off, 0, csum, BPF_F_PSEUDO_HDR)) {
+			return -1;
+		}
+	}
+
+	if (bpf_l4_csum_replace(ctx->skb, csum_off, dport, port, 2) ||
+			bpf_skb_store_bytes(ctx->skb, l4_off + dport_off, &port, sizeof(port), 0)) {
+		return -1;
+	}
+
+	if (bpf_skb_change_proto(ctx->skb, bpf_htons(ETH_P_IPV6), 0)) {
+		CALI_DEBUG("NAT64: failed to change proto to IPv6\n");
+		return -1;
+	}
+
+	struct ipv6hdr ip6 = {
+		.version = 6,
+		.priority = ip4.tos >> 4,
+		.flow_lbl = { (ip4.tos & 0xf) << 4 },
+		.payload_len = bpf_htons(bpf_ntohs(ip4.tot_len) - sizeof(struct iphdr)),
+		.nexthdr = proto == IPPROTO_ICMP ? IPPROTO_ICMPV6 : proto,
+		.hop_limit = ip4.ttl - 1,
+	};
+
+	__builtin_memcpy(&ip6.saddr, saddr, 16);
+	__builtin_memcpy(&ip6.daddr, daddr, 16);
+
+	if (bpf_skb_store_bytes(ctx->skb, l3_off, &ip6, sizeof(ip6), 0)) {
+		return -1;
+	}
+
+	*ip_proto = ip6.nexthdr;
+
+	return nat64_set_eth_proto(ctx, bpf_htons(ETH_P_IPV6));
+}
+
+#endif /* IPVER6 */
+
+#endif /* __CALI_NAT64_H__ */
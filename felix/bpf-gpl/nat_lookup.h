@@ -119,6 +119,18 @@ static CALI_BPF_INLINE struct calico_nat_dest* calico_nat_lookup(ipv46_addr_t *i
 		return NULL;
 	}
 
+	if (nat_lv1_val->flags & NAT_FLG_PROXY_PROTO) {
+#if CALI_F_CGROUP
+		/* The PROXY protocol header is inserted by the tc programs, they
+		 * must see the connection to the service.
+		 */
+		CALI_DEBUG("NAT: PROXY protocol, skipping CTLB\n");
+		return NULL;
+#elif !(CALI_F_XDP)
+		ctx->state->flags |= CALI_ST_PROXY_PROTO;
+#endif
+	}
+
 	if (from_tun) {
 		count = nat_lv1_val->local;
 	} else if (nat_lv1_val->flags & (NAT_FLG_INTERNAL_LOCAL | NAT_FLG_EXTERNAL_LOCAL)) {
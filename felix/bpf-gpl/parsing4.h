@@ -88,6 +88,7 @@ static CALI_BPF_INLINE void tc_state_fill_from_iphdr_v4(struct cali_tc_ctx *ctx)
 	ctx->state->ip_dst = ip_hdr(ctx)->daddr;
 	ctx->state->pre_nat_ip_dst = ip_hdr(ctx)->daddr;
 	ctx->state->ip_proto = ip_hdr(ctx)->protocol;
+	ctx->state->ip_dscp = ip_hdr(ctx)->tos >> 2;
 	ctx->state->ip_size = ip_hdr(ctx)->tot_len;
 	ctx->ipheader_len = ctx->state->ihl = ip_hdr(ctx)->ihl * 4;
 	CALI_DEBUG("IP ihl=%d bytes\n", ctx->ipheader_len);
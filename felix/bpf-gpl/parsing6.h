@@ -100,6 +100,9 @@ static CALI_BPF_INLINE void tc_state_fill_from_iphdr_v6_offset(struct cali_tc_ct
 	ctx->state->pre_nat_ip_dst = ctx->state->ip_dst;
 	// Fill in other information
 	ctx->state->ip_size = ip_hdr(ctx)->payload_len;
+	// The DSCP is the top 6 bits of the traffic class, which straddles the priority and
+	// flow label fields.
+	ctx->state->ip_dscp = (ip_hdr(ctx)->priority << 2) | (ip_hdr(ctx)->flow_lbl[0] >> 6);
 
 	int hdr;
 
@@ -0,0 +1,39 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_POL_BITMAP_H__
+#define __CALI_POL_BITMAP_H__
+
+#include "bpf.h"
+
+/* With BPFPolicyBitmapsEnabled, Felix gives each active policy an index and
+ * precomputes, for each endpoint, the bitmap of the policies that apply to it.
+ * The bitmaps are keyed by the ifindex of the endpoint's interface so that the
+ * set of policies of a packet is resolved with a single lookup. An interface
+ * without an entry has no bitmap and its policy program is used as is.
+ *
+ * WARNING: must be kept in sync with bpf/polbitmap/map.go.
+ */
+#define POL_BITMAP_BITS	8192
+
+struct pol_bitmap {
+	__u8 bits[POL_BITMAP_BITS / 8];
+};
+
+CALI_MAP_V1(cali_pol_bits, BPF_MAP_TYPE_HASH, __u32, struct pol_bitmap, 1000, BPF_F_NO_PREALLOC)
+
+static CALI_BPF_INLINE struct pol_bitmap *pol_bitmap_lookup(__u32 ifindex)
+{
+	return cali_pol_bits_lookup_elem(&ifindex);
+}
+
+static CALI_BPF_INLINE bool pol_bitmap_has(struct pol_bitmap *bm, __u32 idx)
+{
+	if (!bm || idx >= POL_BITMAP_BITS) {
+		return false;
+	}
+	return bm->bits[idx / 8] & (1 << (idx % 8));
+}
+
+#endif /* __CALI_POL_BITMAP_H__ */
@@ -0,0 +1,100 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_POLICY_LOG_H__
+#define __CALI_POLICY_LOG_H__
+
+#include "types.h"
+
+/* Log rules, keyed by their rule match ID.  Felix programs the rate limit of
+ * each rule, which is enforced with the generic cell rate algorithm: a packet
+ * is logged if its theoretical arrival time (tat) is no more than tolerance_ns
+ * in the future, and each logged packet pushes tat back by interval_ns.  An
+ * interval of 0 logs every packet.
+ */
+struct log_rule {
+	__u64 interval_ns;
+	__u64 tolerance_ns;
+	__u64 tat;
+};
+
+CALI_MAP_V1(cali_log_rules, BPF_MAP_TYPE_HASH, __u64, struct log_rule, 10000, 0)
+
+/* libbpf sizes the perf event array to the number of possible CPUs. */
+CALI_MAP_V1(cali_log_evts, BPF_MAP_TYPE_PERF_EVENT_ARRAY, __u32, __u32, 0, 0)
+
+#define CALI_LOG_EVT_INGRESS	1
+
+struct log_event {
+	__u64 rule_id;
+	DECLARE_IP_ADDR(src);
+	DECLARE_IP_ADDR(dst);
+	__u32 ifindex;
+	__u16 sport;
+	__u16 dport;
+	__u8 ip_proto;
+	__u8 ip_version;
+	__u8 flags;
+	__u8 __pad;
+	/* Network order, as in the IP header. */
+	__be16 ip_size;
+	__u16 __pad2;
+};
+
+static CALI_BPF_INLINE bool log_rule_allowed(struct log_rule *rule)
+{
+	if (!rule->interval_ns) {
+		return true;
+	}
+
+	__u64 now = bpf_ktime_get_ns();
+	__u64 tat = rule->tat > now ? rule->tat : now;
+
+	if (tat - now > rule->tolerance_ns) {
+		return false;
+	}
+	rule->tat = tat + rule->interval_ns;
+	return true;
+}
+
+/* log_rule_hits sends an event to Felix for each log rule that the packet hit
+ * and that is within its rate limit.
+ */
+static CALI_BPF_INLINE void log_rule_hits(struct cali_tc_ctx *ctx)
+{
+	for (int i = 0; i < MAX_RULE_IDS; i++) {
+		if (i >= ctx->state->rules_hit) {
+			break;
+		}
+		__u64 rule_id = ctx->state->rule_ids[i];
+		struct log_rule *rule = cali_log_rules_lookup_elem(&rule_id);
+		if (!rule || !log_rule_allowed(rule)) {
+			continue;
+		}
+
+		struct log_event evt = {
+			.rule_id = rule_id,
+			.src = ctx->state->ip_src,
+			.dst = ctx->state->post_nat_ip_dst,
+			.ifindex = ctx->skb->ifindex,
+			.sport = ctx->state->sport,
+			.dport = ctx->state->post_nat_dport,
+			.ip_proto = ctx->state->ip_proto,
+#ifdef IPVER6
+			.ip_version = 6,
+#else
+			.ip_version = 4,
+#endif
+			.flags = CALI_F_INGRESS ? CALI_LOG_EVT_INGRESS : 0,
+			.ip_size = ctx->state->ip_size,
+		};
+		int err = bpf_perf_event_output(ctx->skb, &cali_log_evts, BPF_F_CURRENT_CPU,
+						&evt, sizeof(evt));
+		if (err) {
+			CALI_DEBUG("Failed to send log event for rule 0x%x\n", rule_id);
+		}
+	}
+}
+
+#endif /* __CALI_POLICY_LOG_H__ */
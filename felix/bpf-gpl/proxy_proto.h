@@ -0,0 +1,340 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_PROXY_PROTO_H__
+#define __CALI_PROXY_PROTO_H__
+
+/* PROXY protocol v2 support for services that opt in with an annotation.
+ *
+ * When we DNAT the first data segment that a client sends to a backend, we
+ * insert a PROXY protocol v2 header between the TCP header and the payload so
+ * that the backend learns the original client and service addresses. From then
+ * on, the sequence numbers of the client are shifted by the length of the header
+ * on the way to the backend and the ACKs and SACKs of the backend are shifted
+ * back on the way to the client. The MSS that the backend advertises is reduced
+ * so that the first segment still fits once the header is added.
+ *
+ * We do not keep any extra state, all decisions are made by comparing the
+ * sequence numbers with the initial sequence number of the client, which is
+ * recorded in the conntrack entry of the connection.
+ *
+ * The header is inserted by growing the packet at the tail and moving the
+ * payload so that the TCP header stays in place for checksum offload. We cannot
+ * do that for GSO packets or payloads larger than PP2_MAX_PAYLOAD, those are
+ * dropped and the client retransmits a single segment.
+ */
+
+#define PP2_SIG_LEN		12
+#define PP2_VER_CMD_PROXY	0x21
+#ifdef IPVER6
+#define PP2_FAM_TCP		0x21
+#else
+#define PP2_FAM_TCP		0x11
+#endif
+
+struct pp2_hdr {
+	__u8 sig[PP2_SIG_LEN];
+	__u8 ver_cmd;
+	__u8 fam;
+	__be16 len;
+	ipv46_addr_t src_addr;
+	ipv46_addr_t dst_addr;
+	__be16 src_port;
+	__be16 dst_port;
+} __attribute__((packed));
+
+#define PP2_HDR_LEN		sizeof(struct pp2_hdr)
+#define PP2_ADDR_LEN		(PP2_HDR_LEN - 16)
+
+#define PP2_MOVE_CHUNK		64
+#define PP2_MAX_PAYLOAD		(32 * PP2_MOVE_CHUNK)
+
+#define TCP_MAX_OPTS_LEN	40
+#define TCPOPT_EOL		0
+#define TCPOPT_NOP		1
+#define TCPOPT_MSS		2
+#define TCPOPT_SACK		5
+
+#define proxy_proto_enabled(ctx)						\
+	((ctx)->state->ip_proto == IPPROTO_TCP &&				\
+	 ((ctx)->state->ct_result.flags & CALI_CT_FLAG_PROXY_PROTO) &&		\
+	 /* The node that forwards to another node's backend leaves it to that node. */ \
+	 !ct_result_np_node((ctx)->state->ct_result))
+
+/* proxy_proto_client_isn returns the initial sequence number of the client from
+ * the conntrack entry of the connection with the given post-NAT tuple.
+ */
+static CALI_BPF_INLINE bool proxy_proto_client_isn(struct cali_tc_ctx *ctx,
+						   ipv46_addr_t *src, ipv46_addr_t *dst,
+						   __u16 sport, __u16 dport, __u32 *isn)
+{
+	struct calico_ct_key *k = &ctx->scratch->ct_key;
+	struct calico_ct_value *v;
+
+	fill_ct_key(k, src_lt_dest(src, dst, sport, dport), IPPROTO_TCP, src, dst, sport, dport);
+	v = ct_lookup_elem(ctx, k);
+	if (!v) {
+		CALI_DEBUG("PROXY: conntrack miss\n");
+		return false;
+	}
+
+	/* The client opened the connection. */
+	*isn = bpf_ntohl(v->a_to_b.opener ? v->a_to_b.seqno : v->b_to_a.seqno);
+
+	return true;
+}
+
+/* proxy_proto_shift replaces the 32-bit field at the given offset in the TCP
+ * header, which must be a sequence number.
+ */
+static CALI_BPF_INLINE int proxy_proto_shift(struct cali_tc_ctx *ctx, size_t l4_csum_off,
+					     size_t field_off, __u32 from, __u32 to)
+{
+	__be32 old = bpf_htonl(from);
+	__be32 new = bpf_htonl(to);
+
+	if (bpf_skb_store_bytes(ctx->skb, skb_l4hdr_offset(ctx) + field_off, &new, sizeof(new), 0)) {
+		return -1;
+	}
+
+	return bpf_l4_csum_replace(ctx->skb, l4_csum_off, old, new, 4);
+}
+
+static CALI_BPF_INLINE int proxy_proto_insert(struct cali_tc_ctx *ctx, size_t l4_csum_off,
+					      __u32 l4_len, __u32 tcp_len)
+{
+	__u32 payload_off = skb_l4hdr_offset(ctx) + tcp_len;
+	__u32 len = l4_len - tcp_len;
+	__u8 buf[PP2_MOVE_CHUNK];
+	int i;
+
+	struct pp2_hdr hdr = {
+		.sig = { 0x0d, 0x0a, 0x0d, 0x0a, 0x00, 0x0d, 0x0a, 0x51, 0x55, 0x49, 0x54, 0x0a },
+		.ver_cmd = PP2_VER_CMD_PROXY,
+		.fam = PP2_FAM_TCP,
+		.len = bpf_htons(PP2_ADDR_LEN),
+		.src_addr = STATE->ip_src,
+		.dst_addr = STATE->ip_dst,
+		.src_port = bpf_htons(STATE->sport),
+		.dst_port = bpf_htons(STATE->dport),
+	};
+
+	if (skb_is_gso(ctx->skb) || len > PP2_MAX_PAYLOAD) {
+		CALI_DEBUG("PROXY: first segment too big (%d bytes)\n", len);
+		return -1;
+	}
+
+	if (bpf_skb_change_tail(ctx->skb, ctx->skb->len + PP2_HDR_LEN, 0)) {
+		CALI_DEBUG("PROXY: failed to grow the packet\n");
+		return -1;
+	}
+
+	/* Move the payload from the end so that we do not overwrite it. */
+	for (i = 0; i < PP2_MAX_PAYLOAD / PP2_MOVE_CHUNK && len > 0; i++) {
+		__u32 n = len > PP2_MOVE_CHUNK ? PP2_MOVE_CHUNK : len;
+
+		len -= n;
+		if (bpf_skb_load_bytes(ctx->skb, payload_off + len, buf, n) ||
+				bpf_skb_store_bytes(ctx->skb, payload_off + len + PP2_HDR_LEN, buf, n, 0)) {
+			CALI_DEBUG("PROXY: failed to move the payload\n");
+			return -1;
+		}
+	}
+
+	if (bpf_skb_store_bytes(ctx->skb, payload_off, &hdr, PP2_HDR_LEN, 0)) {
+		CALI_DEBUG("PROXY: failed to write the header\n");
+		return -1;
+	}
+
+	/* The length of the IP packet. */
+#ifdef IPVER6
+	__be16 ip_len_old = bpf_htons(l4_len);
+	__be16 ip_len_new = bpf_htons(l4_len + PP2_HDR_LEN);
+
+	if (bpf_skb_store_bytes(ctx->skb, skb_iphdr_offset(ctx) + offsetof(struct ipv6hdr, payload_len),
+				&ip_len_new, sizeof(ip_len_new), 0)) {
+		return -1;
+	}
+#else
+	__be16 ip_len_old = bpf_htons(l4_len + IP_SIZE);
+	__be16 ip_len_new = bpf_htons(l4_len + IP_SIZE + PP2_HDR_LEN);
+
+	if (bpf_skb_store_bytes(ctx->skb, skb_iphdr_offset(ctx) + offsetof(struct iphdr, tot_len),
+				&ip_len_new, sizeof(ip_len_new), 0) ||
+			bpf_l3_csum_replace(ctx->skb, skb_iphdr_offset(ctx) + offsetof(struct iphdr, check),
+				ip_len_old, ip_len_new, 2)) {
+		return -1;
+	}
+#endif
+
+	/* The length in the pseudo header and the new data. The payload moved by an
+	 * even number of bytes, so its contribution to the checksum is the same.
+	 */
+	__wsum csum = bpf_csum_diff(0, 0, (__u32 *)&hdr, PP2_HDR_LEN, 0);
+
+	if (bpf_l4_csum_replace(ctx->skb, l4_csum_off, bpf_htonl(l4_len),
+				bpf_htonl(l4_len + PP2_HDR_LEN), BPF_F_PSEUDO_HDR | 4) ||
+			bpf_l4_csum_replace(ctx->skb, l4_csum_off, 0, csum, 0)) {
+		return -1;
+	}
+
+	CALI_DEBUG("PROXY: inserted header of %d bytes\n", PP2_HDR_LEN);
+
+	return 0;
+}
+
+/* proxy_proto_fwd is called for packets from the client to the backend once
+ * they are DNATed. It expects STATE to still have the pre-NAT addresses and
+ * ports, which go into the header.
+ */
+static CALI_BPF_INLINE int proxy_proto_fwd(struct cali_tc_ctx *ctx, size_t l4_csum_off)
+{
+	struct tcphdr *tcp = tcp_hdr(ctx);
+	ipv46_addr_t src = ip_void(STATE->ct_result.nat_sip) ? STATE->ip_src : STATE->ct_result.nat_sip;
+	__u16 sport = STATE->ct_result.nat_sport ? : STATE->sport;
+	__u32 isn, seq, l4_len, tcp_len;
+
+	if (!proxy_proto_client_isn(ctx, &src, &STATE->post_nat_ip_dst, sport, STATE->post_nat_dport, &isn)) {
+		return 0;
+	}
+
+	if (skb_refresh_validate_ptrs(ctx, TCP_SIZE)) {
+		deny_reason(ctx, CALI_REASON_SHORT);
+		return -1;
+	}
+#ifdef IPVER6
+	l4_len = bpf_ntohs(ip_hdr(ctx)->payload_len);
+#else
+	l4_len = bpf_ntohs(ip_hdr(ctx)->tot_len) - IP_SIZE;
+#endif
+	tcp_len = tcp->doff * 4;
+	if (ctx->ipheader_len != IP_SIZE || l4_len < tcp_len) {
+		CALI_DEBUG("PROXY: unsupported IP options or malformed TCP\n");
+		return 0;
+	}
+
+	seq = bpf_ntohl(tcp->seq);
+	if ((__s32)(seq - (isn + 1)) > 0) {
+		CALI_DEBUG("PROXY: seq %u -> %u\n", seq, seq + PP2_HDR_LEN);
+		return proxy_proto_shift(ctx, l4_csum_off, offsetof(struct tcphdr, seq),
+					 seq, seq + PP2_HDR_LEN);
+	}
+	if (seq == isn + 1 && l4_len > tcp_len) {
+		return proxy_proto_insert(ctx, l4_csum_off, l4_len, tcp_len);
+	}
+
+	return 0;
+}
+
+/* proxy_proto_rev_opts fixes the MSS and the SACK blocks in the TCP options of
+ * the packets from the backend to the client.
+ */
+static CALI_BPF_INLINE int proxy_proto_rev_opts(struct cali_tc_ctx *ctx, size_t l4_csum_off,
+						__u32 opts_len, __u32 isn, bool syn)
+{
+	__u32 opts_off = skb_l4hdr_offset(ctx) + TCP_SIZE;
+	__u8 opts[TCP_MAX_OPTS_LEN];
+	bool changed = false;
+	__wsum csum;
+	int i, j;
+
+	opts_len &= 0x3c;
+	if (opts_len == 0 || opts_len > TCP_MAX_OPTS_LEN) {
+		return 0;
+	}
+	if (bpf_skb_load_bytes(ctx->skb, opts_off, opts, opts_len)) {
+		return -1;
+	}
+	csum = bpf_csum_diff((__u32 *)opts, opts_len, 0, 0, 0);
+
+	for (i = 0; i < TCP_MAX_OPTS_LEN - 1 && i < opts_len - 1;) {
+		__u8 kind = opts[i];
+		__u8 len = opts[i + 1];
+
+		if (kind == TCPOPT_EOL) {
+			break;
+		}
+		if (kind == TCPOPT_NOP) {
+			i++;
+			continue;
+		}
+		if (len < 2 || i + len > opts_len) {
+			break;
+		}
+
+		if (kind == TCPOPT_MSS && len == 4 && syn && i + 4 <= TCP_MAX_OPTS_LEN) {
+			__u16 mss = (opts[i + 2] << 8) | opts[i + 3];
+			__u16 max = PP2_MAX_PAYLOAD - PP2_HDR_LEN;
+
+			mss = mss > PP2_HDR_LEN ? mss - PP2_HDR_LEN : mss;
+			mss = mss > max ? max : mss;
+			CALI_DEBUG("PROXY: MSS -> %d\n", mss);
+			opts[i + 2] = mss >> 8;
+			opts[i + 3] = mss & 0xff;
+			changed = true;
+		} else if (kind == TCPOPT_SACK && !syn) {
+			/* Up to 4 blocks of left and right edges. */
+			for (j = i + 2; j + 4 <= i + len && j + 4 <= TCP_MAX_OPTS_LEN; j += 4) {
+				__u32 edge;
+
+				__builtin_memcpy(&edge, &opts[j], 4);
+				edge = bpf_ntohl(edge);
+				if ((__s32)(edge - (isn + 1)) > (__s32)PP2_HDR_LEN) {
+					edge = bpf_htonl(edge - PP2_HDR_LEN);
+					__builtin_memcpy(&opts[j], &edge, 4);
+					changed = true;
+				}
+			}
+		}
+
+		i += len;
+	}
+
+	if (!changed) {
+		return 0;
+	}
+
+	csum = bpf_csum_diff(0, 0, (__u32 *)opts, opts_len, csum);
+	if (bpf_skb_store_bytes(ctx->skb, opts_off, opts, opts_len, 0) ||
+			bpf_l4_csum_replace(ctx->skb, l4_csum_off, 0, csum, 0)) {
+		return -1;
+	}
+
+	return 0;
+}
+
+/* proxy_proto_rev is called for packets from the backend to the client once
+ * they are SNATed. It expects STATE to still have the pre-NAT addresses and
+ * ports.
+ */
+static CALI_BPF_INLINE int proxy_proto_rev(struct cali_tc_ctx *ctx, size_t l4_csum_off)
+{
+	struct tcphdr *tcp = tcp_hdr(ctx);
+	__u32 isn, ack, tcp_len;
+
+	if (!proxy_proto_client_isn(ctx, &STATE->ip_src, &STATE->ip_dst, STATE->sport, STATE->dport, &isn)) {
+		return 0;
+	}
+
+	/* The backend cannot have acked a part of the header only. Anything below
+	 * that acks data that were sent without it, e.g. a FIN.
+	 */
+	ack = bpf_ntohl(tcp->ack_seq);
+	if (tcp->ack && (__s32)(ack - (isn + 1)) > (__s32)PP2_HDR_LEN) {
+		CALI_DEBUG("PROXY: ack %u -> %u\n", ack, ack - PP2_HDR_LEN);
+		if (proxy_proto_shift(ctx, l4_csum_off, offsetof(struct tcphdr, ack_seq),
+				      ack, ack - PP2_HDR_LEN)) {
+			return -1;
+		}
+	}
+
+	tcp_len = tcp->doff * 4;
+	if (tcp_len > TCP_SIZE) {
+		return proxy_proto_rev_opts(ctx, l4_csum_off, tcp_len - TCP_SIZE, isn, tcp->syn);
+	}
+
+	return 0;
+}
+
+#endif /* __CALI_PROXY_PROTO_H__ */
@@ -23,6 +23,7 @@ enum calico_reason {
 	CALI_REASON_UNAUTH_SOURCE,
 	CALI_REASON_RT_UNKNOWN,
 	CALI_REASON_BLACK_HOLE,
+	CALI_REASON_FRAGMENT,
 	CALI_REASON_ACCEPTED_BY_XDP, // Not used by counters map
 	CALI_REASON_WEP_NOT_READY,
 	CALI_REASON_NATIFACE,
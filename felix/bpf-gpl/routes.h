@@ -32,6 +32,7 @@ enum cali_rt_flags {
 	CALI_RT_NO_DSR      = 0x80,
 	CALI_RT_BLACKHOLE_DROP  = 0x100,
 	CALI_RT_BLACKHOLE_REJECT  = 0x200,
+	CALI_RT_L7_PROXY    = 0x400,
 };
 
 struct cali_rt {
@@ -80,6 +81,7 @@ static CALI_BPF_INLINE enum cali_rt_flags cali_rt_lookup_flags(ipv46_addr_t *add
 #define cali_rt_is_tunneled(rt)	((rt)->flags & CALI_RT_TUNNELED)
 #define cali_rt_is_blackhole_drop(rt) ((rt)->flags & CALI_RT_BLACKHOLE_DROP)
 #define cali_rt_is_blackhole_reject(rt) ((rt)->flags & CALI_RT_BLACKHOLE_REJECT)
+#define cali_rt_is_l7_proxy(rt) ((rt)->flags & CALI_RT_L7_PROXY)
 
 #define cali_rt_flags_host(t) (((t) & CALI_RT_HOST) == CALI_RT_HOST)
 #define cali_rt_flags_local_host(t) (((t) & (CALI_RT_LOCAL | CALI_RT_HOST)) == (CALI_RT_LOCAL | CALI_RT_HOST))
@@ -23,13 +23,20 @@
 #include "log.h"
 #include "skb.h"
 #include "policy.h"
+#include "pol_bitmap.h"
 #include "conntrack.h"
 #include "nat.h"
 #include "nat_lookup.h"
+#include "proxy_proto.h"
 #include "routes.h"
 #include "jump.h"
 #include "reasons.h"
 #include "icmp.h"
+#include "icmp_ratelimit.h"
+#ifndef IPVER6
+#include "frags.h"
+#endif
+#include "nat64.h"
 #include "arp.h"
 #include "sendrecv.h"
 #include "fib.h"
@@ -40,6 +47,7 @@
 #include "metadata.h"
 #include "bpf_helpers.h"
 #include "rule_counters.h"
+#include "policy_log.h"
 
 #define HAS_HOST_CONFLICT_PROG CALI_F_TO_HEP
 
@@ -199,6 +207,24 @@ static CALI_BPF_INLINE int pre_policy_processing(struct cali_tc_ctx *ctx)
 		goto allow;
 	}
 
+#ifndef IPVER6
+	if (ip_is_frag(ip_hdr(ctx))) {
+		if (GLOBAL_FLAGS & CALI_GLOBALS_DROP_FRAGMENTS) {
+			CALI_DEBUG("IP fragment, drop\n");
+			deny_reason(ctx, CALI_REASON_FRAGMENT);
+			goto deny;
+		}
+		if (ip_frag_no(ip_hdr(ctx))) {
+			/* Not the first fragment, there is no L4 header to parse. */
+			if (frags4_fill_ports(ctx)) {
+				deny_reason(ctx, CALI_REASON_FRAGMENT);
+				goto deny;
+			}
+			goto ct_lookup;
+		}
+	}
+#endif
+
 	/* Parse out the source/dest ports (or type/code for ICMP). */
 	switch (tc_state_fill_from_nexthdr(ctx, dnat_should_decap())) {
 	case PARSING_ERROR:
@@ -236,6 +262,23 @@ static CALI_BPF_INLINE int pre_policy_processing(struct cali_tc_ctx *ctx)
 		}
 	}
 
+#ifndef IPVER6
+#if HAS_NAT64_PROG
+	if (nat64_enabled() && nat64_is_reply(ctx)) {
+		CALI_DEBUG("NAT64: reply to a translated flow\n");
+		CALI_JUMP_TO(ctx, PROG_INDEX_NAT64);
+		CALI_DEBUG("Failed to jump to NAT64 program\n");
+		goto deny;
+	}
+#endif
+
+	if (ip_more_frags(ip_hdr(ctx))) {
+		/* First fragment, remember the ports for the rest of the datagram. */
+		frags4_record(ctx);
+	}
+
+ct_lookup:
+#endif
 	ctx->state->pol_rc = CALI_POL_NO_MATCH;
 
 	/* Do conntrack lookup before anything else */
@@ -590,6 +633,20 @@ syn_force_policy:
 		}
 	}
 
+	/* TCP connections to a workload that is selected for L7 policy go through the
+	 * host IP stack, where iptables hands them to the L7 proxy.  The connections
+	 * that the proxy makes to the workload are policed at the WEP as usual, their
+	 * conntrack entries skip FIB so that the replies go back to the proxy's
+	 * transparent socket rather than straight to the original client.
+	 */
+	if (cali_rt_is_l7_proxy(dest_rt) && ctx->state->ip_proto == IPPROTO_TCP) {
+		CALI_DEBUG("Dest is an L7 proxied workload.\n");
+		ctx->state->flags |= CALI_ST_L7_PROXY;
+		if (CALI_F_TO_HOST) {
+			ctx->state->flags |= CALI_ST_SKIP_FIB;
+		}
+	}
+
 	if (cali_rt_flags_local_host(dest_rt->flags)) {
 		CALI_DEBUG("Post-NAT dest IP is local host.\n");
 		if (CALI_F_FROM_HEP && is_failsafe_in(ctx->state->ip_proto, ctx->state->post_nat_dport, ctx->state->ip_src)) {
@@ -651,7 +708,10 @@ do_policy:
 	}
 #endif
 
-	if (CALI_F_TO_WEP && ctx->skb->mark == CALI_SKB_MARK_MASQ) {
+	/* Match the MASQ mark under its mask; iptables may have set other bits, for
+	 * example the mark of pre-established Linux conntrack flows.
+	 */
+	if (CALI_F_TO_WEP && skb_mark_equals(ctx->skb, CALI_SKB_MARK_MASQ_MASK, CALI_SKB_MARK_MASQ)) {
 		CALI_DEBUG("MASQ to self - using dest as source for policy.\n");
 		ctx->state->ip_src_masq = ctx->state->ip_src;
 		ctx->state->ip_src = ctx->state->ip_dst;
@@ -818,8 +878,16 @@ static CALI_BPF_INLINE enum do_nat_res do_nat(struct cali_tc_ctx *ctx,
 				goto icmp_too_big;
 			}
 			STATE->ip_src = HOST_IP;
-			*seen_mark = CALI_SKB_MARK_BYPASS_FWD; /* Do FIB if possible */
-			CALI_DEBUG("marking CALI_SKB_MARK_BYPASS_FWD\n");
+			if (GLOBAL_FLAGS & CALI_GLOBALS_WIREGUARD_ENABLED) {
+				/* Let the kernel route the tunnel packet so that the WireGuard
+				 * routing rule can pick it up if the remote node is a peer.
+				 */
+				*seen_mark = CALI_SKB_MARK_BYPASS_WG;
+				CALI_DEBUG("marking CALI_SKB_MARK_BYPASS_WG\n");
+			} else {
+				*seen_mark = CALI_SKB_MARK_BYPASS_FWD; /* Do FIB if possible */
+				CALI_DEBUG("marking CALI_SKB_MARK_BYPASS_FWD\n");
+			}
 
 			goto nat_encap;
 		}
@@ -893,6 +961,13 @@ static CALI_BPF_INLINE enum do_nat_res do_nat(struct cali_tc_ctx *ctx,
 			}
 		}
 #endif
+		if (!ct_ctx_nat && !ct_related && !inner_icmp && l4_csum_off && proxy_proto_enabled(ctx)) {
+			if (proxy_proto_fwd(ctx, l4_csum_off)) {
+				CALI_DEBUG("PROXY: failed to update the packet to the backend\n");
+				goto deny;
+			}
+		}
+
 		/* From now on, the packet has a new source IP */
 		if (!ip_void(STATE->ct_result.nat_sip)) {
 			STATE->ip_src = STATE->ct_result.nat_sip;
@@ -937,7 +1012,12 @@ static CALI_BPF_INLINE enum do_nat_res do_nat(struct cali_tc_ctx *ctx,
 		CALI_DEBUG("CT: SNAT from %x:%d\n",
 				debug_ip(STATE->ct_result.nat_ip), STATE->ct_result.nat_port);
 
-		if (dnat_return_should_encap() && !ip_void(STATE->ct_result.tun_ip)) {
+		/* Same condition as for the encap below, after the NAT is done. We must
+		 * check the size before we do the NAT so that the ICMP error carries
+		 * the header that the sender knows.
+		 */
+		if ((dnat_return_should_encap() || (CALI_F_TO_HEP && !CALI_F_DSR)) &&
+									!ip_void(STATE->ct_result.tun_ip)) {
 			if (CALI_F_DSR && !(STATE->ct_result.flags & CALI_CT_FLAG_NP_NO_DSR)) {
 				/* SNAT will be done after routing, when leaving HEP */
 				CALI_DEBUG("DSR enabled, skipping SNAT + encap\n");
@@ -1021,6 +1101,13 @@ static CALI_BPF_INLINE enum do_nat_res do_nat(struct cali_tc_ctx *ctx,
 		}
 #endif
 
+		if (!ct_related && !inner_icmp && l4_csum_off && proxy_proto_enabled(ctx)) {
+			if (proxy_proto_rev(ctx, l4_csum_off)) {
+				CALI_DEBUG("PROXY: failed to update the packet to the client\n");
+				goto deny;
+			}
+		}
+
 		/* In addition to dnat_return_should_encap() we also need to encap on the
 		 * host endpoint for egress traffic, when we hit an SNAT rule. This is the
 		 * case when the target was host namespace. If the target was a pod, the
@@ -1176,6 +1263,22 @@ allow:
 		}
 	}
 
+	if (CALI_F_TO_HOST && !CALI_F_L3_DEV && is_dnat && (GLOBAL_FLAGS & CALI_GLOBALS_WIREGUARD_ENABLED)) {
+		struct cali_rt *r = cali_rt_lookup(&state->post_nat_ip_dst);
+
+		if (r && cali_rt_flags_remote_workload(r->flags)) {
+			/* The backend is a remote workload and the traffic may need to be
+			 * encrypted. Do not short-circuit the IP stack, the FIB lookup may
+			 * resolve the underlay device, instead mark the packet so that the
+			 * WireGuard routing rule steers it to the WireGuard table.
+			 */
+			CALI_DEBUG("DNAT to remote WL %x:%d with WireGuard\n",
+					debug_ip(state->post_nat_ip_dst), state->post_nat_dport);
+			seen_mark = CALI_SKB_MARK_BYPASS_WG;
+			fib = false;
+		}
+	}
+
 encap_allow:
 	{
 		struct fwd fwd = {
@@ -1215,7 +1318,7 @@ int calico_tc_skb_accepted_entrypoint(struct __sk_buff *skb)
 
 	if (!(ctx->state->flags & CALI_ST_SKIP_POLICY)) {
 		counter_inc(ctx, CALI_REASON_ACCEPTED_BY_POLICY);
-		if (CALI_F_TO_WEP && ctx->skb->mark == CALI_SKB_MARK_MASQ) {
+		if (CALI_F_TO_WEP && skb_mark_equals(ctx->skb, CALI_SKB_MARK_MASQ_MASK, CALI_SKB_MARK_MASQ)) {
 			/* Restore state->ip_src */
 			CALI_DEBUG("Accepted MASQ to self - restoring source for conntrack.\n");
 			ctx->state->ip_src = ctx->state->ip_src_masq;
@@ -1237,6 +1340,7 @@ int calico_tc_skb_accepted_entrypoint(struct __sk_buff *skb)
 	}
 
 	update_rule_counters(ctx);
+	log_rule_hits(ctx);
 
 	ctx->fwd = calico_tc_skb_accepted(ctx);
 	return forward_or_drop(ctx);
@@ -1322,8 +1426,10 @@ int calico_tc_skb_new_flow_entrypoint(struct __sk_buff *skb)
 	/* Packets received at WEP with CALI_CT_FLAG_SKIP_FIB mark signal
 	 * that all traffic on this connection must flow via host namespace as it was
 	 * originally meant for host, but got redirected to a WEP by a 3rd party DNAT rule.
+	 * The same goes for connections to a workload that is behind the L7 proxy.
 	 */
-	if (CALI_F_TO_WEP && ((ctx->skb->mark & CALI_SKB_MARK_SKIP_FIB) == CALI_SKB_MARK_SKIP_FIB)) {
+	if (CALI_F_TO_WEP && (((ctx->skb->mark & CALI_SKB_MARK_SKIP_FIB) == CALI_SKB_MARK_SKIP_FIB) ||
+				(state->flags & CALI_ST_L7_PROXY))) {
 		ct_ctx_nat->flags |= CALI_CT_FLAG_SKIP_FIB;
 	}
 	if (CALI_F_TO_HOST && CALI_F_NAT_IF) {
@@ -1338,6 +1444,9 @@ int calico_tc_skb_new_flow_entrypoint(struct __sk_buff *skb)
 	if (state->flags & CALI_ST_HOST_PSNAT) {
 		ct_ctx_nat->flags |= CALI_CT_FLAG_HOST_PSNAT;
 	}
+	if (state->flags & CALI_ST_PROXY_PROTO) {
+		ct_ctx_nat->flags |= CALI_CT_FLAG_PROXY_PROTO;
+	}
 	/* Mark connections that were routed via bpfnatout, but had CT miss at
 	 * HEP. That is because of SNAT happened between bpfnatout and here.
 	 * Returning packets on such a connection must go back via natbpfout
@@ -1428,6 +1537,13 @@ int calico_tc_skb_new_flow_entrypoint(struct __sk_buff *skb)
 		goto deny;
 	}
 
+#ifndef IPVER6
+	if (ip_frag_no(ip_hdr(ctx))) {
+		/* There is no L4 header to update in non-first fragments. */
+		l4_csum_off = 0;
+	}
+#endif
+
 	nat_res = do_nat(ctx, skb_iphdr_offset(ctx), l4_csum_off, false,
 			 CALI_CT_NEW, ct_ctx_nat, &is_dnat, &seen_mark, false);
 	if (nat_res == NAT_ICMP_TOO_BIG) {
@@ -1604,6 +1720,13 @@ static CALI_BPF_INLINE struct fwd calico_tc_skb_accepted(struct cali_tc_ctx *ctx
 		break;
 	}
 
+#ifndef IPVER6
+	if (ip_frag_no(ip_hdr(ctx))) {
+		/* There is no L4 header to update in non-first fragments. */
+		l4_csum_off = 0;
+	}
+#endif
+
 	switch (ct_rc){
 	case CALI_CT_ESTABLISHED_DNAT:
 	case CALI_CT_ESTABLISHED_SNAT:
@@ -1827,14 +1950,21 @@ int calico_tc_skb_send_icmp_replies(struct __sk_buff *skb)
 	CALI_DEBUG("Entering calico_tc_skb_send_icmp_replies\n");
 	CALI_DEBUG("ICMP type %d and code %d\n",ctx->state->icmp_type, ctx->state->icmp_code);
 
+	if (icmp_rate_limited()) {
+		CALI_DEBUG("ICMP reply rate limited, dropping\n");
+		goto deny;
+	}
+
 #ifdef IPVER6
-	if (ctx->state->icmp_code == ICMPV6_PKT_TOOBIG) {
+	if (ctx->state->icmp_type == ICMPV6_PKT_TOOBIG) {
 #else
 	if (ctx->state->icmp_code == ICMP_FRAG_NEEDED) {
 #endif
 		fib_flags |= BPF_FIB_LOOKUP_OUTPUT;
-		if (CALI_F_FROM_WEP) {
-			/* we know it came from workload, just send it back the same way */
+		if (CALI_F_FROM_WEP || CALI_F_TO_HEP) {
+			/* we know it came from workload or from the host, just send it
+			 * back the same way
+			 */
 			ctx->fwd.res = CALI_RES_REDIR_BACK;
 		}
 	}
@@ -1928,6 +2058,52 @@ deny:
 }
 #endif /* HAS_HOST_CONFLICT_PROG */
 
+#if HAS_NAT64_PROG
+SEC("tc")
+int calico_tc_skb_nat64(struct __sk_buff *skb)
+{
+	DECLARE_TC_CTX(_ctx,
+		.skb = skb,
+		.fwd = {
+			.res = TC_ACT_UNSPEC,
+			.reason = CALI_REASON_UNKNOWN,
+		},
+	);
+	struct cali_tc_ctx *ctx = &_ctx;
+	__be32 saddr[4] = {}, daddr[4] = {};
+	__u8 ip_proto = 0;
+
+	CALI_DEBUG("Entering calico_tc_skb_nat64\n");
+
+	if (skb_refresh_validate_ptrs(ctx, UDP_SIZE)) {
+		ctx->fwd.reason = CALI_REASON_SHORT;
+		CALI_DEBUG("Too short\n");
+		goto deny;
+	}
+
+#ifdef IPVER6
+	/* Pod to an IPv4 destination embedded in the NAT64 prefix. */
+	if (nat64_6to4(ctx, saddr, daddr, &ip_proto)) {
+		CALI_DEBUG("NAT64: failed to translate to IPv4\n");
+		goto deny;
+	}
+
+	return nat64_redirect(ctx, false, saddr, daddr, ip_proto);
+#else
+	/* Reply from the IPv4 destination back to the pod. */
+	if (nat64_4to6(ctx, saddr, daddr, &ip_proto)) {
+		CALI_DEBUG("NAT64: failed to translate to IPv6\n");
+		goto deny;
+	}
+
+	return nat64_redirect(ctx, true, saddr, daddr, ip_proto);
+#endif
+
+deny:
+	return TC_ACT_SHOT;
+}
+#endif /* HAS_NAT64_PROG */
+
 SEC("tc")
 int calico_tc_skb_drop(struct __sk_buff *skb)
 {
@@ -1939,6 +2115,7 @@ int calico_tc_skb_drop(struct __sk_buff *skb)
 	CALI_DEBUG("Entering calico_tc_skb_drop\n");
 
 	update_rule_counters(ctx);
+	log_rule_hits(ctx);
 	counter_inc(ctx, CALI_REASON_DROPPED_BY_POLICY);
 
 	CALI_DEBUG("proto=%d\n", ctx->state->ip_proto);
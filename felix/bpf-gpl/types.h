@@ -91,8 +91,8 @@ struct cali_tc_state {
 	__u16 post_nat_dport;
 	/* Packet IP proto; updated to UDP when we encap. */
 	__u8 ip_proto;
-	/* Flags from enum cali_state_flags. */
-	__u8 __pad;
+	/* DSCP of the packet, filled from the IP header in tc_state_fill_from_iphdr(). */
+	__u8 ip_dscp;
 	/* Packet size filled from iphdr->tot_len in tc_state_fill_from_iphdr(). */
 	__be16 ip_size;
 	/* Count of rules that were hit while processing policy. */
@@ -149,6 +149,11 @@ enum cali_state_flags {
 	CALI_ST_CT_NP_REMOTE	  = 0x100,
 	/* CALI_ST_NAT_EXCLUDE is set when there is a NAT hit, but we don't want to resolve (such as node local DNS). */
 	CALI_ST_NAT_EXCLUDE       = 0x200,
+	/* CALI_ST_PROXY_PROTO is set when the service requires a PROXY protocol header. */
+	CALI_ST_PROXY_PROTO       = 0x400,
+	/* CALI_ST_L7_PROXY is set when the destination is a workload whose TCP connections
+	 * are handed to the L7 proxy. */
+	CALI_ST_L7_PROXY          = 0x800,
 };
 
 struct fwd {
@@ -135,7 +135,7 @@ func RememberAttachedProg(a AttachPointInfo, object string, id int) error {
 		Config: a.Config(),
 	}
 
-	if err := os.MkdirAll(RuntimeProgDir, 0600); err != nil {
+	if err := os.MkdirAll(RuntimeProgDir(), 0600); err != nil {
 		return err
 	}
 
@@ -177,7 +177,7 @@ func ForgetIfaceAttachedProg(iface string) error {
 // CleanAttachedProgDir makes sure /var/run/calico/bpf/prog exists and removes
 // json files related to interfaces that do not exist.
 func CleanAttachedProgDir() {
-	if err := os.MkdirAll(RuntimeProgDir, 0600); err != nil {
+	if err := os.MkdirAll(RuntimeProgDir(), 0600); err != nil {
 		log.Errorf("Failed to create BPF hash directory. err=%v", err)
 	}
 
@@ -193,11 +193,11 @@ func CleanAttachedProgDir() {
 		}
 	}
 
-	err = filepath.Walk(RuntimeProgDir, func(p string, info os.FileInfo, err error) error {
+	err = filepath.Walk(RuntimeProgDir(), func(p string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if p == RuntimeProgDir {
+		if p == RuntimeProgDir() {
 			return nil
 		}
 		if !expectedJSONFiles.Contains(p) {
@@ -211,7 +211,7 @@ func CleanAttachedProgDir() {
 	})
 
 	if err != nil {
-		log.Debugf("Error in cleaning up %s. err=%v", RuntimeProgDir, err)
+		log.Debugf("Error in cleaning up %s. err=%v", RuntimeProgDir(), err)
 	}
 }
 
@@ -219,7 +219,7 @@ func CleanAttachedProgDir() {
 // attached program. The filename is [iface name]_[hook].json, for
 // example, eth0_egress.json
 func RuntimeJSONFilename(iface string, hook hook.Hook) string {
-	return path.Join(RuntimeProgDir, fmt.Sprintf("%s_%s.json", iface, hook))
+	return path.Join(RuntimeProgDir(), fmt.Sprintf("%s_%s.json", iface, hook))
 }
 
 func sha256OfFile(name string) (string, error) {
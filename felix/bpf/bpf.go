@@ -2244,11 +2244,11 @@ func KTimeNanos() int64 {
 }
 
 func PolicyDebugJSONFileName(iface, polDir string, ipFamily proto.IPVersion) string {
-	return path.Join(RuntimePolDir, fmt.Sprintf("%s_%s_v%d.json", iface, polDir, ipFamily))
+	return path.Join(RuntimePolDir(), fmt.Sprintf("%s_%s_v%d.json", iface, polDir, ipFamily))
 }
 
 func MapPinDir(typ int, name, iface string, h hook.Hook) string {
-	PinBaseDir := path.Join(bpfdefs.DefaultBPFfsPath, "tc")
+	PinBaseDir := path.Join(bpfdefs.PinRoot(), "tc")
 	subDir := "globals"
 	return path.Join(PinBaseDir, subDir)
 }
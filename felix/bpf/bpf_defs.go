@@ -17,12 +17,14 @@
 package bpf
 
 import (
+	"path"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 
 	"github.com/projectcalico/calico/felix/bpf/asm"
+	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 )
 
 type ProgFD uint32
@@ -50,7 +52,12 @@ type PolicyDebugInfo struct {
 	Error      string    `json:"error"`
 }
 
-const (
-	RuntimeProgDir = "/var/run/calico/bpf/prog"
-	RuntimePolDir  = "/var/run/calico/bpf/policy"
-)
+// RuntimeProgDir is where the debug info of the attached programs is written.
+func RuntimeProgDir() string {
+	return path.Join(bpfdefs.RuntimeDir(), "bpf", "prog")
+}
+
+// RuntimePolDir is where the debug info of the policy programs is written.
+func RuntimePolDir() string {
+	return path.Join(bpfdefs.RuntimeDir(), "bpf", "policy")
+}
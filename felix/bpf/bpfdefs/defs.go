@@ -14,17 +14,108 @@
 
 package bpfdefs
 
-import "os"
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
 
 const (
 	DefaultBPFfsPath    = "/sys/fs/bpf"
 	DefaultCgroupV2Path = "/run/calico/cgroup"
+	DefaultRuntimeDir   = "/var/run/calico"
+
+	ObjectDir = "/usr/lib/calico/bpf"
 
-	GlobalPinDir = DefaultBPFfsPath + "/tc/globals/"
-	ObjectDir    = "/usr/lib/calico/bpf"
+	// instancesDir is the directory, under the BPF filesystem and the runtime directory, that
+	// holds the state of the named Felix instances.
+	instancesDir = "calico-instances"
 )
 
+// Paths are the locations of the runtime state of Felix.  By default there is a single Felix
+// per host; naming the instance moves its BPF pins and runtime files to a directory of their
+// own, so that several instances can share a host.
+type Paths struct {
+	BPFfs      string
+	CgroupV2   string
+	RuntimeDir string
+	Instance   string
+}
+
+var paths = Paths{
+	BPFfs:      DefaultBPFfsPath,
+	RuntimeDir: DefaultRuntimeDir,
+}
+
+// SetPaths sets the locations of the runtime state, it must be called before any BPF map or
+// program is loaded.  Empty fields keep their defaults.
+func SetPaths(p Paths) {
+	if p.BPFfs == "" {
+		p.BPFfs = DefaultBPFfsPath
+	}
+	if p.RuntimeDir == "" {
+		p.RuntimeDir = DefaultRuntimeDir
+	}
+	p.BPFfs = filepath.Clean(p.BPFfs)
+	p.RuntimeDir = filepath.Clean(p.RuntimeDir)
+	paths = p
+}
+
+// Instance returns the name of this Felix instance, empty for the default instance.
+func Instance() string {
+	return paths.Instance
+}
+
+// BPFfsPath returns where the BPF filesystem is mounted.
+func BPFfsPath() string {
+	return paths.BPFfs
+}
+
+// PinRoot returns the directory that this instance pins its BPF maps and programs under.
+func PinRoot() string {
+	if paths.Instance == "" {
+		return paths.BPFfs
+	}
+	return path.Join(paths.BPFfs, instancesDir, paths.Instance)
+}
+
+// InstancesPinDir returns the directory that holds the pins of the named instances, which the
+// cleanup of the default instance must leave alone.
+func InstancesPinDir() string {
+	return path.Join(paths.BPFfs, instancesDir)
+}
+
+func GlobalPinDir() string {
+	return path.Join(PinRoot(), "tc", "globals") + "/"
+}
+
+func TcxPinDir() string {
+	return path.Join(PinRoot(), "tcx") + "/"
+}
+
+// RuntimeDir returns the directory of this instance's runtime files and sockets.
+func RuntimeDir() string {
+	if paths.Instance == "" {
+		return paths.RuntimeDir
+	}
+	return path.Join(paths.RuntimeDir, instancesDir, paths.Instance)
+}
+
+// InstanceRuntimePath moves a path under the shared runtime directory to this instance's
+// runtime directory.  Other paths are returned unchanged, they are configured explicitly.
+func InstanceRuntimePath(p string) string {
+	p = filepath.Clean(p)
+	if paths.Instance == "" || !strings.HasPrefix(p, paths.RuntimeDir+"/") {
+		return p
+	}
+	return path.Join(RuntimeDir(), strings.TrimPrefix(p, paths.RuntimeDir+"/"))
+}
+
 func GetCgroupV2Path() string {
+	if paths.CgroupV2 != "" {
+		return paths.CgroupV2
+	}
 	cgroupV2CustomPath := os.Getenv("CALICO_CGROUP_PATH")
 	if cgroupV2CustomPath == "" {
 		return DefaultCgroupV2Path
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpfdefs
+
+import "testing"
+
+func TestDefaultPaths(t *testing.T) {
+	SetPaths(Paths{})
+	defer SetPaths(Paths{})
+
+	if GlobalPinDir() != "/sys/fs/bpf/tc/globals/" {
+		t.Errorf("unexpected global pin dir %q", GlobalPinDir())
+	}
+	if TcxPinDir() != "/sys/fs/bpf/tcx/" {
+		t.Errorf("unexpected tcx pin dir %q", TcxPinDir())
+	}
+	if RuntimeDir() != "/var/run/calico" {
+		t.Errorf("unexpected runtime dir %q", RuntimeDir())
+	}
+	if p := InstanceRuntimePath("/var/run/calico/l7-verdicts.sock"); p != "/var/run/calico/l7-verdicts.sock" {
+		t.Errorf("expected the default instance to keep its paths, got %q", p)
+	}
+}
+
+func TestInstancePaths(t *testing.T) {
+	SetPaths(Paths{BPFfs: "/mnt/bpf/", RuntimeDir: "/run/calico", Instance: "blue"})
+	defer SetPaths(Paths{})
+
+	if PinRoot() != "/mnt/bpf/calico-instances/blue" {
+		t.Errorf("unexpected pin root %q", PinRoot())
+	}
+	if GlobalPinDir() != "/mnt/bpf/calico-instances/blue/tc/globals/" {
+		t.Errorf("unexpected global pin dir %q", GlobalPinDir())
+	}
+	if InstancesPinDir() != "/mnt/bpf/calico-instances" {
+		t.Errorf("unexpected instances pin dir %q", InstancesPinDir())
+	}
+	if RuntimeDir() != "/run/calico/calico-instances/blue" {
+		t.Errorf("unexpected runtime dir %q", RuntimeDir())
+	}
+	if p := InstanceRuntimePath("/run/calico/l7-verdicts.sock"); p != "/run/calico/calico-instances/blue/l7-verdicts.sock" {
+		t.Errorf("expected the socket to move to the instance, got %q", p)
+	}
+	if p := InstanceRuntimePath("/run/calicoctl.sock"); p != "/run/calicoctl.sock" {
+		t.Errorf("expected a path outside the runtime dir to stay, got %q", p)
+	}
+}
+
+func TestCgroupV2Path(t *testing.T) {
+	defer SetPaths(Paths{})
+
+	t.Setenv("CALICO_CGROUP_PATH", "")
+	SetPaths(Paths{})
+	if GetCgroupV2Path() != DefaultCgroupV2Path {
+		t.Errorf("unexpected default cgroup path %q", GetCgroupV2Path())
+	}
+	t.Setenv("CALICO_CGROUP_PATH", "/run/cgroup")
+	if GetCgroupV2Path() != "/run/cgroup" {
+		t.Errorf("expected the environment to set the cgroup path, got %q", GetCgroupV2Path())
+	}
+	SetPaths(Paths{CgroupV2: "/run/blue/cgroup"})
+	if GetCgroupV2Path() != "/run/blue/cgroup" {
+		t.Errorf("expected the configured cgroup path, got %q", GetCgroupV2Path())
+	}
+}
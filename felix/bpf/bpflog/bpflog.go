@@ -0,0 +1,55 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bpflog defines the standard fields of the logs of the BPF dataplane and of the BPF
+// kube-proxy replacement, so that log pipelines can find the IP family, service and map of an
+// event without parsing the message.  See felix/docs/logging.md for the schema.
+package bpflog
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FieldIPFamily is the IP family, 4 or 6, of the proxy or of the map.
+	FieldIPFamily = "ipFamily"
+	// FieldService is the name of the service port, namespace/name:port.  The derived frontends
+	// of a service have their type and IP as a prefix, for example "NodePort:10.0.0.1:".
+	FieldService = "service"
+	// FieldSvcID is the ID of the service in the NAT maps.
+	FieldSvcID = "svcID"
+	// FieldMap is the name of the BPF map.
+	FieldMap = "map"
+)
+
+// WithIPFamily returns a log entry with the IP family field.
+func WithIPFamily(ipFamily int) *log.Entry {
+	return log.WithField(FieldIPFamily, ipFamily)
+}
+
+// WithMap returns a log entry with the map name field.
+func WithMap(name string) *log.Entry {
+	return log.WithField(FieldMap, name)
+}
+
+// WithService returns a log entry with the fields of a service in the NAT maps: the IP family,
+// the service name, its ID and the name of the frontend map.
+func WithService(ipFamily int, service string, svcID uint32, mapName string) *log.Entry {
+	return log.WithFields(log.Fields{
+		FieldIPFamily: ipFamily,
+		FieldService:  service,
+		FieldSvcID:    svcID,
+		FieldMap:      mapName,
+	})
+}
@@ -24,12 +24,17 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/conntrack"
 	"github.com/projectcalico/calico/felix/bpf/counters"
 	"github.com/projectcalico/calico/felix/bpf/failsafes"
+	"github.com/projectcalico/calico/felix/bpf/frags"
 	"github.com/projectcalico/calico/felix/bpf/hook"
+	"github.com/projectcalico/calico/felix/bpf/icmp"
 	"github.com/projectcalico/calico/felix/bpf/ifstate"
 	"github.com/projectcalico/calico/felix/bpf/ipsets"
 	"github.com/projectcalico/calico/felix/bpf/jump"
+	"github.com/projectcalico/calico/felix/bpf/logrules"
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/nat64"
+	"github.com/projectcalico/calico/felix/bpf/polbitmap"
 	"github.com/projectcalico/calico/felix/bpf/routes"
 	"github.com/projectcalico/calico/felix/bpf/state"
 )
@@ -45,17 +50,25 @@ type IPMaps struct {
 	CtMap        maps.Map
 	SrMsgMap     maps.Map
 	CtNatsMap    maps.Map
+	// FragsMap is only used by IPv4, it is nil for IPv6.
+	FragsMap maps.Map
 }
 
 type CommonMaps struct {
-	StateMap        maps.Map
-	IfStateMap      maps.Map
-	RuleCountersMap maps.Map
-	CountersMap     maps.Map
-	ProgramsMap     maps.Map
-	JumpMap         maps.MapWithDeleteIfExists
-	XDPProgramsMap  maps.Map
-	XDPJumpMap      maps.MapWithDeleteIfExists
+	StateMap         maps.Map
+	IfStateMap       maps.Map
+	RuleCountersMap  maps.Map
+	CountersMap      maps.Map
+	ICMPRateLimitMap maps.Map
+	LogRulesMap      maps.Map
+	LogEventsMap     maps.Map
+	ProgramsMap      maps.Map
+	JumpMap          maps.MapWithDeleteIfExists
+	XDPProgramsMap   maps.Map
+	XDPJumpMap       maps.MapWithDeleteIfExists
+	NAT64FwdMap      maps.Map
+	NAT64RevMap      maps.Map
+	PolicyBitmapMap  maps.Map
 }
 
 type Maps struct {
@@ -72,21 +85,37 @@ func (m *Maps) Destroy() {
 		mps = append(mps, m.V6.slice()...)
 	}
 	for _, m := range mps {
-		os.Remove(m.(pinnedMap).Path())
-		m.(pinnedMap).Close()
+		removePins(m)
 	}
 }
 
+func removePins(m maps.Map) {
+	if sm, ok := m.(*conntrack.ShardedMap); ok {
+		for _, s := range sm.Shards() {
+			removePins(s)
+		}
+		return
+	}
+	os.Remove(m.(pinnedMap).Path())
+	m.(pinnedMap).Close()
+}
+
 func getCommonMaps() *CommonMaps {
 	return &CommonMaps{
-		StateMap:        state.Map(),
-		IfStateMap:      ifstate.Map(),
-		RuleCountersMap: counters.PolicyMap(),
-		CountersMap:     counters.Map(),
-		ProgramsMap:     hook.NewProgramsMap(),
-		JumpMap:         jump.Map().(maps.MapWithDeleteIfExists),
-		XDPProgramsMap:  hook.NewXDPProgramsMap(),
-		XDPJumpMap:      jump.XDPMap().(maps.MapWithDeleteIfExists),
+		StateMap:         state.Map(),
+		IfStateMap:       ifstate.Map(),
+		RuleCountersMap:  counters.PolicyMap(),
+		CountersMap:      counters.Map(),
+		ICMPRateLimitMap: icmp.RateLimitMap(),
+		LogRulesMap:      logrules.Map(),
+		LogEventsMap:     logrules.EventsMap(),
+		ProgramsMap:      hook.NewProgramsMap(),
+		JumpMap:          jump.Map().(maps.MapWithDeleteIfExists),
+		XDPProgramsMap:   hook.NewXDPProgramsMap(),
+		XDPJumpMap:       jump.XDPMap().(maps.MapWithDeleteIfExists),
+		NAT64FwdMap:      nat64.FwdMap(),
+		NAT64RevMap:      nat64.RevMap(),
+		PolicyBitmapMap:  polbitmap.Map(),
 	}
 }
 
@@ -105,6 +134,11 @@ func getIPMaps(ipFamily int) *IPMaps {
 		return v6()
 	}
 
+	var fragsMap maps.Map
+	if ipFamily == 4 {
+		fragsMap = frags.Map()
+	}
+
 	return &IPMaps{
 		IpsetsMap:    getmap(ipsets.Map, ipsets.MapV6),
 		ArpMap:       getmap(arp.Map, arp.MapV6),
@@ -113,9 +147,10 @@ func getIPMaps(ipFamily int) *IPMaps {
 		BackendMap:   getmapWithExistsCheck(nat.BackendMap, nat.BackendMapV6),
 		AffinityMap:  getmap(nat.AffinityMap, nat.AffinityMapV6),
 		RouteMap:     getmap(routes.Map, routes.MapV6),
-		CtMap:        getmap(conntrack.Map, conntrack.MapV6),
+		CtMap:        getmap(conntrack.MapWithShards, conntrack.MapV6WithShards),
 		SrMsgMap:     getmap(nat.SendRecvMsgMap, nat.SendRecvMsgMapV6),
 		CtNatsMap:    getmap(nat.AllNATsMsgMap, nat.AllNATsMsgMapV6),
+		FragsMap:     fragsMap,
 	}
 }
 
@@ -134,9 +169,7 @@ func CreateBPFMaps(ipV6Enabled bool) (*Maps, error) {
 		if err != nil {
 
 			for j := 0; j < i; j++ {
-				m := mps[j]
-				os.Remove(m.(pinnedMap).Path())
-				m.(pinnedMap).Close()
+				removePins(mps[j])
 			}
 
 			return nil, fmt.Errorf("failed to create %s map, err=%w", bpfMap.GetName(), err)
@@ -146,6 +179,30 @@ func CreateBPFMaps(ipV6Enabled bool) (*Maps, error) {
 	return ret, nil
 }
 
+type memoryEstimator interface {
+	EstimatedMemory() uint64
+}
+
+// EstimatedMemory returns the estimated kernel memory of each of the BPF maps, by name,
+// with the currently configured map sizes.  It doesn't create the maps.
+func EstimatedMemory(ipV6Enabled bool) map[string]uint64 {
+	m := &Maps{
+		CommonMaps: getCommonMaps(),
+		V4:         getIPMaps(4),
+	}
+	if ipV6Enabled {
+		m.V6 = getIPMaps(6)
+	}
+
+	mem := map[string]uint64{}
+	for _, bpfMap := range m.slice() {
+		if e, ok := bpfMap.(memoryEstimator); ok {
+			mem[bpfMap.GetName()] += e.EstimatedMemory()
+		}
+	}
+	return mem
+}
+
 func (m *Maps) slice() []maps.Map {
 	mps := []maps.Map{}
 	mps = append(mps, m.CommonMaps.slice()...)
@@ -164,15 +221,21 @@ func (c *CommonMaps) slice() []maps.Map {
 		c.IfStateMap,
 		c.RuleCountersMap,
 		c.CountersMap,
+		c.ICMPRateLimitMap,
+		c.LogRulesMap,
+		c.LogEventsMap,
 		c.ProgramsMap,
 		c.JumpMap,
 		c.XDPProgramsMap,
 		c.XDPJumpMap,
+		c.NAT64FwdMap,
+		c.NAT64RevMap,
+		c.PolicyBitmapMap,
 	}
 }
 
 func (i *IPMaps) slice() []maps.Map {
-	return []maps.Map{
+	mps := []maps.Map{
 		i.IpsetsMap,
 		i.ArpMap,
 		i.FailsafesMap,
@@ -184,6 +247,10 @@ func (i *IPMaps) slice() []maps.Map {
 		i.SrMsgMap,
 		i.CtNatsMap,
 	}
+	if i.FragsMap != nil {
+		mps = append(mps, i.FragsMap)
+	}
+	return mps
 }
 
 type pinnedMap interface {
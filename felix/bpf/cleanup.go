@@ -20,6 +20,8 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 )
 
 func CleanUpCalicoPins(dir string) {
@@ -28,7 +30,11 @@ func CleanUpCalicoPins(dir string) {
 		if err != nil {
 			return err
 		}
-		if path == "/sys/fs/bpf/calico/sockmap" {
+		if path == filepath.Join(bpfdefs.BPFfsPath(), "calico", "sockmap") {
+			return filepath.SkipDir
+		}
+		if path == bpfdefs.InstancesPinDir() && !strings.HasPrefix(dir, path) {
+			// The pins of the other Felix instances on this host.
 			return filepath.SkipDir
 		}
 		if strings.HasPrefix(info.Name(), "cali_") || strings.HasPrefix(info.Name(), "calico_") ||
@@ -16,6 +16,7 @@ package conntrack
 
 import (
 	"net"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -59,6 +60,8 @@ type LivenessScanner struct {
 	dsr      bool
 	time     timeshim.Interface
 
+	// ktimeLock protects the cached kernel time, sharded conntrack tables are scanned concurrently.
+	ktimeLock sync.Mutex
 	// goTimeOfLastKTimeLookup is the go timestamp of the last time we looked up the kernel time.
 	// We cache the kernel time because it's expensive to look up (vs looking up a go timestamp which uses vdso).
 	goTimeOfLastKTimeLookup time.Time
@@ -87,11 +90,13 @@ func WithTimeShim(shim timeshim.Interface) LivenessScannerOpt {
 }
 
 func (l *LivenessScanner) Check(ctKey KeyInterface, ctVal ValueInterface, get EntryGet) ScanVerdict {
+	l.ktimeLock.Lock()
 	if l.cachedKTime == 0 || l.time.Since(l.goTimeOfLastKTimeLookup) > time.Second {
 		l.cachedKTime = l.time.KTimeNanos()
 		l.goTimeOfLastKTimeLookup = l.time.Now()
 	}
 	now := l.cachedKTime
+	l.ktimeLock.Unlock()
 
 	debug := log.GetLevel() >= log.DebugLevel
 
@@ -410,3 +415,50 @@ func (sns *StaleNATScanner) IterationStart() {
 func (sns *StaleNATScanner) IterationEnd() {
 	sns.natChecker.ConntrackScanEnd()
 }
+
+// IPFlushScanner removes all entries of flows that involve any of the given
+// IPs, either directly in the key or, for NAT forward entries, as the address
+// the flow was NATed to.
+type IPFlushScanner struct {
+	ips map[string]struct{}
+}
+
+// NewIPFlushScanner returns an EntryScanner that deletes entries of flows that
+// involve any of the given IPs.
+func NewIPFlushScanner(ips []net.IP) *IPFlushScanner {
+	s := &IPFlushScanner{
+		ips: make(map[string]struct{}, len(ips)),
+	}
+	for _, ip := range ips {
+		s.ips[ipKey(ip)] = struct{}{}
+	}
+	return s
+}
+
+func ipKey(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return string(ip4)
+	}
+	return string(ip.To16())
+}
+
+func (s *IPFlushScanner) has(ip net.IP) bool {
+	_, ok := s.ips[ipKey(ip)]
+	return ok
+}
+
+// Check checks the conntrack entry
+func (s *IPFlushScanner) Check(k KeyInterface, v ValueInterface, _ EntryGet) ScanVerdict {
+	if s.has(k.AddrA()) || s.has(k.AddrB()) {
+		return ScanVerdictDelete
+	}
+
+	if v.Type() == TypeNATForward {
+		rev := v.ReverseNATKey()
+		if s.has(rev.AddrA()) || s.has(rev.AddrB()) {
+			return ScanVerdictDelete
+		}
+	}
+
+	return ScanVerdictOK
+}
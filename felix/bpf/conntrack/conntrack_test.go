@@ -276,6 +276,208 @@ var _ = Describe("BPF Conntrack StaleNATScanner", func() {
 	)
 })
 
+var _ = Describe("BPF Conntrack IPFlushScanner", func() {
+	clientIP := net.IPv4(1, 1, 1, 1)
+	svcIP := net.IPv4(4, 3, 2, 1)
+	backendIP := net.IPv4(2, 2, 2, 2)
+	otherIP := net.IPv4(3, 3, 3, 3)
+
+	DescribeTable("entries",
+		func(ips []net.IP, k conntrack.Key, v conntrack.Value, verdict conntrack.ScanVerdict) {
+			scanner := conntrack.NewIPFlushScanner(ips)
+			Expect(scanner.Check(k, v, nil)).To(Equal(verdict))
+		},
+		Entry("normal entry with IP as A",
+			[]net.IP{clientIP},
+			conntrack.NewKey(6, clientIP, 1111, otherIP, 80),
+			makeValue(now-1, now-1, conntrack.Leg{}, conntrack.Leg{}),
+			conntrack.ScanVerdictDelete,
+		),
+		Entry("normal entry with IP as B",
+			[]net.IP{clientIP},
+			conntrack.NewKey(6, otherIP, 80, clientIP, 1111),
+			makeValue(now-1, now-1, conntrack.Leg{}, conntrack.Leg{}),
+			conntrack.ScanVerdictDelete,
+		),
+		Entry("normal entry not involving the IPs",
+			[]net.IP{clientIP, backendIP},
+			conntrack.NewKey(6, otherIP, 80, svcIP, 1111),
+			makeValue(now-1, now-1, conntrack.Leg{}, conntrack.Leg{}),
+			conntrack.ScanVerdictOK,
+		),
+		Entry("forward entry NATed to the IP",
+			[]net.IP{backendIP},
+			conntrack.NewKey(6, clientIP, 1111, svcIP, 80),
+			conntrack.NewValueNATForward(0, 0, 0, conntrack.NewKey(6, clientIP, 1111, backendIP, 8080)),
+			conntrack.ScanVerdictDelete,
+		),
+		Entry("forward entry NATed elsewhere",
+			[]net.IP{otherIP},
+			conntrack.NewKey(6, clientIP, 1111, svcIP, 80),
+			conntrack.NewValueNATForward(0, 0, 0, conntrack.NewKey(6, clientIP, 1111, backendIP, 8080)),
+			conntrack.ScanVerdictOK,
+		),
+		Entry("reverse entry of the IP",
+			[]net.IP{backendIP},
+			conntrack.NewKey(6, clientIP, 1111, backendIP, 8080),
+			conntrack.NewValueNATReverse(0, 0, 0, conntrack.Leg{}, conntrack.Leg{}, nil, svcIP, 80),
+			conntrack.ScanVerdictDelete,
+		),
+	)
+})
+
+type dummyServiceNamer map[string]string
+
+func (d dummyServiceNamer) ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool) {
+	svc, ok := d[fmt.Sprintf("%s:%d/%d", ip, port, proto)]
+	return svc, ok
+}
+
+var _ = Describe("BPF Conntrack ServiceStatsScanner", func() {
+	clientIP := net.IPv4(1, 1, 1, 1)
+	svcIP := net.IPv4(4, 3, 2, 1)
+	otherSvcIP := net.IPv4(4, 3, 2, 2)
+	backendIP := net.IPv4(2, 2, 2, 2)
+
+	synAck := conntrack.Leg{SynSeen: true, AckSeen: true}
+
+	check := func(scanner *conntrack.ServiceStatsScanner, clientPort uint16, frontend net.IP, a2b, b2a conntrack.Leg) {
+		k := conntrack.NewKey(conntrack.ProtoTCP, clientIP, clientPort, backendIP, 8080)
+		v := conntrack.NewValueNATReverse(0, 0, 0, a2b, b2a, nil, frontend, 80)
+		Expect(scanner.Check(k, v, nil)).To(Equal(conntrack.ScanVerdictOK))
+	}
+
+	It("should count the connections to each service by state", func() {
+		scanner := conntrack.NewServiceStatsScanner(dummyServiceNamer{
+			"4.3.2.1:80/6": "default/svc:http",
+			"4.3.2.2:80/6": "default/other:http",
+		}, 4)
+
+		scanner.IterationStart()
+		check(scanner, 1001, svcIP, synAck, synAck)
+		check(scanner, 1002, svcIP, synAck, synAck)
+		check(scanner, 1003, svcIP, conntrack.Leg{SynSeen: true}, conntrack.Leg{})
+		check(scanner, 1004, svcIP, conntrack.Leg{SynSeen: true, AckSeen: true, FinSeen: true}, synAck)
+		check(scanner, 1005, otherSvcIP, synAck, conntrack.Leg{SynSeen: true, AckSeen: true, RstSeen: true})
+		// Not a service.
+		check(scanner, 1006, net.IPv4(5, 5, 5, 5), synAck, synAck)
+		// Not TCP.
+		Expect(scanner.Check(
+			conntrack.NewKey(conntrack.ProtoUDP, clientIP, 1007, backendIP, 8080),
+			conntrack.NewValueNATReverse(0, 0, 0, conntrack.Leg{}, conntrack.Leg{}, nil, svcIP, 80),
+			nil,
+		)).To(Equal(conntrack.ScanVerdictOK))
+		// Not a reverse entry.
+		Expect(scanner.Check(
+			conntrack.NewKey(conntrack.ProtoTCP, clientIP, 1008, svcIP, 80),
+			conntrack.NewValueNATForward(0, 0, 0, conntrack.NewKey(conntrack.ProtoTCP, clientIP, 1008, backendIP, 8080)),
+			nil,
+		)).To(Equal(conntrack.ScanVerdictOK))
+		scanner.IterationEnd()
+
+		Expect(scanner.Counts()).To(Equal(map[string]conntrack.SvcConnCounts{
+			"default/svc:http":   {Established: 2, HalfOpen: 1, Closing: 1},
+			"default/other:http": {Closing: 1},
+		}))
+
+		By("forgetting services without connections")
+		scanner.IterationStart()
+		check(scanner, 1001, svcIP, synAck, synAck)
+		scanner.IterationEnd()
+
+		Expect(scanner.Counts()).To(Equal(map[string]conntrack.SvcConnCounts{
+			"default/svc:http": {Established: 1},
+		}))
+	})
+})
+
+var _ = Describe("BPF Conntrack sharded map", func() {
+	var shards []*mock.Map
+	var ctMap *conntrack.ShardedMap
+
+	keys := []conntrack.Key{
+		conntrack.NewKey(conntrack.ProtoTCP, ip1, 1234, ip2, 3456),
+		conntrack.NewKey(conntrack.ProtoTCP, ip1, 1235, ip2, 3456),
+		conntrack.NewKey(conntrack.ProtoTCP, ip1, 1236, ip2, 3456),
+		conntrack.NewKey(conntrack.ProtoUDP, ip1, 1234, ip2, 3456),
+		conntrack.NewKey(conntrack.ProtoUDP, ip2, 53, ip1, 5353),
+		conntrack.NewKey(conntrack.ProtoICMP, ip1, 0, ip2, 0),
+		conntrack.NewKey(253, ip2, 0, ip1, 0),
+	}
+
+	BeforeEach(func() {
+		shards = nil
+		var ms []maps.Map
+		for i := 0; i < 4; i++ {
+			m := mock.NewMockMap(conntrack.MapParams)
+			shards = append(shards, m)
+			ms = append(ms, m)
+		}
+		ctMap = conntrack.NewShardedMap(len(ms), ms...)
+	})
+
+	It("should put everything in the first shard when not sharded", func() {
+		for _, k := range keys {
+			Expect(conntrack.ShardOf(k.AsBytes(), 1)).To(Equal(0))
+		}
+	})
+
+	It("should update entries in their shard", func() {
+		for _, k := range keys {
+			Expect(ctMap.Update(k.AsBytes(), udpJustCreated[:])).To(Succeed())
+		}
+
+		used := map[int]bool{}
+		for _, k := range keys {
+			home := conntrack.ShardOf(k.AsBytes(), len(shards))
+			Expect(home).To(BeNumerically("<", len(shards)))
+			used[home] = true
+			for i, s := range shards {
+				Expect(s.ContainsKey(k.AsBytes())).To(Equal(i == home))
+			}
+			v, err := ctMap.Get(k.AsBytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal(udpJustCreated[:]))
+		}
+		Expect(len(used)).To(BeNumerically(">", 1), "all keys hashed to the same shard")
+	})
+
+	It("should find entries outside of their shard", func() {
+		k := keys[0]
+		other := (conntrack.ShardOf(k.AsBytes(), len(shards)) + 1) % len(shards)
+		Expect(shards[other].Update(k.AsBytes(), udpJustCreated[:])).To(Succeed())
+
+		v, err := ctMap.Get(k.AsBytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(udpJustCreated[:]))
+
+		_, err = ctMap.Get(keys[1].AsBytes())
+		Expect(maps.IsNotExists(err)).To(BeTrue())
+	})
+
+	It("should scan all the shards", func() {
+		mockTime := mocktime.New()
+		lc := conntrack.NewLivenessScanner(timeouts, false, conntrack.WithTimeShim(mockTime))
+		scanner := conntrack.NewScanner(ctMap, conntrack.KeyFromBytes, conntrack.ValueFromBytes, lc)
+
+		for _, k := range keys {
+			Expect(ctMap.Update(k.AsBytes(), udpJustCreated[:])).To(Succeed())
+		}
+
+		scanner.Scan()
+		for _, k := range keys {
+			_, err := ctMap.Get(k.AsBytes())
+			Expect(err).NotTo(HaveOccurred(), "Scan() deleted entry unexpectedly")
+		}
+
+		mockTime.IncrementTime(2 * time.Hour)
+		scanner.Scan()
+		for _, s := range shards {
+			Expect(s.IsEmpty()).To(BeTrue(), "Scan() should have cleaned up all entries")
+		}
+	})
+})
+
 var _ = Describe("BPF Conntrack upgrade entries", func() {
 	k2 := v2.NewKey(1, net.ParseIP("10.0.0.1"), 0, net.ParseIP("10.0.0.2"), 0)
 	k3 := conntrack.NewKey(1, net.ParseIP("10.0.0.1"), 0, net.ParseIP("10.0.0.2"), 0)
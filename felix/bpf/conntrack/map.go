@@ -31,11 +31,6 @@ func init() {
 	SetMapSize(MaxEntries)
 }
 
-func SetMapSize(size int) {
-	maps.SetSize(curVer.MapParams.VersionedName(), size)
-	maps.SetSize(curVer.MapParamsV6.VersionedName(), size)
-}
-
 const KeySize = curVer.KeySize
 const KeyV6Size = curVer.KeyV6Size
 const ValueSize = curVer.ValueSize
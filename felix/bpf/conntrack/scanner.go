@@ -85,14 +85,34 @@ func NewScanner(ctMap maps.Map, kfb func([]byte) KeyInterface, vfb func([]byte)
 	}
 }
 
-// Scan executes a scanning iteration
+// Scan executes a scanning iteration. If the conntrack table is split into
+// shards, the shards are scanned concurrently so the EntryScanners must be safe
+// to call from multiple goroutines.
 func (s *Scanner) Scan() {
 	s.iterStart()
 	defer s.iterEnd()
 
+	sm, ok := s.ctMap.(*ShardedMap)
+	if !ok || sm.active <= 1 {
+		s.scan(s.ctMap)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, shard := range sm.Shards() {
+		wg.Add(1)
+		go func(m maps.Map) {
+			defer wg.Done()
+			s.scan(m)
+		}(shard)
+	}
+	wg.Wait()
+}
+
+func (s *Scanner) scan(ctMap maps.Map) {
 	debug := log.GetLevel() >= log.DebugLevel
 
-	err := s.ctMap.Iter(func(k, v []byte) maps.IteratorAction {
+	err := ctMap.Iter(func(k, v []byte) maps.IteratorAction {
 		ctKey := s.keyFromBytes(k)
 		ctVal := s.valueFromBytes(v)
 
@@ -115,7 +135,7 @@ func (s *Scanner) Scan() {
 	})
 
 	if err != nil {
-		log.WithError(err).Warn("Failed to iterate over conntrack map")
+		log.WithError(err).WithField("map", ctMap.GetName()).Warn("Failed to iterate over conntrack map")
 	}
 }
 
@@ -0,0 +1,397 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+// MaxShards is the maximum number of maps that the conntrack table can be split
+// into. It must match CT_MAX_SHARDS in the BPF programs.
+const MaxShards = 8
+
+var (
+	shardsLock sync.Mutex
+	numShards  = 1
+	shardSize  = MaxEntries
+)
+
+// SetShards sets the number of maps that the conntrack table is split into.
+// Flows are distributed among the shards by a hash of their key. Each of the
+// shards has the size set by SetMapSize, the unused shards hold a single entry.
+func SetShards(n int) {
+	if n < 1 || n > MaxShards {
+		log.WithField("shards", n).Panic("Bug: invalid number of conntrack shards")
+	}
+
+	shardsLock.Lock()
+	defer shardsLock.Unlock()
+
+	numShards = n
+	setShardSizes()
+}
+
+// NumShards returns the number of conntrack shards in use.
+func NumShards() int {
+	shardsLock.Lock()
+	defer shardsLock.Unlock()
+
+	return numShards
+}
+
+func SetMapSize(size int) {
+	shardsLock.Lock()
+	defer shardsLock.Unlock()
+
+	shardSize = size
+	setShardSizes()
+}
+
+// MapSize returns the size of each of the conntrack shards in use.
+func MapSize() int {
+	shardsLock.Lock()
+	defer shardsLock.Unlock()
+
+	return shardSize
+}
+
+func setShardSizes() {
+	maps.SetSize(MapParams.VersionedName(), shardSize)
+	maps.SetSize(MapParamsV6.VersionedName(), shardSize)
+
+	for i := 1; i < MaxShards; i++ {
+		size := shardSize
+		if i >= numShards {
+			size = 1
+		}
+		for _, params := range []maps.MapParameters{shardParams(MapParams, i), shardParams(MapParamsV6, i)} {
+			maps.SetSize(params.VersionedName(), size)
+		}
+	}
+}
+
+// shardParams returns the parameters of the i-th shard of the map, i > 0. The
+// first shard is the map itself.
+func shardParams(params maps.MapParameters, i int) maps.MapParameters {
+	params.Name = fmt.Sprintf("%s_%d", params.VersionedName(), i)
+	params.Version = 1
+	return params
+}
+
+// ShardOf returns the shard that the conntrack key belongs to when the table
+// is split into n shards. It must match ct_shard() in the BPF programs - it is
+// a FNV-1a hash over the 32bit words of the key.
+func ShardOf(k []byte, n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	h := uint32(2166136261)
+	for i := 0; i+4 <= len(k); i += 4 {
+		h ^= binary.LittleEndian.Uint32(k[i:])
+		h *= 16777619
+	}
+
+	return int(h % uint32(n))
+}
+
+// ShardedMap is a conntrack table split into several maps. It implements
+// maps.Map so that it can be used in place of a single conntrack map. Updates
+// go to the shard that the key belongs to while lookups and deletes fall back
+// to the other shards so that tools that do not know the number of shards in
+// use still find every entry.
+type ShardedMap struct {
+	shards []maps.Map
+	active int
+}
+
+// MapWithShards returns the IPv4 conntrack table including all its shards.
+func MapWithShards() maps.Map {
+	return newShardedMap(Map(), MapParams)
+}
+
+// MapV6WithShards returns the IPv6 conntrack table including all its shards.
+func MapV6WithShards() maps.Map {
+	return newShardedMap(MapV6(), MapParamsV6)
+}
+
+func newShardedMap(first maps.Map, params maps.MapParameters) *ShardedMap {
+	m := &ShardedMap{
+		shards: []maps.Map{first},
+		active: NumShards(),
+	}
+	for i := 1; i < MaxShards; i++ {
+		m.shards = append(m.shards, maps.NewPinnedMap(shardParams(params, i)))
+	}
+	return m
+}
+
+// NewShardedMap returns a ShardedMap over the given maps of which the first
+// active ones are in use.
+func NewShardedMap(active int, shards ...maps.Map) *ShardedMap {
+	return &ShardedMap{
+		shards: shards,
+		active: active,
+	}
+}
+
+// Shards returns all the maps of the table, including the unused ones.
+func (m *ShardedMap) Shards() []maps.Map {
+	return m.shards
+}
+
+func (m *ShardedMap) home(k []byte) maps.Map {
+	return m.shards[ShardOf(k, m.active)]
+}
+
+func (m *ShardedMap) GetName() string {
+	return m.shards[0].GetName()
+}
+
+// EstimatedMemory returns the estimated kernel memory of all the shards.
+func (m *ShardedMap) EstimatedMemory() uint64 {
+	var mem uint64
+	for _, s := range m.shards {
+		if pm, ok := s.(*maps.PinnedMap); ok {
+			mem += pm.EstimatedMemory()
+		}
+	}
+	return mem
+}
+
+// EnsureExists opens all the shards, creating and pinning them if needed. If
+// the number of shards in use changed, it moves the existing entries to the
+// shards where the BPF programs look for them.
+func (m *ShardedMap) EnsureExists() error {
+	changed := false
+	for i, s := range m.shards {
+		size, err := pinnedSize(s)
+		if err != nil {
+			return err
+		}
+		if i > 0 && (size > 1) != (i < m.active) {
+			changed = true
+		}
+	}
+
+	for _, s := range m.shards[:m.active] {
+		if err := s.EnsureExists(); err != nil {
+			return err
+		}
+	}
+
+	// The unused shards shrink to a single entry, move their entries out first.
+	for _, s := range m.shards[m.active:] {
+		if err := m.drain(s); err != nil {
+			return err
+		}
+		if err := s.EnsureExists(); err != nil {
+			return err
+		}
+	}
+
+	if changed {
+		return m.reshard(m.active)
+	}
+
+	// Entries of an upgraded map are copied to the first shard.
+	return m.reshard(1)
+}
+
+// pinnedSize returns the size of the pinned map or 0 if it does not exist yet.
+func pinnedSize(m maps.Map) (int, error) {
+	pm, ok := m.(*maps.PinnedMap)
+	if !ok {
+		return 0, nil
+	}
+
+	tmp := maps.NewPinnedMap(pm.MapParameters)
+	if err := tmp.Open(); err != nil {
+		return 0, nil
+	}
+	defer tmp.Close()
+
+	info, err := maps.GetMapInfo(tmp.MapFD())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get info of map %s: %w", pm.GetName(), err)
+	}
+
+	return info.MaxEntries, nil
+}
+
+// drain moves the entries of an existing, unused shard to the shards in use.
+func (m *ShardedMap) drain(s maps.Map) error {
+	size, err := pinnedSize(s)
+	if err != nil || size <= 1 {
+		return err
+	}
+
+	pm := s.(*maps.PinnedMap)
+	tmp := maps.NewPinnedMap(pm.MapParameters)
+	tmp.MaxEntries = size
+	if err := tmp.Open(); err != nil {
+		return err
+	}
+	defer tmp.Close()
+
+	// The shard is about to be shrunk so drop entries that do not fit
+	// elsewhere.
+	return m.moveEntries(tmp, -1, true)
+}
+
+// reshard moves the entries of the first n shards that belong to another
+// shard.
+func (m *ShardedMap) reshard(n int) error {
+	if m.active <= 1 {
+		// Everything belongs to the first shard.
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		if err := m.moveEntries(m.shards[i], i, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ShardedMap) moveEntries(from maps.Map, idx int, drop bool) error {
+	moved := 0
+	err := from.Iter(func(k, v []byte) maps.IteratorAction {
+		home := ShardOf(k, m.active)
+		if home == idx {
+			return maps.IterNone
+		}
+		if err := m.shards[home].Update(k, v); err != nil {
+			log.WithError(err).WithField("shard", home).Warn("Failed to move conntrack entry to its shard.")
+			if drop {
+				return maps.IterDelete
+			}
+			// BPF programs do not see the entry but the scanner cleans it
+			// up eventually.
+			return maps.IterNone
+		}
+		moved++
+		return maps.IterDelete
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move entries of %s: %w", from.GetName(), err)
+	}
+
+	if moved > 0 {
+		log.WithFields(log.Fields{
+			"from":   from.GetName(),
+			"moved":  moved,
+			"shards": m.active,
+		}).Info("Moved conntrack entries to their shards.")
+	}
+
+	return nil
+}
+
+func (m *ShardedMap) Open() error {
+	for _, s := range m.shards {
+		if err := s.Open(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ShardedMap) Close() error {
+	var ret error
+	for _, s := range m.shards {
+		if err := s.Close(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
+
+// MapFD returns the file descriptor of the first shard.
+func (m *ShardedMap) MapFD() maps.FD {
+	return m.shards[0].MapFD()
+}
+
+// Path returns the path of the first shard.
+func (m *ShardedMap) Path() string {
+	return m.shards[0].Path()
+}
+
+func (m *ShardedMap) CopyDeltaFromOldMap() error {
+	if err := m.shards[0].CopyDeltaFromOldMap(); err != nil {
+		return err
+	}
+	return m.reshard(1)
+}
+
+// Iter iterates over all the shards, one after the other.
+func (m *ShardedMap) Iter(f maps.IterCallback) error {
+	for _, s := range m.shards {
+		if err := s.Iter(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ShardedMap) Update(k, v []byte) error {
+	return m.home(k).Update(k, v)
+}
+
+func (m *ShardedMap) Get(k []byte) ([]byte, error) {
+	home := m.home(k)
+	v, err := home.Get(k)
+	if err == nil || !maps.IsNotExists(err) {
+		return v, err
+	}
+
+	for _, s := range m.shards {
+		if s == home {
+			continue
+		}
+		if v, err := s.Get(k); err == nil {
+			return v, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (m *ShardedMap) Delete(k []byte) error {
+	home := m.home(k)
+	err := home.Delete(k)
+	if err == nil || !maps.IsNotExists(err) {
+		return err
+	}
+
+	for _, s := range m.shards {
+		if s == home {
+			continue
+		}
+		if s.Delete(k) == nil {
+			return nil
+		}
+	}
+
+	return err
+}
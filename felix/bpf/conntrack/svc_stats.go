@@ -0,0 +1,156 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	SvcConnEstablished = "established"
+	SvcConnHalfOpen    = "half-open"
+	SvcConnClosing     = "closing"
+)
+
+var gaugeServiceConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "felix_bpf_conntrack_service_connections",
+	Help: "Number of TCP connections to each service in the BPF conntrack table, by state.",
+}, []string{"ip_version", "service", "state"})
+
+func init() {
+	prometheus.MustRegister(gaugeServiceConnections)
+}
+
+// ServiceNamer maps a service frontend to the name of its service.
+type ServiceNamer interface {
+	ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool)
+}
+
+// SvcConnCounts are the numbers of connections to a service in each state.
+type SvcConnCounts struct {
+	Established int
+	HalfOpen    int
+	Closing     int
+}
+
+// ServiceStatsScanner counts the TCP connections to each service by their
+// state while the table is swept, so that SYN floods and connection leaks are
+// visible per service. It does not delete any entries.
+//
+// The ServiceNamer is only valid during a scan of the StaleNATScanner, so this
+// scanner must be added after it, using the same NATChecker.
+type ServiceStatsScanner struct {
+	svcNamer  ServiceNamer
+	ipVersion string
+
+	// lock protects counts, sharded conntrack tables are scanned concurrently.
+	lock   sync.Mutex
+	counts map[string]*SvcConnCounts
+	// exported are the services that have been exported after the previous
+	// scan.
+	exported map[string]SvcConnCounts
+}
+
+// NewServiceStatsScanner returns an EntryScanner that exports the number of
+// connections to each service, named by the provided ServiceNamer.
+func NewServiceStatsScanner(svcNamer ServiceNamer, ipVersion int) *ServiceStatsScanner {
+	return &ServiceStatsScanner{
+		svcNamer:  svcNamer,
+		ipVersion: strconv.Itoa(ipVersion),
+		exported:  map[string]SvcConnCounts{},
+	}
+}
+
+// Check checks the conntrack entry
+func (s *ServiceStatsScanner) Check(k KeyInterface, v ValueInterface, _ EntryGet) ScanVerdict {
+	// Each NATed connection has exactly one reverse entry, which also keeps
+	// track of the state of the connection.
+	if v.Type() != TypeNATReverse || k.Proto() != ProtoTCP {
+		return ScanVerdictOK
+	}
+
+	svc, ok := s.svcNamer.ConntrackFrontendService(v.OrigIP(), v.OrigPort(), k.Proto())
+	if !ok {
+		return ScanVerdictOK
+	}
+
+	data := v.Data()
+	dsr := v.IsForwardDSR()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c := s.counts[svc]
+	if c == nil {
+		c = &SvcConnCounts{}
+		s.counts[svc] = c
+	}
+	switch {
+	case data.RSTSeen() || data.FINsSeenDSR():
+		c.Closing++
+	case data.Established() || dsr:
+		c.Established++
+	default:
+		c.HalfOpen++
+	}
+
+	return ScanVerdictOK
+}
+
+// IterationStart satisfies EntryScannerSynced
+func (s *ServiceStatsScanner) IterationStart() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.counts = map[string]*SvcConnCounts{}
+}
+
+// IterationEnd satisfies EntryScannerSynced
+func (s *ServiceStatsScanner) IterationEnd() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for svc := range s.exported {
+		if _, ok := s.counts[svc]; !ok {
+			for _, state := range []string{SvcConnEstablished, SvcConnHalfOpen, SvcConnClosing} {
+				gaugeServiceConnections.DeleteLabelValues(s.ipVersion, svc, state)
+			}
+			delete(s.exported, svc)
+		}
+	}
+	for svc, c := range s.counts {
+		gaugeServiceConnections.WithLabelValues(s.ipVersion, svc, SvcConnEstablished).Set(float64(c.Established))
+		gaugeServiceConnections.WithLabelValues(s.ipVersion, svc, SvcConnHalfOpen).Set(float64(c.HalfOpen))
+		gaugeServiceConnections.WithLabelValues(s.ipVersion, svc, SvcConnClosing).Set(float64(c.Closing))
+		s.exported[svc] = *c
+	}
+	s.counts = nil
+}
+
+// Counts returns the numbers of connections to each service found by the last
+// scan.
+func (s *ServiceStatsScanner) Counts() map[string]SvcConnCounts {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	counts := make(map[string]SvcConnCounts, len(s.exported))
+	for svc, c := range s.exported {
+		counts[svc] = c
+	}
+	return counts
+}
@@ -211,7 +211,7 @@ const (
 	FlagNATFwdDsr uint16 = (1 << 1)
 	FlagNATNPFwd  uint16 = (1 << 2)
 	FlagSkipFIB   uint16 = (1 << 3)
-	FlagReserved4 uint16 = (1 << 4)
+	FlagProxyProt uint16 = (1 << 4)
 	FlagReserved5 uint16 = (1 << 5)
 	FlagExtLocal  uint16 = (1 << 6)
 	FlagViaNATIf  uint16 = (1 << 7)
@@ -466,6 +466,10 @@ func (e Value) String() string {
 			flagsStr += " skip-fib"
 		}
 
+		if flags&FlagProxyProt != 0 {
+			flagsStr += " proxy-proto"
+		}
+
 		if flags&FlagExtLocal != 0 {
 			flagsStr += " ext-local"
 		}
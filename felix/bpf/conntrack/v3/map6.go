@@ -319,6 +319,10 @@ func (e ValueV6) String() string {
 			flagsStr += " skip-fib"
 		}
 
+		if flags&FlagProxyProt != 0 {
+			flagsStr += " proxy-proto"
+		}
+
 		if flags&FlagExtLocal != 0 {
 			flagsStr += " ext-local"
 		}
@@ -27,7 +27,7 @@ import (
 )
 
 const (
-	MaxCounterNumber    int = 14
+	MaxCounterNumber    int = 16
 	counterMapKeySize   int = 8
 	counterMapValueSize int = 8
 )
@@ -73,6 +73,7 @@ const (
 	DroppedUnauthSource
 	DroppedUnknownRoute
 	DroppedBlackholeRoute
+	DroppedFragment
 )
 
 type Description struct {
@@ -155,6 +156,10 @@ var descriptions DescList = DescList{
 		Counter:  DroppedBlackholeRoute,
 		Category: "Dropped", Caption: "packets hitting blackhole route",
 	},
+	{
+		Counter:  DroppedFragment,
+		Category: "Dropped", Caption: "IP fragments",
+	},
 }
 
 func Descriptions() DescList {
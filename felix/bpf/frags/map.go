@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frags
+
+import (
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+//	struct frags4_key {
+//		__be32 src;
+//		__be32 dst;
+//		__u16 id;
+//		__u8 proto;
+//		__u8 __pad;
+//	};
+const KeySize = 12
+
+//	struct frags4_value {
+//		__u16 sport;
+//		__u16 dport;
+//	};
+const ValueSize = 4
+
+// MapParameters describe the map in which the TC programs remember the ports
+// of the first fragment of an IPv4 datagram so that they can conntrack and NAT
+// the rest of its fragments, which do not carry the L4 header.
+var MapParameters = maps.MapParameters{
+	Type:       "lru_hash",
+	KeySize:    KeySize,
+	ValueSize:  ValueSize,
+	MaxEntries: 10000,
+	Name:       "cali_v4_frags",
+}
+
+func Map() maps.Map {
+	return maps.NewPinnedMap(MapParameters)
+}
@@ -89,6 +89,24 @@ func (at AttachType) hasHostConflictProg() bool {
 	return at.Hook == Egress
 }
 
+// hasNAT64Prog must match HAS_NAT64_PROG in the BPF programs. IPv6 packets are
+// translated to IPv4 when they leave workloads, the replies are translated back
+// when they arrive at the host.
+func (at AttachType) hasNAT64Prog() bool {
+	if at.Hook != Ingress {
+		return false
+	}
+
+	switch at.Type {
+	case tcdefs.EpTypeWorkload:
+		return at.Family == 6
+	case tcdefs.EpTypeHost:
+		return at.Family == 4
+	}
+
+	return false
+}
+
 type DefPolicy int
 
 const (
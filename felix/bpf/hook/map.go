@@ -40,6 +40,7 @@ const (
 	SubProgTCHostCtConflict
 	SubProgIcmpInnerNat
 	SubProgNewFlow
+	SubProgNAT64
 	SubProgTCMainDebug
 
 	SubProgXDPMain    = SubProgTCMain
@@ -57,6 +58,7 @@ var tcSubProgNames = []string{
 	"calico_tc_host_ct_conflict",
 	"calico_tc_skb_icmp_inner_nat",
 	"calico_tc_skb_new_flow_entrypoint",
+	"calico_tc_skb_nat64",
 }
 
 var xdpSubProgNames = []string{
@@ -152,11 +154,11 @@ func (pm *ProgramsMap) loadObj(at AttachType, file string) (Layout, error) {
 		if err := pm.setMapSize(m); err != nil {
 			return nil, fmt.Errorf("error setting map size %s : %w", mapName, err)
 		}
-		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir, mapName)); err != nil {
+		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir(), mapName)); err != nil {
 			return nil, fmt.Errorf("error pinning map %s: %w", mapName, err)
 		}
 		log.Debugf("map %s k %d v %d pinned to %s for generic object file %s",
-			mapName, m.KeySize(), m.ValueSize(), path.Join(bpfdefs.GlobalPinDir, mapName), file)
+			mapName, m.KeySize(), m.ValueSize(), path.Join(bpfdefs.GlobalPinDir(), mapName), file)
 	}
 
 	if err := obj.Load(); err != nil {
@@ -198,6 +200,10 @@ func (pm *ProgramsMap) newLayout(at AttachType, obj *libbpf.Obj) (Layout, error)
 			continue
 		}
 
+		if SubProg(idx) == SubProgNAT64 && !at.hasNAT64Prog() {
+			continue
+		}
+
 		err := obj.UpdateJumpMap(mapName, subprog, pm.nextIdx)
 		if err != nil {
 			return nil, fmt.Errorf("error updating programs map with %s/%s at %d: %w",
@@ -0,0 +1,40 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icmp
+
+import (
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+//	struct icmp_rl_state {
+//		__u64 last_ns;
+//		__u64 tokens;
+//	};
+const RateLimitValueSize = 16
+
+// RateLimitMapParameters describe the per-CPU token bucket that limits the
+// ICMP errors, like Fragmentation Needed or Packet Too Big, that the TC
+// programs generate.
+var RateLimitMapParameters = maps.MapParameters{
+	Type:       "percpu_array",
+	KeySize:    4,
+	ValueSize:  RateLimitValueSize,
+	MaxEntries: 1,
+	Name:       "cali_icmp_rl",
+}
+
+func RateLimitMap() maps.Map {
+	return maps.NewPinnedMap(RateLimitMapParameters)
+}
@@ -185,6 +185,51 @@ func (o *Obj) AttachClassifier(secName, ifName string, ingress bool) (int, int,
 	return int(ret.prog_id), int(ret.priority), int(ret.handle), nil
 }
 
+// AttachTCX attaches the program to the tcx hook of the interface and returns
+// the fd of the created link. The link is owned by the caller.
+func (o *Obj) AttachTCX(progName, ifName string, ingress bool) (int, error) {
+	cProgName := C.CString(progName)
+	cIfName := C.CString(ifName)
+	defer C.free(unsafe.Pointer(cProgName))
+	defer C.free(unsafe.Pointer(cIfName))
+	ifIndex, err := C.if_nametoindex(cIfName)
+	if err != nil {
+		return -1, err
+	}
+
+	fd, err := C.bpf_tcx_program_attach(o.obj, cProgName, C.int(ifIndex), C.bool(ingress))
+	if fd < 0 || err != nil {
+		return -1, fmt.Errorf("error attaching tcx program %s: %w", progName, err)
+	}
+
+	return int(fd), nil
+}
+
+// UpdateTCXLink atomically replaces the program of an existing tcx link.
+func (o *Obj) UpdateTCXLink(linkFD int, progName string) error {
+	cProgName := C.CString(progName)
+	defer C.free(unsafe.Pointer(cProgName))
+
+	ret, err := C.bpf_tcx_link_update(C.int(linkFD), o.obj, cProgName)
+	if ret != 0 || err != nil {
+		return fmt.Errorf("error updating tcx link with program %s: %w", progName, err)
+	}
+
+	return nil
+}
+
+func (o *Obj) ProgramID(progName string) (int, error) {
+	cProgName := C.CString(progName)
+	defer C.free(unsafe.Pointer(cProgName))
+
+	id, err := C.bpf_program_id(o.obj, cProgName)
+	if id < 0 || err != nil {
+		return -1, fmt.Errorf("error querying program %s id: %w", progName, err)
+	}
+
+	return int(id), nil
+}
+
 func (o *Obj) AttachXDP(ifName, progName string, oldID int, mode uint) (int, error) {
 	cProgName := C.CString(progName)
 	cIfName := C.CString(ifName)
@@ -381,6 +426,9 @@ const (
 	GlobalsRPFOptionStrict  uint32 = C.CALI_GLOBALS_RPF_OPTION_STRICT
 	GlobalsNoDSRCidrs       uint32 = C.CALI_GLOBALS_NO_DSR_CIDRS
 	GlobalsLoUDPOnly        uint32 = C.CALI_GLOBALS_LO_UDP_ONLY
+	GlobalsWireguardEnabled uint32 = C.CALI_GLOBALS_WIREGUARD_ENABLED
+	GlobalsDropFragments    uint32 = C.CALI_GLOBALS_DROP_FRAGMENTS
+	GlobalsNAT64Enabled     uint32 = C.CALI_GLOBALS_NAT64_ENABLED
 )
 
 func TcSetGlobals(
@@ -419,8 +467,11 @@ func TcSetGlobals(
 		C.uint(globalData.Flags),
 		C.ushort(globalData.WgPort),
 		C.ushort(globalData.Wg6Port),
+		C.ushort(globalData.CTShards),
 		C.uint(globalData.NatIn),
 		C.uint(globalData.NatOut),
+		(*C.char)(unsafe.Pointer(&globalData.NAT64Prefix[0])),
+		(*C.char)(unsafe.Pointer(&globalData.NAT64IP[0])),
 		C.uint(globalData.LogFilterJmp),
 		&cJumps[0], // it is safe because we hold the reference here until we return.
 		&cJumpsV6[0],
@@ -114,6 +114,59 @@ void bpf_tc_remove_qdisc(int ifindex)
         set_errno(bpf_tc_hook_destroy(&hook));
 }
 
+int bpf_tcx_program_attach(struct bpf_object *obj, char *progName, int ifIndex, bool ingress)
+{
+	DECLARE_LIBBPF_OPTS(bpf_link_create_opts, opts);
+	int prog_fd, link_fd;
+
+	prog_fd = bpf_program__fd(bpf_object__find_program_by_name(obj, progName));
+	if (prog_fd < 0) {
+		errno = -prog_fd;
+		return prog_fd;
+	}
+
+	link_fd = bpf_link_create(prog_fd, ifIndex, ingress ? BPF_TCX_INGRESS : BPF_TCX_EGRESS, &opts);
+	if (link_fd < 0) {
+		errno = -link_fd;
+	}
+
+	return link_fd;
+}
+
+int bpf_tcx_link_update(int link_fd, struct bpf_object *obj, char *progName)
+{
+	int prog_fd = bpf_program__fd(bpf_object__find_program_by_name(obj, progName));
+	if (prog_fd < 0) {
+		errno = -prog_fd;
+		return prog_fd;
+	}
+
+	int err = bpf_link_update(link_fd, prog_fd, NULL);
+	set_errno(err);
+	return err;
+}
+
+int bpf_program_id(struct bpf_object *obj, char *progName)
+{
+	struct bpf_prog_info info = {};
+	__u32 info_len = sizeof(info);
+	int prog_fd, err;
+
+	prog_fd = bpf_program__fd(bpf_object__find_program_by_name(obj, progName));
+	if (prog_fd < 0) {
+		errno = -prog_fd;
+		return prog_fd;
+	}
+
+	err = bpf_prog_get_info_by_fd(prog_fd, &info, &info_len);
+	if (err) {
+		set_errno(err);
+		return err;
+	}
+
+	return info.id;
+}
+
 int bpf_update_jump_map(struct bpf_object *obj, char* mapName, char *progName, int progIndex) {
 	struct bpf_program *prog_name = bpf_object__find_program_by_name(obj, progName);
 	if (prog_name == NULL) {
@@ -153,8 +206,11 @@ void bpf_tc_set_globals(struct bpf_map *map,
 			uint flags,
 			ushort wg_port,
 			ushort wg6_port,
+			ushort ct_shards,
 			uint natin,
 			uint natout,
+			char* nat64_prefix,
+			char* nat64_ip,
 			uint log_filter_jmp,
 			uint *jumps,
 			uint *jumps6)
@@ -167,11 +223,15 @@ void bpf_tc_set_globals(struct bpf_map *map,
 		.psnat_len = psnat_len,
 		.flags = flags,
 		.wg_port = wg_port,
+		.ct_shards = ct_shards,
 		.natin_idx = natin,
 		.natout_idx = natout,
 		.log_filter_jmp = log_filter_jmp,
 	};
 
+	memcpy(&v4.nat64_prefix, nat64_prefix, 16);
+	memcpy(&v4.nat64_ip, nat64_ip, 4);
+
 	strncpy(v4.iface_name, iface_name, sizeof(v4.iface_name));
 	v4.iface_name[sizeof(v4.iface_name)-1] = '\0';
 
@@ -27,8 +27,11 @@ type TcGlobalData struct {
 	Flags          uint32
 	WgPort         uint16
 	Wg6Port        uint16
+	CTShards       uint16
 	NatIn          uint32
 	NatOut         uint32
+	NAT64Prefix    [16]byte
+	NAT64IP        [4]byte
 	LogFilterJmp   uint32
 	Jumps          [40]uint32
 
@@ -82,6 +82,18 @@ func (o *Obj) AttachXDP(ifName, progName string, oldFD int, mode uint) (int, err
 	panic("LIBBPF syscall stub")
 }
 
+func (o *Obj) AttachTCX(progName, ifName string, ingress bool) (int, error) {
+	panic("LIBBPF syscall stub")
+}
+
+func (o *Obj) UpdateTCXLink(linkFD int, progName string) error {
+	panic("LIBBPF syscall stub")
+}
+
+func (o *Obj) ProgramID(progName string) (int, error) {
+	panic("LIBBPF syscall stub")
+}
+
 func DetachXDP(ifName string, mode uint) error {
 	panic("LIBBPF syscall stub")
 }
@@ -136,6 +148,9 @@ const (
 	GlobalsRPFOptionStrict  uint32 = 32
 	GlobalsNoDSRCidrs       uint32 = 12345
 	GlobalsLoUDPOnly        uint32 = 12345
+	GlobalsWireguardEnabled uint32 = 12345
+	GlobalsDropFragments    uint32 = 12345
+	GlobalsNAT64Enabled     uint32 = 12345
 )
 
 func TcSetGlobals(_ *Map, globalData *TcGlobalData) error {
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrules
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+const (
+	KeySize   = 8
+	ValueSize = 24
+
+	// DefaultBurst is the burst of rate limited log rules that do not set one, the same as the
+	// iptables limit match.
+	DefaultBurst = 5
+)
+
+// MapParameters describe the log rules, keyed by the match ID of the rule.
+//
+//	struct log_rule {
+//		__u64 interval_ns;
+//		__u64 tolerance_ns;
+//		__u64 tat;
+//	};
+var MapParameters = maps.MapParameters{
+	Type:       "hash",
+	KeySize:    KeySize,
+	ValueSize:  ValueSize,
+	MaxEntries: 10000,
+	Name:       "cali_log_rules",
+}
+
+func Map() maps.Map {
+	return maps.NewPinnedMap(MapParameters)
+}
+
+// EventsMapParameters describe the perf event array through which the TC programs pass the
+// logged packets to Felix.  It has one entry per possible CPU.
+var EventsMapParameters = maps.MapParameters{
+	Type:      "perf_event_array",
+	KeySize:   4,
+	ValueSize: 4,
+	Name:      "cali_log_evts",
+}
+
+func EventsMap() maps.Map {
+	params := EventsMapParameters
+	params.MaxEntries = maps.NumPossibleCPUs()
+	return maps.NewPinnedMap(params)
+}
+
+func Key(matchID uint64) []byte {
+	var k [KeySize]byte
+	binary.LittleEndian.PutUint64(k[:], matchID)
+	return k[:]
+}
+
+// Value returns the value of a log rule that logs a packet every interval after a burst that
+// the tolerance allows.  A zero interval logs every packet.
+func Value(interval, tolerance time.Duration) []byte {
+	var v [ValueSize]byte
+	binary.LittleEndian.PutUint64(v[0:8], uint64(interval))
+	binary.LittleEndian.PutUint64(v[8:16], uint64(tolerance))
+	return v[:]
+}
+
+// RateLimit converts the rate limit of a rule, for example "10/second", and its burst into
+// the interval and tolerance of the log rule.
+func RateLimit(rate string, burst uint32) (interval, tolerance time.Duration, err error) {
+	if rate == "" {
+		return 0, 0, nil
+	}
+	countStr, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("malformed rate limit %q", rate)
+	}
+	count, err := strconv.ParseUint(countStr, 10, 32)
+	if err != nil || count == 0 {
+		return 0, 0, fmt.Errorf("malformed rate limit %q", rate)
+	}
+	var period time.Duration
+	switch unit {
+	case "second":
+		period = time.Second
+	case "minute":
+		period = time.Minute
+	case "hour":
+		period = time.Hour
+	case "day":
+		period = 24 * time.Hour
+	default:
+		return 0, 0, fmt.Errorf("unknown unit in rate limit %q", rate)
+	}
+	if burst == 0 {
+		burst = DefaultBurst
+	}
+	interval = period / time.Duration(count)
+	return interval, interval * time.Duration(burst-1), nil
+}
+
+// EventSize is the size of struct log_event.
+const EventSize = 56
+
+const eventFlagIngress = 1
+
+// Event is a packet logged by a log rule.
+type Event struct {
+	MatchID uint64
+	Src     net.IP
+	Dst     net.IP
+	IfIndex uint32
+	SrcPort uint16
+	DstPort uint16
+	Proto   uint8
+	Ingress bool
+	Length  int
+}
+
+func ParseEvent(b []byte) (Event, error) {
+	var e Event
+	if len(b) < EventSize {
+		return e, fmt.Errorf("short log event (%d bytes)", len(b))
+	}
+	e.MatchID = binary.LittleEndian.Uint64(b[0:8])
+	e.IfIndex = binary.LittleEndian.Uint32(b[40:44])
+	e.SrcPort = binary.LittleEndian.Uint16(b[44:46])
+	e.DstPort = binary.LittleEndian.Uint16(b[46:48])
+	e.Proto = b[48]
+	e.Ingress = b[50]&eventFlagIngress != 0
+	e.Length = int(binary.BigEndian.Uint16(b[52:54]))
+	switch b[49] {
+	case 4:
+		e.Src = net.IP(append([]byte(nil), b[8:12]...))
+		e.Dst = net.IP(append([]byte(nil), b[24:28]...))
+	case 6:
+		e.Src = net.IP(append([]byte(nil), b[8:24]...))
+		e.Dst = net.IP(append([]byte(nil), b[24:40]...))
+		// The IPv6 header carries the payload length.
+		e.Length += 40
+	default:
+		return e, fmt.Errorf("unknown IP version %d in log event", b[49])
+	}
+	return e, nil
+}
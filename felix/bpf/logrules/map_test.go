@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrules
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRateLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	interval, tolerance, err := RateLimit("10/second", 3)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(interval).To(Equal(100 * time.Millisecond))
+	Expect(tolerance).To(Equal(200 * time.Millisecond))
+
+	interval, tolerance, err = RateLimit("2/minute", 0)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(interval).To(Equal(30 * time.Second))
+	Expect(tolerance).To(Equal(time.Duration(DefaultBurst-1) * 30 * time.Second))
+
+	interval, tolerance, err = RateLimit("", 0)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(interval).To(BeZero())
+	Expect(tolerance).To(BeZero())
+
+	for _, bad := range []string{"10", "0/second", "x/second", "10/fortnight"} {
+		_, _, err = RateLimit(bad, 0)
+		Expect(err).To(HaveOccurred(), bad)
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	RegisterTestingT(t)
+
+	b := make([]byte, EventSize)
+	binary.LittleEndian.PutUint64(b[0:8], 0x1234)
+	copy(b[8:], net.IPv4(10, 0, 0, 1).To4())
+	copy(b[24:], net.IPv4(10, 0, 0, 2).To4())
+	binary.LittleEndian.PutUint32(b[40:44], 7)
+	binary.LittleEndian.PutUint16(b[44:46], 34567)
+	binary.LittleEndian.PutUint16(b[46:48], 80)
+	b[48] = 6
+	b[49] = 4
+	b[50] = eventFlagIngress
+	binary.BigEndian.PutUint16(b[52:54], 60)
+
+	e, err := ParseEvent(b)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(e).To(Equal(Event{
+		MatchID: 0x1234,
+		Src:     net.IP{10, 0, 0, 1},
+		Dst:     net.IP{10, 0, 0, 2},
+		IfIndex: 7,
+		SrcPort: 34567,
+		DstPort: 80,
+		Proto:   6,
+		Ingress: true,
+		Length:  60,
+	}))
+
+	_, err = ParseEvent(b[:EventSize-1])
+	Expect(err).To(HaveOccurred())
+}
@@ -32,6 +32,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
+	"github.com/projectcalico/calico/felix/bpf/bpflog"
 	"github.com/projectcalico/calico/felix/bpf/libbpf"
 	"github.com/projectcalico/calico/felix/bpf/utils"
 )
@@ -110,6 +111,36 @@ func repinningIsEnabled() bool {
 	return repinningEnabled
 }
 
+// ShrinkPolicy is what EnsureExists does when the size of a map is reduced below the number of
+// entries that the existing map holds.
+type ShrinkPolicy int
+
+const (
+	// ShrinkRefuse keeps the existing size of the map, with all its entries.
+	ShrinkRefuse ShrinkPolicy = iota
+	// ShrinkTruncate resizes the map and drops the entries that don't fit.
+	ShrinkTruncate
+)
+
+var (
+	shrinkPolicy     ShrinkPolicy
+	shrinkPolicyLock sync.RWMutex
+)
+
+func SetShrinkPolicy(p ShrinkPolicy) {
+	shrinkPolicyLock.Lock()
+	defer shrinkPolicyLock.Unlock()
+
+	shrinkPolicy = p
+}
+
+func getShrinkPolicy() ShrinkPolicy {
+	shrinkPolicyLock.RLock()
+	defer shrinkPolicyLock.RUnlock()
+
+	return shrinkPolicy
+}
+
 type IterCallback func(k, v []byte) IteratorAction
 
 type Map interface {
@@ -170,7 +201,7 @@ func versionedStr(ver int, str string) string {
 }
 
 func (mp *MapParameters) pinDir() string {
-	pindir := bpfdefs.GlobalPinDir
+	pindir := bpfdefs.GlobalPinDir()
 	if mp.PinDir != "" {
 		pindir = mp.PinDir
 	}
@@ -182,6 +213,37 @@ func (mp *MapParameters) VersionedName() string {
 	return versionedStr(mp.Version, mp.Name)
 }
 
+const (
+	// Approximate per-entry overheads of the kernel's hash and LPM trie maps, on top of the
+	// key and value.
+	hashEntryOverhead = 48
+	lpmEntryOverhead  = 40
+)
+
+// EstimatedMemory returns a rough estimate of the kernel memory used by a map with these
+// parameters.  Hash maps preallocate their entries, so the estimate is of a full map.
+func (mp *MapParameters) EstimatedMemory() uint64 {
+	roundUp8 := func(n int) uint64 {
+		return uint64((n + 7) &^ 7)
+	}
+	entries := uint64(mp.MaxEntries)
+	key := roundUp8(mp.KeySize)
+	value := roundUp8(mp.ValueSize)
+	if strings.Contains(mp.Type, "percpu") {
+		value *= uint64(NumPossibleCPUs())
+	}
+
+	switch {
+	case strings.Contains(mp.Type, "array"):
+		// Arrays, per-CPU arrays, program arrays and perf event arrays only store values.
+		return entries * value
+	case mp.Type == "lpm_trie":
+		return entries * (lpmEntryOverhead + key + value)
+	default:
+		return entries * (hashEntryOverhead + key + value)
+	}
+}
+
 func (mp *MapParameters) VersionedFilename() string {
 	return path.Join(mp.pinDir(), mp.VersionedName())
 }
@@ -222,7 +284,7 @@ func ResetSizes() {
 
 func NewPinnedMap(params MapParameters) *PinnedMap {
 	if len(params.VersionedName()) >= unix.BPF_OBJ_NAME_LEN {
-		log.WithField("name", params.Name).Panicf("Bug: BPF map name too long (%d)", len(params.VersionedName()))
+		bpflog.WithMap(params.Name).Panicf("Bug: BPF map name too long (%d)", len(params.VersionedName()))
 	}
 	if val := Size(params.VersionedName()); val != 0 {
 		params.MaxEntries = val
@@ -431,13 +493,13 @@ func (b *PinnedMap) DeleteIfExists(k []byte) error {
 }
 
 func (b *PinnedMap) updateDeltaEntries() error {
-	log.WithField("name", b.Name).Debug("updateDeltaEntries")
+	bpflog.WithMap(b.Name).Debug("updateDeltaEntries")
 
 	if b.oldfd == b.fd {
 		return fmt.Errorf("old and new maps are the same")
 	}
 
-	log.WithField("name", b.Name).Debugf("updateDeltaEntries from fd %d -> %d", b.oldfd, b.fd)
+	bpflog.WithMap(b.Name).Debugf("updateDeltaEntries from fd %d -> %d", b.oldfd, b.fd)
 
 	numEntriesCopied := 0
 	mapMem := make(map[string]struct{})
@@ -445,7 +507,7 @@ func (b *PinnedMap) updateDeltaEntries() error {
 	if err != nil {
 		return fmt.Errorf("failed to create BPF map iterator: %w", err)
 	}
-	log.WithField("name", b.Name).Debugf("updateDeltaEntries iterator over fd %d", b.oldfd)
+	bpflog.WithMap(b.Name).Debugf("updateDeltaEntries iterator over fd %d", b.oldfd)
 	defer func() {
 		err := it.Close()
 		if err != nil {
@@ -462,6 +524,10 @@ func (b *PinnedMap) updateDeltaEntries() error {
 			return errors.Errorf("iterating the old map failed: %s", err)
 		}
 		if numEntriesCopied == b.MaxEntries {
+			if getShrinkPolicy() == ShrinkTruncate {
+				bpflog.WithMap(b.Name).Warn("New map is full, dropping the remaining entries of the old map")
+				break
+			}
 			return fmt.Errorf("new map cannot hold all the data from the old map %s.", b.GetName())
 		}
 
@@ -482,7 +548,7 @@ func (b *PinnedMap) updateDeltaEntries() error {
 		numEntriesCopied++
 	}
 
-	log.WithField("name", b.Name).Debugf("updateDeltaEntries copied %d", numEntriesCopied)
+	bpflog.WithMap(b.Name).Debugf("updateDeltaEntries copied %d", numEntriesCopied)
 
 	return nil
 }
@@ -512,6 +578,10 @@ func (b *PinnedMap) copyFromOldMap() error {
 		}
 
 		if numEntriesCopied == b.MaxEntries {
+			if getShrinkPolicy() == ShrinkTruncate {
+				bpflog.WithMap(b.Name).Warn("New map is full, dropping the remaining entries of the old map")
+				return nil
+			}
 			return fmt.Errorf("new map cannot hold all the data from the old map %s", b.GetName())
 		}
 		if _, ok := mapMem[string(k)]; ok {
@@ -522,15 +592,55 @@ func (b *PinnedMap) copyFromOldMap() error {
 		if err != nil {
 			return fmt.Errorf("error copying data from the old map")
 		}
-		log.WithField("name", b.Name).Debugf("copied data from old map to new map key=%v, value=%v", k, v)
+		bpflog.WithMap(b.Name).Debugf("copied data from old map to new map key=%v, value=%v", k, v)
 		mapMem[string(k)] = struct{}{}
 		numEntriesCopied++
 	}
 }
 
+// keepSizeIfOccupied checks whether the entries of the existing map fit in its new, smaller size.
+// If they don't, it keeps the existing size, for the map and for the BPF programs that use it, and
+// returns true.
+func (b *PinnedMap) keepSizeIfOccupied(size int) (bool, error) {
+	numEntries := 0
+	it, err := NewIterator(b.fd, b.KeySize, b.ValueSize, size)
+	if err != nil {
+		return false, fmt.Errorf("failed to create BPF map iterator: %w", err)
+	}
+	defer func() {
+		err := it.Close()
+		if err != nil {
+			log.WithError(err).Panic("Unexpected error from map iterator Close().")
+		}
+	}()
+	for {
+		_, _, err := it.Next()
+		if err != nil {
+			if err == ErrIterationFinished {
+				break
+			}
+			return false, fmt.Errorf("iterating the map failed: %w", err)
+		}
+		numEntries++
+	}
+
+	if numEntries <= b.MaxEntries {
+		return false, nil
+	}
+	log.WithFields(log.Fields{
+		bpflog.FieldMap: b.Name,
+		"size":          size,
+		"newSize":       b.MaxEntries,
+		"numEntries":    numEntries,
+	}).Warn("Map holds more entries than its new size, keeping the current size")
+	b.MaxEntries = size
+	SetSize(b.VersionedName(), size)
+	return true, nil
+}
+
 func (b *PinnedMap) Open() error {
 	if b.fdLoaded {
-		log.WithField("name", b.Name).Debug("Open - fd loaded")
+		bpflog.WithMap(b.Name).Debug("Open - fd loaded")
 		return nil
 	}
 
@@ -551,9 +661,9 @@ func (b *PinnedMap) Open() error {
 		if !os.IsNotExist(err) {
 			return err
 		}
-		log.WithField("name", b.Name).Debug("Map file didn't exist")
+		bpflog.WithMap(b.Name).Debug("Map file didn't exist")
 		if repinningIsEnabled() {
-			log.WithField("name", b.Name).Info("Looking for map by name (to repin it)")
+			bpflog.WithMap(b.Name).Info("Looking for map by name (to repin it)")
 			err = RepinMap(b.VersionedName(), b.VersionedFilename())
 			if err != nil && !os.IsNotExist(err) {
 				return err
@@ -562,11 +672,11 @@ func (b *PinnedMap) Open() error {
 	}
 
 	if err == nil {
-		log.WithField("name", b.Name).Debug("Map file already exists, trying to open it")
+		bpflog.WithMap(b.Name).Debug("Map file already exists, trying to open it")
 		b.fd, err = GetMapFDByPin(b.VersionedFilename())
 		if err == nil {
 			b.fdLoaded = true
-			log.WithField("fd", b.fd).WithField("name", b.Name).Info("Loaded map file descriptor.")
+			bpflog.WithMap(b.Name).WithField("fd", b.fd).Info("Loaded map file descriptor.")
 			return nil
 		}
 		return err
@@ -607,7 +717,7 @@ func (b *PinnedMap) EnsureExists() error {
 	// In case felix restarts in the middle of migration, we might end up with
 	// old map. Repin the old map and let the map creation continue.
 	if b.oldMapExists() {
-		log.WithField("name", b.Name).Debug("Old map exists")
+		bpflog.WithMap(b.Name).Debug("Old map exists")
 		if _, err := os.Stat(b.Path()); err == nil {
 			os.Remove(b.Path())
 		}
@@ -633,7 +743,7 @@ func (b *PinnedMap) EnsureExists() error {
 			b.MapFD().Close()
 			os.Remove(b.Path())
 			log.WithFields(log.Fields{
-				"name":          b.Name,
+				bpflog.FieldMap: b.Name,
 				"Old KeySize":   mapInfo.KeySize,
 				"New KeySize":   b.KeySize,
 				"Old ValueSize": mapInfo.ValueSize,
@@ -643,7 +753,16 @@ func (b *PinnedMap) EnsureExists() error {
 			if b.MaxEntries == mapInfo.MaxEntries {
 				return nil
 			}
-			log.WithField("name", b.Name).Debugf("Size changed %d -> %d", mapInfo.MaxEntries, b.MaxEntries)
+			if b.MaxEntries < mapInfo.MaxEntries && getShrinkPolicy() == ShrinkRefuse {
+				keep, err := b.keepSizeIfOccupied(mapInfo.MaxEntries)
+				if err != nil {
+					return err
+				}
+				if keep {
+					return nil
+				}
+			}
+			bpflog.WithMap(b.Name).Debugf("Size changed %d -> %d", mapInfo.MaxEntries, b.MaxEntries)
 
 			// store the old fd
 			b.oldfd = b.MapFD()
@@ -665,9 +784,9 @@ func (b *PinnedMap) EnsureExists() error {
 	}
 
 	log.WithFields(log.Fields{
-		"name":      b.Name,
-		"keySize":   b.KeySize,
-		"valuesize": b.ValueSize,
+		bpflog.FieldMap: b.Name,
+		"keySize":       b.KeySize,
+		"valuesize":     b.ValueSize,
 	}).Debug("Map didn't exist, creating it")
 	cmd := exec.Command("bpftool", "map", "create", b.VersionedFilename(),
 		"type", b.Type,
@@ -770,14 +889,14 @@ func (b *PinnedMap) CopyDeltaFromOldMap() error {
 	// If so upgrade delta entries from the old map
 	// to the new map.
 
-	log.WithField("name", b.Name).Debug("CopyDeltaFromOldMap")
+	bpflog.WithMap(b.Name).Debug("CopyDeltaFromOldMap")
 
 	err := b.upgrade()
 	if err != nil {
 		return fmt.Errorf("error upgrading data from old map %s, err=%w", b.GetName(), err)
 	}
 	if b.oldfd == 0 {
-		log.WithField("name", b.Name).Debug("CopyDeltaFromOldMap - no old map, done.")
+		bpflog.WithMap(b.Name).Debug("CopyDeltaFromOldMap - no old map, done.")
 		return nil
 	}
 
@@ -831,7 +950,7 @@ func (b *PinnedMap) getOldMapVersion() (int, error) {
 // If there is a resized version of v2, which is v2_old, data is upgraded from
 // v2_old as well to v3.
 func (b *PinnedMap) upgrade() error {
-	log.WithField("name", b.Name).Debug("upgrade")
+	bpflog.WithMap(b.Name).Debug("upgrade")
 	if b.UpgradeFn == nil {
 		return nil
 	}
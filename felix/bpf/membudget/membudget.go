@@ -0,0 +1,189 @@
+//go:build !windows
+
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package membudget keeps the kernel memory of the BPF maps plus the Felix heap within a
+// configured budget, so that map size settings can't make the node run out of memory.
+package membudget
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+)
+
+// MinConntrackMapSize is the smallest size that the conntrack table is shrunk to in order
+// to fit the budget.
+const MinConntrackMapSize = 16384
+
+var (
+	gaugeBudget = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "felix_memory_budget_bytes",
+		Help: "Memory budget for the BPF maps and the Felix heap.",
+	})
+	gaugeEstimated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "felix_memory_budget_estimated_bytes",
+		Help: "Estimated memory counted against the memory budget, by component.",
+	}, []string{"component"})
+	gaugeDegraded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "felix_memory_budget_degraded",
+		Help: "1 if the conntrack table was shrunk to fit the memory budget, 0 otherwise.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(gaugeBudget, gaugeEstimated, gaugeDegraded)
+}
+
+// heapMemory returns the memory of the Felix heap.
+var heapMemory = func() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapSys
+}
+
+// mapsMemory returns the estimated kernel memory of each of the BPF maps.
+var mapsMemory = bpfmap.EstimatedMemory
+
+// Status is the memory use counted against the budget.
+type Status struct {
+	Budget     uint64
+	MapsMemory uint64
+	HeapMemory uint64
+	// ConntrackMapSize is the size of the conntrack table, after shrinking it to fit the
+	// budget, if needed.
+	ConntrackMapSize int
+	// Degraded is true if the conntrack table was shrunk to fit the budget.
+	Degraded bool
+
+	overBudget bool
+}
+
+// Fit checks that the BPF maps, with their configured sizes, plus the Felix heap fit in the
+// budget.  If they don't, it halves the size of the conntrack table, which is by far the
+// largest map, until they do, down to MinConntrackMapSize.  It returns an error if they
+// still don't fit; that configuration is refused.  Fit must be called before the maps are
+// created.
+func Fit(budget uint64, ipV6Enabled bool) (*Status, error) {
+	s := &Status{
+		Budget:           budget,
+		ConntrackMapSize: conntrack.MapSize(),
+		HeapMemory:       heapMemory(),
+	}
+	gaugeBudget.Set(float64(budget))
+
+	var perMap map[string]uint64
+	for {
+		perMap = mapsMemory(ipV6Enabled)
+		s.MapsMemory = 0
+		for _, mem := range perMap {
+			s.MapsMemory += mem
+		}
+		if s.MapsMemory+s.HeapMemory <= budget || s.ConntrackMapSize <= MinConntrackMapSize {
+			break
+		}
+		s.ConntrackMapSize = max(s.ConntrackMapSize/2, MinConntrackMapSize)
+		s.Degraded = true
+		conntrack.SetMapSize(s.ConntrackMapSize)
+	}
+	s.updateMetrics()
+
+	if s.MapsMemory+s.HeapMemory > budget {
+		return s, fmt.Errorf("estimated memory of the BPF maps (%d MiB) plus the Felix heap (%d MiB) "+
+			"exceeds the memory budget of %d MiB; largest maps: %s",
+			s.MapsMemory>>20, s.HeapMemory>>20, budget>>20, largestMaps(perMap, 3))
+	}
+	if s.Degraded {
+		log.WithFields(log.Fields{
+			"budgetMiB":        budget >> 20,
+			"mapsMiB":          s.MapsMemory >> 20,
+			"heapMiB":          s.HeapMemory >> 20,
+			"conntrackMapSize": s.ConntrackMapSize,
+		}).Warn("BPF maps don't fit in the memory budget with the configured sizes, " +
+			"the conntrack table was shrunk.")
+	} else {
+		log.WithFields(log.Fields{
+			"budgetMiB": budget >> 20,
+			"mapsMiB":   s.MapsMemory >> 20,
+			"heapMiB":   s.HeapMemory >> 20,
+		}).Info("BPF maps fit in the memory budget.")
+	}
+	return s, nil
+}
+
+// Monitor periodically re-checks the Felix heap, which grows with the size of the cluster,
+// and warns when the memory use exceeds the budget.  It never returns.
+func (s *Status) Monitor(interval time.Duration) {
+	for range time.Tick(interval) {
+		s.check()
+	}
+}
+
+func (s *Status) check() {
+	s.HeapMemory = heapMemory()
+	s.updateMetrics()
+
+	over := s.MapsMemory+s.HeapMemory > s.Budget
+	if over && !s.overBudget {
+		log.WithFields(log.Fields{
+			"budgetMiB": s.Budget >> 20,
+			"mapsMiB":   s.MapsMemory >> 20,
+			"heapMiB":   s.HeapMemory >> 20,
+		}).Warn("Memory use exceeds the memory budget.")
+	} else if !over && s.overBudget {
+		log.Info("Memory use is back within the memory budget.")
+	}
+	s.overBudget = over
+}
+
+func (s *Status) updateMetrics() {
+	gaugeEstimated.WithLabelValues("bpf_maps").Set(float64(s.MapsMemory))
+	gaugeEstimated.WithLabelValues("heap").Set(float64(s.HeapMemory))
+	if s.Degraded {
+		gaugeDegraded.Set(1)
+	} else {
+		gaugeDegraded.Set(0)
+	}
+}
+
+// largestMaps returns the names and estimated memory of the n largest maps.
+func largestMaps(perMap map[string]uint64, n int) string {
+	names := make([]string, 0, len(perMap))
+	for name := range perMap {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return perMap[names[i]] > perMap[names[j]]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+
+	var str string
+	for i, name := range names {
+		if i > 0 {
+			str += ", "
+		}
+		str += fmt.Sprintf("%s (%d MiB)", name, perMap[name]>>20)
+	}
+	return str
+}
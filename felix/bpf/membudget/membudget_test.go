@@ -0,0 +1,93 @@
+//go:build !windows
+
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package membudget
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+)
+
+const mib = 1 << 20
+
+func setUp(t *testing.T, heap uint64) {
+	RegisterTestingT(t)
+	conntrack.SetMapSize(512000)
+	heapMemory = func() uint64 { return heap }
+	t.Cleanup(func() {
+		conntrack.SetMapSize(512000)
+	})
+}
+
+func TestFitWithinBudget(t *testing.T) {
+	setUp(t, 100*mib)
+
+	s, err := Fit(8192*mib, false)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(s.Degraded).To(BeFalse())
+	Expect(s.ConntrackMapSize).To(Equal(512000))
+	Expect(s.HeapMemory).To(BeNumerically("==", 100*mib))
+	Expect(s.MapsMemory).To(BeNumerically(">", 0))
+	Expect(conntrack.MapSize()).To(Equal(512000))
+}
+
+func TestFitShrinksConntrack(t *testing.T) {
+	setUp(t, 100*mib)
+
+	full := total(bpfmap.EstimatedMemory(true))
+	s, err := Fit(full+100*mib-1, true)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(s.Degraded).To(BeTrue())
+	Expect(s.ConntrackMapSize).To(Equal(256000))
+	Expect(conntrack.MapSize()).To(Equal(256000))
+	Expect(s.MapsMemory).To(BeNumerically("<", full))
+}
+
+func TestFitRefusesOverBudget(t *testing.T) {
+	setUp(t, 100*mib)
+
+	_, err := Fit(50*mib, false)
+	Expect(err).To(MatchError(ContainSubstring("exceeds the memory budget of 50 MiB")))
+	Expect(conntrack.MapSize()).To(Equal(MinConntrackMapSize))
+}
+
+func TestMonitorTracksHeap(t *testing.T) {
+	setUp(t, 100*mib)
+
+	s, err := Fit(8192*mib, false)
+	Expect(err).NotTo(HaveOccurred())
+
+	heapMemory = func() uint64 { return 8192 * mib }
+	s.check()
+	Expect(s.HeapMemory).To(BeNumerically("==", 8192*mib))
+	Expect(s.overBudget).To(BeTrue())
+
+	heapMemory = func() uint64 { return 100 * mib }
+	s.check()
+	Expect(s.overBudget).To(BeFalse())
+}
+
+func total(perMap map[string]uint64) uint64 {
+	var t uint64
+	for _, mem := range perMap {
+		t += mem
+	}
+	return t
+}
@@ -110,7 +110,7 @@ func RemoveConnectTimeLoadBalancer(cgroupv2 string) error {
 		}
 	}
 
-	bpf.CleanUpCalicoPins("/sys/fs/bpf/calico_connect4")
+	bpf.CleanUpCalicoPins(path.Join(bpfdefs.PinRoot(), "calico_connect4"))
 	ctlbProgsMap := newProgramsMap()
 	os.Remove(ctlbProgsMap.Path())
 
@@ -147,7 +147,7 @@ func loadProgram(logLevel, ipver string, udpNotSeen time.Duration, excludeUDP bo
 				return nil, fmt.Errorf("error set map size %s: %w", m.Name(), err)
 			}
 		}
-		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir, mapName)); err != nil {
+		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir(), mapName)); err != nil {
 			return nil, fmt.Errorf("error pinning map %s: %w", mapName, err)
 		}
 		log.WithFields(log.Fields{"obj": filename, "map": mapName}).Debug("Pinned map")
@@ -17,6 +17,7 @@ package nat
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 	"net"
 	"time"
 
@@ -94,6 +95,31 @@ const backendValueSize = 8
 
 const BlackHoleCount uint32 = 0xffffffff
 
+// MaxBackendsPerService is the hard limit on the number of backends of a
+// single service. The count of backends in a frontend value is a uint32 but
+// BlackHoleCount marks a frontend that drops traffic, so a real count must
+// stay below it. The backends of all services also share the backend map,
+// BackendsLimit takes its size into account.
+const MaxBackendsPerService = BlackHoleCount - 1
+
+// BackendsLimit returns the most backends that can be programmed for a single
+// service of the given IP family: the size of the backend map, but never more
+// than MaxBackendsPerService.
+func BackendsLimit(ipFamily int) int {
+	name := BackendMapParameters.VersionedName()
+	if ipFamily == 6 {
+		name = BackendMapV6Parameters.VersionedName()
+	}
+	limit := uint64(MaxBackendsPerService)
+	if size := maps.Size(name); size > 0 && uint64(size) < limit {
+		limit = uint64(size)
+	}
+	if limit > math.MaxInt {
+		limit = math.MaxInt
+	}
+	return int(limit)
+}
+
 // (sizeof(addr) + sizeof(port) + sizeof(proto)) in bits
 const ZeroCIDRPrefixLen = 56
 
@@ -203,12 +229,14 @@ const (
 	NATFlgExternalLocal = 0x1
 	NATFlgInternalLocal = 0x2
 	NATFlgExclude       = 0x4
+	NATFlgProxyProtocol = 0x8
 )
 
 var flgTostr = map[int]string{
 	NATFlgExternalLocal: "external-local",
 	NATFlgInternalLocal: "internal-local",
 	NATFlgExclude:       "nat-exclude",
+	NATFlgProxyProtocol: "proxy-protocol",
 }
 
 type FrontendValue [frontendValueSize]byte
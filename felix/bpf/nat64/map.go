@@ -0,0 +1,242 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+func init() {
+	maps.SetSize(FwdMapParameters.VersionedName(), FwdMapParameters.MaxEntries)
+	maps.SetSize(RevMapParameters.VersionedName(), RevMapParameters.MaxEntries)
+}
+
+//	struct nat64_fwd_key {
+//		ipv6_addr_t src;
+//		__be32 dst;
+//		__u16 sport;
+//		__u16 dport;
+//		__u8 proto;
+//		__u8 __pad[3];
+//	};
+const FwdKeySize = 28
+
+//	struct nat64_fwd_value {
+//		__u16 nat_port;
+//		__u16 __pad;
+//	};
+const FwdValueSize = 4
+
+//	struct nat64_rev_key {
+//		__be32 addr;
+//		__u16 port;
+//		__u16 nat_port;
+//		__u8 proto;
+//		__u8 __pad[3];
+//	};
+const RevKeySize = 12
+
+//	struct nat64_rev_value {
+//		ipv6_addr_t addr;
+//		__u16 port;
+//		__u16 __pad;
+//	};
+const RevValueSize = 20
+
+// FwdMapParameters describe the map in which the IPv6 programs remember the
+// IPv4 source port that a workload flow to the NAT64 prefix is translated to.
+var FwdMapParameters = maps.MapParameters{
+	Type:       "lru_hash",
+	KeySize:    FwdKeySize,
+	ValueSize:  FwdValueSize,
+	MaxEntries: 65536,
+	Name:       "cali_nat64_fwd",
+}
+
+// RevMapParameters describe the map in which the IPv4 programs look up the
+// workload that a reply to a translated flow belongs to.
+var RevMapParameters = maps.MapParameters{
+	Type:       "lru_hash",
+	KeySize:    RevKeySize,
+	ValueSize:  RevValueSize,
+	MaxEntries: 65536,
+	Name:       "cali_nat64_rev",
+}
+
+func FwdMap() maps.Map {
+	return maps.NewPinnedMap(FwdMapParameters)
+}
+
+func RevMap() maps.Map {
+	return maps.NewPinnedMap(RevMapParameters)
+}
+
+type FwdKey [FwdKeySize]byte
+
+func NewFwdKey(src net.IP, sport uint16, dst net.IP, dport uint16, proto uint8) FwdKey {
+	var k FwdKey
+
+	copy(k[0:16], src.To16())
+	copy(k[16:20], dst.To4())
+	binary.LittleEndian.PutUint16(k[20:22], sport)
+	binary.LittleEndian.PutUint16(k[22:24], dport)
+	k[24] = proto
+
+	return k
+}
+
+func FwdKeyFromBytes(b []byte) FwdKey {
+	var k FwdKey
+	copy(k[:], b)
+	return k
+}
+
+func (k FwdKey) Src() net.IP {
+	return net.IP(k[0:16])
+}
+
+func (k FwdKey) Dst() net.IP {
+	return net.IP(k[16:20])
+}
+
+func (k FwdKey) SrcPort() uint16 {
+	return binary.LittleEndian.Uint16(k[20:22])
+}
+
+func (k FwdKey) DstPort() uint16 {
+	return binary.LittleEndian.Uint16(k[22:24])
+}
+
+func (k FwdKey) Proto() uint8 {
+	return k[24]
+}
+
+func (k FwdKey) AsBytes() []byte {
+	return k[:]
+}
+
+func (k FwdKey) String() string {
+	return fmt.Sprintf("proto %d [%s]:%d -> %s:%d", k.Proto(), k.Src(), k.SrcPort(), k.Dst(), k.DstPort())
+}
+
+type FwdValue [FwdValueSize]byte
+
+func NewFwdValue(natPort uint16) FwdValue {
+	var v FwdValue
+	binary.LittleEndian.PutUint16(v[0:2], natPort)
+	return v
+}
+
+func FwdValueFromBytes(b []byte) FwdValue {
+	var v FwdValue
+	copy(v[:], b)
+	return v
+}
+
+func (v FwdValue) NATPort() uint16 {
+	return binary.LittleEndian.Uint16(v[0:2])
+}
+
+func (v FwdValue) AsBytes() []byte {
+	return v[:]
+}
+
+func (v FwdValue) String() string {
+	return fmt.Sprintf("nat port %d", v.NATPort())
+}
+
+type RevKey [RevKeySize]byte
+
+func NewRevKey(addr net.IP, port, natPort uint16, proto uint8) RevKey {
+	var k RevKey
+
+	copy(k[0:4], addr.To4())
+	binary.LittleEndian.PutUint16(k[4:6], port)
+	binary.LittleEndian.PutUint16(k[6:8], natPort)
+	k[8] = proto
+
+	return k
+}
+
+func RevKeyFromBytes(b []byte) RevKey {
+	var k RevKey
+	copy(k[:], b)
+	return k
+}
+
+func (k RevKey) Addr() net.IP {
+	return net.IP(k[0:4])
+}
+
+func (k RevKey) Port() uint16 {
+	return binary.LittleEndian.Uint16(k[4:6])
+}
+
+func (k RevKey) NATPort() uint16 {
+	return binary.LittleEndian.Uint16(k[6:8])
+}
+
+func (k RevKey) Proto() uint8 {
+	return k[8]
+}
+
+func (k RevKey) AsBytes() []byte {
+	return k[:]
+}
+
+func (k RevKey) String() string {
+	return fmt.Sprintf("proto %d %s:%d nat port %d", k.Proto(), k.Addr(), k.Port(), k.NATPort())
+}
+
+type RevValue [RevValueSize]byte
+
+func NewRevValue(addr net.IP, port uint16) RevValue {
+	var v RevValue
+	copy(v[0:16], addr.To16())
+	binary.LittleEndian.PutUint16(v[16:18], port)
+	return v
+}
+
+func RevValueFromBytes(b []byte) RevValue {
+	var v RevValue
+	copy(v[:], b)
+	return v
+}
+
+func (v RevValue) Addr() net.IP {
+	return net.IP(v[0:16])
+}
+
+func (v RevValue) Port() uint16 {
+	return binary.LittleEndian.Uint16(v[16:18])
+}
+
+func (v RevValue) AsBytes() []byte {
+	return v[:]
+}
+
+func (v RevValue) String() string {
+	return fmt.Sprintf("[%s]:%d", v.Addr(), v.Port())
+}
+
+// RevKeyOf returns the key of the reverse entry that belongs to the forward
+// entry.
+func RevKeyOf(k FwdKey, v FwdValue) RevKey {
+	return NewRevKey(k.Dst(), k.DstPort(), v.NATPort(), k.Proto())
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat64
+
+import (
+	"bytes"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+const (
+	protoICMP   = 1
+	protoICMPv6 = 58
+)
+
+// flow identifies a translated flow the same way in the IPv6 conntrack table
+// and in the forward map. The ICMP echo identifier is not part of it as the
+// conntrack table does not track it.
+type flow struct {
+	src   [16]byte
+	dst   [4]byte
+	sport uint16
+	dport uint16
+	proto uint8
+}
+
+func newFlow(src net.IP, sport uint16, dst net.IP, dport uint16, proto uint8) flow {
+	f := flow{
+		sport: sport,
+		dport: dport,
+		proto: proto,
+	}
+	if proto == protoICMPv6 {
+		f.proto = protoICMP
+	}
+	if f.proto == protoICMP {
+		f.sport, f.dport = 0, 0
+	}
+	copy(f.src[:], src.To16())
+	copy(f.dst[:], dst.To16()[12:16])
+	return f
+}
+
+// Scanner removes the NAT64 state of flows that no longer have an IPv6
+// conntrack entry. It must be added to the IPv6 conntrack scanner after the
+// scanners that expire the conntrack entries.
+//
+// Entries are only removed when their flow is missing from two consecutive
+// iterations as the BPF programs may create the NAT64 state of a new flow
+// after its conntrack entry was already scanned.
+type Scanner struct {
+	fwdMap maps.Map
+	revMap maps.Map
+	prefix []byte
+
+	live      map[flow]struct{}
+	staleFwd  map[FwdKey]struct{}
+	staleRevs map[RevKey]struct{}
+}
+
+// NewScanner returns a Scanner for the flows to the given /96 NAT64 prefix.
+func NewScanner(fwdMap, revMap maps.Map, prefix net.IP) *Scanner {
+	return &Scanner{
+		fwdMap:    fwdMap,
+		revMap:    revMap,
+		prefix:    prefix.To16()[:12],
+		live:      make(map[flow]struct{}),
+		staleFwd:  make(map[FwdKey]struct{}),
+		staleRevs: make(map[RevKey]struct{}),
+	}
+}
+
+func (s *Scanner) inPrefix(ip net.IP) bool {
+	ip = ip.To16()
+	return ip != nil && bytes.Equal(ip[:12], s.prefix)
+}
+
+// Check records the conntrack entries of the translated flows, it never
+// deletes any conntrack entry.
+func (s *Scanner) Check(k conntrack.KeyInterface, _ conntrack.ValueInterface, _ conntrack.EntryGet) conntrack.ScanVerdict {
+	switch {
+	case s.inPrefix(k.AddrA()):
+		s.live[newFlow(k.AddrB(), k.PortB(), k.AddrA(), k.PortA(), k.Proto())] = struct{}{}
+	case s.inPrefix(k.AddrB()):
+		s.live[newFlow(k.AddrA(), k.PortA(), k.AddrB(), k.PortB(), k.Proto())] = struct{}{}
+	}
+
+	return conntrack.ScanVerdictOK
+}
+
+// IterationStart satisfies EntryScannerSynced
+func (s *Scanner) IterationStart() {
+	s.live = make(map[flow]struct{})
+}
+
+// IterationEnd satisfies EntryScannerSynced
+func (s *Scanner) IterationEnd() {
+	staleFwd := make(map[FwdKey]struct{})
+	revs := make(map[RevKey]struct{})
+	deleted := 0
+
+	err := s.fwdMap.Iter(func(kb, vb []byte) maps.IteratorAction {
+		k := FwdKeyFromBytes(kb)
+		v := FwdValueFromBytes(vb)
+		rk := RevKeyOf(k, v)
+
+		if _, ok := s.live[newFlow(k.Src(), k.SrcPort(), k.Dst(), k.DstPort(), k.Proto())]; !ok {
+			if _, ok := s.staleFwd[k]; ok {
+				if err := s.revMap.Delete(rk.AsBytes()); err != nil && !maps.IsNotExists(err) {
+					log.WithError(err).WithField("key", rk).Warn("Failed to delete NAT64 reverse entry.")
+				}
+				deleted++
+				return maps.IterDelete
+			}
+			staleFwd[k] = struct{}{}
+		}
+
+		revs[rk] = struct{}{}
+		return maps.IterNone
+	})
+	if err != nil {
+		log.WithError(err).Warn("Failed to iterate over the NAT64 forward map.")
+		return
+	}
+
+	// Reverse entries without a forward entry are left behind when the
+	// forward map is full.
+	staleRevs := make(map[RevKey]struct{})
+	err = s.revMap.Iter(func(kb, _ []byte) maps.IteratorAction {
+		k := RevKeyFromBytes(kb)
+		if _, ok := revs[k]; ok {
+			return maps.IterNone
+		}
+		if _, ok := s.staleRevs[k]; ok {
+			deleted++
+			return maps.IterDelete
+		}
+		staleRevs[k] = struct{}{}
+		return maps.IterNone
+	})
+	if err != nil {
+		log.WithError(err).Warn("Failed to iterate over the NAT64 reverse map.")
+		return
+	}
+
+	s.staleFwd = staleFwd
+	s.staleRevs = staleRevs
+
+	if deleted > 0 {
+		log.WithField("deleted", deleted).Debug("Removed NAT64 state of finished flows.")
+	}
+}
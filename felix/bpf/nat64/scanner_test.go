@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat64
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+)
+
+var (
+	podIP   = net.ParseIP("fd00::1")
+	podIP2  = net.ParseIP("fd00::2")
+	prefix  = net.ParseIP("64:ff9b::")
+	dstIP   = net.ParseIP("10.0.0.1")
+	dstIPv6 = net.ParseIP("64:ff9b::a00:1")
+)
+
+type nat64Flow struct {
+	src     net.IP
+	sport   uint16
+	dport   uint16
+	proto   uint8
+	natPort uint16
+}
+
+func addFlow(fwd, rev *mock.Map, f nat64Flow) {
+	k := NewFwdKey(f.src, f.sport, dstIP, f.dport, f.proto)
+	v := NewFwdValue(f.natPort)
+	fwd.Contents[string(k.AsBytes())] = string(v.AsBytes())
+	rk := RevKeyOf(k, v)
+	rev.Contents[string(rk.AsBytes())] = string(NewRevValue(f.src, f.sport).AsBytes())
+}
+
+func scan(s *Scanner, keys ...conntrack.KeyInterface) {
+	s.IterationStart()
+	for _, k := range keys {
+		Expect(s.Check(k, nil, nil)).To(Equal(conntrack.ScanVerdictOK))
+	}
+	s.IterationEnd()
+}
+
+func TestScannerRemovesFinishedFlows(t *testing.T) {
+	RegisterTestingT(t)
+
+	fwd := mock.NewMockMap(FwdMapParameters)
+	rev := mock.NewMockMap(RevMapParameters)
+
+	tcp := nat64Flow{src: podIP, sport: 30000, dport: 80, proto: 6, natPort: 20000}
+	icmp := nat64Flow{src: podIP, sport: 1234, proto: protoICMP, natPort: 20001}
+	done := nat64Flow{src: podIP2, sport: 30001, dport: 53, proto: 17, natPort: 20002}
+	addFlow(fwd, rev, tcp)
+	addFlow(fwd, rev, icmp)
+	addFlow(fwd, rev, done)
+
+	// Reverse entry without its forward entry.
+	orphan := NewRevKey(dstIP, 443, 20003, 6)
+	rev.Contents[string(orphan.AsBytes())] = string(NewRevValue(podIP, 30002).AsBytes())
+
+	s := NewScanner(fwd, rev, prefix)
+
+	live := []conntrack.KeyInterface{
+		conntrack.NewKeyV6(6, podIP, 30000, dstIPv6, 80),
+		// Conntrack keys are ordered, the pod may be either side.
+		conntrack.NewKeyV6(protoICMPv6, dstIPv6, 0, podIP, 0),
+		// Not a NAT64 flow.
+		conntrack.NewKeyV6(17, podIP2, 30001, net.ParseIP("fd00::53"), 53),
+	}
+
+	scan(s, live...)
+	Expect(fwd.Contents).To(HaveLen(3), "stale entries must survive the first iteration")
+	Expect(rev.Contents).To(HaveLen(4))
+
+	scan(s, live...)
+	Expect(fwd.Contents).To(HaveLen(2))
+	Expect(fwd.Contents).NotTo(HaveKey(string(NewFwdKey(podIP2, 30001, dstIP, 53, 17).AsBytes())))
+	Expect(rev.Contents).To(HaveLen(2))
+	Expect(rev.Contents).NotTo(HaveKey(string(orphan.AsBytes())))
+
+	// All flows are finished.
+	scan(s)
+	Expect(fwd.Contents).To(HaveLen(2))
+	scan(s)
+	Expect(fwd.Contents).To(BeEmpty())
+	Expect(rev.Contents).To(BeEmpty())
+}
+
+func TestScannerKeepsFlowsThatComeBack(t *testing.T) {
+	RegisterTestingT(t)
+
+	fwd := mock.NewMockMap(FwdMapParameters)
+	rev := mock.NewMockMap(RevMapParameters)
+
+	addFlow(fwd, rev, nat64Flow{src: podIP, sport: 30000, dport: 80, proto: 6, natPort: 20000})
+
+	s := NewScanner(fwd, rev, prefix)
+	ct := conntrack.NewKeyV6(6, podIP, 30000, dstIPv6, 80)
+
+	scan(s)
+	scan(s, ct)
+	scan(s)
+	Expect(fwd.Contents).To(HaveLen(1))
+	Expect(rev.Contents).To(HaveLen(1))
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package perf reads the samples that BPF programs send with bpf_perf_event_output() from the
+// per-CPU ring buffers of a perf event array map.
+package perf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+const (
+	// Offsets of data_head and data_tail in struct perf_event_mmap_page.
+	dataHeadOffset = 1024
+	dataTailOffset = 1032
+
+	recordHeaderSize = 8
+
+	pollTimeoutMS = 1000
+)
+
+type ring struct {
+	cpu  int
+	fd   int
+	mem  []byte
+	data []byte
+}
+
+// Reader reads the samples from all the CPUs of a perf event array.
+type Reader struct {
+	rings []*ring
+	byFD  map[int]*ring
+	epfd  int
+
+	// lock stops Close from releasing the rings while Run reads them.
+	lock   sync.Mutex
+	closed bool
+}
+
+// NewReader opens a perf event with a ring buffer of the given number of pages on each
+// possible CPU and stores it in the perf event array m.  CPUs that are not online are
+// skipped.
+func NewReader(m maps.Map, cpus int, pages int) (*Reader, error) {
+	epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create epoll fd: %w", err)
+	}
+	r := &Reader{
+		byFD: map[int]*ring{},
+		epfd: epfd,
+	}
+
+	pageSize := os.Getpagesize()
+	for cpu := 0; cpu < cpus; cpu++ {
+		attr := unix.PerfEventAttr{
+			Type:        unix.PERF_TYPE_SOFTWARE,
+			Config:      unix.PERF_COUNT_SW_BPF_OUTPUT,
+			Sample_type: unix.PERF_SAMPLE_RAW,
+			Sample:      1,
+			Wakeup:      1,
+		}
+		attr.Size = uint32(unsafe.Sizeof(attr))
+		fd, err := unix.PerfEventOpen(&attr, -1, cpu, -1, unix.PERF_FLAG_FD_CLOEXEC)
+		if errors.Is(err, unix.ENODEV) {
+			log.WithField("cpu", cpu).Debug("CPU is offline, skipping perf event.")
+			continue
+		}
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to open perf event on CPU %d: %w", cpu, err)
+		}
+		mem, err := unix.Mmap(fd, 0, (pages+1)*pageSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+		if err != nil {
+			unix.Close(fd)
+			r.Close()
+			return nil, fmt.Errorf("failed to map perf ring of CPU %d: %w", cpu, err)
+		}
+		rg := &ring{cpu: cpu, fd: fd, mem: mem, data: mem[pageSize:]}
+		r.rings = append(r.rings, rg)
+		r.byFD[fd] = rg
+
+		if err := unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_ENABLE, 0); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to enable perf event on CPU %d: %w", cpu, err)
+		}
+		if err := unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, fd,
+			&unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(fd)}); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to poll perf event on CPU %d: %w", cpu, err)
+		}
+
+		var k, v [4]byte
+		binary.LittleEndian.PutUint32(k[:], uint32(cpu))
+		binary.LittleEndian.PutUint32(v[:], uint32(fd))
+		if err := m.Update(k[:], v[:]); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to store perf event of CPU %d: %w", cpu, err)
+		}
+	}
+	return r, nil
+}
+
+// Run reads samples until the reader is closed, calling onSample with the raw data of each
+// sample and onLost with the number of samples that the kernel dropped because a ring was
+// full.  The data passed to onSample is only valid for the duration of the call.
+func (r *Reader) Run(onSample func(data []byte), onLost func(count uint64)) error {
+	events := make([]unix.EpollEvent, max(len(r.rings), 1))
+	for {
+		n, err := unix.EpollWait(r.epfd, events, pollTimeoutMS)
+		r.lock.Lock()
+		if r.closed {
+			r.lock.Unlock()
+			return nil
+		}
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			r.lock.Unlock()
+			return err
+		}
+		for _, ev := range events[:max(n, 0)] {
+			if rg := r.byFD[int(ev.Fd)]; rg != nil {
+				rg.read(onSample, onLost)
+			}
+		}
+		r.lock.Unlock()
+	}
+}
+
+func (rg *ring) read(onSample func(data []byte), onLost func(count uint64)) {
+	head := atomic.LoadUint64((*uint64)(unsafe.Pointer(&rg.mem[dataHeadOffset])))
+	tailPtr := (*uint64)(unsafe.Pointer(&rg.mem[dataTailOffset]))
+	tail := atomic.LoadUint64(tailPtr)
+	size := uint64(len(rg.data))
+
+	var buf []byte
+	for tail < head {
+		hdr := rg.copyOut(buf[:0], tail, recordHeaderSize)
+		recType := binary.LittleEndian.Uint32(hdr[0:4])
+		recSize := uint64(binary.LittleEndian.Uint16(hdr[6:8]))
+		if recSize < recordHeaderSize || recSize > size {
+			log.WithField("cpu", rg.cpu).Error("Corrupt perf record, discarding the ring.")
+			tail = head
+			break
+		}
+		buf = rg.copyOut(buf[:0], tail+recordHeaderSize, recSize-recordHeaderSize)
+		switch recType {
+		case unix.PERF_RECORD_SAMPLE:
+			if len(buf) >= 4 {
+				rawSize := int(binary.LittleEndian.Uint32(buf[0:4]))
+				if 4+rawSize <= len(buf) {
+					onSample(buf[4 : 4+rawSize])
+				}
+			}
+		case unix.PERF_RECORD_LOST:
+			if len(buf) >= 16 {
+				onLost(binary.LittleEndian.Uint64(buf[8:16]))
+			}
+		}
+		tail += recSize
+	}
+	atomic.StoreUint64(tailPtr, tail)
+}
+
+// copyOut appends n bytes at the given position of the ring to buf, handling the wrap around.
+func (rg *ring) copyOut(buf []byte, pos, n uint64) []byte {
+	size := uint64(len(rg.data))
+	start := pos % size
+	if start+n <= size {
+		return append(buf, rg.data[start:start+n]...)
+	}
+	buf = append(buf, rg.data[start:]...)
+	return append(buf, rg.data[:n-(size-start)]...)
+}
+
+// Close stops Run and releases the rings.
+func (r *Reader) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for _, rg := range r.rings {
+		_ = unix.IoctlSetInt(rg.fd, unix.PERF_EVENT_IOC_DISABLE, 0)
+		_ = unix.Munmap(rg.mem)
+		_ = unix.Close(rg.fd)
+	}
+	_ = unix.Close(r.epfd)
+}
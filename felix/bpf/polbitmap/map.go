@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package polbitmap holds the per-interface bitmaps of the policies that apply to the
+// interface's endpoint, as precomputed by the calculation graph.
+package polbitmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+func init() {
+	maps.SetSize(MapParams.VersionedName(), MapParams.MaxEntries)
+}
+
+const (
+	KeySize = 4
+	// ValueSize holds calc.MaxPolicyBitmapBits bits.
+	ValueSize  = 1024
+	MaxEntries = 1000
+)
+
+//	struct pol_bitmap {
+//		__u8 bits[1024];
+//	};
+var MapParams = maps.MapParameters{
+	Type:       "hash",
+	KeySize:    KeySize,
+	ValueSize:  ValueSize,
+	MaxEntries: MaxEntries,
+	Name:       "cali_pol_bits",
+	Flags:      unix.BPF_F_NO_PREALLOC,
+}
+
+func Map() maps.Map {
+	return maps.NewPinnedMap(MapParams)
+}
+
+// Key is the index of the interface.
+type Key [KeySize]byte
+
+func NewKey(ifIndex uint32) Key {
+	var k Key
+	binary.LittleEndian.PutUint32(k[:], ifIndex)
+	return k
+}
+
+func (k Key) AsBytes() []byte {
+	return k[:]
+}
+
+func (k Key) IfIndex() uint32 {
+	return binary.LittleEndian.Uint32(k[:])
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("{ifIndex: %d}", k.IfIndex())
+}
+
+func KeyFromBytes(b []byte) Key {
+	var k Key
+	copy(k[:], b)
+	return k
+}
+
+// Value is the bitmap, bit N of byte N/8 is set if the policy with index N applies.
+type Value [ValueSize]byte
+
+// NewValue pads the, possibly trimmed, bitmap of an endpoint.  It returns false if the
+// bitmap does not fit.
+func NewValue(bitmap []byte) (Value, bool) {
+	var v Value
+	if len(bitmap) > ValueSize {
+		return v, false
+	}
+	copy(v[:], bitmap)
+	return v, true
+}
+
+func (v Value) AsBytes() []byte {
+	return v[:]
+}
+
+func (v Value) Has(idx uint32) bool {
+	if idx >= ValueSize*8 {
+		return false
+	}
+	return v[idx/8]&(1<<(idx%8)) != 0
+}
+
+func (v Value) String() string {
+	n := 0
+	for _, b := range v {
+		n += bits.OnesCount8(b)
+	}
+	return fmt.Sprintf("{policies: %d}", n)
+}
+
+func ValueFromBytes(b []byte) Value {
+	var v Value
+	copy(v[:], b)
+	return v
+}
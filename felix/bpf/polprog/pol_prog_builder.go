@@ -56,6 +56,7 @@ type Builder struct {
 	policyMapStride    int
 	policyDebugEnabled bool
 	forIPv6            bool
+	ndpFailsafe        bool
 	allowJmp           int
 	denyJmp            int
 	useJmps            bool
@@ -136,6 +137,7 @@ var (
 	_                      = stateOffPreNATDstPort
 	stateOffPostNATDstPort = FieldOffset{Offset: stateEventHdrSize + 94, Field: "state->post_nat_dport"}
 	stateOffIPProto        = FieldOffset{Offset: stateEventHdrSize + 96, Field: "state->ip_proto"}
+	stateOffIPDSCP         = FieldOffset{Offset: stateEventHdrSize + 97, Field: "state->ip_dscp"}
 	stateOffIPSize         = FieldOffset{Offset: stateEventHdrSize + 98, Field: "state->ip_size"}
 	_                      = stateOffIPSize
 
@@ -236,6 +238,10 @@ func (p *Builder) Instructions(rules Rules) ([]Insns, error) {
 		goto normalPolicy
 	}
 
+	if p.ndpFailsafe && p.forIPv6 && rules.ForHostInterface {
+		p.writeNDPFailsafe()
+	}
+
 	// Pre-DNAT policy: on a host interface, or host-* policy on a workload interface.  Traffic
 	// is allowed to continue if there is no applicable pre-DNAT policy.
 	p.writeTiers(rules.HostPreDnatTiers, legDestPreNAT, "allowed_by_host_policy")
@@ -332,6 +338,20 @@ func (p *Builder) writeProgramHeader() {
 	p.b.LabelNextInsn("policy")
 }
 
+// writeNDPFailsafe emits instructions to allow the essential neighbor discovery messages
+// before any host endpoint policy, so that the policy can't break the host's IPv6
+// connectivity.
+func (p *Builder) writeNDPFailsafe() {
+	p.b.AddComment("If protocol == icmpv6 and type is router/neighbor solicitation/advertisement, allow")
+	p.b.Load8(R1, R9, stateOffIPProto)
+	p.b.JumpNEImm64(R1, 58, "ndp_failsafe_done")
+	p.b.Load8(R1, R9, stateOffICMPType)
+	for _, icmpType := range []int32{133, 134, 135, 136} {
+		p.b.JumpEqImm64(R1, icmpType, "allow")
+	}
+	p.b.LabelNextInsn("ndp_failsafe_done")
+}
+
 func (p *Builder) writeJumpIfToOrFromHost(label string) {
 	// Load state flags.
 	p.b.Load64(R1, R9, stateOffFlags)
@@ -529,11 +549,16 @@ func (p *Builder) writePolicyRules(policy Policy, actionLabels map[string]string
 		p.b.AddCommentF("Start of rule %s", rule)
 		p.b.AddCommentF("Rule MatchID: %d", rule.MatchID)
 		action := strings.ToLower(rule.Action)
+		actionLabel := actionLabels[action]
 		if action == "log" {
-			log.Debug("Skipping log rule.  Not supported in BPF mode.")
-			continue
+			if p.xdp {
+				log.Debug("Skipping log rule.  Not supported in XDP programs.")
+				continue
+			}
+			// Log rules carry on to the next rule once they have recorded their ID.
+			actionLabel = p.endOfRuleLabel()
 		}
-		p.writeRule(rule, actionLabels[action], destLeg)
+		p.writeRule(rule, actionLabel, destLeg)
 		log.Debugf("End of rule %d", ruleIdx)
 		p.b.AddCommentF("End of rule %s", rule.RuleId)
 	}
@@ -678,6 +703,13 @@ func (p *Builder) writeRule(r Rule, actionLabel string, destLeg matchLeg) {
 		p.writeIPSetMatch(false, destLeg, rule.DstIpPortSetIds)
 	}
 
+	if len(rule.DstDomainIpSetIds) > 0 {
+		// Felix doesn't snoop DNS in BPF mode so the domain sets are empty and the rule
+		// matches nothing.
+		log.WithField("domainSetIDs", rule.DstDomainIpSetIds).Debugf("DstDomainIpSetIds match")
+		p.writeIPSetMatch(false, destLeg, rule.DstDomainIpSetIds)
+	}
+
 	if len(rule.SrcPorts) > 0 || len(rule.SrcNamedPortIpSetIds) > 0 {
 		log.WithFields(log.Fields{
 			"ports":   rule.SrcPorts,
@@ -717,6 +749,13 @@ func (p *Builder) writeRule(r Rule, actionLabel string, destLeg matchLeg) {
 			p.writeICMPTypeMatch(false, uint8(icmp.IcmpType))
 		}
 	}
+	if len(rule.IcmpTypes) > 0 || len(rule.IcmpTypeCodes) > 0 {
+		log.WithFields(log.Fields{
+			"types":      rule.IcmpTypes,
+			"type/codes": rule.IcmpTypeCodes,
+		}).Debugf("ICMP types match")
+		p.writeICMPTypesMatch(rule.IcmpTypes, rule.IcmpTypeCodes)
+	}
 	if rule.NotIcmp != nil {
 		log.WithField("icmpv4", rule.Icmp).Debugf("Not ICMP type/code match")
 		switch icmp := rule.NotIcmp.(type) {
@@ -727,6 +766,15 @@ func (p *Builder) writeRule(r Rule, actionLabel string, destLeg matchLeg) {
 		}
 	}
 
+	if dscp, ok := rule.Dscp.(*proto.Rule_DscpValue); ok {
+		log.WithField("dscp", dscp.DscpValue).Debugf("DSCP match")
+		p.writeDSCPMatch(false, uint8(dscp.DscpValue))
+	}
+	if dscp, ok := rule.NotDscp.(*proto.Rule_NotDscpValue); ok {
+		log.WithField("dscp", dscp.NotDscpValue).Debugf("NotDSCP match")
+		p.writeDSCPMatch(true, uint8(dscp.NotDscpValue))
+	}
+
 	p.writeEndOfRule(r, actionLabel)
 	p.ruleID++
 	p.rulePartID = 0
@@ -739,12 +787,17 @@ func (p *Builder) writeStartOfRule() {
 func (p *Builder) writeEndOfRule(rule Rule, actionLabel string) {
 	// If all the match criteria are met, we fall through to the end of the rule
 	// so all that's left to do is to jump to the relevant action.
-	// TODO log and log-and-xxx actions
-	if p.policyDebugEnabled {
-		p.writeRecordRuleHit(rule, actionLabel)
-	}
+	if strings.ToLower(rule.Action) == "log" {
+		// Log rules have no action of their own.  Record the rule ID so that
+		// the TC program logs the packet once the verdict is known.
+		p.writeRecordRuleID(rule.MatchID, p.endOfRuleLabel())
+	} else {
+		if p.policyDebugEnabled {
+			p.writeRecordRuleHit(rule, actionLabel)
+		}
 
-	p.b.Jump(actionLabel)
+		p.b.Jump(actionLabel)
+	}
 
 	p.b.LabelNextInsn(p.endOfRuleLabel())
 }
@@ -792,6 +845,52 @@ func (p *Builder) writeICMPTypeCodeMatch(negate bool, icmpType, icmpCode uint8)
 		p.b.JumpNEImm64(R1, (int32(icmpCode)<<8)|int32(icmpType), p.endOfRuleLabel())
 	}
 }
+func (p *Builder) writeICMPTypesMatch(icmpTypes []int32, icmpTypeCodes []*proto.IcmpTypeAndCode) {
+	// The types and type/code pairs are ORed together, if we match any of them, go to the
+	// next match criteria.
+	onMatchLabel := p.freshPerRuleLabel()
+
+	if p.policyDebugEnabled {
+		var typeStrs []string
+		for _, t := range icmpTypes {
+			typeStrs = append(typeStrs, fmt.Sprint(t))
+		}
+		for _, tc := range icmpTypeCodes {
+			typeStrs = append(typeStrs, fmt.Sprintf("%d/%d", tc.Type, tc.Code))
+		}
+		p.b.AddCommentF("If ICMP type/code is not any of {%s}, skip to next rule", strings.Join(typeStrs, ","))
+	}
+	if len(icmpTypes) > 0 {
+		p.b.Load8(R1, R9, stateOffICMPType)
+		for _, t := range icmpTypes {
+			p.b.JumpEqImm64(R1, t, onMatchLabel)
+		}
+	}
+	if len(icmpTypeCodes) > 0 {
+		p.b.Load16(R1, R9, stateOffICMPType)
+		for _, tc := range icmpTypeCodes {
+			p.b.JumpEqImm64(R1, (tc.Code<<8)|tc.Type, onMatchLabel)
+		}
+	}
+	// No match, skip to the next rule.
+	p.b.Jump(p.endOfRuleLabel())
+	p.b.LabelNextInsn(onMatchLabel)
+}
+
+func (p *Builder) writeDSCPMatch(negate bool, dscp uint8) {
+	if negate {
+		p.b.AddCommentF("If DSCP == %d, skip to next rule", dscp)
+	} else {
+		p.b.AddCommentF("If DSCP != %d, skip to next rule", dscp)
+	}
+	p.b.Load8(R1, R9, stateOffIPDSCP)
+	if negate {
+		p.b.JumpEqImm64(R1, int32(dscp), p.endOfRuleLabel())
+	} else {
+		p.b.JumpNEImm64(R1, int32(dscp), p.endOfRuleLabel())
+	}
+}
+
 func (p *Builder) writeCIDRSMatch(negate bool, leg matchLeg, cidrs []string) {
 	if p.policyDebugEnabled {
 		cidrStrings := "{"
@@ -1217,6 +1316,14 @@ func WithIPv6() Option {
 	}
 }
 
+// WithNDPFailsafe makes the programs for host interfaces allow the essential IPv6
+// neighbor discovery messages irrespective of the host endpoint policy.
+func WithNDPFailsafe() Option {
+	return func(p *Builder) {
+		p.ndpFailsafe = true
+	}
+}
+
 // WithPolicyMapIndexAndStride tells the builder the "shape" of the policy
 // jump map, allowing it to split the program if it gets too large.
 // entryPointIdx is the jump map key for the first "entry point" program.
@@ -81,29 +81,55 @@ func TestPolicySanityCheck(t *testing.T) {
 	}
 }
 
-func TestLogActionIgnored(t *testing.T) {
+func TestLogActionRecordsRuleID(t *testing.T) {
 	RegisterTestingT(t)
 	alloc := idalloc.New()
 
+	logRules := func(forXDP bool) Rules {
+		return Rules{
+			ForXDP: forXDP,
+			Tiers: []Tier{{
+				Name: "default",
+				Policies: []Policy{{
+					Name: "test policy",
+					Rules: []Rule{{
+						Rule:    &proto.Rule{Action: "Log"},
+						MatchID: 0x1234,
+					}},
+				}},
+			}}}
+	}
+	noOpRules := func(forXDP bool) Rules {
+		return Rules{
+			ForXDP: forXDP,
+			Tiers: []Tier{{
+				Name:     "default",
+				Policies: []Policy{},
+			}}}
+	}
+
 	pg := NewBuilder(alloc, 1, 2, 3, 4, WithAllowDenyJumps(666, 777))
-	insns, err := pg.Instructions(Rules{
-		Tiers: []Tier{{
-			Name: "default",
-			Policies: []Policy{{
-				Name: "test policy",
-				Rules: []Rule{{Rule: &proto.Rule{
-					Action: "Log",
-				}}},
-			}},
-		}}})
+	insns, err := pg.Instructions(logRules(false))
 	Expect(err).NotTo(HaveOccurred())
+	pg = NewBuilder(alloc, 1, 2, 3, 4, WithAllowDenyJumps(666, 777))
+	noOpInsns, err := pg.Instructions(noOpRules(false))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(insns).NotTo(Equal(noOpInsns))
 
+	var ruleIDs []int32
+	for _, in := range insns[0] {
+		if in.OpCode() == asm.LoadImm64 {
+			ruleIDs = append(ruleIDs, in.Imm())
+		}
+	}
+	Expect(ruleIDs).To(ContainElement(int32(0x1234)), "log rule should record its rule ID")
+
+	// XDP programs cannot log, so log rules are skipped.
 	pg = NewBuilder(alloc, 1, 2, 3, 4, WithAllowDenyJumps(666, 777))
-	noOpInsns, err := pg.Instructions(Rules{
-		Tiers: []Tier{{
-			Name:     "default",
-			Policies: []Policy{},
-		}}})
+	insns, err = pg.Instructions(logRules(true))
+	Expect(err).NotTo(HaveOccurred())
+	pg = NewBuilder(alloc, 1, 2, 3, 4, WithAllowDenyJumps(666, 777))
+	noOpInsns, err = pg.Instructions(noOpRules(true))
 	Expect(err).NotTo(HaveOccurred())
 	Expect(noOpInsns).To(Equal(insns))
 }
@@ -167,6 +193,32 @@ func TestPolicyDump(t *testing.T) {
 	checkLabelsAndComments(proto.Rule{NotIcmp: &proto.Rule_NotIcmpTypeCode{NotIcmpTypeCode: &proto.IcmpTypeAndCode{Type: 10, Code: 12}}}, "If ICMP type == 10 and code == 12, skip to next rule", "comment")
 	checkLabelsAndComments(proto.Rule{Icmp: &proto.Rule_IcmpType{IcmpType: 10}}, "If ICMP type != 10, skip to next rule", "comment")
 	checkLabelsAndComments(proto.Rule{NotIcmp: &proto.Rule_NotIcmpType{NotIcmpType: 10}}, "If ICMP type == 10, skip to next rule", "comment")
+	checkLabelsAndComments(proto.Rule{IcmpTypes: []int32{133, 134}, IcmpTypeCodes: []*proto.IcmpTypeAndCode{{Type: 1, Code: 4}}}, "If ICMP type/code is not any of {133,134,1/4}, skip to next rule", "comment")
+}
+
+func TestNDPFailsafe(t *testing.T) {
+	RegisterTestingT(t)
+	alloc := idalloc.New()
+
+	hasNDPFailsafe := func(forHostInterface bool, opts ...Option) bool {
+		opts = append(opts, WithAllowDenyJumps(666, 777), WithPolicyDebugEnabled())
+		pg := NewBuilder(alloc, 1, 2, 3, 4, opts...)
+		insns, err := pg.Instructions(Rules{ForHostInterface: forHostInterface})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(insns).To(HaveLen(1))
+		labels, _ := aggregateCommentsAndLabels(&insns[0])
+		for _, l := range labels {
+			if l == "ndp_failsafe_done" {
+				return true
+			}
+		}
+		return false
+	}
+
+	Expect(hasNDPFailsafe(true, WithIPv6(), WithNDPFailsafe())).To(BeTrue())
+	Expect(hasNDPFailsafe(true, WithIPv6())).To(BeFalse(), "NDP failsafe is disabled by default")
+	Expect(hasNDPFailsafe(true, WithNDPFailsafe())).To(BeFalse(), "NDP failsafe is IPv6 only")
+	Expect(hasNDPFailsafe(false, WithIPv6(), WithNDPFailsafe())).To(BeFalse(), "NDP failsafe is for host interfaces only")
 }
 
 func aggregateCommentsAndLabels(insns *asm.Insns) ([]string, []string) {
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/proxy/apis"
+
+	"github.com/projectcalico/calico/felix/bpf/nat"
+)
+
+// AuditFindingKind is the kind of difference between how kube-proxy and the
+// BPF proxy handle a service port.
+type AuditFindingKind string
+
+const (
+	// AuditExcluded is a service port that kube-proxy handles but that the BPF
+	// proxy is configured to ignore.
+	AuditExcluded AuditFindingKind = "Excluded"
+	// AuditUnsupported is a service port that uses a feature that the BPF
+	// proxy, as configured, does not support.
+	AuditUnsupported AuditFindingKind = "Unsupported"
+	// AuditOverLimit is a service port with more ready backends than the BPF
+	// proxy programs.
+	AuditOverLimit AuditFindingKind = "OverLimit"
+	// AuditNoEndpoints is a service port without any ready backend.  Both
+	// proxies reject its traffic, it is reported so that it can be checked
+	// before the migration.
+	AuditNoEndpoints AuditFindingKind = "NoEndpoints"
+)
+
+// AuditOptions describes the BPF proxy that the services are audited for.
+type AuditOptions struct {
+	// IPv6Enabled is whether the BPF dataplane handles IPv6.
+	IPv6Enabled bool
+	// Limits are the per-service limits of the BPF proxy.
+	Limits ServiceLimits
+}
+
+// AuditFinding is a service port that the BPF proxy would handle differently
+// from kube-proxy.
+type AuditFinding struct {
+	Service types.NamespacedName
+	Port    string
+	Kind    AuditFindingKind
+	Detail  string
+}
+
+// AuditReport is the result of an audit of the services of a cluster.
+type AuditReport struct {
+	// ServicePorts is the number of service ports that were audited.
+	ServicePorts int
+	// Findings are sorted by service and port.
+	Findings []AuditFinding
+}
+
+// Ready returns whether the BPF proxy can replace kube-proxy without changing
+// how any service is handled.  Service ports without endpoints don't count,
+// both proxies reject their traffic.
+func (r *AuditReport) Ready() bool {
+	for _, f := range r.Findings {
+		if f.Kind != AuditNoEndpoints {
+			return false
+		}
+	}
+	return true
+}
+
+// Audit compares how the BPF proxy would handle the services, with their
+// endpoint slices, to what kube-proxy does, without programming anything, so
+// that it can run while kube-proxy is still active.  Services that kube-proxy
+// ignores as well, such as headless services, are skipped.
+func Audit(svcs []*v1.Service, slices []*discovery.EndpointSlice, opts AuditOptions) *AuditReport {
+	slicesBySvc := make(map[types.NamespacedName][]*discovery.EndpointSlice)
+	for _, s := range slices {
+		name, ok := s.Labels[discovery.LabelServiceName]
+		if !ok {
+			continue
+		}
+		k := types.NamespacedName{Namespace: s.Namespace, Name: name}
+		slicesBySvc[k] = append(slicesBySvc[k], s)
+	}
+
+	r := &AuditReport{}
+	for _, svc := range svcs {
+		if _, ok := svc.Labels[apis.LabelServiceProxyName]; ok {
+			continue
+		}
+		if svc.Spec.Type == v1.ServiceTypeExternalName || svc.Spec.ClusterIP == v1.ClusterIPNone {
+			continue
+		}
+		svcName := types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}
+		for _, port := range svc.Spec.Ports {
+			r.ServicePorts++
+			r.Findings = append(r.Findings, auditServicePort(svc, svcName, port, slicesBySvc[svcName], opts)...)
+		}
+	}
+
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if a.Service != b.Service {
+			return a.Service.String() < b.Service.String()
+		}
+		return a.Port < b.Port
+	})
+	return r
+}
+
+func auditServicePort(svc *v1.Service, svcName types.NamespacedName, port v1.ServicePort,
+	slices []*discovery.EndpointSlice, opts AuditOptions) []AuditFinding {
+
+	finding := func(kind AuditFindingKind, format string, args ...interface{}) AuditFinding {
+		return AuditFinding{
+			Service: svcName,
+			Port:    auditPortName(port),
+			Kind:    kind,
+			Detail:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	if svc.Annotations[ExcludeServiceAnnotation] == "true" {
+		return []AuditFinding{finding(AuditExcluded, "excluded by the %s annotation", ExcludeServiceAnnotation)}
+	}
+
+	var findings []AuditFinding
+	for _, family := range auditIPFamilies(svc) {
+		if family == v1.IPv6Protocol && !opts.IPv6Enabled {
+			findings = append(findings, finding(AuditUnsupported, "IPv6 is not enabled in the BPF dataplane"))
+			continue
+		}
+
+		ipFamily := 4
+		addrType := discovery.AddressTypeIPv4
+		if family == v1.IPv6Protocol {
+			ipFamily = 6
+			addrType = discovery.AddressTypeIPv6
+		}
+		ready := auditReadyBackends(slices, addrType, port.Name)
+		max := nat.BackendsLimit(ipFamily)
+		if hard := opts.Limits.BackendsHard; hard > 0 && hard < max {
+			max = hard
+		}
+		switch {
+		case ready == 0:
+			findings = append(findings, finding(AuditNoEndpoints, "no ready %s endpoints", family))
+		case ready > max:
+			findings = append(findings, finding(AuditOverLimit,
+				"only %d of the %d ready %s backends would be programmed", max, ready, family))
+		}
+	}
+	return findings
+}
+
+// auditIPFamilies returns the IP families of the service, from its cluster IP
+// for services without the field.
+func auditIPFamilies(svc *v1.Service) []v1.IPFamily {
+	if len(svc.Spec.IPFamilies) > 0 {
+		return svc.Spec.IPFamilies
+	}
+	if ip := net.ParseIP(svc.Spec.ClusterIP); ip != nil && ip.To4() == nil {
+		return []v1.IPFamily{v1.IPv6Protocol}
+	}
+	return []v1.IPFamily{v1.IPv4Protocol}
+}
+
+// auditReadyBackends counts the ready endpoints of the slices of the given
+// address type that serve the named port.
+func auditReadyBackends(slices []*discovery.EndpointSlice, addrType discovery.AddressType, portName string) int {
+	n := 0
+	for _, s := range slices {
+		if s.AddressType != addrType || !auditSliceHasPort(s, portName) {
+			continue
+		}
+		for _, ep := range s.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				n += len(ep.Addresses)
+			}
+		}
+	}
+	return n
+}
+
+func auditSliceHasPort(s *discovery.EndpointSlice, portName string) bool {
+	for _, p := range s.Ports {
+		name := ""
+		if p.Name != nil {
+			name = *p.Name
+		}
+		if name == portName {
+			return true
+		}
+	}
+	return false
+}
+
+func auditPortName(port v1.ServicePort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%d/%s", port.Port, port.Protocol)
+}
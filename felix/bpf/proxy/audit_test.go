@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+)
+
+func TestAudit(t *testing.T) {
+	RegisterTestingT(t)
+
+	service := func(name string, families ...v1.IPFamily) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: v1.ServiceSpec{
+				ClusterIP:  "10.96.0.10",
+				IPFamilies: families,
+				Ports:      []v1.ServicePort{{Name: "http", Port: 80, Protocol: v1.ProtocolTCP}},
+			},
+		}
+	}
+	slice := func(svc string, addrType discovery.AddressType, addrs ...string) *discovery.EndpointSlice {
+		name := "http"
+		notReady := false
+		return &discovery.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      svc + "-" + string(addrType),
+				Labels:    map[string]string{discovery.LabelServiceName: svc},
+			},
+			AddressType: addrType,
+			Ports:       []discovery.EndpointPort{{Name: &name}},
+			Endpoints: []discovery.Endpoint{
+				{Addresses: addrs},
+				{Addresses: []string{"10.65.9.9"}, Conditions: discovery.EndpointConditions{Ready: &notReady}},
+			},
+		}
+	}
+
+	ok := service("ok", v1.IPv4Protocol)
+	excluded := service("excluded")
+	excluded.Annotations = map[string]string{proxy.ExcludeServiceAnnotation: "true"}
+	dualStack := service("dual-stack", v1.IPv4Protocol, v1.IPv6Protocol)
+	empty := service("empty")
+	big := service("big")
+	headless := service("headless")
+	headless.Spec.ClusterIP = v1.ClusterIPNone
+
+	svcs := []*v1.Service{ok, excluded, dualStack, empty, big, headless}
+	slices := []*discovery.EndpointSlice{
+		slice("ok", discovery.AddressTypeIPv4, "10.65.0.1"),
+		slice("excluded", discovery.AddressTypeIPv4, "10.65.0.2"),
+		slice("dual-stack", discovery.AddressTypeIPv4, "10.65.0.3"),
+		slice("dual-stack", discovery.AddressTypeIPv6, "fd00::3"),
+		slice("big", discovery.AddressTypeIPv4, "10.65.0.4", "10.65.0.5", "10.65.0.6"),
+	}
+
+	r := proxy.Audit(svcs, slices, proxy.AuditOptions{Limits: proxy.ServiceLimits{BackendsHard: 2}})
+	Expect(r.ServicePorts).To(Equal(5))
+	Expect(r.Findings).To(Equal([]proxy.AuditFinding{
+		{
+			Service: types.NamespacedName{Namespace: "default", Name: "big"},
+			Port:    "http",
+			Kind:    proxy.AuditOverLimit,
+			Detail:  "only 2 of the 3 ready IPv4 backends would be programmed",
+		},
+		{
+			Service: types.NamespacedName{Namespace: "default", Name: "dual-stack"},
+			Port:    "http",
+			Kind:    proxy.AuditUnsupported,
+			Detail:  "IPv6 is not enabled in the BPF dataplane",
+		},
+		{
+			Service: types.NamespacedName{Namespace: "default", Name: "empty"},
+			Port:    "http",
+			Kind:    proxy.AuditNoEndpoints,
+			Detail:  "no ready IPv4 endpoints",
+		},
+		{
+			Service: types.NamespacedName{Namespace: "default", Name: "excluded"},
+			Port:    "http",
+			Kind:    proxy.AuditExcluded,
+			Detail:  "excluded by the projectcalico.org/natExcludeService annotation",
+		},
+	}))
+	Expect(r.Ready()).To(BeFalse())
+
+	r = proxy.Audit([]*v1.Service{ok, empty, dualStack}, slices, proxy.AuditOptions{IPv6Enabled: true})
+	Expect(r.Findings).To(HaveLen(1))
+	Expect(r.Ready()).To(BeTrue())
+}
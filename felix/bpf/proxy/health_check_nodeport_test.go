@@ -15,6 +15,7 @@
 package proxy_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -252,7 +253,7 @@ func (s *mockDummySyncer) SetTriggerFn(_ func()) {
 
 func (*mockDummySyncer) Stop() {}
 
-func (*mockDummySyncer) Apply(state proxy.DPSyncerState) error {
+func (*mockDummySyncer) Apply(_ context.Context, state proxy.DPSyncerState) error {
 	log("state = %+v\n", state)
 	return nil
 }
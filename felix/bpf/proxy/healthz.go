@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/kubernetes/pkg/proxy/healthcheck"
+	"k8s.io/kubernetes/pkg/proxy/metrics"
+)
+
+const (
+	// healthzTimeout matches kube-proxy's default of twice its 30s sync
+	// period. The proxy is unhealthy if a queued update is not applied within
+	// that time.
+	healthzTimeout = 60 * time.Second
+
+	serverRestartPeriod = 5 * time.Second
+)
+
+// StartHealthzServer starts serving kube-proxy compatible /healthz and /livez
+// endpoints on the given address. Pass the returned server to the proxies
+// using WithHealthzServer so that they report their sync state to it.
+func StartHealthzServer(addr, hostname string) healthcheck.ProxierHealthUpdater {
+	nodeRef := &v1.ObjectReference{
+		Kind: "Node",
+		Name: hostname,
+		UID:  types.UID(hostname),
+	}
+	hs := healthcheck.NewProxierHealthServer(addr, healthzTimeout, new(loggerRecorder), nodeRef)
+
+	go wait.Until(func() {
+		if err := hs.Run(); err != nil {
+			log.WithError(err).WithField("addr", addr).Error("kube-proxy healthz server failed")
+		}
+	}, serverRestartPeriod, wait.NeverStop)
+
+	return hs
+}
+
+// StartMetricsServer starts serving the kube-proxy metrics on /metrics on the
+// given address.
+func StartMetricsServer(addr string) {
+	metrics.RegisterMetrics()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", legacyregistry.Handler())
+
+	go wait.Until(func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.WithError(err).WithField("addr", addr).Error("kube-proxy metrics server failed")
+		}
+	}, serverRestartPeriod, wait.NeverStop)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+)
+
+type fakeHealthz struct {
+	queued  atomic.Int32
+	updated atomic.Int32
+}
+
+func (f *fakeHealthz) QueuedUpdate()       { f.queued.Add(1) }
+func (f *fakeHealthz) Updated()            { f.updated.Add(1) }
+func (f *fakeHealthz) Run() error          { return nil }
+func (f *fakeHealthz) SyncNode(_ *v1.Node) {}
+func (f *fakeHealthz) IsHealthy() bool     { return true }
+
+var _ = Describe("BPF Proxy healthz", func() {
+	It("should report only successful syncs as updates", func() {
+		syncStop := make(chan struct{})
+		dp := newMockSyncer(syncStop)
+		hs := new(fakeHealthz)
+
+		k8s := fake.NewSimpleClientset()
+		p, err := proxy.New(k8s, dp, "testnode", proxy.WithImmediateSync(), proxy.WithHealthzServer(hs))
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			close(syncStop)
+			p.Stop()
+		}()
+
+		// Fail the initial sync.
+		<-dp.out
+		dp.in <- errors.New("test failure")
+		Consistently(hs.updated.Load).Should(BeZero())
+
+		svc := &v1.Service{
+			TypeMeta:   typeMetaV1("Service"),
+			ObjectMeta: objectMetaV1("healthz-svc"),
+			Spec: v1.ServiceSpec{
+				ClusterIP: "10.1.0.1",
+				Type:      v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{{
+					Protocol: v1.ProtocolTCP,
+					Port:     1234,
+				}},
+			},
+		}
+		_, err = k8s.CoreV1().Services(svc.Namespace).Create(context.Background(), svc, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		<-dp.out
+		dp.in <- nil
+		Eventually(hs.updated.Load).Should(BeNumerically(">", 0))
+		Expect(hs.queued.Load()).To(BeNumerically(">", 0))
+	})
+
+	It("should serve kube-proxy compatible /healthz", func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := l.Addr().String()
+		Expect(l.Close()).To(Succeed())
+
+		hs := proxy.StartHealthzServer(addr, "testnode")
+		hs.Updated()
+
+		var resp *http.Response
+		Eventually(func() error {
+			resp, err = http.Get("http://" + addr + "/healthz")
+			return err
+		}).Should(Succeed())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var body map[string]interface{}
+		Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+		Expect(body).To(HaveKey("lastUpdated"))
+		Expect(body).To(HaveKey("currentTime"))
+	})
+})
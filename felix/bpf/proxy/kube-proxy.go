@@ -21,7 +21,9 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/events"
 
+	"github.com/projectcalico/calico/felix/bpf/bpflog"
 	"github.com/projectcalico/calico/felix/bpf/bpfmap"
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/routes"
@@ -51,6 +53,13 @@ type KubeProxy struct {
 	opts        []Option
 
 	excludedCIDRs *ip.CIDRTrie
+	// nodePortCIDRs restrict the host IPs of the NodePorts if not nil.
+	nodePortCIDRs []ip.CIDR
+	hostPorts     []HostPort
+	svcLimits     ServiceLimits
+
+	eventBroadcaster events.EventBroadcasterAdapter
+	recorder         events.EventRecorder
 
 	dsrEnabled bool
 }
@@ -80,10 +89,15 @@ func StartKubeProxy(k8s kubernetes.Interface, hostname string,
 		}
 	}
 
+	// Report the services that exceed the limits as events on the services.
+	kp.eventBroadcaster = events.NewEventBroadcasterAdapter(k8s)
+	kp.eventBroadcaster.StartRecordingToSink(kp.exiting)
+	kp.recorder = kp.eventBroadcaster.NewRecorder("calico-bpf-kube-proxy")
+
 	go func() {
 		err := kp.start()
 		if err != nil {
-			log.WithError(err).Panic("kube-proxy failed to start")
+			bpflog.WithIPFamily(kp.ipFamily).WithError(err).Panic("kube-proxy failed to start")
 		}
 	}()
 
@@ -104,6 +118,7 @@ func (kp *KubeProxy) Stop() {
 		close(kp.hostIPUpdates)
 		kp.proxy.Stop()
 		kp.wg.Wait()
+		kp.eventBroadcaster.Shutdown()
 	})
 }
 
@@ -118,7 +133,7 @@ func (kp *KubeProxy) run(hostIPs []net.IP) error {
 		}
 	}
 
-	hostIPs = ips
+	hostIPs = kp.filterNodePortIPs(ips)
 
 	kp.lock.Lock()
 	defer kp.lock.Unlock()
@@ -136,16 +151,44 @@ func (kp *KubeProxy) run(hostIPs []net.IP) error {
 	if err != nil {
 		return errors.WithMessage(err, "new bpf syncer")
 	}
+	syncer.SetServiceLimits(kp.svcLimits)
+	syncer.SetEventRecorder(kp.recorder)
 
 	kp.proxy.SetSyncer(syncer)
 
-	log.Infof("kube-proxy v%d node info updated, hostname=%q hostIPs=%+v", kp.ipFamily, kp.hostname, hostIPs)
+	bpflog.WithIPFamily(kp.ipFamily).Infof("kube-proxy v%d node info updated, hostname=%q hostIPs=%+v", kp.ipFamily, kp.hostname, hostIPs)
 
 	kp.syncer = syncer
 
 	return nil
 }
 
+// filterNodePortIPs returns the host IPs that are within the NodePort CIDRs,
+// if any are set.
+func (kp *KubeProxy) filterNodePortIPs(hostIPs []net.IP) []net.IP {
+	if kp.nodePortCIDRs == nil {
+		return hostIPs
+	}
+
+	var ips []net.IP
+	for _, hostIP := range hostIPs {
+		addr := ip.FromNetIP(hostIP)
+		for _, cidr := range kp.nodePortCIDRs {
+			if cidr.Contains(addr) {
+				ips = append(ips, hostIP)
+				break
+			}
+		}
+	}
+	if len(ips) < len(hostIPs) {
+		bpflog.WithIPFamily(kp.ipFamily).WithFields(log.Fields{
+			"hostIPs":           hostIPs,
+			"nodePortAddresses": kp.nodePortCIDRs,
+		}).Infof("kube-proxy v%d: NodePorts restricted to host IPs %v", kp.ipFamily, ips)
+	}
+	return ips
+}
+
 func (kp *KubeProxy) start() error {
 	var withLocalNP []net.IP
 	if kp.ipFamily == 4 {
@@ -158,6 +201,8 @@ func (kp *KubeProxy) start() error {
 	if err != nil {
 		return errors.WithMessage(err, "new bpf syncer")
 	}
+	syncer.SetServiceLimits(kp.svcLimits)
+	syncer.SetEventRecorder(kp.recorder)
 
 	proxy, err := New(kp.k8s, syncer, kp.hostname, kp.opts...)
 	if err != nil {
@@ -167,6 +212,9 @@ func (kp *KubeProxy) start() error {
 	kp.lock.Lock()
 	kp.proxy = proxy
 	kp.syncer = syncer
+	if kp.hostPorts != nil {
+		proxy.SetHostPorts(kp.hostPorts)
+	}
 	kp.lock.Unlock()
 
 	// wait for the initial update
@@ -184,15 +232,15 @@ func (kp *KubeProxy) start() error {
 			select {
 			case hostIPs, ok := <-kp.hostIPUpdates:
 				if !ok {
-					log.Error("kube-proxy: hostIPUpdates closed")
+					bpflog.WithIPFamily(kp.ipFamily).Error("kube-proxy: hostIPUpdates closed")
 					return
 				}
 				err = kp.run(hostIPs)
 				if err != nil {
-					log.Panic("kube-proxy failed to resync after host IPs update")
+					bpflog.WithIPFamily(kp.ipFamily).Panic("kube-proxy failed to resync after host IPs update")
 				}
 			case <-kp.exiting:
-				log.Info("kube-proxy: exiting")
+				bpflog.WithIPFamily(kp.ipFamily).Info("kube-proxy: exiting")
 				return
 			}
 		}
@@ -216,19 +264,45 @@ func (kp *KubeProxy) OnHostIPsUpdate(IPs []net.IP) {
 		}
 		kp.hostIPUpdates <- IPs
 	}
-	log.Debugf("kube-proxy OnHostIPsUpdate: %+v", IPs)
+	bpflog.WithIPFamily(kp.ipFamily).Debugf("kube-proxy OnHostIPsUpdate: %+v", IPs)
+}
+
+// OnHostPortsUpdate should be used by an external user to update the proxy's
+// list of hostPort mappings of local workloads. Mappings of the other IP family
+// are ignored.
+func (kp *KubeProxy) OnHostPortsUpdate(hps []HostPort) {
+	filtered := make([]HostPort, 0, len(hps))
+	for _, hp := range hps {
+		if (kp.ipFamily == 4) != (hp.IP.To4() != nil) {
+			continue
+		}
+		if hp.HostIP != nil && (kp.ipFamily == 4) != (hp.HostIP.To4() != nil) {
+			continue
+		}
+		filtered = append(filtered, hp)
+	}
+
+	kp.lock.Lock()
+	defer kp.lock.Unlock()
+
+	// Remember the mappings in case the proxy has not been created yet.
+	kp.hostPorts = filtered
+	if kp.proxy != nil {
+		kp.proxy.SetHostPorts(filtered)
+	}
+	bpflog.WithIPFamily(kp.ipFamily).Debugf("kube-proxy OnHostPortsUpdate: %+v", filtered)
 }
 
 // OnRouteUpdate should be used to update the internal state of routing tables
 func (kp *KubeProxy) OnRouteUpdate(k routes.KeyInterface, v routes.ValueInterface) {
-	log.WithFields(log.Fields{"key": k, "value": v}).Debug("kube-proxy: OnRouteUpdate")
+	bpflog.WithIPFamily(kp.ipFamily).WithFields(log.Fields{"key": k, "value": v}).Debug("kube-proxy: OnRouteUpdate")
 	kp.rt.Update(k, v)
 }
 
 // OnRouteDelete should be used to update the internal state of routing tables
 func (kp *KubeProxy) OnRouteDelete(k routes.KeyInterface) {
 	kp.rt.Delete(k)
-	log.WithField("key", k).Debug("kube-proxy: OnRouteDelete")
+	bpflog.WithIPFamily(kp.ipFamily).WithField("key", k).Debug("kube-proxy: OnRouteDelete")
 }
 
 // ConntrackScanStart to satisfy conntrack.NATChecker - forwards to syncer.
@@ -261,3 +335,13 @@ func (kp *KubeProxy) ConntrackFrontendHasBackend(ip net.IP, port uint16, backend
 	// We cannot say yet, so do not break anything
 	return true
 }
+
+// ConntrackFrontendService to satisfy conntrack.ServiceNamer - forwards to syncer.
+func (kp *KubeProxy) ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool) {
+	// Like ConntrackFrontendHasBackend, only valid between ConntrackScanStart and End.
+	if kp.syncer != nil {
+		return kp.syncer.ConntrackFrontendService(ip, port, proto)
+	}
+
+	return "", false
+}
@@ -180,3 +180,81 @@ var _ = Describe("BPF kube-proxy", func() {
 		})
 	})
 })
+
+var _ = Describe("BPF kube-proxy with NodePort addresses", func() {
+	maps := new(bpfmap.IPMaps)
+	maps.FrontendMap = newMockNATMap()
+	maps.BackendMap = newMockNATBackendMap()
+	maps.AffinityMap = newMockAffinityMap()
+	maps.CtMap = mock.NewMockMap(conntrack.MapParams)
+	front := maps.FrontendMap.(*mockNATMap)
+
+	var p *proxy.KubeProxy
+
+	BeforeEach(func() {
+		testSvc := &v1.Service{
+			TypeMeta:   typeMetaV1("Service"),
+			ObjectMeta: objectMetaV1("testService"),
+			Spec: v1.ServiceSpec{
+				ClusterIP: "10.1.0.1",
+				Type:      v1.ServiceTypeNodePort,
+				Selector: map[string]string{
+					"app": "test",
+				},
+				Ports: []v1.ServicePort{
+					{
+						Protocol: v1.ProtocolTCP,
+						Port:     1234,
+						NodePort: 666,
+					},
+				},
+			},
+		}
+
+		k8s := fake.NewSimpleClientset(testSvc)
+		var err error
+		p, err = proxy.StartKubeProxy(k8s, "test-node", maps, proxy.WithImmediateSync(),
+			proxy.WithNodePortAddresses([]string{"3.3.3.0/24", "dead:beef::/64"}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		p.Stop()
+	})
+
+	It("should only program nodeports on the host IPs within the CIDRs", func() {
+		inIP := net.IPv4(3, 3, 3, 3)
+		outIP := net.IPv4(4, 4, 4, 4)
+		p.OnHostIPsUpdate([]net.IP{inIP, outIP})
+
+		Eventually(func() bool {
+			front.Lock()
+			defer front.Unlock()
+
+			for k := range front.m {
+				if k.Addr().String() == inIP.String() {
+					return true
+				}
+			}
+			return false
+		}).Should(BeTrue())
+
+		front.Lock()
+		defer front.Unlock()
+		for k := range front.m {
+			Expect(k.Addr().String()).NotTo(Equal(outIP.String()))
+		}
+	})
+})
+
+var _ = It("BPF kube-proxy should reject bad NodePort addresses", func() {
+	maps := new(bpfmap.IPMaps)
+	maps.FrontendMap = newMockNATMap()
+	maps.BackendMap = newMockNATBackendMap()
+	maps.AffinityMap = newMockAffinityMap()
+	maps.CtMap = mock.NewMockMap(conntrack.MapParams)
+
+	_, err := proxy.StartKubeProxy(fake.NewSimpleClientset(), "test-node", maps,
+		proxy.WithNodePortAddresses([]string{"3.3.3.0/33"}))
+	Expect(err).To(HaveOccurred())
+})
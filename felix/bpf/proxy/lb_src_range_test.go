@@ -15,6 +15,7 @@
 package proxy_test
 
 import (
+	"context"
 	"net"
 
 	"github.com/projectcalico/calico/felix/bpf/nat"
@@ -98,7 +99,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 
 		By("adding LBSourceRangeIP for existing service", makestep(func() {
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(4))
@@ -132,7 +133,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				proxy.K8sSvcWithLBSourceRangeIPs([]string{"35.0.1.2/24", "23.0.1.2/16"}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(4))
 
@@ -151,7 +152,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				proxy.K8sSvcWithLBSourceRangeIPs([]string{"35.0.1.2/24"}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(3))
 
@@ -169,7 +170,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				proxy.K8sSvcWithLBSourceRangeIPs([]string{}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(2))
 
@@ -187,7 +188,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				proxy.K8sSvcWithLBSourceRangeIPs([]string{"33.0.1.2/24", "38.0.1.2/16", "40.0.1.2/32"}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(9))
 			s.Stop()
@@ -202,7 +203,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				proxy.K8sSvcWithLBSourceRangeIPs([]string{"35.0.1.2/24"}),
 			)
 			s, _ = proxy.NewSyncer(4, nodeIPs, svcs, eps, aff, rt, nil)
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(3))
 		}))
@@ -215,7 +216,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 				externalIP,
 			)
 			s, _ = proxy.NewSyncer(4, nodeIPs, svcs, eps, aff, rt, nil)
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(svcs.m).To(HaveLen(2))
 		}))
@@ -223,7 +224,7 @@ func testfn(makeIPs func(ips []string) proxy.K8sServicePortOption) {
 		By("deleting the services", makestep(func() {
 			delete(state.SvcMap, svcKey)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(0))
@@ -0,0 +1,234 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	k8sp "k8s.io/kubernetes/pkg/proxy"
+)
+
+var gaugeServicesOverLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "felix_bpf_kube_proxy_services_over_limit",
+	Help: "Number of services that exceed one of the per-service limits.",
+}, []string{"ip_version", "limit"})
+
+var gaugeBackendsNotProgrammed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "felix_bpf_kube_proxy_backends_not_programmed",
+	Help: "Number of ready backends that are not programmed because their service exceeds a backend limit.",
+}, []string{"ip_version"})
+
+func init() {
+	prometheus.MustRegister(gaugeServicesOverLimit)
+	prometheus.MustRegister(gaugeBackendsNotProgrammed)
+}
+
+// ServiceLimits caps the number of NAT map entries that are programmed for a
+// single service so that a pathological service cannot use up the whole
+// backend map. Zero means no limit.
+//
+// When a soft limit is exceeded, the service is programmed as usual but a
+// warning is logged. When a hard limit is exceeded, only that many entries are
+// programmed. Local backends are preferred over remote ones.
+//
+// Regardless of the limits, a service never gets more backends than the NAT
+// maps can hold, see nat.BackendsLimit.
+type ServiceLimits struct {
+	// BackendsSoft and BackendsHard limit the number of backends of a service.
+	BackendsSoft int
+	BackendsHard int
+	// FrontendsSoft and FrontendsHard limit the number of frontends that are
+	// derived from a service, i.e. its load balancer IPs, external IPs and node
+	// ports.
+	FrontendsSoft int
+	FrontendsHard int
+}
+
+type serviceLimit int
+
+const (
+	limitBackendsSoft serviceLimit = iota
+	limitBackendsHard
+	limitFrontendsSoft
+	limitFrontendsHard
+	// limitBackendsMax is what the NAT maps can hold, it is not configurable.
+	limitBackendsMax
+)
+
+func (l serviceLimit) String() string {
+	switch l {
+	case limitBackendsSoft:
+		return "backends-soft"
+	case limitBackendsHard:
+		return "backends-hard"
+	case limitFrontendsSoft:
+		return "frontends-soft"
+	case limitFrontendsHard:
+		return "frontends-hard"
+	case limitBackendsMax:
+		return "backends-max"
+	}
+	return "unknown"
+}
+
+func (l serviceLimit) hard() bool {
+	return l == limitBackendsHard || l == limitFrontendsHard || l == limitBackendsMax
+}
+
+func (l serviceLimit) backends() bool {
+	return l == limitBackendsHard || l == limitBackendsMax
+}
+
+// limitMax returns the maximum of the limit, zero if there is none.
+func (s *Syncer) limitMax(l serviceLimit) int {
+	switch l {
+	case limitBackendsMax:
+		return s.natBackendsLimit
+	case limitBackendsSoft:
+		return s.limits.BackendsSoft
+	case limitBackendsHard:
+		return s.limits.BackendsHard
+	case limitFrontendsSoft:
+		return s.limits.FrontendsSoft
+	case limitFrontendsHard:
+		return s.limits.FrontendsHard
+	}
+	return 0
+}
+
+// maxBackends returns how many backends of a service are programmed: the hard
+// limit, if any, but never more than the NAT maps can hold.
+func (s *Syncer) maxBackends() int {
+	max := s.natBackendsLimit
+	if hard := s.limits.BackendsHard; hard > 0 && hard < max {
+		max = hard
+	}
+	return max
+}
+
+type svcLimitKey struct {
+	sname k8sp.ServicePortName
+	limit serviceLimit
+}
+
+// SetServiceLimits sets the per-service limits, they take effect with the next
+// Apply().
+func (s *Syncer) SetServiceLimits(limits ServiceLimits) {
+	s.mapsLck.Lock()
+	defer s.mapsLck.Unlock()
+	s.limits = limits
+}
+
+// SetEventRecorder sets the recorder of the events about services that start
+// exceeding a hard limit.
+func (s *Syncer) SetEventRecorder(recorder events.EventRecorder) {
+	s.mapsLck.Lock()
+	defer s.mapsLck.Unlock()
+	s.recorder = recorder
+}
+
+// checkLimit records that the service exceeds the limit if count is over it.
+// It returns false if the limit is exceeded.
+func (s *Syncer) checkLimit(sname k8sp.ServicePortName, l serviceLimit, count int) bool {
+	max := s.limitMax(l)
+	if max <= 0 || count <= max {
+		return true
+	}
+	k := svcLimitKey{sname: sname, limit: l}
+	if count > s.newLimitsHit[k] {
+		s.newLimitsHit[k] = count
+	}
+	return false
+}
+
+// frontendAllowed counts a new derived frontend of the service and returns
+// whether it is within the hard limit.
+func (s *Syncer) frontendAllowed(sname k8sp.ServicePortName) bool {
+	n := s.frontendCounts[sname] + 1
+	s.frontendCounts[sname] = n
+	s.checkLimit(sname, limitFrontendsSoft, n)
+	return s.checkLimit(sname, limitFrontendsHard, n)
+}
+
+// reportLimits logs the services that started or stopped exceeding a limit
+// since the last Apply() and updates the metrics. Services that start
+// exceeding a hard limit also get a warning event.
+func (s *Syncer) reportLimits() {
+	perLimit := make(map[serviceLimit]int)
+	notProgrammed := make(map[k8sp.ServicePortName]int)
+
+	for k, count := range s.newLimitsHit {
+		perLimit[k.limit]++
+		max := s.limitMax(k.limit)
+		if k.limit.backends() && count-max > notProgrammed[k.sname] {
+			notProgrammed[k.sname] = count - max
+		}
+		if _, ok := s.limitsHit[k]; ok {
+			continue
+		}
+		logCxt := log.WithFields(log.Fields{
+			"service": k.sname,
+			"limit":   k.limit,
+			"count":   count,
+			"max":     max,
+		})
+		if k.limit.hard() {
+			logCxt.Warnf("Service exceeds the hard limit, only %d entries are programmed.", max)
+			s.recordLimitEvent(k, count, max)
+		} else {
+			logCxt.Warn("Service exceeds the soft limit.")
+		}
+	}
+
+	for k := range s.limitsHit {
+		if _, ok := s.newLimitsHit[k]; !ok {
+			log.WithFields(log.Fields{
+				"service": k.sname,
+				"limit":   k.limit,
+			}).Info("Service no longer exceeds the limit.")
+		}
+	}
+
+	ipVersion := strconv.Itoa(s.ipFamily)
+	for _, l := range []serviceLimit{limitBackendsSoft, limitBackendsHard, limitFrontendsSoft, limitFrontendsHard, limitBackendsMax} {
+		gaugeServicesOverLimit.WithLabelValues(ipVersion, l.String()).Set(float64(perLimit[l]))
+	}
+	total := 0
+	for _, n := range notProgrammed {
+		total += n
+	}
+	gaugeBackendsNotProgrammed.WithLabelValues(ipVersion).Set(float64(total))
+
+	s.limitsHit = s.newLimitsHit
+	s.newLimitsHit = nil
+}
+
+func (s *Syncer) recordLimitEvent(k svcLimitKey, count, max int) {
+	if s.recorder == nil {
+		return
+	}
+	svcRef := &v1.ObjectReference{
+		Kind:      "Service",
+		Namespace: k.sname.Namespace,
+		Name:      k.sname.Name,
+	}
+	s.recorder.Eventf(svcRef, nil, v1.EventTypeWarning, "ServiceLimitExceeded", "Programming",
+		"Port %q needs %d NAT entries over the %s limit of %d, only %d are programmed.",
+		k.sname.Port, count, k.limit, max, max)
+}
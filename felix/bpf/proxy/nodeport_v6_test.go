@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sp "k8s.io/kubernetes/pkg/proxy"
+
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+var _ = Describe("BPF Syncer IPv6 NodePorts", func() {
+	var (
+		svcs  *mock.Map
+		eps   *mock.Map
+		aff   *mock.Map
+		state proxy.DPSyncerState
+	)
+
+	tcp := proxy.ProtoV1ToIntPanic(v1.ProtocolTCP)
+
+	clusterIP := net.ParseIP("fd00:96::10")
+	extIP := net.ParseIP("2001:db8:1::100")
+	nodeIPs := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("2001:db8:ffff::1"),
+		net.ParseIP("2001:db8::1"), // duplicate, e.g. the same address on two interfaces
+		net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+	}
+
+	svcKey := k8sp.ServicePortName{
+		NamespacedName: types.NamespacedName{
+			Namespace: "default",
+			Name:      "test-service",
+		},
+	}
+
+	newSyncer := func() *proxy.Syncer {
+		s, err := proxy.NewSyncer(6, nodeIPs, svcs, eps, aff, proxy.NewRTCache(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		return s
+	}
+
+	frontend := func(addr net.IP, port uint16) (nat.FrontendValue, bool) {
+		k := nat.NewNATKeyV6(addr, port, tcp)
+		v, ok := svcs.Contents[string(k[:])]
+		if !ok {
+			return nat.FrontendValue{}, false
+		}
+		return nat.FrontendValueFromBytes([]byte(v)), true
+	}
+
+	BeforeEach(func() {
+		svcs = mock.NewMockMap(nat.FrontendMapV6Parameters)
+		eps = mock.NewMockMap(nat.BackendMapV6Parameters)
+		aff = mock.NewMockMap(nat.AffinityMapV6Parameters)
+
+		state = proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{
+				svcKey: proxy.NewK8sServicePort(
+					clusterIP,
+					80,
+					v1.ProtocolTCP,
+					proxy.K8sSvcWithNodePort(30080),
+					proxy.K8sSvcWithExternalIPs([]string{extIP.String()}),
+					proxy.K8sSvcWithLBSourceRangeIPs([]string{"2001:db8:aaaa::/48", "10.0.0.0/8"}),
+				),
+			},
+			EpsMap: k8sp.EndpointsMap{
+				svcKey: []k8sp.Endpoint{&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "[fd00:10::1]:8080"}},
+			},
+		}
+	})
+
+	It("should program a frontend for every unique node address", func() {
+		s := newSyncer()
+		Expect(s.Apply(context.Background(), state)).NotTo(HaveOccurred())
+
+		cip, ok := frontend(clusterIP, 80)
+		Expect(ok).To(BeTrue())
+		Expect(cip.Count()).To(Equal(uint32(1)))
+
+		for _, nip := range []string{"2001:db8::1", "2001:db8:ffff::1", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"} {
+			np, ok := frontend(net.ParseIP(nip), 30080)
+			Expect(ok).To(BeTrue(), nip)
+			Expect(np.ID()).To(Equal(cip.ID()))
+		}
+
+		// ClusterIP, 3 node ports, external IP with one v6 source range and
+		// the blackhole entry for the rest.
+		Expect(svcs.Contents).To(HaveLen(1 + 3 + 2))
+		srcKey := nat.NewNATKeyV6Src(extIP, 80, tcp, ip.MustParseCIDROrIP("2001:db8:aaaa::/48"))
+		Expect(svcs.Contents).To(HaveKey(string(srcKey[:])))
+	})
+
+	It("should keep the service IDs after a restart", func() {
+		s := newSyncer()
+		Expect(s.Apply(context.Background(), state)).NotTo(HaveOccurred())
+		s.Stop()
+
+		before := make(map[string]string, len(svcs.Contents))
+		for k, v := range svcs.Contents {
+			before[k] = v
+		}
+		updates := svcs.UpdateCount
+		deletes := svcs.DeleteCount
+
+		s = newSyncer()
+		Expect(s.Apply(context.Background(), state)).NotTo(HaveOccurred())
+		s.Stop()
+
+		Expect(svcs.Contents).To(Equal(before))
+		Expect(svcs.UpdateCount).To(Equal(updates))
+		Expect(svcs.DeleteCount).To(Equal(deletes))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/kubernetes/pkg/proxy/healthcheck"
 
 	"github.com/projectcalico/calico/felix/ip"
 )
@@ -79,6 +80,16 @@ func WithTopologyNodeZone(nodeZone string) Option {
 	})
 }
 
+// WithHealthzServer makes the proxy report its sync state to the given
+// kube-proxy compatible health server. The server may be shared by the proxies
+// of both IP families.
+func WithHealthzServer(hs healthcheck.ProxierHealthUpdater) Option {
+	return makeOption(func(p *proxy) error {
+		p.healthzServer = hs
+		return nil
+	})
+}
+
 func WithIPFamily(ipFamily int) Option {
 	return func(p Proxy) error {
 		p.setIpFamily(ipFamily)
@@ -112,3 +123,44 @@ func WithExcludedCIDRs(cidrs []string) Option {
 		return nil
 	})
 }
+
+// WithNodePortAddresses restricts the host IPs that NodePorts are reachable on
+// to those within the given CIDRs, like the nodePortAddresses option of
+// kube-proxy.
+func WithNodePortAddresses(cidrs []string) Option {
+	return makeKubeProxyOption(func(kp *KubeProxy) error {
+		if kp.ipFamily == 0 {
+			return fmt.Errorf("ip family is not set")
+		}
+
+		kp.nodePortCIDRs = nil
+		for _, c := range cidrs {
+			cidr, err := ip.CIDRFromString(c)
+			if err != nil {
+				return fmt.Errorf("bad CIDR %s: %w", c, err)
+			}
+
+			if int(cidr.Version()) != kp.ipFamily {
+				continue
+			}
+
+			kp.nodePortCIDRs = append(kp.nodePortCIDRs, cidr)
+		}
+
+		if kp.nodePortCIDRs == nil {
+			// None of the CIDRs is of this family, so none of its IPs qualifies.
+			kp.nodePortCIDRs = []ip.CIDR{}
+		}
+
+		return nil
+	})
+}
+
+// WithServiceLimits sets the limits on the number of entries programmed per
+// service.
+func WithServiceLimits(limits ServiceLimits) Option {
+	return makeKubeProxyOption(func(kp *KubeProxy) error {
+		kp.svcLimits = limits
+		return nil
+	})
+}
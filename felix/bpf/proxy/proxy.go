@@ -18,7 +18,10 @@
 package proxy
 
 import (
+	"context"
+	"math"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -39,8 +42,11 @@ import (
 	"k8s.io/kubernetes/pkg/proxy/apis"
 	"k8s.io/kubernetes/pkg/proxy/config"
 	"k8s.io/kubernetes/pkg/proxy/healthcheck"
+	"k8s.io/kubernetes/pkg/proxy/metrics"
 	"k8s.io/kubernetes/pkg/proxy/util"
 	"k8s.io/kubernetes/pkg/util/async"
+
+	"github.com/projectcalico/calico/felix/bpf/bpflog"
 )
 
 // Proxy watches for updates of Services and Endpoints, maintains their mapping
@@ -55,22 +61,43 @@ type Proxy interface {
 type ProxyFrontend interface {
 	Proxy
 	SetSyncer(DPSyncer)
+	SetHostPorts([]HostPort)
+}
+
+// HostPort is a hostPort mapping of a local workload. It is programmed into the
+// NAT maps as a frontend with the workload as its only backend.
+type HostPort struct {
+	// Namespace and Name identify the workload the port belongs to.
+	Namespace string
+	Name      string
+
+	Protocol v1.Protocol
+	// HostIP restricts the mapping to a single host IP, it applies to all node
+	// port IPs if not set.
+	HostIP   net.IP
+	HostPort int
+
+	// IP and Port are the address of the workload.
+	IP   net.IP
+	Port int
 }
 
 // DPSyncerState groups the information passed to the DPSyncer's Apply
 type DPSyncerState struct {
-	SvcMap   k8sp.ServicePortMap
-	EpsMap   k8sp.EndpointsMap
-	NodeZone string
+	SvcMap    k8sp.ServicePortMap
+	EpsMap    k8sp.EndpointsMap
+	HostPorts []HostPort
+	NodeZone  string
 }
 
 // DPSyncer is an interface representing the dataplane syncer that applies the
 // observed changes to the dataplane
 type DPSyncer interface {
-	Apply(state DPSyncerState) error
+	Apply(ctx context.Context, state DPSyncerState) error
 	ConntrackScanStart()
 	ConntrackScanEnd()
 	ConntrackFrontendHasBackend(ip net.IP, port uint16, backendIP net.IP, backendPort uint16, proto uint8) bool
+	ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool)
 	Stop()
 	SetTriggerFn(func())
 }
@@ -89,6 +116,9 @@ type proxy struct {
 	svcMap k8sp.ServicePortMap
 	epsMap k8sp.EndpointsMap
 
+	hostPorts    []HostPort
+	hostPortsLck sync.Mutex
+
 	dpSyncer  DPSyncer
 	syncerLck sync.Mutex
 	// executes periodic the dataplane updates
@@ -164,7 +194,10 @@ func New(k8s kubernetes.Interface, dp DPSyncer, hostname string, opts ...Option)
 	if p.ipFamily != 4 {
 		ipVersion = v1.IPv6Protocol
 	}
-	p.healthzServer = healthcheck.NewProxierHealthServer("0.0.0.0:10256", p.minDPSyncPeriod, p.recorder, nodeRef)
+	if p.healthzServer == nil {
+		// Not served, but still needed by the service health server.
+		p.healthzServer = healthcheck.NewProxierHealthServer("0.0.0.0:10256", p.minDPSyncPeriod, p.recorder, nodeRef)
+	}
 	p.svcHealthServer = healthcheck.NewServiceHealthServer(p.hostname, p.recorder, util.NewNodePortAddresses(ipVersion, []string{"0.0.0.0/0"}), p.healthzServer)
 
 	p.epsChanges = k8sp.NewEndpointChangeTracker(p.hostname,
@@ -219,14 +252,14 @@ func (p *proxy) setIpFamily(ipFamily int) {
 
 func (p *proxy) Stop() {
 	p.stopOnce.Do(func() {
-		log.Info("Proxy stopping")
+		bpflog.WithIPFamily(p.ipFamily).Info("Proxy stopping")
 		// Pass empty update to close all the health checks.
 		_ = p.svcHealthServer.SyncServices(map[types.NamespacedName]uint16{})
 		_ = p.svcHealthServer.SyncEndpoints(map[types.NamespacedName]int{})
 		p.dpSyncer.Stop()
 		close(p.stopCh)
 		p.stopWg.Wait()
-		log.Info("Proxy stopped")
+		bpflog.WithIPFamily(p.ipFamily).Info("Proxy stopped")
 	})
 }
 
@@ -239,6 +272,8 @@ func (p *proxy) startRoutine(f func()) {
 }
 
 func (p *proxy) syncDP() {
+	p.healthzServer.QueuedUpdate()
+	metrics.SyncProxyRulesLastQueuedTimestamp.SetToCurrentTime()
 	p.runner.Run()
 }
 
@@ -254,33 +289,47 @@ func (p *proxy) invokeDPSyncer() {
 	p.runnerLck.Lock()
 	defer p.runnerLck.Unlock()
 
+	start := time.Now()
+
 	_ = p.svcMap.Update(p.svcChanges)
-	_ = p.epsMap.Update(p.epsChanges)
+	epsUpdate := p.epsMap.Update(p.epsChanges)
 
 	if err := p.svcHealthServer.SyncServices(p.svcMap.HealthCheckNodePorts()); err != nil {
-		log.WithError(err).Error("Error syncing healthcheck services")
+		bpflog.WithIPFamily(p.ipFamily).WithError(err).Error("Error syncing healthcheck services")
 	}
 	if err := p.svcHealthServer.SyncEndpoints(p.epsMap.LocalReadyEndpoints()); err != nil {
-		log.WithError(err).Error("Error syncing healthcheck endpoints")
+		bpflog.WithIPFamily(p.ipFamily).WithError(err).Error("Error syncing healthcheck endpoints")
 	}
 
+	p.hostPortsLck.Lock()
+	hostPorts := p.hostPorts
+	p.hostPortsLck.Unlock()
+
 	p.syncerLck.Lock()
-	err := p.dpSyncer.Apply(DPSyncerState{
-		SvcMap:   p.svcMap,
-		EpsMap:   p.epsMap,
-		NodeZone: p.nodeZone,
+	err := p.dpSyncer.Apply(context.Background(), DPSyncerState{
+		SvcMap:    p.svcMap,
+		EpsMap:    p.epsMap,
+		HostPorts: hostPorts,
+		NodeZone:  p.nodeZone,
 	})
 	p.syncerLck.Unlock()
 
 	if err != nil {
-		log.WithError(err).Errorf("applying changes failed")
+		bpflog.WithIPFamily(p.ipFamily).WithError(err).Errorf("applying changes failed")
 		// TODO log the error or panic as the best might be to restart
 		// completely to wipe out the loaded bpf maps
+		return
 	}
 
-	if p.healthzServer != nil {
-		p.healthzServer.Updated()
+	metrics.SyncProxyRulesLatency.Observe(metrics.SinceInSeconds(start))
+	metrics.SyncProxyRulesLastTimestamp.SetToCurrentTime()
+	for _, triggerTimes := range epsUpdate.LastChangeTriggerTimes {
+		for _, t := range triggerTimes {
+			metrics.NetworkProgrammingLatency.Observe(metrics.SinceInSeconds(t))
+		}
 	}
+
+	p.healthzServer.Updated()
 }
 
 func (p *proxy) OnServiceAdd(svc *v1.Service) {
@@ -334,6 +383,16 @@ func (p *proxy) SetSyncer(s DPSyncer) {
 	p.forceSyncDP()
 }
 
+// SetHostPorts replaces the set of hostPort mappings of local workloads and
+// schedules a dataplane update.
+func (p *proxy) SetHostPorts(hps []HostPort) {
+	p.hostPortsLck.Lock()
+	p.hostPorts = hps
+	p.hostPortsLck.Unlock()
+
+	p.syncDP()
+}
+
 type initState struct {
 	lck        sync.RWMutex
 	svcsSynced bool
@@ -368,16 +427,45 @@ const (
 	ReapTerminatingUDPImmediatelly = "TerminatingImmediately"
 
 	ExcludeServiceAnnotation = "projectcalico.org/natExcludeService"
+
+	// ProxyProtocolAnnotation makes the BPF NAT prepend a PROXY protocol
+	// header to the TCP connections that it forwards to the backends of the
+	// service, so that they learn the address of the client even when the
+	// connection is SNATed. Only ProxyProtocolV2 is supported.
+	ProxyProtocolAnnotation = "projectcalico.org/proxyProtocol"
+	ProxyProtocolV2         = "v2"
+
+	// SuspendAffinityCleanupAnnotation holds an RFC 3339 timestamp until which
+	// session affinity entries of the service are not removed when their
+	// backend goes away, e.g. during a failover of a stateful backend. The
+	// entries still expire according to the service's affinity timeout.
+	SuspendAffinityCleanupAnnotation = "projectcalico.org/suspendAffinityCleanupUntil"
+
+	// AffinityTimeoutAnnotation overrides the session affinity timeout of a
+	// service with ClientIP affinity with a number of seconds that may exceed
+	// the maximum of 86400 that Kubernetes allows in
+	// sessionAffinityConfig.clientIP.timeoutSeconds. It is only honoured by
+	// the BPF dataplane and it is ignored for services without ClientIP
+	// affinity. The value must be a positive integer that is not larger than
+	// MaxAffinityTimeoutSeconds, the largest timeout the BPF NAT can expire.
+	AffinityTimeoutAnnotation = "projectcalico.org/affinityTimeoutSeconds"
+	MaxAffinityTimeoutSeconds = math.MaxInt32
 )
 
 type ServiceAnnotations interface {
 	ReapTerminatingUDP() bool
 	ExcludeService() bool
+	ProxyProtocol() bool
+	AffinityCleanupSuspendedUntil() time.Time
+	AffinityTimeoutSeconds() int
 }
 
 type servicePortAnnotations struct {
-	reapTerminatingUDP bool
-	excludeService     bool
+	reapTerminatingUDP            bool
+	excludeService                bool
+	proxyProtocol                 bool
+	affinityCleanupSuspendedUntil time.Time
+	affinityTimeoutSeconds        int
 }
 
 func (s *servicePortAnnotations) ReapTerminatingUDP() bool {
@@ -388,6 +476,20 @@ func (s *servicePortAnnotations) ExcludeService() bool {
 	return s.excludeService
 }
 
+func (s *servicePortAnnotations) ProxyProtocol() bool {
+	return s.proxyProtocol
+}
+
+func (s *servicePortAnnotations) AffinityCleanupSuspendedUntil() time.Time {
+	return s.affinityCleanupSuspendedUntil
+}
+
+// AffinityTimeoutSeconds returns the affinity timeout set by the
+// AffinityTimeoutAnnotation or 0 if the annotation is not set.
+func (s *servicePortAnnotations) AffinityTimeoutSeconds() int {
+	return s.affinityTimeoutSeconds
+}
+
 type servicePort struct {
 	k8sp.ServicePort
 	servicePortAnnotations
@@ -398,11 +500,48 @@ func makeServiceInfo(_ *v1.ServicePort, s *v1.Service, baseSvc *k8sp.BaseService
 		ServicePort: baseSvc,
 	}
 
+	if baseSvc.SessionAffinityType() == v1.ServiceAffinityClientIP {
+		if v, ok := s.ObjectMeta.Annotations[SuspendAffinityCleanupAnnotation]; ok {
+			if until, err := time.Parse(time.RFC3339, v); err == nil {
+				svc.affinityCleanupSuspendedUntil = until
+			} else {
+				log.WithError(err).WithFields(log.Fields{
+					bpflog.FieldService: s.Namespace + "/" + s.Name,
+					"annotation":        SuspendAffinityCleanupAnnotation,
+				}).Warn("Ignoring malformed annotation, expected an RFC 3339 timestamp.")
+			}
+		}
+		if v, ok := s.ObjectMeta.Annotations[AffinityTimeoutAnnotation]; ok {
+			if timeo, err := strconv.Atoi(v); err == nil && timeo > 0 && timeo <= MaxAffinityTimeoutSeconds {
+				svc.affinityTimeoutSeconds = timeo
+			} else {
+				log.WithFields(log.Fields{
+					bpflog.FieldService: s.Namespace + "/" + s.Name,
+					"annotation":        AffinityTimeoutAnnotation,
+					"value":             v,
+				}).Warnf("Ignoring malformed annotation, expected a number of seconds between 1 and %d.",
+					MaxAffinityTimeoutSeconds)
+			}
+		}
+	}
+
 	if v, ok := s.ObjectMeta.Annotations[ExcludeServiceAnnotation]; ok && v == "true" {
 		svc.excludeService = true
 		goto out
 	}
 
+	if v, ok := s.ObjectMeta.Annotations[ProxyProtocolAnnotation]; ok {
+		if baseSvc.Protocol() == v1.ProtocolTCP && strings.EqualFold(v, ProxyProtocolV2) {
+			svc.proxyProtocol = true
+		} else {
+			log.WithFields(log.Fields{
+				bpflog.FieldService: s.Namespace + "/" + s.Name,
+				"port":              baseSvc.Port(),
+				"annotation":        ProxyProtocolAnnotation,
+			}).Warn("Ignoring annotation, only \"v2\" is supported and only for TCP ports.")
+		}
+	}
+
 	if baseSvc.Protocol() == v1.ProtocolUDP {
 		if v, ok := s.ObjectMeta.Annotations[ReapTerminatingUDPAnnotation]; ok && strings.EqualFold(v, ReapTerminatingUDPImmediatelly) {
 			svc.reapTerminatingUDP = true
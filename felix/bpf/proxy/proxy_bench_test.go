@@ -18,6 +18,7 @@
 package proxy_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -162,8 +163,8 @@ type benchSyncer struct {
 	syncC chan struct{}
 }
 
-func (s *benchSyncer) Apply(state proxy.DPSyncerState) error {
-	err := s.DPSyncer.Apply(state)
+func (s *benchSyncer) Apply(_ context.Context, state proxy.DPSyncerState) error {
+	err := s.DPSyncer.Apply(context.Background(), state)
 	s.syncC <- struct{}{}
 	return err
 }
@@ -15,6 +15,7 @@
 package proxy_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"runtime"
@@ -632,7 +633,7 @@ func newMockSyncer(stop chan struct{}) *mockSyncer {
 
 func (s *mockSyncer) Stop() {}
 
-func (s *mockSyncer) Apply(state proxy.DPSyncerState) error {
+func (s *mockSyncer) Apply(_ context.Context, state proxy.DPSyncerState) error {
 	log("SvcMap = %+v\n", state.SvcMap)
 	log("EpsMap = %+v\n", state.EpsMap)
 	select {
@@ -651,6 +652,9 @@ func (*syncerConntrackAPIDummy) ConntrackFrontendHasBackend(ip net.IP, port uint
 	backendPort uint16, proto uint8) bool {
 	return false
 }
+func (*syncerConntrackAPIDummy) ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool) {
+	return "", false
+}
 
 func (s *mockSyncer) checkState(f func(proxy.DPSyncerState)) {
 	tickC := time.After(10 * time.Second)
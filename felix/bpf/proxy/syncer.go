@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,11 +28,14 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	k8sp "k8s.io/kubernetes/pkg/proxy"
 
 	"github.com/projectcalico/calico/felix/cachingmap"
 
 	"github.com/projectcalico/calico/felix/bpf"
+	"github.com/projectcalico/calico/felix/bpf/bpflog"
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/nat"
 	"github.com/projectcalico/calico/felix/bpf/routes"
@@ -83,6 +87,7 @@ const (
 	svcTypeNodePort
 	svcTypeNodePortRemote
 	svcTypeLoadBalancer
+	svcTypeHostPort
 )
 
 var svcType2String = map[svcType]string{
@@ -90,6 +95,7 @@ var svcType2String = map[svcType]string{
 	svcTypeExternalIP:     "ExternalIP",
 	svcTypeNodePortRemote: "NodePortRemote",
 	svcTypeLoadBalancer:   "LoadBalancer",
+	svcTypeHostPort:       "HostPort",
 }
 
 func getSvcKeyExtra(t svcType, ip string) string {
@@ -103,12 +109,16 @@ func hasSvcKeyExtra(skey svcKey, t svcType) bool {
 func isSvcKeyDerived(skey svcKey) bool {
 	return hasSvcKeyExtra(skey, svcTypeExternalIP) ||
 		hasSvcKeyExtra(skey, svcTypeNodePort) ||
-		hasSvcKeyExtra(skey, svcTypeLoadBalancer)
+		hasSvcKeyExtra(skey, svcTypeLoadBalancer) ||
+		hasSvcKeyExtra(skey, svcTypeHostPort)
 }
 
 type stickyFrontend struct {
 	id    uint32
 	timeo time.Duration
+	// cleanupSuspendedUntil is when the suspension of the cleanup of
+	// affinity entries for removed backends ends, if at all.
+	cleanupSuspendedUntil time.Time
 }
 
 // Syncer is an implementation of DPSyncer interface. It is not thread safe and
@@ -133,8 +143,9 @@ type Syncer struct {
 	prevSvcMap map[svcKey]svcInfo
 	prevEpsMap k8sp.EndpointsMap
 	// active Maps contain all active svcs endpoints at the end of an iteration
-	activeSvcsMap map[ipPortProto]uint32
-	activeEpsMap  map[uint32]map[ipPort]struct{}
+	activeSvcsMap  map[ipPortProto]uint32
+	activeEpsMap   map[uint32]map[ipPort]struct{}
+	activeSvcNames map[uint32]string
 
 	// Protects accessing the [prev|new][Svc|Eps]Map,
 	mapsLck sync.Mutex
@@ -148,6 +159,11 @@ type Syncer struct {
 	stop     chan struct{}
 	stopOnce sync.Once
 
+	// applyCancel cancels the context of the in-flight Apply() so that Stop()
+	// does not wait for it.
+	applyCancelLck sync.Mutex
+	applyCancel    context.CancelFunc
+
 	stickySvcs map[nat.FrontEndAffinityKeyInterface]stickyFrontend
 	stickyEps  map[uint32]map[nat.BackendValueInterface]struct{}
 
@@ -162,6 +178,19 @@ type Syncer struct {
 	affinityValueFromBytes func([]byte) nat.AffinityValueInterface
 
 	excludedCIDRs *ip.CIDRTrie
+
+	limits ServiceLimits
+	// frontendCounts counts the derived frontends of each service during an
+	// Apply().
+	frontendCounts map[k8sp.ServicePortName]int
+	// limitsHit are the services exceeding a limit with the number of entries
+	// they would need, newLimitsHit is valid during the Apply()'s runtime.
+	limitsHit    map[svcLimitKey]int
+	newLimitsHit map[svcLimitKey]int
+	// natBackendsLimit is the most backends a service can have given the size
+	// of the NAT maps, it is refreshed by each Apply().
+	natBackendsLimit int
+	recorder         events.EventRecorder
 }
 
 type ipPort struct {
@@ -186,25 +215,18 @@ func servicePortToIPPortProto(sp k8sp.ServicePort) ipPortProto {
 	}
 }
 
+// uniqueIPs removes duplicate IPs while keeping the order of the first
+// occurrences so that the result is stable for the same input.
 func uniqueIPs(ips []net.IP) []net.IP {
-	m := make(map[string]net.IP)
-	unique := true
+	m := make(map[string]struct{})
+	ret := make([]net.IP, 0, len(ips))
 
 	for _, ip := range ips {
 		s := ip.String()
 		if _, ok := m[s]; ok {
-			unique = false
-		} else {
-			m[s] = ip
+			continue
 		}
-	}
-
-	if unique {
-		return ips
-	}
-
-	ret := make([]net.IP, 0, len(m))
-	for _, ip := range m {
+		m[s] = struct{}{}
 		ret = append(ret, ip)
 	}
 
@@ -364,7 +386,8 @@ func (s *Syncer) startupBuildPrev(state DPSyncerState) error {
 			epk := nat.NewNATBackendKey(id, uint32(i))
 			ep, ok := s.bpfEps.Dataplane().Get(epk)
 			if !ok {
-				log.Warnf("inconsistent backed map, missing ep %s", epk)
+				s.svcLogCxt(*svckey, id).WithField(bpflog.FieldMap, s.bpfEps.Name()).
+					Warnf("inconsistent backed map, missing ep %s", epk)
 				inconsistent = true
 				break
 			}
@@ -384,6 +407,16 @@ func (s *Syncer) startupBuildPrev(state DPSyncerState) error {
 	return nil
 }
 
+// logCxt returns a log entry with the IP family of the syncer.
+func (s *Syncer) logCxt() *log.Entry {
+	return bpflog.WithIPFamily(s.ipFamily)
+}
+
+// svcLogCxt returns a log entry with the fields of a service in the NAT maps.
+func (s *Syncer) svcLogCxt(skey svcKey, id uint32) *log.Entry {
+	return bpflog.WithService(s.ipFamily, skey.String(), id, s.bpfSvcs.Name())
+}
+
 func (s *Syncer) startupSync(state DPSyncerState) error {
 	// Load current dataplane state.
 	if err := s.loadOrigs(); err != nil {
@@ -394,13 +427,13 @@ func (s *Syncer) startupSync(state DPSyncerState) error {
 	// Once we have the previous map, we can apply the current state as if we never
 	// restarted and apply only the diff using the regular code path.
 	if err := s.startupBuildPrev(state); err != nil {
-		log.WithError(err).Error("Failed to load previous state of NAT maps from dataplane, " +
+		s.logCxt().WithError(err).Error("Failed to load previous state of NAT maps from dataplane, " +
 			"maps will get disruptively rewritten")
 	}
 	return nil
 }
 
-func (s *Syncer) applySvc(skey svcKey, sinfo Service, eps []k8sp.Endpoint) error {
+func (s *Syncer) applySvc(ctx context.Context, skey svcKey, sinfo Service, eps []k8sp.Endpoint) error {
 	var id uint32
 
 	old, exists := s.prevSvcMap[skey]
@@ -409,7 +442,7 @@ func (s *Syncer) applySvc(skey svcKey, sinfo Service, eps []k8sp.Endpoint) error
 	} else {
 		id = s.newSvcID()
 	}
-	count, local, err := s.updateService(skey, sinfo, id, eps)
+	count, local, err := s.updateService(ctx, skey, sinfo, id, eps)
 	if err != nil {
 		return err
 	}
@@ -422,7 +455,7 @@ func (s *Syncer) applySvc(skey svcKey, sinfo Service, eps []k8sp.Endpoint) error
 	}
 
 	if log.GetLevel() >= log.DebugLevel {
-		log.Debugf("applied a service %s update: sinfo=%+v", skey, s.newSvcMap[skey])
+		s.svcLogCxt(skey, id).Debugf("applied a service update: sinfo=%+v", s.newSvcMap[skey])
 	}
 
 	return nil
@@ -451,14 +484,14 @@ func (s *Syncer) addActiveEps(id uint32, svc Service, eps []k8sp.Endpoint) {
 	}
 }
 
-func (s *Syncer) applyExpandedNP(sname k8sp.ServicePortName, sinfo k8sp.ServicePort,
+func (s *Syncer) applyExpandedNP(ctx context.Context, sname k8sp.ServicePortName, sinfo k8sp.ServicePort,
 	eps []k8sp.Endpoint, node ip.Addr, nport int) error {
 	skey := getSvcKey(sname, getSvcKeyExtra(svcTypeNodePortRemote, node.String()))
 	si := serviceInfoFromK8sServicePort(sinfo)
 	si.clusterIP = node.AsNetIP()
 	si.port = nport
 
-	if err := s.applySvc(skey, si, eps); err != nil {
+	if err := s.applySvc(ctx, skey, si, eps); err != nil {
 		return errors.Errorf("apply NodePortRemote for %s node %s", sname, node)
 	}
 
@@ -472,14 +505,27 @@ type expandMiss struct {
 	nport int
 }
 
-func (s *Syncer) expandAndApplyNodePorts(sname k8sp.ServicePortName, sinfo k8sp.ServicePort,
+func (s *Syncer) expandAndApplyNodePorts(ctx context.Context, sname k8sp.ServicePortName, sinfo k8sp.ServicePort,
 	eps []k8sp.Endpoint, nport int, rtLookup func(addr ip.Addr) (routes.ValueInterface, bool)) *expandMiss {
 
 	ipToEp, miss := s.expandNodePorts(sname, sinfo, eps, nport, rtLookup)
 
-	for node, neps := range ipToEp {
-		if err := s.applyExpandedNP(sname, sinfo, neps, node, nport); err != nil {
-			log.WithField("error", err).Errorf("Failed to expand NodePort")
+	// Expand the nodes in a stable order so that they get the same service IDs
+	// whatever the map iteration order.
+	nodes := make([]ip.Addr, 0, len(ipToEp))
+	for node := range ipToEp {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].String() < nodes[j].String()
+	})
+
+	for _, node := range nodes {
+		if !s.frontendAllowed(sname) {
+			continue
+		}
+		if err := s.applyExpandedNP(ctx, sname, sinfo, ipToEp[node], node, nport); err != nil {
+			s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).Error("Failed to expand NodePort")
 		}
 	}
 
@@ -500,7 +546,7 @@ func (s *Syncer) expandNodePorts(
 
 		rt, ok := rtLookup(ipa)
 		if !ok {
-			log.Errorf("No route for %s", ipa)
+			s.logCxt().WithField(bpflog.FieldService, sname.String()).Errorf("No route for %s", ipa)
 			if miss == nil {
 				miss = &expandMiss{
 					sname: sname,
@@ -562,27 +608,85 @@ func (s *Syncer) applyDerived(
 		svc:        sinfo,
 	}
 
-	if err := s.writeSvc(sinfo, svc.id, count, local, flags); err != nil {
+	if err := s.writeSvc(skey, sinfo, svc.id, count, local, flags); err != nil {
 		return err
 	}
 	if svcTypeLoadBalancer == t || svcTypeExternalIP == t {
-		err := s.writeLBSrcRangeSvcNATKeys(sinfo, svc.id, count, local, flags)
+		err := s.writeLBSrcRangeSvcNATKeys(skey, sinfo, svc.id, count, local, flags)
 		if err != nil {
-			log.Debug("Failed to write LB source range NAT keys")
+			s.svcLogCxt(skey, svc.id).WithError(err).Debug("Failed to write LB source range NAT keys")
 		}
 	}
 
 	s.newSvcMap[skey] = newInfo
 	if log.GetLevel() >= log.DebugLevel {
-		log.Debugf("applied a derived service %s update: sinfo=%+v", skey, s.newSvcMap[skey])
+		s.svcLogCxt(skey, svc.id).Debugf("applied a derived service update: sinfo=%+v", s.newSvcMap[skey])
 	}
 
 	return nil
 }
 
-func (s *Syncer) apply(state DPSyncerState) error {
-	log.Infof("Applying new state, %d service", len(state.SvcMap))
-	log.Debugf("Applying new state, %v", state)
+// hostPortSvcName makes up a service name for a hostPort mapping so that it
+// can be handled like any other service. It cannot clash with a real service
+// as those do not allow ':' in the port name.
+func hostPortSvcName(hp HostPort) k8sp.ServicePortName {
+	return k8sp.ServicePortName{
+		NamespacedName: types.NamespacedName{
+			Namespace: hp.Namespace,
+			Name:      hp.Name,
+		},
+		Port:     fmt.Sprintf("hostport:%d", hp.HostPort),
+		Protocol: hp.Protocol,
+	}
+}
+
+// applyHostPorts programs hostPort mappings of local workloads. Each mapping is
+// a service with the workload as its only backend, the first frontend IP is
+// the primary service and any other frontend IPs are derived from it.
+func (s *Syncer) applyHostPorts(ctx context.Context, hps []HostPort) {
+	hps = append([]HostPort(nil), hps...)
+	sort.SliceStable(hps, func(i, j int) bool {
+		return hostPortSvcName(hps[i]).String() < hostPortSvcName(hps[j]).String()
+	})
+
+	for _, hp := range hps {
+		sname := hostPortSvcName(hp)
+
+		frontendIPs := s.nodePortIPs
+		if hp.HostIP != nil && !hp.HostIP.IsUnspecified() {
+			frontendIPs = []net.IP{hp.HostIP}
+		}
+		if len(frontendIPs) == 0 {
+			continue
+		}
+
+		eps := []k8sp.Endpoint{&k8sp.BaseEndpointInfo{
+			Endpoint: net.JoinHostPort(hp.IP.String(), strconv.Itoa(hp.Port)),
+			IsLocal:  true,
+			Ready:    true,
+			Serving:  true,
+		}}
+
+		svc := NewK8sServicePort(frontendIPs[0], hp.HostPort, hp.Protocol).(Service)
+		if err := s.applySvc(ctx, getSvcKey(sname, ""), svc, eps); err != nil {
+			s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).Error("failed to apply hostPort")
+			continue
+		}
+
+		for _, fip := range frontendIPs[1:] {
+			hpInfo := serviceInfoFromK8sServicePort(svc)
+			hpInfo.clusterIP = fip
+			if err := s.applyDerived(sname, svcTypeHostPort, hpInfo); err != nil {
+				s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).
+					Errorf("failed to apply hostPort on %s", fip)
+			}
+		}
+	}
+}
+
+func (s *Syncer) apply(ctx context.Context, state DPSyncerState) (err error) {
+	s.logCxt().Infof("Applying new state, %d service", len(state.SvcMap))
+	s.logCxt().Debugf("Applying new state, %v", state)
 
 	// we need to copy the maps from the new state to compute the diff in the
 	// next call. We cannot keep the provided maps as the generic k8s proxy code
@@ -590,6 +694,9 @@ func (s *Syncer) apply(state DPSyncerState) error {
 	// here and now.
 	s.newSvcMap = make(map[svcKey]svcInfo, len(state.SvcMap))
 	s.newEpsMap = make(k8sp.EndpointsMap, len(state.EpsMap))
+	s.frontendCounts = make(map[k8sp.ServicePortName]int)
+	s.newLimitsHit = make(map[svcLimitKey]int)
+	s.natBackendsLimit = nat.BackendsLimit(s.ipFamily)
 	nodeZone := state.NodeZone
 
 	var expNPMisses []*expandMiss
@@ -599,15 +706,31 @@ func (s *Syncer) apply(state DPSyncerState) error {
 	s.bpfSvcs.Desired().DeleteAll()
 	s.bpfEps.Desired().DeleteAll()
 
-	// insert or update existing services
-	for sname, sinfo := range state.SvcMap {
-		svc := sinfo.(Service)
+	// If the context is done before we write the maps, go back to the state of
+	// the last Apply, which is what the maps still hold.
+	writing := false
+	defer func() {
+		if err != nil && !writing && ctx.Err() != nil {
+			s.abandonApply()
+		}
+	}()
+
+	// insert or update existing services, in a stable order so that the new
+	// services get the same IDs, and so the same writes, whatever the map
+	// iteration order.
+	for _, sname := range sortedServicePortNames(state.SvcMap) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		svc := state.SvcMap[sname].(Service)
 		hintsAnnotation := svc.HintsAnnotation()
 
-		log.WithField("service", sname).Debug("Applying service")
+		s.logCxt().WithField(bpflog.FieldService, sname.String()).Debug("Applying service")
 		skey := getSvcKey(sname, "")
 
 		eps := make([]k8sp.Endpoint, 0, len(state.EpsMap[sname]))
+		// The order of the endpoints determines the ordinals of the backends, the
+		// endpoint slice cache of the k8s proxy sorts them.
 		for _, ep := range state.EpsMap[sname] {
 			zoneHints := ep.GetZoneHints()
 			if ep.IsReady() || ep.IsTerminating() {
@@ -623,18 +746,22 @@ func (s *Syncer) apply(state DPSyncerState) error {
 			}
 		}
 
-		err := s.applySvc(skey, svc, eps)
+		err := s.applySvc(ctx, skey, svc, eps)
 		if err != nil {
 			return err
 		}
 
 		for _, lbIP := range svc.LoadBalancerIPStrings() {
 			if lbIP != "" {
+				if !s.frontendAllowed(sname) {
+					continue
+				}
 				extInfo := serviceInfoFromK8sServicePort(svc)
 				extInfo.clusterIP = net.ParseIP(lbIP)
 				err := s.applyDerived(sname, svcTypeLoadBalancer, extInfo)
 				if err != nil {
-					log.Errorf("failed to apply LoadBalancer IP %s for service %s : %s", lbIP, sname, err)
+					s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).
+						Errorf("failed to apply LoadBalancer IP %s", lbIP)
 					continue
 				}
 				log.Debugf("LB status IP %s", lbIP)
@@ -642,11 +769,15 @@ func (s *Syncer) apply(state DPSyncerState) error {
 		}
 		// N.B. we assume that k8s provide us with no duplicities
 		for _, extIP := range svc.ExternalIPStrings() {
+			if !s.frontendAllowed(sname) {
+				continue
+			}
 			extInfo := serviceInfoFromK8sServicePort(svc)
 			extInfo.clusterIP = net.ParseIP(extIP)
 			err := s.applyDerived(sname, svcTypeExternalIP, extInfo)
 			if err != nil {
-				log.Errorf("failed to apply ExternalIP %s for service %s : %s", extIP, sname, err)
+				s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).
+					Errorf("failed to apply ExternalIP %s", extIP)
 				continue
 			}
 		}
@@ -662,56 +793,126 @@ func (s *Syncer) apply(state DPSyncerState) error {
 					// separately
 					continue
 				}
+				if !s.frontendAllowed(sname) {
+					continue
+				}
 				err := s.applyDerived(sname, svcTypeNodePort, npInfo)
 				if err != nil {
-					log.Errorf("failed to apply NodePort %s for service %s : %s", npip, sname, err)
+					s.logCxt().WithField(bpflog.FieldService, sname.String()).WithError(err).
+						Errorf("failed to apply NodePort %s", npip)
 					continue
 				}
 			}
 			if svc.InternalPolicyLocal() {
-				if miss := s.expandAndApplyNodePorts(sname, svc, eps, nport, s.rt.Lookup); miss != nil {
+				if miss := s.expandAndApplyNodePorts(ctx, sname, svc, eps, nport, s.rt.Lookup); miss != nil {
 					expNPMisses = append(expNPMisses, miss)
 				}
 			}
 		}
 	}
 
+	s.applyHostPorts(ctx, state.HostPorts)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// From here on, a cancellation leaves the writes that are not done pending
+	// in the caching maps, for the next Apply to complete.
+	writing = true
+
 	// Delete any front-ends first so the backends become unreachable.
-	err := s.bpfSvcs.ApplyDeletionsOnly()
+	err = s.bpfSvcs.ApplyDeletionsOnly(ctx)
 	if err != nil {
 		return err
 	}
 	// Update the backend maps so that any new backends become available before we update the frontends to use them.
-	err = s.bpfEps.ApplyUpdatesOnly()
+	err = s.bpfEps.ApplyUpdatesOnly(ctx)
 	if err != nil {
 		return err
 	}
 	// Update the frontends, after this is done we should be handling packets correctly.
-	err = s.bpfSvcs.ApplyUpdatesOnly()
+	err = s.bpfSvcs.ApplyUpdatesOnly(ctx)
 	if err != nil {
 		return err
 	}
 	// Remove any unused backends.
-	err = s.bpfEps.ApplyDeletionsOnly()
+	err = s.bpfEps.ApplyDeletionsOnly(ctx)
 	if err != nil {
 		return err
 	}
 
-	log.Info("new state written")
+	s.logCxt().Info("new state written")
+
+	s.reportLimits()
 
 	s.runExpandNPFixup(expNPMisses)
 
 	return nil
 }
 
-// Apply applies the new state
-func (s *Syncer) Apply(state DPSyncerState) error {
+// abandonApply goes back to the state of the last Apply when an Apply is
+// cancelled before writing the maps, so that the conntrack scan and the next
+// Apply do not see a partial state.
+func (s *Syncer) abandonApply() {
+	s.logCxt().Info("Apply cancelled, keeping the previous state")
+	s.newSvcMap = s.prevSvcMap
+	s.newEpsMap = s.prevEpsMap
+	resetDesired(s.bpfSvcs)
+	resetDesired(s.bpfEps)
+}
+
+// resetDesired makes the desired state of the map what is in the dataplane.
+func resetDesired[K comparable, V comparable](m *cachingmap.CachingMap[K, V]) {
+	m.Desired().DeleteAll()
+	m.Dataplane().Iter(func(k K, v V) {
+		m.Desired().Set(k, v)
+	})
+}
+
+func sortedServicePortNames(svcs k8sp.ServicePortMap) []k8sp.ServicePortName {
+	names := make([]k8sp.ServicePortName, 0, len(svcs))
+	for sname := range svcs {
+		names = append(names, sname)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+	return names
+}
+
+// DesiredNATState returns the entries of the NAT frontend and backend maps as
+// programmed by the last Apply, one per line, sorted.  It does not depend on
+// the order of the map writes and so can be compared with a golden file.
+func (s *Syncer) DesiredNATState() []string {
+	s.mapsLck.Lock()
+	defer s.mapsLck.Unlock()
+
+	var lines []string
+	s.bpfSvcs.Desired().Iter(func(k nat.FrontendKeyInterface, v nat.FrontendValue) {
+		lines = append(lines, fmt.Sprintf("%v -> %v", k, v))
+	})
+	s.bpfEps.Desired().Iter(func(k nat.BackendKey, v nat.BackendValueInterface) {
+		lines = append(lines, fmt.Sprintf("%v -> %v", k, v))
+	})
+	sort.Strings(lines)
+	return lines
+}
+
+// Apply applies the new state.  It stops early if the context is done or the
+// syncer is stopped and returns the context's error; the next Apply then
+// brings the dataplane in sync.
+func (s *Syncer) Apply(ctx context.Context, state DPSyncerState) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.setApplyCancel(cancel)
+	defer s.setApplyCancel(nil)
+
 	if !s.synced {
-		log.Infof("Loading BPF map state from dataplane")
+		s.logCxt().Info("Loading BPF map state from dataplane")
 		if err := s.startupSync(state); err != nil {
 			return errors.WithMessage(err, "startup sync")
 		}
-		log.Infof("Loaded BPF map state from dataplane")
+		s.logCxt().Info("Loaded BPF map state from dataplane")
 		s.mapsLck.Lock()
 	} else {
 		// if we were not synced yet, the fixer cannot run yet
@@ -734,7 +935,7 @@ func (s *Syncer) Apply(state DPSyncerState) error {
 		s.stickyEps = nil
 	}()
 
-	if err := s.apply(state); err != nil {
+	if err := s.apply(ctx, state); err != nil {
 		// dont bother to cleanup affinity since we do not know in what state we
 		// are anyway. Will get resolved once we get in a good state
 		return err
@@ -750,11 +951,20 @@ func (s *Syncer) Apply(state DPSyncerState) error {
 	return s.cleanupSticky()
 }
 
-func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp.Endpoint) (int, int, error) {
+func (s *Syncer) updateService(ctx context.Context, skey svcKey, sinfo Service, id uint32, eps []k8sp.Endpoint) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
 	cpEps := make([]k8sp.Endpoint, 0, len(eps))
 
 	cnt := 0
 	local := 0
+	// ready counts all the ready backends, including those over the limits
+	// that are not programmed. The endpoints come sorted, so the same backends
+	// are programmed as long as the service does not change.
+	ready := 0
+	maxBackends := s.maxBackends()
 
 	if sinfo.SessionAffinityType() == v1.ServiceAffinityClientIP {
 		// since we write the backend before we write the frontend, we need to
@@ -769,11 +979,14 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 
 		// eps could contain Ready and Terminating pods but only write Ready pods to backend.
 		if ep.IsReady() {
-			if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
-				return 0, 0, err
+			ready++
+			if cnt < maxBackends {
+				if err := s.writeSvcBackend(skey, id, uint32(cnt), ep); err != nil {
+					return 0, 0, err
+				}
+				cnt++
+				local++
 			}
-			cnt++
-			local++
 		}
 
 		cpEps = append(cpEps, ep)
@@ -786,10 +999,13 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 
 		// eps could contain Ready and Terminating pods but only write Ready pods to backend.
 		if ep.IsReady() {
-			if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
-				return 0, 0, err
+			ready++
+			if cnt < maxBackends {
+				if err := s.writeSvcBackend(skey, id, uint32(cnt), ep); err != nil {
+					return 0, 0, err
+				}
+				cnt++
 			}
-			cnt++
 		}
 
 		cpEps = append(cpEps, ep)
@@ -800,7 +1016,7 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 		flags |= nat.NATFlgInternalLocal
 	}
 
-	if err := s.writeSvc(sinfo, id, cnt, local, flags); err != nil {
+	if err := s.writeSvc(skey, sinfo, id, cnt, local, flags); err != nil {
 		return 0, 0, err
 	}
 
@@ -814,17 +1030,20 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 	// those backends and possible do not break connections that cannot happen.
 	if !hasSvcKeyExtra(skey, svcTypeNodePortRemote) {
 		s.newEpsMap[skey.sname] = cpEps
+		s.checkLimit(skey.sname, limitBackendsSoft, ready)
+		s.checkLimit(skey.sname, limitBackendsHard, ready)
+		s.checkLimit(skey.sname, limitBackendsMax, ready)
 	}
 
 	return cnt, local, nil
 }
 
-func (s *Syncer) writeSvcBackend(svcID uint32, idx uint32, ep k8sp.Endpoint) error {
+func (s *Syncer) writeSvcBackend(skey svcKey, svcID uint32, idx uint32, ep k8sp.Endpoint) error {
 	if log.GetLevel() >= log.DebugLevel {
-		log.WithFields(log.Fields{
-			"svcID": svcID,
-			"idx":   idx,
-			"ep":    ep,
+		s.svcLogCxt(skey, svcID).WithFields(log.Fields{
+			bpflog.FieldMap: s.bpfEps.Name(),
+			"idx":           idx,
+			"ep":            ep,
 		}).Debug("Writing service backend.")
 	}
 	ip := net.ParseIP(ep.IP())
@@ -885,12 +1104,23 @@ func (s *Syncer) getSvcNATKeyLBSrcRange(svc k8sp.ServicePort) ([]nat.FrontendKey
 	return keys, nil
 }
 
-func (s *Syncer) writeLBSrcRangeSvcNATKeys(svc k8sp.ServicePort, svcID uint32, count, local int, flags uint32) error {
-	var key nat.FrontendKeyInterface
-	affinityTimeo := uint32(0)
-	if svc.SessionAffinityType() == v1.ServiceAffinityClientIP {
-		affinityTimeo = uint32(svc.StickyMaxAgeSeconds())
+// affinityTimeout returns the affinity timeout in seconds that is programmed
+// into the NAT value of the service, 0 if the service has no ClientIP affinity.
+// The AffinityTimeoutAnnotation takes precedence over the timeout of the
+// service spec.
+func affinityTimeout(svc Service) uint32 {
+	if svc.SessionAffinityType() != v1.ServiceAffinityClientIP {
+		return 0
+	}
+	if timeo := svc.AffinityTimeoutSeconds(); timeo > 0 {
+		return uint32(timeo)
 	}
+	return uint32(svc.StickyMaxAgeSeconds())
+}
+
+func (s *Syncer) writeLBSrcRangeSvcNATKeys(skey svcKey, svc Service, svcID uint32, count, local int, flags uint32) error {
+	var key nat.FrontendKeyInterface
+	affinityTimeo := affinityTimeout(svc)
 
 	if len(svc.LoadBalancerSourceRanges()) == 0 {
 		return nil
@@ -902,7 +1132,7 @@ func (s *Syncer) writeLBSrcRangeSvcNATKeys(svc k8sp.ServicePort, svcID uint32, c
 	val := nat.NewNATValueWithFlags(svcID, uint32(count), uint32(local), affinityTimeo, flags)
 	for _, key := range keys {
 		if log.GetLevel() >= log.DebugLevel {
-			log.Debugf("bpf map writing %s:%s", key, val)
+			s.svcLogCxt(skey, svcID).Debugf("bpf map writing %s:%s", key, val)
 		}
 		s.bpfSvcs.Desired().Set(key, val)
 	}
@@ -915,7 +1145,7 @@ func (s *Syncer) writeLBSrcRangeSvcNATKeys(svc k8sp.ServicePort, svcID uint32, c
 	return nil
 }
 
-func (s *Syncer) writeSvc(svc Service, svcID uint32, count, local int, flags uint32) error {
+func (s *Syncer) writeSvc(skey svcKey, svc Service, svcID uint32, count, local int, flags uint32) error {
 	key, err := s.getSvcNATKey(svc)
 	if err != nil {
 		return err
@@ -930,16 +1160,16 @@ func (s *Syncer) writeSvc(svc Service, svcID uint32, count, local int, flags uin
 	if svc.ExcludeService() {
 		flags |= nat.NATFlgExclude
 	}
-
-	affinityTimeo := uint32(0)
-	if svc.SessionAffinityType() == v1.ServiceAffinityClientIP {
-		affinityTimeo = uint32(svc.StickyMaxAgeSeconds())
+	if svc.ProxyProtocol() {
+		flags |= nat.NATFlgProxyProtocol
 	}
 
+	affinityTimeo := affinityTimeout(svc)
+
 	val := nat.NewNATValueWithFlags(svcID, uint32(count), uint32(local), affinityTimeo, flags)
 
 	if log.GetLevel() >= log.DebugLevel {
-		log.Debugf("bpf map writing %s:%s", key, val)
+		s.svcLogCxt(skey, svcID).Debugf("bpf map writing %s:%s", key, val)
 	}
 	s.bpfSvcs.Desired().Set(key, val)
 
@@ -947,8 +1177,9 @@ func (s *Syncer) writeSvc(svc Service, svcID uint32, count, local int, flags uin
 	if s.stickyEps[svcID] != nil {
 		affkey := key.AffinityKeyCopy()
 		s.stickySvcs[affkey] = stickyFrontend{
-			id:    svcID,
-			timeo: time.Duration(affinityTimeo) * time.Second,
+			id:                    svcID,
+			timeo:                 time.Duration(affinityTimeo) * time.Second,
+			cleanupSuspendedUntil: svc.AffinityCleanupSuspendedUntil(),
 		}
 	}
 
@@ -997,7 +1228,7 @@ func (s *Syncer) matchBpfSvc(bpfSvc nat.FrontendKeyInterface, k8sSvc k8sp.Servic
 						extra: getSvcKeyExtra(svcTypeNodePort, nip.String()),
 					}
 					if log.GetLevel() >= log.DebugLevel {
-						log.Debugf("resolved %s as %s", bpfSvc, skey)
+						s.logCxt().WithField(bpflog.FieldService, skey.String()).Debugf("resolved %s", bpfSvc)
 					}
 					return skey
 				}
@@ -1013,10 +1244,14 @@ func (s *Syncer) matchBpfSvc(bpfSvc nat.FrontendKeyInterface, k8sSvc k8sp.Servic
 		}
 		return nil
 	}
+	// Entries without a source CIDR have a zero-length prefix, that is
+	// 0.0.0.0/0 for IPv4 and ::/0 for IPv6.
+	zeroSrcCIDR := bpfSvc.SrcCIDR().Prefix() == 0
+
 	matchLBSrcIP := func() bool {
 		// External IP with zero Src CIDR is a valid entry and should not be considered
 		// as stale
-		if bpfSvc.SrcCIDR() == nat.ZeroCIDR {
+		if zeroSrcCIDR {
 			return true
 		}
 		// If the service does not have any source address range, treat all the entries with
@@ -1026,10 +1261,13 @@ func (s *Syncer) matchBpfSvc(bpfSvc nat.FrontendKeyInterface, k8sSvc k8sp.Servic
 		}
 		// If the service does have source range specified, look for a match
 		for _, srcip := range k8sInfo.LoadBalancerSourceRanges() {
-			if strings.Contains(srcip, ":") {
+			if strings.Contains(srcip, ":") != (s.ipFamily == 6) {
+				continue
+			}
+			cidr, err := ip.ParseCIDROrIP(srcip)
+			if err != nil {
 				continue
 			}
-			cidr := ip.MustParseCIDROrIP(srcip).(ip.V4CIDR)
 			if cidr == bpfSvc.SrcCIDR() {
 				return true
 			}
@@ -1037,27 +1275,27 @@ func (s *Syncer) matchBpfSvc(bpfSvc nat.FrontendKeyInterface, k8sSvc k8sp.Servic
 		return false
 	}
 
-	if bpfSvc.Addr().String() == k8sInfo.ClusterIP().String() {
-		if bpfSvc.SrcCIDR() == nat.ZeroCIDR {
+	if bpfSvc.Addr().Equal(k8sInfo.ClusterIP()) {
+		if zeroSrcCIDR {
 			skey := &svcKey{
 				sname: k8sSvc,
 			}
 			if log.GetLevel() >= log.DebugLevel {
-				log.Debugf("resolved %s as %s", bpfSvc, skey)
+				s.logCxt().WithField(bpflog.FieldService, skey.String()).Debugf("resolved %s", bpfSvc)
 			}
 			return skey
 		}
 	}
 
 	for _, eip := range k8sInfo.ExternalIPStrings() {
-		if bpfSvc.Addr().String() == eip {
+		if bpfSvc.Addr().Equal(net.ParseIP(eip)) {
 			if matchLBSrcIP() {
 				skey := &svcKey{
 					sname: k8sSvc,
 					extra: getSvcKeyExtra(svcTypeExternalIP, eip),
 				}
 				if log.GetLevel() >= log.DebugLevel {
-					log.Debugf("resolved %s as %s", bpfSvc, skey)
+					s.logCxt().WithField(bpflog.FieldService, skey.String()).Debugf("resolved %s", bpfSvc)
 				}
 				return skey
 			}
@@ -1066,13 +1304,13 @@ func (s *Syncer) matchBpfSvc(bpfSvc nat.FrontendKeyInterface, k8sSvc k8sp.Servic
 
 	for _, lbip := range k8sInfo.LoadBalancerIPStrings() {
 		if lbip != "" {
-			if bpfSvc.Addr().String() == lbip {
+			if bpfSvc.Addr().Equal(net.ParseIP(lbip)) {
 				if matchLBSrcIP() {
 					skey := &svcKey{
 						sname: k8sSvc,
 						extra: getSvcKeyExtra(svcTypeLoadBalancer, lbip),
 					}
-					log.Debugf("resolved %s as %s", bpfSvc, skey)
+					s.logCxt().WithField(bpflog.FieldService, skey.String()).Debugf("resolved %s", bpfSvc)
 					return skey
 				}
 			}
@@ -1092,7 +1330,7 @@ func (s *Syncer) runExpandNPFixup(misses []*expandMiss) {
 	}
 	expFixupStop := make(chan struct{})
 	if s.expFixupStop != nil {
-		log.Error("BUG: About to start node port fixup goroutine but one already seems to be running. " +
+		s.logCxt().Error("BUG: About to start node port fixup goroutine but one already seems to be running. " +
 			"Stopping the old one.")
 		close(s.expFixupStop)
 	}
@@ -1123,7 +1361,7 @@ func (s *Syncer) runExpandNPFixup(misses []*expandMiss) {
 		}()
 
 		for {
-			log.Debugf("%d misses unresolved", len(misses))
+			s.logCxt().Debugf("%d misses unresolved", len(misses))
 
 			// We do one pass rightaway since we cannot know whether there
 			// was an update or not before we got here
@@ -1173,21 +1411,44 @@ func (s *Syncer) StopExpandNPFixup() {
 	}
 }
 
-// Stop stops the syncer
+// setApplyCancel sets the function that cancels the in-flight Apply, calling
+// it right away if the syncer is already stopped.
+func (s *Syncer) setApplyCancel(cancel context.CancelFunc) {
+	s.applyCancelLck.Lock()
+	defer s.applyCancelLck.Unlock()
+	s.applyCancel = cancel
+	if cancel == nil {
+		return
+	}
+	select {
+	case <-s.stop:
+		cancel()
+	default:
+	}
+}
+
+// Stop stops the syncer, cancelling the in-flight Apply, if any.
 func (s *Syncer) Stop() {
 	s.stopOnce.Do(func() {
-		log.Info("Syncer stopping")
+		s.logCxt().Info("Syncer stopping")
+		s.applyCancelLck.Lock()
 		close(s.stop)
+		if s.applyCancel != nil {
+			s.applyCancel()
+		}
+		s.applyCancelLck.Unlock()
 		s.expFixupWg.Wait()
-		log.Info("Syncer stopped")
+		s.logCxt().Info("Syncer stopped")
 	})
 }
 
 func (s *Syncer) cleanupSticky() error {
 	debug := log.GetLevel() >= log.DebugLevel
 	_ = debug // Work around linter false-positive.
+	logCxt := s.logCxt().WithField(bpflog.FieldMap, s.bpfAff.GetName())
 
 	now := time.Duration(bpf.KTimeNanos())
+	wallNow := time.Now()
 
 	err := s.bpfAff.Iter(func(k, v []byte) maps.IteratorAction {
 		key := s.affinityKeyFromBytes(k)
@@ -1196,26 +1457,35 @@ func (s *Syncer) cleanupSticky() error {
 		fend, ok := s.stickySvcs[key.FrontendAffinityKey()]
 		if !ok {
 			if debug {
-				log.Debugf("cleaning affinity %v:%v - no such a service", key, val)
+				logCxt.Debugf("cleaning affinity %v:%v - no such a service", key, val)
 			}
 			return maps.IterDelete
 		}
 
 		if _, ok := s.stickyEps[fend.id][val.Backend()]; !ok {
-			if debug {
-				log.Debugf("cleaning affinity %v:%v - no such a backend", key, val)
+			if wallNow.Before(fend.cleanupSuspendedUntil) {
+				// The backend may come back, e.g. after a failover, keep
+				// the affinity until it expires or the suspension ends.
+				if debug {
+					logCxt.Debugf("cleaning affinity %v:%v - no such a backend, cleanup suspended until %v",
+						key, val, fend.cleanupSuspendedUntil)
+				}
+			} else {
+				if debug {
+					logCxt.Debugf("cleaning affinity %v:%v - no such a backend", key, val)
+				}
+				return maps.IterDelete
 			}
-			return maps.IterDelete
 		}
 
 		if now-val.Timestamp() > fend.timeo {
 			if debug {
-				log.Debugf("cleaning affinity %v:%v - expired", key, val)
+				logCxt.Debugf("cleaning affinity %v:%v - expired", key, val)
 			}
 			return maps.IterDelete
 		}
 		if debug {
-			log.Debugf("cleaning affinity %v:%v - keeping", key, val)
+			logCxt.Debugf("cleaning affinity %v:%v - keeping", key, val)
 		}
 		return maps.IterNone
 	})
@@ -1235,19 +1505,9 @@ func (s *Syncer) ConntrackFrontendHasBackend(ip net.IP, port uint16,
 		}()
 	}
 
-	id, ok := s.activeSvcsMap[ipPortProto{ipPort{ip.String(), int(port)}, proto}]
+	id, ok := s.activeFrontendID(ip, port, proto)
 	if !ok {
-		// Double check if it is a nodeport as if we are on the node that has
-		// the backing pod for a nodeport and the nodeport was forwarded here,
-		// the frontend is different.
-		npIP := podNPIPStr
-		if s.ipFamily == 6 {
-			npIP = podNPIPV6Str
-		}
-		id, ok = s.activeSvcsMap[ipPortProto{ipPort{npIP, int(port)}, proto}]
-		if !ok {
-			return false
-		}
+		return false
 	}
 
 	backends := s.activeEpsMap[id]
@@ -1260,6 +1520,33 @@ func (s *Syncer) ConntrackFrontendHasBackend(ip net.IP, port uint16,
 	return ok
 }
 
+// ConntrackFrontendService returns the name of the service that the given
+// frontend belongs to.
+func (s *Syncer) ConntrackFrontendService(ip net.IP, port uint16, proto uint8) (string, bool) {
+	id, ok := s.activeFrontendID(ip, port, proto)
+	if !ok {
+		return "", false
+	}
+
+	name, ok := s.activeSvcNames[id]
+	return name, ok
+}
+
+func (s *Syncer) activeFrontendID(ip net.IP, port uint16, proto uint8) (uint32, bool) {
+	id, ok := s.activeSvcsMap[ipPortProto{ipPort{ip.String(), int(port)}, proto}]
+	if !ok {
+		// Double check if it is a nodeport as if we are on the node that has
+		// the backing pod for a nodeport and the nodeport was forwarded here,
+		// the frontend is different.
+		npIP := podNPIPStr
+		if s.ipFamily == 6 {
+			npIP = podNPIPV6Str
+		}
+		id, ok = s.activeSvcsMap[ipPortProto{ipPort{npIP, int(port)}, proto}]
+	}
+	return id, ok
+}
+
 // ConntrackScanStart excludes Apply from running and builds the active maps for
 // ConntrackFrontendHasBackend
 func (s *Syncer) ConntrackScanStart() {
@@ -1268,6 +1555,7 @@ func (s *Syncer) ConntrackScanStart() {
 
 	s.activeSvcsMap = make(map[ipPortProto]uint32)
 	s.activeEpsMap = make(map[uint32]map[ipPort]struct{})
+	s.activeSvcNames = make(map[uint32]string)
 
 	// build active maps for conntrack cleaning
 	for skey, sinfo := range s.newSvcMap {
@@ -1275,6 +1563,9 @@ func (s *Syncer) ConntrackScanStart() {
 			continue
 		}
 
+		// Derived frontends, like node ports, are accounted to their service.
+		s.activeSvcNames[sinfo.id] = skey.sname.String()
+
 		if isSvcKeyDerived(skey) {
 			s.addActiveEps(sinfo.id, sinfo.svc, nil)
 		} else {
@@ -1288,6 +1579,7 @@ func (s *Syncer) ConntrackScanEnd() {
 	// free the maps when the iteration is complete
 	s.activeSvcsMap = nil
 	s.activeEpsMap = nil
+	s.activeSvcNames = nil
 	s.mapsLck.Unlock()
 	log.Debug("ConntrackScanEnd")
 }
@@ -1553,3 +1845,26 @@ func K8sSvcWithReapTerminatingUDP() K8sServicePortOption {
 		s.(*servicePort).reapTerminatingUDP = true
 	}
 }
+
+// K8sSvcWithProxyProtocol makes the service prepend PROXY protocol headers.
+func K8sSvcWithProxyProtocol() K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).proxyProtocol = true
+	}
+}
+
+// K8sSvcWithAffinityTimeout overrides the affinity timeout like the
+// AffinityTimeoutAnnotation does.
+func K8sSvcWithAffinityTimeout(seconds int) K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).affinityTimeoutSeconds = seconds
+	}
+}
+
+// K8sSvcWithAffinityCleanupSuspendedUntil sets the time until which the
+// cleanup of affinity entries of removed backends is suspended.
+func K8sSvcWithAffinityCleanupSuspendedUntil(until time.Time) K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).affinityCleanupSuspendedUntil = until
+	}
+}
@@ -18,6 +18,7 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -196,7 +197,7 @@ func runBenchmarkServiceUpdate(b *testing.B, svcCnt, epCnt int, mockMaps bool, o
 		Expect(err).ShouldNot(HaveOccurred())
 	}
 
-	err = syncer.Apply(state)
+	err = syncer.Apply(context.Background(), state)
 	Expect(err).ShouldNot(HaveOccurred())
 
 	title := fmt.Sprintf("Services %d Endpoints %d mockMaps %t", svcCnt, epCnt, mockMaps)
@@ -218,7 +219,7 @@ func runBenchmarkServiceUpdate(b *testing.B, svcCnt, epCnt int, mockMaps bool, o
 
 			b.StartTimer()
 
-			err := syncer.Apply(state)
+			err := syncer.Apply(context.Background(), state)
 			Expect(err).ShouldNot(HaveOccurred())
 
 			b.StopTimer()
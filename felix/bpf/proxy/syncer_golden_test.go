@@ -0,0 +1,202 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"context"
+	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sp "k8s.io/kubernetes/pkg/proxy"
+
+	proxy "github.com/projectcalico/calico/felix/bpf/proxy"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+// Run the tests with -update-golden to rewrite the golden files after an
+// intended change of the NAT programming, then review the diff.
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden files of the NAT programming")
+
+func goldenSvcName(name, port string) k8sp.ServicePortName {
+	return k8sp.ServicePortName{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: name},
+		Port:           port,
+	}
+}
+
+func goldenEps(local bool, endpoints ...string) []k8sp.Endpoint {
+	var eps []k8sp.Endpoint
+	for _, ep := range endpoints {
+		eps = append(eps, &k8sp.BaseEndpointInfo{Ready: true, Endpoint: ep, IsLocal: local})
+	}
+	return eps
+}
+
+// goldenFixtures are representative service states, keyed by the name of
+// their golden file in testdata.
+var goldenFixtures = map[string]func() proxy.DPSyncerState{
+	"cluster-ip": func() proxy.DPSyncerState {
+		web := goldenSvcName("web", "http")
+		dns := goldenSvcName("dns", "dns")
+		empty := goldenSvcName("empty", "http")
+		return proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{
+				web:   proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 10), 80, v1.ProtocolTCP),
+				dns:   proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 53), 53, v1.ProtocolUDP),
+				empty: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 99), 80, v1.ProtocolTCP),
+			},
+			EpsMap: k8sp.EndpointsMap{
+				web: append(goldenEps(false, "10.65.1.2:8080", "10.65.2.2:8080"), goldenEps(true, "10.65.0.2:8080")...),
+				dns: goldenEps(false, "10.65.1.3:53", "10.65.2.3:53"),
+			},
+		}
+	},
+	"node-port": func() proxy.DPSyncerState {
+		cluster := goldenSvcName("cluster", "http")
+		local := goldenSvcName("local", "http")
+		sticky := goldenSvcName("sticky", "http")
+		return proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{
+				cluster: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 10), 80, v1.ProtocolTCP,
+					proxy.K8sSvcWithNodePort(30080)),
+				local: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 11), 80, v1.ProtocolTCP,
+					proxy.K8sSvcWithNodePort(30081), proxy.K8sSvcWithLocalOnly()),
+				sticky: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 12), 80, v1.ProtocolTCP,
+					proxy.K8sSvcWithNodePort(30082), proxy.K8sSvcWithStickyClientIP(600)),
+			},
+			EpsMap: k8sp.EndpointsMap{
+				cluster: append(goldenEps(false, "10.65.1.2:8080"), goldenEps(true, "10.65.0.2:8080")...),
+				local:   append(goldenEps(false, "10.65.1.3:8080", "10.65.2.3:8080"), goldenEps(true, "10.65.0.3:8080")...),
+				sticky:  goldenEps(false, "10.65.1.4:8080"),
+			},
+		}
+	},
+	"load-balancer": func() proxy.DPSyncerState {
+		lb := goldenSvcName("lb", "https")
+		ext := goldenSvcName("ext", "http")
+		return proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{
+				lb: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 20), 443, v1.ProtocolTCP,
+					proxy.K8sSvcWithLoadBalancerIPs([]string{"35.0.0.1"}),
+					proxy.K8sSvcWithLBSourceRangeIPs([]string{"33.0.0.0/24", "34.0.0.1/32"})),
+				ext: proxy.NewK8sServicePort(net.IPv4(10, 96, 0, 21), 80, v1.ProtocolTCP,
+					proxy.K8sSvcWithExternalIPs([]string{"35.0.1.1", "35.0.1.2"})),
+			},
+			EpsMap: k8sp.EndpointsMap{
+				lb:  goldenEps(false, "10.65.1.5:8443", "10.65.2.5:8443"),
+				ext: goldenEps(true, "10.65.0.6:8080"),
+			},
+		}
+	},
+	"host-ports": func() proxy.DPSyncerState {
+		return proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{},
+			EpsMap: k8sp.EndpointsMap{},
+			HostPorts: []proxy.HostPort{
+				{Namespace: "default", Name: "pod-b", Protocol: v1.ProtocolUDP,
+					HostIP: net.IPv4(10, 123, 0, 1), HostPort: 5353, IP: net.IPv4(10, 65, 0, 8), Port: 53},
+				{Namespace: "default", Name: "pod-a", Protocol: v1.ProtocolTCP,
+					HostPort: 8080, IP: net.IPv4(10, 65, 0, 7), Port: 80},
+			},
+		}
+	},
+}
+
+var _ = Describe("BPF Syncer golden files", func() {
+	var (
+		svcs *mockNATMap
+		eps  *mockNATBackendMap
+		rt   *proxy.RTCache
+		s    *proxy.Syncer
+	)
+
+	nodeIPs := []net.IP{net.IPv4(192, 168, 0, 1), net.IPv4(10, 123, 0, 1)}
+
+	BeforeEach(func() {
+		svcs = newMockNATMap()
+		eps = newMockNATBackendMap()
+
+		// Routes to the remote workloads so that the local-only node ports
+		// expand without misses.
+		rt = proxy.NewRTCache()
+		rt.Update(
+			routes.NewKey(ip.MustParseCIDROrIP("10.65.1.0/24").(ip.V4CIDR)),
+			routes.NewValueWithNextHop(routes.FlagsRemoteWorkload, ip.FromString("10.123.0.2").(ip.V4Addr)),
+		)
+		rt.Update(
+			routes.NewKey(ip.MustParseCIDROrIP("10.65.2.0/24").(ip.V4CIDR)),
+			routes.NewValueWithNextHop(routes.FlagsRemoteWorkload, ip.FromString("10.123.0.3").(ip.V4Addr)),
+		)
+		rt.Update(
+			routes.NewKey(ip.MustParseCIDROrIP("10.65.0.0/24").(ip.V4CIDR)),
+			routes.NewValueWithNextHop(routes.FlagsLocalWorkload, ip.FromString("10.123.0.1").(ip.V4Addr)),
+		)
+
+		var err error
+		s, err = proxy.NewSyncer(4, nodeIPs, svcs, eps, newMockAffinityMap(), rt, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		s.Stop()
+	})
+
+	DescribeTable("should program the NAT maps as in the golden file",
+		func(name string) {
+			state := goldenFixtures[name]()
+			Expect(s.Apply(context.Background(), state)).To(Succeed())
+			got := strings.Join(s.DesiredNATState(), "\n") + "\n"
+
+			path := filepath.Join("testdata", name+".golden")
+			if *updateGolden {
+				Expect(os.WriteFile(path, []byte(got), 0o644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred(), "missing golden file, run the tests with -update-golden")
+			Expect(got).To(Equal(string(expected)))
+
+			By("writing the same state whatever the map iteration order")
+			for i := 0; i < 10; i++ {
+				other, err := proxy.NewSyncer(4, nodeIPs, newMockNATMap(), newMockNATBackendMap(),
+					newMockAffinityMap(), rt, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(other.Apply(context.Background(), goldenFixtures[name]())).To(Succeed())
+				other.Stop()
+				Expect(other.DesiredNATState()).To(Equal(s.DesiredNATState()))
+			}
+
+			By("not writing anything when applying the same state again")
+			svcs.writes = 0
+			eps.writes = 0
+			Expect(s.Apply(context.Background(), goldenFixtures[name]())).To(Succeed())
+			Expect(svcs.writes).To(BeZero())
+			Expect(eps.writes).To(BeZero())
+			Expect(strings.Join(s.DesiredNATState(), "\n") + "\n").To(Equal(got))
+		},
+		Entry("ClusterIP services", "cluster-ip"),
+		Entry("NodePort services", "node-port"),
+		Entry("LoadBalancer and external IP services", "load-balancer"),
+		Entry("hostPorts", "host-ports"),
+	)
+})
@@ -15,6 +15,8 @@
 package proxy_test
 
 import (
+	"context"
+	"fmt"
 	"net"
 	"sync"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	k8sp "k8s.io/kubernetes/pkg/proxy"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -104,7 +107,7 @@ var _ = Describe("BPF Syncer", func() {
 
 	It("should make the right test transitions", func() {
 		By("inserting a service with endpoint", makestep(func() {
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -152,7 +155,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: false, Endpoint: "10.2.0.3:1111"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(2))
@@ -193,7 +196,7 @@ var _ = Describe("BPF Syncer", func() {
 			delete(state.SvcMap, svcKey)
 			delete(state.EpsMap, svcKey)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -232,7 +235,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.2.0.1:2222"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -250,7 +253,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: false, Terminating: true, Endpoint: "10.2.0.1:2222"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(01))
@@ -267,7 +270,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.2.0.1:2222"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -311,7 +314,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.2.0.1:6666"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -334,7 +337,7 @@ var _ = Describe("BPF Syncer", func() {
 				proxy.K8sSvcWithExternalIPs([]string{"35.0.0.2"}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(2))
@@ -360,14 +363,14 @@ var _ = Describe("BPF Syncer", func() {
 				proxy.K8sSvcWithExternalIPs([]string{"35.0.0.2"}),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			// At this (invalid) point the dataplane may have one service or the other...
 
 			// After cleaning up the overlap, we should get back to a good state.
 			delete(state.SvcMap, svcKey3)
-			err = s.Apply(state)
+			err = s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(2))
@@ -392,7 +395,7 @@ var _ = Describe("BPF Syncer", func() {
 				v1.ProtocolTCP,
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -417,7 +420,7 @@ var _ = Describe("BPF Syncer", func() {
 			)
 
 			checkAfterResync = func() {
-				err := s.Apply(state)
+				err := s.Apply(context.Background(), state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(svcs.m).To(HaveLen(3))
@@ -466,7 +469,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.3.0.1:3434"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(6))
@@ -500,7 +503,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.3.0.1:3434"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(6))
@@ -532,7 +535,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.3.0.1:3434"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(6))
@@ -575,7 +578,7 @@ var _ = Describe("BPF Syncer", func() {
 
 			log("state.SvcMap = %+v\n", state.SvcMap)
 			log("state.EpsMap = %+v\n", state.EpsMap)
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			val, ok = svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 2), 2222, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
@@ -590,7 +593,7 @@ var _ = Describe("BPF Syncer", func() {
 			delete(state.SvcMap, svcKey2)
 			delete(state.SvcMap, svcKey3)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(0))
@@ -614,7 +617,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.2.3.1:2222"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(3))
@@ -634,7 +637,7 @@ var _ = Describe("BPF Syncer", func() {
 			s.SetTriggerFn(func() {
 				go func() {
 					logrus.Info("Syncer triggered")
-					err := s.Apply(state)
+					err := s.Apply(context.Background(), state)
 					logrus.WithError(err).Info("Syncer result")
 				}()
 			})
@@ -748,6 +751,16 @@ var _ = Describe("BPF Syncer", func() {
 				net.IPv4(10, 123, 0, 113), 4444, net.IPv4(10, 2, 2, 1), 2222, 6)).To(BeTrue())
 			Expect(s.ConntrackFrontendHasBackend(
 				net.IPv4(10, 123, 0, 113), 4444, net.IPv4(10, 2, 3, 1), 2222, 6)).To(BeTrue())
+
+			// Derived frontends are accounted to their service.
+			name, ok := s.ConntrackFrontendService(net.IPv4(10, 0, 0, 2), 2222, 6)
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal(svcKey2.String()))
+			name, ok = s.ConntrackFrontendService(net.IPv4(192, 168, 0, 1), 4444, 6)
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal(svcKey2.String()))
+			_, ok = s.ConntrackFrontendService(net.IPv4(10, 0, 0, 2), 2223, 6)
+			Expect(ok).To(BeFalse())
 		}))
 
 		By("inserting only non-local eps for a NodePort - multiple nodes & pods/node", makestep(func() {
@@ -781,7 +794,7 @@ var _ = Describe("BPF Syncer", func() {
 					ip.FromString("10.123.0.113").(ip.V4Addr)),
 			)
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			checkAfterResync = func() {
@@ -833,7 +846,7 @@ var _ = Describe("BPF Syncer", func() {
 		By("restarting Syncer to check if NodePortRemotes are picked up correctly", makestep(func() {
 			// use the meta node IP for nodeports as well
 			s, _ = proxy.NewSyncer(4, append(nodeIPs, net.IPv4(255, 255, 255, 255)), svcs, eps, aff, rt, nil)
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			checkAfterResync()
@@ -855,7 +868,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.5.0.1:2222", IsLocal: true},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(3))
@@ -907,6 +920,30 @@ var _ = Describe("BPF Syncer", func() {
 				NotTo(Equal(eps.m[nat.NewNATBackendKey(val1.ID(), 3)]))
 		}))
 
+		By("enabling PROXY protocol for the NodePort", makestep(func() {
+			state.SvcMap[svcKey2] = proxy.NewK8sServicePort(
+				net.IPv4(10, 0, 0, 2),
+				2222,
+				v1.ProtocolTCP,
+				proxy.K8sSvcWithNodePort(4444),
+				proxy.K8sSvcWithLocalOnly(),
+				proxy.K8sSvcWithProxyProtocol(),
+			)
+
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(svcs.m).To(HaveLen(3))
+
+			val1, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 2), 2222, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+			Expect(ok).To(BeTrue())
+			Expect(val1.Flags()).To(Equal(uint32(nat.NATFlgInternalLocal | nat.NATFlgProxyProtocol)))
+
+			val2, ok := svcs.m[nat.NewNATKey(net.IPv4(192, 168, 0, 1), 4444, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+			Expect(ok).To(BeTrue())
+			Expect(val2.Flags()).To(Equal(uint32(nat.NATFlgInternalLocal | nat.NATFlgExternalLocal | nat.NATFlgProxyProtocol)))
+		}))
+
 		By("inserting service with affinity v1.ServiceAffinityClientIP", makestep(func() {
 			state.SvcMap[svcKey2] = proxy.NewK8sServicePort(
 				net.IPv4(10, 0, 0, 2),
@@ -919,7 +956,7 @@ var _ = Describe("BPF Syncer", func() {
 				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.2.0.1:2222"},
 			}
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -963,7 +1000,7 @@ var _ = Describe("BPF Syncer", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(aff.m).To(HaveLen(2))
 
-			err = s.Apply(state)
+			err = s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -1002,7 +1039,27 @@ var _ = Describe("BPF Syncer", func() {
 
 			Expect(aff.m).To(HaveLen(3))
 
-			err = s.Apply(state)
+			err = s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(svcs.m).To(HaveLen(1))
+			Expect(eps.m).To(HaveLen(1))
+			Expect(aff.m).To(HaveLen(2))
+		}))
+
+		By("replacing the ep while affinity cleanup is suspended", makestep(func() {
+			state.SvcMap[svcKey2] = proxy.NewK8sServicePort(
+				net.IPv4(10, 0, 0, 2),
+				2222,
+				v1.ProtocolTCP,
+				proxy.K8sSvcWithStickyClientIP(5),
+				proxy.K8sSvcWithAffinityCleanupSuspendedUntil(time.Now().Add(time.Hour)),
+			)
+			state.EpsMap[svcKey2] = []k8sp.Endpoint{
+				&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.4.0.1:4444"},
+			}
+
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(1))
@@ -1010,6 +1067,40 @@ var _ = Describe("BPF Syncer", func() {
 			Expect(aff.m).To(HaveLen(2))
 		}))
 
+		By("cleaning affinity once the suspension expired", makestep(func() {
+			state.SvcMap[svcKey2] = proxy.NewK8sServicePort(
+				net.IPv4(10, 0, 0, 2),
+				2222,
+				v1.ProtocolTCP,
+				proxy.K8sSvcWithStickyClientIP(5),
+				proxy.K8sSvcWithAffinityCleanupSuspendedUntil(time.Now().Add(-time.Second)),
+			)
+
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(svcs.m).To(HaveLen(1))
+			Expect(eps.m).To(HaveLen(1))
+			Expect(aff.m).To(HaveLen(0))
+		}))
+
+		By("overriding the affinity timeout beyond the k8s limit", makestep(func() {
+			state.SvcMap[svcKey2] = proxy.NewK8sServicePort(
+				net.IPv4(10, 0, 0, 2),
+				2222,
+				v1.ProtocolTCP,
+				proxy.K8sSvcWithStickyClientIP(5),
+				proxy.K8sSvcWithAffinityTimeout(7*24*3600),
+			)
+
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			val, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 2), 2222, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+			Expect(ok).To(BeTrue())
+			Expect(val.AffinityTimeout()).To(Equal(7 * 24 * time.Hour))
+		}))
+
 		By("by removing all services and cleaning affinity table", makestep(func() {
 			delete(state.SvcMap, svcKey2)
 			delete(state.EpsMap, svcKey2)
@@ -1026,7 +1117,7 @@ var _ = Describe("BPF Syncer", func() {
 			)
 			Expect(err).NotTo(HaveOccurred())
 
-			err = s.Apply(state)
+			err = s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(svcs.m).To(HaveLen(0))
@@ -1071,7 +1162,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 			state.NodeZone = "us-west-2a"
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(1))
 		}))
@@ -1089,7 +1180,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 			state.NodeZone = "us-west-2b"
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(1))
 		}))
@@ -1107,7 +1198,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 			state.NodeZone = "us-west-2b"
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(2))
 		}))
@@ -1124,7 +1215,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 			state.NodeZone = "us-west-2b"
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(2))
 		}))
@@ -1142,7 +1233,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 			state.NodeZone = ""
 
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(2))
 		}))
@@ -1170,7 +1261,7 @@ var _ = Describe("BPF Syncer", func() {
 			}
 
 			// Expect 2x new map entries for Ready pods only; Terminating pods not added to map.
-			err := s.Apply(state)
+			err := s.Apply(context.Background(), state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(eps.m).To(HaveLen(2))
 		}))
@@ -1223,7 +1314,7 @@ var _ = Describe("BPF Syncer", func() {
 			},
 		}
 
-		err := s.Apply(state)
+		err := s.Apply(context.Background(), state)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(svcs.m).To(HaveLen(1))
@@ -1260,7 +1351,7 @@ var _ = Describe("BPF Syncer", func() {
 			},
 		}
 
-		err = s.Apply(state)
+		err = s.Apply(context.Background(), state)
 		Expect(err).NotTo(HaveOccurred())
 
 		By("running ct scan again - expect terminating not to be reaped", func() {
@@ -1275,12 +1366,250 @@ var _ = Describe("BPF Syncer", func() {
 		})
 
 	})
+
+	It("should program hostPorts of local workloads", func() {
+		tcp := proxy.ProtoV1ToIntPanic(v1.ProtocolTCP)
+		udp := proxy.ProtoV1ToIntPanic(v1.ProtocolUDP)
+
+		state.HostPorts = []proxy.HostPort{
+			{
+				Namespace: "default",
+				Name:      "pod-a",
+				Protocol:  v1.ProtocolTCP,
+				HostPort:  8080,
+				IP:        net.IPv4(10, 1, 0, 1),
+				Port:      80,
+			},
+			{
+				Namespace: "default",
+				Name:      "pod-b",
+				Protocol:  v1.ProtocolUDP,
+				HostIP:    net.IPv4(10, 123, 0, 1),
+				HostPort:  5353,
+				IP:        net.IPv4(10, 1, 0, 2),
+				Port:      53,
+			},
+		}
+
+		By("applying the hostPorts", func() {
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The service and one frontend per node IP for the first hostPort
+			// and a single frontend for the second one.
+			Expect(svcs.m).To(HaveLen(1 + len(nodeIPs) + 1))
+
+			val, ok := svcs.m[nat.NewNATKey(net.IPv4(192, 168, 0, 1), 8080, tcp)]
+			Expect(ok).To(BeTrue())
+			Expect(val.Count()).To(Equal(uint32(1)))
+			Expect(val.LocalCount()).To(Equal(uint32(1)))
+			val2, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 123, 0, 1), 8080, tcp)]
+			Expect(ok).To(BeTrue())
+			Expect(val2.ID()).To(Equal(val.ID()))
+
+			bval, ok := eps.m[nat.NewNATBackendKey(val.ID(), 0)]
+			Expect(ok).To(BeTrue())
+			Expect(bval).To(Equal(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 80)))
+
+			val, ok = svcs.m[nat.NewNATKey(net.IPv4(10, 123, 0, 1), 5353, udp)]
+			Expect(ok).To(BeTrue())
+			Expect(val.Count()).To(Equal(uint32(1)))
+			_, ok = svcs.m[nat.NewNATKey(net.IPv4(192, 168, 0, 1), 5353, udp)]
+			Expect(ok).To(BeFalse())
+
+			bval, ok = eps.m[nat.NewNATBackendKey(val.ID(), 0)]
+			Expect(ok).To(BeTrue())
+			Expect(bval).To(Equal(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 2), 53)))
+		})
+
+		By("running ct scan - expect hostPort backends to be known", func() {
+			s.StopExpandNPFixup()
+			s.ConntrackScanStart()
+			defer s.ConntrackScanEnd()
+
+			Expect(s.ConntrackFrontendHasBackend(
+				net.IPv4(10, 123, 0, 1), 8080, net.IPv4(10, 1, 0, 1), 80, tcp)).To(BeTrue())
+			Expect(s.ConntrackFrontendHasBackend(
+				net.IPv4(10, 123, 0, 1), 5353, net.IPv4(10, 1, 0, 2), 53, udp)).To(BeTrue())
+		})
+
+		By("removing the hostPorts", func() {
+			state.HostPorts = nil
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(svcs.m).To(HaveLen(1))
+			Expect(eps.m).To(HaveLen(1))
+		})
+	})
+
+	It("should cap the entries of a service at the hard limits", func() {
+		tcp := proxy.ProtoV1ToIntPanic(v1.ProtocolTCP)
+
+		state.SvcMap[svcKey] = proxy.NewK8sServicePort(
+			net.IPv4(10, 0, 0, 1),
+			1234,
+			v1.ProtocolTCP,
+			proxy.K8sSvcWithExternalIPs([]string{"35.0.0.1", "35.0.0.2", "35.0.0.3"}),
+		)
+		state.EpsMap[svcKey] = []k8sp.Endpoint{
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.1:5555"},
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.2:5555"},
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.3:5555", IsLocal: true},
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.4:5555", IsLocal: true},
+		}
+
+		By("applying with limits", func() {
+			s.SetServiceLimits(proxy.ServiceLimits{
+				BackendsSoft:  1,
+				BackendsHard:  3,
+				FrontendsSoft: 1,
+				FrontendsHard: 2,
+			})
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The cluster IP and two of the external IPs.
+			Expect(svcs.m).To(HaveLen(3))
+			val, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, tcp)]
+			Expect(ok).To(BeTrue())
+			Expect(val.Count()).To(Equal(uint32(3)))
+			Expect(val.LocalCount()).To(Equal(uint32(2)))
+
+			// Local backends are preferred.
+			Expect(eps.m).To(HaveLen(3))
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 3), 5555)))
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 4), 5555)))
+		})
+
+		By("applying without limits", func() {
+			s.SetServiceLimits(proxy.ServiceLimits{})
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(svcs.m).To(HaveLen(4))
+			Expect(eps.m).To(HaveLen(4))
+		})
+	})
+
+	It("should cap the backends of a service at what the NAT maps can hold", func() {
+		tcp := proxy.ProtoV1ToIntPanic(v1.ProtocolTCP)
+		backendMapSize := maps.Size(nat.BackendMapParameters.VersionedName())
+		defer maps.SetSize(nat.BackendMapParameters.VersionedName(), backendMapSize)
+		maps.SetSize(nat.BackendMapParameters.VersionedName(), 2)
+		Expect(nat.BackendsLimit(4)).To(Equal(2))
+
+		recorder := events.NewFakeRecorder(10)
+		s.SetEventRecorder(recorder)
+
+		state.SvcMap[svcKey] = proxy.NewK8sServicePort(net.IPv4(10, 0, 0, 1), 1234, v1.ProtocolTCP)
+		state.EpsMap[svcKey] = []k8sp.Endpoint{
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.1:5555"},
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.2:5555"},
+			&k8sp.BaseEndpointInfo{Ready: true, Endpoint: "10.1.0.3:5555"},
+		}
+
+		By("applying with a higher hard limit", func() {
+			s.SetServiceLimits(proxy.ServiceLimits{BackendsHard: 10})
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			val, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, tcp)]
+			Expect(ok).To(BeTrue())
+			Expect(val.Count()).To(Equal(uint32(2)))
+			Expect(eps.m).To(HaveLen(2))
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555)))
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 2), 5555)))
+
+			Expect(recorder.Events).To(Receive(ContainSubstring("backends-max")))
+		})
+
+		By("applying again", func() {
+			err := s.Apply(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The same backends are programmed and there is no new event.
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555)))
+			Expect(eps.m).To(ContainElement(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 2), 5555)))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	Describe("with a cancelled Apply", func() {
+		tcp := proxy.ProtoV1ToIntPanic(v1.ProtocolTCP)
+		var next proxy.DPSyncerState
+		var desired []string
+
+		BeforeEach(func() {
+			Expect(s.Apply(context.Background(), state)).To(Succeed())
+			desired = s.DesiredNATState()
+
+			next = proxy.DPSyncerState{SvcMap: k8sp.ServicePortMap{}, EpsMap: k8sp.EndpointsMap{}}
+			for i := byte(2); i <= 3; i++ {
+				sname := k8sp.ServicePortName{
+					NamespacedName: types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("svc-%d", i)},
+				}
+				next.SvcMap[sname] = proxy.NewK8sServicePort(net.IPv4(10, 0, 0, i), 1234, v1.ProtocolTCP)
+				next.EpsMap[sname] = []k8sp.Endpoint{
+					&k8sp.BaseEndpointInfo{Ready: true, Endpoint: fmt.Sprintf("10.1.0.%d:5555", i)},
+				}
+			}
+		})
+
+		expectNextApplied := func() {
+			eps.onUpdate = nil
+			Expect(s.Apply(context.Background(), next)).To(Succeed())
+			Expect(svcs.m).To(HaveLen(2))
+			Expect(svcs.m).To(HaveKey(nat.NewNATKey(net.IPv4(10, 0, 0, 2), 1234, tcp)))
+			Expect(svcs.m).To(HaveKey(nat.NewNATKey(net.IPv4(10, 0, 0, 3), 1234, tcp)))
+			Expect(eps.m).To(HaveLen(2))
+		}
+
+		It("should keep the previous state if cancelled before writing", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(s.Apply(ctx, next)).To(MatchError(context.Canceled))
+
+			Expect(svcs.m).To(HaveLen(1))
+			Expect(eps.m).To(HaveLen(1))
+			Expect(s.DesiredNATState()).To(Equal(desired))
+			s.ConntrackScanStart()
+			Expect(s.ConntrackFrontendHasBackend(net.IPv4(10, 0, 0, 1), 1234,
+				net.IPv4(10, 1, 0, 1), 5555, tcp)).To(BeTrue())
+			s.ConntrackScanEnd()
+
+			expectNextApplied()
+		})
+
+		It("should leave the remaining writes to the next Apply", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			eps.onUpdate = cancel
+			Expect(s.Apply(ctx, next)).To(MatchError(context.Canceled))
+
+			// The old frontend is deleted first and one new backend is
+			// written, the new frontends are not.
+			Expect(svcs.m).To(BeEmpty())
+			Expect(eps.m).To(HaveLen(2))
+
+			expectNextApplied()
+		})
+
+		It("should be cancelled by Stop", func() {
+			eps.onUpdate = s.Stop
+			Expect(s.Apply(context.Background(), next)).To(MatchError(context.Canceled))
+			Expect(svcs.m).To(BeEmpty())
+			Expect(eps.m).To(HaveLen(2))
+		})
+	})
 })
 
 type mockNATMap struct {
 	mock.DummyMap
 	sync.Mutex
 	m map[nat.FrontendKey]nat.FrontendValue
+	// writes counts the updates and deletions.
+	writes int
 }
 
 func (m *mockNATMap) MapFD() maps.FD {
@@ -1341,6 +1670,7 @@ func (m *mockNATMap) Update(k, v []byte) error {
 	copy(val[:vs], v[:vs])
 
 	m.m[key] = val
+	m.writes++
 
 	return nil
 }
@@ -1366,6 +1696,7 @@ func (m *mockNATMap) Delete(k []byte) error {
 	copy(key[:ks], k[:ks])
 
 	delete(m.m, key)
+	m.writes++
 
 	return nil
 }
@@ -1374,6 +1705,10 @@ type mockNATBackendMap struct {
 	mock.DummyMap
 	sync.Mutex
 	m map[nat.BackendKey]nat.BackendValue
+	// writes counts the updates and deletions.
+	writes int
+	// onUpdate, if set, is called after each update.
+	onUpdate func()
 }
 
 func (m *mockNATBackendMap) MapFD() maps.FD {
@@ -1434,6 +1769,10 @@ func (m *mockNATBackendMap) Update(k, v []byte) error {
 	copy(val[:vs], v[:vs])
 
 	m.m[key] = val
+	m.writes++
+	if m.onUpdate != nil {
+		m.onUpdate()
+	}
 
 	return nil
 }
@@ -1459,6 +1798,7 @@ func (m *mockNATBackendMap) Delete(k []byte) error {
 	copy(key[:ks], k[:ks])
 
 	delete(m.m, key)
+	m.writes++
 
 	return nil
 }
@@ -82,6 +82,7 @@ const (
 	FlagNoDSR           Flags = 0x80
 	FlagBlackHoleDrop   Flags = 0x100
 	FlagBlackHoleReject Flags = 0x200
+	FlagL7Proxy         Flags = 0x400
 
 	FlagsUnknown            Flags = 0
 	FlagsRemoteWorkload           = FlagWorkload
@@ -184,6 +185,10 @@ func (v Value) String() string {
 		parts = append(parts, "blackhole-reject")
 	}
 
+	if typeFlags&FlagL7Proxy != 0 {
+		parts = append(parts, "l7-proxy")
+	}
+
 	if len(parts) == 0 {
 		return fmt.Sprintf("unknown type (%d)", typeFlags)
 	}
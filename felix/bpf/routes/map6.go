@@ -115,6 +115,10 @@ func (v ValueV6) String() string {
 		parts = append(parts, "blackhole-reject")
 	}
 
+	if typeFlags&FlagL7Proxy != 0 {
+		parts = append(parts, "l7-proxy")
+	}
+
 	if typeFlags&FlagLocal != 0 && typeFlags&FlagWorkload != 0 {
 		parts = append(parts, "idx", fmt.Sprint(v.IfaceIndex()))
 	}
@@ -18,14 +18,19 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
 	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
 	"github.com/projectcalico/calico/felix/bpf"
 	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 	"github.com/projectcalico/calico/felix/bpf/hook"
@@ -63,11 +68,25 @@ type AttachPoint struct {
 	NATin                uint32
 	NATout               uint32
 	UDPOnly              bool
+	WireguardEnabled     bool
+	DropFragments        bool
+	CTShards             uint16
+	NAT64Prefix          net.IP
+	NAT64IP              net.IP
+	AttachType           apiv3.BPFAttachOption
+	HardwareOffload      bool
 }
 
 var ErrDeviceNotFound = errors.New("device not found")
 var ErrInterrupted = errors.New("dump interrupted")
 var prefHandleRe = regexp.MustCompile(`pref ([^ ]+) .* handle ([^ ]+)`)
+var inHWRe = regexp.MustCompile(`(^|\s)in_hw(\s|$)`)
+
+// tcxNotSupported is set once the kernel refuses to create a tcx link so that we
+// do not retry for every attach point.
+var tcxNotSupported atomic.Bool
+
+const preambleProgName = "cali_tc_preamble"
 
 func (ap *AttachPoint) Log() *log.Entry {
 	return log.WithFields(log.Fields{
@@ -114,9 +133,11 @@ func (ap *AttachPoint) loadObject(file string) (*libbpf.Obj, error) {
 }
 
 type AttachResult struct {
-	progId int
-	prio   int
-	handle int
+	progId    int
+	prio      int
+	handle    int
+	tcx       bool
+	offloaded bool
 }
 
 func (ar AttachResult) ProgID() int {
@@ -131,6 +152,17 @@ func (ar AttachResult) Handle() int {
 	return ar.handle
 }
 
+// TCX returns true if the program was attached via a tcx link rather than to
+// the clsact qdisc, in which case prio and handle are not valid.
+func (ar AttachResult) TCX() bool {
+	return ar.tcx
+}
+
+// Offloaded returns true if the NIC runs the program, as requested by HardwareOffload.
+func (ar AttachResult) Offloaded() bool {
+	return ar.offloaded
+}
+
 // AttachProgram attaches a BPF program from a file to the TC attach point
 func (ap *AttachPoint) AttachProgram() (bpf.AttachResult, error) {
 	logCxt := log.WithField("attachPoint", ap)
@@ -156,21 +188,167 @@ func (ap *AttachPoint) AttachProgram() (bpf.AttachResult, error) {
 	}
 	defer obj.Close()
 
-	res.progId, res.prio, res.handle, err = obj.AttachClassifier("cali_tc_preamble", ap.Iface, ap.Hook == hook.Ingress)
+	// The programs that are offloaded to the NIC must be attached to the qdisc, tcx links
+	// cannot be offloaded.
+	if ap.AttachType == apiv3.BPFAttachOptionTCX && !ap.HardwareOffload && !tcxNotSupported.Load() {
+		res.progId, err = ap.attachTCXProgram(obj)
+		if err == nil {
+			res.tcx = true
+			logCxt.Info("Program attached to TCX.")
+			// Clean up programs attached to the qdisc by a previous version
+			// or before switching from TC to TCX.
+			if err := ap.detachPrograms(progsToClean); err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
+		if !isTCXNotSupported(err) {
+			logCxt.WithError(err).Warn("Failed to attach to TCX.")
+			return nil, err
+		}
+		logCxt.WithError(err).Warn("TCX is not supported by the kernel, falling back to TC.")
+		tcxNotSupported.Store(true)
+	}
+
+	res.progId, res.prio, res.handle, err = obj.AttachClassifier(preambleProgName, ap.Iface, ap.Hook == hook.Ingress)
 	if err != nil {
 		logCxt.Warnf("Failed to attach to TC section cali_tc_preamble")
 		return nil, err
 	}
 	logCxt.Info("Program attached to TC.")
 
+	if ap.HardwareOffload {
+		res.offloaded = ap.offloadProgram(obj, res.prio, res.handle)
+	}
+
 	if err := ap.detachPrograms(progsToClean); err != nil {
 		return nil, err
 	}
+	if err := ap.detachTCXProgram(); err != nil {
+		return nil, err
+	}
 
 	return res, nil
 }
 
+// attachTCXProgram attaches the preamble via a tcx link, which is pinned so that
+// it outlives felix. If there already is a link, the program is replaced
+// atomically.
+func (ap *AttachPoint) attachTCXProgram(obj *libbpf.Obj) (int, error) {
+	pinPath := ap.tcxLinkPinPath()
+
+	if linkFD, err := libbpf.ObjGet(pinPath); err == nil {
+		err = obj.UpdateTCXLink(linkFD, preambleProgName)
+		syscall.Close(linkFD)
+		if err == nil {
+			return obj.ProgramID(preambleProgName)
+		}
+		// The link is stale, for instance the device was recreated with the
+		// same name, replace it with a new one.
+		log.WithError(err).WithField("pin", pinPath).Debug("Failed to update tcx link, recreating.")
+		if err := os.Remove(pinPath); err != nil && !os.IsNotExist(err) {
+			return -1, fmt.Errorf("failed to remove stale tcx link %s: %w", pinPath, err)
+		}
+	}
+
+	if err := os.MkdirAll(bpfdefs.TcxPinDir(), 0700); err != nil {
+		return -1, fmt.Errorf("failed to create tcx pin directory: %w", err)
+	}
+
+	linkFD, err := obj.AttachTCX(preambleProgName, ap.Iface, ap.Hook == hook.Ingress)
+	if err != nil {
+		return -1, err
+	}
+	defer syscall.Close(linkFD)
+
+	if err := libbpf.ObjPin(linkFD, pinPath); err != nil {
+		return -1, fmt.Errorf("failed to pin tcx link to %s: %w", pinPath, err)
+	}
+
+	return obj.ProgramID(preambleProgName)
+}
+
+// detachTCXProgram removes the pinned tcx link, which detaches the program as
+// nobody else holds a reference to the link.
+func (ap *AttachPoint) detachTCXProgram() error {
+	pinPath := ap.tcxLinkPinPath()
+	if err := os.Remove(pinPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove tcx link %s: %w", pinPath, err)
+	}
+	return nil
+}
+
+func (ap *AttachPoint) tcxLinkPinPath() string {
+	return TCXLinkPinPath(ap.Iface, ap.Hook)
+}
+
+// TCXLinkPinPath returns the path where the tcx link of the given interface and
+// hook is pinned.
+func TCXLinkPinPath(iface string, h hook.Hook) string {
+	return path.Join(bpfdefs.TcxPinDir(), fmt.Sprintf("cali_%s_%s", iface, h))
+}
+
+// isTCXNotSupported returns true for errors that the kernel returns when it
+// does not know the tcx attach types, i.e. kernels before 6.6.
+func isTCXNotSupported(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.EOPNOTSUPP)
+}
+
+// offloadProgram asks the NIC to run the program that was just attached to the qdisc by
+// replacing its filter with the same program marked skip_sw.  The NIC and its driver must
+// support the offload of tc programs and be able to run the program, otherwise the software
+// filter stays in place.  It returns true if the filter is in the hardware.
+func (ap *AttachPoint) offloadProgram(obj *libbpf.Obj, prio, handle int) bool {
+	logCxt := ap.Log()
+
+	// tc can only load a program from a pin.
+	pinDir := path.Join(bpfdefs.GlobalPinDir(), fmt.Sprintf("offload_%s_%s", ap.Iface, ap.Hook))
+	if err := obj.PinPrograms(pinDir); err != nil {
+		logCxt.WithError(err).Warn("Failed to pin program for hardware offload, running it in software.")
+		return false
+	}
+	defer func() {
+		if err := obj.UnpinPrograms(pinDir); err != nil {
+			logCxt.WithError(err).Debug("Failed to unpin offloaded program.")
+		}
+		_ = os.Remove(pinDir)
+	}()
+
+	pref := strconv.Itoa(prio)
+	_, err := ExecTC("filter", "replace", "dev", ap.Iface, ap.Hook.String(), "pref", pref,
+		"handle", fmt.Sprintf("0x%x", handle), "bpf", "object-pinned", path.Join(pinDir, preambleProgName),
+		"direct-action", "skip_sw")
+	if err != nil {
+		logCxt.WithError(err).Warn("The NIC refused to offload the program, running it in software.")
+		return false
+	}
+
+	// The kernel accepts a skip_sw filter only if the driver offloaded it, but we check
+	// anyway as the filter would not see any packets otherwise.
+	out, err := ExecTC("filter", "show", "dev", ap.Iface, ap.Hook.String(), "pref", pref)
+	if err != nil {
+		logCxt.WithError(err).Warn("Failed to check the hardware offload of the program.")
+		return false
+	}
+	if !filterInHW(out) {
+		logCxt.Warn("The offloaded program is not in the hardware.")
+		return false
+	}
+	logCxt.Info("Program offloaded to the NIC.")
+	return true
+}
+
+// filterInHW returns true if the output of tc filter show reports a filter as in_hw, as
+// opposed to not_in_hw.
+func filterInHW(out string) bool {
+	return inHWRe.MatchString(out)
+}
+
 func (ap *AttachPoint) DetachProgram() error {
+	if err := ap.detachTCXProgram(); err != nil {
+		return err
+	}
+
 	progsToClean, err := ap.listAttachedPrograms(true)
 	if err != nil {
 		return err
@@ -319,6 +497,10 @@ func (ap *AttachPoint) ProgramID() (int, error) {
 }
 
 func (ap *AttachPoint) IsAttached() (bool, error) {
+	if _, err := os.Stat(ap.tcxLinkPinPath()); err == nil {
+		return true, nil
+	}
+
 	hasQ, err := HasQdisc(ap.Iface)
 	if err != nil {
 		return false, err
@@ -393,6 +575,7 @@ func (ap *AttachPoint) ConfigureProgram(m *libbpf.Map) error {
 		Wg6Port:      ap.Wg6Port,
 		NatIn:        ap.NATin,
 		NatOut:       ap.NATout,
+		CTShards:     ap.CTShards,
 		LogFilterJmp: uint32(ap.LogFilterIdx),
 	}
 
@@ -422,6 +605,20 @@ func (ap *AttachPoint) ConfigureProgram(m *libbpf.Map) error {
 		globalData.Flags |= libbpf.GlobalsLoUDPOnly
 	}
 
+	if ap.WireguardEnabled {
+		globalData.Flags |= libbpf.GlobalsWireguardEnabled
+	}
+
+	if ap.DropFragments {
+		globalData.Flags |= libbpf.GlobalsDropFragments
+	}
+
+	if ap.NAT64Prefix != nil && ap.NAT64IP != nil {
+		globalData.Flags |= libbpf.GlobalsNAT64Enabled
+		copy(globalData.NAT64Prefix[:], ap.NAT64Prefix.To16())
+		copy(globalData.NAT64IP[:], ap.NAT64IP.To4())
+	}
+
 	globalData.HostTunnelIPv4 = globalData.HostIPv4
 	globalData.HostTunnelIPv6 = globalData.HostIPv6
 
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tc
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/hook"
+)
+
+func TestTCXLinkPinPath(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(TCXLinkPinPath("eth0", hook.Ingress)).To(Equal("/sys/fs/bpf/tcx/cali_eth0_ingress"))
+	Expect(TCXLinkPinPath("cali12345", hook.Egress)).To(Equal("/sys/fs/bpf/tcx/cali_cali12345_egress"))
+}
+
+func TestIsTCXNotSupported(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(isTCXNotSupported(fmt.Errorf("error attaching tcx program: %w", syscall.EINVAL))).To(BeTrue())
+	Expect(isTCXNotSupported(syscall.EOPNOTSUPP)).To(BeTrue())
+	Expect(isTCXNotSupported(syscall.EPERM)).To(BeFalse())
+	Expect(isTCXNotSupported(errors.New("foo"))).To(BeFalse())
+}
+
+func TestFilterInHW(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(filterInHW("filter protocol all pref 49152 bpf chain 0 handle 0x1 cali_tc_preamble direct-action skip_sw in_hw in_hw_count 1 id 61 tag 4add0302745d594c jited")).To(BeTrue())
+	Expect(filterInHW("filter protocol all pref 49152 bpf chain 0 handle 0x1 cali_tc_preamble direct-action not_in_hw id 61 tag 4add0302745d594c jited")).To(BeFalse())
+}
@@ -121,7 +121,7 @@ func CleanUpProgramsAndPins() {
 		return nil
 	})
 
-	bpf.CleanUpCalicoPins(bpfdefs.DefaultBPFfsPath)
+	bpf.CleanUpCalicoPins(bpfdefs.PinRoot())
 }
 
 var tcFiltRegex = regexp.MustCompile(`filter .*? bpf .*? id (\d+)`)
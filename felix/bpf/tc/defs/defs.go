@@ -22,19 +22,21 @@ import (
 )
 
 const (
-	MarkSeen                  = 0x01000000
-	MarkSeenMask              = MarkSeen
-	MarkSeenBypass            = MarkSeen | 0x02000000
-	MarkSeenBypassMask        = MarkSeenMask | MarkSeenBypass
-	MarkSeenFallThrough       = MarkSeen | 0x04000000
-	MarkSeenFallThroughMask   = MarkSeenMask | MarkSeenFallThrough
-	MarkSeenBypassForward     = MarkSeenBypass | 0x00300000
-	MarkSeenBypassForwardMask = MarkSeenBypassMask | 0x00f00000
-	MarkSeenNATOutgoing       = MarkSeenBypass | 0x00800000
-	MarkSeenNATOutgoingMask   = MarkSeenBypassMask | 0x00f00000
-	MarkSeenMASQ              = MarkSeenBypass | 0x00600000
-	MarkSeenMASQMask          = MarkSeenBypassMask | 0x00f00000
-	MarkSeenSkipFIB           = MarkSeen | 0x00100000
+	MarkSeen                    = 0x01000000
+	MarkSeenMask                = MarkSeen
+	MarkSeenBypass              = MarkSeen | 0x02000000
+	MarkSeenBypassMask          = MarkSeenMask | MarkSeenBypass
+	MarkSeenFallThrough         = MarkSeen | 0x04000000
+	MarkSeenFallThroughMask     = MarkSeenMask | MarkSeenFallThrough
+	MarkSeenBypassForward       = MarkSeenBypass | 0x00300000
+	MarkSeenBypassForwardMask   = MarkSeenBypassMask | 0x00f00000
+	MarkSeenNATOutgoing         = MarkSeenBypass | 0x00800000
+	MarkSeenNATOutgoingMask     = MarkSeenBypassMask | 0x00f00000
+	MarkSeenMASQ                = MarkSeenBypass | 0x00600000
+	MarkSeenMASQMask            = MarkSeenBypassMask | 0x00f00000
+	MarkSeenSkipFIB             = MarkSeen | 0x00100000
+	MarkSeenBypassWireguard     = MarkSeenBypass | 0x00500000
+	MarkSeenBypassWireguardMask = MarkSeenBypassMask | 0x00f00000
 
 	MarkLinuxConntrackEstablished     = 0x08000000
 	MarkLinuxConntrackEstablishedMask = 0x08000000
@@ -54,6 +56,7 @@ const (
 	ProgIndexHostCtConflict
 	ProgIndexIcmpInnerNat
 	ProgIndexNewFlow
+	ProgIndexNAT64
 	ProgIndexMainDebug
 	ProgIndexPolicyDebug
 	ProgIndexAllowedDebug
@@ -62,6 +65,7 @@ const (
 	ProgIndexHostCtConflictDebug
 	ProgIndexIcmpInnerNatDebug
 	ProgIndexNewFlowDebug
+	ProgIndexNAT64Debug
 	ProgIndexEndDebug
 	ProgIndexEnd
 
@@ -85,6 +89,7 @@ var ProgramNames = []string{
 	"calico_tc_host_ct_conflict",
 	"calico_tc_skb_icmp_inner_nat",
 	"calico_tc_skb_new_flow_entrypoint",
+	"calico_tc_skb_nat64",
 	/* ipv4 - debug */
 	"calico_tc_main",
 	"calico_tc_norm_pol_tail",
@@ -94,6 +99,7 @@ var ProgramNames = []string{
 	"calico_tc_host_ct_conflict",
 	"calico_tc_skb_icmp_inner_nat",
 	"calico_tc_skb_new_flow_entrypoint",
+	"calico_tc_skb_nat64",
 	/* ipv6 */
 	"calico_tc_main",
 	"calico_tc_norm_pol_tail",
@@ -103,6 +109,7 @@ var ProgramNames = []string{
 	"calico_tc_host_ct_conflict",
 	"calico_tc_skb_icmp_inner_nat",
 	"calico_tc_skb_new_flow_entrypoint",
+	"calico_tc_skb_nat64",
 	/* ipv6 - debug */
 	"calico_tc_main",
 	"calico_tc_norm_pol_tail",
@@ -112,6 +119,7 @@ var ProgramNames = []string{
 	"calico_tc_host_ct_conflict",
 	"calico_tc_skb_icmp_inner_nat",
 	"calico_tc_skb_new_flow_entrypoint",
+	"calico_tc_skb_nat64",
 }
 
 type ToOrFromEp string
@@ -93,9 +93,9 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err = bpfEpMgr.CompleteDeferredWork()
 		Expect(err).NotTo(HaveOccurred())
 
-		programsCount := 13
+		programsCount := 14
 		if ipv6Enabled {
-			programsCount = 25
+			programsCount = 26
 		}
 		Expect(programs.Count()).To(Equal(programsCount))
 		at := programs.Programs()
@@ -149,7 +149,7 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 			bpfEpMgr.OnUpdate(&proto.HostMetadataV6Update{Hostname: "uthost", Ipv6Addr: "1::4"})
 			err = bpfEpMgr.CompleteDeferredWork()
 			Expect(err).NotTo(HaveOccurred())
-			Expect(programs.Count()).To(Equal(50))
+			Expect(programs.Count()).To(Equal(52))
 
 			Expect(at).To(HaveKey(hook.AttachType{
 				Hook:       hook.Ingress,
@@ -287,10 +287,10 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err := bpfEpMgr.CompleteDeferredWork()
 		Expect(err).NotTo(HaveOccurred())
 
-		programCount := 13
+		programCount := 14
 		jumpMapLen := 2
 		if ipv6Enabled {
-			programCount = 50
+			programCount = 52
 			jumpMapLen = 8
 		}
 		Expect(programs.Count()).To(Equal(programCount))
@@ -308,9 +308,9 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err = bpfEpMgr.CompleteDeferredWork()
 		Expect(err).NotTo(HaveOccurred())
 
-		programsCount := 25
+		programsCount := 26
 		if ipv6Enabled {
-			programsCount = 50
+			programsCount = 52
 		}
 		Expect(programs.Count()).To(Equal(programsCount))
 
@@ -378,11 +378,11 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err := bpfEpMgr.CompleteDeferredWork()
 		Expect(err).NotTo(HaveOccurred())
 
-		programsCount := 25
+		programsCount := 26
 		jumpMapLen := 6
 
 		if ipv6Enabled {
-			programsCount = 50
+			programsCount = 52
 			jumpMapLen = 16
 		}
 		Expect(programs.Count()).To(Equal(programsCount))
@@ -524,8 +524,8 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		Expect(err).NotTo(HaveOccurred())
 
 		// Existing maps are repinned
-		oldBase := path.Join(bpfdefs.GlobalPinDir, "old_jumps")
-		_, err = os.Stat(path.Join(bpfdefs.GlobalPinDir, "old_jumps"))
+		oldBase := path.Join(bpfdefs.GlobalPinDir(), "old_jumps")
+		_, err = os.Stat(path.Join(bpfdefs.GlobalPinDir(), "old_jumps"))
 		Expect(err).NotTo(HaveOccurred())
 
 		var tmp string
@@ -546,10 +546,10 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err = oldProgs.Open()
 		Expect(err).NotTo(HaveOccurred())
 		pm := jumpMapDump(oldProgs)
-		programsCount := 25
+		programsCount := 26
 		oldPoliciesCount := 4
 		if ipv6Enabled {
-			programsCount = 50
+			programsCount = 52
 			oldPoliciesCount = 12
 		}
 		Expect(pm).To(HaveLen(programsCount))
@@ -585,9 +585,9 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		err = bpfEpMgr.CompleteDeferredWork()
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(programs.Count()).To(Equal(25))
+		Expect(programs.Count()).To(Equal(26))
 		pm = jumpMapDump(commonMaps.ProgramsMap)
-		Expect(pm).To(HaveLen(25))
+		Expect(pm).To(HaveLen(26))
 
 		pm = jumpMapDump(commonMaps.JumpMap)
 		// We remember the state from above
@@ -595,7 +595,7 @@ func runAttachTest(t *testing.T, ipv6Enabled bool) {
 		Expect(pm).To(HaveKey(wl2State.IngressPolicyV4()))
 		Expect(pm).To(HaveKey(wl2State.EgressPolicyV4()))
 
-		_, err = os.Stat(path.Join(bpfdefs.GlobalPinDir, "old_jumps"))
+		_, err = os.Stat(path.Join(bpfdefs.GlobalPinDir(), "old_jumps"))
 		Expect(err).To(HaveOccurred())
 
 		attachedNew, err := bpf.ListCalicoAttached()
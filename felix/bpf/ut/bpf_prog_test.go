@@ -211,6 +211,7 @@ var tcJumpMapIndexes = map[string][]int{
 		tcdefs.ProgIndexHostCtConflict,
 		tcdefs.ProgIndexIcmpInnerNat,
 		tcdefs.ProgIndexNewFlow,
+		tcdefs.ProgIndexNAT64,
 	},
 	"IPv4 debug": []int{
 		tcdefs.ProgIndexMainDebug,
@@ -221,6 +222,7 @@ var tcJumpMapIndexes = map[string][]int{
 		tcdefs.ProgIndexHostCtConflictDebug,
 		tcdefs.ProgIndexIcmpInnerNatDebug,
 		tcdefs.ProgIndexNewFlowDebug,
+		tcdefs.ProgIndexNAT64Debug,
 	},
 	"IPv6": []int{
 		tcdefs.ProgIndexMain,
@@ -231,6 +233,7 @@ var tcJumpMapIndexes = map[string][]int{
 		tcdefs.ProgIndexHostCtConflict,
 		tcdefs.ProgIndexIcmpInnerNat,
 		tcdefs.ProgIndexNewFlow,
+		tcdefs.ProgIndexNAT64,
 	},
 	"IPv6 debug": []int{
 		tcdefs.ProgIndexMainDebug,
@@ -241,6 +244,7 @@ var tcJumpMapIndexes = map[string][]int{
 		tcdefs.ProgIndexHostCtConflictDebug,
 		tcdefs.ProgIndexIcmpInnerNatDebug,
 		tcdefs.ProgIndexNewFlowDebug,
+		tcdefs.ProgIndexNAT64Debug,
 	},
 }
 
@@ -697,6 +701,10 @@ func tcUpdateJumpMap(obj *libbpf.Obj, progs []int, hasPolicyProg, hasHostConflic
 		log.WithField("prog", tcdefs.ProgramNames[idx]).WithField("idx", idx).Debug("UpdateJumpMap")
 		err := obj.UpdateJumpMap(progMap.GetName(), tcdefs.ProgramNames[idx], idx)
 		if err != nil {
+			if idx == tcdefs.ProgIndexNAT64 || idx == tcdefs.ProgIndexNAT64Debug {
+				// Only the workload and host ingress programs have it.
+				continue
+			}
 			return fmt.Errorf("error updating %s program: %w", tcdefs.ProgramNames[idx], err)
 		}
 	}
@@ -772,6 +780,12 @@ func objLoad(fname, bpfFsDir, ipFamily string, topts testOpts, polProg, hasHostC
 					globals.JumpsV6[i] = uint32(i)
 				}
 
+				if topts.nat64 {
+					globals.Flags |= libbpf.GlobalsNAT64Enabled
+					copy(globals.NAT64Prefix[:], nat64Prefix.To16())
+					copy(globals.NAT64IP[:], nat64IP.To4())
+				}
+
 				log.WithField("globals", globals).Debugf("configure program v6")
 
 				if err := tc.ConfigureProgram(m, ifaceLog, &globals); err != nil {
@@ -797,6 +811,12 @@ func objLoad(fname, bpfFsDir, ipFamily string, topts testOpts, polProg, hasHostC
 					globals.Jumps[i] = uint32(i)
 				}
 
+				if topts.nat64 {
+					globals.Flags |= libbpf.GlobalsNAT64Enabled
+					copy(globals.NAT64Prefix[:], nat64Prefix.To16())
+					copy(globals.NAT64IP[:], nat64IP.To4())
+				}
+
 				log.WithField("globals", globals).Debugf("configure program")
 
 				if err := tc.ConfigureProgram(m, ifaceLog, &globals); err != nil {
@@ -1119,6 +1139,7 @@ type testOpts struct {
 	hostNetworked bool
 	progLog       string
 	ipv6          bool
+	nat64         bool
 	objname       string
 }
 
@@ -1165,6 +1186,12 @@ func withIPv6() testOption {
 	}
 }
 
+func withNAT64() testOption {
+	return func(o *testOpts) {
+		o.nat64 = true
+	}
+}
+
 func withObjName(name string) testOption {
 	return func(o *testOpts) {
 		o.objname = name
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ut_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/counters"
+	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+)
+
+// ipv4Fragments splits the IP payload of an IPv4 packet without options into
+// two fragments, the first one carrying firstLen bytes of the payload.
+func ipv4Fragments(pktBytes []byte, id uint16, firstLen int) ([]byte, []byte) {
+	p := gopacket.NewPacket(pktBytes, layers.LayerTypeEthernet, gopacket.Default)
+	eth := p.Layer(layers.LayerTypeEthernet).(*layers.Ethernet)
+	ipv4 := *p.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
+	l4 := ipv4.Payload
+
+	serialize := func(ip *layers.IPv4, payload []byte) []byte {
+		pkt := gopacket.NewSerializeBuffer()
+		err := gopacket.SerializeLayers(pkt, gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true},
+			eth, ip, gopacket.Payload(payload))
+		Expect(err).NotTo(HaveOccurred())
+		return pkt.Bytes()
+	}
+
+	ipv4.Id = id
+
+	first := ipv4
+	first.Flags = layers.IPv4MoreFragments
+	first.FragOffset = 0
+
+	second := ipv4
+	second.Flags = 0
+	second.FragOffset = uint16(firstLen / 8)
+
+	return serialize(&first, l4[:firstLen]), serialize(&second, l4[firstLen:])
+}
+
+func TestNATFragments(t *testing.T) {
+	RegisterTestingT(t)
+
+	bpfIfaceName = "FRAG"
+	defer func() { bpfIfaceName = "" }()
+
+	payload := make([]byte, 64)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	ipv4 := *ipv4Default
+	ipv4.Flags = 0
+	udp := *udpDefault
+
+	_, _, _, _, pktBytes, err := testPacketV4(nil, &ipv4, &udp, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = natMap.Update(
+		nat.NewNATKey(ipv4.DstIP, uint16(udp.DstPort), uint8(ipv4.Protocol)).AsBytes(),
+		nat.NewNATValue(0, 1, 0, 0).AsBytes(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	natIP := net.IPv4(8, 8, 8, 8)
+	natPort := uint16(666)
+
+	err = natBEMap.Update(
+		nat.NewNATBackendKey(0, 0).AsBytes(),
+		nat.NewNATBackendValue(natIP, natPort).AsBytes(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	defer resetBPFMaps()
+	resetCTMap(ctMap)
+
+	rtKey := routes.NewKey(srcV4CIDR).AsBytes()
+	rtVal := routes.NewValueWithIfIndex(routes.FlagsLocalWorkload|routes.FlagInIPAMPool, 1).AsBytes()
+	err = rtMap.Update(rtKey, rtVal)
+	Expect(err).NotTo(HaveOccurred())
+
+	// The fragments we expect are the fragments of the NATed datagram.
+	ipv4Nat := ipv4
+	ipv4Nat.DstIP = natIP
+	udpNat := udp
+	udpNat.DstPort = layers.UDPPort(natPort)
+
+	_, _, _, _, natPktBytes, err := testPacketV4(nil, &ipv4Nat, &udpNat, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	first, second := ipv4Fragments(pktBytes, 0x1234, 32)
+	natFirst, natSecond := ipv4Fragments(natPktBytes, 0x1234, 32)
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_workload_ep", rulesDefaultAllow, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(first)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Retval).To(Equal(resTC_ACT_UNSPEC))
+
+		pktR := gopacket.NewPacket(res.dataOut, layers.LayerTypeEthernet, gopacket.Default)
+		fmt.Printf("pktR = %+v\n", pktR)
+
+		Expect(res.dataOut).To(Equal(natFirst))
+	})
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_workload_ep", rulesDefaultAllow, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Retval).To(Equal(resTC_ACT_UNSPEC))
+
+		pktR := gopacket.NewPacket(res.dataOut, layers.LayerTypeEthernet, gopacket.Default)
+		fmt.Printf("pktR = %+v\n", pktR)
+
+		// Only the IP header is translated, the payload is left intact.
+		Expect(res.dataOut).To(Equal(natSecond))
+	})
+
+	// A non-first fragment of a datagram whose first fragment we have not
+	// seen cannot be translated.
+	_, unknown := ipv4Fragments(pktBytes, 0x4321, 32)
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_workload_ep", rulesDefaultAllow, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(unknown)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RetvalStr()).To(Equal("TC_ACT_SHOT"), "expected program to return TC_ACT_SHOT")
+
+		bpfCounters, err := counters.Read(countersMap, 1, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(int(bpfCounters[counters.DroppedFragment])).To(Equal(1))
+	})
+}
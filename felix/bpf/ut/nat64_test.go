@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ut_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/nat64"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+)
+
+var (
+	nat64Prefix = net.ParseIP("64:ff9b::")
+	nat64IP     = net.IPv4(10, 65, 0, 1).To4()
+)
+
+func TestNAT64UDP(t *testing.T) {
+	RegisterTestingT(t)
+
+	bpfIfaceName = "NAT64"
+	defer func() { bpfIfaceName = "" }()
+
+	fwdMap := nat64.FwdMap()
+	revMap := nat64.RevMap()
+	for _, m := range []maps.Map{fwdMap, revMap} {
+		Expect(m.EnsureExists()).To(Succeed())
+		resetMap(m)
+		defer resetMap(m)
+	}
+
+	defer resetBPFMaps()
+	resetCTMapV6(ctMapV6)
+	defer resetCTMapV6(ctMapV6)
+
+	rtKey := routes.NewKeyV6(srcV6CIDR).AsBytes()
+	rtVal := routes.NewValueV6WithIfIndex(routes.FlagsLocalWorkload|routes.FlagInIPAMPool, 1).AsBytes()
+	Expect(rtMapV6.Update(rtKey, rtVal)).To(Succeed())
+	defer func() { _ = rtMapV6.Delete(rtKey) }()
+
+	dstIPv4 := net.IPv4(8, 8, 8, 8).To4()
+	payload := []byte("NAT64 payload")
+
+	ipv6 := *ipv6Default
+	ipv6.DstIP = net.ParseIP("64:ff9b::808:808")
+	udp := *udpDefault
+
+	_, _, _, _, pktBytes, err := testPacketV6(nil, &ipv6, &udp, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	var natPort uint16
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_workload_ep", rulesDefaultAllow, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(pktBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RetvalStr()).NotTo(Equal("TC_ACT_SHOT"))
+
+		pktR := gopacket.NewPacket(res.dataOut, layers.LayerTypeEthernet, gopacket.Default)
+		fmt.Printf("pktR = %+v\n", pktR)
+
+		udpR := pktR.Layer(layers.LayerTypeUDP).(*layers.UDP)
+		natPort = uint16(udpR.SrcPort)
+		Expect(natPort).To(BeNumerically(">=", 20000))
+		Expect(natPort).To(BeNumerically("<=", 30000))
+
+		ipv4 := layers.IPv4{
+			Version:  4,
+			IHL:      5,
+			TTL:      ipv6.HopLimit - 1,
+			Flags:    layers.IPv4DontFragment,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    nat64IP,
+			DstIP:    dstIPv4,
+		}
+		udpNat := udp
+		udpNat.SrcPort = layers.UDPPort(natPort)
+
+		_, _, _, _, expected, err := testPacketV4(nil, &ipv4, &udpNat, payload)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The MACs depend on the FIB lookup, compare from the ethertype on.
+		Expect(res.dataOut[12:]).To(Equal(expected[12:]))
+	}, withIPv6(), withNAT64())
+
+	fwdKey := nat64.NewFwdKey(srcIPv6, uint16(udp.SrcPort), dstIPv4, uint16(udp.DstPort), 17)
+	fwdVal, err := fwdMap.Get(fwdKey.AsBytes())
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nat64.FwdValueFromBytes(fwdVal).NATPort()).To(Equal(natPort))
+
+	revKey := nat64.NewRevKey(dstIPv4, uint16(udp.DstPort), natPort, 17)
+	revVal, err := revMap.Get(revKey.AsBytes())
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nat64.RevValueFromBytes(revVal).Addr().Equal(srcIPv6)).To(BeTrue())
+
+	// The reply from the IPv4 destination.
+	ipv4 := layers.IPv4{
+		Version:  4,
+		IHL:      5,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    dstIPv4,
+		DstIP:    nat64IP,
+	}
+	udpReply := layers.UDP{
+		SrcPort: udp.DstPort,
+		DstPort: layers.UDPPort(natPort),
+	}
+
+	_, _, _, _, replyBytes, err := testPacketV4(nil, &ipv4, &udpReply, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_host_ep", nil, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(replyBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RetvalStr()).NotTo(Equal("TC_ACT_SHOT"))
+
+		pktR := gopacket.NewPacket(res.dataOut, layers.LayerTypeEthernet, gopacket.Default)
+		fmt.Printf("pktR = %+v\n", pktR)
+
+		ipv6Reply := layers.IPv6{
+			Version:    6,
+			HopLimit:   ipv4.TTL - 1,
+			NextHeader: layers.IPProtocolUDP,
+			SrcIP:      ipv6.DstIP,
+			DstIP:      srcIPv6,
+		}
+		udpExp := layers.UDP{
+			SrcPort: udp.DstPort,
+			DstPort: udp.SrcPort,
+		}
+
+		_, _, _, _, expected, err := testPacketV6(nil, &ipv6Reply, &udpExp, payload)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(res.dataOut[12:]).To(Equal(expected[12:]))
+	}, withNAT64())
+
+	// Packets to the host's IPv4 address that are not replies are not
+	// translated.
+	udpReply.DstPort = layers.UDPPort(natPort + 1)
+	_, _, _, _, replyBytes, err = testPacketV4(nil, &ipv4, &udpReply, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	skbMark = 0
+	runBpfTest(t, "calico_from_host_ep", nil, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(replyBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.dataOut).To(Equal(replyBytes))
+	}, withNAT64())
+}
@@ -2833,6 +2833,119 @@ func TestNATHostRemoteNPLocalPod(t *testing.T) {
 	dumpCTMap(ctMap)
 }
 
+func TestNATPodToSelfViaService(t *testing.T) {
+	RegisterTestingT(t)
+
+	defer resetCTMap(ctMap)
+
+	bpfIfaceName = "SELF"
+	defer func() { bpfIfaceName = "" }()
+
+	eth, ipv4, l4, payload, pktBytes, err := testPacketUDPDefault()
+	Expect(err).NotTo(HaveOccurred())
+	udp := l4.(*layers.UDP)
+
+	natMap := nat.FrontendMap()
+	err = natMap.EnsureExists()
+	Expect(err).NotTo(HaveOccurred())
+
+	natBEMap := nat.BackendMap()
+	err = natBEMap.EnsureExists()
+	Expect(err).NotTo(HaveOccurred())
+
+	err = natMap.Update(
+		nat.NewNATKey(ipv4.DstIP, uint16(udp.DstPort), uint8(ipv4.Protocol)).AsBytes(),
+		nat.NewNATValue(0, 1, 0, 0).AsBytes(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	// The only backend of the service is the client pod itself.
+	natPort := uint16(666)
+	err = natBEMap.Update(
+		nat.NewNATBackendKey(0, 0).AsBytes(),
+		nat.NewNATBackendValue(ipv4.SrcIP, natPort).AsBytes(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	ctMap := conntrack.Map()
+	err = ctMap.EnsureExists()
+	Expect(err).NotTo(HaveOccurred())
+	resetCTMap(ctMap)
+
+	rtMap := routes.Map()
+	err = rtMap.EnsureExists()
+	Expect(err).NotTo(HaveOccurred())
+	defer resetRTMap(rtMap)
+	err = rtMap.Update(
+		routes.NewKey(srcV4CIDR).AsBytes(),
+		routes.NewValueWithIfIndex(routes.FlagsLocalWorkload|routes.FlagInIPAMPool, 1).AsBytes(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	hostIP = node1ip
+	skbMark = 0
+
+	// Leaving the pod towards the service, the packet is NATed back to the
+	// pod and sent to the host stack to be MASQed.
+	runBpfTest(t, "calico_from_workload_ep", rulesDefaultAllow, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(pktBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Retval).To(Equal(resTC_ACT_UNSPEC))
+
+		ipv4Nat := *ipv4
+		ipv4Nat.DstIP = ipv4.SrcIP
+
+		udpNat := *udp
+		udpNat.DstPort = layers.UDPPort(natPort)
+
+		_, _, _, _, resPktBytes, err := testPacketV4(eth, &ipv4Nat, &udpNat, payload)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.dataOut).To(Equal(resPktBytes))
+	})
+
+	expectMark(tcdefs.MarkSeenMASQ)
+
+	dumpCTMap(ctMap)
+	ct, err := conntrack.LoadMapMem(ctMap)
+	Expect(err).NotTo(HaveOccurred())
+	foundRev := false
+	for _, v := range ct {
+		if v.Type() == conntrack.TypeNATReverse {
+			Expect(v.Flags() & conntrack3.FlagSvcSelf).NotTo(BeZero())
+			foundRev = true
+		}
+	}
+	Expect(foundRev).To(BeTrue())
+
+	// The host MASQs the packet to the host IP and sends it back to the pod.
+	// The mark may carry other bits set by iptables.
+	ipv4Masq := *ipv4
+	ipv4Masq.SrcIP = node1ip
+	ipv4Masq.DstIP = ipv4.SrcIP
+	udpMasq := *udp
+	udpMasq.DstPort = layers.UDPPort(natPort)
+	_, _, _, _, masqPktBytes, err := testPacketV4(eth, &ipv4Masq, &udpMasq, payload)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Policy that allows only the pod itself.
+	rulesSelf := makeRulesSingleTier([]*proto.Rule{{
+		Action: "Allow",
+		SrcNet: []string{srcV4CIDR.String()},
+	}})
+
+	skbMark = tcdefs.MarkSeenMASQ | tcdefs.MarkLinuxConntrackEstablished
+
+	// Arriving at the pod, policy sees the pod as the source.
+	runBpfTest(t, "calico_to_workload_ep", &rulesSelf, func(bpfrun bpfProgRunFn) {
+		res, err := bpfrun(masqPktBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Retval).To(Equal(resTC_ACT_UNSPEC))
+		Expect(res.dataOut).To(Equal(masqPktBytes))
+	})
+
+	dumpCTMap(ctMap)
+}
+
 func TestNATPodPodXNodeV6(t *testing.T) {
 	RegisterTestingT(t)
 
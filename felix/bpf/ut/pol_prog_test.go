@@ -1855,6 +1855,47 @@ var polProgramTests = []polProgramTest{
 		DroppedPackets: []packet{
 			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 8, 3)},
 	},
+	{
+		PolicyName: "allow multiple ICMP types and type/codes",
+		Policy: makeRulesSingleTier([]*proto.Rule{{
+			Action:        "Allow",
+			Protocol:      &proto.Protocol{NumberOrName: &proto.Protocol_Name{Name: "icmp"}},
+			IcmpTypes:     []int32{0, 8},
+			IcmpTypeCodes: []*proto.IcmpTypeAndCode{{Type: 3, Code: 4}},
+		}}),
+		AllowedPackets: []packet{
+			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 0, 0),
+			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 8, 0),
+			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 3, 4)},
+		DroppedPackets: []packet{
+			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 3, 1),
+			icmpPktWithTypeCode("10.0.0.1", "10.0.0.2", 11, 0)},
+	},
+	// DSCP tests.
+	{
+		PolicyName: "allow packets with DSCP 46",
+		Policy: makeRulesSingleTier([]*proto.Rule{{
+			Action: "Allow",
+			Dscp:   &proto.Rule_DscpValue{DscpValue: 46},
+		}}),
+		AllowedPackets: []packet{
+			tcpPkt("10.0.0.1:31245", "10.0.0.2:80").withDSCP(46),
+			udpPkt("10.0.0.1:31245", "10.0.0.2:53").withDSCP(46)},
+		DroppedPackets: []packet{
+			tcpPkt("10.0.0.1:31245", "10.0.0.2:80"),
+			tcpPkt("10.0.0.1:31245", "10.0.0.2:80").withDSCP(10)},
+	},
+	{
+		PolicyName: "allow packets with DSCP not equal to 0",
+		Policy: makeRulesSingleTier([]*proto.Rule{{
+			Action:  "Allow",
+			NotDscp: &proto.Rule_NotDscpValue{NotDscpValue: 0},
+		}}),
+		AllowedPackets: []packet{
+			tcpPkt("10.0.0.1:31245", "10.0.0.2:80").withDSCP(10)},
+		DroppedPackets: []packet{
+			tcpPkt("10.0.0.1:31245", "10.0.0.2:80")},
+	},
 	// Generic protocol tests.
 	{
 		PolicyName: "Protocol match",
@@ -2399,6 +2440,7 @@ type packet struct {
 	srcPort  int
 	dstAddr  string
 	dstPort  int
+	dscp     int
 
 	preNATDstAddr string
 	preNATDstPort int
@@ -2417,6 +2459,11 @@ func (p packet) preNAT(dst string) packet {
 	return p
 }
 
+func (p packet) withDSCP(dscp int) packet {
+	p.dscp = dscp
+	return p
+}
+
 func (p packet) fromHost() packet {
 	p.fromHostFlag = true
 	return p
@@ -2449,7 +2496,11 @@ func (p packet) String() string {
 	if p.toHostFlag {
 		toHost = "(H)"
 	}
-	return fmt.Sprintf("%s-%s%s:%d->%s%s%s:%d", protoName, p.srcAddr, fromHost, p.srcPort, preNAT, p.dstAddr, toHost, p.dstPort)
+	dscp := ""
+	if p.dscp != 0 {
+		dscp = fmt.Sprintf("(DSCP %d)", p.dscp)
+	}
+	return fmt.Sprintf("%s-%s%s:%d->%s%s%s:%d%s", protoName, p.srcAddr, fromHost, p.srcPort, preNAT, p.dstAddr, toHost, p.dstPort, dscp)
 }
 
 func (p packet) StateIn() state.State {
@@ -2476,6 +2527,7 @@ func (p packet) StateIn() state.State {
 
 	return state.State{
 		IPProto:         uint8(p.protocol),
+		IPDSCP:          uint8(p.dscp),
 		SrcAddr:         ipUintFromString(p.srcAddr, 0),
 		SrcAddr1:        ipUintFromString(p.srcAddr, 1),
 		SrcAddr2:        ipUintFromString(p.srcAddr, 2),
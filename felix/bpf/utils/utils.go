@@ -36,24 +36,24 @@ import (
 
 func MaybeMountBPFfs() (string, error) {
 	var err error
-	bpffsPath := bpfdefs.DefaultBPFfsPath
+	bpffsPath := bpfdefs.BPFfsPath()
 
-	mnt, err := isMount(bpfdefs.DefaultBPFfsPath)
+	mnt, err := isMount(bpffsPath)
 	if err != nil {
 		return "", err
 	}
 
-	fsBPF, err := isBPF(bpfdefs.DefaultBPFfsPath)
+	fsBPF, err := isBPF(bpffsPath)
 	if err != nil {
 		return "", err
 	}
 
 	if !mnt {
-		err = mountBPFfs(bpfdefs.DefaultBPFfsPath)
+		err = mountBPFfs(bpffsPath)
 	} else if !fsBPF {
 		var runfsBPF bool
 
-		bpffsPath = "/var/run/calico/bpffs"
+		bpffsPath = filepath.Join(bpfdefs.RuntimeDir(), "bpffs")
 
 		if err := os.MkdirAll(bpffsPath, 0700); err != nil {
 			return "", err
@@ -15,10 +15,10 @@
 package cachingmap
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/sirupsen/logrus"
-
+	"github.com/projectcalico/calico/felix/bpf/bpflog"
 	"github.com/projectcalico/calico/felix/deltatracker"
 )
 
@@ -57,7 +57,7 @@ func New[K comparable, V comparable](name string, dpMap DataplaneMap[K, V]) *Cac
 				// Since we require V to be comparable, can just use '==' here.
 				return a == b
 			}),
-			deltatracker.WithLogCtx[K, V](logrus.WithField("bpfMap", name)),
+			deltatracker.WithLogCtx[K, V](bpflog.WithMap(name)),
 		),
 	}
 	return cm
@@ -66,10 +66,10 @@ func New[K comparable, V comparable](name string, dpMap DataplaneMap[K, V]) *Cac
 // LoadCacheFromDataplane loads the contents of the DP map into the dataplane cache, allowing it to be queried with
 // GetDataplane and IterDataplane.
 func (c *CachingMap[K, V]) LoadCacheFromDataplane() error {
-	logrus.WithField("name", c.name).Debug("Loading BPF map from dataplane.")
+	bpflog.WithMap(c.name).Debug("Loading BPF map from dataplane.")
 	dp, err := c.dpMap.Load()
 	if err != nil {
-		logrus.WithError(err).WithField("name", c.name).Warn("Failed to load cache of dataplane map")
+		bpflog.WithMap(c.name).WithError(err).Warn("Failed to load cache of dataplane map")
 		return err
 	}
 	c.deltaTracker.Dataplane().ReplaceAllMap(dp)
@@ -89,6 +89,11 @@ type ReadWriteMap[K comparable, V any] interface {
 	DeleteAll()
 }
 
+// Name returns the name of the map, as used in the logs.
+func (c *CachingMap[K, V]) Name() string {
+	return c.name
+}
+
 func (c *CachingMap[K, V]) Desired() ReadWriteMap[K, V] {
 	// Pass through to the delta tracker.
 	return c.deltaTracker.Desired()
@@ -102,11 +107,11 @@ func (c *CachingMap[K, V]) Dataplane() ReadOnlyMap[K, V] {
 // ApplyAllChanges attempts to bring the dataplane map into sync with the desired state.
 func (c *CachingMap[K, V]) ApplyAllChanges() error {
 	var errs ErrSlice
-	err := c.ApplyDeletionsOnly()
+	err := c.ApplyDeletionsOnly(context.Background())
 	if err != nil {
 		errs = append(errs, err)
 	}
-	err = c.ApplyUpdatesOnly()
+	err = c.ApplyUpdatesOnly(context.Background())
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -124,23 +129,30 @@ func (c *CachingMap[K, V]) maybeLoadCache() error {
 }
 
 // ApplyUpdatesOnly applies any pending adds/updates to the dataplane map.  It doesn't delete any keys that are no
-// longer wanted.
-func (c *CachingMap[K, V]) ApplyUpdatesOnly() error {
-	logrus.WithField("name", c.name).Debug("Applying updates to DP map.")
+// longer wanted.  If the context is done, it stops writing and returns the context's error; the updates that were
+// not written stay pending.
+func (c *CachingMap[K, V]) ApplyUpdatesOnly(ctx context.Context) error {
+	bpflog.WithMap(c.name).Debug("Applying updates to DP map.")
 	err := c.maybeLoadCache()
 	if err != nil {
 		return err
 	}
 	var errs ErrSlice
 	c.deltaTracker.PendingUpdates().Iter(func(k K, v V) deltatracker.IterAction {
+		if ctx.Err() != nil {
+			return deltatracker.IterActionNoOp
+		}
 		err := c.dpMap.Update(k, v)
 		if err != nil {
-			logrus.WithError(err).Warn("Error while updating DP map")
+			bpflog.WithMap(c.name).WithError(err).Warn("Error while updating DP map")
 			errs = append(errs, err)
 			return deltatracker.IterActionNoOp
 		}
 		return deltatracker.IterActionUpdateDataplane
 	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if len(errs) > 0 {
 		return errs
 	}
@@ -148,23 +160,29 @@ func (c *CachingMap[K, V]) ApplyUpdatesOnly() error {
 }
 
 // ApplyDeletionsOnly applies any pending deletions to the dataplane map.  It doesn't add or update any keys that
-// are new/changed.
-func (c *CachingMap[K, V]) ApplyDeletionsOnly() error {
-	logrus.WithField("name", c.name).Debug("Applying deletions to DP map.")
+// are new/changed.  Like ApplyUpdatesOnly, it stops once the context is done.
+func (c *CachingMap[K, V]) ApplyDeletionsOnly(ctx context.Context) error {
+	bpflog.WithMap(c.name).Debug("Applying deletions to DP map.")
 	err := c.maybeLoadCache()
 	if err != nil {
 		return err
 	}
 	var errs ErrSlice
 	c.deltaTracker.PendingDeletions().Iter(func(k K) deltatracker.IterAction {
+		if ctx.Err() != nil {
+			return deltatracker.IterActionNoOp
+		}
 		err := c.dpMap.Delete(k)
 		if err != nil && !c.dpMap.ErrIsNotExists(err) {
-			logrus.WithError(err).Warn("Error while deleting from DP map")
+			bpflog.WithMap(c.name).WithError(err).Warn("Error while deleting from DP map")
 			errs = append(errs, err)
 			return deltatracker.IterActionNoOp
 		}
 		return deltatracker.IterActionUpdateDataplane
 	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if len(errs) > 0 {
 		return errs
 	}
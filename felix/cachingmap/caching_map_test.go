@@ -15,6 +15,7 @@
 package cachingmap_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -108,7 +109,7 @@ func TestCachingMap_SplitUpdateAndDelete(t *testing.T) {
 	// Shouldn't do anything until we hit apply.
 	Expect(mockMap.OpCount()).To(Equal(0))
 
-	err := cm.ApplyUpdatesOnly()
+	err := cm.ApplyUpdatesOnly(context.Background())
 	Expect(err).NotTo(HaveOccurred())
 	Expect(mockMap.Contents).To(Equal(map[string]string{
 		"1, 1": "1, 2, 4, 3", // No change
@@ -122,7 +123,7 @@ func TestCachingMap_SplitUpdateAndDelete(t *testing.T) {
 	Expect(mockMap.GetCount).To(Equal(0))
 	Expect(mockMap.LoadCount).To(Equal(1))
 
-	err = cm.ApplyDeletionsOnly()
+	err = cm.ApplyDeletionsOnly(context.Background())
 	Expect(err).NotTo(HaveOccurred())
 
 	Expect(mockMap.Contents).To(Equal(map[string]string{
@@ -143,6 +144,29 @@ func TestCachingMap_SplitUpdateAndDelete(t *testing.T) {
 	Expect(mockMap.OpCount()).To(Equal(preApplyOpCount))
 }
 
+// TestCachingMap_CancelledApply verifies that a cancelled apply leaves the changes pending.
+func TestCachingMap_CancelledApply(t *testing.T) {
+	mockMap, cm := setupCachingMapTest(t)
+	mockMap.Contents = map[string]string{
+		"1, 1": "1, 2, 4, 3",
+	}
+	cm.Desired().Set("1, 2", "1, 2, 3, 6")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Expect(cm.ApplyDeletionsOnly(ctx)).To(MatchError(context.Canceled))
+	Expect(cm.ApplyUpdatesOnly(ctx)).To(MatchError(context.Canceled))
+	Expect(mockMap.UpdateCount).To(Equal(0))
+	Expect(mockMap.DeleteCount).To(Equal(0))
+	_, ok := cm.Dataplane().Get("1, 1")
+	Expect(ok).To(BeTrue())
+
+	Expect(cm.ApplyAllChanges()).To(Succeed())
+	Expect(mockMap.Contents).To(Equal(map[string]string{
+		"1, 2": "1, 2, 3, 6",
+	}))
+}
+
 // TestCachingMap_ApplyAll mainline test using ApplyAll() to update the dataplane.
 func TestCachingMap_ApplyAll(t *testing.T) {
 	mockMap, cm := setupCachingMapTest(t)
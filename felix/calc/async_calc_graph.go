@@ -100,6 +100,9 @@ func NewAsyncCalcGraph(
 	healthAggregator *health.HealthAggregator,
 ) *AsyncCalcGraph {
 	eventSequencer := NewEventSequencer(conf)
+	if conf.BPFEnabled && conf.BPFPolicyBitmapsEnabled {
+		eventSequencer.EnablePolicyBitmaps()
+	}
 	g := &AsyncCalcGraph{
 		inputEvents:      make(chan interface{}, 10),
 		outputChannels:   outputChannels,
@@ -60,7 +60,7 @@ type endpointCallbacks interface {
 }
 
 type configCallbacks interface {
-	OnConfigUpdate(globalConfig, hostConfig map[string]string)
+	OnConfigUpdate(globalConfig, selectorConfig, hostConfig map[string]string)
 	OnDatastoreNotReady()
 }
 
@@ -123,6 +123,7 @@ type CalcGraph struct {
 	ruleScanner             *RuleScanner
 	serviceIndex            *serviceindex.ServiceIndex
 	ipsetMemberIndex        *labelindex.SelectorAndNamedPortIndex
+	egressSNATPoolResolver  *EgressSNATPoolResolver
 	hostIPPassthru          *DataplanePassthru
 	l3RouteResolver         *L3RouteResolver
 	vxlanResolver           *VXLANResolver
@@ -206,8 +207,12 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 	//              | Locally active policies/profiles
 	//             ...
 	//
+	// When policy is disabled, the active rules calculator is left unregistered so that no
+	// policies or profiles become active.
 	activeRulesCalc := NewActiveRulesCalculator()
-	activeRulesCalc.RegisterWith(localEndpointDispatcher, allUpdDispatcher)
+	if conf.PolicyEnabled() {
+		activeRulesCalc.RegisterWith(localEndpointDispatcher, allUpdDispatcher)
+	}
 	cg.activeRulesCalculator = activeRulesCalc
 
 	// The active rules calculator only figures out which rules are active, it doesn't extract
@@ -294,7 +299,12 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 	ruleScanner.OnIPSetActive = func(ipSet *IPSetData) {
 		log.WithField("ipSet", ipSet).Info("IPSet now active")
 		callbacks.OnIPSetAdded(ipSet.UniqueID(), ipSet.DataplaneProtocolType())
-		if ipSet.Service != "" {
+		if len(ipSet.Domains) > 0 {
+			// The members of domain IP sets are fixed, the dataplane resolves them.
+			for _, domain := range ipSet.Domains {
+				callbacks.OnIPSetMemberAdded(ipSet.UniqueID(), labelindex.IPSetMember{Domain: domain})
+			}
+		} else if ipSet.Service != "" {
 			serviceIndex.UpdateIPSet(ipSet.UniqueID(), ipSet.Service)
 		} else {
 			ipsetMemberIndex.UpdateIPSet(ipSet.UniqueID(), ipSet.Selector, ipSet.NamedPortProtocol, ipSet.NamedPort)
@@ -305,7 +315,7 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 		log.WithField("ipSet", ipSet).Info("IPSet now inactive")
 		if ipSet.Service != "" {
 			serviceIndex.DeleteIPSet(ipSet.UniqueID())
-		} else {
+		} else if len(ipSet.Domains) == 0 {
 			ipsetMemberIndex.DeleteIPSet(ipSet.UniqueID())
 		}
 		callbacks.OnIPSetRemoved(ipSet.UniqueID())
@@ -332,6 +342,25 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 	}
 	cg.ipsetMemberIndex = ipsetMemberIndex
 
+	// The egress SNAT pool resolver activates an IP set for the egress SNAT selector of each
+	// IP pool.  The IP sets are calculated by the IP set member index, like the IP sets of
+	// the rules' selectors.
+	egressSNATPoolResolver := NewEgressSNATPoolResolver(callbacks, ipsetMemberIndex)
+	egressSNATPoolResolver.RegisterWith(allUpdDispatcher)
+	cg.egressSNATPoolResolver = egressSNATPoolResolver
+
+	// The egress proxy IP set holds the workloads whose HTTP(S) egress the dataplane
+	// redirects to the egress proxy.
+	if conf.EgressProxyAddress != "" {
+		activateEgressProxyIPSet(callbacks, ipsetMemberIndex, conf.EgressProxySelector)
+	}
+
+	// The L7 proxy IP set holds the workloads whose inbound connections the BPF dataplane
+	// hands to the L7 proxy.
+	if conf.BPFEnabled {
+		activateL7ProxyIPSet(callbacks, ipsetMemberIndex, conf.L7ProxySelector)
+	}
+
 	// The endpoint policy resolver marries up the active policies with local endpoints and
 	// calculates the complete, ordered set of policies that apply to each endpoint.
 	//
@@ -359,6 +388,12 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 	polResolver.RegisterWith(allUpdDispatcher, localEndpointDispatcher)
 	// And hook its output to the callbacks.
 	polResolver.Callbacks = callbacks
+	if !conf.PolicyEnabled() {
+		// Without policy, all workload traffic is allowed by a single profile.
+		log.Info("Policy is disabled, allowing all traffic to and from workloads.")
+		polResolver.Callbacks = &policyDisabledEndpointFilter{Callbacks: callbacks}
+		activateAllowAllProfile(ruleScanner)
+	}
 	cg.policyResolver = polResolver
 
 	// Register for host IP updates.
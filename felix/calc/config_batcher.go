@@ -15,36 +15,50 @@
 package calc
 
 import (
+	"reflect"
+	"sort"
+
 	log "github.com/sirupsen/logrus"
 
 	"github.com/projectcalico/calico/felix/dispatcher"
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
 )
 
+// NodeSelectorConfigName is the name of the config parameter that holds the node selector of a
+// FelixConfiguration override.
+const NodeSelectorConfigName = "NodeSelector"
+
 type ConfigBatcher struct {
 	hostname        string
 	datastoreInSync bool
 	configDirty     bool
 	globalConfig    map[string]string
+	overrideConfig  map[string]map[string]string
 	hostConfig      map[string]string
+	nodeLabels      map[string]string
 	datastoreReady  bool
 	callbacks       configCallbacks
 }
 
 func NewConfigBatcher(hostname string, callbacks configCallbacks) *ConfigBatcher {
 	return &ConfigBatcher{
-		hostname:     hostname,
-		configDirty:  true,
-		globalConfig: make(map[string]string),
-		hostConfig:   make(map[string]string),
-		callbacks:    callbacks,
+		hostname:       hostname,
+		configDirty:    true,
+		globalConfig:   make(map[string]string),
+		overrideConfig: make(map[string]map[string]string),
+		hostConfig:     make(map[string]string),
+		callbacks:      callbacks,
 	}
 }
 
 func (cb *ConfigBatcher) RegisterWith(allUpdDispatcher *dispatcher.Dispatcher) {
 	allUpdDispatcher.Register(model.GlobalConfigKey{}, cb.OnUpdate)
+	allUpdDispatcher.Register(model.OverrideConfigKey{}, cb.OnUpdate)
 	allUpdDispatcher.Register(model.HostConfigKey{}, cb.OnUpdate)
+	allUpdDispatcher.Register(model.ResourceKey{}, cb.OnUpdate)
 	allUpdDispatcher.Register(model.ReadyFlagKey{}, cb.OnUpdate)
 	allUpdDispatcher.RegisterStatusHandler(cb.OnDatamodelStatus)
 }
@@ -82,6 +96,46 @@ func (cb *ConfigBatcher) OnUpdate(update api.Update) (filterOut bool) {
 			log.Debugf("Ignoring no-op global config update: %v", update)
 			return
 		}
+	case model.OverrideConfigKey:
+		override := cb.overrideConfig[key.OverrideName]
+		if value, ok := update.Value.(string); value != override[key.Name] {
+			log.Infof("Override config update: %v", update)
+			if ok {
+				if override == nil {
+					override = make(map[string]string)
+					cb.overrideConfig[key.OverrideName] = override
+				}
+				override[key.Name] = value
+			} else {
+				delete(override, key.Name)
+				if len(override) == 0 {
+					delete(cb.overrideConfig, key.OverrideName)
+				}
+			}
+			cb.configDirty = true
+		} else {
+			log.Debugf("Ignoring no-op override config update: %v", update)
+			return
+		}
+	case model.ResourceKey:
+		if key.Kind != libapiv3.KindNode || key.Name != cb.hostname {
+			return
+		}
+		var labels map[string]string
+		if node, ok := update.Value.(*libapiv3.Node); ok {
+			labels = node.Labels
+		}
+		if reflect.DeepEqual(labels, cb.nodeLabels) {
+			log.Debug("Ignoring update to this node that doesn't change its labels")
+			return
+		}
+		log.WithField("labels", labels).Info("Labels of this node updated")
+		cb.nodeLabels = labels
+		if len(cb.overrideConfig) == 0 {
+			// Only the override config depends on the labels.
+			return
+		}
+		cb.configDirty = true
 	case model.ReadyFlagKey:
 		if update.Value != true {
 			log.WithField("value", update.Value).Warn("Ready flag updated/deleted")
@@ -109,8 +163,9 @@ func (cb *ConfigBatcher) maybeSendCachedConfig() {
 	if !cb.configDirty || !cb.datastoreInSync {
 		return
 	}
-	log.Infof("Sending config update global: %v, host: %v.",
-		cb.globalConfig, cb.hostConfig)
+	selectorConfig := MergeNodeSelectorConfig(cb.overrideConfig, cb.nodeLabels)
+	log.Infof("Sending config update global: %v, node selector: %v, host: %v.",
+		cb.globalConfig, selectorConfig, cb.hostConfig)
 	globalConfigCopy := make(map[string]string)
 	hostConfigCopy := make(map[string]string)
 	for k, v := range cb.globalConfig {
@@ -122,6 +177,42 @@ func (cb *ConfigBatcher) maybeSendCachedConfig() {
 	if !cb.datastoreReady {
 		cb.callbacks.OnDatastoreNotReady()
 	}
-	cb.callbacks.OnConfigUpdate(globalConfigCopy, hostConfigCopy)
+	cb.callbacks.OnConfigUpdate(globalConfigCopy, selectorConfig, hostConfigCopy)
 	cb.configDirty = false
 }
+
+// MergeNodeSelectorConfig merges the config of the overrides whose node selector matches the
+// given node labels.  Where more than one override sets a parameter, the override with the
+// alphabetically-last name wins.  Overrides without a valid node selector are ignored.
+func MergeNodeSelectorConfig(overrides map[string]map[string]string, nodeLabels map[string]string) map[string]string {
+	var names []string
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make(map[string]string)
+	for _, name := range names {
+		config := overrides[name]
+		logCxt := log.WithField("override", name)
+		if config[NodeSelectorConfigName] == "" {
+			logCxt.Debug("Ignoring override config without a node selector")
+			continue
+		}
+		sel, err := selector.Parse(config[NodeSelectorConfigName])
+		if err != nil {
+			logCxt.WithError(err).Warn("Ignoring override config with an invalid node selector")
+			continue
+		}
+		if !sel.Evaluate(nodeLabels) {
+			continue
+		}
+		for k, v := range config {
+			if k == NodeSelectorConfigName {
+				continue
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
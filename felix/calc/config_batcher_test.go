@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 )
@@ -49,6 +50,25 @@ var _ = Describe("ConfigBatcher", func() {
 			},
 		})
 	}
+	sendOverrideUpdate := func(override, name string, value interface{}) {
+		cb.OnUpdate(api.Update{
+			KVPair: model.KVPair{
+				Key:   model.OverrideConfigKey{OverrideName: override, Name: name},
+				Value: value,
+			},
+		})
+	}
+	sendNodeUpdate := func(name string, labels map[string]string) {
+		node := libapiv3.NewNode()
+		node.Name = name
+		node.Labels = labels
+		cb.OnUpdate(api.Update{
+			KVPair: model.KVPair{
+				Key:   model.ResourceKey{Kind: libapiv3.KindNode, Name: name},
+				Value: node,
+			},
+		})
+	}
 	sendReady := func(ready interface{}) {
 		cb.OnUpdate(api.Update{
 			KVPair: model.KVPair{
@@ -75,6 +95,7 @@ var _ = Describe("ConfigBatcher", func() {
 			})
 			It("should emit one event", func() {
 				Expect(recorder.Updates).To(ConsistOf(configUpdate{
+					selector: map[string]string{},
 					host: map[string]string{
 						"foo": "bar",
 					},
@@ -92,6 +113,7 @@ var _ = Describe("ConfigBatcher", func() {
 				})
 				It("should emit one event", func() {
 					Expect(recorder.Updates).To(ConsistOf(configUpdate{
+						selector: map[string]string{},
 						host: map[string]string{
 							"foo": "biz",
 						},
@@ -110,7 +132,8 @@ var _ = Describe("ConfigBatcher", func() {
 				})
 				It("should emit one event", func() {
 					Expect(recorder.Updates).To(ConsistOf(configUpdate{
-						host: map[string]string{},
+						selector: map[string]string{},
+						host:     map[string]string{},
 						global: map[string]string{
 							"biff": "bop",
 						},
@@ -124,8 +147,9 @@ var _ = Describe("ConfigBatcher", func() {
 					})
 					It("should emit one event", func() {
 						Expect(recorder.Updates).To(ConsistOf(configUpdate{
-							host:   map[string]string{},
-							global: map[string]string{},
+							selector: map[string]string{},
+							host:     map[string]string{},
+							global:   map[string]string{},
 						}))
 						Expect(recorder.NotReady).To(BeFalse())
 					})
@@ -169,6 +193,60 @@ var _ = Describe("ConfigBatcher", func() {
 		})
 	})
 
+	Context("with node selector overrides", func() {
+		BeforeEach(func() {
+			sendGlobalUpdate("BPFMapSizeConntrack", "512000")
+			sendOverrideUpdate("gpu", "NodeSelector", "pool == 'gpu'")
+			sendOverrideUpdate("gpu", "BPFMapSizeConntrack", "1048576")
+			sendOverrideUpdate("gpu", "BPFMapSizeNATFrontend", "131072")
+			sendOverrideUpdate("gpu-large", "NodeSelector", "pool == 'gpu' && size == 'large'")
+			sendOverrideUpdate("gpu-large", "BPFMapSizeConntrack", "2097152")
+			sendOverrideUpdate("no-selector", "BPFMapSizeConntrack", "1")
+			sendReady(true)
+			cb.OnDatamodelStatus(api.InSync)
+			recorder.Reset()
+		})
+
+		It("should apply the overrides that match the labels of this node in order of name", func() {
+			sendNodeUpdate("myhost", map[string]string{"pool": "gpu", "size": "large"})
+			Expect(recorder.Updates).To(ConsistOf(configUpdate{
+				host: map[string]string{},
+				selector: map[string]string{
+					"BPFMapSizeConntrack":   "2097152",
+					"BPFMapSizeNATFrontend": "131072",
+				},
+				global: map[string]string{"BPFMapSizeConntrack": "512000"},
+			}))
+		})
+
+		It("should ignore the labels of other nodes", func() {
+			sendNodeUpdate("otherhost", map[string]string{"pool": "gpu"})
+			Expect(recorder.Updates).To(BeEmpty())
+		})
+
+		It("should remove the config of an override once this node no longer matches", func() {
+			sendNodeUpdate("myhost", map[string]string{"pool": "gpu"})
+			recorder.Reset()
+			sendNodeUpdate("myhost", map[string]string{"pool": "cpu"})
+			Expect(recorder.Updates).To(ConsistOf(configUpdate{
+				host:     map[string]string{},
+				selector: map[string]string{},
+				global:   map[string]string{"BPFMapSizeConntrack": "512000"},
+			}))
+		})
+
+		It("should remove the config of a deleted override", func() {
+			sendNodeUpdate("myhost", map[string]string{"pool": "gpu"})
+			recorder.Reset()
+			sendOverrideUpdate("gpu", "BPFMapSizeNATFrontend", nil)
+			Expect(recorder.Updates).To(ConsistOf(configUpdate{
+				host:     map[string]string{},
+				selector: map[string]string{"BPFMapSizeConntrack": "1048576"},
+				global:   map[string]string{"BPFMapSizeConntrack": "512000"},
+			}))
+		})
+	})
+
 	Context("after sending in-sync with no config", func() {
 		BeforeEach(func() {
 			cb.OnDatamodelStatus(api.InSync)
@@ -176,16 +254,18 @@ var _ = Describe("ConfigBatcher", func() {
 		It("should emit a not-ready and empty config", func() {
 			Expect(recorder.NotReady).To(BeTrue())
 			Expect(recorder.Updates).To(ConsistOf(configUpdate{
-				host:   map[string]string{},
-				global: map[string]string{},
+				selector: map[string]string{},
+				host:     map[string]string{},
+				global:   map[string]string{},
 			}))
 		})
 	})
 })
 
 type configUpdate struct {
-	host   map[string]string
-	global map[string]string
+	host     map[string]string
+	selector map[string]string
+	global   map[string]string
 }
 
 type configRecorder struct {
@@ -193,10 +273,11 @@ type configRecorder struct {
 	NotReady bool
 }
 
-func (cr *configRecorder) OnConfigUpdate(globalConfig, hostConfig map[string]string) {
+func (cr *configRecorder) OnConfigUpdate(globalConfig, selectorConfig, hostConfig map[string]string) {
 	cr.Updates = append(cr.Updates, configUpdate{
-		host:   hostConfig,
-		global: globalConfig,
+		host:     hostConfig,
+		selector: selectorConfig,
+		global:   globalConfig,
 	})
 }
 
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/labelindex"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+// egressProxyIPSetID is the ID of the IP set of the workloads whose HTTP(S) egress is
+// redirected to the egress proxy.  It must match rules.IPSetIDEgressProxy.
+const egressProxyIPSetID = "egress-proxy"
+
+// activateEgressProxyIPSet activates the IP set of the workloads that the
+// EgressProxySelector matches.  The selector is part of the Felix configuration, which
+// restarts Felix when it changes, so the IP set is never deactivated.
+func activateEgressProxyIPSet(callbacks ipSetUpdateCallbacks, ipSetIndex selectorIPSetIndex, selStr string) {
+	if selStr == "" {
+		return
+	}
+	sel, err := selector.Parse(selStr)
+	if err != nil {
+		logrus.WithError(err).WithField("selector", selStr).Error(
+			"Invalid EgressProxySelector, egress will not be redirected to the proxy.")
+		return
+	}
+	logrus.WithField("selector", sel.String()).Info("Egress proxy IP set now active")
+	callbacks.OnIPSetAdded(egressProxyIPSetID, proto.IPSetUpdate_IP)
+	ipSetIndex.UpdateIPSet(egressProxyIPSetID, sel, labelindex.ProtocolNone, "")
+}
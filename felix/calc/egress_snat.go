@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/dispatcher"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/labelindex"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/hash"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+type selectorIPSetIndex interface {
+	UpdateIPSet(ipSetID string, sel selector.Selector, namedPortProtocol labelindex.IPSetPortProtocol, namedPort string)
+	DeleteIPSet(setID string)
+}
+
+// EgressSNATPoolResolver is a Calculation Graph component that watches IP pool updates and,
+// for each pool with an egress SNAT selector, activates an IP set of the workloads that the
+// selector matches.  The dataplane source NATs the traffic from the members of that IP set
+// to the node's address in the pool.
+//
+// The IP set ID covers the pool's CIDR and selector so that a selector change replaces
+// the IP set rather than updating it in place.
+type EgressSNATPoolResolver struct {
+	callbacks  ipSetUpdateCallbacks
+	ipSetIndex selectorIPSetIndex
+
+	// Active IP set ID by pool CIDR.
+	poolIPSetIDs map[ip.CIDR]string
+}
+
+func NewEgressSNATPoolResolver(callbacks ipSetUpdateCallbacks, ipSetIndex selectorIPSetIndex) *EgressSNATPoolResolver {
+	return &EgressSNATPoolResolver{
+		callbacks:    callbacks,
+		ipSetIndex:   ipSetIndex,
+		poolIPSetIDs: map[ip.CIDR]string{},
+	}
+}
+
+func (r *EgressSNATPoolResolver) RegisterWith(dispatcher *dispatcher.Dispatcher) {
+	dispatcher.Register(model.IPPoolKey{}, r.OnPoolUpdate)
+}
+
+func (r *EgressSNATPoolResolver) OnPoolUpdate(update api.Update) (filterOut bool) {
+	key := update.Key.(model.IPPoolKey)
+	cidr := ip.CIDRFromCalicoNet(key.CIDR)
+
+	newID := ""
+	var sel selector.Selector
+	if pool, ok := update.Value.(*model.IPPool); ok {
+		newID, sel = egressSNATIPSet(pool)
+	}
+
+	oldID := r.poolIPSetIDs[cidr]
+	if oldID == newID {
+		return
+	}
+
+	if oldID != "" {
+		logrus.WithFields(logrus.Fields{"pool": cidr, "ipSetID": oldID}).Info("Egress SNAT IP set now inactive")
+		r.ipSetIndex.DeleteIPSet(oldID)
+		r.callbacks.OnIPSetRemoved(oldID)
+		delete(r.poolIPSetIDs, cidr)
+	}
+	if newID != "" {
+		logrus.WithFields(logrus.Fields{"pool": cidr, "ipSetID": newID}).Info("Egress SNAT IP set now active")
+		r.callbacks.OnIPSetAdded(newID, proto.IPSetUpdate_IP)
+		r.ipSetIndex.UpdateIPSet(newID, sel, labelindex.ProtocolNone, "")
+		r.poolIPSetIDs[cidr] = newID
+	}
+	return
+}
+
+// egressSNATIPSet returns the ID and selector of the IP set of the workloads that are source
+// NATed to the node's address in the pool, or "" if the pool is not an egress SNAT pool.
+func egressSNATIPSet(pool *model.IPPool) (string, selector.Selector) {
+	if pool.EgressSNATSelector == "" {
+		return "", nil
+	}
+	sel, err := selector.Parse(pool.EgressSNATSelector)
+	if err != nil {
+		// Should have been caught by validation.
+		logrus.WithError(err).WithField("pool", pool.CIDR).Warn("Invalid egress SNAT selector, ignoring.")
+		return "", nil
+	}
+	return hash.MakeUniqueID("snat", pool.CIDR.String()+"|"+sel.String()), sel
+}
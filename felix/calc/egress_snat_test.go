@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/labelindex"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+type egressSNATRecorder struct {
+	active  map[string]string
+	added   []string
+	removed []string
+}
+
+func (r *egressSNATRecorder) OnIPSetAdded(setID string, ipSetType proto.IPSetUpdate_IPSetType) {
+	Expect(ipSetType).To(Equal(proto.IPSetUpdate_IP))
+	r.added = append(r.added, setID)
+}
+
+func (r *egressSNATRecorder) OnIPSetRemoved(setID string) {
+	r.removed = append(r.removed, setID)
+}
+
+func (r *egressSNATRecorder) OnIPSetMemberAdded(string, labelindex.IPSetMember)   {}
+func (r *egressSNATRecorder) OnIPSetMemberRemoved(string, labelindex.IPSetMember) {}
+
+func (r *egressSNATRecorder) UpdateIPSet(ipSetID string, sel selector.Selector, _ labelindex.IPSetPortProtocol, _ string) {
+	r.active[ipSetID] = sel.String()
+}
+
+func (r *egressSNATRecorder) DeleteIPSet(setID string) {
+	Expect(r.active).To(HaveKey(setID))
+	delete(r.active, setID)
+}
+
+var _ = Describe("EgressSNATPoolResolver", func() {
+	var (
+		rec      *egressSNATRecorder
+		resolver *EgressSNATPoolResolver
+		key      model.IPPoolKey
+	)
+
+	BeforeEach(func() {
+		rec = &egressSNATRecorder{active: map[string]string{}}
+		resolver = NewEgressSNATPoolResolver(rec, rec)
+		key = model.IPPoolKey{CIDR: net.MustParseCIDR("192.0.2.0/24")}
+	})
+
+	update := func(sel string) {
+		kv := model.KVPair{Key: key}
+		if sel != "-" {
+			kv.Value = &model.IPPool{CIDR: key.CIDR, EgressSNATSelector: sel}
+		}
+		resolver.OnPoolUpdate(api.Update{KVPair: kv})
+	}
+
+	It("should ignore pools without a selector", func() {
+		update("")
+		Expect(rec.added).To(BeEmpty())
+		Expect(rec.active).To(BeEmpty())
+	})
+
+	It("should activate and remove the IP set of the selector", func() {
+		update("projectcalico.org/namespace == 'a'")
+		Expect(rec.added).To(HaveLen(1))
+		id := rec.added[0]
+		Expect(rec.active).To(Equal(map[string]string{id: `projectcalico.org/namespace == "a"`}))
+
+		pool := &model.IPPool{CIDR: key.CIDR, EgressSNATSelector: "projectcalico.org/namespace == 'a'"}
+		poolID, _ := egressSNATIPSet(pool)
+		Expect(poolID).To(Equal(id), "event sequencer and resolver must agree on the ID")
+
+		By("ignoring an update that does not change the selector")
+		update("projectcalico.org/namespace == \"a\"")
+		Expect(rec.added).To(HaveLen(1))
+		Expect(rec.removed).To(BeEmpty())
+
+		By("replacing the IP set when the selector changes")
+		update("projectcalico.org/namespace == 'b'")
+		Expect(rec.removed).To(Equal([]string{id}))
+		Expect(rec.added).To(HaveLen(2))
+		Expect(rec.added[1]).NotTo(Equal(id))
+		Expect(rec.active).To(HaveLen(1))
+		Expect(rec.active).To(HaveKey(rec.added[1]))
+
+		By("removing the IP set with the pool")
+		update("-")
+		Expect(rec.removed).To(Equal([]string{id, rec.added[1]}))
+		Expect(rec.active).To(BeEmpty())
+	})
+
+	It("should ignore an invalid selector", func() {
+		update("not a selector (")
+		Expect(rec.added).To(BeEmpty())
+	})
+})
@@ -250,6 +250,9 @@ func (c *EncapsulationCalculator) removePool(cidr string) {
 }
 
 func (c *EncapsulationCalculator) IPIPEnabled() bool {
+	if c.config != nil && !c.config.NetworkingEnabled() {
+		return false
+	}
 	if c.config != nil && c.config.IpInIpEnabled != nil {
 		return *c.config.IpInIpEnabled
 	}
@@ -258,6 +261,9 @@ func (c *EncapsulationCalculator) IPIPEnabled() bool {
 }
 
 func (c *EncapsulationCalculator) VXLANEnabled() bool {
+	if c.config != nil && !c.config.NetworkingEnabled() {
+		return false
+	}
 	if c.config != nil && c.config.VXLANEnabled != nil {
 		return *c.config.VXLANEnabled
 	}
@@ -266,5 +272,8 @@ func (c *EncapsulationCalculator) VXLANEnabled() bool {
 }
 
 func (c *EncapsulationCalculator) VXLANEnabledV6() bool {
+	if c.config != nil && !c.config.NetworkingEnabled() {
+		return false
+	}
 	return len(c.vxlanPoolsv6) > 0
 }
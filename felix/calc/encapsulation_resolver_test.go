@@ -224,6 +224,16 @@ var _ = Describe("EncapsulationCalculator", func() {
 				nil,
 				false, true, false),
 		)
+		It("should disable all encapsulation in PolicyOnly mode", func() {
+			conf.OperationMode = "PolicyOnly"
+			conf.IpInIpEnabled = &t
+			conf.VXLANEnabled = &t
+			err := encapsulationCalculator.handlePool(*getAPIPool("fe80::0/122", apiv3.IPIPModeNever, apiv3.VXLANModeAlways))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encapsulationCalculator.IPIPEnabled()).To(BeFalse())
+			Expect(encapsulationCalculator.VXLANEnabled()).To(BeFalse())
+			Expect(encapsulationCalculator.VXLANEnabledV6()).To(BeFalse())
+		})
 	})
 	Describe("Invalid IPIPMode and/or VXLANMode", func() {
 		err := encapsulationCalculator.handlePool(*getAPIPool("192.168.11.0/24", "", ""))
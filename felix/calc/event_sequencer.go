@@ -69,6 +69,7 @@ type EventSequencer struct {
 	pendingIPPoolDeletes         set.Set[ip.CIDR]
 	pendingNotReady              bool
 	pendingGlobalConfig          map[string]string
+	pendingSelectorConfig        map[string]string
 	pendingHostConfig            map[string]string
 	pendingServiceAccountUpdates map[proto.ServiceAccountID]*proto.ServiceAccountUpdate
 	pendingServiceAccountDeletes set.Set[proto.ServiceAccountID]
@@ -101,6 +102,9 @@ type EventSequencer struct {
 	sentWireguardV6     set.Set[string]
 	sentServices        set.Set[serviceID]
 
+	// Bit indexes of the active policies, nil unless policy bitmaps are enabled.
+	policyBitmaps *policyBitmaps
+
 	Callback EventHandler
 }
 
@@ -257,8 +261,9 @@ func (buf *EventSequencer) flushReadyFlag() {
 
 type DatastoreNotReady struct{}
 
-func (buf *EventSequencer) OnConfigUpdate(globalConfig, hostConfig map[string]string) {
+func (buf *EventSequencer) OnConfigUpdate(globalConfig, selectorConfig, hostConfig map[string]string) {
 	buf.pendingGlobalConfig = globalConfig
+	buf.pendingSelectorConfig = selectorConfig
 	buf.pendingHostConfig = hostConfig
 }
 
@@ -267,27 +272,39 @@ func (buf *EventSequencer) flushConfigUpdate() {
 		return
 	}
 	logCxt := log.WithFields(log.Fields{
-		"global": buf.pendingGlobalConfig,
-		"host":   buf.pendingHostConfig,
+		"global":       buf.pendingGlobalConfig,
+		"nodeSelector": buf.pendingSelectorConfig,
+		"host":         buf.pendingHostConfig,
 	})
 	logCxt.Info("Possible config update.")
 	globalChanged, err := buf.config.UpdateFrom(buf.pendingGlobalConfig, config.DatastoreGlobal)
 	if err != nil {
 		logCxt.WithError(err).Panic("Failed to parse config update")
 	}
+	selectorChanged, err := buf.config.UpdateFrom(buf.pendingSelectorConfig, config.DatastoreNodeSelector)
+	if err != nil {
+		logCxt.WithError(err).Panic("Failed to parse config update")
+	}
 	hostChanged, err := buf.config.UpdateFrom(buf.pendingHostConfig, config.DatastorePerHost)
 	if err != nil {
 		logCxt.WithError(err).Panic("Failed to parse config update")
 	}
-	if globalChanged || hostChanged {
+	if globalChanged || selectorChanged || hostChanged {
 		rawConfig := buf.config.RawValues()
 		log.WithField("merged", rawConfig).Info("Config changed. Sending ConfigUpdate message.")
 		buf.Callback(buf.config.ToConfigUpdate())
 	}
 	buf.pendingGlobalConfig = nil
+	buf.pendingSelectorConfig = nil
 	buf.pendingHostConfig = nil
 }
 
+// EnablePolicyBitmaps makes the sequencer give each active policy a bit index and send the
+// bitmap of its policies with each endpoint.
+func (buf *EventSequencer) EnablePolicyBitmaps() {
+	buf.policyBitmaps = newPolicyBitmaps()
+}
+
 func (buf *EventSequencer) OnPolicyActive(key model.PolicyKey, rules *ParsedRules) {
 	buf.pendingPolicyDeletes.Discard(key)
 	buf.pendingPolicyUpdates[key] = rules
@@ -295,7 +312,11 @@ func (buf *EventSequencer) OnPolicyActive(key model.PolicyKey, rules *ParsedRule
 
 func (buf *EventSequencer) flushPolicyUpdates() {
 	for key, rules := range buf.pendingPolicyUpdates {
-		buf.Callback(ParsedRulesToActivePolicyUpdate(key, rules))
+		upd := ParsedRulesToActivePolicyUpdate(key, rules)
+		if buf.policyBitmaps != nil {
+			upd.BitmapIndex = buf.policyBitmaps.assign(key)
+		}
+		buf.Callback(upd)
 		buf.sentPolicies.Add(key)
 		delete(buf.pendingPolicyUpdates, key)
 	}
@@ -340,6 +361,9 @@ func (buf *EventSequencer) flushPolicyDeletes() {
 			},
 		})
 		buf.sentPolicies.Discard(item)
+		if buf.policyBitmaps != nil {
+			buf.policyBitmaps.release(item)
+		}
 		return set.RemoveItem
 	})
 }
@@ -407,6 +431,7 @@ func ModelWorkloadEndpointToProto(ep *model.WorkloadEndpoint, tiers []*proto.Tie
 		Ipv6Nat:                    natsToProtoNatInfo(ep.IPv6NAT),
 		AllowSpoofedSourcePrefixes: netsToStrings(ep.AllowSpoofedSourcePrefixes),
 		Annotations:                ep.Annotations,
+		PortMappings:               portMappingsToProto(ep.PortMappings),
 	}
 }
 
@@ -449,21 +474,29 @@ func (buf *EventSequencer) flushEndpointTierUpdates() {
 		switch key := key.(type) {
 		case model.WorkloadEndpointKey:
 			wlep := endpoint.(*model.WorkloadEndpoint)
+			protoEp := ModelWorkloadEndpointToProto(wlep, tiers)
+			if buf.policyBitmaps != nil {
+				protoEp.PolicyBitmap = buf.policyBitmaps.endpointBitmap(tiers)
+			}
 			buf.Callback(&proto.WorkloadEndpointUpdate{
 				Id: &proto.WorkloadEndpointID{
 					OrchestratorId: key.OrchestratorID,
 					WorkloadId:     key.WorkloadID,
 					EndpointId:     key.EndpointID,
 				},
-				Endpoint: ModelWorkloadEndpointToProto(wlep, tiers),
+				Endpoint: protoEp,
 			})
 		case model.HostEndpointKey:
 			hep := endpoint.(*model.HostEndpoint)
+			protoEp := ModelHostEndpointToProto(hep, tiers, untrackedTiers, preDNATTiers, forwardTiers)
+			if buf.policyBitmaps != nil {
+				protoEp.PolicyBitmap = buf.policyBitmaps.endpointBitmap(tiers, untrackedTiers, preDNATTiers, forwardTiers)
+			}
 			buf.Callback(&proto.HostEndpointUpdate{
 				Id: &proto.HostEndpointID{
 					EndpointId: key.EndpointID,
 				},
-				Endpoint: ModelHostEndpointToProto(hep, tiers, untrackedTiers, preDNATTiers, forwardTiers),
+				Endpoint: protoEp,
 			})
 		}
 		// Record that we've sent this endpoint.
@@ -656,13 +689,15 @@ func (buf *EventSequencer) OnIPPoolUpdate(key model.IPPoolKey, pool *model.IPPoo
 
 func (buf *EventSequencer) flushIPPoolUpdates() {
 	for key, pool := range buf.pendingIPPoolUpdates {
+		egressSNATIPSetID, _ := egressSNATIPSet(pool)
 		buf.Callback(&proto.IPAMPoolUpdate{
 			Id: cidrToIPPoolID(key),
 			Pool: &proto.IPAMPool{
-				Cidr:       pool.CIDR.String(),
-				Masquerade: pool.Masquerade,
-				IpipMode:   string(pool.IPIPMode),
-				VxlanMode:  string(pool.VXLANMode),
+				Cidr:              pool.CIDR.String(),
+				Masquerade:        pool.Masquerade,
+				IpipMode:          string(pool.IPIPMode),
+				VxlanMode:         string(pool.VXLANMode),
+				EgressSnatIpSetId: egressSNATIPSetID,
 			},
 		})
 		buf.sentIPPools.Add(key)
@@ -779,6 +814,9 @@ func (buf *EventSequencer) flushAddedIPSets() {
 }
 
 func memberToProto(member labelindex.IPSetMember) string {
+	if member.Domain != "" {
+		return member.Domain
+	}
 	switch member.Protocol {
 	case labelindex.ProtocolNone:
 		return member.CIDR.String()
@@ -1194,6 +1232,22 @@ func ipsToStrings(ips []net.IP) []string {
 	return output
 }
 
+func portMappingsToProto(mappings []model.PortMapping) []*proto.PortMapping {
+	if len(mappings) == 0 {
+		return nil
+	}
+	protoMappings := make([]*proto.PortMapping, len(mappings))
+	for ii, m := range mappings {
+		protoMappings[ii] = &proto.PortMapping{
+			Protocol:      m.Protocol.String(),
+			HostPort:      int32(m.HostPort),
+			ContainerPort: int32(m.ContainerPort),
+			HostIp:        m.HostIP,
+		}
+	}
+	return protoMappings
+}
+
 func natsToProtoNatInfo(nats []model.IPNAT) []*proto.NatInfo {
 	protoNats := make([]*proto.NatInfo, len(nats))
 	for ii, nat := range nats {
@@ -22,8 +22,11 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
 	"github.com/projectcalico/calico/felix/calc"
 	"github.com/projectcalico/calico/felix/config"
+	"github.com/projectcalico/calico/felix/labelindex"
 	"github.com/projectcalico/calico/felix/proto"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
@@ -79,6 +82,36 @@ var _ = DescribeTable("ModelWorkloadEndpointToProto",
 		Ipv6Nat:                    []*proto.NatInfo{},
 		AllowSpoofedSourcePrefixes: []string{"8.8.8.8/32"},
 	}),
+	Entry("workload endpoint with host ports", model.WorkloadEndpoint{
+		State: "up",
+		Name:  "bill",
+		PortMappings: []model.PortMapping{
+			{
+				Protocol:      numorstring.ProtocolFromStringV1("tcp"),
+				HostPort:      8080,
+				ContainerPort: 80,
+			},
+			{
+				Protocol:      numorstring.ProtocolFromStringV1("udp"),
+				HostPort:      5353,
+				ContainerPort: 53,
+				HostIP:        "10.0.0.1",
+			},
+		},
+	}, proto.WorkloadEndpoint{
+		State:                      "up",
+		Name:                       "bill",
+		Ipv4Nets:                   []string{},
+		Ipv6Nets:                   []string{},
+		Tiers:                      []*proto.TierInfo{},
+		Ipv4Nat:                    []*proto.NatInfo{},
+		Ipv6Nat:                    []*proto.NatInfo{},
+		AllowSpoofedSourcePrefixes: []string{},
+		PortMappings: []*proto.PortMapping{
+			{Protocol: "tcp", HostPort: 8080, ContainerPort: 80},
+			{Protocol: "udp", HostPort: 5353, ContainerPort: 53, HostIp: "10.0.0.1"},
+		},
+	}),
 )
 
 var _ = Describe("ParsedRulesToActivePolicyUpdate", func() {
@@ -370,6 +403,24 @@ var _ = Describe("IPPool update/remove", func() {
 	})
 })
 
+var _ = Describe("Domain IP set update", func() {
+	It("should send the domains as the members of the IP set", func() {
+		uut := calc.NewEventSequencer(&dummyConfigInterface{})
+		recorder := &dataplaneRecorder{}
+		uut.Callback = recorder.record
+
+		uut.OnIPSetAdded("d:1234", proto.IPSetUpdate_DOMAIN)
+		uut.OnIPSetMemberAdded("d:1234", labelindex.IPSetMember{Domain: "*.github.com"})
+		uut.Flush()
+
+		Expect(recorder.Messages).To(ConsistOf(&proto.IPSetUpdate{
+			Id:      "d:1234",
+			Type:    proto.IPSetUpdate_DOMAIN,
+			Members: []string{"*.github.com"},
+		}))
+	})
+})
+
 type dataplaneRecorder struct {
 	Messages []interface{}
 }
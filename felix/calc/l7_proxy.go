@@ -0,0 +1,45 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/labelindex"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+// l7ProxyIPSetID is the ID of the IP set of the workloads whose inbound connections are
+// redirected to the L7 proxy.  It must match rules.IPSetIDL7Proxy.
+const l7ProxyIPSetID = "l7-proxy"
+
+// activateL7ProxyIPSet activates the IP set of the workloads that the L7ProxySelector
+// matches.  Like the egress proxy IP set, it is never deactivated since a change to the
+// selector restarts Felix.
+func activateL7ProxyIPSet(callbacks ipSetUpdateCallbacks, ipSetIndex selectorIPSetIndex, selStr string) {
+	if selStr == "" {
+		return
+	}
+	sel, err := selector.Parse(selStr)
+	if err != nil {
+		logrus.WithError(err).WithField("selector", selStr).Error(
+			"Invalid L7ProxySelector, connections will not be redirected to the L7 proxy.")
+		return
+	}
+	logrus.WithField("selector", sel.String()).Info("L7 proxy IP set now active")
+	callbacks.OnIPSetAdded(l7ProxyIPSetID, proto.IPSetUpdate_IP)
+	ipSetIndex.UpdateIPSet(l7ProxyIPSetID, sel, labelindex.ProtocolNone, "")
+}
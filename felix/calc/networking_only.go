@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cresources "github.com/projectcalico/calico/libcalico-go/lib/resources"
+)
+
+// allowAllProfileKey is the profile that every workload endpoint gets when policy is disabled.
+var allowAllProfileKey = model.ProfileRulesKey{ProfileKey: model.ProfileKey{Name: cresources.DefaultAllowProfileName}}
+
+// activateAllowAllProfile sends the profile that allows all traffic to and from the workload
+// endpoints when Felix runs without policy.  Nothing else activates profiles in that mode, so
+// it is never deactivated.
+func activateAllowAllProfile(ruleScanner *RuleScanner) {
+	allowAll := []model.Rule{{Action: "allow"}}
+	ruleScanner.OnProfileActive(allowAllProfileKey, &model.ProfileRules{
+		InboundRules:  allowAll,
+		OutboundRules: allowAll,
+	})
+}
+
+// policyDisabledEndpointFilter sits between the policy resolver and the dataplane when Felix
+// runs without policy.  It gives each workload endpoint the allow-all profile in place of its
+// own profiles, and drops host endpoints, which only exist to have policy applied.
+type policyDisabledEndpointFilter struct {
+	Callbacks PolicyResolverCallbacks
+}
+
+func (f *policyDisabledEndpointFilter) OnEndpointTierUpdate(key model.Key, endpoint interface{}, _ []TierInfo) {
+	if _, ok := key.(model.WorkloadEndpointKey); !ok {
+		logrus.WithField("key", key).Debug("Ignoring host endpoint, policy is disabled.")
+		return
+	}
+	if wep, ok := endpoint.(*model.WorkloadEndpoint); ok {
+		wepCopy := *wep
+		wepCopy.ProfileIDs = []string{allowAllProfileKey.Name}
+		endpoint = &wepCopy
+	}
+	f.Callbacks.OnEndpointTierUpdate(key, endpoint, nil)
+}
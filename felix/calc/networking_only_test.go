@@ -0,0 +1,44 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+func TestPolicyDisabledEndpointFilter(t *testing.T) {
+	recorder := newPolicyResolverRecorder()
+	filter := &policyDisabledEndpointFilter{Callbacks: recorder}
+
+	wepKey := model.WorkloadEndpointKey{Hostname: "host", WorkloadID: "wl", EndpointID: "ep"}
+	wep := &model.WorkloadEndpoint{Name: "cali1234", ProfileIDs: []string{"prof-1"}}
+	filter.OnEndpointTierUpdate(wepKey, wep, []TierInfo{{Name: "default"}})
+	filter.OnEndpointTierUpdate(model.HostEndpointKey{Hostname: "host", EndpointID: "eth0"}, &model.HostEndpoint{}, nil)
+
+	expected := []policyResolverUpdate{{
+		Key:      wepKey,
+		Endpoint: &model.WorkloadEndpoint{Name: "cali1234", ProfileIDs: []string{allowAllProfileKey.Name}},
+	}}
+	if diff := cmp.Diff(expected, recorder.updates); diff != "" {
+		t.Error("Unexpected updates from filter:", diff)
+	}
+	if wep.ProfileIDs[0] != "prof-1" {
+		t.Error("Filter modified the endpoint it was passed")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// MaxPolicyBitmapBits is the size of an endpoint policy bitmap in bits.  It must match the
+// size of the values of the BPF policy bitmap map.  Bit 0 is never used so that a zero index
+// means "no index".
+const MaxPolicyBitmapBits = 8192
+
+// policyBitmaps gives each active policy a bit in the endpoint policy bitmaps.  Indexes of
+// removed policies are reused, lowest first, to keep the bitmaps short.
+type policyBitmaps struct {
+	indexes   map[model.PolicyKey]uint32
+	free      []uint32
+	next      uint32
+	exhausted bool
+}
+
+func newPolicyBitmaps() *policyBitmaps {
+	return &policyBitmaps{
+		indexes: map[model.PolicyKey]uint32{},
+		next:    1,
+	}
+}
+
+// assign returns the index of the policy, allocating one if needed.  It returns 0 if all the
+// indexes are in use.
+func (b *policyBitmaps) assign(key model.PolicyKey) uint32 {
+	if idx, ok := b.indexes[key]; ok {
+		return idx
+	}
+	var idx uint32
+	if n := len(b.free); n > 0 {
+		idx = b.free[n-1]
+		b.free = b.free[:n-1]
+	} else if b.next < MaxPolicyBitmapBits {
+		idx = b.next
+		b.next++
+	} else {
+		if !b.exhausted {
+			logrus.WithField("limit", MaxPolicyBitmapBits-1).Warn(
+				"Too many active policies for the policy bitmaps, endpoints with policies " +
+					"beyond the limit fall back to their per-endpoint programs.")
+			b.exhausted = true
+		}
+		return 0
+	}
+	b.indexes[key] = idx
+	return idx
+}
+
+// release frees the index of the policy, if it has one.
+func (b *policyBitmaps) release(key model.PolicyKey) {
+	idx, ok := b.indexes[key]
+	if !ok {
+		return
+	}
+	delete(b.indexes, key)
+	// Keep the free list sorted in descending order so that the lowest index is reused first.
+	i := len(b.free)
+	b.free = append(b.free, idx)
+	for i > 0 && b.free[i-1] < idx {
+		b.free[i] = b.free[i-1]
+		i--
+	}
+	b.free[i] = idx
+	b.exhausted = false
+}
+
+// endpointBitmap returns the bitmap of the policies in the tiers, trimmed of trailing zero
+// bytes.  It returns nil if any of the policies has no index, in which case the dataplane
+// falls back to evaluating the endpoint's policies without the bitmap.
+func (b *policyBitmaps) endpointBitmap(tiers ...[]*proto.TierInfo) []byte {
+	var bitmap []byte
+	for _, ts := range tiers {
+		for _, t := range ts {
+			for _, pols := range [][]string{t.IngressPolicies, t.EgressPolicies} {
+				for _, name := range pols {
+					idx := b.indexes[model.PolicyKey{Name: name}]
+					if idx == 0 {
+						return nil
+					}
+					byteIdx := int(idx / 8)
+					for len(bitmap) <= byteIdx {
+						bitmap = append(bitmap, 0)
+					}
+					bitmap[byteIdx] |= 1 << (idx % 8)
+				}
+			}
+		}
+	}
+	return bitmap
+}
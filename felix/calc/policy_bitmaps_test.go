@@ -0,0 +1,179 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+var _ = Describe("policyBitmaps", func() {
+	var b *policyBitmaps
+
+	BeforeEach(func() {
+		b = newPolicyBitmaps()
+	})
+
+	polKey := func(name string) model.PolicyKey {
+		return model.PolicyKey{Name: name}
+	}
+
+	It("should assign stable indexes starting from 1", func() {
+		Expect(b.assign(polKey("a"))).To(BeNumerically("==", 1))
+		Expect(b.assign(polKey("b"))).To(BeNumerically("==", 2))
+		Expect(b.assign(polKey("a"))).To(BeNumerically("==", 1))
+	})
+
+	It("should reuse the lowest released index first", func() {
+		for _, n := range []string{"a", "b", "c", "d"} {
+			b.assign(polKey(n))
+		}
+		b.release(polKey("b"))
+		b.release(polKey("d"))
+		b.release(polKey("c"))
+		Expect(b.assign(polKey("e"))).To(BeNumerically("==", 2))
+		Expect(b.assign(polKey("f"))).To(BeNumerically("==", 3))
+		Expect(b.assign(polKey("g"))).To(BeNumerically("==", 4))
+		Expect(b.assign(polKey("h"))).To(BeNumerically("==", 5))
+	})
+
+	It("should return 0 once all indexes are in use", func() {
+		for i := 1; i < MaxPolicyBitmapBits; i++ {
+			Expect(b.assign(polKey(fmt.Sprint(i)))).To(BeNumerically("==", i))
+		}
+		Expect(b.assign(polKey("extra"))).To(BeZero())
+
+		b.release(polKey("7"))
+		Expect(b.assign(polKey("extra"))).To(BeNumerically("==", 7))
+	})
+
+	It("should calculate trimmed endpoint bitmaps", func() {
+		b.assign(polKey("a"))
+		b.assign(polKey("b"))
+		for i := 0; i < 7; i++ {
+			b.assign(polKey(fmt.Sprint(i)))
+		}
+		b.assign(polKey("c"))
+
+		Expect(b.endpointBitmap(nil)).To(BeNil())
+		Expect(b.endpointBitmap([]*proto.TierInfo{{
+			Name:            "default",
+			IngressPolicies: []string{"a"},
+			EgressPolicies:  []string{"b"},
+		}})).To(Equal([]byte{0x6}))
+		Expect(b.endpointBitmap(
+			[]*proto.TierInfo{{Name: "default", IngressPolicies: []string{"a"}}},
+			[]*proto.TierInfo{{Name: "default", EgressPolicies: []string{"c"}}},
+		)).To(Equal([]byte{0x2, 0x4}))
+
+		By("returning no bitmap if a policy has no index")
+		Expect(b.endpointBitmap([]*proto.TierInfo{{
+			Name:            "default",
+			IngressPolicies: []string{"a", "unknown"},
+		}})).To(BeNil())
+	})
+})
+
+var _ = Describe("EventSequencer policy bitmaps", func() {
+	var (
+		uut      *EventSequencer
+		messages []interface{}
+		wepKey   model.WorkloadEndpointKey
+	)
+
+	BeforeEach(func() {
+		uut = NewEventSequencer(nil)
+		uut.EnablePolicyBitmaps()
+		messages = nil
+		uut.Callback = func(msg interface{}) {
+			messages = append(messages, msg)
+		}
+		wepKey = model.WorkloadEndpointKey{
+			Hostname:       "host",
+			OrchestratorID: "k8s",
+			WorkloadID:     "ns/pod",
+			EndpointID:     "eth0",
+		}
+	})
+
+	activate := func(name string) {
+		uut.OnPolicyActive(model.PolicyKey{Name: name}, &ParsedRules{})
+	}
+
+	tierWith := func(names ...string) []TierInfo {
+		ti := TierInfo{Name: "default"}
+		for _, n := range names {
+			ti.OrderedPolicies = append(ti.OrderedPolicies, PolKV{
+				Key:   model.PolicyKey{Name: n},
+				Value: &model.Policy{Types: []string{"ingress"}},
+			})
+		}
+		return []TierInfo{ti}
+	}
+
+	It("should send the indexes and the endpoint bitmaps", func() {
+		activate("a")
+		activate("b")
+		uut.OnEndpointTierUpdate(wepKey, &model.WorkloadEndpoint{Name: "cali1"}, tierWith("b"))
+		uut.Flush()
+
+		indexes := map[string]uint32{}
+		var wep *proto.WorkloadEndpoint
+		for _, m := range messages {
+			switch m := m.(type) {
+			case *proto.ActivePolicyUpdate:
+				indexes[m.Id.Name] = m.BitmapIndex
+			case *proto.WorkloadEndpointUpdate:
+				wep = m.Endpoint
+			}
+		}
+		Expect(indexes).To(HaveLen(2))
+		Expect(indexes["a"]).NotTo(Equal(indexes["b"]))
+		Expect(wep).NotTo(BeNil())
+		idx := indexes["b"]
+		Expect(wep.PolicyBitmap).To(HaveLen(int(idx/8) + 1))
+		Expect(wep.PolicyBitmap[idx/8]).To(Equal(byte(1 << (idx % 8))))
+
+		By("releasing the index of a removed policy")
+		uut.OnPolicyInactive(model.PolicyKey{Name: "b"})
+		uut.OnEndpointTierUpdate(wepKey, &model.WorkloadEndpoint{Name: "cali1"}, tierWith())
+		uut.Flush()
+		messages = nil
+		activate("c")
+		uut.Flush()
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].(*proto.ActivePolicyUpdate).BitmapIndex).To(Equal(idx))
+	})
+
+	It("should not send bitmaps when disabled", func() {
+		uut.policyBitmaps = nil
+		activate("a")
+		uut.OnEndpointTierUpdate(wepKey, &model.WorkloadEndpoint{Name: "cali1"}, tierWith("a"))
+		uut.Flush()
+		for _, m := range messages {
+			switch m := m.(type) {
+			case *proto.ActivePolicyUpdate:
+				Expect(m.BitmapIndex).To(BeZero())
+			case *proto.WorkloadEndpointUpdate:
+				Expect(m.Endpoint.PolicyBitmap).To(BeNil())
+			}
+		}
+	})
+})
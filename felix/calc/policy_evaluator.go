@@ -0,0 +1,456 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	calinet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+const (
+	EvalDirectionIngress = "ingress"
+	EvalDirectionEgress  = "egress"
+)
+
+// PolicyEvaluator evaluates a flow against the policies and profiles of the workload endpoints at
+// either end, to report which rule would allow or deny the flow before any traffic is sent.  It is
+// fed the same v1 model updates as the calc graph and selects and orders the policies the same way,
+// via the PolicySorter.  Workload endpoints only get normal policies: untracked and pre-DNAT
+// policies only apply to host endpoints.
+type PolicyEvaluator struct {
+	sorter        *PolicySorter
+	profileRules  map[string]*model.ProfileRules
+	profileLabels map[string]map[string]string
+	endpoints     map[model.WorkloadEndpointKey]*model.WorkloadEndpoint
+	netSets       map[model.NetworkSetKey]*model.NetworkSet
+}
+
+func NewPolicyEvaluator() *PolicyEvaluator {
+	return &PolicyEvaluator{
+		sorter:        NewPolicySorter(),
+		profileRules:  map[string]*model.ProfileRules{},
+		profileLabels: map[string]map[string]string{},
+		endpoints:     map[model.WorkloadEndpointKey]*model.WorkloadEndpoint{},
+		netSets:       map[model.NetworkSetKey]*model.NetworkSet{},
+	}
+}
+
+func (e *PolicyEvaluator) OnUpdate(update api.Update) (_ bool) {
+	switch key := update.Key.(type) {
+	case model.PolicyKey:
+		e.sorter.OnUpdate(update)
+	case model.ProfileRulesKey:
+		if update.Value == nil {
+			delete(e.profileRules, key.Name)
+		} else {
+			e.profileRules[key.Name] = update.Value.(*model.ProfileRules)
+		}
+	case model.ProfileLabelsKey:
+		if update.Value == nil {
+			delete(e.profileLabels, key.Name)
+		} else {
+			e.profileLabels[key.Name] = update.Value.(map[string]string)
+		}
+	case model.WorkloadEndpointKey:
+		if update.Value == nil {
+			delete(e.endpoints, key)
+		} else {
+			e.endpoints[key] = update.Value.(*model.WorkloadEndpoint)
+		}
+	case model.NetworkSetKey:
+		if update.Value == nil {
+			delete(e.netSets, key)
+		} else {
+			e.netSets[key] = update.Value.(*model.NetworkSet)
+		}
+	}
+	return
+}
+
+// EvalFlow is a flow to evaluate.
+type EvalFlow struct {
+	Protocol uint8
+	SrcIP    net.IP
+	SrcPort  uint16
+	DstIP    net.IP
+	DstPort  uint16
+}
+
+// EvalVerdict is the verdict of the policies of one workload endpoint on a flow.
+type EvalVerdict struct {
+	Endpoint  model.WorkloadEndpointKey
+	Direction string
+	// Action is "allow" or "deny".
+	Action string
+	// Tier and Policy are the tier and policy that decided the flow, if a policy did.
+	Tier   string
+	Policy string
+	// Profile is the profile that decided the flow, if a profile did.
+	Profile string
+	// RuleIndex is the index of the rule that decided the flow, or -1 if no rule matched and the
+	// flow got the default action.
+	RuleIndex int
+	Reason    string
+	// Unevaluated lists the rules that were skipped because they match on properties of the
+	// packets that a flow doesn't have, such as ICMP types, DSCP, HTTP requests or domain names.
+	Unevaluated []string
+}
+
+// EvalResult is the result of evaluating a flow.  Egress is nil if the source is not a Calico
+// workload endpoint, and Ingress if the destination is not.
+type EvalResult struct {
+	Egress  *EvalVerdict
+	Ingress *EvalVerdict
+}
+
+func (r EvalResult) Allowed() bool {
+	for _, v := range []*EvalVerdict{r.Egress, r.Ingress} {
+		if v != nil && v.Action != "allow" {
+			return false
+		}
+	}
+	return true
+}
+
+// evalPeer is one end of a flow.
+type evalPeer struct {
+	ip       net.IP
+	port     uint16
+	endpoint *model.WorkloadEndpoint
+	// labels are the labels that selectors match, the endpoint's own labels merged over the labels
+	// of its profiles, or else the labels of the network sets that contain the IP.
+	labels []map[string]string
+}
+
+// Evaluate evaluates a flow against the policies of the workload endpoints at either end.
+func (e *PolicyEvaluator) Evaluate(flow EvalFlow) EvalResult {
+	src := e.peer(flow.SrcIP, flow.SrcPort)
+	dst := e.peer(flow.DstIP, flow.DstPort)
+
+	var result EvalResult
+	if src.endpoint != nil {
+		result.Egress = e.evaluateEndpoint(flow.Protocol, src, dst, src, EvalDirectionEgress)
+	}
+	if dst.endpoint != nil {
+		result.Ingress = e.evaluateEndpoint(flow.Protocol, src, dst, dst, EvalDirectionIngress)
+	}
+	return result
+}
+
+func (e *PolicyEvaluator) peer(ip net.IP, port uint16) *evalPeer {
+	p := &evalPeer{ip: ip, port: port}
+	for _, key := range e.sortedEndpointKeys() {
+		ep := e.endpoints[key]
+		if !netsContain(ep.IPv4Nets, ip) && !netsContain(ep.IPv6Nets, ip) {
+			continue
+		}
+		p.endpoint = ep
+		labels := map[string]string{}
+		for _, profileID := range ep.ProfileIDs {
+			for k, v := range e.profileLabels[profileID] {
+				labels[k] = v
+			}
+		}
+		for k, v := range ep.Labels {
+			labels[k] = v
+		}
+		p.labels = []map[string]string{labels}
+		return p
+	}
+	for _, ns := range e.netSets {
+		if netsContain(ns.Nets, ip) {
+			p.labels = append(p.labels, ns.Labels)
+		}
+	}
+	return p
+}
+
+func (e *PolicyEvaluator) sortedEndpointKeys() []model.WorkloadEndpointKey {
+	keys := make([]model.WorkloadEndpointKey, 0, len(e.endpoints))
+	for k := range e.endpoints {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func (e *PolicyEvaluator) evaluateEndpoint(protocol uint8, src, dst, local *evalPeer, direction string) *EvalVerdict {
+	v := &EvalVerdict{Direction: direction, RuleIndex: -1}
+	for k, ep := range e.endpoints {
+		if ep == local.endpoint {
+			v.Endpoint = k
+		}
+	}
+
+	tier := e.sorter.Sorted()
+	applied := false
+	passed := false
+policies:
+	for _, pol := range tier.OrderedPolicies {
+		if pol.Value.DoNotTrack || pol.Value.PreDNAT {
+			continue
+		}
+		if direction == EvalDirectionIngress && !pol.GovernsIngress() ||
+			direction == EvalDirectionEgress && !pol.GovernsEgress() {
+			continue
+		}
+		sel, err := selector.Parse(pol.Value.Selector)
+		if err != nil || !sel.Evaluate(local.labels[0]) {
+			continue
+		}
+		applied = true
+
+		rules := pol.Value.InboundRules
+		if direction == EvalDirectionEgress {
+			rules = pol.Value.OutboundRules
+		}
+		for i := range rules {
+			match, unevaluated := ruleMatches(&rules[i], protocol, src, dst)
+			if unevaluated != "" {
+				v.Unevaluated = append(v.Unevaluated, fmt.Sprintf("%s rule %d: %s", pol.Key.Name, i, unevaluated))
+			}
+			if !match {
+				continue
+			}
+			switch rules[i].Action {
+			case "allow", "deny":
+				v.Action = rules[i].Action
+				v.Tier = tier.Name
+				v.Policy = pol.Key.Name
+				v.RuleIndex = i
+				v.Reason = fmt.Sprintf("rule %d of policy %s", i, pol.Key.Name)
+				return v
+			case "next-tier", "pass":
+				passed = true
+				v.Tier = tier.Name
+				v.Policy = pol.Key.Name
+				v.RuleIndex = i
+				break policies
+			}
+			// Log rules don't end the evaluation.
+		}
+	}
+	if applied && !passed {
+		v.Action = "deny"
+		v.Tier = tier.Name
+		v.Reason = fmt.Sprintf("no rule matched in the policies of tier %s that apply to the endpoint, end of tier deny", tier.Name)
+		return v
+	}
+
+	// Without any policies, or after a pass, the profiles decide.
+	for _, profileID := range local.endpoint.ProfileIDs {
+		profile := e.profileRules[profileID]
+		if profile == nil {
+			continue
+		}
+		rules := profile.InboundRules
+		if direction == EvalDirectionEgress {
+			rules = profile.OutboundRules
+		}
+		for i := range rules {
+			match, unevaluated := ruleMatches(&rules[i], protocol, src, dst)
+			if unevaluated != "" {
+				v.Unevaluated = append(v.Unevaluated, fmt.Sprintf("profile %s rule %d: %s", profileID, i, unevaluated))
+			}
+			if match && (rules[i].Action == "allow" || rules[i].Action == "deny") {
+				v.Action = rules[i].Action
+				v.Tier = ""
+				v.Policy = ""
+				v.Profile = profileID
+				v.RuleIndex = i
+				v.Reason = fmt.Sprintf("rule %d of profile %s", i, profileID)
+				return v
+			}
+		}
+	}
+	v.Action = "deny"
+	v.Tier = ""
+	v.Policy = ""
+	v.RuleIndex = -1
+	v.Reason = "no policy or profile rule matched, default deny"
+	return v
+}
+
+// ruleMatches returns whether a rule matches a flow.  Rules that match on properties that a flow
+// doesn't have never match, and the properties are returned.
+func ruleMatches(rule *model.Rule, protocol uint8, src, dst *evalPeer) (bool, string) {
+	var unevaluated []string
+	if len(rule.ICMPTypeCodes) > 0 || rule.ICMPType != nil || rule.ICMPCode != nil ||
+		rule.NotICMPType != nil || rule.NotICMPCode != nil {
+		unevaluated = append(unevaluated, "ICMP type")
+	}
+	if rule.DSCP != nil || rule.NotDSCP != nil {
+		unevaluated = append(unevaluated, "DSCP")
+	}
+	if rule.HTTPMatch != nil {
+		unevaluated = append(unevaluated, "HTTP")
+	}
+	if len(rule.DstDomains) > 0 {
+		unevaluated = append(unevaluated, "domains")
+	}
+	if rule.SrcService != "" || rule.DstService != "" {
+		unevaluated = append(unevaluated, "services")
+	}
+	if len(unevaluated) > 0 {
+		return false, strings.Join(unevaluated, ", ")
+	}
+
+	isV6 := src.ip.To4() == nil
+	if rule.IPVersion != nil && (*rule.IPVersion == 6) != isV6 {
+		return false, ""
+	}
+	if rule.Protocol != nil && ProtocolNumber(*rule.Protocol) != protocol {
+		return false, ""
+	}
+	if rule.NotProtocol != nil && ProtocolNumber(*rule.NotProtocol) == protocol {
+		return false, ""
+	}
+
+	if !netsMatch(rule.AllSrcNets(), rule.AllNotSrcNets(), src.ip) ||
+		!netsMatch(rule.AllDstNets(), rule.AllNotDstNets(), dst.ip) {
+		return false, ""
+	}
+	if !portsMatch(rule.SrcPorts, rule.NotSrcPorts, protocol, src) ||
+		!portsMatch(rule.DstPorts, rule.NotDstPorts, protocol, dst) {
+		return false, ""
+	}
+
+	srcSels, dstSels, notSrcSels, notDstSels := extractSelectors(rule)
+	if !selectorsMatch(srcSels, notSrcSels, src) || !selectorsMatch(dstSels, notDstSels, dst) {
+		return false, ""
+	}
+	return true, ""
+}
+
+func netsMatch(nets, notNets []*calinet.IPNet, ip net.IP) bool {
+	if len(nets) > 0 {
+		found := false
+		for _, n := range nets {
+			if n.Contains(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, n := range notNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func portsMatch(ports, notPorts []numorstring.Port, protocol uint8, peer *evalPeer) bool {
+	if len(ports) == 0 && len(notPorts) == 0 {
+		return true
+	}
+	if protocol != 6 && protocol != 17 && protocol != 132 {
+		return false
+	}
+	if len(ports) > 0 && !portInList(ports, protocol, peer) {
+		return false
+	}
+	return !portInList(notPorts, protocol, peer)
+}
+
+func portInList(ports []numorstring.Port, protocol uint8, peer *evalPeer) bool {
+	for _, p := range ports {
+		if p.PortName == "" {
+			if peer.port >= p.MinPort && peer.port <= p.MaxPort {
+				return true
+			}
+			continue
+		}
+		// Named ports match the ports of the endpoint with that name and protocol.
+		if peer.endpoint == nil {
+			continue
+		}
+		for _, ep := range peer.endpoint.Ports {
+			if ep.Name == p.PortName && ep.Port == peer.port && ProtocolNumber(ep.Protocol) == protocol {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func selectorsMatch(sels, notSels []selector.Selector, peer *evalPeer) bool {
+	for _, sel := range sels {
+		if !anyLabelsMatch(sel, peer.labels) {
+			return false
+		}
+	}
+	for _, sel := range notSels {
+		if anyLabelsMatch(sel, peer.labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyLabelsMatch(sel selector.Selector, labels []map[string]string) bool {
+	for _, l := range labels {
+		if sel.Evaluate(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func netsContain(nets []calinet.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtocolNumber returns the number of a protocol from the v1 data model, or 0 if the protocol is
+// not known.
+func ProtocolNumber(p numorstring.Protocol) uint8 {
+	if p.Type == numorstring.NumOrStringNum {
+		return p.NumVal
+	}
+	switch strings.ToLower(p.StrVal) {
+	case "tcp":
+		return 6
+	case "udp":
+		return 17
+	case "icmp":
+		return 1
+	case "icmpv6":
+		return 58
+	case "sctp":
+		return 132
+	case "udplite":
+		return 136
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	"github.com/projectcalico/calico/felix/calc"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	calinet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+var _ = Describe("PolicyEvaluator", func() {
+	var uut *calc.PolicyEvaluator
+	tcp := numorstring.ProtocolFromString("tcp")
+
+	clientKey := model.WorkloadEndpointKey{Hostname: "node1", OrchestratorID: "k8s", WorkloadID: "ns1/client", EndpointID: "eth0"}
+	serverKey := model.WorkloadEndpointKey{Hostname: "node2", OrchestratorID: "k8s", WorkloadID: "ns1/server", EndpointID: "eth0"}
+
+	update := func(key model.Key, value interface{}) {
+		uut.OnUpdate(api.Update{KVPair: model.KVPair{Key: key, Value: value}, UpdateType: api.UpdateTypeKVNew})
+	}
+	policy := func(name string, order float64, sel string, types []string, inbound, outbound []model.Rule) {
+		update(model.PolicyKey{Name: name}, &model.Policy{
+			Order:         &order,
+			Selector:      sel,
+			Types:         types,
+			InboundRules:  inbound,
+			OutboundRules: outbound,
+		})
+	}
+	flow := func(port uint16) calc.EvalFlow {
+		return calc.EvalFlow{
+			Protocol: 6,
+			SrcIP:    net.ParseIP("10.0.0.1"),
+			SrcPort:  33000,
+			DstIP:    net.ParseIP("10.0.0.2"),
+			DstPort:  port,
+		}
+	}
+
+	BeforeEach(func() {
+		uut = calc.NewPolicyEvaluator()
+		update(model.ProfileLabelsKey{ProfileKey: model.ProfileKey{Name: "kns.ns1"}}, map[string]string{"pcns.team": "a"})
+		update(model.ProfileRulesKey{ProfileKey: model.ProfileKey{Name: "kns.ns1"}}, &model.ProfileRules{
+			InboundRules:  []model.Rule{{Action: "allow"}},
+			OutboundRules: []model.Rule{{Action: "allow"}},
+		})
+		update(clientKey, &model.WorkloadEndpoint{
+			Labels:     map[string]string{"app": "client"},
+			ProfileIDs: []string{"kns.ns1"},
+			IPv4Nets:   []calinet.IPNet{calinet.MustParseCIDR("10.0.0.1/32")},
+		})
+		update(serverKey, &model.WorkloadEndpoint{
+			Labels:     map[string]string{"app": "server"},
+			ProfileIDs: []string{"kns.ns1"},
+			IPv4Nets:   []calinet.IPNet{calinet.MustParseCIDR("10.0.0.2/32")},
+			Ports:      []model.EndpointPort{{Name: "http", Protocol: tcp, Port: 8080}},
+		})
+	})
+
+	It("should fall through to the profiles without policies", func() {
+		result := uut.Evaluate(flow(80))
+		Expect(result.Allowed()).To(BeTrue())
+		Expect(result.Egress.Endpoint).To(Equal(clientKey))
+		Expect(result.Egress.Profile).To(Equal("kns.ns1"))
+		Expect(result.Ingress.Endpoint).To(Equal(serverKey))
+		Expect(result.Ingress.Profile).To(Equal("kns.ns1"))
+		Expect(result.Ingress.RuleIndex).To(Equal(0))
+	})
+
+	It("should deny at the end of the tier when no rule matches", func() {
+		policy("allow-http", 10, "app == 'server'", []string{"ingress"}, []model.Rule{
+			{Action: "allow", Protocol: &tcp, DstPorts: []numorstring.Port{numorstring.SinglePort(80)}},
+		}, nil)
+
+		result := uut.Evaluate(flow(80))
+		Expect(result.Allowed()).To(BeTrue())
+		Expect(result.Ingress.Policy).To(Equal("allow-http"))
+		Expect(result.Ingress.Tier).To(Equal("default"))
+
+		result = uut.Evaluate(flow(443))
+		Expect(result.Allowed()).To(BeFalse())
+		Expect(result.Ingress.Action).To(Equal("deny"))
+		Expect(result.Ingress.Policy).To(BeEmpty())
+		Expect(result.Ingress.RuleIndex).To(Equal(-1))
+		Expect(result.Egress.Action).To(Equal("allow"))
+	})
+
+	It("should apply policies in order", func() {
+		policy("deny-all", 20, "all()", []string{"ingress"}, []model.Rule{{Action: "deny"}}, nil)
+		policy("allow-client", 10, "app == 'server'", []string{"ingress"}, []model.Rule{
+			{Action: "log"},
+			{Action: "allow", SrcSelector: "app == 'client' && pcns.team == 'a'"},
+		}, nil)
+
+		result := uut.Evaluate(flow(80))
+		Expect(result.Ingress.Action).To(Equal("allow"))
+		Expect(result.Ingress.Policy).To(Equal("allow-client"))
+		Expect(result.Ingress.RuleIndex).To(Equal(1))
+	})
+
+	It("should only apply policies to the directions of their types", func() {
+		policy("deny-egress", 10, "app == 'client'", []string{"egress"}, nil, []model.Rule{{Action: "deny"}})
+
+		result := uut.Evaluate(flow(80))
+		Expect(result.Egress.Action).To(Equal("deny"))
+		Expect(result.Egress.Policy).To(Equal("deny-egress"))
+		Expect(result.Ingress.Action).To(Equal("allow"))
+	})
+
+	It("should hand passed flows to the profiles", func() {
+		policy("pass", 10, "all()", []string{"ingress"}, []model.Rule{{Action: "pass"}}, nil)
+
+		result := uut.Evaluate(flow(80))
+		Expect(result.Ingress.Action).To(Equal("allow"))
+		Expect(result.Ingress.Profile).To(Equal("kns.ns1"))
+	})
+
+	It("should match named ports against the endpoint's ports", func() {
+		policy("allow-http", 10, "app == 'server'", []string{"ingress"}, []model.Rule{
+			{Action: "allow", Protocol: &tcp, DstPorts: []numorstring.Port{numorstring.NamedPort("http")}},
+		}, nil)
+
+		Expect(uut.Evaluate(flow(8080)).Ingress.Action).To(Equal("allow"))
+		Expect(uut.Evaluate(flow(80)).Ingress.Action).To(Equal("deny"))
+	})
+
+	It("should match network sets for peers that aren't endpoints", func() {
+		update(model.NetworkSetKey{Name: "ns1/office"}, &model.NetworkSet{
+			Nets:   []calinet.IPNet{calinet.MustParseCIDR("192.168.0.0/16")},
+			Labels: map[string]string{"role": "office"},
+		})
+		policy("allow-office", 10, "app == 'server'", []string{"ingress"}, []model.Rule{
+			{Action: "allow", SrcSelector: "role == 'office'"},
+		}, nil)
+
+		f := flow(80)
+		f.SrcIP = net.ParseIP("192.168.1.1")
+		result := uut.Evaluate(f)
+		Expect(result.Egress).To(BeNil())
+		Expect(result.Ingress.Action).To(Equal("allow"))
+
+		f.SrcIP = net.ParseIP("172.16.0.1")
+		Expect(uut.Evaluate(f).Ingress.Action).To(Equal("deny"))
+	})
+
+	It("should report rules that can't be evaluated", func() {
+		policy("http-only", 10, "app == 'server'", []string{"ingress"}, []model.Rule{
+			{Action: "allow", HTTPMatch: &model.HTTPMatch{Methods: []string{"GET"}}},
+		}, nil)
+
+		result := uut.Evaluate(flow(80))
+		Expect(result.Ingress.Action).To(Equal("deny"))
+		Expect(result.Ingress.Unevaluated).To(ConsistOf("http-only rule 0: HTTP"))
+	})
+})
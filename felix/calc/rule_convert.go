@@ -105,6 +105,7 @@ func parsedRuleToProtoRule(in *ParsedRule) *proto.Rule {
 		SrcIpSetIds:          in.SrcIPSetIDs,
 		DstIpSetIds:          in.DstIPSetIDs,
 		DstIpPortSetIds:      in.DstIPPortSetIDs,
+		DstDomainIpSetIds:    in.DstDomainIPSetIDs,
 
 		NotProtocol:             protocolToProtoProtocol(in.NotProtocol),
 		NotSrcNet:               ipNetsToProtoStrings(in.NotSrcNets),
@@ -166,6 +167,13 @@ func parsedRuleToProtoRule(in *ParsedRule) *proto.Rule {
 		}
 	}
 
+	if in.Log != nil {
+		out.LogTarget = in.Log.Target
+		out.LogSyslogFacility = in.Log.SyslogFacility
+		out.LogRateLimit = in.Log.RateLimit
+		out.LogRateLimitBurst = uint32(in.Log.RateLimitBurst)
+	}
+
 	if in.Metadata != nil {
 		if in.Metadata.Annotations != nil {
 			out.Metadata = &proto.RuleMetadata{Annotations: make(map[string]string)}
@@ -192,6 +200,16 @@ func parsedRuleToProtoRule(in *ParsedRule) *proto.Rule {
 			}
 		}
 	}
+	for _, tc := range in.ICMPTypeCodes {
+		if tc.Code != nil {
+			out.IcmpTypeCodes = append(out.IcmpTypeCodes, &proto.IcmpTypeAndCode{
+				Type: int32(tc.Type),
+				Code: int32(*tc.Code),
+			})
+		} else {
+			out.IcmpTypes = append(out.IcmpTypes, int32(tc.Type))
+		}
+	}
 	if in.NotICMPType != nil {
 		if in.NotICMPCode != nil {
 			out.NotIcmp = &proto.Rule_NotIcmpTypeCode{
@@ -207,6 +225,14 @@ func parsedRuleToProtoRule(in *ParsedRule) *proto.Rule {
 		}
 	}
 
+	// As for ICMP, the DSCP fields are oneofs so that a match on DSCP 0 can be represented.
+	if v, ok := dscpToProtoValue(in.DSCP); ok {
+		out.Dscp = &proto.Rule_DscpValue{DscpValue: v}
+	}
+	if v, ok := dscpToProtoValue(in.NotDSCP); ok {
+		out.NotDscp = &proto.Rule_NotDscpValue{NotDscpValue: v}
+	}
+
 	log.WithFields(log.Fields{
 		"in":  in,
 		"out": out,
@@ -255,6 +281,19 @@ func protocolToProtoProtocol(in *numorstring.Protocol) (out *proto.Protocol) {
 	return
 }
 
+func dscpToProtoValue(in *numorstring.DSCP) (int32, bool) {
+	if in == nil {
+		return 0, false
+	}
+	v, err := in.NumValue()
+	if err != nil {
+		// Should have been caught by validation.
+		log.WithError(err).Error("Ignoring invalid DSCP match")
+		return 0, false
+	}
+	return int32(v), true
+}
+
 func ipNetsToProtoStrings(in []*net.IPNet) (out []string) {
 	for _, n := range in {
 		if n != nil {
@@ -92,6 +92,8 @@ var fullyLoadedParsedRule = ParsedRule{
 		{Prefix: "/bar"},
 	}},
 
+	Log: &model.RuleLog{Target: "Syslog", SyslogFacility: "local1", RateLimit: "1/second", RateLimitBurst: 2},
+
 	Metadata: &model.RuleMetadata{Annotations: map[string]string{"key": "value"}},
 }
 
@@ -159,6 +161,11 @@ var fullyLoadedProtoRule = proto.Rule{
 			{PathMatch: &proto.HTTPMatch_PathMatch_Prefix{Prefix: "/bar"}},
 		}},
 
+	LogTarget:         "Syslog",
+	LogSyslogFacility: "local1",
+	LogRateLimit:      "1/second",
+	LogRateLimitBurst: 2,
+
 	Metadata: &proto.RuleMetadata{Annotations: map[string]string{"key": "value"}},
 }
 
@@ -212,6 +219,33 @@ var _ = DescribeTable("ParsedRulesToProtoRules",
 				NotIcmpType: 11,
 			},
 		}),
+	Entry("Multiple ICMP types and codes",
+		ParsedRule{
+			ICMPTypeCodes: []model.ICMPTypeCode{
+				{Type: 135},
+				{Type: 10, Code: &icmpCode12},
+				{Type: 136},
+			},
+		},
+		proto.Rule{
+			IcmpTypes: []int32{135, 136},
+			IcmpTypeCodes: []*proto.IcmpTypeAndCode{
+				{Type: 10, Code: 12},
+			},
+		}),
+	Entry("DSCP rule",
+		ParsedRule{
+			DSCP:    dscpFromInt(0),
+			NotDSCP: dscpFromString("EF"),
+		},
+		proto.Rule{
+			Dscp: &proto.Rule_DscpValue{
+				DscpValue: 0,
+			},
+			NotDscp: &proto.Rule_NotDscpValue{
+				NotDscpValue: 46,
+			},
+		}),
 	Entry("Service match rule",
 		ParsedRule{
 			DstIPPortSetIDs: []string{"ipPortSetID"},
@@ -219,6 +253,13 @@ var _ = DescribeTable("ParsedRulesToProtoRules",
 		proto.Rule{
 			DstIpPortSetIds: []string{"ipPortSetID"},
 		}),
+	Entry("Domain match rule",
+		ParsedRule{
+			DstDomainIPSetIDs: []string{"domainSetID"},
+		},
+		proto.Rule{
+			DstDomainIpSetIds: []string{"domainSetID"},
+		}),
 	Entry("fully-loaded rule",
 		fullyLoadedParsedRule,
 		fullyLoadedProtoRule),
@@ -287,3 +328,13 @@ func mustParseCalicoIPNet(s string) *net.IPNet {
 	}
 	return &net.IPNet{IPNet: *ipNet}
 }
+
+func dscpFromInt(d uint8) *numorstring.DSCP {
+	dscp := numorstring.DSCPFromInt(d)
+	return &dscp
+}
+
+func dscpFromString(s string) *numorstring.DSCP {
+	dscp := numorstring.DSCPFromString(s)
+	return &dscp
+}
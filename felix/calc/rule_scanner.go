@@ -16,6 +16,7 @@ package calc
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -101,6 +102,8 @@ type IPSetData struct {
 	// Type of the ip set to represent for this service. This allows us to create service
 	// IP sets with and without port information.
 	ServiceIncludePorts bool
+	// The sorted domain names that this IP set represents.  The dataplane resolves them to IPs.
+	Domains []string
 	// cachedUID holds the calculated unique ID of this IP set, or "" if it hasn't been calculated
 	// yet.
 	cachedUID string
@@ -120,13 +123,19 @@ func (d *IPSetData) String() string {
 	if d.ServiceIncludePorts {
 		parts = append(parts, "serviceIncludePorts=true")
 	}
+	if len(d.Domains) > 0 {
+		parts = append(parts, fmt.Sprintf("domains:%v", d.Domains))
+	}
 	parts = append(parts, fmt.Sprintf("uniqueID:%q", d.UniqueID()))
 	return "IPSetData{" + strings.Join(parts, ", ") + "}"
 }
 
 func (d *IPSetData) UniqueID() string {
 	if d.cachedUID == "" {
-		if d.Service != "" {
+		if len(d.Domains) > 0 {
+			// Domain IP set.
+			d.cachedUID = hash.MakeUniqueID("d", strings.Join(d.Domains, ","))
+		} else if d.Service != "" {
 			// Service based IP set.
 			if d.ServiceIncludePorts {
 				// Service IP set including its ports
@@ -152,6 +161,9 @@ func (d *IPSetData) UniqueID() string {
 // DataplaneProtocolType returns the dataplane driver protocol type of this IP set.
 // One of the proto.IPSetUpdate_IPSetType constants.
 func (d *IPSetData) DataplaneProtocolType() proto.IPSetUpdate_IPSetType {
+	if len(d.Domains) > 0 {
+		return proto.IPSetUpdate_DOMAIN
+	}
 	if d.NamedPortProtocol != labelindex.ProtocolNone {
 		return proto.IPSetUpdate_IP_AND_PORT
 	}
@@ -331,9 +343,12 @@ type ParsedRule struct {
 	DstNamedPortIPSetIDs []string
 	ICMPType             *int
 	ICMPCode             *int
+	ICMPTypeCodes        []model.ICMPTypeCode
 	SrcIPSetIDs          []string
 	DstIPSetIDs          []string
 	DstIPPortSetIDs      []string
+	DstDomainIPSetIDs    []string
+	DSCP                 *numorstring.DSCP
 
 	NotProtocol             *numorstring.Protocol
 	NotSrcNets              []*net.IPNet
@@ -346,6 +361,7 @@ type ParsedRule struct {
 	NotICMPCode             *int
 	NotSrcIPSetIDs          []string
 	NotDstIPSetIDs          []string
+	NotDSCP                 *numorstring.DSCP
 
 	// These fields allow us to pass through the raw match criteria from the V3 datamodel,
 	// unmodified. The selectors above are formed in the update processor layer by combining the
@@ -369,6 +385,9 @@ type ParsedRule struct {
 	// does not implement the match, but other dataplanes such as Dikastes do.
 	HTTPMatch *model.HTTPMatch
 
+	// Log carries the log options of rules with the log action.
+	Log *model.RuleLog
+
 	Metadata *model.RuleMetadata
 }
 
@@ -440,6 +459,12 @@ func ruleToParsedRule(rule *model.Rule) (parsedRule *ParsedRule, allIPSets []*IP
 		srcSelIPSets = append(srcSelIPSets, &IPSetData{Service: svc, ServiceIncludePorts: false})
 	}
 
+	// The domains of a rule are matched by a single IP set.
+	var dstDomainIPSets []*IPSetData
+	if len(rule.DstDomains) > 0 {
+		dstDomainIPSets = append(dstDomainIPSets, &IPSetData{Domains: normaliseDomains(rule.DstDomains)})
+	}
+
 	notSrcSelIPSets := selectorsToIPSets(notSrcSels)
 	notDstSelIPSets := selectorsToIPSets(notDstSels)
 
@@ -460,9 +485,13 @@ func ruleToParsedRule(rule *model.Rule) (parsedRule *ParsedRule, allIPSets []*IP
 		DstNamedPortIPSetIDs: ipSetsToUIDs(dstNamedPortIPSets),
 		DstIPSetIDs:          ipSetsToUIDs(dstSelIPSets),
 		DstIPPortSetIDs:      ipSetsToUIDs(dstIPPortSets),
+		DstDomainIPSetIDs:    ipSetsToUIDs(dstDomainIPSets),
 
-		ICMPType: rule.ICMPType,
-		ICMPCode: rule.ICMPCode,
+		ICMPType:      rule.ICMPType,
+		ICMPCode:      rule.ICMPCode,
+		ICMPTypeCodes: rule.ICMPTypeCodes,
+
+		DSCP: rule.DSCP,
 
 		NotProtocol: rule.NotProtocol,
 
@@ -479,6 +508,8 @@ func ruleToParsedRule(rule *model.Rule) (parsedRule *ParsedRule, allIPSets []*IP
 		NotICMPType: rule.NotICMPType,
 		NotICMPCode: rule.NotICMPCode,
 
+		NotDSCP: rule.NotDSCP,
+
 		// Pass through original values of some fields for the policy API.
 		OriginalSrcSelector:               rule.OriginalSrcSelector,
 		OriginalSrcNamespaceSelector:      rule.OriginalSrcNamespaceSelector,
@@ -495,6 +526,7 @@ func ruleToParsedRule(rule *model.Rule) (parsedRule *ParsedRule, allIPSets []*IP
 		OriginalDstService:                rule.DstService,
 		OriginalDstServiceNamespace:       rule.DstServiceNamespace,
 		HTTPMatch:                         rule.HTTPMatch,
+		Log:                               rule.Log,
 
 		// Pass through metadata (used by iptables backend)
 		Metadata: rule.Metadata,
@@ -507,6 +539,7 @@ func ruleToParsedRule(rule *model.Rule) (parsedRule *ParsedRule, allIPSets []*IP
 	allIPSets = append(allIPSets, srcSelIPSets...)
 	allIPSets = append(allIPSets, dstSelIPSets...)
 	allIPSets = append(allIPSets, dstIPPortSets...)
+	allIPSets = append(allIPSets, dstDomainIPSets...)
 	allIPSets = append(allIPSets, notSrcSelIPSets...)
 	allIPSets = append(allIPSets, notDstSelIPSets...)
 
@@ -544,6 +577,18 @@ func selectorsToIPSets(selectors []selector.Selector) []*IPSetData {
 	return ipSets
 }
 
+// normaliseDomains returns the sorted, de-duplicated, lower case domains so that rules with the
+// same domains share an IP set.
+func normaliseDomains(domains []string) []string {
+	normalised := set.New[string]()
+	for _, d := range domains {
+		normalised.Add(strings.ToLower(strings.TrimSuffix(d, ".")))
+	}
+	sorted := normalised.Slice()
+	sort.Strings(sorted)
+	return sorted
+}
+
 func ipSetsToUIDs(ipSets []*IPSetData) []string {
 	var ids []string
 	for _, ipSet := range ipSets {
@@ -144,6 +144,10 @@ var _ = DescribeTable("RuleScanner rule conversion should generate correct Parse
 		{Prefix: "/bar"},
 	}}}),
 
+	Entry("Log",
+		model.Rule{Log: &model.RuleLog{Target: "File", RateLimit: "3/minute"}},
+		ParsedRule{Log: &model.RuleLog{Target: "File", RateLimit: "3/minute"}}),
+
 	Entry("Metadata",
 		model.Rule{Metadata: &model.RuleMetadata{Annotations: map[string]string{"key": "value"}}},
 		ParsedRule{Metadata: &model.RuleMetadata{Annotations: map[string]string{"key": "value"}}}),
@@ -164,6 +168,13 @@ var _ = DescribeTable("RuleScanner rule conversion should generate correct Parse
 			OriginalSrcServiceNamespace: "default",
 		}),
 
+	// Domains.
+	Entry("dest domains",
+		model.Rule{DstDomains: []string{"api.github.com", "*.GitHub.com.", "api.github.com"}},
+		ParsedRule{
+			DstDomainIPSetIDs: []string{"d:vV73XMnEDxR6pdmKb4mQSWK05B6BuKZz27wt9w"},
+		}),
+
 	// Selectors.
 	Entry("source selector", model.Rule{SrcSelector: sel1}, ParsedRule{SrcIPSetIDs: []string{sel1ID}}),
 	Entry("dest selector", model.Rule{DstSelector: sel1}, ParsedRule{DstIPSetIDs: []string{sel1ID}}),
@@ -239,6 +250,10 @@ var _ = Describe("ParsedRule", func() {
 				// as either IPPortIPSetIDs or IPSetIDs.
 				continue
 			}
+			if name == "DstDomains" {
+				// Domains are rendered on the ParsedRule as DstDomainIPSetIDs.
+				continue
+			}
 			if strings.HasSuffix(name, "Net") {
 				// Deprecated XXXNet fields.
 				continue
@@ -252,7 +267,8 @@ var _ = Describe("ParsedRule", func() {
 	})
 	It("should have correct fields relative to proto.Rule", func() {
 		// We expect all the fields to have the same name, except for
-		// ICMP and service account matches, which differ in structure.
+		// ICMP and service account matches and the log options, which
+		// differ in structure.
 		prType := reflect.TypeOf(ParsedRule{})
 		numPRFields := prType.NumField()
 		prFields := []string{}
@@ -260,7 +276,8 @@ var _ = Describe("ParsedRule", func() {
 			name := strings.ToLower(prType.Field(i).Name)
 			if strings.Contains(name, "icmptype") ||
 				strings.Contains(name, "icmpcode") ||
-				strings.Contains(name, "serviceaccount") {
+				strings.Contains(name, "serviceaccount") ||
+				name == "log" {
 				// expected to differ.
 				continue
 			}
@@ -277,7 +294,8 @@ var _ = Describe("ParsedRule", func() {
 		for i := 0; i < numMRFields; i++ {
 			name := strings.ToLower(protoType.Field(i).Name)
 			if strings.Contains(name, "icmp") ||
-				strings.Contains(name, "serviceaccount") {
+				strings.Contains(name, "serviceaccount") ||
+				strings.HasPrefix(name, "log") {
 				// expected to differ.
 				continue
 			}
@@ -313,7 +331,7 @@ func (ur *scanUpdateRecorder) OnProfileInactive(key model.ProfileRulesKey) {
 }
 
 func (ur *scanUpdateRecorder) ipSetActive(ipSet *IPSetData) {
-	if ipSet.Service != "" {
+	if ipSet.Service != "" || len(ipSet.Domains) > 0 {
 		// Not a selector-based set.
 		return
 	}
@@ -321,7 +339,7 @@ func (ur *scanUpdateRecorder) ipSetActive(ipSet *IPSetData) {
 }
 
 func (ur *scanUpdateRecorder) ipSetInactive(ipSet *IPSetData) {
-	if ipSet.Service != "" {
+	if ipSet.Service != "" || len(ipSet.Domains) > 0 {
 		// Not a selector-based set.
 		return
 	}
@@ -20,6 +20,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 )
@@ -33,6 +35,10 @@ var (
 		Name: "felix_cluster_num_host_endpoints",
 		Help: "Total number of host endpoints cluster-wide.",
 	})
+	gaugeVecClusNumHostEndpointsByZone = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "felix_cluster_num_host_endpoints_by_zone",
+		Help: "Number of host endpoints cluster-wide in each zone.",
+	}, []string{"zone"})
 	gaugeClusNumWorkloadEndpoints = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "felix_cluster_num_workload_endpoints",
 		Help: "Total number of workload endpoints cluster-wide.",
@@ -50,6 +56,7 @@ var (
 func init() {
 	prometheus.MustRegister(gaugeClusNumHosts)
 	prometheus.MustRegister(gaugeClusNumHostEndpoints)
+	prometheus.MustRegister(gaugeVecClusNumHostEndpointsByZone)
 	prometheus.MustRegister(gaugeClusNumWorkloadEndpoints)
 	prometheus.MustRegister(gaugeClusNumPolicies)
 	prometheus.MustRegister(gaugeClusNumProfiles)
@@ -63,6 +70,9 @@ type StatsCollector struct {
 	numProfiles          int
 	numALPPolicies       int
 
+	hostEndpointZones       map[model.HostEndpointKey]string
+	numHostEndpointsPerZone map[string]int
+
 	lastUpdate StatsUpdate
 	inSync     bool
 
@@ -84,9 +94,11 @@ func (s StatsUpdate) String() string {
 
 func NewStatsCollector(callback func(StatsUpdate) error) *StatsCollector {
 	return &StatsCollector{
-		keyCountByHost: make(map[string]int),
-		lastUpdate:     StatsUpdate{NumHosts: -1},
-		Callback:       callback,
+		keyCountByHost:          make(map[string]int),
+		hostEndpointZones:       make(map[model.HostEndpointKey]string),
+		numHostEndpointsPerZone: make(map[string]int),
+		lastUpdate:              StatsUpdate{NumHosts: -1},
+		Callback:                callback,
 	}
 }
 
@@ -119,6 +131,7 @@ func (s *StatsCollector) OnUpdate(update api.Update) (filterOut bool) {
 	case model.HostEndpointKey:
 		hostname = key.Hostname
 		counter = &s.numHostEndpoints
+		s.updateHostEndpointZone(key, update.Value)
 	case model.HostConfigKey:
 		hostname = key.Hostname
 	}
@@ -155,6 +168,39 @@ func (s *StatsCollector) OnUpdate(update api.Update) (filterOut bool) {
 	return
 }
 
+// updateHostEndpointZone updates the count of host endpoints of the zone of the given host
+// endpoint, which is nil if it was deleted.
+func (s *StatsCollector) updateHostEndpointZone(key model.HostEndpointKey, value interface{}) {
+	zone := ""
+	if hep, ok := value.(*model.HostEndpoint); ok && hep != nil {
+		zone = hep.Labels[apiv3.LabelZone]
+	}
+	oldZone := s.hostEndpointZones[key]
+	if zone == oldZone {
+		return
+	}
+	if oldZone != "" {
+		s.numHostEndpointsPerZone[oldZone]--
+		if s.numHostEndpointsPerZone[oldZone] <= 0 {
+			delete(s.numHostEndpointsPerZone, oldZone)
+			gaugeVecClusNumHostEndpointsByZone.DeleteLabelValues(oldZone)
+		} else {
+			gaugeVecClusNumHostEndpointsByZone.WithLabelValues(oldZone).Set(float64(s.numHostEndpointsPerZone[oldZone]))
+		}
+		delete(s.hostEndpointZones, key)
+	}
+	if zone != "" {
+		s.hostEndpointZones[key] = zone
+		s.numHostEndpointsPerZone[zone]++
+		gaugeVecClusNumHostEndpointsByZone.WithLabelValues(zone).Set(float64(s.numHostEndpointsPerZone[zone]))
+	}
+}
+
+// NumHostEndpointsInZone returns the number of host endpoints in the given zone.
+func (s *StatsCollector) NumHostEndpointsInZone(zone string) int {
+	return s.numHostEndpointsPerZone[zone]
+}
+
 func (s *StatsCollector) UpdatePolicyCounts(numPolicies, numProfiles, numALPPolicies int) {
 	if numPolicies == s.numPolicies && numProfiles == s.numProfiles && numALPPolicies == s.numALPPolicies {
 		return
@@ -88,6 +88,24 @@ var _ = Describe("Stats collector", func() {
 				NumHostEndpoints: 1,
 			}))
 		})
+		It("should count the host endpoints of each zone", func() {
+			dmzHostEp := &HostEndpoint{Labels: map[string]string{"projectcalico.org/zone": "dmz"}}
+			storageHostEp := &HostEndpoint{Labels: map[string]string{"projectcalico.org/zone": "storage"}}
+			sc.OnUpdate(api.Update{KVPair: KVPair{Key: hostEpWithNameKey, Value: dmzHostEp}, UpdateType: api.UpdateTypeKVNew})
+			sc.OnUpdate(api.Update{KVPair: KVPair{Key: hostEp2NoNameKey, Value: dmzHostEp}, UpdateType: api.UpdateTypeKVNew})
+			Expect(sc.NumHostEndpointsInZone("dmz")).To(Equal(2))
+
+			sc.OnUpdate(api.Update{KVPair: KVPair{Key: hostEp2NoNameKey, Value: storageHostEp}, UpdateType: api.UpdateTypeKVUpdated})
+			Expect(sc.NumHostEndpointsInZone("dmz")).To(Equal(1))
+			Expect(sc.NumHostEndpointsInZone("storage")).To(Equal(1))
+
+			sc.OnUpdate(api.Update{KVPair: KVPair{Key: hostEpWithNameKey}, UpdateType: api.UpdateTypeKVDeleted})
+			Expect(sc.NumHostEndpointsInZone("dmz")).To(Equal(0))
+			Expect(*lastStatsUpdate).To(Equal(StatsUpdate{
+				NumHosts:         1,
+				NumHostEndpoints: 1,
+			}))
+		})
 		It("should count a host config create", func() {
 			sc.OnUpdate(api.Update{KVPair: KVPair{Key: HostConfigKey{Name: localHostname, Hostname: "foo"}},
 				UpdateType: api.UpdateTypeKVNew})
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capabilities lets Felix run with a minimal set of Linux capabilities instead of as a
+// fully privileged container.  It works out which capabilities the dataplane needs, reports
+// the ones that are missing and passes the ones that Felix has on to the helper binaries
+// (iptables, ipset, conntrack) that perform the privileged operations.
+package capabilities
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Cap is a Linux capability number.
+type Cap uint
+
+const (
+	NetAdmin    Cap = 12
+	NetRaw      Cap = 13
+	SysAdmin    Cap = 21
+	SysResource Cap = 24
+	Perfmon     Cap = 38
+	BPF         Cap = 39
+)
+
+var names = map[Cap]string{
+	NetAdmin:    "CAP_NET_ADMIN",
+	NetRaw:      "CAP_NET_RAW",
+	SysAdmin:    "CAP_SYS_ADMIN",
+	SysResource: "CAP_SYS_RESOURCE",
+	Perfmon:     "CAP_PERFMON",
+	BPF:         "CAP_BPF",
+}
+
+func (c Cap) String() string {
+	if n, ok := names[c]; ok {
+		return n
+	}
+	return fmt.Sprintf("CAP_%d", uint(c))
+}
+
+// Set is a set of capabilities, bit N set means that capability N is present.
+type Set uint64
+
+func (s Set) Has(c Cap) bool {
+	return s&(1<<c) != 0
+}
+
+func (s Set) With(caps ...Cap) Set {
+	for _, c := range caps {
+		s |= 1 << c
+	}
+	return s
+}
+
+// Required returns the capabilities that the dataplane needs.  The iptables dataplane needs
+// to manage interfaces, routes, iptables and IP sets.  The BPF dataplane also needs to load
+// BPF programs and to lift the locked memory limit for the BPF maps.
+func Required(bpfEnabled bool) []Cap {
+	caps := []Cap{NetAdmin, NetRaw}
+	if bpfEnabled {
+		caps = append(caps, BPF, Perfmon, SysResource)
+	}
+	return caps
+}
+
+// Missing returns the required capabilities that are not in the set, sorted.  CAP_SYS_ADMIN
+// stands in for CAP_BPF and CAP_PERFMON, as it does on kernels before 5.8.
+func Missing(have Set, required []Cap) []Cap {
+	var missing []Cap
+	for _, c := range required {
+		if have.Has(c) {
+			continue
+		}
+		if (c == BPF || c == Perfmon) && have.Has(SysAdmin) {
+			continue
+		}
+		missing = append(missing, c)
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i] < missing[j]
+	})
+	return missing
+}
+
+const defaultProcSysPath = "/proc/sys"
+
+var procSysPath = defaultProcSysPath
+
+// SetProcSysPath sets where the /proc/sys settings are written.
+func SetProcSysPath(path string) {
+	procSysPath = filepath.Clean(path)
+}
+
+// ProcSysPath maps a /proc/sys path to the place where it is written.
+func ProcSysPath(path string) string {
+	if procSysPath == defaultProcSysPath || !strings.HasPrefix(path, defaultProcSysPath+"/") {
+		return path
+	}
+	return procSysPath + strings.TrimPrefix(path, defaultProcSysPath)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capabilities
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// Effective returns the effective capabilities of the process.
+func Effective() (Set, error) {
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if err := unix.Capget(&hdr, &data[0]); err != nil {
+		return 0, err
+	}
+	return Set(data[0].Effective) | Set(data[1].Effective)<<32, nil
+}
+
+var (
+	ambientOnce sync.Once
+	ambientCaps []uintptr
+)
+
+// helperCaps are the capabilities that the helper binaries may need.
+var helperCaps = []Cap{NetAdmin, NetRaw}
+
+// ConfigureCmd makes the capabilities that Felix has for the privileged operations available
+// to the helper binary that cmd runs.  Root keeps its capabilities across exec but a non-root
+// Felix, that got its capabilities from the file capabilities of its binary, has to raise them
+// as ambient capabilities for the helper to inherit them.
+func ConfigureCmd(cmd *exec.Cmd) {
+	if os.Geteuid() == 0 {
+		return
+	}
+	ambientOnce.Do(func() {
+		have, err := Effective()
+		if err != nil {
+			log.WithError(err).Warn("Failed to read the process capabilities, helper binaries may fail.")
+			return
+		}
+		for _, c := range helperCaps {
+			if have.Has(c) {
+				ambientCaps = append(ambientCaps, uintptr(c))
+			}
+		}
+		log.WithField("caps", ambientCaps).Info("Running as non-root, passing capabilities to helper binaries.")
+	})
+	if len(ambientCaps) == 0 {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.AmbientCaps = ambientCaps
+}
+
+// CheckRequired logs the capabilities that the dataplane needs but that the process does not
+// have.  Felix carries on regardless, the operations that need them fail and are retried.
+func CheckRequired(bpfEnabled bool) {
+	have, err := Effective()
+	if err != nil {
+		log.WithError(err).Warn("Failed to read the process capabilities.")
+		return
+	}
+	missing := Missing(have, Required(bpfEnabled))
+	if len(missing) > 0 {
+		log.WithFields(log.Fields{
+			"missing":    missing,
+			"bpfEnabled": bpfEnabled,
+		}).Warn("Felix is missing capabilities that the dataplane needs, it is likely to fail.")
+		return
+	}
+	log.Info("Felix has the capabilities that the dataplane needs.")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capabilities
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMissingIptables(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(Missing(Set(0).With(NetAdmin, NetRaw), Required(false))).To(BeEmpty())
+	Expect(Missing(Set(0).With(NetRaw), Required(false))).To(Equal([]Cap{NetAdmin}))
+}
+
+func TestMissingBPF(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(Missing(Set(0).With(NetAdmin, NetRaw, BPF, Perfmon, SysResource), Required(true))).To(BeEmpty())
+	Expect(Missing(Set(0).With(NetAdmin, NetRaw), Required(true))).To(Equal([]Cap{SysResource, Perfmon, BPF}))
+
+	// CAP_SYS_ADMIN covers the BPF capabilities on older kernels.
+	Expect(Missing(Set(0).With(NetAdmin, NetRaw, SysAdmin, SysResource), Required(true))).To(BeEmpty())
+}
+
+func TestCapString(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(BPF.String()).To(Equal("CAP_BPF"))
+	Expect(Cap(7).String()).To(Equal("CAP_7"))
+}
+
+func TestProcSysPath(t *testing.T) {
+	RegisterTestingT(t)
+	defer SetProcSysPath(defaultProcSysPath)
+
+	Expect(ProcSysPath("/proc/sys/net/ipv4/ip_forward")).To(Equal("/proc/sys/net/ipv4/ip_forward"))
+
+	SetProcSysPath("/host/proc/sys/")
+	Expect(ProcSysPath("/proc/sys/net/ipv4/ip_forward")).To(Equal("/host/proc/sys/net/ipv4/ip_forward"))
+	Expect(ProcSysPath("/proc/self/status")).To(Equal("/proc/self/status"))
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capabilities
+
+import "os/exec"
+
+// ConfigureCmd is a no-op on Windows, which has no Linux capabilities.
+func ConfigureCmd(*exec.Cmd) {}
+
+// CheckRequired is a no-op on Windows, which has no Linux capabilities.
+func CheckRequired(bool) {}
@@ -40,6 +40,7 @@ import (
 func init() {
 	conntrackCmd.AddCommand(newConntrackDumpCmd())
 	conntrackCmd.AddCommand(newConntrackRemoveCmd())
+	conntrackCmd.AddCommand(newConntrackFlushCmd())
 	conntrackCmd.AddCommand(&cobra.Command{
 		Use:   "clean",
 		Short: "Clean all  conntrack entries",
@@ -131,9 +132,9 @@ func (cmd *conntrackDumpCmd) Run(c *cobra.Command, _ []string) {
 		ctMap = conntrack.MapV2()
 	default:
 		if cmd.ipv6 {
-			ctMap = conntrack.MapV6()
+			ctMap = conntrack.MapV6WithShards()
 		} else {
-			ctMap = conntrack.Map()
+			ctMap = conntrack.MapWithShards()
 		}
 	}
 	if err := ctMap.Open(); err != nil {
@@ -383,15 +384,83 @@ func (cmd *conntrackRemoveCmd) Run(c *cobra.Command, _ []string) {
 	}
 }
 
+type conntrackFlushCmd struct {
+	*cobra.Command
+
+	IPs []string `docopt:"<ip>"`
+
+	ipsV4 []net.IP
+	ipsV6 []net.IP
+}
+
+func newConntrackFlushCmd() *cobra.Command {
+	cmd := &conntrackFlushCmd{
+		Command: &cobra.Command{
+			Use:   "flush <ip>...",
+			Short: "removes connection tracking of all flows involving the given IPs",
+		},
+	}
+
+	cmd.Command.Args = cmd.Args
+	cmd.Command.Run = cmd.Run
+
+	return cmd.Command
+}
+
+func (cmd *conntrackFlushCmd) Args(c *cobra.Command, args []string) error {
+	a, err := docopt.ParseArgs(makeDocUsage(c), args, "")
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	err = a.Bind(cmd)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	for _, s := range cmd.IPs {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return errors.Errorf("%q is not an ip", s)
+		}
+		if ip.To4() != nil {
+			cmd.ipsV4 = append(cmd.ipsV4, ip)
+		} else {
+			cmd.ipsV6 = append(cmd.ipsV6, ip)
+		}
+	}
+
+	return nil
+}
+
+func (cmd *conntrackFlushCmd) Run(c *cobra.Command, _ []string) {
+	if len(cmd.ipsV4) > 0 {
+		flushCtMap(conntrack.MapWithShards(), conntrack.KeyFromBytes, conntrack.ValueFromBytes, cmd.ipsV4)
+	}
+	if len(cmd.ipsV6) > 0 {
+		flushCtMap(conntrack.MapV6WithShards(), conntrack.KeyV6FromBytes, conntrack.ValueV6FromBytes, cmd.ipsV6)
+	}
+}
+
+func flushCtMap(ctMap maps.Map, kfb func([]byte) conntrack.KeyInterface,
+	vfb func([]byte) conntrack.ValueInterface, ips []net.IP) {
+
+	if err := ctMap.Open(); err != nil {
+		log.WithError(err).Fatal("Failed to access ConntrackMap")
+	}
+
+	conntrack.NewScanner(ctMap, kfb, vfb, conntrack.NewIPFlushScanner(ips)).Scan()
+}
+
 func runClean(c *cobra.Command, _ []string) {
 	var (
 		ctMap maps.Map
 	)
 
 	if ipv6 != nil && *ipv6 {
-		ctMap = conntrack.MapV6()
+		ctMap = conntrack.MapV6WithShards()
 	} else {
-		ctMap = conntrack.Map()
+		ctMap = conntrack.MapWithShards()
 	}
 
 	if err := ctMap.Open(); err != nil {
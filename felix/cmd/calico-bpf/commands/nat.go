@@ -16,6 +16,7 @@ package commands
 
 import (
 	"net"
+	"os"
 	"strconv"
 	"strings"
 
@@ -28,6 +29,10 @@ import (
 )
 
 func init() {
+	natDumpCmd.Flags().Bool("resolve", false,
+		"show the services and pods of the entries, from the Services and EndpointSlices of the cluster")
+	natDumpCmd.Flags().String("kubeconfig", os.Getenv("KUBECONFIG"),
+		"kubeconfig of the cluster, the in-cluster config is used if not set")
 	natCmd.AddCommand(natDumpCmd)
 	natCmd.AddCommand(natAffDumpCmd)
 
@@ -96,6 +101,14 @@ func dumpAff(cmd *cobra.Command) (err error) {
 }
 
 func dump(cmd *cobra.Command) error {
+	var names *natNames
+	if resolve, _ := cmd.Flags().GetBool("resolve"); resolve {
+		var err error
+		if names, err = loadNATNames(cmd); err != nil {
+			return err
+		}
+	}
+
 	if ipv6 != nil && *ipv6 {
 		natMap, err := nat.LoadFrontendMapV6(nat.FrontendMapV6())
 		if err != nil {
@@ -107,7 +120,7 @@ func dump(cmd *cobra.Command) error {
 			return err
 		}
 
-		dumpNice[nat.FrontendKeyV6, nat.BackendValueV6](cmd.Printf, natMap, back)
+		dumpNice[nat.FrontendKeyV6, nat.BackendValueV6](cmd.Printf, natMap, back, names)
 	} else {
 		natMap, err := nat.LoadFrontendMap(nat.FrontendMap())
 		if err != nil {
@@ -119,7 +132,7 @@ func dump(cmd *cobra.Command) error {
 			return err
 		}
 
-		dumpNice[nat.FrontendKey, nat.BackendValue](cmd.Printf, natMap, back)
+		dumpNice[nat.FrontendKey, nat.BackendValue](cmd.Printf, natMap, back, names)
 	}
 	return nil
 }
@@ -127,7 +140,7 @@ func dump(cmd *cobra.Command) error {
 type printfFn func(format string, i ...interface{})
 
 func dumpNice[FK nat.FrontendKeyComparable, BV nat.BackendValueInterface](printf printfFn,
-	natMap map[FK]nat.FrontendValue, back map[nat.BackendKey]BV) {
+	natMap map[FK]nat.FrontendValue, back map[nat.BackendKey]BV, names *natNames) {
 	for nk, nv := range natMap {
 		valCount := nv.Count()
 		count := int(valCount)
@@ -140,8 +153,12 @@ func dumpNice[FK nat.FrontendKeyComparable, BV nat.BackendValueInterface](printf
 		if flags != "" {
 			flags = " flags " + flags
 		}
-		printf("%s port %d proto %d id %d count %d local %d%s\n",
-			nk.Addr(), nk.Port(), nk.Proto(), id, count, local, flags)
+		svc := ""
+		if name := names.frontend(nk.Addr(), nk.Port(), nk.Proto()); name != "" {
+			svc = " svc " + name
+		}
+		printf("%s port %d proto %d id %d count %d local %d%s%s\n",
+			nk.Addr(), nk.Port(), nk.Proto(), id, count, local, flags, svc)
 		for i := 0; i < count; i++ {
 			bk := nat.NewNATBackendKey(id, uint32(i))
 			bv, ok := back[bk]
@@ -149,12 +166,16 @@ func dumpNice[FK nat.FrontendKeyComparable, BV nat.BackendValueInterface](printf
 			if !ok {
 				printf("is missing\n")
 			} else {
-				fmtStr := "%s:%d\n"
+				fmtStr := "%s:%d"
 				// Use "[]" with IPv6 addresses
 				if bv.Addr().To4() == nil {
-					fmtStr = "[%s]:%d\n"
+					fmtStr = "[%s]:%d"
 				}
 				printf(fmtStr, bv.Addr(), bv.Port())
+				if pod := names.backend(bv.Addr()); pod != "" {
+					printf(" pod %s", pod)
+				}
+				printf("\n")
 			}
 		}
 	}
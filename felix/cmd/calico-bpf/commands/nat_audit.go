@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"os"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+)
+
+func init() {
+	natAuditCmd.Flags().String("kubeconfig", os.Getenv("KUBECONFIG"),
+		"kubeconfig of the cluster, the in-cluster config is used if not set")
+	natAuditCmd.Flags().Bool("ipv6-enabled", false, "audit for a BPF dataplane with IPv6 enabled")
+	natAuditCmd.Flags().Int("backends-hard-limit", 0, "hard limit of the number of backends per service, 0 for none")
+	natCmd.AddCommand(natAuditCmd)
+}
+
+var natAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "reports the services that the BPF kube-proxy replacement would handle differently",
+	Long: "audit lists the services of the cluster and reports, without programming anything, " +
+		"the service ports that the BPF kube-proxy replacement would handle differently from " +
+		"kube-proxy, so that it can be run while kube-proxy is still active to check that a " +
+		"cluster is ready for the migration.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := natAudit(cmd); err != nil {
+			log.WithError(err).Error("Failed to audit the services")
+		}
+	},
+}
+
+func natAudit(cmd *cobra.Command) error {
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	ipv6Enabled, _ := cmd.Flags().GetBool("ipv6-enabled")
+	backendsHard, _ := cmd.Flags().GetInt("backends-hard-limit")
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	k8s, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	svcList, err := k8s.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sliceList, err := k8s.DiscoveryV1().EndpointSlices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var svcs []*v1.Service
+	for i := range svcList.Items {
+		svcs = append(svcs, &svcList.Items[i])
+	}
+	var slices []*discovery.EndpointSlice
+	for i := range sliceList.Items {
+		slices = append(slices, &sliceList.Items[i])
+	}
+
+	r := proxy.Audit(svcs, slices, proxy.AuditOptions{
+		IPv6Enabled: ipv6Enabled,
+		Limits:      proxy.ServiceLimits{BackendsHard: backendsHard},
+	})
+	printAuditReport(cmd, r)
+	return nil
+}
+
+func printAuditReport(cmd *cobra.Command, r *proxy.AuditReport) {
+	if len(r.Findings) > 0 {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+		_, _ = w.Write([]byte("SERVICE\tPORT\tKIND\tDETAIL\n"))
+		for _, f := range r.Findings {
+			_, _ = w.Write([]byte(f.Service.String() + "\t" + f.Port + "\t" + string(f.Kind) + "\t" + f.Detail + "\n"))
+		}
+		_ = w.Flush()
+		cmd.Printf("\n")
+	}
+
+	status := "ready"
+	if !r.Ready() {
+		status = "not ready"
+	}
+	cmd.Printf("Audited %d service ports, %d findings. Migration readiness: %s\n",
+		r.ServicePorts, len(r.Findings), status)
+}
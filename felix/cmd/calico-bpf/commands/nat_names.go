@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type natFrontendRef struct {
+	addr  string
+	port  uint16
+	proto uint8
+}
+
+type natNodePortRef struct {
+	port  uint16
+	proto uint8
+}
+
+// natNames resolves the entries of the NAT maps to the services and pods they were programmed for.
+type natNames struct {
+	frontends map[natFrontendRef]string
+	nodePorts map[natNodePortRef]string
+	backends  map[string]string
+}
+
+func loadNATNames(cmd *cobra.Command) (*natNames, error) {
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	k8s, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	svcList, err := k8s.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sliceList, err := k8s.DiscoveryV1().EndpointSlices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return newNATNames(svcList.Items, sliceList.Items), nil
+}
+
+func newNATNames(svcs []v1.Service, slices []discovery.EndpointSlice) *natNames {
+	n := &natNames{
+		frontends: map[natFrontendRef]string{},
+		nodePorts: map[natNodePortRef]string{},
+		backends:  map[string]string{},
+	}
+
+	for _, svc := range svcs {
+		addrs := append([]string{}, svc.Spec.ClusterIPs...)
+		addrs = append(addrs, svc.Spec.ExternalIPs...)
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if ing.IP != "" {
+				addrs = append(addrs, ing.IP)
+			}
+		}
+		for _, p := range svc.Spec.Ports {
+			name := svc.Namespace + "/" + svc.Name
+			if p.Name != "" {
+				name += ":" + p.Name
+			}
+			proto := natProtoNumber(p.Protocol)
+			for _, a := range addrs {
+				if ip := net.ParseIP(a); ip != nil {
+					n.frontends[natFrontendRef{addr: ip.String(), port: uint16(p.Port), proto: proto}] = name
+				}
+			}
+			if p.NodePort != 0 {
+				n.nodePorts[natNodePortRef{port: uint16(p.NodePort), proto: proto}] = name
+			}
+		}
+	}
+
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" {
+				continue
+			}
+			ns := ep.TargetRef.Namespace
+			if ns == "" {
+				ns = slice.Namespace
+			}
+			for _, a := range ep.Addresses {
+				if ip := net.ParseIP(a); ip != nil {
+					n.backends[ip.String()] = ns + "/" + ep.TargetRef.Name
+				}
+			}
+		}
+	}
+
+	return n
+}
+
+// frontend returns the service of a frontend, or "" if it is not known.  Frontends that don't
+// match an address of a service are matched against the node ports.
+func (n *natNames) frontend(addr net.IP, port uint16, proto uint8) string {
+	if n == nil {
+		return ""
+	}
+	if name, ok := n.frontends[natFrontendRef{addr: addr.String(), port: port, proto: proto}]; ok {
+		return name
+	}
+	if name, ok := n.nodePorts[natNodePortRef{port: port, proto: proto}]; ok {
+		return fmt.Sprintf("%s (nodeport)", name)
+	}
+	return ""
+}
+
+// backend returns the pod of a backend, or "" if it is not known.
+func (n *natNames) backend(addr net.IP) string {
+	if n == nil {
+		return ""
+	}
+	return n.backends[addr.String()]
+}
+
+func natProtoNumber(p v1.Protocol) uint8 {
+	switch p {
+	case v1.ProtocolUDP:
+		return 17
+	case v1.ProtocolSCTP:
+		return 132
+	}
+	return 6
+}
@@ -15,11 +15,20 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"net"
 	"testing"
 
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
 	nat2 "github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/proxy"
 )
 
 func TestNATDump(t *testing.T) {
@@ -35,5 +44,73 @@ func TestNATDump(t *testing.T) {
 		nat2.NewNATBackendKey(108, 0): nat2.NewNATBackendValue(net.IPv4(3, 3, 3, 3), 553),
 	}
 
-	dumpNice(func(format string, i ...interface{}) { fmt.Printf(format, i...) }, nat, back)
+	dumpNice(func(format string, i ...interface{}) { fmt.Printf(format, i...) }, nat, back, nil)
+}
+
+func TestNATDumpResolved(t *testing.T) {
+	RegisterTestingT(t)
+
+	nat := nat2.MapMem{
+		nat2.NewNATKey(net.IPv4(10, 96, 0, 10), 53, 17):    nat2.NewNATValue(1, 1, 0, 0),
+		nat2.NewNATKey(net.IPv4(192, 168, 0, 1), 30080, 6): nat2.NewNATValue(2, 1, 0, 0),
+	}
+	back := nat2.BackendMapMem{
+		nat2.NewNATBackendKey(1, 0): nat2.NewNATBackendValue(net.IPv4(10, 65, 0, 2), 53),
+		nat2.NewNATBackendKey(2, 0): nat2.NewNATBackendValue(net.IPv4(10, 65, 0, 3), 8080),
+	}
+	names := newNATNames([]v1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-dns"},
+			Spec: v1.ServiceSpec{
+				ClusterIPs: []string{"10.96.0.10"},
+				Ports:      []v1.ServicePort{{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: v1.ServiceSpec{
+				ClusterIPs: []string{"10.96.0.20"},
+				Ports:      []v1.ServicePort{{Port: 80, NodePort: 30080, Protocol: v1.ProtocolTCP}},
+			},
+		},
+	}, []discovery.EndpointSlice{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-dns-abcde"},
+		Endpoints: []discovery.Endpoint{{
+			Addresses: []string{"10.65.0.2"},
+			TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "coredns-1"},
+		}},
+	}})
+
+	var out bytes.Buffer
+	dumpNice(func(format string, i ...interface{}) { fmt.Fprintf(&out, format, i...) }, nat, back, names)
+
+	Expect(out.String()).To(ContainSubstring(
+		"10.96.0.10 port 53 proto 17 id 1 count 1 local 0 svc kube-system/kube-dns:dns\n" +
+			"\t1:0\t 10.65.0.2:53 pod kube-system/coredns-1\n"))
+	Expect(out.String()).To(ContainSubstring(
+		"192.168.0.1 port 30080 proto 6 id 2 count 1 local 0 svc default/web (nodeport)\n" +
+			"\t2:0\t 10.65.0.3:8080\n"))
+}
+
+func TestNATAuditReport(t *testing.T) {
+	RegisterTestingT(t)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	printAuditReport(cmd, &proxy.AuditReport{
+		ServicePorts: 3,
+		Findings: []proxy.AuditFinding{{
+			Service: types.NamespacedName{Namespace: "default", Name: "web"},
+			Port:    "http",
+			Kind:    proxy.AuditExcluded,
+			Detail:  "excluded",
+		}},
+	})
+
+	Expect(out.String()).To(Equal(
+		"SERVICE      PORT  KIND      DETAIL\n" +
+			"default/web  http  Excluded  excluded\n" +
+			"\n" +
+			"Audited 3 service ports, 1 findings. Migration readiness: not ready\n"))
 }
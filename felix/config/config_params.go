@@ -54,6 +54,11 @@ var (
 	IfaceParamRegexp         = regexp.MustCompile(`^[a-zA-Z0-9:._+-]{1,15}$`)
 	// Hostname  have to be valid ipv4, ipv6 or strings up to 64 characters.
 	HostAddressRegexp = regexp.MustCompile(`^[a-zA-Z0-9:._+-]{1,64}$`)
+	// AuthorityListRegexp matches a comma-separated list of host:port, where the host may also be an
+	// IPv6 address in brackets.
+	AuthorityListRegexp = regexp.MustCompile(`^(\[[0-9a-fA-F:.]+\]|[^:/,\[\]]+):\d+(,(\[[0-9a-fA-F:.]+\]|[^:/,\[\]]+):\d+)*$`)
+	// InstanceNameRegexp matches the name of a Felix instance, which is used in paths.
+	InstanceNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
 )
 
 // Source of a config value.  Values from higher-numbered sources override
@@ -64,13 +69,14 @@ type Source uint8
 const (
 	Default Source = iota
 	DatastoreGlobal
+	DatastoreNodeSelector
 	DatastorePerHost
 	ConfigFile
 	EnvironmentVariable
 	InternalOverride
 )
 
-var SourcesInDescendingOrder = []Source{InternalOverride, EnvironmentVariable, ConfigFile, DatastorePerHost, DatastoreGlobal}
+var SourcesInDescendingOrder = []Source{InternalOverride, EnvironmentVariable, ConfigFile, DatastorePerHost, DatastoreNodeSelector, DatastoreGlobal}
 
 func (source Source) String() string {
 	switch source {
@@ -78,6 +84,8 @@ func (source Source) String() string {
 		return "<default>"
 	case DatastoreGlobal:
 		return "datastore (global)"
+	case DatastoreNodeSelector:
+		return "datastore (node selector)"
 	case DatastorePerHost:
 		return "datastore (per-host)"
 	case ConfigFile:
@@ -156,6 +164,41 @@ type Config struct {
 	UseInternalDataplaneDriver bool          `config:"bool;true"`
 	DataplaneDriver            string        `config:"file(must-exist,executable);calico-iptables-plugin;non-zero,die-on-fail,skip-default-validation"`
 	DataplaneWatchdogTimeout   time.Duration `config:"seconds;90"`
+	DataplaneShutdownMode      string        `config:"oneof(Retain,Cleanup);Retain"`
+
+	// OperationMode selects whether Felix programs the pod network, enforces policy or both; see
+	// PolicyEnabled and NetworkingEnabled.
+	OperationMode string `config:"oneof(Full,PolicyOnly,NetworkingOnly);Full;non-zero"`
+
+	// DataplaneApplyPreHook and DataplaneApplyPostHook are run before and after each
+	// dataplane programming transaction with a JSON summary of the changes.  Each is
+	// either the absolute path of an executable, which gets the summary on stdin, or an
+	// http(s) URL, which gets it POSTed.
+	DataplaneApplyPreHook     string        `config:"string;;local"`
+	DataplaneApplyPostHook    string        `config:"string;;local"`
+	DataplaneApplyHookTimeout time.Duration `config:"seconds;5;local"`
+
+	// ProcSysPath is where Felix writes the /proc/sys settings.  Without full privileges
+	// the container's /proc/sys is read-only, so the host's /proc/sys has to be mounted
+	// elsewhere.
+	ProcSysPath string `config:"file;/proc/sys;non-zero,local"`
+
+	// InstanceName names this Felix when several run on one host, for example to try a new
+	// dataplane configuration next to the current one.  It moves the BPF pins and the files
+	// under RuntimeDir to directories of the instance.  The dataplane itself, such as the
+	// iptables chains, marks and routing tables, must still be configured not to overlap.
+	InstanceName string `config:"instance-name;;local"`
+	// BPFFilesystemPath is where the BPF filesystem is mounted.
+	BPFFilesystemPath string `config:"file;/sys/fs/bpf;non-zero,local"`
+	// CgroupV2Path is where the cgroup v2 filesystem is mounted for the connect-time load
+	// balancer.  Defaults to $CALICO_CGROUP_PATH or /run/calico/cgroup.
+	CgroupV2Path string `config:"file;;local"`
+	// RuntimeDir holds the runtime files and sockets of Felix, such as the L7 proxy verdict
+	// socket and the BPF debug files.
+	RuntimeDir string `config:"file;/var/run/calico;non-zero,local"`
+	// MTUFilePath is where Felix writes the pod MTU for the CNI plugin, so it is shared by the
+	// instances of a host.
+	MTUFilePath string `config:"file;/var/lib/calico/mtu;non-zero,local"`
 
 	// Wireguard configuration
 	WireguardEnabled               bool          `config:"bool;false"`
@@ -177,6 +220,9 @@ type Config struct {
 	BPFCTLBLogFilter                   string            `config:"oneof(all);;"`
 	BPFDataIfacePattern                *regexp.Regexp    `config:"regexp;^((en|wl|ww|sl|ib)[Popsx].*|(eth|wlan|wwan|bond).*|tunl0$|vxlan.calico$|vxlan-v6.calico$|wireguard.cali$|wg-v6.cali$)"`
 	BPFL3IfacePattern                  *regexp.Regexp    `config:"regexp;"`
+	BPFAttachIncludeIfaces             []*regexp.Regexp  `config:"iface-list-regexp;"`
+	BPFAttachExcludeIfaces             []*regexp.Regexp  `config:"iface-list-regexp;"`
+	BPFHardwareOffloadIfaces           []*regexp.Regexp  `config:"iface-list-regexp;"`
 	BPFConnectTimeLoadBalancingEnabled bool              `config:"bool;;"`
 	BPFConnectTimeLoadBalancing        string            `config:"oneof(TCP,Enabled,Disabled);TCP;non-zero"`
 	BPFHostNetworkedNATWithoutCTLB     string            `config:"oneof(Enabled,Disabled);Enabled;non-zero"`
@@ -185,6 +231,9 @@ type Config struct {
 	BPFKubeProxyIptablesCleanupEnabled bool              `config:"bool;true"`
 	BPFKubeProxyMinSyncPeriod          time.Duration     `config:"seconds;1"`
 	BPFKubeProxyEndpointSlicesEnabled  bool              `config:"bool;true"`
+	BPFKubeProxyHealthzBindAddress     string            `config:"string;"`
+	BPFKubeProxyMetricsBindAddress     string            `config:"string;"`
+	BPFNodePortLinkLocalEnabled        bool              `config:"bool;false"`
 	BPFExtToServiceConnmark            int               `config:"int;0"`
 	BPFPSNATPorts                      numorstring.Port  `config:"portrange;20000:29999"`
 	BPFMapSizeNATFrontend              int               `config:"int;65536;non-zero"`
@@ -192,14 +241,28 @@ type Config struct {
 	BPFMapSizeNATAffinity              int               `config:"int;65536;non-zero"`
 	BPFMapSizeRoute                    int               `config:"int;262144;non-zero"`
 	BPFMapSizeConntrack                int               `config:"int;512000;non-zero"`
+	BPFConntrackShards                 int               `config:"int(1,8);1;non-zero"`
 	BPFMapSizeIPSets                   int               `config:"int;1048576;non-zero"`
 	BPFMapSizeIfState                  int               `config:"int;1000;non-zero"`
+	BPFMemoryBudgetMB                  int               `config:"int;0"`
+	BPFMapShrinkPolicy                 string            `config:"oneof(Refuse,Truncate);Refuse;non-zero"`
 	BPFHostConntrackBypass             bool              `config:"bool;true"`
 	BPFEnforceRPF                      string            `config:"oneof(Disabled,Strict,Loose);Loose;non-zero"`
+	BPFAttachType                      string            `config:"oneof(TC,TCX);TCX;non-zero"`
+	BPFFragmentHandling                string            `config:"oneof(Translate,Drop);Translate;non-zero"`
+	BPFNAT64Enabled                    bool              `config:"bool;false"`
+	BPFNAT64Prefix                     string            `config:"string;64:ff9b::/96;non-zero"`
+	BPFRoutesMirrorTableIndex          int               `config:"int(0,4294967295);0"`
 	BPFPolicyDebugEnabled              bool              `config:"bool;true"`
+	BPFPolicyBitmapsEnabled            bool              `config:"bool;false"`
 	BPFForceTrackPacketsFromIfaces     []string          `config:"iface-filter-slice;docker+"`
 	BPFDisableGROForIfaces             *regexp.Regexp    `config:"regexp;"`
 	BPFExcludeCIDRsFromNAT             []string          `config:"cidr-list;;"`
+	BPFNodePortAddresses               []string          `config:"cidr-list;;"`
+	BPFServiceBackendsSoftLimit        int               `config:"int;0"`
+	BPFServiceBackendsHardLimit        int               `config:"int;0"`
+	BPFServiceFrontendsSoftLimit       int               `config:"int;0"`
+	BPFServiceFrontendsHardLimit       int               `config:"int;0"`
 
 	// DebugBPFCgroupV2 controls the cgroup v2 path that we apply the connect-time load balancer to.  Most distros
 	// are configured for cgroup v1, which prevents all but the root cgroup v2 from working so this is only useful
@@ -220,17 +283,29 @@ type Config struct {
 	EtcdCaFile    string   `config:"file(must-exist);;local"`
 	EtcdEndpoints []string `config:"endpoint-list;;local"`
 
-	TyphaAddr           string        `config:"authority;;local"`
+	// TyphaAddr is a comma-separated list of Typha addresses, which Felix tries in order.
+	TyphaAddr           string        `config:"authority-list;;local"`
 	TyphaK8sServiceName string        `config:"string;;local"`
 	TyphaK8sNamespace   string        `config:"string;kube-system;non-zero,local"`
 	TyphaReadTimeout    time.Duration `config:"seconds;30;local"`
 	TyphaWriteTimeout   time.Duration `config:"seconds;10;local"`
 
+	// TyphaAddressFamily is the IP family of the Typha addresses that Felix tries first when it
+	// discovers Typha instances with addresses of both families.  Whatever the preference, Felix
+	// deprioritises the family whose connections recently failed.
+	TyphaAddressFamily string `config:"oneof(Any,IPv4,IPv6);Any;local"`
+
+	// TyphaK8sEndpointSlicesOnly stops Typha discovery from falling back to the Endpoints API
+	// when the EndpointSlice API is not available.
+	TyphaK8sEndpointSlicesOnly bool `config:"bool;false;local"`
+
 	// Client-side TLS config for Felix's communication with Typha.  If any of these are
 	// specified, they _all_ must be - except that either TyphaCN or TyphaURISAN may be left
 	// unset.  Felix will then initiate a secure (TLS) connection to Typha.  Typha must present
 	// a certificate signed by a CA in TyphaCAFile, and with CN matching TyphaCN or URI SAN
-	// matching TyphaURISAN.
+	// matching TyphaURISAN.  If TyphaURISAN is a SPIFFE ID, Typha's certificate must also be a
+	// valid X.509-SVID.  The files are read on each connection attempt, so rotated certificates
+	// are picked up when Felix reconnects to Typha.
 	TyphaKeyFile  string `config:"file(must-exist);;local"`
 	TyphaCertFile string `config:"file(must-exist);;local"`
 	TyphaCAFile   string `config:"file(must-exist);;local"`
@@ -264,11 +339,50 @@ type Config struct {
 	MetadataAddr string `config:"hostname;127.0.0.1;die-on-fail"`
 	MetadataPort int    `config:"int(0,65535);8775;die-on-fail"`
 
+	EgressProxyAddress          string             `config:"host-address;"`
+	EgressProxyPort             int                `config:"int(0,65535);3128"`
+	EgressProxySelector         string             `config:"string;"`
+	EgressProxyDestinationPorts []numorstring.Port `config:"portrange-list;80,443"`
+	EgressProxyExcludeCIDRs     []string           `config:"cidr-list;;"`
+
+	L7ProxySelector          string             `config:"string;"`
+	L7ProxyPort              int                `config:"int(1,65535);16001"`
+	L7ProxyDestinationPorts  []numorstring.Port `config:"portrange-list;80"`
+	L7ProxyVerdictSocketPath string             `config:"file;/var/run/calico/l7-verdicts.sock"`
+
+	DNSTrustedServers   []string `config:"cidr-list;;"`
+	DNSPolicyNFLOGGroup int      `config:"int(1,65535);41"`
+
+	// DNSLogsEnabled logs the snooped DNS responses to a rotating file, or to the TCP collector
+	// at DNSLogsCollectorAddress if set.
+	DNSLogsEnabled          bool   `config:"bool;false"`
+	DNSLogsFilePath         string `config:"file;/var/log/calico/dns/dns.log"`
+	DNSLogsFileMaxSize      int    `config:"int(1,);100"`
+	DNSLogsFileMaxFiles     int    `config:"int(0,);5"`
+	DNSLogsCollectorAddress string `config:"authority;"`
+
+	// FlowLogsEnabled aggregates the connections of the local workloads into flow logs, which
+	// are written to a rotating file, or to the OTLP collector at FlowLogsOTLPAddress if set.
+	FlowLogsEnabled       bool          `config:"bool;false"`
+	FlowLogsFlushInterval time.Duration `config:"seconds;300"`
+	FlowLogsAggregation   string        `config:"oneof(None,SourcePort);SourcePort;non-zero"`
+	FlowLogsSampleRate    int           `config:"int(1,);1"`
+	FlowLogsFilePath      string        `config:"file;/var/log/calico/flowlogs/flows.log"`
+	FlowLogsFileMaxSize   int           `config:"int(1,);100"`
+	FlowLogsFileMaxFiles  int           `config:"int(0,);5"`
+	FlowLogsOTLPAddress   string        `config:"authority;"`
+	FlowLogsNFLOGGroup    int           `config:"int(1,65535);42"`
+	// FlowLogsKTLSAccountingEnabled detects the kTLS sockets of the host, whose traffic may
+	// bypass conntrack.
+	FlowLogsKTLSAccountingEnabled bool `config:"bool;false"`
+
 	OpenstackRegion string `config:"region;;die-on-fail"`
 
 	InterfacePrefix  string           `config:"iface-list;cali;non-zero,die-on-fail"`
 	InterfaceExclude []*regexp.Regexp `config:"iface-list-regexp;kube-ipvs0"`
 
+	VirtualInterfaceHostEndpointsEnabled bool `config:"bool;false"`
+
 	ChainInsertMode             string `config:"oneof(insert,append);insert;non-zero,die-on-fail"`
 	DefaultEndpointToHostAction string `config:"oneof(DROP,RETURN,ACCEPT);DROP;non-zero,die-on-fail"`
 	IptablesFilterAllowAction   string `config:"oneof(ACCEPT,RETURN);ACCEPT;non-zero,die-on-fail"`
@@ -276,6 +390,12 @@ type Config struct {
 	IptablesFilterDenyAction    string `config:"oneof(DROP,REJECT);DROP;non-zero,die-on-fail"`
 	LogPrefix                   string `config:"string;calico-packet"`
 
+	// PolicyLogFilePath is the file that the policy rules with the File log target log to.
+	// PolicyLogNFLOGGroup is the NFLOG group that the iptables rules use to pass the packets
+	// logged to syslog, the journal or a file to Felix.
+	PolicyLogFilePath   string `config:"file;/var/log/calico/policy.log"`
+	PolicyLogNFLOGGroup int    `config:"int(1,65535);40"`
+
 	LogFilePath string `config:"file;/var/log/calico/felix.log;die-on-fail"`
 
 	LogSeverityFile   string `config:"oneof(DEBUG,INFO,WARNING,ERROR,FATAL);INFO"`
@@ -285,6 +405,9 @@ type Config struct {
 	// Only logs from files with names that match the given regular expression are included.  The filter only applies
 	// to Debug level logs.
 	LogDebugFilenameRegex *regexp.Regexp `config:"regexp(nil-on-empty);"`
+	// LogFormat is the format of the screen and file logs: Default, or one of the structured
+	// formats, Logfmt or JSON.
+	LogFormat string `config:"oneof(Default,Logfmt,JSON);Default"`
 
 	// Optional: VXLAN encap is now determined by the existing IP pools (Encapsulation struct)
 	VXLANEnabled         *bool  `config:"*bool;"`
@@ -335,6 +458,15 @@ type Config struct {
 	// [Default: ""]
 	EndpointStatusPathPrefix string `config:"file;;"`
 
+	// EndpointStatusConditionsEnabled enables reporting the status of the local workload
+	// endpoints as a condition of their pods.  Requires the Kubernetes datastore.
+	EndpointStatusConditionsEnabled bool `config:"bool;false"`
+
+	// NodeInterfacesReportingInterval is the period at which Felix updates the host interfaces of
+	// its node in the datastore, for the host endpoint templates of kube-controllers.  Zero disables
+	// the reporting.
+	NodeInterfacesReportingInterval time.Duration `config:"seconds;30"`
+
 	IptablesMarkMask uint32 `config:"mark-bitmask;0xffff0000;non-zero,die-on-fail"`
 
 	DisableConntrackInvalidCheck bool `config:"bool;false"`
@@ -350,9 +482,14 @@ type Config struct {
 	PrometheusGoMetricsEnabled        bool   `config:"bool;true"`
 	PrometheusProcessMetricsEnabled   bool   `config:"bool;true"`
 	PrometheusWireGuardMetricsEnabled bool   `config:"bool;true"`
+	PrometheusPolicyMetricsEnabled    bool   `config:"bool;false"`
+
+	PrometheusPolicyVerdictMetricsEnabled   bool `config:"bool;false"`
+	PrometheusPolicyVerdictMetricsMaxSeries int  `config:"int(1,);1000"`
 
 	FailsafeInboundHostPorts  []ProtoPort `config:"port-list;tcp:22,udp:68,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:6666,tcp:6667;die-on-fail"`
 	FailsafeOutboundHostPorts []ProtoPort `config:"port-list;udp:53,udp:67,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:6666,tcp:6667;die-on-fail"`
+	FailsafeNDPEnabled        bool        `config:"bool;false"`
 
 	KubeNodePortRanges []numorstring.Port `config:"portrange-list;30000:32767"`
 	NATPortRange       numorstring.Port   `config:"portrange;"`
@@ -379,6 +516,14 @@ type Config struct {
 	DebugHost string `config:"host-address;localhost"`
 	// DebugPort is the port to bind the pprof debug server to or 0 to disable the debug port.
 	DebugPort int `config:"int(0,65535);"`
+	// DebugStateHost is the host to bind the debug state server to.  Only used if DebugStatePort is non-zero.
+	DebugStateHost string `config:"host-address;localhost"`
+	// DebugStatePort is the port to serve the calculation graph state of the local endpoints on, i.e. the
+	// tiers, policies and profiles that apply to each endpoint and their rules, or 0 to disable it.
+	DebugStatePort int `config:"int(0,65535);"`
+	// DebugStateTokenFile is the file holding the bearer token that requests to the debug state server must
+	// carry.  The server is not started without it.
+	DebugStateTokenFile string `config:"file(must-exist);;local"`
 
 	// Configure where Felix gets its routing information.
 	// - workloadIPs: use workload endpoints to construct routes.
@@ -420,6 +565,8 @@ type Config struct {
 	sourceToRawConfig map[Source]map[string]string
 	// rawValues maps keys to the current highest-priority raw value.
 	rawValues map[string]string
+	// rawValueSources maps the names of the known parameters in rawValues to the source of their value.
+	rawValueSources map[string]Source
 	// Err holds the most recent error from a config update.
 	Err error
 
@@ -453,6 +600,11 @@ func (config *Config) Copy() *Config {
 		cp.rawValues[k] = v
 	}
 
+	cp.rawValueSources = map[string]Source{}
+	for k, v := range config.rawValueSources {
+		cp.rawValueSources[k] = v
+	}
+
 	return &cp
 }
 
@@ -532,6 +684,33 @@ func (config *Config) IsLeader() bool {
 	return config.Variant == "Calico"
 }
 
+// PolicyEnabled returns whether Felix enforces policy, which it doesn't in the NetworkingOnly
+// operation mode.
+func (config *Config) PolicyEnabled() bool {
+	return config.OperationMode != "NetworkingOnly"
+}
+
+// NetworkingEnabled returns whether Felix programs the pod network, which it doesn't in the
+// PolicyOnly operation mode.
+func (config *Config) NetworkingEnabled() bool {
+	return config.OperationMode != "PolicyOnly"
+}
+
+// applyOperationMode overrides the parameters that the operation mode turns off, so that the
+// subsystems that they control stay disabled whatever else is configured.  IPIP and VXLAN are
+// handled by the EncapsulationCalculator.
+func (config *Config) applyOperationMode() {
+	if !config.NetworkingEnabled() {
+		config.WireguardEnabled = false
+		config.WireguardEnabledV6 = false
+		config.RouteSyncDisabled = true
+	}
+	if !config.PolicyEnabled() {
+		// Traffic from workloads to their host is allowed along with all other traffic.
+		config.DefaultEndpointToHostAction = "ACCEPT"
+	}
+}
+
 func (config *Config) InterfacePrefixes() []string {
 	return strings.Split(config.InterfacePrefix, ",")
 }
@@ -605,6 +784,7 @@ func (config *Config) resolve() (changedFields set.Set[string], err error) {
 	config.applyDefaults()
 
 	newRawValues := make(map[string]string)
+	newRawValueSources := make(map[string]Source)
 	// Map from lower-case version of name to the highest-priority source found so far.
 	// We use the lower-case version of the name since we can calculate it both for
 	// expected and "raw" parameters, which may be used by plugins.
@@ -682,10 +862,13 @@ func (config *Config) resolve() (changedFields set.Set[string], err error) {
 			field := reflect.ValueOf(config).Elem().FieldByName(name)
 			field.Set(reflect.ValueOf(value))
 			newRawValues[name] = rawValue
+			newRawValueSources[name] = source
 			nameToSource[lowerCaseName] = source
 		}
 	}
 
+	config.applyOperationMode()
+
 	log.WithField("changedFields", changedFields).Debug("Calculated changed fields.")
 	changedFields = set.New[string]()
 	kind := reflect.TypeOf(Config{})
@@ -706,6 +889,7 @@ func (config *Config) resolve() (changedFields set.Set[string], err error) {
 	}
 
 	config.rawValues = newRawValues
+	config.rawValueSources = newRawValueSources
 	return
 }
 
@@ -855,6 +1039,26 @@ func (config *Config) Validate() (err error) {
 		}
 	}
 
+	if config.BPFNAT64Enabled {
+		_, prefix, perr := net.ParseCIDR(config.BPFNAT64Prefix)
+		if perr != nil || prefix.IP.To4() != nil {
+			err = fmt.Errorf("BPFNAT64Prefix %q is not an IPv6 CIDR", config.BPFNAT64Prefix)
+		} else if ones, _ := prefix.Mask.Size(); ones != 96 {
+			err = fmt.Errorf("BPFNAT64Prefix %q must be a /96", config.BPFNAT64Prefix)
+		}
+	}
+
+	if idx := config.BPFRoutesMirrorTableIndex; idx != 0 {
+		if idx >= 253 && idx <= 255 {
+			err = fmt.Errorf("BPFRoutesMirrorTableIndex %d is reserved for the default, main and local tables", idx)
+		}
+		for _, r := range config.RouteTableIndices() {
+			if idx >= r.Min && idx <= r.Max {
+				err = fmt.Errorf("BPFRoutesMirrorTableIndex %d is within the RouteTableRanges", idx)
+			}
+		}
+	}
+
 	if err != nil {
 		config.Err = err
 	}
@@ -941,6 +1145,16 @@ func loadParams() {
 				Regexp: AuthorityRegexp,
 				Msg:    "invalid URL authority",
 			}
+		case "instance-name":
+			param = &RegexpParam{
+				Regexp: InstanceNameRegexp,
+				Msg:    "invalid instance name",
+			}
+		case "authority-list":
+			param = &RegexpParam{
+				Regexp: AuthorityListRegexp,
+				Msg:    "invalid list of URL authorities",
+			}
 		case "ipv4":
 			param = &Ipv4Param{}
 		case "ipv6":
@@ -1102,6 +1316,7 @@ func New() *Config {
 	}
 	p := &Config{
 		rawValues:         map[string]string{},
+		rawValueSources:   map[string]Source{},
 		sourceToRawConfig: map[Source]map[string]string{},
 		internalOverrides: map[string]string{},
 	}
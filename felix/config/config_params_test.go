@@ -43,6 +43,7 @@ var _ = Describe("FelixConfig vs ConfigParams parity", func() {
 	cpFieldsToIgnore := []string{
 		"sourceToRawConfig",
 		"rawValues",
+		"rawValueSources",
 		"Err",
 		"numIptablesBitsAllocated",
 
@@ -91,6 +92,9 @@ var _ = Describe("FelixConfig vs ConfigParams parity", func() {
 		fcFields = fieldsByName(v3.FelixConfigurationSpec{})
 
 		cpFields = fieldsByName(config.Config{})
+
+		// Selects the nodes that an override applies to; it's not a config parameter itself.
+		delete(fcFields, "NodeSelector")
 		for _, name := range cpFieldsToIgnore {
 			delete(cpFields, name)
 		}
@@ -202,6 +206,78 @@ var _ = Describe("Config override empty", func() {
 	})
 })
 
+var _ = Describe("Config report", func() {
+	var cp *config.Config
+
+	paramReport := func(name string) config.ParamReport {
+		for _, r := range cp.Report() {
+			if r.Name == name {
+				return r
+			}
+		}
+		Fail("no report for " + name)
+		return config.ParamReport{}
+	}
+
+	BeforeEach(func() {
+		cp = config.New()
+		_, err := cp.UpdateFrom(map[string]string{
+			"LogSeverityScreen":         "Warning",
+			"BPFEnabled":                "true",
+			"DataplaneApplyHookTimeout": "30",
+		}, config.DatastoreGlobal)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cp.UpdateFrom(map[string]string{"LogSeverityScreen": "Debug"}, config.DatastorePerHost)
+		Expect(err).NotTo(HaveOccurred())
+		// Env vars get converted to lower-case before calling UpdateFrom.
+		_, err = cp.UpdateFrom(map[string]string{"bpfenabled": "false"}, config.EnvironmentVariable)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report the default of unset params", func() {
+		Expect(paramReport("IptablesBackend")).To(Equal(config.ParamReport{
+			Name:   "IptablesBackend",
+			Value:  "auto",
+			Source: "<default>",
+		}))
+	})
+
+	It("should report per-host values that override global ones", func() {
+		Expect(paramReport("LogSeverityScreen")).To(Equal(config.ParamReport{
+			Name:   "LogSeverityScreen",
+			Value:  "Debug",
+			Source: "datastore (per-host)",
+			Values: map[string]string{
+				"datastore (global)":   "Warning",
+				"datastore (per-host)": "Debug",
+			},
+		}))
+	})
+
+	It("should report drift when a local source overrides the datastore", func() {
+		Expect(paramReport("BPFEnabled")).To(Equal(config.ParamReport{
+			Name:   "BPFEnabled",
+			Value:  "false",
+			Source: "environment variable",
+			Values: map[string]string{
+				"datastore (global)":   "true",
+				"environment variable": "false",
+			},
+			Drift: true,
+		}))
+	})
+
+	It("should report drift for local-only params set in the datastore", func() {
+		Expect(paramReport("DataplaneApplyHookTimeout")).To(Equal(config.ParamReport{
+			Name:   "DataplaneApplyHookTimeout",
+			Value:  "5",
+			Source: "<default>",
+			Values: map[string]string{"datastore (global)": "30"},
+			Drift:  true,
+		}))
+	})
+})
+
 var t bool = true
 
 var _ = DescribeTable("Config parsing",
@@ -242,6 +318,22 @@ var _ = DescribeTable("Config parsing",
 
 	Entry("TyphaAddr empty", "TyphaAddr", "", ""),
 	Entry("TyphaAddr set", "TyphaAddr", "foo:1234", "foo:1234"),
+	Entry("TyphaAddr list", "TyphaAddr", "10.0.0.1:5473,[fd00::1]:5473", "10.0.0.1:5473,[fd00::1]:5473"),
+	Entry("TyphaAddr bad IPv6", "TyphaAddr", "fd00::1:5473", ""),
+	Entry("TyphaAddressFamily default", "TyphaAddressFamily", "", "Any"),
+	Entry("TyphaAddressFamily IPv6", "TyphaAddressFamily", "ipv6", "IPv6"),
+	Entry("InstanceName empty", "InstanceName", "", ""),
+	Entry("InstanceName set", "InstanceName", "blue", "blue"),
+	Entry("InstanceName with a slash", "InstanceName", "../blue", ""),
+	Entry("BPFFilesystemPath default", "BPFFilesystemPath", "", "/sys/fs/bpf"),
+	Entry("RuntimeDir default", "RuntimeDir", "", "/var/run/calico"),
+	Entry("RuntimeDir set", "RuntimeDir", "/run/felix", "/run/felix"),
+	Entry("MTUFilePath default", "MTUFilePath", "", "/var/lib/calico/mtu"),
+	Entry("DataplaneShutdownMode default", "DataplaneShutdownMode", "", "Retain"),
+	Entry("DataplaneShutdownMode Cleanup", "DataplaneShutdownMode", "Cleanup", "Cleanup"),
+	Entry("DataplaneShutdownMode bad", "DataplaneShutdownMode", "Wipe", "Retain"),
+	Entry("NodeInterfacesReportingInterval default", "NodeInterfacesReportingInterval", "", 30*time.Second),
+	Entry("NodeInterfacesReportingInterval disabled", "NodeInterfacesReportingInterval", "0", time.Duration(0)),
 	Entry("TyphaK8sServiceName empty", "TyphaK8sServiceName", "", ""),
 	Entry("TyphaK8sServiceName set", "TyphaK8sServiceName", "calico-typha", "calico-typha"),
 	Entry("TyphaK8sNamespace empty", "TyphaK8sNamespace", "", "kube-system"),
@@ -280,6 +372,14 @@ var _ = DescribeTable("Config parsing",
 		regexp.MustCompile("^kube-ipvs0$"),
 	}),
 
+	Entry("BPFAttachIncludeIfaces list", "BPFAttachIncludeIfaces", "ipvl0,/^macvtap/", []*regexp.Regexp{
+		regexp.MustCompile("^ipvl0$"),
+		regexp.MustCompile("^macvtap"),
+	}),
+	Entry("BPFAttachExcludeIfaces one value", "BPFAttachExcludeIfaces", "tun0", []*regexp.Regexp{
+		regexp.MustCompile("^tun0$"),
+	}),
+
 	Entry("ChainInsertMode append", "ChainInsertMode", "append", "append"),
 	Entry("ChainInsertMode append", "ChainInsertMode", "Append", "append"),
 
@@ -327,6 +427,10 @@ var _ = DescribeTable("Config parsing",
 	Entry("LogDebugFilenameRegex", "LogDebugFilenameRegex", "", (*regexp.Regexp)(nil)),
 	Entry("LogDebugFilenameRegex", "LogDebugFilenameRegex", ".*", regexp.MustCompile(".*")),
 
+	Entry("LogFormat", "LogFormat", "json", "JSON"),
+	Entry("LogFormat", "LogFormat", "Logfmt", "Logfmt"),
+	Entry("LogFormat", "LogFormat", "xml", "Default"),
+
 	Entry("IpInIpEnabled", "IpInIpEnabled", "true", &t),
 	Entry("IpInIpEnabled", "IpInIpEnabled", "y", &t),
 	Entry("IpInIpEnabled", "IpInIpEnabled", "True", &t),
@@ -575,6 +679,50 @@ var _ = DescribeTable("Kubernetes Provider tests",
 	Entry("DockerEE provider", "dockerenterprise,k8s", config.ProviderDockerEE),
 )
 
+var _ = Describe("OperationMode tests", func() {
+	var c *config.Config
+	BeforeEach(func() {
+		c = config.New()
+		_, err := c.UpdateFrom(map[string]string{
+			"WireguardEnabled":            "true",
+			"DefaultEndpointToHostAction": "DROP",
+		}, config.DatastorePerHost)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	setMode := func(mode string) {
+		_, err := c.UpdateFrom(map[string]string{"OperationMode": mode}, config.EnvironmentVariable)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should enable everything by default", func() {
+		Expect(c.OperationMode).To(Equal("Full"))
+		Expect(c.PolicyEnabled()).To(BeTrue())
+		Expect(c.NetworkingEnabled()).To(BeTrue())
+		Expect(c.WireguardEnabled).To(BeTrue())
+		Expect(c.RouteSyncDisabled).To(BeFalse())
+		Expect(c.DefaultEndpointToHostAction).To(Equal("DROP"))
+	})
+	It("should disable routing and WireGuard in PolicyOnly mode", func() {
+		setMode("PolicyOnly")
+		Expect(c.PolicyEnabled()).To(BeTrue())
+		Expect(c.NetworkingEnabled()).To(BeFalse())
+		Expect(c.WireguardEnabled).To(BeFalse())
+		Expect(c.RouteSyncDisabled).To(BeTrue())
+		Expect(c.DefaultEndpointToHostAction).To(Equal("DROP"))
+	})
+	It("should accept traffic to the host in NetworkingOnly mode", func() {
+		setMode("NetworkingOnly")
+		Expect(c.PolicyEnabled()).To(BeFalse())
+		Expect(c.NetworkingEnabled()).To(BeTrue())
+		Expect(c.WireguardEnabled).To(BeTrue())
+		Expect(c.DefaultEndpointToHostAction).To(Equal("ACCEPT"))
+	})
+	It("should ignore an unknown mode", func() {
+		setMode("Bogus")
+		Expect(c.OperationMode).To(Equal("Full"))
+	})
+})
+
 var _ = Describe("DatastoreConfig tests", func() {
 	var c *config.Config
 	Describe("with IPIP enabled", func() {
@@ -745,6 +893,26 @@ var _ = DescribeTable("Config validation",
 	Entry("OpenstackRegion too long", map[string]string{
 		"OpenstackRegion": "my-region-has-a-very-long-and-extremely-interesting-name",
 	}, false),
+	Entry("NAT64 with the default prefix", map[string]string{
+		"BPFNAT64Enabled": "true",
+	}, true),
+	Entry("NAT64 with an IPv4 prefix", map[string]string{
+		"BPFNAT64Enabled": "true",
+		"BPFNAT64Prefix":  "10.0.0.0/8",
+	}, false),
+	Entry("NAT64 with a /64 prefix", map[string]string{
+		"BPFNAT64Enabled": "true",
+		"BPFNAT64Prefix":  "64:ff9b:1::/64",
+	}, false),
+	Entry("routes mirror table outside RouteTableRanges", map[string]string{
+		"BPFRoutesMirrorTableIndex": "300",
+	}, true),
+	Entry("routes mirror table in the default RouteTableRanges", map[string]string{
+		"BPFRoutesMirrorTableIndex": "100",
+	}, false),
+	Entry("routes mirror table is the main table", map[string]string{
+		"BPFRoutesMirrorTableIndex": "254",
+	}, false),
 	Entry("valid RouteTableRange", map[string]string{
 		"RouteTableRange": "1-250",
 	}, true),
@@ -0,0 +1,86 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParamReport describes the effective value of a config parameter and where it comes from.
+type ParamReport struct {
+	Name string `json:"name"`
+	// Value is the raw value that is in effect, the default if no source sets the parameter.
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	// Values holds the raw value that each source gives the parameter, by source, including
+	// the values that lost to a higher-priority source.
+	Values map[string]string `json:"values,omitempty"`
+	// Drift is set if the FelixConfiguration resources set the parameter but the value in
+	// effect does not come from them, e.g. because an environment variable overrides them or
+	// because the parameter can only be set locally.
+	Drift bool `json:"drift,omitempty"`
+}
+
+// Report returns the known config parameters, sorted by name, with the source of their
+// effective value and the value that each source gives them.
+func (config *Config) Report() []ParamReport {
+	// Index the values of each source by parameter, the raw names may differ in case.
+	values := map[string]map[Source]string{}
+	for source, rawConfig := range config.sourceToRawConfig {
+		for rawName, rawValue := range rawConfig {
+			param, ok := knownParams[strings.ToLower(rawName)]
+			if !ok {
+				continue
+			}
+			name := param.GetMetadata().Name
+			if values[name] == nil {
+				values[name] = map[Source]string{}
+			}
+			values[name][source] = rawValue
+		}
+	}
+
+	report := make([]ParamReport, 0, len(knownParams))
+	for _, param := range knownParams {
+		metadata := param.GetMetadata()
+		r := ParamReport{
+			Name:   metadata.Name,
+			Value:  metadata.DefaultString,
+			Source: Default.String(),
+		}
+		source, ok := config.rawValueSources[metadata.Name]
+		if ok {
+			r.Value = config.rawValues[metadata.Name]
+			r.Source = source.String()
+		}
+		datastoreSet := false
+		for s, v := range values[metadata.Name] {
+			if r.Values == nil {
+				r.Values = map[string]string{}
+			}
+			r.Values[s.String()] = v
+			if !s.Local() {
+				datastoreSet = true
+			}
+		}
+		r.Drift = datastoreSet && (!ok || source.Local())
+		report = append(report, r)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Name < report[j].Name
+	})
+	return report
+}
@@ -21,6 +21,8 @@ import (
 	"os/exec"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/capabilities"
 )
 
 // For TCP/UDP, each conntrack entry holds two copies of the tuple
@@ -49,7 +51,9 @@ type Conntrack struct {
 
 func New() *Conntrack {
 	return NewWithCmdShim(func(name string, arg ...string) CmdIface {
-		return (*cmdAdapter)(exec.Command(name, arg...))
+		cmd := exec.Command(name, arg...)
+		capabilities.ConfigureCmd(cmd)
+		return (*cmdAdapter)(cmd)
 	})
 }
 
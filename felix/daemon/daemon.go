@@ -21,9 +21,11 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -34,14 +36,18 @@ import (
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
+	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 	"github.com/projectcalico/calico/felix/buildinfo"
 	"github.com/projectcalico/calico/felix/calc"
+	"github.com/projectcalico/calico/felix/capabilities"
 	"github.com/projectcalico/calico/felix/config"
 	dp "github.com/projectcalico/calico/felix/dataplane"
+	"github.com/projectcalico/calico/felix/debugstate"
 	"github.com/projectcalico/calico/felix/jitter"
 	"github.com/projectcalico/calico/felix/logutils"
 	"github.com/projectcalico/calico/felix/policysync"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/shutdown"
 	"github.com/projectcalico/calico/felix/statusrep"
 	"github.com/projectcalico/calico/felix/usagerep"
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
@@ -222,7 +228,7 @@ configRetry:
 		numClientsCreated++
 		backendClient = v3Client.(interface{ Backend() bapi.Client }).Backend()
 		for {
-			globalConfig, hostConfig, err := loadConfigFromDatastore(
+			globalConfig, selectorConfig, hostConfig, err := loadConfigFromDatastore(
 				ctx, backendClient, datastoreConfig, configParams.FelixHostname)
 			if err == ErrNotReady {
 				log.Warn("Waiting for datastore to be initialized (or migrated)")
@@ -240,6 +246,12 @@ configRetry:
 				time.Sleep(1 * time.Second)
 				continue configRetry
 			}
+			_, err = configParams.UpdateFrom(selectorConfig, config.DatastoreNodeSelector)
+			if err != nil {
+				log.WithError(err).Error("Failed update node selector config from datastore")
+				time.Sleep(1 * time.Second)
+				continue configRetry
+			}
 			_, err = configParams.UpdateFrom(hostConfig, config.DatastorePerHost)
 			if err != nil {
 				log.WithError(err).Error("Failed update host config from datastore")
@@ -354,6 +366,21 @@ configRetry:
 
 	doGoRuntimeSetup(configParams)
 
+	// Set where the BPF pins and runtime files go before anything uses them.
+	bpfdefs.SetPaths(bpfdefs.Paths{
+		BPFfs:      configParams.BPFFilesystemPath,
+		CgroupV2:   configParams.CgroupV2Path,
+		RuntimeDir: configParams.RuntimeDir,
+		Instance:   configParams.InstanceName,
+	})
+	if configParams.InstanceName != "" {
+		log.WithFields(log.Fields{
+			"instance":   configParams.InstanceName,
+			"pinRoot":    bpfdefs.PinRoot(),
+			"runtimeDir": bpfdefs.RuntimeDir(),
+		}).Info("Running as a named Felix instance.")
+	}
+
 	if configParams.BPFEnabled {
 		// Check for BPF dataplane support before we do anything that relies on the flag being set one way or another.
 		if err := dp.SupportsBPF(); err != nil {
@@ -365,6 +392,11 @@ configRetry:
 		}
 	}
 
+	// Felix may run with only the capabilities that the dataplane needs rather than fully
+	// privileged; flag any that are missing up front.
+	capabilities.CheckRequired(configParams.BPFEnabled)
+	capabilities.SetProcSysPath(configParams.ProcSysPath)
+
 	// Set any watchdog timeout overrides before we initialise components.
 	health.SetGlobalTimeoutOverrides(configParams.HealthTimeoutOverrides)
 
@@ -464,6 +496,23 @@ configRetry:
 		calcGraphClientChannels = append(calcGraphClientChannels, toPolicySync)
 	}
 
+	// If enabled, keep a copy of the calculation graph's output for the debug state server.
+	var debugState *debugstate.State
+	var debugStateServer *debugstate.Server
+	if configParams.DebugStatePort != 0 {
+		token, err := debugstate.ReadTokenFile(configParams.DebugStateTokenFile)
+		if err != nil {
+			log.WithError(err).Error("DebugStatePort is set but the token could not be read, " +
+				"not starting the debug state server.")
+		} else {
+			toDebugState := make(chan interface{})
+			debugState = debugstate.NewState(toDebugState)
+			debugStateServer = debugstate.NewServer(debugState, configParams.DebugStateHost,
+				configParams.DebugStatePort, token)
+			calcGraphClientChannels = append(calcGraphClientChannels, toDebugState)
+		}
+	}
+
 	// Now create the calculation graph, which receives updates from the
 	// datastore and outputs dataplane updates for the dataplane driver.
 	//
@@ -658,6 +707,33 @@ configRetry:
 		}
 	}
 
+	if configParams.EndpointStatusConditionsEnabled {
+		if k8sClientSet == nil {
+			log.Warn("EndpointStatusConditionsEnabled requires access to the Kubernetes API. Ignoring config...")
+		} else {
+			fromDataplaneC := dpConnector.NewFromDataplaneConsumer()
+			podConditionReporter := statusrep.NewPodConditionReporter(
+				configParams.FelixHostname,
+				fromDataplaneC,
+				k8sClientSet,
+				configParams.EndpointReportingDelaySecs,
+			)
+			log.Info("Starting endpoint-status pod condition reporter")
+			go podConditionReporter.SyncForever(context.Background())
+		}
+	}
+
+	if configParams.NodeInterfacesReportingInterval > 0 {
+		nodeInterfacesReporter := statusrep.NewNodeInterfacesReporter(
+			configParams.FelixHostname,
+			v3Client.Nodes(),
+			configParams.NodeInterfacesReportingInterval,
+			excludeFromNodeInterfaces(configParams),
+		)
+		log.Info("Starting node interfaces reporter")
+		go nodeInterfacesReporter.SyncForever(context.Background())
+	}
+
 	// Start communicating with the dataplane driver.
 	dpConnector.Start()
 
@@ -670,6 +746,13 @@ configRetry:
 		go policySyncAPIBinder.SearchAndBind(sc)
 	}
 
+	if debugState != nil {
+		// The calculation graph only sends config updates when the datastore config changes.
+		debugState.OnUpdate(configParams.ToConfigUpdate())
+		debugState.Start()
+		debugStateServer.Start()
+	}
+
 	// Send the opening message to the dataplane driver, giving it its
 	// config.
 	dpConnector.ToDataplane <- configParams.ToConfigUpdate()
@@ -693,9 +776,36 @@ configRetry:
 	// Register signal handlers to dump memory/CPU profiles.
 	logutils.RegisterProfilingSignalHandlers(configParams)
 
+	// The shutdown mode can be changed by a pre-stop hook, to clean up the dataplane of a
+	// node that is decommissioned.
+	shutdownMode := shutdown.NewMode(configParams.DataplaneShutdownMode)
+	if runtime.GOOS != "windows" {
+		socketPath := filepath.Join(bpfdefs.RuntimeDir(), shutdown.SocketName)
+		if err := shutdown.NewServer(shutdownMode, socketPath).Start(); err != nil {
+			log.WithError(err).Warn("Failed to serve the dataplane shutdown mode.")
+		}
+	}
+	onTermination := func() {
+		if !shutdownMode.CleanUp() {
+			log.Info("Leaving the dataplane programmed.")
+			return
+		}
+		cleaner, ok := dpDriver.(dataplaneCleaner)
+		if !ok {
+			log.Warn("The dataplane driver cannot clean up the dataplane, leaving it programmed.")
+			return
+		}
+		cleaner.StopAndCleanUp()
+	}
+
 	// Now monitor the worker process and our worker threads and shut
 	// down the process gracefully if they fail.
-	monitorAndManageShutdown(failureReportChan, dpDriverCmd, stopSignalChans)
+	monitorAndManageShutdown(failureReportChan, dpDriverCmd, stopSignalChans, onTermination)
+}
+
+// dataplaneCleaner is implemented by the dataplane drivers that can remove what they programmed.
+type dataplaneCleaner interface {
+	StopAndCleanUp()
 }
 
 func doGoRuntimeSetup(params *config.Config) {
@@ -725,7 +835,14 @@ func doGoRuntimeSetup(params *config.Config) {
 	}
 }
 
-func monitorAndManageShutdown(failureReportChan <-chan string, driverCmd *exec.Cmd, stopSignalChans []chan<- *sync.WaitGroup) {
+// monitorAndManageShutdown waits for a reason to shut down and stops the components.  If Felix is
+// terminated by a signal, as opposed to restarting, onTermination is called first.
+func monitorAndManageShutdown(
+	failureReportChan <-chan string,
+	driverCmd *exec.Cmd,
+	stopSignalChans []chan<- *sync.WaitGroup,
+	onTermination func(),
+) {
 	// Ask the runtime to tell us if we get a term/int signal.
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGTERM)
@@ -775,6 +892,10 @@ func monitorAndManageShutdown(failureReportChan <-chan string, driverCmd *exec.C
 		}
 	}()
 
+	if receivedFatalSignal && onTermination != nil {
+		onTermination()
+	}
+
 	// Notify other components to stop.  Each notified component must call Done() on the wait
 	// group when it has completed its shutdown.
 	var stopWG sync.WaitGroup
@@ -865,12 +986,13 @@ var ErrNotReady = errors.New("datastore is not ready or has not been initialised
 
 func loadConfigFromDatastore(
 	ctx context.Context, client bapi.Client, cfg apiconfig.CalicoAPIConfig, hostname string,
-) (globalConfig, hostConfig map[string]string, err error) {
+) (globalConfig, selectorConfig, hostConfig map[string]string, err error) {
 
-	// The configuration is split over 3 different resource types and 4 different resource
-	// instances in the v3 data model:
+	// The configuration is split over 3 different resource types and 5 different kinds of
+	// resource instance in the v3 data model:
 	// -  ClusterInformation (global): name "default"
 	// -  FelixConfiguration (global): name "default"
+	// -  FelixConfiguration (node selector overrides): any other name
 	// -  FelixConfiguration (per-host): name "node.<hostname>"
 	// -  Node (per-host): name: <hostname>
 	// Get the global values and host specific values separately.  We re-use the updateprocessor
@@ -901,6 +1023,10 @@ func loadConfigFromDatastore(
 	if err != nil {
 		return
 	}
+	selectorConfig, err = getNodeSelectorConfig(ctx, client, hostname)
+	if err != nil {
+		return
+	}
 	err = getAndMergeConfig(
 		ctx, client, hostConfig,
 		apiv3.KindFelixConfiguration, "node."+hostname,
@@ -980,6 +1106,50 @@ func getAndMergeConfig(
 	return nil
 }
 
+// getNodeSelectorConfig lists the FelixConfiguration overrides and merges the config of those whose
+// node selector matches the labels of this node.
+func getNodeSelectorConfig(ctx context.Context, client bapi.Client, hostname string) (map[string]string, error) {
+	logCxt := log.WithField("kind", apiv3.KindFelixConfiguration)
+	kvps, err := client.List(ctx, model.ResourceListOptions{Kind: apiv3.KindFelixConfiguration}, "")
+	if err != nil {
+		logCxt.WithError(err).Info("Failed to list config overrides from datastore")
+		return nil, err
+	}
+	overrides := map[string]map[string]string{}
+	configConverter := updateprocessors.NewFelixConfigUpdateProcessor()
+	for _, kvp := range kvps.KVPairs {
+		v1kvs, err := configConverter.Process(kvp)
+		if err != nil {
+			logCxt.WithError(err).Error("Failed to convert configuration")
+		}
+		for _, v1KV := range v1kvs {
+			k, ok := v1KV.Key.(model.OverrideConfigKey)
+			if !ok || v1KV.Value == nil {
+				continue
+			}
+			if overrides[k.OverrideName] == nil {
+				overrides[k.OverrideName] = map[string]string{}
+			}
+			overrides[k.OverrideName][k.Name] = v1KV.Value.(string)
+		}
+	}
+	if len(overrides) == 0 {
+		return map[string]string{}, nil
+	}
+
+	var nodeLabels map[string]string
+	node, err := client.Get(ctx, model.ResourceKey{Kind: libapiv3.KindNode, Name: hostname}, "")
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			log.WithError(err).Info("Failed to load node from datastore")
+			return nil, err
+		}
+	} else {
+		nodeLabels = node.Value.(*libapiv3.Node).Labels
+	}
+	return calc.MergeNodeSelectorConfig(overrides, nodeLabels), nil
+}
+
 type DataplaneConnector struct {
 	configLock sync.Mutex
 	config     *config.Config
@@ -1108,6 +1278,7 @@ func (fc *DataplaneConnector) handleProcessStatusUpdate(ctx context.Context, msg
 		hostname = fc.config.FelixHostname
 		regionString = model.RegionString(fc.config.OpenstackRegion)
 		reportingTTL = fc.config.ReportingTTLSecs
+		statusReport.OperationMode = fc.config.OperationMode
 	}()
 
 	kv := model.KVPair{
@@ -1140,6 +1311,38 @@ func (fc *DataplaneConnector) handleProcessStatusUpdate(ctx context.Context, msg
 	}
 }
 
+// excludeFromNodeInterfaces returns a function that excludes the workload interfaces, the
+// interfaces that Felix is configured to ignore and Calico's own devices from the host interfaces
+// that Felix reports.
+func excludeFromNodeInterfaces(configParams *config.Config) func(string) bool {
+	calicoIfaces := set.From(
+		"tunl0",
+		"vxlan.calico",
+		"vxlan-v6.calico",
+		"bpfin.cali",
+		"bpfout.cali",
+		configParams.WireguardInterfaceName,
+		configParams.WireguardInterfaceNameV6,
+	)
+	workloadPrefixes := configParams.InterfacePrefixes()
+	return func(ifaceName string) bool {
+		if calicoIfaces.Contains(ifaceName) {
+			return true
+		}
+		for _, prefix := range workloadPrefixes {
+			if strings.HasPrefix(ifaceName, prefix) {
+				return true
+			}
+		}
+		for _, r := range configParams.InterfaceExclude {
+			if r.MatchString(ifaceName) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 func (fc *DataplaneConnector) reconcileWireguardStatUpdate(dpPubKey string, ipVersion proto.IPVersion) error {
 	// In case of a recoverable failure (ErrorResourceUpdateConflict), retry update 3 times.
 	for iter := 0; iter < 3; iter++ {
@@ -1244,6 +1447,9 @@ var handledConfigChanges = set.From(
 	"ClusterGUID",
 	"ClusterType",
 	"HealthTimeoutOverrides",
+	// Handled by the BPF endpoint manager.
+	"BPFAttachIncludeIfaces",
+	"BPFAttachExcludeIfaces",
 )
 
 func (fc *DataplaneConnector) sendMessagesToDataplaneDriver() {
@@ -1390,7 +1596,9 @@ func createTyphaDiscoverer(configParams *config.Config, k8sClientSet kubernetes.
 		discovery.WithAddrOverride(configParams.TyphaAddr),
 		discovery.WithKubeService(configParams.TyphaK8sNamespace, configParams.TyphaK8sServiceName),
 		discovery.WithKubeClient(k8sClientSet),
+		discovery.WithEndpointSlicesOnly(configParams.TyphaK8sEndpointSlicesOnly),
 		discovery.WithNodeAffinity(configParams.FelixHostname),
+		discovery.WithAddressFamilyPreference(configParams.TyphaAddressFamily),
 	)
 	return typhaDiscoverer
 }
@@ -35,6 +35,13 @@ type IPSetsDataplane interface {
 	ApplyDeletions() (reschedule bool)
 }
 
+// DomainResolver returns the addresses that domain names currently resolve to.  Generation
+// increases whenever those addresses change.
+type DomainResolver interface {
+	IPsForDomains(domains []string, ipVersion int) []string
+	Generation() uint64
+}
+
 // Except for domain IP sets, IPSetsManager simply passes through IP set updates from the datastore
 // to the ipsets.IPSets dataplane layer.  For domain IP sets - which hereafter we'll just call
 // "domain sets" - IPSetsManager handles the resolution from domain names to expiring IPs.
@@ -42,12 +49,21 @@ type IPSetsManager struct {
 	dataplanes []IPSetsDataplane
 	maxSize    int
 	lg         *log.Entry
+
+	// domainSets maps the ID of each domain set to its domain names.  The members of a domain
+	// set are the addresses that the resolver returns for those names.
+	domainSets      map[string]set.Set[string]
+	dirtyDomainSets set.Set[string]
+	resolver        DomainResolver
+	resolverGen     uint64
 }
 
 func NewIPSetsManager(name string, ipsets_ IPSetsDataplane, maxIPSetSize int) *IPSetsManager {
 	m := &IPSetsManager{
-		maxSize: maxIPSetSize,
-		lg:      log.WithField("name", name),
+		maxSize:         maxIPSetSize,
+		lg:              log.WithField("name", name),
+		domainSets:      map[string]set.Set[string]{},
+		dirtyDomainSets: set.New[string](),
 	}
 
 	if ipsets_ != nil {
@@ -61,6 +77,12 @@ func (m *IPSetsManager) AddDataplane(dp IPSetsDataplane) {
 	m.dataplanes = append(m.dataplanes, dp)
 }
 
+// SetDomainResolver sets the resolver of the names in domain sets.  Without one, domain sets
+// are programmed empty.
+func (m *IPSetsManager) SetDomainResolver(r DomainResolver) {
+	m.resolver = r
+}
+
 func (m *IPSetsManager) GetIPSetType(setID string) (typ ipsets.IPSetType, err error) {
 	for _, dp := range m.dataplanes {
 		typ, err = dp.GetTypeOf(setID)
@@ -86,12 +108,27 @@ func (m *IPSetsManager) OnUpdate(msg interface{}) {
 	// IP set-related messages, these are extremely common.
 	case *proto.IPSetDeltaUpdate:
 		m.lg.WithField("ipSetId", msg.Id).Debug("IP set delta update")
+		if domains, ok := m.domainSets[msg.Id]; ok {
+			for _, d := range msg.AddedMembers {
+				domains.Add(d)
+			}
+			for _, d := range msg.RemovedMembers {
+				domains.Discard(d)
+			}
+			m.dirtyDomainSets.Add(msg.Id)
+			return
+		}
 		for _, dp := range m.dataplanes {
 			dp.AddMembers(msg.Id, msg.AddedMembers)
 			dp.RemoveMembers(msg.Id, msg.RemovedMembers)
 		}
 	case *proto.IPSetUpdate:
 		m.lg.WithField("ipSetId", msg.Id).Debug("IP set update")
+		if msg.Type == proto.IPSetUpdate_DOMAIN {
+			m.domainSets[msg.Id] = set.FromArray(msg.Members)
+			m.dirtyDomainSets.Add(msg.Id)
+			return
+		}
 		var setType ipsets.IPSetType
 		switch msg.Type {
 		case proto.IPSetUpdate_IP:
@@ -113,6 +150,8 @@ func (m *IPSetsManager) OnUpdate(msg interface{}) {
 		}
 	case *proto.IPSetRemove:
 		m.lg.WithField("ipSetId", msg.Id).Debug("IP set remove")
+		delete(m.domainSets, msg.Id)
+		m.dirtyDomainSets.Discard(msg.Id)
 		for _, dp := range m.dataplanes {
 			dp.RemoveIPSet(msg.Id)
 		}
@@ -120,6 +159,41 @@ func (m *IPSetsManager) OnUpdate(msg interface{}) {
 }
 
 func (m *IPSetsManager) CompleteDeferredWork() error {
-	// Nothing to do, we don't defer any work.
+	// The only deferred work is the resolution of domain sets, which we redo in full when the
+	// resolver's addresses have changed.
+	if m.resolver != nil {
+		if gen := m.resolver.Generation(); gen != m.resolverGen {
+			for id := range m.domainSets {
+				m.dirtyDomainSets.Add(id)
+			}
+			m.resolverGen = gen
+		}
+	}
+	m.dirtyDomainSets.Iter(func(id string) error {
+		m.updateDomainSet(id)
+		return nil
+	})
+	m.dirtyDomainSets.Clear()
 	return nil
 }
+
+func (m *IPSetsManager) updateDomainSet(id string) {
+	metadata := ipsets.IPSetMetadata{
+		Type:    ipsets.IPSetTypeHashIP,
+		SetID:   id,
+		MaxSize: m.maxSize,
+	}
+	domains := m.domainSets[id].Slice()
+	for _, dp := range m.dataplanes {
+		var ips []string
+		if m.resolver != nil {
+			ipVersion := 4
+			if dp.GetIPFamily() == ipsets.IPFamilyV6 {
+				ipVersion = 6
+			}
+			ips = m.resolver.IPsForDomains(domains, ipVersion)
+		}
+		m.lg.WithFields(log.Fields{"ipSetId": id, "domains": domains, "ips": ips}).Debug("Updating domain set")
+		dp.AddOrReplaceIPSet(metadata, ips)
+	}
+}
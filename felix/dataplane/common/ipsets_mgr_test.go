@@ -145,3 +145,74 @@ var _ = Describe("IP Sets manager", func() {
 		IPsetsMgrTest1(testCase.ipsetID, testCase.ipsetType, testCase.ipsetMembers)
 	}
 })
+
+type mockDomainResolver struct {
+	ips        map[string][]string
+	generation uint64
+}
+
+func (r *mockDomainResolver) IPsForDomains(domains []string, ipVersion int) (ips []string) {
+	for _, d := range domains {
+		ips = append(ips, r.ips[d]...)
+	}
+	return
+}
+
+func (r *mockDomainResolver) Generation() uint64 {
+	return r.generation
+}
+
+var _ = Describe("IP Sets manager domain sets", func() {
+	var (
+		ipsetsMgr *IPSetsManager
+		ipSets    *MockIPSets
+		resolver  *mockDomainResolver
+	)
+
+	BeforeEach(func() {
+		ipSets = NewMockIPSets()
+		ipsetsMgr = NewIPSetsManager("ipv4", ipSets, 1024)
+		resolver = &mockDomainResolver{ips: map[string][]string{
+			"api.github.com": {"140.82.112.5"},
+			"example.com":    {"192.0.2.1"},
+		}}
+		ipsetsMgr.SetDomainResolver(resolver)
+
+		ipsetsMgr.OnUpdate(&proto.IPSetUpdate{
+			Id:      "d:abcd",
+			Members: []string{"api.github.com"},
+			Type:    proto.IPSetUpdate_DOMAIN,
+		})
+		Expect(ipsetsMgr.CompleteDeferredWork()).To(Succeed())
+	})
+
+	It("should program the addresses of the domains", func() {
+		Expect(ipSets.Members["d:abcd"]).To(Equal(set.From("140.82.112.5")))
+	})
+
+	It("should handle delta updates of the domains", func() {
+		ipsetsMgr.OnUpdate(&proto.IPSetDeltaUpdate{
+			Id:             "d:abcd",
+			AddedMembers:   []string{"example.com"},
+			RemovedMembers: []string{"api.github.com"},
+		})
+		Expect(ipsetsMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(ipSets.Members["d:abcd"]).To(Equal(set.From("192.0.2.1")))
+	})
+
+	It("should reprogram the set when the resolver's addresses change", func() {
+		resolver.ips["api.github.com"] = []string{"140.82.112.5", "140.82.112.6"}
+		Expect(ipsetsMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(ipSets.Members["d:abcd"]).To(Equal(set.From("140.82.112.5")))
+
+		resolver.generation++
+		Expect(ipsetsMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(ipSets.Members["d:abcd"]).To(Equal(set.From("140.82.112.5", "140.82.112.6")))
+	})
+
+	It("should remove the set", func() {
+		ipsetsMgr.OnUpdate(&proto.IPSetRemove{Id: "d:abcd"})
+		Expect(ipsetsMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(ipSets.Members["d:abcd"]).To(BeNil())
+	})
+})
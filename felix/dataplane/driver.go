@@ -36,12 +36,16 @@ import (
 
 	"github.com/projectcalico/calico/felix/aws"
 	"github.com/projectcalico/calico/felix/bpf"
+	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	bpfproxy "github.com/projectcalico/calico/felix/bpf/proxy"
 	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/config"
 	extdataplane "github.com/projectcalico/calico/felix/dataplane/external"
 	"github.com/projectcalico/calico/felix/dataplane/inactive"
 	intdataplane "github.com/projectcalico/calico/felix/dataplane/linux"
+	"github.com/projectcalico/calico/felix/dnslog"
+	"github.com/projectcalico/calico/felix/flowlog"
 	"github.com/projectcalico/calico/felix/idalloc"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ipsets"
@@ -124,6 +128,22 @@ func StartDataplaneDriver(configParams *config.Config,
 			}
 		}
 
+		var markL7Proxy uint32
+		if configParams.L7ProxySelector != "" {
+			if configParams.BPFEnabled {
+				log.Info("L7 proxy enabled, allocating a mark bit")
+				markL7Proxy, _ = markBitsManager.NextSingleBitMark()
+				if markL7Proxy == 0 {
+					log.WithFields(log.Fields{
+						"Name":     "felix-iptables",
+						"MarkMask": allowedMarkBits,
+					}).Panic("Failed to allocate a mark bit for the L7 proxy, not enough mark bits available.")
+				}
+			} else {
+				log.Warn("L7ProxySelector is only supported by the BPF dataplane, connections will not be redirected to the L7 proxy.")
+			}
+		}
+
 		if markAccept == 0 || markScratch0 == 0 || markPass == 0 || markScratch1 == 0 {
 			log.WithFields(log.Fields{
 				"Name":     "felix-iptables",
@@ -150,8 +170,20 @@ func StartDataplaneDriver(configParams *config.Config,
 			"scratch1Mark":        markScratch1,
 			"endpointMark":        markEndpointMark,
 			"endpointMarkNonCali": markEndpointNonCaliEndpoint,
+			"l7ProxyMark":         markL7Proxy,
 		}).Info("Calculated iptables mark bits")
 
+		var egressProxyIP net.IP
+		if configParams.EgressProxyAddress != "" {
+			egressProxyIP = net.ParseIP(configParams.EgressProxyAddress)
+			if egressProxyIP == nil {
+				log.WithField("address", configParams.EgressProxyAddress).Error(
+					"EgressProxyAddress is not an IP address, egress will not be redirected to the proxy.")
+			} else if configParams.BPFEnabled {
+				log.Warn("EgressProxyAddress is not supported by the BPF dataplane, egress will not be redirected to the proxy.")
+			}
+		}
+
 		// Create a routing table manager. There are certain components that should take specific indices in the range
 		// to simplify table tidy-up.
 		reservedTables := []idalloc.IndexRange{{Min: 253, Max: 255}}
@@ -179,6 +211,16 @@ func StartDataplaneDriver(configParams *config.Config,
 			log.WithError(err).Warning("Unable to assign table index for IPv6 wireguard")
 		}
 
+		// Like the wireguard table, always allocate the L7 proxy table index so that its routes
+		// are tidied up if the L7 proxy is disabled.
+		var l7ProxyTableIndex int
+		if idx, err := routeTableIndexAllocator.GrabIndex(); err == nil {
+			log.Debugf("Assigned L7 proxy table index: %d", idx)
+			l7ProxyTableIndex = idx
+		} else {
+			log.WithError(err).Warning("Unable to assign table index for the L7 proxy")
+		}
+
 		// Extract node labels from the hosts such they could be referenced later
 		// e.g. Topology Aware Hints.
 		felixHostname := configParams.FelixHostname
@@ -197,10 +239,56 @@ func StartDataplaneDriver(configParams *config.Config,
 			felixNodeZone = felixNode.Labels[coreV1.LabelTopologyZone]
 		}
 
+		var dnsLogExporter dnslog.Exporter
+		if configParams.DNSLogsEnabled {
+			if configParams.DNSLogsCollectorAddress != "" {
+				dnsLogExporter = dnslog.NewCollectorExporter(configParams.DNSLogsCollectorAddress)
+			} else {
+				dnsLogExporter = dnslog.NewFileExporter(configParams.DNSLogsFilePath,
+					configParams.DNSLogsFileMaxSize, configParams.DNSLogsFileMaxFiles)
+			}
+		}
+
+		// Flow logs and the policy verdict metrics rely on the iptables policy rules to report
+		// their verdicts.
+		var flowLogExporter flowlog.Exporter
+		flowLogsNFLOGGroup := 0
+		if configParams.FlowLogsEnabled {
+			if configParams.BPFEnabled {
+				log.Warn("Flow logs are not supported in BPF mode, no flow logs will be written.")
+			} else if configParams.FlowLogsOTLPAddress != "" {
+				exporter, err := flowlog.NewOTLPExporter(configParams.FlowLogsOTLPAddress, felixHostname)
+				if err != nil {
+					log.WithError(err).Error("Failed to create the flow log exporter, no flow logs will be written.")
+				} else {
+					flowLogExporter = exporter
+				}
+			} else {
+				flowLogExporter = flowlog.NewFileExporter(configParams.FlowLogsFilePath,
+					configParams.FlowLogsFileMaxSize, configParams.FlowLogsFileMaxFiles)
+			}
+		}
+		policyVerdictMetricsEnabled := configParams.PrometheusMetricsEnabled &&
+			configParams.PrometheusPolicyVerdictMetricsEnabled
+		if policyVerdictMetricsEnabled && configParams.BPFEnabled {
+			log.Warn("Policy verdict metrics are not supported in BPF mode.")
+			policyVerdictMetricsEnabled = false
+		}
+		if flowLogExporter != nil || policyVerdictMetricsEnabled {
+			flowLogsNFLOGGroup = configParams.FlowLogsNFLOGGroup
+		}
+		flowLogConfig := flowlog.Config{
+			Aggregation: flowlog.Aggregation(configParams.FlowLogsAggregation),
+			SampleRate:  configParams.FlowLogsSampleRate,
+		}
+
 		dpConfig := intdataplane.Config{
 			Hostname:           felixHostname,
 			NodeZone:           felixNodeZone,
 			FloatingIPsEnabled: strings.EqualFold(configParams.FloatingIPs, string(apiv3.FloatingIPsEnabled)),
+
+			VirtualInterfaceHostEndpointsEnabled: configParams.VirtualInterfaceHostEndpointsEnabled,
+
 			IfaceMonitorConfig: ifacemonitor.Config{
 				InterfaceExcludes: configParams.InterfaceExclude,
 				ResyncInterval:    configParams.InterfaceRefreshInterval,
@@ -229,6 +317,15 @@ func StartDataplaneDriver(configParams *config.Config,
 				OpenStackMetadataIP:          net.ParseIP(configParams.MetadataAddr),
 				OpenStackMetadataPort:        uint16(configParams.MetadataPort),
 
+				EgressProxyIP:               egressProxyIP,
+				EgressProxyPort:             uint16(configParams.EgressProxyPort),
+				EgressProxyDestinationPorts: configParams.EgressProxyDestinationPorts,
+				EgressProxyExcludeCIDRs:     configParams.EgressProxyExcludeCIDRs,
+
+				L7ProxyMark:             markL7Proxy,
+				L7ProxyPort:             uint16(configParams.L7ProxyPort),
+				L7ProxyDestinationPorts: configParams.L7ProxyDestinationPorts,
+
 				IptablesMarkAccept:          markAccept,
 				IptablesMarkPass:            markPass,
 				IptablesMarkScratch0:        markScratch0,
@@ -261,6 +358,10 @@ func StartDataplaneDriver(configParams *config.Config,
 				RouteSource:                 configParams.RouteSource,
 
 				IptablesLogPrefix:         configParams.LogPrefix,
+				PolicyLogNFLOGGroup:       configParams.PolicyLogNFLOGGroup,
+				DNSTrustedServers:         configParams.DNSTrustedServers,
+				DNSPolicyNFLOGGroup:       configParams.DNSPolicyNFLOGGroup,
+				FlowLogsNFLOGGroup:        flowLogsNFLOGGroup,
 				EndpointToHostAction:      configParams.DefaultEndpointToHostAction,
 				IptablesFilterAllowAction: configParams.IptablesFilterAllowAction,
 				IptablesMangleAllowAction: configParams.IptablesMangleAllowAction,
@@ -268,6 +369,7 @@ func StartDataplaneDriver(configParams *config.Config,
 
 				FailsafeInboundHostPorts:  configParams.FailsafeInboundHostPorts,
 				FailsafeOutboundHostPorts: configParams.FailsafeOutboundHostPorts,
+				FailsafeNDPEnabled:        configParams.FailsafeNDPEnabled,
 
 				DisableConntrackInvalid: configParams.DisableConntrackInvalidCheck,
 
@@ -295,6 +397,7 @@ func StartDataplaneDriver(configParams *config.Config,
 				EncryptHostTraffic:  configParams.WireguardHostEncryptionEnabled,
 				PersistentKeepAlive: configParams.WireguardPersistentKeepAlive,
 				RouteSyncDisabled:   configParams.RouteSyncDisabled,
+				BPFEnabled:          configParams.BPFEnabled,
 			},
 			IPIPMTU:                        configParams.IpInIpMtu,
 			VXLANMTU:                       configParams.VXLANMTU,
@@ -338,12 +441,28 @@ func StartDataplaneDriver(configParams *config.Config,
 			},
 			HealthAggregator:                   healthAggregator,
 			WatchdogTimeout:                    configParams.DataplaneWatchdogTimeout,
+			DataplaneApplyPreHook:              configParams.DataplaneApplyPreHook,
+			DataplaneApplyPostHook:             configParams.DataplaneApplyPostHook,
+			DataplaneApplyHookTimeout:          configParams.DataplaneApplyHookTimeout,
 			DebugSimulateDataplaneHangAfter:    configParams.DebugSimulateDataplaneHangAfter,
 			DebugSimulateDataplaneApplyDelay:   configParams.DebugSimulateDataplaneApplyDelay,
 			ExternalNodesCidrs:                 configParams.ExternalNodesCIDRList,
 			SidecarAccelerationEnabled:         configParams.SidecarAccelerationEnabled,
 			BPFEnabled:                         configParams.BPFEnabled,
 			BPFPolicyDebugEnabled:              configParams.BPFPolicyDebugEnabled,
+			PolicyMetricsEnabled:               configParams.PrometheusMetricsEnabled && configParams.PrometheusPolicyMetricsEnabled,
+			PolicyLogFilePath:                  configParams.PolicyLogFilePath,
+			DNSLogExporter:                     dnsLogExporter,
+			FlowLogExporter:                    flowLogExporter,
+			FlowLogConfig:                      flowLogConfig,
+			FlowLogsFlushInterval:              configParams.FlowLogsFlushInterval,
+			FlowLogsKTLSAccountingEnabled:      configParams.FlowLogsKTLSAccountingEnabled,
+			PolicyVerdictMetricsEnabled:        policyVerdictMetricsEnabled,
+			PolicyVerdictMetricsMaxSeries:      configParams.PrometheusPolicyVerdictMetricsMaxSeries,
+			L7ProxyTableIndex:                  l7ProxyTableIndex,
+			L7ProxyVerdictSocketPath:           bpfdefs.InstanceRuntimePath(configParams.L7ProxyVerdictSocketPath),
+			MTUFilePath:                        configParams.MTUFilePath,
+			BPFPolicyBitmapsEnabled:            configParams.BPFPolicyBitmapsEnabled,
 			BPFDisableUnprivileged:             configParams.BPFDisableUnprivileged,
 			BPFConnTimeLBEnabled:               configParams.BPFConnectTimeLoadBalancingEnabled,
 			BPFConnTimeLB:                      configParams.BPFConnectTimeLoadBalancing,
@@ -354,19 +473,33 @@ func StartDataplaneDriver(configParams *config.Config,
 			BPFCTLBLogFilter:                   configParams.BPFCTLBLogFilter,
 			BPFExtToServiceConnmark:            configParams.BPFExtToServiceConnmark,
 			BPFDataIfacePattern:                configParams.BPFDataIfacePattern,
+			BPFAttachIncludeIfaces:             configParams.BPFAttachIncludeIfaces,
+			BPFAttachExcludeIfaces:             configParams.BPFAttachExcludeIfaces,
+			BPFHardwareOffloadIfaces:           configParams.BPFHardwareOffloadIfaces,
 			BPFL3IfacePattern:                  configParams.BPFL3IfacePattern,
 			BPFCgroupV2:                        configParams.DebugBPFCgroupV2,
 			BPFMapRepin:                        configParams.DebugBPFMapRepinEnabled,
 			KubeProxyMinSyncPeriod:             configParams.BPFKubeProxyMinSyncPeriod,
+			KubeProxyHealthzBindAddress:        configParams.BPFKubeProxyHealthzBindAddress,
+			KubeProxyMetricsBindAddress:        configParams.BPFKubeProxyMetricsBindAddress,
 			BPFPSNATPorts:                      configParams.BPFPSNATPorts,
 			BPFMapSizeRoute:                    configParams.BPFMapSizeRoute,
 			BPFMapSizeNATFrontend:              configParams.BPFMapSizeNATFrontend,
 			BPFMapSizeNATBackend:               configParams.BPFMapSizeNATBackend,
 			BPFMapSizeNATAffinity:              configParams.BPFMapSizeNATAffinity,
 			BPFMapSizeConntrack:                configParams.BPFMapSizeConntrack,
+			BPFConntrackShards:                 configParams.BPFConntrackShards,
 			BPFMapSizeIPSets:                   configParams.BPFMapSizeIPSets,
 			BPFMapSizeIfState:                  configParams.BPFMapSizeIfState,
+			BPFMemoryBudgetMB:                  configParams.BPFMemoryBudgetMB,
+			BPFMapShrinkPolicy:                 configParams.BPFMapShrinkPolicy,
 			BPFEnforceRPF:                      configParams.BPFEnforceRPF,
+			BPFAttachType:                      configParams.BPFAttachType,
+			BPFFragmentHandling:                configParams.BPFFragmentHandling,
+			BPFNAT64Enabled:                    configParams.BPFNAT64Enabled,
+			BPFNAT64Prefix:                     configParams.BPFNAT64Prefix,
+			BPFRoutesMirrorTableIndex:          configParams.BPFRoutesMirrorTableIndex,
+			BPFNodePortLinkLocalEnabled:        configParams.BPFNodePortLinkLocalEnabled,
 			BPFDisableGROForIfaces:             configParams.BPFDisableGROForIfaces,
 			XDPEnabled:                         configParams.XDPEnabled,
 			XDPAllowGeneric:                    configParams.GenericXDPEnabled,
@@ -374,7 +507,14 @@ func StartDataplaneDriver(configParams *config.Config,
 			RouteTableManager:                  routeTableIndexAllocator,
 			MTUIfacePattern:                    configParams.MTUIfacePattern,
 			BPFExcludeCIDRsFromNAT:             configParams.BPFExcludeCIDRsFromNAT,
-			ServiceLoopPrevention:              configParams.ServiceLoopPrevention,
+			BPFNodePortAddresses:               configParams.BPFNodePortAddresses,
+			BPFServiceLimits: bpfproxy.ServiceLimits{
+				BackendsSoft:  configParams.BPFServiceBackendsSoftLimit,
+				BackendsHard:  configParams.BPFServiceBackendsHardLimit,
+				FrontendsSoft: configParams.BPFServiceFrontendsSoftLimit,
+				FrontendsHard: configParams.BPFServiceFrontendsHardLimit,
+			},
+			ServiceLoopPrevention: configParams.ServiceLoopPrevention,
 
 			KubeClientSet: k8sClientSet,
 
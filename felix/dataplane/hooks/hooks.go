@@ -0,0 +1,229 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hooks implements the hooks that Felix runs before and after it
+// programs the dataplane.  A hook is either an executable, which gets a JSON
+// summary of the changes on its stdin, or a webhook, which gets the same
+// summary as the body of a POST request.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Phase string
+
+const (
+	PhasePreApply  Phase = "pre-apply"
+	PhasePostApply Phase = "post-apply"
+)
+
+// Summary describes a dataplane programming transaction.
+type Summary struct {
+	Phase    Phase  `json:"phase"`
+	Hostname string `json:"hostname"`
+	// Sequence numbers the transactions so that the post-apply summary can be
+	// matched with the pre-apply one.
+	Sequence uint64 `json:"sequence"`
+	// Updates counts the updates that the transaction programs by their type.
+	Updates map[string]int `json:"updates"`
+
+	// Only set after the transaction.
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	Success         *bool   `json:"success,omitempty"`
+}
+
+type Hook interface {
+	Run(ctx context.Context, s *Summary) error
+}
+
+// New returns the hook for the target, which is either an http(s) URL or the
+// absolute path of an executable.  It returns nil if the target is empty.
+func New(target string) (Hook, error) {
+	if target == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return &webHook{
+			url:    target,
+			client: &http.Client{},
+		}, nil
+	}
+
+	if !filepath.IsAbs(target) {
+		return nil, fmt.Errorf("hook %q is neither an http(s) URL nor an absolute path", target)
+	}
+
+	return &execHook{path: target}, nil
+}
+
+type execHook struct {
+	path string
+}
+
+func (h *execHook) Run(ctx context.Context, s *Summary) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, h.path, string(s.Phase))
+	cmd.Stdin = bytes.NewReader(buf)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("hook %s failed: %w, output: %s", h.path, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+type webHook struct {
+	url    string
+	client *http.Client
+}
+
+func (h *webHook) Run(ctx context.Context, s *Summary) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", h.url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", h.url, resp.Status)
+	}
+
+	return nil
+}
+
+// Runner runs the hooks around each dataplane programming transaction and
+// collects the updates that go into the next one.  Hooks are run synchronously
+// so that a pre-apply hook completes before the dataplane changes.  A failing
+// hook is only logged, it does not stop Felix from programming the dataplane.
+type Runner struct {
+	pre     Hook
+	post    Hook
+	timeout time.Duration
+
+	hostname string
+	sequence uint64
+	updates  map[string]int
+}
+
+// NewRunner returns a Runner for the pre and post apply hook targets, see New.
+// It returns nil if neither hook is configured.
+func NewRunner(preTarget, postTarget string, timeout time.Duration, hostname string) (*Runner, error) {
+	pre, err := New(preTarget)
+	if err != nil {
+		return nil, err
+	}
+	post, err := New(postTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	if pre == nil && post == nil {
+		return nil, nil
+	}
+
+	return newRunner(pre, post, timeout, hostname), nil
+}
+
+func newRunner(pre, post Hook, timeout time.Duration, hostname string) *Runner {
+	return &Runner{
+		pre:      pre,
+		post:     post,
+		timeout:  timeout,
+		hostname: hostname,
+		updates:  map[string]int{},
+	}
+}
+
+// RecordUpdate records an update of the given type for the next transaction.
+func (r *Runner) RecordUpdate(kind string) {
+	r.updates[kind]++
+}
+
+// PreApply runs the pre-apply hook and starts a new transaction.
+func (r *Runner) PreApply() {
+	r.sequence++
+	r.run(r.pre, r.summary(PhasePreApply))
+}
+
+// PostApply runs the post-apply hook and resets the recorded updates.
+func (r *Runner) PostApply(duration time.Duration, success bool) {
+	s := r.summary(PhasePostApply)
+	s.DurationSeconds = duration.Seconds()
+	s.Success = &success
+	r.run(r.post, s)
+
+	r.updates = map[string]int{}
+}
+
+func (r *Runner) summary(phase Phase) *Summary {
+	return &Summary{
+		Phase:    phase,
+		Hostname: r.hostname,
+		Sequence: r.sequence,
+		Updates:  r.updates,
+	}
+}
+
+func (r *Runner) run(h Hook, s *Summary) {
+	if h == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if err := h.Run(ctx, s); err != nil {
+		log.WithError(err).WithField("phase", s.Phase).Warn("Dataplane apply hook failed.")
+		return
+	}
+	log.WithFields(log.Fields{
+		"phase":    s.Phase,
+		"sequence": s.Sequence,
+		"duration": time.Since(start),
+	}).Debug("Dataplane apply hook succeeded.")
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingHook struct {
+	summaries []Summary
+	err       error
+}
+
+func (h *recordingHook) Run(_ context.Context, s *Summary) error {
+	c := *s
+	c.Updates = map[string]int{}
+	for k, v := range s.Updates {
+		c.Updates[k] = v
+	}
+	h.summaries = append(h.summaries, c)
+	return h.err
+}
+
+var _ = Describe("Dataplane apply hooks", func() {
+	It("should not return a runner without hooks", func() {
+		r, err := NewRunner("", "", time.Second, "node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r).To(BeNil())
+	})
+
+	It("should reject relative paths", func() {
+		_, err := NewRunner("hook.sh", "", time.Second, "node1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pass the summary of each transaction to the hooks", func() {
+		pre := &recordingHook{}
+		post := &recordingHook{err: errors.New("hook failed")}
+		r := newRunner(pre, post, time.Second, "node1")
+
+		r.RecordUpdate("WorkloadEndpointUpdate")
+		r.RecordUpdate("WorkloadEndpointUpdate")
+		r.RecordUpdate("ActivePolicyUpdate")
+		r.PreApply()
+		r.PostApply(2*time.Second, true)
+
+		r.PreApply()
+		r.PostApply(time.Second, false)
+
+		Expect(pre.summaries).To(Equal([]Summary{
+			{
+				Phase:    PhasePreApply,
+				Hostname: "node1",
+				Sequence: 1,
+				Updates:  map[string]int{"WorkloadEndpointUpdate": 2, "ActivePolicyUpdate": 1},
+			},
+			{
+				Phase:    PhasePreApply,
+				Hostname: "node1",
+				Sequence: 2,
+				Updates:  map[string]int{},
+			},
+		}))
+
+		Expect(post.summaries).To(HaveLen(2))
+		Expect(post.summaries[0].Phase).To(Equal(PhasePostApply))
+		Expect(post.summaries[0].Sequence).To(Equal(uint64(1)))
+		Expect(post.summaries[0].Updates).To(HaveLen(2))
+		Expect(post.summaries[0].DurationSeconds).To(Equal(2.0))
+		Expect(*post.summaries[0].Success).To(BeTrue())
+		Expect(*post.summaries[1].Success).To(BeFalse())
+	})
+
+	It("should pass the summary to an executable", func() {
+		dir, err := os.MkdirTemp("", "hooks")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		out := filepath.Join(dir, "out")
+		script := filepath.Join(dir, "hook.sh")
+		Expect(os.WriteFile(script, []byte("#!/bin/sh\necho $1 > "+out+"\ncat >> "+out+"\n"), 0o700)).To(Succeed())
+
+		h, err := New(script)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(h.Run(context.Background(), &Summary{Phase: PhasePreApply, Sequence: 3})).To(Succeed())
+
+		buf, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).To(HavePrefix("pre-apply\n"))
+		Expect(string(buf)).To(ContainSubstring(`"sequence":3`))
+	})
+
+	It("should POST the summary to a webhook", func() {
+		var got Summary
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(json.NewDecoder(r.Body).Decode(&got)).To(Succeed())
+			if got.Phase == PhasePostApply {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer srv.Close()
+
+		h, err := New(srv.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(h.Run(context.Background(), &Summary{Phase: PhasePreApply, Hostname: "node1"})).To(Succeed())
+		Expect(got.Hostname).To(Equal("node1"))
+
+		Expect(h.Run(context.Background(), &Summary{Phase: PhasePostApply})).To(HaveOccurred())
+	})
+})
@@ -54,6 +54,7 @@ import (
 	"github.com/projectcalico/calico/felix/logutils"
 	"github.com/projectcalico/calico/felix/rules"
 
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	logutilslc "github.com/projectcalico/calico/libcalico-go/lib/logutils"
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
 
@@ -72,11 +73,13 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/legacy"
 	"github.com/projectcalico/calico/felix/bpf/libbpf"
 	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/polbitmap"
 	"github.com/projectcalico/calico/felix/bpf/polprog"
 	"github.com/projectcalico/calico/felix/bpf/tc"
 	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/bpf/xdp"
 	"github.com/projectcalico/calico/felix/cachingmap"
+	"github.com/projectcalico/calico/felix/config"
 	"github.com/projectcalico/calico/felix/idalloc"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ip"
@@ -105,6 +108,11 @@ var (
 		Name: "felix_bpf_happy_dataplane_endpoints",
 		Help: "Number of BPF endpoints that are successfully programmed.",
 	})
+	bpfHWOffloadAttachesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "felix_bpf_hw_offload_attaches",
+		Help: "Number of times that a program was attached to an interface in BPFHardwareOffloadIfaces, " +
+			"by result: offloaded if the NIC runs it, software if it fell back to the kernel.",
+	}, []string{"result"})
 	errApplyingPolicy = errors.New("error applying policy")
 )
 
@@ -117,6 +125,7 @@ func init() {
 	prometheus.MustRegister(bpfEndpointsGauge)
 	prometheus.MustRegister(bpfDirtyEndpointsGauge)
 	prometheus.MustRegister(bpfHappyEndpointsGauge)
+	prometheus.MustRegister(bpfHWOffloadAttachesCounter)
 
 	binary.LittleEndian.PutUint32(jumpMapV4PolicyKey, uint32(tcdefs.ProgIndexPolicy))
 	binary.LittleEndian.PutUint32(jumpMapV6PolicyKey, uint32(tcdefs.ProgIndexPolicy))
@@ -252,6 +261,7 @@ func (d *bpfInterfaceJumpIndices) clearJumps() {
 
 type qDiscInfo struct {
 	valid  bool
+	tcx    bool
 	prio   int
 	handle int
 }
@@ -284,6 +294,12 @@ type bpfEndpointManager struct {
 
 	dirtyIfaceNames set.Set[string]
 
+	// Last state of every interface, including the ones that we do not attach to, so that they
+	// can be reconsidered when BPFAttachIncludeIfaces/BPFAttachExcludeIfaces change.
+	ifaceStates map[string]ifaceStateUpdate
+	// Interfaces that have been excluded and still need their programs detached.
+	ifacesToDetach set.Set[string]
+
 	logFilters              map[string]string
 	bpfLogLevel             string
 	hostname                string
@@ -291,17 +307,25 @@ type bpfEndpointManager struct {
 	dataIfaceRegex          *regexp.Regexp
 	l3IfaceRegex            *regexp.Regexp
 	workloadIfaceRegex      *regexp.Regexp
+	attachIncludeIfaces     []*regexp.Regexp
+	attachExcludeIfaces     []*regexp.Regexp
+	hwOffloadIfaces         []*regexp.Regexp
 	epToHostAction          string
 	vxlanMTU                int
 	vxlanPort               uint16
 	wgPort                  uint16
 	wg6Port                 uint16
+	wgEnabled               bool
 	dsrEnabled              bool
 	dsrOptoutCidrs          bool
+	dropFragments           bool
+	ctShards                uint16
+	nat64Prefix             net.IP
 	bpfExtToServiceConnmark int
 	psnatPorts              numorstring.Port
 	commonMaps              *bpfmap.CommonMaps
 	ifStateMap              *cachingmap.CachingMap[ifstate.Key, ifstate.Value]
+	policyBitmapMap         *cachingmap.CachingMap[polbitmap.Key, polbitmap.Value]
 	removeOldJumps          bool
 	legacyCleanUp           bool
 
@@ -350,6 +374,7 @@ type bpfEndpointManager struct {
 
 	// RPF mode
 	rpfEnforceOption string
+	bpfAttachType    apiv3.BPFAttachOption
 
 	// BPF Disable GRO ifaces map
 	bpfDisableGROForIfaces *regexp.Regexp
@@ -358,6 +383,7 @@ type bpfEndpointManager struct {
 	hostNetworkedNATMode hostNetworkedNATMode
 
 	bpfPolicyDebugEnabled bool
+	failsafeNDPEnabled    bool
 
 	routeTableV4     routetable.RouteTableInterface
 	routeTableV6     routetable.RouteTableInterface
@@ -476,19 +502,27 @@ func newBPFEndpointManager(
 		policiesToWorkloads:     map[proto.PolicyID]set.Set[any]{},
 		profilesToWorkloads:     map[proto.ProfileID]set.Set[any]{},
 		dirtyIfaceNames:         set.New[string](),
+		ifaceStates:             map[string]ifaceStateUpdate{},
+		ifacesToDetach:          set.New[string](),
 		bpfLogLevel:             config.BPFLogLevel,
 		hostname:                config.Hostname,
 		fibLookupEnabled:        fibLookupEnabled,
 		dataIfaceRegex:          config.BPFDataIfacePattern,
 		l3IfaceRegex:            config.BPFL3IfacePattern,
 		workloadIfaceRegex:      workloadIfaceRegex,
+		attachIncludeIfaces:     config.BPFAttachIncludeIfaces,
+		attachExcludeIfaces:     config.BPFAttachExcludeIfaces,
+		hwOffloadIfaces:         config.BPFHardwareOffloadIfaces,
 		epToHostAction:          config.RulesConfig.EndpointToHostAction,
 		vxlanMTU:                config.VXLANMTU,
 		vxlanPort:               uint16(config.VXLANPort),
 		wgPort:                  uint16(config.Wireguard.ListeningPort),
 		wg6Port:                 uint16(config.Wireguard.ListeningPortV6),
+		wgEnabled:               config.Wireguard.Enabled || config.Wireguard.EnabledV6,
 		dsrEnabled:              config.BPFNodePortDSREnabled,
 		dsrOptoutCidrs:          len(config.BPFDSROptoutCIDRs) > 0,
+		dropFragments:           config.BPFFragmentHandling == string(apiv3.BPFFragmentHandlingDrop),
+		ctShards:                uint16(config.BPFConntrackShards),
 		bpfExtToServiceConnmark: config.BPFExtToServiceConnmark,
 		psnatPorts:              config.BPFPSNATPorts,
 		commonMaps:              bpfmaps.CommonMaps,
@@ -511,8 +545,10 @@ func newBPFEndpointManager(
 		// TODO: set ipv6Enabled to config.Ipv6Enabled when IPv6 support is complete
 		ipv6Enabled:            config.BPFIpv6Enabled,
 		rpfEnforceOption:       config.BPFEnforceRPF,
+		bpfAttachType:          apiv3.BPFAttachOption(config.BPFAttachType),
 		bpfDisableGROForIfaces: config.BPFDisableGROForIfaces,
 		bpfPolicyDebugEnabled:  config.BPFPolicyDebugEnabled,
+		failsafeNDPEnabled:     config.RulesConfig.FailsafeNDPEnabled,
 		polNameToMatchIDs:      map[string]set.Set[polprog.RuleMatchID]{},
 		dirtyRules:             set.New[polprog.RuleMatchID](),
 
@@ -531,6 +567,13 @@ func newBPFEndpointManager(
 		})
 	}
 
+	if config.BPFPolicyBitmapsEnabled {
+		m.policyBitmapMap = cachingmap.New[polbitmap.Key, polbitmap.Value](polbitmap.MapParams.Name,
+			maps.NewTypedMap[polbitmap.Key, polbitmap.Value](
+				bpfmaps.CommonMaps.PolicyBitmapMap.(maps.MapWithExistsCheck), polbitmap.KeyFromBytes, polbitmap.ValueFromBytes,
+			))
+	}
+
 	m.updateRateLimitedLog = logutilslc.NewRateLimitedLogger(
 		logutilslc.OptInterval(30*time.Second),
 		logutilslc.OptBurst(10),
@@ -569,6 +612,15 @@ func newBPFEndpointManager(
 		m.v6 = newBPFEndpointManagerDataplane(proto.IPVersion_IPV6, bpfmaps.V6, iptablesFilterTableV6, ipSetIDAllocV6, m)
 	}
 
+	if config.BPFNAT64Enabled && m.ipv6Enabled {
+		// The prefix is validated by the config.
+		_, prefix, err := net.ParseCIDR(config.BPFNAT64Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NAT64 prefix %q: %w", config.BPFNAT64Prefix, err)
+		}
+		m.nat64Prefix = prefix.IP
+	}
+
 	if m.hostNetworkedNATMode != hostNetworkedNATDisabled {
 		log.Infof("HostNetworkedNATMode is %d", m.hostNetworkedNATMode)
 		if m.v4 != nil {
@@ -691,7 +743,7 @@ func newBPFEndpointManagerDataplane(
 var _ hasLoadPolicyProgram = (*bpfEndpointManager)(nil)
 
 func (m *bpfEndpointManager) repinJumpMaps() error {
-	oldBase := path.Join(bpfdefs.GlobalPinDir, "old_jumps")
+	oldBase := path.Join(bpfdefs.GlobalPinDir(), "old_jumps")
 	err := os.Mkdir(oldBase, 0700)
 	if err != nil && !os.IsExist(err) {
 		return fmt.Errorf("cannot create %s: %w", oldBase, err)
@@ -850,9 +902,89 @@ func (m *bpfEndpointManager) OnUpdate(msg interface{}) {
 		m.onServiceRemove(msg)
 	case *proto.RouteUpdate:
 		m.onRouteUpdate(msg)
+	case *proto.ConfigUpdate:
+		m.onConfigUpdate(msg)
 	}
 }
 
+// onConfigUpdate applies changes of BPFAttachIncludeIfaces and BPFAttachExcludeIfaces, which do
+// not need a restart.
+func (m *bpfEndpointManager) onConfigUpdate(msg *proto.ConfigUpdate) {
+	// The update carries the already resolved values, re-parse them into a scratch config.
+	cfg := config.New()
+	if _, err := cfg.UpdateFrom(msg.Config, config.InternalOverride); err != nil {
+		log.WithError(err).Warn("Failed to parse config update, ignoring interface attach lists.")
+		return
+	}
+	if sameRegexps(cfg.BPFAttachIncludeIfaces, m.attachIncludeIfaces) &&
+		sameRegexps(cfg.BPFAttachExcludeIfaces, m.attachExcludeIfaces) {
+		return
+	}
+	m.updateAttachLists(cfg.BPFAttachIncludeIfaces, cfg.BPFAttachExcludeIfaces)
+}
+
+func (m *bpfEndpointManager) updateAttachLists(include, exclude []*regexp.Regexp) {
+	log.WithFields(log.Fields{
+		"include": include,
+		"exclude": exclude,
+	}).Info("BPF interface attach lists changed.")
+
+	wasManaged := map[string]bool{}
+	for name := range m.ifaceStates {
+		wasManaged[name] = m.isManagedIface(name)
+	}
+
+	// Take interfaces that are no longer managed down while the old lists are in place so that
+	// their state is cleaned up the same way as when they go down.
+	oldInclude, oldExclude := m.attachIncludeIfaces, m.attachExcludeIfaces
+	m.attachIncludeIfaces, m.attachExcludeIfaces = include, exclude
+	var removed []string
+	for name, managed := range wasManaged {
+		if managed && !m.isManagedIface(name) {
+			removed = append(removed, name)
+		}
+	}
+	m.attachIncludeIfaces, m.attachExcludeIfaces = oldInclude, oldExclude
+	for _, name := range removed {
+		log.WithField("iface", name).Info("Interface excluded from BPF attachment.")
+		st := m.ifaceStates[name]
+		m.onInterfaceUpdate(&ifaceStateUpdate{Name: name, State: ifacemonitor.StateDown, Index: st.Index, ParentName: st.ParentName})
+		m.ifaceStates[name] = st
+		m.ifacesToDetach.Add(name)
+	}
+
+	m.attachIncludeIfaces, m.attachExcludeIfaces = include, exclude
+	for name, managed := range wasManaged {
+		if !managed && m.isManagedIface(name) {
+			log.WithField("iface", name).Info("Interface included in BPF attachment.")
+			m.ifacesToDetach.Discard(name)
+			st := m.ifaceStates[name]
+			m.onInterfaceUpdate(&st)
+		}
+	}
+}
+
+// detachExcludedIfaces removes the programs from interfaces that were excluded from BPF
+// attachment at runtime.
+func (m *bpfEndpointManager) detachExcludedIfaces() {
+	m.ifacesToDetach.Iter(func(name string) error {
+		if _, ok := m.ifaceStates[name]; !ok {
+			// Interface is gone.
+			return set.RemoveItem
+		}
+		// We do not know which of the hooks have programs, try all of them.
+		ai := bpf.EPAttachInfo{Ingress: 1, Egress: 1, XDP: 1}
+		if err := m.cleanupOldAttach(name, ai); err != nil {
+			log.WithError(err).WithField("iface", name).Warn("Failed to detach programs from excluded interface, will retry.")
+			return nil
+		}
+		if err := bpf.ForgetIfaceAttachedProg(name); err != nil {
+			log.WithError(err).WithField("iface", name).Warn("Failed to remove attached programs record.")
+		}
+		return set.RemoveItem
+	})
+}
+
 func (m *bpfEndpointManager) onRouteUpdate(update *proto.RouteUpdate) {
 	if update.Type == proto.RouteType_LOCAL_TUNNEL {
 		ip, _, err := net.ParseCIDR(update.Dst)
@@ -1005,6 +1137,7 @@ func (m *bpfEndpointManager) updateIfaceStateMap(name string, iface *bpfInterfac
 			iface.dpState.filterIdx[hook.Egress],
 		)
 		m.ifStateMap.Desired().Set(k, v)
+		m.updatePolicyBitmap(name, iface)
 	} else {
 		if m.v4 != nil {
 			m.reclaimPolicyIdx(name, 4, iface)
@@ -1014,10 +1147,44 @@ func (m *bpfEndpointManager) updateIfaceStateMap(name string, iface *bpfInterfac
 		}
 		m.reclaimFilterIdx(name, iface)
 		m.ifStateMap.Desired().Delete(k)
+		if m.policyBitmapMap != nil {
+			m.policyBitmapMap.Desired().Delete(polbitmap.NewKey(uint32(iface.info.ifIndex)))
+		}
 		iface.dpState.clearJumps()
 	}
 }
 
+// updatePolicyBitmap sets the policy bitmap of the interface's endpoint, as calculated by the
+// calculation graph.  The entry is removed if the endpoint has no bitmap so that the programs
+// fall back to the endpoint's policy program.
+func (m *bpfEndpointManager) updatePolicyBitmap(name string, iface *bpfInterface) {
+	if m.policyBitmapMap == nil {
+		return
+	}
+
+	var bitmap []byte
+	if iface.info.endpointID != nil {
+		if wep := m.allWEPs[*iface.info.endpointID]; wep != nil {
+			bitmap = wep.PolicyBitmap
+		}
+	} else if hep, ok := m.hostIfaceToEpMap[name]; ok {
+		bitmap = hep.PolicyBitmap
+	}
+
+	k := polbitmap.NewKey(uint32(iface.info.ifIndex))
+	if len(bitmap) == 0 {
+		m.policyBitmapMap.Desired().Delete(k)
+		return
+	}
+	v, ok := polbitmap.NewValue(bitmap)
+	if !ok {
+		log.WithField("iface", name).Warn("Policy bitmap too large, ignoring.")
+		m.policyBitmapMap.Desired().Delete(k)
+		return
+	}
+	m.policyBitmapMap.Desired().Set(k, v)
+}
+
 func (m *bpfEndpointManager) deleteIfaceCounters(name string, ifindex int) {
 	err := m.commonMaps.CountersMap.Delete(counters.NewKey(ifindex, hook.Ingress).AsBytes())
 	if err != nil && !maps.IsNotExists(err) {
@@ -1082,9 +1249,12 @@ func (m *bpfEndpointManager) onInterfaceUpdate(update *ifaceStateUpdate) {
 		if err := bpf.ForgetIfaceAttachedProg(update.Name); err != nil {
 			log.WithError(err).Errorf("Error in removing interface %s json file. err=%v", update.Name, err)
 		}
+		delete(m.ifaceStates, update.Name)
+	} else {
+		m.ifaceStates[update.Name] = *update
 	}
 
-	if !m.isDataIface(update.Name) && !m.isWorkloadIface(update.Name) && !m.isL3Iface(update.Name) {
+	if !m.isManagedIface(update.Name) {
 		if update.State == ifacemonitor.StateUp {
 			if ai, ok := m.initAttaches[update.Name]; ok {
 				if err := m.cleanupOldAttach(update.Name, ai); err != nil {
@@ -1530,7 +1700,7 @@ func (m *bpfEndpointManager) loadDefaultPolicies() error {
 				return fmt.Errorf("error resizing map %s: %w", mapName, err)
 			}
 		}
-		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir, mapName)); err != nil {
+		if err := m.SetPinPath(path.Join(bpfdefs.GlobalPinDir(), mapName)); err != nil {
 			return fmt.Errorf("error pinning map %s: %w", mapName, err)
 		}
 	}
@@ -1567,6 +1737,11 @@ func (m *bpfEndpointManager) CompleteDeferredWork() error {
 		if err := m.ifStateMap.LoadCacheFromDataplane(); err != nil {
 			log.WithError(err).Fatal("Cannot load interface state map - essential for consistent operation.")
 		}
+		if m.policyBitmapMap != nil {
+			if err := m.policyBitmapMap.LoadCacheFromDataplane(); err != nil {
+				log.WithError(err).Warn("Failed to load policy bitmap map, entries may leak.")
+			}
+		}
 
 		m.initUnknownIfaces.Iter(func(iface string) error {
 			if ai, ok := m.initAttaches[iface]; ok {
@@ -1598,6 +1773,7 @@ func (m *bpfEndpointManager) CompleteDeferredWork() error {
 		log.Info("BPF counters synced.")
 	})
 
+	m.detachExcludedIfaces()
 	m.applyProgramsToDirtyDataInterfaces()
 	m.updateWEPsInDataplane()
 	if m.bpfPolicyDebugEnabled {
@@ -1623,6 +1799,13 @@ func (m *bpfEndpointManager) CompleteDeferredWork() error {
 		return err
 	}
 
+	if m.policyBitmapMap != nil {
+		if err := m.policyBitmapMap.ApplyAllChanges(); err != nil {
+			log.WithError(err).Warn("Failed to write updates to policy bitmap BPF map.")
+			return err
+		}
+	}
+
 	if m.happyWEPsDirty {
 		chains := m.ruleRenderer.WorkloadInterfaceAllowChains(m.happyWEPs)
 		if m.v4 != nil {
@@ -1650,7 +1833,7 @@ func (m *bpfEndpointManager) CompleteDeferredWork() error {
 
 	if m.dirtyIfaceNames.Len() == 0 {
 		if m.removeOldJumps {
-			oldBase := path.Join(bpfdefs.GlobalPinDir, "old_jumps")
+			oldBase := path.Join(bpfdefs.GlobalPinDir(), "old_jumps")
 			if err := os.RemoveAll(oldBase); err != nil && os.IsNotExist(err) {
 				m.reportHealth(false, "Failed to clean up old jump maps.")
 				return fmt.Errorf("failed to remove %s: %w", oldBase, err)
@@ -2185,7 +2368,9 @@ func (m *bpfEndpointManager) doApplyPolicy(ifaceName string) (bpfInterfaceState,
 
 	v4Readiness := state.v4Readiness
 	v6Readiness := state.v6Readiness
-	if v4Readiness == ifaceIsReady || v6Readiness == ifaceIsReady {
+	// Programs attached via tcx are pinned links that are only ever replaced
+	// atomically, there is no classifier to query.
+	if !state.qdisc.tcx && (v4Readiness == ifaceIsReady || v6Readiness == ifaceIsReady) {
 		if _, err := m.dp.queryClassifier(ifindex, state.qdisc.handle, state.qdisc.prio, true); err != nil {
 			v4Readiness = ifaceNotReady
 			v6Readiness = ifaceNotReady
@@ -2247,7 +2432,9 @@ func (m *bpfEndpointManager) doApplyPolicy(ifaceName string) (bpfInterfaceState,
 		return state, fmt.Errorf("ingress qdisc info (%v) does not equal egress qdisc info (%v)",
 			ingressQdisc, egressQdisc)
 	}
-	state.qdisc = ingressQdisc
+	if attachPreamble {
+		state.qdisc = ingressQdisc
+	}
 
 	if err4 != nil && err6 != nil {
 		// This covers the case when we don't have hostIP on both paths.
@@ -2294,10 +2481,18 @@ func (m *bpfEndpointManager) ensureProgramAttached(ap attachPoint) (qDiscInfo, e
 	if err != nil {
 		return qdisc, err
 	}
-	if tcRes, ok := res.(*tc.AttachResult); ok {
+	if tcRes, ok := res.(tc.AttachResult); ok {
 		qdisc.valid = true
+		qdisc.tcx = tcRes.TCX()
 		qdisc.prio = tcRes.Prio()
 		qdisc.handle = tcRes.Handle()
+		if tcAP, ok := ap.(*tc.AttachPoint); ok && tcAP.HardwareOffload {
+			if tcRes.Offloaded() {
+				bpfHWOffloadAttachesCounter.WithLabelValues("offloaded").Inc()
+			} else {
+				bpfHWOffloadAttachesCounter.WithLabelValues("software").Inc()
+			}
+		}
 	}
 	return qdisc, nil
 }
@@ -2778,11 +2973,25 @@ func (m *bpfEndpointManager) calculateTCAttachPoint(ifaceName string) *tc.Attach
 	ap.FIB = m.fibLookupEnabled
 	ap.DSR = m.dsrEnabled
 	ap.DSROptoutCIDRs = m.dsrOptoutCidrs
+	// The flag is shared by both IP families, with WireGuard enabled only for
+	// one of them, the other family merely loses the FIB short-circuit for
+	// DNATed traffic to remote workloads.
+	ap.WireguardEnabled = m.wgEnabled
+	ap.DropFragments = m.dropFragments
+	ap.CTShards = m.ctShards
+	if m.nat64Prefix != nil && m.v4 != nil && m.v4.hostIP != nil {
+		// Pods translated to IPv4 are masqueraded to the IPv4 address of the
+		// host.
+		ap.NAT64Prefix = m.nat64Prefix
+		ap.NAT64IP = m.v4.hostIP
+	}
 	ap.LogLevel, ap.LogFilter = m.apLogFilter(ap, ifaceName)
 	ap.VXLANPort = m.vxlanPort
 	ap.PSNATStart = m.psnatPorts.MinPort
 	ap.PSNATEnd = m.psnatPorts.MaxPort
 	ap.TunnelMTU = uint16(m.vxlanMTU)
+	ap.AttachType = m.bpfAttachType
+	ap.HardwareOffload = matchesAnyRegexp(m.hwOffloadIfaces, ifaceName)
 
 	switch m.rpfEnforceOption {
 	case "Strict":
@@ -2841,9 +3050,17 @@ func (m *bpfEndpointManager) extractTiers(tier *proto.TierInfo, direction PolDir
 		return
 	}
 
-	directionalPols := tier.IngressPolicies
+	tierPols := tier.IngressPolicies
 	if direction == PolDirnEgress {
-		directionalPols = tier.EgressPolicies
+		tierPols = tier.EgressPolicies
+	}
+
+	// Staged policies are only evaluated by the iptables dataplane.
+	var directionalPols []string
+	for _, polName := range tierPols {
+		if !model.PolicyIsStaged(polName) {
+			directionalPols = append(directionalPols, polName)
+		}
 	}
 
 	if len(directionalPols) > 0 {
@@ -2934,19 +3151,64 @@ func (m *bpfEndpointManager) extractRules(tier *proto.TierInfo, profileNames []s
 }
 
 func (m *bpfEndpointManager) isWorkloadIface(iface string) bool {
-	return m.workloadIfaceRegex.MatchString(iface)
+	return m.workloadIfaceRegex.MatchString(iface) && !m.isAttachExcluded(iface)
 }
 
 func (m *bpfEndpointManager) isDataIface(iface string) bool {
-	return m.dataIfaceRegex.MatchString(iface) ||
-		(m.hostNetworkedNATMode != hostNetworkedNATDisabled && (iface == bpfOutDev || iface == "lo"))
+	if m.hostNetworkedNATMode != hostNetworkedNATDisabled && (iface == bpfOutDev || iface == "lo") {
+		return true
+	}
+	if m.isAttachExcluded(iface) {
+		return false
+	}
+	if m.dataIfaceRegex.MatchString(iface) || matchesAnyRegexp(m.attachIncludeIfaces, iface) {
+		return true
+	}
+	// Macvlan and ipvlan interfaces of a data interface get programs too, their traffic doesn't
+	// go through the programs of their parent.
+	parent := m.ifaceStates[iface].ParentName
+	return parent != "" && m.isDataIface(parent)
 }
 
 func (m *bpfEndpointManager) isL3Iface(iface string) bool {
 	if m.l3IfaceRegex == nil {
 		return false
 	}
-	return m.l3IfaceRegex.MatchString(iface)
+	return m.l3IfaceRegex.MatchString(iface) && !m.isAttachExcluded(iface)
+}
+
+func (m *bpfEndpointManager) isManagedIface(iface string) bool {
+	return m.isDataIface(iface) || m.isWorkloadIface(iface) || m.isL3Iface(iface)
+}
+
+// isAttachExcluded returns true if the interface is in BPFAttachExcludeIfaces.  Our own
+// devices cannot be excluded.
+func (m *bpfEndpointManager) isAttachExcluded(iface string) bool {
+	if iface == bpfInDev || iface == bpfOutDev {
+		return false
+	}
+	return matchesAnyRegexp(m.attachExcludeIfaces, iface)
+}
+
+func sameRegexps(a, b []*regexp.Regexp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].String() != b[i].String() {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAnyRegexp(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *bpfEndpointManager) addWEPToIndexes(wlID proto.WorkloadEndpointID, wl *proto.WorkloadEndpoint) {
@@ -3471,7 +3733,7 @@ func (m *bpfEndpointManager) writePolicyDebugInfo(insns []asm.Insns, ifaceName s
 	if !m.bpfPolicyDebugEnabled {
 		return nil
 	}
-	if err := os.MkdirAll(bpf.RuntimePolDir, 0600); err != nil {
+	if err := os.MkdirAll(bpf.RuntimePolDir(), 0600); err != nil {
 		return err
 	}
 
@@ -3678,6 +3940,9 @@ func (m *bpfEndpointManager) doUpdatePolicyProgram(
 	if m.bpfPolicyDebugEnabled {
 		opts = append(opts, polprog.WithPolicyDebugEnabled())
 	}
+	if m.failsafeNDPEnabled && hk != hook.XDP {
+		opts = append(opts, polprog.WithNDPFailsafe())
+	}
 
 	staticProgsMap := m.commonMaps.ProgramsMap
 	if hk == hook.XDP {
@@ -4035,6 +4300,11 @@ func (m *bpfEndpointManager) addRuleInfo(rule *proto.Rule, idx int,
 }
 
 func (m *bpfEndpointManager) ruleMatchID(dir, action, owner, name string, idx int) polprog.RuleMatchID {
+	return ruleMatchID(dir, action, owner, name, idx)
+}
+
+// ruleMatchID returns the ID under which the hits of a rule are counted in the rule counters map.
+func ruleMatchID(dir, action, owner, name string, idx int) polprog.RuleMatchID {
 	h := fnv.New64a()
 	h.Write([]byte(action + owner + dir + strconv.Itoa(idx) + name))
 	return h.Sum64()
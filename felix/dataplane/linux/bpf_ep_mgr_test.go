@@ -44,6 +44,7 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	bpfmaps "github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/polbitmap"
 	"github.com/projectcalico/calico/felix/bpf/polprog"
 	"github.com/projectcalico/calico/felix/bpf/state"
 	"github.com/projectcalico/calico/felix/bpf/tc"
@@ -341,6 +342,8 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		countersMap          *mock.Map
 		jumpMap              *mock.Map
 		xdpJumpMap           *mock.Map
+		policyBitmapMap      *mock.Map
+		policyBitmaps        bool
 	)
 
 	BeforeEach(func() {
@@ -353,6 +356,7 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		ipSetIDAllocatorV6 = idalloc.New()
 		vxlanMTU = 0
 		nodePortDSR = true
+		policyBitmaps = false
 
 		bpfmaps.EnableRepin()
 
@@ -392,6 +396,8 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		commonMaps.JumpMap = jumpMap
 		xdpJumpMap = mock.NewMockMap(progsParams)
 		commonMaps.XDPJumpMap = xdpJumpMap
+		policyBitmapMap = mock.NewMockMap(polbitmap.MapParams)
+		commonMaps.PolicyBitmapMap = policyBitmapMap
 
 		maps.V4 = v4Maps
 		maps.V6 = v6Maps
@@ -440,6 +446,7 @@ var _ = Describe("BPF Endpoint Manager", func() {
 				BPFExtToServiceConnmark: 0,
 				BPFHostNetworkedNAT:     "Enabled",
 				BPFPolicyDebugEnabled:   true,
+				BPFPolicyBitmapsEnabled: policyBitmaps,
 				BPFIpv6Enabled:          ipv6Enabled,
 			},
 			maps,
@@ -784,6 +791,55 @@ var _ = Describe("BPF Endpoint Manager", func() {
 			Expect(dp.programAttached("eth0:egress")).To(BeTrue())
 		})
 
+		It("should request hardware offload for the listed interfaces", func() {
+			Expect(bpfEpMgr.calculateTCAttachPoint("eth0").HardwareOffload).To(BeFalse())
+			bpfEpMgr.hwOffloadIfaces = []*regexp.Regexp{regexp.MustCompile("^eth0$")}
+			Expect(bpfEpMgr.calculateTCAttachPoint("eth0").HardwareOffload).To(BeTrue())
+		})
+
+		It("should detach from eth0 when it is excluded at runtime", func() {
+			bpfEpMgr.OnUpdate(&proto.ConfigUpdate{Config: map[string]string{
+				"BPFAttachExcludeIfaces": "/^eth/",
+			}})
+			err := bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dp.programAttached("eth0:ingress")).To(BeFalse())
+			Expect(dp.programAttached("eth0:egress")).To(BeFalse())
+			Expect(ifStateMap.ContainsKey(ifstate.NewKey(10).AsBytes())).To(BeFalse())
+
+			By("attaching again when the exclusion is removed")
+			bpfEpMgr.OnUpdate(&proto.ConfigUpdate{Config: map[string]string{}})
+			err = bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dp.programAttached("eth0:ingress")).To(BeTrue())
+			Expect(dp.programAttached("eth0:egress")).To(BeTrue())
+		})
+
+		It("should attach to an interface included at runtime", func() {
+			genIfaceUpdate("ipvl0", ifacemonitor.StateUp, 11)()
+			Expect(dp.programAttached("ipvl0:ingress")).To(BeFalse())
+
+			bpfEpMgr.OnUpdate(&proto.ConfigUpdate{Config: map[string]string{
+				"BPFAttachIncludeIfaces": "ipvl0",
+			}})
+			err := bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dp.programAttached("ipvl0:ingress")).To(BeTrue())
+			Expect(dp.programAttached("ipvl0:egress")).To(BeTrue())
+			Expect(dp.programAttached("eth0:ingress")).To(BeTrue())
+		})
+
+		It("should attach to the macvlan and ipvlan interfaces of data interfaces", func() {
+			bpfEpMgr.OnUpdate(&ifaceStateUpdate{Name: "mv0", State: ifacemonitor.StateUp, Index: 11, ParentName: "eth0"})
+			bpfEpMgr.OnUpdate(&ifaceStateUpdate{Name: "mv1", State: ifacemonitor.StateUp, Index: 12, ParentName: "dummy0"})
+			err := bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dp.programAttached("mv0:ingress")).To(BeTrue())
+			Expect(dp.programAttached("mv0:egress")).To(BeTrue())
+			Expect(dp.programAttached("mv1:ingress")).To(BeFalse())
+			Expect(dp.programAttached("mv1:egress")).To(BeFalse())
+		})
+
 		Context("with dataIfacePattern changed to eth1", func() {
 			JustBeforeEach(func() {
 				dataIfacePattern = "^eth1"
@@ -1189,6 +1245,54 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		})
 	})
 
+	Describe("policy bitmaps", func() {
+		BeforeEach(func() {
+			policyBitmaps = true
+		})
+
+		genWLBitmapUpdate := func(name string, bitmap []byte) {
+			bpfEpMgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+				Id: &proto.WorkloadEndpointID{
+					OrchestratorId: "k8s",
+					WorkloadId:     name,
+					EndpointId:     name,
+				},
+				Endpoint: &proto.WorkloadEndpoint{Name: name, PolicyBitmap: bitmap},
+			})
+			err := bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should maintain the bitmap of the workload interface", func() {
+			genIfaceUpdate("cali12345", ifacemonitor.StateUp, 15)()
+			genWLBitmapUpdate("cali12345", []byte{0x0, 0x2})
+
+			k := polbitmap.NewKey(15)
+			vb, err := policyBitmapMap.Get(k.AsBytes())
+			Expect(err).NotTo(HaveOccurred())
+			v := polbitmap.ValueFromBytes(vb)
+			Expect(v.Has(9)).To(BeTrue())
+			Expect(v.Has(1)).To(BeFalse())
+
+			By("removing the entry when the endpoint has no bitmap")
+			genWLBitmapUpdate("cali12345", nil)
+			Expect(policyBitmapMap.ContainsKey(k.AsBytes())).To(BeFalse())
+
+			By("removing the entry when the interface goes down")
+			genWLBitmapUpdate("cali12345", []byte{0x2})
+			Expect(policyBitmapMap.ContainsKey(k.AsBytes())).To(BeTrue())
+			genIfaceUpdate("cali12345", ifacemonitor.StateDown, 15)()
+			Expect(policyBitmapMap.ContainsKey(k.AsBytes())).To(BeFalse())
+		})
+
+		It("should remove stale entries after restart", func() {
+			_ = policyBitmapMap.Update(polbitmap.NewKey(123).AsBytes(), polbitmap.Value{0x2}.AsBytes())
+			err := bpfEpMgr.CompleteDeferredWork()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policyBitmapMap.ContainsKey(polbitmap.NewKey(123).AsBytes())).To(BeFalse())
+		})
+	})
+
 	Describe("ifstate(ipv6 disabled)", func() {
 		It("should clean up jump map entries for missing interfaces", func() {
 			for i := 0; i < 17; i++ {
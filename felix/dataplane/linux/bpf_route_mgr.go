@@ -15,19 +15,26 @@
 package intdataplane
 
 import (
+	"bytes"
 	"net"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
 
 	"github.com/projectcalico/calico/felix/bpf/bpfmap"
 	"github.com/projectcalico/calico/felix/bpf/maps"
+	bpfproxy "github.com/projectcalico/calico/felix/bpf/proxy"
 	"github.com/projectcalico/calico/felix/bpf/routes"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ip"
 	"github.com/projectcalico/calico/felix/logutils"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/routetable"
+	"github.com/projectcalico/calico/felix/rules"
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
 )
 
@@ -63,12 +70,17 @@ type bpfRouteManager struct {
 	// cidrToLocalIfaces maps from (/32) CIDR to the set of interfaces that have that CIDR
 	cidrToLocalIfaces map[ip.CIDR]set.Set[string]
 	localIfaceToCIDRs map[string]set.Set[ip.CIDR]
+	// localIfaceToLinkLocals maps local interface name to its link-local addresses. These do not
+	// get routes, they are only passed to kube-proxy as NodePort addresses if enabled.
+	localIfaceToLinkLocals map[string]set.Set[ip.Addr]
 	// cidrToWEPIDs maps from (/32) CIDR to the set of local proto.WorkloadEndpointIDs that have that CIDR.
 	cidrToWEPIDs map[ip.CIDR]set.Set[proto.WorkloadEndpointID]
 	// wepIDToWorkload contains all the local workloads.
 	wepIDToWorkload map[proto.WorkloadEndpointID]*proto.WorkloadEndpoint
 	// ifaceNameToIdx maps local interface name to interface ID.
 	ifaceNameToIdx map[string]int
+	// ifaceIdxToName is the reverse of ifaceNameToIdx.
+	ifaceIdxToName map[int]string
 	// ifaceNameToWEPIDs maps local interface name to the set of local proto.WorkloadEndpointIDs that have that name.
 	// (Usually a single WEP).
 	ifaceNameToWEPIDs map[string]set.Set[proto.WorkloadEndpointID]
@@ -78,6 +90,9 @@ type bpfRouteManager struct {
 	// Set of CIDRs for which we need to update the BPF routes.
 	dirtyCIDRs     set.Set[ip.CIDR]
 	dsrOptoutCIDRs *ip.CIDRTrie
+	// l7ProxyCIDRs contains the members of the L7 proxy IP set, the workloads whose TCP
+	// connections are handed to the L7 proxy.
+	l7ProxyCIDRs set.Set[ip.CIDR]
 
 	// These fields track the desired state of the dataplane and the set of inconsistencies
 	// between that and the real state of the dataplane.
@@ -86,11 +101,20 @@ type bpfRouteManager struct {
 	desiredRoutes map[routes.KeyInterface]routes.ValueInterface
 	dirtyRoutes   set.Set[routes.KeyInterface]
 
+	// mirrorTable, if not nil, is the kernel routing table that the desired routes are mirrored
+	// into for operators to inspect.  mirroredIfaces records the interface of each mirrored route.
+	mirrorTable    routetable.RouteTableInterface
+	mirroredIfaces map[ip.CIDR]string
+
 	// Callbacks used to tell kube-proxy about the relevant routes.
 	cbLck           sync.RWMutex
 	hostIPsUpdateCB func([]net.IP)
 	routesUpdateCB  func(routes.KeyInterface, routes.ValueInterface)
 	routesDeleteCB  func(routes.KeyInterface)
+	hostPortsCB     func([]bpfproxy.HostPort)
+	// hostPortsDirty is set when the hostPorts of local workloads changed and
+	// kube-proxy needs to be told about them.
+	hostPortsDirty bool
 
 	opReporter logutils.OpRecorder
 
@@ -98,10 +122,11 @@ type bpfRouteManager struct {
 	ipFamily          proto.IPVersion
 	blockedCIDRs      set.Set[ip.CIDR]
 	svcLoopPrevention string
+	nodePortLinkLocal bool
 }
 
 func newBPFRouteManager(config *Config, maps *bpfmap.IPMaps, ipFamily proto.IPVersion,
-	opReporter logutils.OpRecorder) *bpfRouteManager {
+	mirrorTable routetable.RouteTableInterface, opReporter logutils.OpRecorder) *bpfRouteManager {
 
 	// Record the external node CIDRs and pre-mark them as dirty.  These can only change with a config update,
 	// which would restart Felix.
@@ -149,11 +174,15 @@ func newBPFRouteManager(config *Config, maps *bpfmap.IPMaps, ipFamily proto.IPVe
 		cidrToWEPIDs:      map[ip.CIDR]set.Set[proto.WorkloadEndpointID]{},
 		wepIDToWorkload:   map[proto.WorkloadEndpointID]*proto.WorkloadEndpoint{},
 		ifaceNameToIdx:    map[string]int{},
+		ifaceIdxToName:    map[int]string{},
 		ifaceNameToWEPIDs: map[string]set.Set[proto.WorkloadEndpointID]{},
 		externalNodeCIDRs: extCIDRs,
 		dirtyCIDRs:        dirtyCIDRs,
 		dsrOptoutCIDRs:    noDsrCIDRs,
 		blockedCIDRs:      set.New[ip.CIDR](),
+		l7ProxyCIDRs:      set.New[ip.CIDR](),
+
+		localIfaceToLinkLocals: map[string]set.Set[ip.Addr]{},
 
 		desiredRoutes: map[routes.KeyInterface]routes.ValueInterface{},
 		routeMap:      maps.RouteMap,
@@ -161,11 +190,15 @@ func newBPFRouteManager(config *Config, maps *bpfmap.IPMaps, ipFamily proto.IPVe
 		dirtyRoutes:     set.New[routes.KeyInterface](),
 		resyncScheduled: true,
 
+		mirrorTable:    mirrorTable,
+		mirroredIfaces: map[ip.CIDR]string{},
+
 		opReporter: opReporter,
 
 		wgEnabled:         config.Wireguard.Enabled || config.Wireguard.EnabledV6,
 		ipFamily:          ipFamily,
 		svcLoopPrevention: config.ServiceLoopPrevention,
+		nodePortLinkLocal: config.BPFNodePortLinkLocalEnabled && ipFamily == proto.IPVersion_IPV6,
 	}
 
 	if ipFamily == proto.IPVersion_IPV6 {
@@ -209,6 +242,41 @@ func (m *bpfRouteManager) OnUpdate(msg interface{}) {
 		m.onWorkloadEndpointRemove(msg)
 	case *proto.GlobalBGPConfigUpdate:
 		m.onBGPConfigUpdate(msg)
+
+	// Updates to the L7 proxy IP set, which flag the routes of its local workloads.
+	case *proto.IPSetUpdate:
+		if msg.Id == rules.IPSetIDL7Proxy {
+			m.onL7ProxyIPSetUpdate(msg.Members)
+		}
+	case *proto.IPSetDeltaUpdate:
+		if msg.Id == rules.IPSetIDL7Proxy {
+			m.onL7ProxyIPSetDelta(msg.AddedMembers, msg.RemovedMembers)
+		}
+	case *proto.IPSetRemove:
+		if msg.Id == rules.IPSetIDL7Proxy {
+			m.onL7ProxyIPSetUpdate(nil)
+		}
+	}
+}
+
+func (m *bpfRouteManager) onL7ProxyIPSetUpdate(members []string) {
+	m.l7ProxyCIDRs.Iter(func(cidr ip.CIDR) error {
+		m.dirtyCIDRs.Add(cidr)
+		return set.RemoveItem
+	})
+	m.onL7ProxyIPSetDelta(members, nil)
+}
+
+func (m *bpfRouteManager) onL7ProxyIPSetDelta(added, removed []string) {
+	for _, member := range removed {
+		cidr := ip.MustParseCIDROrIP(member)
+		m.l7ProxyCIDRs.Discard(cidr)
+		m.dirtyCIDRs.Add(cidr)
+	}
+	for _, member := range added {
+		cidr := ip.MustParseCIDROrIP(member)
+		m.l7ProxyCIDRs.Add(cidr)
+		m.dirtyCIDRs.Add(cidr)
 	}
 }
 
@@ -228,6 +296,11 @@ func (m *bpfRouteManager) CompleteDeferredWork() error {
 	// Step 3: apply dataplane updates.
 	numDels, numAdds := m.applyUpdates()
 
+	if m.hostPortsDirty {
+		m.onHostPortsUpdateCB(m.calculateHostPorts())
+		m.hostPortsDirty = false
+	}
+
 	duration := time.Since(startTime)
 	if numDels > 0 || numAdds > 0 {
 		m.opReporter.RecordOperation("update-bpf-routes")
@@ -258,6 +331,7 @@ func (m *bpfRouteManager) recalculateRoutesForDirtyCIDRs() {
 			}
 			m.desiredRoutes[dataplaneKey] = newValue
 			m.onRouteUpdateCB(dataplaneKey, newValue)
+			m.updateMirrorRoute(cidr, newValue)
 		} else {
 			if !exists {
 				// Value is already correct.  We're done.
@@ -265,12 +339,58 @@ func (m *bpfRouteManager) recalculateRoutesForDirtyCIDRs() {
 			}
 			delete(m.desiredRoutes, dataplaneKey)
 			m.onRouteDeleteCB(dataplaneKey)
+			m.updateMirrorRoute(cidr, nil)
 		}
 		m.dirtyRoutes.Add(dataplaneKey)
 		return set.RemoveItem
 	})
 }
 
+// updateMirrorRoute reflects the new BPF route of the CIDR, or its removal if value is nil, in the
+// mirror routing table.  The kernel can't represent all the BPF route flags, so routes to local
+// workloads point at the workload interface, blackhole routes are blackhole or prohibit routes and
+// the rest are throw routes, that is, routes that the kernel forwards with the main table.
+func (m *bpfRouteManager) updateMirrorRoute(cidr ip.CIDR, value routes.ValueInterface) {
+	if m.mirrorTable == nil {
+		return
+	}
+
+	var ifaceName string
+	var target routetable.Target
+	if value != nil {
+		ifaceName = routetable.InterfaceNone
+		target = routetable.Target{Type: routetable.TargetTypeThrow, CIDR: cidr}
+		flags := value.Flags()
+		if flags&routes.FlagBlackHoleDrop != 0 {
+			target.Type = routetable.TargetTypeBlackhole
+		} else if flags&routes.FlagBlackHoleReject != 0 {
+			target.Type = routetable.TargetTypeProhibit
+		} else if flags&routes.FlagsLocalWorkload == routes.FlagsLocalWorkload {
+			// Until the interface is up, the route has no interface index.
+			if name, ok := m.ifaceIdxToName[int(value.IfaceIndex())]; ok {
+				ifaceName = name
+				target = routetable.Target{CIDR: cidr}
+			}
+		}
+	}
+
+	if oldIfaceName, ok := m.mirroredIfaces[cidr]; ok && oldIfaceName != ifaceName {
+		m.mirrorTable.RouteRemove(oldIfaceName, cidr)
+		delete(m.mirroredIfaces, cidr)
+	}
+	if value != nil {
+		m.mirrorTable.RouteUpdate(ifaceName, target)
+		m.mirroredIfaces[cidr] = ifaceName
+	}
+}
+
+func (m *bpfRouteManager) GetRouteTableSyncers() []routetable.RouteTableSyncer {
+	if m.mirrorTable == nil {
+		return nil
+	}
+	return []routetable.RouteTableSyncer{m.mirrorTable}
+}
+
 func (m *bpfRouteManager) calculateRoute(cidr ip.CIDR) routes.ValueInterface {
 	// First check for a matching local host IP.  The calculation graph doesn't know about all of these
 	// so we might not get a CG route.
@@ -328,6 +448,9 @@ func (m *bpfRouteManager) calculateRoute(cidr ip.CIDR) routes.ValueInterface {
 			// Just the local IPAM block, not an actual workload.
 			return nil
 		}
+		if m.l7ProxyCIDRs.Contains(cidr) {
+			flags |= routes.FlagL7Proxy
+		}
 		if wepIDs, ok := m.cidrToWEPIDs[cidr]; ok {
 			bestWepScore := -1
 			var bestWepID proto.WorkloadEndpointID
@@ -486,13 +609,18 @@ func (m *bpfRouteManager) onIfaceUpdate(msg *ifaceStateUpdate) {
 	if msg.State == ifacemonitor.StateUp {
 		oldIdx, ok := m.ifaceNameToIdx[msg.Name]
 		if !ok || oldIdx != msg.Index {
+			if ok {
+				delete(m.ifaceIdxToName, oldIdx)
+			}
 			m.ifaceNameToIdx[msg.Name] = msg.Index
+			m.ifaceIdxToName[msg.Index] = msg.Name
 			m.onIfaceIdxChanged(msg.Name)
 		}
 	} else {
-		_, ok := m.ifaceNameToIdx[msg.Name]
+		oldIdx, ok := m.ifaceNameToIdx[msg.Name]
 		if ok {
 			delete(m.ifaceNameToIdx, msg.Name)
+			delete(m.ifaceIdxToName, oldIdx)
 			m.onIfaceIdxChanged(msg.Name)
 		}
 	}
@@ -515,6 +643,7 @@ func (m *bpfRouteManager) onIfaceAddrsUpdate(update *ifaceAddrsUpdate) {
 	changed := false
 
 	var newCIDRs set.Set[ip.CIDR]
+	newLinkLocals := set.New[ip.Addr]()
 	if update.Addrs == nil {
 		newCIDRs = set.Empty[ip.CIDR]()
 	} else {
@@ -524,13 +653,27 @@ func (m *bpfRouteManager) onIfaceAddrsUpdate(update *ifaceAddrsUpdate) {
 			if uint8(m.ipFamily) != cidr.Version() {
 				return nil
 			}
-			if cidr.Addr().AsNetIP().IsGlobalUnicast() {
+			addr := cidr.Addr().AsNetIP()
+			if addr.IsGlobalUnicast() {
 				newCIDRs.Add(cidr)
+			} else if m.nodePortLinkLocal && addr.IsLinkLocalUnicast() {
+				newLinkLocals.Add(cidr.Addr())
 			}
 			return nil
 		})
 	}
 
+	if oldLinkLocals, ok := m.localIfaceToLinkLocals[update.Name]; ok || newLinkLocals.Len() > 0 {
+		if !ok || !oldLinkLocals.Equals(newLinkLocals) {
+			changed = true
+		}
+		if newLinkLocals.Len() > 0 {
+			m.localIfaceToLinkLocals[update.Name] = newLinkLocals
+		} else {
+			delete(m.localIfaceToLinkLocals, update.Name)
+		}
+	}
+
 	cidrs := m.localIfaceToCIDRs[update.Name]
 	if cidrs != nil {
 		cidrs.Iter(func(cidr ip.CIDR) error {
@@ -568,14 +711,34 @@ func (m *bpfRouteManager) onIfaceAddrsUpdate(update *ifaceAddrsUpdate) {
 	})
 
 	if changed {
-		var newIPs []net.IP
-		for cidr := range m.cidrToLocalIfaces {
-			newIPs = append(newIPs, cidr.Addr().AsNetIP())
-		}
-		m.onHostIPsChange(newIPs)
+		m.onHostIPsChange(m.hostIPs())
 	}
 }
 
+// hostIPs returns the sorted, de-duplicated list of host IPs which kube-proxy
+// uses as NodePort addresses. Sorting keeps the list stable for hosts with
+// multiple addresses so that kube-proxy sees the same list for the same state.
+func (m *bpfRouteManager) hostIPs() []net.IP {
+	addrs := set.New[ip.Addr]()
+	for cidr := range m.cidrToLocalIfaces {
+		addrs.Add(cidr.Addr())
+	}
+	for _, lls := range m.localIfaceToLinkLocals {
+		addrs.AddSet(lls)
+	}
+
+	ips := make([]net.IP, 0, addrs.Len())
+	addrs.Iter(func(addr ip.Addr) error {
+		ips = append(ips, addr.AsNetIP())
+		return nil
+	})
+	sort.Slice(ips, func(i, j int) bool {
+		return bytes.Compare(ips[i].To16(), ips[j].To16()) < 0
+	})
+
+	return ips
+}
+
 func (m *bpfRouteManager) onHostIPsChange(newIPs []net.IP) {
 	m.cbLck.RLock()
 	defer m.cbLck.RUnlock()
@@ -626,6 +789,9 @@ func (m *bpfRouteManager) onWorkloadEndpointUpdate(update *proto.WorkloadEndpoin
 
 func (m *bpfRouteManager) addWEP(update *proto.WorkloadEndpointUpdate) {
 	m.wepIDToWorkload[*update.Id] = update.Endpoint
+	if len(update.Endpoint.PortMappings) > 0 {
+		m.hostPortsDirty = true
+	}
 	newCIDRs := m.getWorkloadCIDRs(update.Endpoint)
 
 	for _, cidr := range newCIDRs {
@@ -692,6 +858,9 @@ func (m *bpfRouteManager) removeWEP(id *proto.WorkloadEndpointID) {
 		return
 	}
 	delete(m.wepIDToWorkload, *id)
+	if len(oldWEP.PortMappings) > 0 {
+		m.hostPortsDirty = true
+	}
 	oldCIDRs := m.getWorkloadCIDRs(oldWEP)
 	for _, cidr := range oldCIDRs {
 		m.cidrToWEPIDs[cidr].Discard(*id)
@@ -722,6 +891,70 @@ func (m *bpfRouteManager) getWorkloadCIDRs(wep *proto.WorkloadEndpoint) (cidrs [
 	return
 }
 
+// calculateHostPorts collects the hostPort mappings of all local workloads of
+// our IP family.
+func (m *bpfRouteManager) calculateHostPorts() []bpfproxy.HostPort {
+	var hps []bpfproxy.HostPort
+
+	for id, wep := range m.wepIDToWorkload {
+		if len(wep.PortMappings) == 0 {
+			continue
+		}
+		cidrs := m.getWorkloadCIDRs(wep)
+		if len(cidrs) == 0 {
+			continue
+		}
+		wlIP := cidrs[0].Addr().AsNetIP()
+
+		namespace, name := "", id.WorkloadId
+		if parts := strings.SplitN(id.WorkloadId, "/", 2); len(parts) == 2 {
+			namespace, name = parts[0], parts[1]
+		}
+
+		for _, pm := range wep.PortMappings {
+			var hostIP net.IP
+			if pm.HostIp != "" {
+				hostIP = net.ParseIP(pm.HostIp)
+				if hostIP == nil {
+					log.WithField("hostIP", pm.HostIp).Warn("Ignoring hostPort with unparsable host IP.")
+					continue
+				}
+				if (hostIP.To4() != nil) != (m.ipFamily == proto.IPVersion_IPV4) {
+					continue
+				}
+			}
+			hps = append(hps, bpfproxy.HostPort{
+				Namespace: namespace,
+				Name:      name,
+				Protocol:  v1.Protocol(strings.ToUpper(pm.Protocol)),
+				HostIP:    hostIP,
+				HostPort:  int(pm.HostPort),
+				IP:        wlIP,
+				Port:      int(pm.ContainerPort),
+			})
+		}
+	}
+
+	return hps
+}
+
+func (m *bpfRouteManager) setHostPortsCallBack(cb func([]bpfproxy.HostPort)) {
+	m.cbLck.Lock()
+	defer m.cbLck.Unlock()
+
+	m.hostPortsCB = cb
+	// Make sure that the new consumer gets the current state.
+	m.hostPortsDirty = true
+}
+
+func (m *bpfRouteManager) onHostPortsUpdateCB(hps []bpfproxy.HostPort) {
+	m.cbLck.RLock()
+	defer m.cbLck.RUnlock()
+	if m.hostPortsCB != nil {
+		m.hostPortsCB(hps)
+	}
+}
+
 func (m *bpfRouteManager) setHostIPUpdatesCallBack(cb func([]net.IP)) {
 	m.cbLck.Lock()
 	defer m.cbLck.Unlock()
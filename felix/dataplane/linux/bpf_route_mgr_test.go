@@ -0,0 +1,124 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+	"github.com/projectcalico/calico/felix/ifacemonitor"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/logutils"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/routetable"
+)
+
+// recordingRouteTable records the routes of each interface that are set with RouteUpdate.
+type recordingRouteTable struct {
+	mockRouteTable
+	ifaceRoutes map[string]map[ip.CIDR]routetable.Target
+}
+
+func (t *recordingRouteTable) RouteUpdate(ifaceName string, target routetable.Target) {
+	if t.ifaceRoutes[ifaceName] == nil {
+		t.ifaceRoutes[ifaceName] = map[ip.CIDR]routetable.Target{}
+	}
+	t.ifaceRoutes[ifaceName][target.CIDR] = target
+}
+
+func (t *recordingRouteTable) RouteRemove(ifaceName string, cidr ip.CIDR) {
+	delete(t.ifaceRoutes[ifaceName], cidr)
+	if len(t.ifaceRoutes[ifaceName]) == 0 {
+		delete(t.ifaceRoutes, ifaceName)
+	}
+}
+
+var _ = Describe("BPF route manager routes mirror", func() {
+	var (
+		rt    *recordingRouteTable
+		rtMgr *bpfRouteManager
+	)
+
+	wepCIDR := ip.MustParseCIDROrIP("10.65.0.2/32")
+	remoteCIDR := ip.MustParseCIDROrIP("10.65.1.0/26")
+
+	BeforeEach(func() {
+		rt = &recordingRouteTable{ifaceRoutes: map[string]map[ip.CIDR]routetable.Target{}}
+		maps := &bpfmap.IPMaps{RouteMap: mock.NewMockMap(routes.MapParameters)}
+		rtMgr = newBPFRouteManager(&Config{
+			Hostname:              "node-1",
+			ServiceLoopPrevention: "Drop",
+		}, maps, proto.IPVersion_IPV4, rt, logutils.NewSummarizer("test"))
+
+		rtMgr.OnUpdate(&proto.RouteUpdate{
+			Type:          proto.RouteType_LOCAL_WORKLOAD,
+			Dst:           wepCIDR.String(),
+			DstNodeName:   "node-1",
+			LocalWorkload: true,
+		})
+		rtMgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+			Id:       &proto.WorkloadEndpointID{OrchestratorId: "k8s", WorkloadId: "default/web", EndpointId: "eth0"},
+			Endpoint: &proto.WorkloadEndpoint{Name: "cali12345", Ipv4Nets: []string{wepCIDR.String()}},
+		})
+		rtMgr.OnUpdate(&proto.RouteUpdate{
+			Type:        proto.RouteType_REMOTE_WORKLOAD,
+			Dst:         remoteCIDR.String(),
+			DstNodeName: "node-2",
+			DstNodeIp:   "192.168.0.2",
+		})
+		rtMgr.OnUpdate(&ifaceStateUpdate{Name: "cali12345", State: ifacemonitor.StateUp, Index: 7})
+		Expect(rtMgr.CompleteDeferredWork()).To(Succeed())
+	})
+
+	It("should mirror local workload routes to their interface and the rest as throw routes", func() {
+		Expect(rt.ifaceRoutes).To(Equal(map[string]map[ip.CIDR]routetable.Target{
+			"cali12345": {wepCIDR: {CIDR: wepCIDR}},
+			routetable.InterfaceNone: {
+				remoteCIDR: {Type: routetable.TargetTypeThrow, CIDR: remoteCIDR},
+			},
+		}))
+		Expect(rtMgr.GetRouteTableSyncers()).To(ConsistOf(rt))
+	})
+
+	It("should move the workload route when its interface goes down", func() {
+		rtMgr.OnUpdate(&ifaceStateUpdate{Name: "cali12345", State: ifacemonitor.StateDown, Index: 7})
+		Expect(rtMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(rt.ifaceRoutes).To(Equal(map[string]map[ip.CIDR]routetable.Target{
+			routetable.InterfaceNone: {
+				wepCIDR:    {Type: routetable.TargetTypeThrow, CIDR: wepCIDR},
+				remoteCIDR: {Type: routetable.TargetTypeThrow, CIDR: remoteCIDR},
+			},
+		}))
+	})
+
+	It("should mirror blocked service CIDRs as blackhole routes", func() {
+		svcCIDR := ip.MustParseCIDROrIP("10.96.0.0/12")
+		rtMgr.OnUpdate(&proto.GlobalBGPConfigUpdate{ServiceClusterCidrs: []string{svcCIDR.String()}})
+		Expect(rtMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(rt.ifaceRoutes[routetable.InterfaceNone]).To(HaveKeyWithValue(svcCIDR,
+			routetable.Target{Type: routetable.TargetTypeBlackhole, CIDR: svcCIDR}))
+	})
+
+	It("should remove the routes that are removed from the BPF routes", func() {
+		rtMgr.OnUpdate(&proto.RouteRemove{Dst: remoteCIDR.String()})
+		Expect(rtMgr.CompleteDeferredWork()).To(Succeed())
+		Expect(rt.ifaceRoutes).To(Equal(map[string]map[ip.CIDR]routetable.Target{
+			"cali12345": {wepCIDR: {CIDR: wepCIDR}},
+		}))
+	})
+})
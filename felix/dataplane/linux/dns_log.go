@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/dnscache"
+	"github.com/projectcalico/calico/felix/dnslog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+// dnsLogManager logs the DNS responses that Felix snoops along with the local workload that
+// received each of them.  It tracks the addresses of the local workload endpoints so that the
+// client of a response can be identified on the NFLOG goroutine.
+type dnsLogManager struct {
+	logger *dnslog.Logger
+
+	lock         sync.Mutex
+	endpointIPs  map[proto.WorkloadEndpointID][]string
+	ipToEndpoint map[string]proto.WorkloadEndpointID
+
+	timeNow func() time.Time
+}
+
+func newDNSLogManager(logger *dnslog.Logger) *dnsLogManager {
+	return &dnsLogManager{
+		logger:       logger,
+		endpointIPs:  map[proto.WorkloadEndpointID][]string{},
+		ipToEndpoint: map[string]proto.WorkloadEndpointID{},
+		timeNow:      time.Now,
+	}
+}
+
+func (m *dnsLogManager) OnUpdate(msg interface{}) {
+	switch msg := msg.(type) {
+	case *proto.WorkloadEndpointUpdate:
+		var ips []string
+		for _, nets := range [][]string{msg.Endpoint.Ipv4Nets, msg.Endpoint.Ipv6Nets} {
+			for _, n := range nets {
+				ip, _, _ := strings.Cut(n, "/")
+				ips = append(ips, ip)
+			}
+		}
+		m.lock.Lock()
+		m.removeEndpointLocked(*msg.Id)
+		m.endpointIPs[*msg.Id] = ips
+		for _, ip := range ips {
+			m.ipToEndpoint[ip] = *msg.Id
+		}
+		m.lock.Unlock()
+	case *proto.WorkloadEndpointRemove:
+		m.lock.Lock()
+		m.removeEndpointLocked(*msg.Id)
+		m.lock.Unlock()
+	}
+}
+
+func (m *dnsLogManager) removeEndpointLocked(id proto.WorkloadEndpointID) {
+	for _, ip := range m.endpointIPs[id] {
+		if m.ipToEndpoint[ip] == id {
+			delete(m.ipToEndpoint, ip)
+		}
+	}
+	delete(m.endpointIPs, id)
+}
+
+func (m *dnsLogManager) CompleteDeferredWork() error {
+	return nil
+}
+
+// OnPacket is called on the NFLOG goroutine with each snooped DNS response.
+func (m *dnsLogManager) OnPacket(payload []byte) {
+	resp, ok := dnscache.ParseResponse(payload)
+	if !ok {
+		return
+	}
+	e := dnslog.NewEntry(m.timeNow(), resp)
+
+	m.lock.Lock()
+	id, ok := m.ipToEndpoint[e.ClientIP]
+	m.lock.Unlock()
+	if ok {
+		e.ClientWorkload = id.WorkloadId
+		e.ClientEndpoint = id.EndpointId
+	} else {
+		log.WithField("client", e.ClientIP).Debug("DNS response to an address that is not a local workload.")
+	}
+	m.logger.Log(e)
+}
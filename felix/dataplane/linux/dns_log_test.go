@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/dnslog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+type chanDNSLogExporter chan dnslog.Entry
+
+func (c chanDNSLogExporter) Export(line []byte) error {
+	var e dnslog.Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return err
+	}
+	c <- e
+	return nil
+}
+
+func (c chanDNSLogExporter) Close() {}
+
+func snoopedDNSResponse(client string) []byte {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.ParseIP("10.96.0.10"),
+		DstIP:    net.ParseIP(client),
+	}
+	udp := &layers.UDP{SrcPort: 53, DstPort: 34567}
+	_ = udp.SetNetworkLayerForChecksum(ip)
+	dns := &layers.DNS{
+		ID:        1,
+		QR:        true,
+		Questions: []layers.DNSQuestion{{Name: []byte("example.com"), Type: layers.DNSTypeA, Class: layers.DNSClassIN}},
+		Answers: []layers.DNSResourceRecord{{
+			Name:  []byte("example.com"),
+			Type:  layers.DNSTypeA,
+			Class: layers.DNSClassIN,
+			TTL:   60,
+			IP:    net.ParseIP("93.184.216.34"),
+		}},
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true},
+		ip, udp, dns)
+	Expect(err).NotTo(HaveOccurred())
+	return buf.Bytes()
+}
+
+var _ = Describe("dnsLogManager", func() {
+	var (
+		entries chanDNSLogExporter
+		mgr     *dnsLogManager
+		wepID   proto.WorkloadEndpointID
+	)
+
+	BeforeEach(func() {
+		entries = make(chanDNSLogExporter, 10)
+		logger := dnslog.NewLogger(entries)
+		logger.Start()
+		mgr = newDNSLogManager(logger)
+		wepID = proto.WorkloadEndpointID{
+			OrchestratorId: "k8s",
+			WorkloadId:     "default/pod-1",
+			EndpointId:     "eth0",
+		}
+		mgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+			Id:       &wepID,
+			Endpoint: &proto.WorkloadEndpoint{Ipv4Nets: []string{"10.65.0.2/32"}},
+		})
+	})
+
+	It("should log the workload that received a response", func() {
+		mgr.OnPacket(snoopedDNSResponse("10.65.0.2"))
+		var e dnslog.Entry
+		Eventually(entries).Should(Receive(&e))
+		Expect(e.ClientIP).To(Equal("10.65.0.2"))
+		Expect(e.ClientWorkload).To(Equal("default/pod-1"))
+		Expect(e.ClientEndpoint).To(Equal("eth0"))
+		Expect(e.QName).To(Equal("example.com"))
+		Expect(e.IPs).To(Equal([]string{"93.184.216.34"}))
+	})
+
+	It("should log responses to other addresses without a workload", func() {
+		mgr.OnPacket(snoopedDNSResponse("10.65.0.3"))
+		var e dnslog.Entry
+		Eventually(entries).Should(Receive(&e))
+		Expect(e.ClientIP).To(Equal("10.65.0.3"))
+		Expect(e.ClientWorkload).To(BeEmpty())
+	})
+
+	It("should forget the addresses of removed endpoints", func() {
+		mgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: &wepID})
+		mgr.OnPacket(snoopedDNSResponse("10.65.0.2"))
+		var e dnslog.Entry
+		Eventually(entries).Should(Receive(&e))
+		Expect(e.ClientWorkload).To(BeEmpty())
+	})
+
+	It("should ignore packets that are not DNS responses", func() {
+		mgr.OnPacket([]byte{0x45, 0, 0})
+		Consistently(entries, "100ms").ShouldNot(Receive())
+	})
+})
@@ -26,6 +26,7 @@ import (
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/felix/capabilities"
 	"github.com/projectcalico/calico/felix/dataplane/common"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ip"
@@ -176,6 +177,8 @@ type endpointManager struct {
 	// hostIfaceToAddrs maps host interface name to the set of IPs on that interface (reported
 	// from the dataplane).
 	hostIfaceToAddrs map[string]set.Set[string]
+	// hostIfaceToParent maps macvlan and ipvlan interfaces to their parent interface.
+	hostIfaceToParent map[string]string
 	// rawHostEndpoints contains the raw (i.e. not resolved to interface) host endpoints.
 	rawHostEndpoints map[proto.HostEndpointID]*proto.HostEndpoint
 	// hostEndpointsDirty is set to true when host endpoints are updated.
@@ -313,6 +316,7 @@ func newEndpointManagerWithShims(
 		defaultRPFilter:      defaultRPFilter,
 
 		hostIfaceToAddrs:   map[string]set.Set[string]{},
+		hostIfaceToParent:  map[string]string{},
 		rawHostEndpoints:   map[proto.HostEndpointID]*proto.HostEndpoint{},
 		hostEndpointsDirty: true,
 
@@ -358,6 +362,15 @@ func (m *endpointManager) OnUpdate(protoBufMsg interface{}) {
 	case *ifaceStateUpdate:
 		log.WithField("update", msg).Debug("Interface state changed.")
 		m.pendingIfaceUpdates[msg.Name] = msg.State
+		if msg.ParentName != "" && msg.State != ifacemonitor.StateNotPresent {
+			if m.hostIfaceToParent[msg.Name] != msg.ParentName {
+				m.hostIfaceToParent[msg.Name] = msg.ParentName
+				m.hostEndpointsDirty = true
+			}
+		} else if _, ok := m.hostIfaceToParent[msg.Name]; ok {
+			delete(m.hostIfaceToParent, msg.Name)
+			m.hostEndpointsDirty = true
+		}
 	case *ifaceAddrsUpdate:
 		log.WithField("update", msg).Debug("Interface addrs changed.")
 		if m.wlIfacesRegexp.MatchString(msg.Name) {
@@ -995,6 +1008,22 @@ func (m *endpointManager) resolveHostEndpoints() map[string]proto.HostEndpointID
 		}
 	}
 
+	// The traffic of macvlan and ipvlan interfaces doesn't go through the hooks of their parent,
+	// so, unless a host endpoint matches them directly, they get the host endpoint of the parent.
+	for ifaceName, parentName := range m.hostIfaceToParent {
+		if _, ok := newIfaceNameToHostEpID[ifaceName]; ok {
+			continue
+		}
+		if id, ok := newIfaceNameToHostEpID[parentName]; ok {
+			log.WithFields(log.Fields{
+				"ifaceName":  ifaceName,
+				"parentName": parentName,
+				"hostEpId":   id,
+			}).Debug("Interface inherits HostEp of its parent")
+			newIfaceNameToHostEpID[ifaceName] = id
+		}
+	}
+
 	// Similar loop to find the best all-interfaces host endpoint.
 	bestHostEpId := proto.HostEndpointID{}
 	for id, hostEp := range m.rawHostEndpoints {
@@ -1456,7 +1485,7 @@ func (m *endpointManager) configureInterface(name string) error {
 }
 
 func writeProcSys(path, value string) error {
-	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	f, err := os.OpenFile(capabilities.ProcSysPath(path), os.O_WRONLY, 0)
 	if err != nil {
 		return err
 	}
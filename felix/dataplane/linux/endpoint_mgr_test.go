@@ -888,6 +888,65 @@ func endpointManagerTests(ipVersion uint8) func() {
 				})
 			})
 
+			Context("with macvlan interface mv0 on eth0", func() {
+				JustBeforeEach(func() {
+					epMgr.OnUpdate(&ifaceStateUpdate{
+						Name:       "mv0",
+						State:      "up",
+						ParentName: "eth0",
+					})
+					epMgr.OnUpdate(&ifaceAddrsUpdate{
+						Name:  "mv0",
+						Addrs: set.From("192.168.10.2"),
+					})
+					applyUpdates(epMgr)
+				})
+
+				Describe("with host endpoint matching eth0", func() {
+					JustBeforeEach(configureHostEp(&hostEpSpec{
+						id:      "id1",
+						name:    "eth0",
+						polName: "polA",
+					}))
+
+					It("should apply the host endpoint of eth0 to mv0", func() {
+						Expect(hepListener.state).To(Equal(map[string]string{
+							"eth0": "profiles=,normal=I=polA,E=polA,untracked=,preDNAT=,AoF=",
+							"mv0":  "profiles=,normal=I=polA,E=polA,untracked=,preDNAT=,AoF=",
+						}))
+					})
+
+					Context("with a host endpoint matching mv0", func() {
+						JustBeforeEach(configureHostEp(&hostEpSpec{
+							id:      "id2",
+							name:    "mv0",
+							polName: "polB",
+						}))
+
+						It("should apply its own host endpoint to mv0", func() {
+							Expect(hepListener.state).To(Equal(map[string]string{
+								"eth0": "profiles=,normal=I=polA,E=polA,untracked=,preDNAT=,AoF=",
+								"mv0":  "profiles=,normal=I=polB,E=polB,untracked=,preDNAT=,AoF=",
+							}))
+						})
+					})
+
+					Context("after mv0 is removed", func() {
+						JustBeforeEach(func() {
+							epMgr.OnUpdate(&ifaceStateUpdate{Name: "mv0", State: ifacemonitor.StateNotPresent})
+							epMgr.OnUpdate(&ifaceAddrsUpdate{Name: "mv0"})
+							applyUpdates(epMgr)
+						})
+
+						It("should only apply the host endpoint to eth0", func() {
+							Expect(hepListener.state).To(Equal(map[string]string{
+								"eth0": "profiles=,normal=I=polA,E=polA,untracked=,preDNAT=,AoF=",
+							}))
+						})
+					})
+				})
+			})
+
 			// Configure host endpoints with tier names here, so we can check which of
 			// the host endpoints gets used in the programming for a particular host
 			// interface.  When more than one host endpoint matches a given interface,
@@ -0,0 +1,397 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/flowlog"
+	"github.com/projectcalico/calico/felix/ktls"
+	"github.com/projectcalico/calico/felix/nflog"
+	"github.com/projectcalico/calico/felix/policylog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+const (
+	// flowLogNFLOGCopyRange is the number of bytes of each packet that the kernel passes to
+	// Felix, enough for the IP and transport headers.
+	flowLogNFLOGCopyRange = 128
+
+	// flowLogConntrackPollInterval is how often the conntrack table is read for the packet and
+	// byte counts of the connections, and to detect the connections that ended.
+	flowLogConntrackPollInterval = 10 * time.Second
+
+	flowLogProfileTier = "__PROFILE__"
+)
+
+// ruleRef identifies the policy rule with a rule ID.
+type ruleRef struct {
+	tier      string
+	name      string
+	direction string
+	index     int
+}
+
+// flowLogManager produces the flow logs of the local workloads in iptables mode.  The policy
+// rules report their verdicts over NFLOG with their rule ID, which the manager maps back to the
+// policy rule, and the traffic of the allowed connections is counted from the conntrack table.
+// The verdicts also feed the policy verdict metrics, if enabled; without an exporter, the
+// manager only reads the verdicts for the metrics.
+//
+// The updates arrive on the dataplane goroutine while the verdicts arrive on the NFLOG
+// goroutine, so the maps are protected by the lock.
+type flowLogManager struct {
+	aggregator    *flowlog.Aggregator
+	exporter      flowlog.Exporter
+	reader        *nflog.Reader
+	flushInterval time.Duration
+	families      []netlink.InetFamily
+
+	verdictMetrics *policyVerdictMetrics
+
+	lock         sync.Mutex
+	endpointIPs  map[proto.WorkloadEndpointID][]netip.Addr
+	ipToEndpoint map[netip.Addr]proto.WorkloadEndpointID
+	policyRules  map[proto.PolicyID][]string
+	profileRules map[proto.ProfileID][]string
+	rules        map[string]ruleRef
+
+	listConntrack func(netlink.ConntrackTableType, netlink.InetFamily) ([]*netlink.ConntrackFlow, error)
+	// listKTLSSockets is only set if the kTLS accounting is enabled.
+	listKTLSSockets func(family uint8) ([]ktls.Socket, error)
+	timeNow         func() time.Time
+}
+
+func newFlowLogManager(
+	exporter flowlog.Exporter,
+	config flowlog.Config,
+	flushInterval time.Duration,
+	nflogGroup int,
+	ipv6Enabled bool,
+	ktlsAccounting bool,
+	verdictMetrics *policyVerdictMetrics,
+) *flowLogManager {
+	m := &flowLogManager{
+		exporter:       exporter,
+		reader:         nflog.NewReader(nflogGroup, flowLogNFLOGCopyRange),
+		flushInterval:  flushInterval,
+		families:       []netlink.InetFamily{netlink.FAMILY_V4},
+		verdictMetrics: verdictMetrics,
+		endpointIPs:    map[proto.WorkloadEndpointID][]netip.Addr{},
+		ipToEndpoint:   map[netip.Addr]proto.WorkloadEndpointID{},
+		policyRules:    map[proto.PolicyID][]string{},
+		profileRules:   map[proto.ProfileID][]string{},
+		rules:          map[string]ruleRef{},
+		listConntrack:  netlink.ConntrackTableList,
+		timeNow:        time.Now,
+	}
+	if ipv6Enabled {
+		m.families = append(m.families, netlink.FAMILY_V6)
+	}
+	if ktlsAccounting {
+		m.listKTLSSockets = ktls.ListSockets
+	}
+	if exporter != nil {
+		m.aggregator = flowlog.NewAggregator(config, m, m.timeNow())
+	}
+	return m
+}
+
+func (m *flowLogManager) OnUpdate(msg interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch msg := msg.(type) {
+	case *proto.WorkloadEndpointUpdate:
+		m.removeEndpointLocked(*msg.Id)
+		var addrs []netip.Addr
+		for _, nets := range [][]string{msg.Endpoint.Ipv4Nets, msg.Endpoint.Ipv6Nets} {
+			for _, n := range nets {
+				s, _, _ := strings.Cut(n, "/")
+				addr, err := netip.ParseAddr(s)
+				if err != nil {
+					continue
+				}
+				addrs = append(addrs, addr)
+				m.ipToEndpoint[addr] = *msg.Id
+			}
+		}
+		m.endpointIPs[*msg.Id] = addrs
+	case *proto.WorkloadEndpointRemove:
+		m.removeEndpointLocked(*msg.Id)
+		if m.verdictMetrics != nil {
+			m.verdictMetrics.removeEndpoint(msg.Id.WorkloadId)
+		}
+	case *proto.ActivePolicyUpdate:
+		m.removeRulesLocked(m.policyRules[*msg.Id])
+		m.policyRules[*msg.Id] = m.addRulesLocked(msg.Id.Tier, msg.Id.Name,
+			msg.Policy.InboundRules, msg.Policy.OutboundRules)
+	case *proto.ActivePolicyRemove:
+		m.removeRulesLocked(m.policyRules[*msg.Id])
+		delete(m.policyRules, *msg.Id)
+	case *proto.ActiveProfileUpdate:
+		m.removeRulesLocked(m.profileRules[*msg.Id])
+		m.profileRules[*msg.Id] = m.addRulesLocked(flowLogProfileTier, msg.Id.Name,
+			msg.Profile.InboundRules, msg.Profile.OutboundRules)
+	case *proto.ActiveProfileRemove:
+		m.removeRulesLocked(m.profileRules[*msg.Id])
+		delete(m.profileRules, *msg.Id)
+	}
+}
+
+func (m *flowLogManager) removeEndpointLocked(id proto.WorkloadEndpointID) {
+	for _, addr := range m.endpointIPs[id] {
+		if m.ipToEndpoint[addr] == id {
+			delete(m.ipToEndpoint, addr)
+		}
+	}
+	delete(m.endpointIPs, id)
+}
+
+func (m *flowLogManager) addRulesLocked(tier, name string, inbound, outbound []*proto.Rule) []string {
+	var ids []string
+	for _, dir := range []struct {
+		name  string
+		rules []*proto.Rule
+	}{{"ingress", inbound}, {"egress", outbound}} {
+		for i, r := range dir.rules {
+			m.rules[r.RuleId] = ruleRef{tier: tier, name: name, direction: dir.name, index: i}
+			ids = append(ids, r.RuleId)
+		}
+	}
+	return ids
+}
+
+func (m *flowLogManager) removeRulesLocked(ids []string) {
+	for _, id := range ids {
+		delete(m.rules, id)
+	}
+}
+
+func (m *flowLogManager) CompleteDeferredWork() error {
+	return nil
+}
+
+// LookupEndpoint implements flowlog.EndpointLookup.
+func (m *flowLogManager) LookupEndpoint(addr netip.Addr) (string, string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	id, ok := m.ipToEndpoint[addr]
+	return id.WorkloadId, id.EndpointId, ok
+}
+
+// Start reads the verdicts and the conntrack table, and exports the flow logs, in the
+// background.
+func (m *flowLogManager) Start() {
+	if m.aggregator == nil {
+		if err := m.reader.Start(m.OnPacket); err != nil {
+			log.WithError(err).Error("Failed to start reading policy verdicts, no policy verdict metrics " +
+				"will be reported.")
+		}
+		return
+	}
+
+	// The conntrack entries only count their packets and bytes with accounting enabled.
+	if err := writeProcSys("/proc/sys/net/netfilter/nf_conntrack_acct", "1"); err != nil {
+		log.WithError(err).Warn("Failed to enable conntrack accounting, flow logs will have no byte counts.")
+	}
+	if err := m.reader.Start(m.OnPacket); err != nil {
+		log.WithError(err).Error("Failed to start reading policy verdicts, flow logs will only " +
+			"include the connections that are in the conntrack table.")
+	}
+	go m.loop()
+}
+
+func (m *flowLogManager) loop() {
+	pollTicker := time.NewTicker(flowLogConntrackPollInterval)
+	flushTicker := time.NewTicker(m.flushInterval)
+	for {
+		select {
+		case <-pollTicker.C:
+			m.pollConntrack()
+		case <-flushTicker.C:
+			m.flush()
+		}
+	}
+}
+
+// OnPacket is called on the NFLOG goroutine with each packet whose verdict a policy rule
+// reported.
+func (m *flowLogManager) OnPacket(p nflog.Packet) {
+	action, ruleID, err := flowlog.ParseNFLOGPrefix(p.Prefix)
+	if err != nil {
+		log.WithError(err).Debug("Ignoring flow log packet.")
+		return
+	}
+	pkt, err := policylog.ParsePacket(p.Payload)
+	if err != nil {
+		log.WithError(err).Debug("Failed to parse flow log packet.")
+		return
+	}
+
+	t := flowlog.Tuple{
+		Proto:   pkt.Protocol,
+		Src:     toAddr(pkt.Src),
+		Dst:     toAddr(pkt.Dst),
+		SrcPort: pkt.SrcPort,
+		DstPort: pkt.DstPort,
+	}
+
+	policy := flowlog.DefaultDenyPolicy
+	ref, known := ruleRef{name: flowlog.DefaultDenyPolicy}, true
+	if ruleID != "" {
+		m.lock.Lock()
+		ref, known = m.rules[ruleID]
+		m.lock.Unlock()
+		if !known {
+			// The policy was updated since the packet was logged.
+			log.WithField("ruleID", ruleID).Debug("Verdict of an unknown rule.")
+			policy = ""
+		} else {
+			policy = flowlog.PolicyHit(ref.tier, ref.name, ref.direction, ref.index, action)
+		}
+	}
+
+	if m.verdictMetrics != nil && known {
+		m.verdictMetrics.recordVerdict(ref.tier, ref.name, action, m.policyWorkload(t, ref.direction))
+	}
+	if m.aggregator != nil {
+		m.aggregator.Verdict(t, action, policy, pkt.Length)
+	}
+}
+
+// policyWorkload returns the ID of the workload whose policy reported a verdict: the
+// destination of the ingress policies and the source of the egress policies.  The direction of
+// the default deny is unknown, we take the destination if it is local.
+func (m *flowLogManager) policyWorkload(t flowlog.Tuple, direction string) string {
+	if direction != "egress" {
+		if workload, _, ok := m.LookupEndpoint(t.Dst); ok || direction == "ingress" {
+			return workload
+		}
+	}
+	workload, _, _ := m.LookupEndpoint(t.Src)
+	return workload
+}
+
+func (m *flowLogManager) pollConntrack() {
+	var entries []flowlog.ConntrackEntry
+	for _, family := range m.families {
+		flows, err := m.listConntrack(netlink.ConntrackTable, family)
+		if err != nil {
+			// Without a complete dump, connections would be taken to have ended.
+			log.WithError(err).Warn("Failed to read the conntrack table for flow logs.")
+			return
+		}
+		sockets := m.ktlsSockets(family)
+		for _, f := range flows {
+			// The source of the reply is the destination after DNAT, which is what policy
+			// saw.
+			e := flowlog.ConntrackEntry{
+				Tuple: flowlog.Tuple{
+					Proto:   f.Forward.Protocol,
+					Src:     toAddr(f.Forward.SrcIP),
+					Dst:     toAddr(f.Reverse.SrcIP),
+					SrcPort: f.Forward.SrcPort,
+					DstPort: f.Reverse.SrcPort,
+				},
+				Source: flowlog.Counters{Packets: f.Forward.Packets, Bytes: f.Forward.Bytes},
+				Dest:   flowlog.Counters{Packets: f.Reverse.Packets, Bytes: f.Reverse.Bytes},
+			}
+			if len(sockets) > 0 && f.Forward.Protocol == unix.IPPROTO_TCP {
+				addKTLSCounters(&e, f, sockets)
+			}
+			entries = append(entries, e)
+		}
+	}
+	m.aggregator.UpdateConntrack(entries)
+}
+
+type socketAddrs struct {
+	local, remote netip.AddrPort
+}
+
+// ktlsSockets returns the sockets of the host that use kTLS, by their addresses.  The sockets
+// in the network namespaces of the workloads are not visible, so this only covers connections
+// that are terminated on the host, such as by a TLS proxy in the host network.
+func (m *flowLogManager) ktlsSockets(family netlink.InetFamily) map[socketAddrs]ktls.Socket {
+	if m.listKTLSSockets == nil {
+		return nil
+	}
+	sockets, err := m.listKTLSSockets(uint8(family))
+	if err != nil {
+		log.WithError(err).Warn("Failed to list the kTLS sockets for flow logs.")
+		return nil
+	}
+	byAddrs := map[socketAddrs]ktls.Socket{}
+	for _, s := range sockets {
+		if !s.Encrypted() {
+			continue
+		}
+		byAddrs[socketAddrs{local: s.Local, remote: s.Remote}] = s
+	}
+	return byAddrs
+}
+
+// addKTLSCounters marks the conntrack entry as using kTLS if either end of the connection is a
+// kTLS socket of the host.  A NIC that runs the TCP connection of the socket bypasses conntrack,
+// so the counters of the socket are used where they are higher.
+func addKTLSCounters(e *flowlog.ConntrackEntry, f *netlink.ConntrackFlow, sockets map[socketAddrs]ktls.Socket) {
+	// The client of the connection sees the original tuple and the server the reply tuple.
+	orig := socketAddrs{
+		local:  netip.AddrPortFrom(toAddr(f.Forward.SrcIP), f.Forward.SrcPort),
+		remote: netip.AddrPortFrom(toAddr(f.Forward.DstIP), f.Forward.DstPort),
+	}
+	reply := socketAddrs{
+		local:  netip.AddrPortFrom(toAddr(f.Reverse.SrcIP), f.Reverse.SrcPort),
+		remote: netip.AddrPortFrom(toAddr(f.Reverse.DstIP), f.Reverse.DstPort),
+	}
+	var sent, received *flowlog.Counters
+	s, ok := sockets[orig]
+	if ok {
+		sent, received = &e.Source, &e.Dest
+	} else if s, ok = sockets[reply]; ok {
+		sent, received = &e.Dest, &e.Source
+	} else {
+		return
+	}
+	e.KTLS = true
+	sent.Packets = max(sent.Packets, s.SegmentsSent)
+	sent.Bytes = max(sent.Bytes, s.BytesSent)
+	received.Packets = max(received.Packets, s.SegmentsReceived)
+	received.Bytes = max(received.Bytes, s.BytesReceived)
+}
+
+func (m *flowLogManager) flush() {
+	records := m.aggregator.Flush(m.timeNow())
+	if len(records) == 0 {
+		return
+	}
+	if err := m.exporter.Export(records); err != nil {
+		log.WithError(err).WithField("count", len(records)).Warn("Failed to export flow logs.")
+	}
+}
+
+func toAddr(ip net.IP) netip.Addr {
+	addr, _ := netip.AddrFromSlice(ip)
+	return addr.Unmap()
+}
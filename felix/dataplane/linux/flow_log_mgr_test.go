@@ -0,0 +1,241 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+
+	"github.com/projectcalico/calico/felix/flowlog"
+	"github.com/projectcalico/calico/felix/ktls"
+	"github.com/projectcalico/calico/felix/nflog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+type recordingFlowLogExporter struct {
+	records []flowlog.Record
+}
+
+func (r *recordingFlowLogExporter) Export(records []flowlog.Record) error {
+	r.records = append(r.records, records...)
+	return nil
+}
+
+func (r *recordingFlowLogExporter) Close() {}
+
+func flowLogTCPPacket(src, dst string, srcPort, dstPort uint16) []byte {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    net.ParseIP(src),
+		DstIP:    net.ParseIP(dst),
+	}
+	tcp := &layers.TCP{SrcPort: layers.TCPPort(srcPort), DstPort: layers.TCPPort(dstPort), SYN: true}
+	_ = tcp.SetNetworkLayerForChecksum(ip)
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true},
+		ip, tcp)
+	Expect(err).NotTo(HaveOccurred())
+	return buf.Bytes()
+}
+
+var _ = Describe("flowLogManager", func() {
+	var (
+		exporter *recordingFlowLogExporter
+		mgr      *flowLogManager
+		flows    []*netlink.ConntrackFlow
+	)
+
+	BeforeEach(func() {
+		exporter = &recordingFlowLogExporter{}
+		mgr = newFlowLogManager(exporter, flowlog.Config{Aggregation: flowlog.AggregationSourcePort, SampleRate: 1},
+			time.Minute, 42, false, false, nil)
+		flows = nil
+		mgr.listConntrack = func(table netlink.ConntrackTableType, family netlink.InetFamily) ([]*netlink.ConntrackFlow, error) {
+			Expect(family).To(Equal(netlink.InetFamily(netlink.FAMILY_V4)))
+			return flows, nil
+		}
+
+		mgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+			Id: &proto.WorkloadEndpointID{
+				OrchestratorId: "k8s",
+				WorkloadId:     "default/server",
+				EndpointId:     "eth0",
+			},
+			Endpoint: &proto.WorkloadEndpoint{Ipv4Nets: []string{"10.65.0.3/32"}},
+		})
+		mgr.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &proto.PolicyID{Tier: "default", Name: "default.web"},
+			Policy: &proto.Policy{
+				InboundRules: []*proto.Rule{
+					{Action: "deny", RuleId: "rule-deny"},
+					{Action: "allow", RuleId: "rule-allow"},
+				},
+			},
+		})
+	})
+
+	It("should log the verdicts with their policy rules and the conntrack counters", func() {
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionAllow, "rule-allow"),
+			Payload: flowLogTCPPacket("10.65.1.2", "10.65.0.3", 40000, 80),
+		})
+		// The conntrack entry of a connection to a service, the reply comes from the
+		// backend.
+		flows = []*netlink.ConntrackFlow{{FamilyType: netlink.FAMILY_V4}}
+		flows[0].Forward.Protocol = 6
+		flows[0].Forward.SrcIP = net.ParseIP("10.65.1.2")
+		flows[0].Forward.SrcPort = 40000
+		flows[0].Forward.DstIP = net.ParseIP("10.96.0.80")
+		flows[0].Forward.DstPort = 80
+		flows[0].Forward.Packets = 5
+		flows[0].Forward.Bytes = 400
+		flows[0].Reverse.Protocol = 6
+		flows[0].Reverse.SrcIP = net.ParseIP("10.65.0.3")
+		flows[0].Reverse.SrcPort = 80
+		flows[0].Reverse.DstIP = net.ParseIP("10.65.1.2")
+		flows[0].Reverse.DstPort = 40000
+		flows[0].Reverse.Packets = 4
+		flows[0].Reverse.Bytes = 3000
+		mgr.pollConntrack()
+		mgr.flush()
+
+		Expect(exporter.records).To(HaveLen(1))
+		r := exporter.records[0]
+		Expect(r.Source).To(Equal(flowlog.Endpoint{Type: flowlog.EndpointTypeNetwork, IP: "10.65.1.2"}))
+		Expect(r.Dest).To(Equal(flowlog.Endpoint{
+			Type:     flowlog.EndpointTypeWorkload,
+			IP:       "10.65.0.3",
+			Port:     80,
+			Workload: "default/server",
+			Endpoint: "eth0",
+		}))
+		Expect(r.Action).To(Equal(flowlog.ActionAllow))
+		Expect(r.Policies).To(Equal([]string{"default|default.web|ingress|1|allow"}))
+		Expect(r.SourcePackets).To(BeNumerically("==", 5))
+		Expect(r.DestBytes).To(BeNumerically("==", 3000))
+	})
+
+	It("should count the connections of kTLS sockets of the host from the sockets", func() {
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionAllow, "rule-allow"),
+			Payload: flowLogTCPPacket("10.0.0.1", "10.65.0.3", 50000, 443),
+		})
+		// A TLS proxy on the host connects to a service, the NIC runs the connection after the
+		// handshake so conntrack only counts the handshake.
+		flows = []*netlink.ConntrackFlow{{FamilyType: netlink.FAMILY_V4}}
+		flows[0].Forward.Protocol = 6
+		flows[0].Forward.SrcIP = net.ParseIP("10.0.0.1")
+		flows[0].Forward.SrcPort = 50000
+		flows[0].Forward.DstIP = net.ParseIP("10.96.0.80")
+		flows[0].Forward.DstPort = 443
+		flows[0].Forward.Packets = 4
+		flows[0].Forward.Bytes = 600
+		flows[0].Reverse.Protocol = 6
+		flows[0].Reverse.SrcIP = net.ParseIP("10.65.0.3")
+		flows[0].Reverse.SrcPort = 443
+		flows[0].Reverse.DstIP = net.ParseIP("10.0.0.1")
+		flows[0].Reverse.DstPort = 50000
+		flows[0].Reverse.Packets = 3
+		flows[0].Reverse.Bytes = 4000
+		mgr.listKTLSSockets = func(family uint8) ([]ktls.Socket, error) {
+			return []ktls.Socket{
+				{
+					Local:            netip.MustParseAddrPort("10.0.0.1:50000"),
+					Remote:           netip.MustParseAddrPort("10.96.0.80:443"),
+					TX:               ktls.ModeHardwareRecord,
+					RX:               ktls.ModeHardwareRecord,
+					SegmentsSent:     10,
+					SegmentsReceived: 200,
+					BytesSent:        500,
+					BytesReceived:    250000,
+				},
+				{
+					// A socket with the TLS ULP but without keys.
+					Local:  netip.MustParseAddrPort("10.0.0.1:50001"),
+					Remote: netip.MustParseAddrPort("10.96.0.80:443"),
+					TX:     ktls.ModeBase,
+					RX:     ktls.ModeBase,
+				},
+			}, nil
+		}
+		mgr.pollConntrack()
+		mgr.flush()
+
+		Expect(exporter.records).To(HaveLen(1))
+		r := exporter.records[0]
+		Expect(r.KTLS).To(BeTrue())
+		Expect(r.SourcePackets).To(BeNumerically("==", 10))
+		Expect(r.SourceBytes).To(BeNumerically("==", 600))
+		Expect(r.DestPackets).To(BeNumerically("==", 200))
+		Expect(r.DestBytes).To(BeNumerically("==", 250000))
+	})
+
+	It("should log the default deny", func() {
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionDeny, ""),
+			Payload: flowLogTCPPacket("10.65.1.2", "10.65.0.3", 40000, 22),
+		})
+		mgr.flush()
+
+		Expect(exporter.records).To(HaveLen(1))
+		Expect(exporter.records[0].Action).To(Equal(flowlog.ActionDeny))
+		Expect(exporter.records[0].Policies).To(Equal([]string{flowlog.DefaultDenyPolicy}))
+		Expect(exporter.records[0].SourcePackets).To(BeNumerically("==", 1))
+	})
+
+	It("should forget the rules of removed policies and the removed endpoints", func() {
+		mgr.OnUpdate(&proto.ActivePolicyRemove{Id: &proto.PolicyID{Tier: "default", Name: "default.web"}})
+		mgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: &proto.WorkloadEndpointID{
+			OrchestratorId: "k8s",
+			WorkloadId:     "default/server",
+			EndpointId:     "eth0",
+		}})
+		Expect(mgr.rules).To(BeEmpty())
+		Expect(mgr.ipToEndpoint).To(BeEmpty())
+
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionDeny, "rule-deny"),
+			Payload: flowLogTCPPacket("10.65.1.2", "10.65.0.3", 40000, 22),
+		})
+		mgr.flush()
+		Expect(exporter.records).To(HaveLen(1))
+		Expect(exporter.records[0].Policies).To(BeEmpty())
+		Expect(exporter.records[0].Dest.Type).To(Equal(flowlog.EndpointTypeNetwork))
+	})
+
+	It("should map the rules of profiles", func() {
+		mgr.OnUpdate(&proto.ActiveProfileUpdate{
+			Id: &proto.ProfileID{Name: "kns.default"},
+			Profile: &proto.Profile{
+				OutboundRules: []*proto.Rule{{Action: "allow", RuleId: "rule-profile"}},
+			},
+		})
+		Expect(mgr.rules).To(HaveKeyWithValue("rule-profile", ruleRef{
+			tier:      flowLogProfileTier,
+			name:      "kns.default",
+			direction: "egress",
+			index:     0,
+		}))
+	})
+})
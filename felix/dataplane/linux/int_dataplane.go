@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
@@ -33,6 +34,7 @@ import (
 	"golang.org/x/sys/unix"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/proxy/healthcheck"
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
@@ -45,23 +47,33 @@ import (
 	bpfifstate "github.com/projectcalico/calico/felix/bpf/ifstate"
 	bpfipsets "github.com/projectcalico/calico/felix/bpf/ipsets"
 	bpfmaps "github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/membudget"
 	"github.com/projectcalico/calico/felix/bpf/nat"
 	bpfnat "github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/nat64"
 	bpfproxy "github.com/projectcalico/calico/felix/bpf/proxy"
 	bpfroutes "github.com/projectcalico/calico/felix/bpf/routes"
 	"github.com/projectcalico/calico/felix/bpf/tc"
 	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/config"
 	"github.com/projectcalico/calico/felix/dataplane/common"
+	"github.com/projectcalico/calico/felix/dataplane/hooks"
+	"github.com/projectcalico/calico/felix/dnscache"
+	"github.com/projectcalico/calico/felix/dnslog"
 	"github.com/projectcalico/calico/felix/environment"
+	"github.com/projectcalico/calico/felix/flowlog"
 	"github.com/projectcalico/calico/felix/idalloc"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ipsets"
 	"github.com/projectcalico/calico/felix/iptables"
 	"github.com/projectcalico/calico/felix/iptables/cmdshim"
 	"github.com/projectcalico/calico/felix/jitter"
+	"github.com/projectcalico/calico/felix/l7verdict"
 	"github.com/projectcalico/calico/felix/labelindex"
 	"github.com/projectcalico/calico/felix/logutils"
+	"github.com/projectcalico/calico/felix/netlinkshim"
+	"github.com/projectcalico/calico/felix/nflog"
+	"github.com/projectcalico/calico/felix/policylog"
 	"github.com/projectcalico/calico/felix/proto"
 	"github.com/projectcalico/calico/felix/routerule"
 	"github.com/projectcalico/calico/felix/routetable"
@@ -86,6 +98,10 @@ const (
 
 	// Route cleanup grace period. Used for workload routes only.
 	routeCleanupGracePeriod = 10 * time.Second
+
+	// dnsSnoopCopyRange is the number of bytes of each snooped DNS response that the kernel
+	// passes to Felix, enough for responses that use EDNS0 to exceed 512 bytes.
+	dnsSnoopCopyRange = 4096
 )
 
 var (
@@ -163,6 +179,10 @@ type Config struct {
 
 	FloatingIPsEnabled bool
 
+	// VirtualInterfaceHostEndpointsEnabled makes macvlan and ipvlan interfaces inherit the host
+	// endpoint of their parent and, in BPF mode, get programs if their parent is a data interface.
+	VirtualInterfaceHostEndpointsEnabled bool
+
 	Wireguard wireguard.Config
 
 	NetlinkTimeout time.Duration
@@ -181,13 +201,49 @@ type Config struct {
 	WatchdogTimeout    time.Duration
 	RouteTableManager  *idalloc.IndexAllocator
 
+	DataplaneApplyPreHook     string
+	DataplaneApplyPostHook    string
+	DataplaneApplyHookTimeout time.Duration
+
 	DebugSimulateDataplaneHangAfter  time.Duration
 	DebugSimulateDataplaneApplyDelay time.Duration
 
 	ExternalNodesCidrs []string
 
+	// DNSLogExporter, if set, receives the logs of the DNS responses that are snooped for
+	// domain name policy.
+	DNSLogExporter dnslog.Exporter
+
+	// FlowLogExporter, if set, receives the flow logs of the local workloads, which are
+	// aggregated according to FlowLogConfig and exported every FlowLogsFlushInterval.  The
+	// policy rules report their verdicts to RulesConfig.FlowLogsNFLOGGroup.
+	FlowLogExporter       flowlog.Exporter
+	FlowLogConfig         flowlog.Config
+	FlowLogsFlushInterval time.Duration
+	// FlowLogsKTLSAccountingEnabled marks the connections of the kTLS sockets of the host in the
+	// flow logs and counts them from the sockets where conntrack undercounts them.
+	FlowLogsKTLSAccountingEnabled bool
+
+	// PolicyVerdictMetricsEnabled enables the connection counts per policy and workload
+	// endpoint, which are read from the same verdicts as the flow logs.
+	PolicyVerdictMetricsEnabled   bool
+	PolicyVerdictMetricsMaxSeries int
+
+	// L7ProxyTableIndex is the routing table that delivers the connections marked with
+	// RulesConfig.L7ProxyMark to the L7 proxy, whose verdicts are read from
+	// L7ProxyVerdictSocketPath.
+	L7ProxyTableIndex        int
+	L7ProxyVerdictSocketPath string
+
+	// MTUFilePath is where the pod MTU is written for the CNI plugin, defaults to
+	// /var/lib/calico/mtu.
+	MTUFilePath string
+
 	BPFEnabled                         bool
 	BPFPolicyDebugEnabled              bool
+	PolicyMetricsEnabled               bool
+	PolicyLogFilePath                  string
+	BPFPolicyBitmapsEnabled            bool
 	BPFDisableUnprivileged             bool
 	BPFKubeProxyIptablesCleanupEnabled bool
 	BPFLogLevel                        string
@@ -195,6 +251,9 @@ type Config struct {
 	BPFCTLBLogFilter                   string
 	BPFExtToServiceConnmark            int
 	BPFDataIfacePattern                *regexp.Regexp
+	BPFAttachIncludeIfaces             []*regexp.Regexp
+	BPFAttachExcludeIfaces             []*regexp.Regexp
+	BPFHardwareOffloadIfaces           []*regexp.Regexp
 	BPFL3IfacePattern                  *regexp.Regexp
 	XDPEnabled                         bool
 	XDPAllowGeneric                    bool
@@ -206,20 +265,33 @@ type Config struct {
 	BPFMapRepin                        bool
 	BPFNodePortDSREnabled              bool
 	BPFDSROptoutCIDRs                  []string
+	BPFNodePortLinkLocalEnabled        bool
 	BPFPSNATPorts                      numorstring.Port
 	BPFMapSizeRoute                    int
 	BPFMapSizeConntrack                int
+	BPFConntrackShards                 int
 	BPFMapSizeNATFrontend              int
 	BPFMapSizeNATBackend               int
 	BPFMapSizeNATAffinity              int
 	BPFMapSizeIPSets                   int
 	BPFMapSizeIfState                  int
+	BPFMemoryBudgetMB                  int
+	BPFMapShrinkPolicy                 string
 	BPFIpv6Enabled                     bool
 	BPFHostConntrackBypass             bool
 	BPFEnforceRPF                      string
+	BPFAttachType                      string
+	BPFFragmentHandling                string
+	BPFNAT64Enabled                    bool
+	BPFNAT64Prefix                     string
+	BPFRoutesMirrorTableIndex          int
 	BPFDisableGROForIfaces             *regexp.Regexp
 	BPFExcludeCIDRsFromNAT             []string
+	BPFNodePortAddresses               []string
+	BPFServiceLimits                   bpfproxy.ServiceLimits
 	KubeProxyMinSyncPeriod             time.Duration
+	KubeProxyHealthzBindAddress        string
+	KubeProxyMetricsBindAddress        string
 	SidecarAccelerationEnabled         bool
 	ServiceLoopPrevention              string
 
@@ -278,6 +350,7 @@ type UpdateBatchResolver interface {
 type InternalDataplane struct {
 	toDataplane             chan interface{}
 	fromDataplane           chan interface{}
+	cleanUpC                chan chan struct{}
 	sendDataplaneInSyncOnce sync.Once
 
 	allIptablesTables    []*iptables.Table
@@ -335,6 +408,29 @@ type InternalDataplane struct {
 	reschedTimer *time.Timer
 	reschedC     <-chan time.Time
 
+	// kubeProxyHealthz is the kube-proxy compatible health server shared by the
+	// BPF kube-proxy replacements of both IP families, if enabled.
+	kubeProxyHealthz healthcheck.ProxierHealthUpdater
+
+	// policyLogWriter writes the packets logged by the policy Log rules that Felix logs itself,
+	// in iptables mode they are received by nflogReader.
+	policyLogWriter *policylog.Writer
+	nflogReader     *policylog.NFLOGReader
+
+	// dnsCache holds the addresses of the domain names in the DNS responses that dnsSnooper
+	// receives from the iptables DNS snooping rules.  It resolves the domain IP sets.
+	dnsCache      *dnscache.Cache
+	dnsSnooper    *nflog.Reader
+	dnsLogManager *dnsLogManager
+
+	// flowLogManager produces the flow logs, it is only set when they are enabled in iptables
+	// mode.
+	flowLogManager *flowLogManager
+
+	// l7VerdictListener receives the verdicts of the L7 proxy, it is only set when the L7
+	// proxy is enabled.
+	l7VerdictListener *l7verdict.Listener
+
 	applyThrottle *throttle.Throttle
 
 	config Config
@@ -349,6 +445,9 @@ type InternalDataplane struct {
 
 	loopSummarizer *logutils.Summarizer
 
+	// applyHooks, if set, runs the configured hooks around each apply().
+	applyHooks *hooks.Runner
+
 	// Fields used to accumulate counts of messages of various types before we report them to
 	// prometheus.
 	datastoreBatchSize   int
@@ -360,6 +459,8 @@ const (
 	healthName     = "InternalDataplaneMainLoop"
 	healthInterval = 10 * time.Second
 
+	memoryBudgetCheckInterval = 30 * time.Second
+
 	ipipMTUOverhead        = 20
 	vxlanMTUOverhead       = 50
 	vxlanV6MTUOverhead     = 70
@@ -386,7 +487,7 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 	}
 	ConfigureDefaultMTUs(hostMTU, &config)
 	podMTU := determinePodMTU(config)
-	if err := writeMTUFile(podMTU); err != nil {
+	if err := writeMTUFile(config.MTUFilePath, podMTU); err != nil {
 		log.WithError(err).Error("Failed to write MTU file, pod MTU may not be properly set")
 	}
 
@@ -397,6 +498,7 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 	dp := &InternalDataplane{
 		toDataplane:    make(chan interface{}, msgPeekLimit),
 		fromDataplane:  make(chan interface{}, 100),
+		cleanUpC:       make(chan chan struct{}),
 		ruleRenderer:   ruleRenderer,
 		ifaceMonitor:   ifacemonitor.New(config.IfaceMonitorConfig, featureDetector, config.FatalErrorRestartCallback),
 		ifaceUpdates:   make(chan any, 100),
@@ -404,7 +506,15 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		applyThrottle:  throttle.New(10),
 		loopSummarizer: logutils.NewSummarizer("dataplane reconciliation loops"),
 	}
+	dp.policyLogWriter = policylog.NewWriter(config.PolicyLogFilePath)
 	dp.applyThrottle.Refill() // Allow the first apply() immediately.
+
+	applyHooks, err := hooks.NewRunner(config.DataplaneApplyPreHook, config.DataplaneApplyPostHook,
+		config.DataplaneApplyHookTimeout, config.Hostname)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid dataplane apply hook, shutting down")
+	}
+	dp.applyHooks = applyHooks
 	dp.ifaceMonitor.StateCallback = dp.onIfaceStateChange
 	dp.ifaceMonitor.AddrCallback = dp.onIfaceAddrsChange
 	dp.ifaceMonitor.InSyncCallback = dp.onIfaceInSync
@@ -433,7 +543,7 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 	}
 
 	if config.BPFEnabled && !config.BPFPolicyDebugEnabled {
-		err := os.RemoveAll(bpf.RuntimePolDir)
+		err := os.RemoveAll(bpf.RuntimePolDir())
 		if err != nil && !os.IsNotExist(err) {
 			log.WithError(err).Info("Policy debug disabled but failed to remove the debug directory.  Ignoring.")
 		}
@@ -617,6 +727,9 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 
 	dp.RegisterManager(ipsetsManager)
 
+	// Readers of the policy rule counters, for the dataplanes in use.
+	var ruleCounters []ruleCountersReader
+
 	if !config.BPFEnabled {
 		// BPF mode disabled, create the iptables-only managers.
 		dp.ipsetsSourceV4 = ipsetsManager
@@ -627,6 +740,39 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 			ipSetsV4,
 			config.MaxIPSetSize))
 		dp.RegisterManager(newPolicyManager(rawTableV4, mangleTableV4, filterTableV4, ruleRenderer, 4))
+		if config.PolicyMetricsEnabled {
+			ruleCounters = append(ruleCounters, &iptablesRuleCounters{
+				table:        filterTableV4,
+				ruleRenderer: ruleRenderer,
+				ipVersion:    4,
+			})
+		}
+		dp.nflogReader = policylog.NewNFLOGReader(config.RulesConfig.PolicyLogNFLOGGroup, dp.policyLogWriter)
+		if len(config.RulesConfig.DNSTrustedServers) > 0 {
+			dp.dnsCache = dnscache.New()
+			dp.dnsSnooper = nflog.NewReader(config.RulesConfig.DNSPolicyNFLOGGroup, dnsSnoopCopyRange)
+			ipsetsManager.SetDomainResolver(dp.dnsCache)
+			ipsetsManagerV6.SetDomainResolver(dp.dnsCache)
+			if config.DNSLogExporter != nil {
+				dp.dnsLogManager = newDNSLogManager(dnslog.NewLogger(config.DNSLogExporter))
+				dp.RegisterManager(dp.dnsLogManager)
+			}
+		} else if config.DNSLogExporter != nil {
+			log.Warn("DNS logs are enabled but DNSTrustedServers is empty, no DNS responses will be logged.")
+		}
+		var verdictMetrics *policyVerdictMetrics
+		if config.PolicyVerdictMetricsEnabled {
+			verdictMetrics = newPolicyVerdictMetrics(config.PolicyVerdictMetricsMaxSeries)
+			if err := prometheus.Register(verdictMetrics); err != nil {
+				log.WithError(err).Warn("Failed to register policy verdict metrics.")
+			}
+		}
+		if config.FlowLogExporter != nil || verdictMetrics != nil {
+			dp.flowLogManager = newFlowLogManager(config.FlowLogExporter, config.FlowLogConfig,
+				config.FlowLogsFlushInterval, config.RulesConfig.FlowLogsNFLOGGroup, config.IPv6Enabled,
+				config.FlowLogsKTLSAccountingEnabled, verdictMetrics)
+			dp.RegisterManager(dp.flowLogManager)
+		}
 
 		// Clean up any leftover BPF state.
 		err := bpfnat.RemoveConnectTimeLoadBalancer("")
@@ -657,9 +803,16 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		bpfmaps.DisableRepin()
 	}
 
+	if config.BPFMapShrinkPolicy == "Truncate" {
+		bpfmaps.SetShrinkPolicy(bpfmaps.ShrinkTruncate)
+	} else {
+		bpfmaps.SetShrinkPolicy(bpfmaps.ShrinkRefuse)
+	}
+
 	bpfipsets.SetMapSize(config.BPFMapSizeIPSets)
 	bpfnat.SetMapSizes(config.BPFMapSizeNATFrontend, config.BPFMapSizeNATBackend, config.BPFMapSizeNATAffinity)
 	bpfroutes.SetMapSize(config.BPFMapSizeRoute)
+	bpfconntrack.SetShards(config.BPFConntrackShards)
 	bpfconntrack.SetMapSize(config.BPFMapSizeConntrack)
 	bpfifstate.SetMapSize(config.BPFMapSizeIfState)
 
@@ -670,6 +823,15 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 	if config.BPFEnabled {
 		log.Info("BPF enabled, starting BPF endpoint manager and map manager.")
 
+		if config.BPFMemoryBudgetMB > 0 {
+			// Shrink the maps, if needed, before they are created.
+			budgetStatus, err := membudget.Fit(uint64(config.BPFMemoryBudgetMB)<<20, config.BPFIpv6Enabled)
+			if err != nil {
+				log.WithError(err).Panic("BPF maps don't fit in the memory budget, refusing the configuration")
+			}
+			go budgetStatus.Monitor(memoryBudgetCheckInterval)
+		}
+
 		bpfMaps, err := bpfmap.CreateBPFMaps(config.BPFIpv6Enabled)
 		if err != nil {
 			log.WithError(err).Panic("error creating bpf maps")
@@ -681,12 +843,67 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		var ipSetIDAllocatorV4, ipSetIDAllocatorV6 *idalloc.IDAllocator
 		ipSetIDAllocatorV4 = idalloc.New()
 
+		if config.KubeClientSet != nil {
+			if config.KubeProxyHealthzBindAddress != "" {
+				dp.kubeProxyHealthz = bpfproxy.StartHealthzServer(config.KubeProxyHealthzBindAddress, config.Hostname)
+			}
+			if config.KubeProxyMetricsBindAddress != "" {
+				bpfproxy.StartMetricsServer(config.KubeProxyMetricsBindAddress)
+			}
+		}
+
+		// The routes mirror table is only there for operators to inspect the BPF routes, it owns
+		// the local workload interfaces and the routes without an interface in its table.
+		newRoutesMirrorTable := func(ipVersion uint8) routetable.RouteTableInterface {
+			if config.BPFRoutesMirrorTableIndex == 0 || config.RouteSyncDisabled {
+				return nil
+			}
+			regexes := append([]string{routetable.InterfaceNone}, interfaceRegexes...)
+			return routetable.New(regexes, ipVersion, config.NetlinkTimeout, nil, config.DeviceRouteProtocol,
+				true, config.BPFRoutesMirrorTableIndex, dp.loopSummarizer, featureDetector,
+				routetable.WithLivenessCB(dp.reportHealth))
+		}
+
 		// Start IPv4 BPF dataplane components
-		startBPFDataplaneComponents(proto.IPVersion_IPV4, bpfMaps.V4, ipSetIDAllocatorV4, config, ipsetsManager, dp)
+		startBPFDataplaneComponents(proto.IPVersion_IPV4, bpfMaps.V4, bpfMaps.CommonMaps, ipSetIDAllocatorV4, config,
+			ipsetsManager, newRoutesMirrorTable(4), dp)
 		if config.BPFIpv6Enabled {
 			// Start IPv6 BPF dataplane components
 			ipSetIDAllocatorV6 = idalloc.New()
-			startBPFDataplaneComponents(proto.IPVersion_IPV6, bpfMaps.V6, ipSetIDAllocatorV6, config, ipsetsManagerV6, dp)
+			startBPFDataplaneComponents(proto.IPVersion_IPV6, bpfMaps.V6, bpfMaps.CommonMaps, ipSetIDAllocatorV6, config,
+				ipsetsManagerV6, newRoutesMirrorTable(6), dp)
+		}
+
+		if !config.RouteSyncDisabled {
+			ipVersions := []uint8{4}
+			if config.BPFIpv6Enabled {
+				ipVersions = append(ipVersions, 6)
+			}
+			for _, ipVersion := range ipVersions {
+				l7ProxyRouteRules, err := routerule.New(
+					int(ipVersion),
+					set.From(config.L7ProxyTableIndex),
+					routerule.RulesMatchSrcFWMarkTable,
+					routerule.RulesMatchSrcFWMarkTable,
+					config.NetlinkTimeout,
+					func() (routerule.HandleIface, error) {
+						return netlinkshim.NewRealNetlink()
+					},
+					dp.loopSummarizer,
+				)
+				if err != nil {
+					log.WithError(err).Error("Failed to create L7 proxy routing rules, connections will not reach the L7 proxy.")
+					continue
+				}
+				l7ProxyRouteTable := routetable.New([]string{"^" + l7ProxyLoopbackIface + "$"}, ipVersion,
+					config.NetlinkTimeout, nil, config.DeviceRouteProtocol, true, config.L7ProxyTableIndex,
+					dp.loopSummarizer, featureDetector, routetable.WithLivenessCB(dp.reportHealth))
+				dp.RegisterManager(newL7ProxyManager(ipVersion, config.RulesConfig.L7ProxyMark,
+					config.L7ProxyTableIndex, l7ProxyRouteTable, l7ProxyRouteRules))
+			}
+		}
+		if config.RulesConfig.L7ProxyMark != 0 {
+			dp.l7VerdictListener = l7verdict.NewListener(config.L7ProxyVerdictSocketPath)
 		}
 
 		workloadIfaceRegex := regexp.MustCompile(strings.Join(interfaceRegexes, "|"))
@@ -724,6 +941,15 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		}
 
 		dp.RegisterManager(bpfEndpointManager)
+		dp.RegisterManager(newBPFPolicyLogManager(bpfMaps.CommonMaps.LogRulesMap, bpfMaps.CommonMaps.LogEventsMap,
+			dp.policyLogWriter, config.RulesConfig.IptablesLogPrefix))
+
+		if config.PolicyMetricsEnabled {
+			if !config.BPFPolicyDebugEnabled {
+				log.Warn("Policy metrics are enabled but BPFPolicyDebugEnabled is not set, rules will not be counted.")
+			}
+			ruleCounters = append(ruleCounters, newBPFRuleCounters(bpfMaps.CommonMaps.RuleCountersMap))
+		}
 
 		// HostNetworkedNAT is Enabled and CTLB enabled.
 		// HostNetworkedNAT is Disabled and CTLB is either disabled/TCP.
@@ -929,6 +1155,13 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 				ipSetsV6,
 				config.MaxIPSetSize))
 			dp.RegisterManager(newPolicyManager(rawTableV6, mangleTableV6, filterTableV6, ruleRenderer, 6))
+			if config.PolicyMetricsEnabled {
+				ruleCounters = append(ruleCounters, &iptablesRuleCounters{
+					table:        filterTableV6,
+					ruleRenderer: ruleRenderer,
+					ipVersion:    6,
+				})
+			}
 		} else {
 			dp.RegisterManager(newRawEgressPolicyManager(rawTableV6, ruleRenderer, 6, ipSetsV6.SetFilter))
 		}
@@ -971,6 +1204,14 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		dp.RegisterManager(dp.wireguardManagerV6)
 	}
 
+	if config.PolicyMetricsEnabled {
+		policyStats := newPolicyStatsCollector(ruleCounters...)
+		dp.RegisterManager(policyStats)
+		if err := prometheus.Register(policyStats); err != nil {
+			log.WithError(err).Warn("Failed to register policy metrics.")
+		}
+	}
+
 	dp.allIptablesTables = append(dp.allIptablesTables, dp.iptablesMangleTables...)
 	dp.allIptablesTables = append(dp.allIptablesTables, dp.iptablesNATTables...)
 	dp.allIptablesTables = append(dp.allIptablesTables, dp.iptablesFilterTables...)
@@ -1037,16 +1278,22 @@ func findHostMTU(matchRegex *regexp.Regexp) (int, error) {
 	return smallest, nil
 }
 
+const defaultMTUFilePath = "/var/lib/calico/mtu"
+
 // writeMTUFile writes the smallest MTU among enabled encapsulation types to disk
 // for use by other components (e.g., CNI plugin).
-func writeMTUFile(mtu int) error {
+func writeMTUFile(filename string, mtu int) error {
+	if filename == "" {
+		filename = defaultMTUFilePath
+	}
+
 	// Make sure directory exists.
-	if err := os.MkdirAll("/var/lib/calico", os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create directory /var/lib/calico: %s", err)
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory %s: %s", dir, err)
 	}
 
 	// Write the smallest MTU to disk so other components can rely on this calculation consistently.
-	filename := "/var/lib/calico/mtu"
 	log.Debugf("Writing %d to "+filename, mtu)
 	if err := os.WriteFile(filename, []byte(fmt.Sprintf("%d", mtu)), 0644); err != nil {
 		log.WithError(err).Error("Unable to write to " + filename)
@@ -1293,6 +1540,38 @@ func (d *InternalDataplane) Start() {
 	d.doStaticDataplaneConfig()
 
 	// Then, start the worker threads.
+	if d.nflogReader != nil {
+		if err := d.nflogReader.Start(); err != nil {
+			log.WithError(err).Error("Failed to start reading policy log packets, " +
+				"Log rules with a target other than Kernel will not log.")
+		}
+	}
+	if d.dnsSnooper != nil {
+		d.dnsCache.Start()
+		if d.dnsLogManager != nil {
+			d.dnsLogManager.logger.Start()
+		}
+		err := d.dnsSnooper.Start(func(p nflog.Packet) {
+			d.dnsCache.OnPacket(p.Payload)
+			if d.dnsLogManager != nil {
+				d.dnsLogManager.OnPacket(p.Payload)
+			}
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to start snooping DNS responses, " +
+				"rules that match domain names will not match any traffic.")
+		}
+	}
+	if d.flowLogManager != nil {
+		d.flowLogManager.Start()
+	}
+	if d.l7VerdictListener != nil {
+		if err := d.l7VerdictListener.Start(); err != nil {
+			log.WithError(err).Error("Failed to listen for L7 proxy verdicts, " +
+				"the verdicts will not be reported.")
+		}
+	}
+
 	go d.loopUpdatingDataplane()
 	go d.loopReportingStatus()
 	go d.ifaceMonitor.MonitorInterfaces()
@@ -1309,16 +1588,22 @@ type ifaceInSync struct {
 }
 
 // onIfaceStateChange is our interface monitor callback.  It gets called from the monitor's thread.
-func (d *InternalDataplane) onIfaceStateChange(ifaceName string, state ifacemonitor.State, ifIndex int) {
+func (d *InternalDataplane) onIfaceStateChange(ifaceName string, state ifacemonitor.State, ifIndex int, parentName string) {
 	log.WithFields(log.Fields{
-		"ifaceName": ifaceName,
-		"ifIndex":   ifIndex,
-		"state":     state,
+		"ifaceName":  ifaceName,
+		"ifIndex":    ifIndex,
+		"state":      state,
+		"parentName": parentName,
 	}).Info("Linux interface state changed.")
+	if !d.config.VirtualInterfaceHostEndpointsEnabled {
+		// The managers only treat interfaces with a parent specially if enabled.
+		parentName = ""
+	}
 	d.ifaceUpdates <- &ifaceStateUpdate{
-		Name:  ifaceName,
-		State: state,
-		Index: ifIndex,
+		Name:       ifaceName,
+		State:      state,
+		Index:      ifIndex,
+		ParentName: parentName,
 	}
 }
 
@@ -1326,6 +1611,9 @@ type ifaceStateUpdate struct {
 	Name  string
 	State ifacemonitor.State
 	Index int
+	// ParentName is the parent interface of a macvlan or ipvlan interface, when
+	// VirtualInterfaceHostEndpointsEnabled is set.
+	ParentName string
 }
 
 func NewIfaceStateUpdate(name string, state ifacemonitor.State, index int) any {
@@ -1460,6 +1748,15 @@ func (d *InternalDataplane) setUpIptablesBPF() {
 			},
 		)
 
+		if rulesConfig.L7ProxyMark != 0 {
+			// The connections that the mangle table handed to the L7 proxy.
+			inputRules = append(inputRules, iptables.Rule{
+				Match:   iptables.Match().MarkSingleBitSet(rulesConfig.L7ProxyMark),
+				Comment: []string{"Accept connections to the L7 proxy."},
+				Action:  iptables.AcceptAction{},
+			})
+		}
+
 		// Mark traffic leaving the host that already has an established linux conntrack entry.
 		outputRules = append(outputRules, bpfMarkPreestablishedFlowsRules()...)
 
@@ -1578,17 +1875,27 @@ func (d *InternalDataplane) setUpIptablesBPF() {
 		}})
 	}
 
-	if d.config.BPFExtToServiceConnmark != 0 {
-		mark := uint32(d.config.BPFExtToServiceConnmark)
-		for _, t := range d.iptablesMangleTables {
-			t.InsertOrAppendRules("PREROUTING", []iptables.Rule{{
+	for _, t := range d.iptablesMangleTables {
+		var preroutingRules []iptables.Rule
+		if d.config.BPFExtToServiceConnmark != 0 {
+			mark := uint32(d.config.BPFExtToServiceConnmark)
+			preroutingRules = append(preroutingRules, iptables.Rule{
 				Match: iptables.Match().MarkMatchesWithMask(
 					tcdefs.MarkSeen|mark,
 					tcdefs.MarkSeenMask|mark,
 				),
 				Comment: []string{"Mark connections with ExtToServiceConnmark"},
 				Action:  iptables.SetConnMarkAction{Mark: mark, Mask: mark},
-			}})
+			})
+		}
+		if l7ProxyChains := d.ruleRenderer.StaticBPFModeL7ProxyChains(t.IPVersion); l7ProxyChains != nil {
+			t.UpdateChains(l7ProxyChains)
+			preroutingRules = append(preroutingRules, iptables.Rule{
+				Action: iptables.JumpAction{Target: rules.ChainL7Proxy},
+			})
+		}
+		if len(preroutingRules) > 0 {
+			t.InsertOrAppendRules("PREROUTING", preroutingRules)
 		}
 	}
 }
@@ -1745,6 +2052,51 @@ func (d *InternalDataplane) shutdownXDPCompletely() error {
 	return fmt.Errorf("Failed to wipe the XDP state after %v tries over %v seconds: Error %v", maxTries, waitInterval, err)
 }
 
+// StopAndCleanUp stops updating the dataplane and removes what Felix programmed: the BPF
+// programs and maps, the iptables chains and rules, and the routes.  It returns once the
+// cleanup is done; the dataplane is not updated again.
+func (d *InternalDataplane) StopAndCleanUp() {
+	done := make(chan struct{})
+	d.cleanUpC <- done
+	<-done
+}
+
+// cleanUpDataplane runs on the dataplane goroutine, after the last apply.  It is best effort:
+// failures are logged, Felix is exiting anyway.
+func (d *InternalDataplane) cleanUpDataplane() {
+	log.Info("Removing the programmed state from the dataplane.")
+	if d.config.BPFEnabled {
+		if err := bpfnat.RemoveConnectTimeLoadBalancer(d.config.BPFCgroupV2); err != nil {
+			log.WithError(err).Warn("Failed to remove the BPF connect-time load balancer.")
+		}
+		// Detaches the programs and unpins the maps.
+		tc.CleanUpProgramsAndPins()
+		removeBPFSpecialDevices()
+	}
+	if d.xdpState != nil {
+		if err := d.shutdownXDPCompletely(); err != nil {
+			log.WithError(err).Warn("Failed to remove the XDP state.")
+		}
+	}
+
+	for _, t := range d.allIptablesTables {
+		t.RemoveAll()
+		t.Apply()
+	}
+
+	for _, r := range d.routeTableSyncers() {
+		rt, ok := r.(interface{ RemoveAllRoutes() })
+		if !ok {
+			continue
+		}
+		rt.RemoveAllRoutes()
+		if err := r.Apply(); err != nil {
+			log.WithError(err).Warn("Failed to remove routes.")
+		}
+	}
+	log.Info("Removed the programmed state from the dataplane.")
+}
+
 func (d *InternalDataplane) loopUpdatingDataplane() {
 	log.Info("Started internal iptables dataplane driver loop")
 	healthTicks := time.NewTicker(healthInterval).C
@@ -1760,6 +2112,10 @@ func (d *InternalDataplane) loopUpdatingDataplane() {
 	if d.xdpState != nil {
 		xdpRefreshC = newRefreshTicker("XDP state", d.config.XDPRefreshInterval)
 	}
+	var dnsCacheC <-chan struct{}
+	if d.dnsCache != nil {
+		dnsCacheC = d.dnsCache.Changes()
+	}
 
 	// Implement a simple leaky bucket throttle to control how often we refresh the dataplane.
 	// This makes sure that we tend to favour processing updates from the datastore if we're
@@ -1771,6 +2127,10 @@ func (d *InternalDataplane) loopUpdatingDataplane() {
 		select {
 		case msg := <-d.toDataplane:
 			d.onDatastoreMessage(msg)
+		case done := <-d.cleanUpC:
+			d.cleanUpDataplane()
+			close(done)
+			return
 		case ifaceUpdate := <-d.ifaceUpdates:
 			d.onIfaceMonitorMessage(ifaceUpdate)
 		case name := <-d.vxlanParentC:
@@ -1789,6 +2149,9 @@ func (d *InternalDataplane) loopUpdatingDataplane() {
 			log.Debug("Refreshing XDP")
 			d.forceXDPRefresh = true
 			d.dataplaneNeedsSync = true
+		case <-dnsCacheC:
+			log.Debug("Snooped DNS addresses changed, updating domain IP sets")
+			d.dataplaneNeedsSync = true
 		case <-d.reschedC:
 			log.Debug("Reschedule kick received")
 			d.dataplaneNeedsSync = true
@@ -1819,6 +2182,10 @@ func (d *InternalDataplane) loopUpdatingDataplane() {
 					log.WithField("delay", d.config.DebugSimulateDataplaneApplyDelay).Debug("Simulating a dataplane-apply delay")
 					time.Sleep(d.config.DebugSimulateDataplaneApplyDelay)
 				}
+				if d.applyHooks != nil {
+					d.applyHooks.PreApply()
+				}
+
 				// Actually apply the changes to the dataplane.
 				d.apply()
 
@@ -1826,6 +2193,10 @@ func (d *InternalDataplane) loopUpdatingDataplane() {
 				applyTime := time.Since(applyStart)
 				summaryApplyTime.Observe(applyTime.Seconds())
 
+				if d.applyHooks != nil {
+					d.applyHooks.PostApply(applyTime, !d.dataplaneNeedsSync)
+				}
+
 				if d.dataplaneNeedsSync {
 					// Dataplane is still dirty, record an error.
 					countDataplaneSyncErrors.Inc()
@@ -1943,6 +2314,9 @@ func (d *InternalDataplane) processIfaceStateUpdate(ifaceUpdate *ifaceStateUpdat
 	log.WithField("msg", ifaceUpdate).Info("Received interface update")
 	d.dataplaneNeedsSync = true
 	d.linkUpdateBatchSize++
+	if d.applyHooks != nil {
+		d.applyHooks.RecordUpdate("InterfaceStateUpdate")
+	}
 	if ifaceUpdate.Name == KubeIPVSInterface {
 		d.checkIPVSConfigOnStateUpdate(ifaceUpdate.State)
 		return
@@ -1967,6 +2341,9 @@ func (d *InternalDataplane) processIfaceAddrsUpdate(ifaceAddrsUpdate *ifaceAddrs
 	log.WithField("msg", ifaceAddrsUpdate).Info("Received interface addresses update")
 	d.dataplaneNeedsSync = true
 	d.addrsUpdateBatchSize++
+	if d.applyHooks != nil {
+		d.applyHooks.RecordUpdate("InterfaceAddrsUpdate")
+	}
 	for _, mgr := range d.allManagers {
 		mgr.OnUpdate(ifaceAddrsUpdate)
 	}
@@ -2027,6 +2404,9 @@ func (d *InternalDataplane) configureKernel() {
 func (d *InternalDataplane) recordMsgStat(msg interface{}) {
 	typeName := reflect.ValueOf(msg).Elem().Type().Name()
 	countMessages.WithLabelValues(typeName).Inc()
+	if d.applyHooks != nil {
+		d.applyHooks.RecordUpdate(typeName)
+	}
 }
 
 func (d *InternalDataplane) apply() {
@@ -2317,9 +2697,11 @@ func (d dummyLock) Unlock() {
 
 func startBPFDataplaneComponents(ipFamily proto.IPVersion,
 	bpfmaps *bpfmap.IPMaps,
+	commonMaps *bpfmap.CommonMaps,
 	ipSetIDAllocator *idalloc.IDAllocator,
 	config Config,
 	ipSetsMgr *common.IPSetsManager,
+	routesMirrorTable routetable.RouteTableInterface,
 	dp *InternalDataplane) {
 
 	ipSetConfig := config.RulesConfig.IPSetConfigV4
@@ -2344,10 +2726,22 @@ func startBPFDataplaneComponents(ipFamily proto.IPVersion,
 		bpfproxyOpts = append(bpfproxyOpts, bpfproxy.WithTopologyNodeZone(config.NodeZone))
 	}
 
+	if dp.kubeProxyHealthz != nil {
+		bpfproxyOpts = append(bpfproxyOpts, bpfproxy.WithHealthzServer(dp.kubeProxyHealthz))
+	}
+
 	if len(config.BPFExcludeCIDRsFromNAT) > 0 {
 		bpfproxyOpts = append(bpfproxyOpts, bpfproxy.WithExcludedCIDRs(config.BPFExcludeCIDRsFromNAT))
 	}
 
+	if len(config.BPFNodePortAddresses) > 0 {
+		bpfproxyOpts = append(bpfproxyOpts, bpfproxy.WithNodePortAddresses(config.BPFNodePortAddresses))
+	}
+
+	if config.BPFServiceLimits != (bpfproxy.ServiceLimits{}) {
+		bpfproxyOpts = append(bpfproxyOpts, bpfproxy.WithServiceLimits(config.BPFServiceLimits))
+	}
+
 	if ipFamily == proto.IPVersion_IPV6 {
 		ipSetConfig = config.RulesConfig.IPSetConfigV6
 		ipSetEntry = bpfipsets.IPSetEntryV6FromBytes
@@ -2377,7 +2771,7 @@ func startBPFDataplaneComponents(ipFamily proto.IPVersion,
 	)
 	dp.RegisterManager(failsafeMgr)
 
-	bpfRTMgr := newBPFRouteManager(&config, bpfmaps, ipFamily, dp.loopSummarizer)
+	bpfRTMgr := newBPFRouteManager(&config, bpfmaps, ipFamily, routesMirrorTable, dp.loopSummarizer)
 	dp.RegisterManager(bpfRTMgr)
 
 	conntrackScanner := bpfconntrack.NewScanner(bpfmaps.CtMap, ctKey, ctVal,
@@ -2401,7 +2795,16 @@ func startBPFDataplaneComponents(ipFamily proto.IPVersion,
 
 		bpfRTMgr.setHostIPUpdatesCallBack(kp.OnHostIPsUpdate)
 		bpfRTMgr.setRoutesCallBacks(kp.OnRouteUpdate, kp.OnRouteDelete)
+		bpfRTMgr.setHostPortsCallBack(kp.OnHostPortsUpdate)
 		conntrackScanner.AddUnlocked(bpfconntrack.NewStaleNATScanner(kp))
+		conntrackScanner.AddUnlocked(bpfconntrack.NewServiceStatsScanner(kp, int(ipFamily)))
+		if ipFamily == proto.IPVersion_IPV6 && config.BPFNAT64Enabled {
+			_, prefix, err := net.ParseCIDR(config.BPFNAT64Prefix)
+			if err != nil {
+				log.WithError(err).Panic("Invalid NAT64 prefix.")
+			}
+			conntrackScanner.AddUnlocked(nat64.NewScanner(commonMaps.NAT64FwdMap, commonMaps.NAT64RevMap, prefix.IP))
+		}
 		conntrackScanner.Start()
 	} else {
 		log.Info("BPF enabled but no Kubernetes client available, unable to run kube-proxy module.")
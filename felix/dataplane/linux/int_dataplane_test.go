@@ -85,7 +85,8 @@ var _ = Describe("Constructor test", func() {
 			IPIPMTU:          configParams.IpInIpMtu,
 			HealthAggregator: healthAggregator,
 
-			MTUIfacePattern: regexp.MustCompile(".*"),
+			MTUIfacePattern:    regexp.MustCompile(".*"),
+			BPFConntrackShards: configParams.BPFConntrackShards,
 
 			LookPathOverride: func(file string) (string, error) {
 				return file, nil
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/routerule"
+	"github.com/projectcalico/calico/felix/routetable"
+)
+
+const (
+	// l7ProxyRoutingRulePriority is the priority of the routing rule that delivers the
+	// connections of the L7 proxy to its transparent sockets.  It must come before the rule of
+	// the main table.
+	l7ProxyRoutingRulePriority = 98
+
+	l7ProxyLoopbackIface = "lo"
+)
+
+// l7ProxyManager programs the routing that TPROXY needs in BPF mode: the packets that the
+// iptables rules mark with the L7 proxy mark are routed to the loopback device by a dedicated
+// routing table so that they are delivered to the proxy's sockets rather than forwarded.
+//
+// The manager exists whenever BPF mode is enabled, with a zero mark when the L7 proxy is
+// disabled, so that the routing rule and table are removed after the L7 proxy is disabled.
+type l7ProxyManager struct {
+	routeTable routetable.RouteTableInterface
+	routeRules routeRules
+}
+
+func newL7ProxyManager(
+	ipVersion uint8,
+	mark uint32,
+	tableIndex int,
+	routeTable routetable.RouteTableInterface,
+	routeRules routeRules,
+) *l7ProxyManager {
+	var targets []routetable.Target
+	if mark != 0 {
+		defaultCIDR := ip.MustParseCIDROrIP("0.0.0.0/0")
+		if ipVersion == 6 {
+			defaultCIDR = ip.MustParseCIDROrIP("::/0")
+		}
+		targets = []routetable.Target{{Type: routetable.TargetTypeLocal, CIDR: defaultCIDR}}
+		routeRules.SetRule(routerule.NewRule(int(ipVersion), l7ProxyRoutingRulePriority).
+			MatchFWMarkWithMask(mark, mark).
+			GoToTable(tableIndex))
+	}
+	routeTable.SetRoutes(l7ProxyLoopbackIface, targets)
+
+	return &l7ProxyManager{
+		routeTable: routeTable,
+		routeRules: routeRules,
+	}
+}
+
+func (m *l7ProxyManager) OnUpdate(msg interface{}) {
+}
+
+func (m *l7ProxyManager) CompleteDeferredWork() error {
+	// Dataplane programming is handled through the routetable and routerule interfaces.
+	return nil
+}
+
+func (m *l7ProxyManager) GetRouteTableSyncers() []routetable.RouteTableSyncer {
+	return []routetable.RouteTableSyncer{m.routeTable}
+}
+
+func (m *l7ProxyManager) GetRouteRules() []routeRules {
+	return []routeRules{m.routeRules}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/routerule"
+	"github.com/projectcalico/calico/felix/routetable"
+)
+
+type mockRouteRules struct {
+	rules []*routerule.Rule
+}
+
+func (r *mockRouteRules) SetRule(rule *routerule.Rule) {
+	r.rules = append(r.rules, rule)
+}
+
+func (r *mockRouteRules) RemoveRule(rule *routerule.Rule) {}
+func (r *mockRouteRules) QueueResync()                    {}
+func (r *mockRouteRules) Apply() error {
+	return nil
+}
+
+var _ = Describe("L7ProxyManager", func() {
+	var rt *mockRouteTable
+	var rr *mockRouteRules
+
+	BeforeEach(func() {
+		rt = &mockRouteTable{
+			currentRoutes: map[string][]routetable.Target{},
+		}
+		rr = &mockRouteRules{}
+	})
+
+	It("should route marked packets to the loopback device", func() {
+		newL7ProxyManager(4, 0x100, 250, rt, rr)
+
+		rt.checkRoutes("lo", []routetable.Target{{
+			Type: routetable.TargetTypeLocal,
+			CIDR: ip.MustParseCIDROrIP("0.0.0.0/0"),
+		}})
+		Expect(rr.rules).To(Equal([]*routerule.Rule{
+			routerule.NewRule(4, l7ProxyRoutingRulePriority).
+				MatchFWMarkWithMask(0x100, 0x100).
+				GoToTable(250),
+		}))
+	})
+
+	It("should use the IPv6 default route for IPv6", func() {
+		newL7ProxyManager(6, 0x100, 250, rt, rr)
+
+		rt.checkRoutes("lo", []routetable.Target{{
+			Type: routetable.TargetTypeLocal,
+			CIDR: ip.MustParseCIDROrIP("::/0"),
+		}})
+	})
+
+	It("should clean up when the L7 proxy is disabled", func() {
+		newL7ProxyManager(4, 0, 250, rt, rr)
+
+		Expect(rt.currentRoutes).To(HaveKey("lo"))
+		rt.checkRoutes("lo", nil)
+		Expect(rr.rules).To(BeEmpty())
+	})
+})
@@ -15,11 +15,14 @@
 package intdataplane
 
 import (
+	"bytes"
+	"sort"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/projectcalico/calico/felix/dataplane/common"
+	"github.com/projectcalico/calico/felix/ip"
 	"github.com/projectcalico/calico/felix/ipsets"
 	"github.com/projectcalico/calico/felix/proto"
 	"github.com/projectcalico/calico/felix/rules"
@@ -37,12 +40,18 @@ import (
 // When NAT-enabled pools are present, the masqManager inserts the iptables masquerade rule
 // to trigger NAT of outgoing packets from NAT-enabled pools.  Traffic to any Calico-owned
 // pool is excluded.
+//
+// Egress SNAT pools carry the ID of an IP set of selected workloads.  If the host has an
+// address in such a pool, the NAT-outgoing traffic from the members of the IP set is source
+// NATed to that address instead.
 type masqManager struct {
 	ipVersion       uint8
 	ipsetsDataplane common.IPSetsDataplane
 	natTable        IptablesTable
 	activePools     map[string]*proto.IPAMPool
 	masqPools       set.Set[string]
+	egressPools     set.Set[string]
+	hostIfaceAddrs  map[string]set.Set[string]
 	dirty           bool
 	ruleRenderer    rules.RuleRenderer
 
@@ -76,6 +85,8 @@ func newMasqManager(
 		natTable:        natTable,
 		activePools:     map[string]*proto.IPAMPool{},
 		masqPools:       set.New[string](),
+		egressPools:     set.New[string](),
+		hostIfaceAddrs:  map[string]set.Set[string]{},
 		dirty:           true,
 		ruleRenderer:    ruleRenderer,
 		logCxt:          log.WithField("ipVersion", ipVersion),
@@ -94,6 +105,17 @@ func (d *masqManager) OnUpdate(msg interface{}) {
 	case *proto.IPAMPoolRemove:
 		d.logCxt.WithField("id", msg.Id).Debug("IPAM pool removed")
 		poolID = msg.Id
+	case *ifaceAddrsUpdate:
+		if msg.Addrs != nil {
+			d.hostIfaceAddrs[msg.Name] = msg.Addrs
+		} else {
+			delete(d.hostIfaceAddrs, msg.Name)
+		}
+		// The addresses only matter for the egress SNAT pools.
+		if d.egressPools.Len() > 0 {
+			d.dirty = true
+		}
+		return
 	default:
 		return
 	}
@@ -112,6 +134,7 @@ func (d *masqManager) OnUpdate(msg interface{}) {
 		}
 		delete(d.activePools, poolID)
 		d.masqPools.Discard(poolID)
+		d.egressPools.Discard(poolID)
 	}
 	if newPool != nil {
 		// An update/create.
@@ -130,6 +153,10 @@ func (d *masqManager) OnUpdate(msg interface{}) {
 			d.ipsetsDataplane.AddMembers(rules.IPSetIDNATOutgoingMasqPools, []string{newPool.Cidr})
 			d.masqPools.Add(poolID)
 		}
+		if newPool.EgressSnatIpSetId != "" {
+			logCxt.Debug("IPAM pool is an egress SNAT pool.")
+			d.egressPools.Add(poolID)
+		}
 		d.activePools[poolID] = newPool
 	}
 	d.dirty = true
@@ -143,9 +170,49 @@ func (m *masqManager) CompleteDeferredWork() error {
 	// Refresh the chain in case we've gone from having no masq pools to
 	// having some or vice-versa.
 	m.logCxt.Info("IPAM pools updated, refreshing iptables rule")
-	chain := m.ruleRenderer.NATOutgoingChain(m.masqPools.Len() > 0, m.ipVersion)
+	chain := m.ruleRenderer.NATOutgoingChain(m.masqPools.Len() > 0, m.egressSNATs(), m.ipVersion)
 	m.natTable.UpdateChain(chain)
 	m.dirty = false
 
 	return nil
 }
+
+// egressSNATs returns the SNATs of the egress SNAT pools that the host has an address in, in a
+// stable order.  If the host has several addresses in a pool, the lowest one is used.
+func (m *masqManager) egressSNATs() []rules.EgressSNAT {
+	var snats []rules.EgressSNAT
+	for _, poolID := range m.egressPools.Slice() {
+		pool := m.activePools[poolID]
+		cidr, err := ip.CIDRFromString(pool.Cidr)
+		if err != nil {
+			m.logCxt.WithError(err).WithField("id", poolID).Warn("Failed to parse egress SNAT pool CIDR.")
+			continue
+		}
+
+		var toAddr ip.Addr
+		for _, addrs := range m.hostIfaceAddrs {
+			addrs.Iter(func(a string) error {
+				addr := ip.FromString(a)
+				if addr == nil || !cidr.Contains(addr) {
+					return nil
+				}
+				if toAddr == nil || bytes.Compare(addr.AsNetIP().To16(), toAddr.AsNetIP().To16()) < 0 {
+					toAddr = addr
+				}
+				return nil
+			})
+		}
+		if toAddr == nil {
+			m.logCxt.WithField("id", poolID).Debug("Host has no address in egress SNAT pool.")
+			continue
+		}
+		snats = append(snats, rules.EgressSNAT{
+			IPSetID: pool.EgressSnatIpSetId,
+			ToAddr:  toAddr.String(),
+		})
+	}
+	sort.Slice(snats, func(i, j int) bool {
+		return snats[i].IPSetID < snats[j].IPSetID
+	})
+	return snats
+}
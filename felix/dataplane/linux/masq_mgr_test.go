@@ -118,6 +118,72 @@ var _ = Describe("Masquerade manager", func() {
 			Expect(natTable.UpdateCalled).To(BeFalse())
 		})
 
+		Describe("after adding an egress SNAT pool", func() {
+			egressRule := func(addr string) iptables.Rule {
+				return iptables.Rule{
+					Action: iptables.SNATAction{ToAddr: addr},
+					Match: iptables.Match().
+						SourceIPSet("cali40masq-ipam-pools").
+						NotDestIPSet("cali40all-ipam-pools").
+						SourceIPSet("cali40snat:tenant-a"),
+				}
+			}
+			masqRule := iptables.Rule{
+				Action: iptables.MasqAction{},
+				Match: iptables.Match().
+					SourceIPSet("cali40masq-ipam-pools").
+					NotDestIPSet("cali40all-ipam-pools"),
+			}
+
+			BeforeEach(func() {
+				masqMgr.OnUpdate(&proto.IPAMPoolUpdate{
+					Id: "pool-egress",
+					Pool: &proto.IPAMPool{
+						Cidr:              "192.0.2.0/28",
+						EgressSnatIpSetId: "snat:tenant-a",
+					},
+				})
+				err := masqMgr.CompleteDeferredWork()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not render a rule while the host has no address in the pool", func() {
+				natTable.checkChains([][]*iptables.Chain{{{
+					Name:  "cali-nat-outgoing",
+					Rules: []iptables.Rule{masqRule},
+				}}})
+			})
+
+			It("should SNAT to the lowest host address in the pool", func() {
+				masqMgr.OnUpdate(&ifaceAddrsUpdate{Name: "eth0", Addrs: set.From("10.0.240.1", "192.0.2.5")})
+				masqMgr.OnUpdate(&ifaceAddrsUpdate{Name: "eth1", Addrs: set.From("192.0.2.3", "192.0.2.20")})
+				err := masqMgr.CompleteDeferredWork()
+				Expect(err).ToNot(HaveOccurred())
+				natTable.checkChains([][]*iptables.Chain{{{
+					Name:  "cali-nat-outgoing",
+					Rules: []iptables.Rule{egressRule("192.0.2.3"), masqRule},
+				}}})
+
+				By("following the host addresses")
+				masqMgr.OnUpdate(&ifaceAddrsUpdate{Name: "eth1"})
+				err = masqMgr.CompleteDeferredWork()
+				Expect(err).ToNot(HaveOccurred())
+				natTable.checkChains([][]*iptables.Chain{{{
+					Name:  "cali-nat-outgoing",
+					Rules: []iptables.Rule{egressRule("192.0.2.5"), masqRule},
+				}}})
+
+				By("removing the rule with the pool")
+				masqMgr.OnUpdate(&proto.IPAMPoolRemove{Id: "pool-egress"})
+				err = masqMgr.CompleteDeferredWork()
+				Expect(err).ToNot(HaveOccurred())
+				natTable.checkChains([][]*iptables.Chain{{{
+					Name:  "cali-nat-outgoing",
+					Rules: []iptables.Rule{masqRule},
+				}}})
+			})
+		})
+
 		Describe("after adding a non-masq pool", func() {
 			BeforeEach(func() {
 				masqMgr.OnUpdate(&proto.IPAMPoolUpdate{
@@ -0,0 +1,244 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/logrules"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/perf"
+	"github.com/projectcalico/calico/felix/policylog"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// bpfPolicyLogPerfPages is the size of the per-CPU ring buffers of the log events.
+const bpfPolicyLogPerfPages = 8
+
+type bpfLogRule struct {
+	target    policylog.Target
+	facility  string
+	interval  time.Duration
+	tolerance time.Duration
+}
+
+// bpfPolicyLogManager programs the log rules of the active policies and profiles into the BPF
+// log rules map, which holds their rate limits, and writes the packets that the TC programs
+// report through the log events map to the targets of the rules.
+type bpfPolicyLogManager struct {
+	rulesMap  maps.Map
+	writer    *policylog.Writer
+	logPrefix string
+	matchID   func(dir, action, owner, name string, idx int) uint64
+
+	policyRules  map[proto.PolicyID]map[uint64]bpfLogRule
+	profileRules map[proto.ProfileID]map[uint64]bpfLogRule
+	dirty        bool
+
+	// programmed is read by the goroutine that handles the log events.
+	lock       sync.Mutex
+	programmed map[uint64]bpfLogRule
+
+	startReader   func() error
+	readerStarted bool
+	eventsMap     maps.Map
+	ifaceNames    map[uint32]string
+}
+
+func newBPFPolicyLogManager(rulesMap, eventsMap maps.Map, writer *policylog.Writer, logPrefix string) *bpfPolicyLogManager {
+	m := &bpfPolicyLogManager{
+		rulesMap:     rulesMap,
+		eventsMap:    eventsMap,
+		writer:       writer,
+		logPrefix:    logPrefix,
+		matchID:      ruleMatchID,
+		policyRules:  map[proto.PolicyID]map[uint64]bpfLogRule{},
+		profileRules: map[proto.ProfileID]map[uint64]bpfLogRule{},
+		programmed:   map[uint64]bpfLogRule{},
+		ifaceNames:   map[uint32]string{},
+	}
+	m.startReader = m.startPerfReader
+	return m
+}
+
+func (m *bpfPolicyLogManager) OnUpdate(msg interface{}) {
+	switch msg := msg.(type) {
+	case *proto.ActivePolicyUpdate:
+		if model.PolicyIsStaged(msg.Id.Name) {
+			// Staged policies are not programmed in BPF mode.
+			return
+		}
+		m.policyRules[*msg.Id] = m.logRules("Policy", msg.Id.Name, msg.Policy.InboundRules, msg.Policy.OutboundRules)
+		m.dirty = true
+	case *proto.ActivePolicyRemove:
+		delete(m.policyRules, *msg.Id)
+		m.dirty = true
+	case *proto.ActiveProfileUpdate:
+		m.profileRules[*msg.Id] = m.logRules("Profile", msg.Id.Name, msg.Profile.InboundRules, msg.Profile.OutboundRules)
+		m.dirty = true
+	case *proto.ActiveProfileRemove:
+		delete(m.profileRules, *msg.Id)
+		m.dirty = true
+	}
+}
+
+func (m *bpfPolicyLogManager) logRules(owner, name string, inbound, outbound []*proto.Rule) map[uint64]bpfLogRule {
+	var logRules map[uint64]bpfLogRule
+	for _, dir := range []PolDirection{PolDirnIngress, PolDirnEgress} {
+		prules := inbound
+		if dir == PolDirnEgress {
+			prules = outbound
+		}
+		for i, r := range prules {
+			if r.Action != "log" {
+				continue
+			}
+			interval, tolerance, err := logrules.RateLimit(r.LogRateLimit, r.LogRateLimitBurst)
+			if err != nil {
+				// Validation should have caught this, log every packet rather than none.
+				log.WithError(err).WithField("rule", r).Warn("Ignoring bad rate limit of log rule.")
+			}
+			if logRules == nil {
+				logRules = map[uint64]bpfLogRule{}
+			}
+			logRules[m.matchID(dir.RuleDir(), r.Action, owner, name, i)] = bpfLogRule{
+				target:    policylog.Target(r.LogTarget),
+				facility:  r.LogSyslogFacility,
+				interval:  interval,
+				tolerance: tolerance,
+			}
+		}
+	}
+	return logRules
+}
+
+func (m *bpfPolicyLogManager) CompleteDeferredWork() error {
+	if !m.dirty {
+		return nil
+	}
+
+	desired := map[uint64]bpfLogRule{}
+	for _, rs := range m.policyRules {
+		for id, r := range rs {
+			desired[id] = r
+		}
+	}
+	for _, rs := range m.profileRules {
+		for id, r := range rs {
+			desired[id] = r
+		}
+	}
+
+	for id, r := range desired {
+		if old, ok := m.programmed[id]; ok && old.interval == r.interval && old.tolerance == r.tolerance {
+			// Leave the entry alone so that the rule keeps its place in the rate limit.
+			continue
+		}
+		if err := m.rulesMap.Update(logrules.Key(id), logrules.Value(r.interval, r.tolerance)); err != nil {
+			return fmt.Errorf("failed to update log rule %x: %w", id, err)
+		}
+	}
+	for id := range m.programmed {
+		if _, ok := desired[id]; ok {
+			continue
+		}
+		if err := m.rulesMap.Delete(logrules.Key(id)); err != nil && !maps.IsNotExists(err) {
+			return fmt.Errorf("failed to delete log rule %x: %w", id, err)
+		}
+	}
+
+	m.lock.Lock()
+	m.programmed = desired
+	m.lock.Unlock()
+	m.dirty = false
+
+	if len(desired) > 0 && !m.readerStarted {
+		if err := m.startReader(); err != nil {
+			log.WithError(err).Error("Failed to start reading policy log events, packets will not be logged.")
+		}
+		m.readerStarted = true
+	}
+	return nil
+}
+
+func (m *bpfPolicyLogManager) startPerfReader() error {
+	r, err := perf.NewReader(m.eventsMap, maps.NumPossibleCPUs(), bpfPolicyLogPerfPages)
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := r.Run(m.onEvent, func(count uint64) {
+			log.WithField("count", count).Warn("Policy log events were dropped because Felix could not keep up.")
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to read policy log events, packets will no longer be logged.")
+		}
+	}()
+	return nil
+}
+
+// onEvent is called on the perf reader's goroutine with each logged packet.
+func (m *bpfPolicyLogManager) onEvent(data []byte) {
+	e, err := logrules.ParseEvent(data)
+	if err != nil {
+		log.WithError(err).Debug("Ignoring bad policy log event.")
+		return
+	}
+
+	m.lock.Lock()
+	r, ok := m.programmed[e.MatchID]
+	m.lock.Unlock()
+	if !ok {
+		// The rule was removed since the packet was logged.
+		return
+	}
+
+	pkt := policylog.Packet{
+		Src:      e.Src,
+		Dst:      e.Dst,
+		Length:   e.Length,
+		Protocol: e.Proto,
+		SrcPort:  e.SrcPort,
+		DstPort:  e.DstPort,
+	}
+	if e.Ingress {
+		pkt.InIface = m.ifaceName(e.IfIndex)
+	} else {
+		pkt.OutIface = m.ifaceName(e.IfIndex)
+	}
+	if err := m.writer.Write(r.target, r.facility, pkt.Format(m.logPrefix)); err != nil {
+		log.WithError(err).Debug("Failed to write policy log.")
+	}
+}
+
+// ifaceName is only called from the perf reader's goroutine.  Interface indexes are not
+// reused often enough for a stale name to matter.
+func (m *bpfPolicyLogManager) ifaceName(idx uint32) string {
+	if name, ok := m.ifaceNames[idx]; ok {
+		return name
+	}
+	name := fmt.Sprint(idx)
+	if iface, err := net.InterfaceByIndex(int(idx)); err == nil {
+		name = iface.Name
+	}
+	m.ifaceNames[idx] = name
+	return name
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/logrules"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/policylog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+var _ = Describe("bpfPolicyLogManager", func() {
+	var (
+		dir           string
+		rulesMap      *mock.Map
+		mgr           *bpfPolicyLogManager
+		readerStarted int
+		polID         proto.PolicyID
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "policylog")
+		Expect(err).NotTo(HaveOccurred())
+		rulesMap = mock.NewMockMap(logrules.MapParameters)
+		mgr = newBPFPolicyLogManager(rulesMap, nil, policylog.NewWriter(filepath.Join(dir, "policy.log")), "calico-packet")
+		readerStarted = 0
+		mgr.startReader = func() error {
+			readerStarted++
+			return nil
+		}
+		polID = proto.PolicyID{Tier: "default", Name: "pol1"}
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	logRuleID := func() uint64 {
+		return ruleMatchID("Ingress", "log", "Policy", "pol1", 1)
+	}
+
+	sendPolicy := func(rules ...*proto.Rule) {
+		mgr.OnUpdate(&proto.ActivePolicyUpdate{
+			Id:     &polID,
+			Policy: &proto.Policy{InboundRules: rules},
+		})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+	}
+
+	It("should program the rate limits of the log rules", func() {
+		sendPolicy(
+			&proto.Rule{Action: "allow"},
+			&proto.Rule{Action: "log", LogTarget: "File", LogRateLimit: "10/second", LogRateLimitBurst: 2},
+		)
+		Expect(rulesMap.Contents).To(HaveLen(1))
+		Expect(rulesMap.ContainsKV(logrules.Key(logRuleID()),
+			logrules.Value(100*time.Millisecond, 100*time.Millisecond))).To(BeTrue())
+		Expect(readerStarted).To(Equal(1))
+
+		By("leaving unchanged rules alone")
+		sendPolicy(
+			&proto.Rule{Action: "allow"},
+			&proto.Rule{Action: "log", LogTarget: "Syslog", LogRateLimit: "10/second", LogRateLimitBurst: 2},
+		)
+		Expect(rulesMap.UpdateCount).To(Equal(1))
+
+		By("removing the rules of removed policies")
+		mgr.OnUpdate(&proto.ActivePolicyRemove{Id: &polID})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(rulesMap.Contents).To(BeEmpty())
+		Expect(readerStarted).To(Equal(1))
+	})
+
+	It("should ignore staged policies", func() {
+		polID.Name = "staged:pol1"
+		sendPolicy(&proto.Rule{Action: "log"})
+		Expect(rulesMap.Contents).To(BeEmpty())
+		Expect(readerStarted).To(Equal(0))
+	})
+
+	It("should write the logged packets to the target of their rule", func() {
+		sendPolicy(
+			&proto.Rule{Action: "allow"},
+			&proto.Rule{Action: "log", LogTarget: "File"},
+		)
+
+		evt := make([]byte, logrules.EventSize)
+		binary.LittleEndian.PutUint64(evt[0:8], logRuleID())
+		copy(evt[8:], net.IPv4(10, 0, 0, 1).To4())
+		copy(evt[24:], net.IPv4(10, 0, 0, 2).To4())
+		binary.LittleEndian.PutUint16(evt[44:46], 34567)
+		binary.LittleEndian.PutUint16(evt[46:48], 80)
+		evt[48] = 6
+		evt[49] = 4
+		binary.BigEndian.PutUint16(evt[52:54], 60)
+		mgr.ifaceNames[0] = "cali1234"
+		mgr.onEvent(evt)
+
+		// Events of unknown rules are dropped.
+		binary.LittleEndian.PutUint64(evt[0:8], 1)
+		mgr.onEvent(evt)
+
+		content, err := os.ReadFile(filepath.Join(dir, "policy.log"))
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(HaveSuffix(
+			"calico-packet: IN= OUT=cali1234 SRC=10.0.0.1 DST=10.0.0.2 LEN=60 PROTO=TCP SPT=34567 DPT=80"))
+	})
+})
@@ -0,0 +1,276 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/counters"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/iptables"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// policyStatsMinInterval limits how often the counters are read from the dataplane, reading
+// them from iptables means dumping the whole filter table.
+const policyStatsMinInterval = 10 * time.Second
+
+var (
+	policyRulePacketsDesc = prometheus.NewDesc(
+		"felix_policy_rule_packets",
+		"Number of packets that matched a policy rule.  In BPF mode, only the packets that "+
+			"are evaluated by the policy, i.e. the first packets of the connections, are counted.",
+		[]string{"tier", "policy", "direction", "rule"}, nil,
+	)
+	policyRuleBytesDesc = prometheus.NewDesc(
+		"felix_policy_rule_bytes",
+		"Number of bytes of the packets that matched a policy rule, only available in iptables mode.",
+		[]string{"tier", "policy", "direction", "rule"}, nil,
+	)
+)
+
+type policyRuleKey struct {
+	policy    proto.PolicyID
+	direction PolDirection
+	index     int
+}
+
+type policyRuleHits struct {
+	packets  uint64
+	bytes    uint64
+	hasBytes bool
+}
+
+// ruleCountersReader reads the hit counters of the rules of the given policies from one of the
+// dataplanes and adds them to hits.
+type ruleCountersReader interface {
+	readRuleCounters(policies map[proto.PolicyID]*proto.Policy, hits map[policyRuleKey]*policyRuleHits) error
+}
+
+// policyStatsCollector tracks the active policies and exposes the hit counters of their rules
+// as Prometheus metrics.  It is a Manager so that it learns about the policies from the calc
+// graph and a Collector so that the counters are only read when they are scraped.
+type policyStatsCollector struct {
+	lock     sync.Mutex
+	policies map[proto.PolicyID]*proto.Policy
+
+	readers []ruleCountersReader
+
+	collectLock        sync.Mutex
+	lastCollectionTime time.Time
+	lastMetrics        []prometheus.Metric
+	minInterval        time.Duration
+	timeNow            func() time.Time
+}
+
+func newPolicyStatsCollector(readers ...ruleCountersReader) *policyStatsCollector {
+	return &policyStatsCollector{
+		policies:    map[proto.PolicyID]*proto.Policy{},
+		readers:     readers,
+		minInterval: policyStatsMinInterval,
+		timeNow:     time.Now,
+	}
+}
+
+func (c *policyStatsCollector) OnUpdate(msg interface{}) {
+	switch msg := msg.(type) {
+	case *proto.ActivePolicyUpdate:
+		c.lock.Lock()
+		c.policies[*msg.Id] = msg.Policy
+		c.lock.Unlock()
+	case *proto.ActivePolicyRemove:
+		c.lock.Lock()
+		delete(c.policies, *msg.Id)
+		c.lock.Unlock()
+	}
+}
+
+func (c *policyStatsCollector) CompleteDeferredWork() error {
+	return nil
+}
+
+func (c *policyStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- policyRulePacketsDesc
+	ch <- policyRuleBytesDesc
+}
+
+func (c *policyStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectLock.Lock()
+	defer c.collectLock.Unlock()
+
+	now := c.timeNow()
+	if c.lastMetrics == nil || now.Sub(c.lastCollectionTime) >= c.minInterval {
+		c.lastMetrics = c.collectMetrics()
+		c.lastCollectionTime = now
+	}
+	for _, m := range c.lastMetrics {
+		ch <- m
+	}
+}
+
+func (c *policyStatsCollector) collectMetrics() []prometheus.Metric {
+	// Take a copy so that we do not block the dataplane while reading the counters.
+	c.lock.Lock()
+	policies := make(map[proto.PolicyID]*proto.Policy, len(c.policies))
+	for id, pol := range c.policies {
+		policies[id] = pol
+	}
+	c.lock.Unlock()
+
+	hits := map[policyRuleKey]*policyRuleHits{}
+	for _, r := range c.readers {
+		if err := r.readRuleCounters(policies, hits); err != nil {
+			log.WithError(err).Warn("Failed to read policy rule counters.")
+		}
+	}
+
+	metrics := []prometheus.Metric{}
+	for k, h := range hits {
+		labels := []string{k.policy.Tier, k.policy.Name, strings.ToLower(k.direction.RuleDir()), strconv.Itoa(k.index)}
+		metrics = append(metrics, prometheus.MustNewConstMetric(
+			policyRulePacketsDesc, prometheus.CounterValue, float64(h.packets), labels...))
+		if h.hasBytes {
+			metrics = append(metrics, prometheus.MustNewConstMetric(
+				policyRuleBytesDesc, prometheus.CounterValue, float64(h.bytes), labels...))
+		}
+	}
+	return metrics
+}
+
+func policyRules(pol *proto.Policy, dir PolDirection) []*proto.Rule {
+	if dir == PolDirnIngress {
+		return pol.InboundRules
+	}
+	return pol.OutboundRules
+}
+
+func addRuleHits(hits map[policyRuleKey]*policyRuleHits, k policyRuleKey) *policyRuleHits {
+	h := hits[k]
+	if h == nil {
+		h = &policyRuleHits{}
+		hits[k] = h
+	}
+	return h
+}
+
+// iptablesRuleCounters reads the rule counters from the policy chains in the filter table.
+type iptablesRuleCounters struct {
+	table interface {
+		ReadRuleCounters() (map[string][]iptables.RuleCounters, error)
+	}
+	ruleRenderer rules.RuleRenderer
+	ipVersion    uint8
+}
+
+func (r *iptablesRuleCounters) readRuleCounters(
+	policies map[proto.PolicyID]*proto.Policy,
+	hits map[policyRuleKey]*policyRuleHits,
+) error {
+	chains, err := r.table.ReadRuleCounters()
+	if err != nil {
+		return err
+	}
+
+	for id, pol := range policies {
+		for _, dir := range []PolDirection{PolDirnIngress, PolDirnEgress} {
+			prefix := rules.PolicyInboundPfx
+			if dir == PolDirnEgress {
+				prefix = rules.PolicyOutboundPfx
+			}
+			chain := chains[rules.PolicyChainName(prefix, &id)]
+			if len(chain) == 0 {
+				continue
+			}
+
+			// A rule may be rendered as several iptables rules, all the rules that do the
+			// rule's action come last and see the same packets so we count the last one.  The
+			// trailing return rules of the chain are not programmed, in which case we count
+			// the preceding action rule.  Staged policies are rendered differently but the
+			// same holds for them.
+			renderRule := r.ruleRenderer.ProtoRuleToIptablesRules
+			if model.PolicyIsStaged(id.Name) {
+				renderRule = r.ruleRenderer.StagedProtoRuleToIptablesRules
+			}
+			end := 0
+			for i, rule := range policyRules(pol, dir) {
+				n := len(renderRule(rule, r.ipVersion))
+				if n == 0 {
+					continue
+				}
+				start := end
+				end += n
+				pos := end - 1
+				if pos >= len(chain) {
+					pos = len(chain) - 1
+				}
+				if pos < start {
+					break
+				}
+				h := addRuleHits(hits, policyRuleKey{policy: id, direction: dir, index: i})
+				h.packets += chain[pos].Packets
+				h.bytes += chain[pos].Bytes
+				h.hasBytes = true
+			}
+		}
+	}
+	return nil
+}
+
+// bpfRuleCounters reads the rule counters from the BPF rule counters map, which is only
+// populated when BPFPolicyDebugEnabled is set.
+type bpfRuleCounters struct {
+	countersMap maps.Map
+	matchID     func(dir, action, owner, name string, idx int) uint64
+}
+
+func newBPFRuleCounters(m maps.Map) *bpfRuleCounters {
+	return &bpfRuleCounters{
+		countersMap: m,
+		matchID:     ruleMatchID,
+	}
+}
+
+func (r *bpfRuleCounters) readRuleCounters(
+	policies map[proto.PolicyID]*proto.Policy,
+	hits map[policyRuleKey]*policyRuleHits,
+) error {
+	mem := make(counters.PolicyMapMem)
+	iter := counters.PolicyMapMemIter(mem)
+	err := r.countersMap.Iter(func(k, v []byte) maps.IteratorAction {
+		iter(k, v)
+		return maps.IterNone
+	})
+	if err != nil {
+		return err
+	}
+
+	for id, pol := range policies {
+		for _, dir := range []PolDirection{PolDirnIngress, PolDirnEgress} {
+			for i, rule := range policyRules(pol, dir) {
+				h := addRuleHits(hits, policyRuleKey{policy: id, direction: dir, index: i})
+				h.packets += mem[r.matchID(dir.RuleDir(), rule.Action, "Policy", id.Name, i)]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/projectcalico/calico/felix/ipsets"
+	"github.com/projectcalico/calico/felix/iptables"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
+)
+
+type mockCountersTable struct {
+	counters map[string][]iptables.RuleCounters
+	reads    int
+}
+
+func (t *mockCountersTable) ReadRuleCounters() (map[string][]iptables.RuleCounters, error) {
+	t.reads++
+	return t.counters, nil
+}
+
+var _ = Describe("policyStatsCollector", func() {
+	var (
+		table     *mockCountersTable
+		collector *policyStatsCollector
+		now       time.Time
+		polID     proto.PolicyID
+	)
+
+	BeforeEach(func() {
+		ruleRenderer := rules.NewRenderer(rules.Config{
+			IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+			IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+			IptablesMarkAccept:          0x8,
+			IptablesMarkPass:            0x10,
+			IptablesMarkScratch0:        0x20,
+			IptablesMarkScratch1:        0x40,
+			IptablesMarkEndpoint:        0xff00,
+			IptablesMarkNonCaliEndpoint: 0x0100,
+		})
+		polID = proto.PolicyID{Tier: "default", Name: "pol1"}
+		table = &mockCountersTable{counters: map[string][]iptables.RuleCounters{
+			// Log, then allow as a set-mark and a trailing return that is stripped.
+			rules.PolicyChainName(rules.PolicyInboundPfx, &polID): {
+				{Packets: 10, Bytes: 1000},
+				{Packets: 4, Bytes: 400},
+			},
+			// Deny.
+			rules.PolicyChainName(rules.PolicyOutboundPfx, &polID): {
+				{Packets: 3, Bytes: 300},
+			},
+		}}
+		collector = newPolicyStatsCollector(&iptablesRuleCounters{
+			table:        table,
+			ruleRenderer: ruleRenderer,
+			ipVersion:    4,
+		})
+		now = time.Now()
+		collector.timeNow = func() time.Time { return now }
+
+		collector.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &polID,
+			Policy: &proto.Policy{
+				InboundRules:  []*proto.Rule{{Action: "log"}, {Action: "allow"}},
+				OutboundRules: []*proto.Rule{{Action: "deny"}},
+			},
+		})
+	})
+
+	collect := func() map[string]float64 {
+		ch := make(chan prometheus.Metric, 100)
+		collector.Collect(ch)
+		close(ch)
+		values := map[string]float64{}
+		for m := range ch {
+			var pb dto.Metric
+			Expect(m.Write(&pb)).To(Succeed())
+			key := ""
+			for _, l := range pb.Label {
+				key += l.GetValue() + "/"
+			}
+			if m.Desc() == policyRulePacketsDesc {
+				key += "packets"
+			} else {
+				key += "bytes"
+			}
+			values[key] = pb.GetCounter().GetValue()
+		}
+		return values
+	}
+
+	It("should map the iptables counters to the policy rules", func() {
+		Expect(collect()).To(Equal(map[string]float64{
+			"ingress/pol1/0/default/packets": 10,
+			"ingress/pol1/0/default/bytes":   1000,
+			"ingress/pol1/1/default/packets": 4,
+			"ingress/pol1/1/default/bytes":   400,
+			"egress/pol1/0/default/packets":  3,
+			"egress/pol1/0/default/bytes":    300,
+		}))
+	})
+
+	It("should only read the counters once per interval", func() {
+		collect()
+		table.counters[rules.PolicyChainName(rules.PolicyOutboundPfx, &polID)][0].Packets = 5
+		Expect(collect()).To(HaveKeyWithValue("egress/pol1/0/default/packets", 3.0))
+		Expect(table.reads).To(Equal(1))
+
+		now = now.Add(policyStatsMinInterval)
+		Expect(collect()).To(HaveKeyWithValue("egress/pol1/0/default/packets", 5.0))
+		Expect(table.reads).To(Equal(2))
+	})
+
+	It("should count the would-deny rules of staged policies", func() {
+		stagedID := proto.PolicyID{Tier: "default", Name: "staged:pol2"}
+		// Deny as a log and a return, then allow as a return that is kept.
+		table.counters[rules.PolicyChainName(rules.PolicyInboundPfx, &stagedID)] = []iptables.RuleCounters{
+			{Packets: 1, Bytes: 100},
+			{Packets: 1, Bytes: 100},
+			{Packets: 6, Bytes: 600},
+		}
+		collector.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &stagedID,
+			Policy: &proto.Policy{
+				InboundRules: []*proto.Rule{{Action: "deny"}, {Action: "allow"}},
+			},
+		})
+		values := collect()
+		Expect(values).To(HaveKeyWithValue("ingress/staged:pol2/0/default/packets", 1.0))
+		Expect(values).To(HaveKeyWithValue("ingress/staged:pol2/1/default/packets", 6.0))
+	})
+
+	It("should stop reporting removed policies", func() {
+		collector.OnUpdate(&proto.ActivePolicyRemove{Id: &polID})
+		Expect(collect()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/projectcalico/calico/felix/flowlog"
+)
+
+// policyVerdictOverflowEndpoint is the endpoint label of the connections that are counted once
+// the series limit is reached.
+const policyVerdictOverflowEndpoint = "other"
+
+type policyVerdictSeries struct {
+	tier      string
+	policy    string
+	action    flowlog.Action
+	namespace string
+	endpoint  string
+}
+
+func (s policyVerdictSeries) labels() []string {
+	return []string{s.tier, s.policy, string(s.action), s.namespace, s.endpoint}
+}
+
+// policyVerdictMetrics counts the connections that the policies allowed or denied per local
+// workload endpoint, from the verdicts that the flowLogManager reads.  Every endpoint adds a
+// series per policy and action so, to bound the cardinality, the endpoints beyond maxSeries are
+// counted together per namespace, and the series of an endpoint are deleted when it is removed.
+type policyVerdictMetrics struct {
+	connections *prometheus.CounterVec
+	maxSeries   int
+
+	lock sync.Mutex
+	// series are the series of the endpoints, which count towards maxSeries.  The overflow
+	// series are bounded by the number of namespaces and are not included.
+	series map[policyVerdictSeries]bool
+}
+
+func newPolicyVerdictMetrics(maxSeries int) *policyVerdictMetrics {
+	return &policyVerdictMetrics{
+		connections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "felix_policy_verdict_connections",
+			Help: "Number of connections that a policy allowed or denied, per local workload endpoint. " +
+				"The policy is default-deny for the connections that no policy or profile allowed.",
+		}, []string{"tier", "policy", "action", "namespace", "endpoint"}),
+		maxSeries: maxSeries,
+		series:    map[policyVerdictSeries]bool{},
+	}
+}
+
+// recordVerdict counts a connection of the workload endpoint, as identified by the flow log
+// lookup, which is empty for the connections of host endpoints.
+func (m *policyVerdictMetrics) recordVerdict(tier, policy string, action flowlog.Action, workload string) {
+	s := policyVerdictSeries{tier: tier, policy: policy, action: action}
+	s.namespace, s.endpoint = splitWorkloadID(workload)
+
+	m.lock.Lock()
+	if !m.series[s] && s.endpoint != "" {
+		if len(m.series) < m.maxSeries {
+			m.series[s] = true
+		} else {
+			s.endpoint = policyVerdictOverflowEndpoint
+		}
+	}
+	m.lock.Unlock()
+
+	m.connections.WithLabelValues(s.labels()...).Inc()
+}
+
+// removeEndpoint deletes the series of a workload endpoint, making room for others.
+func (m *policyVerdictMetrics) removeEndpoint(workload string) {
+	namespace, endpoint := splitWorkloadID(workload)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for s := range m.series {
+		if s.namespace == namespace && s.endpoint == endpoint {
+			m.connections.DeleteLabelValues(s.labels()...)
+			delete(m.series, s)
+		}
+	}
+}
+
+func (m *policyVerdictMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.connections.Describe(ch)
+}
+
+func (m *policyVerdictMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.connections.Collect(ch)
+}
+
+// splitWorkloadID splits the workload ID of a Kubernetes pod, "<namespace>/<pod name>", into
+// its parts.  Other workload IDs have no namespace.
+func splitWorkloadID(workload string) (string, string) {
+	if namespace, name, ok := strings.Cut(workload, "/"); ok {
+		return namespace, name
+	}
+	return "", workload
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/projectcalico/calico/felix/flowlog"
+	"github.com/projectcalico/calico/felix/nflog"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+var _ = Describe("policyVerdictMetrics", func() {
+	var metrics *policyVerdictMetrics
+
+	BeforeEach(func() {
+		metrics = newPolicyVerdictMetrics(2)
+	})
+
+	count := func(tier, policy string, action flowlog.Action, namespace, endpoint string) float64 {
+		return testutil.ToFloat64(metrics.connections.WithLabelValues(tier, policy, string(action), namespace, endpoint))
+	}
+
+	It("should count the verdicts per policy, action and endpoint", func() {
+		metrics.recordVerdict("default", "default.web", flowlog.ActionAllow, "prod/web-1")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionAllow, "prod/web-1")
+		metrics.recordVerdict("", flowlog.DefaultDenyPolicy, flowlog.ActionDeny, "prod/web-1")
+
+		Expect(count("default", "default.web", flowlog.ActionAllow, "prod", "web-1")).To(Equal(2.0))
+		Expect(count("", flowlog.DefaultDenyPolicy, flowlog.ActionDeny, "prod", "web-1")).To(Equal(1.0))
+	})
+
+	It("should count the endpoints beyond the limit per namespace", func() {
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-1")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-2")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-3")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-4")
+		// Existing series are still counted.
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-1")
+
+		Expect(testutil.CollectAndCount(metrics)).To(Equal(3))
+		Expect(count("default", "default.web", flowlog.ActionDeny, "prod", "web-1")).To(Equal(2.0))
+		Expect(count("default", "default.web", flowlog.ActionDeny, "prod", policyVerdictOverflowEndpoint)).To(Equal(2.0))
+	})
+
+	It("should delete the series of removed endpoints", func() {
+		metrics.recordVerdict("default", "default.web", flowlog.ActionAllow, "prod/web-1")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionDeny, "prod/web-1")
+		metrics.removeEndpoint("prod/web-1")
+		Expect(testutil.CollectAndCount(metrics)).To(Equal(0))
+
+		metrics.recordVerdict("default", "default.web", flowlog.ActionAllow, "prod/web-2")
+		metrics.recordVerdict("default", "default.web", flowlog.ActionAllow, "prod/web-3")
+		Expect(count("default", "default.web", flowlog.ActionAllow, "prod", "web-3")).To(Equal(1.0))
+	})
+
+	It("should count the verdicts of the flow log manager without flow logs", func() {
+		mgr := newFlowLogManager(nil, flowlog.Config{}, time.Minute, 42, false, false, metrics)
+		mgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+			Id: &proto.WorkloadEndpointID{
+				OrchestratorId: "k8s",
+				WorkloadId:     "prod/client",
+				EndpointId:     "eth0",
+			},
+			Endpoint: &proto.WorkloadEndpoint{Ipv4Nets: []string{"10.65.0.2/32"}},
+		})
+		mgr.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &proto.PolicyID{Tier: "default", Name: "default.egress"},
+			Policy: &proto.Policy{
+				OutboundRules: []*proto.Rule{{Action: "deny", RuleId: "rule-deny"}},
+			},
+		})
+
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionDeny, "rule-deny"),
+			Payload: flowLogTCPPacket("10.65.0.2", "10.65.0.3", 40000, 80),
+		})
+		mgr.OnPacket(nflog.Packet{
+			Prefix:  flowlog.NFLOGPrefix(flowlog.ActionDeny, ""),
+			Payload: flowLogTCPPacket("10.65.1.2", "10.65.0.2", 40000, 22),
+		})
+		Expect(count("default", "default.egress", flowlog.ActionDeny, "prod", "client")).To(Equal(1.0))
+		Expect(count("", flowlog.DefaultDenyPolicy, flowlog.ActionDeny, "prod", "client")).To(Equal(1.0))
+
+		mgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: &proto.WorkloadEndpointID{
+			OrchestratorId: "k8s",
+			WorkloadId:     "prod/client",
+			EndpointId:     "eth0",
+		}})
+		Expect(testutil.CollectAndCount(metrics)).To(Equal(0))
+	})
+})
@@ -1473,12 +1473,18 @@ func isValidRuleForXDP(rule *proto.Rule) bool {
 		// have no icmp stuff
 		rule.Icmp == nil &&
 		rule.NotIcmp == nil &&
+		len(rule.IcmpTypes) == 0 &&
+		len(rule.IcmpTypeCodes) == 0 &&
+		// have no DSCP match
+		rule.Dscp == nil &&
+		rule.NotDscp == nil &&
 		// have no destination stuff
 		len(rule.DstNet) == 0 &&
 		len(rule.DstPorts) == 0 &&
 		len(rule.DstNamedPortIpSetIds) == 0 &&
 		len(rule.DstIpSetIds) == 0 &&
 		len(rule.DstIpPortSetIds) == 0 &&
+		len(rule.DstDomainIpSetIds) == 0 &&
 		len(rule.NotDstNet) == 0 &&
 		len(rule.NotDstPorts) == 0 &&
 		len(rule.NotDstIpSetIds) == 0 &&
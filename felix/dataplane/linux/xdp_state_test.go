@@ -88,6 +88,10 @@ var knownProtoRuleFields = set.From(
 	"NotProtocol",
 	"Icmp",
 	"NotIcmp",
+	"IcmpTypes",
+	"IcmpTypeCodes",
+	"Dscp",
+	"NotDscp",
 	"Action",
 	"IpVersion",
 	"DstNet",
@@ -121,6 +125,11 @@ var knownProtoRuleFields = set.From(
 	"HttpMatch",
 	"Metadata",
 	"DstIpPortSetIds",
+	"DstDomainIpSetIds",
+	"LogTarget",
+	"LogSyslogFacility",
+	"LogRateLimit",
+	"LogRateLimitBurst",
 )
 
 func testAllProtoRuleFieldsAreKnown() {
@@ -1532,6 +1541,22 @@ var _ = Describe("XDP state", func() {
 							name: "notIcmpDefined",
 							rule: modifiedRule("NotIcmp", &proto.Rule_NotIcmpType{}),
 						},
+						{
+							name: "icmpTypesDefined",
+							rule: modifiedRule("IcmpTypes", []int32{8}),
+						},
+						{
+							name: "icmpTypeCodesDefined",
+							rule: modifiedRule("IcmpTypeCodes", []*proto.IcmpTypeAndCode{{Type: 8}}),
+						},
+						{
+							name: "dscpDefined",
+							rule: modifiedRule("Dscp", &proto.Rule_DscpValue{}),
+						},
+						{
+							name: "notDscpDefined",
+							rule: modifiedRule("NotDscp", &proto.Rule_NotDscpValue{}),
+						},
 						{
 							name: "srcNetDefined",
 							rule: modifiedRule("SrcNet", []string{"net"}),
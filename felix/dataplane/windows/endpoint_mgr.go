@@ -30,6 +30,7 @@ import (
 
 	"github.com/projectcalico/calico/felix/dataplane/windows/policysets"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
 )
 
@@ -237,10 +238,12 @@ func (m *endpointManager) refreshPendingWlEpUpdates(updatedPolicies []string) {
 		profilesApply := true
 
 		if len(workload.Tiers) > 0 {
-			activePolicyNames = append(activePolicyNames, prependAll(policysets.PolicyNamePrefix, workload.Tiers[0].IngressPolicies)...)
-			activePolicyNames = append(activePolicyNames, prependAll(policysets.PolicyNamePrefix, workload.Tiers[0].EgressPolicies)...)
+			ingressPolicies := enforcedPolicies(workload.Tiers[0].IngressPolicies)
+			egressPolicies := enforcedPolicies(workload.Tiers[0].EgressPolicies)
+			activePolicyNames = append(activePolicyNames, prependAll(policysets.PolicyNamePrefix, ingressPolicies)...)
+			activePolicyNames = append(activePolicyNames, prependAll(policysets.PolicyNamePrefix, egressPolicies)...)
 
-			if len(workload.Tiers[0].IngressPolicies) > 0 && len(workload.Tiers[0].EgressPolicies) > 0 {
+			if len(ingressPolicies) > 0 && len(egressPolicies) > 0 {
 				profilesApply = false
 			}
 		}
@@ -346,17 +349,23 @@ func (m *endpointManager) CompleteDeferredWork() error {
 
 			logCxt.Info("Processing endpoint add/update")
 
-			if len(workload.Tiers) > 0 && len(workload.Tiers[0].IngressPolicies) > 0 {
+			var ingressPolicies, egressPolicies []string
+			if len(workload.Tiers) > 0 {
+				ingressPolicies = enforcedPolicies(workload.Tiers[0].IngressPolicies)
+				egressPolicies = enforcedPolicies(workload.Tiers[0].EgressPolicies)
+			}
+
+			if len(ingressPolicies) > 0 {
 				logCxt.Debug("Workload Tier Policies will be applied Inbound")
-				inboundPolicyIds = append(inboundPolicyIds, prependAll(policysets.PolicyNamePrefix, workload.Tiers[0].IngressPolicies)...)
+				inboundPolicyIds = append(inboundPolicyIds, prependAll(policysets.PolicyNamePrefix, ingressPolicies)...)
 			} else if len(workload.ProfileIds) > 0 {
 				logCxt.Debug("Profiles will be applied Inbound")
 				inboundPolicyIds = append(inboundPolicyIds, prependAll(policysets.ProfileNamePrefix, workload.ProfileIds)...)
 			}
 
-			if len(workload.Tiers) > 0 && len(workload.Tiers[0].EgressPolicies) > 0 {
+			if len(egressPolicies) > 0 {
 				logCxt.Debug("Workload Tier Policies will be applied Outbound")
-				outboundPolicyIds = append(outboundPolicyIds, prependAll(policysets.PolicyNamePrefix, workload.Tiers[0].EgressPolicies)...)
+				outboundPolicyIds = append(outboundPolicyIds, prependAll(policysets.PolicyNamePrefix, egressPolicies)...)
 			} else if len(workload.ProfileIds) > 0 {
 				logCxt.Debug("Profiles will be applied Outbound")
 				outboundPolicyIds = append(outboundPolicyIds, prependAll(policysets.ProfileNamePrefix, workload.ProfileIds)...)
@@ -508,6 +517,17 @@ func (m *endpointManager) getHnsEndpointId(ip string) (string, error) {
 	return "", ErrorUnknownEndpoint
 }
 
+// enforcedPolicies filters out the staged policies, which the Windows dataplane does not
+// evaluate.
+func enforcedPolicies(in []string) (out []string) {
+	for _, s := range in {
+		if !model.PolicyIsStaged(s) {
+			out = append(out, s)
+		}
+	}
+	return
+}
+
 // prependAll prepends a string to all of the provided input strings
 func prependAll(prefix string, in []string) (out []string) {
 	for _, s := range in {
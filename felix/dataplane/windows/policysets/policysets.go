@@ -285,7 +285,7 @@ func (s *PolicySets) protoRulesToHnsRules(policyId string, protoRules []*proto.R
 // there are a few limitations to be aware of:
 //
 // The following types of rules are not supported in this release and will be logged+skipped:
-// Rules with: Negative match criteria, Actions other than 'allow' or 'deny'and ICMP type/codes.
+// Rules with: Negative match criteria, Actions other than 'allow' or 'deny', ICMP type/codes and DSCP.
 func (s *PolicySets) protoRuleToHnsRules(policyId string, pRule *proto.Rule, isInbound bool, ipPortsPerRule int) ([]*hns.ACLPolicy, error) {
 	log.WithField("policyId", policyId).Debug("protoRuleToHnsRules")
 
@@ -302,11 +302,17 @@ func (s *PolicySets) protoRuleToHnsRules(policyId string, pRule *proto.Rule, isI
 	}
 
 	// Skip rules with ICMP type/codes, these are not supported
-	if pRule.Icmp != nil {
+	if pRule.Icmp != nil || len(pRule.IcmpTypes) > 0 || len(pRule.IcmpTypeCodes) > 0 {
 		log.WithField("rule", pRule).Info("Skipping rule because it contains ICMP type or code (currently unsupported).")
 		return nil, ErrNotSupported
 	}
 
+	// Skip rules with DSCP matches, these are not supported
+	if pRule.Dscp != nil {
+		log.WithField("rule", pRule).Info("Skipping rule because it contains a DSCP match (currently unsupported).")
+		return nil, ErrNotSupported
+	}
+
 	// Skip rules with name port ipsets
 	if len(pRule.SrcNamedPortIpSetIds) > 0 || len(pRule.DstNamedPortIpSetIds) > 0 {
 		log.WithField("rule", pRule).Info("Skipping rule because it contains named port ipsets (currently unsupported).")
@@ -359,6 +365,11 @@ func (s *PolicySets) protoRuleToHnsRules(policyId string, pRule *proto.Rule, isI
 		logCxt.WithField("action", ruleCopy.Action).Panic("Unknown rule action")
 	}
 
+	if len(ruleCopy.DstDomainIpSetIds) > 0 {
+		logCxt.Info("Domain name matches are not supported, rule will be skipped")
+		return nil, ErrNotSupported
+	}
+
 	//
 	// DstIpPort sets - these cannot co-exist with other fields, so do them first and short-circuit if set.
 	//
@@ -647,6 +658,9 @@ func ruleHasNegativeMatches(pRule *proto.Rule) bool {
 	if pRule.NotIcmp != nil {
 		return true
 	}
+	if pRule.NotDscp != nil {
+		return true
+	}
 	return false
 }
 
@@ -653,6 +653,46 @@ func TestNegativeTestCases(t *testing.T) {
 		{Type: hns.ACL, Protocol: 256, Action: hns.Allow, Direction: hns.In, RuleType: hns.Host},
 	}), "unexpected rule with ICMP Type")
 
+	//Negative test: multiple ICMP types
+	ps.AddOrReplacePolicySet("icmp-types", &proto.Policy{
+		InboundRules: []*proto.Rule{
+			{
+				Action:    "Allow",
+				IcmpTypes: []int32{0, 8},
+				RuleId:    "rule-1",
+			},
+		},
+		OutboundRules: []*proto.Rule{},
+	})
+
+	Expect(ps.GetPolicySetRules([]string{"icmp-types"}, true)).To(Equal([]*hns.ACLPolicy{
+		//The rule with ICMP types should be skipped
+		// Default deny rule.
+		{Type: hns.ACL, Protocol: 256, Action: hns.Block, Direction: hns.In, RuleType: hns.Switch, Priority: 1001},
+		// Default host/pod
+		{Type: hns.ACL, Protocol: 256, Action: hns.Allow, Direction: hns.In, RuleType: hns.Host},
+	}), "unexpected rule with ICMP Types")
+
+	//Negative test: DSCP
+	ps.AddOrReplacePolicySet("dscp", &proto.Policy{
+		InboundRules: []*proto.Rule{
+			{
+				Action: "Allow",
+				Dscp:   &proto.Rule_DscpValue{DscpValue: 46},
+				RuleId: "rule-1",
+			},
+		},
+		OutboundRules: []*proto.Rule{},
+	})
+
+	Expect(ps.GetPolicySetRules([]string{"dscp"}, true)).To(Equal([]*hns.ACLPolicy{
+		//The rule with DSCP should be skipped
+		// Default deny rule.
+		{Type: hns.ACL, Protocol: 256, Action: hns.Block, Direction: hns.In, RuleType: hns.Switch, Priority: 1001},
+		// Default host/pod
+		{Type: hns.ACL, Protocol: 256, Action: hns.Allow, Direction: hns.In, RuleType: hns.Host},
+	}), "unexpected rule with DSCP")
+
 	//Negative test: With Negative Matches
 	ps.AddOrReplacePolicySet("negative-match", &proto.Policy{
 		InboundRules: []*proto.Rule{
@@ -1322,6 +1362,11 @@ func TestRuleHasNegativeMatches(t *testing.T) {
 		NotIcmp: &proto.Rule_NotIcmpType{NotIcmpType: 10},
 	})).To(Equal(true), "Unexpected result with NotIcmp")
 
+	Expect(ruleHasNegativeMatches(&proto.Rule{
+		Action:  "Allow",
+		NotDscp: &proto.Rule_NotDscpValue{NotDscpValue: 0},
+	})).To(Equal(true), "Unexpected result with NotDscp")
+
 }
 
 // Test of ProtocolNameToNumber()
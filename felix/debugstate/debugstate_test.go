@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugstate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/config"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+var _ = Describe("Debug state", func() {
+	var state *State
+
+	wepID := &proto.WorkloadEndpointID{
+		OrchestratorId: "k8s",
+		WorkloadId:     "default/client",
+		EndpointId:     "eth0",
+	}
+
+	BeforeEach(func() {
+		state = NewState(nil)
+		state.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &proto.PolicyID{Tier: "security", Name: "security.deny-ssh"},
+			Policy: &proto.Policy{
+				InboundRules: []*proto.Rule{{Action: "deny", RuleId: "rule-1"}},
+			},
+		})
+		state.OnUpdate(&proto.ActivePolicyUpdate{
+			Id: &proto.PolicyID{Tier: "default", Name: "default.allow-web"},
+			Policy: &proto.Policy{
+				InboundRules:  []*proto.Rule{{Action: "allow", RuleId: "rule-2"}},
+				OutboundRules: []*proto.Rule{{Action: "allow", RuleId: "rule-3"}},
+			},
+		})
+		state.OnUpdate(&proto.ActiveProfileUpdate{
+			Id:      &proto.ProfileID{Name: "kns.default"},
+			Profile: &proto.Profile{InboundRules: []*proto.Rule{{Action: "allow", RuleId: "rule-4"}}},
+		})
+		state.OnUpdate(&proto.WorkloadEndpointUpdate{
+			Id: wepID,
+			Endpoint: &proto.WorkloadEndpoint{
+				Name: "cali1234",
+				Tiers: []*proto.TierInfo{
+					{Name: "security", IngressPolicies: []string{"security.deny-ssh"}},
+					{
+						Name:            "default",
+						IngressPolicies: []string{"default.allow-web", "default.not-sent"},
+						EgressPolicies:  []string{"default.allow-web"},
+					},
+				},
+				ProfileIds: []string{"kns.default"},
+			},
+		})
+		state.OnUpdate(&proto.HostEndpointUpdate{
+			Id: &proto.HostEndpointID{EndpointId: "node1-eth0"},
+			Endpoint: &proto.HostEndpoint{
+				Name:         "eth0",
+				PreDnatTiers: []*proto.TierInfo{{Name: "security", IngressPolicies: []string{"security.deny-ssh"}}},
+			},
+		})
+	})
+
+	It("should list the endpoints", func() {
+		Expect(state.Endpoints()).To(Equal([]EndpointSummary{
+			{Type: EndpointTypeWorkload, ID: "k8s/default/client/eth0", Name: "cali1234"},
+			{Type: EndpointTypeHost, ID: "node1-eth0", Name: "eth0"},
+		}))
+	})
+
+	It("should resolve the tiers, policies and profiles of a workload", func() {
+		ep, ok := state.Endpoint(EndpointTypeWorkload, WorkloadEndpointKey(wepID))
+		Expect(ok).To(BeTrue())
+		Expect(ep.Tiers).To(HaveLen(2))
+		Expect(ep.Tiers[0].Name).To(Equal("security"))
+		Expect(ep.Tiers[0].Ingress[0].Rules[0].RuleId).To(Equal("rule-1"))
+		Expect(ep.Tiers[1].Ingress).To(Equal([]Policy{
+			{Name: "default.allow-web", Rules: []*proto.Rule{{Action: "allow", RuleId: "rule-2"}}},
+			{Name: "default.not-sent", Missing: true},
+		}))
+		Expect(ep.Tiers[1].Egress[0].Rules[0].RuleId).To(Equal("rule-3"))
+		Expect(ep.Profiles).To(Equal([]Profile{
+			{Name: "kns.default", InboundRules: []*proto.Rule{{Action: "allow", RuleId: "rule-4"}}},
+		}))
+	})
+
+	It("should resolve the pre-DNAT tiers of a host endpoint", func() {
+		ep, ok := state.Endpoint(EndpointTypeHost, "node1-eth0")
+		Expect(ok).To(BeTrue())
+		Expect(ep.Tiers).To(BeEmpty())
+		Expect(ep.PreDNATTiers).To(HaveLen(1))
+		Expect(ep.PreDNATTiers[0].Ingress[0].Name).To(Equal("security.deny-ssh"))
+	})
+
+	It("should forget removed endpoints", func() {
+		state.OnUpdate(&proto.WorkloadEndpointRemove{Id: wepID})
+		_, ok := state.Endpoint(EndpointTypeWorkload, WorkloadEndpointKey(wepID))
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("server", func() {
+		var handler http.Handler
+
+		BeforeEach(func() {
+			handler = NewServer(state, "localhost", 0, "s3cret").Handler()
+		})
+
+		get := func(url, token string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should reject requests without the token", func() {
+			Expect(get("/endpoints", "").Code).To(Equal(http.StatusUnauthorized))
+			Expect(get("/endpoints", "wrong").Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should serve the state of an endpoint", func() {
+			w := get("/endpoint?id=k8s/default/client/eth0", "s3cret")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var ep EndpointState
+			Expect(json.Unmarshal(w.Body.Bytes(), &ep)).To(Succeed())
+			Expect(ep.ID).To(Equal("k8s/default/client/eth0"))
+			Expect(ep.Tiers).To(HaveLen(2))
+
+			Expect(get("/endpoint?type=host&id=node1-eth0", "s3cret").Code).To(Equal(http.StatusOK))
+			Expect(get("/endpoint?id=k8s/default/other/eth0", "s3cret").Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should serve the config report once it has received the config", func() {
+			Expect(get("/config", "s3cret").Code).To(Equal(http.StatusServiceUnavailable))
+
+			state.OnUpdate(&proto.ConfigUpdate{
+				SourceToRawConfig: map[uint32]*proto.RawConfig{
+					uint32(config.DatastoreGlobal):     {Config: map[string]string{"BPFEnabled": "true"}},
+					uint32(config.EnvironmentVariable): {Config: map[string]string{"bpfenabled": "false"}},
+				},
+			})
+			w := get("/config", "s3cret")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var report []config.ParamReport
+			Expect(json.Unmarshal(w.Body.Bytes(), &report)).To(Succeed())
+			Expect(report).To(ContainElement(config.ParamReport{
+				Name:   "BPFEnabled",
+				Value:  "false",
+				Source: "environment variable",
+				Values: map[string]string{
+					"datastore (global)":   "true",
+					"environment variable": "false",
+				},
+				Drift: true,
+			}))
+		})
+	})
+
+	It("should read the token file", func() {
+		dir, err := os.MkdirTemp("", "debugstate")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "token")
+		Expect(os.WriteFile(path, []byte("s3cret\n"), 0600)).To(Succeed())
+		Expect(ReadTokenFile(path)).To(Equal("s3cret"))
+
+		Expect(os.WriteFile(path, []byte("\n"), 0600)).To(Succeed())
+		_, err = ReadTokenFile(path)
+		Expect(err).To(HaveOccurred())
+	})
+})
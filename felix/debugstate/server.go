@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugstate
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Server serves the State as JSON:
+//
+//	GET /endpoints                       lists the local endpoints.
+//	GET /endpoint?type=<type>&id=<id>    returns the policy of an endpoint, see EndpointState.
+//	GET /config                          returns the config parameters and the sources of their values.
+//
+// The state includes the rules of all the policies of the node, so every request must carry the
+// token as a bearer token in its Authorization header.
+type Server struct {
+	state *State
+	token []byte
+	addr  string
+}
+
+func NewServer(state *State, host string, port int, token string) *Server {
+	return &Server{
+		state: state,
+		token: []byte(token),
+		addr:  net.JoinHostPort(host, strconv.Itoa(port)),
+	}
+}
+
+// ReadTokenFile reads the token of the server from a file, ignoring surrounding whitespace.
+func ReadTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read debug state token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("debug state token file is empty")
+	}
+	return token, nil
+}
+
+func (s *Server) Start() {
+	log.WithField("addr", s.addr).Info("Starting debug state server.")
+	go func() {
+		for {
+			err := http.ListenAndServe(s.addr, s.Handler())
+			log.WithError(err).Error("Debug state HTTP server failed.  Will retry...")
+			time.Sleep(time.Second)
+		}
+	}()
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/endpoints", s.handleEndpoints)
+	mux.HandleFunc("/endpoint", s.handleEndpoint)
+	mux.HandleFunc("/config", s.handleConfig)
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+			log.WithField("remote", r.RemoteAddr).Warn("Unauthorized debug state request.")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleEndpoints(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.state.Endpoints())
+}
+
+func (s *Server) handleEndpoint(w http.ResponseWriter, r *http.Request) {
+	epType := r.URL.Query().Get("type")
+	if epType == "" {
+		epType = EndpointTypeWorkload
+	}
+	id := r.URL.Query().Get("id")
+	state, ok := s.state.Endpoint(epType, id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no %s endpoint %q", epType, id), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, state)
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	report := s.state.Config()
+	if report == nil {
+		http.Error(w, "config not received yet", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, report)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.WithError(err).Warn("Failed to write debug state response.")
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debugstate keeps the output of the calculation graph for the local endpoints and
+// serves it over HTTP, to answer why a packet is allowed or denied: which tiers and policies
+// apply to an endpoint, in which order, and their rules as the dataplane receives them.  It also
+// serves the effective Felix configuration with the source of each value, to answer which of
+// the FelixConfiguration resources, the config file and the environment variables sets a
+// parameter.
+package debugstate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/config"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+const (
+	EndpointTypeWorkload = "workload"
+	EndpointTypeHost     = "host"
+)
+
+// State tracks the endpoints, policies and profiles that the calculation graph sends to the
+// dataplane.  The updates arrive on the calculation graph's goroutine and the HTTP requests on
+// the server's goroutines, so the maps are protected by the lock.
+type State struct {
+	updates <-chan interface{}
+
+	lock          sync.Mutex
+	workloads     map[string]*proto.WorkloadEndpointUpdate
+	hostEndpoints map[string]*proto.HostEndpointUpdate
+	policies      map[proto.PolicyID]*proto.Policy
+	profiles      map[proto.ProfileID]*proto.Profile
+	config        []config.ParamReport
+	inSync        bool
+}
+
+func NewState(updates <-chan interface{}) *State {
+	return &State{
+		updates:       updates,
+		workloads:     map[string]*proto.WorkloadEndpointUpdate{},
+		hostEndpoints: map[string]*proto.HostEndpointUpdate{},
+		policies:      map[proto.PolicyID]*proto.Policy{},
+		profiles:      map[proto.ProfileID]*proto.Profile{},
+	}
+}
+
+func (s *State) Start() {
+	go s.loop()
+}
+
+func (s *State) loop() {
+	for msg := range s.updates {
+		s.OnUpdate(msg)
+	}
+}
+
+func (s *State) OnUpdate(msg interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch msg := msg.(type) {
+	case *proto.WorkloadEndpointUpdate:
+		s.workloads[WorkloadEndpointKey(msg.Id)] = msg
+	case *proto.WorkloadEndpointRemove:
+		delete(s.workloads, WorkloadEndpointKey(msg.Id))
+	case *proto.HostEndpointUpdate:
+		s.hostEndpoints[msg.Id.EndpointId] = msg
+	case *proto.HostEndpointRemove:
+		delete(s.hostEndpoints, msg.Id.EndpointId)
+	case *proto.ActivePolicyUpdate:
+		s.policies[*msg.Id] = msg.Policy
+	case *proto.ActivePolicyRemove:
+		delete(s.policies, *msg.Id)
+	case *proto.ActiveProfileUpdate:
+		s.profiles[*msg.Id] = msg.Profile
+	case *proto.ActiveProfileRemove:
+		delete(s.profiles, *msg.Id)
+	case *proto.ConfigUpdate:
+		cfg := config.New()
+		if _, err := cfg.UpdateFromConfigUpdate(msg); err != nil {
+			log.WithError(err).Warn("Failed to resolve config update for the debug state.")
+			return
+		}
+		s.config = cfg.Report()
+	case *proto.InSync:
+		log.Debug("Debug state in sync.")
+		s.inSync = true
+	}
+}
+
+// WorkloadEndpointKey returns the ID of a workload endpoint in the debug API,
+// "<orchestrator>/<workload>/<endpoint>"; for Kubernetes pods "k8s/<namespace>/<pod>/eth0".
+func WorkloadEndpointKey(id *proto.WorkloadEndpointID) string {
+	return fmt.Sprintf("%s/%s/%s", id.OrchestratorId, id.WorkloadId, id.EndpointId)
+}
+
+// EndpointSummary identifies an endpoint in the list of endpoints.
+type EndpointSummary struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Endpoints lists the local endpoints, workloads first.
+func (s *State) Endpoints() []EndpointSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	eps := make([]EndpointSummary, 0, len(s.workloads)+len(s.hostEndpoints))
+	for id, upd := range s.workloads {
+		eps = append(eps, EndpointSummary{Type: EndpointTypeWorkload, ID: id, Name: upd.Endpoint.Name})
+	}
+	for id, upd := range s.hostEndpoints {
+		eps = append(eps, EndpointSummary{Type: EndpointTypeHost, ID: id, Name: upd.Endpoint.Name})
+	}
+	sort.Slice(eps, func(i, j int) bool {
+		if eps[i].Type != eps[j].Type {
+			return eps[i].Type == EndpointTypeWorkload
+		}
+		return eps[i].ID < eps[j].ID
+	})
+	return eps
+}
+
+// Config returns the report of the config parameters of the last config update, nil before the
+// first one.
+func (s *State) Config() []config.ParamReport {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.config
+}
+
+// EndpointState is the policy of an endpoint as the dataplane enforces it.  The tiers are in
+// the order that they apply, each with the ordered policies of each direction; the profiles
+// apply after the tiers.  Host endpoints have separate tiers for the untracked, pre-DNAT and
+// forwarded traffic.
+type EndpointState struct {
+	Type     string      `json:"type"`
+	ID       string      `json:"id"`
+	InSync   bool        `json:"in_sync"`
+	Endpoint interface{} `json:"endpoint"`
+
+	Tiers          []Tier    `json:"tiers"`
+	UntrackedTiers []Tier    `json:"untracked_tiers,omitempty"`
+	PreDNATTiers   []Tier    `json:"pre_dnat_tiers,omitempty"`
+	ForwardTiers   []Tier    `json:"forward_tiers,omitempty"`
+	Profiles       []Profile `json:"profiles"`
+}
+
+type Tier struct {
+	Name    string   `json:"name"`
+	Ingress []Policy `json:"ingress,omitempty"`
+	Egress  []Policy `json:"egress,omitempty"`
+}
+
+// Policy is a policy of a tier with the rules of one direction.  Missing is set if the
+// calculation graph has not sent the policy, the dataplane then drops the traffic that reaches
+// it.
+type Policy struct {
+	Name      string        `json:"name"`
+	Untracked bool          `json:"untracked,omitempty"`
+	PreDNAT   bool          `json:"pre_dnat,omitempty"`
+	Missing   bool          `json:"missing,omitempty"`
+	Rules     []*proto.Rule `json:"rules"`
+}
+
+type Profile struct {
+	Name          string        `json:"name"`
+	Missing       bool          `json:"missing,omitempty"`
+	InboundRules  []*proto.Rule `json:"inbound_rules"`
+	OutboundRules []*proto.Rule `json:"outbound_rules"`
+}
+
+// Endpoint returns the state of the endpoint of the given type and ID, as listed by Endpoints.
+func (s *State) Endpoint(epType, id string) (*EndpointState, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	state := &EndpointState{Type: epType, ID: id, InSync: s.inSync}
+	var profileIDs []string
+	switch epType {
+	case EndpointTypeWorkload:
+		upd, ok := s.workloads[id]
+		if !ok {
+			return nil, false
+		}
+		state.Endpoint = upd.Endpoint
+		state.Tiers = s.tiersLocked(upd.Endpoint.Tiers)
+		profileIDs = upd.Endpoint.ProfileIds
+	case EndpointTypeHost:
+		upd, ok := s.hostEndpoints[id]
+		if !ok {
+			return nil, false
+		}
+		state.Endpoint = upd.Endpoint
+		state.Tiers = s.tiersLocked(upd.Endpoint.Tiers)
+		state.UntrackedTiers = s.tiersLocked(upd.Endpoint.UntrackedTiers)
+		state.PreDNATTiers = s.tiersLocked(upd.Endpoint.PreDnatTiers)
+		state.ForwardTiers = s.tiersLocked(upd.Endpoint.ForwardTiers)
+		profileIDs = upd.Endpoint.ProfileIds
+	default:
+		return nil, false
+	}
+
+	state.Profiles = []Profile{}
+	for _, name := range profileIDs {
+		p := Profile{Name: name}
+		if prof, ok := s.profiles[proto.ProfileID{Name: name}]; ok {
+			p.InboundRules = prof.InboundRules
+			p.OutboundRules = prof.OutboundRules
+		} else {
+			p.Missing = true
+		}
+		state.Profiles = append(state.Profiles, p)
+	}
+	return state, true
+}
+
+func (s *State) tiersLocked(tierInfos []*proto.TierInfo) []Tier {
+	tiers := []Tier{}
+	for _, ti := range tierInfos {
+		tier := Tier{Name: ti.Name}
+		for _, name := range ti.IngressPolicies {
+			tier.Ingress = append(tier.Ingress, s.policyLocked(ti.Name, name, true))
+		}
+		for _, name := range ti.EgressPolicies {
+			tier.Egress = append(tier.Egress, s.policyLocked(ti.Name, name, false))
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers
+}
+
+func (s *State) policyLocked(tier, name string, ingress bool) Policy {
+	p := Policy{Name: name}
+	pol, ok := s.policies[proto.PolicyID{Tier: tier, Name: name}]
+	if !ok {
+		p.Missing = true
+		return p
+	}
+	p.Untracked = pol.Untracked
+	p.PreDNAT = pol.PreDnat
+	if ingress {
+		p.Rules = pol.InboundRules
+	} else {
+		p.Rules = pol.OutboundRules
+	}
+	return p
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dnscache keeps the addresses of the domain names in the DNS responses that Felix
+// snoops, so that policy rules can match traffic to a domain name.
+package dnscache
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// minTTL is the shortest time for which a snooped record is kept.  Some DNS servers hand out
+	// very short TTLs, while clients often use an address for a little longer than that.
+	minTTL = 30 * time.Second
+
+	// maxCNAMEDepth bounds the CNAME chains that are followed, in case of a loop.
+	maxCNAMEDepth = 10
+
+	expiryInterval = time.Second
+)
+
+// Cache holds the A, AAAA and CNAME records of the snooped DNS responses until their TTL
+// expires.  It is safe for concurrent use; OnPacket is called on the NFLOG goroutine while the
+// dataplane reads the addresses.
+type Cache struct {
+	lock sync.Mutex
+	now  func() time.Time
+
+	// addrs maps each name to its addresses and their expiry.
+	addrs map[string]map[string]time.Time
+	// cnames maps each name to the names that it is an alias for and their expiry.
+	cnames map[string]map[string]time.Time
+
+	generation uint64
+	changes    chan struct{}
+}
+
+func New() *Cache {
+	return newWithClock(time.Now)
+}
+
+func newWithClock(now func() time.Time) *Cache {
+	return &Cache{
+		now:     now,
+		addrs:   map[string]map[string]time.Time{},
+		cnames:  map[string]map[string]time.Time{},
+		changes: make(chan struct{}, 1),
+	}
+}
+
+// Start expires the records in the background.
+func (c *Cache) Start() {
+	go func() {
+		for range time.Tick(expiryInterval) {
+			c.expire()
+		}
+	}()
+}
+
+// Changes returns a channel that receives a value after the addresses of some names change.
+// Several changes may be coalesced into one value.
+func (c *Cache) Changes() <-chan struct{} {
+	return c.changes
+}
+
+// Generation returns a counter that increases whenever the addresses of some names change.
+func (c *Cache) Generation() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.generation
+}
+
+// Response is a DNS response that Felix snooped.
+type Response struct {
+	// Server is the address of the DNS server and Client is the address that it responded to.
+	Server, Client net.IP
+	DNS            *layers.DNS
+}
+
+// ParseResponse decodes a snooped packet, which starts at the IP header.  It returns false if
+// the packet is not a UDP DNS response.
+func ParseResponse(payload []byte) (Response, bool) {
+	var first gopacket.LayerType
+	switch {
+	case len(payload) > 0 && payload[0]>>4 == 4:
+		first = layers.LayerTypeIPv4
+	case len(payload) > 0 && payload[0]>>4 == 6:
+		first = layers.LayerTypeIPv6
+	default:
+		return Response{}, false
+	}
+	pkt := gopacket.NewPacket(payload, first, gopacket.Lazy)
+	dnsLayer, ok := pkt.Layer(layers.LayerTypeDNS).(*layers.DNS)
+	if !ok || !dnsLayer.QR {
+		return Response{}, false
+	}
+	resp := Response{DNS: dnsLayer}
+	switch ip := pkt.NetworkLayer().(type) {
+	case *layers.IPv4:
+		resp.Server, resp.Client = ip.SrcIP, ip.DstIP
+	case *layers.IPv6:
+		resp.Server, resp.Client = ip.SrcIP, ip.DstIP
+	}
+	return resp, true
+}
+
+// OnPacket records the answers of a DNS response.  The packet starts at the IP header.
+// Packets that are not successful UDP DNS responses are ignored.
+func (c *Cache) OnPacket(payload []byte) {
+	resp, ok := ParseResponse(payload)
+	if !ok || resp.DNS.ResponseCode != layers.DNSResponseCodeNoErr {
+		log.Debug("Ignoring snooped packet that is not a successful DNS response.")
+		return
+	}
+	c.onAnswers(resp.DNS.Answers)
+}
+
+func (c *Cache) onAnswers(answers []layers.DNSResourceRecord) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	changed := false
+	for _, rr := range answers {
+		if rr.Class != layers.DNSClassIN {
+			continue
+		}
+		ttl := time.Duration(rr.TTL) * time.Second
+		if ttl < minTTL {
+			ttl = minTTL
+		}
+		expiry := c.now().Add(ttl)
+		name := normaliseName(string(rr.Name))
+		switch rr.Type {
+		case layers.DNSTypeA, layers.DNSTypeAAAA:
+			if rr.IP == nil {
+				continue
+			}
+			changed = record(c.addrs, name, rr.IP.String(), expiry) || changed
+		case layers.DNSTypeCNAME:
+			changed = record(c.cnames, name, normaliseName(string(rr.CNAME)), expiry) || changed
+		}
+	}
+	if changed {
+		c.onChangeLocked()
+	}
+}
+
+// record stores the value of a name, or extends its expiry, and returns whether it is new.
+func record(m map[string]map[string]time.Time, name, value string, expiry time.Time) bool {
+	values := m[name]
+	if values == nil {
+		values = map[string]time.Time{}
+		m[name] = values
+	}
+	old, ok := values[value]
+	if !ok || expiry.After(old) {
+		values[value] = expiry
+	}
+	return !ok
+}
+
+func (c *Cache) expire() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	changed := false
+	for _, m := range []map[string]map[string]time.Time{c.addrs, c.cnames} {
+		for name, values := range m {
+			for value, expiry := range values {
+				if now.After(expiry) {
+					delete(values, value)
+					changed = true
+				}
+			}
+			if len(values) == 0 {
+				delete(m, name)
+			}
+		}
+	}
+	if changed {
+		c.onChangeLocked()
+	}
+}
+
+func (c *Cache) onChangeLocked() {
+	c.generation++
+	select {
+	case c.changes <- struct{}{}:
+	default:
+	}
+}
+
+// IPsForDomains returns the sorted addresses of the given IP version that the domains resolve
+// to, following CNAMEs.  A domain of the form "*.example.com" matches the subdomains of
+// example.com, but not example.com itself.
+func (c *Cache) IPsForDomains(domains []string, ipVersion int) []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ips := map[string]struct{}{}
+	for _, d := range domains {
+		d = normaliseName(d)
+		if suffix, ok := strings.CutPrefix(d, "*"); ok {
+			for name := range c.addrs {
+				if strings.HasSuffix(name, suffix) {
+					c.collectIPs(name, ips, 0)
+				}
+			}
+			for name := range c.cnames {
+				if strings.HasSuffix(name, suffix) {
+					c.collectIPs(name, ips, 0)
+				}
+			}
+			continue
+		}
+		c.collectIPs(d, ips, 0)
+	}
+
+	var out []string
+	for ip := range ips {
+		isV4 := net.ParseIP(ip).To4() != nil
+		if isV4 == (ipVersion == 4) {
+			out = append(out, ip)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (c *Cache) collectIPs(name string, ips map[string]struct{}, depth int) {
+	if depth > maxCNAMEDepth {
+		return
+	}
+	for ip := range c.addrs[name] {
+		ips[ip] = struct{}{}
+	}
+	for target := range c.cnames[name] {
+		c.collectIPs(target, ips, depth+1)
+	}
+}
+
+func normaliseName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
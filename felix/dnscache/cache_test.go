@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscache
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func dnsResponse(rcode layers.DNSResponseCode, answers ...layers.DNSResourceRecord) []byte {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.ParseIP("10.96.0.10"),
+		DstIP:    net.ParseIP("10.65.0.2"),
+	}
+	udp := &layers.UDP{SrcPort: 53, DstPort: 34567}
+	_ = udp.SetNetworkLayerForChecksum(ip)
+	dns := &layers.DNS{
+		ID:           1,
+		QR:           true,
+		ResponseCode: rcode,
+		Answers:      answers,
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true},
+		ip, udp, dns)
+	Expect(err).NotTo(HaveOccurred())
+	return buf.Bytes()
+}
+
+func a(name, ip string, ttl uint32) layers.DNSResourceRecord {
+	typ := layers.DNSTypeA
+	if net.ParseIP(ip).To4() == nil {
+		typ = layers.DNSTypeAAAA
+	}
+	return layers.DNSResourceRecord{
+		Name:  []byte(name),
+		Type:  typ,
+		Class: layers.DNSClassIN,
+		TTL:   ttl,
+		IP:    net.ParseIP(ip),
+	}
+}
+
+func cname(name, target string, ttl uint32) layers.DNSResourceRecord {
+	return layers.DNSResourceRecord{
+		Name:  []byte(name),
+		Type:  layers.DNSTypeCNAME,
+		Class: layers.DNSClassIN,
+		TTL:   ttl,
+		CNAME: []byte(target),
+	}
+}
+
+var _ = Describe("DNS cache", func() {
+	var (
+		now   time.Time
+		cache *Cache
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache = newWithClock(func() time.Time { return now })
+	})
+
+	It("should record the addresses of a response", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr,
+			a("api.github.com", "140.82.112.5", 60),
+			a("api.github.com", "2606:50c0::5", 60),
+		))
+		Expect(cache.IPsForDomains([]string{"api.github.com"}, 4)).To(Equal([]string{"140.82.112.5"}))
+		Expect(cache.IPsForDomains([]string{"api.github.com"}, 6)).To(Equal([]string{"2606:50c0::5"}))
+		Expect(cache.Generation()).To(BeNumerically("==", 1))
+		Expect(cache.Changes()).To(Receive())
+	})
+
+	It("should follow CNAMEs", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr,
+			cname("www.example.com", "cdn.example.net", 60),
+			cname("cdn.example.net", "edge.example.net", 60),
+			a("edge.example.net", "192.0.2.1", 60),
+		))
+		Expect(cache.IPsForDomains([]string{"www.example.com"}, 4)).To(Equal([]string{"192.0.2.1"}))
+	})
+
+	It("should match the subdomains of a wildcard but not the domain itself", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr,
+			a("github.com", "140.82.112.3", 60),
+			a("api.github.com", "140.82.112.5", 60),
+			a("raw.github.com", "140.82.112.6", 60),
+			a("notgithub.com", "192.0.2.1", 60),
+		))
+		Expect(cache.IPsForDomains([]string{"*.github.com"}, 4)).To(Equal([]string{"140.82.112.5", "140.82.112.6"}))
+	})
+
+	It("should ignore the case and trailing dot of names", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr, a("API.GitHub.com", "140.82.112.5", 60)))
+		Expect(cache.IPsForDomains([]string{"api.github.com."}, 4)).To(Equal([]string{"140.82.112.5"}))
+	})
+
+	It("should ignore failed responses", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNXDomain, a("api.github.com", "140.82.112.5", 60)))
+		Expect(cache.IPsForDomains([]string{"api.github.com"}, 4)).To(BeEmpty())
+		Expect(cache.Generation()).To(BeZero())
+	})
+
+	It("should ignore packets that are not DNS", func() {
+		cache.OnPacket([]byte{0x45, 0, 0})
+		cache.OnPacket(nil)
+		Expect(cache.Generation()).To(BeZero())
+	})
+
+	It("should not count a refresh of a known address as a change", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr, a("api.github.com", "140.82.112.5", 60)))
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr, a("api.github.com", "140.82.112.5", 60)))
+		Expect(cache.Generation()).To(BeNumerically("==", 1))
+	})
+
+	It("should expire records after their TTL, but no sooner than the minimum", func() {
+		cache.OnPacket(dnsResponse(layers.DNSResponseCodeNoErr,
+			a("api.github.com", "140.82.112.5", 300),
+			a("raw.github.com", "140.82.112.6", 1),
+		))
+
+		now = now.Add(minTTL - time.Second)
+		cache.expire()
+		Expect(cache.IPsForDomains([]string{"*.github.com"}, 4)).To(Equal([]string{"140.82.112.5", "140.82.112.6"}))
+
+		now = now.Add(2 * time.Second)
+		cache.expire()
+		Expect(cache.IPsForDomains([]string{"*.github.com"}, 4)).To(Equal([]string{"140.82.112.5"}))
+		Expect(cache.Generation()).To(BeNumerically("==", 2))
+
+		now = now.Add(300 * time.Second)
+		cache.expire()
+		Expect(cache.IPsForDomains([]string{"*.github.com"}, 4)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ParseResponse", func() {
+	It("should return the server, client and DNS layer of a response", func() {
+		resp, ok := ParseResponse(dnsResponse(layers.DNSResponseCodeNXDomain))
+		Expect(ok).To(BeTrue())
+		Expect(resp.Server.String()).To(Equal("10.96.0.10"))
+		Expect(resp.Client.String()).To(Equal("10.65.0.2"))
+		Expect(resp.DNS.ResponseCode).To(Equal(layers.DNSResponseCodeNXDomain))
+	})
+
+	It("should reject packets that are not DNS", func() {
+		_, ok := ParseResponse([]byte{0x45, 0, 0})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/dnscache"
+)
+
+var _ = Describe("NewEntry", func() {
+	It("should record the question and answers of a response", func() {
+		t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		e := NewEntry(t, dnscache.Response{
+			Server: net.ParseIP("10.96.0.10"),
+			Client: net.ParseIP("10.65.0.2"),
+			DNS: &layers.DNS{
+				QR:           true,
+				ResponseCode: layers.DNSResponseCodeNoErr,
+				Questions: []layers.DNSQuestion{
+					{Name: []byte("WWW.Example.com."), Type: layers.DNSTypeA, Class: layers.DNSClassIN},
+				},
+				Answers: []layers.DNSResourceRecord{
+					{Name: []byte("www.example.com"), Type: layers.DNSTypeCNAME, CNAME: []byte("edge.example.net.")},
+					{Name: []byte("edge.example.net"), Type: layers.DNSTypeA, IP: net.ParseIP("93.184.216.35")},
+					{Name: []byte("edge.example.net"), Type: layers.DNSTypeA, IP: net.ParseIP("93.184.216.34")},
+				},
+			},
+		})
+		Expect(e).To(Equal(Entry{
+			Time:     t,
+			ClientIP: "10.65.0.2",
+			ServerIP: "10.96.0.10",
+			QName:    "www.example.com",
+			QType:    "A",
+			RCode:    "No Error",
+			IPs:      []string{"93.184.216.34", "93.184.216.35"},
+			CNAMEs:   []string{"edge.example.net"},
+		}))
+	})
+})
+
+type recordingExporter struct {
+	lines chan string
+}
+
+func (r *recordingExporter) Export(line []byte) error {
+	r.lines <- string(line)
+	return nil
+}
+
+func (r *recordingExporter) Close() {}
+
+var _ = Describe("Logger", func() {
+	It("should export each log as a line of JSON", func() {
+		exp := &recordingExporter{lines: make(chan string, 1)}
+		l := NewLogger(exp)
+		l.Start()
+		l.Log(Entry{ClientIP: "10.65.0.2", ClientWorkload: "default/pod-1", QName: "example.com"})
+
+		var line string
+		Eventually(exp.lines).Should(Receive(&line))
+		Expect(line).To(HaveSuffix("\n"))
+		var e Entry
+		Expect(json.Unmarshal([]byte(line), &e)).To(Succeed())
+		Expect(e.ClientWorkload).To(Equal("default/pod-1"))
+		Expect(e.QName).To(Equal("example.com"))
+	})
+
+	It("should drop logs rather than block when the queue is full", func() {
+		l := NewLogger(&recordingExporter{})
+		for i := 0; i < queueLen+10; i++ {
+			l.Log(Entry{})
+		}
+		Expect(l.dropped.Load()).To(BeNumerically("==", 10))
+	})
+})
+
+var _ = Describe("Exporters", func() {
+	It("should append to the file", func() {
+		dir, err := os.MkdirTemp("", "dnslog")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "dns", "dns.log")
+
+		f := NewFileExporter(path, 1, 1)
+		Expect(f.Export([]byte("one\n"))).To(Succeed())
+		Expect(f.Export([]byte("two\n"))).To(Succeed())
+		f.Close()
+		Expect(os.ReadFile(path)).To(Equal([]byte("one\ntwo\n")))
+	})
+
+	It("should stream to the collector and reconnect after an error", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer lis.Close()
+		received := make(chan string, 10)
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					s := bufio.NewScanner(conn)
+					for s.Scan() {
+						received <- s.Text()
+					}
+				}()
+			}
+		}()
+
+		c := NewCollectorExporter(lis.Addr().String())
+		Expect(c.Export([]byte("one\n"))).To(Succeed())
+		Eventually(received).Should(Receive(Equal("one")))
+
+		// Simulate a broken connection.
+		_ = c.conn.Close()
+		Expect(c.Export([]byte("lost\n"))).NotTo(Succeed())
+		Expect(c.conn).To(BeNil())
+		Expect(c.Export([]byte("two\n"))).To(Succeed())
+		Eventually(received).Should(Receive(Equal("two")))
+	})
+})
@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dnslog records which workload resolved which domain name to which addresses, from the
+// DNS responses that Felix snoops for domain name policy.
+package dnslog
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket/layers"
+
+	"github.com/projectcalico/calico/felix/dnscache"
+)
+
+// Entry is one DNS log, which is written as a line of JSON.
+type Entry struct {
+	Time time.Time `json:"time"`
+
+	// ClientIP is the address that the response was sent to.  ClientWorkload and
+	// ClientEndpoint identify the local workload endpoint with that address, if there is one;
+	// for Kubernetes pods ClientWorkload is "<namespace>/<pod name>".
+	ClientIP       string `json:"client_ip"`
+	ClientWorkload string `json:"client_workload,omitempty"`
+	ClientEndpoint string `json:"client_endpoint,omitempty"`
+
+	ServerIP string `json:"server_ip"`
+
+	QName string `json:"qname"`
+	QType string `json:"qtype"`
+	RCode string `json:"rcode"`
+
+	// IPs are the addresses in the A and AAAA records of the response, and CNAMEs the targets
+	// of its CNAME records.
+	IPs    []string `json:"ips,omitempty"`
+	CNAMEs []string `json:"cnames,omitempty"`
+}
+
+// NewEntry returns the log of a snooped response, without the client workload.
+func NewEntry(t time.Time, resp dnscache.Response) Entry {
+	e := Entry{
+		Time:     t,
+		ClientIP: resp.Client.String(),
+		ServerIP: resp.Server.String(),
+		RCode:    resp.DNS.ResponseCode.String(),
+	}
+	if len(resp.DNS.Questions) > 0 {
+		q := resp.DNS.Questions[0]
+		e.QName = normaliseName(string(q.Name))
+		e.QType = q.Type.String()
+	}
+	for _, rr := range resp.DNS.Answers {
+		switch rr.Type {
+		case layers.DNSTypeA, layers.DNSTypeAAAA:
+			if rr.IP != nil {
+				e.IPs = append(e.IPs, rr.IP.String())
+			}
+		case layers.DNSTypeCNAME:
+			e.CNAMEs = append(e.CNAMEs, normaliseName(string(rr.CNAME)))
+		}
+	}
+	sort.Strings(e.IPs)
+	return e
+}
+
+func normaliseName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
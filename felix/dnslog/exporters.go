@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnslog
+
+import (
+	"net"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileExporter appends the logs to a file, which it rotates once it reaches a maximum size.
+type FileExporter struct {
+	file *lumberjack.Logger
+}
+
+// NewFileExporter returns an exporter that writes to path, keeping at most maxFiles rotated
+// files of at most maxSizeMB megabytes.
+func NewFileExporter(path string, maxSizeMB, maxFiles int) *FileExporter {
+	return &FileExporter{
+		file: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxFiles,
+		},
+	}
+}
+
+func (f *FileExporter) Export(line []byte) error {
+	_, err := f.file.Write(line)
+	return err
+}
+
+func (f *FileExporter) Close() {
+	_ = f.file.Close()
+}
+
+const (
+	collectorDialTimeout  = 5 * time.Second
+	collectorWriteTimeout = 5 * time.Second
+)
+
+// CollectorExporter streams the logs to a collector over TCP.  It connects on first use and
+// reconnects after an error; the logs exported while the collector is unreachable are lost.
+type CollectorExporter struct {
+	addr string
+	conn net.Conn
+	dial func(network, addr string, timeout time.Duration) (net.Conn, error)
+}
+
+// NewCollectorExporter returns an exporter that sends to addr, in host:port form.
+func NewCollectorExporter(addr string) *CollectorExporter {
+	return &CollectorExporter{
+		addr: addr,
+		dial: net.DialTimeout,
+	}
+}
+
+func (c *CollectorExporter) Export(line []byte) error {
+	if c.conn == nil {
+		conn, err := c.dial("tcp", c.addr, collectorDialTimeout)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(collectorWriteTimeout))
+	if _, err := c.conn.Write(line); err != nil {
+		c.Close()
+		return err
+	}
+	return nil
+}
+
+func (c *CollectorExporter) Close() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
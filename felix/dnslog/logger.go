@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnslog
+
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// queueLen is the number of logs that can wait for the exporter before new logs are dropped.
+const queueLen = 1000
+
+// Exporter writes DNS logs, each of which is a line of JSON, to their destination.
+type Exporter interface {
+	Export(line []byte) error
+	Close()
+}
+
+// Logger queues DNS logs for an exporter so that a slow destination never holds up DNS
+// snooping.  Logs are dropped while the queue is full.
+type Logger struct {
+	exporter Exporter
+	queue    chan Entry
+	dropped  atomic.Uint64
+}
+
+func NewLogger(exporter Exporter) *Logger {
+	return &Logger{
+		exporter: exporter,
+		queue:    make(chan Entry, queueLen),
+	}
+}
+
+// Start exports the queued logs in the background.
+func (l *Logger) Start() {
+	go l.loop()
+}
+
+// Log queues a log without blocking.
+func (l *Logger) Log(e Entry) {
+	select {
+	case l.queue <- e:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+func (l *Logger) loop() {
+	for e := range l.queue {
+		l.export(e)
+	}
+}
+
+func (l *Logger) export(e Entry) {
+	if n := l.dropped.Swap(0); n > 0 {
+		log.WithField("count", n).Warn("Dropped DNS logs because they could not be exported fast enough.")
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).Error("Failed to encode DNS log.")
+		return
+	}
+	if err := l.exporter.Export(append(line, '\n')); err != nil {
+		log.WithError(err).Warn("Failed to export DNS log.")
+	}
+}
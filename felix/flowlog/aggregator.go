@@ -0,0 +1,374 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowlog
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"net/netip"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Aggregation string
+
+const (
+	// AggregationNone logs each connection separately.
+	AggregationNone Aggregation = "None"
+	// AggregationSourcePort aggregates the connections that only differ by their source port,
+	// such as the connections of a client to a service.
+	AggregationSourcePort Aggregation = "SourcePort"
+)
+
+// maxMissedPolls is the number of conntrack polls that an allowed connection may be missing
+// from before it is considered to have ended, for the connections that ended before they were
+// first seen in the table.
+const maxMissedPolls = 2
+
+// Tuple identifies a connection by its first packet as policy sees it: after DNAT and before
+// SNAT.
+type Tuple struct {
+	Proto   uint8
+	Src     netip.Addr
+	Dst     netip.Addr
+	SrcPort uint16
+	DstPort uint16
+}
+
+type Counters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// ConntrackEntry is a connection in the conntrack table, with the cumulative counters of the
+// packets sent by its source and by its destination.  KTLS is set if a socket of the connection
+// on this host uses kernel TLS; the counters then include those of the socket where they are
+// higher, since a NIC that runs the TCP connection bypasses conntrack.
+type ConntrackEntry struct {
+	Tuple  Tuple
+	Source Counters
+	Dest   Counters
+	KTLS   bool
+}
+
+// EndpointLookup identifies the local workload endpoint with an IP.
+type EndpointLookup interface {
+	LookupEndpoint(addr netip.Addr) (workload, endpoint string, ok bool)
+}
+
+type Config struct {
+	Aggregation Aggregation
+	// SampleRate logs one in every SampleRate connections.  They are chosen by a hash of
+	// their tuple so that a connection is either always or never logged.
+	SampleRate int
+}
+
+type conn struct {
+	action   Action
+	policies map[string]struct{}
+
+	// lastSource and lastDest are the counters of the conntrack entry at the previous poll;
+	// source and dest are the traffic since the previous flush.
+	lastSource, lastDest Counters
+	source, dest         Counters
+	ktls                 bool
+
+	inConntrack bool
+	lastPoll    uint64
+	missedPolls int
+
+	// active is set if the connection started, ended or passed traffic since the previous
+	// flush.
+	active    bool
+	started   bool
+	completed bool
+}
+
+type flowKey struct {
+	Tuple
+	action Action
+}
+
+// Aggregator tracks the connections of the local workloads and aggregates them into flow logs.
+// Verdicts arrive on the NFLOG goroutine while the conntrack table is polled on another, so
+// all of its methods lock.
+//
+// Allowed connections are tracked until they leave the conntrack table.  Denied connections
+// never enter it, their packets are counted from the verdicts, and they are forgotten after a
+// flush interval without packets; they never count as completed.
+type Aggregator struct {
+	config Config
+	lookup EndpointLookup
+
+	lock      sync.Mutex
+	conns     map[Tuple]*conn
+	poll      uint64
+	lastFlush time.Time
+}
+
+func NewAggregator(config Config, lookup EndpointLookup, now time.Time) *Aggregator {
+	return &Aggregator{
+		config:    config,
+		lookup:    lookup,
+		conns:     map[Tuple]*conn{},
+		lastFlush: now,
+	}
+}
+
+// Verdict records that a policy rule allowed the first packet of a connection, or denied one of
+// its packets.  length is the length of the packet.  policy is empty if the rule is unknown.
+func (a *Aggregator) Verdict(t Tuple, action Action, policy string, length int) {
+	if !a.sampled(t) {
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	c := a.connLocked(t)
+	if action == ActionDeny {
+		c.action = ActionDeny
+		c.source.Packets++
+		c.source.Bytes += uint64(length)
+	}
+	if policy != "" {
+		c.policies[policy] = struct{}{}
+	}
+	c.active = true
+}
+
+// UpdateConntrack updates the counters of the connections from a dump of the conntrack table.
+// Connections that are not in the table have ended.
+func (a *Aggregator) UpdateConntrack(entries []ConntrackEntry) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.poll++
+	for _, e := range entries {
+		if !a.sampled(e.Tuple) {
+			continue
+		}
+		c := a.conns[e.Tuple]
+		if c == nil {
+			// Connections that started before the verdicts were reported, such as those
+			// from before Felix started, are logged if they belong to a local workload.
+			if !a.isLocal(e.Tuple.Src) && !a.isLocal(e.Tuple.Dst) {
+				continue
+			}
+			c = a.connLocked(e.Tuple)
+			c.started = false
+		}
+		if c.action == ActionDeny {
+			continue
+		}
+		if c.ktls && !e.KTLS {
+			// The socket closed before the conntrack entry expired, its counters must not
+			// look like a new conntrack entry.
+			e.Source, e.Dest = maxCounters(e.Source, c.lastSource), maxCounters(e.Dest, c.lastDest)
+		}
+		c.ktls = c.ktls || e.KTLS
+		src, dst := delta(e.Source, c.lastSource), delta(e.Dest, c.lastDest)
+		if src.Packets > 0 || dst.Packets > 0 {
+			c.active = true
+		}
+		c.source.add(src)
+		c.dest.add(dst)
+		c.lastSource, c.lastDest = e.Source, e.Dest
+		c.inConntrack = true
+		c.lastPoll = a.poll
+		c.missedPolls = 0
+	}
+
+	for _, c := range a.conns {
+		if c.action == ActionDeny || c.completed || c.lastPoll == a.poll {
+			continue
+		}
+		c.missedPolls++
+		if c.inConntrack || c.missedPolls >= maxMissedPolls {
+			c.completed = true
+			c.active = true
+		}
+	}
+}
+
+// Flush returns the flow logs of the connections that were active since the previous flush.
+func (a *Aggregator) Flush(now time.Time) []Record {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	flows := map[flowKey]*Record{}
+	policies := map[flowKey]map[string]struct{}{}
+	for t, c := range a.conns {
+		if c.active {
+			key := flowKey{Tuple: t, action: c.action}
+			if a.config.Aggregation == AggregationSourcePort {
+				key.SrcPort = 0
+			}
+			r := flows[key]
+			if r == nil {
+				r = &Record{
+					StartTime: a.lastFlush,
+					EndTime:   now,
+					Proto:     protoName(t.Proto),
+					Source:    a.endpoint(key.Src, key.SrcPort),
+					Dest:      a.endpoint(key.Dst, key.DstPort),
+					Action:    c.action,
+				}
+				flows[key] = r
+				policies[key] = map[string]struct{}{}
+			}
+			r.NumFlows++
+			if c.started {
+				r.NumFlowsStarted++
+			}
+			if c.completed {
+				r.NumFlowsCompleted++
+			}
+			r.SourcePackets += c.source.Packets
+			r.SourceBytes += c.source.Bytes
+			r.DestPackets += c.dest.Packets
+			r.DestBytes += c.dest.Bytes
+			r.KTLS = r.KTLS || c.ktls
+			for p := range c.policies {
+				policies[key][p] = struct{}{}
+			}
+		}
+
+		if c.completed || (c.action == ActionDeny && !c.active) {
+			delete(a.conns, t)
+			continue
+		}
+		c.active = false
+		c.started = false
+		c.source = Counters{}
+		c.dest = Counters{}
+	}
+	a.lastFlush = now
+
+	records := make([]Record, 0, len(flows))
+	for key, r := range flows {
+		for p := range policies[key] {
+			r.Policies = append(r.Policies, p)
+		}
+		sort.Strings(r.Policies)
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		ri, rj := records[i], records[j]
+		if ri.Source.IP != rj.Source.IP {
+			return ri.Source.IP < rj.Source.IP
+		}
+		if ri.Dest.IP != rj.Dest.IP {
+			return ri.Dest.IP < rj.Dest.IP
+		}
+		if ri.Dest.Port != rj.Dest.Port {
+			return ri.Dest.Port < rj.Dest.Port
+		}
+		if ri.Source.Port != rj.Source.Port {
+			return ri.Source.Port < rj.Source.Port
+		}
+		if ri.Proto != rj.Proto {
+			return ri.Proto < rj.Proto
+		}
+		return ri.Action < rj.Action
+	})
+	return records
+}
+
+func (a *Aggregator) connLocked(t Tuple) *conn {
+	c := a.conns[t]
+	if c == nil {
+		c = &conn{
+			action:   ActionAllow,
+			policies: map[string]struct{}{},
+			lastPoll: a.poll,
+			started:  true,
+			active:   true,
+		}
+		a.conns[t] = c
+	}
+	return c
+}
+
+func (a *Aggregator) sampled(t Tuple) bool {
+	if a.config.SampleRate <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	src, dst := t.Src.As16(), t.Dst.As16()
+	_, _ = h.Write(src[:])
+	_, _ = h.Write(dst[:])
+	var b [5]byte
+	b[0] = t.Proto
+	binary.BigEndian.PutUint16(b[1:3], t.SrcPort)
+	binary.BigEndian.PutUint16(b[3:5], t.DstPort)
+	_, _ = h.Write(b[:])
+	return h.Sum32()%uint32(a.config.SampleRate) == 0
+}
+
+func (a *Aggregator) isLocal(addr netip.Addr) bool {
+	_, _, ok := a.lookup.LookupEndpoint(addr)
+	return ok
+}
+
+func (a *Aggregator) endpoint(addr netip.Addr, port uint16) Endpoint {
+	ep := Endpoint{
+		Type: EndpointTypeNetwork,
+		IP:   addr.String(),
+		Port: port,
+	}
+	if workload, endpoint, ok := a.lookup.LookupEndpoint(addr); ok {
+		ep.Type = EndpointTypeWorkload
+		ep.Workload = workload
+		ep.Endpoint = endpoint
+	}
+	return ep
+}
+
+func (c *Counters) add(o Counters) {
+	c.Packets += o.Packets
+	c.Bytes += o.Bytes
+}
+
+func maxCounters(a, b Counters) Counters {
+	return Counters{Packets: max(a.Packets, b.Packets), Bytes: max(a.Bytes, b.Bytes)}
+}
+
+// delta returns the traffic since the last poll, or all of it if the counters went backwards
+// because the conntrack entry was replaced.
+func delta(cur, last Counters) Counters {
+	if cur.Packets < last.Packets || cur.Bytes < last.Bytes {
+		return cur
+	}
+	return Counters{Packets: cur.Packets - last.Packets, Bytes: cur.Bytes - last.Bytes}
+}
+
+func protoName(p uint8) string {
+	switch p {
+	case 1:
+		return "icmp"
+	case 6:
+		return "tcp"
+	case 17:
+		return "udp"
+	case 58:
+		return "icmp6"
+	case 132:
+		return "sctp"
+	}
+	return strconv.Itoa(int(p))
+}
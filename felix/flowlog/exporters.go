@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Exporter writes the flow logs of a flush interval to their destination.
+type Exporter interface {
+	Export(records []Record) error
+	Close()
+}
+
+// FileExporter appends the logs to a file as lines of JSON, rotating the file once it reaches
+// a maximum size.
+type FileExporter struct {
+	file *lumberjack.Logger
+}
+
+// NewFileExporter returns an exporter that writes to path, keeping at most maxFiles rotated
+// files of at most maxSizeMB megabytes.
+func NewFileExporter(path string, maxSizeMB, maxFiles int) *FileExporter {
+	return &FileExporter{
+		file: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxFiles,
+		},
+	}
+}
+
+func (f *FileExporter) Export(records []Record) error {
+	var buf []byte
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	_, err := f.file.Write(buf)
+	return err
+}
+
+func (f *FileExporter) Close() {
+	_ = f.file.Close()
+}
+
+const (
+	otlpScopeName     = "felix.flowlog"
+	otlpExportTimeout = 10 * time.Second
+)
+
+// OTLPExporter sends the logs to an OpenTelemetry collector with the OTLP/gRPC logs service.
+// The body of each OTLP log record is the flow log as JSON.  The connection is not encrypted,
+// the collector is expected to be local to the node or cluster.
+type OTLPExporter struct {
+	conn     *grpc.ClientConn
+	client   collogspb.LogsServiceClient
+	resource *resourcepb.Resource
+}
+
+// NewOTLPExporter returns an exporter that sends to addr, in host:port form.  hostname is
+// recorded as the host.name attribute of the resource.  It connects in the background.
+func NewOTLPExporter(addr, hostname string) (*OTLPExporter, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial OTLP collector %s: %w", addr, err)
+	}
+	return &OTLPExporter{
+		conn:   conn,
+		client: collogspb.NewLogsServiceClient(conn),
+		resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{
+				stringAttribute("service.name", "calico-felix"),
+				stringAttribute("host.name", hostname),
+			},
+		},
+	}, nil
+}
+
+func (o *OTLPExporter) Export(records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	req, err := o.request(records, time.Now())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+	_, err = o.client.Export(ctx, req)
+	return err
+}
+
+func (o *OTLPExporter) request(records []Record, now time.Time) (*collogspb.ExportLogsServiceRequest, error) {
+	logRecords := make([]*logspb.LogRecord, 0, len(records))
+	for _, r := range records {
+		body, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		logRecords = append(logRecords, &logspb.LogRecord{
+			TimeUnixNano:         uint64(r.EndTime.UnixNano()),
+			ObservedTimeUnixNano: uint64(now.UnixNano()),
+			SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+			Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: string(body)}},
+			Attributes: []*commonpb.KeyValue{
+				stringAttribute("flow.action", string(r.Action)),
+				stringAttribute("flow.source.ip", r.Source.IP),
+				stringAttribute("flow.dest.ip", r.Dest.IP),
+			},
+		})
+	}
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: o.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: otlpScopeName},
+				LogRecords: logRecords,
+			}},
+		}},
+	}, nil
+}
+
+func (o *OTLPExporter) Close() {
+	_ = o.conn.Close()
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
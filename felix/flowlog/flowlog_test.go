@@ -0,0 +1,330 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+)
+
+type mockLookup map[netip.Addr][2]string
+
+func (m mockLookup) LookupEndpoint(addr netip.Addr) (string, string, bool) {
+	ep, ok := m[addr]
+	return ep[0], ep[1], ok
+}
+
+var (
+	client = netip.MustParseAddr("10.65.0.2")
+	server = netip.MustParseAddr("10.65.0.3")
+	remote = netip.MustParseAddr("8.8.8.8")
+
+	allowEgress  = PolicyHit("default", "default.allow-egress", "egress", 0, ActionAllow)
+	allowIngress = PolicyHit("default", "default.allow-ingress", "ingress", 1, ActionAllow)
+	denyIngress  = PolicyHit("default", "default.deny-ingress", "ingress", 0, ActionDeny)
+)
+
+func tcp(src netip.Addr, srcPort uint16, dst netip.Addr, dstPort uint16) Tuple {
+	return Tuple{Proto: 6, Src: src, Dst: dst, SrcPort: srcPort, DstPort: dstPort}
+}
+
+var _ = Describe("Aggregator", func() {
+	var a *Aggregator
+	var config Config
+	var t0 time.Time
+
+	BeforeEach(func() {
+		config = Config{Aggregation: AggregationSourcePort, SampleRate: 1}
+		t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	JustBeforeEach(func() {
+		a = NewAggregator(config, mockLookup{
+			client: {"default/client", "eth0"},
+			server: {"default/server", "eth0"},
+		}, t0)
+	})
+
+	It("should log an allowed connection with its policies and traffic", func() {
+		t := tcp(client, 40000, server, 80)
+		a.Verdict(t, ActionAllow, allowEgress, 60)
+		a.Verdict(t, ActionAllow, allowIngress, 60)
+		a.UpdateConntrack([]ConntrackEntry{
+			{Tuple: t, Source: Counters{Packets: 3, Bytes: 200}, Dest: Counters{Packets: 2, Bytes: 1000}},
+		})
+
+		t1 := t0.Add(time.Minute)
+		Expect(a.Flush(t1)).To(Equal([]Record{{
+			StartTime:       t0,
+			EndTime:         t1,
+			Proto:           "tcp",
+			Source:          Endpoint{Type: EndpointTypeWorkload, IP: "10.65.0.2", Workload: "default/client", Endpoint: "eth0"},
+			Dest:            Endpoint{Type: EndpointTypeWorkload, IP: "10.65.0.3", Port: 80, Workload: "default/server", Endpoint: "eth0"},
+			Action:          ActionAllow,
+			Policies:        []string{allowEgress, allowIngress},
+			NumFlows:        1,
+			NumFlowsStarted: 1,
+			SourcePackets:   3,
+			SourceBytes:     200,
+			DestPackets:     2,
+			DestBytes:       1000,
+		}}))
+
+		// The next interval only has the traffic since the previous flush, and the
+		// connection completes once it leaves the conntrack table.
+		a.UpdateConntrack([]ConntrackEntry{
+			{Tuple: t, Source: Counters{Packets: 4, Bytes: 260}, Dest: Counters{Packets: 3, Bytes: 1500}},
+		})
+		a.UpdateConntrack(nil)
+		records := a.Flush(t1.Add(time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].StartTime).To(Equal(t1))
+		Expect(records[0].NumFlowsStarted).To(Equal(0))
+		Expect(records[0].NumFlowsCompleted).To(Equal(1))
+		Expect(records[0].SourcePackets).To(BeNumerically("==", 1))
+		Expect(records[0].DestBytes).To(BeNumerically("==", 500))
+
+		Expect(a.Flush(t1.Add(2 * time.Minute))).To(BeEmpty())
+		Expect(a.conns).To(BeEmpty())
+	})
+
+	It("should not log idle connections", func() {
+		t := tcp(client, 40000, server, 80)
+		entries := []ConntrackEntry{{Tuple: t, Source: Counters{Packets: 1, Bytes: 60}}}
+		a.UpdateConntrack(entries)
+		Expect(a.Flush(t0.Add(time.Minute))).To(HaveLen(1))
+		a.UpdateConntrack(entries)
+		Expect(a.Flush(t0.Add(2 * time.Minute))).To(BeEmpty())
+		Expect(a.conns).To(HaveLen(1))
+	})
+
+	It("should not count the traffic of a kTLS connection again once its socket closes", func() {
+		t := tcp(client, 40000, server, 443)
+		a.UpdateConntrack([]ConntrackEntry{
+			{Tuple: t, Source: Counters{Packets: 10, Bytes: 500}, Dest: Counters{Packets: 200, Bytes: 250000}, KTLS: true},
+		})
+		records := a.Flush(t0.Add(time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].KTLS).To(BeTrue())
+		Expect(records[0].DestBytes).To(BeNumerically("==", 250000))
+
+		// Only the conntrack counters remain, which missed most of the traffic.
+		a.UpdateConntrack([]ConntrackEntry{
+			{Tuple: t, Source: Counters{Packets: 12, Bytes: 700}, Dest: Counters{Packets: 3, Bytes: 4000}},
+		})
+		records = a.Flush(t0.Add(2 * time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].KTLS).To(BeTrue())
+		Expect(records[0].SourcePackets).To(BeNumerically("==", 2))
+		Expect(records[0].SourceBytes).To(BeNumerically("==", 200))
+		Expect(records[0].DestPackets).To(BeNumerically("==", 0))
+		Expect(records[0].DestBytes).To(BeNumerically("==", 0))
+	})
+
+	It("should aggregate the connections by source port", func() {
+		a.Verdict(tcp(client, 40000, server, 80), ActionAllow, allowEgress, 60)
+		a.Verdict(tcp(client, 40001, server, 80), ActionAllow, allowEgress, 60)
+		a.Verdict(tcp(client, 40002, server, 443), ActionAllow, allowEgress, 60)
+
+		records := a.Flush(t0.Add(time.Minute))
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Source.Port).To(BeZero())
+		Expect(records[0].Dest.Port).To(BeNumerically("==", 80))
+		Expect(records[0].NumFlows).To(Equal(2))
+		Expect(records[1].Dest.Port).To(BeNumerically("==", 443))
+		Expect(records[1].NumFlows).To(Equal(1))
+	})
+
+	Context("without aggregation", func() {
+		BeforeEach(func() {
+			config.Aggregation = AggregationNone
+		})
+
+		It("should log each connection", func() {
+			a.Verdict(tcp(client, 40000, server, 80), ActionAllow, allowEgress, 60)
+			a.Verdict(tcp(client, 40001, server, 80), ActionAllow, allowEgress, 60)
+
+			records := a.Flush(t0.Add(time.Minute))
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].Source.Port).To(BeNumerically("==", 40000))
+			Expect(records[1].Source.Port).To(BeNumerically("==", 40001))
+		})
+	})
+
+	It("should count the packets of a denied connection from the verdicts", func() {
+		t := tcp(client, 40000, server, 80)
+		for i := 0; i < 3; i++ {
+			a.Verdict(t, ActionAllow, allowEgress, 60)
+			a.Verdict(t, ActionDeny, denyIngress, 60)
+		}
+
+		records := a.Flush(t0.Add(time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Action).To(Equal(ActionDeny))
+		Expect(records[0].Policies).To(Equal([]string{allowEgress, denyIngress}))
+		Expect(records[0].SourcePackets).To(BeNumerically("==", 3))
+		Expect(records[0].SourceBytes).To(BeNumerically("==", 180))
+
+		// Denied connections are forgotten after an interval without packets.
+		Expect(a.Flush(t0.Add(2 * time.Minute))).To(BeEmpty())
+		Expect(a.conns).To(BeEmpty())
+	})
+
+	It("should complete a connection that ended before it was seen in the conntrack table", func() {
+		a.Verdict(tcp(client, 40000, remote, 53), ActionAllow, allowEgress, 60)
+		for i := 0; i < maxMissedPolls; i++ {
+			a.UpdateConntrack(nil)
+		}
+		records := a.Flush(t0.Add(time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Dest.Type).To(Equal(EndpointTypeNetwork))
+		Expect(records[0].NumFlowsStarted).To(Equal(1))
+		Expect(records[0].NumFlowsCompleted).To(Equal(1))
+		Expect(a.conns).To(BeEmpty())
+	})
+
+	It("should only log the conntrack entries without a verdict of local workloads", func() {
+		a.UpdateConntrack([]ConntrackEntry{
+			{Tuple: tcp(remote, 40000, server, 80), Source: Counters{Packets: 1, Bytes: 60}},
+			{Tuple: tcp(remote, 40000, remote, 80), Source: Counters{Packets: 1, Bytes: 60}},
+		})
+		records := a.Flush(t0.Add(time.Minute))
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Dest.Workload).To(Equal("default/server"))
+		Expect(records[0].NumFlowsStarted).To(Equal(0))
+	})
+
+	Context("with sampling", func() {
+		BeforeEach(func() {
+			config.Aggregation = AggregationNone
+			config.SampleRate = 4
+		})
+
+		It("should log a consistent subset of the connections", func() {
+			for port := uint16(40000); port < 41000; port++ {
+				a.Verdict(tcp(client, port, server, 80), ActionAllow, allowEgress, 60)
+			}
+			n := len(a.Flush(t0.Add(time.Minute)))
+			Expect(n).To(BeNumerically(">", 150))
+			Expect(n).To(BeNumerically("<", 350))
+
+			for port := uint16(40000); port < 41000; port++ {
+				a.Verdict(tcp(client, port, server, 80), ActionAllow, allowEgress, 60)
+			}
+			Expect(a.Flush(t0.Add(2 * time.Minute))).To(HaveLen(n))
+		})
+	})
+})
+
+var _ = Describe("NFLOG prefix", func() {
+	It("should round trip", func() {
+		action, ruleID, err := ParseNFLOGPrefix(NFLOGPrefix(ActionAllow, "abcdefgh01234567"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(action).To(Equal(ActionAllow))
+		Expect(ruleID).To(Equal("abcdefgh01234567"))
+	})
+
+	It("should parse the default deny with or without the trailing space", func() {
+		for _, prefix := range []string{NFLOGPrefix(ActionDeny, ""), "deny"} {
+			action, ruleID, err := ParseNFLOGPrefix(prefix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(action).To(Equal(ActionDeny))
+			Expect(ruleID).To(BeEmpty())
+		}
+	})
+
+	It("should reject other prefixes", func() {
+		_, _, err := ParseNFLOGPrefix("File calico-packet")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+type logsServer struct {
+	collogspb.UnimplementedLogsServiceServer
+	requests chan *collogspb.ExportLogsServiceRequest
+}
+
+func (s *logsServer) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	s.requests <- req
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+var _ = Describe("Exporters", func() {
+	records := []Record{
+		{Proto: "tcp", Source: Endpoint{IP: "10.65.0.2"}, Dest: Endpoint{IP: "10.65.0.3", Port: 80}, Action: ActionAllow},
+		{Proto: "udp", Source: Endpoint{IP: "10.65.0.2"}, Dest: Endpoint{IP: "8.8.8.8", Port: 53}, Action: ActionDeny},
+	}
+
+	It("should append the logs to the file as lines of JSON", func() {
+		dir, err := os.MkdirTemp("", "flowlog")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "flowlogs", "flows.log")
+
+		f := NewFileExporter(path, 1, 1)
+		Expect(f.Export(records)).To(Succeed())
+		f.Close()
+
+		file, err := os.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		var read []Record
+		s := bufio.NewScanner(file)
+		for s.Scan() {
+			var r Record
+			Expect(json.Unmarshal(s.Bytes(), &r)).To(Succeed())
+			read = append(read, r)
+		}
+		Expect(read).To(Equal(records))
+	})
+
+	It("should send the logs to the OTLP collector", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		srv := grpc.NewServer()
+		logs := &logsServer{requests: make(chan *collogspb.ExportLogsServiceRequest, 1)}
+		collogspb.RegisterLogsServiceServer(srv, logs)
+		go func() {
+			_ = srv.Serve(lis)
+		}()
+		defer srv.Stop()
+
+		o, err := NewOTLPExporter(lis.Addr().String(), "node-1")
+		Expect(err).NotTo(HaveOccurred())
+		defer o.Close()
+		Expect(o.Export(records)).To(Succeed())
+
+		var req *collogspb.ExportLogsServiceRequest
+		Eventually(logs.requests).Should(Receive(&req))
+		Expect(req.ResourceLogs).To(HaveLen(1))
+		Expect(req.ResourceLogs[0].Resource.Attributes[1].Value.GetStringValue()).To(Equal("node-1"))
+		logRecords := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+		Expect(logRecords).To(HaveLen(2))
+		var r Record
+		Expect(json.Unmarshal([]byte(logRecords[1].Body.GetStringValue()), &r)).To(Succeed())
+		Expect(r).To(Equal(records[1]))
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flowlog aggregates the connections of the local workloads into flow logs: which
+// endpoints talked to which, the policy rules that allowed or denied them and how much traffic
+// they exchanged.  The verdicts come from the iptables NFLOG rules that Felix adds to the
+// policy rules and the packet and byte counts from the conntrack table.
+package flowlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionAllow Action = "allow"
+	ActionDeny  Action = "deny"
+)
+
+const (
+	EndpointTypeWorkload = "wep"
+	EndpointTypeNetwork  = "net"
+)
+
+// DefaultDenyPolicy is recorded in place of a policy rule for the connections that are denied
+// because no policy or profile allowed them.
+const DefaultDenyPolicy = "default-deny"
+
+// Record is one flow log, which aggregates the connections between a source and a destination
+// over a flush interval.
+type Record struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+
+	Proto  string   `json:"proto"`
+	Source Endpoint `json:"source"`
+	Dest   Endpoint `json:"dest"`
+
+	// Action is deny if any policy denied the connections.  Policies are the policy rules
+	// that allowed or denied them, see PolicyHit.
+	Action   Action   `json:"action"`
+	Policies []string `json:"policies,omitempty"`
+
+	// NumFlows is the number of connections that were active during the interval, of which
+	// NumFlowsStarted started and NumFlowsCompleted ended during it.
+	NumFlows          int `json:"num_flows"`
+	NumFlowsStarted   int `json:"num_flows_started"`
+	NumFlowsCompleted int `json:"num_flows_completed"`
+
+	// SourcePackets and SourceBytes were sent by the source, DestPackets and DestBytes by the
+	// destination in reply.
+	SourcePackets uint64 `json:"source_packets"`
+	SourceBytes   uint64 `json:"source_bytes"`
+	DestPackets   uint64 `json:"dest_packets"`
+	DestBytes     uint64 `json:"dest_bytes"`
+
+	// KTLS is set if any of the connections used kernel TLS on this host, see
+	// FlowLogsKTLSAccountingEnabled.
+	KTLS bool `json:"ktls,omitempty"`
+}
+
+// Endpoint is one end of a flow.  Workload and Endpoint identify the local workload endpoint
+// with the IP, if there is one; for Kubernetes pods Workload is "<namespace>/<pod name>".  The
+// port is omitted for the source when the flows are aggregated by source port.
+type Endpoint struct {
+	Type     string `json:"type"`
+	IP       string `json:"ip"`
+	Port     uint16 `json:"port,omitempty"`
+	Workload string `json:"workload,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// PolicyHit formats a policy rule as recorded in Record.Policies:
+// "<tier>|<policy>|<direction>|<rule index>|<action>".
+func PolicyHit(tier, name, direction string, index int, action Action) string {
+	return fmt.Sprintf("%s|%s|%s|%d|%s", tier, name, direction, index, action)
+}
+
+// NFLOGPrefix returns the prefix of the NFLOG rule that reports the verdict of a policy rule,
+// which fits in the 64 characters that iptables allows.  The rule ID is empty for the default
+// deny at the end of the policies and profiles of an endpoint.
+func NFLOGPrefix(action Action, ruleID string) string {
+	return string(action) + " " + ruleID
+}
+
+// ParseNFLOGPrefix reverses NFLOGPrefix.  The trailing space of the default deny prefix is
+// optional.
+func ParseNFLOGPrefix(s string) (Action, string, error) {
+	a, ruleID, _ := strings.Cut(s, " ")
+	switch action := Action(a); action {
+	case ActionAllow, ActionDeny:
+		return action, ruleID, nil
+	}
+	return "", "", fmt.Errorf("unknown action in flow log prefix %q", s)
+}
@@ -47,7 +47,9 @@ const (
 	StateDown       State = "down"
 )
 
-type InterfaceStateCallback func(ifaceName string, ifaceState State, ifIndex int)
+// InterfaceStateCallback is called when an interface changes state.  parentName is the name of the
+// parent interface of macvlan and ipvlan interfaces, it is empty for other interfaces.
+type InterfaceStateCallback func(ifaceName string, ifaceState State, ifIndex int, parentName string)
 type AddrStateCallback func(ifaceName string, addrs set.Set[string])
 type InSyncCallback func()
 
@@ -298,6 +300,20 @@ func LinkIsOperUp(link netlink.Link) bool {
 	return ifaceIsUp
 }
 
+// virtualLinkParent returns the name of the parent interface of a macvlan or ipvlan link, or ""
+// for other links.  The traffic of these links doesn't go through the hooks of their parent.
+func (m *InterfaceMonitor) virtualLinkParent(link netlink.Link) string {
+	switch link.Type() {
+	case "macvlan", "macvtap", "ipvlan", "ipvtap":
+	default:
+		return ""
+	}
+	if info := m.ifaceIdxToInfo[link.Attrs().ParentIndex]; info != nil {
+		return info.Name
+	}
+	return ""
+}
+
 func (m *InterfaceMonitor) storeAndNotifyLinkInner(ifaceExists bool, ifaceName string, link netlink.Link) {
 	attrs := link.Attrs()
 	ifIndex := attrs.Index
@@ -348,7 +364,7 @@ func (m *InterfaceMonitor) storeAndNotifyLinkInner(ifaceExists bool, ifaceName s
 	})
 	if oldState != newState {
 		logCxt.Debug("Interface changed state")
-		m.StateCallback(ifaceName, newState, ifIndex)
+		m.StateCallback(ifaceName, newState, ifIndex, m.virtualLinkParent(link))
 	} else {
 		logCxt.Debug("Interface state hasn't changed, nothing to notify.")
 	}
@@ -426,7 +442,7 @@ func (m *InterfaceMonitor) resync() error {
 			continue
 		}
 		log.WithField("ifaceName", name).Info("Spotted interface removal on resync.")
-		m.StateCallback(name, StateNotPresent, ifIndex)
+		m.StateCallback(name, StateNotPresent, ifIndex, "")
 		if info.TrackAddrs {
 			// We were tracking addresses for this interface before but now it's gone.  Signal that.
 			m.AddrCallback(name, nil)
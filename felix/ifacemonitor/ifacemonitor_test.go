@@ -40,6 +40,8 @@ type linkModel struct {
 	index int
 	state string
 	addrs set.Set[string]
+	// parent is the name of the parent of a macvlan link, empty for dummy links.
+	parent string
 }
 
 type netlinkTest struct {
@@ -65,9 +67,10 @@ type addrState struct {
 }
 
 type linkUpdate struct {
-	name  string
-	state ifacemonitor.State
-	index int
+	name   string
+	state  ifacemonitor.State
+	index  int
+	parent string
 }
 
 type inSyncupdate struct{}
@@ -99,6 +102,26 @@ func (nl *netlinkTest) addLinkNoSignal(name string) {
 	nl.linksMutex.Unlock()
 }
 
+func (nl *netlinkTest) addMacvlan(name, parent string) {
+	nl.addLinkNoSignal(name)
+	nl.linksMutex.Lock()
+	link := nl.links[name]
+	link.parent = parent
+	nl.links[name] = link
+	nl.linksMutex.Unlock()
+	nl.signalLink(name, 0)
+}
+
+// newLink returns a dummy link, or a macvlan link if the model has a parent.  Must be called
+// with the links mutex held.
+func (nl *netlinkTest) newLink(la netlink.LinkAttrs, model linkModel) netlink.Link {
+	if model.parent == "" {
+		return &netlink.Dummy{LinkAttrs: la}
+	}
+	la.ParentIndex = nl.links[model.parent].index
+	return &netlink.Macvlan{LinkAttrs: la}
+}
+
 func (nl *netlinkTest) renameLink(oldName, newName string) {
 	log.WithFields(log.Fields{"oldName": oldName, "newName": newName}).Info("RENAMELINK")
 	nl.linksMutex.Lock()
@@ -154,7 +177,6 @@ func (nl *netlinkTest) signalLink(name string, oldIndex int) {
 			rawFlags = syscall.IFF_RUNNING
 		}
 	}
-	nl.linksMutex.Unlock()
 
 	// Build the update.
 	la := netlink.NewLinkAttrs()
@@ -165,10 +187,9 @@ func (nl *netlinkTest) signalLink(name string, oldIndex int) {
 		Header: unix.NlMsghdr{
 			Type: msgType,
 		},
-		Link: &netlink.Dummy{
-			LinkAttrs: la,
-		},
+		Link: nl.newLink(la, link),
 	}
+	nl.linksMutex.Unlock()
 
 	// Send it.
 	log.WithField("channel", nl.linkUpdates).Info("Test code signaling a link update")
@@ -252,9 +273,7 @@ func (nl *netlinkTest) LinkList() ([]netlink.Link, error) {
 		la.Name = name
 		la.Index = link.index
 		la.RawFlags = rawFlags
-		links = append(links, &netlink.Dummy{
-			LinkAttrs: la,
-		})
+		links = append(links, nl.newLink(la, link))
 	}
 	nl.linksMutex.Unlock()
 	return links, nil
@@ -324,12 +343,13 @@ func (nl *netlinkTest) AddrList(link netlink.Link, family int) ([]netlink.Addr,
 	return addrs, nil
 }
 
-func (dp *mockDataplane) linkStateCallback(ifaceName string, ifaceState ifacemonitor.State, idx int) {
+func (dp *mockDataplane) linkStateCallback(ifaceName string, ifaceState ifacemonitor.State, idx int, parent string) {
 	log.WithFields(log.Fields{"name": ifaceName, "state": ifaceState}).Info("CALLBACK LINK")
 	dp.linkC <- linkUpdate{
-		name:  ifaceName,
-		state: ifaceState,
-		index: idx,
+		name:   ifaceName,
+		state:  ifaceState,
+		index:  idx,
+		parent: parent,
 	}
 	log.Info("mock dataplane reported link callback")
 }
@@ -609,6 +629,25 @@ var _ = Describe("ifacemonitor", func() {
 		Expect(fatalErrC).ToNot(BeClosed())
 	})
 
+	It("should report the parent of macvlan interfaces", func() {
+		parentIdx := nl.nextIndex
+		nl.addLink("eth0")
+		resyncC <- time.Time{}
+		dp.expectLinkStateCb("eth0", ifacemonitor.StateDown, parentIdx)
+		dp.expectAddrStateCb("eth0", "", true)
+
+		idx := nl.nextIndex
+		nl.addMacvlan("mv0", "eth0")
+		var upd linkUpdate
+		Eventually(dp.linkC).Should(Receive(&upd))
+		Expect(upd).To(Equal(linkUpdate{name: "mv0", state: ifacemonitor.StateDown, index: idx, parent: "eth0"}))
+		dp.expectAddrStateCb("mv0", "", true)
+
+		nl.changeLinkState("mv0", "up")
+		Eventually(dp.linkC).Should(Receive(&upd))
+		Expect(upd).To(Equal(linkUpdate{name: "mv0", state: ifacemonitor.StateUp, index: idx, parent: "eth0"}))
+	})
+
 	It("should handle an interface rename", func() {
 		defer log.Info("Exiting...")
 		// Add a link and an address.  No link callback expected because the link is not up
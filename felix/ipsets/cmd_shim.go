@@ -18,6 +18,8 @@ import (
 	"bufio"
 	"io"
 	"os/exec"
+
+	"github.com/projectcalico/calico/felix/capabilities"
 )
 
 type WriteFlusher interface {
@@ -48,6 +50,7 @@ type cmdFactory func(name string, arg ...string) CmdIface
 
 func newRealCmd(name string, arg ...string) CmdIface {
 	cmd := exec.Command(name, arg...)
+	capabilities.ConfigureCmd(cmd)
 	return (*cmdAdapter)(cmd)
 }
 
@@ -116,6 +116,20 @@ func (g LogAction) String() string {
 	return "Log"
 }
 
+type NflogAction struct {
+	Group     uint16
+	Prefix    string
+	TypeNflog struct{}
+}
+
+func (n NflogAction) ToFragment(features *environment.Features) string {
+	return fmt.Sprintf(`--jump NFLOG --nflog-group %d --nflog-prefix "%s"`, n.Group, n.Prefix)
+}
+
+func (n NflogAction) String() string {
+	return "Nflog"
+}
+
 type AcceptAction struct {
 	TypeAccept struct{}
 }
@@ -183,6 +197,21 @@ func (g MasqAction) String() string {
 	return "Masq"
 }
 
+type TProxyAction struct {
+	Port       uint16
+	Mark       uint32
+	Mask       uint32
+	TypeTProxy struct{}
+}
+
+func (g TProxyAction) ToFragment(features *environment.Features) string {
+	return fmt.Sprintf("--jump TPROXY --on-port %d --tproxy-mark %#x/%#x", g.Port, g.Mark, g.Mask)
+}
+
+func (g TProxyAction) String() string {
+	return fmt.Sprintf("TProxy->:%d", g.Port)
+}
+
 type ClearMarkAction struct {
 	Mark          uint32
 	TypeClearMark struct{}
@@ -32,11 +32,13 @@ var _ = DescribeTable("Actions",
 	Entry("DropAction", environment.Features{}, DropAction{}, "--jump DROP"),
 	Entry("AcceptAction", environment.Features{}, AcceptAction{}, "--jump ACCEPT"),
 	Entry("LogAction", environment.Features{}, LogAction{Prefix: "prefix"}, `--jump LOG --log-prefix "prefix: " --log-level 5`),
+	Entry("NflogAction", environment.Features{}, NflogAction{Group: 40, Prefix: "Syslog prefix"}, `--jump NFLOG --nflog-group 40 --nflog-prefix "Syslog prefix"`),
 	Entry("DNATAction", environment.Features{}, DNATAction{DestAddr: "10.0.0.1", DestPort: 8081}, "--jump DNAT --to-destination 10.0.0.1:8081"),
 	Entry("SNATAction", environment.Features{}, SNATAction{ToAddr: "10.0.0.1"}, "--jump SNAT --to-source 10.0.0.1"),
 	Entry("SNATAction fully random", environment.Features{SNATFullyRandom: true}, SNATAction{ToAddr: "10.0.0.1"}, "--jump SNAT --to-source 10.0.0.1 --random-fully"),
 	Entry("MasqAction", environment.Features{}, MasqAction{}, "--jump MASQUERADE"),
 	Entry("MasqAction", environment.Features{MASQFullyRandom: true}, MasqAction{}, "--jump MASQUERADE --random-fully"),
+	Entry("TProxyAction", environment.Features{}, TProxyAction{Port: 16001, Mark: 0x400, Mask: 0x400}, "--jump TPROXY --on-port 16001 --tproxy-mark 0x400/0x400"),
 	Entry("ClearMarkAction", environment.Features{}, ClearMarkAction{Mark: 0x1000}, "--jump MARK --set-mark 0/0x1000"),
 	Entry("SetMarkAction", environment.Features{}, SetMarkAction{Mark: 0x1000}, "--jump MARK --set-mark 0x1000/0x1000"),
 	Entry("SetMaskedMarkAction", environment.Features{}, SetMaskedMarkAction{
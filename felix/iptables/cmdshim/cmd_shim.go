@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+
+	"github.com/projectcalico/calico/felix/capabilities"
 )
 
 type CmdIface interface {
@@ -37,6 +39,7 @@ type CmdFactory func(name string, arg ...string) CmdIface
 
 func NewRealCmd(name string, arg ...string) CmdIface {
 	cmd := exec.Command(name, arg...)
+	capabilities.ConfigureCmd(cmd)
 	return (*cmdAdapter)(cmd)
 }
 
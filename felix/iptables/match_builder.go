@@ -152,6 +152,10 @@ func (m MatchCriteria) NotDestAddrType(addrType AddrType) MatchCriteria {
 	return append(m, fmt.Sprintf("-m addrtype ! --dst-type %s", addrType))
 }
 
+func (m MatchCriteria) TransparentSocket() MatchCriteria {
+	return append(m, "-m socket --transparent")
+}
+
 func (m MatchCriteria) ConntrackState(stateNames string) MatchCriteria {
 	return append(m, fmt.Sprintf("-m conntrack --ctstate %s", stateNames))
 }
@@ -160,6 +164,21 @@ func (m MatchCriteria) NotConntrackState(stateNames string) MatchCriteria {
 	return append(m, fmt.Sprintf("-m conntrack ! --ctstate %s", stateNames))
 }
 
+// ConntrackReplyFrom matches the reply packets of connections whose original destination was
+// the given CIDR and port, before any DNAT.
+func (m MatchCriteria) ConntrackReplyFrom(origDst string, origDstPort uint16) MatchCriteria {
+	return append(m, fmt.Sprintf("-m conntrack --ctdir REPLY --ctorigdst %s --ctorigdstport %d", origDst, origDstPort))
+}
+
+// Limit matches packets at up to the given rate, for example "10/second", after an initial
+// burst.  A zero burst leaves the iptables default in place.
+func (m MatchCriteria) Limit(rate string, burst uint32) MatchCriteria {
+	if burst == 0 {
+		return append(m, fmt.Sprintf("-m limit --limit %s", rate))
+	}
+	return append(m, fmt.Sprintf("-m limit --limit %s --limit-burst %d", rate, burst))
+}
+
 func (m MatchCriteria) Protocol(name string) MatchCriteria {
 	return append(m, fmt.Sprintf("-p %s", name))
 }
@@ -308,6 +327,15 @@ func (m MatchCriteria) NotICMPV6TypeAndCode(t, c uint8) MatchCriteria {
 	return append(m, fmt.Sprintf("-m icmp6 ! --icmpv6-type %d/%d", t, c))
 }
 
+// DSCP matches on the Differentiated Services Code Point in the IPv4 or IPv6 header.
+func (m MatchCriteria) DSCP(d uint8) MatchCriteria {
+	return append(m, fmt.Sprintf("-m dscp --dscp %d", d))
+}
+
+func (m MatchCriteria) NotDSCP(d uint8) MatchCriteria {
+	return append(m, fmt.Sprintf("-m dscp ! --dscp %d", d))
+}
+
 // VXLANVNI matches on the VNI contained within the VXLAN header.  It assumes that this is indeed a VXLAN
 // packet; i.e. it should be used with a protocol==UDP and port==VXLAN port match.
 //
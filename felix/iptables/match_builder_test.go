@@ -59,6 +59,9 @@ var _ = DescribeTable("MatchBuilder",
 	Entry("NotMarkMatchesWithMask", Match().NotMarkMatchesWithMask(0x400a, 0xf00f), "-m mark ! --mark 0x400a/0xf00f"),
 	// Conntrack.
 	Entry("ConntrackState", Match().ConntrackState("INVALID"), "-m conntrack --ctstate INVALID"),
+	Entry("TransparentSocket", Match().TransparentSocket(), "-m socket --transparent"),
+	Entry("Limit", Match().Limit("10/second", 0), "-m limit --limit 10/second"),
+	Entry("Limit with burst", Match().Limit("10/second", 20), "-m limit --limit 10/second --limit-burst 20"),
 	// Interfaces.
 	Entry("InInterface", Match().InInterface("tap1234abcd"), "--in-interface tap1234abcd"),
 	Entry("OutInterface", Match().OutInterface("tap1234abcd"), "--out-interface tap1234abcd"),
@@ -106,6 +109,8 @@ var _ = DescribeTable("MatchBuilder",
 	Entry("NotICMPV6Type", Match().NotICMPV6Type(123), "-m icmp6 ! --icmpv6-type 123"),
 	Entry("ICMPV6TypeAndCode", Match().ICMPV6TypeAndCode(123, 5), "-m icmp6 --icmpv6-type 123/5"),
 	Entry("NotICMPV6TypeAndCode", Match().NotICMPV6TypeAndCode(123, 5), "-m icmp6 ! --icmpv6-type 123/5"),
+	Entry("DSCP", Match().DSCP(46), "-m dscp --dscp 46"),
+	Entry("NotDSCP", Match().NotDSCP(0), "-m dscp ! --dscp 0"),
 	// Check multiple match criteria are joined correctly.
 	Entry("Protocol and ports", Match().Protocol("tcp").SourcePorts(1234).DestPorts(8080),
 		"-p tcp -m multiport --source-ports 1234 -m multiport --destination-ports 8080"),
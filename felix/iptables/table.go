@@ -23,6 +23,7 @@ import (
 	"os/exec"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -56,6 +57,9 @@ var (
 	chainCreateRegexp = regexp.MustCompile(`^:(\S+)`)
 	// appendRegexp matches an iptables-save output line for an append operation.
 	appendRegexp = regexp.MustCompile(`^-A (\S+)`)
+	// countedAppendRegexp matches an append line of iptables-save -c, which is prefixed by
+	// the rule's packet and byte counters.
+	countedAppendRegexp = regexp.MustCompile(`^\[(\d+):(\d+)\] -A (\S+)`)
 	// nftErrorRegexp matches a particular error emitted if iptables-nft is run on a system that
 	// uses nft features that iptables-nft doesn't understand.
 	nftErrorRegexp = regexp.MustCompile(`^# Table .* is incompatible, use 'nft' tool.`)
@@ -552,6 +556,21 @@ func (t *Table) RemoveChains(chains []*Chain) {
 	}
 }
 
+// RemoveAll drops all the chains and the inserted and appended rules of the table, so that the
+// next Apply removes everything that Felix programmed in the table.
+func (t *Table) RemoveAll() {
+	for chainName := range t.chainToInsertedRules {
+		t.InsertOrAppendRules(chainName, nil)
+	}
+	for chainName := range t.chainToAppendedRules {
+		t.AppendRules(chainName, nil)
+	}
+	for chainName := range t.chainNameToChain {
+		t.RemoveChainByName(chainName)
+	}
+	t.InvalidateDataplaneCache("removing all rules")
+}
+
 func (t *Table) RemoveChainByName(name string) {
 	t.logCxt.WithField("chainName", name).Debug("Removing chain from available set.")
 	if oldChain, known := t.chainNameToChain[name]; known {
@@ -1469,6 +1488,55 @@ func (t *Table) CheckRulesPresent(chain string, rules []Rule) []Rule {
 	return present
 }
 
+// RuleCounters holds the counters of a rule in the dataplane.
+type RuleCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// ReadRuleCounters reads the counters of the rules in our chains from the dataplane.  The
+// counters are indexed by chain name and are in the order of the rules in the chain.
+//
+// Unlike the other methods, it only uses the immutable configuration of the Table so it
+// may be called from any goroutine.
+func (t *Table) ReadRuleCounters() (map[string][]RuleCounters, error) {
+	cmd := t.newCmd(t.iptablesSaveCmd, "-c", "-t", t.Name)
+	countNumSaveCalls.Inc()
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", t.iptablesSaveCmd, err)
+	}
+	return t.readRuleCountersFrom(bytes.NewReader(output))
+}
+
+func (t *Table) readRuleCountersFrom(r io.Reader) (map[string][]RuleCounters, error) {
+	counters := map[string][]RuleCounters{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		captures := countedAppendRegexp.FindSubmatch(scanner.Bytes())
+		if captures == nil {
+			continue
+		}
+		chainName := string(captures[3])
+		if !t.ourChainsRegexp.MatchString(chainName) {
+			continue
+		}
+		packets, err := strconv.ParseUint(string(captures[1]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		byteCount, err := strconv.ParseUint(string(captures[2]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		counters[chainName] = append(counters[chainName], RuleCounters{Packets: packets, Bytes: byteCount})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
 // InsertRulesNow insets the given rules immediately without removing or syncing
 // other rules. This is primarily useful when bootstrapping and we cannot wait
 // until we have the full state.
@@ -231,6 +231,46 @@ func describeEmptyDataplaneTests(dataplaneMode string) {
 			}
 		})
 
+		It("should remove all its rules and chains", func() {
+			table.UpdateChain(&Chain{Name: "cali-foobar", Rules: []Rule{
+				{Action: AcceptAction{}},
+			}})
+			table.InsertOrAppendRules("INPUT", []Rule{
+				{Action: JumpAction{Target: "cali-foobar"}},
+			})
+			table.Apply()
+			Expect(dataplane.Chains).To(HaveKey("cali-foobar"))
+
+			table.RemoveAll()
+			table.Apply()
+			Expect(dataplane.Chains).To(Equal(map[string][]string{
+				"FORWARD": {},
+				"INPUT":   {},
+				"OUTPUT":  {},
+			}))
+		})
+
+		It("should read the counters of our chains only", func() {
+			table.UpdateChain(&Chain{Name: "cali-foobar", Rules: []Rule{
+				{Action: AcceptAction{}},
+				{Action: DropAction{}},
+			}})
+			table.InsertOrAppendRules("FORWARD", []Rule{
+				{Action: JumpAction{Target: "cali-foobar"}},
+			})
+			table.Apply()
+			dataplane.RuleCounters = map[string][]string{
+				"FORWARD":     {"5:500", "7:700"},
+				"cali-foobar": {"3:300", "2:200"},
+			}
+
+			counters, err := table.ReadRuleCounters()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counters).To(Equal(map[string][]RuleCounters{
+				"cali-foobar": {{Packets: 3, Bytes: 300}, {Packets: 2, Bytes: 200}},
+			}))
+		})
+
 		Describe("after inserting a rule then updating the insertions", func() {
 			BeforeEach(func() {
 				table.InsertOrAppendRules("FORWARD", []Rule{
@@ -82,6 +82,9 @@ type MockDataplane struct {
 	Version                        string
 	KernelVersion                  string
 	NftablesMode                   bool
+	// RuleCounters are the "packets:bytes" counters reported for the rules of each chain by
+	// iptables-save -c, rules without an entry report "0:0".
+	RuleCounters map[string][]string
 }
 
 func (d *MockDataplane) ResetCmds() {
@@ -121,9 +124,10 @@ func (d *MockDataplane) NewCmd(name string, arg ...string) cmdshim.CmdIface {
 	case "iptables-save", "ip6tables-save",
 		"iptables-legacy-save", "ip6tables-legacy-save",
 		"iptables-nft-save", "ip6tables-nft-save":
-		Expect(arg).To(Equal([]string{"-t", d.Table}))
+		Expect(arg).To(Or(Equal([]string{"-t", d.Table}), Equal([]string{"-c", "-t", d.Table})))
 		cmd = &saveCmd{
 			Dataplane: d,
+			counters:  arg[0] == "-c",
 		}
 	case "iptables":
 		Expect(arg).To(Equal([]string{"--version"}))
@@ -410,6 +414,7 @@ func PrependLine(src []string, line string) []string {
 type saveCmd struct {
 	Dataplane  *MockDataplane
 	stdoutPipe *closableBuffer
+	counters   bool
 }
 
 func (d *saveCmd) String() string {
@@ -473,7 +478,14 @@ func (d *saveCmd) Output() ([]byte, error) {
 	}
 
 	for chainName, chain := range d.Dataplane.Chains {
-		for _, rule := range chain {
+		for i, rule := range chain {
+			if d.counters {
+				counters := "0:0"
+				if i < len(d.Dataplane.RuleCounters[chainName]) {
+					counters = d.Dataplane.RuleCounters[chainName][i]
+				}
+				buf.WriteString(fmt.Sprintf("[%s] ", counters))
+			}
 			buf.WriteString(fmt.Sprintf("-A %s %s\n", chainName, rule))
 		}
 	}
@@ -0,0 +1,264 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ktls finds the TCP sockets that use kernel TLS (kTLS), with their byte counts, using
+// the sock_diag netlink interface.  Only the sockets of the network namespace of the caller are
+// listed, and the kernel only reports the TLS configuration of a socket to a caller with
+// CAP_NET_ADMIN.
+package ktls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Constants from linux/inet_diag.h and linux/tls.h.
+const (
+	inetDiagInfo    = 2
+	inetDiagULPInfo = 19
+
+	inetULPInfoName = 1
+	inetULPInfoTLS  = 2
+
+	tlsInfoTXConf = 3
+	tlsInfoRXConf = 4
+
+	sizeofInetDiagReq = 56
+	sizeofInetDiagMsg = 72
+
+	// Offsets of the counters in struct tcp_info.
+	tcpInfoBytesAckedOffset    = 120
+	tcpInfoBytesReceivedOffset = 128
+	tcpInfoSegsOutOffset       = 136
+	tcpInfoSegsInOffset        = 140
+
+	// All TCP states but TCP_LISTEN, whose sockets never carry any data.
+	tcpStatesConnected = 0xfff &^ (1 << 10)
+
+	nlaTypeMask = 0x3fff
+)
+
+// Mode is how a direction of a kTLS socket is encrypted, TLS_CONF_* in linux/tls.h.
+type Mode uint16
+
+const (
+	ModeNone Mode = iota
+	// ModeBase is the mode of a direction without keys.
+	ModeBase
+	// ModeSoftware is encrypted by the kernel.
+	ModeSoftware
+	// ModeHardware is encrypted by the NIC, which the kernel passes the records to.
+	ModeHardware
+	// ModeHardwareRecord is encrypted by a NIC that also runs the TCP connection, so its
+	// packets bypass the network stack of the host and its conntrack counters.
+	ModeHardwareRecord
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeNone:
+		return "none"
+	case ModeBase:
+		return "base"
+	case ModeSoftware:
+		return "sw"
+	case ModeHardware:
+		return "hw"
+	case ModeHardwareRecord:
+		return "hw-record"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint16(m))
+	}
+}
+
+// Socket is a TCP socket that uses kTLS.  The counters are those of the TCP connection, whether
+// or not its packets went through the network stack; the bytes count the TCP stream, excluding
+// the headers.
+type Socket struct {
+	Local  netip.AddrPort
+	Remote netip.AddrPort
+
+	TX Mode
+	RX Mode
+
+	SegmentsSent     uint64
+	SegmentsReceived uint64
+	BytesSent        uint64
+	BytesReceived    uint64
+}
+
+// Encrypted returns whether either direction of the socket is encrypted by kTLS.
+func (s Socket) Encrypted() bool {
+	return s.TX >= ModeSoftware || s.RX >= ModeSoftware
+}
+
+// inetDiagReq is struct inet_diag_req_v2, with a zero socket ID to dump all the sockets.
+type inetDiagReq struct {
+	family uint8
+	ext    uint8
+	states uint32
+}
+
+func (r *inetDiagReq) Len() int {
+	return sizeofInetDiagReq
+}
+
+func (r *inetDiagReq) Serialize() []byte {
+	b := make([]byte, sizeofInetDiagReq)
+	b[0] = r.family
+	b[1] = unix.IPPROTO_TCP
+	b[2] = r.ext
+	nl.NativeEndian().PutUint32(b[4:], r.states)
+	return b
+}
+
+// ListSockets returns the TCP sockets of the given address family, unix.AF_INET or
+// unix.AF_INET6, that use kTLS.
+func ListSockets(family uint8) ([]Socket, error) {
+	sock, err := nl.Subscribe(unix.NETLINK_INET_DIAG)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sock_diag socket: %w", err)
+	}
+	defer sock.Close()
+
+	req := nl.NewNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, unix.NLM_F_DUMP)
+	req.AddData(&inetDiagReq{
+		family: family,
+		ext:    1 << (inetDiagInfo - 1),
+		states: tcpStatesConnected,
+	})
+	if err := sock.Send(req); err != nil {
+		return nil, fmt.Errorf("failed to send sock_diag request: %w", err)
+	}
+
+	var sockets []Socket
+	for {
+		msgs, _, err := sock.Receive()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case unix.NLMSG_DONE:
+				return sockets, nil
+			case unix.NLMSG_ERROR:
+				if len(m.Data) < 4 {
+					return nil, fmt.Errorf("short netlink error message")
+				}
+				return nil, syscall.Errno(-int32(nl.NativeEndian().Uint32(m.Data[0:4])))
+			}
+			s, ok, err := ParseSocket(m.Data)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				sockets = append(sockets, s)
+			}
+		}
+	}
+}
+
+// ParseSocket parses a struct inet_diag_msg and its attributes.  It returns false if the
+// socket doesn't use kTLS.
+func ParseSocket(data []byte) (Socket, bool, error) {
+	var s Socket
+	if len(data) < sizeofInetDiagMsg {
+		return s, false, fmt.Errorf("short sock_diag message")
+	}
+	attrs, err := nl.ParseRouteAttr(data[sizeofInetDiagMsg:])
+	if err != nil {
+		return s, false, err
+	}
+
+	var tcpInfo []byte
+	isTLS := false
+	for _, a := range attrs {
+		switch a.Attr.Type & nlaTypeMask {
+		case inetDiagInfo:
+			tcpInfo = a.Value
+		case inetDiagULPInfo:
+			isTLS, err = parseULPInfo(a.Value, &s)
+			if err != nil {
+				return s, false, err
+			}
+		}
+	}
+	if !isTLS {
+		return s, false, nil
+	}
+
+	// The socket ID starts with the ports and the addresses, all in network byte order.  IPv4
+	// addresses only use the first 4 bytes of their 16.
+	family := data[0]
+	sport := binary.BigEndian.Uint16(data[4:6])
+	dport := binary.BigEndian.Uint16(data[6:8])
+	var src, dst netip.Addr
+	if family == unix.AF_INET {
+		src = netip.AddrFrom4([4]byte(data[8:12]))
+		dst = netip.AddrFrom4([4]byte(data[24:28]))
+	} else {
+		src = netip.AddrFrom16([16]byte(data[8:24])).Unmap()
+		dst = netip.AddrFrom16([16]byte(data[24:40])).Unmap()
+	}
+	s.Local = netip.AddrPortFrom(src, sport)
+	s.Remote = netip.AddrPortFrom(dst, dport)
+
+	// Older kernels have a shorter struct tcp_info without the counters.
+	if len(tcpInfo) >= tcpInfoSegsInOffset+4 {
+		s.BytesSent = nl.NativeEndian().Uint64(tcpInfo[tcpInfoBytesAckedOffset:])
+		s.BytesReceived = nl.NativeEndian().Uint64(tcpInfo[tcpInfoBytesReceivedOffset:])
+		s.SegmentsSent = uint64(nl.NativeEndian().Uint32(tcpInfo[tcpInfoSegsOutOffset:]))
+		s.SegmentsReceived = uint64(nl.NativeEndian().Uint32(tcpInfo[tcpInfoSegsInOffset:]))
+	}
+	return s, true, nil
+}
+
+// parseULPInfo parses the nested INET_DIAG_ULP_INFO attribute, filling in the TLS modes of the
+// socket.  It returns whether the upper layer protocol is TLS.
+func parseULPInfo(data []byte, s *Socket) (bool, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return false, err
+	}
+	isTLS := false
+	for _, a := range attrs {
+		switch a.Attr.Type & nlaTypeMask {
+		case inetULPInfoName:
+			isTLS = strings.TrimRight(string(a.Value), "\x00") == "tls"
+		case inetULPInfoTLS:
+			tlsAttrs, err := nl.ParseRouteAttr(a.Value)
+			if err != nil {
+				return false, err
+			}
+			for _, t := range tlsAttrs {
+				if len(t.Value) < 2 {
+					continue
+				}
+				switch t.Attr.Type & nlaTypeMask {
+				case tlsInfoTXConf:
+					s.TX = Mode(nl.NativeEndian().Uint16(t.Value))
+				case tlsInfoRXConf:
+					s.RX = Mode(nl.NativeEndian().Uint16(t.Value))
+				}
+			}
+		}
+	}
+	return isTLS, nil
+}
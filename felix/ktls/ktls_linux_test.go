@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ktls
+
+import (
+	"encoding/binary"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("sock_diag message parsing", func() {
+	message := func(ulp string, tx, rx Mode) []byte {
+		msg := make([]byte, sizeofInetDiagMsg)
+		msg[0] = unix.AF_INET
+		binary.BigEndian.PutUint16(msg[4:], 443)
+		binary.BigEndian.PutUint16(msg[6:], 40000)
+		copy(msg[8:], []byte{10, 0, 0, 1})
+		copy(msg[24:], []byte{10, 65, 0, 2})
+
+		tcpInfo := make([]byte, 232)
+		nl.NativeEndian().PutUint64(tcpInfo[tcpInfoBytesAckedOffset:], 1000)
+		nl.NativeEndian().PutUint64(tcpInfo[tcpInfoBytesReceivedOffset:], 200)
+		nl.NativeEndian().PutUint32(tcpInfo[tcpInfoSegsOutOffset:], 3)
+		nl.NativeEndian().PutUint32(tcpInfo[tcpInfoSegsInOffset:], 2)
+		msg = append(msg, nl.NewRtAttr(inetDiagInfo, tcpInfo).Serialize()...)
+
+		ulpInfo := nl.NewRtAttr(inetDiagULPInfo|unix.NLA_F_NESTED, nil)
+		ulpInfo.AddRtAttr(inetULPInfoName, append([]byte(ulp), 0))
+		tlsInfo := ulpInfo.AddRtAttr(inetULPInfoTLS|unix.NLA_F_NESTED, nil)
+		tlsInfo.AddRtAttr(tlsInfoTXConf, nl.Uint16Attr(uint16(tx)))
+		tlsInfo.AddRtAttr(tlsInfoRXConf, nl.Uint16Attr(uint16(rx)))
+		return append(msg, ulpInfo.Serialize()...)
+	}
+
+	It("should parse a kTLS socket", func() {
+		s, ok, err := ParseSocket(message("tls", ModeHardwareRecord, ModeSoftware))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(s).To(Equal(Socket{
+			Local:            netip.MustParseAddrPort("10.0.0.1:443"),
+			Remote:           netip.MustParseAddrPort("10.65.0.2:40000"),
+			TX:               ModeHardwareRecord,
+			RX:               ModeSoftware,
+			SegmentsSent:     3,
+			SegmentsReceived: 2,
+			BytesSent:        1000,
+			BytesReceived:    200,
+		}))
+		Expect(s.Encrypted()).To(BeTrue())
+	})
+
+	It("should skip the sockets of other upper layer protocols", func() {
+		_, ok, err := ParseSocket(message("mptcp", ModeNone, ModeNone))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should skip the sockets without an upper layer protocol", func() {
+		_, ok, err := ParseSocket(make([]byte, sizeofInetDiagMsg))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject short messages", func() {
+		_, _, err := ParseSocket([]byte{unix.AF_INET, 1})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package l7verdict receives the verdicts that the node-local L7 proxy reaches on the HTTP
+// requests of the connections that Felix redirects to it, and reports them as metrics.
+package l7verdict
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+
+	// maxLineSize bounds the size of a verdict, which includes the request path.
+	maxLineSize = 64 * 1024
+)
+
+var countVerdicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "felix_l7_proxy_verdicts",
+	Help: "Number of HTTP requests that the L7 proxy allowed or denied, by policy.",
+}, []string{"action", "policy"})
+
+func init() {
+	prometheus.MustRegister(countVerdicts)
+}
+
+// Verdict is the decision of the L7 proxy on one HTTP request.  The proxy writes each verdict
+// to the socket as a line of JSON.
+type Verdict struct {
+	Src     string `json:"src"`
+	Dst     string `json:"dst"`
+	DstPort int    `json:"dst_port"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Action  string `json:"action"`
+	// Policy is the name of the policy that decided the request, if any.
+	Policy string `json:"policy,omitempty"`
+}
+
+// Listener accepts connections from the L7 proxy on a unix socket and reads the verdicts
+// that it sends.
+type Listener struct {
+	path      string
+	onVerdict func(Verdict)
+
+	lock     sync.Mutex
+	listener net.Listener
+}
+
+func NewListener(path string) *Listener {
+	return &Listener{
+		path:      path,
+		onVerdict: countVerdict,
+	}
+}
+
+// Start creates the socket, replacing any left behind by a previous Felix, and accepts
+// connections in the background.
+func (l *Listener) Start() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.Listen("unix", l.path)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	l.listener = listener
+	l.lock.Unlock()
+
+	log.WithField("path", l.path).Info("Listening for L7 proxy verdicts.")
+	go l.acceptLoop(listener)
+	return nil
+}
+
+func (l *Listener) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.WithError(err).Warn("Failed to accept connection from the L7 proxy.")
+			continue
+		}
+		go l.serve(conn)
+	}
+}
+
+func (l *Listener) serve(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxLineSize)
+	for scanner.Scan() {
+		var v Verdict
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			log.WithError(err).Debug("Ignoring malformed L7 proxy verdict.")
+			continue
+		}
+		if v.Action != ActionAllow && v.Action != ActionDeny {
+			log.WithField("verdict", v).Debug("Ignoring L7 proxy verdict with unknown action.")
+			continue
+		}
+		l.onVerdict(v)
+	}
+	if err := scanner.Err(); err != nil {
+		log.WithError(err).Debug("Connection from the L7 proxy failed.")
+	}
+}
+
+// Close stops accepting connections.
+func (l *Listener) Close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.listener != nil {
+		_ = l.listener.Close()
+		l.listener = nil
+	}
+}
+
+func countVerdict(v Verdict) {
+	if v.Action == ActionDeny {
+		log.WithField("verdict", v).Debug("L7 proxy denied request.")
+	}
+	countVerdicts.WithLabelValues(v.Action, v.Policy).Inc()
+}
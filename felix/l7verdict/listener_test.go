@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l7verdict
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listener", func() {
+	var (
+		dir      string
+		l        *Listener
+		verdicts chan Verdict
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "l7verdict")
+		Expect(err).NotTo(HaveOccurred())
+		verdicts = make(chan Verdict, 10)
+		l = NewListener(filepath.Join(dir, "run", "verdicts.sock"))
+		l.onVerdict = func(v Verdict) { verdicts <- v }
+	})
+
+	AfterEach(func() {
+		l.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	It("should read the verdicts and skip malformed ones", func() {
+		Expect(l.Start()).To(Succeed())
+
+		conn, err := net.Dial("unix", l.path)
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		_, err = conn.Write([]byte("not json\n" +
+			`{"src":"10.65.0.2","dst":"10.65.1.3","dst_port":80,"method":"GET","path":"/x","action":"maybe"}` + "\n" +
+			`{"src":"10.65.0.2","dst":"10.65.1.3","dst_port":80,"method":"POST","path":"/admin","action":"deny","policy":"default/api"}` + "\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var v Verdict
+		Eventually(verdicts).Should(Receive(&v))
+		Expect(v).To(Equal(Verdict{
+			Src:     "10.65.0.2",
+			Dst:     "10.65.1.3",
+			DstPort: 80,
+			Method:  "POST",
+			Path:    "/admin",
+			Action:  ActionDeny,
+			Policy:  "default/api",
+		}))
+		Consistently(verdicts).ShouldNot(Receive())
+	})
+
+	It("should replace a stale socket", func() {
+		Expect(os.MkdirAll(filepath.Dir(l.path), 0o700)).To(Succeed())
+		Expect(os.WriteFile(l.path, nil, 0o600)).To(Succeed())
+		Expect(l.Start()).To(Succeed())
+
+		conn, err := net.Dial("unix", l.path)
+		Expect(err).NotTo(HaveOccurred())
+		conn.Close()
+	})
+})
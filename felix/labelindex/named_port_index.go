@@ -166,6 +166,9 @@ type IPSetMember struct {
 	CIDR       ip.CIDR
 	Protocol   IPSetPortProtocol
 	PortNumber uint16
+
+	// Domain is set, instead of the CIDR, for the members of domain IP sets.
+	Domain string
 }
 
 type ipSetData struct {
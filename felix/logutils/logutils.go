@@ -16,6 +16,7 @@ package logutils
 
 import (
 	"os"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -52,8 +53,8 @@ func ConfigureEarlyLogging() {
 	log.SetOutput(os.Stdout)
 
 	// Replace logrus' formatter with a custom one using our time format,
-	// shared with the Python code.
-	log.SetFormatter(&logutils.Formatter{Component: "felix"})
+	// shared with the Python code, unless a structured format is requested.
+	log.SetFormatter(formatter(os.Getenv("FELIX_LOGFORMAT")))
 
 	// Install a hook that adds file/line no information.
 	log.AddHook(&logutils.ContextHook{})
@@ -103,6 +104,9 @@ func ConfigureLogging(configParams *config.Config) {
 	// are filtered out as early as possible.
 	log.SetLevel(mostVerboseLevel)
 
+	// The formatter renders the logs of the screen and file targets, syslog has its own format.
+	log.SetFormatter(formatter(configParams.LogFormat))
+
 	// Screen target.
 	var dests []*logutils.Destination
 	if configParams.LogSeverityScreen != "" {
@@ -167,6 +171,17 @@ func ConfigureLogging(configParams *config.Config) {
 	}
 }
 
+// formatter returns the formatter of the LogFormat config parameter.
+func formatter(format string) log.Formatter {
+	switch strings.ToLower(format) {
+	case "logfmt":
+		return &logutils.LogfmtFormatter{Component: "felix"}
+	case "json":
+		return &logutils.JSONFormatter{Component: "felix"}
+	}
+	return &logutils.Formatter{Component: "felix"}
+}
+
 func getScreenDestination(configParams *config.Config, logLevel log.Level) *logutils.Destination {
 	return logutils.NewStreamDestination(
 		logLevel,
@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nflog receives the packets that iptables NFLOG rules pass to an NFLOG group.
+package nflog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Constants from linux/netfilter/nfnetlink_log.h.
+const (
+	nfnlSubsysULOG = 4
+
+	nfulnlMsgPacket = 0
+	nfulnlMsgConfig = 1
+
+	nfulaCfgCmd  = 1
+	nfulaCfgMode = 2
+
+	nfulnlCfgCmdBind   = 1
+	nfulnlCopyPacket   = 2
+	nfulaIfindexIndev  = 4
+	nfulaIfindexOutdev = 5
+	nfulaPayload       = 9
+	nfulaPrefix        = 10
+
+	nlaTypeMask = 0x3fff
+)
+
+// nfgenmsg is the nfnetlink message header, with the resource ID (the NFLOG group) in network
+// byte order.
+type nfgenmsg struct {
+	family  uint8
+	version uint8
+	resID   uint16
+}
+
+func (m *nfgenmsg) Len() int {
+	return nl.SizeofNfgenmsg
+}
+
+func (m *nfgenmsg) Serialize() []byte {
+	b := []byte{m.family, m.version, 0, 0}
+	binary.BigEndian.PutUint16(b[2:], m.resID)
+	return b
+}
+
+// Packet is a packet passed to the NFLOG group, with the prefix of the rule that matched it.
+// The payload starts at the IP header.
+type Packet struct {
+	Prefix     string
+	Payload    []byte
+	InIfindex  uint32
+	OutIfindex uint32
+}
+
+// Reader receives the packets of an NFLOG group.
+type Reader struct {
+	group    uint16
+	copySize uint32
+	sock     *nl.NetlinkSocket
+}
+
+// NewReader returns a reader of the given group that receives up to copySize bytes of each
+// packet.
+func NewReader(group int, copySize int) *Reader {
+	return &Reader{
+		group:    uint16(group),
+		copySize: uint32(copySize),
+	}
+}
+
+// Start binds to the NFLOG group and calls onPacket with each packet in the background.  The
+// packet is only valid for the duration of the call.
+func (r *Reader) Start(onPacket func(Packet)) error {
+	sock, err := nl.Subscribe(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return fmt.Errorf("failed to open nfnetlink socket: %w", err)
+	}
+
+	req := nl.NewNetlinkRequest((nfnlSubsysULOG<<8)|nfulnlMsgConfig, unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	req.AddData(&nfgenmsg{family: unix.AF_UNSPEC, version: nl.NFNETLINK_V0, resID: r.group})
+	req.AddData(nl.NewRtAttr(nfulaCfgCmd, []byte{nfulnlCfgCmdBind}))
+	mode := make([]byte, 6)
+	binary.BigEndian.PutUint32(mode, r.copySize)
+	mode[4] = nfulnlCopyPacket
+	req.AddData(nl.NewRtAttr(nfulaCfgMode, mode))
+	if err := sock.Send(req); err != nil {
+		sock.Close()
+		return fmt.Errorf("failed to send NFLOG bind request: %w", err)
+	}
+	if err := waitForAck(sock); err != nil {
+		sock.Close()
+		return fmt.Errorf("failed to bind to NFLOG group %d: %w", r.group, err)
+	}
+
+	r.sock = sock
+	go r.loop(onPacket)
+	return nil
+}
+
+func waitForAck(sock *nl.NetlinkSocket) error {
+	for {
+		msgs, _, err := sock.Receive()
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			if len(m.Data) < 4 {
+				return fmt.Errorf("short netlink error message")
+			}
+			if errno := int32(nl.NativeEndian().Uint32(m.Data[0:4])); errno != 0 {
+				return syscall.Errno(-errno)
+			}
+			return nil
+		}
+	}
+}
+
+// Stop closes the socket, which ends the background receive loop.
+func (r *Reader) Stop() {
+	if r.sock != nil {
+		r.sock.Close()
+	}
+}
+
+func (r *Reader) loop(onPacket func(Packet)) {
+	logCxt := log.WithField("group", r.group)
+	for {
+		msgs, _, err := r.sock.Receive()
+		if err != nil {
+			if r.sock.GetFd() < 0 {
+				return
+			}
+			if err == unix.ENOBUFS {
+				logCxt.Warn("NFLOG packets were dropped because Felix could not keep up.")
+				continue
+			}
+			logCxt.WithError(err).Error("Failed to receive NFLOG packets, stopped reading NFLOG group.")
+			return
+		}
+		for _, m := range msgs {
+			if m.Header.Type != (nfnlSubsysULOG<<8)|nfulnlMsgPacket {
+				continue
+			}
+			pkt, err := ParsePacket(m.Data)
+			if err != nil {
+				logCxt.WithError(err).Debug("Ignoring bad NFLOG message.")
+				continue
+			}
+			onPacket(pkt)
+		}
+	}
+}
+
+// ParsePacket parses the body of an NFULNL_MSG_PACKET message.
+func ParsePacket(data []byte) (Packet, error) {
+	var pkt Packet
+	if len(data) < nl.SizeofNfgenmsg {
+		return pkt, fmt.Errorf("short NFLOG message")
+	}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return pkt, err
+	}
+
+	for _, a := range attrs {
+		switch a.Attr.Type & nlaTypeMask {
+		case nfulaPrefix:
+			pkt.Prefix = strings.TrimRight(string(a.Value), "\x00")
+		case nfulaPayload:
+			pkt.Payload = a.Value
+		case nfulaIfindexIndev:
+			if len(a.Value) >= 4 {
+				pkt.InIfindex = binary.BigEndian.Uint32(a.Value)
+			}
+		case nfulaIfindexOutdev:
+			if len(a.Value) >= 4 {
+				pkt.OutIfindex = binary.BigEndian.Uint32(a.Value)
+			}
+		}
+	}
+	return pkt, nil
+}
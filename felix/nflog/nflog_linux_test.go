@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nflog
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink/nl"
+)
+
+var _ = Describe("NFLOG message parsing", func() {
+	payload := []byte{0x45, 0, 0, 20}
+
+	message := func(prefix string) []byte {
+		msg := []byte{2, 0, 0, 40}
+		idx := make([]byte, 4)
+		binary.BigEndian.PutUint32(idx, 7)
+		msg = append(msg, nl.NewRtAttr(nfulaIfindexIndev, idx).Serialize()...)
+		msg = append(msg, nl.NewRtAttr(nfulaPrefix, append([]byte(prefix), 0)).Serialize()...)
+		msg = append(msg, nl.NewRtAttr(nfulaPayload, payload).Serialize()...)
+		return msg
+	}
+
+	It("should parse the prefix, payload and interfaces", func() {
+		pkt, err := ParsePacket(message("calico-packet"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkt).To(Equal(Packet{
+			Prefix:    "calico-packet",
+			Payload:   payload,
+			InIfindex: 7,
+		}))
+	})
+
+	It("should reject short messages", func() {
+		_, err := ParsePacket([]byte{2, 0})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policylog
+
+import (
+	"fmt"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/nflog"
+)
+
+// nflogCopyRange is the number of bytes of each packet that the kernel passes to Felix, enough
+// for the IP and transport headers.
+const nflogCopyRange = 128
+
+// NFLOGReader receives the packets that the iptables NFLOG rules of policy Log rules pass to
+// Felix and writes them to the target encoded in their prefix.
+type NFLOGReader struct {
+	reader *nflog.Reader
+	writer *Writer
+}
+
+func NewNFLOGReader(group int, writer *Writer) *NFLOGReader {
+	return &NFLOGReader{
+		reader: nflog.NewReader(group, nflogCopyRange),
+		writer: writer,
+	}
+}
+
+// Start binds to the NFLOG group and starts logging the packets in the background.
+func (r *NFLOGReader) Start() error {
+	return r.reader.Start(r.onPacket)
+}
+
+// Stop closes the socket, which ends the background logging.
+func (r *NFLOGReader) Stop() {
+	r.reader.Stop()
+}
+
+func (r *NFLOGReader) onPacket(p nflog.Packet) {
+	target, facility, line, err := parseNFLOGPacket(p, ifaceName)
+	if err != nil {
+		log.WithError(err).Debug("Failed to log NFLOG packet.")
+		return
+	}
+	if err := r.writer.Write(target, facility, line); err != nil {
+		log.WithError(err).Debug("Failed to write policy log.")
+	}
+}
+
+// parseNFLOGPacket turns an NFLOG packet into a log line.
+func parseNFLOGPacket(p nflog.Packet, ifaceName func(uint32) string) (Target, string, string, error) {
+	target, facility, prefix, err := ParseNFLOGPrefix(p.Prefix)
+	if err != nil {
+		return "", "", "", err
+	}
+	pkt, err := ParsePacket(p.Payload)
+	if err != nil {
+		return "", "", "", err
+	}
+	if p.InIfindex != 0 {
+		pkt.InIface = ifaceName(p.InIfindex)
+	}
+	if p.OutIfindex != 0 {
+		pkt.OutIface = ifaceName(p.OutIfindex)
+	}
+	return target, facility, pkt.Format(prefix), nil
+}
+
+func ifaceName(idx uint32) string {
+	iface, err := net.InterfaceByIndex(int(idx))
+	if err != nil {
+		return fmt.Sprint(idx)
+	}
+	return iface.Name
+}
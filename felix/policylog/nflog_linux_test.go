@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policylog
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/nflog"
+)
+
+var _ = Describe("NFLOG packet parsing", func() {
+	ifaceName := func(idx uint32) string {
+		return fmt.Sprintf("if%d", idx)
+	}
+
+	packet := func(prefix string) nflog.Packet {
+		return nflog.Packet{
+			Prefix:    prefix,
+			Payload:   tcpv4,
+			InIfindex: 7,
+		}
+	}
+
+	It("should turn the packet into a log line for its target", func() {
+		target, facility, line, err := parseNFLOGPacket(packet("Syslog.local1 calico-packet"), ifaceName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(TargetSyslog))
+		Expect(facility).To(Equal("local1"))
+		Expect(line).To(Equal(
+			"calico-packet: IN=if7 OUT= SRC=10.0.0.1 DST=10.0.0.2 LEN=60 PROTO=TCP SPT=34567 DPT=80"))
+	})
+
+	It("should reject packets from other NFLOG rules", func() {
+		_, _, _, err := parseNFLOGPacket(packet("something-else"), ifaceName)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policylog logs the packets matched by policy rules with the Log action to the log
+// targets that Felix handles itself: syslog, the systemd journal and a file.  In iptables mode
+// the packets reach Felix over NFLOG, in BPF mode as perf events.
+package policylog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+type Target string
+
+const (
+	TargetKernel  Target = "Kernel"
+	TargetSyslog  Target = "Syslog"
+	TargetJournal Target = "Journal"
+	TargetFile    Target = "File"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+	protoSCTP   = 132
+)
+
+// Packet holds the fields of a logged packet that end up in the log line.
+type Packet struct {
+	InIface  string
+	OutIface string
+	Src      net.IP
+	Dst      net.IP
+	Length   int
+	Protocol uint8
+	SrcPort  uint16
+	DstPort  uint16
+}
+
+// Format renders the packet in the same layout as the iptables LOG target so that existing
+// tooling keeps working when a rule moves from the kernel log to another target.
+func (p Packet) Format(prefix string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: IN=%s OUT=%s SRC=%s DST=%s LEN=%d PROTO=%s",
+		prefix, p.InIface, p.OutIface, p.Src, p.Dst, p.Length, protoName(p.Protocol))
+	if hasPorts(p.Protocol) {
+		fmt.Fprintf(&sb, " SPT=%d DPT=%d", p.SrcPort, p.DstPort)
+	}
+	return sb.String()
+}
+
+func protoName(p uint8) string {
+	switch p {
+	case protoICMP:
+		return "ICMP"
+	case protoTCP:
+		return "TCP"
+	case protoUDP:
+		return "UDP"
+	case protoICMPv6:
+		return "ICMPv6"
+	case protoSCTP:
+		return "SCTP"
+	}
+	return fmt.Sprint(p)
+}
+
+func hasPorts(p uint8) bool {
+	return p == protoTCP || p == protoUDP || p == protoSCTP
+}
+
+// ParsePacket extracts the addresses, protocol and ports from the IP header at the start of
+// payload.  IPv6 extension headers are not followed.
+func ParsePacket(payload []byte) (Packet, error) {
+	var p Packet
+	if len(payload) < 1 {
+		return p, fmt.Errorf("empty packet")
+	}
+
+	var l4 []byte
+	switch payload[0] >> 4 {
+	case 4:
+		if len(payload) < 20 {
+			return p, fmt.Errorf("short IPv4 header (%d bytes)", len(payload))
+		}
+		ihl := int(payload[0]&0xf) * 4
+		if ihl < 20 || len(payload) < ihl {
+			return p, fmt.Errorf("bad IPv4 header length %d", ihl)
+		}
+		p.Length = int(binary.BigEndian.Uint16(payload[2:4]))
+		p.Protocol = payload[9]
+		p.Src = net.IP(append([]byte(nil), payload[12:16]...))
+		p.Dst = net.IP(append([]byte(nil), payload[16:20]...))
+		l4 = payload[ihl:]
+	case 6:
+		if len(payload) < 40 {
+			return p, fmt.Errorf("short IPv6 header (%d bytes)", len(payload))
+		}
+		p.Length = int(binary.BigEndian.Uint16(payload[4:6])) + 40
+		p.Protocol = payload[6]
+		p.Src = net.IP(append([]byte(nil), payload[8:24]...))
+		p.Dst = net.IP(append([]byte(nil), payload[24:40]...))
+		l4 = payload[40:]
+	default:
+		return p, fmt.Errorf("unknown IP version %d", payload[0]>>4)
+	}
+
+	if hasPorts(p.Protocol) && len(l4) >= 4 {
+		p.SrcPort = binary.BigEndian.Uint16(l4[0:2])
+		p.DstPort = binary.BigEndian.Uint16(l4[2:4])
+	}
+	return p, nil
+}
+
+// NFLOGPrefix returns the NFLOG prefix that passes the log target and syslog facility of a
+// rule to Felix along with the configured log prefix.
+func NFLOGPrefix(target Target, facility, prefix string) string {
+	t := string(target)
+	if facility != "" {
+		t += "." + facility
+	}
+	return t + " " + prefix
+}
+
+// ParseNFLOGPrefix reverses NFLOGPrefix.
+func ParseNFLOGPrefix(s string) (target Target, facility, prefix string, err error) {
+	t, prefix, ok := strings.Cut(s, " ")
+	if !ok {
+		return "", "", "", fmt.Errorf("malformed policy log prefix %q", s)
+	}
+	t, facility, _ = strings.Cut(t, ".")
+	target = Target(t)
+	switch target {
+	case TargetSyslog, TargetJournal, TargetFile:
+	default:
+		return "", "", "", fmt.Errorf("unknown policy log target in prefix %q", s)
+	}
+	return target, facility, prefix, nil
+}
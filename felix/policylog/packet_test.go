@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policylog
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// tcpv4 is a TCP SYN from 10.0.0.1:34567 to 10.0.0.2:80, truncated after the ports.
+var tcpv4 = []byte{
+	0x45, 0x00, 0x00, 0x3c, 0x00, 0x00, 0x40, 0x00, 0x40, 0x06, 0x00, 0x00,
+	10, 0, 0, 1,
+	10, 0, 0, 2,
+	0x87, 0x07, 0x00, 0x50,
+}
+
+var _ = Describe("Packet", func() {
+	It("should parse an IPv4 TCP packet", func() {
+		p, err := ParsePacket(tcpv4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p).To(Equal(Packet{
+			Src:      net.IP{10, 0, 0, 1},
+			Dst:      net.IP{10, 0, 0, 2},
+			Length:   60,
+			Protocol: 6,
+			SrcPort:  34567,
+			DstPort:  80,
+		}))
+	})
+
+	It("should parse an IPv6 UDP packet", func() {
+		payload := make([]byte, 44)
+		payload[0] = 0x60
+		payload[5] = 8
+		payload[6] = 17
+		copy(payload[8:24], net.ParseIP("fd00::1"))
+		copy(payload[24:40], net.ParseIP("fd00::2"))
+		copy(payload[40:44], []byte{0x00, 0x35, 0x04, 0xd2})
+		p, err := ParsePacket(payload)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Src.String()).To(Equal("fd00::1"))
+		Expect(p.Dst.String()).To(Equal("fd00::2"))
+		Expect(p.Length).To(Equal(48))
+		Expect(p.Protocol).To(Equal(uint8(17)))
+		Expect(p.SrcPort).To(Equal(uint16(53)))
+		Expect(p.DstPort).To(Equal(uint16(1234)))
+	})
+
+	It("should reject truncated packets", func() {
+		_, err := ParsePacket(tcpv4[:10])
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePacket(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should format packets like the LOG target", func() {
+		p := Packet{
+			InIface:  "eth0",
+			OutIface: "cali1234",
+			Src:      net.IP{10, 0, 0, 1},
+			Dst:      net.IP{10, 0, 0, 2},
+			Length:   60,
+			Protocol: 6,
+			SrcPort:  34567,
+			DstPort:  80,
+		}
+		Expect(p.Format("calico-packet")).To(Equal(
+			"calico-packet: IN=eth0 OUT=cali1234 SRC=10.0.0.1 DST=10.0.0.2 LEN=60 PROTO=TCP SPT=34567 DPT=80"))
+
+		p.Protocol = 1
+		Expect(p.Format("calico-packet")).To(Equal(
+			"calico-packet: IN=eth0 OUT=cali1234 SRC=10.0.0.1 DST=10.0.0.2 LEN=60 PROTO=ICMP"))
+	})
+})
+
+var _ = Describe("NFLOG prefix", func() {
+	It("should round trip the target and facility", func() {
+		s := NFLOGPrefix(TargetSyslog, "local3", "calico-packet")
+		Expect(s).To(Equal("Syslog.local3 calico-packet"))
+		target, facility, prefix, err := ParseNFLOGPrefix(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(TargetSyslog))
+		Expect(facility).To(Equal("local3"))
+		Expect(prefix).To(Equal("calico-packet"))
+	})
+
+	It("should round trip a target without a facility", func() {
+		target, facility, prefix, err := ParseNFLOGPrefix(NFLOGPrefix(TargetFile, "", "calico-packet-staged"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(TargetFile))
+		Expect(facility).To(BeEmpty())
+		Expect(prefix).To(Equal("calico-packet-staged"))
+	})
+
+	It("should reject prefixes that are not policy log prefixes", func() {
+		_, _, _, err := ParseNFLOGPrefix("calico-packet")
+		Expect(err).To(HaveOccurred())
+		_, _, _, err = ParseNFLOGPrefix("Kernel calico-packet")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policylog
+
+import (
+	"fmt"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+const (
+	syslogTag         = "calico-policy"
+	defaultFacility   = "local0"
+	kmsgPath          = "/dev/kmsg"
+	kmsgNoticeLevel   = "<5>"
+	fileTimestampForm = time.RFC3339
+)
+
+var facilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// Writer writes policy log lines to their targets.  The syslog connections and files are
+// opened on first use so that nodes that only log to the kernel pay nothing.
+type Writer struct {
+	filePath string
+
+	lock    sync.Mutex
+	syslogs map[string]*syslog.Writer
+	file    *os.File
+	kmsg    *os.File
+
+	timeNow func() time.Time
+}
+
+func NewWriter(filePath string) *Writer {
+	return &Writer{
+		filePath: filePath,
+		syslogs:  map[string]*syslog.Writer{},
+		timeNow:  time.Now,
+	}
+}
+
+// Write logs line to target.  The facility only applies to the Syslog target.
+func (w *Writer) Write(target Target, facility, line string) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	switch target {
+	case TargetSyslog:
+		if facility == "" {
+			facility = defaultFacility
+		}
+		s, err := w.syslogWriter(facility)
+		if err != nil {
+			return err
+		}
+		return s.Notice(line)
+	case TargetJournal:
+		return journal.Send(line, journal.PriNotice, map[string]string{"SYSLOG_IDENTIFIER": syslogTag})
+	case TargetFile:
+		if w.file == nil {
+			if err := os.MkdirAll(filepath.Dir(w.filePath), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(w.filePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+			if err != nil {
+				return err
+			}
+			w.file = f
+		}
+		_, err := fmt.Fprintf(w.file, "%s %s\n", w.timeNow().Format(fileTimestampForm), line)
+		return err
+	case TargetKernel, "":
+		// Only used in BPF mode, where there is no LOG target to write to the kernel log for us.
+		if w.kmsg == nil {
+			f, err := os.OpenFile(kmsgPath, os.O_WRONLY, 0)
+			if err != nil {
+				return err
+			}
+			w.kmsg = f
+		}
+		_, err := w.kmsg.WriteString(kmsgNoticeLevel + line)
+		return err
+	}
+	return fmt.Errorf("unknown policy log target %q", target)
+}
+
+func (w *Writer) syslogWriter(facility string) (*syslog.Writer, error) {
+	if s := w.syslogs[facility]; s != nil {
+		return s, nil
+	}
+	prio, ok := facilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	s, err := syslog.New(prio|syslog.LOG_NOTICE, syslogTag)
+	if err != nil {
+		return nil, err
+	}
+	w.syslogs[facility] = s
+	return s, nil
+}
+
+// Close closes the syslog connections and files opened by the writer.
+func (w *Writer) Close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for f, s := range w.syslogs {
+		_ = s.Close()
+		delete(w.syslogs, f)
+	}
+	if w.file != nil {
+		_ = w.file.Close()
+		w.file = nil
+	}
+	if w.kmsg != nil {
+		_ = w.kmsg.Close()
+		w.kmsg = nil
+	}
+}
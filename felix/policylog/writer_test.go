@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policylog
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Writer", func() {
+	var (
+		dir string
+		w   *Writer
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "policylog")
+		Expect(err).NotTo(HaveOccurred())
+		w = NewWriter(filepath.Join(dir, "sub", "policy.log"))
+		w.timeNow = func() time.Time {
+			return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		}
+	})
+
+	AfterEach(func() {
+		w.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	It("should append timestamped lines to the file", func() {
+		Expect(w.Write(TargetFile, "", "calico-packet: one")).To(Succeed())
+		Expect(w.Write(TargetFile, "", "calico-packet: two")).To(Succeed())
+		content, err := os.ReadFile(filepath.Join(dir, "sub", "policy.log"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(
+			"2024-05-01T12:00:00Z calico-packet: one\n" +
+				"2024-05-01T12:00:00Z calico-packet: two\n"))
+	})
+
+	It("should reject unknown syslog facilities", func() {
+		Expect(w.Write(TargetSyslog, "bogus", "calico-packet: one")).NotTo(Succeed())
+	})
+
+	It("should reject unknown targets", func() {
+		Expect(w.Write("Printer", "", "calico-packet: one")).NotTo(Succeed())
+	})
+})
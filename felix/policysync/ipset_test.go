@@ -35,7 +35,8 @@ var _ = Describe("AddIPSetsRule", func() {
 		rv := reflect.ValueOf(&r)
 		for i := 0; i < rt.NumField(); i++ {
 			fn := rt.Field(i).Name
-			if strings.HasSuffix(fn, "IpSetIds") {
+			// Domain IP sets are not passed to the policy sync API clients.
+			if strings.HasSuffix(fn, "IpSetIds") && fn != "DstDomainIpSetIds" {
 				fields = append(fields, fn)
 				fv := rv.Elem().Field(i)
 				fv.Set(reflect.ValueOf([]string{fn}))
@@ -387,6 +387,12 @@ func (p *Processor) handleIPSetUpdate(update *proto.IPSetUpdate) {
 	id := update.Id
 	logCxt := log.WithField("ID", id)
 	logCxt.Debug("Processing IPSetUpdate")
+	if update.Type == proto.IPSetUpdate_DOMAIN {
+		// Domain IP sets are resolved by the dataplane, they are not passed to the policy
+		// sync API clients.
+		logCxt.Debug("Ignoring domain IP set")
+		return
+	}
 	s, ok := p.ipSetsByID[id]
 	if !ok {
 		logCxt.Info("Adding new IP Set")
@@ -417,8 +423,13 @@ func (p *Processor) handleIPSetDeltaUpdate(update *proto.IPSetDeltaUpdate) {
 	id := update.Id
 	log.WithField("ID", id).Debug("Processing IPSetDeltaUpdate")
 
-	// We trust the calc graph to never send us Delta updates for nonexistent sets.
-	s := p.ipSetsByID[id]
+	// We trust the calc graph to never send us Delta updates for nonexistent sets, so a
+	// missing set is a domain IP set, which we ignore.
+	s, ok := p.ipSetsByID[id]
+	if !ok {
+		log.WithField("ID", id).Debug("Ignoring delta update of domain IP set")
+		return
+	}
 	s.deltaUpdate(update)
 
 	// gRPC has limits on message size, so break up large update if necessary.
@@ -81,6 +81,7 @@ It has these top-level messages:
 	ServicePort
 	ServiceUpdate
 	ServiceRemove
+	PortMapping
 */
 package proto
 
@@ -206,17 +207,20 @@ const (
 	IPSetUpdate_IP          IPSetUpdate_IPSetType = 0
 	IPSetUpdate_IP_AND_PORT IPSetUpdate_IPSetType = 1
 	IPSetUpdate_NET         IPSetUpdate_IPSetType = 2
+	IPSetUpdate_DOMAIN      IPSetUpdate_IPSetType = 3
 )
 
 var IPSetUpdate_IPSetType_name = map[int32]string{
 	0: "IP",
 	1: "IP_AND_PORT",
 	2: "NET",
+	3: "DOMAIN",
 }
 var IPSetUpdate_IPSetType_value = map[string]int32{
 	"IP":          0,
 	"IP_AND_PORT": 1,
 	"NET":         2,
+	"DOMAIN":      3,
 }
 
 func (x IPSetUpdate_IPSetType) String() string {
@@ -1967,6 +1971,9 @@ func (m *Profile) GetOutboundRules() []*Rule {
 type ActivePolicyUpdate struct {
 	Id     *PolicyID `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	Policy *Policy   `protobuf:"bytes,2,opt,name=policy" json:"policy,omitempty"`
+	// Index of the policy's bit in the endpoint policy bitmaps, zero if policy bitmaps are disabled
+	// or all the indexes are in use.
+	BitmapIndex uint32 `protobuf:"varint,3,opt,name=bitmap_index,json=bitmapIndex,proto3" json:"bitmap_index,omitempty"`
 }
 
 func (m *ActivePolicyUpdate) Reset()                    { *m = ActivePolicyUpdate{} }
@@ -1988,6 +1995,13 @@ func (m *ActivePolicyUpdate) GetPolicy() *Policy {
 	return nil
 }
 
+func (m *ActivePolicyUpdate) GetBitmapIndex() uint32 {
+	if m != nil {
+		return m.BitmapIndex
+	}
+	return 0
+}
+
 type ActivePolicyRemove struct {
 	Id *PolicyID `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 }
@@ -2106,18 +2120,30 @@ type Rule struct {
 	SrcIpSetIds []string    `protobuf:"bytes,10,rep,name=src_ip_set_ids,json=srcIpSetIds" json:"src_ip_set_ids,omitempty"`
 	DstIpSetIds []string    `protobuf:"bytes,11,rep,name=dst_ip_set_ids,json=dstIpSetIds" json:"dst_ip_set_ids,omitempty"`
 	// IP sets on which we should match both IP and port.
-	DstIpPortSetIds []string     `protobuf:"bytes,15,rep,name=dst_ip_port_set_ids,json=dstIpPortSetIds" json:"dst_ip_port_set_ids,omitempty"`
-	NotProtocol     *Protocol    `protobuf:"bytes,102,opt,name=not_protocol,json=notProtocol" json:"not_protocol,omitempty"`
-	NotSrcNet       []string     `protobuf:"bytes,103,rep,name=not_src_net,json=notSrcNet" json:"not_src_net,omitempty"`
-	NotSrcPorts     []*PortRange `protobuf:"bytes,104,rep,name=not_src_ports,json=notSrcPorts" json:"not_src_ports,omitempty"`
-	NotDstNet       []string     `protobuf:"bytes,105,rep,name=not_dst_net,json=notDstNet" json:"not_dst_net,omitempty"`
-	NotDstPorts     []*PortRange `protobuf:"bytes,106,rep,name=not_dst_ports,json=notDstPorts" json:"not_dst_ports,omitempty"`
+	DstIpPortSetIds []string `protobuf:"bytes,15,rep,name=dst_ip_port_set_ids,json=dstIpPortSetIds" json:"dst_ip_port_set_ids,omitempty"`
+	// Domain IP sets; the destination must be one of the IPs that the domains resolved to.
+	DstDomainIpSetIds []string `protobuf:"bytes,16,rep,name=dst_domain_ip_set_ids,json=dstDomainIpSetIds" json:"dst_domain_ip_set_ids,omitempty"`
+	// Types that are valid to be assigned to Dscp:
+	//	*Rule_DscpValue
+	Dscp isRule_Dscp `protobuf_oneof:"dscp"`
+	// Further ICMP types, and types and codes, to match.  A packet matches these if it matches
+	// any of the listed types or any of the listed type and code pairs.
+	IcmpTypes     []int32            `protobuf:"varint,18,rep,packed,name=icmp_types,json=icmpTypes" json:"icmp_types,omitempty"`
+	IcmpTypeCodes []*IcmpTypeAndCode `protobuf:"bytes,19,rep,name=icmp_type_codes,json=icmpTypeCodes" json:"icmp_type_codes,omitempty"`
+	NotProtocol   *Protocol          `protobuf:"bytes,102,opt,name=not_protocol,json=notProtocol" json:"not_protocol,omitempty"`
+	NotSrcNet     []string           `protobuf:"bytes,103,rep,name=not_src_net,json=notSrcNet" json:"not_src_net,omitempty"`
+	NotSrcPorts   []*PortRange       `protobuf:"bytes,104,rep,name=not_src_ports,json=notSrcPorts" json:"not_src_ports,omitempty"`
+	NotDstNet     []string           `protobuf:"bytes,105,rep,name=not_dst_net,json=notDstNet" json:"not_dst_net,omitempty"`
+	NotDstPorts   []*PortRange       `protobuf:"bytes,106,rep,name=not_dst_ports,json=notDstPorts" json:"not_dst_ports,omitempty"`
 	// Types that are valid to be assigned to NotIcmp:
 	//	*Rule_NotIcmpType
 	//	*Rule_NotIcmpTypeCode
-	NotIcmp                 isRule_NotIcmp `protobuf_oneof:"not_icmp"`
-	NotSrcIpSetIds          []string       `protobuf:"bytes,109,rep,name=not_src_ip_set_ids,json=notSrcIpSetIds" json:"not_src_ip_set_ids,omitempty"`
-	NotDstIpSetIds          []string       `protobuf:"bytes,110,rep,name=not_dst_ip_set_ids,json=notDstIpSetIds" json:"not_dst_ip_set_ids,omitempty"`
+	NotIcmp        isRule_NotIcmp `protobuf_oneof:"not_icmp"`
+	NotSrcIpSetIds []string       `protobuf:"bytes,109,rep,name=not_src_ip_set_ids,json=notSrcIpSetIds" json:"not_src_ip_set_ids,omitempty"`
+	NotDstIpSetIds []string       `protobuf:"bytes,110,rep,name=not_dst_ip_set_ids,json=notDstIpSetIds" json:"not_dst_ip_set_ids,omitempty"`
+	// Types that are valid to be assigned to NotDscp:
+	//	*Rule_NotDscpValue
+	NotDscp                 isRule_NotDscp `protobuf_oneof:"not_dscp"`
 	NotSrcNamedPortIpSetIds []string       `protobuf:"bytes,112,rep,name=not_src_named_port_ip_set_ids,json=notSrcNamedPortIpSetIds" json:"not_src_named_port_ip_set_ids,omitempty"`
 	NotDstNamedPortIpSetIds []string       `protobuf:"bytes,113,rep,name=not_dst_named_port_ip_set_ids,json=notDstNamedPortIpSetIds" json:"not_dst_named_port_ip_set_ids,omitempty"`
 	// These fields pass through the original selectors from the v3 datamodel unmodified as required
@@ -2140,6 +2166,11 @@ type Rule struct {
 	// Pass through of the v3 datamodel HTTP match criteria.
 	HttpMatch *HTTPMatch    `protobuf:"bytes,122,opt,name=http_match,json=httpMatch" json:"http_match,omitempty"`
 	Metadata  *RuleMetadata `protobuf:"bytes,123,opt,name=metadata" json:"metadata,omitempty"`
+	// Log options of rules with the log action.
+	LogTarget         string `protobuf:"bytes,124,opt,name=log_target,json=logTarget,proto3" json:"log_target,omitempty"`
+	LogSyslogFacility string `protobuf:"bytes,125,opt,name=log_syslog_facility,json=logSyslogFacility,proto3" json:"log_syslog_facility,omitempty"`
+	LogRateLimit      string `protobuf:"bytes,126,opt,name=log_rate_limit,json=logRateLimit,proto3" json:"log_rate_limit,omitempty"`
+	LogRateLimitBurst uint32 `protobuf:"varint,127,opt,name=log_rate_limit_burst,json=logRateLimitBurst,proto3" json:"log_rate_limit_burst,omitempty"`
 	// An opaque ID/hash for the rule.
 	RuleId string `protobuf:"bytes,201,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
 }
@@ -2154,11 +2185,21 @@ type isRule_Icmp interface {
 	MarshalTo([]byte) (int, error)
 	Size() int
 }
+type isRule_Dscp interface {
+	isRule_Dscp()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
 type isRule_NotIcmp interface {
 	isRule_NotIcmp()
 	MarshalTo([]byte) (int, error)
 	Size() int
 }
+type isRule_NotDscp interface {
+	isRule_NotDscp()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
 
 type Rule_IcmpType struct {
 	IcmpType int32 `protobuf:"varint,8,opt,name=icmp_type,json=icmpType,proto3,oneof"`
@@ -2166,17 +2207,25 @@ type Rule_IcmpType struct {
 type Rule_IcmpTypeCode struct {
 	IcmpTypeCode *IcmpTypeAndCode `protobuf:"bytes,9,opt,name=icmp_type_code,json=icmpTypeCode,oneof"`
 }
+type Rule_DscpValue struct {
+	DscpValue int32 `protobuf:"varint,17,opt,name=dscp_value,json=dscpValue,proto3,oneof"`
+}
 type Rule_NotIcmpType struct {
 	NotIcmpType int32 `protobuf:"varint,107,opt,name=not_icmp_type,json=notIcmpType,proto3,oneof"`
 }
 type Rule_NotIcmpTypeCode struct {
 	NotIcmpTypeCode *IcmpTypeAndCode `protobuf:"bytes,108,opt,name=not_icmp_type_code,json=notIcmpTypeCode,oneof"`
 }
+type Rule_NotDscpValue struct {
+	NotDscpValue int32 `protobuf:"varint,111,opt,name=not_dscp_value,json=notDscpValue,proto3,oneof"`
+}
 
 func (*Rule_IcmpType) isRule_Icmp()           {}
 func (*Rule_IcmpTypeCode) isRule_Icmp()       {}
+func (*Rule_DscpValue) isRule_Dscp()          {}
 func (*Rule_NotIcmpType) isRule_NotIcmp()     {}
 func (*Rule_NotIcmpTypeCode) isRule_NotIcmp() {}
+func (*Rule_NotDscpValue) isRule_NotDscp()    {}
 
 func (m *Rule) GetIcmp() isRule_Icmp {
 	if m != nil {
@@ -2184,12 +2233,24 @@ func (m *Rule) GetIcmp() isRule_Icmp {
 	}
 	return nil
 }
+func (m *Rule) GetDscp() isRule_Dscp {
+	if m != nil {
+		return m.Dscp
+	}
+	return nil
+}
 func (m *Rule) GetNotIcmp() isRule_NotIcmp {
 	if m != nil {
 		return m.NotIcmp
 	}
 	return nil
 }
+func (m *Rule) GetNotDscp() isRule_NotDscp {
+	if m != nil {
+		return m.NotDscp
+	}
+	return nil
+}
 
 func (m *Rule) GetAction() string {
 	if m != nil {
@@ -2289,6 +2350,34 @@ func (m *Rule) GetDstIpPortSetIds() []string {
 	return nil
 }
 
+func (m *Rule) GetDstDomainIpSetIds() []string {
+	if m != nil {
+		return m.DstDomainIpSetIds
+	}
+	return nil
+}
+
+func (m *Rule) GetDscpValue() int32 {
+	if x, ok := m.GetDscp().(*Rule_DscpValue); ok {
+		return x.DscpValue
+	}
+	return 0
+}
+
+func (m *Rule) GetIcmpTypes() []int32 {
+	if m != nil {
+		return m.IcmpTypes
+	}
+	return nil
+}
+
+func (m *Rule) GetIcmpTypeCodes() []*IcmpTypeAndCode {
+	if m != nil {
+		return m.IcmpTypeCodes
+	}
+	return nil
+}
+
 func (m *Rule) GetNotProtocol() *Protocol {
 	if m != nil {
 		return m.NotProtocol
@@ -2352,6 +2441,13 @@ func (m *Rule) GetNotDstIpSetIds() []string {
 	return nil
 }
 
+func (m *Rule) GetNotDscpValue() int32 {
+	if x, ok := m.GetNotDscp().(*Rule_NotDscpValue); ok {
+		return x.NotDscpValue
+	}
+	return 0
+}
+
 func (m *Rule) GetNotSrcNamedPortIpSetIds() []string {
 	if m != nil {
 		return m.NotSrcNamedPortIpSetIds
@@ -2464,6 +2560,34 @@ func (m *Rule) GetMetadata() *RuleMetadata {
 	return nil
 }
 
+func (m *Rule) GetLogTarget() string {
+	if m != nil {
+		return m.LogTarget
+	}
+	return ""
+}
+
+func (m *Rule) GetLogSyslogFacility() string {
+	if m != nil {
+		return m.LogSyslogFacility
+	}
+	return ""
+}
+
+func (m *Rule) GetLogRateLimit() string {
+	if m != nil {
+		return m.LogRateLimit
+	}
+	return ""
+}
+
+func (m *Rule) GetLogRateLimitBurst() uint32 {
+	if m != nil {
+		return m.LogRateLimitBurst
+	}
+	return 0
+}
+
 func (m *Rule) GetRuleId() string {
 	if m != nil {
 		return m.RuleId
@@ -2476,8 +2600,10 @@ func (*Rule) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error,
 	return _Rule_OneofMarshaler, _Rule_OneofUnmarshaler, _Rule_OneofSizer, []interface{}{
 		(*Rule_IcmpType)(nil),
 		(*Rule_IcmpTypeCode)(nil),
+		(*Rule_DscpValue)(nil),
 		(*Rule_NotIcmpType)(nil),
 		(*Rule_NotIcmpTypeCode)(nil),
+		(*Rule_NotDscpValue)(nil),
 	}
 }
 
@@ -2497,6 +2623,15 @@ func _Rule_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 	default:
 		return fmt.Errorf("Rule.Icmp has unexpected type %T", x)
 	}
+	// dscp
+	switch x := m.Dscp.(type) {
+	case *Rule_DscpValue:
+		_ = b.EncodeVarint(17<<3 | proto1.WireVarint)
+		_ = b.EncodeVarint(uint64(x.DscpValue))
+	case nil:
+	default:
+		return fmt.Errorf("Rule.Dscp has unexpected type %T", x)
+	}
 	// not_icmp
 	switch x := m.NotIcmp.(type) {
 	case *Rule_NotIcmpType:
@@ -2511,6 +2646,15 @@ func _Rule_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 	default:
 		return fmt.Errorf("Rule.NotIcmp has unexpected type %T", x)
 	}
+	// not_dscp
+	switch x := m.NotDscp.(type) {
+	case *Rule_NotDscpValue:
+		_ = b.EncodeVarint(111<<3 | proto1.WireVarint)
+		_ = b.EncodeVarint(uint64(x.NotDscpValue))
+	case nil:
+	default:
+		return fmt.Errorf("Rule.NotDscp has unexpected type %T", x)
+	}
 	return nil
 }
 
@@ -2532,6 +2676,13 @@ func _Rule_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Icmp = &Rule_IcmpTypeCode{msg}
 		return true, err
+	case 17: // dscp.dscp_value
+		if wire != proto1.WireVarint {
+			return true, proto1.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Dscp = &Rule_DscpValue{int32(x)}
+		return true, err
 	case 107: // not_icmp.not_icmp_type
 		if wire != proto1.WireVarint {
 			return true, proto1.ErrInternalBadWireType
@@ -2547,6 +2698,13 @@ func _Rule_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.Buffer)
 		err := b.DecodeMessage(msg)
 		m.NotIcmp = &Rule_NotIcmpTypeCode{msg}
 		return true, err
+	case 111: // not_dscp.not_dscp_value
+		if wire != proto1.WireVarint {
+			return true, proto1.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.NotDscp = &Rule_NotDscpValue{int32(x)}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -2568,6 +2726,15 @@ func _Rule_OneofSizer(msg proto1.Message) (n int) {
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
 	}
+	// dscp
+	switch x := m.Dscp.(type) {
+	case *Rule_DscpValue:
+		n += proto1.SizeVarint(17<<3 | proto1.WireVarint)
+		n += proto1.SizeVarint(uint64(x.DscpValue))
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
 	// not_icmp
 	switch x := m.NotIcmp.(type) {
 	case *Rule_NotIcmpType:
@@ -2582,6 +2749,15 @@ func _Rule_OneofSizer(msg proto1.Message) (n int) {
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
 	}
+	// not_dscp
+	switch x := m.NotDscp.(type) {
+	case *Rule_NotDscpValue:
+		n += proto1.SizeVarint(111<<3 | proto1.WireVarint)
+		n += proto1.SizeVarint(uint64(x.NotDscpValue))
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
 	return n
 }
 
@@ -3001,6 +3177,10 @@ type WorkloadEndpoint struct {
 	Ipv6Nat                    []*NatInfo        `protobuf:"bytes,9,rep,name=ipv6_nat,json=ipv6Nat" json:"ipv6_nat,omitempty"`
 	AllowSpoofedSourcePrefixes []string          `protobuf:"bytes,10,rep,name=allow_spoofed_source_prefixes,json=allowSpoofedSourcePrefixes" json:"allow_spoofed_source_prefixes,omitempty"`
 	Annotations                map[string]string `protobuf:"bytes,11,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PortMappings               []*PortMapping    `protobuf:"bytes,12,rep,name=port_mappings,json=portMappings" json:"port_mappings,omitempty"`
+	// Bitmap of the policies that apply to the endpoint, indexed by the policies' bitmap_index.
+	// Empty if policy bitmaps are disabled or a policy has no index.
+	PolicyBitmap []byte `protobuf:"bytes,13,opt,name=policy_bitmap,json=policyBitmap,proto3" json:"policy_bitmap,omitempty"`
 }
 
 func (m *WorkloadEndpoint) Reset()                    { *m = WorkloadEndpoint{} }
@@ -3085,6 +3265,20 @@ func (m *WorkloadEndpoint) GetAnnotations() map[string]string {
 	return nil
 }
 
+func (m *WorkloadEndpoint) GetPortMappings() []*PortMapping {
+	if m != nil {
+		return m.PortMappings
+	}
+	return nil
+}
+
+func (m *WorkloadEndpoint) GetPolicyBitmap() []byte {
+	if m != nil {
+		return m.PolicyBitmap
+	}
+	return nil
+}
+
 type WorkloadEndpointRemove struct {
 	Id *WorkloadEndpointID `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 }
@@ -3152,6 +3346,9 @@ type HostEndpoint struct {
 	ForwardTiers      []*TierInfo `protobuf:"bytes,8,rep,name=forward_tiers,json=forwardTiers" json:"forward_tiers,omitempty"`
 	ExpectedIpv4Addrs []string    `protobuf:"bytes,4,rep,name=expected_ipv4_addrs,json=expectedIpv4Addrs" json:"expected_ipv4_addrs,omitempty"`
 	ExpectedIpv6Addrs []string    `protobuf:"bytes,5,rep,name=expected_ipv6_addrs,json=expectedIpv6Addrs" json:"expected_ipv6_addrs,omitempty"`
+	// Bitmap of the policies that apply to the endpoint, indexed by the policies' bitmap_index.
+	// Empty if policy bitmaps are disabled or a policy has no index.
+	PolicyBitmap []byte `protobuf:"bytes,9,opt,name=policy_bitmap,json=policyBitmap,proto3" json:"policy_bitmap,omitempty"`
 }
 
 func (m *HostEndpoint) Reset()                    { *m = HostEndpoint{} }
@@ -3215,6 +3412,13 @@ func (m *HostEndpoint) GetExpectedIpv6Addrs() []string {
 	return nil
 }
 
+func (m *HostEndpoint) GetPolicyBitmap() []byte {
+	if m != nil {
+		return m.PolicyBitmap
+	}
+	return nil
+}
+
 type HostEndpointRemove struct {
 	Id *HostEndpointID `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 }
@@ -3674,6 +3878,9 @@ type IPAMPool struct {
 	Masquerade bool   `protobuf:"varint,2,opt,name=masquerade,proto3" json:"masquerade,omitempty"`
 	IpipMode   string `protobuf:"bytes,3,opt,name=ipip_mode,json=ipipMode,proto3" json:"ipip_mode,omitempty"`
 	VxlanMode  string `protobuf:"bytes,4,opt,name=vxlan_mode,json=vxlanMode,proto3" json:"vxlan_mode,omitempty"`
+	// ID of the IP set of the workloads that are source NATed to the
+	// node's address in this pool, empty if the pool is not an egress SNAT pool.
+	EgressSnatIpSetId string `protobuf:"bytes,5,opt,name=egress_snat_ip_set_id,json=egressSnatIpSetId,proto3" json:"egress_snat_ip_set_id,omitempty"`
 }
 
 func (m *IPAMPool) Reset()                    { *m = IPAMPool{} }
@@ -3709,6 +3916,13 @@ func (m *IPAMPool) GetVxlanMode() string {
 	return ""
 }
 
+func (m *IPAMPool) GetEgressSnatIpSetId() string {
+	if m != nil {
+		return m.EgressSnatIpSetId
+	}
+	return ""
+}
+
 type Encapsulation struct {
 	IpipEnabled    bool `protobuf:"varint,1,opt,name=ipip_enabled,json=ipipEnabled,proto3" json:"ipip_enabled,omitempty"`
 	VxlanEnabled   bool `protobuf:"varint,2,opt,name=vxlan_enabled,json=vxlanEnabled,proto3" json:"vxlan_enabled,omitempty"`
@@ -4345,6 +4559,46 @@ func (m *ServiceRemove) GetNamespace() string {
 	return ""
 }
 
+type PortMapping struct {
+	Protocol      string `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	HostPort      int32  `protobuf:"varint,2,opt,name=host_port,json=hostPort,proto3" json:"host_port,omitempty"`
+	ContainerPort int32  `protobuf:"varint,3,opt,name=container_port,json=containerPort,proto3" json:"container_port,omitempty"`
+	HostIp        string `protobuf:"bytes,4,opt,name=host_ip,json=hostIp,proto3" json:"host_ip,omitempty"`
+}
+
+func (m *PortMapping) Reset()                    { *m = PortMapping{} }
+func (m *PortMapping) String() string            { return proto1.CompactTextString(m) }
+func (*PortMapping) ProtoMessage()               {}
+func (*PortMapping) Descriptor() ([]byte, []int) { return fileDescriptorFelixbackend, []int{71} }
+
+func (m *PortMapping) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *PortMapping) GetHostPort() int32 {
+	if m != nil {
+		return m.HostPort
+	}
+	return 0
+}
+
+func (m *PortMapping) GetContainerPort() int32 {
+	if m != nil {
+		return m.ContainerPort
+	}
+	return 0
+}
+
+func (m *PortMapping) GetHostIp() string {
+	if m != nil {
+		return m.HostIp
+	}
+	return ""
+}
+
 func init() {
 	proto1.RegisterType((*SyncRequest)(nil), "felix.SyncRequest")
 	proto1.RegisterType((*ToDataplane)(nil), "felix.ToDataplane")
@@ -4418,6 +4672,7 @@ func init() {
 	proto1.RegisterType((*ServicePort)(nil), "felix.ServicePort")
 	proto1.RegisterType((*ServiceUpdate)(nil), "felix.ServiceUpdate")
 	proto1.RegisterType((*ServiceRemove)(nil), "felix.ServiceRemove")
+	proto1.RegisterType((*PortMapping)(nil), "felix.PortMapping")
 	proto1.RegisterEnum("felix.IPVersion", IPVersion_name, IPVersion_value)
 	proto1.RegisterEnum("felix.RouteType", RouteType_name, RouteType_value)
 	proto1.RegisterEnum("felix.IPPoolType", IPPoolType_name, IPPoolType_value)
@@ -5717,6 +5972,11 @@ func (m *ActivePolicyUpdate) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n52
 	}
+	if m.BitmapIndex != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.BitmapIndex))
+	}
 	return i, nil
 }
 
@@ -6024,6 +6284,64 @@ func (m *Rule) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if len(m.DstDomainIpSetIds) > 0 {
+		for _, s := range m.DstDomainIpSetIds {
+			dAtA[i] = 0x82
+			i++
+			dAtA[i] = 0x1
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.Dscp != nil {
+		nn66, err := m.Dscp.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn66
+	}
+	if len(m.IcmpTypes) > 0 {
+		dAtA69 := make([]byte, len(m.IcmpTypes)*10)
+		var j68 int
+		for _, num1 := range m.IcmpTypes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA69[j68] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j68++
+			}
+			dAtA69[j68] = uint8(num)
+			j68++
+		}
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(j68))
+		i += copy(dAtA[i:], dAtA69[:j68])
+	}
+	if len(m.IcmpTypeCodes) > 0 {
+		for _, msg := range m.IcmpTypeCodes {
+			dAtA[i] = 0x9a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintFelixbackend(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.NotProtocol != nil {
 		dAtA[i] = 0xb2
 		i++
@@ -6139,6 +6457,13 @@ func (m *Rule) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if m.NotDscp != nil {
+		nn67, err := m.NotDscp.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn67
+	}
 	if len(m.NotSrcNamedPortIpSetIds) > 0 {
 		for _, s := range m.NotSrcNamedPortIpSetIds {
 			dAtA[i] = 0x82
@@ -6269,6 +6594,37 @@ func (m *Rule) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n61
 	}
+	if len(m.LogTarget) > 0 {
+		dAtA[i] = 0xe2
+		i++
+		dAtA[i] = 0x7
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.LogTarget)))
+		i += copy(dAtA[i:], m.LogTarget)
+	}
+	if len(m.LogSyslogFacility) > 0 {
+		dAtA[i] = 0xea
+		i++
+		dAtA[i] = 0x7
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.LogSyslogFacility)))
+		i += copy(dAtA[i:], m.LogSyslogFacility)
+	}
+	if len(m.LogRateLimit) > 0 {
+		dAtA[i] = 0xf2
+		i++
+		dAtA[i] = 0x7
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.LogRateLimit)))
+		i += copy(dAtA[i:], m.LogRateLimit)
+	}
+	if m.LogRateLimitBurst != 0 {
+		dAtA[i] = 0xf8
+		i++
+		dAtA[i] = 0x7
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.LogRateLimitBurst))
+	}
 	if len(m.OriginalDstService) > 0 {
 		dAtA[i] = 0x92
 		i++
@@ -6333,6 +6689,15 @@ func (m *Rule_IcmpTypeCode) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
+func (m *Rule_DscpValue) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	dAtA[i] = 0x88
+	i++
+	dAtA[i] = 0x1
+	i++
+	i = encodeVarintFelixbackend(dAtA, i, uint64(m.DscpValue))
+	return i, nil
+}
 func (m *Rule_NotIcmpType) MarshalTo(dAtA []byte) (int, error) {
 	i := 0
 	dAtA[i] = 0xd8
@@ -6358,6 +6723,15 @@ func (m *Rule_NotIcmpTypeCode) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
+func (m *Rule_NotDscpValue) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	dAtA[i] = 0xf8
+	i++
+	dAtA[i] = 0x6
+	i++
+	i = encodeVarintFelixbackend(dAtA, i, uint64(m.NotDscpValue))
+	return i, nil
+}
 func (m *ServiceAccountMatch) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -6834,6 +7208,24 @@ func (m *WorkloadEndpoint) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], v)
 		}
 	}
+	if len(m.PortMappings) > 0 {
+		for _, msg := range m.PortMappings {
+			dAtA[i] = 0x62
+			i++
+			i = encodeVarintFelixbackend(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.PolicyBitmap) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.PolicyBitmap)))
+		i += copy(dAtA[i:], m.PolicyBitmap)
+	}
 	return i, nil
 }
 
@@ -7041,6 +7433,12 @@ func (m *HostEndpoint) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.PolicyBitmap) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.PolicyBitmap)))
+		i += copy(dAtA[i:], m.PolicyBitmap)
+	}
 	return i, nil
 }
 
@@ -7699,6 +8097,12 @@ func (m *IPAMPool) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.VxlanMode)))
 		i += copy(dAtA[i:], m.VxlanMode)
 	}
+	if len(m.EgressSnatIpSetId) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.EgressSnatIpSetId)))
+		i += copy(dAtA[i:], m.EgressSnatIpSetId)
+	}
 	return i, nil
 }
 
@@ -8514,6 +8918,46 @@ func (m *ServiceRemove) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *PortMapping) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PortMapping) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Protocol) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.Protocol)))
+		i += copy(dAtA[i:], m.Protocol)
+	}
+	if m.HostPort != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.HostPort))
+	}
+	if m.ContainerPort != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.ContainerPort))
+	}
+	if len(m.HostIp) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(len(m.HostIp)))
+		i += copy(dAtA[i:], m.HostIp)
+	}
+	return i, nil
+}
+
 func encodeVarintFelixbackend(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -9118,6 +9562,9 @@ func (m *ActivePolicyUpdate) Size() (n int) {
 		l = m.Policy.Size()
 		n += 1 + l + sovFelixbackend(uint64(l))
 	}
+	if m.BitmapIndex != 0 {
+		n += 1 + sovFelixbackend(uint64(m.BitmapIndex))
+	}
 	return n
 }
 
@@ -9248,7 +9695,29 @@ func (m *Rule) Size() (n int) {
 			n += 1 + l + sovFelixbackend(uint64(l))
 		}
 	}
-	if m.NotProtocol != nil {
+	if len(m.DstDomainIpSetIds) > 0 {
+		for _, s := range m.DstDomainIpSetIds {
+			l = len(s)
+			n += 2 + l + sovFelixbackend(uint64(l))
+		}
+	}
+	if m.Dscp != nil {
+		n += m.Dscp.Size()
+	}
+	if len(m.IcmpTypes) > 0 {
+		l = 0
+		for _, e := range m.IcmpTypes {
+			l += sovFelixbackend(uint64(e))
+		}
+		n += 2 + sovFelixbackend(uint64(l)) + l
+	}
+	if len(m.IcmpTypeCodes) > 0 {
+		for _, e := range m.IcmpTypeCodes {
+			l = e.Size()
+			n += 2 + l + sovFelixbackend(uint64(l))
+		}
+	}
+	if m.NotProtocol != nil {
 		l = m.NotProtocol.Size()
 		n += 2 + l + sovFelixbackend(uint64(l))
 	}
@@ -9291,6 +9760,9 @@ func (m *Rule) Size() (n int) {
 			n += 2 + l + sovFelixbackend(uint64(l))
 		}
 	}
+	if m.NotDscp != nil {
+		n += m.NotDscp.Size()
+	}
 	if len(m.NotSrcNamedPortIpSetIds) > 0 {
 		for _, s := range m.NotSrcNamedPortIpSetIds {
 			l = len(s)
@@ -9343,6 +9815,21 @@ func (m *Rule) Size() (n int) {
 		l = m.Metadata.Size()
 		n += 2 + l + sovFelixbackend(uint64(l))
 	}
+	l = len(m.LogTarget)
+	if l > 0 {
+		n += 2 + l + sovFelixbackend(uint64(l))
+	}
+	l = len(m.LogSyslogFacility)
+	if l > 0 {
+		n += 2 + l + sovFelixbackend(uint64(l))
+	}
+	l = len(m.LogRateLimit)
+	if l > 0 {
+		n += 2 + l + sovFelixbackend(uint64(l))
+	}
+	if m.LogRateLimitBurst != 0 {
+		n += 2 + sovFelixbackend(uint64(m.LogRateLimitBurst))
+	}
 	l = len(m.OriginalDstService)
 	if l > 0 {
 		n += 2 + l + sovFelixbackend(uint64(l))
@@ -9381,6 +9868,12 @@ func (m *Rule_IcmpTypeCode) Size() (n int) {
 	}
 	return n
 }
+func (m *Rule_DscpValue) Size() (n int) {
+	var l int
+	_ = l
+	n += 2 + sovFelixbackend(uint64(m.DscpValue))
+	return n
+}
 func (m *Rule_NotIcmpType) Size() (n int) {
 	var l int
 	_ = l
@@ -9396,6 +9889,12 @@ func (m *Rule_NotIcmpTypeCode) Size() (n int) {
 	}
 	return n
 }
+func (m *Rule_NotDscpValue) Size() (n int) {
+	var l int
+	_ = l
+	n += 2 + sovFelixbackend(uint64(m.NotDscpValue))
+	return n
+}
 func (m *ServiceAccountMatch) Size() (n int) {
 	var l int
 	_ = l
@@ -9610,6 +10109,16 @@ func (m *WorkloadEndpoint) Size() (n int) {
 			n += mapEntrySize + 1 + sovFelixbackend(uint64(mapEntrySize))
 		}
 	}
+	if len(m.PortMappings) > 0 {
+		for _, e := range m.PortMappings {
+			l = e.Size()
+			n += 1 + l + sovFelixbackend(uint64(l))
+		}
+	}
+	l = len(m.PolicyBitmap)
+	if l > 0 {
+		n += 1 + l + sovFelixbackend(uint64(l))
+	}
 	return n
 }
 
@@ -9696,6 +10205,10 @@ func (m *HostEndpoint) Size() (n int) {
 			n += 1 + l + sovFelixbackend(uint64(l))
 		}
 	}
+	l = len(m.PolicyBitmap)
+	if l > 0 {
+		n += 1 + l + sovFelixbackend(uint64(l))
+	}
 	return n
 }
 
@@ -9977,6 +10490,10 @@ func (m *IPAMPool) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovFelixbackend(uint64(l))
 	}
+	l = len(m.EgressSnatIpSetId)
+	if l > 0 {
+		n += 1 + l + sovFelixbackend(uint64(l))
+	}
 	return n
 }
 
@@ -10329,6 +10846,26 @@ func (m *ServiceRemove) Size() (n int) {
 	return n
 }
 
+func (m *PortMapping) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Protocol)
+	if l > 0 {
+		n += 1 + l + sovFelixbackend(uint64(l))
+	}
+	if m.HostPort != 0 {
+		n += 1 + sovFelixbackend(uint64(m.HostPort))
+	}
+	if m.ContainerPort != 0 {
+		n += 1 + sovFelixbackend(uint64(m.ContainerPort))
+	}
+	l = len(m.HostIp)
+	if l > 0 {
+		n += 1 + l + sovFelixbackend(uint64(l))
+	}
+	return n
+}
+
 func sovFelixbackend(x uint64) (n int) {
 	for {
 		n++
@@ -13322,6 +13859,25 @@ func (m *ActivePolicyUpdate) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BitmapIndex", wireType)
+			}
+			m.BitmapIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BitmapIndex |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFelixbackend(dAtA[iNdEx:])
@@ -14171,6 +14727,148 @@ func (m *Rule) Unmarshal(dAtA []byte) error {
 			}
 			m.DstIpPortSetIds = append(m.DstIpPortSetIds, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DstDomainIpSetIds", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DstDomainIpSetIds = append(m.DstDomainIpSetIds, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DscpValue", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Dscp = &Rule_DscpValue{v}
+		case 18:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFelixbackend
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (int32(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.IcmpTypes = append(m.IcmpTypes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFelixbackend
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthFelixbackend
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFelixbackend
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (int32(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.IcmpTypes = append(m.IcmpTypes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field IcmpTypes", wireType)
+			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IcmpTypeCodes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IcmpTypeCodes = append(m.IcmpTypeCodes, &IcmpTypeAndCode{})
+			if err := m.IcmpTypeCodes[len(m.IcmpTypeCodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 102:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field NotProtocol", wireType)
@@ -14434,6 +15132,26 @@ func (m *Rule) Unmarshal(dAtA []byte) error {
 			}
 			m.NotDstIpSetIds = append(m.NotDstIpSetIds, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 111:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotDscpValue", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NotDscp = &Rule_NotDscpValue{v}
 		case 112:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field NotSrcNamedPortIpSetIds", wireType)
@@ -14765,11 +15483,131 @@ func (m *Rule) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		case 123:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+		case 123:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &RuleMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 124:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogTarget", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogTarget = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 125:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogSyslogFacility", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogSyslogFacility = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 126:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogRateLimit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogRateLimit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 127:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogRateLimitBurst", wireType)
 			}
-			var msglen int
+			m.LogRateLimitBurst = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFelixbackend
@@ -14779,25 +15617,11 @@ func (m *Rule) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.LogRateLimitBurst |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthFelixbackend
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Metadata == nil {
-				m.Metadata = &RuleMetadata{}
-			}
-			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 130:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field OriginalDstService", wireType)
@@ -16429,6 +17253,68 @@ func (m *WorkloadEndpoint) Unmarshal(dAtA []byte) error {
 			}
 			m.Annotations[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PortMappings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PortMappings = append(m.PortMappings, &PortMapping{})
+			if err := m.PortMappings[len(m.PortMappings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PolicyBitmap", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PolicyBitmap = append(m.PolicyBitmap[:0], dAtA[iNdEx:postIndex]...)
+			if m.PolicyBitmap == nil {
+				m.PolicyBitmap = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFelixbackend(dAtA[iNdEx:])
@@ -16997,6 +17883,37 @@ func (m *HostEndpoint) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PolicyBitmap", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PolicyBitmap = append(m.PolicyBitmap[:0], dAtA[iNdEx:postIndex]...)
+			if m.PolicyBitmap == nil {
+				m.PolicyBitmap = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFelixbackend(dAtA[iNdEx:])
@@ -19212,6 +20129,35 @@ func (m *IPAMPool) Unmarshal(dAtA []byte) error {
 			}
 			m.VxlanMode = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EgressSnatIpSetId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EgressSnatIpSetId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFelixbackend(dAtA[iNdEx:])
@@ -21936,6 +22882,152 @@ func (m *ServiceRemove) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PortMapping) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFelixbackend
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PortMapping: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PortMapping: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Protocol", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Protocol = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPort", wireType)
+			}
+			m.HostPort = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HostPort |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerPort", wireType)
+			}
+			m.ContainerPort = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ContainerPort |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostIp", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostIp = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFelixbackend(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFelixbackend
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipFelixbackend(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -22044,269 +23136,289 @@ var (
 func init() { proto1.RegisterFile("felixbackend.proto", fileDescriptorFelixbackend) }
 
 var fileDescriptorFelixbackend = []byte{
-	// 4220 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5b, 0xcd, 0x73, 0x1c, 0x49,
-	0x56, 0x57, 0xb7, 0xa4, 0x56, 0xf7, 0xeb, 0xaf, 0x72, 0xea, 0xab, 0x25, 0xdb, 0xb2, 0xa7, 0x3c,
-	0xde, 0xd1, 0x78, 0x77, 0x3c, 0x46, 0x23, 0xb7, 0xd7, 0xc3, 0x32, 0x1b, 0x6d, 0xb5, 0x66, 0xd4,
-	0x33, 0xb6, 0x24, 0x4a, 0x1a, 0x0d, 0xb3, 0x6c, 0x44, 0x51, 0xaa, 0x4a, 0x49, 0x85, 0xab, 0xab,
-	0x6a, 0xaa, 0xb2, 0xf5, 0xb1, 0x9c, 0x80, 0x25, 0x02, 0x82, 0x03, 0x1c, 0x08, 0x82, 0x3f, 0x80,
-	0x13, 0xc1, 0x7f, 0xc0, 0x81, 0xeb, 0x6e, 0x70, 0x81, 0xe0, 0x4c, 0x04, 0x31, 0xdc, 0xb8, 0x41,
-	0x04, 0x77, 0x22, 0x3f, 0xeb, 0xa3, 0xab, 0x65, 0x99, 0x1d, 0xf6, 0xa4, 0xce, 0x97, 0xef, 0xfd,
-	0xf2, 0x97, 0xaf, 0x5e, 0xbe, 0xfc, 0x14, 0xa0, 0x13, 0xec, 0xb9, 0x97, 0xc7, 0x96, 0xfd, 0x1a,
-	0xfb, 0xce, 0xe3, 0x30, 0x0a, 0x48, 0x80, 0x66, 0x99, 0x4c, 0x6f, 0x42, 0xfd, 0xe0, 0xca, 0xb7,
-	0x0d, 0xfc, 0xcd, 0x08, 0xc7, 0x44, 0xff, 0xa7, 0x25, 0xa8, 0x1f, 0x06, 0x7d, 0x8b, 0x58, 0xa1,
-	0x67, 0xf9, 0x18, 0xad, 0xc3, 0x9c, 0xeb, 0x9b, 0xf1, 0x95, 0x6f, 0x77, 0x4a, 0xf7, 0x4b, 0xeb,
-	0xf5, 0x8d, 0xe6, 0x63, 0x66, 0xf7, 0x78, 0xe0, 0x53, 0xb3, 0x9d, 0x29, 0xa3, 0xe2, 0xb2, 0x5f,
-	0xe8, 0x19, 0x34, 0xdc, 0x30, 0xc6, 0xc4, 0x1c, 0x85, 0x8e, 0x45, 0x70, 0xa7, 0xcc, 0xd4, 0x91,
-	0x54, 0xdf, 0x3f, 0xc0, 0xe4, 0x4b, 0x56, 0xb3, 0x33, 0x65, 0xd4, 0x99, 0x26, 0x2f, 0xa2, 0xcf,
-	0x00, 0x71, 0x43, 0x07, 0x7b, 0xc4, 0x92, 0xe6, 0xd3, 0xcc, 0x7c, 0x39, 0x6d, 0xde, 0xa7, 0xf5,
-	0x0a, 0x43, 0x63, 0x46, 0x29, 0x59, 0xc2, 0x20, 0xc2, 0xc3, 0xe0, 0x1c, 0x77, 0x66, 0xc6, 0x19,
-	0x18, 0xac, 0x46, 0x31, 0xe0, 0x45, 0xb4, 0x0f, 0x8b, 0x96, 0x4d, 0xdc, 0x73, 0x6c, 0x86, 0x51,
-	0x70, 0xe2, 0x7a, 0x58, 0x92, 0x98, 0x65, 0x08, 0xab, 0x02, 0xa1, 0xc7, 0x74, 0xf6, 0xb9, 0x8a,
-	0xe2, 0x31, 0x6f, 0x8d, 0x8b, 0x0b, 0x10, 0x05, 0xa7, 0xca, 0x64, 0x44, 0xc5, 0x2d, 0x8b, 0x28,
-	0x38, 0xbe, 0x82, 0x05, 0x89, 0x18, 0x78, 0xae, 0x7d, 0x25, 0x29, 0xce, 0x31, 0xc0, 0x95, 0x2c,
-	0x20, 0xd3, 0x50, 0x0c, 0x91, 0x35, 0x26, 0x1d, 0x87, 0x13, 0xfc, 0xaa, 0x13, 0xe1, 0x14, 0xbd,
-	0x0c, 0x5c, 0xc2, 0xee, 0x2c, 0x88, 0x89, 0x89, 0x7d, 0x27, 0x0c, 0x5c, 0x5f, 0x05, 0x41, 0x2d,
-	0x03, 0xb7, 0x13, 0xc4, 0x64, 0x5b, 0x68, 0x24, 0xec, 0xce, 0xc6, 0xa4, 0xe3, 0x70, 0x82, 0x1d,
-	0x4c, 0x84, 0x4b, 0xd8, 0x9d, 0x8d, 0x49, 0xd1, 0xd7, 0xd0, 0xb9, 0x08, 0xa2, 0xd7, 0x5e, 0x60,
-	0x39, 0x63, 0x0c, 0xeb, 0x0c, 0xf2, 0xae, 0x80, 0xfc, 0x4a, 0xa8, 0x8d, 0xb1, 0x5c, 0xba, 0x28,
-	0xac, 0x29, 0x86, 0x16, 0x6c, 0x1b, 0xd7, 0x42, 0x2b, 0xc6, 0x63, 0xd0, 0x82, 0xf5, 0xc7, 0xd0,
-	0xb4, 0x03, 0xff, 0xc4, 0x3d, 0x95, 0x54, 0x9b, 0x0c, 0x6f, 0x5e, 0xe0, 0x6d, 0xb1, 0x3a, 0x45,
-	0xb0, 0x61, 0xa7, 0xca, 0xca, 0x81, 0x43, 0x4c, 0x2c, 0xc7, 0x4a, 0x46, 0x55, 0x6b, 0xcc, 0x81,
-	0xaf, 0x84, 0x46, 0xf6, 0x7b, 0x64, 0xa5, 0xe8, 0x3d, 0x68, 0xc7, 0x34, 0x41, 0xf8, 0x36, 0x36,
-	0xfd, 0xd1, 0xf0, 0x18, 0x47, 0x9d, 0xf6, 0xfd, 0xd2, 0xfa, 0x8c, 0xd1, 0x92, 0xe2, 0x5d, 0x26,
-	0x45, 0x3d, 0xd0, 0xdc, 0xd0, 0x1a, 0x9a, 0x61, 0x10, 0x78, 0xb2, 0x4d, 0x8d, 0xb5, 0xb9, 0xa8,
-	0x86, 0x61, 0xef, 0xd5, 0x7e, 0x10, 0x78, 0xaa, 0xbd, 0x16, 0x35, 0x48, 0x24, 0x59, 0x08, 0xe1,
-	0xc9, 0x5b, 0x85, 0x10, 0xca, 0x83, 0x0a, 0x22, 0x17, 0x8d, 0xaa, 0xf7, 0x02, 0x06, 0x4d, 0xec,
-	0x7d, 0x36, 0x7c, 0xb2, 0x52, 0x74, 0x00, 0x4b, 0x31, 0x8e, 0xce, 0x5d, 0x1b, 0x9b, 0x96, 0x6d,
-	0x07, 0xa3, 0x24, 0x78, 0xe6, 0x19, 0xe0, 0x6d, 0x01, 0x78, 0xc0, 0x95, 0x7a, 0x5c, 0x47, 0x75,
-	0x70, 0x21, 0x2e, 0x90, 0x17, 0x81, 0x0a, 0x96, 0x0b, 0xd7, 0x80, 0x2a, 0x9e, 0x39, 0x50, 0xc1,
-	0x74, 0x0b, 0x34, 0xdf, 0x1a, 0xe2, 0x38, 0xb4, 0x6c, 0x95, 0xc3, 0x16, 0x19, 0xdc, 0x92, 0x80,
-	0xdb, 0x95, 0xd5, 0x8a, 0x5e, 0xdb, 0xcf, 0x8a, 0xb2, 0x20, 0x82, 0xd3, 0x52, 0x31, 0x88, 0xa2,
-	0x93, 0x80, 0x08, 0x26, 0xcf, 0xa0, 0x11, 0x05, 0x23, 0xa2, 0x58, 0x2c, 0x67, 0x72, 0xb1, 0x41,
-	0xab, 0x92, 0xd9, 0x20, 0x4a, 0x8a, 0x89, 0xa1, 0x68, 0xb9, 0x33, 0x6e, 0x98, 0x24, 0xf1, 0x28,
-	0x29, 0xa2, 0x2d, 0xa8, 0x9f, 0x13, 0x1c, 0xca, 0x06, 0x57, 0x98, 0xdd, 0x7d, 0x61, 0x77, 0xf4,
-	0x3b, 0x2f, 0x7b, 0xbb, 0x87, 0x23, 0xdf, 0xc7, 0xde, 0xd8, 0xd0, 0x06, 0x6a, 0xa6, 0xfa, 0xce,
-	0x41, 0x44, 0xe3, 0xab, 0x6f, 0x02, 0x51, 0x54, 0x18, 0x88, 0x60, 0xf2, 0x53, 0x58, 0xb9, 0x70,
-	0x23, 0x7c, 0x3a, 0xb2, 0xa2, 0xf1, 0x7c, 0x73, 0x9b, 0x41, 0xae, 0xc9, 0xa4, 0x20, 0xf5, 0xc6,
-	0x58, 0x2d, 0x5f, 0x14, 0x57, 0x4d, 0x40, 0x17, 0x84, 0xef, 0x5c, 0x8f, 0xae, 0xe8, 0x8e, 0xa3,
-	0x0b, 0xee, 0x5f, 0x41, 0xe7, 0xd4, 0x0b, 0x8e, 0x2d, 0xcf, 0x3c, 0x3e, 0x0d, 0xcd, 0x6c, 0xfe,
-	0xb9, 0xcb, 0xc0, 0xef, 0x08, 0xf0, 0xcf, 0x98, 0xda, 0x8b, 0xcf, 0xf6, 0x73, 0x89, 0x68, 0x91,
-	0xdb, 0xbf, 0x38, 0x0d, 0xd3, 0x15, 0xe8, 0x47, 0xd0, 0xc4, 0xbe, 0x6d, 0x85, 0xf1, 0xc8, 0xb3,
-	0x88, 0x1b, 0xf8, 0x9d, 0x35, 0x86, 0xb6, 0x20, 0xd0, 0xb6, 0xd3, 0x75, 0x3b, 0x53, 0x46, 0x56,
-	0x19, 0xfd, 0x16, 0xb4, 0xe4, 0x68, 0x11, 0x64, 0xee, 0x65, 0xcc, 0xc5, 0x28, 0x51, 0x24, 0x9a,
-	0x71, 0x5a, 0x90, 0x36, 0x17, 0x8e, 0xba, 0x5f, 0x64, 0xae, 0xdc, 0x23, 0xcd, 0x85, 0x53, 0x6c,
-	0xb8, 0x53, 0xe0, 0xf2, 0xf3, 0xae, 0xe4, 0xf2, 0x4e, 0x26, 0x4c, 0xc6, 0xbc, 0x7e, 0xd4, 0x55,
-	0xbc, 0x56, 0x2e, 0x26, 0x55, 0x4e, 0x6e, 0x44, 0x30, 0xd6, 0xdf, 0xd4, 0x88, 0x62, 0x5f, 0xd4,
-	0x88, 0xe8, 0xc9, 0x21, 0x2c, 0x67, 0x33, 0x63, 0xd2, 0x89, 0x07, 0x99, 0xb4, 0x93, 0x4e, 0x8e,
-	0x29, 0xfe, 0x0b, 0x67, 0x05, 0xf2, 0x42, 0x54, 0xc1, 0xfa, 0xdd, 0x6b, 0x50, 0x93, 0x64, 0x76,
-	0x56, 0x20, 0x47, 0x3f, 0x81, 0x95, 0x1c, 0xea, 0x66, 0xc2, 0xf6, 0x61, 0x66, 0x6e, 0xcd, 0xe0,
-	0x6e, 0xa6, 0xf8, 0x2e, 0x65, 0x90, 0x37, 0xcf, 0x25, 0xe3, 0x62, 0x6c, 0xc1, 0xf9, 0x7b, 0xd7,
-	0x62, 0x27, 0xf3, 0x76, 0x1e, 0x9b, 0xd7, 0xbc, 0xa8, 0xc1, 0x5c, 0x68, 0x5d, 0xd1, 0x09, 0x5d,
-	0xff, 0xd7, 0x59, 0x68, 0x7e, 0x1a, 0x05, 0xc3, 0x64, 0x3d, 0xbd, 0x0f, 0x8b, 0x61, 0x14, 0xd8,
-	0x38, 0x8e, 0xcd, 0x98, 0x58, 0x64, 0x14, 0x67, 0xd7, 0xbb, 0x72, 0x61, 0xb8, 0xcf, 0x75, 0x0e,
-	0x98, 0x4a, 0xb2, 0xd4, 0x0c, 0xc7, 0xc5, 0xe8, 0xf7, 0xe0, 0x76, 0x76, 0xad, 0x94, 0xc5, 0xe5,
-	0x8b, 0xe0, 0x7b, 0x05, 0x4b, 0xa6, 0x1c, 0x78, 0xe7, 0x6c, 0x42, 0xdd, 0xc4, 0x16, 0x84, 0xbb,
-	0x66, 0xdf, 0xd0, 0x82, 0x72, 0x58, 0x41, 0x0b, 0xe2, 0x53, 0x7b, 0x70, 0x6f, 0x7c, 0x15, 0x95,
-	0xed, 0x07, 0x5f, 0x38, 0x3f, 0x98, 0xb0, 0x98, 0xca, 0xf5, 0xe5, 0xce, 0xc5, 0x35, 0xf5, 0xd7,
-	0xb6, 0x26, 0xfa, 0x34, 0x77, 0x83, 0xd6, 0x54, 0xbf, 0x26, 0xb4, 0x26, 0xfa, 0x56, 0xb0, 0x76,
-	0xaa, 0x16, 0xae, 0x9d, 0x8e, 0x20, 0xc9, 0xca, 0xb9, 0xce, 0xd7, 0x32, 0x99, 0x57, 0x8d, 0xfd,
-	0x5c, 0xaf, 0x17, 0x2f, 0x8a, 0x2a, 0x50, 0x1f, 0x6e, 0x39, 0x32, 0xfe, 0x4c, 0xb9, 0x99, 0x83,
-	0xcc, 0x84, 0xae, 0xe2, 0x53, 0xed, 0xea, 0xda, 0x4e, 0x56, 0x94, 0x8e, 0xea, 0x7f, 0x29, 0x43,
-	0x23, 0x93, 0xdb, 0x9f, 0x41, 0x85, 0xcf, 0x14, 0x9d, 0xd2, 0xfd, 0xe9, 0x54, 0x2c, 0xa4, 0x95,
-	0x44, 0x61, 0xdb, 0x27, 0xd1, 0x95, 0x21, 0xd4, 0xd1, 0xef, 0xc2, 0x42, 0x1c, 0x8c, 0x22, 0x1b,
-	0x9b, 0x24, 0x30, 0x23, 0xeb, 0x42, 0x4c, 0x38, 0x9d, 0x32, 0x83, 0x79, 0x54, 0x04, 0x73, 0xc0,
-	0xf4, 0x0f, 0x03, 0xc3, 0xba, 0x48, 0x23, 0xde, 0x8a, 0xf3, 0x72, 0xd4, 0x81, 0xb9, 0x21, 0x8e,
-	0x63, 0xeb, 0x94, 0x0f, 0xae, 0x9a, 0x21, 0x8b, 0xab, 0xcf, 0xa1, 0x9e, 0xb2, 0x45, 0x1a, 0x4c,
-	0xbf, 0xc6, 0x57, 0x6c, 0x7f, 0x5b, 0x33, 0xe8, 0x4f, 0xb4, 0x00, 0xb3, 0xe7, 0x96, 0x37, 0xe2,
-	0x9b, 0xd8, 0x9a, 0xc1, 0x0b, 0x1f, 0x97, 0x7f, 0x58, 0x5a, 0x3d, 0x82, 0xa5, 0x62, 0x06, 0x69,
-	0x94, 0x26, 0x47, 0xf9, 0x5e, 0x1a, 0xa5, 0xbe, 0xa1, 0xc9, 0x35, 0x8c, 0xb4, 0x4b, 0xe1, 0xea,
-	0x7f, 0x55, 0x82, 0x5a, 0x42, 0x7d, 0x09, 0x2a, 0xbc, 0x3f, 0x82, 0x94, 0x28, 0xa1, 0x4d, 0xe5,
-	0x68, 0xee, 0xa1, 0x3b, 0x79, 0xc8, 0x22, 0x2f, 0xff, 0x0a, 0xdd, 0xd5, 0xab, 0x50, 0xe1, 0xdf,
-	0x5f, 0xff, 0x9b, 0x12, 0xd4, 0x53, 0x9b, 0x78, 0xd4, 0x82, 0xb2, 0xeb, 0x08, 0x90, 0xb2, 0xeb,
-	0x70, 0x6f, 0xd3, 0x38, 0x8e, 0x19, 0x37, 0xe6, 0x6d, 0x56, 0x44, 0x4f, 0x60, 0x86, 0x5c, 0x85,
-	0xfc, 0x23, 0xb4, 0x14, 0xe5, 0x14, 0x16, 0xff, 0x7d, 0x78, 0x15, 0x62, 0x83, 0x69, 0xea, 0x1f,
-	0x40, 0x4d, 0x89, 0x50, 0x05, 0xca, 0x83, 0x7d, 0x6d, 0x0a, 0xb5, 0x69, 0xfb, 0x66, 0x6f, 0xb7,
-	0x6f, 0xee, 0xef, 0x19, 0x87, 0x5a, 0x09, 0xcd, 0xc1, 0xf4, 0xee, 0xf6, 0xa1, 0x56, 0xd6, 0x43,
-	0xd0, 0xf2, 0xe7, 0x03, 0x63, 0xf4, 0x1e, 0x40, 0xd3, 0x72, 0x1c, 0xec, 0x98, 0x59, 0x92, 0x0d,
-	0x26, 0x7c, 0x25, 0x98, 0xbe, 0x07, 0x6d, 0x3e, 0xfe, 0x13, 0xb5, 0x69, 0xa6, 0xd6, 0x12, 0x62,
-	0xa1, 0xa8, 0xdf, 0x15, 0xbe, 0x10, 0x43, 0x3c, 0xd7, 0x98, 0x6e, 0xc1, 0x7c, 0xc1, 0x59, 0x01,
-	0xba, 0xaf, 0xd4, 0x92, 0x60, 0x10, 0x1a, 0x83, 0x3e, 0x63, 0xb9, 0x0e, 0x73, 0xe2, 0xbc, 0x40,
-	0xc4, 0x4c, 0x2b, 0xab, 0x66, 0xc8, 0x6a, 0xfd, 0x59, 0xae, 0x09, 0xc1, 0xe4, 0x8d, 0x4d, 0xe8,
-	0xf7, 0xa0, 0xa6, 0x04, 0x08, 0xc1, 0x0c, 0x5d, 0xb8, 0x0b, 0xea, 0xec, 0xb7, 0x1e, 0xc0, 0x9c,
-	0x50, 0x40, 0x4f, 0xa0, 0xe9, 0xfa, 0xc7, 0xc1, 0xc8, 0x77, 0xcc, 0x68, 0xe4, 0xe1, 0x58, 0x0c,
-	0xef, 0xba, 0x8c, 0xba, 0x91, 0x87, 0x8d, 0x86, 0xd0, 0xa0, 0x85, 0x18, 0x6d, 0x40, 0x2b, 0x18,
-	0x91, 0xb4, 0x49, 0x79, 0xdc, 0xa4, 0x29, 0x55, 0x98, 0x8d, 0xfe, 0x53, 0x40, 0xe3, 0xc7, 0x16,
-	0xe8, 0x5e, 0xaa, 0x27, 0x6d, 0xd9, 0x13, 0xa6, 0x20, 0x7c, 0xf5, 0x10, 0x2a, 0xfc, 0xe8, 0x42,
-	0xb8, 0xaa, 0x99, 0x51, 0x32, 0x44, 0xa5, 0xfe, 0x34, 0x8b, 0x2e, 0xfc, 0xf4, 0x26, 0x74, 0x7d,
-	0x03, 0xaa, 0xb2, 0x4c, 0xbd, 0x44, 0x5c, 0x1c, 0x49, 0x2f, 0xd1, 0xdf, 0xca, 0x73, 0xe5, 0x94,
-	0xe7, 0xfe, 0xbb, 0x04, 0x15, 0x6e, 0xf4, 0xeb, 0xf1, 0x1c, 0xba, 0x03, 0xb5, 0x91, 0x4f, 0x22,
-	0xcb, 0x7e, 0x8d, 0x1d, 0x36, 0xbc, 0xaa, 0x46, 0x22, 0x40, 0x2b, 0x50, 0x0d, 0x23, 0x6c, 0x3a,
-	0xbe, 0x45, 0xd8, 0x2a, 0xa0, 0x4a, 0xa3, 0x07, 0xf7, 0x7d, 0x8b, 0x50, 0x43, 0xb5, 0x61, 0x63,
-	0xf3, 0x77, 0xcd, 0x48, 0x04, 0xe8, 0xfb, 0x70, 0x2b, 0x88, 0xdc, 0x53, 0xd7, 0xb7, 0x3c, 0x33,
-	0xc6, 0x1e, 0xb6, 0x49, 0x10, 0xb1, 0xf9, 0xb7, 0x66, 0x68, 0xb2, 0xe2, 0x40, 0xc8, 0xf5, 0xbf,
-	0xd3, 0x60, 0x86, 0xb2, 0xa1, 0x39, 0xcb, 0xb2, 0xd9, 0xca, 0x5e, 0xe4, 0x2c, 0x5e, 0x42, 0x1f,
-	0x02, 0xb8, 0xa1, 0x79, 0x8e, 0xa3, 0x98, 0xd6, 0x95, 0x59, 0x12, 0xd0, 0x54, 0x12, 0x38, 0xe2,
-	0x72, 0xa3, 0xe6, 0x86, 0xe2, 0x27, 0xfa, 0x3e, 0xe5, 0x1d, 0x90, 0xc0, 0x0e, 0x3c, 0xb1, 0x2a,
-	0x6a, 0x27, 0x91, 0xcc, 0xc4, 0x86, 0x52, 0x40, 0xcb, 0x30, 0x17, 0x47, 0xb6, 0xe9, 0x63, 0xda,
-	0xc7, 0x69, 0x96, 0x2a, 0x23, 0x7b, 0x17, 0x13, 0xf4, 0x01, 0xd4, 0x68, 0x45, 0x18, 0x44, 0x24,
-	0xee, 0xcc, 0x32, 0x57, 0xaa, 0x01, 0x11, 0x44, 0xc4, 0xb0, 0xfc, 0x53, 0x6c, 0x54, 0xe3, 0xc8,
-	0xa6, 0xa5, 0x98, 0xe2, 0x38, 0x31, 0x61, 0x38, 0x15, 0x8e, 0xe3, 0xc4, 0x44, 0xe0, 0xd0, 0x0a,
-	0x8e, 0x33, 0x37, 0x09, 0xc7, 0x89, 0x09, 0xc7, 0xb9, 0x0b, 0x35, 0xd7, 0x1e, 0x86, 0x26, 0xcb,
-	0x78, 0x74, 0x9e, 0x9f, 0xdd, 0x99, 0x32, 0xaa, 0x54, 0xc4, 0x92, 0xd9, 0x27, 0xd0, 0x52, 0xd5,
-	0xa6, 0x1d, 0x38, 0x72, 0x6a, 0x97, 0x13, 0xf1, 0x40, 0x28, 0xf6, 0x7c, 0x67, 0x2b, 0x70, 0xd8,
-	0xb9, 0x8e, 0xb4, 0xa5, 0x65, 0xf4, 0x00, 0x5a, 0xb4, 0x57, 0x6e, 0x68, 0xc6, 0x98, 0x98, 0xae,
-	0x13, 0x77, 0x80, 0xb1, 0xad, 0xc7, 0x91, 0x3d, 0x08, 0x0f, 0x30, 0x19, 0x38, 0x31, 0x55, 0xa2,
-	0x94, 0x53, 0x4a, 0x75, 0xae, 0xe4, 0xc4, 0x44, 0x29, 0x3d, 0x83, 0x15, 0xe6, 0x38, 0x6b, 0x88,
-	0x1d, 0xd6, 0xbb, 0xb4, 0x7e, 0x83, 0xe9, 0x2f, 0x50, 0x57, 0xd2, 0x7a, 0xda, 0xb5, 0xb4, 0x21,
-	0xf3, 0x54, 0xa1, 0x61, 0x93, 0x1b, 0x52, 0xdf, 0x8d, 0x19, 0xfe, 0x00, 0xe6, 0x05, 0x2d, 0x66,
-	0x25, 0x4d, 0xda, 0xcc, 0xa4, 0xcd, 0xb8, 0x51, 0x7d, 0xa1, 0xbd, 0x01, 0x0d, 0x3f, 0x20, 0xa6,
-	0x8a, 0x84, 0x93, 0xe2, 0x48, 0xa8, 0xfb, 0x01, 0x91, 0x05, 0xb4, 0x06, 0xb4, 0x68, 0xca, 0x80,
-	0x38, 0x65, 0xc8, 0x35, 0x3f, 0x20, 0x07, 0x3c, 0x26, 0x36, 0xa1, 0x29, 0xeb, 0xf9, 0xf7, 0x3c,
-	0x9b, 0xf0, 0x3d, 0xeb, 0xdc, 0x86, 0x7f, 0x52, 0x81, 0x2a, 0xc3, 0xc3, 0x55, 0xa8, 0x7d, 0x1e,
-	0x21, 0x02, 0x35, 0x89, 0x92, 0xdf, 0xbf, 0x06, 0xb5, 0x2f, 0x03, 0xe5, 0x5d, 0x6e, 0x95, 0x04,
-	0xcb, 0x6b, 0x16, 0x2c, 0x25, 0xa6, 0x25, 0xc3, 0x00, 0x6d, 0x03, 0xca, 0x68, 0xf1, 0x98, 0xf1,
-	0xae, 0x8d, 0x99, 0x92, 0xd1, 0x4e, 0x41, 0xb0, 0xb0, 0x79, 0xc4, 0x61, 0x72, 0xa1, 0x33, 0xe4,
-	0x73, 0x1b, 0xef, 0xab, 0xfa, 0x4c, 0x42, 0x37, 0x17, 0x41, 0xbe, 0xd2, 0xed, 0xa7, 0x82, 0xe8,
-	0x13, 0xb8, 0xab, 0x1c, 0x5e, 0x18, 0x0f, 0x21, 0x33, 0x5b, 0x16, 0x9f, 0x60, 0x2c, 0x24, 0x84,
-	0xfd, 0xe4, 0x78, 0xfa, 0x46, 0xd9, 0xf7, 0x8b, 0x42, 0x6a, 0x03, 0x16, 0x93, 0x4c, 0x15, 0xd9,
-	0x49, 0xb6, 0x8a, 0x58, 0x0a, 0x9a, 0x57, 0xd9, 0x2a, 0xb2, 0x65, 0xc2, 0xca, 0xd8, 0xd0, 0x86,
-	0x95, 0x4d, 0x9c, 0xb5, 0xe9, 0xc7, 0x44, 0xd9, 0x6c, 0xc3, 0xbd, 0x4c, 0x3b, 0xc9, 0xf9, 0x98,
-	0xb2, 0x26, 0xcc, 0xfa, 0x4e, 0xaa, 0x45, 0x75, 0x4a, 0x56, 0x08, 0x23, 0xfb, 0x9c, 0x83, 0x19,
-	0x65, 0x61, 0x44, 0xaf, 0xb3, 0x30, 0xcf, 0x61, 0x45, 0xc1, 0x48, 0xf7, 0x2b, 0x80, 0x73, 0x06,
-	0xb0, 0x24, 0x15, 0x76, 0x99, 0xe7, 0x27, 0x9a, 0x66, 0x1c, 0x70, 0x31, 0x66, 0x9a, 0xf6, 0xc1,
-	0x97, 0x3c, 0x61, 0xe4, 0x0f, 0x2d, 0x87, 0x16, 0xb1, 0xcf, 0x3a, 0x97, 0x99, 0xdd, 0x6b, 0xf6,
-	0xcc, 0xf2, 0x15, 0xd5, 0x30, 0x96, 0x62, 0x4a, 0x63, 0x4c, 0x4e, 0x61, 0x39, 0x89, 0x22, 0xd8,
-	0xab, 0x37, 0xc3, 0x3a, 0x94, 0xe2, 0x38, 0xec, 0x87, 0x00, 0x67, 0x84, 0x84, 0x02, 0xe7, 0x67,
-	0x99, 0x05, 0xd1, 0xce, 0xe1, 0xe1, 0x3e, 0xb7, 0xae, 0x51, 0x1d, 0x69, 0x50, 0x95, 0x87, 0x01,
-	0x9d, 0x3f, 0xc8, 0x1c, 0xb4, 0xd3, 0xd9, 0x4d, 0x9d, 0x08, 0x2b, 0x25, 0xf4, 0x1b, 0xb0, 0x90,
-	0x8b, 0x23, 0xc6, 0xa2, 0xf3, 0x47, 0x7c, 0xfa, 0x43, 0x99, 0x38, 0x62, 0x55, 0xa8, 0x0f, 0x6b,
-	0x45, 0x26, 0x49, 0x1c, 0x74, 0xfe, 0x98, 0x1b, 0xdf, 0x1e, 0x37, 0x56, 0x61, 0x90, 0x69, 0x38,
-	0xf5, 0x45, 0x3a, 0x3f, 0xcf, 0x35, 0x7c, 0xa0, 0x1c, 0x9e, 0x69, 0x38, 0xfd, 0x11, 0x93, 0x86,
-	0xff, 0x24, 0xd7, 0x70, 0x62, 0x9c, 0x34, 0xdc, 0x81, 0x39, 0xba, 0x32, 0x31, 0x5d, 0xa7, 0xf3,
-	0x4b, 0x31, 0xc7, 0xd3, 0xf2, 0xc0, 0x79, 0x51, 0x81, 0x19, 0x9a, 0xa2, 0x5e, 0x00, 0x54, 0x65,
-	0xba, 0xfa, 0xbc, 0x52, 0xfd, 0x45, 0x49, 0xfb, 0x65, 0xc9, 0x00, 0x2f, 0x38, 0x35, 0xc3, 0x08,
-	0x9f, 0xb8, 0x97, 0xfa, 0x67, 0x30, 0x5f, 0xf4, 0xb1, 0x56, 0xa1, 0xaa, 0x82, 0x90, 0x03, 0xab,
-	0x32, 0xdd, 0x9b, 0x30, 0x96, 0x62, 0xc1, 0xce, 0x0b, 0xfa, 0xdf, 0x96, 0xa0, 0xa6, 0x3e, 0x23,
-	0xdf, 0x7b, 0x90, 0xb3, 0xc0, 0xe1, 0xeb, 0x2c, 0xb6, 0xf7, 0x60, 0x45, 0xf4, 0x04, 0x66, 0x43,
-	0x8b, 0x9c, 0xc9, 0xc5, 0xd4, 0x6a, 0x3e, 0x02, 0x1e, 0xef, 0x5b, 0xe4, 0x8c, 0xc7, 0x02, 0x57,
-	0x5c, 0xfd, 0x02, 0x6a, 0x4a, 0x86, 0x96, 0x60, 0x16, 0x5f, 0x5a, 0x36, 0xe1, 0xac, 0x76, 0xa6,
-	0x0c, 0x5e, 0x44, 0x1d, 0xa8, 0xf0, 0x1e, 0xf1, 0xf5, 0xdf, 0xce, 0x94, 0x21, 0xca, 0x2f, 0x1a,
-	0x00, 0x14, 0x87, 0xc7, 0x9d, 0xfe, 0xd7, 0x25, 0x68, 0xa4, 0xc3, 0x07, 0x7d, 0x0a, 0x75, 0xcb,
-	0xf7, 0x03, 0xc2, 0x4e, 0x35, 0xe5, 0xaa, 0xf0, 0xdd, 0x82, 0x40, 0x7b, 0xdc, 0x4b, 0xd4, 0xf8,
-	0x6e, 0x2e, 0x6d, 0xb8, 0xfa, 0x09, 0x68, 0x79, 0x85, 0xb7, 0xda, 0xd7, 0x3d, 0x87, 0x76, 0x6e,
-	0xda, 0x60, 0xab, 0x5c, 0x3a, 0x0f, 0x51, 0xfb, 0x59, 0xbe, 0x11, 0xa3, 0x32, 0x36, 0xe1, 0x94,
-	0xb9, 0x8c, 0xfe, 0xd6, 0x5f, 0x42, 0x55, 0x4d, 0xb8, 0x1d, 0xa8, 0x88, 0x23, 0x8d, 0x92, 0x58,
-	0xea, 0x88, 0x32, 0x5a, 0x48, 0xaf, 0x8f, 0x77, 0xa6, 0xf8, 0x0a, 0xf9, 0x85, 0x06, 0x2d, 0x5e,
-	0x6f, 0x06, 0x11, 0x0b, 0x3e, 0xfd, 0x29, 0xd4, 0xd4, 0x04, 0x49, 0xf9, 0x9e, 0xb8, 0x51, 0x4c,
-	0x04, 0x07, 0x5e, 0xa0, 0x24, 0x3c, 0x2b, 0x26, 0x92, 0x04, 0xfd, 0xad, 0xff, 0x45, 0x09, 0x50,
-	0xfe, 0x54, 0x66, 0xd0, 0xa7, 0x1b, 0xb8, 0x20, 0xb2, 0xcf, 0x70, 0x4c, 0x22, 0x8b, 0x04, 0x11,
-	0x8d, 0x54, 0xde, 0xf5, 0x56, 0x5a, 0x3c, 0x70, 0xd0, 0x3d, 0xa8, 0xab, 0x23, 0x20, 0xd7, 0x11,
-	0xe7, 0x03, 0x20, 0x45, 0x5c, 0x41, 0x1d, 0x0d, 0xb9, 0x0e, 0x5b, 0x3f, 0xd7, 0x0c, 0x90, 0xa2,
-	0x81, 0xf3, 0xf9, 0x4c, 0xb5, 0xa4, 0x95, 0x8d, 0xea, 0x59, 0x10, 0x13, 0xd6, 0x91, 0x4b, 0x58,
-	0x2a, 0xbe, 0x3c, 0x44, 0xef, 0xa7, 0xf6, 0x1a, 0x2b, 0x13, 0x4e, 0x94, 0xc4, 0x9e, 0xe6, 0x23,
-	0xa8, 0xca, 0x26, 0xc4, 0xb1, 0xda, 0xf2, 0xa4, 0xdb, 0x43, 0xa5, 0xa8, 0xff, 0xcf, 0x34, 0x68,
-	0xf9, 0x6a, 0xea, 0xca, 0x98, 0x58, 0x44, 0x6e, 0xed, 0x78, 0xa1, 0x68, 0xd7, 0x42, 0xc3, 0x66,
-	0x68, 0xd9, 0xc2, 0x05, 0xf4, 0x27, 0xed, 0xbb, 0xbc, 0xb5, 0xa6, 0x73, 0x30, 0x5f, 0x57, 0x83,
-	0x10, 0xd1, 0x69, 0xf7, 0x36, 0xd4, 0xdc, 0xf0, 0x7c, 0x93, 0x2e, 0x87, 0xf8, 0xda, 0xba, 0x66,
-	0x54, 0xa9, 0x60, 0x17, 0x13, 0x59, 0xd9, 0xe5, 0x95, 0x15, 0x55, 0xd9, 0x65, 0x95, 0x0f, 0x61,
-	0x96, 0x6e, 0x9f, 0xe4, 0x4a, 0x5a, 0x2e, 0xe7, 0x0e, 0x5d, 0x1c, 0x0d, 0xfc, 0x93, 0xc0, 0xe0,
-	0xb5, 0xe8, 0x7d, 0xa8, 0xf2, 0x06, 0x2c, 0xd2, 0xa9, 0x32, 0xcd, 0x96, 0xba, 0x7a, 0x22, 0x4c,
-	0x71, 0x8e, 0xb5, 0x67, 0x11, 0xa1, 0xda, 0x65, 0xaa, 0xb5, 0x89, 0xaa, 0x5d, 0xaa, 0xda, 0x83,
-	0xbb, 0x96, 0xe7, 0x05, 0x17, 0x66, 0x1c, 0x06, 0xc1, 0x09, 0x76, 0x4c, 0x71, 0xf6, 0xc4, 0x87,
-	0x2e, 0x96, 0x6b, 0xe9, 0x55, 0xa6, 0x74, 0xc0, 0x75, 0xf8, 0x61, 0xcf, 0xbe, 0xd0, 0x40, 0x9f,
-	0x67, 0xc7, 0x6f, 0x9d, 0x35, 0xb8, 0x3e, 0xe1, 0x1b, 0xfd, 0x3f, 0x8f, 0xe1, 0xad, 0xf1, 0x88,
-	0x13, 0xbb, 0xdb, 0x9b, 0x47, 0x9c, 0xde, 0x83, 0x56, 0xfa, 0xc4, 0x76, 0xd0, 0xcf, 0x47, 0x7e,
-	0xf9, 0x8d, 0x91, 0xef, 0x01, 0x1a, 0xbf, 0xd8, 0x47, 0x0f, 0x53, 0x1c, 0x16, 0x0b, 0xce, 0x86,
-	0x45, 0xc4, 0x7f, 0x98, 0x8a, 0xf8, 0xe9, 0xcc, 0xb4, 0x9b, 0xb9, 0xdd, 0x4f, 0xa2, 0xfd, 0xbf,
-	0xca, 0xd0, 0x48, 0x57, 0x15, 0x9d, 0x61, 0xe4, 0x23, 0xb8, 0x3c, 0x16, 0xc1, 0x2a, 0x0e, 0xa7,
-	0xaf, 0x8d, 0xc3, 0xc7, 0x30, 0x8f, 0x2f, 0x43, 0x6c, 0x13, 0xec, 0x98, 0x2c, 0x20, 0x2d, 0xc7,
-	0x89, 0xe4, 0x88, 0xb8, 0x25, 0xab, 0x06, 0xe1, 0xf9, 0x66, 0x8f, 0x56, 0xe4, 0xf5, 0xbb, 0x42,
-	0x7f, 0x76, 0x4c, 0xbf, 0xcb, 0xf5, 0x7f, 0x08, 0x6d, 0xb5, 0x5f, 0x37, 0x39, 0xa1, 0x4a, 0x31,
-	0xa1, 0x96, 0xd2, 0x3b, 0x64, 0xcc, 0x9e, 0x42, 0x4b, 0x6e, 0xee, 0xcd, 0x6b, 0x47, 0x54, 0x43,
-	0xec, 0xf9, 0xb9, 0xd9, 0x26, 0x34, 0x4f, 0x82, 0xe8, 0xc2, 0x8a, 0x64, 0x73, 0xd5, 0x09, 0x56,
-	0x42, 0x8b, 0x59, 0xe9, 0xbf, 0x99, 0xfd, 0xc2, 0x22, 0xca, 0x6e, 0xf6, 0x85, 0xf5, 0x08, 0xaa,
-	0x12, 0xb6, 0xf0, 0x5b, 0xbd, 0x0f, 0x9a, 0xeb, 0x9f, 0x46, 0x38, 0x8e, 0xf9, 0x53, 0x14, 0x57,
-	0xcd, 0xf5, 0x6d, 0x21, 0xdf, 0x17, 0x62, 0x9a, 0xde, 0x71, 0x4e, 0x53, 0x9c, 0xcf, 0xe1, 0x8c,
-	0xa2, 0xfe, 0x0c, 0xe6, 0xc4, 0xe8, 0x47, 0x8b, 0x50, 0xc1, 0x97, 0x74, 0x4f, 0x21, 0x33, 0x21,
-	0xbe, 0x24, 0x83, 0x90, 0x8a, 0x59, 0x80, 0x87, 0x72, 0x5c, 0x51, 0xc2, 0xa1, 0x6e, 0xc0, 0x7c,
-	0xc1, 0xd5, 0x0b, 0x7a, 0x00, 0x4d, 0x37, 0x0e, 0x4c, 0xe2, 0x0e, 0x71, 0x4c, 0xac, 0xa1, 0xc4,
-	0x6a, 0xb8, 0x71, 0x70, 0x28, 0x65, 0x68, 0x09, 0x2a, 0xa3, 0x90, 0xaa, 0x30, 0xc8, 0x92, 0x21,
-	0x4a, 0x7a, 0x08, 0x9d, 0x49, 0xd7, 0x2e, 0x37, 0x1d, 0x25, 0x1f, 0x40, 0x85, 0x5f, 0x08, 0x88,
-	0xb3, 0xae, 0x45, 0x75, 0x6b, 0x9a, 0xb9, 0x70, 0x10, 0x4a, 0xfa, 0x3a, 0xb4, 0xb2, 0x35, 0xec,
-	0x40, 0x99, 0x03, 0xc8, 0x03, 0x65, 0xae, 0xd9, 0x2b, 0xe2, 0xf6, 0x76, 0xdf, 0xf7, 0x12, 0xee,
-	0x5c, 0x77, 0x1b, 0xf3, 0x36, 0xd3, 0xdf, 0x5b, 0x76, 0x73, 0x30, 0xa9, 0xe5, 0xb7, 0x4f, 0x83,
-	0xa7, 0xb0, 0x58, 0x78, 0xab, 0x82, 0xee, 0x02, 0x84, 0xa3, 0x63, 0xcf, 0xb5, 0xcd, 0x24, 0x2f,
-	0xd7, 0xb8, 0xe4, 0x0b, 0x7c, 0xf5, 0xd6, 0x87, 0x5b, 0xfa, 0x2d, 0x68, 0xe7, 0x2e, 0x5b, 0xf4,
-	0x3f, 0x2d, 0xc3, 0x52, 0xf1, 0x05, 0x26, 0x5d, 0x18, 0xcb, 0x34, 0x2b, 0x17, 0xc6, 0xb2, 0xac,
-	0x26, 0x61, 0x9a, 0x62, 0x44, 0x10, 0xb3, 0x49, 0x93, 0x66, 0x16, 0x35, 0x09, 0xb3, 0xca, 0x69,
-	0x55, 0xc9, 0xd2, 0x0e, 0x45, 0xb5, 0x62, 0xb1, 0x6e, 0xe3, 0x0b, 0x1b, 0x55, 0x46, 0x3d, 0xa8,
-	0x78, 0xd6, 0x31, 0xf6, 0xe4, 0x99, 0xd9, 0xfb, 0xd7, 0xde, 0xb0, 0x3e, 0x7e, 0xc9, 0x74, 0xc5,
-	0x75, 0x03, 0x37, 0x5c, 0x7d, 0x0e, 0xf5, 0x94, 0xf8, 0xad, 0xa6, 0xb4, 0xdf, 0x1e, 0xf7, 0x84,
-	0xf8, 0x96, 0xff, 0x57, 0x4f, 0xe8, 0xaf, 0x78, 0xee, 0xca, 0x3d, 0x68, 0xfa, 0xae, 0xe0, 0x7e,
-	0x55, 0x76, 0x7b, 0xb0, 0x50, 0x74, 0xd3, 0x7e, 0x03, 0xc0, 0x6e, 0x1e, 0xb0, 0x5b, 0x0c, 0x78,
-	0x63, 0x86, 0x13, 0x00, 0xb7, 0xa1, 0x95, 0x7d, 0xb2, 0x55, 0x70, 0xb5, 0x32, 0x13, 0x06, 0x81,
-	0x27, 0xc6, 0x6c, 0x3b, 0xff, 0x48, 0x8b, 0x55, 0xea, 0xf7, 0x13, 0x98, 0x09, 0x97, 0x26, 0x3f,
-	0x83, 0xaa, 0xd4, 0x60, 0xfb, 0x0e, 0xd7, 0x51, 0x27, 0xee, 0xf4, 0x37, 0x5a, 0x03, 0x18, 0x5a,
-	0xf1, 0x37, 0x23, 0x1c, 0x59, 0x62, 0x47, 0x52, 0x35, 0x52, 0x12, 0xde, 0x0b, 0x37, 0x34, 0x87,
-	0x74, 0xc3, 0xa2, 0x42, 0xde, 0x0d, 0x5f, 0xd1, 0xcd, 0xcd, 0x5d, 0x80, 0xf3, 0x4b, 0xcf, 0xf2,
-	0x79, 0x2d, 0x0f, 0xfa, 0x1a, 0x93, 0xd0, 0x6a, 0xfd, 0x0f, 0x4b, 0xd0, 0xcc, 0xbc, 0x40, 0x41,
-	0xef, 0x40, 0x83, 0xa1, 0x61, 0xdf, 0x3a, 0xf6, 0x30, 0xe7, 0x59, 0x35, 0xea, 0x54, 0xb6, 0xcd,
-	0x45, 0x74, 0x52, 0xe0, 0x98, 0x52, 0x87, 0x73, 0x6a, 0x30, 0xa1, 0x54, 0x5a, 0x07, 0x2d, 0xa3,
-	0x64, 0x9e, 0x77, 0xc5, 0x49, 0x7d, 0x2b, 0xad, 0x77, 0xd4, 0xd5, 0xff, 0xa1, 0x04, 0x0b, 0x45,
-	0x2f, 0xc8, 0xd0, 0x7b, 0xa9, 0x34, 0xb6, 0x5c, 0x78, 0x14, 0x22, 0xd2, 0xe7, 0x8f, 0xd5, 0xd8,
-	0xe5, 0xbb, 0xdd, 0xf7, 0xae, 0x79, 0x97, 0xf6, 0x5d, 0x8f, 0xdc, 0x1f, 0xe7, 0xc9, 0xab, 0xdb,
-	0xef, 0x9b, 0x91, 0xd7, 0xfb, 0xa0, 0xe5, 0xe5, 0xd9, 0x6b, 0x8a, 0x52, 0xfe, 0x9a, 0xa2, 0xe8,
-	0x0a, 0xe6, 0xef, 0x4b, 0xd0, 0xce, 0x3d, 0x71, 0x43, 0x7a, 0x8a, 0x02, 0xca, 0xbf, 0x60, 0x13,
-	0xae, 0xfb, 0x38, 0xe7, 0x3a, 0xbd, 0xf8, 0xb9, 0xdc, 0x77, 0xed, 0xb5, 0xa7, 0x29, 0xb6, 0xc2,
-	0x61, 0x37, 0x60, 0xab, 0xbf, 0x03, 0xf5, 0x94, 0xa8, 0xf0, 0x16, 0xef, 0x10, 0x80, 0xbf, 0x54,
-	0x3b, 0x14, 0xfb, 0x78, 0x1a, 0xb9, 0x22, 0x8a, 0xd9, 0x6f, 0xc6, 0x8a, 0x46, 0xa0, 0x08, 0x5b,
-	0x5e, 0xa0, 0x2e, 0x57, 0xaf, 0x08, 0xe4, 0x95, 0x92, 0x12, 0xe8, 0xff, 0x56, 0x86, 0x7a, 0xea,
-	0xed, 0x1e, 0x7a, 0x37, 0x75, 0x66, 0x90, 0x4c, 0x7c, 0x4c, 0x23, 0xb9, 0xce, 0x45, 0x1f, 0xd1,
-	0xb1, 0xc4, 0xdf, 0x73, 0x32, 0x6d, 0x3e, 0x4d, 0xde, 0x52, 0x89, 0x82, 0x0e, 0x79, 0xa6, 0x0e,
-	0x6e, 0x28, 0x7f, 0x53, 0x37, 0x3a, 0x31, 0x91, 0xdb, 0x52, 0x27, 0x26, 0x48, 0x87, 0x26, 0x3b,
-	0x34, 0x0d, 0x1c, 0x7e, 0x70, 0x25, 0x86, 0x71, 0xdd, 0x89, 0xc9, 0x6e, 0xe0, 0xb0, 0x73, 0x2a,
-	0xb4, 0x06, 0x75, 0xa5, 0xe3, 0x86, 0xf2, 0x6a, 0x4b, 0x68, 0x0c, 0x42, 0xba, 0x31, 0x88, 0xad,
-	0x21, 0x36, 0xe3, 0xd1, 0xb1, 0x8f, 0x09, 0x7b, 0xe6, 0x51, 0x35, 0x80, 0x8a, 0x0e, 0x98, 0x84,
-	0x8e, 0x7b, 0xba, 0xa4, 0x0e, 0x46, 0xe4, 0x34, 0x70, 0xfd, 0x53, 0x76, 0x85, 0x53, 0x35, 0xea,
-	0xbe, 0x45, 0xf6, 0x84, 0x08, 0x3d, 0x84, 0x96, 0x17, 0xd8, 0x96, 0x67, 0xca, 0xe3, 0x02, 0x76,
-	0x87, 0x53, 0x35, 0x9a, 0x4c, 0x2a, 0x17, 0x18, 0x68, 0x03, 0xea, 0x84, 0x7d, 0x01, 0xde, 0x69,
-	0xfe, 0xe0, 0x42, 0x76, 0x3a, 0xf9, 0x36, 0x06, 0x10, 0xf5, 0x5b, 0xbf, 0x27, 0xdc, 0x2b, 0x62,
-	0x41, 0xf8, 0xa0, 0xac, 0x7c, 0xa0, 0xff, 0x67, 0x09, 0x56, 0x26, 0xbe, 0x65, 0x64, 0x81, 0x40,
-	0xf3, 0x9b, 0x0c, 0x04, 0x9a, 0xf9, 0xc4, 0xf6, 0xbe, 0x9c, 0x6c, 0xef, 0x33, 0x13, 0xd2, 0x74,
-	0x6e, 0xe1, 0xb0, 0x0e, 0x5a, 0x68, 0x45, 0xd8, 0x27, 0xa6, 0x83, 0xd9, 0x11, 0xa1, 0x1b, 0x0a,
-	0x3f, 0xb7, 0xb8, 0xbc, 0xcf, 0xc4, 0x7c, 0x05, 0x3d, 0xb4, 0x6c, 0x9a, 0xcf, 0xb8, 0x97, 0x67,
-	0x87, 0x96, 0x7d, 0xd4, 0xcd, 0x4e, 0x26, 0x95, 0xdc, 0xca, 0xe3, 0x07, 0x80, 0xf2, 0xe8, 0xe7,
-	0x5d, 0xf6, 0x15, 0x6a, 0x86, 0x96, 0xc5, 0x3f, 0xef, 0xea, 0x1f, 0x16, 0xf6, 0x55, 0xf8, 0xa6,
-	0xa0, 0xaf, 0xfa, 0xcf, 0x4b, 0xb0, 0x3c, 0xe1, 0x45, 0xe5, 0xb5, 0x13, 0x60, 0x76, 0x91, 0x57,
-	0xce, 0x2f, 0xf2, 0x1e, 0xc3, 0xbc, 0xeb, 0x13, 0x1c, 0x9d, 0x58, 0x9c, 0x71, 0xc6, 0x75, 0xb7,
-	0x54, 0x95, 0xdc, 0x06, 0xea, 0x4f, 0x0b, 0x58, 0xbc, 0x79, 0x1a, 0xd6, 0xff, 0xbc, 0x04, 0x2b,
-	0x13, 0xdf, 0x0e, 0x5e, 0xcb, 0x5f, 0x87, 0x66, 0xc2, 0x9f, 0x7e, 0x11, 0xde, 0x85, 0xba, 0xea,
-	0xc2, 0x51, 0x77, 0xac, 0x13, 0xdd, 0x89, 0x9d, 0xe0, 0xf3, 0xfe, 0xb3, 0x42, 0x32, 0x37, 0xe8,
-	0xc6, 0x3f, 0x96, 0x60, 0xb1, 0xf0, 0x6d, 0x28, 0xda, 0x80, 0x45, 0x79, 0xf0, 0x6c, 0x7b, 0xa3,
-	0x98, 0xe0, 0xc8, 0xa4, 0x33, 0xbb, 0x3c, 0xb4, 0x9d, 0x17, 0x95, 0x5b, 0xbc, 0x6e, 0x8b, 0x56,
-	0xa1, 0xcd, 0xe4, 0x99, 0x34, 0xbe, 0x24, 0x38, 0xf2, 0x2d, 0x4f, 0x18, 0x95, 0xc5, 0x1d, 0x25,
-	0xaf, 0xdd, 0x16, 0x95, 0xdc, 0xea, 0x47, 0xb0, 0x2a, 0xad, 0xe8, 0x58, 0x3c, 0xb6, 0x3c, 0xcb,
-	0xb7, 0x55, 0x73, 0x7c, 0xcf, 0xd8, 0x11, 0x1a, 0x2f, 0x53, 0x0a, 0xcc, 0x5a, 0xff, 0x1a, 0xea,
-	0x62, 0x2a, 0xda, 0x0f, 0x22, 0x42, 0x3b, 0x2b, 0x0f, 0x3c, 0x65, 0x67, 0xd5, 0x01, 0x28, 0x82,
-	0x19, 0xaa, 0x23, 0xcf, 0x26, 0xa5, 0x3e, 0xcd, 0x36, 0x4c, 0x3e, 0xcd, 0xe4, 0xaa, 0x4c, 0xc7,
-	0x6f, 0x33, 0xf3, 0x56, 0xb5, 0x70, 0x4b, 0x9c, 0x99, 0xf7, 0xca, 0x05, 0xf3, 0x9e, 0x7a, 0x4f,
-	0x53, 0x13, 0x29, 0xf6, 0x2e, 0x80, 0x74, 0xa9, 0x1a, 0xb0, 0x35, 0x21, 0x19, 0x84, 0x74, 0xe3,
-	0x9c, 0xf1, 0x83, 0x4a, 0x8d, 0xad, 0xb4, 0x78, 0x10, 0xd2, 0xf4, 0xa7, 0xdc, 0xec, 0x86, 0xf2,
-	0xfc, 0xae, 0x2e, 0x65, 0x83, 0x30, 0x46, 0xeb, 0x30, 0x9b, 0xbe, 0x0c, 0x47, 0xd9, 0x49, 0x9d,
-	0x1d, 0xe6, 0x72, 0x05, 0xbd, 0xa7, 0xfa, 0x9a, 0x1a, 0xb3, 0x6f, 0xd5, 0xd7, 0x47, 0xeb, 0x50,
-	0x53, 0xdb, 0x28, 0x34, 0x07, 0xd3, 0xbd, 0xdd, 0xaf, 0xb5, 0x29, 0x54, 0x85, 0x99, 0xc1, 0xfe,
-	0xd1, 0xa6, 0x36, 0x23, 0x7e, 0x75, 0xb5, 0xca, 0xa3, 0x3f, 0x2b, 0x41, 0x4d, 0x4d, 0x3c, 0xa8,
-	0x09, 0xb5, 0xad, 0x41, 0xdf, 0x30, 0x07, 0xbb, 0x9f, 0xee, 0x69, 0x53, 0x68, 0x1e, 0xda, 0xc6,
-	0xf6, 0xab, 0xbd, 0xc3, 0x6d, 0xf3, 0xab, 0x3d, 0xe3, 0x8b, 0x97, 0x7b, 0xbd, 0xbe, 0x56, 0x42,
-	0x6d, 0xa8, 0x0b, 0xe1, 0xce, 0xde, 0xc1, 0xa1, 0x56, 0x46, 0x08, 0x5a, 0x2f, 0xf7, 0xb6, 0x7a,
-	0x2f, 0x13, 0xa5, 0x69, 0xd4, 0x02, 0xe0, 0x32, 0xa6, 0x33, 0x83, 0x6e, 0x41, 0x53, 0x18, 0x1d,
-	0x7e, 0xb9, 0xbb, 0xbb, 0xfd, 0x52, 0x9b, 0x45, 0x1a, 0x34, 0xb8, 0x8a, 0x90, 0x54, 0x1e, 0x3d,
-	0x07, 0x48, 0x66, 0x35, 0xca, 0x71, 0x77, 0x6f, 0x77, 0x5b, 0x9b, 0x42, 0x0d, 0xa8, 0xee, 0xee,
-	0x99, 0xdb, 0xbb, 0x5b, 0xbd, 0x7d, 0xad, 0x84, 0x6a, 0x30, 0xcb, 0xd2, 0x9b, 0x56, 0xe6, 0xdd,
-	0x18, 0xec, 0x6b, 0xd3, 0x1b, 0x9f, 0x00, 0xf0, 0x27, 0x24, 0xec, 0x7f, 0xaa, 0x9e, 0xc0, 0x0c,
-	0xfb, 0xab, 0x9c, 0x9c, 0xfc, 0xa7, 0xd6, 0xaa, 0x94, 0xa5, 0xfe, 0x5b, 0xeb, 0x49, 0xe9, 0xc5,
-	0xf2, 0x2f, 0xbe, 0x5d, 0x2b, 0xfd, 0xf3, 0xb7, 0x6b, 0xa5, 0x7f, 0xff, 0x76, 0xad, 0xf4, 0x97,
-	0xff, 0xb1, 0x36, 0xf5, 0x93, 0x59, 0x76, 0x85, 0x7e, 0x5c, 0x61, 0x7f, 0x3e, 0xfa, 0xdf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x43, 0xda, 0xdb, 0xf5, 0x0b, 0x36, 0x00, 0x00,
+	// 4538 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5b, 0x4b, 0x73, 0x1c, 0x47,
+	0x72, 0xc6, 0xcc, 0x00, 0x83, 0x99, 0x9c, 0x07, 0x06, 0x85, 0xd7, 0x00, 0x24, 0x48, 0xa8, 0x45,
+	0x4a, 0x10, 0x77, 0x97, 0xa2, 0x29, 0x10, 0x5c, 0xca, 0x6b, 0x6d, 0x00, 0x18, 0x48, 0x1c, 0x89,
+	0x78, 0xb8, 0x01, 0x41, 0xd6, 0xda, 0x11, 0xed, 0x46, 0x77, 0x03, 0x68, 0xab, 0xa7, 0xbb, 0xd5,
+	0x5d, 0x83, 0x87, 0x5f, 0x1b, 0xb6, 0xd7, 0x0e, 0x3b, 0x7c, 0xf0, 0xc5, 0xb1, 0xbf, 0xc0, 0xc7,
+	0xfd, 0x07, 0x8e, 0xb0, 0xaf, 0xde, 0xf0, 0xc9, 0xb1, 0x67, 0xff, 0x01, 0x1f, 0xfd, 0x0b, 0x1c,
+	0x59, 0xaf, 0x7e, 0x4c, 0x0f, 0x48, 0x5a, 0x8a, 0x3d, 0x61, 0x2a, 0xf3, 0xcb, 0xac, 0xac, 0xec,
+	0xac, 0xac, 0xaa, 0xac, 0x02, 0x90, 0x33, 0xc7, 0x73, 0xaf, 0x4f, 0x4d, 0xeb, 0x1b, 0xc7, 0xb7,
+	0x1f, 0x87, 0x51, 0x40, 0x03, 0x32, 0xc5, 0x68, 0x5a, 0x0b, 0x1a, 0x47, 0x37, 0xbe, 0xa5, 0x3b,
+	0xdf, 0x0e, 0x9d, 0x98, 0x6a, 0xff, 0xb9, 0x08, 0x8d, 0xe3, 0xa0, 0x67, 0x52, 0x33, 0xf4, 0x4c,
+	0xdf, 0x21, 0xeb, 0x30, 0xed, 0xfa, 0x46, 0x7c, 0xe3, 0x5b, 0xdd, 0xd2, 0x5a, 0x69, 0xbd, 0xf1,
+	0xb4, 0xf5, 0x98, 0xc9, 0x3d, 0xee, 0xfb, 0x28, 0xf6, 0x72, 0x42, 0xaf, 0xba, 0xec, 0x17, 0x79,
+	0x0e, 0x4d, 0x37, 0x8c, 0x1d, 0x6a, 0x0c, 0x43, 0xdb, 0xa4, 0x4e, 0xb7, 0xcc, 0xe0, 0x44, 0xc2,
+	0x0f, 0x8f, 0x1c, 0xfa, 0x25, 0xe3, 0xbc, 0x9c, 0xd0, 0x1b, 0x0c, 0xc9, 0x9b, 0xe4, 0x33, 0x20,
+	0x5c, 0xd0, 0x76, 0x3c, 0x6a, 0x4a, 0xf1, 0x0a, 0x13, 0x5f, 0x4a, 0x8b, 0xf7, 0x90, 0xaf, 0x74,
+	0x74, 0x98, 0x50, 0x8a, 0x96, 0x58, 0x10, 0x39, 0x83, 0xe0, 0xd2, 0xe9, 0x4e, 0x8e, 0x5a, 0xa0,
+	0x33, 0x8e, 0xb2, 0x80, 0x37, 0xc9, 0x21, 0x2c, 0x98, 0x16, 0x75, 0x2f, 0x1d, 0x23, 0x8c, 0x82,
+	0x33, 0xd7, 0x73, 0xa4, 0x11, 0x53, 0x4c, 0xc3, 0x8a, 0xd0, 0xb0, 0xc5, 0x30, 0x87, 0x1c, 0xa2,
+	0xec, 0x98, 0x33, 0x47, 0xc9, 0x05, 0x1a, 0x85, 0x4d, 0xd5, 0xf1, 0x1a, 0x95, 0x6d, 0x73, 0xe6,
+	0x28, 0x99, 0xec, 0xc1, 0xbc, 0xd4, 0x18, 0x78, 0xae, 0x75, 0x23, 0x4d, 0x9c, 0x66, 0x0a, 0x97,
+	0xb3, 0x0a, 0x19, 0x42, 0x59, 0x48, 0xcc, 0x11, 0xea, 0xa8, 0x3a, 0x61, 0x5f, 0x6d, 0xac, 0x3a,
+	0x65, 0x1e, 0x31, 0x47, 0xa8, 0xa8, 0xee, 0x22, 0x88, 0xa9, 0xe1, 0xf8, 0x76, 0x18, 0xb8, 0xbe,
+	0x0a, 0x82, 0x7a, 0x46, 0xdd, 0xcb, 0x20, 0xa6, 0xbb, 0x02, 0x91, 0x58, 0x77, 0x31, 0x42, 0x1d,
+	0x55, 0x27, 0xac, 0x83, 0xb1, 0xea, 0x12, 0xeb, 0x2e, 0x46, 0xa8, 0xe4, 0x6b, 0xe8, 0x5e, 0x05,
+	0xd1, 0x37, 0x5e, 0x60, 0xda, 0x23, 0x16, 0x36, 0x98, 0xca, 0x55, 0xa1, 0xf2, 0x2b, 0x01, 0x1b,
+	0xb1, 0x72, 0xf1, 0xaa, 0x90, 0x53, 0xac, 0x5a, 0x58, 0xdb, 0xbc, 0x55, 0xb5, 0xb2, 0x78, 0xf1,
+	0xaa, 0x90, 0x43, 0x3e, 0x86, 0x96, 0x15, 0xf8, 0x67, 0xee, 0xb9, 0x34, 0xb5, 0xc5, 0xf4, 0xcd,
+	0x09, 0x7d, 0x3b, 0x8c, 0xa7, 0x0c, 0x6c, 0x5a, 0xa9, 0xb6, 0x72, 0xe0, 0xc0, 0xa1, 0xa6, 0x6d,
+	0x26, 0xb3, 0xaa, 0x3d, 0xe2, 0xc0, 0x3d, 0x81, 0xc8, 0x7e, 0x8f, 0x2c, 0x95, 0xbc, 0x0f, 0x33,
+	0x31, 0x26, 0x08, 0xdf, 0x72, 0x0c, 0x7f, 0x38, 0x38, 0x75, 0xa2, 0xee, 0xcc, 0x5a, 0x69, 0x7d,
+	0x52, 0x6f, 0x4b, 0xf2, 0x3e, 0xa3, 0x92, 0x2d, 0xe8, 0xb8, 0xa1, 0x39, 0x30, 0xc2, 0x20, 0xf0,
+	0x64, 0x9f, 0x1d, 0xd6, 0xe7, 0x82, 0x9a, 0x86, 0x5b, 0x7b, 0x87, 0x41, 0xe0, 0xa9, 0xfe, 0xda,
+	0x28, 0x90, 0x50, 0xb2, 0x2a, 0x84, 0x27, 0x67, 0x0b, 0x55, 0x28, 0x0f, 0x2a, 0x15, 0xb9, 0x68,
+	0x54, 0xa3, 0x17, 0x6a, 0xc8, 0xd8, 0xd1, 0x67, 0xc3, 0x27, 0x4b, 0x25, 0x47, 0xb0, 0x18, 0x3b,
+	0xd1, 0xa5, 0x6b, 0x39, 0x86, 0x69, 0x59, 0xc1, 0x30, 0x09, 0x9e, 0x39, 0xa6, 0xf0, 0x8e, 0x50,
+	0x78, 0xc4, 0x41, 0x5b, 0x1c, 0xa3, 0x06, 0x38, 0x1f, 0x17, 0xd0, 0x8b, 0x94, 0x0a, 0x2b, 0xe7,
+	0x6f, 0x51, 0xaa, 0xec, 0x9c, 0x8f, 0x0b, 0xe8, 0x64, 0x07, 0x3a, 0xbe, 0x39, 0x70, 0xe2, 0xd0,
+	0xb4, 0x54, 0x0e, 0x5b, 0x60, 0xea, 0x16, 0x85, 0xba, 0x7d, 0xc9, 0x56, 0xe6, 0xcd, 0xf8, 0x59,
+	0x52, 0x56, 0x89, 0xb0, 0x69, 0xb1, 0x58, 0x89, 0x32, 0x67, 0xc6, 0xcf, 0x92, 0x30, 0x17, 0x47,
+	0xc1, 0x90, 0x2a, 0x2b, 0x96, 0x32, 0xb9, 0x58, 0x47, 0x56, 0xb2, 0x1a, 0x44, 0x49, 0x33, 0x11,
+	0x14, 0x3d, 0x77, 0x47, 0x05, 0x93, 0x24, 0x1e, 0x25, 0x4d, 0xb2, 0x03, 0x8d, 0x4b, 0xea, 0x84,
+	0xb2, 0xc3, 0x65, 0x26, 0xb7, 0x26, 0xe4, 0x4e, 0xfe, 0xe0, 0xd5, 0xd6, 0xfe, 0xf1, 0xd0, 0xf7,
+	0x1d, 0x6f, 0x64, 0x6a, 0x03, 0x8a, 0xa9, 0xb1, 0x73, 0x25, 0xa2, 0xf3, 0x95, 0xd7, 0x29, 0x51,
+	0xa6, 0x30, 0x25, 0xc2, 0x92, 0x3f, 0x82, 0xe5, 0x2b, 0x37, 0x72, 0xce, 0x87, 0x66, 0x34, 0x9a,
+	0x6f, 0xee, 0x30, 0x95, 0xf7, 0x64, 0x52, 0x90, 0xb8, 0x11, 0xab, 0x96, 0xae, 0x8a, 0x59, 0x63,
+	0xb4, 0x0b, 0x83, 0xef, 0xde, 0xae, 0x5d, 0x99, 0xbb, 0x74, 0x55, 0xcc, 0x22, 0x5f, 0x41, 0xf7,
+	0xdc, 0x0b, 0x4e, 0x4d, 0xcf, 0x38, 0x3d, 0x0f, 0x8d, 0x6c, 0xfe, 0x59, 0x65, 0xca, 0xef, 0x0a,
+	0xe5, 0x9f, 0x31, 0xd8, 0xf6, 0x67, 0x87, 0xb9, 0x44, 0xb4, 0xc0, 0xe5, 0xb7, 0xcf, 0xc3, 0x34,
+	0x83, 0xfc, 0x04, 0x5a, 0x8e, 0x6f, 0x99, 0x61, 0x3c, 0xf4, 0x4c, 0xea, 0x06, 0x7e, 0xf7, 0x1e,
+	0xd3, 0x36, 0x2f, 0xb4, 0xed, 0xa6, 0x79, 0x2f, 0x27, 0xf4, 0x2c, 0x98, 0xfc, 0x1e, 0xb4, 0xe5,
+	0x6c, 0x11, 0xc6, 0xdc, 0xcf, 0x88, 0x8b, 0x59, 0xa2, 0x8c, 0x68, 0xc5, 0x69, 0x42, 0x5a, 0x5c,
+	0x38, 0x6a, 0xad, 0x48, 0x5c, 0xb9, 0xa7, 0x15, 0xa7, 0x09, 0xc4, 0x82, 0xbb, 0x05, 0x2e, 0xbf,
+	0xdc, 0x94, 0xb6, 0xbc, 0x93, 0x09, 0x93, 0x11, 0xaf, 0x9f, 0x6c, 0x2a, 0xbb, 0x96, 0xaf, 0xc6,
+	0x31, 0xc7, 0x77, 0x22, 0x2c, 0xd6, 0x5e, 0xd7, 0x89, 0xb2, 0x7e, 0xf9, 0x6a, 0x1c, 0x93, 0x1c,
+	0xc3, 0x52, 0x36, 0x33, 0x26, 0x83, 0x78, 0x37, 0x93, 0x76, 0xd2, 0xc9, 0x31, 0x65, 0xff, 0xfc,
+	0x45, 0x01, 0xbd, 0x50, 0xab, 0xb0, 0xfa, 0xc1, 0x2d, 0x5a, 0x93, 0x64, 0x76, 0x51, 0x40, 0x27,
+	0x3f, 0x83, 0xe5, 0x9c, 0xd6, 0x8d, 0xc4, 0xda, 0x87, 0x99, 0xb5, 0x35, 0xa3, 0x77, 0x23, 0x65,
+	0xef, 0x62, 0x46, 0xf3, 0xc6, 0xa5, 0xb4, 0xb8, 0x58, 0xb7, 0xb0, 0xf9, 0xbd, 0x5b, 0x75, 0x27,
+	0xeb, 0x76, 0x5e, 0x37, 0xe7, 0x6c, 0xd7, 0x61, 0x3a, 0x34, 0x6f, 0x70, 0x41, 0xd7, 0x7e, 0x33,
+	0x05, 0xad, 0x4f, 0xa3, 0x60, 0x90, 0xec, 0xa7, 0x0f, 0x61, 0x21, 0x8c, 0x02, 0xcb, 0x89, 0x63,
+	0x23, 0xa6, 0x26, 0x1d, 0xc6, 0xd9, 0xfd, 0xae, 0xdc, 0x18, 0x1e, 0x72, 0xcc, 0x11, 0x83, 0x24,
+	0x5b, 0xcd, 0x70, 0x94, 0x4c, 0xfe, 0x18, 0xee, 0x64, 0xf7, 0x4a, 0x59, 0xbd, 0x7c, 0x13, 0x7c,
+	0xbf, 0x60, 0xcb, 0x94, 0x53, 0xde, 0xbd, 0x18, 0xc3, 0x1b, 0xdb, 0x83, 0x70, 0xd7, 0xd4, 0x6b,
+	0x7a, 0x50, 0x0e, 0xeb, 0x5e, 0x8c, 0xe1, 0x11, 0x0f, 0xee, 0x8f, 0xee, 0xa2, 0xb2, 0xe3, 0xe0,
+	0x1b, 0xe7, 0x77, 0xc7, 0x6c, 0xa6, 0x72, 0x63, 0xb9, 0x7b, 0x75, 0x0b, 0xff, 0xd6, 0xde, 0xc4,
+	0x98, 0xa6, 0xdf, 0xa0, 0x37, 0x35, 0xae, 0xbb, 0x57, 0xb7, 0xf0, 0x8b, 0xf6, 0x4e, 0xb5, 0xc2,
+	0xbd, 0xd3, 0x09, 0x24, 0x59, 0x39, 0x37, 0xf8, 0x7a, 0x26, 0xf3, 0xaa, 0xb9, 0x9f, 0x1b, 0xf5,
+	0xc2, 0x55, 0x11, 0x83, 0xf4, 0x60, 0xd6, 0x96, 0xf1, 0x67, 0xc8, 0xc3, 0x1c, 0x64, 0x16, 0x74,
+	0x15, 0x9f, 0xea, 0x54, 0x37, 0x63, 0x67, 0x49, 0xe9, 0xa8, 0xfe, 0xaf, 0x32, 0x34, 0x33, 0xb9,
+	0xfd, 0x39, 0x54, 0xf9, 0x4a, 0xd1, 0x2d, 0xad, 0x55, 0x52, 0xb1, 0x90, 0x06, 0x89, 0xc6, 0xae,
+	0x4f, 0xa3, 0x1b, 0x5d, 0xc0, 0xc9, 0x1f, 0xc2, 0x7c, 0x1c, 0x0c, 0x23, 0xcb, 0x31, 0x68, 0x60,
+	0x44, 0xe6, 0x95, 0x58, 0x70, 0xba, 0x65, 0xa6, 0xe6, 0x51, 0x91, 0x9a, 0x23, 0x86, 0x3f, 0x0e,
+	0x74, 0xf3, 0x2a, 0xad, 0x71, 0x36, 0xce, 0xd3, 0x49, 0x17, 0xa6, 0x07, 0x4e, 0x1c, 0x9b, 0xe7,
+	0x7c, 0x72, 0xd5, 0x75, 0xd9, 0x5c, 0x79, 0x01, 0x8d, 0x94, 0x2c, 0xe9, 0x40, 0xe5, 0x1b, 0xe7,
+	0x86, 0x9d, 0x6f, 0xeb, 0x3a, 0xfe, 0x24, 0xf3, 0x30, 0x75, 0x69, 0x7a, 0x43, 0x7e, 0x88, 0xad,
+	0xeb, 0xbc, 0xf1, 0x71, 0xf9, 0xc7, 0xa5, 0x95, 0x13, 0x58, 0x2c, 0xb6, 0x20, 0xad, 0xa5, 0xc5,
+	0xb5, 0xbc, 0x97, 0xd6, 0xd2, 0x78, 0xda, 0x91, 0x7b, 0x18, 0x29, 0x97, 0xd2, 0xab, 0xfd, 0x73,
+	0x09, 0xea, 0x89, 0xe9, 0x8b, 0x50, 0xe5, 0xe3, 0x11, 0x46, 0x89, 0x16, 0xd9, 0x80, 0x6a, 0xc6,
+	0x43, 0x77, 0xf3, 0x2a, 0x8b, 0xbc, 0xfc, 0x1d, 0x86, 0xab, 0xd5, 0xa0, 0xca, 0xbf, 0xbf, 0xf6,
+	0x2f, 0x25, 0x68, 0xa4, 0x0e, 0xf1, 0xa4, 0x0d, 0x65, 0xd7, 0x16, 0x4a, 0xca, 0xae, 0xcd, 0xbd,
+	0x8d, 0x71, 0x1c, 0x33, 0xdb, 0xea, 0xba, 0x6c, 0x92, 0x27, 0x30, 0x49, 0x6f, 0x42, 0xfe, 0x11,
+	0xda, 0xca, 0xe4, 0x94, 0x2e, 0xfe, 0xfb, 0xf8, 0x26, 0x74, 0x74, 0x86, 0xd4, 0x5e, 0x40, 0x5d,
+	0x91, 0x48, 0x15, 0xca, 0xfd, 0xc3, 0xce, 0x04, 0x99, 0xc1, 0xfe, 0x8d, 0xad, 0xfd, 0x9e, 0x71,
+	0x78, 0xa0, 0x1f, 0x77, 0x4a, 0x64, 0x1a, 0x2a, 0xfb, 0xbb, 0xc7, 0x9d, 0x32, 0x01, 0xa8, 0xf6,
+	0x0e, 0xf6, 0xb6, 0xfa, 0xfb, 0x9d, 0x8a, 0x16, 0x42, 0x27, 0x5f, 0x2b, 0x18, 0x31, 0xf5, 0x5d,
+	0x68, 0x99, 0xb6, 0xed, 0xd8, 0x46, 0xd6, 0xe0, 0x26, 0x23, 0xee, 0x09, 0xab, 0xdf, 0x87, 0x19,
+	0x9e, 0x0b, 0x12, 0x58, 0x85, 0xc1, 0xda, 0x82, 0x2c, 0x80, 0xda, 0xaa, 0xf0, 0x8b, 0x98, 0xee,
+	0xb9, 0xce, 0x34, 0x13, 0xe6, 0x0a, 0xea, 0x06, 0x64, 0x4d, 0xc1, 0x92, 0xc0, 0x10, 0x88, 0x7e,
+	0x8f, 0x59, 0xb9, 0x0e, 0xd3, 0xa2, 0x76, 0x20, 0xe2, 0xa7, 0x9d, 0x85, 0xe9, 0x92, 0xad, 0x3d,
+	0xcf, 0x75, 0x21, 0x2c, 0x79, 0x6d, 0x17, 0xda, 0x7d, 0xa8, 0x2b, 0x02, 0x21, 0x30, 0x89, 0x9b,
+	0x78, 0x61, 0x3a, 0xfb, 0xad, 0x05, 0x30, 0x2d, 0x00, 0xe4, 0x09, 0xb4, 0x5c, 0xff, 0x34, 0x18,
+	0xfa, 0xb6, 0x11, 0x0d, 0x3d, 0x27, 0x16, 0x53, 0xbd, 0x21, 0x23, 0x70, 0xe8, 0x39, 0x7a, 0x53,
+	0x20, 0xb0, 0x11, 0x93, 0xa7, 0xd0, 0x0e, 0x86, 0x34, 0x2d, 0x52, 0x1e, 0x15, 0x69, 0x49, 0x08,
+	0x93, 0xd1, 0x7e, 0x0e, 0x64, 0xb4, 0x84, 0x41, 0xee, 0xa7, 0x46, 0x32, 0x23, 0x47, 0xc2, 0x00,
+	0xc2, 0x57, 0x0f, 0xa1, 0xca, 0xcb, 0x18, 0xdd, 0x72, 0xa6, 0x48, 0xc5, 0x41, 0xba, 0x60, 0x92,
+	0x77, 0xa0, 0x79, 0xea, 0xd2, 0x81, 0x19, 0x1a, 0xae, 0x6f, 0x3b, 0xd7, 0x2c, 0x22, 0x5b, 0x7a,
+	0x83, 0xd3, 0xfa, 0x48, 0xd2, 0x9e, 0x65, 0x0d, 0x10, 0xae, 0x7c, 0x9d, 0x01, 0xda, 0x53, 0xa8,
+	0xc9, 0x36, 0x3a, 0x92, 0xba, 0x4e, 0x24, 0x1d, 0x89, 0xbf, 0x95, 0x73, 0xcb, 0x29, 0xe7, 0xfe,
+	0x6f, 0x09, 0xaa, 0x5c, 0xe8, 0xb7, 0xe3, 0x5c, 0x72, 0x17, 0xea, 0x43, 0x9f, 0x46, 0x58, 0x05,
+	0xb4, 0xd9, 0xd8, 0x6b, 0x7a, 0x42, 0x20, 0xcb, 0x50, 0x0b, 0x23, 0xc7, 0xb0, 0x7d, 0x93, 0xb2,
+	0x4d, 0x43, 0x0d, 0x03, 0xcc, 0xe9, 0xf9, 0x26, 0x45, 0x41, 0x75, 0xbe, 0x63, 0xcb, 0x7d, 0x5d,
+	0x4f, 0x08, 0xe4, 0x07, 0x30, 0x1b, 0x44, 0xee, 0xb9, 0xeb, 0x9b, 0x9e, 0x11, 0x3b, 0x9e, 0x63,
+	0xd1, 0x20, 0x62, 0xcb, 0x75, 0x5d, 0xef, 0x48, 0xc6, 0x91, 0xa0, 0x6b, 0xff, 0x36, 0x07, 0x93,
+	0x68, 0x0d, 0xa6, 0x38, 0xd3, 0x62, 0x07, 0x01, 0x91, 0xe2, 0x78, 0x8b, 0x7c, 0x08, 0xe0, 0x86,
+	0xc6, 0xa5, 0x13, 0xc5, 0xc8, 0x2b, 0xb3, 0x9c, 0xd1, 0x51, 0x39, 0xe3, 0x84, 0xd3, 0xf5, 0xba,
+	0x1b, 0x8a, 0x9f, 0xe4, 0x07, 0x68, 0x77, 0x40, 0x03, 0x2b, 0xf0, 0xba, 0x95, 0xec, 0x17, 0x12,
+	0x64, 0x5d, 0x01, 0xc8, 0x12, 0x4c, 0xc7, 0x91, 0x65, 0xf8, 0x0e, 0x8e, 0xb1, 0xc2, 0x32, 0x6b,
+	0x64, 0xed, 0x3b, 0x94, 0xfc, 0x08, 0xea, 0xc8, 0x08, 0x83, 0x88, 0xc6, 0xdd, 0x29, 0xe6, 0x4a,
+	0x35, 0x67, 0x82, 0x88, 0xea, 0xa6, 0x7f, 0xee, 0xe8, 0xb5, 0x38, 0xb2, 0xb0, 0x15, 0xa3, 0x1e,
+	0x3b, 0xa6, 0x4c, 0x4f, 0x95, 0xeb, 0xb1, 0x63, 0x2a, 0xf4, 0x20, 0x83, 0xeb, 0x99, 0x1e, 0xa7,
+	0xc7, 0x8e, 0x29, 0xd7, 0xb3, 0x0a, 0x75, 0xd7, 0x1a, 0x84, 0x06, 0x4b, 0x90, 0xb8, 0x2d, 0x98,
+	0x7a, 0x39, 0xa1, 0xd7, 0x90, 0xc4, 0x72, 0xdf, 0x27, 0xd0, 0x56, 0x6c, 0xc3, 0x0a, 0x6c, 0xb9,
+	0x13, 0x90, 0xeb, 0x76, 0x5f, 0x00, 0xb7, 0x7c, 0x7b, 0x27, 0xb0, 0x59, 0x19, 0x48, 0xca, 0x62,
+	0x9b, 0xbc, 0x0b, 0x6d, 0x1c, 0x95, 0x1b, 0x1a, 0x58, 0x16, 0x75, 0xed, 0xb8, 0x0b, 0xcc, 0xda,
+	0x46, 0x1c, 0x59, 0xfd, 0xf0, 0xc8, 0xa1, 0x7d, 0x3b, 0x46, 0x10, 0x9a, 0x9c, 0x02, 0x35, 0x38,
+	0xc8, 0x8e, 0xa9, 0x02, 0x3d, 0x87, 0x65, 0xe6, 0x38, 0x73, 0xe0, 0xd8, 0x6c, 0x74, 0x69, 0x7c,
+	0x93, 0xe1, 0xe7, 0xd1, 0x95, 0xc8, 0xc7, 0xa1, 0xa5, 0x05, 0x99, 0xa7, 0x0a, 0x05, 0x5b, 0x5c,
+	0x10, 0x7d, 0x37, 0x22, 0xf8, 0x43, 0x98, 0x13, 0x66, 0x31, 0x29, 0x29, 0x32, 0xc3, 0x44, 0x66,
+	0x98, 0x6d, 0x88, 0x17, 0xe8, 0xa7, 0xd0, 0xf4, 0x03, 0x6a, 0xa8, 0x48, 0x38, 0x2b, 0x8e, 0x84,
+	0x86, 0x1f, 0x50, 0xd9, 0x20, 0xf7, 0x00, 0x9b, 0x86, 0x0c, 0x88, 0x73, 0xa6, 0xb9, 0xee, 0x07,
+	0xf4, 0x88, 0xc7, 0xc4, 0x06, 0xb4, 0x24, 0x9f, 0x7f, 0xcf, 0x8b, 0x31, 0xdf, 0xb3, 0xc1, 0x65,
+	0xf8, 0x27, 0x15, 0x5a, 0x65, 0x78, 0xb8, 0x4a, 0x6b, 0x2f, 0xa6, 0x29, 0xad, 0x49, 0x94, 0xfc,
+	0xc9, 0x2d, 0x5a, 0x7b, 0x32, 0x50, 0x1e, 0x70, 0xa9, 0x24, 0x58, 0xbe, 0x61, 0xc1, 0x52, 0x62,
+	0x28, 0x19, 0x06, 0x64, 0x17, 0x48, 0x06, 0xc5, 0x63, 0xc6, 0xbb, 0x35, 0x66, 0x4a, 0xfa, 0x4c,
+	0x4a, 0x05, 0x92, 0xc8, 0x23, 0x20, 0x72, 0xe0, 0xa9, 0x8f, 0x35, 0xe0, 0xcb, 0x1f, 0x1f, 0xab,
+	0xfa, 0x4c, 0x02, 0x9b, 0x8b, 0x20, 0x5f, 0x61, 0x7b, 0xa9, 0x20, 0xfa, 0x04, 0x56, 0x95, 0xc3,
+	0x0b, 0xe3, 0x21, 0x64, 0x62, 0x4b, 0xe2, 0x13, 0x8c, 0x84, 0x84, 0x90, 0x1f, 0x1f, 0x4f, 0xdf,
+	0x2a, 0xf9, 0x5e, 0x51, 0x48, 0x3d, 0x85, 0x85, 0x24, 0x53, 0x45, 0x56, 0x92, 0xad, 0x22, 0x96,
+	0x82, 0xe6, 0x54, 0xb6, 0x8a, 0x2c, 0x99, 0xb0, 0x32, 0x32, 0xd8, 0xb1, 0x92, 0x89, 0xb3, 0x32,
+	0xbd, 0x98, 0x2a, 0x99, 0x5d, 0xb8, 0x9f, 0xe9, 0x27, 0x29, 0xa7, 0x29, 0x69, 0xca, 0xa4, 0xef,
+	0xa6, 0x7a, 0x54, 0x45, 0xb5, 0x42, 0x35, 0x72, 0xcc, 0x39, 0x35, 0xc3, 0xac, 0x1a, 0x31, 0xea,
+	0xac, 0x9a, 0x17, 0xb0, 0xac, 0xd4, 0x48, 0xf7, 0x2b, 0x05, 0x97, 0x4c, 0xc1, 0xa2, 0x04, 0xec,
+	0x33, 0xcf, 0x8f, 0x15, 0xcd, 0x38, 0xe0, 0x6a, 0x44, 0x34, 0xed, 0x83, 0x2f, 0x79, 0xc2, 0xc8,
+	0xd7, 0x38, 0x07, 0x26, 0xb5, 0x2e, 0xba, 0xd7, 0x99, 0xc3, 0x6e, 0xb6, 0xc4, 0xb9, 0x87, 0x08,
+	0x7d, 0x31, 0x8e, 0xac, 0x02, 0x3a, 0xaa, 0xe5, 0x46, 0x14, 0xa9, 0xbd, 0x79, 0xbd, 0x5a, 0x3b,
+	0xa6, 0x05, 0x74, 0x5c, 0x75, 0x2e, 0x28, 0x0d, 0x85, 0x9e, 0x3f, 0xcd, 0xec, 0x99, 0x5e, 0x1e,
+	0x1f, 0x1f, 0x72, 0xe9, 0x3a, 0x62, 0xa4, 0x40, 0x4d, 0xd6, 0x0e, 0xba, 0x7f, 0x96, 0xa9, 0xcb,
+	0xe3, 0xea, 0xa6, 0x0a, 0xc8, 0x0a, 0x44, 0x7e, 0x07, 0xe6, 0x73, 0x71, 0xc4, 0xac, 0xe8, 0xfe,
+	0x35, 0x5f, 0xfe, 0x48, 0x26, 0x8e, 0x18, 0x8b, 0xf4, 0xe0, 0x5e, 0x91, 0x48, 0x12, 0x07, 0xdd,
+	0xbf, 0xe1, 0xc2, 0x77, 0x46, 0x85, 0x55, 0x18, 0x64, 0x3a, 0x4e, 0x7d, 0x91, 0xee, 0x2f, 0x72,
+	0x1d, 0x1f, 0x45, 0x56, 0x51, 0xc7, 0xe9, 0x8f, 0x98, 0x74, 0xfc, 0xb7, 0xb9, 0x8e, 0x13, 0xe1,
+	0xa4, 0xe3, 0x2e, 0x4c, 0xe3, 0xce, 0xc4, 0x70, 0xed, 0xee, 0xaf, 0xc5, 0x1a, 0x8f, 0xed, 0xbe,
+	0x4d, 0x56, 0x01, 0xbc, 0xe0, 0xdc, 0xa0, 0x66, 0x74, 0xee, 0xd0, 0xee, 0x9f, 0x33, 0x5e, 0xdd,
+	0x0b, 0xce, 0x8f, 0x19, 0x81, 0x3c, 0x86, 0x39, 0x64, 0xc7, 0x37, 0x31, 0xfe, 0x39, 0x33, 0x2d,
+	0xd7, 0x73, 0xe9, 0x4d, 0xf7, 0x2f, 0x18, 0x6e, 0xd6, 0x0b, 0xce, 0x8f, 0x18, 0xe7, 0x53, 0xc1,
+	0x20, 0x0f, 0xa0, 0x8d, 0xc0, 0xc8, 0xa4, 0x8e, 0xe1, 0xb9, 0x03, 0x97, 0x76, 0xff, 0x92, 0x41,
+	0x9b, 0x5e, 0x70, 0xae, 0x9b, 0xd4, 0x79, 0x85, 0x34, 0xf2, 0x21, 0xcc, 0x67, 0x51, 0xc6, 0xe9,
+	0x30, 0x8a, 0x69, 0xf7, 0xe7, 0x6c, 0x13, 0x38, 0x9b, 0xc6, 0x6e, 0x23, 0x83, 0x3c, 0x81, 0x05,
+	0xf4, 0xba, 0x1d, 0x0c, 0x4c, 0xd7, 0x4f, 0x67, 0x99, 0x0e, 0xcb, 0x32, 0xb3, 0x76, 0x4c, 0x7b,
+	0x8c, 0xa7, 0xf2, 0xcb, 0x7d, 0x00, 0x3b, 0xb6, 0x42, 0x83, 0x1f, 0xa6, 0x66, 0x59, 0x86, 0x2e,
+	0xeb, 0x75, 0xa4, 0x9d, 0x20, 0x89, 0xbc, 0x07, 0x6d, 0x3e, 0x8d, 0x14, 0x28, 0x60, 0xa0, 0x8a,
+	0xde, 0x64, 0x39, 0x4b, 0xe2, 0x56, 0x01, 0x54, 0x0e, 0x8f, 0xbb, 0x64, 0xad, 0xb2, 0x3e, 0xa5,
+	0xd7, 0xe5, 0xca, 0x8e, 0x79, 0x70, 0x26, 0x9b, 0xe2, 0xe3, 0xee, 0xdc, 0x5a, 0x65, 0x7c, 0x8e,
+	0xd7, 0x5b, 0xe9, 0x5d, 0x41, 0xbc, 0x5d, 0x85, 0x49, 0x24, 0x6c, 0x03, 0xd4, 0xe4, 0x72, 0x81,
+	0x34, 0x34, 0x4b, 0xd2, 0xf0, 0xf7, 0xe7, 0xd5, 0xda, 0x7f, 0x94, 0x3a, 0xbf, 0x2e, 0xe9, 0xec,
+	0x6b, 0x85, 0x91, 0x73, 0xe6, 0x5e, 0x6b, 0x9f, 0xc1, 0x5c, 0xd1, 0x04, 0x5a, 0x81, 0x9a, 0x4a,
+	0x0c, 0xfc, 0x63, 0xab, 0x36, 0x1e, 0x2f, 0x59, 0xe4, 0x88, 0x73, 0x16, 0x6f, 0xe0, 0x81, 0xb2,
+	0xae, 0xa6, 0x16, 0x3f, 0x3e, 0xd2, 0x8b, 0xc0, 0xe6, 0x7b, 0xdf, 0xba, 0x2e, 0x9b, 0xe4, 0x09,
+	0x4c, 0x85, 0x26, 0xbd, 0x90, 0x1b, 0xdc, 0x95, 0xfc, 0xac, 0x7c, 0x7c, 0x68, 0xd2, 0x0b, 0xf6,
+	0x4b, 0xe7, 0xc0, 0x95, 0x2f, 0xa0, 0xae, 0x68, 0x64, 0x11, 0xa6, 0x9c, 0x6b, 0xd3, 0xa2, 0xdc,
+	0xaa, 0x97, 0x13, 0x3a, 0x6f, 0x92, 0x2e, 0x54, 0xf9, 0x88, 0xf8, 0x9e, 0x1c, 0x2f, 0xb2, 0x79,
+	0x7b, 0xbb, 0x09, 0x80, 0x7a, 0x78, 0x2e, 0xd0, 0x7e, 0x59, 0x82, 0x66, 0x7a, 0x4a, 0x93, 0x4f,
+	0xa1, 0x61, 0xfa, 0x7e, 0x40, 0x59, 0x61, 0x5a, 0xee, 0xd4, 0x1f, 0x14, 0x4c, 0xfe, 0xc7, 0x5b,
+	0x09, 0x8c, 0x1f, 0xc8, 0xd3, 0x82, 0x2b, 0x9f, 0x40, 0x27, 0x0f, 0x78, 0xab, 0xa3, 0xf9, 0x0b,
+	0x98, 0xc9, 0x7d, 0x66, 0x76, 0xf2, 0xc0, 0xbd, 0x01, 0xca, 0x4f, 0xf1, 0xb3, 0x34, 0xd2, 0xd8,
+	0x26, 0xa0, 0xcc, 0x69, 0xf8, 0x5b, 0x7b, 0x05, 0x35, 0xb5, 0x09, 0xea, 0x42, 0x55, 0x54, 0xa5,
+	0x4a, 0x62, 0xfb, 0x29, 0xda, 0x64, 0x3e, 0x7d, 0x66, 0x79, 0x39, 0xc1, 0x4f, 0x2d, 0xdb, 0x1d,
+	0x68, 0x73, 0xbe, 0x11, 0x44, 0x2c, 0x21, 0x68, 0xcf, 0xa0, 0xae, 0x36, 0x2d, 0x68, 0xef, 0x99,
+	0x8b, 0xd3, 0x8a, 0xdb, 0xc0, 0x1b, 0x68, 0x84, 0x67, 0xc6, 0x54, 0x1a, 0x81, 0xbf, 0xb5, 0x7f,
+	0x2a, 0x01, 0xc9, 0x17, 0xd6, 0xfa, 0x3d, 0x3c, 0x77, 0x07, 0x91, 0x75, 0xe1, 0xc4, 0x34, 0x32,
+	0x69, 0x10, 0x61, 0xf6, 0xe0, 0x43, 0x6f, 0xa7, 0xc9, 0x7d, 0x9b, 0xdc, 0x87, 0x86, 0xaa, 0xe2,
+	0xb9, 0xb6, 0x28, 0xf1, 0x80, 0x24, 0x71, 0x80, 0xaa, 0xee, 0xb9, 0x36, 0x3b, 0xd3, 0xd4, 0x75,
+	0x90, 0xa4, 0xbe, 0xfd, 0xf9, 0x64, 0xad, 0xd4, 0x29, 0xeb, 0x35, 0xac, 0x4a, 0xb2, 0x81, 0x5c,
+	0xc3, 0x62, 0xf1, 0xfd, 0x2f, 0xf9, 0x20, 0x75, 0xfe, 0x5b, 0x1e, 0x53, 0x14, 0x14, 0x47, 0xd1,
+	0x8f, 0xa0, 0x26, 0xbb, 0xe8, 0x4e, 0x65, 0xde, 0x30, 0xe4, 0x05, 0x74, 0x05, 0xd4, 0x7e, 0x33,
+	0x09, 0x9d, 0x3c, 0x1b, 0x5d, 0x19, 0x53, 0x93, 0xca, 0x13, 0x39, 0x6f, 0x14, 0x9d, 0x24, 0x31,
+	0x6c, 0x06, 0xa6, 0x25, 0x5c, 0x80, 0x3f, 0x71, 0xec, 0xf2, 0xe1, 0x01, 0x66, 0x2c, 0x7e, 0xd6,
+	0x01, 0x41, 0xc2, 0x54, 0x75, 0x07, 0xea, 0x6e, 0x78, 0xb9, 0x81, 0x5b, 0x54, 0x7e, 0xde, 0xa9,
+	0xeb, 0x35, 0x24, 0xec, 0x3b, 0x54, 0x32, 0x37, 0x39, 0xb3, 0xaa, 0x98, 0x9b, 0x8c, 0xf9, 0x10,
+	0xa6, 0xf0, 0x48, 0x2b, 0x4f, 0x37, 0x72, 0x8b, 0x7d, 0xec, 0x3a, 0x51, 0xdf, 0x3f, 0x0b, 0x74,
+	0xce, 0x25, 0x1f, 0x40, 0x8d, 0x77, 0x60, 0xd2, 0x6e, 0x6d, 0xad, 0x92, 0xaa, 0x5f, 0xec, 0x9b,
+	0x94, 0x01, 0xa7, 0x59, 0x7f, 0x26, 0x15, 0xd0, 0x4d, 0x06, 0xad, 0x8f, 0x85, 0x6e, 0x22, 0x74,
+	0x0b, 0x56, 0x4d, 0xcf, 0x0b, 0xae, 0x8c, 0x38, 0x0c, 0x82, 0x33, 0xc7, 0x36, 0x44, 0xf9, 0x90,
+	0x4f, 0x5d, 0x47, 0x9e, 0x6f, 0x56, 0x18, 0xe8, 0x88, 0x63, 0x78, 0xbd, 0xee, 0x50, 0x20, 0xc8,
+	0xe7, 0xd9, 0xf9, 0xdb, 0x60, 0x1d, 0xae, 0x8f, 0xf9, 0x46, 0xb7, 0xcf, 0x61, 0xf2, 0x1c, 0x5a,
+	0x6c, 0x0b, 0x3a, 0x30, 0xc3, 0xd0, 0xf5, 0xcf, 0xf9, 0x49, 0x28, 0xb9, 0xad, 0xc4, 0x69, 0xb1,
+	0xc7, 0x59, 0x7a, 0x33, 0x4c, 0x1a, 0x78, 0xe6, 0x6a, 0x89, 0x77, 0x17, 0xbc, 0xf8, 0xc0, 0xee,
+	0xf6, 0x9b, 0x7a, 0x93, 0x13, 0xb7, 0x19, 0xed, 0x3b, 0x67, 0x88, 0x9d, 0xd1, 0x78, 0x16, 0xf5,
+	0x8c, 0x37, 0x8f, 0x67, 0x6d, 0x0b, 0xda, 0xe9, 0x92, 0x7e, 0xbf, 0x97, 0x9f, 0x57, 0xe5, 0xd7,
+	0xce, 0x2b, 0x0f, 0xc8, 0xe8, 0xcb, 0x0f, 0xf2, 0x30, 0x65, 0xc3, 0x42, 0xc1, 0xe5, 0x81, 0x98,
+	0x4f, 0x1f, 0xa6, 0xe6, 0x53, 0x25, 0xb3, 0xd1, 0x4a, 0x83, 0x53, 0x73, 0xe9, 0x97, 0x15, 0x68,
+	0xa6, 0x59, 0x45, 0x85, 0xad, 0xfc, 0xfc, 0x28, 0x8f, 0xcc, 0x0f, 0x15, 0xe5, 0x95, 0x5b, 0xa3,
+	0xfc, 0x31, 0xcc, 0x39, 0xd7, 0xa1, 0x63, 0x51, 0xc7, 0x36, 0x58, 0xb8, 0x9b, 0xb6, 0x1d, 0xc9,
+	0xf9, 0x36, 0x2b, 0x59, 0xfd, 0xf0, 0x72, 0x63, 0xcb, 0xb6, 0x47, 0xf1, 0x9b, 0x02, 0x3f, 0x35,
+	0x82, 0xdf, 0xe4, 0xf8, 0x1f, 0xc3, 0x8c, 0xaa, 0xd0, 0x18, 0xdc, 0xa0, 0x6a, 0xb1, 0x41, 0x6d,
+	0x85, 0x3b, 0x66, 0x96, 0x3d, 0x83, 0xb6, 0x2c, 0xe7, 0x18, 0xb7, 0xce, 0xd7, 0xa6, 0xa8, 0xf2,
+	0x70, 0xb1, 0x0d, 0x68, 0x9d, 0x05, 0xd1, 0x15, 0x5e, 0x41, 0x70, 0xa9, 0xda, 0x18, 0x29, 0x81,
+	0xe2, 0x52, 0x23, 0xe1, 0x5c, 0x1f, 0x0d, 0x67, 0xed, 0x77, 0xb3, 0x61, 0x20, 0x42, 0xf1, 0xcd,
+	0xc2, 0x40, 0x8b, 0xa0, 0x26, 0xfb, 0x2e, 0xfc, 0xa0, 0x1f, 0x40, 0xc7, 0xf5, 0xcf, 0x23, 0xbc,
+	0x57, 0x63, 0x9d, 0xba, 0x6a, 0xbb, 0x31, 0x23, 0xe8, 0x87, 0x82, 0x8c, 0x2b, 0x8c, 0x93, 0x43,
+	0x8a, 0xca, 0xae, 0x93, 0x01, 0x6a, 0xcf, 0x61, 0x5a, 0x24, 0x20, 0xb2, 0x00, 0x55, 0xe7, 0x1a,
+	0x8f, 0x9a, 0x32, 0x19, 0x3b, 0xd7, 0xb4, 0x1f, 0x22, 0x99, 0xcd, 0x82, 0x50, 0x4e, 0x3e, 0x34,
+	0x38, 0xd4, 0x74, 0x98, 0x2b, 0xb8, 0xc0, 0x43, 0x2f, 0xb9, 0x71, 0x60, 0x50, 0x77, 0xe0, 0xc4,
+	0xd4, 0x1c, 0x48, 0x5d, 0x4d, 0x37, 0x0e, 0x8e, 0x25, 0x0d, 0xeb, 0x62, 0xc3, 0x10, 0x21, 0x4c,
+	0x65, 0x49, 0x17, 0x2d, 0x2d, 0x84, 0xee, 0xb8, 0xcb, 0xbb, 0x37, 0x9d, 0x4a, 0x3f, 0x82, 0x2a,
+	0xbf, 0x56, 0xea, 0x96, 0x33, 0xd0, 0xac, 0x4e, 0x5d, 0x80, 0xb4, 0x75, 0x68, 0x67, 0x39, 0x68,
+	0x9b, 0x50, 0x20, 0xaf, 0x25, 0x38, 0x72, 0xab, 0xc8, 0xb6, 0xb7, 0xfb, 0xbe, 0xd7, 0x70, 0xf7,
+	0xb6, 0x3b, 0xbd, 0xb7, 0x59, 0x81, 0xdf, 0x72, 0x98, 0xfd, 0x71, 0x3d, 0xbf, 0x7d, 0xae, 0x3c,
+	0x87, 0x85, 0xc2, 0xbb, 0x39, 0xdc, 0xcf, 0x87, 0xc3, 0x53, 0xcf, 0xb5, 0x8c, 0x24, 0x79, 0xd7,
+	0x39, 0xe5, 0x0b, 0xe7, 0xe6, 0xad, 0x6b, 0x9e, 0xda, 0x2c, 0xcc, 0xe4, 0xae, 0xec, 0xb4, 0xbf,
+	0x2f, 0xc3, 0x62, 0xf1, 0x35, 0x38, 0xee, 0xcd, 0x65, 0x2e, 0x96, 0x7b, 0x73, 0xd9, 0x56, 0xfb,
+	0x00, 0xcc, 0x43, 0x22, 0x88, 0xd9, 0xba, 0x8d, 0xe9, 0x47, 0xed, 0x03, 0x18, 0xb3, 0xa2, 0x98,
+	0x2c, 0x37, 0xa1, 0x56, 0x33, 0x16, 0x5b, 0x47, 0xbe, 0xb7, 0x52, 0x6d, 0xb2, 0x05, 0x55, 0xcf,
+	0x3c, 0x75, 0x3c, 0x59, 0x4a, 0xfd, 0xe0, 0xd6, 0x7b, 0xfa, 0xc7, 0xaf, 0x18, 0x56, 0x5c, 0x5a,
+	0x71, 0x41, 0xbc, 0xb4, 0x4a, 0x91, 0xdf, 0x6a, 0xdd, 0xfb, 0xfd, 0x51, 0x4f, 0x88, 0x6f, 0xf9,
+	0xff, 0xf5, 0x84, 0xb6, 0x07, 0x24, 0xad, 0xf2, 0x3b, 0x3a, 0x36, 0xaf, 0xee, 0xbb, 0x5a, 0x77,
+	0x00, 0xf3, 0x45, 0xef, 0x35, 0xde, 0x40, 0xe1, 0x66, 0x5e, 0xe1, 0x66, 0xb1, 0xc2, 0x37, 0xb6,
+	0x70, 0x8c, 0xc2, 0x5d, 0x68, 0x67, 0x1f, 0xfe, 0x15, 0x5c, 0xca, 0x4d, 0x86, 0x41, 0xe0, 0x89,
+	0x39, 0x3b, 0x93, 0x7f, 0xea, 0xc7, 0x98, 0xda, 0x5a, 0xa2, 0x66, 0xcc, 0x75, 0xdb, 0xaf, 0x4a,
+	0x50, 0x93, 0x10, 0x76, 0xf6, 0x71, 0x6d, 0x75, 0x13, 0x83, 0xbf, 0xc9, 0x3d, 0x80, 0x81, 0x19,
+	0x7f, 0x3b, 0x74, 0x22, 0x53, 0x9c, 0x8a, 0x6a, 0x7a, 0x8a, 0xc2, 0x87, 0xe1, 0x86, 0xc6, 0x00,
+	0x0f, 0x4d, 0x2a, 0xe6, 0xdd, 0x70, 0x0f, 0x0f, 0x58, 0xab, 0x00, 0x97, 0xd7, 0x9e, 0xe9, 0x73,
+	0x2e, 0x8f, 0xfa, 0x3a, 0xa3, 0x30, 0xf6, 0x13, 0x58, 0x10, 0x2b, 0x4b, 0x8c, 0xcb, 0xae, 0x2a,
+	0x19, 0x88, 0x3b, 0x93, 0x59, 0xce, 0x3c, 0xf2, 0x4d, 0x59, 0x92, 0xd4, 0xfe, 0xaa, 0x04, 0xad,
+	0xcc, 0xd3, 0x27, 0xbc, 0xa3, 0x62, 0xfd, 0x3b, 0xbe, 0x79, 0xea, 0x39, 0x7c, 0x68, 0x35, 0x7c,
+	0xae, 0xec, 0x86, 0xbb, 0x9c, 0x84, 0xeb, 0x08, 0xb7, 0x42, 0x62, 0xf8, 0x28, 0x9a, 0x8c, 0x28,
+	0x41, 0xeb, 0xd0, 0xc9, 0x80, 0x8c, 0xcb, 0x4d, 0x71, 0xe7, 0xd3, 0x4e, 0xe3, 0x4e, 0x36, 0xb5,
+	0x7f, 0x2d, 0xc1, 0x7c, 0xd1, 0xd3, 0x45, 0xf2, 0x7e, 0x2a, 0xf3, 0x2d, 0x15, 0x16, 0xd5, 0x44,
+	0xc6, 0xfd, 0xa9, 0x9a, 0xee, 0xfc, 0x8c, 0xfe, 0xfe, 0x2d, 0x0f, 0x22, 0xbf, 0xef, 0xc9, 0xfe,
+	0xd3, 0xbc, 0xf1, 0xea, 0xd9, 0xc5, 0x9b, 0x19, 0xaf, 0xf5, 0xa0, 0x93, 0xa7, 0x67, 0x2f, 0xbc,
+	0x4a, 0xf9, 0x0b, 0xaf, 0xa2, 0xcb, 0xbc, 0x5f, 0x95, 0x60, 0x26, 0xf7, 0xb6, 0x92, 0x68, 0x29,
+	0x13, 0x48, 0xfe, 0xe9, 0xa4, 0x70, 0xdd, 0xc7, 0x39, 0xd7, 0x69, 0xc5, 0xef, 0x34, 0xbf, 0x6f,
+	0xaf, 0x3d, 0x4b, 0x59, 0x2b, 0x1c, 0xf6, 0x06, 0xd6, 0x6a, 0xef, 0x40, 0x23, 0x45, 0x2a, 0xbc,
+	0x32, 0x3e, 0x06, 0xe0, 0x4f, 0x24, 0x8f, 0x45, 0xf5, 0x01, 0x23, 0x57, 0x44, 0x31, 0xfb, 0xcd,
+	0xac, 0xc2, 0x08, 0x14, 0x61, 0xcb, 0x1b, 0xe8, 0x72, 0xf5, 0x7c, 0x45, 0x5e, 0x4e, 0x2a, 0x82,
+	0xf6, 0xdf, 0x65, 0x68, 0xa4, 0x1e, 0x8d, 0x92, 0x07, 0xa9, 0x4a, 0x47, 0xb2, 0x56, 0x32, 0x44,
+	0xf2, 0x8e, 0x80, 0x7c, 0x84, 0x73, 0x89, 0x3f, 0x24, 0x66, 0x68, 0xbe, 0xb2, 0xce, 0xaa, 0xdc,
+	0x82, 0x49, 0x82, 0xc1, 0xc1, 0x0d, 0xe5, 0x6f, 0x74, 0xa3, 0x1d, 0x53, 0x79, 0x98, 0xb6, 0x63,
+	0x4a, 0x34, 0x68, 0xb1, 0xf2, 0x7b, 0x60, 0xf3, 0x12, 0xa8, 0x98, 0xf8, 0x78, 0x3f, 0xb6, 0x1f,
+	0xd8, 0xac, 0xe2, 0x89, 0xb7, 0x3e, 0x0a, 0xe3, 0x86, 0xf2, 0x92, 0x54, 0x20, 0xfa, 0x21, 0x1e,
+	0x38, 0x62, 0x73, 0xe0, 0x18, 0xf1, 0xf0, 0x14, 0x6f, 0x85, 0xa6, 0x79, 0xde, 0x41, 0xd2, 0x11,
+	0xa3, 0xe0, 0xbc, 0xc7, 0x9c, 0x11, 0x0c, 0xe9, 0x79, 0xe0, 0xfa, 0xe7, 0xec, 0x32, 0xb0, 0xa6,
+	0x37, 0x7c, 0x93, 0x1e, 0x08, 0x12, 0x79, 0x88, 0x75, 0x4e, 0xcb, 0xf4, 0x0c, 0x59, 0xe4, 0x60,
+	0xdb, 0xec, 0x9a, 0xde, 0x62, 0x54, 0xb9, 0x27, 0x21, 0x4f, 0xa1, 0x41, 0xd9, 0x17, 0xe0, 0x83,
+	0xe6, 0x2f, 0x7d, 0xe4, 0xa0, 0x93, 0x6f, 0xa3, 0x03, 0x55, 0xbf, 0xb5, 0xfb, 0xc2, 0xbd, 0x22,
+	0x16, 0x84, 0x0f, 0xca, 0xca, 0x07, 0xda, 0xff, 0x94, 0x60, 0x79, 0xec, 0x23, 0x5a, 0x16, 0x08,
+	0x81, 0xcd, 0x3f, 0x07, 0x06, 0x42, 0x60, 0xab, 0xa2, 0x44, 0x39, 0x29, 0x4a, 0x64, 0xd6, 0xb0,
+	0x4a, 0x6e, 0xaf, 0xb1, 0x0e, 0x9d, 0xd0, 0x8c, 0x1c, 0x9f, 0x1a, 0xb6, 0xc3, 0x8a, 0xcd, 0x6e,
+	0x28, 0xfc, 0xdc, 0xe6, 0xf4, 0x1e, 0x23, 0xf3, 0x4d, 0xf7, 0xc0, 0xb4, 0x30, 0x9f, 0x71, 0x2f,
+	0x4f, 0x0d, 0x4c, 0xeb, 0x64, 0x33, 0xbb, 0xfe, 0x54, 0x73, 0x9b, 0x95, 0x1f, 0x02, 0xc9, 0x6b,
+	0xbf, 0xdc, 0x64, 0x5f, 0xa1, 0xae, 0x77, 0xb2, 0xfa, 0x2f, 0x37, 0xb5, 0x0f, 0x0b, 0xc7, 0x2a,
+	0x7c, 0x53, 0x30, 0x56, 0xed, 0x17, 0x25, 0x58, 0x1a, 0xf3, 0x94, 0xf7, 0xd6, 0x35, 0x33, 0xbb,
+	0x2f, 0x2c, 0xe7, 0xf7, 0x85, 0x8f, 0x61, 0xce, 0xf5, 0xa9, 0x13, 0x9d, 0x99, 0xdc, 0xe2, 0x8c,
+	0xeb, 0x66, 0x15, 0x4b, 0x1e, 0x2f, 0xb5, 0x67, 0x05, 0x56, 0xbc, 0x7e, 0xe5, 0xd6, 0xfe, 0xb1,
+	0x04, 0xcb, 0x63, 0x1f, 0xad, 0xde, 0x6a, 0xbf, 0x06, 0xad, 0xc4, 0x7e, 0xfc, 0x22, 0x7c, 0x08,
+	0x0d, 0x35, 0x84, 0x93, 0xcd, 0x91, 0x41, 0x6c, 0x8e, 0x1d, 0x04, 0xdf, 0x2a, 0x3c, 0x2f, 0x34,
+	0xe6, 0x0d, 0x86, 0xf1, 0xef, 0x25, 0x58, 0x28, 0x7c, 0x94, 0x8c, 0x77, 0x78, 0xf2, 0x0a, 0xc3,
+	0xf2, 0x86, 0x31, 0x75, 0x22, 0x03, 0xf7, 0x02, 0xb2, 0xd4, 0x3c, 0x27, 0x98, 0x3b, 0x9c, 0xb7,
+	0x83, 0x2c, 0xb2, 0x91, 0xbc, 0xcf, 0x77, 0xae, 0xa9, 0x13, 0xe1, 0x5d, 0x08, 0x17, 0x2a, 0x8b,
+	0xdb, 0x6e, 0xce, 0xdd, 0x15, 0x4c, 0x2e, 0xf5, 0x13, 0x58, 0x91, 0x52, 0x38, 0x17, 0x4f, 0x4d,
+	0xcf, 0xf4, 0x2d, 0xd5, 0x1d, 0x3f, 0x66, 0x76, 0x05, 0xe2, 0x55, 0x0a, 0xc0, 0xa4, 0xb5, 0xaf,
+	0xa1, 0x21, 0x96, 0x22, 0xac, 0x1c, 0x91, 0x95, 0xa4, 0x4c, 0x2b, 0x07, 0x2b, 0xdb, 0x18, 0x85,
+	0x88, 0x91, 0x15, 0x55, 0x89, 0xc7, 0x6c, 0xc3, 0xe8, 0x15, 0x46, 0x57, 0x6d, 0x9c, 0xbf, 0xad,
+	0xcc, 0x23, 0xe9, 0xc2, 0x53, 0x74, 0x66, 0xdd, 0x2b, 0x17, 0xac, 0x7b, 0xea, 0x21, 0x57, 0x5d,
+	0xa4, 0xd8, 0x55, 0x00, 0xe9, 0x52, 0x35, 0x61, 0xeb, 0x82, 0xd2, 0x0f, 0xf1, 0xac, 0x9d, 0xf1,
+	0x83, 0x4a, 0x8d, 0xed, 0x34, 0xb9, 0x1f, 0x62, 0xfa, 0x53, 0x6e, 0x76, 0x43, 0x59, 0x75, 0x6c,
+	0x48, 0x5a, 0x3f, 0x8c, 0xc9, 0x3a, 0x4c, 0xa5, 0x9f, 0x55, 0x90, 0xec, 0xa2, 0x8e, 0xa3, 0xd4,
+	0x39, 0x40, 0xdb, 0x52, 0x63, 0x4d, 0xcd, 0xd9, 0xb7, 0x1a, 0xab, 0xf6, 0x77, 0x25, 0x68, 0xa4,
+	0xca, 0x77, 0xe8, 0xdb, 0x30, 0xf7, 0x2d, 0x64, 0x1b, 0x33, 0x0f, 0x7b, 0x1f, 0x1b, 0x26, 0x1f,
+	0x84, 0x45, 0x25, 0xfb, 0x28, 0x0f, 0xa1, 0x6d, 0x05, 0x3e, 0x35, 0x5d, 0xdf, 0x89, 0x38, 0x82,
+	0x7f, 0x9a, 0x96, 0xa2, 0x32, 0xd8, 0x12, 0x4c, 0x33, 0x1d, 0xca, 0x89, 0x55, 0x6c, 0xf6, 0xc3,
+	0x47, 0xeb, 0xf8, 0x16, 0x4e, 0xbe, 0x75, 0x99, 0x86, 0xca, 0xd6, 0xfe, 0xd7, 0x9d, 0x09, 0x52,
+	0x83, 0xc9, 0xfe, 0xe1, 0xc9, 0x46, 0x67, 0x52, 0xfc, 0xda, 0xec, 0x54, 0x1f, 0xfd, 0x03, 0x3e,
+	0x21, 0x94, 0x2b, 0x20, 0x69, 0x41, 0x7d, 0xa7, 0xdf, 0xd3, 0x8d, 0xfe, 0xfe, 0xa7, 0x07, 0x9d,
+	0x09, 0x32, 0x07, 0x33, 0xfa, 0xee, 0xde, 0xc1, 0xf1, 0xae, 0xf1, 0xd5, 0x81, 0xfe, 0xc5, 0xab,
+	0x83, 0xad, 0x5e, 0xa7, 0x84, 0x4f, 0xea, 0x04, 0xf1, 0xe5, 0xc1, 0x11, 0xbe, 0xa4, 0x23, 0xd0,
+	0x7e, 0x75, 0xb0, 0xb3, 0xf5, 0x2a, 0x01, 0x55, 0x48, 0x1b, 0x80, 0xd3, 0x18, 0x66, 0x92, 0xcc,
+	0x42, 0x4b, 0x08, 0x1d, 0x7f, 0xb9, 0xbf, 0xbf, 0xfb, 0xaa, 0x33, 0x45, 0x3a, 0xd0, 0xe4, 0x10,
+	0x41, 0xa9, 0x3e, 0x7a, 0x01, 0x90, 0x2c, 0xaf, 0x68, 0xe3, 0xfe, 0xc1, 0xfe, 0x6e, 0x67, 0x82,
+	0x34, 0xa1, 0xb6, 0x7f, 0x60, 0xec, 0xee, 0xef, 0x6c, 0x1d, 0x76, 0x4a, 0xa4, 0x0e, 0x53, 0x2c,
+	0xcf, 0x76, 0xca, 0x7c, 0x18, 0xfd, 0xc3, 0x4e, 0xe5, 0xe9, 0x27, 0x00, 0xfc, 0x55, 0x14, 0xfb,
+	0xaf, 0xc2, 0x27, 0x30, 0xc9, 0xfe, 0xaa, 0xaf, 0x9d, 0xfc, 0xaf, 0xe2, 0x8a, 0xa4, 0xa5, 0xfe,
+	0x5f, 0xf1, 0x49, 0x69, 0x7b, 0xfa, 0x67, 0x53, 0xec, 0xcb, 0x9c, 0x56, 0xd9, 0x9f, 0x8f, 0xfe,
+	0x6f, 0x00, 0x3d, 0x96, 0xd1, 0xf8, 0xfd, 0x38, 0x00, 0x00,
 }
@@ -461,6 +461,22 @@ func (r *RouteTable) RouteRemove(ifaceName string, cidr ip.CIDR) {
 	}
 }
 
+// RemoveAllRoutes drops all the routes of the table, so that the next Apply removes them from
+// the dataplane.
+func (r *RouteTable) RemoveAllRoutes() {
+	ifaceNames := set.New[string]()
+	for ifaceName := range r.ifaceNameToTargets {
+		ifaceNames.Add(ifaceName)
+	}
+	for ifaceName := range r.pendingIfaceNameToDeltaTargets {
+		ifaceNames.Add(ifaceName)
+	}
+	ifaceNames.Iter(func(ifaceName string) error {
+		r.SetRoutes(ifaceName, nil)
+		return nil
+	})
+}
+
 func (r *RouteTable) QueueResync() {
 	r.logCxt.Debug("Queueing a resync of routing table.")
 	r.reSync = true
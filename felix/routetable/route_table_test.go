@@ -234,6 +234,18 @@ var _ = Describe("RouteTable", func() {
 			Expect(dataplane.RouteKeyToRoute).To(ConsistOf(gatewayRoute))
 			Expect(dataplane.AddedRouteKeys).To(BeEmpty())
 		})
+		It("should remove all its routes", func() {
+			rt.SetRoutes("cali1", []Target{{CIDR: ip.MustParseCIDROrIP("10.0.0.1/32")}})
+			rt.RouteUpdate("cali3", Target{CIDR: ip.MustParseCIDROrIP("10.0.0.3/32")})
+			err := rt.Apply()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataplane.RouteKeyToRoute).To(HaveLen(3))
+
+			rt.RemoveAllRoutes()
+			err = rt.Apply()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dataplane.RouteKeyToRoute).To(ConsistOf(gatewayRoute))
+		})
 		It("should delete only our conntrack entries", func() {
 			err := rt.Apply()
 			Expect(err).ToNot(HaveOccurred())
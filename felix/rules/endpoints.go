@@ -23,9 +23,11 @@ import (
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/sha3"
 
+	"github.com/projectcalico/calico/felix/flowlog"
 	"github.com/projectcalico/calico/felix/hashutils"
 	. "github.com/projectcalico/calico/felix/iptables"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 )
 
 const (
@@ -457,12 +459,14 @@ func (r *DefaultRuleRenderer) endpointIptablesChain(policyGroups []*PolicyGroup,
 			}
 		}
 
-		if chainType == chainTypeNormal || chainType == chainTypeForward {
+		if HasEnforcedPolicies(policyGroups) && (chainType == chainTypeNormal || chainType == chainTypeForward) {
 			// When rendering normal and forward rules, if no policy marked the packet as "pass", drop
-			// or reject the packet.
+			// or reject the packet.  Staged policies never set a verdict, so they don't count
+			// as policies here.
 			//
 			// For untracked and pre-DNAT rules, we don't do that because there may be
 			// normal rules still to be applied to the packet in the filter table.
+			rules = r.appendFlowLogDefaultDenyRule(rules, Match().MarkClear(r.IptablesMarkPass))
 			rules = append(rules, Rule{
 				Match:   Match().MarkClear(r.IptablesMarkPass),
 				Action:  r.IptablesFilterDenyAction(),
@@ -470,7 +474,9 @@ func (r *DefaultRuleRenderer) endpointIptablesChain(policyGroups []*PolicyGroup,
 			})
 		}
 
-	} else if chainType == chainTypeForward {
+	}
+
+	if !HasEnforcedPolicies(policyGroups) && chainType == chainTypeForward {
 		// Forwarded traffic is allowed when there are no policies with
 		// applyOnForward that apply to this endpoint (and in this direction).
 		rules = append(rules, Rule{
@@ -504,6 +510,7 @@ func (r *DefaultRuleRenderer) endpointIptablesChain(policyGroups []*PolicyGroup,
 		// For untracked rules, we don't do that because there may be tracked rules
 		// still to be applied to the packet in the filter table.
 		// if dropIfNoProfilesMatched {
+		rules = r.appendFlowLogDefaultDenyRule(rules, Match())
 		rules = append(rules, Rule{
 			Match:   Match(),
 			Action:  r.IptablesFilterDenyAction(),
@@ -518,6 +525,21 @@ func (r *DefaultRuleRenderer) endpointIptablesChain(policyGroups []*PolicyGroup,
 	}
 }
 
+// appendFlowLogDefaultDenyRule appends the rule that reports the packets that are about to be
+// denied because no policy or profile allowed them, if flow logs are enabled.
+func (r *DefaultRuleRenderer) appendFlowLogDefaultDenyRule(rules []Rule, match MatchCriteria) []Rule {
+	if r.FlowLogsNFLOGGroup == 0 {
+		return rules
+	}
+	return append(rules, Rule{
+		Match: match,
+		Action: NflogAction{
+			Group:  uint16(r.FlowLogsNFLOGGroup),
+			Prefix: flowlog.NFLOGPrefix(flowlog.ActionDeny, ""),
+		},
+	})
+}
+
 func (r *DefaultRuleRenderer) appendConntrackRules(rules []Rule, allowAction Action) []Rule {
 	// Allow return packets for established connections.
 	if allowAction != (AcceptAction{}) {
@@ -621,6 +643,19 @@ func (g *PolicyGroup) ShouldBeInlined() bool {
 	return len(g.PolicyNames) <= 1
 }
 
+// HasEnforcedPolicies returns true if any of the groups contains a policy that is not
+// staged.
+func HasEnforcedPolicies(groups []*PolicyGroup) bool {
+	for _, g := range groups {
+		for _, name := range g.PolicyNames {
+			if !model.PolicyIsStaged(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // PolicyGroupSliceStringer provides a String() method for a slice of
 // PolicyGroup pointers.
 type PolicyGroupSliceStringer []*PolicyGroup
@@ -27,6 +27,7 @@ import (
 
 	"github.com/projectcalico/calico/felix/ipsets"
 	. "github.com/projectcalico/calico/felix/iptables"
+	"github.com/projectcalico/calico/felix/proto"
 )
 
 var _ = Describe("Endpoints", func() {
@@ -281,6 +282,44 @@ var _ = Describe("Endpoints", func() {
 				})))
 			})
 
+			It("should not drop by default when only staged policies apply", func() {
+				stagedChain := PolicyChainName(PolicyInboundPfx, &proto.PolicyID{Name: "staged:ai"})
+				chains := renderer.WorkloadEndpointToIptablesChains(
+					"cali1234",
+					epMarkMapper,
+					true,
+					singlePolicyGroups([]string{"staged:ai"}),
+					nil,
+					[]string{"prof1"},
+				)
+				Expect(chains[0]).To(Equal(&Chain{
+					Name: "cali-tw-cali1234",
+					Rules: []Rule{
+						// conntrack rules.
+						{Match: Match().ConntrackState("RELATED,ESTABLISHED"),
+							Action: AcceptAction{}},
+						{Match: Match().ConntrackState("INVALID"),
+							Action: denyAction},
+
+						{Action: ClearMarkAction{Mark: 0x18}},
+
+						{Match: Match().MarkClear(0x10),
+							Action: JumpAction{Target: stagedChain}},
+						{Match: Match().MarkSingleBitSet(0x8),
+							Action:  ReturnAction{},
+							Comment: []string{"Return if policy accepted"}},
+
+						{Action: JumpAction{Target: "cali-pri-prof1"}},
+						{Match: Match().MarkSingleBitSet(0x8),
+							Action:  ReturnAction{},
+							Comment: []string{"Return if profile accepted"}},
+
+						{Action: denyAction,
+							Comment: []string{fmt.Sprintf("%s if no profiles matched", denyActionString)}},
+					},
+				}))
+			})
+
 			It("should render a workload endpoint with policy groups", func() {
 				format.MaxLength = 1000000
 
@@ -900,6 +939,43 @@ func trimSMChain(ipvsEnable bool, chains []*Chain) []*Chain {
 	return result
 }
 
+var _ = Describe("Endpoints with flow logs", func() {
+	It("should report the default deny over NFLOG", func() {
+		renderer := NewRenderer(Config{
+			IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+			IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+			IptablesMarkAccept:          0x8,
+			IptablesMarkPass:            0x10,
+			IptablesMarkScratch0:        0x20,
+			IptablesMarkScratch1:        0x40,
+			IptablesMarkEndpoint:        0xff00,
+			IptablesMarkNonCaliEndpoint: 0x0100,
+			IptablesFilterDenyAction:    "DROP",
+			FlowLogsNFLOGGroup:          42,
+		})
+		epMarkMapper := NewEndpointMarkMapper(0xff00, 0x0100)
+		chains := renderer.WorkloadEndpointToIptablesChains(
+			"cali1234",
+			epMarkMapper,
+			true,
+			singlePolicyGroups([]string{"ai"}),
+			nil,
+			[]string{"prof1"},
+		)
+		Expect(chains[0].Name).To(Equal("cali-tw-cali1234"))
+		nflogAction := NflogAction{Group: 42, Prefix: "deny "}
+		Expect(chains[0].Rules).To(ContainElements(
+			Rule{Match: Match().MarkClear(0x10), Action: nflogAction},
+			Rule{Match: Match(), Action: nflogAction},
+		))
+		for i, r := range chains[0].Rules {
+			if _, ok := r.Action.(NflogAction); ok {
+				Expect(chains[0].Rules[i+1].Action).To(Equal(DropAction{}))
+			}
+		}
+	})
+})
+
 func singlePolicyGroups(names []string) (groups []*PolicyGroup) {
 	for _, n := range names {
 		groups = append(groups, &PolicyGroup{
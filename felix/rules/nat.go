@@ -73,9 +73,32 @@ func (r *DefaultRuleRenderer) makeNATOutgoingRuleIPTables(ipVersion uint8, proto
 	return rule
 }
 
-func (r *DefaultRuleRenderer) NATOutgoingChain(natOutgoingActive bool, ipVersion uint8) *iptables.Chain {
+// EgressSNAT source NATs the NAT-outgoing traffic from the members of an IP set to an address
+// of the host rather than to the default NAT-outgoing address.
+type EgressSNAT struct {
+	IPSetID string
+	ToAddr  string
+}
+
+func (r *DefaultRuleRenderer) NATOutgoingChain(natOutgoingActive bool, egressSNATs []EgressSNAT, ipVersion uint8) *iptables.Chain {
 	var rules []iptables.Rule
 	if natOutgoingActive {
+		ipConf := r.ipSetConfig(ipVersion)
+		for _, e := range egressSNATs {
+			setName := ipConf.NameForMainIPSet(e.IPSetID)
+			if r.Config.NATPortRange.MaxPort > 0 {
+				toAddress := fmt.Sprintf("%s:%d-%d", e.ToAddr, r.Config.NATPortRange.MinPort, r.Config.NATPortRange.MaxPort)
+				for _, protocol := range []string{"tcp", "udp"} {
+					rule := r.MakeNatOutgoingRule(protocol, iptables.SNATAction{ToAddr: toAddress}, ipVersion)
+					rule.Match = rule.Match.SourceIPSet(setName)
+					rules = append(rules, rule)
+				}
+			}
+			rule := r.MakeNatOutgoingRule("", iptables.SNATAction{ToAddr: e.ToAddr}, ipVersion)
+			rule.Match = rule.Match.SourceIPSet(setName)
+			rules = append(rules, rule)
+		}
+
 		var defaultSnatRule iptables.Action = iptables.MasqAction{}
 		if r.Config.NATOutgoingAddress != nil {
 			defaultSnatRule = iptables.SNATAction{ToAddr: r.Config.NATOutgoingAddress.String()}
@@ -88,17 +111,17 @@ func (r *DefaultRuleRenderer) NATOutgoingChain(natOutgoingActive bool, ipVersion
 				toAddress := fmt.Sprintf("%s:%s", r.Config.NATOutgoingAddress.String(), toPorts)
 				portRangeSnatRule = iptables.SNATAction{ToAddr: toAddress}
 			}
-			rules = []iptables.Rule{
+			rules = append(rules,
 				r.MakeNatOutgoingRule("tcp", portRangeSnatRule, ipVersion),
 				r.MakeNatOutgoingRule("tcp", iptables.ReturnAction{}, ipVersion),
 				r.MakeNatOutgoingRule("udp", portRangeSnatRule, ipVersion),
 				r.MakeNatOutgoingRule("udp", iptables.ReturnAction{}, ipVersion),
 				r.MakeNatOutgoingRule("", defaultSnatRule, ipVersion),
-			}
+			)
 		} else {
-			rules = []iptables.Rule{
+			rules = append(rules,
 				r.MakeNatOutgoingRule("", defaultSnatRule, ipVersion),
-			}
+			)
 		}
 	}
 	return &iptables.Chain{
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/ipsets"
 	. "github.com/projectcalico/calico/felix/iptables"
 )
@@ -48,7 +49,7 @@ var _ = Describe("NAT", func() {
 	})
 
 	It("should render rules when active", func() {
-		Expect(renderer.NATOutgoingChain(true, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(true, nil, 4)).To(Equal(&Chain{
 			Name: "cali-nat-outgoing",
 			Rules: []Rule{
 				{
@@ -66,7 +67,7 @@ var _ = Describe("NAT", func() {
 		localConfig.NATOutgoingAddress = net.ParseIP(snatAddress)
 		renderer = NewRenderer(localConfig)
 
-		Expect(renderer.NATOutgoingChain(true, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(true, nil, 4)).To(Equal(&Chain{
 			Name: "cali-nat-outgoing",
 			Rules: []Rule{
 				{
@@ -85,7 +86,7 @@ var _ = Describe("NAT", func() {
 		localConfig.NATPortRange, _ = numorstring.PortFromRange(99, 100)
 		renderer = NewRenderer(localConfig)
 
-		Expect(renderer.NATOutgoingChain(true, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(true, nil, 4)).To(Equal(&Chain{
 			Name: "cali-nat-outgoing",
 			Rules: []Rule{
 				{
@@ -129,7 +130,7 @@ var _ = Describe("NAT", func() {
 		localConfig.IptablesNATOutgoingInterfaceFilter = "cali-123"
 		renderer = NewRenderer(localConfig)
 
-		Expect(renderer.NATOutgoingChain(true, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(true, nil, 4)).To(Equal(&Chain{
 			Name: "cali-nat-outgoing",
 			Rules: []Rule{
 				{
@@ -181,7 +182,7 @@ var _ = Describe("NAT", func() {
 
 		expectedAddress := fmt.Sprintf("%s:%s", snatAddress, "99-100")
 
-		Expect(renderer.NATOutgoingChain(true, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(true, nil, 4)).To(Equal(&Chain{
 			Name: "cali-nat-outgoing",
 			Rules: []Rule{
 				{
@@ -217,8 +218,65 @@ var _ = Describe("NAT", func() {
 			},
 		}))
 	})
+	It("should render egress SNAT rules before the default rule", func() {
+		Expect(renderer.NATOutgoingChain(true, []EgressSNAT{
+			{IPSetID: "snat:a", ToAddr: "192.0.2.1"},
+			{IPSetID: "snat:b", ToAddr: "192.0.2.2"},
+		}, 4)).To(Equal(&Chain{
+			Name: "cali-nat-outgoing",
+			Rules: []Rule{
+				{
+					Action: SNATAction{ToAddr: "192.0.2.1"},
+					Match: Match().
+						SourceIPSet("cali40masq-ipam-pools").
+						NotDestIPSet("cali40all-ipam-pools").
+						SourceIPSet("cali40snat:a"),
+				},
+				{
+					Action: SNATAction{ToAddr: "192.0.2.2"},
+					Match: Match().
+						SourceIPSet("cali40masq-ipam-pools").
+						NotDestIPSet("cali40all-ipam-pools").
+						SourceIPSet("cali40snat:b"),
+				},
+				{
+					Action: MasqAction{},
+					Match: Match().
+						SourceIPSet("cali40masq-ipam-pools").
+						NotDestIPSet("cali40all-ipam-pools"),
+				},
+			},
+		}))
+	})
+	It("should render egress SNAT rules with explicit port range in BPF mode", func() {
+		localConfig := rrConfigNormal
+		localConfig.BPFEnabled = true
+		localConfig.NATPortRange, _ = numorstring.PortFromRange(99, 100)
+		renderer = NewRenderer(localConfig)
+
+		bpfMatch := func() MatchCriteria {
+			return Match().MarkMatchesWithMask(tcdefs.MarkSeenNATOutgoing, tcdefs.MarkSeenNATOutgoingMask)
+		}
+		Expect(renderer.NATOutgoingChain(true, []EgressSNAT{{IPSetID: "snat:a", ToAddr: "192.0.2.1"}}, 4).Rules[:3]).To(Equal([]Rule{
+			{
+				Action: SNATAction{ToAddr: "192.0.2.1:99-100"},
+				Match:  bpfMatch().Protocol("tcp").SourceIPSet("cali40snat:a"),
+			},
+			{
+				Action: SNATAction{ToAddr: "192.0.2.1:99-100"},
+				Match:  bpfMatch().Protocol("udp").SourceIPSet("cali40snat:a"),
+			},
+			{
+				Action: SNATAction{ToAddr: "192.0.2.1"},
+				Match:  bpfMatch().SourceIPSet("cali40snat:a"),
+			},
+		}))
+	})
+	It("should not render egress SNAT rules when inactive", func() {
+		Expect(renderer.NATOutgoingChain(false, []EgressSNAT{{IPSetID: "snat:a", ToAddr: "192.0.2.1"}}, 4).Rules).To(BeEmpty())
+	})
 	It("should render nothing when inactive", func() {
-		Expect(renderer.NATOutgoingChain(false, 4)).To(Equal(&Chain{
+		Expect(renderer.NATOutgoingChain(false, nil, 4)).To(Equal(&Chain{
 			Name:  "cali-nat-outgoing",
 			Rules: nil,
 		}))
@@ -1,4 +1,4 @@
-// Copyright (c) 2016-2023 Tigera, Inc. All rights reserved.
+// Copyright (c) 2016-2024 Tigera, Inc. All rights reserved.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -20,15 +20,21 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/felix/flowlog"
 	"github.com/projectcalico/calico/felix/hashutils"
 	"github.com/projectcalico/calico/felix/ipsets"
 	"github.com/projectcalico/calico/felix/iptables"
+	"github.com/projectcalico/calico/felix/policylog"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 )
 
 // ruleRenderer defined in rules_defs.go.
 
 func (r *DefaultRuleRenderer) PolicyToIptablesChains(policyID *proto.PolicyID, policy *proto.Policy, ipVersion uint8) []*iptables.Chain {
+	if model.PolicyIsStaged(policyID.Name) {
+		return r.stagedPolicyToIptablesChains(policyID, policy, ipVersion)
+	}
 	inbound := iptables.Chain{
 		Name:  PolicyChainName(PolicyInboundPfx, policyID),
 		Rules: r.ProtoRulesToIptablesRules(policy.InboundRules, ipVersion, fmt.Sprintf("Policy %s ingress", policyID.Name)),
@@ -40,6 +46,35 @@ func (r *DefaultRuleRenderer) PolicyToIptablesChains(policyID *proto.PolicyID, p
 	return []*iptables.Chain{&inbound, &outbound}
 }
 
+// stagedPolicyToIptablesChains renders the chains of a staged policy.  The rules match as
+// usual but never set a verdict, so the packet carries on to the next policy whatever the
+// staged policy would have done.  The rule counters record the would-be verdicts and the
+// rules that would deny log the packet.
+func (r *DefaultRuleRenderer) stagedPolicyToIptablesChains(policyID *proto.PolicyID, policy *proto.Policy, ipVersion uint8) []*iptables.Chain {
+	render := func(protoRules []*proto.Rule, comment string) []iptables.Rule {
+		var rules []iptables.Rule
+		for _, protoRule := range protoRules {
+			rules = append(rules, r.StagedProtoRuleToIptablesRules(protoRule, ipVersion)...)
+		}
+		// Unlike an enforced policy, the trailing return rules are kept because their
+		// counters are the would-allow counters.
+		if len(rules) == 0 {
+			rules = append(rules, iptables.Rule{})
+		}
+		rules[0].Comment = append(rules[0].Comment, comment)
+		return rules
+	}
+	inbound := iptables.Chain{
+		Name:  PolicyChainName(PolicyInboundPfx, policyID),
+		Rules: render(policy.InboundRules, fmt.Sprintf("Staged policy %s ingress", policyID.Name)),
+	}
+	outbound := iptables.Chain{
+		Name:  PolicyChainName(PolicyOutboundPfx, policyID),
+		Rules: render(policy.OutboundRules, fmt.Sprintf("Staged policy %s egress", policyID.Name)),
+	}
+	return []*iptables.Chain{&inbound, &outbound}
+}
+
 func (r *DefaultRuleRenderer) ProfileToIptablesChains(profileID *proto.ProfileID, profile *proto.Profile, ipVersion uint8) (inbound, outbound *iptables.Chain) {
 	inbound = &iptables.Chain{
 		Name:  ProfileChainName(ProfileInboundPfx, profileID),
@@ -144,6 +179,20 @@ func FilterRuleToIPVersion(ipVersion uint8, pRule *proto.Rule) *proto.Rule {
 }
 
 func (r *DefaultRuleRenderer) ProtoRuleToIptablesRules(pRule *proto.Rule, ipVersion uint8) []iptables.Rule {
+	return r.protoRuleToIptablesRules(pRule, ipVersion, r.CalculateActions)
+}
+
+// StagedProtoRuleToIptablesRules renders a rule of a staged policy, see
+// CalculateStagedActions.
+func (r *DefaultRuleRenderer) StagedProtoRuleToIptablesRules(pRule *proto.Rule, ipVersion uint8) []iptables.Rule {
+	return r.protoRuleToIptablesRules(pRule, ipVersion, r.CalculateStagedActions)
+}
+
+func (r *DefaultRuleRenderer) protoRuleToIptablesRules(
+	pRule *proto.Rule,
+	ipVersion uint8,
+	calculateActions func(pRule *proto.Rule, ipVersion uint8) (uint32, []iptables.Action),
+) []iptables.Rule {
 
 	ruleCopy := FilterRuleToIPVersion(ipVersion, pRule)
 	if ruleCopy == nil {
@@ -163,12 +212,16 @@ func (r *DefaultRuleRenderer) ProtoRuleToIptablesRules(pRule *proto.Rule, ipVers
 	//     - our datamodel includes named ports, which we render as (IP, port) IP sets, these are
 	//       or-ed with the numeric ports; the "or" operation can't be done in a single rule.
 	//
+	//     - iptables only supports a single ICMP type (and code) in an ICMP match, and, as for
+	//       ports, more than one match would be and-ed together.
+	//
 	// To work around these limitations, where needed, we break the rule into blocks,
 	// each of which implements a part of the match as follows:
 	//
 	//     rule to initialise mark bits
 	//     positive matches on source ports
 	//     positive matches on dest ports
+	//     positive matches on ICMP types
 	//     positive matches on source address
 	//     positive matches on dest address
 	//     negated matches on source address
@@ -225,6 +278,14 @@ func (r *DefaultRuleRenderer) ProtoRuleToIptablesRules(pRule *proto.Rule, ipVers
 		ruleCopy.DstNamedPortIpSetIds = nil
 	}
 
+	// Similarly, if there's more than one ICMP type to match, render a block.
+	if len(ruleCopy.IcmpTypes)+len(ruleCopy.IcmpTypeCodes) > 1 {
+		matchBlockBuilder.AppendICMPMatchBlock(ipVersion, ruleCopy.Protocol, ruleCopy.IcmpTypes, ruleCopy.IcmpTypeCodes)
+		// And remove them from the rule since they're already handled.
+		ruleCopy.IcmpTypes = nil
+		ruleCopy.IcmpTypeCodes = nil
+	}
+
 	// If there's more than one positive source/destination CIDR match, we have to render a block.
 	// Otherwise, if there's exactly one, we'll include it in the main rule below.
 	if len(ruleCopy.SrcNet) > 1 {
@@ -275,7 +336,10 @@ func (r *DefaultRuleRenderer) ProtoRuleToIptablesRules(pRule *proto.Rule, ipVers
 		// success.  Add a match on that bit to the calculated rule.
 		match = match.MarkSingleBitSet(matchBlockBuilder.markAllBlocksPass)
 	}
-	markBit, actions := r.CalculateActions(ruleCopy, ipVersion)
+	if ruleCopy.Action == "log" && ruleCopy.LogRateLimit != "" {
+		match = match.Limit(ruleCopy.LogRateLimit, ruleCopy.LogRateLimitBurst)
+	}
+	markBit, actions := calculateActions(ruleCopy, ipVersion)
 	rs := matchBlockBuilder.Rules
 	if markBit != 0 {
 		// The rule needs to do more than one action. Render a rule that
@@ -353,6 +417,41 @@ func (r *matchBlockBuilder) AppendPortMatchBlock(
 	r.finishPositiveBlock()
 }
 
+func (r *matchBlockBuilder) AppendICMPMatchBlock(
+	ipVersion uint8,
+	protocol *proto.Protocol,
+	icmpTypes []int32,
+	icmpTypeCodes []*proto.IcmpTypeAndCode,
+) {
+	// Write out the initial "reset" rule if this is the first block.
+	r.maybeAppendInitialRule(0)
+	// Figure out which bit to set.  See comment in positiveBlockMarkToSet() for details.
+	markToSet := r.positiveBlockMarkToSet()
+
+	logCxt := log.WithFields(log.Fields{
+		"protocol":      protocol,
+		"icmpTypes":     icmpTypes,
+		"icmpTypeCodes": icmpTypeCodes,
+	})
+	for _, icmpType := range icmpTypes {
+		m := appendProtocolMatch(iptables.Match(), protocol, logCxt)
+		r.Rules = append(r.Rules, iptables.Rule{
+			Match:  appendICMPTypeMatch(m, ipVersion, icmpType),
+			Action: iptables.SetMarkAction{Mark: markToSet},
+		})
+	}
+	for _, icmpTypeCode := range icmpTypeCodes {
+		m := appendProtocolMatch(iptables.Match(), protocol, logCxt)
+		r.Rules = append(r.Rules, iptables.Rule{
+			Match:  appendICMPTypeAndCodeMatch(m, ipVersion, icmpTypeCode),
+			Action: iptables.SetMarkAction{Mark: markToSet},
+		})
+	}
+
+	// Append the end-of-block rules.
+	r.finishPositiveBlock()
+}
+
 func (r *matchBlockBuilder) AppendCIDRMatchBlock(cidrs []string, srcOrDst srcOrDst) {
 	// Write out the initial "reset" rule if this is the first block.
 	r.maybeAppendInitialRule(0)
@@ -526,6 +625,7 @@ func (r *DefaultRuleRenderer) CalculateActions(pRule *proto.Rule, ipVersion uint
 		// Allow needs to set the accept mark, and then return to the calling chain for
 		// further processing.
 		mark = r.IptablesMarkAccept
+		actions = r.appendFlowLogAction(actions, flowlog.ActionAllow, pRule.RuleId)
 		actions = append(actions, iptables.ReturnAction{})
 	case "next-tier", "pass":
 		// pass (called next-tier in the API for historical reasons) needs to set the pass
@@ -534,18 +634,65 @@ func (r *DefaultRuleRenderer) CalculateActions(pRule *proto.Rule, ipVersion uint
 		actions = append(actions, iptables.ReturnAction{})
 	case "deny":
 		// Deny maps to DROP/REJECT.
+		actions = r.appendFlowLogAction(actions, flowlog.ActionDeny, pRule.RuleId)
 		actions = append(actions, r.IptablesFilterDenyAction())
 	case "log":
 		// This rule should log.
-		actions = append(actions, iptables.LogAction{
-			Prefix: r.IptablesLogPrefix,
-		})
+		actions = append(actions, r.logAction(pRule, r.IptablesLogPrefix))
 	default:
 		log.WithField("action", pRule.Action).Panic("Unknown rule action")
 	}
 	return
 }
 
+// appendFlowLogAction appends the action that reports the verdict of a rule to Felix for flow
+// logs, if they are enabled.
+func (r *DefaultRuleRenderer) appendFlowLogAction(actions []iptables.Action, action flowlog.Action, ruleID string) []iptables.Action {
+	if r.FlowLogsNFLOGGroup == 0 {
+		return actions
+	}
+	return append(actions, iptables.NflogAction{
+		Group:  uint16(r.FlowLogsNFLOGGroup),
+		Prefix: flowlog.NFLOGPrefix(action, ruleID),
+	})
+}
+
+// CalculateStagedActions calculates the actions of a rule of a staged policy.  None of them
+// set a verdict: the rules that would allow or pass return to the calling chain, as if the
+// policy did not match, and the rules that would deny log the packet first.
+func (r *DefaultRuleRenderer) CalculateStagedActions(pRule *proto.Rule, ipVersion uint8) (mark uint32, actions []iptables.Action) {
+	actions = []iptables.Action{}
+
+	switch pRule.Action {
+	case "", "allow", "next-tier", "pass":
+		actions = append(actions, iptables.ReturnAction{})
+	case "deny":
+		actions = append(actions,
+			iptables.LogAction{Prefix: r.IptablesLogPrefix + "-staged-deny"},
+			iptables.ReturnAction{},
+		)
+	case "log":
+		actions = append(actions, r.logAction(pRule, r.IptablesLogPrefix+"-staged"))
+	default:
+		log.WithField("action", pRule.Action).Panic("Unknown rule action")
+	}
+	return
+}
+
+// logAction returns the action of a Log rule.  The kernel log is written by the LOG target;
+// the other targets are written by Felix, which receives the packets over NFLOG along with
+// the target in the prefix.
+func (r *DefaultRuleRenderer) logAction(pRule *proto.Rule, prefix string) iptables.Action {
+	target := policylog.Target(pRule.LogTarget)
+	if target == "" || target == policylog.TargetKernel {
+		return iptables.LogAction{Prefix: prefix}
+	}
+	return iptables.NflogAction{
+		Group:  uint16(r.PolicyLogNFLOGGroup),
+		Prefix: policylog.NFLOGPrefix(target, pRule.LogSyslogFacility, prefix),
+	}
+}
+
 func appendProtocolMatch(match iptables.MatchCriteria, protocol *proto.Protocol, logCxt *log.Entry) iptables.MatchCriteria {
 	if protocol == nil {
 		return match
@@ -563,6 +710,24 @@ func appendProtocolMatch(match iptables.MatchCriteria, protocol *proto.Protocol,
 	return match
 }
 
+func appendICMPTypeMatch(match iptables.MatchCriteria, ipVersion uint8, icmpType int32) iptables.MatchCriteria {
+	if ipVersion == 4 {
+		return match.ICMPType(uint8(icmpType))
+	}
+	return match.ICMPV6Type(uint8(icmpType))
+}
+
+func appendICMPTypeAndCodeMatch(
+	match iptables.MatchCriteria,
+	ipVersion uint8,
+	icmpTypeCode *proto.IcmpTypeAndCode,
+) iptables.MatchCriteria {
+	if ipVersion == 4 {
+		return match.ICMPTypeAndCode(uint8(icmpTypeCode.Type), uint8(icmpTypeCode.Code))
+	}
+	return match.ICMPV6TypeAndCode(uint8(icmpTypeCode.Type), uint8(icmpTypeCode.Code))
+}
+
 func (r *DefaultRuleRenderer) CalculateRuleMatch(pRule *proto.Rule, ipVersion uint8) iptables.MatchCriteria {
 	match := iptables.Match()
 
@@ -645,6 +810,15 @@ func (r *DefaultRuleRenderer) CalculateRuleMatch(pRule *proto.Rule, ipVersion ui
 		}).Debug("Adding dst IP+port set match")
 	}
 
+	for _, ipsetID := range pRule.DstDomainIpSetIds {
+		ipsetName := nameForIPSet(ipsetID)
+		match = match.DestIPSet(ipsetName)
+		logCxt.WithFields(log.Fields{
+			"ipsetID":   ipsetID,
+			"ipSetName": ipsetName,
+		}).Debug("Adding dst domain IP set match")
+	}
+
 	if len(pRule.DstPorts) > 0 {
 		logCxt.WithFields(log.Fields{
 			"ports": pRule.SrcPorts,
@@ -687,6 +861,23 @@ func (r *DefaultRuleRenderer) CalculateRuleMatch(pRule *proto.Rule, ipVersion ui
 		}
 	}
 
+	if len(pRule.IcmpTypes)+len(pRule.IcmpTypeCodes) > 1 {
+		log.WithField("rule", pRule).Panic("CalculateRuleMatch() passed more than one ICMP type.")
+	}
+	for _, icmpType := range pRule.IcmpTypes {
+		logCxt.WithField("icmpType", icmpType).Debug("Adding ICMP type-only match.")
+		match = appendICMPTypeMatch(match, ipVersion, icmpType)
+	}
+	for _, icmpTypeCode := range pRule.IcmpTypeCodes {
+		logCxt.WithField("icmpTypeCode", icmpTypeCode).Debug("Adding ICMP type/code match.")
+		match = appendICMPTypeAndCodeMatch(match, ipVersion, icmpTypeCode)
+	}
+
+	if dscp, ok := pRule.Dscp.(*proto.Rule_DscpValue); ok {
+		logCxt.WithField("dscp", dscp.DscpValue).Debug("Adding DSCP match.")
+		match = match.DSCP(uint8(dscp.DscpValue))
+	}
+
 	// Now, the negated versions.
 
 	if pRule.NotProtocol != nil {
@@ -789,6 +980,11 @@ func (r *DefaultRuleRenderer) CalculateRuleMatch(pRule *proto.Rule, ipVersion ui
 			match = match.NotICMPV6Type(uint8(icmp.NotIcmpType))
 		}
 	}
+
+	if dscp, ok := pRule.NotDscp.(*proto.Rule_NotDscpValue); ok {
+		logCxt.WithField("dscp", dscp.NotDscpValue).Debug("Adding !DSCP match.")
+		match = match.NotDSCP(uint8(dscp.NotDscpValue))
+	}
 	return match
 }
 
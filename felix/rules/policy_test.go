@@ -70,6 +70,12 @@ var ruleTestData = []TableEntry{
 	Entry("ICMP with code", 6,
 		proto.Rule{Icmp: &proto.Rule_IcmpTypeCode{IcmpTypeCode: &proto.IcmpTypeAndCode{Type: 10, Code: 12}}},
 		"-m icmp6 --icmpv6-type 10/12"),
+	Entry("DSCP", 4,
+		proto.Rule{Dscp: &proto.Rule_DscpValue{DscpValue: 0}},
+		"-m dscp --dscp 0"),
+	Entry("DSCP", 6,
+		proto.Rule{Dscp: &proto.Rule_DscpValue{DscpValue: 46}},
+		"-m dscp --dscp 46"),
 
 	Entry("Dest net", 4,
 		proto.Rule{DstNet: []string{"10.0.0.0/16"}},
@@ -80,6 +86,9 @@ var ruleTestData = []TableEntry{
 	Entry("Dest IP sets", 4,
 		proto.Rule{DstIpSetIds: []string{"ipsetid1", "ipsetid2"}},
 		"-m set --match-set cali40ipsetid1 dst -m set --match-set cali40ipsetid2 dst"),
+	Entry("Dest domain IP set", 4,
+		proto.Rule{DstDomainIpSetIds: []string{"d:domainset"}},
+		"-m set --match-set cali40d:domainset dst"),
 	Entry("Dest ports", 4,
 		proto.Rule{DstPorts: []*proto.PortRange{{First: 10, Last: 12}}},
 		"-m multiport --destination-ports 10:12"),
@@ -146,6 +155,12 @@ var ruleTestData = []TableEntry{
 	Entry("ICMP with code", 6,
 		proto.Rule{NotIcmp: &proto.Rule_NotIcmpTypeCode{NotIcmpTypeCode: &proto.IcmpTypeAndCode{Type: 10, Code: 12}}},
 		"-m icmp6 ! --icmpv6-type 10/12"),
+	Entry("DSCP", 4,
+		proto.Rule{NotDscp: &proto.Rule_NotDscpValue{NotDscpValue: 0}},
+		"-m dscp ! --dscp 0"),
+	Entry("DSCP", 6,
+		proto.Rule{NotDscp: &proto.Rule_NotDscpValue{NotDscpValue: 46}},
+		"-m dscp ! --dscp 46"),
 
 	Entry("Dest net", 4,
 		proto.Rule{NotDstNet: []string{"10.0.0.0/16"}},
@@ -274,6 +289,54 @@ var _ = Describe("Protobuf rule to iptables rule conversion", func() {
 		ruleTestData...,
 	)
 
+	It("should rate limit log rules", func() {
+		renderer := NewRenderer(rrConfigNormal)
+		rules := renderer.ProtoRuleToIptablesRules(&proto.Rule{
+			Action:            "log",
+			LogRateLimit:      "10/minute",
+			LogRateLimitBurst: 3,
+		}, 4)
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Match.Render()).To(Equal("-m limit --limit 10/minute --limit-burst 3"))
+		Expect(rules[0].Action).To(Equal(iptables.LogAction{Prefix: "calico-packet"}))
+	})
+
+	It("should pass log rules with other targets to Felix over NFLOG", func() {
+		rrConfigNflog := rrConfigNormal
+		rrConfigNflog.PolicyLogNFLOGGroup = 40
+		renderer := NewRenderer(rrConfigNflog)
+		rules := renderer.ProtoRuleToIptablesRules(&proto.Rule{
+			Action:            "log",
+			LogTarget:         "Syslog",
+			LogSyslogFacility: "local2",
+		}, 4)
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Match.Render()).To(Equal(""))
+		Expect(rules[0].Action).To(Equal(iptables.NflogAction{Group: 40, Prefix: "Syslog.local2 calico-packet"}))
+	})
+
+	It("should report the verdicts of allow and deny rules over NFLOG for flow logs", func() {
+		rrConfigFlowLogs := rrConfigNormal
+		rrConfigFlowLogs.FlowLogsNFLOGGroup = 42
+		renderer := NewRenderer(rrConfigFlowLogs)
+
+		rules := renderer.ProtoRuleToIptablesRules(&proto.Rule{Action: "allow", RuleId: "rule-allow"}, 4)
+		Expect(rules).To(HaveLen(3))
+		Expect(rules[0].Action).To(Equal(iptables.SetMarkAction{Mark: 0x80}))
+		Expect(rules[1].Match.Render()).To(Equal("-m mark --mark 0x80/0x80"))
+		Expect(rules[1].Action).To(Equal(iptables.NflogAction{Group: 42, Prefix: "allow rule-allow"}))
+		Expect(rules[2].Action).To(Equal(iptables.ReturnAction{}))
+
+		rules = renderer.ProtoRuleToIptablesRules(&proto.Rule{Action: "deny", RuleId: "rule-deny"}, 4)
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].Action).To(Equal(iptables.NflogAction{Group: 42, Prefix: "deny rule-deny"}))
+		Expect(rules[1].Action).To(Equal(iptables.DropAction{}))
+
+		rules = renderer.ProtoRuleToIptablesRules(&proto.Rule{Action: "pass", RuleId: "rule-pass"}, 4)
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[1].Action).To(Equal(iptables.ReturnAction{}))
+	})
+
 	DescribeTable(
 		"Deny (DROP) rules should be correctly rendered",
 		func(ipVer int, in proto.Rule, expMatch string) {
@@ -836,6 +899,73 @@ var _ = Describe("Protobuf rule to iptables rule conversion", func() {
 		),
 	)
 
+	icmpv6 := &proto.Protocol{NumberOrName: &proto.Protocol_Name{Name: "ipv6-icmp"}}
+	DescribeTable(
+		"Multiple ICMP type tests",
+		func(pRule proto.Rule, expected []string) {
+			renderer := NewRenderer(rrConfigNormal)
+			iptRules := renderer.ProtoRuleToIptablesRules(&pRule, 6)
+			rendered := []string{}
+			for _, ir := range iptRules {
+				s := ir.RenderAppend("test", "", &environment.Features{})
+				rendered = append(rendered, s)
+			}
+			Expect(rendered).To(Equal(expected))
+		},
+		Entry("single type rendered as single rule",
+			proto.Rule{
+				Protocol:  icmpv6,
+				IcmpTypes: []int32{135},
+			},
+			[]string{
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 135 --jump MARK --set-mark 0x80/0x80",
+				returnRule,
+			},
+		),
+		Entry("single type and code rendered as single rule",
+			proto.Rule{
+				Protocol:      icmpv6,
+				IcmpTypeCodes: []*proto.IcmpTypeAndCode{{Type: 1, Code: 3}},
+			},
+			[]string{
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 1/3 --jump MARK --set-mark 0x80/0x80",
+				returnRule,
+			},
+		),
+		Entry("multiple types and codes need a block",
+			proto.Rule{
+				Protocol:      icmpv6,
+				IcmpTypes:     []int32{135, 136},
+				IcmpTypeCodes: []*proto.IcmpTypeAndCode{{Type: 1, Code: 3}},
+			},
+			[]string{
+				clearBothMarksRule,
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 135 --jump MARK --set-mark 0x200/0x200",
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 136 --jump MARK --set-mark 0x200/0x200",
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 1/3 --jump MARK --set-mark 0x200/0x200",
+				"-A test -p ipv6-icmp -m mark --mark 0x200/0x200 --jump MARK --set-mark 0x80/0x80",
+				returnRule,
+			},
+		),
+		Entry("multiple types and source CIDRs need two blocks",
+			proto.Rule{
+				Protocol:  icmpv6,
+				SrcNet:    []string{"fd00::1/128", "fd00::2/128"},
+				IcmpTypes: []int32{135, 136},
+			},
+			[]string{
+				clearBothMarksRule,
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 135 --jump MARK --set-mark 0x200/0x200",
+				"-A test -p ipv6-icmp -m icmp6 --icmpv6-type 136 --jump MARK --set-mark 0x200/0x200",
+				"-A test --source fd00::1/128 --jump MARK --set-mark 0x400/0x400",
+				"-A test --source fd00::2/128 --jump MARK --set-mark 0x400/0x400",
+				allBlocksPassAndEqThisBlockPassRule,
+				"-A test -p ipv6-icmp -m mark --mark 0x200/0x200 --jump MARK --set-mark 0x80/0x80",
+				returnRule,
+			},
+		),
+	)
+
 	var renderer *DefaultRuleRenderer
 	BeforeEach(func() {
 		renderer = NewRenderer(rrConfigNormal).(*DefaultRuleRenderer)
@@ -1117,6 +1247,38 @@ var _ = Describe("rule metadata tests", func() {
 			},
 		))
 	})
+	It("should render staged policies without verdicts", func() {
+		renderer := NewRenderer(rrConfigNormal)
+		polID := &proto.PolicyID{Name: "staged:pol1"}
+		chains := renderer.PolicyToIptablesChains(
+			polID,
+			&proto.Policy{
+				InboundRules: []*proto.Rule{{Action: "deny"}, {Action: "log"}, {Action: "allow"}},
+			},
+			4,
+		)
+		Expect(chains).To(ConsistOf(
+			&iptables.Chain{
+				Name: PolicyChainName(PolicyInboundPfx, polID),
+				Rules: []iptables.Rule{
+					{
+						Match:   iptables.Match(),
+						Action:  iptables.LogAction{Prefix: "calico-packet-staged-deny"},
+						Comment: []string{"Staged policy staged:pol1 ingress"},
+					},
+					{Match: iptables.Match(), Action: iptables.ReturnAction{}},
+					{Match: iptables.Match(), Action: iptables.LogAction{Prefix: "calico-packet-staged"}},
+					{Match: iptables.Match(), Action: iptables.ReturnAction{}},
+				},
+			},
+			&iptables.Chain{
+				Name: PolicyChainName(PolicyOutboundPfx, polID),
+				Rules: []iptables.Rule{
+					{Comment: []string{"Staged policy staged:pol1 egress"}},
+				},
+			},
+		))
+	})
 	It("should include a chain name comment", func() {
 		renderer := NewRenderer(rrConfigNormal)
 		inbound, outbound := renderer.ProfileToIptablesChains(
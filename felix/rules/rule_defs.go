@@ -65,9 +65,19 @@ const (
 	IPSetIDAllVXLANSourceNets = "all-vxlan-net"
 	IPSetIDThisHostIPs        = "this-host"
 
+	// IPSetIDEgressProxy is the IP set of the workloads whose egress is redirected to the
+	// egress proxy, it is calculated by the calculation graph.
+	IPSetIDEgressProxy = "egress-proxy"
+	// IPSetIDL7Proxy is the IP set of the workloads whose inbound connections are redirected
+	// to the L7 proxy, it is calculated by the calculation graph.
+	IPSetIDL7Proxy = "l7-proxy"
+
 	ChainFIPDnat = ChainNamePrefix + "fip-dnat"
 	ChainFIPSnat = ChainNamePrefix + "fip-snat"
 
+	ChainEgressProxy = ChainNamePrefix + "egress-proxy"
+	ChainL7Proxy     = ChainNamePrefix + "l7-proxy"
+
 	ChainCIDRBlock = ChainNamePrefix + "cidr-block"
 
 	PolicyInboundPfx   PolicyChainNamePrefix  = ChainNamePrefix + "pi-"
@@ -186,6 +196,7 @@ type RuleRenderer interface {
 	StaticNATPostroutingChains(ipVersion uint8) []*iptables.Chain
 	StaticRawTableChains(ipVersion uint8) []*iptables.Chain
 	StaticBPFModeRawChains(ipVersion uint8, wgEncryptHost, disableConntrack bool) []*iptables.Chain
+	StaticBPFModeL7ProxyChains(ipVersion uint8) []*iptables.Chain
 	StaticMangleTableChains(ipVersion uint8) []*iptables.Chain
 	StaticFilterForwardAppendRules() []iptables.Rule
 
@@ -235,9 +246,10 @@ type RuleRenderer interface {
 	PolicyToIptablesChains(policyID *proto.PolicyID, policy *proto.Policy, ipVersion uint8) []*iptables.Chain
 	ProfileToIptablesChains(profileID *proto.ProfileID, policy *proto.Profile, ipVersion uint8) (inbound, outbound *iptables.Chain)
 	ProtoRuleToIptablesRules(pRule *proto.Rule, ipVersion uint8) []iptables.Rule
+	StagedProtoRuleToIptablesRules(pRule *proto.Rule, ipVersion uint8) []iptables.Rule
 
 	MakeNatOutgoingRule(protocol string, action iptables.Action, ipVersion uint8) iptables.Rule
-	NATOutgoingChain(active bool, ipVersion uint8) *iptables.Chain
+	NATOutgoingChain(active bool, egressSNATs []EgressSNAT, ipVersion uint8) *iptables.Chain
 
 	DNATsToIptablesChains(dnats map[string]string) []*iptables.Chain
 	SNATsToIptablesChains(snats map[string]string) []*iptables.Chain
@@ -294,6 +306,17 @@ type Config struct {
 	OpenStackMetadataPort        uint16
 	OpenStackSpecialCasesEnabled bool
 
+	EgressProxyIP               net.IP
+	EgressProxyPort             uint16
+	EgressProxyDestinationPorts []numorstring.Port
+	EgressProxyExcludeCIDRs     []string
+
+	// L7ProxyMark is the mark bit of the connections that are delivered to the L7 proxy, it is
+	// only allocated in BPF mode when L7ProxySelector is set.
+	L7ProxyMark             uint32
+	L7ProxyPort             uint16
+	L7ProxyDestinationPorts []numorstring.Port
+
 	VXLANEnabled   bool
 	VXLANEnabledV6 bool
 	VXLANPort      int
@@ -322,6 +345,10 @@ type Config struct {
 	RouteSource                 string
 
 	IptablesLogPrefix         string
+	PolicyLogNFLOGGroup       int
+	DNSTrustedServers         []string
+	DNSPolicyNFLOGGroup       int
+	FlowLogsNFLOGGroup        int
 	EndpointToHostAction      string
 	IptablesFilterAllowAction string
 	IptablesMangleAllowAction string
@@ -329,6 +356,7 @@ type Config struct {
 
 	FailsafeInboundHostPorts  []config.ProtoPort
 	FailsafeOutboundHostPorts []config.ProtoPort
+	FailsafeNDPEnabled        bool
 
 	DisableConntrackInvalid bool
 
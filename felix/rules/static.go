@@ -27,7 +27,7 @@ import (
 )
 
 func (r *DefaultRuleRenderer) StaticFilterTableChains(ipVersion uint8) (chains []*Chain) {
-	chains = append(chains, r.StaticFilterForwardChains()...)
+	chains = append(chains, r.StaticFilterForwardChains(ipVersion)...)
 	chains = append(chains, r.StaticFilterInputChains(ipVersion)...)
 	chains = append(chains, r.StaticFilterOutputChains(ipVersion)...)
 	return
@@ -471,8 +471,32 @@ func (r *DefaultRuleRenderer) filterWorkloadToHostChain(ipVersion uint8) *Chain
 	}
 }
 
-func (r *DefaultRuleRenderer) failsafeInChain(table string, ipVersion uint8) *Chain {
+// ndpFailsafeRules returns the rules that accept the essential neighbor discovery messages,
+// irrespective of the host endpoint policy, so that a default-deny policy on a host endpoint
+// can't break the host's IPv6 connectivity:
+//
+// - 133: router solicitation.
+// - 134: router advertisement.
+// - 135: neighbor solicitation.
+// - 136: neighbor advertisement.
+func (r *DefaultRuleRenderer) ndpFailsafeRules(ipVersion uint8) []Rule {
 	rules := []Rule{}
+	if ipVersion != 6 || !r.Config.FailsafeNDPEnabled {
+		return rules
+	}
+	for _, icmpType := range []uint8{133, 134, 135, 136} {
+		rules = append(rules, Rule{
+			Match: Match().
+				ProtocolNum(ProtoICMPv6).
+				ICMPV6Type(icmpType),
+			Action: AcceptAction{},
+		})
+	}
+	return rules
+}
+
+func (r *DefaultRuleRenderer) failsafeInChain(table string, ipVersion uint8) *Chain {
+	rules := r.ndpFailsafeRules(ipVersion)
 
 	for _, protoPort := range r.Config.FailsafeInboundHostPorts {
 		rule := Rule{
@@ -539,7 +563,7 @@ func (r *DefaultRuleRenderer) failsafeInChain(table string, ipVersion uint8) *Ch
 }
 
 func (r *DefaultRuleRenderer) failsafeOutChain(table string, ipVersion uint8) *Chain {
-	rules := []Rule{}
+	rules := r.ndpFailsafeRules(ipVersion)
 
 	for _, protoPort := range r.Config.FailsafeOutboundHostPorts {
 		rule := Rule{
@@ -605,7 +629,7 @@ func (r *DefaultRuleRenderer) failsafeOutChain(table string, ipVersion uint8) *C
 	}
 }
 
-func (r *DefaultRuleRenderer) StaticFilterForwardChains() []*Chain {
+func (r *DefaultRuleRenderer) StaticFilterForwardChains(ipVersion uint8) []*Chain {
 	rules := []Rule{}
 
 	// Rules for filter forward chains dispatches the packet to our dispatch chains if it is going
@@ -636,6 +660,7 @@ func (r *DefaultRuleRenderer) StaticFilterForwardChains() []*Chain {
 	for _, prefix := range r.WorkloadIfacePrefixes {
 		log.WithField("ifacePrefix", prefix).Debug("Adding workload match rules")
 		ifaceMatch := prefix + "+"
+		rules = append(rules, r.dnsSnoopRules(ipVersion, ifaceMatch)...)
 		rules = append(rules,
 			Rule{
 				Match:  Match().InInterface(ifaceMatch),
@@ -669,6 +694,30 @@ func (r *DefaultRuleRenderer) StaticFilterForwardChains() []*Chain {
 	}}
 }
 
+// dnsSnoopRules pass the DNS responses from the trusted DNS servers to workloads to Felix, which
+// learns the addresses of the domain names in policy rules from them.  The conntrack match
+// checks the original destination of the query, so that it covers queries to a DNS service IP
+// and ignores responses that were not asked for.
+func (r *DefaultRuleRenderer) dnsSnoopRules(ipVersion uint8, ifaceMatch string) []Rule {
+	var rules []Rule
+	for _, cidr := range r.DNSTrustedServers {
+		_, ipNet, err := cnet.ParseCIDR(cidr)
+		if err != nil || ipNet.Version() != int(ipVersion) {
+			continue
+		}
+		rules = append(rules, Rule{
+			Match: Match().OutInterface(ifaceMatch).
+				Protocol("udp").
+				ConntrackReplyFrom(ipNet.String(), 53),
+			Action: NflogAction{
+				Group:  uint16(r.DNSPolicyNFLOGGroup),
+				Prefix: "DNS",
+			},
+		})
+	}
+	return rules
+}
+
 // StaticFilterForwardAppendRules returns rules which should be statically appended to the end of the filter
 // table's forward chain.
 func (r *DefaultRuleRenderer) StaticFilterForwardAppendRules() []Rule {
@@ -880,14 +929,82 @@ func (r *DefaultRuleRenderer) StaticNATPreroutingChains(ipVersion uint8) []*Chai
 		})
 	}
 
+	var egressProxyChain *Chain
+	if r.egressProxyEnabled(ipVersion) {
+		rules = append(rules, Rule{
+			Match: Match().
+				Protocol("tcp").
+				SourceIPSet(r.ipSetConfig(ipVersion).NameForMainIPSet(IPSetIDEgressProxy)),
+			Action: JumpAction{Target: ChainEgressProxy},
+		})
+		egressProxyChain = r.egressProxyChain(ipVersion)
+	}
+
 	chains := []*Chain{{
 		Name:  ChainNATPrerouting,
 		Rules: rules,
 	}}
+	if egressProxyChain != nil {
+		chains = append(chains, egressProxyChain)
+	}
 
 	return chains
 }
 
+func (r *DefaultRuleRenderer) egressProxyEnabled(ipVersion uint8) bool {
+	if r.EgressProxyIP == nil || r.BPFEnabled {
+		return false
+	}
+	return (r.EgressProxyIP.To4() != nil) == (ipVersion == 4)
+}
+
+// egressProxyChain redirects the HTTP(S) connections from the workloads in the egress proxy IP
+// set to the egress proxy, unless they are to workloads, to the host or to one of the excluded
+// CIDRs.
+func (r *DefaultRuleRenderer) egressProxyChain(ipVersion uint8) *Chain {
+	ipConf := r.ipSetConfig(ipVersion)
+	rules := []Rule{
+		{
+			Match:  Match().DestIPSet(ipConf.NameForMainIPSet(IPSetIDNATOutgoingAllPools)),
+			Action: ReturnAction{},
+		},
+		{
+			Match:  Match().DestAddrType(AddrTypeLocal),
+			Action: ReturnAction{},
+		},
+	}
+	for _, cidr := range r.EgressProxyExcludeCIDRs {
+		_, ipNet, err := cnet.ParseCIDR(cidr)
+		if err != nil || ipNet.Version() != int(ipVersion) {
+			continue
+		}
+		rules = append(rules, Rule{
+			Match:  Match().DestNet(ipNet.String()),
+			Action: ReturnAction{},
+		})
+	}
+
+	destAddr := r.EgressProxyIP.String()
+	if ipVersion == 6 {
+		destAddr = "[" + destAddr + "]"
+	}
+	var portRanges []*proto.PortRange
+	for _, p := range r.EgressProxyDestinationPorts {
+		portRanges = append(portRanges, &proto.PortRange{First: int32(p.MinPort), Last: int32(p.MaxPort)})
+	}
+	for _, portSplit := range SplitPortList(portRanges) {
+		rules = append(rules, Rule{
+			Match:  Match().Protocol("tcp").DestPortRanges(portSplit),
+			Action: DNATAction{DestAddr: destAddr, DestPort: r.EgressProxyPort},
+		})
+	}
+
+	return &Chain{
+		Name:  ChainEgressProxy,
+		Rules: rules,
+	}
+}
+
 func (r *DefaultRuleRenderer) StaticNATPostroutingChains(ipVersion uint8) []*Chain {
 	rules := []Rule{
 		{
@@ -1258,6 +1375,49 @@ func (r *DefaultRuleRenderer) StaticBPFModeRawChains(ipVersion uint8,
 	return chains
 }
 
+// StaticBPFModeL7ProxyChains returns the mangle PREROUTING chain that hands the TCP
+// connections to the workloads in the L7 proxy IP set to the L7 proxy.  The BPF programs send
+// those connections through the host IP stack instead of forwarding them.  The later packets of
+// the intercepted connections, and the replies to the connections that the proxy makes to the
+// workloads on behalf of the original clients, belong to the proxy's transparent sockets; they
+// are marked so that a routing rule delivers them locally.
+func (r *DefaultRuleRenderer) StaticBPFModeL7ProxyChains(ipVersion uint8) []*Chain {
+	if r.L7ProxyMark == 0 {
+		return nil
+	}
+
+	rules := []Rule{
+		{
+			Match:  Match().Protocol("tcp").TransparentSocket(),
+			Action: SetMarkAction{Mark: r.L7ProxyMark},
+		},
+		{
+			Match:  Match().MarkSingleBitSet(r.L7ProxyMark),
+			Action: ReturnAction{},
+		},
+	}
+
+	var portRanges []*proto.PortRange
+	for _, p := range r.L7ProxyDestinationPorts {
+		portRanges = append(portRanges, &proto.PortRange{First: int32(p.MinPort), Last: int32(p.MaxPort)})
+	}
+	ipSetName := r.ipSetConfig(ipVersion).NameForMainIPSet(IPSetIDL7Proxy)
+	for _, portSplit := range SplitPortList(portRanges) {
+		rules = append(rules, Rule{
+			Match: Match().
+				Protocol("tcp").
+				DestIPSet(ipSetName).
+				DestPortRanges(portSplit),
+			Action: TProxyAction{Port: r.L7ProxyPort, Mark: r.L7ProxyMark, Mask: r.L7ProxyMark},
+		})
+	}
+
+	return []*Chain{{
+		Name:  ChainL7Proxy,
+		Rules: rules,
+	}}
+}
+
 func (r *DefaultRuleRenderer) StaticRawPreroutingChain(ipVersion uint8) *Chain {
 	rules := []Rule{}
 
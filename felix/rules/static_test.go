@@ -1251,6 +1251,185 @@ var _ = Describe("Static", func() {
 		})
 	})
 
+	Describe("with an egress proxy", func() {
+		BeforeEach(func() {
+			conf = Config{
+				WorkloadIfacePrefixes:       []string{"cali"},
+				IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+				IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+				EgressProxyIP:               net.ParseIP("10.0.0.1"),
+				EgressProxyPort:             3128,
+				EgressProxyDestinationPorts: []numorstring.Port{numorstring.SinglePort(80), numorstring.SinglePort(443)},
+				EgressProxyExcludeCIDRs:     []string{"10.96.0.0/12", "fd00::/64"},
+				IptablesMarkAccept:          0x10,
+				IptablesMarkPass:            0x20,
+				IptablesMarkScratch0:        0x40,
+				IptablesMarkScratch1:        0x80,
+				IptablesMarkEndpoint:        0xff00,
+				IptablesMarkNonCaliEndpoint: 0x100,
+			}
+		})
+
+		It("IPv4: should redirect the egress of the selected workloads to the proxy", func() {
+			Expect(rr.StaticNATPreroutingChains(4)).To(Equal([]*Chain{
+				{
+					Name: "cali-PREROUTING",
+					Rules: []Rule{
+						{Action: JumpAction{Target: "cali-fip-dnat"}},
+						{
+							Match:  Match().Protocol("tcp").SourceIPSet("cali40egress-proxy"),
+							Action: JumpAction{Target: "cali-egress-proxy"},
+						},
+					},
+				},
+				{
+					Name: "cali-egress-proxy",
+					Rules: []Rule{
+						{Match: Match().DestIPSet("cali40all-ipam-pools"), Action: ReturnAction{}},
+						{Match: Match().DestAddrType(AddrTypeLocal), Action: ReturnAction{}},
+						{Match: Match().DestNet("10.96.0.0/12"), Action: ReturnAction{}},
+						{
+							Match:  Match().Protocol("tcp").DestPortRanges([]*proto.PortRange{{First: 80, Last: 80}, {First: 443, Last: 443}}),
+							Action: DNATAction{DestAddr: "10.0.0.1", DestPort: 3128},
+						},
+					},
+				},
+			}))
+		})
+
+		It("IPv6: should not redirect to an IPv4 proxy", func() {
+			Expect(rr.StaticNATPreroutingChains(6)).To(Equal([]*Chain{
+				{
+					Name: "cali-PREROUTING",
+					Rules: []Rule{
+						{Action: JumpAction{Target: "cali-fip-dnat"}},
+					},
+				},
+			}))
+		})
+
+		It("should not redirect in BPF mode", func() {
+			conf.BPFEnabled = true
+			rr = NewRenderer(conf).(*DefaultRuleRenderer)
+			Expect(findChain(rr.StaticNATPreroutingChains(4), "cali-egress-proxy")).To(BeNil())
+		})
+	})
+
+	Describe("with an L7 proxy", func() {
+		BeforeEach(func() {
+			conf = Config{
+				WorkloadIfacePrefixes:       []string{"cali"},
+				IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+				IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+				BPFEnabled:                  true,
+				L7ProxyMark:                 0x1000,
+				L7ProxyPort:                 16001,
+				L7ProxyDestinationPorts:     []numorstring.Port{numorstring.SinglePort(80), {MinPort: 8080, MaxPort: 8090}},
+				IptablesMarkAccept:          0x10,
+				IptablesMarkPass:            0x20,
+				IptablesMarkScratch0:        0x40,
+				IptablesMarkScratch1:        0x80,
+				IptablesMarkEndpoint:        0xff00,
+				IptablesMarkNonCaliEndpoint: 0x100,
+			}
+		})
+
+		It("should hand the connections to the selected workloads to the proxy", func() {
+			Expect(rr.StaticBPFModeL7ProxyChains(6)).To(Equal([]*Chain{
+				{
+					Name: "cali-l7-proxy",
+					Rules: []Rule{
+						{Match: Match().Protocol("tcp").TransparentSocket(), Action: SetMarkAction{Mark: 0x1000}},
+						{Match: Match().MarkSingleBitSet(0x1000), Action: ReturnAction{}},
+						{
+							Match: Match().Protocol("tcp").DestIPSet("cali60l7-proxy").
+								DestPortRanges([]*proto.PortRange{{First: 80, Last: 80}, {First: 8080, Last: 8090}}),
+							Action: TProxyAction{Port: 16001, Mark: 0x1000, Mask: 0x1000},
+						},
+					},
+				},
+			}))
+		})
+
+		It("should render nothing without a mark", func() {
+			conf.L7ProxyMark = 0
+			rr = NewRenderer(conf).(*DefaultRuleRenderer)
+			Expect(rr.StaticBPFModeL7ProxyChains(4)).To(BeNil())
+		})
+	})
+
+	Describe("with trusted DNS servers", func() {
+		BeforeEach(func() {
+			conf = Config{
+				WorkloadIfacePrefixes:       []string{"cali"},
+				IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+				IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+				DNSTrustedServers:           []string{"10.96.0.10/32", "fd00::10/128"},
+				DNSPolicyNFLOGGroup:         41,
+				IptablesMarkAccept:          0x10,
+				IptablesMarkPass:            0x20,
+				IptablesMarkScratch0:        0x40,
+				IptablesMarkScratch1:        0x80,
+				IptablesMarkEndpoint:        0xff00,
+				IptablesMarkNonCaliEndpoint: 0x100,
+			}
+		})
+
+		for _, ipVersion := range []uint8{4, 6} {
+			ipVersion := ipVersion
+			server := map[uint8]string{4: "10.96.0.10/32", 6: "fd00::10/128"}[ipVersion]
+			otherServer := map[uint8]string{4: "fd00::10/128", 6: "10.96.0.10/32"}[ipVersion]
+
+			It(fmt.Sprintf("IPv%d: should snoop the DNS responses to workloads", ipVersion), func() {
+				chain := findChain(rr.StaticFilterTableChains(ipVersion), "cali-FORWARD")
+				Expect(chain.Rules).To(ContainElement(Rule{
+					Match:  Match().OutInterface("cali+").Protocol("udp").ConntrackReplyFrom(server, 53),
+					Action: NflogAction{Group: 41, Prefix: "DNS"},
+				}))
+				Expect(chain.Rules).NotTo(ContainElement(Rule{
+					Match:  Match().OutInterface("cali+").Protocol("udp").ConntrackReplyFrom(otherServer, 53),
+					Action: NflogAction{Group: 41, Prefix: "DNS"},
+				}))
+			})
+		}
+	})
+
+	Describe("with the NDP failsafe enabled", func() {
+		BeforeEach(func() {
+			conf = Config{
+				WorkloadIfacePrefixes:       []string{"cali"},
+				IPSetConfigV4:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV4, "cali", nil, nil),
+				IPSetConfigV6:               ipsets.NewIPVersionConfig(ipsets.IPFamilyV6, "cali", nil, nil),
+				FailsafeNDPEnabled:          true,
+				IptablesMarkAccept:          0x10,
+				IptablesMarkPass:            0x20,
+				IptablesMarkScratch0:        0x40,
+				IptablesMarkScratch1:        0x80,
+				IptablesMarkEndpoint:        0xff00,
+				IptablesMarkNonCaliEndpoint: 0x100,
+			}
+		})
+
+		ndpRules := []Rule{
+			{Match: Match().ProtocolNum(ProtoICMPv6).ICMPV6Type(133), Action: AcceptAction{}},
+			{Match: Match().ProtocolNum(ProtoICMPv6).ICMPV6Type(134), Action: AcceptAction{}},
+			{Match: Match().ProtocolNum(ProtoICMPv6).ICMPV6Type(135), Action: AcceptAction{}},
+			{Match: Match().ProtocolNum(ProtoICMPv6).ICMPV6Type(136), Action: AcceptAction{}},
+		}
+
+		for _, chainName := range []string{"cali-failsafe-in", "cali-failsafe-out"} {
+			chainName := chainName
+			It("IPv6: should accept NDP in the "+chainName+" chains", func() {
+				Expect(findChain(rr.StaticFilterTableChains(6), chainName).Rules).To(Equal(ndpRules))
+				Expect(findChain(rr.StaticRawTableChains(6), chainName).Rules).To(Equal(ndpRules))
+				Expect(findChain(rr.StaticMangleTableChains(6), chainName).Rules).To(Equal(ndpRules))
+			})
+			It("IPv4: should not accept ICMPv6 in the "+chainName+" chains", func() {
+				Expect(findChain(rr.StaticFilterTableChains(4), chainName).Rules).To(BeEmpty())
+			})
+		}
+	})
+
 	Describe("with openstack special-cases and RETURN action", func() {
 		BeforeEach(func() {
 			conf = Config{
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shutdown decides what Felix does with the dataplane when it is terminated: leave it
+// programmed, so that the next Felix picks up where this one left off, or remove what Felix
+// programmed, when the node is decommissioned.  The mode comes from the DataplaneShutdownMode
+// config and can be changed at run time over a unix socket, from a pre-stop hook for example.
+package shutdown
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// ModeRetain leaves the dataplane as it is, for a fast restart or upgrade.
+	ModeRetain = "Retain"
+	// ModeCleanup removes the BPF programs and maps, the iptables chains and the routes that
+	// Felix programmed.
+	ModeCleanup = "Cleanup"
+)
+
+// Mode is the shutdown mode, shared by the socket server and the shutdown of Felix.
+type Mode struct {
+	lock sync.Mutex
+	mode string
+}
+
+// NewMode returns the given mode, or Retain if the mode is unknown.
+func NewMode(mode string) *Mode {
+	m := &Mode{mode: ModeRetain}
+	_ = m.Set(mode)
+	return m
+}
+
+func (m *Mode) Get() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.mode
+}
+
+func (m *Mode) Set(mode string) error {
+	if mode != ModeRetain && mode != ModeCleanup {
+		return fmt.Errorf("unknown dataplane shutdown mode %q, expected %s or %s", mode, ModeRetain, ModeCleanup)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.mode = mode
+	return nil
+}
+
+// CleanUp returns true if Felix should remove what it programmed when it is terminated.
+func (m *Mode) CleanUp() bool {
+	return m.Get() == ModeCleanup
+}
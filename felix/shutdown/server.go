@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SocketName is the name of the socket in the runtime directory of Felix.
+const SocketName = "felix-shutdown.sock"
+
+// Server serves the shutdown mode over a unix socket, which only root can connect to:
+//
+//	GET /mode    returns the mode.
+//	PUT /mode    sets the mode to the body of the request, Retain or Cleanup.
+type Server struct {
+	mode       *Mode
+	socketPath string
+}
+
+func NewServer(mode *Mode, socketPath string) *Server {
+	return &Server{mode: mode, socketPath: socketPath}
+}
+
+func (s *Server) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0o700); err != nil {
+		return err
+	}
+	// Remove the socket of a previous Felix.
+	if err := os.Remove(s.socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(s.socketPath, 0o600); err != nil {
+		_ = l.Close()
+		return err
+	}
+	log.WithField("socket", s.socketPath).Info("Serving the dataplane shutdown mode.")
+	go func() {
+		err := http.Serve(l, s.Handler())
+		log.WithError(err).Error("Dataplane shutdown mode server failed.")
+	}()
+	return nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mode", s.handleMode)
+	return mux
+}
+
+func (s *Server) handleMode(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = fmt.Fprintln(w, s.mode.Get())
+	case http.MethodPut:
+		body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mode := strings.TrimSpace(string(body))
+		if err := s.mode.Set(mode); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.WithField("mode", mode).Info("Dataplane shutdown mode changed.")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SetMode asks the Felix listening on the socket to use the given mode when it is terminated.
+func SetMode(socketPath, mode string) error {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	req, err := http.NewRequest(http.MethodPut, "http://felix/mode", strings.NewReader(mode))
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Felix on %s: %w", socketPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("felix refused the dataplane shutdown mode: %s", strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
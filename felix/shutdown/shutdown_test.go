@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dataplane shutdown mode", func() {
+	It("should default to Retain", func() {
+		Expect(NewMode("").Get()).To(Equal(ModeRetain))
+		Expect(NewMode("Bogus").CleanUp()).To(BeFalse())
+		Expect(NewMode(ModeCleanup).CleanUp()).To(BeTrue())
+	})
+
+	It("should reject unknown modes", func() {
+		m := NewMode(ModeCleanup)
+		Expect(m.Set("cleanup-everything")).NotTo(Succeed())
+		Expect(m.Get()).To(Equal(ModeCleanup))
+		Expect(m.Set(ModeRetain)).To(Succeed())
+		Expect(m.CleanUp()).To(BeFalse())
+	})
+
+	Describe("server", func() {
+		var (
+			mode    *Mode
+			handler http.Handler
+		)
+
+		BeforeEach(func() {
+			mode = NewMode(ModeRetain)
+			handler = NewServer(mode, "").Handler()
+		})
+
+		do := func(method, body string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(method, "/mode", strings.NewReader(body)))
+			return rec
+		}
+
+		It("should return the mode", func() {
+			rec := do(http.MethodGet, "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("Retain\n"))
+		})
+
+		It("should set the mode", func() {
+			Expect(do(http.MethodPut, "Cleanup\n").Code).To(Equal(http.StatusNoContent))
+			Expect(mode.CleanUp()).To(BeTrue())
+		})
+
+		It("should reject an unknown mode", func() {
+			Expect(do(http.MethodPut, "Wipe").Code).To(Equal(http.StatusBadRequest))
+			Expect(mode.Get()).To(Equal(ModeRetain))
+		})
+
+		It("should reject other methods", func() {
+			Expect(do(http.MethodDelete, "").Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+
+		It("should set the mode over the socket", func() {
+			socketPath := filepath.Join(GinkgoT().TempDir(), "run", SocketName)
+			Expect(NewServer(mode, socketPath).Start()).To(Succeed())
+
+			info, err := os.Stat(socketPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+			Expect(SetMode(socketPath, ModeCleanup)).To(Succeed())
+			Expect(mode.CleanUp()).To(BeTrue())
+			Expect(SetMode(socketPath, "Wipe")).To(MatchError(ContainSubstring("unknown dataplane shutdown mode")))
+		})
+	})
+})
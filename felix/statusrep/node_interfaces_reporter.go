@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusrep
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// hostEndpointIfaceRegex matches the interface names that a host endpoint accepts, other
+// interfaces can't get a host endpoint so they are not reported.
+var hostEndpointIfaceRegex = regexp.MustCompile("^[a-zA-Z0-9_.-]{1,15}$")
+
+// NodeInterfacesReporter reports the host interfaces of this node, and their addresses, in the
+// Node resource, so that kube-controllers can generate host endpoints for them from templates.
+// The interfaces are polled once per reporting interval and the Node is only updated when they
+// changed; failed updates are retried on the next interval.
+type NodeInterfacesReporter struct {
+	hostname       string
+	nodes          clientv3.NodeInterface
+	reportingDelay time.Duration
+	exclude        func(ifaceName string) bool
+
+	reported       []libapiv3.NodeInterface
+	reportedOnce   bool
+	listInterfaces func() ([]libapiv3.NodeInterface, error)
+}
+
+// NewNodeInterfacesReporter creates a reporter for the node with the given name.  The interfaces
+// for which exclude returns true, such as workload interfaces, are not reported.
+func NewNodeInterfacesReporter(
+	hostname string,
+	nodes clientv3.NodeInterface,
+	reportingDelay time.Duration,
+	exclude func(ifaceName string) bool,
+) *NodeInterfacesReporter {
+	return &NodeInterfacesReporter{
+		hostname:       hostname,
+		nodes:          nodes,
+		reportingDelay: reportingDelay,
+		exclude:        exclude,
+		listInterfaces: listHostInterfaces,
+	}
+}
+
+// SyncForever blocks until ctx is cancelled, reporting the interfaces once per reporting interval.
+func (r *NodeInterfacesReporter) SyncForever(ctx context.Context) {
+	ticker := time.NewTicker(r.reportingDelay)
+	defer ticker.Stop()
+	for {
+		if err := r.Report(ctx); err != nil {
+			logrus.WithError(err).Warn("Failed to report the host interfaces of the node, will retry.")
+		}
+		select {
+		case <-ctx.Done():
+			logrus.Debug("Context cancelled, stopping node interfaces reporter.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report updates the interfaces in the Node resource if they changed since the last report.
+func (r *NodeInterfacesReporter) Report(ctx context.Context) error {
+	all, err := r.listInterfaces()
+	if err != nil {
+		return err
+	}
+	var ifaces []libapiv3.NodeInterface
+	for _, iface := range all {
+		if !hostEndpointIfaceRegex.MatchString(iface.Name) || r.exclude(iface.Name) {
+			continue
+		}
+		ifaces = append(ifaces, iface)
+	}
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
+	if r.reportedOnce && reflect.DeepEqual(ifaces, r.reported) {
+		return nil
+	}
+
+	// In case of a conflict with another writer of the Node, retry the update a few times.
+	for attempt := 0; ; attempt++ {
+		node, err := r.nodes.Get(ctx, r.hostname, options.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(node.Spec.Interfaces, ifaces) {
+			break
+		}
+		node.Spec.Interfaces = ifaces
+		_, err = r.nodes.Update(ctx, node, options.SetOptions{})
+		if _, ok := err.(cerrors.ErrorResourceUpdateConflict); ok && attempt < 2 {
+			logrus.Debug("Update conflict, retrying update of the node interfaces")
+			continue
+		} else if err != nil {
+			return err
+		}
+		logrus.WithField("interfaces", ifaces).Info("Updated the host interfaces of the node.")
+		break
+	}
+	r.reported = ifaces
+	r.reportedOnce = true
+	return nil
+}
+
+// listHostInterfaces returns the interfaces of the host, other than loopback, with their
+// addresses.  Link-local addresses are left out, they are the same on many hosts.
+func listHostInterfaces() ([]libapiv3.NodeInterface, error) {
+	netIfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var ifaces []libapiv3.NodeInterface
+	for _, netIface := range netIfaces {
+		if netIface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := netIface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		iface := libapiv3.NodeInterface{Name: netIface.Name}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			iface.Addresses = append(iface.Addresses, ipNet.IP.String())
+		}
+		sort.Strings(iface.Addresses)
+		ifaces = append(ifaces, iface)
+	}
+	return ifaces, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusrep
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// fakeNodes stores a single node.  Only Get and Update are implemented.
+type fakeNodes struct {
+	clientv3.NodeInterface
+	node      *libapiv3.Node
+	updates   int
+	conflicts int
+}
+
+func (f *fakeNodes) Get(_ context.Context, name string, _ options.GetOptions) (*libapiv3.Node, error) {
+	if f.node == nil || f.node.Name != name {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	return f.node.DeepCopy(), nil
+}
+
+func (f *fakeNodes) Update(_ context.Context, res *libapiv3.Node, _ options.SetOptions) (*libapiv3.Node, error) {
+	if f.conflicts > 0 {
+		f.conflicts--
+		return nil, cerrors.ErrorResourceUpdateConflict{Identifier: res.Name}
+	}
+	f.updates++
+	f.node = res.DeepCopy()
+	return res, nil
+}
+
+var _ = Describe("NodeInterfacesReporter", func() {
+	var (
+		nodes    *fakeNodes
+		reporter *NodeInterfacesReporter
+		ifaces   []libapiv3.NodeInterface
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		node := libapiv3.NewNode()
+		node.Name = "node-1"
+		nodes = &fakeNodes{node: node}
+		ifaces = []libapiv3.NodeInterface{
+			{Name: "eth1", Addresses: []string{"192.168.0.1"}},
+			{Name: "cali12345", Addresses: []string{"169.254.1.1"}},
+			{Name: "eth0", Addresses: []string{"10.0.0.1", "fd00::1"}},
+			{Name: "bond0@eth1"},
+		}
+		reporter = NewNodeInterfacesReporter("node-1", nodes, time.Second, func(name string) bool {
+			return strings.HasPrefix(name, "cali")
+		})
+		reporter.listInterfaces = func() ([]libapiv3.NodeInterface, error) { return ifaces, nil }
+	})
+
+	It("should report the interfaces that can have a host endpoint", func() {
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.node.Spec.Interfaces).To(Equal([]libapiv3.NodeInterface{
+			{Name: "eth0", Addresses: []string{"10.0.0.1", "fd00::1"}},
+			{Name: "eth1", Addresses: []string{"192.168.0.1"}},
+		}))
+	})
+
+	It("should only update the node when the interfaces changed", func() {
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.updates).To(Equal(1))
+
+		ifaces = ifaces[:1]
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.updates).To(Equal(2))
+		Expect(nodes.node.Spec.Interfaces).To(Equal([]libapiv3.NodeInterface{
+			{Name: "eth1", Addresses: []string{"192.168.0.1"}},
+		}))
+	})
+
+	It("should not update a node that already has the interfaces", func() {
+		nodes.node.Spec.Interfaces = []libapiv3.NodeInterface{
+			{Name: "eth0", Addresses: []string{"10.0.0.1", "fd00::1"}},
+			{Name: "eth1", Addresses: []string{"192.168.0.1"}},
+		}
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.updates).To(BeZero())
+	})
+
+	It("should retry on update conflicts", func() {
+		nodes.conflicts = 2
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.updates).To(Equal(1))
+	})
+
+	It("should report again after a failure", func() {
+		nodes.node = nil
+		Expect(reporter.Report(ctx)).NotTo(Succeed())
+
+		node := libapiv3.NewNode()
+		node.Name = "node-1"
+		nodes.node = node
+		Expect(reporter.Report(ctx)).To(Succeed())
+		Expect(nodes.node.Spec.Interfaces).To(HaveLen(2))
+	})
+})
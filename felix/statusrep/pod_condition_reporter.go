@@ -0,0 +1,240 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusrep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+const (
+	// PolicyProgrammedCondition is the type of the pod condition that reports whether Felix has
+	// programmed the policy of the pod's workload endpoint on its node.
+	PolicyProgrammedCondition v1.PodConditionType = "projectcalico.org/PolicyProgrammed"
+
+	statusError = "error"
+
+	ReasonProgrammed     = "Programmed"
+	ReasonEndpointDown   = "EndpointDown"
+	ReasonDataplaneError = "DataplaneError"
+)
+
+// PodConditionReporter reports the status of the local Kubernetes workload endpoints as the
+// PolicyProgrammed condition of their pods, so that describing a pod shows whether its policy is
+// programmed on the node.  The dataplane only reports changes of the endpoint status, so the
+// last transition time of the condition is when the dataplane finished programming the endpoint.
+// A pod with several interfaces has an endpoint per interface, its condition is only true once
+// all of them are programmed.
+//
+// The conditions are patched in batches, at most once per reporting interval; failed patches
+// are retried on the next interval.
+type PodConditionReporter struct {
+	hostname         string
+	endpointUpdatesC <-chan interface{}
+	client           kubernetes.Interface
+	reportingDelay   time.Duration
+
+	endpointStatuses map[types.NamespacedName]map[string]string
+	conditions       map[types.NamespacedName]v1.PodCondition
+	dirtyPods        set.Set[types.NamespacedName]
+
+	now func() time.Time
+}
+
+func NewPodConditionReporter(
+	hostname string,
+	endpointUpdatesC <-chan interface{},
+	client kubernetes.Interface,
+	reportingDelay time.Duration,
+) *PodConditionReporter {
+	return &PodConditionReporter{
+		hostname:         hostname,
+		endpointUpdatesC: endpointUpdatesC,
+		client:           client,
+		reportingDelay:   reportingDelay,
+		endpointStatuses: map[types.NamespacedName]map[string]string{},
+		conditions:       map[types.NamespacedName]v1.PodCondition{},
+		dirtyPods:        set.New[types.NamespacedName](),
+		now:              time.Now,
+	}
+}
+
+// SyncForever blocks until ctx is cancelled, recording the endpoint updates and patching the
+// conditions of the pods that changed once per reporting interval.
+func (r *PodConditionReporter) SyncForever(ctx context.Context) {
+	ticker := time.NewTicker(r.reportingDelay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Debug("Context cancelled, stopping pod condition reporter.")
+			return
+		case msg, ok := <-r.endpointUpdatesC:
+			if !ok {
+				logrus.Panic("Input channel closed unexpectedly")
+			}
+			r.OnEndpointUpdate(msg)
+		case <-ticker.C:
+			r.Flush(ctx)
+		}
+	}
+}
+
+// OnEndpointUpdate records the condition of the pod of a workload endpoint status update.  The
+// endpoints of other orchestrators are ignored, they have no pod.
+func (r *PodConditionReporter) OnEndpointUpdate(msg interface{}) {
+	switch m := msg.(type) {
+	case *proto.WorkloadEndpointStatusUpdate:
+		pod, ok := podForEndpoint(m.Id)
+		if !ok {
+			return
+		}
+		if r.endpointStatuses[pod] == nil {
+			r.endpointStatuses[pod] = map[string]string{}
+		}
+		r.endpointStatuses[pod][m.Id.EndpointId] = m.Status.GetStatus()
+		r.updateCondition(pod)
+	case *proto.WorkloadEndpointStatusRemove:
+		pod, ok := podForEndpoint(m.Id)
+		if !ok {
+			return
+		}
+		delete(r.endpointStatuses[pod], m.Id.EndpointId)
+		if len(r.endpointStatuses[pod]) > 0 {
+			// Only one interface of the pod was removed.
+			r.updateCondition(pod)
+			return
+		}
+		// The pod is going away, or moved to another node, which will report it from now on.
+		delete(r.endpointStatuses, pod)
+		delete(r.conditions, pod)
+		r.dirtyPods.Discard(pod)
+	}
+}
+
+// updateCondition queues an update of the condition of the pod if the combined status of its
+// endpoints changed.
+func (r *PodConditionReporter) updateCondition(pod types.NamespacedName) {
+	status := combinedStatus(r.endpointStatuses[pod])
+	cond := r.conditionForStatus(status)
+	if old, ok := r.conditions[pod]; ok && old.Status == cond.Status && old.Reason == cond.Reason {
+		return
+	}
+	logrus.WithFields(logrus.Fields{"pod": pod, "status": status}).Debug("Queueing pod condition update.")
+	r.conditions[pod] = cond
+	r.dirtyPods.Add(pod)
+}
+
+// combinedStatus returns the status of the endpoint that is furthest from being programmed: an
+// error, then down, then any other status, then up.
+func combinedStatus(statuses map[string]string) string {
+	combined := statusUp
+	for _, status := range statuses {
+		switch {
+		case status == statusError:
+			return statusError
+		case status == statusDown:
+			combined = statusDown
+		case status != statusUp && combined == statusUp:
+			combined = status
+		}
+	}
+	return combined
+}
+
+// Flush patches the conditions of the pods that changed since the last flush.  Pods that no
+// longer exist are skipped, other failures are retried on the next flush.
+func (r *PodConditionReporter) Flush(ctx context.Context) {
+	r.dirtyPods.Iter(func(pod types.NamespacedName) error {
+		err := r.patchCondition(ctx, pod, r.conditions[pod])
+		if k8serrors.IsNotFound(err) {
+			logrus.WithField("pod", pod).Debug("Pod no longer exists, skipping its condition.")
+		} else if err != nil {
+			logrus.WithError(err).WithField("pod", pod).Warn(
+				"Failed to update pod condition, will retry.")
+			return nil
+		}
+		return set.RemoveItem
+	})
+}
+
+func (r *PodConditionReporter) patchCondition(ctx context.Context, pod types.NamespacedName, cond v1.PodCondition) error {
+	// Conditions are merged by type, so the patch leaves the other conditions of the pod alone.
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []v1.PodCondition{cond},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.client.CoreV1().Pods(pod.Namespace).Patch(
+		ctx, pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func (r *PodConditionReporter) conditionForStatus(status string) v1.PodCondition {
+	cond := v1.PodCondition{
+		Type:               PolicyProgrammedCondition,
+		Status:             v1.ConditionFalse,
+		LastTransitionTime: metav1.NewTime(r.now()),
+	}
+	switch status {
+	case statusUp:
+		cond.Status = v1.ConditionTrue
+		cond.Reason = ReasonProgrammed
+		cond.Message = fmt.Sprintf("Policy programmed on node %s.", r.hostname)
+	case statusDown:
+		cond.Reason = ReasonEndpointDown
+		cond.Message = fmt.Sprintf("Interface of the endpoint is down on node %s.", r.hostname)
+	case statusError:
+		cond.Reason = ReasonDataplaneError
+		cond.Message = fmt.Sprintf("Failed to program the endpoint on node %s, see the Felix logs.", r.hostname)
+	default:
+		cond.Status = v1.ConditionUnknown
+		cond.Reason = "Unknown"
+		cond.Message = fmt.Sprintf("Unrecognised endpoint status %q on node %s.", status, r.hostname)
+	}
+	return cond
+}
+
+// podForEndpoint returns the pod of a Kubernetes workload endpoint, whose workload ID is
+// "<namespace>/<pod>".
+func podForEndpoint(id *proto.WorkloadEndpointID) (types.NamespacedName, bool) {
+	if id == nil || id.OrchestratorId != apiv3.OrchestratorKubernetes {
+		return types.NamespacedName{}, false
+	}
+	namespace, name, ok := strings.Cut(id.WorkloadId, "/")
+	if !ok {
+		logrus.WithField("id", id).Warn("Unexpected workload ID for a Kubernetes endpoint.")
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
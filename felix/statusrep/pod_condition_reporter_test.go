@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusrep
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+var _ = Describe("PodConditionReporter", func() {
+	var (
+		client   *fake.Clientset
+		reporter *PodConditionReporter
+		now      time.Time
+		ctx      context.Context
+	)
+
+	podEndpointID := func(name string) *proto.WorkloadEndpointID {
+		return &proto.WorkloadEndpointID{OrchestratorId: "k8s", WorkloadId: "default/" + name, EndpointId: "eth0"}
+	}
+	statusUpdate := func(name, status string) *proto.WorkloadEndpointStatusUpdate {
+		return &proto.WorkloadEndpointStatusUpdate{Id: podEndpointID(name), Status: &proto.EndpointStatus{Status: status}}
+	}
+	condition := func(name string) *v1.PodCondition {
+		pod, err := client.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, c := range pod.Status.Conditions {
+			if c.Type == PolicyProgrammedCondition {
+				return &c
+			}
+		}
+		return nil
+	}
+	numPatches := func() int {
+		n := 0
+		for _, a := range client.Actions() {
+			if a.GetVerb() == "patch" {
+				n++
+			}
+		}
+		return n
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = fake.NewSimpleClientset(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		})
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		reporter = NewPodConditionReporter("node-1", nil, client, time.Second)
+		reporter.now = func() time.Time { return now }
+	})
+
+	It("should report a programmed endpoint and keep the other conditions", func() {
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.Flush(ctx)
+
+		cond := condition("web")
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(v1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(ReasonProgrammed))
+		Expect(cond.Message).To(ContainSubstring("node-1"))
+		Expect(cond.LastTransitionTime.Time.Equal(now)).To(BeTrue())
+
+		pod, err := client.CoreV1().Pods("default").Get(ctx, "web", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Status.Conditions).To(HaveLen(2))
+	})
+
+	It("should report endpoints that are down or failed", func() {
+		reporter.OnEndpointUpdate(statusUpdate("web", "down"))
+		reporter.Flush(ctx)
+		Expect(condition("web").Status).To(Equal(v1.ConditionFalse))
+		Expect(condition("web").Reason).To(Equal(ReasonEndpointDown))
+
+		now = now.Add(time.Minute)
+		reporter.OnEndpointUpdate(statusUpdate("web", "error"))
+		reporter.Flush(ctx)
+		Expect(condition("web").Status).To(Equal(v1.ConditionFalse))
+		Expect(condition("web").Reason).To(Equal(ReasonDataplaneError))
+		Expect(condition("web").LastTransitionTime.Time.Equal(now)).To(BeTrue())
+	})
+
+	It("should only patch the pods that changed", func() {
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.Flush(ctx)
+		Expect(numPatches()).To(Equal(1))
+
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.Flush(ctx)
+		reporter.Flush(ctx)
+		Expect(numPatches()).To(Equal(1))
+	})
+
+	It("should ignore endpoints of other orchestrators", func() {
+		reporter.OnEndpointUpdate(&proto.WorkloadEndpointStatusUpdate{
+			Id:     &proto.WorkloadEndpointID{OrchestratorId: "openstack", WorkloadId: "default/web", EndpointId: "tap1"},
+			Status: &proto.EndpointStatus{Status: "up"},
+		})
+		reporter.Flush(ctx)
+		Expect(numPatches()).To(BeZero())
+	})
+
+	It("should drop the pods that no longer exist", func() {
+		reporter.OnEndpointUpdate(statusUpdate("gone", "up"))
+		reporter.Flush(ctx)
+		reporter.Flush(ctx)
+		Expect(numPatches()).To(Equal(1))
+	})
+
+	It("should forget removed endpoints", func() {
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.OnEndpointUpdate(&proto.WorkloadEndpointStatusRemove{Id: podEndpointID("web")})
+		reporter.Flush(ctx)
+		Expect(numPatches()).To(BeZero())
+		Expect(condition("web")).To(BeNil())
+	})
+
+	It("should only report a pod with several interfaces as programmed once all are up", func() {
+		net1 := &proto.WorkloadEndpointID{OrchestratorId: "k8s", WorkloadId: "default/web", EndpointId: "net1"}
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.OnEndpointUpdate(&proto.WorkloadEndpointStatusUpdate{Id: net1, Status: &proto.EndpointStatus{Status: "down"}})
+		reporter.Flush(ctx)
+		Expect(condition("web").Status).To(Equal(v1.ConditionFalse))
+		Expect(condition("web").Reason).To(Equal(ReasonEndpointDown))
+
+		reporter.OnEndpointUpdate(&proto.WorkloadEndpointStatusUpdate{Id: net1, Status: &proto.EndpointStatus{Status: "up"}})
+		reporter.Flush(ctx)
+		Expect(condition("web").Status).To(Equal(v1.ConditionTrue))
+
+		By("removing the secondary interface")
+		reporter.OnEndpointUpdate(&proto.WorkloadEndpointStatusRemove{Id: net1})
+		reporter.Flush(ctx)
+		Expect(condition("web").Status).To(Equal(v1.ConditionTrue))
+		Expect(numPatches()).To(Equal(2))
+	})
+
+	It("should retry failed patches", func() {
+		failures := 1
+		client.PrependReactor("patch", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if failures > 0 {
+				failures--
+				return true, nil, errors.New("apiserver unavailable")
+			}
+			return false, nil, nil
+		})
+
+		reporter.OnEndpointUpdate(statusUpdate("web", "up"))
+		reporter.Flush(ctx)
+		Expect(condition("web")).To(BeNil())
+
+		reporter.Flush(ctx)
+		Expect(condition("web")).NotTo(BeNil())
+		Expect(condition("web").Status).To(Equal(v1.ConditionTrue))
+	})
+})
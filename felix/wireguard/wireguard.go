@@ -27,6 +27,8 @@ import (
 	"github.com/vishvananda/netlink"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
+	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
+	"github.com/projectcalico/calico/felix/capabilities"
 	"github.com/projectcalico/calico/felix/environment"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ip"
@@ -1590,6 +1592,16 @@ func (w *Wireguard) addRouteRule() {
 	w.routerule.SetRule(routerule.NewRule(int(w.ipVersion), w.config.RoutingRulePriority).
 		GoToTable(w.config.RoutingTableIndex).
 		Not().MatchFWMarkWithMask(uint32(w.config.FirewallMark), uint32(w.config.FirewallMark)))
+
+	if w.config.BPFEnabled {
+		// The BPF programs mark DNATed traffic to remote backends so that it skips the FIB
+		// short-circuit and is routed by the kernel. Match that mark explicitly so that
+		// such traffic is always steered to the wireguard table, regardless of any other
+		// bits the BPF programs may set alongside it.
+		w.routerule.SetRule(routerule.NewRule(int(w.ipVersion), w.config.RoutingRulePriority).
+			GoToTable(w.config.RoutingTableIndex).
+			MatchFWMarkWithMask(tcdefs.MarkSeenBypassWireguard, tcdefs.MarkSeenBypassWireguardMask))
+	}
 }
 
 // ensureDisabled ensures all calico-installed wireguard configuration is removed.
@@ -1814,7 +1826,7 @@ func getOnlyItemInSet[T comparable](s set.Set[T]) *T {
 
 // writeProcSys writes the value to the given sysctl path
 func writeProcSys(path, value string) error {
-	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	f, err := os.OpenFile(capabilities.ProcSysPath(path), os.O_WRONLY, 0)
 	if err != nil {
 		return err
 	}
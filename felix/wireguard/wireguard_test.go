@@ -32,6 +32,7 @@ import (
 	"github.com/vishvananda/netlink"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
+	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/ifacemonitor"
 	"github.com/projectcalico/calico/felix/ip"
 	mocknetlink "github.com/projectcalico/calico/felix/netlinkshim/mocknetlink"
@@ -3726,3 +3727,75 @@ var _ = Describe("Wireguard (with no table index)", func() {
 		Expect(func() { wgFn(true, 7) }).To(Panic())
 	})
 })
+
+var _ = Describe("Wireguard (with BPF enabled)", func() {
+	var wgDataplane, rtDataplane, rrDataplane *mocknetlink.MockNetlinkDataplane
+	var t *mocktime.MockTime
+	var s mockCallbacks
+	var wg *Wireguard
+
+	BeforeEach(func() {
+		wgDataplane = mocknetlink.New()
+		rtDataplane = mocknetlink.New()
+		rrDataplane = mocknetlink.New()
+		t = mocktime.New()
+		t.SetAutoIncrement(11 * time.Second)
+
+		mockFeatureDetector := &environment.FakeFeatureDetector{
+			Features: environment.Features{
+				KernelSideRouteFiltering: true,
+			},
+		}
+		wg = NewWithShims(
+			hostname,
+			&Config{
+				Enabled:             true,
+				ListeningPort:       listeningPort,
+				FirewallMark:        firewallMark,
+				RoutingRulePriority: rulePriority,
+				RoutingTableIndex:   tableIndex,
+				InterfaceName:       ifaceName,
+				MTU:                 mtu,
+				BPFEnabled:          true,
+			},
+			4,
+			rtDataplane.NewMockNetlink,
+			rrDataplane.NewMockNetlink,
+			wgDataplane.NewMockNetlink,
+			wgDataplane.NewMockWireguard,
+			10*time.Second,
+			t,
+			FelixRouteProtocol,
+			s.status,
+			s.writeProcSys,
+			logutils.NewSummarizer("test loop"),
+			mockFeatureDetector,
+		)
+
+		err := wg.Apply()
+		Expect(err).NotTo(HaveOccurred())
+		wgDataplane.SetIface(ifaceName, true, true)
+		wg.OnIfaceStateChanged(ifaceName, ifacemonitor.StateUp)
+		err = wg.Apply()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should add a routing rule matching the BPF wireguard mark", func() {
+		rule := netlink.NewRule()
+		rule.Family = netlink.FAMILY_V4
+		rule.Priority = rulePriority
+		rule.Table = tableIndex
+		rule.Invert = true
+		rule.Mark = firewallMark
+		rule.Mask = firewallMark
+
+		bpfRule := netlink.NewRule()
+		bpfRule.Family = netlink.FAMILY_V4
+		bpfRule.Priority = rulePriority
+		bpfRule.Table = tableIndex
+		bpfRule.Mark = tcdefs.MarkSeenBypassWireguard
+		bpfRule.Mask = tcdefs.MarkSeenBypassWireguardMask
+
+		Expect(rrDataplane.AddedRules).To(ConsistOf(*rule, *bpfRule))
+	})
+})
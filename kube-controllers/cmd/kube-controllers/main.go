@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
@@ -41,6 +42,7 @@ import (
 
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
 	"github.com/projectcalico/calico/libcalico-go/lib/logutils"
 
 	"github.com/projectcalico/calico/crypto/pkg/tls"
@@ -60,6 +62,10 @@ var (
 	VERSION    string
 	version    bool
 	statusFile string
+
+	ipamGC            bool
+	ipamGCRelease     bool
+	ipamGCGracePeriod time.Duration
 )
 
 func init() {
@@ -67,6 +73,11 @@ func init() {
 	flag.BoolVar(&version, "version", false, "Display version")
 	flag.StringVar(&statusFile, "status-file", status.DefaultStatusFile, "File to write status information to")
 
+	// Add flags to run a single IPAM garbage collection instead of the controllers, for example from a Job.
+	flag.BoolVar(&ipamGC, "ipam-gc", false, "Run a single IPAM garbage collection, print its report in JSON and exit")
+	flag.BoolVar(&ipamGCRelease, "ipam-gc-release", false, "Release the leaks that the IPAM garbage collection finds")
+	flag.DurationVar(&ipamGCGracePeriod, "ipam-gc-grace-period", 15*time.Minute, "Minimum age of the IPs that the IPAM garbage collection reports as leaked")
+
 	// Tell klog to log into STDERR. Otherwise, we risk
 	// certain kinds of API errors getting logged into a directory not
 	// available in a `FROM scratch` Docker container, causing us to abort
@@ -112,6 +123,11 @@ func main() {
 		log.WithError(err).Fatal("Failed to start")
 	}
 
+	if ipamGC {
+		runIPAMGC(calicoClient)
+		return
+	}
+
 	stop := make(chan struct{})
 
 	// Create the context.
@@ -313,6 +329,22 @@ func runHealthChecks(ctx context.Context, s *status.Status, k8sClientset *kubern
 }
 
 // Starts an etcdv3 compaction goroutine with the given config.
+// runIPAMGC runs a single IPAM garbage collection and prints its report to stdout.
+func runIPAMGC(calicoClient client.Interface) {
+	report, err := calicoClient.IPAM().GarbageCollect(context.Background(), ipam.GCArgs{
+		Release:     ipamGCRelease,
+		GracePeriod: ipamGCGracePeriod,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("IPAM garbage collection failed")
+	}
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.WithError(err).Fatal("Failed to marshal IPAM garbage collection report")
+	}
+	fmt.Println(string(bytes))
+}
+
 func startCompactor(ctx context.Context, interval time.Duration) {
 	if interval.Nanoseconds() == 0 {
 		log.Info("Disabling periodic etcdv3 compaction")
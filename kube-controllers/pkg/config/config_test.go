@@ -189,8 +189,17 @@ var _ = Describe("Config", func() {
 						Node: &v3.NodeControllerConfig{
 							ReconcilerPeriod: nil,
 							SyncLabels:       v3.Disabled,
-							HostEndpoint:     &v3.AutoHostEndpointConfig{AutoCreate: v3.Enabled},
-							LeakGracePeriod:  &v1.Duration{Duration: 20 * time.Minute},
+							HostEndpoint: &v3.AutoHostEndpointConfig{
+								AutoCreate:                v3.Enabled,
+								CreateDefaultHostEndpoint: v3.Disabled,
+								Templates: []v3.AutoHostEndpointTemplate{{
+									GenerateName:     "data",
+									InterfacePattern: "^eth[1-9]$",
+									Labels:           map[string]string{"network": "data"},
+								}},
+							},
+							LeakGracePeriod: &v1.Duration{Duration: 20 * time.Minute},
+							PodCIDRPools:    v3.Enabled,
 						},
 						Policy: &v3.PolicyControllerConfig{
 							ReconcilerPeriod: &v1.Duration{Duration: time.Second * 30}},
@@ -221,8 +230,14 @@ var _ = Describe("Config", func() {
 				Expect(rc.Node).To(Equal(&config.NodeControllerConfig{
 					SyncLabels:        false,
 					AutoHostEndpoints: true,
-					DeleteNodes:       true,
-					LeakGracePeriod:   &v1.Duration{Duration: 20 * time.Minute},
+					HostEndpointTemplates: []v3.AutoHostEndpointTemplate{{
+						GenerateName:     "data",
+						InterfacePattern: "^eth[1-9]$",
+						Labels:           map[string]string{"network": "data"},
+					}},
+					DeleteNodes:     true,
+					LeakGracePeriod: &v1.Duration{Duration: 20 * time.Minute},
+					PodCIDRPools:    true,
 				}))
 				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
 					ReconcilerPeriod: time.Second * 30,
@@ -442,10 +457,11 @@ var _ = Describe("Config", func() {
 
 				rc := runCfg.Controllers
 				Expect(rc.Node).To(Equal(&config.NodeControllerConfig{
-					SyncLabels:        false,
-					AutoHostEndpoints: true,
-					DeleteNodes:       true,
-					LeakGracePeriod:   &v1.Duration{Duration: 15 * time.Minute},
+					SyncLabels:                false,
+					AutoHostEndpoints:         true,
+					CreateDefaultHostEndpoint: true,
+					DeleteNodes:               true,
+					LeakGracePeriod:           &v1.Duration{Duration: 15 * time.Minute},
 				}))
 				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
 					ReconcilerPeriod: time.Second * 105,
@@ -535,9 +551,10 @@ var _ = Describe("Config", func() {
 
 				rc := runCfg.Controllers
 				Expect(rc.Node).To(Equal(&config.NodeControllerConfig{
-					SyncLabels:        false,
-					AutoHostEndpoints: true,
-					DeleteNodes:       true,
+					SyncLabels:                false,
+					AutoHostEndpoints:         true,
+					CreateDefaultHostEndpoint: true,
+					DeleteNodes:               true,
 				}))
 				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
 					ReconcilerPeriod: time.Second * 105,
@@ -101,6 +101,12 @@ type NodeControllerConfig struct {
 	SyncLabels        bool
 	AutoHostEndpoints bool
 
+	// Should the auto host endpoints include one for all the interfaces of each node, and
+	// which templates generate host endpoints for individual interfaces?  Only used if
+	// AutoHostEndpoints is true.
+	CreateDefaultHostEndpoint bool
+	HostEndpointTemplates     []v3.AutoHostEndpointTemplate
+
 	// Should the Node controller delete Calico nodes?  Generally, this is
 	// true for etcdv3 datastores.
 	DeleteNodes bool
@@ -108,6 +114,9 @@ type NodeControllerConfig struct {
 	// The grace period used by the controller to determine if an IP address is leaked.
 	// Set to 0 to disable IP address garbage collection.
 	LeakGracePeriod *v1.Duration
+
+	// Should the Node controller create an IP pool for the podCIDRs of each node?
+	PodCIDRPools bool
 }
 
 type RunConfigController struct {
@@ -330,10 +339,13 @@ func mergeConfig(envVars map[string]string, envCfg Config, apiCfg v3.KubeControl
 
 		mergeAutoHostEndpoints(envVars, &status, &rCfg, apiCfg)
 
-		// There is no env var config for this, so always merge from the API config.
+		// There is no env var config for these, so always merge from the API config.
 		if apiCfg.Controllers.Node != nil {
 			rc.Node.LeakGracePeriod = apiCfg.Controllers.Node.LeakGracePeriod
 			status.RunningConfig.Controllers.Node.LeakGracePeriod = apiCfg.Controllers.Node.LeakGracePeriod
+
+			rc.Node.PodCIDRPools = apiCfg.Controllers.Node.PodCIDRPools == v3.Enabled
+			status.RunningConfig.Controllers.Node.PodCIDRPools = apiCfg.Controllers.Node.PodCIDRPools
 		}
 
 		if envCfg.DatastoreType != "kubernetes" {
@@ -380,10 +392,24 @@ func mergeAutoHostEndpoints(envVars map[string]string, status *v3.KubeController
 			rc.Node.AutoHostEndpoints = true
 		}
 	}
-	if rc.Node.AutoHostEndpoints {
-		sc.Node.HostEndpoint = &v3.AutoHostEndpointConfig{AutoCreate: v3.Enabled}
-	} else {
+	if !rc.Node.AutoHostEndpoints {
 		sc.Node.HostEndpoint = &v3.AutoHostEndpointConfig{AutoCreate: v3.Disabled}
+		return
+	}
+
+	// There is no env var config for the default host endpoint and the templates, so always
+	// merge them from the API config.
+	rc.Node.CreateDefaultHostEndpoint = true
+	if ac.Node != nil && ac.Node.HostEndpoint != nil {
+		rc.Node.CreateDefaultHostEndpoint = ac.Node.HostEndpoint.CreateDefaultHostEndpoint != v3.Disabled
+		rc.Node.HostEndpointTemplates = ac.Node.HostEndpoint.Templates
+	}
+	sc.Node.HostEndpoint = &v3.AutoHostEndpointConfig{
+		AutoCreate: v3.Enabled,
+		Templates:  rc.Node.HostEndpointTemplates,
+	}
+	if !rc.Node.CreateDefaultHostEndpoint {
+		sc.Node.HostEndpoint.CreateDefaultHostEndpoint = v3.Disabled
 	}
 }
 
@@ -59,7 +59,8 @@ type NodeController struct {
 	dataFeed     *DataFeed
 
 	// Sub-controllers
-	ipamCtrl *ipamController
+	ipamCtrl        *ipamController
+	podCIDRPoolCtrl *podCIDRPoolController
 }
 
 // NewNodeController Constructor for NodeController
@@ -77,8 +78,9 @@ func NewNodeController(ctx context.Context,
 		podInformer:  podInformer,
 	}
 
-	// Store functions to call on node deletion.
+	// Store functions to call on node deletion, and on node creation and update.
 	nodeDeletionFuncs := []func(){}
+	nodeUpdateFuncs := []func(obj interface{}){}
 
 	// Create the IPAM controller.
 	nc.ipamCtrl = NewIPAMController(cfg, calicoClient, k8sClientset, podInformer.GetIndexer(), nodeInformer.GetIndexer())
@@ -94,15 +96,11 @@ func NewNodeController(ctx context.Context,
 		nodeDeletionFuncs = append(nodeDeletionFuncs, nodeDeletionController.OnKubernetesNodeDeleted)
 	}
 
-	// Setup event handlers for nodes and pods learned through the
-	// respective informers.
-	nodeHandlers := cache.ResourceEventHandlerFuncs{
-		DeleteFunc: func(obj interface{}) {
-			// Call all of the registered node deletion funcs.
-			for _, f := range nodeDeletionFuncs {
-				f()
-			}
-		}}
+	// Create the pod CIDR pool sub-controller. Like the Auto HostEndpoint sub-controller, we
+	// always launch it so that it cleans up its pools if they were previously enabled.
+	nc.podCIDRPoolCtrl = NewPodCIDRPoolController(cfg, calicoClient, nodeInformer.GetIndexer())
+	nodeUpdateFuncs = append(nodeUpdateFuncs, nc.podCIDRPoolCtrl.OnKubernetesNodeUpdate)
+	nodeDeletionFuncs = append(nodeDeletionFuncs, nc.podCIDRPoolCtrl.OnKubernetesNodeDeleted)
 
 	// Create the Auto HostEndpoint sub-controller and register it to receive data.
 	// We always launch this controller, even if auto-HEPs are disabled, since the controller
@@ -120,10 +118,29 @@ func NewNodeController(ctx context.Context,
 
 		// Hook the node label controller into the node informer so we are notified
 		// when Kubernetes node labels change.
-		nodeHandlers.AddFunc = func(obj interface{}) { nodeLabelCtrl.OnKubernetesNodeUpdate(obj) }
-		nodeHandlers.UpdateFunc = func(_, obj interface{}) { nodeLabelCtrl.OnKubernetesNodeUpdate(obj) }
+		nodeUpdateFuncs = append(nodeUpdateFuncs, nodeLabelCtrl.OnKubernetesNodeUpdate)
 	}
 
+	// Setup event handlers for nodes and pods learned through the
+	// respective informers.
+	nodeHandlers := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			for _, f := range nodeUpdateFuncs {
+				f(obj)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			for _, f := range nodeUpdateFuncs {
+				f(obj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// Call all of the registered node deletion funcs.
+			for _, f := range nodeDeletionFuncs {
+				f()
+			}
+		}}
+
 	// Set the handlers on the informers.
 	if _, err := nc.nodeInformer.AddEventHandler(nodeHandlers); err != nil {
 		log.WithError(err).Error("failed to add event handler for node")
@@ -173,6 +190,7 @@ func (c *NodeController) Run(stopCh chan struct{}) {
 
 	// We're in-sync. Start the sub-controllers.
 	c.ipamCtrl.Start(stopCh)
+	c.podCIDRPoolCtrl.Start(stopCh)
 
 	<-stopCh
 	log.Info("Stopping Node controller")
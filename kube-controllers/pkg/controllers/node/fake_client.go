@@ -19,6 +19,8 @@ import (
 	"strings"
 	"sync"
 
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
 	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
@@ -38,9 +40,17 @@ func NewFakeCalicoClient() *FakeCalicoClient {
 		affinitiesReleased: make(map[string]bool),
 		handlesReleased:    make(map[string]bool),
 	}
+	poolClient := fakeIPPoolClient{
+		pools: make(map[string]*v3.IPPool),
+	}
+	hepClient := fakeHostEndpointClient{
+		heps: make(map[string]*v3.HostEndpoint),
+	}
 	return &FakeCalicoClient{
 		nodeClient: &nc,
 		ipamClient: &ipamClient,
+		poolClient: &poolClient,
+		hepClient:  &hepClient,
 	}
 }
 
@@ -48,6 +58,8 @@ func NewFakeCalicoClient() *FakeCalicoClient {
 type FakeCalicoClient struct {
 	nodeClient clientv3.NodeInterface
 	ipamClient ipam.Interface
+	poolClient clientv3.IPPoolInterface
+	hepClient  clientv3.HostEndpointInterface
 }
 
 func (f *FakeCalicoClient) Backend() bapi.Client {
@@ -69,9 +81,19 @@ func (f *FakeCalicoClient) NetworkPolicies() clientv3.NetworkPolicyInterface {
 	panic("not implemented")
 }
 
+// StagedGlobalNetworkPolicies returns an interface for managing staged global network policy resources.
+func (f *FakeCalicoClient) StagedGlobalNetworkPolicies() clientv3.StagedGlobalNetworkPolicyInterface {
+	panic("not implemented")
+}
+
+// StagedNetworkPolicies returns an interface for managing staged namespaced network policy resources.
+func (f *FakeCalicoClient) StagedNetworkPolicies() clientv3.StagedNetworkPolicyInterface {
+	panic("not implemented")
+}
+
 // IPPools returns an interface for managing IP pool resources.
 func (f *FakeCalicoClient) IPPools() clientv3.IPPoolInterface {
-	panic("not implemented")
+	return f.poolClient
 }
 
 // Profiles returns an interface for managing profile resources.
@@ -91,7 +113,7 @@ func (f *FakeCalicoClient) NetworkSets() clientv3.NetworkSetInterface {
 
 // HostEndpoints returns an interface for managing host endpoint resources.
 func (f *FakeCalicoClient) HostEndpoints() clientv3.HostEndpointInterface {
-	panic("not implemented")
+	return f.hepClient
 }
 
 // WorkloadEndpoints returns an interface for managing workload endpoint resources.
@@ -204,6 +226,146 @@ func (f *fakeNodeClient) Watch(ctx context.Context, opts options.ListOptions) (w
 	panic("not implemented") // TODO: Implement
 }
 
+// fakeIPPoolClient implements the clientv3 IPPoolInterface for testing purposes.
+type fakeIPPoolClient struct {
+	sync.Mutex
+	pools map[string]*v3.IPPool
+}
+
+func (f *fakeIPPoolClient) Create(ctx context.Context, res *v3.IPPool, opts options.SetOptions) (*v3.IPPool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.pools[res.Name]; ok {
+		return nil, cerrors.ErrorResourceAlreadyExists{Identifier: res.Name}
+	}
+	f.pools[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeIPPoolClient) Update(ctx context.Context, res *v3.IPPool, opts options.SetOptions) (*v3.IPPool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.pools[res.Name]; !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: res.Name}
+	}
+	f.pools[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeIPPoolClient) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*v3.IPPool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	pool, ok := f.pools[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	delete(f.pools, name)
+	return pool, nil
+}
+
+func (f *fakeIPPoolClient) Get(ctx context.Context, name string, opts options.GetOptions) (*v3.IPPool, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	pool, ok := f.pools[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	return pool.DeepCopy(), nil
+}
+
+func (f *fakeIPPoolClient) List(ctx context.Context, opts options.ListOptions) (*v3.IPPoolList, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	list := &v3.IPPoolList{}
+	for _, pool := range f.pools {
+		list.Items = append(list.Items, *pool.DeepCopy())
+	}
+	return list, nil
+}
+
+func (f *fakeIPPoolClient) Watch(ctx context.Context, opts options.ListOptions) (watch.Interface, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *fakeIPPoolClient) UnsafeCreate(ctx context.Context, res *v3.IPPool, opts options.SetOptions) (*v3.IPPool, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *fakeIPPoolClient) UnsafeDelete(ctx context.Context, name string, opts options.DeleteOptions) (*v3.IPPool, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+// fakeHostEndpointClient implements the clientv3 HostEndpointInterface for testing purposes.
+type fakeHostEndpointClient struct {
+	sync.Mutex
+	heps map[string]*v3.HostEndpoint
+}
+
+func (f *fakeHostEndpointClient) Create(ctx context.Context, res *v3.HostEndpoint, opts options.SetOptions) (*v3.HostEndpoint, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.heps[res.Name]; ok {
+		return nil, cerrors.ErrorResourceAlreadyExists{Identifier: res.Name}
+	}
+	f.heps[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeHostEndpointClient) Update(ctx context.Context, res *v3.HostEndpoint, opts options.SetOptions) (*v3.HostEndpoint, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.heps[res.Name]; !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: res.Name}
+	}
+	f.heps[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeHostEndpointClient) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*v3.HostEndpoint, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	hep, ok := f.heps[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	delete(f.heps, name)
+	return hep, nil
+}
+
+func (f *fakeHostEndpointClient) Get(ctx context.Context, name string, opts options.GetOptions) (*v3.HostEndpoint, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	hep, ok := f.heps[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	return hep.DeepCopy(), nil
+}
+
+func (f *fakeHostEndpointClient) List(ctx context.Context, opts options.ListOptions) (*v3.HostEndpointList, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	list := &v3.HostEndpointList{}
+	for _, hep := range f.heps {
+		list.Items = append(list.Items, *hep.DeepCopy())
+	}
+	return list, nil
+}
+
+func (f *fakeHostEndpointClient) Watch(ctx context.Context, opts options.ListOptions) (watch.Interface, error) {
+	panic("not implemented") // TODO: Implement
+}
+
 // fakeIPAMClient implements ipam.Interface for testing purposes.
 type fakeIPAMClient struct {
 	sync.Mutex
@@ -359,3 +521,13 @@ func (f *fakeIPAMClient) GetUtilization(ctx context.Context, args ipam.GetUtiliz
 func (f *fakeIPAMClient) EnsureBlock(ctx context.Context, args ipam.BlockArgs) (*cnet.IPNet, *cnet.IPNet, error) {
 	panic("not implemented") // TODO: Implement
 }
+
+// ClaimBlocks claims affinity to a host for a batch of blocks.
+func (f *fakeIPAMClient) ClaimBlocks(ctx context.Context, args ipam.ClaimBlocksArgs) ([]cnet.IPNet, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+// GarbageCollect reports, and optionally releases, the leaked handles and orphaned blocks.
+func (f *fakeIPAMClient) GarbageCollect(ctx context.Context, args ipam.GCArgs) (*ipam.GCReport, error) {
+	panic("not implemented") // TODO: Implement
+}
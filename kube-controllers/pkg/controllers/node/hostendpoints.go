@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2021 Tigera, Inc. All rights reserved.
+// Copyright (c) 2020-2024 Tigera, Inc. All rights reserved.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
@@ -34,14 +36,22 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 	"github.com/projectcalico/calico/libcalico-go/lib/resources"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
 )
 
+// invalidHostendpointNameChars matches the characters of interface names that can't be used in
+// the name of a hostendpoint.
+var invalidHostendpointNameChars = regexp.MustCompile("[^a-z0-9-]")
+
 func NewAutoHEPController(c config.NodeControllerConfig, client client.Interface) *autoHostEndpointController {
 	ctrl := &autoHostEndpointController{
 		rl:        workqueue.DefaultControllerRateLimiter(),
 		config:    c,
 		client:    client,
 		nodeCache: make(map[string]*libapi.Node),
+		nodeHeps:  make(map[string]set.Set[string]),
+		templates: newHostendpointTemplates(c.HostEndpointTemplates),
 	}
 	return ctrl
 }
@@ -52,6 +62,74 @@ type autoHostEndpointController struct {
 	client     client.Interface
 	nodeCache  map[string]*libapi.Node
 	syncStatus bapi.SyncStatus
+
+	// nodeHeps tracks the names of the auto hostendpoints of each node, so that the
+	// hostendpoints of interfaces that went away can be deleted without listing all the
+	// hostendpoints.
+	nodeHeps  map[string]set.Set[string]
+	templates []hostendpointTemplate
+}
+
+// hostendpointTemplate is a template of the config with its selector, pattern and CIDRs parsed.
+type hostendpointTemplate struct {
+	api.AutoHostEndpointTemplate
+	nodeSelector     selector.Selector
+	interfacePattern *regexp.Regexp
+	interfaceCIDRs   []*net.IPNet
+}
+
+// newHostendpointTemplates parses the templates of the config.  The templates are validated
+// when the config is written, so a template that doesn't parse is logged and ignored.
+func newHostendpointTemplates(templates []api.AutoHostEndpointTemplate) []hostendpointTemplate {
+	var parsed []hostendpointTemplate
+	for _, t := range templates {
+		logCxt := logrus.WithField("template", t.GenerateName)
+		ht := hostendpointTemplate{AutoHostEndpointTemplate: t}
+		var err error
+		if ht.nodeSelector, err = selector.Parse(t.NodeSelector); err != nil {
+			logCxt.WithError(err).Error("Ignoring hostendpoint template with invalid node selector")
+			continue
+		}
+		if ht.interfacePattern, err = regexp.Compile(t.InterfacePattern); err != nil {
+			logCxt.WithError(err).Error("Ignoring hostendpoint template with invalid interface pattern")
+			continue
+		}
+		for _, cidr := range t.InterfaceCIDRs {
+			_, ipNet, err := net.ParseCIDROrIP(cidr)
+			if err != nil {
+				logCxt.WithError(err).Error("Ignoring hostendpoint template with invalid interface CIDR")
+				break
+			}
+			ht.interfaceCIDRs = append(ht.interfaceCIDRs, ipNet)
+		}
+		if len(ht.interfaceCIDRs) != len(t.InterfaceCIDRs) {
+			continue
+		}
+		parsed = append(parsed, ht)
+	}
+	return parsed
+}
+
+// matchesInterface returns true if the template generates a hostendpoint for the interface.
+func (t *hostendpointTemplate) matchesInterface(iface libapi.NodeInterface) bool {
+	if !t.interfacePattern.MatchString(iface.Name) {
+		return false
+	}
+	if len(t.interfaceCIDRs) == 0 {
+		return true
+	}
+	for _, addr := range iface.Addresses {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		for _, cidr := range t.interfaceCIDRs {
+			if cidr.Contains(ip.IP) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (c *autoHostEndpointController) RegisterWith(f *DataFeed) {
@@ -100,11 +178,17 @@ func (c *autoHostEndpointController) onUpdate(update bapi.Update) {
 				// Try to perform unmapping based on resource name (calico node name).
 				nodeName := update.KVPair.Key.(model.ResourceKey).Name
 				if c.config.AutoHostEndpoints && c.syncStatus == bapi.InSync {
-					hepName := c.generateAutoHostendpointName(nodeName)
-					err := c.deleteHostendpointWithRetries(context.Background(), hepName)
-					if err != nil {
-						logrus.WithError(err).Fatal()
+					hepNames := set.From(c.generateAutoHostendpointName(nodeName))
+					if heps, ok := c.nodeHeps[nodeName]; ok {
+						hepNames.AddSet(heps)
+					}
+					for _, hepName := range hepNames.Slice() {
+						err := c.deleteHostendpointWithRetries(context.Background(), hepName)
+						if err != nil {
+							logrus.WithError(err).Fatal()
+						}
 					}
+					delete(c.nodeHeps, nodeName)
 				}
 			}
 		}
@@ -112,23 +196,45 @@ func (c *autoHostEndpointController) onUpdate(update bapi.Update) {
 }
 
 // deleteAutoHostendpointsWithoutNodes deletes auto hostendpoints that either
-// reference a Calico node that doesn't exist, or, that remain after
-// autoHostEndpoints has been disabled.
+// reference a Calico node that doesn't exist, that remain after
+// autoHostEndpoints has been disabled, or that are no longer generated for
+// their node, for example because the interface or the template went away.
 func (c *autoHostEndpointController) deleteAutoHostendpointsWithoutNodes(ctx context.Context, heps map[string]api.HostEndpoint) error {
+	c.nodeHeps = make(map[string]set.Set[string])
+	expectedHeps := make(map[string]set.Set[string])
 	for _, hep := range heps {
-		_, hepNodeExists := c.nodeCache[hep.Spec.Node]
+		node, hepNodeExists := c.nodeCache[hep.Spec.Node]
 
-		if !hepNodeExists || !c.config.AutoHostEndpoints {
-			err := c.deleteHostendpoint(ctx, hep.Name)
-			if err != nil {
-				logrus.WithError(err).Warnf("failed to delete hostendpoint %q", hep.Name)
-				return err
+		if hepNodeExists && c.config.AutoHostEndpoints {
+			if _, ok := expectedHeps[node.Name]; !ok {
+				expectedHeps[node.Name] = set.New[string]()
+				for _, h := range c.generateAutoHostendpointsFromNode(node) {
+					expectedHeps[node.Name].Add(h.Name)
+				}
+			}
+			if expectedHeps[node.Name].Contains(hep.Name) {
+				c.trackHostendpoint(node.Name, hep.Name)
+				continue
 			}
 		}
+
+		err := c.deleteHostendpoint(ctx, hep.Name)
+		if err != nil {
+			logrus.WithError(err).Warnf("failed to delete hostendpoint %q", hep.Name)
+			return err
+		}
 	}
 	return nil
 }
 
+// trackHostendpoint records that the hostendpoint belongs to the node.
+func (c *autoHostEndpointController) trackHostendpoint(nodeName, hepName string) {
+	if _, ok := c.nodeHeps[nodeName]; !ok {
+		c.nodeHeps[nodeName] = set.New[string]()
+	}
+	c.nodeHeps[nodeName].Add(hepName)
+}
+
 // createUpdateAutohostendpoints creates or updates all auto hostendpoints.
 func (c *autoHostEndpointController) createUpdateAutohostendpoints(ctx context.Context) error {
 	for _, node := range c.nodeCache {
@@ -175,18 +281,46 @@ func (c *autoHostEndpointController) syncAllAutoHostendpoints(ctx context.Contex
 	return fmt.Errorf("too many retries when syncing all hostendpoints")
 }
 
-// syncAutoHostendpoint syncs the auto hostendpoint for the given node.
+// syncAutoHostendpoint syncs the auto hostendpoints for the given node: it
+// creates or updates the expected ones and deletes those that are no longer
+// expected.
 func (c *autoHostEndpointController) syncAutoHostendpoint(ctx context.Context, node *libapi.Node) error {
-	hepName := c.generateAutoHostendpointName(node.Name)
-	logrus.Debugf("syncing hostendpoint %q from node %+v", hepName, node)
+	expectedNames := set.New[string]()
+	for _, expectedHep := range c.generateAutoHostendpointsFromNode(node) {
+		if err := c.syncHostendpoint(ctx, node, expectedHep); err != nil {
+			return err
+		}
+		expectedNames.Add(expectedHep.Name)
+		c.trackHostendpoint(node.Name, expectedHep.Name)
+	}
+
+	if heps, ok := c.nodeHeps[node.Name]; ok {
+		for _, hepName := range heps.Slice() {
+			if expectedNames.Contains(hepName) {
+				continue
+			}
+			if err := c.deleteHostendpoint(ctx, hepName); err != nil {
+				if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+					return err
+				}
+			}
+			heps.Discard(hepName)
+		}
+	}
+	return nil
+}
+
+// syncHostendpoint creates the expected hostendpoint of the node, or updates it if it
+// already exists.
+func (c *autoHostEndpointController) syncHostendpoint(ctx context.Context, node *libapi.Node, expectedHep *api.HostEndpoint) error {
+	logrus.Debugf("syncing hostendpoint %q from node %+v", expectedHep.Name, node)
 
 	// Try getting the host endpoint.
-	expectedHep := c.generateAutoHostendpointFromNode(node)
-	currentHep, err := c.client.HostEndpoints().Get(ctx, hepName, options.GetOptions{})
+	currentHep, err := c.client.HostEndpoints().Get(ctx, expectedHep.Name, options.GetOptions{})
 	if err != nil {
 		switch err.(type) {
 		case errors.ErrorResourceDoesNotExist:
-			if _, err := c.createAutoHostendpoint(ctx, node); err != nil {
+			if _, err := c.createHostendpoint(ctx, expectedHep); err != nil {
 				return err
 			}
 		default:
@@ -276,9 +410,8 @@ func isAutoHostendpoint(h *api.HostEndpoint) bool {
 	return ok && v == hepCreatedLabelValue
 }
 
-// createAutoHostendpoint creates an auto hostendpoint for the specified node.
-func (c *autoHostEndpointController) createAutoHostendpoint(ctx context.Context, n *libapi.Node) (*api.HostEndpoint, error) {
-	hep := c.generateAutoHostendpointFromNode(n)
+// createHostendpoint creates an auto hostendpoint.
+func (c *autoHostEndpointController) createHostendpoint(ctx context.Context, hep *api.HostEndpoint) (*api.HostEndpoint, error) {
 	rlKey := rateLimiterItemKey{Type: RateLimitCalicoCreate, Name: hep.Name}
 
 	time.Sleep(c.rl.When(rlKey))
@@ -296,6 +429,14 @@ func (c *autoHostEndpointController) generateAutoHostendpointName(nodeName strin
 	return fmt.Sprintf("%s-auto-hep", nodeName)
 }
 
+// generateTemplateHostendpointName returns the name of the hostendpoint of a template for
+// an interface.  Interface names can contain characters that names can't, they are replaced
+// with dashes.
+func (c *autoHostEndpointController) generateTemplateHostendpointName(nodeName, generateName, ifaceName string) string {
+	ifaceName = invalidHostendpointNameChars.ReplaceAllString(strings.ToLower(ifaceName), "-")
+	return fmt.Sprintf("%s-%s-%s", nodeName, generateName, ifaceName)
+}
+
 // getAutoHostendpointExpectedIPs returns all of the known IPs on the node resource
 // that should set on the auto hostendpoint.
 func (c *autoHostEndpointController) getAutoHostendpointExpectedIPs(node *libapi.Node) []string {
@@ -345,14 +486,67 @@ func (c *autoHostEndpointController) getAutoHostendpointExpectedIPs(node *libapi
 	return expectedIPs
 }
 
-// generateAutoHostendpointFromNode returns the expected auto hostendpoint to be
-// created from the given node.
-func (c *autoHostEndpointController) generateAutoHostendpointFromNode(node *libapi.Node) *api.HostEndpoint {
-	hepLabels := make(map[string]string, len(node.Labels)+1)
+// generateAutoHostendpointsFromNode returns all the expected auto hostendpoints
+// of the given node: the one for all of its interfaces, if enabled, and one for
+// each interface that matches a template.
+func (c *autoHostEndpointController) generateAutoHostendpointsFromNode(node *libapi.Node) []*api.HostEndpoint {
+	var heps []*api.HostEndpoint
+	if c.config.CreateDefaultHostEndpoint {
+		heps = append(heps, c.generateAutoHostendpointFromNode(node))
+	}
+	for i := range c.templates {
+		t := &c.templates[i]
+		if !t.nodeSelector.Evaluate(node.Labels) {
+			continue
+		}
+		for _, iface := range node.Spec.Interfaces {
+			if t.matchesInterface(iface) {
+				heps = append(heps, c.generateTemplateHostendpoint(node, t, iface))
+			}
+		}
+	}
+	return heps
+}
+
+// generateAutoHostendpointLabels returns the labels of the node, with the
+// given extra labels and the label that marks auto hostendpoints.
+func generateAutoHostendpointLabels(node *libapi.Node, extraLabels map[string]string) map[string]string {
+	hepLabels := make(map[string]string, len(node.Labels)+len(extraLabels)+1)
 	for k, v := range node.Labels {
 		hepLabels[k] = v
 	}
+	for k, v := range extraLabels {
+		hepLabels[k] = v
+	}
 	hepLabels[hepCreatedLabelKey] = hepCreatedLabelValue
+	return hepLabels
+}
+
+// generateTemplateHostendpoint returns the expected hostendpoint of the template
+// for the given interface of the node.
+func (c *autoHostEndpointController) generateTemplateHostendpoint(node *libapi.Node, t *hostendpointTemplate, iface libapi.NodeInterface) *api.HostEndpoint {
+	var expectedIPs []string
+	if len(iface.Addresses) > 0 {
+		expectedIPs = append(expectedIPs, iface.Addresses...)
+	}
+	return &api.HostEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   c.generateTemplateHostendpointName(node.Name, t.GenerateName, iface.Name),
+			Labels: generateAutoHostendpointLabels(node, t.Labels),
+		},
+		Spec: api.HostEndpointSpec{
+			Node:          node.Name,
+			InterfaceName: iface.Name,
+			ExpectedIPs:   expectedIPs,
+			Profiles:      []string{resources.DefaultAllowProfileName},
+		},
+	}
+}
+
+// generateAutoHostendpointFromNode returns the expected auto hostendpoint to be
+// created from the given node for all of its interfaces.
+func (c *autoHostEndpointController) generateAutoHostendpointFromNode(node *libapi.Node) *api.HostEndpoint {
+	hepLabels := generateAutoHostendpointLabels(node, nil)
 
 	return &api.HostEndpoint{
 		ObjectMeta: metav1.ObjectMeta{
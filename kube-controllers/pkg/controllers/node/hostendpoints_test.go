@@ -0,0 +1,190 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/resources"
+)
+
+var _ = Describe("Auto hostendpoint controller UTs", func() {
+	var c *autoHostEndpointController
+	var cli *FakeCalicoClient
+	var cfg config.NodeControllerConfig
+	ctx := context.Background()
+
+	newNode := func(name string, labels map[string]string, ifaces ...libapi.NodeInterface) *libapi.Node {
+		n := libapi.NewNode()
+		n.ObjectMeta = metav1.ObjectMeta{Name: name, Labels: labels}
+		n.Spec.Interfaces = ifaces
+		return n
+	}
+
+	listHeps := func() map[string]apiv3.HostEndpoint {
+		heps, err := cli.HostEndpoints().List(ctx, options.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		m := map[string]apiv3.HostEndpoint{}
+		for _, h := range heps.Items {
+			m[h.Name] = h
+		}
+		return m
+	}
+
+	BeforeEach(func() {
+		cli = NewFakeCalicoClient()
+		cfg = config.NodeControllerConfig{
+			AutoHostEndpoints: true,
+			HostEndpointTemplates: []apiv3.AutoHostEndpointTemplate{{
+				GenerateName:     "data",
+				InterfacePattern: "^eth[1-9]$",
+				NodeSelector:     "has(storage)",
+				Labels:           map[string]string{"network": "data"},
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		c = NewAutoHEPController(cfg, cli)
+	})
+
+	It("should create a hostendpoint for each matching interface of each matching node", func() {
+		c.nodeCache["node-a"] = newNode("node-a", map[string]string{"storage": "ssd"},
+			libapi.NodeInterface{Name: "eth0", Addresses: []string{"10.0.0.1"}},
+			libapi.NodeInterface{Name: "eth1", Addresses: []string{"192.168.0.1", "fd00::1"}},
+		)
+		c.nodeCache["node-b"] = newNode("node-b", nil,
+			libapi.NodeInterface{Name: "eth1", Addresses: []string{"192.168.0.2"}},
+		)
+
+		Expect(c.syncAllAutoHostendpoints(ctx)).To(Succeed())
+		heps := listHeps()
+		Expect(heps).To(HaveLen(1))
+		hep := heps["node-a-data-eth1"]
+		Expect(hep.Labels).To(Equal(map[string]string{
+			"storage":          "ssd",
+			"network":          "data",
+			hepCreatedLabelKey: hepCreatedLabelValue,
+		}))
+		Expect(hep.Spec).To(Equal(apiv3.HostEndpointSpec{
+			Node:          "node-a",
+			InterfaceName: "eth1",
+			ExpectedIPs:   []string{"192.168.0.1", "fd00::1"},
+			Profiles:      []string{resources.DefaultAllowProfileName},
+		}))
+	})
+
+	It("should follow the interfaces and labels of the node", func() {
+		node := newNode("node-a", map[string]string{"storage": "ssd"},
+			libapi.NodeInterface{Name: "eth1", Addresses: []string{"192.168.0.1"}},
+		)
+		c.nodeCache[node.Name] = node
+		Expect(c.syncAllAutoHostendpoints(ctx)).To(Succeed())
+		Expect(listHeps()).To(HaveKey("node-a-data-eth1"))
+
+		By("moving the address to another interface")
+		node = newNode("node-a", map[string]string{"storage": "nvme"},
+			libapi.NodeInterface{Name: "eth2", Addresses: []string{"192.168.0.1"}},
+		)
+		c.nodeCache[node.Name] = node
+		Expect(c.syncAutoHostendpoint(ctx, node)).To(Succeed())
+		heps := listHeps()
+		Expect(heps).To(HaveLen(1))
+		Expect(heps).To(HaveKey("node-a-data-eth2"))
+		Expect(heps["node-a-data-eth2"].Labels).To(HaveKeyWithValue("storage", "nvme"))
+
+		By("removing the label that the template selects")
+		node = newNode("node-a", nil, libapi.NodeInterface{Name: "eth2", Addresses: []string{"192.168.0.1"}})
+		c.nodeCache[node.Name] = node
+		Expect(c.syncAutoHostendpoint(ctx, node)).To(Succeed())
+		Expect(listHeps()).To(BeEmpty())
+	})
+
+	It("should only match interfaces with an address in the CIDRs", func() {
+		cfg.HostEndpointTemplates[0].InterfacePattern = ""
+		cfg.HostEndpointTemplates[0].InterfaceCIDRs = []string{"192.168.0.0/16"}
+		c = NewAutoHEPController(cfg, cli)
+		c.nodeCache["node-a"] = newNode("node-a", map[string]string{"storage": "ssd"},
+			libapi.NodeInterface{Name: "eth0", Addresses: []string{"10.0.0.1"}},
+			libapi.NodeInterface{Name: "bond0.100", Addresses: []string{"fd00::1", "192.168.0.1"}},
+			libapi.NodeInterface{Name: "eth2"},
+		)
+
+		Expect(c.syncAllAutoHostendpoints(ctx)).To(Succeed())
+		heps := listHeps()
+		Expect(heps).To(HaveLen(1))
+		Expect(heps).To(HaveKey("node-a-data-bond0-100"))
+		Expect(heps["node-a-data-bond0-100"].Spec.InterfaceName).To(Equal("bond0.100"))
+	})
+
+	It("should create the default hostendpoint alongside the templates if enabled", func() {
+		cfg.CreateDefaultHostEndpoint = true
+		c = NewAutoHEPController(cfg, cli)
+		c.nodeCache["node-a"] = newNode("node-a", map[string]string{"storage": "ssd"},
+			libapi.NodeInterface{Name: "eth1"},
+		)
+
+		Expect(c.syncAllAutoHostendpoints(ctx)).To(Succeed())
+		heps := listHeps()
+		Expect(heps).To(HaveLen(2))
+		Expect(heps["node-a-auto-hep"].Spec.InterfaceName).To(Equal("*"))
+		Expect(heps["node-a-data-eth1"].Spec.InterfaceName).To(Equal("eth1"))
+	})
+
+	It("should delete the hostendpoints that are no longer generated on resync", func() {
+		stale := &apiv3.HostEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-a-data-eth9",
+				Labels: map[string]string{hepCreatedLabelKey: hepCreatedLabelValue},
+			},
+			Spec: apiv3.HostEndpointSpec{Node: "node-a", InterfaceName: "eth9"},
+		}
+		manual := &apiv3.HostEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a-manual"},
+			Spec:       apiv3.HostEndpointSpec{Node: "node-a", InterfaceName: "eth0"},
+		}
+		_, err := cli.HostEndpoints().Create(ctx, stale, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cli.HostEndpoints().Create(ctx, manual, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		c.nodeCache["node-a"] = newNode("node-a", map[string]string{"storage": "ssd"},
+			libapi.NodeInterface{Name: "eth1"},
+		)
+
+		Expect(c.syncAllAutoHostendpoints(ctx)).To(Succeed())
+		heps := listHeps()
+		Expect(heps).To(HaveLen(2))
+		Expect(heps).To(HaveKey("node-a-data-eth1"))
+		Expect(heps).To(HaveKey("node-a-manual"))
+	})
+
+	It("should ignore invalid templates", func() {
+		cfg.HostEndpointTemplates = append(cfg.HostEndpointTemplates, apiv3.AutoHostEndpointTemplate{
+			GenerateName:     "broken",
+			InterfacePattern: "eth[",
+		})
+		c = NewAutoHEPController(cfg, cli)
+		Expect(c.templates).To(HaveLen(1))
+		Expect(c.templates[0].GenerateName).To(Equal("data"))
+	})
+})
@@ -58,6 +58,9 @@ var (
 	blocksGauges             map[string]*prometheus.GaugeVec
 	gcCandidateGauges        map[string]*prometheus.GaugeVec
 	gcReclamationCounters    map[string]*prometheus.CounterVec
+	gcReleaseFailureCounters map[string]*prometheus.CounterVec
+	allocationCounters       map[string]*prometheus.CounterVec
+	releaseCounters          map[string]*prometheus.CounterVec
 
 	// Single dimension metrics. Legacy metrics are replaced by multidimensional equivalents above. Retain for
 	// backwards compatibility.
@@ -83,6 +86,9 @@ func init() {
 	blocksGauges = make(map[string]*prometheus.GaugeVec)
 	gcCandidateGauges = make(map[string]*prometheus.GaugeVec)
 	gcReclamationCounters = make(map[string]*prometheus.CounterVec)
+	gcReleaseFailureCounters = make(map[string]*prometheus.CounterVec)
+	allocationCounters = make(map[string]*prometheus.CounterVec)
+	releaseCounters = make(map[string]*prometheus.CounterVec)
 
 	// Register the unknown pool explicitly.
 	registerMetricVectorsForPool(unknownPoolLabel)
@@ -422,6 +428,9 @@ func (c *ipamController) onBlockUpdated(kvp model.KVPair) {
 		}
 	}
 
+	// Associate the block with its pool before counting its allocations and releases.
+	c.poolManager.onBlockUpdated(blockCIDR)
+
 	// Update allocations contributed from this block.
 	numAllocationsInBlock := 0
 	currentAllocations := map[string]bool{}
@@ -469,6 +478,7 @@ func (c *ipamController) onBlockUpdated(kvp model.KVPair) {
 			c.allocationsByNode[node][alloc.id()] = &alloc
 		}
 		c.handleTracker.setAllocation(&alloc)
+		c.incrementAllocationMetric(allocationCounters, &alloc)
 		log.WithFields(alloc.fields()).Debug("New IP allocation")
 	}
 
@@ -504,11 +514,11 @@ func (c *ipamController) onBlockUpdated(kvp model.KVPair) {
 
 			// And to be safe, remove from confirmed leaks just in case.
 			delete(c.confirmedLeaks, id)
+
+			c.incrementAllocationMetric(releaseCounters, alloc)
 		}
 	}
 
-	c.poolManager.onBlockUpdated(blockCIDR)
-
 	// Finally, update the raw storage.
 	c.allBlocks[blockCIDR] = kvp
 }
@@ -527,6 +537,7 @@ func (c *ipamController) onBlockDeleted(key model.BlockKey) {
 		if len(c.allocationsByNode[node]) == 0 {
 			delete(c.allocationsByNode, node)
 		}
+		c.incrementAllocationMetric(releaseCounters, alloc)
 	}
 	delete(c.allocationsByBlock, blockCIDR)
 
@@ -891,6 +902,13 @@ func (c *ipamController) allocationIsValid(a *allocation, preferCache bool) bool
 		return false
 	}
 
+	// Sticky addresses belong to the pod rather than to its container, and are kept while the pod
+	// exists, even if it is restarted on another node or has yet to be given the address again.
+	if a.isSticky() {
+		logc.Debug("Pod of sticky IP exists, allocation is valid")
+		return true
+	}
+
 	// The pod exists - check if it is still on the original node.
 	// TODO: Do we need this check?
 	if p.Spec.NodeName != "" && a.knode != "" && p.Spec.NodeName != a.knode {
@@ -1042,6 +1060,7 @@ func (c *ipamController) garbageCollectIPs() error {
 			continue
 		} else if err != nil {
 			logc.WithError(err).WithField("handle", a.handle).Warning("Failed to release leaked IP")
+			c.incrementPoolCounter(gcReleaseFailureCounters, a.block, a.node())
 			return err
 		}
 
@@ -1070,7 +1089,7 @@ func (c *ipamController) cleanupNode(cnode string) error {
 		return err
 	}
 
-	clearReclaimedIPCountForNode(cnode)
+	clearCountersForNode(cnode)
 
 	logc.Debug("Released all affinities for node")
 	return nil
@@ -1147,16 +1166,30 @@ func (c *ipamController) kubernetesNodeForCalico(cnode string) (string, error) {
 }
 
 func (c *ipamController) incrementReclamationMetric(block string, node string) {
+	c.incrementPoolCounter(gcReclamationCounters, block, node)
+}
+
+// incrementAllocationMetric counts an allocation or release that the controller observed.  Allocations
+// and releases are only counted once the syncer is in sync, so that the existing allocations are not
+// counted as new each time the controller starts.
+func (c *ipamController) incrementAllocationMetric(countersByPool map[string]*prometheus.CounterVec, a *allocation) {
+	if c.syncStatus != bapi.InSync {
+		return
+	}
+	c.incrementPoolCounter(countersByPool, a.block, a.node())
+}
+
+func (c *ipamController) incrementPoolCounter(countersByPool map[string]*prometheus.CounterVec, block string, node string) {
 	pool := c.poolManager.poolsByBlock[block]
 	if node == "" {
 		node = unknownNodeLabel
 	}
-	gcReclamationsCounter := gcReclamationCounters[pool]
-	if gcReclamationsCounter == nil {
-		log.Warnf("Reclamation count metric vector used for pool %s was not created, skipping publishing", pool)
+	poolCounter := countersByPool[pool]
+	if poolCounter == nil {
+		log.Warnf("Counter metric vector used for pool %s was not created, skipping publishing", pool)
 		return
 	}
-	gcReclamationsCounter.With(prometheus.Labels{"node": node}).Inc()
+	poolCounter.With(prometheus.Labels{"node": node}).Inc()
 }
 
 func registerMetricVectorsForPool(poolName string) {
@@ -1199,6 +1232,30 @@ func registerMetricVectorsForPool(poolName string) {
 		ConstLabels: prometheus.Labels{"ippool": poolName},
 	}, []string{"node"})
 	prometheus.MustRegister(gcReclamationCounters[poolName])
+
+	gcReleaseFailureCounters[poolName] = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ipam_allocations_gc_release_failures",
+		Help: "The total attempts of the garbage collector to reclaim an allocation that failed. Failed " +
+			"reclamations are retried.",
+		ConstLabels: prometheus.Labels{"ippool": poolName},
+	}, []string{"node"})
+	prometheus.MustRegister(gcReleaseFailureCounters[poolName])
+
+	allocationCounters[poolName] = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ipam_allocations_created",
+		Help: "The total IPs that have been allocated in IPAM over time. Allocations that existed when " +
+			"kube-controllers started are not counted.",
+		ConstLabels: prometheus.Labels{"ippool": poolName},
+	}, []string{"node"})
+	prometheus.MustRegister(allocationCounters[poolName])
+
+	releaseCounters[poolName] = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ipam_allocations_released",
+		Help: "The total IPs that have been released in IPAM over time, whether by their owner or by the " +
+			"garbage collector.",
+		ConstLabels: prometheus.Labels{"ippool": poolName},
+	}, []string{"node"})
+	prometheus.MustRegister(releaseCounters[poolName])
 }
 
 func unregisterMetricVectorsForPool(poolName string) {
@@ -1226,6 +1283,21 @@ func unregisterMetricVectorsForPool(poolName string) {
 		prometheus.Unregister(gcReclamationCounters[poolName])
 		delete(gcReclamationCounters, poolName)
 	}
+
+	if _, ok := gcReleaseFailureCounters[poolName]; ok {
+		prometheus.Unregister(gcReleaseFailureCounters[poolName])
+		delete(gcReleaseFailureCounters, poolName)
+	}
+
+	if _, ok := allocationCounters[poolName]; ok {
+		prometheus.Unregister(allocationCounters[poolName])
+		delete(allocationCounters, poolName)
+	}
+
+	if _, ok := releaseCounters[poolName]; ok {
+		prometheus.Unregister(releaseCounters[poolName])
+		delete(releaseCounters, poolName)
+	}
 }
 
 // Creates map used to index gauge values by node, and seeds with zeroes to create explicit zero values rather than
@@ -1273,9 +1345,16 @@ func clearPoolSizeMetric(poolName string) {
 }
 
 // When we stop tracking a node, clear counters to prevent accumulation of stale metrics.
-func clearReclaimedIPCountForNode(node string) {
-	for _, reclamationCounter := range gcReclamationCounters {
-		reclamationCounter.Delete(prometheus.Labels{"node": node})
+func clearCountersForNode(node string) {
+	for _, countersByPool := range []map[string]*prometheus.CounterVec{
+		gcReclamationCounters,
+		gcReleaseFailureCounters,
+		allocationCounters,
+		releaseCounters,
+	} {
+		for _, counter := range countersByPool {
+			counter.Delete(prometheus.Labels{"node": node})
+		}
 	}
 }
 
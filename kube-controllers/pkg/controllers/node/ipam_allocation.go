@@ -221,6 +221,11 @@ func (a *allocation) isPodIP() bool {
 	return ns != "" && pod != ""
 }
 
+// isSticky returns whether the allocation is kept across restarts of its pod.
+func (a *allocation) isSticky() bool {
+	return a.attrs[ipam.AttributeSticky] == "true"
+}
+
 func (a *allocation) isTunnelAddress() bool {
 	ipip := a.attrs[ipam.AttributeType] == ipam.AttributeTypeIPIP
 	vxlan := a.attrs[ipam.AttributeType] == ipam.AttributeTypeVXLAN
@@ -1067,6 +1067,80 @@ var _ = Describe("IPAM controller UTs", func() {
 		})
 	})
 
+	It("should keep sticky IPs while their pod exists", func() {
+		// Create Calico and k8s nodes for the test.
+		n := libapiv3.Node{}
+		n.Name = "cnode"
+		n.Spec.OrchRefs = []libapiv3.OrchRef{{NodeName: "kname", Orchestrator: apiv3.OrchestratorKubernetes}}
+		_, err := cli.Nodes().Create(context.TODO(), &n, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		kn := v1.Node{}
+		kn.Name = "kname"
+		_, err = cs.CoreV1().Nodes().Create(context.TODO(), &kn, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var node *v1.Node
+		Eventually(nodes).WithTimeout(time.Second).Should(Receive(&node))
+
+		// Create the pod of the sticky IP, restarted on another node without an IP yet.
+		pod := v1.Pod{}
+		pod.Name = "test-pod"
+		pod.Namespace = "test-namespace"
+		pod.Spec.NodeName = "kname2"
+		pod.Status.PodIP = "10.0.0.1"
+		pod.Status.PodIPs = []v1.PodIP{{IP: "10.0.0.1"}}
+		_, err = cs.CoreV1().Pods(pod.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var gotPod *v1.Pod
+		Eventually(pods).WithTimeout(time.Second).Should(Receive(&gotPod))
+
+		idx := 0
+		handle := "k8s-pod-network.sticky.test-namespace.test-pod"
+		cidr := net.MustParseCIDR("10.0.0.0/30")
+		aff := "host:cnode"
+		b := model.AllocationBlock{
+			CIDR:        cidr,
+			Affinity:    &aff,
+			Allocations: []*int{&idx, nil, nil, nil},
+			Unallocated: []int{1, 2, 3},
+			Attributes: []model.AllocationAttribute{
+				{
+					AttrPrimary: &handle,
+					AttrSecondary: map[string]string{
+						ipam.AttributeNode:      "cnode",
+						ipam.AttributePod:       pod.Name,
+						ipam.AttributeNamespace: pod.Namespace,
+						ipam.AttributeSticky:    "true",
+					},
+				},
+			},
+		}
+		kvp := model.KVPair{Key: model.BlockKey{CIDR: cidr}, Value: &b}
+		c.onUpdate(bapi.Update{KVPair: kvp, UpdateType: bapi.UpdateTypeKVNew})
+
+		// Start the controller.
+		c.Start(stopChan)
+		Eventually(func() bool {
+			done := c.pause()
+			defer done()
+			_, ok := c.allBlocks[cidr.String()]
+			return ok
+		}, 1*time.Second, 100*time.Millisecond).Should(BeTrue())
+		c.onStatusUpdate(bapi.InSync)
+
+		// The sticky IP is kept, although the pod is on another node with another IP.
+		fakeClient := cli.IPAM().(*fakeIPAMClient)
+		Consistently(func() bool {
+			return fakeClient.handlesReleased[handle]
+		}, assertionTimeout, 500*time.Millisecond).Should(BeFalse())
+
+		// Deleting the pod releases the sticky IP.
+		err = cs.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() bool {
+			return fakeClient.handlesReleased[handle]
+		}, assertionTimeout, 100*time.Millisecond).Should(BeTrue())
+	})
+
 	It("should not clean up leaked addresses if no grace period set", func() {
 		// Set the controller's grace period to 0.
 		c.config.LeakGracePeriod = &metav1.Duration{Duration: 0 * time.Second}
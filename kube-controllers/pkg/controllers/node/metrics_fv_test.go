@@ -221,6 +221,7 @@ var _ = Describe("kube-controllers metrics FV tests", func() {
 				// Block gauges, GC reclamation count, and legacy allocation gauges should be absent.
 				`ipam_blocks_`,
 				`ipam_allocations_gc_reclamations`,
+				`ipam_allocations_created`,
 				`ipam_allocations_per_node`,
 				`ipam_allocations_borrowed_per_node`,
 			},
@@ -271,9 +272,15 @@ var _ = Describe("kube-controllers metrics FV tests", func() {
 				`ipam_blocks_per_node{node="node-a"} 2`,
 				`ipam_blocks_per_node{node="node-b"} 2`,
 				`ipam_blocks_per_node{node="node-c"} 1`,
+				`ipam_allocations_created{ippool="test-ippool-1",node="node-a"} 4`,
+				`ipam_allocations_created{ippool="test-ippool-2",node="node-a"} 1`,
+				`ipam_allocations_created{ippool="test-ippool-1",node="node-b"} 1`,
+				`ipam_allocations_created{ippool="test-ippool-3",node="node-b"} 1`,
+				`ipam_allocations_created{ippool="test-ippool-1",node="node-c"} 1`,
 			},
 			[]string{
 				`ipam_allocations_gc_reclamations`,
+				`ipam_allocations_released`,
 			},
 			kubeControllers.IP,
 			5*time.Second,
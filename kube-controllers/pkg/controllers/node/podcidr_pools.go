@@ -0,0 +1,268 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+const (
+	// podCIDRPoolNodeAnnotation records the Kubernetes node that a pod CIDR pool was created for.
+	podCIDRPoolNodeAnnotation = "projectcalico.org/pod-cidr-node"
+
+	hostnameLabel = "kubernetes.io/hostname"
+
+	rateLimitPodCIDRPoolSync = "pod-cidr-pool-sync"
+)
+
+// NewPodCIDRPoolController creates the controller that mirrors the podCIDRs of the Kubernetes nodes
+// into IP pools, for clusters where a cloud controller assigns the podCIDRs and routes them to the
+// nodes.  Each pool selects only its own node, so that the node's pods are assigned addresses from
+// its podCIDRs, as they would be with host-local IPAM.
+func NewPodCIDRPoolController(cfg config.NodeControllerConfig, c client.Interface, nodeIndexer cache.Indexer) *podCIDRPoolController {
+	return &podCIDRPoolController{
+		rl:           workqueue.DefaultControllerRateLimiter(),
+		config:       cfg,
+		client:       c,
+		nodeIndexer:  nodeIndexer,
+		syncChan:     make(chan interface{}, 1),
+		nodePoolKeys: map[string]string{},
+	}
+}
+
+type podCIDRPoolController struct {
+	rl          workqueue.RateLimiter
+	config      config.NodeControllerConfig
+	client      client.Interface
+	nodeIndexer cache.Indexer
+
+	// syncChan triggers a sync of all the pod CIDR pools.
+	syncChan chan interface{}
+
+	// nodePoolKeys maps each node to a summary of its pools, so that updates to a node that
+	// don't affect its pools don't trigger a sync.
+	lock         sync.Mutex
+	nodePoolKeys map[string]string
+}
+
+// OnKubernetesNodeUpdate is called when a Kubernetes node is created or updated.
+func (c *podCIDRPoolController) OnKubernetesNodeUpdate(obj interface{}) {
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		return
+	}
+	var key string
+	for _, pool := range podCIDRPoolsForNode(node) {
+		key += pool.Spec.CIDR + "/" + pool.Spec.NodeSelector + ","
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if prev, ok := c.nodePoolKeys[node.Name]; ok && prev == key {
+		return
+	}
+	c.nodePoolKeys[node.Name] = key
+	kick(c.syncChan)
+}
+
+// OnKubernetesNodeDeleted is called when a Kubernetes node is deleted.
+func (c *podCIDRPoolController) OnKubernetesNodeDeleted() {
+	kick(c.syncChan)
+}
+
+// Start syncs the pod CIDR pools in the background.  It must be called after the node informer
+// has synced.  The controller always runs, even if pod CIDR pools are disabled, since it is
+// responsible for deleting the pools that it created when they were enabled.
+func (c *podCIDRPoolController) Start(stopCh chan struct{}) {
+	kick(c.syncChan)
+	go func() {
+		for {
+			select {
+			case <-c.syncChan:
+				if err := c.syncAllPodCIDRPools(context.Background()); err != nil {
+					log.WithError(err).Warn("Failed to sync pod CIDR pools, will retry")
+					time.AfterFunc(c.rl.When(rateLimitPodCIDRPoolSync), func() { kick(c.syncChan) })
+					continue
+				}
+				c.rl.Forget(rateLimitPodCIDRPoolSync)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// syncAllPodCIDRPools creates, updates and deletes pod CIDR pools so that there is one for each
+// podCIDR of each node.
+func (c *podCIDRPoolController) syncAllPodCIDRPools(ctx context.Context) error {
+	expected := map[string]*api.IPPool{}
+	nodeNames := map[string]bool{}
+	for _, obj := range c.nodeIndexer.List() {
+		node, ok := obj.(*v1.Node)
+		if !ok {
+			continue
+		}
+		nodeNames[node.Name] = true
+		if !c.config.PodCIDRPools {
+			continue
+		}
+		for _, pool := range podCIDRPoolsForNode(node) {
+			expected[pool.Name] = pool
+		}
+	}
+
+	// Forget the nodes that have been deleted.
+	c.lock.Lock()
+	for name := range c.nodePoolKeys {
+		if !nodeNames[name] {
+			delete(c.nodePoolKeys, name)
+		}
+	}
+	c.lock.Unlock()
+
+	pools, err := c.client.IPPools().List(ctx, options.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("could not list IP pools: %w", err)
+	}
+	current := map[string]*api.IPPool{}
+	for i := range pools.Items {
+		if isPodCIDRPool(&pools.Items[i]) {
+			current[pools.Items[i].Name] = &pools.Items[i]
+		}
+	}
+
+	var storedErr error
+	for name, pool := range current {
+		exp, ok := expected[name]
+		if ok && exp.Spec.CIDR == pool.Spec.CIDR && exp.Spec.BlockSize == pool.Spec.BlockSize {
+			continue
+		}
+		// The CIDR and block size of a pool can't be changed, so the pool is replaced if the
+		// node's podCIDRs change.
+		logc := log.WithFields(log.Fields{"pool": name, "cidr": pool.Spec.CIDR})
+		logc.Info("Deleting pod CIDR pool")
+		if _, err := c.client.IPPools().Delete(ctx, name, options.DeleteOptions{}); err != nil {
+			if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+				logc.WithError(err).Warn("Failed to delete pod CIDR pool")
+				storedErr = err
+				continue
+			}
+		}
+		delete(current, name)
+	}
+
+	for name, exp := range expected {
+		logc := log.WithFields(log.Fields{"pool": name, "cidr": exp.Spec.CIDR})
+		pool, ok := current[name]
+		if !ok {
+			logc.Info("Creating pod CIDR pool")
+			if _, err := c.client.IPPools().Create(ctx, exp, options.SetOptions{}); err != nil {
+				// This fails if the podCIDRs overlap another IP pool.
+				logc.WithError(err).Warn("Failed to create pod CIDR pool")
+				storedErr = err
+			}
+			continue
+		}
+		// Only the node selector is kept in sync, so that the other settings of the pool, such
+		// as its encapsulation and NAT, can be changed by the user.
+		if pool.Spec.NodeSelector == exp.Spec.NodeSelector {
+			continue
+		}
+		logc.Info("Updating pod CIDR pool")
+		pool.Spec.NodeSelector = exp.Spec.NodeSelector
+		if _, err := c.client.IPPools().Update(ctx, pool, options.SetOptions{}); err != nil {
+			logc.WithError(err).Warn("Failed to update pod CIDR pool")
+			storedErr = err
+		}
+	}
+	return storedErr
+}
+
+// isPodCIDRPool determines if the given IP pool is managed by this controller.
+func isPodCIDRPool(p *api.IPPool) bool {
+	if p.Labels[hepCreatedLabelKey] != hepCreatedLabelValue {
+		return false
+	}
+	_, ok := p.Annotations[podCIDRPoolNodeAnnotation]
+	return ok
+}
+
+// podCIDRPoolsForNode returns the pod CIDR pools for the podCIDRs of the given node.
+func podCIDRPoolsForNode(node *v1.Node) []*api.IPPool {
+	podCIDRs := node.Spec.PodCIDRs
+	if len(podCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		podCIDRs = []string{node.Spec.PodCIDR}
+	}
+
+	// The pool selects the node by its hostname label, which is copied to the Calico node.
+	hostname := node.Labels[hostnameLabel]
+	if hostname == "" {
+		hostname = node.Name
+	}
+
+	var pools []*api.IPPool
+	for _, podCIDR := range podCIDRs {
+		_, cidr, err := cnet.ParseCIDR(podCIDR)
+		if err != nil {
+			log.WithError(err).WithField("node", node.Name).Warnf("Ignoring invalid podCIDR %q", podCIDR)
+			continue
+		}
+		ones, _ := cidr.Mask.Size()
+
+		pool := api.NewIPPool()
+		pool.Name = fmt.Sprintf("%s-pod-cidr-v%d", node.Name, cidr.Version())
+		pool.Labels = map[string]string{hepCreatedLabelKey: hepCreatedLabelValue}
+		pool.Annotations = map[string]string{podCIDRPoolNodeAnnotation: node.Name}
+		pool.Spec = api.IPPoolSpec{
+			CIDR:         cidr.String(),
+			BlockSize:    podCIDRBlockSize(ones, cidr.Version()),
+			IPIPMode:     api.IPIPModeNever,
+			VXLANMode:    api.VXLANModeNever,
+			NATOutgoing:  true,
+			NodeSelector: fmt.Sprintf("%s == '%s'", hostnameLabel, hostname),
+			AllowedUses:  []api.IPPoolAllowedUse{api.IPPoolAllowedUseWorkload, api.IPPoolAllowedUseTunnel},
+		}
+		pools = append(pools, pool)
+	}
+	return pools
+}
+
+// podCIDRBlockSize returns the block size for a pod CIDR pool.  The pool only serves one node,
+// so the blocks are made as large as IPAM allows, up to the whole podCIDR.
+func podCIDRBlockSize(prefixLen int, ipVersion int) int {
+	minBlockSize := 20
+	if ipVersion == 6 {
+		minBlockSize = 116
+	}
+	if prefixLen < minBlockSize {
+		return minBlockSize
+	}
+	return prefixLen
+}
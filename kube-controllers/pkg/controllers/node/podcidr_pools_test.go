@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var _ = Describe("Pod CIDR pool controller UTs", func() {
+	var c *podCIDRPoolController
+	var cli *FakeCalicoClient
+	var nodes cache.Indexer
+	var cfg config.NodeControllerConfig
+
+	newNode := func(name string, podCIDRs ...string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/hostname": name + "-host"},
+			},
+			Spec: v1.NodeSpec{PodCIDRs: podCIDRs},
+		}
+	}
+
+	listPools := func() map[string]apiv3.IPPoolSpec {
+		pools, err := cli.IPPools().List(context.Background(), options.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		specs := map[string]apiv3.IPPoolSpec{}
+		for _, p := range pools.Items {
+			specs[p.Name] = p.Spec
+		}
+		return specs
+	}
+
+	BeforeEach(func() {
+		cli = NewFakeCalicoClient()
+		nodes = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		cfg = config.NodeControllerConfig{PodCIDRPools: true}
+	})
+
+	JustBeforeEach(func() {
+		c = NewPodCIDRPoolController(cfg, cli, nodes)
+	})
+
+	It("should create a pool for each podCIDR of each node", func() {
+		Expect(nodes.Add(newNode("node-a", "10.0.1.0/24", "fd00:0:0:1::/64"))).To(Succeed())
+		Expect(nodes.Add(newNode("node-b", "10.0.2.0/24"))).To(Succeed())
+
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+		Expect(listPools()).To(Equal(map[string]apiv3.IPPoolSpec{
+			"node-a-pod-cidr-v4": {
+				CIDR:         "10.0.1.0/24",
+				BlockSize:    24,
+				IPIPMode:     apiv3.IPIPModeNever,
+				VXLANMode:    apiv3.VXLANModeNever,
+				NATOutgoing:  true,
+				NodeSelector: "kubernetes.io/hostname == 'node-a-host'",
+				AllowedUses:  []apiv3.IPPoolAllowedUse{apiv3.IPPoolAllowedUseWorkload, apiv3.IPPoolAllowedUseTunnel},
+			},
+			"node-a-pod-cidr-v6": {
+				CIDR:         "fd00:0:0:1::/64",
+				BlockSize:    116,
+				IPIPMode:     apiv3.IPIPModeNever,
+				VXLANMode:    apiv3.VXLANModeNever,
+				NATOutgoing:  true,
+				NodeSelector: "kubernetes.io/hostname == 'node-a-host'",
+				AllowedUses:  []apiv3.IPPoolAllowedUse{apiv3.IPPoolAllowedUseWorkload, apiv3.IPPoolAllowedUseTunnel},
+			},
+			"node-b-pod-cidr-v4": {
+				CIDR:         "10.0.2.0/24",
+				BlockSize:    24,
+				IPIPMode:     apiv3.IPIPModeNever,
+				VXLANMode:    apiv3.VXLANModeNever,
+				NATOutgoing:  true,
+				NodeSelector: "kubernetes.io/hostname == 'node-b-host'",
+				AllowedUses:  []apiv3.IPPoolAllowedUse{apiv3.IPPoolAllowedUseWorkload, apiv3.IPPoolAllowedUseTunnel},
+			},
+		}))
+	})
+
+	It("should replace the pool when the podCIDR changes and delete it with the node", func() {
+		node := newNode("node-a", "10.0.1.0/24")
+		Expect(nodes.Add(node)).To(Succeed())
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+
+		node = newNode("node-a", "10.0.3.0/24")
+		Expect(nodes.Update(node)).To(Succeed())
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+		Expect(listPools()).To(HaveKey("node-a-pod-cidr-v4"))
+		Expect(listPools()["node-a-pod-cidr-v4"].CIDR).To(Equal("10.0.3.0/24"))
+
+		Expect(nodes.Delete(node)).To(Succeed())
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+		Expect(listPools()).To(BeEmpty())
+	})
+
+	It("should keep the user's changes to the pool", func() {
+		Expect(nodes.Add(newNode("node-a", "10.0.1.0/24"))).To(Succeed())
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+
+		pool, err := cli.IPPools().Get(context.Background(), "node-a-pod-cidr-v4", options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		pool.Spec.NATOutgoing = false
+		_, err = cli.IPPools().Update(context.Background(), pool, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+		Expect(listPools()["node-a-pod-cidr-v4"].NATOutgoing).To(BeFalse())
+	})
+
+	It("should only trigger a sync when the pools of a node change", func() {
+		node := newNode("node-a", "10.0.1.0/24")
+		c.OnKubernetesNodeUpdate(node)
+		Expect(c.syncChan).To(HaveLen(1))
+		<-c.syncChan
+
+		c.OnKubernetesNodeUpdate(node)
+		Expect(c.syncChan).To(HaveLen(0))
+
+		node.Labels["kubernetes.io/hostname"] = "other-host"
+		c.OnKubernetesNodeUpdate(node)
+		Expect(c.syncChan).To(HaveLen(1))
+	})
+
+	Context("with pod CIDR pools disabled", func() {
+		BeforeEach(func() {
+			cfg.PodCIDRPools = false
+		})
+
+		It("should delete its pools but no others", func() {
+			Expect(nodes.Add(newNode("node-a", "10.0.1.0/24"))).To(Succeed())
+			pool := apiv3.NewIPPool()
+			pool.Name = "node-a-pod-cidr-v4"
+			pool.Labels = map[string]string{hepCreatedLabelKey: hepCreatedLabelValue}
+			pool.Annotations = map[string]string{podCIDRPoolNodeAnnotation: "node-a"}
+			pool.Spec.CIDR = "10.0.1.0/24"
+			_, err := cli.IPPools().Create(context.Background(), pool, options.SetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			other := apiv3.NewIPPool()
+			other.Name = "default-ipv4-ippool"
+			other.Spec.CIDR = "192.168.0.0/16"
+			_, err = cli.IPPools().Create(context.Background(), other, options.SetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.syncAllPodCIDRPools(context.Background())).To(Succeed())
+			Expect(listPools()).To(HaveLen(1))
+			Expect(listPools()).To(HaveKey("default-ipv4-ippool"))
+		})
+	})
+})
@@ -27,6 +27,7 @@ type DatastoreType string
 const (
 	EtcdV3              DatastoreType = "etcdv3"
 	Kubernetes          DatastoreType = "kubernetes"
+	Postgres            DatastoreType = "postgres"
 	KindCalicoAPIConfig               = "CalicoAPIConfig"
 )
 
@@ -46,6 +47,8 @@ type CalicoAPIConfigSpec struct {
 	EtcdConfig
 	// Inline the k8s config fields.
 	KubeConfig
+	// Inline the SQL config fields.
+	SQLConfig
 }
 
 type EtcdConfig struct {
@@ -86,6 +89,15 @@ type KubeConfig struct {
 	K8sCurrentContext string `json:"k8sCurrentContext" envconfig:"K8S_CURRENT_CONTEXT" default:""`
 }
 
+// SQLConfig contains the connection information for the SQL datastore drivers.
+type SQLConfig struct {
+	// SQLDriverName is the name of the database/sql driver, which the binary must import.
+	SQLDriverName string `json:"sqlDriverName" envconfig:"SQL_DRIVER_NAME" default:""`
+	// SQLDataSourceName is the driver-specific data source name, for example a PostgreSQL
+	// connection URL.
+	SQLDataSourceName string `json:"sqlDataSourceName" envconfig:"SQL_DATA_SOURCE_NAME" default:""`
+}
+
 // NewCalicoAPIConfig creates a new (zeroed) CalicoAPIConfig struct with the
 // TypeMetadata initialised to the current version.
 func NewCalicoAPIConfig() *CalicoAPIConfig {
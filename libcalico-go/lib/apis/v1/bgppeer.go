@@ -17,6 +17,7 @@ package v1
 import (
 	"fmt"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/apis/v1/unversioned"
@@ -71,6 +72,9 @@ type BGPPeerMetadata struct {
 type BGPPeerSpec struct {
 	// The AS Number of the peer.
 	ASNumber numorstring.ASNumber `json:"asNumber"`
+
+	// Optional BGP password for the peerings, referencing a secret.
+	Password *apiv3.BGPPassword `json:"password,omitempty" validate:"omitempty"`
 }
 
 // NewBGPPeer creates a new (zeroed) BGPPeer struct with the TypeMetadata initialised to the current
@@ -69,6 +69,10 @@ type NodeSpec struct {
 
 	// Addresses list address that a client can reach the node at.
 	Addresses []NodeAddress `json:"addresses,omitempty" validate:"omitempty"`
+
+	// Interfaces lists the host interfaces of this node and their addresses, as reported by
+	// Felix.  It is used to generate the host endpoints of the node from templates.
+	Interfaces []NodeInterface `json:"interfaces,omitempty" validate:"omitempty,dive"`
 }
 
 // NodeAddress represents an address assigned to a node.
@@ -80,6 +84,15 @@ type NodeAddress struct {
 	Type string `json:"type,omitempty" validate:"omitempty,ipType"`
 }
 
+// NodeInterface represents a host interface of a node.
+type NodeInterface struct {
+	// Name is the name of the interface.
+	Name string `json:"name" validate:"interface"`
+
+	// Addresses are the addresses assigned to the interface.
+	Addresses []string `json:"addresses,omitempty" validate:"omitempty,dive,ip"`
+}
+
 type NodeStatus struct {
 	// WireguardPublicKey is the IPv4 Wireguard public-key for this node.
 	// wireguardPublicKey validates if the string is a valid base64 encoded key.
@@ -479,6 +479,27 @@ func (in *NodeBGPSpec) DeepCopy() *NodeBGPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInterface) DeepCopyInto(out *NodeInterface) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInterface.
+func (in *NodeInterface) DeepCopy() *NodeInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeList) DeepCopyInto(out *NodeList) {
 	*out = *in
@@ -535,6 +556,13 @@ func (in *NodeSpec) DeepCopyInto(out *NodeSpec) {
 		*out = make([]NodeAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NodeInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -21,6 +21,7 @@ import (
 	"context"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
 )
 
 // SyncStatus represents the overall state of the datastore.
@@ -124,6 +125,35 @@ type Client interface {
 	//Close()
 }
 
+// ListPager is implemented by the backend clients that can List a page at a time, so that a
+// large List doesn't need to hold all the results in memory.
+type ListPager interface {
+	// ListPage is List, but returns up to limit KVPairs, and the token to pass as
+	// continueToken to get the next page.  The limit is a hint: a page may be shorter, even
+	// empty, while there are more pages.  A limit of 0 or less returns all the remaining
+	// KVPairs.  All the pages of a List are consistent with the revision of the first page;
+	// the revision is ignored for the other pages.
+	ListPage(ctx context.Context, list model.ListInterface, revision string, limit int64, continueToken string) (*model.KVPairList, error)
+}
+
+// ListPaged calls ListPage if the client is a ListPager.  Otherwise, it calls List, which
+// returns all the KVPairs in a single page.
+func ListPaged(ctx context.Context, c Client, list model.ListInterface, revision string, limit int64, continueToken string) (*model.KVPairList, error) {
+	if p, ok := c.(ListPager); ok {
+		return p.ListPage(ctx, list, revision, limit, continueToken)
+	}
+	if continueToken != "" {
+		return nil, cerrors.ErrorValidation{
+			ErroredFields: []cerrors.ErroredField{{
+				Name:   "Continue",
+				Value:  continueToken,
+				Reason: "the datastore does not support paged lists",
+			}},
+		}
+	}
+	return c.List(ctx, list, revision)
+}
+
 type Syncer interface {
 	// Starts the Syncer.  May start a background goroutine.
 	Start()
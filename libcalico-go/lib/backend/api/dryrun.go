@@ -0,0 +1,47 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+type dryRunKey struct{}
+
+// ContextWithDryRun returns a context that requests a dry run of any write issued with it: the
+// datastore runs its full validation and admission logic and returns the resulting object, but
+// does not persist it.  Backends that cannot honor a dry run reject the write with an
+// ErrorOperationNotSupported rather than persisting it.
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun returns true if the context was created by ContextWithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	v, _ := ctx.Value(dryRunKey{}).(bool)
+	return v
+}
+
+// DryRunNotSupported returns the error returned by a backend that is asked to dry run an
+// operation it can only perform for real.
+func DryRunNotSupported(operation string, identifier interface{}) error {
+	return cerrors.ErrorOperationNotSupported{
+		Operation:  operation,
+		Identifier: identifier,
+		Reason:     "dry run is not supported by this datastore",
+	}
+}
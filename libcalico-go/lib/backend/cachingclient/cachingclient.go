@@ -0,0 +1,276 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachingclient implements a read-through cache in front of a backend client, for the
+// hot paths that read the same keys again and again, such as config resolution.
+package cachingclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var (
+	counterCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "calico_client_cache_hits",
+		Help: "Number of datastore reads served by the client cache.",
+	})
+	counterCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "calico_client_cache_misses",
+		Help: "Number of cacheable datastore reads that the client cache passed to the datastore.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		counterCacheHits,
+		counterCacheMisses,
+	)
+}
+
+// defaultWatchRetryInterval is the time between the attempts to restart a failed watch.
+const defaultWatchRetryInterval = 5 * time.Second
+
+// Client is a backend client that caches the results of Get for the resource types that it
+// watches.  The watches invalidate the cached keys as they change in the datastore, and the
+// writes through the client invalidate the keys they write, so reads see the writes.  While the
+// watch of a type is down, the reads of that type go to the datastore.
+//
+// Get returns a copy of the cached values that are Kubernetes objects, the other values are
+// shared with the cache and must not be modified.  All the other calls go to the datastore.
+type Client struct {
+	api.Client
+
+	resources          []model.ListInterface
+	watchRetryInterval time.Duration
+
+	lock sync.Mutex
+	// entries maps the default paths of the cached keys to their values, nil for the keys that do
+	// not exist.
+	entries map[string]*model.KVPair
+	// watching records which of the resource types are watched, and so cached.
+	watching []bool
+	// epoch increases with each invalidation, so that a read started before an invalidation does
+	// not fill the cache with a stale value.
+	epoch uint64
+}
+
+// New returns a client that caches the reads of the given resource types from the backend
+// client.  Nothing is cached until Start is called.
+func New(client api.Client, resources ...model.ListInterface) *Client {
+	return &Client{
+		Client:             client,
+		resources:          resources,
+		watchRetryInterval: defaultWatchRetryInterval,
+		entries:            map[string]*model.KVPair{},
+		watching:           make([]bool, len(resources)),
+	}
+}
+
+// Start starts the watches of the resource types.  They run, and the cache is used, until the
+// context is done.
+func (c *Client) Start(ctx context.Context) {
+	for i := range c.resources {
+		go c.watchLoop(ctx, i)
+	}
+}
+
+// Get returns the key from the cache if it is cached, or from the datastore otherwise.  Reads
+// of a specific revision always go to the datastore.
+func (c *Client) Get(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	if revision != "" {
+		return c.Client.Get(ctx, key, revision)
+	}
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return c.Client.Get(ctx, key, revision)
+	}
+
+	c.lock.Lock()
+	idx := c.resourceOf(path)
+	if idx < 0 || !c.watching[idx] {
+		c.lock.Unlock()
+		return c.Client.Get(ctx, key, revision)
+	}
+	if kvp, ok := c.entries[path]; ok {
+		c.lock.Unlock()
+		counterCacheHits.Inc()
+		if kvp == nil {
+			return nil, cerrors.ErrorResourceDoesNotExist{Identifier: key}
+		}
+		return copyKVPair(kvp), nil
+	}
+	epoch := c.epoch
+	c.lock.Unlock()
+
+	counterCacheMisses.Inc()
+	kvp, err := c.Client.Get(ctx, key, revision)
+	if err != nil && !errors.Is(err, cerrors.ErrNotFound) {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	if c.epoch == epoch && c.watching[idx] {
+		c.entries[path] = kvp
+	}
+	c.lock.Unlock()
+
+	if kvp == nil {
+		return nil, err
+	}
+	return copyKVPair(kvp), nil
+}
+
+func (c *Client) ListPage(ctx context.Context, list model.ListInterface, revision string, limit int64, continueToken string) (*model.KVPairList, error) {
+	return api.ListPaged(ctx, c.Client, list, revision, limit, continueToken)
+}
+
+func (c *Client) Create(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	defer c.invalidate(object.Key)
+	return c.Client.Create(ctx, object)
+}
+
+func (c *Client) Update(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	defer c.invalidate(object.Key)
+	return c.Client.Update(ctx, object)
+}
+
+func (c *Client) Apply(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	defer c.invalidate(object.Key)
+	return c.Client.Apply(ctx, object)
+}
+
+func (c *Client) Delete(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	defer c.invalidate(key)
+	return c.Client.Delete(ctx, key, revision)
+}
+
+func (c *Client) DeleteKVP(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	defer c.invalidate(object.Key)
+	return c.Client.DeleteKVP(ctx, object)
+}
+
+func (c *Client) Clean() error {
+	defer c.flush(-1)
+	return c.Client.Clean()
+}
+
+// watchLoop keeps a watch of a resource type running until the context is done, restarting it
+// when it fails.  The resource type is cached while the watch runs.
+func (c *Client) watchLoop(ctx context.Context, idx int) {
+	logCxt := log.WithField("resource", c.resources[idx])
+	for ctx.Err() == nil {
+		w, err := c.Client.Watch(ctx, c.resources[idx], "")
+		if err != nil {
+			logCxt.WithError(err).Warn("Failed to watch resource for the client cache")
+		} else {
+			logCxt.Debug("Caching resource")
+			c.setWatching(idx, true)
+			c.processEvents(ctx, w)
+			w.Stop()
+			c.setWatching(idx, false)
+			logCxt.Debug("Stopped caching resource")
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.watchRetryInterval):
+		}
+	}
+}
+
+// processEvents invalidates the keys of the events of the watch, until the watch fails or the
+// context is done.
+func (c *Client) processEvents(ctx context.Context, w api.WatchInterface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type == api.WatchError {
+				log.WithError(event.Error).Info("Client cache watch failed, will restart it")
+				return
+			}
+			if event.Old != nil {
+				c.invalidate(event.Old.Key)
+			}
+			if event.New != nil {
+				c.invalidate(event.New.Key)
+			}
+		}
+	}
+}
+
+// setWatching records whether a resource type is watched.  Its keys are flushed either way:
+// a new watch has no record of the changes before it started.
+func (c *Client) setWatching(idx int, watching bool) {
+	c.lock.Lock()
+	c.watching[idx] = watching
+	c.lock.Unlock()
+	c.flush(idx)
+}
+
+// flush drops the cached keys of a resource type, or all of them if the index is negative.
+func (c *Client) flush(idx int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.epoch++
+	for path := range c.entries {
+		if idx < 0 || c.resourceOf(path) == idx {
+			delete(c.entries, path)
+		}
+	}
+}
+
+func (c *Client) invalidate(key model.Key) {
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.epoch++
+	delete(c.entries, path)
+}
+
+// resourceOf returns the index of the resource type of the path, or -1 if it isn't cached.
+func (c *Client) resourceOf(path string) int {
+	for i, r := range c.resources {
+		if r.KeyFromDefaultPath(path) != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyKVPair(kvp *model.KVPair) *model.KVPair {
+	cp := *kvp
+	if obj, ok := kvp.Value.(runtime.Object); ok {
+		cp.Value = obj.DeepCopyObject()
+	}
+	return &cp
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachingclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("Caching client", func() {
+	var backend *fakeBackend
+	var client *Client
+	var cancel context.CancelFunc
+
+	logLevel := model.GlobalConfigKey{Name: "LogSeverityScreen"}
+	missing := model.GlobalConfigKey{Name: "Missing"}
+	node := model.HostConfigKey{Hostname: "node1", Name: "LogSeverityScreen"}
+
+	BeforeEach(func() {
+		backend = &fakeBackend{
+			kvps:     map[model.Key]*model.KVPair{},
+			watchers: make(chan *fakeWatcher, 10),
+		}
+		backend.set(logLevel, "Info")
+		backend.set(node, "Debug")
+		client = New(backend, model.GlobalConfigListOptions{})
+		client.watchRetryInterval = 10 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+
+	start := func() *fakeWatcher {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		client.Start(ctx)
+		var w *fakeWatcher
+		Eventually(backend.watchers).Should(Receive(&w))
+		Eventually(client.isWatching).Should(BeTrue())
+		return w
+	}
+
+	get := func(key model.Key) interface{} {
+		kvp, err := client.Get(context.Background(), key, "")
+		if err != nil {
+			return err
+		}
+		return kvp.Value
+	}
+
+	It("should read from the datastore before it is started", func() {
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(backend.numGets()).To(Equal(2))
+	})
+
+	It("should only read the watched resources once", func() {
+		start()
+		hits := testutil.ToFloat64(counterCacheHits)
+		misses := testutil.ToFloat64(counterCacheMisses)
+
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(get(missing)).To(BeAssignableToTypeOf(cerrors.ErrorResourceDoesNotExist{}))
+		Expect(get(missing)).To(BeAssignableToTypeOf(cerrors.ErrorResourceDoesNotExist{}))
+		Expect(backend.numGets()).To(Equal(2))
+
+		// The host config is not watched.
+		Expect(get(node)).To(Equal("Debug"))
+		Expect(get(node)).To(Equal("Debug"))
+		Expect(backend.numGets()).To(Equal(4))
+
+		Expect(testutil.ToFloat64(counterCacheHits) - hits).To(Equal(2.0))
+		Expect(testutil.ToFloat64(counterCacheMisses) - misses).To(Equal(2.0))
+	})
+
+	It("should invalidate the keys that the watch reports", func() {
+		w := start()
+		Expect(get(logLevel)).To(Equal("Info"))
+
+		backend.set(logLevel, "Warning")
+		w.events <- api.WatchEvent{
+			Type: api.WatchModified,
+			New:  &model.KVPair{Key: logLevel, Value: "Warning"},
+		}
+		Eventually(func() interface{} { return get(logLevel) }).Should(Equal("Warning"))
+	})
+
+	It("should invalidate the keys that it writes", func() {
+		start()
+		Expect(get(logLevel)).To(Equal("Info"))
+
+		_, err := client.Apply(context.Background(), &model.KVPair{Key: logLevel, Value: "Error"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(logLevel)).To(Equal("Error"))
+
+		_, err = client.Delete(context.Background(), logLevel, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(logLevel)).To(BeAssignableToTypeOf(cerrors.ErrorResourceDoesNotExist{}))
+	})
+
+	It("should read from the datastore while the watch is down", func() {
+		w := start()
+		Expect(get(logLevel)).To(Equal("Info"))
+
+		w.events <- api.WatchEvent{Type: api.WatchError, Error: errors.New("watch failed")}
+		var w2 *fakeWatcher
+		Eventually(backend.watchers).Should(Receive(&w2))
+
+		// The cache is flushed, and filled again once the new watch runs.
+		Eventually(client.isWatching).Should(BeTrue())
+		gets := backend.numGets()
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(get(logLevel)).To(Equal("Info"))
+		Expect(backend.numGets()).To(Equal(gets + 1))
+	})
+})
+
+// isWatching returns whether the client caches the resource, for the tests.
+func (c *Client) isWatching() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.watching[0]
+}
+
+type fakeBackend struct {
+	api.Client
+
+	lock     sync.Mutex
+	kvps     map[model.Key]*model.KVPair
+	gets     int
+	watchers chan *fakeWatcher
+}
+
+func (b *fakeBackend) set(key model.Key, value interface{}) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.kvps[key] = &model.KVPair{Key: key, Value: value}
+}
+
+func (b *fakeBackend) numGets() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.gets
+}
+
+func (b *fakeBackend) Get(_ context.Context, key model.Key, _ string) (*model.KVPair, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.gets++
+	kvp, ok := b.kvps[key]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: key}
+	}
+	return kvp, nil
+}
+
+func (b *fakeBackend) Apply(_ context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	b.set(kvp.Key, kvp.Value)
+	return kvp, nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, key model.Key, _ string) (*model.KVPair, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	kvp := b.kvps[key]
+	delete(b.kvps, key)
+	return kvp, nil
+}
+
+func (b *fakeBackend) Watch(_ context.Context, _ model.ListInterface, _ string) (api.WatchInterface, error) {
+	w := &fakeWatcher{events: make(chan api.WatchEvent)}
+	b.watchers <- w
+	return w, nil
+}
+
+type fakeWatcher struct {
+	events chan api.WatchEvent
+}
+
+func (w *fakeWatcher) Stop() {}
+
+func (w *fakeWatcher) ResultChan() <-chan api.WatchEvent {
+	return w.events
+}
+
+func (w *fakeWatcher) HasTerminated() bool {
+	return false
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2016 Tigera, Inc. All rights reserved.
+// Copyright (c) 2016-2024 Tigera, Inc. All rights reserved.
 
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,6 +16,7 @@ package backend
 
 import (
 	"fmt"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 
@@ -25,17 +26,48 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s"
 )
 
-// NewClient creates a new backend datastore client.
-func NewClient(config apiconfig.CalicoAPIConfig) (c bapi.Client, err error) {
+// DriverFactory creates a backend datastore client from the client config.
+type DriverFactory func(config apiconfig.CalicoAPIConfig) (bapi.Client, error)
+
+var (
+	driversLock sync.RWMutex
+	drivers     = map[apiconfig.DatastoreType]DriverFactory{}
+)
+
+func init() {
+	RegisterDriver(apiconfig.EtcdV3, func(config apiconfig.CalicoAPIConfig) (bapi.Client, error) {
+		return etcdv3.NewEtcdV3Client(&config.Spec.EtcdConfig)
+	})
+	RegisterDriver(apiconfig.Kubernetes, func(config apiconfig.CalicoAPIConfig) (bapi.Client, error) {
+		return k8s.NewKubeClient(&config.Spec)
+	})
+}
+
+// RegisterDriver makes a datastore driver available to NewClient for the given datastore type.
+// Drivers normally register themselves from an init function, so that importing the driver's
+// package is enough to enable it.  RegisterDriver panics if the datastore type already has a
+// driver.
+func RegisterDriver(datastoreType apiconfig.DatastoreType, factory DriverFactory) {
+	driversLock.Lock()
+	defer driversLock.Unlock()
+	if factory == nil {
+		panic("backend: RegisterDriver factory is nil")
+	}
+	if _, ok := drivers[datastoreType]; ok {
+		panic(fmt.Sprintf("backend: RegisterDriver called twice for datastore type %q", datastoreType))
+	}
+	drivers[datastoreType] = factory
+}
+
+// NewClient creates a new backend datastore client, using the driver registered for the
+// datastore type of the config.
+func NewClient(config apiconfig.CalicoAPIConfig) (bapi.Client, error) {
 	log.Debugf("Using datastore type '%s'", config.Spec.DatastoreType)
-	switch config.Spec.DatastoreType {
-	case apiconfig.EtcdV3:
-		c, err = etcdv3.NewEtcdV3Client(&config.Spec.EtcdConfig)
-	case apiconfig.Kubernetes:
-		c, err = k8s.NewKubeClient(&config.Spec)
-	default:
-		err = fmt.Errorf("unknown datastore type: %v",
-			config.Spec.DatastoreType)
+	driversLock.RLock()
+	factory, ok := drivers[config.Spec.DatastoreType]
+	driversLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown datastore type: %v", config.Spec.DatastoreType)
 	}
-	return
+	return factory(config)
 }
@@ -0,0 +1,60 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+)
+
+var _ = Describe("Datastore drivers", func() {
+	It("should create the clients of the registered drivers", func() {
+		var received apiconfig.CalicoAPIConfig
+		expected := &fakeClient{}
+		backend.RegisterDriver("fake", func(config apiconfig.CalicoAPIConfig) (bapi.Client, error) {
+			received = config
+			return expected, nil
+		})
+
+		config := apiconfig.NewCalicoAPIConfig()
+		config.Spec.DatastoreType = "fake"
+		config.Spec.SQLDataSourceName = "fake://datastore"
+		c, err := backend.NewClient(*config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeIdenticalTo(expected))
+		Expect(received.Spec.SQLDataSourceName).To(Equal("fake://datastore"))
+
+		Expect(func() {
+			backend.RegisterDriver("fake", func(apiconfig.CalicoAPIConfig) (bapi.Client, error) {
+				return nil, nil
+			})
+		}).To(Panic())
+	})
+
+	It("should reject an unknown datastore type", func() {
+		config := apiconfig.NewCalicoAPIConfig()
+		config.Spec.DatastoreType = "unknown"
+		_, err := backend.NewClient(*config)
+		Expect(err).To(MatchError("unknown datastore type: unknown"))
+	})
+})
+
+type fakeClient struct {
+	bapi.Client
+}
@@ -17,6 +17,8 @@ package etcdv3
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -149,6 +151,9 @@ func NewEtcdV3Client(config *apiconfig.EtcdConfig) (api.Client, error) {
 func (c *etcdV3Client) Create(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
 	logCxt := log.WithFields(log.Fields{"model-etcdKey": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
 	logCxt.Debug("Processing Create request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Create", d.Key)
+	}
 
 	key, value, err := getKeyValueStrings(d)
 	if err != nil {
@@ -190,7 +195,7 @@ func (c *etcdV3Client) Create(ctx context.Context, d *model.KVPair) (*model.KVPa
 
 	v, err := model.ParseValue(d.Key, []byte(value))
 	if err != nil {
-		return nil, cerrors.ErrorPartialFailure{Err: fmt.Errorf("Unexpected error parsing stored datastore entry '%v': %+v", value, err)}
+		return nil, cerrors.ErrorPartialFailure{Err: fmt.Errorf("Unexpected error parsing stored datastore entry '%v': %w", value, err)}
 	}
 	d.Value = v
 	d.Revision = strconv.FormatInt(txnResp.Header.Revision, 10)
@@ -204,6 +209,9 @@ func (c *etcdV3Client) Create(ctx context.Context, d *model.KVPair) (*model.KVPa
 func (c *etcdV3Client) Update(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
 	logCxt := log.WithFields(log.Fields{"model-etcdKey": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
 	logCxt.Debug("Processing Update request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Update", d.Key)
+	}
 	key, value, err := getKeyValueStrings(d)
 	if err != nil {
 		return nil, err
@@ -267,6 +275,9 @@ func (c *etcdV3Client) Update(ctx context.Context, d *model.KVPair) (*model.KVPa
 func (c *etcdV3Client) Apply(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
 	logCxt := log.WithFields(log.Fields{"etcdKey": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
 	logCxt.Debug("Processing Apply request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Apply", d.Key)
+	}
 	key, value, err := getKeyValueStrings(d)
 	if err != nil {
 		return nil, err
@@ -300,6 +311,9 @@ func (c *etcdV3Client) DeleteKVP(ctx context.Context, kvp *model.KVPair) (*model
 func (c *etcdV3Client) Delete(ctx context.Context, k model.Key, revision string) (*model.KVPair, error) {
 	logCxt := log.WithFields(log.Fields{"model-etcdKey": k, "rev": revision})
 	logCxt.Debug("Processing Delete request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Delete", k)
+	}
 	key, err := model.KeyToDefaultDeletePath(k)
 	if err != nil {
 		return nil, err
@@ -448,6 +462,108 @@ func (c *etcdV3Client) List(ctx context.Context, l model.ListInterface, revision
 	}, nil
 }
 
+// ListPage lists the entries in the datastore a page at a time.  The continue token holds the
+// revision of the first page and the key to continue from; the pages after the first one read
+// at that revision, so they fail once etcd compacts it.
+func (c *etcdV3Client) ListPage(ctx context.Context, l model.ListInterface, revision string, limit int64, continueToken string) (*model.KVPairList, error) {
+	logCxt := log.WithFields(log.Fields{"list-interface": l, "rev": revision, "limit": limit, "continue": continueToken})
+	logCxt.Debug("Processing ListPage request")
+
+	key, ops := calculateListKeyAndOptions(logCxt, l)
+	if len(ops) == 0 {
+		// An exact Get returns at most one entry, there is nothing to page.
+		logCxt.Debug("List is fully qualified, listing as a single page")
+		return c.List(ctx, l, revision)
+	}
+	logCxt = logCxt.WithField("etcdv3-etcdKey", key)
+
+	// The range starts at the key of the list, or where the previous page stopped.
+	start := key
+	var rev int64
+	var err error
+	if continueToken != "" {
+		if rev, start, err = parseContinueToken(continueToken, key); err != nil {
+			return nil, err
+		}
+	} else if len(revision) != 0 {
+		if rev, err = parseRevision(revision); err != nil {
+			return nil, err
+		}
+	}
+	ops = []clientv3.OpOption{clientv3.WithRange(clientv3.GetPrefixRangeEnd(key))}
+	if rev != 0 {
+		ops = append(ops, clientv3.WithRev(rev))
+	}
+	if limit > 0 {
+		ops = append(ops, clientv3.WithLimit(limit))
+	}
+
+	logCxt.Debug("Calling Get on etcdv3 client")
+	resp, err := c.etcdClient.Get(ctx, start, ops...)
+	if err != nil {
+		logCxt.WithError(err).Debug("Error returned from etcdv3 client")
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	if rev == 0 {
+		rev = resp.Header.Revision
+	}
+	logCxt.WithFields(log.Fields{"numResults": len(resp.Kvs), "more": resp.More}).Debug("Processing response from etcdv3")
+
+	list := []*model.KVPair{}
+	for _, p := range resp.Kvs {
+		if kv := convertListResponse(p, l); kv != nil {
+			list = append(list, kv)
+		}
+	}
+
+	// The statically defined default-allow profile comes first, on the first page.
+	if continueToken == "" && (key == profilesKey || key == defaultAllowProfileKey) {
+		list = append([]*model.KVPair{resources.DefaultAllowProfile()}, list...)
+	}
+
+	kvps := &model.KVPairList{
+		KVPairs:  list,
+		Revision: strconv.FormatInt(rev, 10),
+	}
+	if resp.More && len(resp.Kvs) > 0 {
+		// The next page starts just after the last key of this one.
+		kvps.Continue = newContinueToken(rev, string(resp.Kvs[len(resp.Kvs)-1].Key)+"\x00")
+	}
+	return kvps, nil
+}
+
+// listContinueToken is the content of the continue token of a paged List.
+type listContinueToken struct {
+	Revision int64  `json:"rev"`
+	Start    string `json:"start"`
+}
+
+func newContinueToken(rev int64, start string) string {
+	b, _ := json.Marshal(listContinueToken{Revision: rev, Start: start})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parseContinueToken returns the revision and the start key of a continue token, which must
+// be a token of a List of the given key.
+func parseContinueToken(continueToken, key string) (int64, string, error) {
+	var t listContinueToken
+	b, err := base64.RawURLEncoding.DecodeString(continueToken)
+	if err == nil {
+		err = json.Unmarshal(b, &t)
+	}
+	if err != nil || t.Revision <= 0 || !strings.HasPrefix(t.Start, key) {
+		log.WithField("Continue", continueToken).Debug("Unable to parse continue token")
+		return 0, "", cerrors.ErrorValidation{
+			ErroredFields: []cerrors.ErroredField{{
+				Name:   "Continue",
+				Value:  continueToken,
+				Reason: "invalid continue token",
+			}},
+		}
+	}
+	return t.Revision, t.Start, nil
+}
+
 func calculateListKeyAndOptions(logCxt *log.Entry, l model.ListInterface) (string, []clientv3.OpOption) {
 	// -  If the final name segment of the name is itself a prefix, then just perform a prefix Get
 	//    using the constructed key.
@@ -33,6 +33,13 @@ const (
 	// AnnotationPodIPs is similar for the plural PodIPs field.
 	AnnotationPodIPs = "cni.projectcalico.org/podIPs"
 
+	// AnnotationInterfaceIPsPrefix is the prefix of the annotations that hold the IPs of the secondary
+	// interfaces of a pod that the CNI plugin configured, for example when Calico is a Multus
+	// secondary network.  The full annotation is the prefix followed by the name of the interface in
+	// the pod, for example "cni.projectcalico.org/podIPs.net1".  Like AnnotationPodIPs, it is set to
+	// the empty string when the interface is removed.
+	AnnotationInterfaceIPsPrefix = AnnotationPodIPs + "."
+
 	// AnnotationPodIPs is the annotation set by the Amazon VPC CNI plugin.
 	AnnotationAWSPodIPs = "vpc.amazonaws.com/pod-ips"
 
@@ -41,6 +48,13 @@ const (
 	// on older Pods.
 	AnnotationContainerID = "cni.projectcalico.org/containerID"
 
+	// AnnotationLabelPrefix is the prefix of the pod annotations that add labels to the workload
+	// endpoints of a pod, for use in policy selectors.  The annotation
+	// "labels.cni.projectcalico.org/<name>: <value>" adds the label "<name>: <value>".  Setting
+	// an annotation on a pod needs the same permissions as setting a label on it.  Pod labels
+	// take precedence over these labels, as do the labels that Calico adds itself.
+	AnnotationLabelPrefix = "labels.cni.projectcalico.org/"
+
 	// NameLabel is a label that can be used to match a serviceaccount or namespace
 	// name exactly.
 	NameLabel = "projectcalico.org/name"
@@ -68,6 +68,15 @@ var _ = Describe("Test parsing strings", func() {
 		Expect(name).To(Equal("eni82111e10a96"))
 	})
 
+	It("should generate a distinct veth name for each interface of a workload", func() {
+		Expect(c.VethNameForWorkloadInterface("namespace", "podname", "eth0")).To(Equal(c.VethNameForWorkload("namespace", "podname")))
+		net1 := c.VethNameForWorkloadInterface("namespace", "podname", "net1")
+		Expect(net1).To(HavePrefix("cali"))
+		Expect(net1).To(HaveLen(15))
+		Expect(net1).NotTo(Equal(c.VethNameForWorkload("namespace", "podname")))
+		Expect(net1).NotTo(Equal(c.VethNameForWorkloadInterface("namespace", "podname", "net2")))
+	})
+
 	It("should parse valid profile names", func() {
 		name := "kns.default"
 		ns, err := c.ProfileNameToNamespace(name)
@@ -955,6 +964,39 @@ var _ = Describe("Test Pod conversion", func() {
 		Expect(wep.Value.(*libapiv3.WorkloadEndpoint).Spec.InterfaceName).To(Equal("cali92cf1f5e9f6"))
 	})
 
+	It("should add the labels of the label annotations to the WorkloadEndpoint", func() {
+		pod := kapiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podB",
+				Namespace: "default",
+				Labels: map[string]string{
+					"app": "frontend",
+				},
+				Annotations: map[string]string{
+					"labels.cni.projectcalico.org/team":    "payments",
+					"labels.cni.projectcalico.org/app":     "backend",
+					"labels.cni.projectcalico.org/invalid": "not a label value",
+					"example.com/deployment":               "blue",
+				},
+			},
+			Spec: kapiv1.PodSpec{
+				NodeName: "nodeA",
+			},
+			Status: kapiv1.PodStatus{
+				PodIP: "192.168.0.1",
+			},
+		}
+
+		wep, err := podToWorkloadEndpoint(c, &pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wep.Value.(*libapiv3.WorkloadEndpoint).ObjectMeta.Labels).To(Equal(map[string]string{
+			"app":                            "frontend",
+			"team":                           "payments",
+			"projectcalico.org/namespace":    "default",
+			"projectcalico.org/orchestrator": "k8s",
+		}))
+	})
+
 	It("should not parse a Pod with no NodeName", func() {
 		pod := kapiv1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
@@ -1177,6 +1219,72 @@ var _ = Describe("Test Pod conversion", func() {
 	})
 })
 
+var _ = Describe("Test Pod conversion with secondary interfaces", func() {
+	c := NewConverter()
+
+	pod := func(annotations map[string]string) *kapiv1.Pod {
+		return &kapiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "podA",
+				Namespace:   "default",
+				Labels:      map[string]string{"app": "db"},
+				Annotations: annotations,
+			},
+			Spec: kapiv1.PodSpec{
+				NodeName: "nodeA",
+			},
+			Status: kapiv1.PodStatus{
+				PodIP: "192.168.0.1",
+			},
+		}
+	}
+
+	It("should convert each secondary interface to a WorkloadEndpoint", func() {
+		weps, err := c.PodToWorkloadEndpoints(pod(map[string]string{
+			"cni.projectcalico.org/floatingIPs": `["1.1.1.1"]`,
+			"cni.projectcalico.org/podIPs.net2": "10.2.0.5/32,fd00:2::5/128",
+			"cni.projectcalico.org/podIPs.net1": "10.1.0.5",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(weps).To(HaveLen(3))
+
+		primary := weps[0].Value.(*libapiv3.WorkloadEndpoint)
+		Expect(primary.Name).To(Equal("nodeA-k8s-podA-eth0"))
+		Expect(primary.Spec.IPNetworks).To(Equal([]string{"192.168.0.1/32"}))
+		Expect(primary.Spec.IPNATs).To(HaveLen(1))
+
+		net1 := weps[1].Value.(*libapiv3.WorkloadEndpoint)
+		Expect(weps[1].Key.(model.ResourceKey).Name).To(Equal("nodeA-k8s-podA-net1"))
+		Expect(net1.Name).To(Equal("nodeA-k8s-podA-net1"))
+		Expect(net1.Spec.Endpoint).To(Equal("net1"))
+		Expect(net1.Spec.InterfaceName).To(Equal(c.VethNameForWorkloadInterface("default", "podA", "net1")))
+		Expect(net1.Spec.IPNetworks).To(Equal([]string{"10.1.0.5/32"}))
+		Expect(net1.Spec.IPNATs).To(BeEmpty())
+		Expect(net1.Spec.Profiles).To(Equal(primary.Spec.Profiles))
+		Expect(net1.Labels).To(Equal(primary.Labels))
+
+		net2 := weps[2].Value.(*libapiv3.WorkloadEndpoint)
+		Expect(net2.Spec.Endpoint).To(Equal("net2"))
+		Expect(net2.Spec.IPNetworks).To(Equal([]string{"10.2.0.5/32", "fd00:2::5/128"}))
+	})
+
+	It("should keep a removed secondary interface without IPs", func() {
+		weps, err := c.PodToWorkloadEndpoints(pod(map[string]string{
+			"cni.projectcalico.org/podIPs.net1": "",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(weps).To(HaveLen(2))
+		Expect(weps[1].Value.(*libapiv3.WorkloadEndpoint).Spec.IPNetworks).To(BeEmpty())
+	})
+
+	It("should return an error for a bad secondary interface IP", func() {
+		_, err := c.PodToWorkloadEndpoints(pod(map[string]string{
+			"cni.projectcalico.org/podIPs.net1": "10.1.0",
+		}))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("Test UID conversion", func() {
 	It("should parse a UID to a Calico ID", func() {
 		By("Converting a UID")
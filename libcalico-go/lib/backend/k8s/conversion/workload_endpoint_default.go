@@ -19,11 +19,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	kapiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
@@ -35,15 +37,32 @@ import (
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
 )
 
+// defaultEndpointName is the name of the interface of the default workload endpoint of a pod.
+const defaultEndpointName = "eth0"
+
 type defaultWorkloadEndpointConverter struct{}
 
 // VethNameForWorkload returns a deterministic veth name
 // for the given Kubernetes workload (WEP) name and namespace.
 func (wc defaultWorkloadEndpointConverter) VethNameForWorkload(namespace, podname string) string {
+	return vethNameForHashInput(fmt.Sprintf("%s.%s", namespace, podname))
+}
+
+// VethNameForWorkloadInterface returns a deterministic veth name for the given interface
+// of a Kubernetes workload.  The default interface, eth0, keeps the name returned by
+// VethNameForWorkload so that existing workloads are unaffected.
+func (wc defaultWorkloadEndpointConverter) VethNameForWorkloadInterface(namespace, podname, endpoint string) string {
+	if endpoint == "" || endpoint == defaultEndpointName {
+		return wc.VethNameForWorkload(namespace, podname)
+	}
+	return vethNameForHashInput(fmt.Sprintf("%s.%s.%s", namespace, podname, endpoint))
+}
+
+func vethNameForHashInput(input string) string {
 	// A SHA1 is always 20 bytes long, and so is sufficient for generating the
 	// veth name and mac addr.
 	h := sha1.New()
-	h.Write([]byte(fmt.Sprintf("%s.%s", namespace, podname)))
+	h.Write([]byte(input))
 	prefix := os.Getenv("FELIX_INTERFACEPREFIX")
 	if prefix == "" {
 		// Prefix is not set. Default to "cali"
@@ -63,7 +82,76 @@ func (wc defaultWorkloadEndpointConverter) PodToWorkloadEndpoints(pod *kapiv1.Po
 		return nil, err
 	}
 
-	return []*model.KVPair{wep}, nil
+	secondaryWEPs, err := wc.podToSecondaryWorkloadEndpoints(pod, wep)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]*model.KVPair{wep}, secondaryWEPs...), nil
+}
+
+// podToSecondaryWorkloadEndpoints returns a WorkloadEndpoint for each secondary interface of the
+// Pod that the CNI plugin configured, as recorded in the annotations with the
+// AnnotationInterfaceIPsPrefix prefix.  The secondary endpoints share the labels, profiles and
+// ports of the default endpoint, so that policy applies to every interface of the Pod, but each
+// has its own IPs and host-side interface.
+func (wc defaultWorkloadEndpointConverter) podToSecondaryWorkloadEndpoints(pod *kapiv1.Pod, defaultWEP *model.KVPair) ([]*model.KVPair, error) {
+	var endpoints []string
+	for annotation := range pod.Annotations {
+		endpoint, ok := strings.CutPrefix(annotation, AnnotationInterfaceIPsPrefix)
+		if ok && endpoint != "" && endpoint != defaultEndpointName {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	// Sort the endpoints so that the conversion is deterministic.
+	sort.Strings(endpoints)
+
+	var kvps []*model.KVPair
+	for _, endpoint := range endpoints {
+		wepids := names.WorkloadEndpointIdentifiers{
+			Node:         pod.Spec.NodeName,
+			Orchestrator: apiv3.OrchestratorKubernetes,
+			Endpoint:     endpoint,
+			Pod:          pod.Name,
+		}
+		wepName, err := wepids.CalculateWorkloadEndpointName(false)
+		if err != nil {
+			return nil, err
+		}
+
+		// As for the default endpoint, the IPs of a finished Pod have been released.  The annotation
+		// is emptied when the interface is removed, which leaves the endpoint without IPs.
+		ipNets := []string{}
+		if ips := pod.Annotations[AnnotationInterfaceIPsPrefix+endpoint]; ips != "" && !IsFinished(pod) {
+			for _, ip := range strings.Split(ips, ",") {
+				_, ipNet, err := cnet.ParseCIDROrIP(ip)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse IP %q of interface %s: %w", ip, endpoint, err)
+				}
+				ipNets = append(ipNets, ipNet.String())
+			}
+		}
+
+		wep := defaultWEP.Value.(*libapiv3.WorkloadEndpoint).DeepCopy()
+		wep.Name = wepName
+		wep.Annotations = nil
+		wep.Spec.Endpoint = endpoint
+		wep.Spec.InterfaceName = wc.VethNameForWorkloadInterface(pod.Namespace, pod.Name, endpoint)
+		wep.Spec.IPNetworks = ipNets
+		// Floating IPs are NATted to the IPs of the default interface only.
+		wep.Spec.IPNATs = nil
+
+		kvps = append(kvps, &model.KVPair{
+			Key: model.ResourceKey{
+				Name:      wepName,
+				Namespace: pod.Namespace,
+				Kind:      libapiv3.KindWorkloadEndpoint,
+			},
+			Value:    wep,
+			Revision: pod.ResourceVersion,
+		})
+	}
+	return kvps, nil
 }
 
 // PodToWorkloadEndpoint converts a Pod to a WorkloadEndpoint.  It assumes the calling code
@@ -86,7 +174,7 @@ func (wc defaultWorkloadEndpointConverter) podToDefaultWorkloadEndpoint(pod *kap
 	wepids := names.WorkloadEndpointIdentifiers{
 		Node:         pod.Spec.NodeName,
 		Orchestrator: apiv3.OrchestratorKubernetes,
-		Endpoint:     "eth0",
+		Endpoint:     defaultEndpointName,
 		Pod:          pod.Name,
 	}
 	wepName, err := wepids.CalculateWorkloadEndpointName(false)
@@ -125,6 +213,7 @@ func (wc defaultWorkloadEndpointConverter) podToDefaultWorkloadEndpoint(pod *kap
 	if labels == nil {
 		labels = make(map[string]string, 2)
 	}
+	addAnnotationLabels(pod, labels)
 	labels[apiv3.LabelNamespace] = pod.Namespace
 	labels[apiv3.LabelOrchestrator] = apiv3.OrchestratorKubernetes
 
@@ -239,7 +328,7 @@ func (wc defaultWorkloadEndpointConverter) podToDefaultWorkloadEndpoint(pod *kap
 		Node:                       pod.Spec.NodeName,
 		Pod:                        pod.Name,
 		ContainerID:                containerID,
-		Endpoint:                   "eth0",
+		Endpoint:                   defaultEndpointName,
 		InterfaceName:              interfaceName,
 		Profiles:                   profiles,
 		IPNetworks:                 ipNets,
@@ -292,3 +381,27 @@ func HandleSourceIPSpoofingAnnotation(annot map[string]string) ([]string, error)
 	}
 	return sourcePrefixes, nil
 }
+
+// addAnnotationLabels adds the labels of the AnnotationLabelPrefix annotations of the pod to the
+// given labels, unless the labels already have them.  Annotations whose values are not valid
+// label values are ignored.
+func addAnnotationLabels(pod *kapiv1.Pod, labels map[string]string) {
+	for k, v := range pod.Annotations {
+		if !strings.HasPrefix(k, AnnotationLabelPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(k, AnnotationLabelPrefix)
+		if _, ok := labels[name]; ok {
+			continue
+		}
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			log.WithFields(log.Fields{"pod": pod.Name, "annotation": k}).Warnf("Ignoring label annotation with invalid name: %v", errs)
+			continue
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			log.WithFields(log.Fields{"pod": pod.Name, "annotation": k}).Warnf("Ignoring label annotation with invalid value: %v", errs)
+			continue
+		}
+		labels[name] = v
+	}
+}
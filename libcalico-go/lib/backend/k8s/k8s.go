@@ -16,6 +16,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"reflect"
@@ -85,7 +86,7 @@ func NewKubeClient(ca *apiconfig.CalicoAPIConfigSpec) (api.Client, error) {
 
 	crdClientV1, err := buildCRDClientV1(*config)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to build V1 CRD client: %v", err)
+		return nil, fmt.Errorf("Failed to build V1 CRD client: %w", err)
 	}
 
 	kubeClient := &KubeClient{
@@ -128,6 +129,18 @@ func NewKubeClient(ca *apiconfig.CalicoAPIConfigSpec) (api.Client, error) {
 		apiv3.KindNetworkPolicy,
 		resources.NewNetworkPolicyClient(cs, crdClientV1),
 	)
+	kubeClient.registerResourceClient(
+		reflect.TypeOf(model.ResourceKey{}),
+		reflect.TypeOf(model.ResourceListOptions{}),
+		apiv3.KindStagedGlobalNetworkPolicy,
+		resources.NewStagedGlobalNetworkPolicyClient(cs, crdClientV1),
+	)
+	kubeClient.registerResourceClient(
+		reflect.TypeOf(model.ResourceKey{}),
+		reflect.TypeOf(model.ResourceListOptions{}),
+		apiv3.KindStagedNetworkPolicy,
+		resources.NewStagedNetworkPolicyClient(cs, crdClientV1),
+	)
 	kubeClient.registerResourceClient(
 		reflect.TypeOf(model.ResourceKey{}),
 		reflect.TypeOf(model.ResourceListOptions{}),
@@ -423,6 +436,8 @@ func (c *KubeClient) Clean() error {
 		apiv3.KindFelixConfiguration,
 		apiv3.KindGlobalNetworkPolicy,
 		apiv3.KindNetworkPolicy,
+		apiv3.KindStagedGlobalNetworkPolicy,
+		apiv3.KindStagedNetworkPolicy,
 		apiv3.KindGlobalNetworkSet,
 		apiv3.KindNetworkSet,
 		apiv3.KindIPPool,
@@ -537,6 +552,10 @@ func buildCRDClientV1(cfg rest.Config) (*rest.RESTClient, error) {
 					&apiv3.GlobalNetworkPolicyList{},
 					&apiv3.NetworkPolicy{},
 					&apiv3.NetworkPolicyList{},
+					&apiv3.StagedGlobalNetworkPolicy{},
+					&apiv3.StagedGlobalNetworkPolicyList{},
+					&apiv3.StagedNetworkPolicy{},
+					&apiv3.StagedNetworkPolicyList{},
 					&apiv3.NetworkSet{},
 					&apiv3.NetworkSetList{},
 					&apiv3.HostEndpoint{},
@@ -579,6 +598,9 @@ func (c *KubeClient) Create(ctx context.Context, d *model.KVPair) (*model.KVPair
 			Operation:  "Create",
 		}
 	}
+	if api.IsDryRun(ctx) && !resources.SupportsDryRun(client) {
+		return nil, api.DryRunNotSupported("Create", d.Key)
+	}
 	return client.Create(ctx, d)
 }
 
@@ -594,6 +616,9 @@ func (c *KubeClient) Update(ctx context.Context, d *model.KVPair) (*model.KVPair
 			Operation:  "Update",
 		}
 	}
+	if api.IsDryRun(ctx) && !resources.SupportsDryRun(client) {
+		return nil, api.DryRunNotSupported("Update", d.Key)
+	}
 	return client.Update(ctx, d)
 }
 
@@ -615,7 +640,7 @@ func (c *KubeClient) Apply(ctx context.Context, kvp *model.KVPair) (*model.KVPai
 		Value: kvp.Value,
 	})
 	if err != nil {
-		if _, ok := err.(cerrors.ErrorResourceAlreadyExists); !ok {
+		if !errors.Is(err, cerrors.ErrAlreadyExists) {
 			logContext.Debug("Error applying resource (using Create)")
 			return nil, err
 		}
@@ -641,6 +666,9 @@ func (c *KubeClient) DeleteKVP(ctx context.Context, kvp *model.KVPair) (*model.K
 			Operation:  "Delete",
 		}
 	}
+	if api.IsDryRun(ctx) && !resources.SupportsDryRun(client) {
+		return nil, api.DryRunNotSupported("Delete", kvp.Key)
+	}
 	return client.DeleteKVP(ctx, kvp)
 }
 
@@ -655,6 +683,9 @@ func (c *KubeClient) Delete(ctx context.Context, k model.Key, revision string) (
 			Operation:  "Delete",
 		}
 	}
+	if api.IsDryRun(ctx) && !resources.SupportsDryRun(client) {
+		return nil, api.DryRunNotSupported("Delete", k)
+	}
 	return client.Delete(ctx, k, revision, nil)
 }
 
@@ -687,6 +718,13 @@ func (c *KubeClient) List(ctx context.Context, l model.ListInterface, revision s
 	return client.List(ctx, l, revision)
 }
 
+// ListPage lists the entries in the datastore a page at a time, using the Kubernetes continue
+// tokens.  The resource types that don't map to a single Kubernetes List return all their
+// entries in a single page.
+func (c *KubeClient) ListPage(ctx context.Context, l model.ListInterface, revision string, limit int64, continueToken string) (*model.KVPairList, error) {
+	return c.List(resources.WithListPage(ctx, limit, continueToken), l, revision)
+}
+
 // List entries in the datastore.  This may return an empty list if there are
 // no entries matching the request in the ListInterface.
 func (c *KubeClient) Watch(ctx context.Context, l model.ListInterface, revision string) (api.WatchInterface, error) {
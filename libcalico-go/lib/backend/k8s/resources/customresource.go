@@ -16,6 +16,7 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -88,6 +89,13 @@ type Validator interface {
 	Validate(Resource) error
 }
 
+// SupportsDryRun returns true if the client honors a dry run requested with api.ContextWithDryRun.
+// Only the custom resource clients pass the request on to the Kubernetes API server.
+func SupportsDryRun(c K8sResourceClient) bool {
+	_, ok := c.(*customK8sResourceClient)
+	return ok
+}
+
 // Create creates a new Custom K8s Resource instance in the k8s API from the supplied KVPair.
 func (c *customK8sResourceClient) Create(ctx context.Context, kvp *model.KVPair) (*model.KVPair, error) {
 	logContext := log.WithFields(log.Fields{
@@ -115,11 +123,14 @@ func (c *customK8sResourceClient) Create(ctx context.Context, kvp *model.KVPair)
 	// Send the update request using the REST interface.
 	resOut := reflect.New(c.k8sResourceType).Interface().(Resource)
 	namespace := kvp.Key.(model.ResourceKey).Namespace
-	err = c.restClient.Post().
+	req := c.restClient.Post().
 		NamespaceIfScoped(namespace, c.namespaced).
 		Resource(c.resource).
-		Body(resIn).
-		Do(ctx).Into(resOut)
+		Body(resIn)
+	if api.IsDryRun(ctx) {
+		req = req.Param("dryRun", metav1.DryRunAll)
+	}
+	err = req.Do(ctx).Into(resOut)
 	if err != nil {
 		logContext.WithError(err).Debug("Error creating resource")
 		return nil, K8sErrorToCalico(err, kvp.Key)
@@ -169,12 +180,15 @@ func (c *customK8sResourceClient) Update(ctx context.Context, kvp *model.KVPair)
 	namespace := resIn.GetObjectMeta().GetNamespace()
 	logContext = logContext.WithField("Name", name)
 	logContext.Debug("Update resource by name")
-	updateError = c.restClient.Put().
+	req := c.restClient.Put().
 		Resource(c.resource).
 		NamespaceIfScoped(namespace, c.namespaced).
 		Body(resIn).
-		Name(name).
-		Do(ctx).Into(resOut)
+		Name(name)
+	if api.IsDryRun(ctx) {
+		req = req.Param("dryRun", metav1.DryRunAll)
+	}
+	updateError = req.Do(ctx).Into(resOut)
 	if updateError != nil {
 		// Failed to update the resource.
 		logContext.WithError(updateError).Error("Error updating resource")
@@ -229,6 +243,9 @@ func (c *customK8sResourceClient) Delete(ctx context.Context, k model.Key, revis
 		}
 		opts.Preconditions = &metav1.Preconditions{UID: &uid}
 	}
+	if api.IsDryRun(ctx) {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
 
 	// Delete the resource using the name.
 	logContext = logContext.WithField("Name", name)
@@ -300,7 +317,7 @@ func (c *customK8sResourceClient) List(ctx context.Context, list model.ListInter
 			// The error will already be a Calico error type.  Ignore
 			// error that it doesn't exist - we'll return an empty
 			// list.
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			if !errors.Is(err, cerrors.ErrNotFound) {
 				logContext.WithField("Resource", c.resource).WithError(err).Debug("Error listing resource")
 				return nil, err
 			}
@@ -318,10 +335,13 @@ func (c *customK8sResourceClient) List(ctx context.Context, list model.ListInter
 	}
 
 	// If it is a namespaced resource, then we'll need the namespace.
-	namespace := list.(model.ResourceListOptions).Namespace
+	rlo := list.(model.ResourceListOptions)
+	namespace := rlo.Namespace
 
 	// listFunc performs a list with the given options.
 	listFunc := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		opts.LabelSelector = rlo.LabelSelector
+		opts.FieldSelector = k8sFieldSelector(rlo.FieldSelector, crdSelectableFields)
 		out := reflect.New(c.k8sListType).Interface().(ResourceList)
 		err := c.restClient.Get().
 			NamespaceIfScoped(namespace, c.namespaced).
@@ -368,6 +388,11 @@ func (c *customK8sResourceClient) Watch(ctx context.Context, list model.ListInte
 		}
 	}
 
+	opts.LabelSelector = rlo.LabelSelector
+	if s := k8sFieldSelector(rlo.FieldSelector, crdSelectableFields); s != "" {
+		fieldSelector = fields.AndSelectors(fieldSelector, fields.ParseSelectorOrDie(s))
+	}
+
 	k8sWatchClient := cache.NewListWatchFromClient(c.restClient, c.resource, rlo.Namespace, fieldSelector)
 	k8sWatch, err := k8sWatchClient.WatchFunc(opts)
 	if err != nil {
@@ -419,7 +444,7 @@ func (c *customK8sResourceClient) convertResourceToKVPair(r Resource) (*model.KV
 	// No-op for a v3 resource.
 	if c.versionconverter != nil {
 		if r, err = c.versionconverter.ConvertFromK8s(r); err != nil {
-			return nil, fmt.Errorf("error converting resource from v1 to v3: %s", err)
+			return nil, fmt.Errorf("error converting resource from v1 to v3: %w", err)
 		}
 	}
 
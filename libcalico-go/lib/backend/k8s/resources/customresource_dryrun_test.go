@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+var _ = Describe("Custom resource dry run (tested using BGPPeer)", func() {
+	var (
+		server  *httptest.Server
+		client  K8sResourceClient
+		methods []string
+		dryRuns []string
+	)
+
+	BeforeEach(func() {
+		methods = nil
+		dryRuns = nil
+
+		// Echo back the request body, as the API server does for a successful write.
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			methods = append(methods, r.Method)
+			dryRuns = append(dryRuns, r.URL.Query().Get("dryRun"))
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+			_, _ = w.Write(body)
+		}))
+
+		gv := schema.GroupVersion{Group: "crd.projectcalico.org", Version: "v1"}
+		scheme := runtime.NewScheme()
+		scheme.AddKnownTypes(gv, &apiv3.BGPPeer{}, &apiv3.BGPPeerList{})
+		metav1.AddToGroupVersion(scheme, gv)
+		restClient, err := rest.RESTClientFor(&rest.Config{
+			Host:    server.URL,
+			APIPath: "/apis",
+			ContentConfig: rest.ContentConfig{
+				GroupVersion:         &gv,
+				ContentType:          runtime.ContentTypeJSON,
+				NegotiatedSerializer: serializer.WithoutConversionCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		client = NewBGPPeerClient(nil, restClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	kvp := func() *model.KVPair {
+		peer := apiv3.NewBGPPeer()
+		peer.Name = "peer1"
+		peer.ResourceVersion = "1"
+		peer.Spec.PeerIP = "1.2.3.4"
+		peer.Spec.ASNumber = 64512
+		return &model.KVPair{
+			Key:      model.ResourceKey{Name: "peer1", Kind: apiv3.KindBGPPeer},
+			Value:    peer,
+			Revision: "1",
+		}
+	}
+
+	It("should support dry run", func() {
+		Expect(SupportsDryRun(client)).To(BeTrue())
+	})
+
+	It("should only request a dry run from the API server when the context asks for one", func() {
+		_, err := client.Create(context.Background(), kvp())
+		Expect(err).NotTo(HaveOccurred())
+		out, err := client.Create(api.ContextWithDryRun(context.Background()), kvp())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Value.(*apiv3.BGPPeer).Spec.PeerIP).To(Equal("1.2.3.4"))
+		Expect(methods).To(Equal([]string{http.MethodPost, http.MethodPost}))
+		Expect(dryRuns).To(Equal([]string{"", metav1.DryRunAll}))
+	})
+
+	It("should request a dry run for an update", func() {
+		_, err := client.Update(api.ContextWithDryRun(context.Background()), kvp())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(methods).To(Equal([]string{http.MethodPut}))
+		Expect(dryRuns).To(Equal([]string{metav1.DryRunAll}))
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -261,10 +262,10 @@ func (c *blockAffinityClient) deleteKVPV3(ctx context.Context, kvp *model.KVPair
 		// Look up missing values with the provided key.
 		nkvp, err = c.getV3(ctx, kvp.Key.(model.ResourceKey), kvp.Revision)
 		if err != nil {
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			if errors.Is(err, cerrors.ErrNotFound) {
 				return nil, fmt.Errorf("Unable to find block affinity. Block affinity may have already been deleted.")
 			}
-			return nil, fmt.Errorf("Error retrieving block affinity for deletion: %s", err)
+			return nil, fmt.Errorf("Error retrieving block affinity for deletion: %w", err)
 		}
 	}
 
@@ -366,7 +367,11 @@ func (c *blockAffinityClient) listV1(ctx context.Context, list model.BlockAffini
 	host := list.Host
 	requestedIPVersion := list.IPVersion
 
-	kvpl := &model.KVPairList{KVPairs: []*model.KVPair{}}
+	kvpl := &model.KVPairList{
+		KVPairs:  []*model.KVPair{},
+		Revision: v3list.Revision,
+		Continue: v3list.Continue,
+	}
 	for _, i := range v3list.KVPairs {
 		v1kvp, err := c.toV1(i)
 		if err != nil {
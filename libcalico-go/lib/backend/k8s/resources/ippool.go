@@ -123,14 +123,15 @@ func IPPoolV3ToV1(kvp *model.KVPair) (*model.KVPair, error) {
 	return &model.KVPair{
 		Key: v1key,
 		Value: &model.IPPool{
-			CIDR:             *cidr,
-			IPIPInterface:    ipipInterface,
-			IPIPMode:         ipipMode,
-			VXLANMode:        vxlanMode,
-			Masquerade:       v3res.Spec.NATOutgoing,
-			IPAM:             !v3res.Spec.Disabled,
-			Disabled:         v3res.Spec.Disabled,
-			DisableBGPExport: v3res.Spec.DisableBGPExport,
+			CIDR:               *cidr,
+			IPIPInterface:      ipipInterface,
+			IPIPMode:           ipipMode,
+			VXLANMode:          vxlanMode,
+			Masquerade:         v3res.Spec.NATOutgoing,
+			IPAM:               !v3res.Spec.Disabled,
+			Disabled:           v3res.Spec.Disabled,
+			DisableBGPExport:   v3res.Spec.DisableBGPExport,
+			EgressSNATSelector: v3res.Spec.EgressSNATSelector,
 		},
 		Revision: kvp.Revision,
 	}, nil
@@ -16,6 +16,7 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -101,7 +102,7 @@ func (c *serviceClient) List(ctx context.Context, list model.ListInterface, revi
 		// If the entry does not exist then we just return an empty list.
 		kvp, err := c.Get(ctx, model.ResourceKey{Name: rl.Name, Namespace: rl.Namespace, Kind: model.KindKubernetesService}, revision)
 		if err != nil {
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			if !errors.Is(err, cerrors.ErrNotFound) {
 				return nil, err
 			}
 			return &model.KVPairList{
@@ -26,8 +26,32 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 )
 
+type listPageKey struct{}
+
+// listPage is a request for a single page of a List.
+type listPage struct {
+	limit         int64
+	continueToken string
+}
+
+// WithListPage returns a context that makes the Lists of the resource clients return a single
+// page of up to limit resources, starting at the continue token, with the token of the next
+// page in the Continue field of the result.  The resource clients that combine several
+// Kubernetes Lists return all their resources in a single page.
+func WithListPage(ctx context.Context, limit int64, continueToken string) context.Context {
+	return context.WithValue(ctx, listPageKey{}, listPage{limit: limit, continueToken: continueToken})
+}
+
+// withoutListPage returns a context that makes the Lists return all the resources.
+func withoutListPage(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(listPageKey{}).(listPage); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, listPageKey{}, nil)
+}
+
 // pagedList performs a paginated list operation against the Kubernetes API using the given
-// information.
+// information.  If the context has a page request from WithListPage, it returns that page only.
 func pagedList(
 	ctx context.Context,
 	log *logrus.Entry,
@@ -39,12 +63,24 @@ func pagedList(
 	*model.KVPairList,
 	error,
 ) {
-	lp := pager.New(listFunc)
 	opts := metav1.ListOptions{ResourceVersion: revision}
 	if revision != "" {
 		opts.ResourceVersionMatch = metav1.ResourceVersionMatchNotOlderThan
 	}
-	result, isPaged, err := lp.List(ctx, opts)
+	var result runtime.Object
+	var isPaged bool
+	var err error
+	if page, ok := ctx.Value(listPageKey{}).(listPage); ok {
+		opts.Limit = page.limit
+		if page.continueToken != "" {
+			// The continue token determines the resource version of the page.
+			opts = metav1.ListOptions{Limit: page.limit, Continue: page.continueToken}
+		}
+		result, err = listFunc(ctx, opts)
+		isPaged = true
+	} else {
+		result, isPaged, err = pager.New(listFunc).List(ctx, opts)
+	}
 	if err != nil {
 		return nil, K8sErrorToCalico(err, list)
 	}
@@ -77,5 +113,6 @@ func pagedList(
 	return &model.KVPairList{
 		KVPairs:  kvps,
 		Revision: m.GetResourceVersion(),
+		Continue: m.GetContinue(),
 	}, nil
 }
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+var _ = Describe("Paged list", func() {
+	var requests []metav1.ListOptions
+
+	// listFunc returns namespaces in pages of two, out of five.
+	listFunc := func(_ context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		requests = append(requests, opts)
+		start := 0
+		if opts.Continue != "" {
+			start = int(opts.Continue[0] - '0')
+		}
+		list := &v1.NamespaceList{ListMeta: metav1.ListMeta{ResourceVersion: "100"}}
+		for i := start; i < 5 && i < start+2; i++ {
+			list.Items = append(list.Items, v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + i))}})
+		}
+		if start+2 < 5 {
+			list.Continue = string(rune('0' + start + 2))
+		}
+		return list, nil
+	}
+	toKVPs := func(r Resource) ([]*model.KVPair, error) {
+		return []*model.KVPair{{Key: model.ResourceKey{Kind: "Namespace", Name: r.GetObjectMeta().GetName()}}}, nil
+	}
+	names := func(kvps *model.KVPairList) []string {
+		var n []string
+		for _, kvp := range kvps.KVPairs {
+			n = append(n, kvp.Key.(model.ResourceKey).Name)
+		}
+		return n
+	}
+	list := func(ctx context.Context, revision string) *model.KVPairList {
+		kvps, err := pagedList(ctx, logrus.WithField("test", true), revision, model.ResourceListOptions{}, toKVPs, listFunc)
+		Expect(err).NotTo(HaveOccurred())
+		return kvps
+	}
+
+	BeforeEach(func() {
+		requests = nil
+	})
+
+	It("should return a single page when asked to", func() {
+		kvps := list(WithListPage(context.Background(), 2, ""), "50")
+		Expect(names(kvps)).To(Equal([]string{"a", "b"}))
+		Expect(kvps.Revision).To(Equal("100"))
+		Expect(kvps.Continue).To(Equal("2"))
+		Expect(requests).To(Equal([]metav1.ListOptions{{
+			ResourceVersion:      "50",
+			ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+			Limit:                2,
+		}}))
+
+		// The continue token replaces the revision.
+		kvps = list(WithListPage(context.Background(), 2, kvps.Continue), "50")
+		Expect(names(kvps)).To(Equal([]string{"c", "d"}))
+		Expect(requests[1]).To(Equal(metav1.ListOptions{Limit: 2, Continue: "2"}))
+	})
+
+	It("should return all the pages otherwise", func() {
+		kvps := list(withoutListPage(WithListPage(context.Background(), 2, "")), "")
+		Expect(names(kvps)).To(Equal([]string{"a", "b", "c", "d", "e"}))
+		Expect(kvps.Continue).To(BeEmpty())
+	})
+})
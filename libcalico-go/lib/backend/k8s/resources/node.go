@@ -56,6 +56,7 @@ const (
 	nodeWireguardIpv6IfaceAddrAnnotation  = "projectcalico.org/IPv6WireguardInterfaceAddr"
 	nodeWireguardPublicKeyAnnotation      = "projectcalico.org/WireguardPublicKey"
 	nodeWireguardPublicKeyV6Annotation    = "projectcalico.org/WireguardPublicKeyV6"
+	nodeInterfacesAnnotation              = "projectcalico.org/Interfaces"
 )
 
 func NewNodeClient(c *kubernetes.Clientset, usePodCIDR bool) K8sResourceClient {
@@ -148,7 +149,7 @@ func (c *nodeClient) List(ctx context.Context, list model.ListInterface, revisio
 		// If the entry does not exist then we just return an empty list.
 		kvp, err := c.Get(ctx, model.ResourceKey{Name: nl.Name, Kind: libapiv3.KindNode}, revision)
 		if err != nil {
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			if !errors.Is(err, cerrors.ErrNotFound) {
 				return nil, err
 			}
 			return &model.KVPairList{
@@ -288,6 +289,14 @@ func K8sNodeToCalico(k8sNode *kapiv1.Node, usePodCIDR bool) (*model.KVPair, erro
 	calicoNode.Spec.IPv6VXLANTunnelAddr = getAnnotation(k8sNode, nodeBgpIpv6VXLANTunnelAddrAnnotation, validatorv3.ValidateIPv6Network)
 	calicoNode.Spec.VXLANTunnelMACAddrV6 = getAnnotation(k8sNode, nodeBgpVXLANTunnelMACAddrV6Annotation, validatorv3.ValidateMAC)
 
+	// The host interfaces are stored as JSON.
+	if ifaces, ok := annotations[nodeInterfacesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(ifaces), &calicoNode.Spec.Interfaces); err != nil {
+			log.WithError(err).Warnf("failed to read node interfaces from annotation: %s", nodeInterfacesAnnotation)
+			calicoNode.Spec.Interfaces = nil
+		}
+	}
+
 	// Set the node status
 	nodeStatus := libapiv3.NodeStatus{}
 	nodeStatus.WireguardPublicKey = annotations[nodeWireguardPublicKeyAnnotation]
@@ -452,6 +461,17 @@ func mergeCalicoNodeIntoK8sNode(calicoNode *libapiv3.Node, k8sNode *kapiv1.Node)
 		delete(k8sNode.Annotations, nodeWireguardPublicKeyV6Annotation)
 	}
 
+	// Handle the host interfaces.
+	if len(calicoNode.Spec.Interfaces) > 0 {
+		ifaces, err := json.Marshal(calicoNode.Spec.Interfaces)
+		if err != nil {
+			return nil, err
+		}
+		k8sNode.Annotations[nodeInterfacesAnnotation] = string(ifaces)
+	} else {
+		delete(k8sNode.Annotations, nodeInterfacesAnnotation)
+	}
+
 	return k8sNode, nil
 }
 
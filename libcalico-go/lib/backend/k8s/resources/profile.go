@@ -165,7 +165,7 @@ func (c *profileClient) List(ctx context.Context, list model.ListInterface, revi
 		kvps := []*model.KVPair{}
 		kvp, err := c.Get(ctx, model.ResourceKey{Name: nl.Name, Kind: nl.Kind}, revision)
 		if err != nil {
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			if !errors.Is(err, cerrors.ErrNotFound) {
 				return nil, err
 			}
 			return &model.KVPairList{
@@ -186,6 +186,9 @@ func (c *profileClient) List(ctx context.Context, list model.ListInterface, revi
 		return nil, err
 	}
 
+	// The profiles come from two Lists, return them in a single page.
+	ctx = withoutListPage(ctx)
+
 	// Enumerate all namespaces, paginated.
 	listFunc := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
 		return c.clientSet.CoreV1().Namespaces().List(ctx, opts)
@@ -0,0 +1,65 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// crdSelectableFields maps the fields that the field selectors of the custom resources can use
+// to the fields of the Kubernetes API.
+var crdSelectableFields = map[string]string{
+	"metadata.name":      "metadata.name",
+	"metadata.namespace": "metadata.namespace",
+}
+
+// k8sFieldSelector returns the requirements of a field selector on the fields in the map,
+// renamed to their Kubernetes API fields, as a field selector for the Kubernetes API.  The
+// other requirements, and any invalid selector, are left to the client to filter.
+func k8sFieldSelector(selector string, k8sFields map[string]string) string {
+	if selector == "" {
+		return ""
+	}
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return ""
+	}
+	var terms []string
+	for _, r := range parsed.Requirements() {
+		k8sField, ok := k8sFields[r.Field]
+		if !ok {
+			continue
+		}
+		op := "="
+		if r.Operator == selection.NotEquals {
+			op = "!="
+		}
+		terms = append(terms, k8sField+op+fields.EscapeValue(r.Value))
+	}
+	return strings.Join(terms, ",")
+}
+
+// andFieldSelectors combines two field selectors for the Kubernetes API.
+func andFieldSelectors(a, b string) string {
+	if a == "" {
+		return b
+	} else if b == "" {
+		return a
+	}
+	return a + "," + b
+}
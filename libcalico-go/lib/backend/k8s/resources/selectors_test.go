@@ -0,0 +1,31 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("Kubernetes field selectors",
+	func(selector, expected string) {
+		Expect(k8sFieldSelector(selector, podSelectableFields)).To(Equal(expected))
+	},
+	Entry("no selector", "", ""),
+	Entry("a renamed field", "spec.node=node1", "spec.nodeName=node1"),
+	Entry("a negated field", "spec.node!=node1", "spec.nodeName!=node1"),
+	Entry("only the supported fields", "spec.orchestrator=k8s,metadata.namespace=default", "metadata.namespace=default"),
+	Entry("an invalid selector", "spec.node", ""),
+)
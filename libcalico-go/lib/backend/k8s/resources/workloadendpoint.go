@@ -114,6 +114,13 @@ func (c *WorkloadEndpointClient) calcCNIAnnotations(kvp *model.KVPair) map[strin
 	}
 	log.Debugf("PATCHing pod with IPs: %v", ips)
 
+	if isSecondaryEndpoint(wep.Spec.Endpoint) {
+		// The IPs of a secondary interface are stored separately so that they don't replace the
+		// IPs of the pod.
+		annotations[conversion.AnnotationInterfaceIPsPrefix+wep.Spec.Endpoint] = strings.Join(ips, ",")
+		return annotations
+	}
+
 	// Write the IP addresses into annotations.  This generates an event more quickly than
 	// waiting for kubelet to update the PodStatus PodIP and PodIPs fields.
 	firstIP := ""
@@ -134,6 +141,16 @@ func (c *WorkloadEndpointClient) calcCNIAnnotations(kvp *model.KVPair) map[strin
 // patchOutAnnotations sets our pod IP annotations to empty strings; this is used to signal that the IP has been removed
 // from the pod at teardown.
 func (c *WorkloadEndpointClient) patchOutAnnotations(ctx context.Context, key model.Key, revision string, uid *types.UID) (*model.KVPair, error) {
+	wepID, err := c.converter.ParseWorkloadEndpointName(key.(model.ResourceKey).Name)
+	if err != nil {
+		return nil, err
+	}
+	if isSecondaryEndpoint(wepID.Endpoint) {
+		// Only remove the IPs of the secondary interface, the pod keeps its other interfaces.
+		annotations := map[string]string{conversion.AnnotationInterfaceIPsPrefix + wepID.Endpoint: ""}
+		return c.patchPodAnnotations(ctx, key, revision, uid, annotations)
+	}
+
 	// Passing nil for annotations will result in all annotations being explicitly set to the empty string.
 	// Setting the podIPs to empty string is used to signal that the CNI DEL has removed the IP from the Pod.
 	// We leave the container ID in place to allow any repeat invocations of the CNI DEL to tell which instance of a Pod they are seeing.
@@ -170,9 +187,21 @@ func (c *WorkloadEndpointClient) patchPodAnnotations(ctx context.Context, key mo
 		return nil, err
 	}
 
+	// Return the WorkloadEndpoint of the patched interface.
+	for _, kvp := range kvps {
+		if kvp.Value.(*libapiv3.WorkloadEndpoint).Name == key.(model.ResourceKey).Name {
+			return kvp, nil
+		}
+	}
 	return kvps[0], nil
 }
 
+// isSecondaryEndpoint returns true if the endpoint is a secondary interface of a pod, whose IPs are
+// stored in their own annotation.
+func isSecondaryEndpoint(endpoint string) bool {
+	return endpoint != "" && endpoint != "eth0"
+}
+
 func calculateAnnotationPatch(revision string, uid *types.UID, annotations map[string]string) ([]byte, error) {
 	patch := map[string]interface{}{}
 	metadata := map[string]interface{}{}
@@ -311,6 +340,14 @@ func (c *WorkloadEndpointClient) listUsingName(ctx context.Context, listOptions
 	}, nil
 }
 
+// podSelectableFields maps the fields of the WorkloadEndpoints that the Pods can be selected on
+// to the fields of the Pods.  The labels of a WorkloadEndpoint are not those of its Pod, so the
+// Pods are not selected on labels.
+var podSelectableFields = map[string]string{
+	"metadata.namespace": "metadata.namespace",
+	"spec.node":          "spec.nodeName",
+}
+
 // list lists all the Workload endpoints for the namespace given in listOptions.
 func (c *WorkloadEndpointClient) list(ctx context.Context, list model.ResourceListOptions, revision string) (*model.KVPairList, error) {
 	logContext := log.WithField("Resource", "WorkloadEndpoint")
@@ -327,6 +364,7 @@ func (c *WorkloadEndpointClient) list(ctx context.Context, list model.ResourceLi
 
 	// Perform a paginated list of pods, executing the conversion function on each.
 	listFunc := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		opts.FieldSelector = k8sFieldSelector(list.FieldSelector, podSelectableFields)
 		return c.clientSet.CoreV1().Pods(list.Namespace).List(ctx, opts)
 	}
 	return pagedList(ctx, logContext, revision, list, convertFunc, listFunc)
@@ -355,6 +393,7 @@ func (c *WorkloadEndpointClient) Watch(ctx context.Context, list model.ListInter
 		log.WithField("name", wepids.Pod).Debug("Watching a single workloadendpoint")
 		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", wepids.Pod).String()
 	}
+	opts.FieldSelector = andFieldSelectors(opts.FieldSelector, k8sFieldSelector(rlo.FieldSelector, podSelectableFields))
 
 	ns := rlo.Namespace
 	k8sWatch, err := c.clientSet.CoreV1().Pods(ns).Watch(ctx, opts)
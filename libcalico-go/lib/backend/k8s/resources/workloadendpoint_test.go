@@ -328,6 +328,80 @@ var _ = Describe("WorkloadEndpointClient", func() {
 		})
 	})
 
+	Describe("Secondary interfaces", func() {
+		It("sets and zeros out only the annotation of the interface", func() {
+			k8sClient := fake.NewSimpleClientset(&k8sapi.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "simplePod",
+					Namespace: "testNamespace",
+					Annotations: map[string]string{
+						conversion.AnnotationPodIP:       "192.168.91.117/32",
+						conversion.AnnotationPodIPs:      "192.168.91.117/32",
+						conversion.AnnotationContainerID: "abcde12345",
+					},
+				},
+				Spec: k8sapi.PodSpec{
+					NodeName: "test-node",
+				},
+			})
+
+			wepClient := resources.NewWorkloadEndpointClient(k8sClient)
+			wepIDs := names.WorkloadEndpointIdentifiers{
+				Orchestrator: "k8s",
+				Node:         "test-node",
+				Pod:          "simplePod",
+				Endpoint:     "net1",
+			}
+
+			wepName, err := wepIDs.CalculateWorkloadEndpointName(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			kvp := &model.KVPair{
+				Key: model.ResourceKey{
+					Name:      wepName,
+					Namespace: "testNamespace",
+					Kind:      libapiv3.KindWorkloadEndpoint,
+				},
+				Value: &libapiv3.WorkloadEndpoint{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      wepName,
+						Namespace: "testNamespace",
+					},
+					Spec: libapiv3.WorkloadEndpointSpec{
+						ContainerID: "abcde12345",
+						Endpoint:    "net1",
+						IPNetworks:  []string{"10.1.0.5/32"},
+					},
+				},
+			}
+
+			ctxCNI := resources.ContextWithPatchMode(context.Background(), resources.PatchModeCNI)
+			created, err := wepClient.Create(ctxCNI, kvp)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(created.Value.(*libapiv3.WorkloadEndpoint).Name).To(Equal(wepName))
+			Expect(created.Value.(*libapiv3.WorkloadEndpoint).Spec.IPNetworks).To(Equal([]string{"10.1.0.5/32"}))
+
+			pod, err := k8sClient.CoreV1().Pods("testNamespace").Get(ctx, "simplePod", metav1.GetOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pod.GetAnnotations()).Should(Equal(map[string]string{
+				conversion.AnnotationPodIP:                       "192.168.91.117/32",
+				conversion.AnnotationPodIPs:                      "192.168.91.117/32",
+				conversion.AnnotationContainerID:                 "abcde12345",
+				conversion.AnnotationInterfaceIPsPrefix + "net1": "10.1.0.5/32",
+			}))
+
+			_, err = wepClient.Delete(context.Background(), kvp.Key, "", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			pod, err = k8sClient.CoreV1().Pods("testNamespace").Get(ctx, "simplePod", metav1.GetOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pod.GetAnnotations()).Should(Equal(map[string]string{
+				conversion.AnnotationPodIP:                       "192.168.91.117/32",
+				conversion.AnnotationPodIPs:                      "192.168.91.117/32",
+				conversion.AnnotationContainerID:                 "abcde12345",
+				conversion.AnnotationInterfaceIPsPrefix + "net1": "",
+			}))
+		})
+	})
+
 	Describe("Get", func() {
 		It("gets the WorkloadEndpoint using the given name", func() {
 			k8sClient := fake.NewSimpleClientset(&k8sapi.Pod{
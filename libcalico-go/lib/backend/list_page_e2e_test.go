@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/testutils"
+)
+
+var _ = testutils.E2eDatastoreDescribe("Backend paged list tests", testutils.DatastoreAll, func(config apiconfig.CalicoAPIConfig) {
+	ctx := context.Background()
+	var c api.Client
+
+	BeforeEach(func() {
+		var err error
+		c, err = backend.NewClient(config)
+		Expect(err).NotTo(HaveOccurred())
+		c.Clean()
+
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprintf("ippool-%d", i)
+			_, err := c.Create(ctx, &model.KVPair{
+				Key: model.ResourceKey{Kind: apiv3.KindIPPool, Name: name},
+				Value: &apiv3.IPPool{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       apiv3.IPPoolSpec{CIDR: fmt.Sprintf("10.%d.0.0/16", i)},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		c.Clean()
+	})
+
+	It("should list all the resources a page at a time", func() {
+		_, ok := c.(api.ListPager)
+		Expect(ok).To(BeTrue())
+		list := model.ResourceListOptions{Kind: apiv3.KindIPPool}
+
+		var names []string
+		var revision, continueToken string
+		for pages := 0; ; pages++ {
+			Expect(pages).To(BeNumerically("<", 5), "Too many pages")
+			kvps, err := api.ListPaged(ctx, c, list, "", 2, continueToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(kvps.KVPairs)).To(BeNumerically("<=", 2))
+			if revision == "" {
+				revision = kvps.Revision
+			}
+			for _, kvp := range kvps.KVPairs {
+				names = append(names, kvp.Key.(model.ResourceKey).Name)
+			}
+			if kvps.Continue == "" {
+				break
+			}
+			continueToken = kvps.Continue
+
+			if pages == 0 {
+				// A write between the pages does not change the list.
+				_, err = c.Delete(ctx, model.ResourceKey{Kind: apiv3.KindIPPool, Name: "ippool-4"}, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		Expect(names).To(ConsistOf("ippool-0", "ippool-1", "ippool-2", "ippool-3", "ippool-4"))
+		Expect(revision).NotTo(BeEmpty())
+	})
+
+	It("should reject an invalid continue token", func() {
+		_, err := api.ListPaged(ctx, c, model.ResourceListOptions{Kind: apiv3.KindIPPool}, "", 2, "not-a-token")
+		Expect(err).To(HaveOccurred())
+	})
+})
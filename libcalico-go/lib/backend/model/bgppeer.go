@@ -23,6 +23,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
@@ -205,6 +206,10 @@ type BGPPeer struct {
 	// converts large uints to float e notation which breaks the BIRD
 	// configuration.
 	ASNum numorstring.ASNumber `json:"as_num,string"`
+
+	// Password, if set, references the secret holding the password of the
+	// BGP session with the peer.
+	Password *apiv3.BGPPassword `json:"password,omitempty"`
 }
 
 func extractIPAndPort(ipPort string) ([]byte, uint16) {
@@ -39,6 +39,7 @@ const (
 	IPAMBlockAttributeTypeWireguard   = "wireguardTunnelAddress"
 	IPAMBlockAttributeTypeWireguardV6 = "wireguardV6TunnelAddress"
 	IPAMBlockAttributeTimestamp       = "timestamp"
+	IPAMBlockAttributeSticky          = "sticky"
 )
 
 var (
@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var (
+	matchConfigAudit = regexp.MustCompile("^/?calico/v1/configaudit/([^/]+)$")
+	typeConfigAudit  = reflect.TypeOf(ConfigAuditEntry{})
+)
+
+// ConfigAuditKey is the key of an entry of the config audit log.  The IDs sort
+// in the order in which the entries were recorded.
+type ConfigAuditKey struct {
+	ID string `json:"-" validate:"required"`
+}
+
+func (key ConfigAuditKey) defaultPath() (string, error) {
+	if key.ID == "" {
+		return "", errors.ErrorInsufficientIdentifiers{Name: "id"}
+	}
+	return fmt.Sprintf("/calico/v1/configaudit/%s", key.ID), nil
+}
+
+func (key ConfigAuditKey) defaultDeletePath() (string, error) {
+	return key.defaultPath()
+}
+
+func (key ConfigAuditKey) defaultDeleteParentPaths() ([]string, error) {
+	return nil, nil
+}
+
+func (key ConfigAuditKey) valueType() (reflect.Type, error) {
+	return typeConfigAudit, nil
+}
+
+func (key ConfigAuditKey) String() string {
+	return fmt.Sprintf("ConfigAudit(id=%s)", key.ID)
+}
+
+type ConfigAuditListOptions struct {
+}
+
+func (options ConfigAuditListOptions) defaultPathRoot() string {
+	return "/calico/v1/configaudit"
+}
+
+func (options ConfigAuditListOptions) KeyFromDefaultPath(path string) Key {
+	log.Debugf("Get ConfigAudit key from %s", path)
+	r := matchConfigAudit.FindAllStringSubmatch(path, -1)
+	if len(r) != 1 {
+		log.Debugf("Didn't match regex")
+		return nil
+	}
+	return ConfigAuditKey{ID: r[0][1]}
+}
+
+// ConfigAuditEntry records a change of a config key.  The old value is nil if
+// the key was created and the new value is nil if it was deleted.  Values that
+// are not strings, such as BGP peers, are recorded as JSON.
+type ConfigAuditEntry struct {
+	// Key is the default path of the config key.
+	Key       string    `json:"key"`
+	User      string    `json:"user,omitempty"`
+	OldValue  *string   `json:"oldValue,omitempty"`
+	NewValue  *string   `json:"newValue,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -25,12 +25,14 @@ import (
 )
 
 var (
-	matchGlobalConfig = regexp.MustCompile("^/?calico/v1/config/(.+)$")
-	matchHostConfig   = regexp.MustCompile("^/?calico/v1/host/([^/]+)/config/(.+)$")
-	matchReadyFlag    = regexp.MustCompile("^/calico/v1/Ready$")
-	typeGlobalConfig  = rawStringType
-	typeHostConfig    = rawStringType
-	typeReadyFlag     = rawBoolType
+	matchGlobalConfig   = regexp.MustCompile("^/?calico/v1/config/(.+)$")
+	matchHostConfig     = regexp.MustCompile("^/?calico/v1/host/([^/]+)/config/(.+)$")
+	matchOverrideConfig = regexp.MustCompile("^/?calico/v1/override/([^/]+)/config/(.+)$")
+	matchReadyFlag      = regexp.MustCompile("^/calico/v1/Ready$")
+	typeGlobalConfig    = rawStringType
+	typeHostConfig      = rawStringType
+	typeOverrideConfig  = rawStringType
+	typeReadyFlag       = rawBoolType
 )
 
 type ReadyFlagKey struct {
@@ -181,3 +183,76 @@ func (options HostConfigListOptions) KeyFromDefaultPath(path string) Key {
 	}
 	return HostConfigKey{Hostname: hostname, Name: name}
 }
+
+// OverrideConfigKey is the key of a config value of a FelixConfiguration override, i.e. a
+// FelixConfiguration with a node selector.  The node selector itself is the value of the
+// "NodeSelector" config name.
+type OverrideConfigKey struct {
+	OverrideName string `json:"-" validate:"required,name"`
+	Name         string `json:"-" validate:"required,name"`
+}
+
+func (key OverrideConfigKey) defaultPath() (string, error) {
+	if key.Name == "" {
+		return "", errors.ErrorInsufficientIdentifiers{Name: "name"}
+	}
+	if key.OverrideName == "" {
+		return "", errors.ErrorInsufficientIdentifiers{Name: "override"}
+	}
+	e := fmt.Sprintf("/calico/v1/override/%s/config/%s", key.OverrideName, key.Name)
+	return e, nil
+}
+
+func (key OverrideConfigKey) defaultDeletePath() (string, error) {
+	return key.defaultPath()
+}
+
+func (key OverrideConfigKey) defaultDeleteParentPaths() ([]string, error) {
+	return nil, nil
+}
+
+func (key OverrideConfigKey) valueType() (reflect.Type, error) {
+	return typeOverrideConfig, nil
+}
+
+func (key OverrideConfigKey) String() string {
+	return fmt.Sprintf("OverrideConfig(override=%s,name=%s)", key.OverrideName, key.Name)
+}
+
+type OverrideConfigListOptions struct {
+	OverrideName string
+	Name         string
+}
+
+func (options OverrideConfigListOptions) defaultPathRoot() string {
+	k := "/calico/v1/override"
+	if options.OverrideName == "" {
+		return k
+	}
+	k = k + fmt.Sprintf("/%s/config", options.OverrideName)
+	if options.Name == "" {
+		return k
+	}
+	k = k + fmt.Sprintf("/%s", options.Name)
+	return k
+}
+
+func (options OverrideConfigListOptions) KeyFromDefaultPath(path string) Key {
+	log.Debugf("Get OverrideConfig key from %s", path)
+	r := matchOverrideConfig.FindAllStringSubmatch(path, -1)
+	if len(r) != 1 {
+		log.Debugf("Didn't match regex")
+		return nil
+	}
+	overrideName := r[0][1]
+	name := r[0][2]
+	if options.OverrideName != "" && overrideName != options.OverrideName {
+		log.Debugf("Didn't match override %s != %s", options.OverrideName, overrideName)
+		return nil
+	}
+	if options.Name != "" && name != options.Name {
+		log.Debugf("Didn't match name %s != %s", options.Name, name)
+		return nil
+	}
+	return OverrideConfigKey{OverrideName: overrideName, Name: name}
+}
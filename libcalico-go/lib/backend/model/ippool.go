@@ -96,12 +96,13 @@ func (options IPPoolListOptions) KeyFromDefaultPath(path string) Key {
 }
 
 type IPPool struct {
-	CIDR             net.IPNet  `json:"cidr"`
-	IPIPInterface    string     `json:"ipip"`
-	IPIPMode         encap.Mode `json:"ipip_mode"`
-	VXLANMode        encap.Mode `json:"vxlan_mode"`
-	Masquerade       bool       `json:"masquerade"`
-	IPAM             bool       `json:"ipam"`
-	Disabled         bool       `json:"disabled"`
-	DisableBGPExport bool       `json:"disableBGPExport"`
+	CIDR               net.IPNet  `json:"cidr"`
+	IPIPInterface      string     `json:"ipip"`
+	IPIPMode           encap.Mode `json:"ipip_mode"`
+	VXLANMode          encap.Mode `json:"vxlan_mode"`
+	Masquerade         bool       `json:"masquerade"`
+	IPAM               bool       `json:"ipam"`
+	Disabled           bool       `json:"disabled"`
+	DisableBGPExport   bool       `json:"disableBGPExport"`
+	EgressSNATSelector string     `json:"egressSNATSelector,omitempty"`
 }
@@ -102,6 +102,9 @@ type KVPair struct {
 type KVPairList struct {
 	KVPairs  []*KVPair
 	Revision string
+	// Continue is the token to pass to the next call of a paged list, or empty if this is the
+	// last page.
+	Continue string
 }
 
 // KeyToDefaultPath converts one of the Keys from this package into a unique
@@ -221,6 +224,19 @@ func keyFromDefaultPathInner(path string, parts []string) Key {
 			return IPPoolListOptions{}.KeyFromDefaultPath(path)
 		case "config":
 			return GlobalConfigKey{Name: strings.Join(parts[3:], "/")}
+		case "configaudit":
+			if len(parts) != 4 {
+				return nil
+			}
+			return ConfigAuditKey{ID: parts[3]}
+		case "override":
+			if len(parts) < 6 || parts[4] != "config" {
+				return nil
+			}
+			return OverrideConfigKey{
+				OverrideName: parts[3],
+				Name:         strings.Join(parts[5:], "/"),
+			}
 		case "host":
 			if len(parts) < 5 {
 				return nil
@@ -520,6 +536,9 @@ func OldKeyFromDefaultPath(path string) Key {
 	} else if m := matchHostConfig.FindStringSubmatch(path); m != nil {
 		log.Debugf("Path is a host config: %v", path)
 		return HostConfigKey{Hostname: m[1], Name: m[2]}
+	} else if m := matchOverrideConfig.FindStringSubmatch(path); m != nil {
+		log.Debugf("Path is an override config: %v", path)
+		return OverrideConfigKey{OverrideName: m[1], Name: m[2]}
 	} else if matchReadyFlag.MatchString(path) {
 		log.Debugf("Path is a ready flag: %v", path)
 		return ReadyFlagKey{}
@@ -43,6 +43,8 @@ import (
 var interestingPaths = []string{
 	"/calico/v1/config/foobar",
 	"/calico/v1/config/foobar/bazz",
+	"/calico/v1/configaudit/00001-abcd",
+	"/calico/v1/configaudit/00001-abcd/bazz",
 	"/calico/v1/policy/profile/foo%2fbar/rules",
 	"/calico/v1/policy/profile/foo%2fbar/labels",
 	"/calico/v1/policy/tier/default/policy/biff%2fbop",
@@ -337,12 +339,24 @@ var _ = DescribeTable(
 		GlobalConfigKey{Name: "foo"},
 		false,
 	),
+	Entry(
+		"config audit entry",
+		"/calico/v1/configaudit/00001-abcd",
+		ConfigAuditKey{ID: "00001-abcd"},
+		false,
+	),
 	Entry(
 		"host config",
 		"/calico/v1/host/hostname/config/foo",
 		HostConfigKey{Hostname: "hostname", Name: "foo"},
 		false,
 	),
+	Entry(
+		"override config",
+		"/calico/v1/override/gpu-nodes/config/foo",
+		OverrideConfigKey{OverrideName: "gpu-nodes", Name: "foo"},
+		false,
+	),
 	Entry(
 		"network set",
 		"/calico/v1/netset/netsetname",
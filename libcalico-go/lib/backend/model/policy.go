@@ -31,6 +31,20 @@ var (
 	typePolicy  = reflect.TypeOf(Policy{})
 )
 
+// PolicyNamePrefixStaged is prepended to the names of staged policies, so that they don't
+// clash with the enforced policies of the same name.  For namespaced policies, it is
+// prepended to the part of the name that follows the namespace.
+const PolicyNamePrefixStaged = "staged:"
+
+// PolicyIsStaged returns true if the policy name is the name of a staged policy, which is
+// evaluated without being enforced.
+func PolicyIsStaged(name string) bool {
+	if i := strings.Index(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.HasPrefix(name, PolicyNamePrefixStaged)
+}
+
 type PolicyKey struct {
 	Name string `json:"-" validate:"required,name"`
 }
@@ -110,6 +110,16 @@ func init() {
 		"networkpolicies",
 		reflect.TypeOf(apiv3.NetworkPolicy{}),
 	)
+	registerResourceInfo(
+		apiv3.KindStagedGlobalNetworkPolicy,
+		"stagedglobalnetworkpolicies",
+		reflect.TypeOf(apiv3.StagedGlobalNetworkPolicy{}),
+	)
+	registerResourceInfo(
+		apiv3.KindStagedNetworkPolicy,
+		"stagednetworkpolicies",
+		reflect.TypeOf(apiv3.StagedNetworkPolicy{}),
+	)
 	registerResourceInfo(
 		KindKubernetesNetworkPolicy,
 		"kubernetesnetworkpolicies",
@@ -223,6 +233,11 @@ type ResourceListOptions struct {
 	Kind string
 	// Whether the name is prefix rather than the full name.
 	Prefix bool
+	// Kubernetes label and field selectors that the backend may use to filter the resources.
+	// A backend may return resources that don't match them, so the caller must still filter
+	// the results.
+	LabelSelector string
+	FieldSelector string
 }
 
 // If the Kind, Namespace and Name are specified, but the Name is a prefix then the
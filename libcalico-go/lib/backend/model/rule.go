@@ -40,6 +40,11 @@ type Rule struct {
 	ICMPCode    *int `json:"icmp_code,omitempty" validate:"omitempty,gte=0,lte=255"`
 	NotICMPType *int `json:"!icmp_type,omitempty" validate:"omitempty,gte=0,lt=255"`
 	NotICMPCode *int `json:"!icmp_code,omitempty" validate:"omitempty,gte=0,lte=255"`
+	// ICMPTypeCodes match any of several ICMP types, or types and codes.
+	ICMPTypeCodes []ICMPTypeCode `json:"icmp_type_codes,omitempty" validate:"omitempty,dive"`
+
+	DSCP    *numorstring.DSCP `json:"dscp,omitempty" validate:"omitempty"`
+	NotDSCP *numorstring.DSCP `json:"!dscp,omitempty" validate:"omitempty"`
 
 	SrcTag              string             `json:"src_tag,omitempty" validate:"omitempty,tag"`
 	SrcNet              *net.IPNet         `json:"src_net,omitempty" validate:"omitempty"`
@@ -55,6 +60,7 @@ type Rule struct {
 	DstPorts            []numorstring.Port `json:"dst_ports,omitempty" validate:"omitempty,dive"`
 	DstService          string             `json:"dst_service,omitempty" validate:"omitempty"`
 	DstServiceNamespace string             `json:"dst_service_ns,omitempty" validate:"omitempty"`
+	DstDomains          []string           `json:"dst_domains,omitempty" validate:"omitempty"`
 
 	NotSrcTag      string             `json:"!src_tag,omitempty" validate:"omitempty,tag"`
 	NotSrcNet      *net.IPNet         `json:"!src_net,omitempty" validate:"omitempty"`
@@ -86,14 +92,37 @@ type Rule struct {
 
 	LogPrefix string `json:"log_prefix,omitempty" validate:"omitempty"`
 
+	// Log passes through the log options of rules with the Log action.
+	Log *RuleLog `json:"log,omitempty" validate:"omitempty"`
+
 	Metadata *RuleMetadata `json:"metadata,omitempty" validate:"omitempty"`
 }
 
+// ICMPTypeCode is an ICMP type to match, optionally with a code.
+type ICMPTypeCode struct {
+	Type int  `json:"type" validate:"gte=0,lt=255"`
+	Code *int `json:"code,omitempty" validate:"omitempty,gte=0,lte=255"`
+}
+
+func (tc ICMPTypeCode) String() string {
+	if tc.Code == nil {
+		return strconv.Itoa(tc.Type)
+	}
+	return fmt.Sprintf("%d:%d", tc.Type, *tc.Code)
+}
+
 type HTTPMatch struct {
 	Methods []string         `json:"methods,omitempty" validate:"omitempty"`
 	Paths   []apiv3.HTTPPath `json:"paths,omitempty" validate:"omitempty"`
 }
 
+type RuleLog struct {
+	Target         string `json:"target,omitempty"`
+	SyslogFacility string `json:"syslog_facility,omitempty"`
+	RateLimit      string `json:"rate_limit,omitempty"`
+	RateLimitBurst int    `json:"rate_limit_burst,omitempty"`
+}
+
 type RuleMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
@@ -164,6 +193,19 @@ func (r Rule) String() string {
 	if r.NotICMPCode != nil {
 		parts = append(parts, "!code", strconv.Itoa(*r.NotICMPCode))
 	}
+	if len(r.ICMPTypeCodes) > 0 {
+		typeCodes := make([]string, len(r.ICMPTypeCodes))
+		for i, tc := range r.ICMPTypeCodes {
+			typeCodes[i] = tc.String()
+		}
+		parts = append(parts, "types", strings.Join(typeCodes, ","))
+	}
+	if r.DSCP != nil {
+		parts = append(parts, "dscp", r.DSCP.String())
+	}
+	if r.NotDSCP != nil {
+		parts = append(parts, "!dscp", r.NotDSCP.String())
+	}
 
 	{
 		// Source attributes.  New block ensures that fromParts goes out-of-scope before
@@ -230,6 +272,9 @@ func (r Rule) String() string {
 		if len(dstNets) != 0 {
 			toParts = append(toParts, "cidr", joinNets(dstNets))
 		}
+		if len(r.DstDomains) != 0 {
+			toParts = append(toParts, "domains", strings.Join(r.DstDomains, ","))
+		}
 		if len(r.NotDstPorts) > 0 {
 			notDstPorts := make([]string, len(r.NotDstPorts))
 			for ii, port := range r.NotDstPorts {
@@ -40,6 +40,8 @@ var icmpType = 10
 var icmpCode = 6
 var icmpTypeZero = 0
 var icmpCodeZero = 0
+var dscpEF = numorstring.DSCPFromString("EF")
+var dscp10 = numorstring.DSCPFromInt(10)
 var portRange, _ = numorstring.PortFromRange(10, 20)
 var ports = []numorstring.Port{
 	numorstring.SinglePort(1234),
@@ -70,12 +72,15 @@ var ruleStringTests = []ruleTest{
 		"Deny ICMP type 0"},
 	{model.Rule{Protocol: &icmpProto, ICMPType: &icmpTypeZero, ICMPCode: &icmpCodeZero},
 		"Allow ICMP type 0 code 0"},
+	{model.Rule{Protocol: &icmpProto, ICMPTypeCodes: []model.ICMPTypeCode{{Type: 8}, {Type: icmpType, Code: &icmpCode}}},
+		"Allow ICMP types 8,10:6"},
 	// And negations of packet-wide matches.
 	{model.Rule{Action: "Allow", NotProtocol: &tcpProto}, "Allow !TCP"},
 	{model.Rule{Action: "Deny", Protocol: &icmpProto, NotICMPType: &icmpType},
 		"Deny ICMP !type 10"},
 	{model.Rule{Protocol: &icmpProto, NotICMPType: &icmpType, NotICMPCode: &icmpCode},
 		"Allow ICMP !type 10 !code 6"},
+	{model.Rule{DSCP: &dscpEF, NotDSCP: &dscp10}, "Allow dscp EF !dscp 10"},
 
 	// From rules.
 	{model.Rule{SrcPorts: ports}, "Allow from ports 1234,10:20"},
@@ -181,4 +181,6 @@ type StatusReport struct {
 	Timestamp     string  `json:"time"`
 	UptimeSeconds float64 `json:"uptime"`
 	FirstUpdate   bool    `json:"first_update"`
+	// OperationMode is the operation mode of Felix: Full, PolicyOnly or NetworkingOnly.
+	OperationMode string `json:"operation_mode,omitempty"`
 }
@@ -166,6 +166,7 @@ type WorkloadEndpoint struct {
 	GenerateName               string            `json:"generate_name,omitempty"`
 	AllowSpoofedSourcePrefixes []net.IPNet       `json:"allow_spoofed_source_ips,omitempty"`
 	Annotations                map[string]string `json:"annotations,omitempty"`
+	PortMappings               []PortMapping     `json:"port_mappings,omitempty" validate:"dive"`
 }
 
 type EndpointPort struct {
@@ -174,6 +175,15 @@ type EndpointPort struct {
 	Port     uint16               `json:"port" validate:"gt=0"`
 }
 
+// PortMapping maps a port on the host to a port of the workload, as requested by a
+// hostPort in the pod spec.
+type PortMapping struct {
+	Protocol      numorstring.Protocol `json:"protocol"`
+	HostPort      uint16               `json:"host_port" validate:"gt=0"`
+	ContainerPort uint16               `json:"container_port" validate:"gt=0"`
+	HostIP        string               `json:"host_ip,omitempty" validate:"omitempty,ip"`
+}
+
 // IPNat contains a single NAT mapping for a WorkloadEndpoint resource.
 type IPNAT struct {
 	// The internal IP address which must be associated with the owning endpoint via the
@@ -0,0 +1,667 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package postgres is a reference datastore driver that stores the Calico data in PostgreSQL,
+// for the environments that can run neither etcd nor the Kubernetes API server.  Importing the
+// package registers the driver for the "postgres" datastore type:
+//
+//	import (
+//		_ "github.com/jackc/pgx/v5/stdlib"
+//		_ "github.com/projectcalico/calico/libcalico-go/lib/backend/postgres"
+//	)
+//
+// The driver talks to the database through database/sql, so the binary must also import a
+// PostgreSQL database/sql driver, named by the SQLDriverName config field.
+//
+// The data is stored as in etcd: a table maps the default paths of the keys to their serialized
+// values, and every write appends an event to a second table.  The sequence number of the event
+// is the revision of the write, which gives the same revision semantics as etcd, and the watches
+// poll the event table.  The event table is never compacted by the driver.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/resources"
+)
+
+const (
+	defaultDriverName = "pgx"
+
+	// writeLockID is the ID of the advisory lock that serializes the writes.  Since the writes
+	// commit in the order of their revisions, a watch that has seen a revision has seen all the
+	// revisions before it.
+	writeLockID = 0x63616c69636f
+
+	profilesKey            = "/calico/resources/v3/projectcalico.org/profiles/"
+	defaultAllowProfileKey = "/calico/resources/v3/projectcalico.org/profiles/projectcalico-default-allow"
+
+	// live restricts a query of calico_kv to the entries that have not expired.
+	live = "(expires_at IS NULL OR expires_at > now())"
+)
+
+var (
+	clientTimeout                  = 10 * time.Second
+	defaultAllowProfileResourceKey = model.ResourceKey{Name: "projectcalico-default-allow", Kind: apiv3.KindProfile}
+
+	schema = []string{
+		`CREATE TABLE IF NOT EXISTS calico_kv (
+			path       TEXT PRIMARY KEY,
+			value      TEXT NOT NULL,
+			revision   BIGINT NOT NULL,
+			expires_at TIMESTAMPTZ
+		)`,
+		`CREATE TABLE IF NOT EXISTS calico_kv_events (
+			revision      BIGSERIAL PRIMARY KEY,
+			path          TEXT NOT NULL,
+			value         TEXT,
+			prev_value    TEXT,
+			prev_revision BIGINT
+		)`,
+		`CREATE INDEX IF NOT EXISTS calico_kv_events_path ON calico_kv_events (path, revision)`,
+	}
+)
+
+func init() {
+	backend.RegisterDriver(apiconfig.Postgres, func(config apiconfig.CalicoAPIConfig) (api.Client, error) {
+		return NewPostgresClient(&config.Spec.SQLConfig)
+	})
+}
+
+type postgresClient struct {
+	db           *sql.DB
+	pollInterval time.Duration
+}
+
+// entry is a row of calico_kv, or of calico_kv_events for the reads of past revisions.
+type entry struct {
+	path     string
+	value    string
+	revision int64
+}
+
+// querier is implemented by both sql.DB and sql.Tx.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func NewPostgresClient(config *apiconfig.SQLConfig) (api.Client, error) {
+	if config.SQLDataSourceName == "" {
+		log.Warning("No data source name specified in postgres API config")
+		return nil, errors.New("no SQL data source name specified")
+	}
+	driverName := config.SQLDriverName
+	if driverName == "" {
+		driverName = defaultDriverName
+	}
+
+	db, err := sql.Open(driverName, config.SQLDataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize postgres client: %w", err)
+	}
+
+	c := &postgresClient{db: db, pollInterval: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), clientTimeout)
+	defer cancel()
+	if err := c.createSchema(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not initialize postgres client: %w", err)
+	}
+	return c, nil
+}
+
+func (c *postgresClient) createSchema(ctx context.Context) error {
+	for _, stmt := range schema {
+		if _, err := c.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create an entry in the datastore.  If the entry already exists, this will return
+// an ErrorResourceAlreadyExists error and the current entry.
+func (c *postgresClient) Create(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
+	logCxt.Debug("Processing Create request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Create", d.Key)
+	}
+	key, value, err := getKeyValueStrings(d)
+	if err != nil {
+		return nil, err
+	}
+
+	current, rev, err := c.write(ctx, key, d.TTL, func(current *entry) (*string, error) {
+		if current != nil {
+			return nil, cerrors.ErrorResourceAlreadyExists{Identifier: d.Key}
+		}
+		return &value, nil
+	})
+	if err != nil {
+		logCxt.WithError(err).Debug("Create failed")
+		return existingKVPair(d.Key, current), err
+	}
+	return setValue(d, value, rev)
+}
+
+// Update an entry in the datastore.  If the entry does not exist, this will return
+// an ErrorResourceDoesNotExist error.  The ResourceVersion must be specified, and if
+// incorrect will return an ErrorResourceUpdateConflict error and the current entry.
+func (c *postgresClient) Update(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
+	logCxt.Debug("Processing Update request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Update", d.Key)
+	}
+	key, value, err := getKeyValueStrings(d)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := parseRevision(d.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	current, rev, err := c.write(ctx, key, d.TTL, func(current *entry) (*string, error) {
+		if current == nil {
+			return nil, cerrors.ErrorResourceDoesNotExist{Identifier: d.Key}
+		}
+		if current.revision != expected {
+			return nil, cerrors.ErrorResourceUpdateConflict{Identifier: d.Key}
+		}
+		return &value, nil
+	})
+	if err != nil {
+		logCxt.WithError(err).Debug("Update failed")
+		return existingKVPair(d.Key, current), err
+	}
+	return setValue(d, value, rev)
+}
+
+// Apply an entry to the datastore, creating or replacing it.
+func (c *postgresClient) Apply(ctx context.Context, d *model.KVPair) (*model.KVPair, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": d.Key, "value": d.Value, "ttl": d.TTL, "rev": d.Revision})
+	logCxt.Debug("Processing Apply request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Apply", d.Key)
+	}
+	key, value, err := getKeyValueStrings(d)
+	if err != nil {
+		return nil, err
+	}
+
+	_, rev, err := c.write(ctx, key, d.TTL, func(*entry) (*string, error) {
+		return &value, nil
+	})
+	if err != nil {
+		logCxt.WithError(err).Warning("Apply failed")
+		return nil, err
+	}
+	return setValue(d, value, rev)
+}
+
+func (c *postgresClient) DeleteKVP(ctx context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	return c.Delete(ctx, kvp.Key, kvp.Revision)
+}
+
+// Delete an entry in the datastore.  This errors if the entry does not exists.
+func (c *postgresClient) Delete(ctx context.Context, k model.Key, revision string) (*model.KVPair, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": k, "rev": revision})
+	logCxt.Debug("Processing Delete request")
+	if api.IsDryRun(ctx) {
+		return nil, api.DryRunNotSupported("Delete", k)
+	}
+	key, err := model.KeyToDefaultDeletePath(k)
+	if err != nil {
+		return nil, err
+	}
+	var expected int64
+	if revision != "" {
+		if expected, err = parseRevision(revision); err != nil {
+			return nil, err
+		}
+	}
+
+	current, _, err := c.write(ctx, key, 0, func(current *entry) (*string, error) {
+		if current == nil {
+			return nil, cerrors.ErrorResourceDoesNotExist{Identifier: k}
+		}
+		if revision != "" && current.revision != expected {
+			return nil, cerrors.ErrorResourceUpdateConflict{Identifier: k}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logCxt.WithError(err).Debug("Delete failed")
+		return existingKVPair(k, current), err
+	}
+
+	// Don't propagate a parse error of the deleted value, since the delete did succeed.
+	return existingKVPair(k, current), nil
+}
+
+// write runs a write of a path in a transaction that holds the write lock.  The update function
+// receives the current entry of the path, or nil if there is none, and returns the new value, or
+// nil to delete the path.  write returns the entry that the update function received, and the
+// revision of the write.
+func (c *postgresClient) write(
+	ctx context.Context,
+	path string,
+	ttl time.Duration,
+	update func(current *entry) (*string, error),
+) (*entry, int64, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, cerrors.ErrorDatastoreError{Err: err}
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, writeLockID); err != nil {
+		return nil, 0, cerrors.ErrorDatastoreError{Err: err}
+	}
+	current, err := getEntry(ctx, tx, path)
+	if err != nil {
+		return nil, 0, err
+	}
+	value, err := update(current)
+	if err != nil {
+		return current, 0, err
+	}
+
+	var prevValue *string
+	var prevRevision *int64
+	if current != nil {
+		prevValue = &current.value
+		prevRevision = &current.revision
+	}
+	var rev int64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO calico_kv_events (path, value, prev_value, prev_revision) VALUES ($1, $2, $3, $4) RETURNING revision`,
+		path, value, prevValue, prevRevision,
+	).Scan(&rev)
+	if err != nil {
+		return nil, 0, cerrors.ErrorDatastoreError{Err: err}
+	}
+
+	if value == nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM calico_kv WHERE path = $1`, path)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO calico_kv (path, value, revision, expires_at)
+			VALUES ($1, $2, $3, CASE WHEN $4::float8 > 0 THEN now() + $4::float8 * interval '1 second' END)
+			ON CONFLICT (path) DO UPDATE
+			SET value = EXCLUDED.value, revision = EXCLUDED.revision, expires_at = EXCLUDED.expires_at`,
+			path, *value, rev, ttl.Seconds(),
+		)
+	}
+	if err != nil {
+		return nil, 0, cerrors.ErrorDatastoreError{Err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, cerrors.ErrorDatastoreError{Err: err}
+	}
+	return current, rev, nil
+}
+
+// Get an entry from the datastore.  This errors if the entry does not exist.
+func (c *postgresClient) Get(ctx context.Context, k model.Key, revision string) (*model.KVPair, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": k, "rev": revision})
+	logCxt.Debug("Processing Get request")
+	key, err := model.KeyToDefaultPath(k)
+	if err != nil {
+		logCxt.Error("Unable to convert model.Key to a postgres key")
+		return nil, err
+	}
+
+	// Handle the static default-allow profile. Always return the default profile.
+	if key == defaultAllowProfileKey {
+		logCxt.Debug("Returning default-allow profile for get")
+		return resources.DefaultAllowProfile(), nil
+	}
+
+	var e *entry
+	if revision == "" {
+		e, err = getEntry(ctx, c.db, key)
+	} else {
+		var rev int64
+		if rev, err = parseRevision(revision); err != nil {
+			return nil, err
+		}
+		e, err = getPastEntry(ctx, c.db, key, rev)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		logCxt.Debug("No results returned from postgres")
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: k}
+	}
+	return toKVPair(k, e)
+}
+
+// getEntry returns the current entry of a path, or nil if there is none.
+func getEntry(ctx context.Context, q querier, path string) (*entry, error) {
+	e := entry{path: path}
+	err := q.QueryRowContext(ctx,
+		`SELECT value, revision FROM calico_kv WHERE path = $1 AND `+live, path,
+	).Scan(&e.value, &e.revision)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	return &e, nil
+}
+
+// getPastEntry returns the entry of a path at a revision, or nil if there was none.
+func getPastEntry(ctx context.Context, q querier, path string, rev int64) (*entry, error) {
+	e := entry{path: path}
+	var value sql.NullString
+	err := q.QueryRowContext(ctx,
+		`SELECT value, revision FROM calico_kv_events WHERE path = $1 AND revision <= $2
+		ORDER BY revision DESC LIMIT 1`, path, rev,
+	).Scan(&value, &e.revision)
+	if err == sql.ErrNoRows || (err == nil && !value.Valid) {
+		return nil, nil
+	} else if err != nil {
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	e.value = value.String
+	return &e, nil
+}
+
+// List entries in the datastore.  This may return an empty list of there are
+// no entries matching the request in the ListInterface.
+func (c *postgresClient) List(ctx context.Context, l model.ListInterface, revision string) (*model.KVPairList, error) {
+	logCxt := log.WithFields(log.Fields{"list-interface": l, "rev": revision})
+	logCxt.Debug("Processing List request")
+	key, match := listCondition(l)
+
+	var rev int64
+	if revision != "" {
+		var err error
+		if rev, err = parseRevision(revision); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read the revision and the entries from the same snapshot.
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var rows *sql.Rows
+	if revision == "" {
+		err = tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(revision), 0) FROM calico_kv_events`).Scan(&rev)
+		if err != nil {
+			return nil, cerrors.ErrorDatastoreError{Err: err}
+		}
+		rows, err = tx.QueryContext(ctx,
+			`SELECT path, value, revision FROM calico_kv WHERE `+match+` AND `+live+` ORDER BY path`, key)
+	} else {
+		rows, err = tx.QueryContext(ctx,
+			`SELECT path, value, revision FROM (
+				SELECT DISTINCT ON (path) path, value, revision FROM calico_kv_events
+				WHERE `+match+` AND revision <= $2 ORDER BY path, revision DESC
+			) AS past WHERE value IS NOT NULL ORDER BY path`, key, rev)
+	}
+	if err != nil {
+		logCxt.WithError(err).Debug("Error returned from postgres")
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	defer rows.Close()
+
+	list := []*model.KVPair{}
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.path, &e.value, &e.revision); err != nil {
+			return nil, cerrors.ErrorDatastoreError{Err: err}
+		}
+		if kv := convertListEntry(&e, l); kv != nil {
+			list = append(list, kv)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerrors.ErrorDatastoreError{Err: err}
+	}
+	logCxt.WithField("numResults", len(list)).Debug("Processed response from postgres")
+
+	// If we're listing profiles, we need to handle the statically defined
+	// default-allow profile in the resources package.
+	// We always include the default profile.
+	if key == profilesKey || key == defaultAllowProfileKey {
+		list = append(list, resources.DefaultAllowProfile())
+	}
+
+	return &model.KVPairList{
+		KVPairs:  list,
+		Revision: strconv.FormatInt(rev, 10),
+	}, nil
+}
+
+// listCondition returns the path to list and the condition on the path column that selects
+// the entries of the list.  The condition uses the path as its first parameter.  As for etcd, a
+// list that isn't fully qualified lists the children of its path, unless the last segment of
+// the path is a name prefix.
+func listCondition(l model.ListInterface) (string, string) {
+	key := model.ListOptionsToDefaultPathRoot(l)
+	if model.IsListOptionsLastSegmentPrefix(l) {
+		return key, "substr(path, 1, length($1)) = $1"
+	} else if !model.ListOptionsIsFullyQualified(l) {
+		if !strings.HasSuffix(key, "/") {
+			key += "/"
+		}
+		return key, "substr(path, 1, length($1)) = $1"
+	}
+	return key, "path = $1"
+}
+
+// convertListEntry converts an entry to a model.KVPair with a parsed value.  If the path
+// does not represent the resource specified by the ListInterface, or if value cannot be
+// parsed, this method returns nil.
+func convertListEntry(e *entry, l model.ListInterface) *model.KVPair {
+	if k := l.KeyFromDefaultPath(e.path); k != nil {
+		if kv, err := toKVPair(k, e); err == nil {
+			return kv
+		}
+	}
+	return nil
+}
+
+// EnsureInitialized makes sure that the tables of the datastore exist.
+func (c *postgresClient) EnsureInitialized() error {
+	ctx, cancel := context.WithTimeout(context.Background(), clientTimeout)
+	defer cancel()
+	if err := c.createSchema(ctx); err != nil {
+		return cerrors.ErrorDatastoreError{Err: err}
+	}
+	return nil
+}
+
+// Clean removes all of the Calico data from the datastore.  The watches see a delete event for
+// each of the entries.
+func (c *postgresClient) Clean() error {
+	log.Debug("Cleaning postgres datastore of all Calico data")
+	ctx := context.Background()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return cerrors.ErrorDatastoreError{Err: err}
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stmts := []string{
+		`SELECT pg_advisory_xact_lock(` + strconv.FormatInt(writeLockID, 10) + `)`,
+		`INSERT INTO calico_kv_events (path, prev_value, prev_revision)
+		SELECT path, value, revision FROM calico_kv ORDER BY path`,
+		`DELETE FROM calico_kv`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return cerrors.ErrorDatastoreError{Err: err}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return cerrors.ErrorDatastoreError{Err: err}
+	}
+	return nil
+}
+
+// IsClean() returns true if there are no Calico entries in the datastore.  This is not part of
+// the exposed API, but is public to allow direct consumers of the backend API to access this.
+func (c *postgresClient) IsClean() (bool, error) {
+	var found bool
+	err := c.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM calico_kv WHERE `+live+`)`,
+	).Scan(&found)
+	if err != nil {
+		return false, cerrors.ErrorDatastoreError{Err: err}
+	}
+	return !found, nil
+}
+
+// expire deletes the entries whose TTL has passed, with a delete event for each of them.
+func (c *postgresClient) expire(ctx context.Context) error {
+	var found bool
+	err := c.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM calico_kv WHERE expires_at <= now())`,
+	).Scan(&found)
+	if err != nil || !found {
+		return err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, writeLockID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`WITH expired AS (
+			DELETE FROM calico_kv WHERE expires_at <= now() RETURNING path, value, revision
+		)
+		INSERT INTO calico_kv_events (path, prev_value, prev_revision)
+		SELECT path, value, revision FROM expired ORDER BY path`,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getKeyValueStrings returns the path and serialized value calculated from the KVPair.
+func getKeyValueStrings(d *model.KVPair) (string, string, error) {
+	logCxt := log.WithFields(log.Fields{"model-key": d.Key, "value": d.Value})
+	key, err := model.KeyToDefaultPath(d.Key)
+	if err != nil {
+		logCxt.WithError(err).Error("Failed to convert model-key to postgres key")
+		return "", "", cerrors.ErrorDatastoreError{
+			Err:        err,
+			Identifier: d.Key,
+		}
+	}
+	bytes, err := model.SerializeValue(d)
+	if err != nil {
+		logCxt.WithError(err).Error("Failed to serialize value")
+		return "", "", cerrors.ErrorDatastoreError{
+			Err:        err,
+			Identifier: d.Key,
+		}
+	}
+
+	return key, string(bytes), nil
+}
+
+// setValue updates the KVPair of a successful write with the stored value and revision.
+func setValue(d *model.KVPair, value string, rev int64) (*model.KVPair, error) {
+	v, err := model.ParseValue(d.Key, []byte(value))
+	if err != nil {
+		return nil, cerrors.ErrorPartialFailure{Err: fmt.Errorf("Unexpected error parsing stored datastore entry '%v': %w", value, err)}
+	}
+	d.Value = v
+	d.Revision = strconv.FormatInt(rev, 10)
+	return d, nil
+}
+
+// toKVPair converts an entry into a model.KVPair.
+func toKVPair(k model.Key, e *entry) (*model.KVPair, error) {
+	v, err := model.ParseValue(k, []byte(e.value))
+	if err != nil {
+		return nil, cerrors.ErrorParsingDatastoreEntry{
+			RawKey:   e.path,
+			RawValue: e.value,
+			Err:      err,
+		}
+	}
+	return &model.KVPair{
+		Key:      k,
+		Value:    v,
+		Revision: strconv.FormatInt(e.revision, 10),
+	}, nil
+}
+
+// existingKVPair converts the entry of a failed write into a model.KVPair, for the errors that
+// return the current entry.  It returns nil if there is no entry or it cannot be parsed.
+func existingKVPair(k model.Key, e *entry) *model.KVPair {
+	if e == nil {
+		return nil
+	}
+	kv, _ := toKVPair(k, e)
+	return kv
+}
+
+// parseRevision parses the model.KVPair revision string and converts to the
+// equivalent event sequence number.
+func parseRevision(revs string) (int64, error) {
+	rev, err := strconv.ParseInt(revs, 10, 64)
+	if err != nil {
+		log.WithField("Revision", revs).Debug("Unable to parse Revision")
+		return 0, cerrors.ErrorValidation{
+			ErroredFields: []cerrors.ErroredField{
+				{
+					Name:  "ResourceVersion",
+					Value: revs,
+				},
+			},
+		}
+	}
+	return rev, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"database/sql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("Postgres driver", func() {
+	It("should be registered for the postgres datastore type", func() {
+		config := apiconfig.NewCalicoAPIConfig()
+		config.Spec.DatastoreType = apiconfig.Postgres
+		_, err := backend.NewClient(*config)
+		Expect(err).To(MatchError("no SQL data source name specified"))
+
+		config.Spec.SQLDriverName = "no-such-driver"
+		config.Spec.SQLDataSourceName = "postgres://localhost/calico"
+		_, err = backend.NewClient(*config)
+		Expect(err).To(MatchError(ContainSubstring(`unknown driver "no-such-driver"`)))
+	})
+
+	DescribeTable("list conditions",
+		func(l model.ListInterface, expectedKey, expectedMatch string) {
+			key, match := listCondition(l)
+			Expect(key).To(Equal(expectedKey))
+			Expect(match).To(Equal(expectedMatch))
+		},
+		Entry("a resource type",
+			model.GlobalConfigListOptions{},
+			"/calico/v1/config/", "substr(path, 1, length($1)) = $1"),
+		Entry("a single resource",
+			model.GlobalConfigListOptions{Name: "LogSeverityScreen"},
+			"/calico/v1/config/LogSeverityScreen", "path = $1"),
+		Entry("a name prefix",
+			model.ResourceListOptions{Kind: "IPPool", Name: "pool", Prefix: true},
+			"/calico/resources/v3/projectcalico.org/ippools/pool", "substr(path, 1, length($1)) = $1"),
+	)
+
+	Describe("watch events", func() {
+		l := model.GlobalConfigListOptions{}
+		key := model.GlobalConfigKey{Name: "LogSeverityScreen"}
+		path := "/calico/v1/config/LogSeverityScreen"
+		str := func(s string) sql.NullString {
+			return sql.NullString{String: s, Valid: true}
+		}
+
+		It("should convert the creates, updates and deletes", func() {
+			ae, err := convertWatchEvent(&event{revision: 5, path: path, value: str("Info")}, l)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ae).To(Equal(&api.WatchEvent{
+				Type: api.WatchAdded,
+				New:  &model.KVPair{Key: key, Value: "Info", Revision: "5"},
+			}))
+
+			ae, err = convertWatchEvent(&event{
+				revision:     7,
+				path:         path,
+				value:        str("Debug"),
+				prevValue:    str("Info"),
+				prevRevision: sql.NullInt64{Int64: 5, Valid: true},
+			}, l)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ae).To(Equal(&api.WatchEvent{
+				Type: api.WatchModified,
+				Old:  &model.KVPair{Key: key, Value: "Info", Revision: "5"},
+				New:  &model.KVPair{Key: key, Value: "Debug", Revision: "7"},
+			}))
+
+			ae, err = convertWatchEvent(&event{
+				revision:     8,
+				path:         path,
+				prevValue:    str("Debug"),
+				prevRevision: sql.NullInt64{Int64: 7, Valid: true},
+			}, l)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ae).To(Equal(&api.WatchEvent{
+				Type: api.WatchDeleted,
+				Old:  &model.KVPair{Key: key, Value: "Debug", Revision: "7"},
+			}))
+		})
+
+		It("should filter the events of other resource types", func() {
+			ae, err := convertWatchEvent(&event{revision: 5, path: "/calico/v1/host/node1/config/LogSeverityScreen", value: str("Info")}, l)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ae).To(BeNil())
+		})
+
+		It("should return an error for a value that cannot be parsed", func() {
+			poolPath := "/calico/resources/v3/projectcalico.org/ippools/pool1"
+			_, err := convertWatchEvent(&event{revision: 5, path: poolPath, value: str("{")}, model.ResourceListOptions{Kind: "IPPool"})
+			Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorParsingDatastoreEntry{}))
+		})
+	})
+})
@@ -0,0 +1,314 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+const (
+	resultsBufSize = 100
+
+	// eventsBatchSize is the maximum number of events that a watch reads per query.
+	eventsBatchSize = 1000
+)
+
+// event is a row of calico_kv_events.  The value is null for a delete, and the previous value
+// is null for a create.
+type event struct {
+	revision     int64
+	path         string
+	value        sql.NullString
+	prevValue    sql.NullString
+	prevRevision sql.NullInt64
+}
+
+// Watch entries in the datastore matching the resources specified by the ListInterface.
+func (c *postgresClient) Watch(cxt context.Context, l model.ListInterface, revision string) (api.WatchInterface, error) {
+	var rev int64
+	if len(revision) != 0 {
+		var err error
+		rev, err = strconv.ParseInt(revision, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wc := &watcher{
+		client:     c,
+		list:       l,
+		initialRev: rev,
+		resultChan: make(chan api.WatchEvent, resultsBufSize),
+	}
+	wc.ctx, wc.cancel = context.WithCancel(cxt)
+	go wc.watchLoop()
+	return wc, nil
+}
+
+// watcher implements watch.Interface.
+type watcher struct {
+	client     *postgresClient
+	initialRev int64
+	ctx        context.Context
+	cancel     context.CancelFunc
+	resultChan chan api.WatchEvent
+	list       model.ListInterface
+	terminated uint32
+}
+
+// Stop stops the watcher and releases associated resources.
+// This calls through to the context cancel function.
+func (wc *watcher) Stop() {
+	wc.cancel()
+}
+
+// ResultChan returns a channel used to receive WatchEvents.
+func (wc *watcher) ResultChan() <-chan api.WatchEvent {
+	return wc.resultChan
+}
+
+// HasTerminated returns true when the watcher has completed termination processing.
+func (wc *watcher) HasTerminated() bool {
+	return atomic.LoadUint32(&wc.terminated) != 0
+}
+
+// watchLoop polls the event table for the events after the current revision, and sends
+// them for internal processing.
+func (wc *watcher) watchLoop() {
+	// When this loop exits, make sure we terminate the watcher resources.
+	defer wc.terminateWatcher()
+
+	key, match := listCondition(wc.list)
+	log.Debug("Starting watcher.watchLoop")
+	if wc.initialRev == 0 {
+		// No initial revision supplied, so perform a list of current configuration
+		// which will also get the current revision we will start our watch from.
+		kvps, err := wc.listCurrent()
+		if err != nil {
+			log.Errorf("failed to list current with latest state: %v", err)
+			// Error considered as terminating error, hence terminate watcher.
+			wc.sendError(err)
+			return
+		}
+
+		// If we're handling profiles, filter out the default-allow profile.
+		if len(kvps.KVPairs) > 0 && (key == profilesKey || key == defaultAllowProfileKey) {
+			wc.removeDefaultAllowProfile(kvps)
+		}
+
+		// We are sending an initial sync of entries to the watcher to provide current
+		// state.  To the perspective of the watcher, these are added entries, so set the
+		// event type to WatchAdded.
+		log.WithField("NumEntries", len(kvps.KVPairs)).Debug("Sending create events for each existing entry")
+		wc.sendAddedEvents(kvps)
+	}
+
+	logCxt := log.WithFields(log.Fields{"list": wc.list, "key": key})
+	logCxt.WithField("rev", wc.initialRev).Debug("Starting postgres watch")
+	rev := wc.initialRev
+	ticker := time.NewTicker(wc.client.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := wc.client.expire(wc.ctx); err != nil && wc.ctx.Err() == nil {
+			logCxt.WithError(err).Warning("Failed to delete the expired entries")
+		}
+
+		events, err := wc.client.eventsAfter(wc.ctx, key, match, rev)
+		if err != nil {
+			if wc.ctx.Err() != nil {
+				return
+			}
+			// A failed poll is a terminating event, so exit the loop.
+			logCxt.WithError(err).Warning("Failed to read the events")
+			wc.sendError(err)
+			return
+		}
+		for _, e := range events {
+			rev = e.revision
+			// Convert the event to the equivalent Watcher event.  An error parsing the event
+			// is returned as an error, but don't exit the watcher as restarting the watcher
+			// is unlikely to fix the conversion error.
+			if ae, err := convertWatchEvent(e, wc.list); ae != nil {
+				wc.sendEvent(ae)
+			} else if err != nil {
+				wc.sendError(err)
+			}
+		}
+		if len(events) == eventsBatchSize {
+			// There may be more events waiting.
+			continue
+		}
+
+		select {
+		case <-wc.ctx.Done():
+			logCxt.Debug("Postgres watch stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// eventsAfter returns the next batch of events after a revision that match a list condition.
+func (c *postgresClient) eventsAfter(ctx context.Context, key, match string, rev int64) ([]*event, error) {
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT revision, path, value, prev_value, prev_revision FROM calico_kv_events
+		WHERE `+match+` AND revision > $2 ORDER BY revision LIMIT `+strconv.Itoa(eventsBatchSize),
+		key, rev,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*event
+	for rows.Next() {
+		var e event
+		if err := rows.Scan(&e.revision, &e.path, &e.value, &e.prevValue, &e.prevRevision); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// convertWatchEvent converts an event to an api.WatchEvent, or nil if the event did not
+// correspond to an event that we are interested in.
+func convertWatchEvent(e *event, l model.ListInterface) (*api.WatchEvent, error) {
+	k := l.KeyFromDefaultPath(e.path)
+	if k == nil {
+		log.WithField("key", e.path).Debug("key filtered")
+		return nil, nil
+	}
+
+	var eventType api.WatchEventType
+	switch {
+	case !e.value.Valid:
+		eventType = api.WatchDeleted
+	case !e.prevValue.Valid:
+		eventType = api.WatchAdded
+	default:
+		eventType = api.WatchModified
+	}
+
+	var oldKV, newKV *model.KVPair
+	var err error
+	if e.value.Valid {
+		if newKV, err = toKVPair(k, &entry{path: e.path, value: e.value.String, revision: e.revision}); err != nil {
+			return nil, err
+		}
+	}
+	if e.prevValue.Valid {
+		prev := &entry{path: e.path, value: e.prevValue.String, revision: e.prevRevision.Int64}
+		if oldKV, err = toKVPair(k, prev); err != nil {
+			return nil, err
+		}
+	}
+
+	return &api.WatchEvent{
+		Old:  oldKV,
+		New:  newKV,
+		Type: eventType,
+	}, nil
+}
+
+// listCurrent retrieves the existing entries.
+func (wc *watcher) listCurrent() (*model.KVPairList, error) {
+	log.Info("Performing initial list with no revision")
+	list, err := wc.client.List(wc.ctx, wc.list, "")
+	if err != nil {
+		return nil, err
+	}
+
+	wc.initialRev, err = strconv.ParseInt(list.Revision, 10, 64)
+	if err != nil {
+		log.WithError(err).Error("List returned revision that could not be parsed")
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// removeDefaultAllowProfile filters out the default-allow profile out of the
+// given kvps list.
+func (wc *watcher) removeDefaultAllowProfile(list *model.KVPairList) {
+	log.Debugf("Filtering the default-allow profile out of the kvps list")
+	n := 0
+	s := list.KVPairs
+	for _, kvp := range s {
+		if kvp.Key != defaultAllowProfileResourceKey {
+			s[n] = kvp
+			n++
+		}
+	}
+	list.KVPairs = s[:n]
+}
+
+// sendAddedEvents sends an ADDED event for each entry in the kvp list.
+func (wc *watcher) sendAddedEvents(list *model.KVPairList) {
+	for _, kv := range list.KVPairs {
+		wc.sendEvent(&api.WatchEvent{
+			Type: api.WatchAdded,
+			New:  kv,
+		})
+	}
+}
+
+// terminateWatcher terminates the resources associated with the watcher.
+func (wc *watcher) terminateWatcher() {
+	log.Debug("Terminating postgres watcher")
+	wc.cancel()
+
+	// Close the results channel.
+	close(wc.resultChan)
+
+	// Increment the terminated counter using a goroutine safe operation.
+	atomic.AddUint32(&wc.terminated, 1)
+}
+
+// sendError packages up the error as an event and sends it in the results channel.
+func (wc *watcher) sendError(err error) {
+	// Skip over a context.Canceled error, the error processing in the main watch thread will
+	// terminate the watcher.
+	if err == context.Canceled {
+		return
+	}
+
+	// Wrap the error up in a WatchEvent and use sendEvent to send it.
+	errEvent := &api.WatchEvent{
+		Type:  api.WatchError,
+		Error: err,
+	}
+	wc.sendEvent(errEvent)
+}
+
+// sendEvent sends an event in the results channel.
+func (wc *watcher) sendEvent(e *api.WatchEvent) {
+	if len(wc.resultChan) == resultsBufSize {
+		log.Warningf("Watch events backing up: %d events", resultsBufSize)
+	}
+	select {
+	case wc.resultChan <- *e:
+	case <-wc.ctx.Done():
+	}
+}
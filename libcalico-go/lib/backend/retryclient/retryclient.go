@@ -0,0 +1,309 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retryclient wraps a backend client to retry the operations that fail with transient
+// datastore errors, such as connection resets and etcd leader changes, with an exponential
+// backoff and jitter.
+package retryclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var (
+	counterRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "calico_client_retries",
+		Help: "Number of datastore operations retried after a transient error, by operation.",
+	}, []string{"operation"})
+	counterRetriesExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "calico_client_retries_exhausted",
+		Help: "Number of datastore operations that failed with a transient error after all their attempts, by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		counterRetries,
+		counterRetriesExhausted,
+	)
+}
+
+// Operation identifies a backend client operation, for the per-operation policies and the
+// metrics.
+type Operation string
+
+const (
+	OpCreate            Operation = "create"
+	OpUpdate            Operation = "update"
+	OpApply             Operation = "apply"
+	OpDelete            Operation = "delete"
+	OpGet               Operation = "get"
+	OpList              Operation = "list"
+	OpWatch             Operation = "watch"
+	OpEnsureInitialized Operation = "ensureInitialized"
+	OpClean             Operation = "clean"
+)
+
+// Policy is the retry policy of an operation.  The delay before the nth retry is
+// InitialBackoff * Multiplier^(n-1), capped at MaxBackoff, then randomized by up to
+// +/- Jitter of itself.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.  A value of 1 or
+	// less disables the retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the fraction, between 0 and 1, of the delay that is randomized.
+	Jitter float64
+}
+
+// DefaultPolicy returns the policy that the client uses for the operations without an
+// override.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// backoff returns the delay before the retry that follows the given attempt, counted from 1.
+func (p Policy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt && d < float64(p.MaxBackoff); i++ {
+		d *= p.Multiplier
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	d += d * p.Jitter * (2*rand.Float64() - 1)
+	return time.Duration(d)
+}
+
+// Option is an option of the client.
+type Option func(*Client)
+
+// WithOperationPolicy overrides the policy of an operation.
+func WithOperationPolicy(op Operation, policy Policy) Option {
+	return func(c *Client) {
+		c.overrides[op] = policy
+	}
+}
+
+// Client is a backend client that retries the operations that fail with a transient error,
+// until they succeed, fail with another error, run out of attempts, or their context is done.
+//
+// The retries of the writes are not idempotent: a write that succeeded but whose response was
+// lost is retried, so a retried Create may return an ErrorResourceAlreadyExists for its own
+// write, and a retried Update or Delete an ErrorResourceUpdateConflict.  Only the creation of a
+// watch is retried; the events of a watch are passed through as they are.
+type Client struct {
+	api.Client
+
+	policy    Policy
+	overrides map[Operation]Policy
+	// sleep waits for the backoff delay, or until the context is done.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New returns a client that retries the operations of the backend client with the given
+// policy, unless an option overrides it.
+func New(client api.Client, policy Policy, opts ...Option) *Client {
+	c := &Client{
+		Client:    client,
+		policy:    policy,
+		overrides: map[Operation]Policy{},
+		sleep:     sleep,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+func (c *Client) Create(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpCreate, func() (*model.KVPair, error) {
+		return c.Client.Create(ctx, object)
+	})
+}
+
+func (c *Client) Update(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpUpdate, func() (*model.KVPair, error) {
+		return c.Client.Update(ctx, object)
+	})
+}
+
+func (c *Client) Apply(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpApply, func() (*model.KVPair, error) {
+		return c.Client.Apply(ctx, object)
+	})
+}
+
+func (c *Client) Delete(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpDelete, func() (*model.KVPair, error) {
+		return c.Client.Delete(ctx, key, revision)
+	})
+}
+
+func (c *Client) DeleteKVP(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpDelete, func() (*model.KVPair, error) {
+		return c.Client.DeleteKVP(ctx, object)
+	})
+}
+
+func (c *Client) Get(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	return c.doKVP(ctx, OpGet, func() (*model.KVPair, error) {
+		return c.Client.Get(ctx, key, revision)
+	})
+}
+
+func (c *Client) List(ctx context.Context, list model.ListInterface, revision string) (kvps *model.KVPairList, err error) {
+	err = c.do(ctx, OpList, func() error {
+		kvps, err = c.Client.List(ctx, list, revision)
+		return err
+	})
+	return
+}
+
+func (c *Client) ListPage(ctx context.Context, list model.ListInterface, revision string, limit int64, continueToken string) (kvps *model.KVPairList, err error) {
+	err = c.do(ctx, OpList, func() error {
+		kvps, err = api.ListPaged(ctx, c.Client, list, revision, limit, continueToken)
+		return err
+	})
+	return
+}
+
+func (c *Client) Watch(ctx context.Context, list model.ListInterface, revision string) (w api.WatchInterface, err error) {
+	err = c.do(ctx, OpWatch, func() error {
+		w, err = c.Client.Watch(ctx, list, revision)
+		return err
+	})
+	return
+}
+
+func (c *Client) EnsureInitialized() error {
+	return c.do(context.Background(), OpEnsureInitialized, c.Client.EnsureInitialized)
+}
+
+func (c *Client) Clean() error {
+	return c.do(context.Background(), OpClean, c.Client.Clean)
+}
+
+func (c *Client) doKVP(ctx context.Context, op Operation, f func() (*model.KVPair, error)) (kvp *model.KVPair, err error) {
+	err = c.do(ctx, op, func() error {
+		kvp, err = f()
+		return err
+	})
+	return
+}
+
+// do runs an operation, retrying it while it fails with a transient error.
+func (c *Client) do(ctx context.Context, op Operation, f func() error) error {
+	policy, ok := c.overrides[op]
+	if !ok {
+		policy = c.policy
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !IsTransient(err) {
+			return err
+		}
+		logCxt := log.WithError(err).WithFields(log.Fields{"operation": op, "attempt": attempt})
+		if attempt >= policy.MaxAttempts {
+			if policy.MaxAttempts > 1 {
+				logCxt.Warning("Datastore operation failed, out of retries")
+				counterRetriesExhausted.WithLabelValues(string(op)).Inc()
+			}
+			return err
+		}
+		delay := policy.backoff(attempt)
+		logCxt.WithField("delay", delay).Info("Datastore operation failed with a transient error, will retry")
+		if c.sleep(ctx, delay) != nil {
+			return err
+		}
+		counterRetries.WithLabelValues(string(op)).Inc()
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// IsTransient returns whether an error of a backend client is a transient datastore error,
+// that the same operation may not hit if it is retried.
+func IsTransient(err error) bool {
+	if e, ok := err.(cerrors.ErrorDatastoreError); ok {
+		err = e.Err
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.Is(err, rpctypes.ErrLeaderChanged),
+		errors.Is(err, rpctypes.ErrNoLeader),
+		errors.Is(err, rpctypes.ErrTimeout),
+		errors.Is(err, rpctypes.ErrTimeoutDueToLeaderFail),
+		errors.Is(err, rpctypes.ErrTimeoutDueToConnectionLost):
+		return true
+	case kerrors.IsServerTimeout(err),
+		kerrors.IsTimeout(err),
+		kerrors.IsTooManyRequests(err),
+		kerrors.IsServiceUnavailable(err):
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	return false
+}
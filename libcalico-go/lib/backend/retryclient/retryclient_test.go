@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retryclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("Retry client", func() {
+	var backend *fakeBackend
+	var client *Client
+	var delays []time.Duration
+
+	key := model.GlobalConfigKey{Name: "LogSeverityScreen"}
+	transient := cerrors.ErrorDatastoreError{Err: rpctypes.ErrLeaderChanged}
+	policy := Policy{
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	newClient := func(opts ...Option) {
+		client = New(backend, policy, opts...)
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return ctx.Err()
+		}
+	}
+
+	BeforeEach(func() {
+		backend = &fakeBackend{}
+		delays = nil
+		newClient()
+	})
+
+	It("should retry the transient errors with an exponential backoff", func() {
+		backend.errs = []error{transient, transient, transient}
+		retries := testutil.ToFloat64(counterRetries.WithLabelValues("get"))
+
+		kvp, err := client.Get(context.Background(), key, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvp.Key).To(Equal(key))
+		Expect(backend.calls).To(Equal(4))
+		Expect(delays).To(Equal([]time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			300 * time.Millisecond,
+		}))
+		Expect(testutil.ToFloat64(counterRetries.WithLabelValues("get")) - retries).To(Equal(3.0))
+	})
+
+	It("should give up after the last attempt", func() {
+		backend.errs = []error{transient, transient, transient, transient, transient}
+		exhausted := testutil.ToFloat64(counterRetriesExhausted.WithLabelValues("apply"))
+
+		_, err := client.Apply(context.Background(), &model.KVPair{Key: key, Value: "Info"})
+		Expect(err).To(Equal(transient))
+		Expect(backend.calls).To(Equal(4))
+		Expect(testutil.ToFloat64(counterRetriesExhausted.WithLabelValues("apply")) - exhausted).To(Equal(1.0))
+	})
+
+	It("should not retry the other errors", func() {
+		backend.errs = []error{cerrors.ErrorResourceUpdateConflict{Identifier: key}}
+		_, err := client.Update(context.Background(), &model.KVPair{Key: key, Value: "Info", Revision: "1"})
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorResourceUpdateConflict{}))
+		Expect(backend.calls).To(Equal(1))
+	})
+
+	It("should stop retrying when the context is done", func() {
+		backend.errs = []error{transient, transient}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.Delete(ctx, key, "")
+		Expect(err).To(Equal(transient))
+		Expect(backend.calls).To(Equal(1))
+	})
+
+	It("should use the policies of the operations that have one", func() {
+		newClient(WithOperationPolicy(OpCreate, Policy{MaxAttempts: 1}))
+		backend.errs = []error{transient, transient}
+		_, err := client.Create(context.Background(), &model.KVPair{Key: key, Value: "Info"})
+		Expect(err).To(Equal(transient))
+		Expect(backend.calls).To(Equal(1))
+
+		_, err = client.List(context.Background(), model.GlobalConfigListOptions{}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backend.calls).To(Equal(3))
+	})
+
+	It("should randomize the backoff by up to the jitter", func() {
+		p := Policy{InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 2, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			Expect(p.backoff(3)).To(BeNumerically("~", time.Second, 500*time.Millisecond))
+		}
+	})
+
+	DescribeTable("transient errors",
+		func(err error, expected bool) {
+			Expect(IsTransient(err)).To(Equal(expected))
+		},
+		Entry("connection reset", cerrors.ErrorDatastoreError{Err: fmt.Errorf("read: %w", syscall.ECONNRESET)}, true),
+		Entry("etcd leader change", cerrors.ErrorDatastoreError{Err: rpctypes.ErrLeaderChanged}, true),
+		Entry("gRPC unavailable", status.Error(codes.Unavailable, "connection closed"), true),
+		Entry("Kubernetes server timeout", kerrors.NewServerTimeout(schema.GroupResource{}, "get", 1), true),
+		Entry("Kubernetes throttling", kerrors.NewTooManyRequests("slow down", 1), true),
+		Entry("context cancelled", cerrors.ErrorDatastoreError{Err: context.Canceled}, false),
+		Entry("missing resource", cerrors.ErrorResourceDoesNotExist{}, false),
+		Entry("other datastore error", cerrors.ErrorDatastoreError{Err: errors.New("bad request")}, false),
+	)
+})
+
+type fakeBackend struct {
+	api.Client
+
+	errs  []error
+	calls int
+}
+
+// call returns the next of the errors that the backend fails with, or nil.
+func (b *fakeBackend) call() error {
+	b.calls++
+	if len(b.errs) == 0 {
+		return nil
+	}
+	err := b.errs[0]
+	b.errs = b.errs[1:]
+	return err
+}
+
+func (b *fakeBackend) Create(_ context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	return kvp, b.call()
+}
+
+func (b *fakeBackend) Update(_ context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	return kvp, b.call()
+}
+
+func (b *fakeBackend) Apply(_ context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	return kvp, b.call()
+}
+
+func (b *fakeBackend) Delete(_ context.Context, key model.Key, _ string) (*model.KVPair, error) {
+	return &model.KVPair{Key: key}, b.call()
+}
+
+func (b *fakeBackend) Get(_ context.Context, key model.Key, _ string) (*model.KVPair, error) {
+	return &model.KVPair{Key: key, Value: "Info"}, b.call()
+}
+
+func (b *fakeBackend) List(context.Context, model.ListInterface, string) (*model.KVPairList, error) {
+	return &model.KVPairList{}, b.call()
+}
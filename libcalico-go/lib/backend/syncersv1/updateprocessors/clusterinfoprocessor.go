@@ -40,6 +40,7 @@ func NewClusterInfoUpdateProcessor() watchersyncer.SyncerUpdateProcessor {
 			}
 			return model.GlobalConfigKey{Name: name}
 		},
+		nil,
 		map[string]ConfigFieldValueToV1ModelValue{
 			"DatastoreReady": datastoreReadyToBool,
 		},
@@ -51,6 +51,9 @@ const (
 // - `default` for global
 // - `node.<nodename>` for per-node
 //
+// If an overrideConfigKeyFn is specified, resources with any other name are converted to
+// override config keys, otherwise their names are invalid.
+//
 // If allowAnnotations is set to true, then this helper will also check the annotations
 // for additional config key/values.  An annotation prefixed with "config.projectcalico.org/"
 // will be used (prefix removed) as the config key, and the value of the annotation used as
@@ -62,6 +65,7 @@ func NewConfigUpdateProcessor(
 	allowAnnotations bool,
 	nodeConfigKeyFn NodeConfigKeyFn,
 	globalConfigKeyFn GlobalConfigKeyFn,
+	overrideConfigKeyFn OverrideConfigKeyFn,
 	valueConverters map[string]ConfigFieldValueToV1ModelValue,
 ) watchersyncer.SyncerUpdateProcessor {
 	names := make(map[string]struct{}, specType.NumField())
@@ -69,13 +73,14 @@ func NewConfigUpdateProcessor(
 		names[getConfigName(specType.Field(i))] = struct{}{}
 	}
 	return &configUpdateProcessor{
-		specType:          specType,
-		allowAnnotations:  allowAnnotations,
-		nodeConfigKeyFn:   nodeConfigKeyFn,
-		globalConfigKeyFn: globalConfigKeyFn,
-		names:             names,
-		additionalNames:   map[string]struct{}{},
-		valueConverters:   valueConverters,
+		specType:            specType,
+		allowAnnotations:    allowAnnotations,
+		nodeConfigKeyFn:     nodeConfigKeyFn,
+		globalConfigKeyFn:   globalConfigKeyFn,
+		overrideConfigKeyFn: overrideConfigKeyFn,
+		names:               names,
+		additionalNames:     map[string]struct{}{},
+		valueConverters:     valueConverters,
 	}
 }
 
@@ -85,6 +90,9 @@ type NodeConfigKeyFn func(node, name string) model.Key
 // Convert the config name to the corresponding global config key
 type GlobalConfigKeyFn func(name string) model.Key
 
+// Convert the override and config name to the corresponding override config key
+type OverrideConfigKeyFn func(override, name string) model.Key
+
 // Convert an arbitrary value to the value used in the v1 model.
 type ConfigFieldValueToV1ModelValue func(value interface{}) interface{}
 
@@ -114,13 +122,14 @@ var (
 // annotations.  This is used as a simplistic mechanism for sending deletes for config
 // removed from an annotation.
 type configUpdateProcessor struct {
-	specType          reflect.Type
-	allowAnnotations  bool
-	nodeConfigKeyFn   NodeConfigKeyFn
-	globalConfigKeyFn GlobalConfigKeyFn
-	names             map[string]struct{}
-	additionalNames   map[string]struct{}
-	valueConverters   map[string]ConfigFieldValueToV1ModelValue
+	specType            reflect.Type
+	allowAnnotations    bool
+	nodeConfigKeyFn     NodeConfigKeyFn
+	globalConfigKeyFn   GlobalConfigKeyFn
+	overrideConfigKeyFn OverrideConfigKeyFn
+	names               map[string]struct{}
+	additionalNames     map[string]struct{}
+	valueConverters     map[string]ConfigFieldValueToV1ModelValue
 }
 
 func (c *configUpdateProcessor) Process(kvp *model.KVPair) ([]*model.KVPair, error) {
@@ -133,7 +142,7 @@ func (c *configUpdateProcessor) Process(kvp *model.KVPair) ([]*model.KVPair, err
 
 // processDeleted is called when the syncer is processing a delete event.
 func (c *configUpdateProcessor) processDeleted(kvp *model.KVPair) ([]*model.KVPair, error) {
-	node, err := c.extractNode(kvp.Key)
+	scope, err := c.extractScope(kvp.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -142,13 +151,13 @@ func (c *configUpdateProcessor) processDeleted(kvp *model.KVPair) ([]*model.KVPa
 	i := 0
 	for name := range c.names {
 		kvps[i] = &model.KVPair{
-			Key: c.createV1Key(node, name),
+			Key: c.createV1Key(scope, name),
 		}
 		i++
 	}
 	for name := range c.additionalNames {
 		kvps[i] = &model.KVPair{
-			Key: c.createV1Key(node, name),
+			Key: c.createV1Key(scope, name),
 		}
 		i++
 	}
@@ -158,7 +167,7 @@ func (c *configUpdateProcessor) processDeleted(kvp *model.KVPair) ([]*model.KVPa
 
 // processAddOrModified is called when the syncer is processing either a New or Updated event.
 func (c *configUpdateProcessor) processAddOrModified(kvp *model.KVPair) ([]*model.KVPair, error) {
-	node, err := c.extractNode(kvp.Key)
+	scope, err := c.extractScope(kvp.Key)
 	if err != nil {
 		log.WithField("Key", kvp.Key).Warning("Failed to extract node/global name from key")
 		return nil, err
@@ -188,7 +197,7 @@ func (c *configUpdateProcessor) processAddOrModified(kvp *model.KVPair) ([]*mode
 		fieldInfo := c.specType.Field(i)
 		name := getConfigName(fieldInfo)
 
-		key := c.createV1Key(node, name)
+		key := c.createV1Key(scope, name)
 		if key == nil {
 			log.WithField("name", name).Debug("No associated v1 key, skipping field")
 			continue
@@ -280,7 +289,7 @@ func (c *configUpdateProcessor) processAddOrModified(kvp *model.KVPair) ([]*mode
 			value = nil
 		}
 		kvps = append(kvps, &model.KVPair{
-			Key:      c.createV1Key(node, name),
+			Key:      c.createV1Key(scope, name),
 			Value:    value,
 			Revision: kvp.Revision,
 		})
@@ -290,7 +299,7 @@ func (c *configUpdateProcessor) processAddOrModified(kvp *model.KVPair) ([]*mode
 	// include them in the additionalNames.
 	for name, value := range overrides {
 		kvps = append(kvps, &model.KVPair{
-			Key:      c.createV1Key(node, name),
+			Key:      c.createV1Key(scope, name),
 			Value:    value,
 			Revision: kvp.Revision,
 		})
@@ -330,34 +339,46 @@ func (c *configUpdateProcessor) extractAnnotations(kvp *model.KVPair) (map[strin
 	return overrides, nil
 }
 
-// extractNode returns the name of the Node for which this configuration is for.  A empty string
-// indicates that this is global configuration.
+// configScope identifies what a configuration resource applies to: the node it is for, the
+// override it is, or neither for the global configuration.
+type configScope struct {
+	node     string
+	override string
+}
+
+// extractScope returns the scope of the configuration resource.
 //
-// Currently the name of a configuration resource has a strict format.  It is either "default"
-// for the global default values, or "node.<nodename>" for the node specific vales.  Returns an
-// error if the name is in neither format.
-func (c *configUpdateProcessor) extractNode(key model.Key) (string, error) {
+// The name of a configuration resource has a strict format.  It is either "default" for the
+// global default values, or "node.<nodename>" for the node specific vales.  If the processor
+// supports overrides, any other name is the name of an override.  Returns an error if the name
+// is in neither format.
+func (c *configUpdateProcessor) extractScope(key model.Key) (configScope, error) {
 	k, ok := key.(model.ResourceKey)
 	if !ok {
-		return "", errors.New("Unexpected key type in conversion")
+		return configScope{}, errors.New("Unexpected key type in conversion")
 	}
 	switch {
 	case k.Name == globalConfigName:
-		return "", nil
+		return configScope{}, nil
 	case strings.HasPrefix(k.Name, perNodeConfigNamePrefix):
-		return k.Name[len(perNodeConfigNamePrefix):], nil
+		return configScope{node: k.Name[len(perNodeConfigNamePrefix):]}, nil
+	case c.overrideConfigKeyFn != nil:
+		return configScope{override: k.Name}, nil
 	default:
-		return "", cerrors.ErrorParsingDatastoreEntry{RawKey: k.Name}
+		return configScope{}, cerrors.ErrorParsingDatastoreEntry{RawKey: k.Name}
 	}
 }
 
-// Create the appropriate v1 config key depending on whether this global or node specific
-// configuration.
-func (c *configUpdateProcessor) createV1Key(node, name string) model.Key {
-	if node == "" {
+// Create the appropriate v1 config key depending on whether this global, node specific or
+// override configuration.
+func (c *configUpdateProcessor) createV1Key(scope configScope, name string) model.Key {
+	switch {
+	case scope.node != "":
+		return c.nodeConfigKeyFn(scope.node, name)
+	case scope.override != "":
+		return c.overrideConfigKeyFn(scope.override, name)
+	default:
 		return c.globalConfigKeyFn(name)
-	} else {
-		return c.nodeConfigKeyFn(node, name)
 	}
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
@@ -45,7 +46,7 @@ const (
 )
 
 const (
-	numBaseFelixConfigs = 138
+	numBaseFelixConfigs = 197
 )
 
 var _ = Describe("Test the generic configuration update processor and the concrete implementations", func() {
@@ -58,7 +59,7 @@ var _ = Describe("Test the generic configuration update processor and the concre
 		Kind: apiv3.KindFelixConfiguration,
 		Name: "default",
 	}
-	invalidFelixKey := model.ResourceKey{
+	overrideFelixKey := model.ResourceKey{
 		Kind: apiv3.KindFelixConfiguration,
 		Name: "foobar",
 	}
@@ -168,19 +169,60 @@ var _ = Describe("Test the generic configuration update processor and the concre
 		Expect(err).To(HaveOccurred())
 
 		By("Testing incorrect name structure on Process with add/mod")
+		cc = updateprocessors.NewClusterInfoUpdateProcessor()
+		invalidClusterKey := model.ResourceKey{
+			Kind: apiv3.KindClusterInformation,
+			Name: "foobar",
+		}
 		_, err = cc.Process(&model.KVPair{
-			Key:   invalidFelixKey,
-			Value: apiv3.NewFelixConfiguration(),
+			Key:   invalidClusterKey,
+			Value: apiv3.NewClusterInformation(),
 		})
 		Expect(err).To(HaveOccurred())
 
 		By("Testing incorrect name structure on Process with delete")
 		_, err = cc.Process(&model.KVPair{
-			Key: invalidFelixKey,
+			Key: invalidClusterKey,
 		})
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should convert felix configs with other names to override configs", func() {
+		cc := updateprocessors.NewFelixConfigUpdateProcessor()
+		res := apiv3.NewFelixConfiguration()
+		res.Spec.NodeSelector = "pool == 'gpu'"
+		res.Spec.BPFMapSizeConntrack = &[]int{1048576}[0]
+		kvps, err := cc.Process(&model.KVPair{
+			Key:   overrideFelixKey,
+			Value: res,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(HaveLen(numFelixConfigs))
+		values := map[string]interface{}{}
+		for _, kvp := range kvps {
+			Expect(kvp.Key).To(BeAssignableToTypeOf(model.OverrideConfigKey{}))
+			Expect(kvp.Key.(model.OverrideConfigKey).OverrideName).To(Equal("foobar"))
+			if kvp.Value != nil {
+				values[kvp.Key.(model.OverrideConfigKey).Name] = kvp.Value
+			}
+		}
+		Expect(values).To(Equal(map[string]interface{}{
+			"NodeSelector":        "pool == 'gpu'",
+			"BPFMapSizeConntrack": "1048576",
+		}))
+
+		By("deleting all the override configs")
+		kvps, err = cc.Process(&model.KVPair{
+			Key: overrideFelixKey,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(HaveLen(numFelixConfigs))
+		for _, kvp := range kvps {
+			Expect(kvp.Key).To(BeAssignableToTypeOf(model.OverrideConfigKey{}))
+			Expect(kvp.Value).To(BeNil())
+		}
+	})
+
 	It("should handle different field types being assigned", func() {
 		cc := updateprocessors.NewFelixConfigUpdateProcessor()
 		By("converting a per-node felix KVPair with certain values and checking for the correct number of fields")
@@ -219,6 +261,7 @@ var _ = Describe("Test the generic configuration update processor and the concre
 		res.Spec.ExternalNodesCIDRList = &[]string{"1.1.1.1", "2.2.2.2"}
 		res.Spec.IptablesNATOutgoingInterfaceFilter = "cali-123"
 		res.Spec.RouteTableRanges = &apiv3.RouteTableRanges{{Min: 43, Max: 211}}
+		res.Spec.EgressProxyDestinationPorts = &[]numorstring.Port{numorstring.SinglePort(80), {MinPort: 8080, MaxPort: 8090}}
 		expected := map[string]interface{}{
 			"RouteRefreshInterval":               "12.345",
 			"IptablesLockProbeIntervalMillis":    "54.321",
@@ -232,6 +275,7 @@ var _ = Describe("Test the generic configuration update processor and the concre
 			"ExternalNodesCIDRList":              "1.1.1.1,2.2.2.2",
 			"IptablesNATOutgoingInterfaceFilter": "cali-123",
 			"RouteTableRanges":                   "43-211",
+			"EgressProxyDestinationPorts":        "80,8080:8090",
 		}
 		kvps, err := cc.Process(&model.KVPair{
 			Key:   perNodeFelixKey,
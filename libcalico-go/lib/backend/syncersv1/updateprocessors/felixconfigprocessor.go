@@ -22,6 +22,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/watchersyncer"
@@ -36,12 +37,17 @@ func NewFelixConfigUpdateProcessor() watchersyncer.SyncerUpdateProcessor {
 		AllowAnnotations,
 		func(node, name string) model.Key { return model.HostConfigKey{Hostname: node, Name: name} },
 		func(name string) model.Key { return model.GlobalConfigKey{Name: name} },
+		func(override, name string) model.Key {
+			return model.OverrideConfigKey{OverrideName: override, Name: name}
+		},
 		map[string]ConfigFieldValueToV1ModelValue{
-			"FailsafeInboundHostPorts":  protoPortSliceToString,
-			"FailsafeOutboundHostPorts": protoPortSliceToString,
-			"RouteTableRange":           routeTableRangeToString,
-			"RouteTableRanges":          routeTableRangeListToString,
-			"HealthTimeoutOverrides":    healthTimeoutOverridesToString,
+			"FailsafeInboundHostPorts":    protoPortSliceToString,
+			"FailsafeOutboundHostPorts":   protoPortSliceToString,
+			"RouteTableRange":             routeTableRangeToString,
+			"RouteTableRanges":            routeTableRangeListToString,
+			"HealthTimeoutOverrides":      healthTimeoutOverridesToString,
+			"EgressProxyDestinationPorts": portSliceToString,
+			"L7ProxyDestinationPorts":     portSliceToString,
 		},
 	)
 }
@@ -71,6 +77,17 @@ func protoPortSliceToString(value interface{}) interface{} {
 	return strings.Join(parts, ",")
 }
 
+// Converts a slice of ports or port ranges to a comma separated list.
+// e.g. []Port{80, 8080:8090} => "80,8080:8090"
+func portSliceToString(value interface{}) interface{} {
+	ports := value.([]numorstring.Port)
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, ",")
+}
+
 // Converts multiple route table ranges to its string config representation.
 // e.g. RouteTableRanges{{Min: 0, Max: 250}, {Min: 255, Max: 3000}} => "0-250,255-3000"
 func routeTableRangeListToString(value interface{}) interface{} {
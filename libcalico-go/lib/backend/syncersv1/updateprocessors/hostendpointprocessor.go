@@ -71,11 +71,22 @@ func convertHostEndpointV2ToV1(kvp *model.KVPair) (*model.KVPair, error) {
 		})
 	}
 
+	// Add the zone to the labels, so that policy can select the endpoints of a zone.  Copy the
+	// labels so as not to modify the resource.
+	labels := v3res.GetLabels()
+	if v3res.Spec.Zone != "" {
+		labels = make(map[string]string, len(v3res.GetLabels())+1)
+		for k, v := range v3res.GetLabels() {
+			labels[k] = v
+		}
+		labels[apiv3.LabelZone] = v3res.Spec.Zone
+	}
+
 	v1value := &model.HostEndpoint{
 		Name:              v3res.Spec.InterfaceName,
 		ExpectedIPv4Addrs: ipv4Addrs,
 		ExpectedIPv6Addrs: ipv6Addrs,
-		Labels:            v3res.GetLabels(),
+		Labels:            labels,
 		ProfileIDs:        v3res.Spec.Profiles,
 		Ports:             ports,
 	}
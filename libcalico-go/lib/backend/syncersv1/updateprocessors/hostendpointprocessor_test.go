@@ -116,6 +116,23 @@ var _ = Describe("Test the HostEndpoint update processor", func() {
 			},
 		}))
 
+		By("adding the zone of a HostEndpoint to its labels")
+		res = res.DeepCopy()
+		res.Spec.Zone = "dmz"
+
+		kvps, err = up.Process(&model.KVPair{
+			Key:      v3HostEndpointKey2,
+			Value:    res,
+			Revision: "1235",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(HaveLen(1))
+		Expect(kvps[0].Value.(*model.HostEndpoint).Labels).To(Equal(map[string]string{
+			"testLabel":              "label",
+			"projectcalico.org/zone": "dmz",
+		}))
+		Expect(res.Labels).To(Equal(map[string]string{"testLabel": "label"}))
+
 		By("deleting the first host endpoint")
 		kvps, err = up.Process(&model.KVPair{
 			Key: v3HostEndpointKey1,
@@ -212,6 +212,34 @@ var _ = Describe("Test the IPPool update processor", func() {
 		}))
 	})
 
+	It("should convert the egress SNAT selector", func() {
+		up := updateprocessors.NewIPPoolUpdateProcessor()
+
+		res := apiv3.NewIPPool()
+		res.Name = v3PoolKey1.Name
+		res.Spec.CIDR = cidr1str
+		res.Spec.AllowedUses = []apiv3.IPPoolAllowedUse{apiv3.IPPoolAllowedUseEgressSNAT}
+		res.Spec.EgressSNATSelector = "projectcalico.org/namespace == 'tenant-a'"
+
+		kvps, err := up.Process(&model.KVPair{
+			Key:      v3PoolKey1,
+			Value:    res,
+			Revision: "abcde",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(HaveLen(1))
+		Expect(kvps[0]).To(Equal(&model.KVPair{
+			Key: v1PoolKeyCidr1,
+			Value: &model.IPPool{
+				CIDR:               v1PoolKeyCidr1.CIDR,
+				IPIPMode:           encap.Undefined,
+				IPAM:               true,
+				EgressSNATSelector: "projectcalico.org/namespace == 'tenant-a'",
+			},
+			Revision: "abcde",
+		}))
+	})
+
 	It("should fail to convert an invalid resource", func() {
 		up := updateprocessors.NewIPPoolUpdateProcessor()
 
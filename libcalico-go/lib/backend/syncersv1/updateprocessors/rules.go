@@ -139,6 +139,15 @@ func RuleAPIV2ToBackend(ar apiv3.Rule, ns string) model.Rule {
 		notICMPType = ar.NotICMP.Type
 	}
 
+	var icmpTypeCodes []model.ICMPTypeCode
+	for _, icmp := range ar.ICMPs {
+		if icmp.Type == nil {
+			// Not allowed by validation.
+			continue
+		}
+		icmpTypeCodes = append(icmpTypeCodes, model.ICMPTypeCode{Type: *icmp.Type, Code: icmp.Code})
+	}
+
 	sourceSelector := GetEntityRuleSelector(&ar.Source, ns, "source")
 	destSelector := GetEntityRuleSelector(&ar.Destination, ns, "destination")
 
@@ -163,14 +172,17 @@ func RuleAPIV2ToBackend(ar apiv3.Rule, ns string) model.Rule {
 	}
 
 	r := model.Rule{
-		Action:      ruleActionAPIV2ToBackend(ar.Action),
-		IPVersion:   ar.IPVersion,
-		Protocol:    convertV3ProtocolToV1(ar.Protocol),
-		ICMPCode:    icmpCode,
-		ICMPType:    icmpType,
-		NotProtocol: convertV3ProtocolToV1(ar.NotProtocol),
-		NotICMPCode: notICMPCode,
-		NotICMPType: notICMPType,
+		Action:        ruleActionAPIV2ToBackend(ar.Action),
+		IPVersion:     ar.IPVersion,
+		Protocol:      convertV3ProtocolToV1(ar.Protocol),
+		ICMPCode:      icmpCode,
+		ICMPType:      icmpType,
+		ICMPTypeCodes: icmpTypeCodes,
+		NotProtocol:   convertV3ProtocolToV1(ar.NotProtocol),
+		NotICMPCode:   notICMPCode,
+		NotICMPType:   notICMPType,
+		DSCP:          ar.DSCP,
+		NotDSCP:       ar.NotDSCP,
 
 		SrcNets:             ConvertStringsToNets(ar.Source.Nets),
 		SrcSelector:         sourceSelector,
@@ -182,6 +194,7 @@ func RuleAPIV2ToBackend(ar apiv3.Rule, ns string) model.Rule {
 		DstPorts:            ar.Destination.Ports,
 		DstService:          dstService,
 		DstServiceNamespace: dstServiceNS,
+		DstDomains:          ar.Destination.Domains,
 
 		NotSrcNets:     ConvertStringsToNets(ar.Source.NotNets),
 		NotSrcSelector: ar.Source.NotSelector,
@@ -205,6 +218,16 @@ func RuleAPIV2ToBackend(ar apiv3.Rule, ns string) model.Rule {
 	if ar.HTTP != nil {
 		r.HTTPMatch = &model.HTTPMatch{Methods: ar.HTTP.Methods, Paths: ar.HTTP.Paths}
 	}
+	if ar.Log != nil {
+		r.Log = &model.RuleLog{
+			Target:         string(ar.Log.Target),
+			SyslogFacility: ar.Log.SyslogFacility,
+			RateLimit:      ar.Log.RateLimit,
+		}
+		if ar.Log.RateLimitBurst != nil {
+			r.Log.RateLimitBurst = *ar.Log.RateLimitBurst
+		}
+	}
 	if ar.Metadata != nil {
 		if ar.Metadata.Annotations != nil {
 			r.Metadata = &model.RuleMetadata{Annotations: make(map[string]string)}
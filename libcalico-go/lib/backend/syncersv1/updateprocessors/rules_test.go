@@ -24,6 +24,7 @@ import (
 	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/updateprocessors"
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
 )
@@ -40,6 +41,8 @@ var _ = Describe("Test the Rules Conversion Functions", func() {
 		inproto := numorstring.ProtocolFromString("UDP")
 		port80 := numorstring.SinglePort(uint16(80))
 		port443 := numorstring.SinglePort(uint16(443))
+		idscp := numorstring.DSCPFromString("AF11")
+		indscp := numorstring.DSCPFromInt(46)
 		irule := apiv3.Rule{
 			Action:    apiv3.Allow,
 			IPVersion: &v4,
@@ -53,6 +56,8 @@ var _ = Describe("Test the Rules Conversion Functions", func() {
 				Type: &intype,
 				Code: &incode,
 			},
+			DSCP:    &idscp,
+			NotDSCP: &indscp,
 			Source: apiv3.EntityRule{
 				Nets:        []string{"10.100.10.1/32"},
 				Selector:    "mylabel = value1",
@@ -92,6 +97,8 @@ var _ = Describe("Test the Rules Conversion Functions", func() {
 		Expect(rulev1.NotProtocol.StrVal).To(Equal("udp"))
 		Expect(rulev1.NotICMPCode).To(Equal(&incode))
 		Expect(rulev1.NotICMPType).To(Equal(&intype))
+		Expect(rulev1.DSCP).To(Equal(&idscp))
+		Expect(rulev1.NotDSCP).To(Equal(&indscp))
 
 		Expect(rulev1.SrcNets).To(Equal([]*cnet.IPNet{mustParseCIDR("10.100.10.1/32")}))
 		Expect(rulev1.SrcSelector).To(Equal("(projectcalico.org/namespace == 'namespace2') && (mylabel = value1)"))
@@ -290,6 +297,28 @@ var _ = Describe("Test the Rules Conversion Functions", func() {
 		})
 	})
 
+	It("should convert multiple ICMP types and codes", func() {
+		icmpv6 := numorstring.ProtocolFromString("ICMPv6")
+		ns, na, du, noRoute := 135, 136, 1, 0
+		r := apiv3.Rule{
+			Action:   apiv3.Allow,
+			Protocol: &icmpv6,
+			ICMPs: []apiv3.ICMPFields{
+				{Type: &ns},
+				{Type: &na},
+				{Type: &du, Code: &noRoute},
+			},
+		}
+
+		rulev1 := updateprocessors.RuleAPIV2ToBackend(r, "")
+		Expect(rulev1.ICMPType).To(BeNil())
+		Expect(rulev1.ICMPTypeCodes).To(Equal([]model.ICMPTypeCode{
+			{Type: 135},
+			{Type: 136},
+			{Type: 1, Code: &noRoute},
+		}))
+	})
+
 	It("should parse a rule with ports but no selectors", func() {
 		tcp := numorstring.ProtocolFromString("TCP")
 		port80 := numorstring.SinglePort(uint16(80))
@@ -475,6 +504,40 @@ var _ = Describe("Test the Rules Conversion Functions", func() {
 		})
 	})
 
+	It("should copy the domains of a rule", func() {
+		r := apiv3.Rule{
+			Action: apiv3.Allow,
+			Destination: apiv3.EntityRule{
+				Domains: []string{"api.github.com", "*.github.com"},
+			},
+		}
+
+		rulev1 := updateprocessors.RuleAPIV2ToBackend(r, "")
+		Expect(rulev1.DstDomains).To(Equal([]string{"api.github.com", "*.github.com"}))
+		Expect(rulev1.DstSelector).To(Equal(""))
+	})
+
+	It("should copy the log options of a log rule", func() {
+		burst := 10
+		r := apiv3.Rule{
+			Action: apiv3.Log,
+			Log: &apiv3.RuleLog{
+				Target:         apiv3.LogTargetSyslog,
+				SyslogFacility: "local2",
+				RateLimit:      "5/second",
+				RateLimitBurst: &burst,
+			},
+		}
+
+		rulev1 := updateprocessors.RuleAPIV2ToBackend(r, "")
+		Expect(rulev1.Log).To(Equal(&model.RuleLog{
+			Target:         "Syslog",
+			SyslogFacility: "local2",
+			RateLimit:      "5/second",
+			RateLimitBurst: 10,
+		}))
+	})
+
 	It("should parse a source rule services match", func() {
 		r := apiv3.Rule{
 			Action: apiv3.Allow,
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updateprocessors
+
+import (
+	"errors"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/watchersyncer"
+)
+
+// Create a new SyncerUpdateProcessor to sync StagedGlobalNetworkPolicy data in v1 format for
+// consumption by Felix.  Staged policies are converted in the same way as the enforced
+// policies, with model.PolicyNamePrefixStaged prepended to their names.
+func NewStagedGlobalNetworkPolicyUpdateProcessor() watchersyncer.SyncerUpdateProcessor {
+	return NewSimpleUpdateProcessor(apiv3.KindStagedGlobalNetworkPolicy, convertStagedGlobalNetworkPolicyV2ToV1Key, convertStagedGlobalNetworkPolicyV2ToV1Value)
+}
+
+func convertStagedGlobalNetworkPolicyV2ToV1Key(v3key model.ResourceKey) (model.Key, error) {
+	if v3key.Name == "" {
+		return model.PolicyKey{}, errors.New("Missing Name field to create a v1 StagedGlobalNetworkPolicy Key")
+	}
+	return model.PolicyKey{
+		Name: model.PolicyNamePrefixStaged + v3key.Name,
+	}, nil
+}
+
+func convertStagedGlobalNetworkPolicyV2ToV1Value(val interface{}) (interface{}, error) {
+	v3res, ok := val.(*apiv3.StagedGlobalNetworkPolicy)
+	if !ok {
+		return nil, errors.New("Value is not a valid StagedGlobalNetworkPolicy resource value")
+	}
+	return convertGlobalNetworkPolicyV2ToV1Value(&apiv3.GlobalNetworkPolicy{
+		ObjectMeta: v3res.ObjectMeta,
+		Spec:       v3res.Spec,
+	})
+}
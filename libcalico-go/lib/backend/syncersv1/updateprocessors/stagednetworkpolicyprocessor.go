@@ -0,0 +1,51 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updateprocessors
+
+import (
+	"errors"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/watchersyncer"
+)
+
+// Create a new SyncerUpdateProcessor to sync StagedNetworkPolicy data in v1 format for
+// consumption by Felix.  Staged policies are converted in the same way as the enforced
+// policies, with model.PolicyNamePrefixStaged prepended to their names.
+func NewStagedNetworkPolicyUpdateProcessor() watchersyncer.SyncerUpdateProcessor {
+	return NewSimpleUpdateProcessor(apiv3.KindStagedNetworkPolicy, convertStagedNetworkPolicyV2ToV1Key, convertStagedNetworkPolicyV2ToV1Value)
+}
+
+func convertStagedNetworkPolicyV2ToV1Key(v3key model.ResourceKey) (model.Key, error) {
+	if v3key.Name == "" || v3key.Namespace == "" {
+		return model.PolicyKey{}, errors.New("Missing Name or Namespace field to create a v1 StagedNetworkPolicy Key")
+	}
+	return model.PolicyKey{
+		Name: v3key.Namespace + "/" + model.PolicyNamePrefixStaged + v3key.Name,
+	}, nil
+}
+
+func convertStagedNetworkPolicyV2ToV1Value(val interface{}) (interface{}, error) {
+	v3res, ok := val.(*apiv3.StagedNetworkPolicy)
+	if !ok {
+		return nil, errors.New("Value is not a valid StagedNetworkPolicy resource value")
+	}
+	return convertNetworkPolicyV2ToV1Value(&apiv3.NetworkPolicy{
+		ObjectMeta: v3res.ObjectMeta,
+		Spec:       v3res.Spec,
+	})
+}
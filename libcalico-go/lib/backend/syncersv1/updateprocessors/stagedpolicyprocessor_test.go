@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updateprocessors_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/updateprocessors"
+)
+
+var _ = Describe("Test the staged policy update processors", func() {
+	It("should convert a StagedGlobalNetworkPolicy to a staged v1 policy", func() {
+		up := updateprocessors.NewStagedGlobalNetworkPolicyUpdateProcessor()
+		key := model.ResourceKey{Kind: apiv3.KindStagedGlobalNetworkPolicy, Name: "deny-all"}
+		sgnp := apiv3.NewStagedGlobalNetworkPolicy()
+		sgnp.Name = "deny-all"
+		sgnp.Spec.Selector = "all()"
+		sgnp.Spec.Types = []apiv3.PolicyType{apiv3.PolicyTypeIngress}
+
+		kvps, err := up.Process(&model.KVPair{Key: key, Value: sgnp, Revision: testRev})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(Equal([]*model.KVPair{{
+			Key: model.PolicyKey{Name: "staged:deny-all"},
+			Value: &model.Policy{
+				Selector: "all()",
+				Types:    []string{"ingress"},
+			},
+			Revision: testRev,
+		}}))
+		Expect(model.PolicyIsStaged(kvps[0].Key.(model.PolicyKey).Name)).To(BeTrue())
+
+		By("deleting the policy")
+		kvps, err = up.Process(&model.KVPair{Key: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(Equal([]*model.KVPair{{Key: model.PolicyKey{Name: "staged:deny-all"}}}))
+	})
+
+	It("should convert a StagedNetworkPolicy to a staged v1 policy", func() {
+		up := updateprocessors.NewStagedNetworkPolicyUpdateProcessor()
+		key := model.ResourceKey{Kind: apiv3.KindStagedNetworkPolicy, Name: "deny-all", Namespace: "ns1"}
+		snp := apiv3.NewStagedNetworkPolicy()
+		snp.Name = "deny-all"
+		snp.Namespace = "ns1"
+
+		kvps, err := up.Process(&model.KVPair{Key: key, Value: snp, Revision: testRev})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(Equal([]*model.KVPair{{
+			Key: model.PolicyKey{Name: "ns1/staged:deny-all"},
+			Value: &model.Policy{
+				Namespace:      "ns1",
+				Selector:       "projectcalico.org/namespace == 'ns1'",
+				ApplyOnForward: true,
+			},
+			Revision: testRev,
+		}}))
+		Expect(model.PolicyIsStaged(kvps[0].Key.(model.PolicyKey).Name)).To(BeTrue())
+	})
+
+	It("should not accept an enforced policy as a staged policy", func() {
+		up := updateprocessors.NewStagedGlobalNetworkPolicyUpdateProcessor()
+		key := model.ResourceKey{Kind: apiv3.KindStagedGlobalNetworkPolicy, Name: "gnp"}
+		kvps, err := up.Process(&model.KVPair{Key: key, Value: apiv3.NewGlobalNetworkPolicy(), Revision: testRev})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(Equal([]*model.KVPair{{Key: model.PolicyKey{Name: "staged:gnp"}}}))
+	})
+
+	It("should not treat enforced policies as staged", func() {
+		Expect(model.PolicyIsStaged("deny-all")).To(BeFalse())
+		Expect(model.PolicyIsStaged("ns1/deny-all")).To(BeFalse())
+		Expect(model.PolicyIsStaged("ns1/knp.default.staged:foo")).To(BeFalse())
+	})
+})
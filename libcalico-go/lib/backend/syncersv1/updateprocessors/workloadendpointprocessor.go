@@ -113,7 +113,16 @@ func convertWorkloadEndpointV2ToV1Value(val interface{}) (interface{}, error) {
 
 	// Convert the EndpointPort type from the API pkg to the v1 model equivalent type
 	ports := []model.EndpointPort{}
+	var portMappings []model.PortMapping
 	for _, port := range v3res.Spec.Ports {
+		if port.HostPort != 0 {
+			portMappings = append(portMappings, model.PortMapping{
+				Protocol:      port.Protocol.ToV1(),
+				HostPort:      port.HostPort,
+				ContainerPort: port.Port,
+				HostIP:        port.HostIP,
+			})
+		}
 		// The v1 API doesn't yet support ports which have no name. However, this is allowed on the
 		// v3 API and used by the CNI plugin only. Filter these out since Felix doesn't use them anyway.
 		if port.Name != "" {
@@ -180,6 +189,7 @@ func convertWorkloadEndpointV2ToV1Value(val interface{}) (interface{}, error) {
 		GenerateName:               v3res.GenerateName,
 		AllowSpoofedSourcePrefixes: allowedSources,
 		Annotations:                v3res.GetObjectMeta().GetAnnotations(),
+		PortMappings:               portMappings,
 	}
 
 	return v1value, nil
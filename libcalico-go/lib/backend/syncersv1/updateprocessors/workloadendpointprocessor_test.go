@@ -152,6 +152,12 @@ var _ = Describe("Test the WorkloadEndpoint update processor", func() {
 				Protocol: numorstring.ProtocolFromInt(uint8(30)),
 				Port:     uint16(8080),
 			},
+			{
+				Protocol: numorstring.ProtocolFromString("TCP"),
+				Port:     uint16(80),
+				HostPort: uint16(8081),
+				HostIP:   "192.168.0.1",
+			},
 		}
 		res.Spec.AllowSpoofedSourcePrefixes = []string{"8.8.8.8/32"}
 
@@ -185,6 +191,14 @@ var _ = Describe("Test the WorkloadEndpoint update processor", func() {
 							Port:     uint16(8080),
 						},
 					},
+					PortMappings: []model.PortMapping{
+						{
+							Protocol:      numorstring.ProtocolFromStringV1("tcp"),
+							HostPort:      uint16(8081),
+							ContainerPort: uint16(80),
+							HostIP:        "192.168.0.1",
+						},
+					},
 					AllowSpoofedSourcePrefixes: []cnet.IPNet{cnet.MustParseCIDR("8.8.8.8/32")},
 				},
 				Revision: "1234",
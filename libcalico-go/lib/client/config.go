@@ -22,8 +22,10 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
 
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
@@ -51,40 +53,199 @@ const (
 	GlobalDefaultLogLevel       = "info"
 	GlobalDefaultIPIP           = false
 	GlobalDefaultNodeToNodeMesh = true
+	GlobalDefaultVXLAN          = false
+	GlobalDefaultVXLANVNI       = 4096
+	GlobalDefaultVXLANPort      = 4789
+	GlobalDefaultWireguard      = false
+	GlobalDefaultWireguardPort  = 51820
+)
+
+const (
+	maxVXLANVNI = 1<<24 - 1
+	maxPort     = 65535
 )
 
 // ConfigInterface provides methods for setting, unsetting and retrieving low
-// level config options.
+// level config options.  Each method has a variant with a Context suffix that
+// takes a context, which bounds the datastore calls of the method.
+//
+// The setters validate their values before writing anything, and return an
+// errors.ErrorValidation for an invalid value.  DryRun returns a
+// ConfigInterface whose setters only validate and record their changes.
+//
+// Each change is appended to the config audit log in the datastore, with the
+// old and new values and the user set by AsUser; GetFelixConfigHistory and
+// GetBGPConfigHistory return the log of a key.
 type ConfigInterface interface {
+	DryRun() DryRunConfigInterface
+	AsUser(string) ConfigInterface
 	SetNodeToNodeMesh(bool) error
+	SetNodeToNodeMeshContext(context.Context, bool) error
 	GetNodeToNodeMesh() (bool, error)
+	GetNodeToNodeMeshContext(context.Context) (bool, error)
 	SetGlobalASNumber(numorstring.ASNumber) error
+	SetGlobalASNumberContext(context.Context, numorstring.ASNumber) error
 	GetGlobalASNumber() (numorstring.ASNumber, error)
+	GetGlobalASNumberContext(context.Context) (numorstring.ASNumber, error)
 	SetGlobalIPIP(bool) error
+	SetGlobalIPIPContext(context.Context, bool) error
 	GetGlobalIPIP() (bool, error)
+	GetGlobalIPIPContext(context.Context) (bool, error)
+	SetGlobalVXLAN(bool) error
+	SetGlobalVXLANContext(context.Context, bool) error
+	GetGlobalVXLAN() (bool, error)
+	GetGlobalVXLANContext(context.Context) (bool, error)
+	SetGlobalVXLANVNI(int) error
+	SetGlobalVXLANVNIContext(context.Context, int) error
+	GetGlobalVXLANVNI() (int, error)
+	GetGlobalVXLANVNIContext(context.Context) (int, error)
+	SetGlobalVXLANPort(int) error
+	SetGlobalVXLANPortContext(context.Context, int) error
+	GetGlobalVXLANPort() (int, error)
+	GetGlobalVXLANPortContext(context.Context) (int, error)
 	SetNodeIPIPTunnelAddress(string, *net.IP) error
+	SetNodeIPIPTunnelAddressContext(context.Context, string, *net.IP) error
 	GetNodeIPIPTunnelAddress(string) (*net.IP, error)
+	GetNodeIPIPTunnelAddressContext(context.Context, string) (*net.IP, error)
 	SetGlobalLogLevel(string) error
+	SetGlobalLogLevelContext(context.Context, string) error
 	GetGlobalLogLevel() (string, error)
+	GetGlobalLogLevelContext(context.Context) (string, error)
 	SetNodeLogLevel(string, string) error
+	SetNodeLogLevelContext(context.Context, string, string) error
 	SetNodeLogLevelUseGlobal(string) error
+	SetNodeLogLevelUseGlobalContext(context.Context, string) error
 	GetNodeLogLevel(string) (string, ConfigLocation, error)
+	GetNodeLogLevelContext(context.Context, string) (string, ConfigLocation, error)
+	SetGlobalWireguard(bool) error
+	SetGlobalWireguardContext(context.Context, bool) error
+	GetGlobalWireguard() (bool, error)
+	GetGlobalWireguardContext(context.Context) (bool, error)
+	SetNodeWireguard(string, bool) error
+	SetNodeWireguardContext(context.Context, string, bool) error
+	SetNodeWireguardUseGlobal(string) error
+	SetNodeWireguardUseGlobalContext(context.Context, string) error
+	GetNodeWireguard(string) (bool, ConfigLocation, error)
+	GetNodeWireguardContext(context.Context, string) (bool, ConfigLocation, error)
+	SetGlobalWireguardPort(int) error
+	SetGlobalWireguardPortContext(context.Context, int) error
+	GetGlobalWireguardPort() (int, error)
+	GetGlobalWireguardPortContext(context.Context) (int, error)
+	SetNodeWireguardPort(string, int) error
+	SetNodeWireguardPortContext(context.Context, string, int) error
+	SetNodeWireguardPortUseGlobal(string) error
+	SetNodeWireguardPortUseGlobalContext(context.Context, string) error
+	GetNodeWireguardPort(string) (int, ConfigLocation, error)
+	GetNodeWireguardPortContext(context.Context, string) (int, ConfigLocation, error)
+	SetNodeWireguardPublicKey(string, string) error
+	SetNodeWireguardPublicKeyContext(context.Context, string, string) error
+	GetNodeWireguardPublicKey(string) (string, error)
+	GetNodeWireguardPublicKeyContext(context.Context, string) (string, error)
+	SetNodeWireguardTunnelAddress(string, *net.IP) error
+	SetNodeWireguardTunnelAddressContext(context.Context, string, *net.IP) error
+	GetNodeWireguardTunnelAddress(string) (*net.IP, error)
+	GetNodeWireguardTunnelAddressContext(context.Context, string) (*net.IP, error)
 	GetFelixConfig(string, string) (string, bool, error)
+	GetFelixConfigContext(context.Context, string, string) (string, bool, error)
 	SetFelixConfig(string, string, string) error
+	SetFelixConfigContext(context.Context, string, string, string) error
 	UnsetFelixConfig(string, string) error
+	UnsetFelixConfigContext(context.Context, string, string) error
 	GetBGPConfig(string, string) (string, bool, error)
+	GetBGPConfigContext(context.Context, string, string) (string, bool, error)
 	SetBGPConfig(string, string, string) error
+	SetBGPConfigContext(context.Context, string, string, string) error
 	UnsetBGPConfig(string, string) error
+	UnsetBGPConfigContext(context.Context, string, string) error
+	WatchGlobalConfig(string) (bapi.WatchInterface, error)
+	WatchGlobalConfigContext(context.Context, string) (bapi.WatchInterface, error)
+	WatchNodeConfig(string, string) (bapi.WatchInterface, error)
+	WatchNodeConfigContext(context.Context, string, string) (bapi.WatchInterface, error)
+	GetFailsafeInboundPorts() ([]apiv3.ProtoPort, error)
+	GetFailsafeInboundPortsContext(context.Context) ([]apiv3.ProtoPort, error)
+	AddFailsafeInboundPort(apiv3.ProtoPort) error
+	AddFailsafeInboundPortContext(context.Context, apiv3.ProtoPort) error
+	RemoveFailsafeInboundPort(apiv3.ProtoPort) error
+	RemoveFailsafeInboundPortContext(context.Context, apiv3.ProtoPort) error
+	GetFailsafeOutboundPorts() ([]apiv3.ProtoPort, error)
+	GetFailsafeOutboundPortsContext(context.Context) ([]apiv3.ProtoPort, error)
+	AddFailsafeOutboundPort(apiv3.ProtoPort) error
+	AddFailsafeOutboundPortContext(context.Context, apiv3.ProtoPort) error
+	RemoveFailsafeOutboundPort(apiv3.ProtoPort) error
+	RemoveFailsafeOutboundPortContext(context.Context, apiv3.ProtoPort) error
+	SetGlobalBGPPeer(net.IP, numorstring.ASNumber) error
+	SetGlobalBGPPeerContext(context.Context, net.IP, numorstring.ASNumber) error
+	UnsetGlobalBGPPeer(net.IP) error
+	UnsetGlobalBGPPeerContext(context.Context, net.IP) error
+	SetGlobalBGPPeerPassword(net.IP, *apiv3.BGPPassword) error
+	SetGlobalBGPPeerPasswordContext(context.Context, net.IP, *apiv3.BGPPassword) error
+	GetGlobalBGPPeers() ([]BGPPeerConfig, error)
+	GetGlobalBGPPeersContext(context.Context) ([]BGPPeerConfig, error)
+	SetNodeBGPPeer(string, net.IP, numorstring.ASNumber) error
+	SetNodeBGPPeerContext(context.Context, string, net.IP, numorstring.ASNumber) error
+	SetNodeBGPPeerUseGlobal(string, net.IP) error
+	SetNodeBGPPeerUseGlobalContext(context.Context, string, net.IP) error
+	SetNodeBGPPeerPassword(string, net.IP, *apiv3.BGPPassword) error
+	SetNodeBGPPeerPasswordContext(context.Context, string, net.IP, *apiv3.BGPPassword) error
+	GetNodeBGPPeer(string, net.IP) (*BGPPeerConfig, error)
+	GetNodeBGPPeerContext(context.Context, string, net.IP) (*BGPPeerConfig, error)
+	GetNodeBGPPeers(string) ([]BGPPeerConfig, error)
+	GetNodeBGPPeersContext(context.Context, string) ([]BGPPeerConfig, error)
+	GetFelixConfigHistory(string, string) ([]model.ConfigAuditEntry, error)
+	GetFelixConfigHistoryContext(context.Context, string, string) ([]model.ConfigAuditEntry, error)
+	GetBGPConfigHistory(string, string) ([]model.ConfigAuditEntry, error)
+	GetBGPConfigHistoryContext(context.Context, string, string) ([]model.ConfigAuditEntry, error)
+	ExportConfig() (*ConfigSnapshot, error)
+	ExportConfigContext(context.Context) (*ConfigSnapshot, error)
+	ImportConfig(*ConfigSnapshot, bool) ([]ConfigConflict, error)
+	ImportConfigContext(context.Context, *ConfigSnapshot, bool) ([]ConfigConflict, error)
+}
+
+// DryRunConfigInterface is a ConfigInterface whose setters validate their
+// values and record the changes that they would make instead of writing them
+// to the datastore.  Its getters return the values that would result from the
+// recorded changes, so that the caller can check the outcome of a sequence of
+// setters before running it for real.
+type DryRunConfigInterface interface {
+	ConfigInterface
+	// Changes returns the changes recorded by the setters, in order.
+	Changes() []ConfigChange
+}
+
+// ConfigChange is a change that a setter of a DryRunConfigInterface would have
+// made to the datastore.  The value is nil if the key would be deleted.
+type ConfigChange struct {
+	Key   model.Key
+	Value interface{}
 }
 
 // config implements ConfigInterface
 type config struct {
 	c *Client
+	// changes, if not nil, records the changes of a dry run instead of writing
+	// them to the datastore.
+	changes *[]ConfigChange
+	// user is recorded in the audit log as the author of the changes.
+	user string
 }
 
 // newConfig returns a new ConfigInterface bound to the supplied client.
 func newConfigs(c *Client) ConfigInterface {
-	return &config{c}
+	return &config{c: c}
+}
+
+// DryRun returns a config whose setters record their changes instead of
+// writing them to the datastore.  See DryRunConfigInterface for details.
+func (c *config) DryRun() DryRunConfigInterface {
+	return &config{c: c.c, changes: &[]ConfigChange{}, user: c.user}
+}
+
+// Changes returns the changes recorded by the setters of a dry run.
+func (c *config) Changes() []ConfigChange {
+	if c.changes == nil {
+		return nil
+	}
+	return append([]ConfigChange(nil), *c.changes...)
 }
 
 // The configuration interface provides the ability to set and get low-level,
@@ -94,8 +255,13 @@ func newConfigs(c *Client) ConfigInterface {
 // When this is enabled, each calico/node instance automatically establishes a
 // full BGP peering mesh between all nodes that support BGP.
 func (c *config) SetNodeToNodeMesh(enabled bool) error {
+	return c.SetNodeToNodeMeshContext(context.Background(), enabled)
+}
+
+// SetNodeToNodeMeshContext is SetNodeToNodeMesh with a context for the datastore calls.
+func (c *config) SetNodeToNodeMeshContext(ctx context.Context, enabled bool) error {
 	b, _ := json.Marshal(enabled)
-	_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	_, err := c.apply(ctx, &model.KVPair{
 		Key:   model.GlobalBGPConfigKey{Name: "NodeMeshEnabled"},
 		Value: string(b),
 	})
@@ -105,8 +271,13 @@ func (c *config) SetNodeToNodeMesh(enabled bool) error {
 // GetNodeToNodeMesh returns the current enabled state of the system-wide
 // node-to-node mesh option.  See SetNodeToNodeMesh for details.
 func (c *config) GetNodeToNodeMesh() (bool, error) {
+	return c.GetNodeToNodeMeshContext(context.Background())
+}
+
+// GetNodeToNodeMeshContext is GetNodeToNodeMesh with a context for the datastore calls.
+func (c *config) GetNodeToNodeMeshContext(ctx context.Context) (bool, error) {
 	var n bool
-	if s, err := c.getValue(model.GlobalBGPConfigKey{Name: "NodeMeshEnabled"}); err != nil {
+	if s, err := c.getValue(ctx, model.GlobalBGPConfigKey{Name: "NodeMeshEnabled"}); err != nil {
 		log.Info("Error getting node mesh")
 		return false, err
 	} else if s == nil {
@@ -125,7 +296,16 @@ func (c *config) GetNodeToNodeMesh() (bool, error) {
 // on each node.  This may be overridden by an explicitly configured value in
 // the node resource.
 func (c *config) SetGlobalASNumber(asNumber numorstring.ASNumber) error {
-	_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	return c.SetGlobalASNumberContext(context.Background(), asNumber)
+}
+
+// SetGlobalASNumberContext is SetGlobalASNumber with a context for the datastore calls.
+func (c *config) SetGlobalASNumberContext(ctx context.Context, asNumber numorstring.ASNumber) error {
+	// AS number 0 is reserved, see RFC 7607.
+	if asNumber == 0 {
+		return erroredField("asNumber", asNumber)
+	}
+	_, err := c.apply(ctx, &model.KVPair{
 		Key:   model.GlobalBGPConfigKey{Name: "AsNumber"},
 		Value: asNumber.String(),
 	})
@@ -135,7 +315,12 @@ func (c *config) SetGlobalASNumber(asNumber numorstring.ASNumber) error {
 // SetGlobalASNumber gets the global AS Number used by the BGP agent running
 // on each node.  See SetGlobalASNumber for more details.
 func (c *config) GetGlobalASNumber() (numorstring.ASNumber, error) {
-	if s, err := c.getValue(model.GlobalBGPConfigKey{Name: "AsNumber"}); err != nil {
+	return c.GetGlobalASNumberContext(context.Background())
+}
+
+// GetGlobalASNumberContext is GetGlobalASNumber with a context for the datastore calls.
+func (c *config) GetGlobalASNumberContext(ctx context.Context) (numorstring.ASNumber, error) {
+	if s, err := c.getValue(ctx, model.GlobalBGPConfigKey{Name: "AsNumber"}); err != nil {
 		return 0, err
 	} else if s == nil {
 		return GlobalDefaultASNumber, nil
@@ -151,7 +336,12 @@ func (c *config) GetGlobalASNumber() (numorstring.ASNumber, error) {
 // that fall within an IP in IP enabled Calico IP Pool, will be routed over an
 // IP in IP tunnel.
 func (c *config) SetGlobalIPIP(enabled bool) error {
-	_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	return c.SetGlobalIPIPContext(context.Background(), enabled)
+}
+
+// SetGlobalIPIPContext is SetGlobalIPIP with a context for the datastore calls.
+func (c *config) SetGlobalIPIPContext(ctx context.Context, enabled bool) error {
+	_, err := c.apply(ctx, &model.KVPair{
 		Key:   model.GlobalConfigKey{Name: "IpInIpEnabled"},
 		Value: strconv.FormatBool(enabled),
 	})
@@ -160,7 +350,12 @@ func (c *config) SetGlobalIPIP(enabled bool) error {
 
 // GetGlobalIPIP gets the global IPIP enabled setting.  See SetGlobalIPIP for details.
 func (c *config) GetGlobalIPIP() (bool, error) {
-	if s, err := c.getValue(model.GlobalConfigKey{Name: "IpInIpEnabled"}); err != nil {
+	return c.GetGlobalIPIPContext(context.Background())
+}
+
+// GetGlobalIPIPContext is GetGlobalIPIP with a context for the datastore calls.
+func (c *config) GetGlobalIPIPContext(ctx context.Context) (bool, error) {
+	if s, err := c.getValue(ctx, model.GlobalConfigKey{Name: "IpInIpEnabled"}); err != nil {
 		return false, err
 	} else if s == nil {
 		return GlobalDefaultIPIP, nil
@@ -171,15 +366,113 @@ func (c *config) GetGlobalIPIP() (bool, error) {
 	}
 }
 
+// SetGlobalVXLAN sets the global VXLAN enabled setting inherited by all nodes
+// in the Calico cluster.  When VXLAN is enabled, Felix creates the VXLAN tunnel
+// device even if no Calico IP Pool uses VXLAN.
+func (c *config) SetGlobalVXLAN(enabled bool) error {
+	return c.SetGlobalVXLANContext(context.Background(), enabled)
+}
+
+// SetGlobalVXLANContext is SetGlobalVXLAN with a context for the datastore calls.
+func (c *config) SetGlobalVXLANContext(ctx context.Context, enabled bool) error {
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   model.GlobalConfigKey{Name: "VXLANEnabled"},
+		Value: strconv.FormatBool(enabled),
+	})
+	return err
+}
+
+// GetGlobalVXLAN gets the global VXLAN enabled setting.  See SetGlobalVXLAN for details.
+func (c *config) GetGlobalVXLAN() (bool, error) {
+	return c.GetGlobalVXLANContext(context.Background())
+}
+
+// GetGlobalVXLANContext is GetGlobalVXLAN with a context for the datastore calls.
+func (c *config) GetGlobalVXLANContext(ctx context.Context) (bool, error) {
+	if s, err := c.getValue(ctx, model.GlobalConfigKey{Name: "VXLANEnabled"}); err != nil {
+		return false, err
+	} else if s == nil {
+		return GlobalDefaultVXLAN, nil
+	} else if enabled, err := strconv.ParseBool(*s); err != nil {
+		return false, err
+	} else {
+		return enabled, nil
+	}
+}
+
+// SetGlobalVXLANVNI sets the global VXLAN Network Identifier used by the VXLAN
+// tunnels of all nodes.  The VNI must be between 1 and 16777215.
+func (c *config) SetGlobalVXLANVNI(vni int) error {
+	return c.SetGlobalVXLANVNIContext(context.Background(), vni)
+}
+
+// SetGlobalVXLANVNIContext is SetGlobalVXLANVNI with a context for the datastore calls.
+func (c *config) SetGlobalVXLANVNIContext(ctx context.Context, vni int) error {
+	if vni < 1 || vni > maxVXLANVNI {
+		return erroredField("vxlanVNI", vni)
+	}
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   model.GlobalConfigKey{Name: "VXLANVNI"},
+		Value: strconv.Itoa(vni),
+	})
+	return err
+}
+
+// GetGlobalVXLANVNI gets the global VXLAN Network Identifier.  See SetGlobalVXLANVNI for details.
+func (c *config) GetGlobalVXLANVNI() (int, error) {
+	return c.GetGlobalVXLANVNIContext(context.Background())
+}
+
+// GetGlobalVXLANVNIContext is GetGlobalVXLANVNI with a context for the datastore calls.
+func (c *config) GetGlobalVXLANVNIContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, model.GlobalConfigKey{Name: "VXLANVNI"}, GlobalDefaultVXLANVNI)
+}
+
+// SetGlobalVXLANPort sets the global UDP port used by the VXLAN tunnels of all
+// nodes.  The port must be between 1 and 65535.
+func (c *config) SetGlobalVXLANPort(port int) error {
+	return c.SetGlobalVXLANPortContext(context.Background(), port)
+}
+
+// SetGlobalVXLANPortContext is SetGlobalVXLANPort with a context for the datastore calls.
+func (c *config) SetGlobalVXLANPortContext(ctx context.Context, port int) error {
+	if port < 1 || port > maxPort {
+		return erroredField("vxlanPort", port)
+	}
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   model.GlobalConfigKey{Name: "VXLANPort"},
+		Value: strconv.Itoa(port),
+	})
+	return err
+}
+
+// GetGlobalVXLANPort gets the global VXLAN UDP port.  See SetGlobalVXLANPort for details.
+func (c *config) GetGlobalVXLANPort() (int, error) {
+	return c.GetGlobalVXLANPortContext(context.Background())
+}
+
+// GetGlobalVXLANPortContext is GetGlobalVXLANPort with a context for the datastore calls.
+func (c *config) GetGlobalVXLANPortContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, model.GlobalConfigKey{Name: "VXLANPort"}, GlobalDefaultVXLANPort)
+}
+
 // SetNodeIPIPTunnelAddress sets the IP in IP tunnel address for a specific node.
 // Felix will use this to configure the tunnel.
 func (c *config) SetNodeIPIPTunnelAddress(node string, ip *net.IP) error {
+	return c.SetNodeIPIPTunnelAddressContext(context.Background(), node, ip)
+}
+
+// SetNodeIPIPTunnelAddressContext is SetNodeIPIPTunnelAddress with a context for the datastore calls.
+func (c *config) SetNodeIPIPTunnelAddressContext(ctx context.Context, node string, ip *net.IP) error {
+	if err := validateTunnelAddress(ip); err != nil {
+		return err
+	}
 	key := model.HostConfigKey{Hostname: node, Name: "IpInIpTunnelAddr"}
 	if ip == nil {
-		err := c.deleteConfig(key)
+		err := c.deleteConfig(ctx, key)
 		return err
 	} else {
-		_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+		_, err := c.apply(ctx, &model.KVPair{
 			Key:   key,
 			Value: ip.String(),
 		})
@@ -190,8 +483,13 @@ func (c *config) SetNodeIPIPTunnelAddress(node string, ip *net.IP) error {
 // GetNodeIPIPTunnelAddress gets the IP in IP tunnel address for a specific node.
 // See SetNodeIPIPTunnelAddress for more details.
 func (c *config) GetNodeIPIPTunnelAddress(node string) (*net.IP, error) {
+	return c.GetNodeIPIPTunnelAddressContext(context.Background(), node)
+}
+
+// GetNodeIPIPTunnelAddressContext is GetNodeIPIPTunnelAddress with a context for the datastore calls.
+func (c *config) GetNodeIPIPTunnelAddressContext(ctx context.Context, node string) (*net.IP, error) {
 	ip := &net.IP{}
-	if s, err := c.getValue(model.HostConfigKey{Hostname: node, Name: "IpInIpTunnelAddr"}); err != nil {
+	if s, err := c.getValue(ctx, model.HostConfigKey{Hostname: node, Name: "IpInIpTunnelAddr"}); err != nil {
 		return nil, err
 	} else if s == nil {
 		return nil, nil
@@ -205,7 +503,12 @@ func (c *config) GetNodeIPIPTunnelAddress(node string) (*net.IP, error) {
 // SetGlobalLogLevel sets the system global log level used by the node.  This
 // may be overridden on a per-node basis.
 func (c *config) SetGlobalLogLevel(level string) error {
-	return c.setLogLevel(
+	return c.SetGlobalLogLevelContext(context.Background(), level)
+}
+
+// SetGlobalLogLevelContext is SetGlobalLogLevel with a context for the datastore calls.
+func (c *config) SetGlobalLogLevelContext(ctx context.Context, level string) error {
+	return c.setLogLevel(ctx,
 		level,
 		model.GlobalConfigKey{Name: "LogSeverityScreen"},
 		model.GlobalBGPConfigKey{Name: "loglevel"})
@@ -213,7 +516,12 @@ func (c *config) SetGlobalLogLevel(level string) error {
 
 // GetGlobalLogLevel gets the current system global log level.
 func (c *config) GetGlobalLogLevel() (string, error) {
-	s, err := c.getValue(model.GlobalConfigKey{Name: "LogSeverityScreen"})
+	return c.GetGlobalLogLevelContext(context.Background())
+}
+
+// GetGlobalLogLevelContext is GetGlobalLogLevel with a context for the datastore calls.
+func (c *config) GetGlobalLogLevelContext(ctx context.Context) (string, error) {
+	s, err := c.getValue(ctx, model.GlobalConfigKey{Name: "LogSeverityScreen"})
 	if err != nil {
 		return "", err
 	} else if s == nil {
@@ -226,17 +534,27 @@ func (c *config) GetGlobalLogLevel() (string, error) {
 // SetNodeLogLevel sets the node specific log level.  This overrides the global
 // log level.
 func (c *config) SetNodeLogLevel(node string, level string) error {
-	return c.setLogLevel(level,
+	return c.SetNodeLogLevelContext(context.Background(), node, level)
+}
+
+// SetNodeLogLevelContext is SetNodeLogLevel with a context for the datastore calls.
+func (c *config) SetNodeLogLevelContext(ctx context.Context, node string, level string) error {
+	return c.setLogLevel(ctx, level,
 		model.HostConfigKey{Hostname: node, Name: "LogSeverityScreen"},
 		model.NodeBGPConfigKey{Nodename: node, Name: "loglevel"})
 }
 
 // SetNodeLogLevelUseGlobal sets the node to use the global log level.
 func (c *config) SetNodeLogLevelUseGlobal(node string) error {
+	return c.SetNodeLogLevelUseGlobalContext(context.Background(), node)
+}
+
+// SetNodeLogLevelUseGlobalContext is SetNodeLogLevelUseGlobal with a context for the datastore calls.
+func (c *config) SetNodeLogLevelUseGlobalContext(ctx context.Context, node string) error {
 	kf := model.HostConfigKey{Hostname: node, Name: "LogSeverityScreen"}
 	kb := model.NodeBGPConfigKey{Nodename: node, Name: "loglevel"}
-	err1 := c.deleteConfig(kf)
-	err2 := c.deleteConfig(kb)
+	err1 := c.deleteConfig(ctx, kf)
+	err2 := c.deleteConfig(ctx, kb)
 
 	// Return error or nil.
 	if err1 != nil {
@@ -249,23 +567,234 @@ func (c *config) SetNodeLogLevelUseGlobal(node string) error {
 // second return parameter indicates whether the value is explicitly set on the
 // node or inherited from the system-wide global value.
 func (c *config) GetNodeLogLevel(node string) (string, ConfigLocation, error) {
-	s, err := c.getValue(model.HostConfigKey{Hostname: node, Name: "LogSeverityScreen"})
+	return c.GetNodeLogLevelContext(context.Background(), node)
+}
+
+// GetNodeLogLevelContext is GetNodeLogLevel with a context for the datastore calls.
+func (c *config) GetNodeLogLevelContext(ctx context.Context, node string) (string, ConfigLocation, error) {
+	s, err := c.getValue(ctx, model.HostConfigKey{Hostname: node, Name: "LogSeverityScreen"})
 	if err != nil {
 		return "", ConfigLocationNone, err
 	} else if s == nil {
-		l, err := c.GetGlobalLogLevel()
+		l, err := c.GetGlobalLogLevelContext(ctx)
 		return l, ConfigLocationGlobal, err
 	} else {
 		return *s, ConfigLocationNode, nil
 	}
 }
 
+// SetGlobalWireguard sets the system global WireGuard enabled setting.  When
+// WireGuard is enabled, Felix encrypts the traffic between the nodes.  This may
+// be overridden on a per-node basis.
+func (c *config) SetGlobalWireguard(enabled bool) error {
+	return c.SetGlobalWireguardContext(context.Background(), enabled)
+}
+
+// SetGlobalWireguardContext is SetGlobalWireguard with a context for the datastore calls.
+func (c *config) SetGlobalWireguardContext(ctx context.Context, enabled bool) error {
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   model.GlobalConfigKey{Name: "WireguardEnabled"},
+		Value: strconv.FormatBool(enabled),
+	})
+	return err
+}
+
+// GetGlobalWireguard gets the system global WireGuard enabled setting.  See
+// SetGlobalWireguard for details.
+func (c *config) GetGlobalWireguard() (bool, error) {
+	return c.GetGlobalWireguardContext(context.Background())
+}
+
+// GetGlobalWireguardContext is GetGlobalWireguard with a context for the datastore calls.
+func (c *config) GetGlobalWireguardContext(ctx context.Context) (bool, error) {
+	return c.getBoolValue(ctx, model.GlobalConfigKey{Name: "WireguardEnabled"}, GlobalDefaultWireguard)
+}
+
+// SetNodeWireguard sets the node specific WireGuard enabled setting.  This
+// overrides the global setting.
+func (c *config) SetNodeWireguard(node string, enabled bool) error {
+	return c.SetNodeWireguardContext(context.Background(), node, enabled)
+}
+
+// SetNodeWireguardContext is SetNodeWireguard with a context for the datastore calls.
+func (c *config) SetNodeWireguardContext(ctx context.Context, node string, enabled bool) error {
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   model.HostConfigKey{Hostname: node, Name: "WireguardEnabled"},
+		Value: strconv.FormatBool(enabled),
+	})
+	return err
+}
+
+// SetNodeWireguardUseGlobal sets the node to use the global WireGuard enabled setting.
+func (c *config) SetNodeWireguardUseGlobal(node string) error {
+	return c.SetNodeWireguardUseGlobalContext(context.Background(), node)
+}
+
+// SetNodeWireguardUseGlobalContext is SetNodeWireguardUseGlobal with a context for the datastore calls.
+func (c *config) SetNodeWireguardUseGlobalContext(ctx context.Context, node string) error {
+	return c.deleteConfig(ctx, model.HostConfigKey{Hostname: node, Name: "WireguardEnabled"})
+}
+
+// GetNodeWireguard returns the effective WireGuard enabled setting for the
+// node.  The second return parameter indicates whether the value is explicitly
+// set on the node or inherited from the system-wide global value.
+func (c *config) GetNodeWireguard(node string) (bool, ConfigLocation, error) {
+	return c.GetNodeWireguardContext(context.Background(), node)
+}
+
+// GetNodeWireguardContext is GetNodeWireguard with a context for the datastore calls.
+func (c *config) GetNodeWireguardContext(ctx context.Context, node string) (bool, ConfigLocation, error) {
+	s, err := c.getValue(ctx, model.HostConfigKey{Hostname: node, Name: "WireguardEnabled"})
+	if err != nil {
+		return false, ConfigLocationNone, err
+	} else if s == nil {
+		enabled, err := c.GetGlobalWireguardContext(ctx)
+		return enabled, ConfigLocationGlobal, err
+	} else if enabled, err := strconv.ParseBool(*s); err != nil {
+		return false, ConfigLocationNone, err
+	} else {
+		return enabled, ConfigLocationNode, nil
+	}
+}
+
+// SetGlobalWireguardPort sets the system global UDP port that WireGuard listens
+// on.  The port must be between 1 and 65535.  This may be overridden on a
+// per-node basis.
+func (c *config) SetGlobalWireguardPort(port int) error {
+	return c.SetGlobalWireguardPortContext(context.Background(), port)
+}
+
+// SetGlobalWireguardPortContext is SetGlobalWireguardPort with a context for the datastore calls.
+func (c *config) SetGlobalWireguardPortContext(ctx context.Context, port int) error {
+	return c.setPort(ctx, model.GlobalConfigKey{Name: "WireguardListeningPort"}, port)
+}
+
+// GetGlobalWireguardPort gets the system global WireGuard listening port.  See
+// SetGlobalWireguardPort for details.
+func (c *config) GetGlobalWireguardPort() (int, error) {
+	return c.GetGlobalWireguardPortContext(context.Background())
+}
+
+// GetGlobalWireguardPortContext is GetGlobalWireguardPort with a context for the datastore calls.
+func (c *config) GetGlobalWireguardPortContext(ctx context.Context) (int, error) {
+	return c.getIntValue(ctx, model.GlobalConfigKey{Name: "WireguardListeningPort"}, GlobalDefaultWireguardPort)
+}
+
+// SetNodeWireguardPort sets the node specific WireGuard listening port.  This
+// overrides the global setting.
+func (c *config) SetNodeWireguardPort(node string, port int) error {
+	return c.SetNodeWireguardPortContext(context.Background(), node, port)
+}
+
+// SetNodeWireguardPortContext is SetNodeWireguardPort with a context for the datastore calls.
+func (c *config) SetNodeWireguardPortContext(ctx context.Context, node string, port int) error {
+	return c.setPort(ctx, model.HostConfigKey{Hostname: node, Name: "WireguardListeningPort"}, port)
+}
+
+// SetNodeWireguardPortUseGlobal sets the node to use the global WireGuard listening port.
+func (c *config) SetNodeWireguardPortUseGlobal(node string) error {
+	return c.SetNodeWireguardPortUseGlobalContext(context.Background(), node)
+}
+
+// SetNodeWireguardPortUseGlobalContext is SetNodeWireguardPortUseGlobal with a context for the datastore calls.
+func (c *config) SetNodeWireguardPortUseGlobalContext(ctx context.Context, node string) error {
+	return c.deleteConfig(ctx, model.HostConfigKey{Hostname: node, Name: "WireguardListeningPort"})
+}
+
+// GetNodeWireguardPort returns the effective WireGuard listening port for the
+// node.  The second return parameter indicates whether the value is explicitly
+// set on the node or inherited from the system-wide global value.
+func (c *config) GetNodeWireguardPort(node string) (int, ConfigLocation, error) {
+	return c.GetNodeWireguardPortContext(context.Background(), node)
+}
+
+// GetNodeWireguardPortContext is GetNodeWireguardPort with a context for the datastore calls.
+func (c *config) GetNodeWireguardPortContext(ctx context.Context, node string) (int, ConfigLocation, error) {
+	s, err := c.getValue(ctx, model.HostConfigKey{Hostname: node, Name: "WireguardListeningPort"})
+	if err != nil {
+		return 0, ConfigLocationNone, err
+	} else if s == nil {
+		port, err := c.GetGlobalWireguardPortContext(ctx)
+		return port, ConfigLocationGlobal, err
+	} else if port, err := strconv.Atoi(*s); err != nil {
+		return 0, ConfigLocationNone, err
+	} else {
+		return port, ConfigLocationNode, nil
+	}
+}
+
+// SetNodeWireguardPublicKey sets the IPv4 WireGuard public key of a specific
+// node, which the other nodes use to encrypt the traffic to it.  A blank key
+// removes it.
+func (c *config) SetNodeWireguardPublicKey(node string, key string) error {
+	return c.SetNodeWireguardPublicKeyContext(context.Background(), node, key)
+}
+
+// SetNodeWireguardPublicKeyContext is SetNodeWireguardPublicKey with a context for the datastore calls.
+func (c *config) SetNodeWireguardPublicKeyContext(ctx context.Context, node string, key string) error {
+	return c.updateWireguard(ctx, node, func(wg *model.Wireguard) {
+		wg.PublicKey = key
+	})
+}
+
+// GetNodeWireguardPublicKey gets the IPv4 WireGuard public key of a specific
+// node, or a blank key if it has none.  See SetNodeWireguardPublicKey for details.
+func (c *config) GetNodeWireguardPublicKey(node string) (string, error) {
+	return c.GetNodeWireguardPublicKeyContext(context.Background(), node)
+}
+
+// GetNodeWireguardPublicKeyContext is GetNodeWireguardPublicKey with a context for the datastore calls.
+func (c *config) GetNodeWireguardPublicKeyContext(ctx context.Context, node string) (string, error) {
+	wg, err := c.getWireguard(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	return wg.PublicKey, nil
+}
+
+// SetNodeWireguardTunnelAddress sets the IPv4 address of the WireGuard device
+// of a specific node.  A nil address removes it.
+func (c *config) SetNodeWireguardTunnelAddress(node string, ip *net.IP) error {
+	return c.SetNodeWireguardTunnelAddressContext(context.Background(), node, ip)
+}
+
+// SetNodeWireguardTunnelAddressContext is SetNodeWireguardTunnelAddress with a context for the datastore calls.
+func (c *config) SetNodeWireguardTunnelAddressContext(ctx context.Context, node string, ip *net.IP) error {
+	if err := validateTunnelAddress(ip); err != nil {
+		return err
+	}
+	return c.updateWireguard(ctx, node, func(wg *model.Wireguard) {
+		wg.InterfaceIPv4Addr = ip
+	})
+}
+
+// GetNodeWireguardTunnelAddress gets the IPv4 address of the WireGuard device of
+// a specific node, or nil if it has none.  See SetNodeWireguardTunnelAddress for
+// details.
+func (c *config) GetNodeWireguardTunnelAddress(node string) (*net.IP, error) {
+	return c.GetNodeWireguardTunnelAddressContext(context.Background(), node)
+}
+
+// GetNodeWireguardTunnelAddressContext is GetNodeWireguardTunnelAddress with a context for the datastore calls.
+func (c *config) GetNodeWireguardTunnelAddressContext(ctx context.Context, node string) (*net.IP, error) {
+	wg, err := c.getWireguard(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return wg.InterfaceIPv4Addr, nil
+}
+
 // GetFelixConfig provides a mechanism for getting arbitrary Felix configuration
 // in the datastore.  A blank value for the node will get the global
 // configuration.  If the boolean value returned is false, the configurations
 // is unset and the return value should be ignored.
 func (c *config) GetFelixConfig(name, node string) (string, bool, error) {
-	value, err := c.getValue(getFelixConfigKey(name, node))
+	return c.GetFelixConfigContext(context.Background(), name, node)
+}
+
+// GetFelixConfigContext is GetFelixConfig with a context for the datastore calls.
+func (c *config) GetFelixConfigContext(ctx context.Context, name, node string) (string, bool, error) {
+	value, err := c.getValue(ctx, getFelixConfigKey(name, node))
 	if err != nil {
 		return "", false, err
 	} else if value == nil {
@@ -282,7 +811,12 @@ func (c *config) GetFelixConfig(name, node string) (string, bool, error) {
 // Caution should be observed using this method as no validation is performed
 // and changing arbitrary configuration may have unexpected consequences.
 func (c *config) SetFelixConfig(name, node string, value string) error {
-	_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	return c.SetFelixConfigContext(context.Background(), name, node, value)
+}
+
+// SetFelixConfigContext is SetFelixConfig with a context for the datastore calls.
+func (c *config) SetFelixConfigContext(ctx context.Context, name, node string, value string) error {
+	_, err := c.apply(ctx, &model.KVPair{
 		Key:   getFelixConfigKey(name, node),
 		Value: value,
 	})
@@ -296,7 +830,12 @@ func (c *config) SetFelixConfig(name, node string, value string) error {
 // Caution should be observed using this method as no validation is performed
 // and changing arbitrary configuration may have unexpected consequences.
 func (c *config) UnsetFelixConfig(name, node string) error {
-	return c.deleteConfig(getFelixConfigKey(name, node))
+	return c.UnsetFelixConfigContext(context.Background(), name, node)
+}
+
+// UnsetFelixConfigContext is UnsetFelixConfig with a context for the datastore calls.
+func (c *config) UnsetFelixConfigContext(ctx context.Context, name, node string) error {
+	return c.deleteConfig(ctx, getFelixConfigKey(name, node))
 }
 
 // GetBGPConfig provides a mechanism for getting arbitrary BGP configuration
@@ -304,7 +843,12 @@ func (c *config) UnsetFelixConfig(name, node string) error {
 // configuration.  If the boolean value returned is false, the configurations
 // is unset and the return value should be ignored.
 func (c *config) GetBGPConfig(name, node string) (string, bool, error) {
-	value, err := c.getValue(getBGPConfigKey(name, node))
+	return c.GetBGPConfigContext(context.Background(), name, node)
+}
+
+// GetBGPConfigContext is GetBGPConfig with a context for the datastore calls.
+func (c *config) GetBGPConfigContext(ctx context.Context, name, node string) (string, bool, error) {
+	value, err := c.getValue(ctx, getBGPConfigKey(name, node))
 	if err != nil {
 		return "", false, err
 	} else if value == nil {
@@ -321,7 +865,12 @@ func (c *config) GetBGPConfig(name, node string) (string, bool, error) {
 // Caution should be observed using this method as no validation is performed
 // and changing arbitrary configuration may have unexpected consequences.
 func (c *config) SetBGPConfig(name, node string, value string) error {
-	_, err := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	return c.SetBGPConfigContext(context.Background(), name, node, value)
+}
+
+// SetBGPConfigContext is SetBGPConfig with a context for the datastore calls.
+func (c *config) SetBGPConfigContext(ctx context.Context, name, node string, value string) error {
+	_, err := c.apply(ctx, &model.KVPair{
 		Key:   getBGPConfigKey(name, node),
 		Value: value,
 	})
@@ -335,7 +884,49 @@ func (c *config) SetBGPConfig(name, node string, value string) error {
 // Caution should be observed using this method as no validation is performed
 // and changing arbitrary configuration may have unexpected consequences.
 func (c *config) UnsetBGPConfig(name, node string) error {
-	return c.deleteConfig(getBGPConfigKey(name, node))
+	return c.UnsetBGPConfigContext(context.Background(), name, node)
+}
+
+// UnsetBGPConfigContext is UnsetBGPConfig with a context for the datastore calls.
+func (c *config) UnsetBGPConfigContext(ctx context.Context, name, node string) error {
+	return c.deleteConfig(ctx, getBGPConfigKey(name, node))
+}
+
+// WatchGlobalConfig watches the global Felix and BGP configuration with the
+// given name, or all of it if the name is blank.  The watch starts with an
+// added event for each existing value, followed by an event for each change,
+// and runs until it is stopped.
+//
+// Watching is not supported by all datastores: the Kubernetes datastore
+// returns an ErrorOperationNotSupported.
+func (c *config) WatchGlobalConfig(name string) (bapi.WatchInterface, error) {
+	return c.WatchGlobalConfigContext(context.Background(), name)
+}
+
+// WatchGlobalConfigContext is WatchGlobalConfig with a context, which also
+// stops the watch when done.
+func (c *config) WatchGlobalConfigContext(ctx context.Context, name string) (bapi.WatchInterface, error) {
+	return c.watchConfig(ctx,
+		model.GlobalConfigListOptions{Name: name},
+		model.GlobalBGPConfigListOptions{Name: name})
+}
+
+// WatchNodeConfig watches the per-node Felix and BGP configuration of the
+// given node with the given name, or all of it if the name is blank.  See
+// WatchGlobalConfig for details.
+func (c *config) WatchNodeConfig(node, name string) (bapi.WatchInterface, error) {
+	return c.WatchNodeConfigContext(context.Background(), node, name)
+}
+
+// WatchNodeConfigContext is WatchNodeConfig with a context, which also stops
+// the watch when done.
+func (c *config) WatchNodeConfigContext(ctx context.Context, node, name string) (bapi.WatchInterface, error) {
+	if node == "" {
+		return nil, erroredField("node", node)
+	}
+	return c.watchConfig(ctx,
+		model.HostConfigListOptions{Hostname: node, Name: name},
+		model.NodeBGPConfigListOptions{Nodename: node, Name: name})
 }
 
 // getFelixConfigKey returns the model.Key interface for the Felix config.
@@ -355,16 +946,16 @@ func getBGPConfigKey(name, node string) model.Key {
 }
 
 // setLogLevel sets the log level fields with the appropriate log string value.
-func (c *config) setLogLevel(level string, felixKey, bgpKey model.Key) error {
+func (c *config) setLogLevel(ctx context.Context, level string, felixKey, bgpKey model.Key) error {
 	bgpLevel, ok := logToBgp[level]
 	if !ok {
 		return erroredField("loglevel", level)
 	}
-	_, err1 := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	_, err1 := c.apply(ctx, &model.KVPair{
 		Key:   felixKey,
 		Value: level,
 	})
-	_, err2 := c.c.Backend.Apply(context.Background(), &model.KVPair{
+	_, err2 := c.apply(ctx, &model.KVPair{
 		Key:   bgpKey,
 		Value: bgpLevel,
 	})
@@ -376,21 +967,131 @@ func (c *config) setLogLevel(level string, felixKey, bgpKey model.Key) error {
 	return err2
 }
 
+// watchConfig watches the Felix and BGP configuration of the given lists and
+// merges the events of both.
+func (c *config) watchConfig(ctx context.Context, felixList, bgpList model.ListInterface) (bapi.WatchInterface, error) {
+	felixWatch, err := c.c.Backend.Watch(ctx, felixList, "")
+	if err != nil {
+		return nil, err
+	}
+	bgpWatch, err := c.c.Backend.Watch(ctx, bgpList, "")
+	if err != nil {
+		felixWatch.Stop()
+		return nil, err
+	}
+	return newConfigWatcher(ctx, felixWatch, bgpWatch), nil
+}
+
+// apply writes the KVPair to the datastore, or records it for a dry run.
+func (c *config) apply(ctx context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	if c.changes != nil {
+		*c.changes = append(*c.changes, ConfigChange{Key: kvp.Key, Value: kvp.Value})
+		return kvp, nil
+	}
+	old := c.currentValue(ctx, kvp.Key)
+	updated, err := c.c.Backend.Apply(ctx, kvp)
+	if err != nil {
+		return nil, err
+	}
+	c.recordChange(ctx, kvp.Key, old, kvp.Value)
+	return updated, nil
+}
+
+// get reads a KVPair from the datastore.  For a dry run, the last recorded
+// change of the key takes precedence.
+func (c *config) get(ctx context.Context, key model.Key) (*model.KVPair, error) {
+	if c.changes != nil {
+		changes := *c.changes
+		for i := len(changes) - 1; i >= 0; i-- {
+			if !sameKey(changes[i].Key, key) {
+				continue
+			}
+			if changes[i].Value == nil {
+				return nil, errors.ErrorResourceDoesNotExist{Identifier: key}
+			}
+			return &model.KVPair{Key: key, Value: changes[i].Value}, nil
+		}
+	}
+	return c.c.Backend.Get(ctx, key, "")
+}
+
+// list lists the KVPairs that match the list options.  For a dry run, the
+// recorded changes are applied to the listed KVPairs.
+func (c *config) list(ctx context.Context, l model.ListInterface) ([]*model.KVPair, error) {
+	kvps, err := c.c.Backend.List(ctx, l, "")
+	if err != nil {
+		return nil, err
+	}
+	if c.changes == nil {
+		return kvps.KVPairs, nil
+	}
+
+	var paths []string
+	byPath := map[string]*model.KVPair{}
+	set := func(path string, kvp *model.KVPair) {
+		if _, ok := byPath[path]; !ok {
+			paths = append(paths, path)
+		}
+		byPath[path] = kvp
+	}
+	for _, kvp := range kvps.KVPairs {
+		path, err := model.KeyToDefaultPath(kvp.Key)
+		if err != nil {
+			return nil, err
+		}
+		set(path, kvp)
+	}
+	for _, change := range *c.changes {
+		path, err := model.KeyToDefaultPath(change.Key)
+		if err != nil || l.KeyFromDefaultPath(path) == nil {
+			continue
+		}
+		var kvp *model.KVPair
+		if change.Value != nil {
+			kvp = &model.KVPair{Key: change.Key, Value: change.Value}
+		}
+		set(path, kvp)
+	}
+
+	var result []*model.KVPair
+	for _, path := range paths {
+		if kvp := byPath[path]; kvp != nil {
+			result = append(result, kvp)
+		}
+	}
+	return result, nil
+}
+
 // deleteConfig deletes a resource and ignores deleted errors.
-func (c *config) deleteConfig(key model.Key) error {
-	_, err := c.c.Backend.Delete(context.Background(), key, "")
+func (c *config) deleteConfig(ctx context.Context, key model.Key) error {
+	if c.changes != nil {
+		*c.changes = append(*c.changes, ConfigChange{Key: key})
+		return nil
+	}
+	old := c.currentValue(ctx, key)
+	_, err := c.c.Backend.Delete(ctx, key, "")
 	if err != nil {
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 			return err
 		}
+		return nil
 	}
+	c.recordChange(ctx, key, old, nil)
 	return nil
 }
 
+// sameKey returns whether two keys identify the same resource.  Keys holding
+// IP addresses are not comparable, so they are compared by path.
+func sameKey(a, b model.Key) bool {
+	pa, errA := model.KeyToDefaultPath(a)
+	pb, errB := model.KeyToDefaultPath(b)
+	return errA == nil && errB == nil && pa == pb
+}
+
 // getValue returns the string value (pointer) or nil if the key does not
 // exist in the datastore.
-func (c *config) getValue(key model.Key) (*string, error) {
-	kv, err := c.c.Backend.Get(context.Background(), key, "")
+func (c *config) getValue(ctx context.Context, key model.Key) (*string, error) {
+	kv, err := c.get(ctx, key)
 	if err != nil {
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
 			return nil, nil
@@ -403,6 +1104,89 @@ func (c *config) getValue(key model.Key) (*string, error) {
 	}
 }
 
+// getBoolValue returns the boolean value of the key, or the default if the key
+// does not exist in the datastore.
+func (c *config) getBoolValue(ctx context.Context, key model.Key, def bool) (bool, error) {
+	if s, err := c.getValue(ctx, key); err != nil {
+		return false, err
+	} else if s == nil {
+		return def, nil
+	} else if b, err := strconv.ParseBool(*s); err != nil {
+		return false, err
+	} else {
+		return b, nil
+	}
+}
+
+// setPort validates and sets a port value.
+func (c *config) setPort(ctx context.Context, key model.Key, port int) error {
+	if port < 1 || port > maxPort {
+		return erroredField("port", port)
+	}
+	_, err := c.apply(ctx, &model.KVPair{
+		Key:   key,
+		Value: strconv.Itoa(port),
+	})
+	return err
+}
+
+// getWireguard returns the WireGuard settings of the node, which are empty if
+// the node has none.
+func (c *config) getWireguard(ctx context.Context, node string) (*model.Wireguard, error) {
+	kv, err := c.get(ctx, model.WireguardKey{NodeName: node})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return &model.Wireguard{}, nil
+		}
+		return nil, err
+	}
+	return kv.Value.(*model.Wireguard), nil
+}
+
+// updateWireguard applies the update to the WireGuard settings of the node,
+// deleting them if they end up empty.
+func (c *config) updateWireguard(ctx context.Context, node string, update func(*model.Wireguard)) error {
+	wg, err := c.getWireguard(ctx, node)
+	if err != nil {
+		return err
+	}
+	// Update a copy, the value may be shared with the changes of a dry run.
+	updated := *wg
+	update(&updated)
+	key := model.WireguardKey{NodeName: node}
+	if updated == (model.Wireguard{}) {
+		return c.deleteConfig(ctx, key)
+	}
+	_, err = c.apply(ctx, &model.KVPair{
+		Key:   key,
+		Value: &updated,
+	})
+	return err
+}
+
+// getIntValue returns the integer value of the key, or the default if the key
+// does not exist in the datastore.
+func (c *config) getIntValue(ctx context.Context, key model.Key, def int) (int, error) {
+	if s, err := c.getValue(ctx, key); err != nil {
+		return 0, err
+	} else if s == nil {
+		return def, nil
+	} else if i, err := strconv.Atoi(*s); err != nil {
+		return 0, err
+	} else {
+		return i, nil
+	}
+}
+
+// validateTunnelAddress checks that the tunnel address, if set, is an IPv4
+// address.
+func validateTunnelAddress(ip *net.IP) error {
+	if ip != nil && (ip.IP == nil || ip.To4() == nil) {
+		return erroredField("ip", ip)
+	}
+	return nil
+}
+
 // erroredField creates an ErrorValidation.
 func erroredField(name string, value interface{}) error {
 	err := errors.ErrorValidation{
@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// AsUser returns a config that records the given user as the author of its
+// changes in the config audit log.  By default, the changes are recorded with
+// the name of the user running the process.
+func (c *config) AsUser(user string) ConfigInterface {
+	return &config{c: c.c, changes: c.changes, user: user}
+}
+
+// GetFelixConfigHistory returns the audit log of a Felix config key, oldest
+// first.  The node is blank for the global config.
+func (c *config) GetFelixConfigHistory(name, node string) ([]model.ConfigAuditEntry, error) {
+	return c.GetFelixConfigHistoryContext(context.Background(), name, node)
+}
+
+// GetFelixConfigHistoryContext is GetFelixConfigHistory with a context for the datastore calls.
+func (c *config) GetFelixConfigHistoryContext(ctx context.Context, name, node string) ([]model.ConfigAuditEntry, error) {
+	return c.getHistory(ctx, getFelixConfigKey(name, node))
+}
+
+// GetBGPConfigHistory returns the audit log of a BGP config key, oldest first.
+// The node is blank for the global config.
+func (c *config) GetBGPConfigHistory(name, node string) ([]model.ConfigAuditEntry, error) {
+	return c.GetBGPConfigHistoryContext(context.Background(), name, node)
+}
+
+// GetBGPConfigHistoryContext is GetBGPConfigHistory with a context for the datastore calls.
+func (c *config) GetBGPConfigHistoryContext(ctx context.Context, name, node string) ([]model.ConfigAuditEntry, error) {
+	return c.getHistory(ctx, getBGPConfigKey(name, node))
+}
+
+func (c *config) getHistory(ctx context.Context, key model.Key) ([]model.ConfigAuditEntry, error) {
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return nil, err
+	}
+	kvps, err := c.c.Backend.List(ctx, model.ConfigAuditListOptions{}, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(kvps.KVPairs, func(i, j int) bool {
+		return kvps.KVPairs[i].Key.(model.ConfigAuditKey).ID < kvps.KVPairs[j].Key.(model.ConfigAuditKey).ID
+	})
+	var entries []model.ConfigAuditEntry
+	for _, kvp := range kvps.KVPairs {
+		if entry := kvp.Value.(*model.ConfigAuditEntry); entry.Key == path {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+// currentValue returns the value of the key in the datastore, or nil if it
+// does not exist or cannot be read.
+func (c *config) currentValue(ctx context.Context, key model.Key) interface{} {
+	kvp, err := c.c.Backend.Get(ctx, key, "")
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			log.WithError(err).WithField("key", key).Warn("Failed to read config for the audit log")
+		}
+		return nil
+	}
+	return kvp.Value
+}
+
+// recordChange appends an entry for a change of the key to the audit log,
+// unless the value did not change.  The change is already written, so a
+// failure to record it is only logged.
+func (c *config) recordChange(ctx context.Context, key model.Key, old, new interface{}) {
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return
+	}
+	entry := &model.ConfigAuditEntry{
+		Key:       path,
+		User:      c.user,
+		OldValue:  auditValue(old),
+		NewValue:  auditValue(new),
+		Timestamp: time.Now().UTC(),
+	}
+	if entry.OldValue != nil && entry.NewValue != nil && *entry.OldValue == *entry.NewValue {
+		return
+	}
+	if entry.OldValue == nil && entry.NewValue == nil {
+		return
+	}
+	if entry.User == "" {
+		entry.User = processUser()
+	}
+
+	// The IDs sort by time, the random suffix avoids clashes between clients.
+	id := fmt.Sprintf("%020d-%08x", entry.Timestamp.UnixNano(), rand.Uint32())
+	if _, err := c.c.Backend.Create(ctx, &model.KVPair{
+		Key:   model.ConfigAuditKey{ID: id},
+		Value: entry,
+	}); err != nil {
+		log.WithError(err).WithField("key", key).Warn("Failed to record config change in the audit log")
+	}
+}
+
+// auditValue returns the value as recorded in the audit log.
+func auditValue(v interface{}) *string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return &v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s := fmt.Sprint(v)
+			return &s
+		}
+		s := string(b)
+		return &s
+	}
+}
+
+// processUser returns the name of the user running the process, and of the
+// host if known.
+func processUser() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		name += "@" + host
+	}
+	return name
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+var _ = Describe("Config audit log", func() {
+	var backend *testBackend
+	var cfg ConfigInterface
+
+	BeforeEach(func() {
+		backend = &testBackend{kvps: map[string]*model.KVPair{}}
+		cfg = newConfigs(&Client{Backend: backend})
+	})
+
+	values := func(entries []model.ConfigAuditEntry) [][2]*string {
+		var v [][2]*string
+		for _, e := range entries {
+			v = append(v, [2]*string{e.OldValue, e.NewValue})
+		}
+		return v
+	}
+	str := func(s string) *string {
+		return &s
+	}
+
+	It("should record who changed a key, and how", func() {
+		Expect(cfg.AsUser("alice").SetNodeToNodeMesh(true)).To(Succeed())
+		Expect(cfg.AsUser("bob").SetNodeToNodeMesh(false)).To(Succeed())
+		Expect(cfg.UnsetBGPConfig("NodeMeshEnabled", "")).To(Succeed())
+
+		history, err := cfg.GetBGPConfigHistory("NodeMeshEnabled", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(3))
+		Expect(history[0].User).To(Equal("alice"))
+		Expect(history[1].User).To(Equal("bob"))
+		Expect(history[2].User).NotTo(BeEmpty())
+		Expect(values(history)).To(Equal([][2]*string{
+			{nil, str("true")},
+			{str("true"), str("false")},
+			{str("false"), nil},
+		}))
+		Expect(history[0].Timestamp).NotTo(BeZero())
+	})
+
+	It("should only record the changes of the key", func() {
+		Expect(cfg.SetFelixConfig("LogSeverityScreen", "node1", "debug")).To(Succeed())
+		Expect(cfg.SetFelixConfig("LogSeverityScreen", "node1", "debug")).To(Succeed())
+		Expect(cfg.SetFelixConfig("LogSeverityScreen", "", "warning")).To(Succeed())
+		Expect(cfg.UnsetFelixConfig("LogSeverityScreen", "node2")).To(Succeed())
+
+		history, err := cfg.GetFelixConfigHistory("LogSeverityScreen", "node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(history)).To(Equal([][2]*string{{nil, str("debug")}}))
+	})
+
+	It("should not record the changes of a dry run", func() {
+		Expect(cfg.DryRun().SetFelixConfig("LogSeverityScreen", "", "warning")).To(Succeed())
+		history, err := cfg.GetFelixConfigHistory("LogSeverityScreen", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(BeEmpty())
+	})
+})
@@ -0,0 +1,287 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sort"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// BGPPeerConfig is a BGP peer as seen by a node.  The location indicates
+// whether the peer is specific to the node or a global peer of all the nodes.
+type BGPPeerConfig struct {
+	PeerIP   net.IP
+	ASNumber numorstring.ASNumber
+	Password *apiv3.BGPPassword
+	Location ConfigLocation
+}
+
+// SetGlobalBGPPeer creates or updates a global BGP peer, which peers with all
+// the nodes.  The password of an existing peer is kept.
+func (c *config) SetGlobalBGPPeer(peerIP net.IP, asNumber numorstring.ASNumber) error {
+	return c.SetGlobalBGPPeerContext(context.Background(), peerIP, asNumber)
+}
+
+// SetGlobalBGPPeerContext is SetGlobalBGPPeer with a context for the datastore calls.
+func (c *config) SetGlobalBGPPeerContext(ctx context.Context, peerIP net.IP, asNumber numorstring.ASNumber) error {
+	if peerIP.IP == nil {
+		return erroredField("peerIP", peerIP)
+	}
+	return c.setBGPPeer(ctx, model.GlobalBGPPeerKey{PeerIP: peerIP}, asNumber)
+}
+
+// UnsetGlobalBGPPeer deletes a global BGP peer.  Nodes with their own peer of
+// the same address keep it.
+func (c *config) UnsetGlobalBGPPeer(peerIP net.IP) error {
+	return c.UnsetGlobalBGPPeerContext(context.Background(), peerIP)
+}
+
+// UnsetGlobalBGPPeerContext is UnsetGlobalBGPPeer with a context for the datastore calls.
+func (c *config) UnsetGlobalBGPPeerContext(ctx context.Context, peerIP net.IP) error {
+	if peerIP.IP == nil {
+		return erroredField("peerIP", peerIP)
+	}
+	return c.deleteConfig(ctx, model.GlobalBGPPeerKey{PeerIP: peerIP})
+}
+
+// SetGlobalBGPPeerPassword sets the password of a global BGP peer, which must
+// exist.  A nil password removes it.
+func (c *config) SetGlobalBGPPeerPassword(peerIP net.IP, password *apiv3.BGPPassword) error {
+	return c.SetGlobalBGPPeerPasswordContext(context.Background(), peerIP, password)
+}
+
+// SetGlobalBGPPeerPasswordContext is SetGlobalBGPPeerPassword with a context for the datastore calls.
+func (c *config) SetGlobalBGPPeerPasswordContext(ctx context.Context, peerIP net.IP, password *apiv3.BGPPassword) error {
+	if peerIP.IP == nil {
+		return erroredField("peerIP", peerIP)
+	}
+	return c.setBGPPeerPassword(ctx, model.GlobalBGPPeerKey{PeerIP: peerIP}, password)
+}
+
+// GetGlobalBGPPeers returns the global BGP peers, sorted by address.
+func (c *config) GetGlobalBGPPeers() ([]BGPPeerConfig, error) {
+	return c.GetGlobalBGPPeersContext(context.Background())
+}
+
+// GetGlobalBGPPeersContext is GetGlobalBGPPeers with a context for the datastore calls.
+func (c *config) GetGlobalBGPPeersContext(ctx context.Context) ([]BGPPeerConfig, error) {
+	peers, err := c.listBGPPeers(ctx, model.GlobalBGPPeerListOptions{}, ConfigLocationGlobal)
+	if err != nil {
+		return nil, err
+	}
+	return sortedBGPPeers(peers), nil
+}
+
+// SetNodeBGPPeer creates or updates a BGP peer of the node.  This overrides a
+// global peer of the same address.  The password of an existing peer is kept.
+func (c *config) SetNodeBGPPeer(node string, peerIP net.IP, asNumber numorstring.ASNumber) error {
+	return c.SetNodeBGPPeerContext(context.Background(), node, peerIP, asNumber)
+}
+
+// SetNodeBGPPeerContext is SetNodeBGPPeer with a context for the datastore calls.
+func (c *config) SetNodeBGPPeerContext(ctx context.Context, node string, peerIP net.IP, asNumber numorstring.ASNumber) error {
+	key, err := nodeBGPPeerKey(node, peerIP)
+	if err != nil {
+		return err
+	}
+	return c.setBGPPeer(ctx, key, asNumber)
+}
+
+// SetNodeBGPPeerUseGlobal deletes the BGP peer of the node, so that the global
+// peer of the same address, if any, applies.
+func (c *config) SetNodeBGPPeerUseGlobal(node string, peerIP net.IP) error {
+	return c.SetNodeBGPPeerUseGlobalContext(context.Background(), node, peerIP)
+}
+
+// SetNodeBGPPeerUseGlobalContext is SetNodeBGPPeerUseGlobal with a context for the datastore calls.
+func (c *config) SetNodeBGPPeerUseGlobalContext(ctx context.Context, node string, peerIP net.IP) error {
+	key, err := nodeBGPPeerKey(node, peerIP)
+	if err != nil {
+		return err
+	}
+	return c.deleteConfig(ctx, key)
+}
+
+// SetNodeBGPPeerPassword sets the password of a BGP peer of the node, which
+// must exist.  A nil password removes it.
+func (c *config) SetNodeBGPPeerPassword(node string, peerIP net.IP, password *apiv3.BGPPassword) error {
+	return c.SetNodeBGPPeerPasswordContext(context.Background(), node, peerIP, password)
+}
+
+// SetNodeBGPPeerPasswordContext is SetNodeBGPPeerPassword with a context for the datastore calls.
+func (c *config) SetNodeBGPPeerPasswordContext(ctx context.Context, node string, peerIP net.IP, password *apiv3.BGPPassword) error {
+	key, err := nodeBGPPeerKey(node, peerIP)
+	if err != nil {
+		return err
+	}
+	return c.setBGPPeerPassword(ctx, key, password)
+}
+
+// GetNodeBGPPeer returns the effective BGP peer of the node with the given
+// address: the peer of the node if there is one, otherwise the global peer.
+// It returns nil if there is neither.
+func (c *config) GetNodeBGPPeer(node string, peerIP net.IP) (*BGPPeerConfig, error) {
+	return c.GetNodeBGPPeerContext(context.Background(), node, peerIP)
+}
+
+// GetNodeBGPPeerContext is GetNodeBGPPeer with a context for the datastore calls.
+func (c *config) GetNodeBGPPeerContext(ctx context.Context, node string, peerIP net.IP) (*BGPPeerConfig, error) {
+	key, err := nodeBGPPeerKey(node, peerIP)
+	if err != nil {
+		return nil, err
+	}
+	if peer, err := c.getBGPPeer(ctx, key, ConfigLocationNode); err != nil || peer != nil {
+		return peer, err
+	}
+	return c.getBGPPeer(ctx, model.GlobalBGPPeerKey{PeerIP: peerIP}, ConfigLocationGlobal)
+}
+
+// GetNodeBGPPeers returns the effective BGP peers of the node, sorted by
+// address: its own peers and the global peers that they don't override.
+func (c *config) GetNodeBGPPeers(node string) ([]BGPPeerConfig, error) {
+	return c.GetNodeBGPPeersContext(context.Background(), node)
+}
+
+// GetNodeBGPPeersContext is GetNodeBGPPeers with a context for the datastore calls.
+func (c *config) GetNodeBGPPeersContext(ctx context.Context, node string) ([]BGPPeerConfig, error) {
+	if node == "" {
+		return nil, erroredField("node", node)
+	}
+	nodePeers, err := c.listBGPPeers(ctx, model.NodeBGPPeerListOptions{Nodename: node}, ConfigLocationNode)
+	if err != nil {
+		return nil, err
+	}
+	globalPeers, err := c.listBGPPeers(ctx, model.GlobalBGPPeerListOptions{}, ConfigLocationGlobal)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := nodePeers
+	overridden := map[string]bool{}
+	for _, p := range nodePeers {
+		overridden[p.PeerIP.String()] = true
+	}
+	for _, p := range globalPeers {
+		if !overridden[p.PeerIP.String()] {
+			peers = append(peers, p)
+		}
+	}
+	return sortedBGPPeers(peers), nil
+}
+
+// setBGPPeer validates the AS number and writes the peer, keeping the password
+// of an existing one.
+func (c *config) setBGPPeer(ctx context.Context, key model.Key, asNumber numorstring.ASNumber) error {
+	if asNumber == 0 {
+		return erroredField("asNumber", asNumber)
+	}
+	existing, err := c.getBGPPeer(ctx, key, ConfigLocationNone)
+	if err != nil {
+		return err
+	}
+	peer := &model.BGPPeer{PeerIP: bgpPeerKeyIP(key), ASNum: asNumber}
+	if existing != nil {
+		peer.Password = existing.Password
+	}
+	_, err = c.apply(ctx, &model.KVPair{Key: key, Value: peer})
+	return err
+}
+
+// setBGPPeerPassword validates the password and writes it to the existing peer.
+func (c *config) setBGPPeerPassword(ctx context.Context, key model.Key, password *apiv3.BGPPassword) error {
+	if password != nil {
+		ref := password.SecretKeyRef
+		if ref == nil || ref.Name == "" || ref.Key == "" {
+			return erroredField("password", password)
+		}
+	}
+	kvp, err := c.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	peer := *kvp.Value.(*model.BGPPeer)
+	peer.Password = password.DeepCopy()
+	_, err = c.apply(ctx, &model.KVPair{Key: key, Value: &peer})
+	return err
+}
+
+// getBGPPeer returns the peer of the key, or nil if it does not exist.
+func (c *config) getBGPPeer(ctx context.Context, key model.Key, location ConfigLocation) (*BGPPeerConfig, error) {
+	kvp, err := c.get(ctx, key)
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	p := bgpPeerConfig(kvp, location)
+	return &p, nil
+}
+
+func (c *config) listBGPPeers(ctx context.Context, l model.ListInterface, location ConfigLocation) ([]BGPPeerConfig, error) {
+	kvps, err := c.list(ctx, l)
+	if err != nil {
+		return nil, err
+	}
+	var peers []BGPPeerConfig
+	for _, kvp := range kvps {
+		peers = append(peers, bgpPeerConfig(kvp, location))
+	}
+	return peers, nil
+}
+
+func bgpPeerConfig(kvp *model.KVPair, location ConfigLocation) BGPPeerConfig {
+	peer := kvp.Value.(*model.BGPPeer)
+	return BGPPeerConfig{
+		PeerIP:   bgpPeerKeyIP(kvp.Key),
+		ASNumber: peer.ASNum,
+		Password: peer.Password,
+		Location: location,
+	}
+}
+
+func nodeBGPPeerKey(node string, peerIP net.IP) (model.NodeBGPPeerKey, error) {
+	if node == "" {
+		return model.NodeBGPPeerKey{}, erroredField("node", node)
+	}
+	if peerIP.IP == nil {
+		return model.NodeBGPPeerKey{}, erroredField("peerIP", peerIP)
+	}
+	return model.NodeBGPPeerKey{Nodename: node, PeerIP: peerIP}, nil
+}
+
+func bgpPeerKeyIP(key model.Key) net.IP {
+	switch k := key.(type) {
+	case model.GlobalBGPPeerKey:
+		return k.PeerIP
+	case model.NodeBGPPeerKey:
+		return k.PeerIP
+	}
+	return net.IP{}
+}
+
+func sortedBGPPeers(peers []BGPPeerConfig) []BGPPeerConfig {
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].PeerIP.String() < peers[j].PeerIP.String()
+	})
+	return peers
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	k8sv1 "k8s.io/api/core/v1"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+var _ = Describe("BGP peer config", func() {
+	var backend *testBackend
+	var cfg ConfigInterface
+
+	peer1 := *net.ParseIP("10.0.0.1")
+	peer2 := *net.ParseIP("10.0.0.2")
+	password := &apiv3.BGPPassword{
+		SecretKeyRef: &k8sv1.SecretKeySelector{
+			LocalObjectReference: k8sv1.LocalObjectReference{Name: "bgp-secrets"},
+			Key:                  "peer1",
+		},
+	}
+
+	BeforeEach(func() {
+		backend = &testBackend{kvps: map[string]*model.KVPair{}}
+		cfg = newConfigs(&Client{Backend: backend})
+		Expect(cfg.SetGlobalBGPPeer(peer1, 64512)).To(Succeed())
+		Expect(cfg.SetGlobalBGPPeer(peer2, 64512)).To(Succeed())
+	})
+
+	It("should let node peers override the global ones", func() {
+		Expect(cfg.SetNodeBGPPeer("node1", peer2, 64513)).To(Succeed())
+
+		peers, err := cfg.GetNodeBGPPeers("node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peers).To(Equal([]BGPPeerConfig{
+			{PeerIP: peer1, ASNumber: 64512, Location: ConfigLocationGlobal},
+			{PeerIP: peer2, ASNumber: 64513, Location: ConfigLocationNode},
+		}))
+		peer, err := cfg.GetNodeBGPPeer("node2", peer2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peer).To(Equal(&BGPPeerConfig{PeerIP: peer2, ASNumber: 64512, Location: ConfigLocationGlobal}))
+
+		Expect(cfg.SetNodeBGPPeerUseGlobal("node1", peer2)).To(Succeed())
+		peer, err = cfg.GetNodeBGPPeer("node1", peer2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peer.Location).To(Equal(ConfigLocationGlobal))
+
+		Expect(cfg.UnsetGlobalBGPPeer(peer2)).To(Succeed())
+		peer, err = cfg.GetNodeBGPPeer("node1", peer2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peer).To(BeNil())
+	})
+
+	It("should keep the password when the AS number changes", func() {
+		Expect(cfg.SetGlobalBGPPeerPassword(peer1, password)).To(Succeed())
+		Expect(cfg.SetGlobalBGPPeer(peer1, 64520)).To(Succeed())
+		peers, err := cfg.GetGlobalBGPPeers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peers[0]).To(Equal(BGPPeerConfig{
+			PeerIP:   peer1,
+			ASNumber: 64520,
+			Password: password,
+			Location: ConfigLocationGlobal,
+		}))
+
+		Expect(cfg.SetGlobalBGPPeerPassword(peer1, nil)).To(Succeed())
+		peers, err = cfg.GetGlobalBGPPeers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peers[0].Password).To(BeNil())
+	})
+
+	It("should reject invalid values", func() {
+		numKVPs := len(backend.kvps)
+		err := cfg.SetNodeBGPPeer("", peer1, 64513)
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetNodeBGPPeer("node1", net.IP{}, 64513)
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetNodeBGPPeer("node1", peer1, 0)
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetGlobalBGPPeerPassword(peer1, &apiv3.BGPPassword{})
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetNodeBGPPeerPassword("node1", peer1, password)
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorResourceDoesNotExist{}))
+		Expect(backend.kvps).To(HaveLen(numKVPs))
+	})
+
+	It("should return the would-be peers of a dry run", func() {
+		numKVPs := len(backend.kvps)
+		dryRun := cfg.DryRun()
+		Expect(dryRun.SetNodeBGPPeer("node1", peer1, 64513)).To(Succeed())
+		Expect(dryRun.SetNodeBGPPeerPassword("node1", peer1, password)).To(Succeed())
+		Expect(dryRun.UnsetGlobalBGPPeer(peer2)).To(Succeed())
+
+		peers, err := dryRun.GetNodeBGPPeers("node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(peers).To(Equal([]BGPPeerConfig{
+			{PeerIP: peer1, ASNumber: 64513, Password: password, Location: ConfigLocationNode},
+		}))
+		Expect(dryRun.Changes()).To(HaveLen(3))
+		Expect(backend.kvps).To(HaveLen(numKVPs))
+	})
+})
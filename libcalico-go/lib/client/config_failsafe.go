@@ -0,0 +1,288 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+const (
+	failsafeInboundConfigName  = "FailsafeInboundHostPorts"
+	failsafeOutboundConfigName = "FailsafeOutboundHostPorts"
+
+	// failsafePortsNone is the value of an empty list of failsafe ports; an
+	// empty string would give the default ports.
+	failsafePortsNone = "none"
+)
+
+// The default failsafe ports of Felix, which apply while the config is unset.
+var (
+	GlobalDefaultFailsafeInboundPorts = []apiv3.ProtoPort{
+		{Protocol: "TCP", Port: 22},
+		{Protocol: "UDP", Port: 68},
+		{Protocol: "TCP", Port: 179},
+		{Protocol: "TCP", Port: 2379},
+		{Protocol: "TCP", Port: 2380},
+		{Protocol: "TCP", Port: 5473},
+		{Protocol: "TCP", Port: 6443},
+		{Protocol: "TCP", Port: 6666},
+		{Protocol: "TCP", Port: 6667},
+	}
+	GlobalDefaultFailsafeOutboundPorts = []apiv3.ProtoPort{
+		{Protocol: "UDP", Port: 53},
+		{Protocol: "UDP", Port: 67},
+		{Protocol: "TCP", Port: 179},
+		{Protocol: "TCP", Port: 2379},
+		{Protocol: "TCP", Port: 2380},
+		{Protocol: "TCP", Port: 5473},
+		{Protocol: "TCP", Port: 6443},
+		{Protocol: "TCP", Port: 6666},
+		{Protocol: "TCP", Port: 6667},
+	}
+)
+
+// GetFailsafeInboundPorts returns the global failsafe inbound host ports, the
+// ports that Felix always allows to the hosts, whatever the policy.
+func (c *config) GetFailsafeInboundPorts() ([]apiv3.ProtoPort, error) {
+	return c.GetFailsafeInboundPortsContext(context.Background())
+}
+
+// GetFailsafeInboundPortsContext is GetFailsafeInboundPorts with a context for the datastore calls.
+func (c *config) GetFailsafeInboundPortsContext(ctx context.Context) ([]apiv3.ProtoPort, error) {
+	return c.getFailsafePorts(ctx, failsafeInboundConfigName, GlobalDefaultFailsafeInboundPorts)
+}
+
+// AddFailsafeInboundPort adds a port to the global failsafe inbound host
+// ports.  Adding a port that is already in the list does nothing.
+func (c *config) AddFailsafeInboundPort(port apiv3.ProtoPort) error {
+	return c.AddFailsafeInboundPortContext(context.Background(), port)
+}
+
+// AddFailsafeInboundPortContext is AddFailsafeInboundPort with a context for the datastore calls.
+func (c *config) AddFailsafeInboundPortContext(ctx context.Context, port apiv3.ProtoPort) error {
+	return c.addFailsafePort(ctx, failsafeInboundConfigName, GlobalDefaultFailsafeInboundPorts, port)
+}
+
+// RemoveFailsafeInboundPort removes a port from the global failsafe inbound
+// host ports.  Removing a port that is not in the list does nothing.
+//
+// The last port that allows SSH (TCP port 22) cannot be removed, since that
+// could lock the operators out of the hosts; use SetFelixConfig to override
+// the whole list if that is really intended.
+func (c *config) RemoveFailsafeInboundPort(port apiv3.ProtoPort) error {
+	return c.RemoveFailsafeInboundPortContext(context.Background(), port)
+}
+
+// RemoveFailsafeInboundPortContext is RemoveFailsafeInboundPort with a context for the datastore calls.
+func (c *config) RemoveFailsafeInboundPortContext(ctx context.Context, port apiv3.ProtoPort) error {
+	return c.updateFailsafePorts(ctx, failsafeInboundConfigName, GlobalDefaultFailsafeInboundPorts,
+		func(ports []apiv3.ProtoPort) ([]apiv3.ProtoPort, error) {
+			updated := removeProtoPort(ports, port)
+			if allowsSSH(ports) && !allowsSSH(updated) {
+				return nil, erroredField("port", formatProtoPort(port)+" (the last failsafe port for SSH)")
+			}
+			return updated, nil
+		})
+}
+
+// GetFailsafeOutboundPorts returns the global failsafe outbound host ports,
+// the ports that Felix always allows from the hosts, whatever the policy.
+func (c *config) GetFailsafeOutboundPorts() ([]apiv3.ProtoPort, error) {
+	return c.GetFailsafeOutboundPortsContext(context.Background())
+}
+
+// GetFailsafeOutboundPortsContext is GetFailsafeOutboundPorts with a context for the datastore calls.
+func (c *config) GetFailsafeOutboundPortsContext(ctx context.Context) ([]apiv3.ProtoPort, error) {
+	return c.getFailsafePorts(ctx, failsafeOutboundConfigName, GlobalDefaultFailsafeOutboundPorts)
+}
+
+// AddFailsafeOutboundPort adds a port to the global failsafe outbound host
+// ports.  Adding a port that is already in the list does nothing.
+func (c *config) AddFailsafeOutboundPort(port apiv3.ProtoPort) error {
+	return c.AddFailsafeOutboundPortContext(context.Background(), port)
+}
+
+// AddFailsafeOutboundPortContext is AddFailsafeOutboundPort with a context for the datastore calls.
+func (c *config) AddFailsafeOutboundPortContext(ctx context.Context, port apiv3.ProtoPort) error {
+	return c.addFailsafePort(ctx, failsafeOutboundConfigName, GlobalDefaultFailsafeOutboundPorts, port)
+}
+
+// RemoveFailsafeOutboundPort removes a port from the global failsafe outbound
+// host ports.  Removing a port that is not in the list does nothing.
+func (c *config) RemoveFailsafeOutboundPort(port apiv3.ProtoPort) error {
+	return c.RemoveFailsafeOutboundPortContext(context.Background(), port)
+}
+
+// RemoveFailsafeOutboundPortContext is RemoveFailsafeOutboundPort with a context for the datastore calls.
+func (c *config) RemoveFailsafeOutboundPortContext(ctx context.Context, port apiv3.ProtoPort) error {
+	return c.updateFailsafePorts(ctx, failsafeOutboundConfigName, GlobalDefaultFailsafeOutboundPorts,
+		func(ports []apiv3.ProtoPort) ([]apiv3.ProtoPort, error) {
+			return removeProtoPort(ports, port), nil
+		})
+}
+
+// getFailsafePorts returns the failsafe ports of the config, or the defaults
+// if the config is unset.
+func (c *config) getFailsafePorts(ctx context.Context, name string, defaults []apiv3.ProtoPort) ([]apiv3.ProtoPort, error) {
+	s, err := c.getValue(ctx, model.GlobalConfigKey{Name: name})
+	if err != nil {
+		return nil, err
+	} else if s == nil {
+		return append([]apiv3.ProtoPort(nil), defaults...), nil
+	}
+	return parseProtoPorts(*s)
+}
+
+// addFailsafePort validates the port and adds it to the failsafe ports of the
+// config, unless it is already there.
+func (c *config) addFailsafePort(ctx context.Context, name string, defaults []apiv3.ProtoPort, port apiv3.ProtoPort) error {
+	if err := validateProtoPort(port); err != nil {
+		return err
+	}
+	return c.updateFailsafePorts(ctx, name, defaults, func(ports []apiv3.ProtoPort) ([]apiv3.ProtoPort, error) {
+		for _, p := range ports {
+			if protoPortsEqual(p, port) {
+				return ports, nil
+			}
+		}
+		return append(ports, port), nil
+	})
+}
+
+// updateFailsafePorts applies the update to the failsafe ports of the config.
+func (c *config) updateFailsafePorts(ctx context.Context, name string, defaults []apiv3.ProtoPort,
+	update func([]apiv3.ProtoPort) ([]apiv3.ProtoPort, error)) error {
+	ports, err := c.getFailsafePorts(ctx, name, defaults)
+	if err != nil {
+		return err
+	}
+	ports, err = update(ports)
+	if err != nil {
+		return err
+	}
+	_, err = c.apply(ctx, &model.KVPair{
+		Key:   model.GlobalConfigKey{Name: name},
+		Value: formatProtoPorts(ports),
+	})
+	return err
+}
+
+// validateProtoPort checks that the port has a protocol that Felix supports
+// for failsafe ports and, if set, a valid network.
+func validateProtoPort(port apiv3.ProtoPort) error {
+	if p := strings.ToUpper(port.Protocol); p != "TCP" && p != "UDP" {
+		return erroredField("protocol", port.Protocol)
+	}
+	if port.Net != "" {
+		if _, _, err := net.ParseCIDROrIP(port.Net); err != nil {
+			return erroredField("net", port.Net)
+		}
+	}
+	return nil
+}
+
+// parseProtoPorts parses a list of failsafe ports in the format of the Felix
+// config: comma-separated <protocol>:<port>, <protocol>:<net>:<port> or
+// <port> entries, with the IPv6 networks in brackets, or "none".
+func parseProtoPorts(s string) ([]apiv3.ProtoPort, error) {
+	ports := []apiv3.ProtoPort{}
+	if strings.EqualFold(strings.TrimSpace(s), failsafePortsNone) {
+		return ports, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pp := apiv3.ProtoPort{Protocol: "TCP"}
+		if start, end := strings.Index(entry, "["), strings.Index(entry, "]:"); start >= 0 && end > start {
+			pp.Net = entry[start+1 : end]
+			entry = entry[:start] + entry[end+2:]
+		}
+		parts := strings.Split(entry, ":")
+		switch len(parts) {
+		case 1:
+		case 2:
+			pp.Protocol = strings.ToUpper(parts[0])
+		case 3:
+			pp.Protocol = strings.ToUpper(parts[0])
+			pp.Net = parts[1]
+		default:
+			return nil, fmt.Errorf("invalid failsafe port %q", entry)
+		}
+		port, err := strconv.ParseUint(parts[len(parts)-1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failsafe port %q: %w", entry, err)
+		}
+		pp.Port = uint16(port)
+		ports = append(ports, pp)
+	}
+	return ports, nil
+}
+
+// formatProtoPorts formats a list of failsafe ports for the Felix config.
+func formatProtoPorts(ports []apiv3.ProtoPort) string {
+	if len(ports) == 0 {
+		return failsafePortsNone
+	}
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = formatProtoPort(p)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatProtoPort(p apiv3.ProtoPort) string {
+	proto := strings.ToLower(p.Protocol)
+	if p.Net == "" {
+		return fmt.Sprintf("%s:%d", proto, p.Port)
+	}
+	if strings.Contains(p.Net, ":") {
+		return fmt.Sprintf("%s:[%s]:%d", proto, p.Net, p.Port)
+	}
+	return fmt.Sprintf("%s:%s:%d", proto, p.Net, p.Port)
+}
+
+func protoPortsEqual(a, b apiv3.ProtoPort) bool {
+	return strings.EqualFold(a.Protocol, b.Protocol) && a.Port == b.Port && a.Net == b.Net
+}
+
+func removeProtoPort(ports []apiv3.ProtoPort, port apiv3.ProtoPort) []apiv3.ProtoPort {
+	var updated []apiv3.ProtoPort
+	for _, p := range ports {
+		if !protoPortsEqual(p, port) {
+			updated = append(updated, p)
+		}
+	}
+	return updated
+}
+
+// allowsSSH returns whether one of the failsafe ports is the SSH port.
+func allowsSSH(ports []apiv3.ProtoPort) bool {
+	for _, p := range ports {
+		if strings.EqualFold(p.Protocol, "TCP") && p.Port == 22 {
+			return true
+		}
+	}
+	return false
+}
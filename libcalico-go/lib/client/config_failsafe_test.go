@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("Failsafe ports", func() {
+	var backend *testBackend
+	var cfg ConfigInterface
+
+	BeforeEach(func() {
+		backend = &testBackend{kvps: map[string]*model.KVPair{}}
+		cfg = newConfigs(&Client{Backend: backend})
+	})
+
+	setValue := func(name, value string) {
+		_, err := backend.Apply(context.Background(), &model.KVPair{
+			Key:   model.GlobalConfigKey{Name: name},
+			Value: value,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	value := func(name string) interface{} {
+		kvp, err := backend.Get(context.Background(), model.GlobalConfigKey{Name: name}, "")
+		Expect(err).NotTo(HaveOccurred())
+		return kvp.Value
+	}
+
+	It("should return the defaults while unset", func() {
+		ports, err := cfg.GetFailsafeInboundPorts()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ports).To(Equal(GlobalDefaultFailsafeInboundPorts))
+		ports, err = cfg.GetFailsafeOutboundPorts()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ports).To(Equal(GlobalDefaultFailsafeOutboundPorts))
+	})
+
+	It("should parse all the formats of the config", func() {
+		setValue("FailsafeInboundHostPorts", "tcp:22, udp:10.0.0.0/8:53,tcp:[fd00::/64]:443,8080")
+		ports, err := cfg.GetFailsafeInboundPorts()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ports).To(Equal([]apiv3.ProtoPort{
+			{Protocol: "TCP", Port: 22},
+			{Protocol: "UDP", Port: 53, Net: "10.0.0.0/8"},
+			{Protocol: "TCP", Port: 443, Net: "fd00::/64"},
+			{Protocol: "TCP", Port: 8080},
+		}))
+
+		setValue("FailsafeOutboundHostPorts", "none")
+		ports, err = cfg.GetFailsafeOutboundPorts()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ports).To(BeEmpty())
+	})
+
+	It("should add and remove individual ports", func() {
+		setValue("FailsafeOutboundHostPorts", "udp:53")
+		Expect(cfg.AddFailsafeOutboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 443, Net: "fd00::/64"})).To(Succeed())
+		Expect(cfg.AddFailsafeOutboundPort(apiv3.ProtoPort{Protocol: "udp", Port: 53})).To(Succeed())
+		Expect(value("FailsafeOutboundHostPorts")).To(Equal("udp:53,tcp:[fd00::/64]:443"))
+
+		Expect(cfg.RemoveFailsafeOutboundPort(apiv3.ProtoPort{Protocol: "UDP", Port: 53})).To(Succeed())
+		Expect(cfg.RemoveFailsafeOutboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 443, Net: "fd00::/64"})).To(Succeed())
+		Expect(value("FailsafeOutboundHostPorts")).To(Equal("none"))
+	})
+
+	It("should start from the defaults while unset", func() {
+		Expect(cfg.RemoveFailsafeInboundPort(apiv3.ProtoPort{Protocol: "UDP", Port: 68})).To(Succeed())
+		Expect(value("FailsafeInboundHostPorts")).To(Equal(
+			"tcp:22,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:6666,tcp:6667"))
+	})
+
+	It("should reject invalid ports", func() {
+		err := cfg.AddFailsafeInboundPort(apiv3.ProtoPort{Protocol: "SCTP", Port: 9000})
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.AddFailsafeInboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 9000, Net: "10.0.0.300"})
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		Expect(backend.kvps).To(BeEmpty())
+	})
+
+	It("should refuse to remove the last SSH port", func() {
+		setValue("FailsafeInboundHostPorts", "tcp:22,tcp:10.0.0.0/8:22")
+		Expect(cfg.RemoveFailsafeInboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 22})).To(Succeed())
+		err := cfg.RemoveFailsafeInboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 22, Net: "10.0.0.0/8"})
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		Expect(value("FailsafeInboundHostPorts")).To(Equal("tcp:10.0.0.0/8:22"))
+	})
+
+	It("should only record the change in a dry run", func() {
+		dryRun := cfg.DryRun()
+		Expect(dryRun.AddFailsafeInboundPort(apiv3.ProtoPort{Protocol: "TCP", Port: 8443})).To(Succeed())
+		ports, err := dryRun.GetFailsafeInboundPorts()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ports).To(ContainElement(apiv3.ProtoPort{Protocol: "TCP", Port: 8443}))
+		Expect(dryRun.Changes()).To(HaveLen(1))
+		Expect(backend.kvps).To(BeEmpty())
+	})
+})
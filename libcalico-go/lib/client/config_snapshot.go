@@ -0,0 +1,210 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// ConfigSnapshotVersion is the version of the ConfigSnapshot documents written
+// by ExportConfig.
+const ConfigSnapshotVersion = 1
+
+const (
+	ConfigComponentFelix = "felix"
+	ConfigComponentBGP   = "bgp"
+)
+
+// ConfigSnapshot holds all the global and per-node Felix and BGP config keys
+// of a cluster.
+type ConfigSnapshot struct {
+	Version int                           `json:"version"`
+	Felix   map[string]string             `json:"felix,omitempty"`
+	BGP     map[string]string             `json:"bgp,omitempty"`
+	Nodes   map[string]NodeConfigSnapshot `json:"nodes,omitempty"`
+}
+
+// NodeConfigSnapshot holds the per-node config keys of a node.
+type NodeConfigSnapshot struct {
+	Felix map[string]string `json:"felix,omitempty"`
+	BGP   map[string]string `json:"bgp,omitempty"`
+}
+
+// ConfigConflict is a config key whose value in the datastore differs from the
+// one of the snapshot being imported.  The node is blank for global config.
+type ConfigConflict struct {
+	Node      string
+	Component string
+	Name      string
+	Current   string
+	Snapshot  string
+}
+
+func (c ConfigConflict) String() string {
+	scope := "global"
+	if c.Node != "" {
+		scope = "node " + c.Node
+	}
+	return fmt.Sprintf("%s %s config %s: current value %q, snapshot value %q",
+		scope, c.Component, c.Name, c.Current, c.Snapshot)
+}
+
+// ExportConfig returns a snapshot of all the global and per-node Felix and
+// BGP config keys in the datastore.
+func (c *config) ExportConfig() (*ConfigSnapshot, error) {
+	return c.ExportConfigContext(context.Background())
+}
+
+// ExportConfigContext is ExportConfig with a context for the datastore calls.
+func (c *config) ExportConfigContext(ctx context.Context) (*ConfigSnapshot, error) {
+	s := &ConfigSnapshot{Version: ConfigSnapshotVersion}
+	for _, l := range []model.ListInterface{
+		model.GlobalConfigListOptions{},
+		model.GlobalBGPConfigListOptions{},
+		model.HostConfigListOptions{},
+		model.NodeBGPConfigListOptions{},
+	} {
+		kvps, err := c.c.Backend.List(ctx, l, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, kvp := range kvps.KVPairs {
+			value, ok := kvp.Value.(string)
+			if !ok {
+				log.WithField("key", kvp.Key).Warn("Skipping config with a non-string value")
+				continue
+			}
+			switch k := kvp.Key.(type) {
+			case model.GlobalConfigKey:
+				s.Felix = setSnapshotValue(s.Felix, k.Name, value)
+			case model.GlobalBGPConfigKey:
+				s.BGP = setSnapshotValue(s.BGP, k.Name, value)
+			case model.HostConfigKey:
+				n := s.node(k.Hostname)
+				n.Felix = setSnapshotValue(n.Felix, k.Name, value)
+				s.Nodes[k.Hostname] = n
+			case model.NodeBGPConfigKey:
+				n := s.node(k.Nodename)
+				n.BGP = setSnapshotValue(n.BGP, k.Name, value)
+				s.Nodes[k.Nodename] = n
+			}
+		}
+	}
+	return s, nil
+}
+
+// ImportConfig writes the config keys of a snapshot to the datastore.  Keys
+// that are set in the datastore but not in the snapshot are left alone.
+//
+// Keys whose value in the datastore differs from the snapshot are returned as
+// conflicts, sorted by node, component and name.  If there are conflicts and
+// overwrite is false, nothing is written; otherwise the values of the snapshot
+// replace the current ones.
+func (c *config) ImportConfig(snapshot *ConfigSnapshot, overwrite bool) ([]ConfigConflict, error) {
+	return c.ImportConfigContext(context.Background(), snapshot, overwrite)
+}
+
+// ImportConfigContext is ImportConfig with a context for the datastore calls.
+func (c *config) ImportConfigContext(ctx context.Context, snapshot *ConfigSnapshot, overwrite bool) ([]ConfigConflict, error) {
+	if snapshot.Version != ConfigSnapshotVersion {
+		return nil, erroredField("version", snapshot.Version)
+	}
+	for node := range snapshot.Nodes {
+		if node == "" {
+			return nil, erroredField("node", node)
+		}
+	}
+
+	current, err := c.ExportConfigContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []ConfigConflict
+	var writes []*model.KVPair
+	check := func(node, component string, values, currentValues map[string]string) {
+		for name, value := range values {
+			if cur, ok := currentValues[name]; ok {
+				if cur == value {
+					continue
+				}
+				conflicts = append(conflicts, ConfigConflict{
+					Node:      node,
+					Component: component,
+					Name:      name,
+					Current:   cur,
+					Snapshot:  value,
+				})
+			}
+			key := getFelixConfigKey(name, node)
+			if component == ConfigComponentBGP {
+				key = getBGPConfigKey(name, node)
+			}
+			writes = append(writes, &model.KVPair{Key: key, Value: value})
+		}
+	}
+	check("", ConfigComponentFelix, snapshot.Felix, current.Felix)
+	check("", ConfigComponentBGP, snapshot.BGP, current.BGP)
+	for node, n := range snapshot.Nodes {
+		cur := current.Nodes[node]
+		check(node, ConfigComponentFelix, n.Felix, cur.Felix)
+		check(node, ConfigComponentBGP, n.BGP, cur.BGP)
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i], conflicts[j]
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Name < b.Name
+	})
+	if len(conflicts) > 0 && !overwrite {
+		return conflicts, nil
+	}
+
+	for _, kvp := range writes {
+		if _, err := c.apply(ctx, kvp); err != nil {
+			return conflicts, err
+		}
+	}
+	return conflicts, nil
+}
+
+// node returns the snapshot of the node, creating the map of nodes if needed.
+func (s *ConfigSnapshot) node(name string) NodeConfigSnapshot {
+	if s.Nodes == nil {
+		s.Nodes = map[string]NodeConfigSnapshot{}
+	}
+	return s.Nodes[name]
+}
+
+// setSnapshotValue sets a value in a map of config values, creating it if
+// needed.
+func setSnapshotValue(m map[string]string, name, value string) map[string]string {
+	if m == nil {
+		m = map[string]string{}
+	}
+	m[name] = value
+	return m
+}
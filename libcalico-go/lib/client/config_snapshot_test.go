@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// testBackend is a bapi.Client that keeps the KVPairs in a map; only the
+// methods used by the config tests are implemented.
+type testBackend struct {
+	bapi.Client
+	kvps map[string]*model.KVPair
+}
+
+func (b *testBackend) List(ctx context.Context, list model.ListInterface, revision string) (*model.KVPairList, error) {
+	l := &model.KVPairList{}
+	for path, kvp := range b.kvps {
+		if list.KeyFromDefaultPath(path) != nil {
+			l.KVPairs = append(l.KVPairs, kvp)
+		}
+	}
+	return l, nil
+}
+
+func (b *testBackend) Get(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return nil, err
+	}
+	if kvp, ok := b.kvps[path]; ok {
+		return kvp, nil
+	}
+	return nil, errors.ErrorResourceDoesNotExist{Identifier: key}
+}
+
+func (b *testBackend) Apply(ctx context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	path, err := model.KeyToDefaultPath(kvp.Key)
+	if err != nil {
+		return nil, err
+	}
+	b.kvps[path] = kvp
+	return kvp, nil
+}
+
+func (b *testBackend) Create(ctx context.Context, kvp *model.KVPair) (*model.KVPair, error) {
+	path, err := model.KeyToDefaultPath(kvp.Key)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := b.kvps[path]; ok {
+		return nil, errors.ErrorResourceAlreadyExists{Identifier: kvp.Key}
+	}
+	b.kvps[path] = kvp
+	return kvp, nil
+}
+
+func (b *testBackend) Delete(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	path, err := model.KeyToDefaultPath(key)
+	if err != nil {
+		return nil, err
+	}
+	kvp, ok := b.kvps[path]
+	if !ok {
+		return nil, errors.ErrorResourceDoesNotExist{Identifier: key}
+	}
+	delete(b.kvps, path)
+	return kvp, nil
+}
+
+var _ = Describe("Config snapshots", func() {
+	var backend *testBackend
+	var cfg ConfigInterface
+
+	set := func(key model.Key, value string) {
+		_, err := backend.Apply(context.Background(), &model.KVPair{Key: key, Value: value})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		backend = &testBackend{kvps: map[string]*model.KVPair{}}
+		cfg = newConfigs(&Client{Backend: backend})
+		set(model.GlobalConfigKey{Name: "LogSeverityScreen"}, "info")
+		set(model.GlobalBGPConfigKey{Name: "loglevel"}, "info")
+		set(model.HostConfigKey{Hostname: "node1", Name: "IpInIpTunnelAddr"}, "10.0.0.1")
+		set(model.NodeBGPConfigKey{Nodename: "node2", Name: "loglevel"}, "debug")
+	})
+
+	It("should export the global and per-node config", func() {
+		s, err := cfg.ExportConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(Equal(&ConfigSnapshot{
+			Version: ConfigSnapshotVersion,
+			Felix:   map[string]string{"LogSeverityScreen": "info"},
+			BGP:     map[string]string{"loglevel": "info"},
+			Nodes: map[string]NodeConfigSnapshot{
+				"node1": {Felix: map[string]string{"IpInIpTunnelAddr": "10.0.0.1"}},
+				"node2": {BGP: map[string]string{"loglevel": "debug"}},
+			},
+		}))
+	})
+
+	It("should import a snapshot without conflicts", func() {
+		conflicts, err := cfg.ImportConfig(&ConfigSnapshot{
+			Version: ConfigSnapshotVersion,
+			Felix:   map[string]string{"LogSeverityScreen": "info", "IpInIpEnabled": "true"},
+			Nodes: map[string]NodeConfigSnapshot{
+				"node3": {BGP: map[string]string{"loglevel": "none"}},
+			},
+		}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		v, ok, err := cfg.GetFelixConfig("IpInIpEnabled", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("true"))
+		s, err := cfg.ExportConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Nodes["node3"].BGP).To(Equal(map[string]string{"loglevel": "none"}))
+		Expect(s.Nodes["node1"].Felix).To(Equal(map[string]string{"IpInIpTunnelAddr": "10.0.0.1"}))
+	})
+
+	It("should report conflicts and write nothing unless overwriting", func() {
+		snapshot := &ConfigSnapshot{
+			Version: ConfigSnapshotVersion,
+			Felix:   map[string]string{"LogSeverityScreen": "debug", "IpInIpEnabled": "true"},
+			Nodes: map[string]NodeConfigSnapshot{
+				"node2": {BGP: map[string]string{"loglevel": "none"}},
+			},
+		}
+		expected := []ConfigConflict{
+			{Component: ConfigComponentFelix, Name: "LogSeverityScreen", Current: "info", Snapshot: "debug"},
+			{Node: "node2", Component: ConfigComponentBGP, Name: "loglevel", Current: "debug", Snapshot: "none"},
+		}
+
+		conflicts, err := cfg.ImportConfig(snapshot, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal(expected))
+		_, ok, err := cfg.GetFelixConfig("IpInIpEnabled", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		conflicts, err = cfg.ImportConfig(snapshot, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal(expected))
+		v, _, err := cfg.GetBGPConfig("loglevel", "node2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("none"))
+	})
+
+	It("should reject a snapshot of another version", func() {
+		_, err := cfg.ImportConfig(&ConfigSnapshot{Version: ConfigSnapshotVersion + 1}, true)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+var _ = Describe("Config setters", func() {
+	var backend *testBackend
+	var cfg ConfigInterface
+
+	BeforeEach(func() {
+		backend = &testBackend{kvps: map[string]*model.KVPair{}}
+		cfg = newConfigs(&Client{Backend: backend})
+		_, err := backend.Apply(context.Background(), &model.KVPair{
+			Key:   model.HostConfigKey{Hostname: "node1", Name: "LogSeverityScreen"},
+			Value: "debug",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject invalid values with a validation error", func() {
+		err := cfg.SetGlobalASNumber(0)
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetGlobalLogLevel("verbose")
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetNodeIPIPTunnelAddress("node1", &net.IP{})
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		err = cfg.SetNodeWireguardTunnelAddress("node1", net.ParseIP("fd00::1"))
+		Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+		Expect(backend.kvps).To(HaveLen(1))
+	})
+
+	Describe("with a dry run", func() {
+		var dryRun DryRunConfigInterface
+
+		BeforeEach(func() {
+			dryRun = cfg.DryRun()
+		})
+
+		It("should record the changes without writing them", func() {
+			Expect(dryRun.SetGlobalASNumber(64513)).To(Succeed())
+			Expect(dryRun.SetNodeLogLevelUseGlobal("node1")).To(Succeed())
+
+			Expect(dryRun.Changes()).To(Equal([]ConfigChange{
+				{Key: model.GlobalBGPConfigKey{Name: "AsNumber"}, Value: "64513"},
+				{Key: model.HostConfigKey{Hostname: "node1", Name: "LogSeverityScreen"}},
+				{Key: model.NodeBGPConfigKey{Nodename: "node1", Name: "loglevel"}},
+			}))
+			Expect(backend.kvps).To(HaveLen(1))
+			Expect(cfg.(*config).Changes()).To(BeNil())
+		})
+
+		It("should return the would-be values", func() {
+			Expect(dryRun.SetGlobalLogLevel("warning")).To(Succeed())
+			Expect(dryRun.SetNodeLogLevelUseGlobal("node1")).To(Succeed())
+			level, location, err := dryRun.GetNodeLogLevel("node1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level).To(Equal("warning"))
+			Expect(location).To(Equal(ConfigLocationGlobal))
+
+			ip := net.ParseIP("10.0.0.1")
+			Expect(dryRun.SetNodeWireguardTunnelAddress("node1", ip)).To(Succeed())
+			Expect(dryRun.SetNodeWireguardPublicKey("node1", "key")).To(Succeed())
+			wgIP, err := dryRun.GetNodeWireguardTunnelAddress("node1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wgIP).To(Equal(ip))
+
+			level, _, err = cfg.GetNodeLogLevel("node1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level).To(Equal("debug"))
+		})
+
+		It("should reject invalid values without recording them", func() {
+			err := dryRun.SetNodeLogLevel("node1", "verbose")
+			Expect(err).To(BeAssignableToTypeOf(errors.ErrorValidation{}))
+			Expect(dryRun.Changes()).To(BeEmpty())
+		})
+	})
+})
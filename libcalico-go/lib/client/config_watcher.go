@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+)
+
+const configResultsBufSize = 100
+
+// configWatcher merges the events of the watches of the Felix and the BGP
+// configuration.  It terminates when either of them does.
+type configWatcher struct {
+	felixWatch bapi.WatchInterface
+	bgpWatch   bapi.WatchInterface
+	context    context.Context
+	cancel     context.CancelFunc
+	resultChan chan bapi.WatchEvent
+	terminated uint32
+}
+
+func newConfigWatcher(ctx context.Context, felixWatch, bgpWatch bapi.WatchInterface) bapi.WatchInterface {
+	ctx, cancel := context.WithCancel(ctx)
+	cw := &configWatcher{
+		felixWatch: felixWatch,
+		bgpWatch:   bgpWatch,
+		context:    ctx,
+		cancel:     cancel,
+		resultChan: make(chan bapi.WatchEvent, configResultsBufSize),
+	}
+	go cw.processEvents()
+	return cw
+}
+
+// Stop stops the watcher and releases associated resources.
+func (cw *configWatcher) Stop() {
+	cw.cancel()
+	cw.felixWatch.Stop()
+	cw.bgpWatch.Stop()
+}
+
+// ResultChan returns a channel used to receive WatchEvents.
+func (cw *configWatcher) ResultChan() <-chan bapi.WatchEvent {
+	return cw.resultChan
+}
+
+// HasTerminated returns true when the watcher has completed termination processing.
+func (cw *configWatcher) HasTerminated() bool {
+	return atomic.LoadUint32(&cw.terminated) != 0 &&
+		cw.felixWatch.HasTerminated() &&
+		cw.bgpWatch.HasTerminated()
+}
+
+// processEvents passes the events of both watches to the result channel until
+// either watch closes or the watcher is stopped.
+func (cw *configWatcher) processEvents() {
+	defer func() {
+		log.Debug("Config watcher terminated")
+		cw.Stop()
+		close(cw.resultChan)
+		atomic.AddUint32(&cw.terminated, 1)
+	}()
+
+	for {
+		var e bapi.WatchEvent
+		var ok bool
+		select {
+		case e, ok = <-cw.felixWatch.ResultChan():
+			if !ok {
+				log.Debug("Felix config watch channel closed")
+				return
+			}
+		case e, ok = <-cw.bgpWatch.ResultChan():
+			if !ok {
+				log.Debug("BGP config watch channel closed")
+				return
+			}
+		case <-cw.context.Done():
+			return
+		}
+
+		select {
+		case cw.resultChan <- e:
+		case <-cw.context.Done():
+			return
+		}
+	}
+}
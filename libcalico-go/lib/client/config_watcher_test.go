@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// testWatcher is a bapi.WatchInterface whose events are sent by the test.
+type testWatcher struct {
+	events  chan bapi.WatchEvent
+	stopped bool
+}
+
+func newTestWatcher() *testWatcher {
+	return &testWatcher{events: make(chan bapi.WatchEvent)}
+}
+
+func (w *testWatcher) Stop() {
+	w.stopped = true
+}
+
+func (w *testWatcher) ResultChan() <-chan bapi.WatchEvent {
+	return w.events
+}
+
+func (w *testWatcher) HasTerminated() bool {
+	return w.stopped
+}
+
+var _ = Describe("Config watcher", func() {
+	var felixWatch, bgpWatch *testWatcher
+	var cw bapi.WatchInterface
+
+	BeforeEach(func() {
+		felixWatch = newTestWatcher()
+		bgpWatch = newTestWatcher()
+		cw = newConfigWatcher(context.Background(), felixWatch, bgpWatch)
+	})
+
+	It("should merge the events of the Felix and BGP config watches", func() {
+		felixEvent := bapi.WatchEvent{
+			Type: bapi.WatchAdded,
+			New:  &model.KVPair{Key: model.GlobalConfigKey{Name: "LogSeverityScreen"}, Value: "info"},
+		}
+		bgpEvent := bapi.WatchEvent{
+			Type: bapi.WatchDeleted,
+			Old:  &model.KVPair{Key: model.GlobalBGPConfigKey{Name: "loglevel"}, Value: "info"},
+		}
+
+		felixWatch.events <- felixEvent
+		Eventually(cw.ResultChan()).Should(Receive(Equal(felixEvent)))
+		bgpWatch.events <- bgpEvent
+		Eventually(cw.ResultChan()).Should(Receive(Equal(bgpEvent)))
+	})
+
+	It("should terminate and stop both watches when either closes", func() {
+		close(bgpWatch.events)
+		Eventually(cw.ResultChan()).Should(BeClosed())
+		Expect(cw.HasTerminated()).To(BeTrue())
+		Expect(felixWatch.stopped).To(BeTrue())
+	})
+
+	It("should terminate when stopped", func() {
+		cw.Stop()
+		Eventually(cw.ResultChan()).Should(BeClosed())
+		Expect(felixWatch.stopped).To(BeTrue())
+		Expect(bgpWatch.stopped).To(BeTrue())
+	})
+})
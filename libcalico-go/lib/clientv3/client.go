@@ -17,6 +17,7 @@ package clientv3
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend"
 	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/retryclient"
 	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
@@ -49,13 +51,43 @@ type client struct {
 	resources resourceInterface
 }
 
+// Option is an option of New.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	retries      bool
+	retryPolicy  retryclient.Policy
+	retryOptions []retryclient.Option
+}
+
+// WithRetries makes the client retry the datastore operations that fail with a transient error,
+// such as a connection reset or an etcd leader change, using the given policy and the
+// per-operation overrides of the options.  The backend client of the client is then a
+// retryclient.Client, so this option does not suit the callers that need the concrete type of
+// the backend client.
+func WithRetries(policy retryclient.Policy, opts ...retryclient.Option) Option {
+	return func(o *clientOptions) {
+		o.retries = true
+		o.retryPolicy = policy
+		o.retryOptions = opts
+	}
+}
+
 // New returns a connected client. The ClientConfig can either be created explicitly,
 // or can be loaded from a config file or environment variables using the LoadClientConfig() function.
-func New(config apiconfig.CalicoAPIConfig) (Interface, error) {
+func New(config apiconfig.CalicoAPIConfig, opts ...Option) (Interface, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	be, err := backend.NewClient(config)
 	if err != nil {
 		return nil, err
 	}
+	if o.retries {
+		be = retryclient.New(be, o.retryPolicy, o.retryOptions...)
+	}
 	return client{
 		config:    config,
 		backend:   be,
@@ -89,6 +121,16 @@ func (c client) GlobalNetworkPolicies() GlobalNetworkPolicyInterface {
 	return globalNetworkPolicies{client: c}
 }
 
+// StagedGlobalNetworkPolicies returns an interface for managing staged policy resources.
+func (c client) StagedGlobalNetworkPolicies() StagedGlobalNetworkPolicyInterface {
+	return stagedGlobalNetworkPolicies{client: c}
+}
+
+// StagedNetworkPolicies returns an interface for managing staged policy resources.
+func (c client) StagedNetworkPolicies() StagedNetworkPolicyInterface {
+	return stagedNetworkPolicies{client: c}
+}
+
 // IPPools returns an interface for managing IP pool resources.
 func (c client) IPPools() IPPoolInterface {
 	return ipPools{client: c}
@@ -264,7 +306,7 @@ func (c client) ensureClusterInformation(ctx context.Context, calicoVersion, clu
 		clusterInfo, err := c.ClusterInformation().Get(ctx, globalClusterInfoName, options.GetOptions{})
 		if err != nil {
 			// Create the default config if it doesn't already exist.
-			if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			if errors.Is(err, cerrors.ErrNotFound) {
 				newClusterInfo := v3.NewClusterInformation()
 				newClusterInfo.Name = globalClusterInfoName
 				newClusterInfo.Spec.CalicoVersion = calicoVersion
@@ -275,7 +317,7 @@ func (c client) ensureClusterInformation(ctx context.Context, calicoVersion, clu
 				newClusterInfo.Spec.DatastoreReady = &datastoreReady
 				_, err = c.ClusterInformation().Create(ctx, newClusterInfo, options.SetOptions{})
 				if err != nil {
-					if _, ok := err.(cerrors.ErrorResourceAlreadyExists); ok {
+					if errors.Is(err, cerrors.ErrAlreadyExists) {
 						log.Info("Failed to create global ClusterInformation; another node got there first.")
 						time.Sleep(1 * time.Second)
 						continue
@@ -347,7 +389,7 @@ func (c client) ensureClusterInformation(ctx context.Context, calicoVersion, clu
 
 		if updateNeeded {
 			_, err = c.ClusterInformation().Update(ctx, clusterInfo, options.SetOptions{})
-			if _, ok := err.(cerrors.ErrorResourceUpdateConflict); ok {
+			if errors.Is(err, cerrors.ErrConflict) {
 				log.WithError(err).WithField("ClusterInformation", clusterInfo).Warning(
 					"Conflict while updating cluster information, may retry")
 				time.Sleep(1 * time.Second)
@@ -24,6 +24,8 @@ type Interface interface {
 	NodesClient
 	GlobalNetworkPoliciesClient
 	NetworkPoliciesClient
+	StagedGlobalNetworkPoliciesClient
+	StagedNetworkPoliciesClient
 	IPPoolsClient
 	IPReservationsClient
 	ProfilesClient
@@ -66,6 +68,16 @@ type NetworkPoliciesClient interface {
 	NetworkPolicies() NetworkPolicyInterface
 }
 
+type StagedGlobalNetworkPoliciesClient interface {
+	// StagedGlobalNetworkPolicies returns an interface for managing staged global network policy resources.
+	StagedGlobalNetworkPolicies() StagedGlobalNetworkPolicyInterface
+}
+
+type StagedNetworkPoliciesClient interface {
+	// StagedNetworkPolicies returns an interface for managing staged namespaced network policy resources.
+	StagedNetworkPolicies() StagedNetworkPolicyInterface
+}
+
 type IPPoolsClient interface {
 	// IPPools returns an interface for managing IP pool resources.
 	IPPools() IPPoolInterface
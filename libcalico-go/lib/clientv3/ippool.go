@@ -16,6 +16,7 @@ package clientv3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -84,7 +85,7 @@ func (r ipPools) Create(ctx context.Context, res *apiv3.IPPool, opts options.Set
 	}
 
 	blocks, err := r.client.backend.List(ctx, model.BlockListOptions{IPVersion: ipVersion}, "")
-	if _, ok := err.(cerrors.ErrorOperationNotSupported); !ok && err != nil {
+	if err != nil && !errors.Is(err, cerrors.ErrNotSupported) {
 		// There was an error and it wasn't OperationNotSupported - return it.
 		return nil, err
 	} else if err == nil {
@@ -200,7 +201,7 @@ func (r ipPools) Delete(ctx context.Context, name string, opts options.DeleteOpt
 
 		// Depending on the datastore, IPAM may not be supported.  If we get a not supported
 		// error, then continue.  Any other error, fail.
-		if _, ok := err.(cerrors.ErrorOperationNotSupported); !ok && err != nil {
+		if err != nil && !errors.Is(err, cerrors.ErrNotSupported) {
 			return nil, err
 		}
 	}
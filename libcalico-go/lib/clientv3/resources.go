@@ -207,23 +207,39 @@ func (c *resources) Get(ctx context.Context, opts options.GetOptions, kind, ns,
 
 // List lists a resource from the backend datastore.
 func (c *resources) List(ctx context.Context, opts options.ListOptions, kind, listKind string, listObj resourceList) error {
+	selector, err := newListSelector(kind, opts)
+	if err != nil {
+		return err
+	}
 	list := model.ResourceListOptions{
-		Kind:      kind,
-		Name:      opts.Name,
-		Namespace: opts.Namespace,
-		Prefix:    opts.Prefix,
+		Kind:          kind,
+		Name:          opts.Name,
+		Namespace:     opts.Namespace,
+		Prefix:        opts.Prefix,
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
 	}
 
-	// Query the backend.
-	kvps, err := c.backend.List(ctx, list, opts.ResourceVersion)
+	// Query the backend, a page at a time if requested.
+	var kvps *model.KVPairList
+	if opts.Limit > 0 || opts.Continue != "" {
+		kvps, err = bapi.ListPaged(ctx, c.backend, list, opts.ResourceVersion, opts.Limit, opts.Continue)
+	} else {
+		kvps, err = c.backend.List(ctx, list, opts.ResourceVersion)
+	}
 	if err != nil {
 		return err
 	}
 
-	// Convert the slice of KVPairs to a slice of Objects.
+	// Convert the slice of KVPairs to a slice of Objects, dropping any that the backend did
+	// not filter out.
 	resources := []runtime.Object{}
 	for _, kvp := range kvps.KVPairs {
-		resources = append(resources, c.kvPairToResource(kvp))
+		res := c.kvPairToResource(kvp)
+		if selector != nil && !selector.matches(res) {
+			continue
+		}
+		resources = append(resources, res)
 	}
 	err = meta.SetList(listObj, resources)
 	if err != nil {
@@ -232,6 +248,7 @@ func (c *resources) List(ctx context.Context, opts options.ListOptions, kind, li
 
 	// Finally, set the resource version and api group version of the list object.
 	listObj.GetListMeta().SetResourceVersion(kvps.Revision)
+	listObj.GetListMeta().SetContinue(kvps.Continue)
 	listObj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
 		Group:   apiv3.Group,
 		Version: apiv3.VersionCurrent,
@@ -243,10 +260,16 @@ func (c *resources) List(ctx context.Context, opts options.ListOptions, kind, li
 
 // Watch watches a specific resource or resource type.
 func (c *resources) Watch(ctx context.Context, opts options.ListOptions, kind string, converter watcherConverter) (watch.Interface, error) {
+	selector, err := newListSelector(kind, opts)
+	if err != nil {
+		return nil, err
+	}
 	list := model.ResourceListOptions{
-		Kind:      kind,
-		Name:      opts.Name,
-		Namespace: opts.Namespace,
+		Kind:          kind,
+		Name:          opts.Name,
+		Namespace:     opts.Namespace,
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
 	}
 
 	// Create the backend watcher.  We need to process the results to add revision data etc.
@@ -263,6 +286,7 @@ func (c *resources) Watch(ctx context.Context, opts options.ListOptions, kind st
 		context:   ctx,
 		backend:   backend,
 		converter: converter,
+		selector:  selector,
 	}
 	go w.run()
 	return w, nil
@@ -334,6 +358,7 @@ type watcher struct {
 	client     *resources
 	terminated uint32
 	converter  watcherConverter
+	selector   *listSelector
 }
 
 func (w *watcher) Stop() {
@@ -360,6 +385,11 @@ func (w *watcher) run() {
 				return
 			}
 			e := w.convertEvent(event)
+			if w.selector != nil {
+				if e, ok = w.selector.filterEvent(e); !ok {
+					continue
+				}
+			}
 			select {
 			case w.results <- e:
 			case <-w.context.Done():
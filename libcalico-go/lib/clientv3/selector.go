@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+// specFieldsByKind lists the fields of the specs that the field selectors support, by kind.
+var specFieldsByKind = map[string][]string{
+	libapiv3.KindWorkloadEndpoint: {"spec.node", "spec.orchestrator", "spec.workload", "spec.pod"},
+	apiv3.KindHostEndpoint:        {"spec.node"},
+	apiv3.KindBGPPeer:             {"spec.node"},
+}
+
+// selectableFields returns the fields of a resource that the field selectors can select on.
+func selectableFields(res resource) fields.Set {
+	f := fields.Set{
+		"metadata.name":      res.GetObjectMeta().GetName(),
+		"metadata.namespace": res.GetObjectMeta().GetNamespace(),
+	}
+	switch r := res.(type) {
+	case *libapiv3.WorkloadEndpoint:
+		f["spec.node"] = r.Spec.Node
+		f["spec.orchestrator"] = r.Spec.Orchestrator
+		f["spec.workload"] = r.Spec.Workload
+		f["spec.pod"] = r.Spec.Pod
+	case *apiv3.HostEndpoint:
+		f["spec.node"] = r.Spec.Node
+	case *apiv3.BGPPeer:
+		f["spec.node"] = r.Spec.Node
+	}
+	return f
+}
+
+// listSelector filters the resources of a List or Watch by the label and field selectors of
+// the list options.  The backends may filter the resources themselves, but don't have to.
+type listSelector struct {
+	labels labels.Selector
+	fields fields.Selector
+}
+
+// newListSelector returns the selector of the list options of a kind, or nil if the options
+// have no selectors.
+func newListSelector(kind string, opts options.ListOptions) (*listSelector, error) {
+	if opts.LabelSelector == "" && opts.FieldSelector == "" {
+		return nil, nil
+	}
+	invalid := func(name, value, reason string) error {
+		return cerrors.ErrorValidation{
+			ErroredFields: []cerrors.ErroredField{{Name: name, Value: value, Reason: reason}},
+		}
+	}
+
+	s := &listSelector{labels: labels.Everything(), fields: fields.Everything()}
+	var err error
+	if opts.LabelSelector != "" {
+		if s.labels, err = labels.Parse(opts.LabelSelector); err != nil {
+			return nil, invalid("LabelSelector", opts.LabelSelector, err.Error())
+		}
+	}
+	if opts.FieldSelector != "" {
+		if s.fields, err = fields.ParseSelector(opts.FieldSelector); err != nil {
+			return nil, invalid("FieldSelector", opts.FieldSelector, err.Error())
+		}
+		supported := append([]string{"metadata.name", "metadata.namespace"}, specFieldsByKind[kind]...)
+		for _, r := range s.fields.Requirements() {
+			if !contains(supported, r.Field) {
+				return nil, invalid("FieldSelector", opts.FieldSelector, "field "+r.Field+" is not supported for "+kind)
+			}
+		}
+	}
+	return s, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether the resource matches the selectors.
+func (s *listSelector) matches(res resource) bool {
+	return s.labels.Matches(labels.Set(res.GetObjectMeta().GetLabels())) &&
+		s.fields.Matches(selectableFields(res))
+}
+
+// filterEvent filters a watch event, and returns false if the event is to be dropped.  As for
+// the Kubernetes watches, an update of a resource into the selection is an Added event, and an
+// update out of it is a Deleted event.
+func (s *listSelector) filterEvent(e watch.Event) (watch.Event, bool) {
+	switch e.Type {
+	case watch.Added:
+		return e, s.matches(e.Object.(resource))
+	case watch.Deleted:
+		return e, e.Previous == nil || s.matches(e.Previous.(resource))
+	case watch.Modified:
+		newMatches := s.matches(e.Object.(resource))
+		// Without the previous resource, assume that it matched.
+		oldMatches := e.Previous == nil || s.matches(e.Previous.(resource))
+		switch {
+		case newMatches && oldMatches:
+			return e, true
+		case newMatches:
+			return watch.Event{Type: watch.Added, Object: e.Object}, true
+		case oldMatches:
+			previous := e.Previous
+			if previous == nil {
+				previous = e.Object
+			}
+			return watch.Event{Type: watch.Deleted, Previous: previous}, true
+		}
+		return e, false
+	}
+	return e, true
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+var _ = Describe("List selectors", func() {
+	wep := func(name, node, app string) *libapiv3.WorkloadEndpoint {
+		return &libapiv3.WorkloadEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+			Spec:       libapiv3.WorkloadEndpointSpec{Node: node},
+		}
+	}
+
+	It("should match the labels and fields of the resources", func() {
+		s, err := newListSelector(libapiv3.KindWorkloadEndpoint, options.ListOptions{
+			LabelSelector: "app in (web, db)",
+			FieldSelector: "spec.node=node1,metadata.namespace=default",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.matches(wep("wep1", "node1", "web"))).To(BeTrue())
+		Expect(s.matches(wep("wep2", "node2", "web"))).To(BeFalse())
+		Expect(s.matches(wep("wep3", "node1", "cache"))).To(BeFalse())
+	})
+
+	It("should not filter without selectors", func() {
+		s, err := newListSelector(libapiv3.KindWorkloadEndpoint, options.ListOptions{Name: "wep1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeNil())
+	})
+
+	It("should reject the invalid selectors", func() {
+		_, err := newListSelector(apiv3.KindIPPool, options.ListOptions{LabelSelector: "app in"})
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+		_, err = newListSelector(apiv3.KindIPPool, options.ListOptions{FieldSelector: "spec.node=node1"})
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+	})
+
+	It("should turn the updates into and out of the selection into adds and deletes", func() {
+		s, err := newListSelector(libapiv3.KindWorkloadEndpoint, options.ListOptions{LabelSelector: "app=web"})
+		Expect(err).NotTo(HaveOccurred())
+		web, db := wep("wep1", "node1", "web"), wep("wep1", "node1", "db")
+
+		e, ok := s.filterEvent(watch.Event{Type: watch.Added, Object: db})
+		Expect(ok).To(BeFalse())
+
+		e, ok = s.filterEvent(watch.Event{Type: watch.Modified, Previous: db, Object: web})
+		Expect(ok).To(BeTrue())
+		Expect(e).To(Equal(watch.Event{Type: watch.Added, Object: web}))
+
+		e, ok = s.filterEvent(watch.Event{Type: watch.Modified, Previous: web, Object: db})
+		Expect(ok).To(BeTrue())
+		Expect(e).To(Equal(watch.Event{Type: watch.Deleted, Previous: web}))
+
+		e, ok = s.filterEvent(watch.Event{Type: watch.Modified, Object: db})
+		Expect(ok).To(BeTrue())
+		Expect(e).To(Equal(watch.Event{Type: watch.Deleted, Previous: db}))
+
+		_, ok = s.filterEvent(watch.Event{Type: watch.Deleted, Previous: db})
+		Expect(ok).To(BeFalse())
+	})
+})
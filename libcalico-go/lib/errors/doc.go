@@ -18,5 +18,9 @@ may be returned from the client interface.
 
 Errors returned by the client that are not covered by these errors can be considered
 as general internal failures.
+
+Each error type matches one of the Err sentinel errors with errors.Is, and the types that wrap
+an underlying error unwrap to it, so callers can check errors with errors.Is and errors.As
+rather than type assertions, which fail once an error has been wrapped.
 */
 package errors
@@ -15,6 +15,7 @@
 package errors
 
 import (
+	goerrors "errors"
 	"fmt"
 	"net/http"
 
@@ -23,6 +24,27 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Sentinel errors that the error types below match with errors.Is, so that callers can check the
+// kind of an error without asserting its concrete type, even when it has been wrapped:
+//
+//	if errors.Is(err, cerrors.ErrNotFound) { ... }
+//
+// Use errors.As with the error types to get the details of an error.
+var (
+	ErrDatastore               = goerrors.New("datastore error")
+	ErrNotFound                = goerrors.New("resource does not exist")
+	ErrNotSupported            = goerrors.New("operation is not supported")
+	ErrAlreadyExists           = goerrors.New("resource already exists")
+	ErrUnauthorized            = goerrors.New("connection is unauthorized")
+	ErrValidation              = goerrors.New("validation error")
+	ErrInsufficientIdentifiers = goerrors.New("insufficient identifiers")
+	ErrConflict                = goerrors.New("update conflict")
+	ErrBadHandle               = goerrors.New("bad handle")
+	ErrBadSequenceNumber       = goerrors.New("bad sequence number")
+	ErrPartialFailure          = goerrors.New("operation partially failed")
+	ErrParsingDatastoreEntry   = goerrors.New("failed to parse datastore entry")
+)
+
 // Error indicating a problem connecting to the backend.
 type ErrorDatastoreError struct {
 	Err        error
@@ -33,6 +55,14 @@ func (e ErrorDatastoreError) Error() string {
 	return e.Err.Error()
 }
 
+func (e ErrorDatastoreError) Is(target error) bool {
+	return target == ErrDatastore
+}
+
+func (e ErrorDatastoreError) Unwrap() error {
+	return e.Err
+}
+
 func (e ErrorDatastoreError) Status() metav1.Status {
 	if i, ok := e.Err.(apierrors.APIStatus); ok {
 		return i.Status()
@@ -61,6 +91,14 @@ func (e ErrorResourceDoesNotExist) Error() string {
 	return fmt.Sprintf("resource does not exist: %v with error: %v", e.Identifier, e.Err)
 }
 
+func (e ErrorResourceDoesNotExist) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+func (e ErrorResourceDoesNotExist) Unwrap() error {
+	return e.Err
+}
+
 // Error indicating an operation is not supported.
 type ErrorOperationNotSupported struct {
 	Operation  string
@@ -76,6 +114,10 @@ func (e ErrorOperationNotSupported) Error() string {
 	}
 }
 
+func (e ErrorOperationNotSupported) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
 // Error indicating a resource already exists.  Used when attempting to create a
 // resource that already exists.
 type ErrorResourceAlreadyExists struct {
@@ -87,6 +129,14 @@ func (e ErrorResourceAlreadyExists) Error() string {
 	return fmt.Sprintf("resource already exists: %v", e.Identifier)
 }
 
+func (e ErrorResourceAlreadyExists) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
+
+func (e ErrorResourceAlreadyExists) Unwrap() error {
+	return e.Err
+}
+
 // Error indicating a problem connecting to the backend.
 type ErrorConnectionUnauthorized struct {
 	Err error
@@ -96,6 +146,14 @@ func (e ErrorConnectionUnauthorized) Error() string {
 	return fmt.Sprintf("connection is unauthorized: %v", e.Err)
 }
 
+func (e ErrorConnectionUnauthorized) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+func (e ErrorConnectionUnauthorized) Unwrap() error {
+	return e.Err
+}
+
 // Validation error containing the fields that are failed validation.
 type ErrorValidation struct {
 	ErroredFields []ErroredField
@@ -135,6 +193,10 @@ func (e ErrorValidation) Error() string {
 	}
 }
 
+func (e ErrorValidation) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // Error indicating insufficient identifiers have been supplied on a resource
 // management request (create, apply, update, get, delete).
 type ErrorInsufficientIdentifiers struct {
@@ -145,6 +207,10 @@ func (e ErrorInsufficientIdentifiers) Error() string {
 	return fmt.Sprintf("insufficient identifiers, missing '%s'", e.Name)
 }
 
+func (e ErrorInsufficientIdentifiers) Is(target error) bool {
+	return target == ErrInsufficientIdentifiers
+}
+
 // Error indicating an atomic update attempt that failed due to a update conflict.
 type ErrorResourceUpdateConflict struct {
 	Err        error
@@ -155,6 +221,14 @@ func (e ErrorResourceUpdateConflict) Error() string {
 	return fmt.Sprintf("update conflict: %v", e.Identifier)
 }
 
+func (e ErrorResourceUpdateConflict) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e ErrorResourceUpdateConflict) Unwrap() error {
+	return e.Err
+}
+
 // Error indicating that the caller has attempted to release an IP address using
 // outdated information.
 type ErrorBadHandle struct {
@@ -167,6 +241,10 @@ func (e ErrorBadHandle) Error() string {
 	return fmt.Sprintf(f, e.Requested, e.Expected)
 }
 
+func (e ErrorBadHandle) Is(target error) bool {
+	return target == ErrBadHandle
+}
+
 // Error indicating that the caller has attempted to release an IP address using
 // outdated information.
 type ErrorBadSequenceNumber struct {
@@ -179,6 +257,10 @@ func (e ErrorBadSequenceNumber) Error() string {
 	return fmt.Sprintf(f, e.Requested, e.Expected)
 }
 
+func (e ErrorBadSequenceNumber) Is(target error) bool {
+	return target == ErrBadSequenceNumber
+}
+
 // Error indicating that the operation may have partially succeeded, then
 // failed, without rolling back. A common example is when a function failed
 // in an acceptable way after it successfully wrote some data to the datastore.
@@ -190,6 +272,14 @@ func (e ErrorPartialFailure) Error() string {
 	return fmt.Sprintf("operation partially failed: %v", e.Err)
 }
 
+func (e ErrorPartialFailure) Is(target error) bool {
+	return target == ErrPartialFailure
+}
+
+func (e ErrorPartialFailure) Unwrap() error {
+	return e.Err
+}
+
 // UpdateErrorIdentifier modifies the supplied error to use the new resource
 // identifier.
 func UpdateErrorIdentifier(err error, id interface{}) error {
@@ -228,6 +318,14 @@ func (e ErrorParsingDatastoreEntry) Error() string {
 	return fmt.Sprintf("failed to parse datastore entry key=%s; value=%s: %v", e.RawKey, e.RawValue, e.Err)
 }
 
+func (e ErrorParsingDatastoreEntry) Is(target error) bool {
+	return target == ErrParsingDatastoreEntry
+}
+
+func (e ErrorParsingDatastoreEntry) Unwrap() error {
+	return e.Err
+}
+
 type ErrorPolicyConversionRule struct {
 	EgressRule  *networkingv1.NetworkPolicyEgressRule
 	IngressRule *networkingv1.NetworkPolicyIngressRule
@@ -15,11 +15,17 @@
 package errors_test
 
 import (
+	goerrors "errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
@@ -165,3 +171,44 @@ var _ = DescribeTable(
 		"policy: test-policy3: error with the following rules:\n-  &NetworkPolicyEgressRule{Ports:[]NetworkPolicyPort{NetworkPolicyPort{Protocol:nil,Port:80,EndPort:nil,},NetworkPolicyPort{Protocol:nil,Port:-22:-3,EndPort:nil,},},To:[]NetworkPolicyPeer{NetworkPolicyPeer{PodSelector:&v1.LabelSelector{MatchLabels:map[string]string{k: v,k2: v2,},MatchExpressions:[]LabelSelectorRequirement{},},NamespaceSelector:nil,IPBlock:nil,},},} (reason1)\n-  &NetworkPolicyIngressRule{Ports:[]NetworkPolicyPort{NetworkPolicyPort{Protocol:nil,Port:80,EndPort:nil,},NetworkPolicyPort{Protocol:nil,Port:-50:-1,EndPort:nil,},},From:[]NetworkPolicyPeer{NetworkPolicyPeer{PodSelector:&v1.LabelSelector{MatchLabels:map[string]string{k: v,k2: v2,},MatchExpressions:[]LabelSelectorRequirement{},},NamespaceSelector:nil,IPBlock:nil,},},} (reason2)\n-  unknown rule (reason3)\n",
 	),
 )
+
+var _ = DescribeTable(
+	"error sentinels",
+	func(err, sentinel error) {
+		Expect(goerrors.Is(err, sentinel)).To(BeTrue())
+		Expect(goerrors.Is(fmt.Errorf("wrapped: %w", err), sentinel)).To(BeTrue())
+		Expect(goerrors.Is(err, errors.ErrDatastore)).To(Equal(sentinel == errors.ErrDatastore))
+	},
+	Entry("datastore error", errors.ErrorDatastoreError{Err: goerrors.New("failed")}, errors.ErrDatastore),
+	Entry("resource does not exist", errors.ErrorResourceDoesNotExist{Identifier: "foo"}, errors.ErrNotFound),
+	Entry("operation not supported", errors.ErrorOperationNotSupported{Operation: "create"}, errors.ErrNotSupported),
+	Entry("resource already exists", errors.ErrorResourceAlreadyExists{Identifier: "foo"}, errors.ErrAlreadyExists),
+	Entry("connection unauthorized", errors.ErrorConnectionUnauthorized{}, errors.ErrUnauthorized),
+	Entry("validation", errors.ErrorValidation{}, errors.ErrValidation),
+	Entry("insufficient identifiers", errors.ErrorInsufficientIdentifiers{Name: "name"}, errors.ErrInsufficientIdentifiers),
+	Entry("update conflict", errors.ErrorResourceUpdateConflict{Identifier: "foo"}, errors.ErrConflict),
+	Entry("bad handle", errors.ErrorBadHandle{}, errors.ErrBadHandle),
+	Entry("bad sequence number", errors.ErrorBadSequenceNumber{}, errors.ErrBadSequenceNumber),
+	Entry("partial failure", errors.ErrorPartialFailure{}, errors.ErrPartialFailure),
+	Entry("parsing datastore entry", errors.ErrorParsingDatastoreEntry{}, errors.ErrParsingDatastoreEntry),
+)
+
+var _ = Describe("error unwrapping", func() {
+	It("should unwrap to the underlying error", func() {
+		ke := kerrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "pod1")
+		err := fmt.Errorf("get failed: %w", errors.ErrorResourceDoesNotExist{Err: ke, Identifier: "pod1"})
+		Expect(goerrors.Is(err, errors.ErrNotFound)).To(BeTrue())
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+		var e errors.ErrorResourceDoesNotExist
+		Expect(goerrors.As(err, &e)).To(BeTrue())
+		Expect(e.Identifier).To(Equal("pod1"))
+	})
+
+	It("should match the errors wrapped by a partial failure", func() {
+		err := errors.ErrorPartialFailure{Err: errors.ErrorResourceUpdateConflict{Identifier: "foo"}}
+		Expect(goerrors.Is(err, errors.ErrPartialFailure)).To(BeTrue())
+		Expect(goerrors.Is(err, errors.ErrConflict)).To(BeTrue())
+		Expect(goerrors.Is(err, errors.ErrNotFound)).To(BeFalse())
+	})
+})
@@ -105,4 +105,16 @@ type Interface interface {
 	// Otherwise, return the CIDR of the IPAM block allocated for this host.
 	// It returns IPv4, IPv6 block CIDR and any error encountered.
 	EnsureBlock(ctx context.Context, args BlockArgs) (*cnet.IPNet, *cnet.IPNet, error)
+
+	// ClaimBlocks claims affinity to a host for a batch of blocks, either a number of new
+	// blocks from the host's pools or the given blocks, with a single read of the existing
+	// blocks.  The claim is all or nothing: if any block cannot be claimed, the blocks claimed
+	// by the call are released again and an error is returned.  It returns the CIDRs of the
+	// claimed blocks, including any of the given blocks that the host had already claimed.
+	ClaimBlocks(ctx context.Context, args ClaimBlocksArgs) ([]cnet.IPNet, error)
+
+	// GarbageCollect cross-references the IPAM allocations with the nodes and workload endpoints,
+	// and reports the handles whose addresses are no longer in use and the blocks affine to nodes
+	// that no longer exist.  If requested, it also releases them.
+	GarbageCollect(ctx context.Context, args GCArgs) (*GCReport, error)
 }
@@ -56,6 +56,10 @@ const (
 	AttributeTypeVXLANV6     = model.IPAMBlockAttributeTypeVXLANV6
 	AttributeTypeWireguard   = model.IPAMBlockAttributeTypeWireguard
 	AttributeTypeWireguardV6 = model.IPAMBlockAttributeTypeWireguardV6
+
+	// AttributeSticky marks the pod addresses that are kept across restarts of the pod, rather
+	// than released with the pod's container.  Its value is "true".
+	AttributeSticky = model.IPAMBlockAttributeSticky
 )
 
 var (
@@ -116,7 +120,7 @@ func (c ipamClient) AutoAssign(ctx context.Context, args AutoAssignArgs) (*IPAMA
 				return nil, nil, fmt.Errorf("provided IPv4 IPPools list contains one or more IPv6 IPPools")
 			}
 		}
-		v4ia, err = c.autoAssign(ctx, args.Num4, args.HandleID, args.Attrs, args.IPv4Pools, 4, hostname, args.MaxBlocksPerHost, args.HostReservedAttrIPv4s, args.IntendedUse)
+		v4ia, err = c.autoAssign(ctx, args.Num4, args.HandleID, args.Attrs, args.IPv4Pools, 4, hostname, args.NamespaceLabels, args.MaxBlocksPerHost, args.HostReservedAttrIPv4s, args.IntendedUse)
 		if err != nil {
 			log.Errorf("Error assigning IPV4 addresses: %v", err)
 			return v4ia, nil, err
@@ -131,7 +135,7 @@ func (c ipamClient) AutoAssign(ctx context.Context, args AutoAssignArgs) (*IPAMA
 				return nil, nil, fmt.Errorf("provided IPv6 IPPools list contains one or more IPv4 IPPools")
 			}
 		}
-		v6ia, err = c.autoAssign(ctx, args.Num6, args.HandleID, args.Attrs, args.IPv6Pools, 6, hostname, args.MaxBlocksPerHost, args.HostReservedAttrIPv6s, args.IntendedUse)
+		v6ia, err = c.autoAssign(ctx, args.Num6, args.HandleID, args.Attrs, args.IPv6Pools, 6, hostname, args.NamespaceLabels, args.MaxBlocksPerHost, args.HostReservedAttrIPv6s, args.IntendedUse)
 		if err != nil {
 			log.Errorf("Error assigning IPV6 addresses: %v", err)
 			return v4ia, v6ia, err
@@ -245,9 +249,11 @@ func detectOS(ctx context.Context) string {
 // determinePools compares a list of requested pools with the enabled pools and returns the intersect.
 // If any requested pool does not exist, or is not enabled, an error is returned.
 // If no pools are requested, all enabled pools are returned.
-// Also applies selector logic on node labels to determine if the pool is a match.
+// Also applies selector logic on node labels to determine if the pool is a match, and prefers the
+// pools whose namespace selector matches the given namespace labels, if any, over the pools without
+// a namespace selector.
 // Returns the set of matching pools as well as the full set of ip pools.
-func (c ipamClient) determinePools(ctx context.Context, requestedPoolNets []net.IPNet, version int, node libapiv3.Node, maxPrefixLen int) (matchingPools, enabledPools []v3.IPPool, err error) {
+func (c ipamClient) determinePools(ctx context.Context, requestedPoolNets []net.IPNet, version int, node libapiv3.Node, nsLabels map[string]string, maxPrefixLen int) (matchingPools, enabledPools []v3.IPPool, err error) {
 	// Get all the enabled IP pools from the datastore.
 	enabledPools, err = c.pools.GetEnabledPools(version)
 	if err != nil {
@@ -305,7 +311,9 @@ func (c ipamClient) determinePools(ctx context.Context, requestedPoolNets []net.
 
 	// At this point, we've determined the set of enabled IP pools which are valid for use.
 	// We only want to use IP pools which actually match this node, so do a filter based on
-	// selector.
+	// selector.  The pools with a namespace selector are only used by the namespaces they select,
+	// and take precedence over the pools without one.
+	var nsPools []v3.IPPool
 	for _, pool := range enabledPools {
 		var matches bool
 		matches, err = SelectsNode(pool, node)
@@ -319,7 +327,22 @@ func (c ipamClient) determinePools(ctx context.Context, requestedPoolNets []net.
 			continue
 		}
 		log.Debugf("IP pool matches this node: %s", pool.Name)
-		matchingPools = append(matchingPools, pool)
+		if pool.Spec.NamespaceSelector == "" {
+			matchingPools = append(matchingPools, pool)
+			continue
+		}
+		matches, err = SelectsNamespace(pool, nsLabels)
+		if err != nil {
+			log.WithError(err).WithField("pool", pool).Error("failed to determine if namespace matches pool")
+			return
+		}
+		if matches {
+			log.Debugf("IP pool matches the namespace: %s", pool.Name)
+			nsPools = append(nsPools, pool)
+		}
+	}
+	if len(nsPools) > 0 {
+		matchingPools = nsPools
 	}
 
 	return
@@ -328,7 +351,7 @@ func (c ipamClient) determinePools(ctx context.Context, requestedPoolNets []net.
 // prepareAffinityBlocksForHost returns a list of blocks affine to a node based on requested IP pools.
 // It also releases any emptied blocks still affine to this host but no longer part of an IP Pool which
 // selects this node. It returns matching pools, list of host-affine blocks and any error encountered.
-func (c ipamClient) prepareAffinityBlocksForHost(ctx context.Context, requestedPools []net.IPNet, version int, host string, rsvdAttr *HostReservedAttr, use v3.IPPoolAllowedUse) ([]v3.IPPool, []net.IPNet, error) {
+func (c ipamClient) prepareAffinityBlocksForHost(ctx context.Context, requestedPools []net.IPNet, version int, host string, nsLabels map[string]string, rsvdAttr *HostReservedAttr, use v3.IPPoolAllowedUse) ([]v3.IPPool, []net.IPNet, error) {
 	// Retrieve node for given hostname to use for ip pool node selection
 	node, err := c.client.Get(ctx, model.ResourceKey{Kind: libapiv3.KindNode, Name: host}, "")
 	if err != nil {
@@ -348,7 +371,7 @@ func (c ipamClient) prepareAffinityBlocksForHost(ctx context.Context, requestedP
 	}
 
 	// Determine the correct set of IP pools to use for this request.
-	poolsSelectingNode, allPools, err := c.determinePools(ctx, requestedPools, version, *v3n, maxPrefixLen)
+	poolsSelectingNode, allPools, err := c.determinePools(ctx, requestedPools, version, *v3n, nsLabels, maxPrefixLen)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -606,6 +629,7 @@ type IPAMAssignments struct {
 	NumRequested     int               // number of requested IP addresses (not all may be assigned)
 	HostReservedAttr *HostReservedAttr // reserved addresses at start and/or end of blocks
 	Msgs             []string          // warning/error messages to be rendered in case there are any issues with the assignment
+	ExhaustedPools   []string          // names of the pools found to have no free addresses
 }
 
 func (i *IPAMAssignments) AddMsg(msg string) {
@@ -639,7 +663,7 @@ func (i *IPAMAssignments) PartialFulfillmentError() error {
 
 var ErrUseRequired = errors.New("must specify the intended use when assigning an IP")
 
-func (c ipamClient) autoAssign(ctx context.Context, num int, handleID *string, attrs map[string]string, requestedPools []net.IPNet, version int, host string, maxNumBlocks int, rsvdAttr *HostReservedAttr, use v3.IPPoolAllowedUse) (*IPAMAssignments, error) {
+func (c ipamClient) autoAssign(ctx context.Context, num int, handleID *string, attrs map[string]string, requestedPools []net.IPNet, version int, host string, nsLabels map[string]string, maxNumBlocks int, rsvdAttr *HostReservedAttr, use v3.IPPoolAllowedUse) (*IPAMAssignments, error) {
 	// Default parameters.
 	if use == "" {
 		log.Error("Attempting to auto-assign an IP without specifying intended use.")
@@ -658,7 +682,7 @@ func (c ipamClient) autoAssign(ctx context.Context, num int, handleID *string, a
 		logCtx = logCtx.WithField("handle", *handleID)
 	}
 	logCtx.Info("Looking up existing affinities for host")
-	pools, affBlocks, err := c.prepareAffinityBlocksForHost(ctx, requestedPools, version, host, rsvdAttr, use)
+	pools, affBlocks, err := c.prepareAffinityBlocksForHost(ctx, requestedPools, version, host, nsLabels, rsvdAttr, use)
 	if err != nil {
 		return nil, err
 	}
@@ -802,6 +826,7 @@ func (c ipamClient) autoAssign(ctx context.Context, num int, handleID *string, a
 				blockCIDR := newBlockCIDR()
 				if blockCIDR == nil {
 					exhaustedPools = append(exhaustedPools, p.Spec.CIDR)
+					ia.ExhaustedPools = append(ia.ExhaustedPools, p.Name)
 					logCtx.Warningf("All addresses exhausted in pool %s", p.Spec.CIDR)
 					break
 				}
@@ -2170,7 +2195,7 @@ func (c ipamClient) ensureBlock(ctx context.Context, rsvdAttr *HostReservedAttr,
 	logCtx := log.WithFields(log.Fields{"host": host})
 
 	logCtx.Info("Looking up existing affinities for host")
-	pools, affBlocks, err := c.prepareAffinityBlocksForHost(ctx, requestedPools, version, host, rsvdAttr, v3.IPPoolAllowedUseWorkload)
+	pools, affBlocks, err := c.prepareAffinityBlocksForHost(ctx, requestedPools, version, host, nil, rsvdAttr, v3.IPPoolAllowedUseWorkload)
 	if err != nil {
 		return nil, err
 	}
@@ -2206,6 +2231,158 @@ func (c ipamClient) ensureBlock(ctx context.Context, rsvdAttr *HostReservedAttr,
 	return &blockCIDR, nil
 }
 
+// ClaimBlocks claims affinity to the host for a batch of blocks, releasing the blocks it
+// claimed if any of them cannot be claimed.
+func (c ipamClient) ClaimBlocks(ctx context.Context, args ClaimBlocksArgs) ([]net.IPNet, error) {
+	if (args.NumBlocks > 0) == (len(args.Blocks) > 0) {
+		return nil, errors.New("exactly one of the number of blocks and the blocks to claim must be specified")
+	}
+	hostname, err := decideHostname(args.Hostname)
+	if err != nil {
+		return nil, err
+	}
+	logCtx := log.WithFields(log.Fields{"host": hostname, "numBlocks": args.NumBlocks, "blocks": args.Blocks})
+
+	cfg, err := c.GetIPAMConfig(ctx)
+	if err != nil {
+		logCtx.Errorf("Failed to get IPAM Config: %v", err)
+		return nil, err
+	}
+
+	var claimed []net.IPNet
+	if len(args.Blocks) > 0 {
+		// Check all the blocks before claiming any of them.  The blocks that the host
+		// has already claimed are returned, but are not released if the claim fails.
+		logCtx.Info("Claiming blocks")
+		var toClaim []net.IPNet
+		for _, cidr := range args.Blocks {
+			owned, err := c.validateBlockToClaim(ctx, hostname, cidr)
+			if err != nil {
+				return nil, err
+			}
+			if !owned {
+				toClaim = append(toClaim, cidr)
+			}
+		}
+		for _, cidr := range toClaim {
+			if err := c.claimBlock(ctx, hostname, cidr, *cfg, args.HostReservedAttr); err != nil {
+				logCtx.WithError(err).WithField("block", cidr).Error("Failed to claim block, releasing the claimed blocks")
+				c.releaseClaimedBlocks(ctx, hostname, claimed)
+				return nil, err
+			}
+			claimed = append(claimed, cidr)
+		}
+		return args.Blocks, nil
+	}
+
+	if args.IPVersion != 4 && args.IPVersion != 6 {
+		return nil, fmt.Errorf("invalid IP version %d", args.IPVersion)
+	}
+	for _, pool := range args.Pools {
+		if pool.Version() != args.IPVersion {
+			return nil, fmt.Errorf("provided IPPools list contains one or more IPv%d IPPools", pool.Version())
+		}
+	}
+
+	logCtx.Info("Claiming new blocks")
+	pools, _, err := c.prepareAffinityBlocksForHost(ctx, args.Pools, args.IPVersion, hostname, nil, args.HostReservedAttr, v3.IPPoolAllowedUseWorkload)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := c.getReservedIPs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up reserved IPs: %w", err)
+	}
+	blocks, err := c.blockReaderWriter.freeBlocks(ctx, hostname, pools, reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(claimed) < args.NumBlocks {
+		cidr := blocks()
+		if cidr == nil {
+			logCtx.WithField("numClaimed", len(claimed)).Error("Not enough free blocks, releasing the claimed blocks")
+			c.releaseClaimedBlocks(ctx, hostname, claimed)
+			return nil, noFreeBlocksError("No Free Blocks")
+		}
+		if err := c.claimBlock(ctx, hostname, *cidr, *cfg, args.HostReservedAttr); err != nil {
+			if _, ok := err.(errBlockClaimConflict); ok {
+				// Another host claimed the block since we listed the blocks, try the next one.
+				logCtx.Debugf("Block %s is claimed by another host", cidr.String())
+				continue
+			}
+			logCtx.WithError(err).WithField("block", *cidr).Error("Failed to claim block, releasing the claimed blocks")
+			c.releaseClaimedBlocks(ctx, hostname, claimed)
+			return nil, err
+		}
+		claimed = append(claimed, *cidr)
+	}
+	logCtx.Infof("Claimed blocks %v", claimed)
+	return claimed, nil
+}
+
+// validateBlockToClaim checks that the CIDR is exactly one block of a configured pool, and
+// returns whether the host has already claimed it.
+func (c ipamClient) validateBlockToClaim(ctx context.Context, host string, cidr net.IPNet) (bool, error) {
+	pool, err := c.blockReaderWriter.getPoolForIP(net.IP{IP: cidr.IP}, nil)
+	if err != nil {
+		return false, err
+	}
+	if pool == nil {
+		return false, fmt.Errorf("The requested block (%s) is not within any configured pools.", cidr.String())
+	}
+	if ones, _ := cidr.Mask.Size(); ones != pool.Spec.BlockSize || !cidr.IP.Equal(cidr.IP.Mask(cidr.Mask)) {
+		return false, invalidSizeError(fmt.Sprintf("The requested CIDR (%s) is not a block of the pool %s.", cidr.String(), pool.Spec.CIDR))
+	}
+
+	obj, err := c.blockReaderWriter.queryBlock(ctx, cidr, "")
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	b := allocationBlock{obj.Value.(*model.AllocationBlock)}
+	if b.Affinity == nil || !hostAffinityMatches(host, b.AllocationBlock) {
+		return false, errBlockClaimConflict{Block: b}
+	}
+	return true, nil
+}
+
+// claimBlock claims affinity to the host for the block, retrying on update conflicts.  It returns
+// errBlockClaimConflict if the block is claimed by another host.
+func (c ipamClient) claimBlock(ctx context.Context, host string, cidr net.IPNet, cfg IPAMConfig, rsvdAttr *HostReservedAttr) error {
+	var err error
+	for i := 0; i < datastoreRetries; i++ {
+		var pa *model.KVPair
+		pa, err = c.blockReaderWriter.getPendingAffinity(ctx, host, cidr)
+		if err == nil {
+			_, err = c.blockReaderWriter.claimAffineBlock(ctx, pa, cfg, rsvdAttr)
+		}
+		if _, ok := err.(cerrors.ErrorResourceUpdateConflict); !ok {
+			return err
+		}
+		log.WithError(err).WithField("block", cidr).Debug("CAS error claiming block - retry")
+	}
+	return err
+}
+
+// releaseClaimedBlocks releases the affinity of the blocks claimed by a ClaimBlocks call that failed.
+func (c ipamClient) releaseClaimedBlocks(ctx context.Context, host string, blocks []net.IPNet) {
+	for _, block := range blocks {
+		for i := 0; i < datastoreRetries; i++ {
+			err := c.blockReaderWriter.releaseBlockAffinity(ctx, host, block, true)
+			if _, ok := err.(cerrors.ErrorResourceUpdateConflict); ok {
+				continue
+			}
+			if err != nil {
+				log.WithError(err).WithField("block", block).Warn("Failed to release claimed block")
+			}
+			break
+		}
+	}
+}
+
 func (c ipamClient) getReservedIPs(ctx context.Context) (addrFilter, error) {
 	reservations, err := c.reservations.List(ctx, options.ListOptions{})
 	if err != nil {
@@ -164,6 +164,41 @@ func (rw blockReaderWriter) findUsableBlock(ctx context.Context, host string, ve
 	return nil, noFreeBlocksError("No Free Blocks")
 }
 
+// freeBlocks returns a generator of the block cidrs within the given pools which do not yet exist, so that several new
+// blocks can be claimed with a single list of the existing blocks. The provided pools should already be sanitized and
+// only include existing, enabled pools.
+//
+// As with findUsableBlock, the blocks are not reserved and may become claimed by another host before they are claimed.
+func (rw blockReaderWriter) freeBlocks(ctx context.Context, host string, pools []v3.IPPool, reservations addrFilter) (func() *cnet.IPNet, error) {
+	existingBlocks, err := rw.listBlocks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	exists := map[string]bool{}
+	for _, e := range existingBlocks.KVPairs {
+		exists[e.Key.(model.BlockKey).CIDR.String()] = true
+	}
+
+	var blocks func() *cnet.IPNet
+	return func() *cnet.IPNet {
+		for len(pools) > 0 {
+			if blocks == nil {
+				log.Debugf("Looking for free blocks in pool %+v", pools[0])
+				blocks = randomBlockGenerator(pools[0], host)
+			}
+			for subnet := blocks(); subnet != nil; subnet = blocks() {
+				if reservations.MatchesWholeCIDR(subnet) || exists[subnet.String()] {
+					continue
+				}
+				return subnet
+			}
+			blocks = nil
+			pools = pools[1:]
+		}
+		return nil
+	}, nil
+}
+
 // getPendingAffinity claims a pending affinity for the given host and subnet. The affinity can then
 // be used to claim a block. If an affinity already exists, it will return that affinity.
 func (rw blockReaderWriter) getPendingAffinity(ctx context.Context, host string, subnet cnet.IPNet) (*model.KVPair, error) {
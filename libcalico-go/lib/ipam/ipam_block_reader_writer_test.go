@@ -227,7 +227,7 @@ var _ = testutils.E2eDatastoreDescribe("IPAM affine block allocation tests", tes
 						applyNode(bc, kc, testhost, nil)
 						defer deleteNode(bc, kc, testhost)
 
-						ia, err := ic.autoAssign(ctx, 1, &testhost, nil, nil, 4, testhost, 0, nil, v3.IPPoolAllowedUseWorkload)
+						ia, err := ic.autoAssign(ctx, 1, &testhost, nil, nil, 4, testhost, nil, 0, nil, v3.IPPoolAllowedUseWorkload)
 						if err != nil {
 							log.WithError(err).Errorf("Auto assign failed for host %s", testhost)
 							testErr = err
@@ -315,7 +315,7 @@ var _ = testutils.E2eDatastoreDescribe("IPAM affine block allocation tests", tes
 						defer GinkgoRecover()
 						defer wg.Done()
 
-						ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, testhost, 0, nil, v3.IPPoolAllowedUseWorkload)
+						ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, testhost, nil, 0, nil, v3.IPPoolAllowedUseWorkload)
 						if err != nil {
 							log.WithError(err).Errorf("Auto assign failed for host %s", testhost)
 							testErr = err
@@ -729,7 +729,7 @@ var _ = testutils.E2eDatastoreDescribe("IPAM affine block allocation tests", tes
 			}
 
 			By("attempting to claim the block on multiple hosts at the same time", func() {
-				ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, hostA, 0, nil, v3.IPPoolAllowedUseWorkload)
+				ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, hostA, nil, 0, nil, v3.IPPoolAllowedUseWorkload)
 
 				// Shouldn't return an error.
 				Expect(err).NotTo(HaveOccurred())
@@ -759,7 +759,7 @@ var _ = testutils.E2eDatastoreDescribe("IPAM affine block allocation tests", tes
 			})
 
 			By("attempting to claim another address", func() {
-				ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, hostA, 0, nil, v3.IPPoolAllowedUseWorkload)
+				ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, hostA, nil, 0, nil, v3.IPPoolAllowedUseWorkload)
 
 				// Shouldn't return an error.
 				Expect(err).NotTo(HaveOccurred())
@@ -1034,7 +1034,7 @@ var _ = testutils.E2eDatastoreDescribe("IPAM affine block allocation tests", tes
 				blockReaderWriter: rw,
 				reservations:      &fakeReservations{},
 			}
-			ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, host, 0, rsvdAttr, v3.IPPoolAllowedUseTunnel /* for variety */)
+			ia, err := ic.autoAssign(ctx, 1, nil, nil, nil, 4, host, nil, 0, rsvdAttr, v3.IPPoolAllowedUseTunnel /* for variety */)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(len(ia.IPs)).To(Equal(1))
 			Expect(ia.IPs[0].String()).To(Equal("10.0.0.2/30"))
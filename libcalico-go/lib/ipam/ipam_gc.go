@@ -0,0 +1,299 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+// timestampLayout is the layout of the timestamp attribute of the allocations, which the CNI
+// plugin writes with time.Time.String.
+const timestampLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// gcAllocation is an allocated address checked by the garbage collection.
+type gcAllocation struct {
+	ip     string
+	seqNum uint64
+	attrs  map[string]string
+
+	// reason is why the address is leaked, or empty if it is in use.
+	reason string
+}
+
+// GarbageCollect cross-references the IPAM allocations with the nodes and workload endpoints.
+// A handle is leaked when all of its addresses are allocated to nodes that no longer exist, or
+// to pods whose workload endpoints no longer use them, or when no block has an address allocated
+// with it.  A block is orphaned when it is affine to a node that no longer exists, or when it is
+// not within any IP pool.  The addresses whose owner is not recorded in their attributes are
+// never reported as leaked.
+func (c ipamClient) GarbageCollect(ctx context.Context, args GCArgs) (*GCReport, error) {
+	report := &GCReport{Timestamp: time.Now().UTC()}
+	logCtx := log.WithField("release", args.Release)
+	logCtx.Info("Garbage collecting IPAM")
+
+	nodes, err := c.listNodeNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	podIPs, err := c.listPodIPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allPools, err := c.pools.GetAllPools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IP pools: %w", err)
+	}
+
+	// List the handles before the blocks.  A handle is written before the allocations that use it,
+	// so a handle that is written again after this list is not deleted: its revision changed.
+	handles, err := c.blockReaderWriter.listHandles(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IPAM handles: %w", err)
+	}
+	blocks, err := c.client.List(ctx, model.BlockListOptions{}, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IPAM blocks: %w", err)
+	}
+
+	allocsByHandle := map[string][]*gcAllocation{}
+	orphanedBlocks := map[string]*model.AllocationBlock{}
+	for _, kvp := range blocks.KVPairs {
+		b := kvp.Value.(*model.AllocationBlock)
+		report.NumBlocks++
+
+		numAllocs := 0
+		for ord, attrIdx := range b.Allocations {
+			if attrIdx == nil {
+				continue
+			}
+			numAllocs++
+			if *attrIdx >= len(b.Attributes) {
+				continue
+			}
+			attr := b.Attributes[*attrIdx]
+			if attr.AttrPrimary == nil || strings.ToLower(*attr.AttrPrimary) == WindowsReservedHandle {
+				continue
+			}
+			a := &gcAllocation{
+				ip:     b.OrdinalToIP(ord).String(),
+				seqNum: b.GetSequenceNumberForOrdinal(ord),
+				attrs:  attr.AttrSecondary,
+			}
+			if !a.withinGracePeriod(report.Timestamp, args.GracePeriod) {
+				a.reason = a.leakReason(nodes, podIPs)
+			}
+			allocsByHandle[*attr.AttrPrimary] = append(allocsByHandle[*attr.AttrPrimary], a)
+		}
+		report.NumAllocations += numAllocs
+
+		ob := OrphanedBlock{CIDR: b.CIDR.String(), NumAllocations: numAllocs}
+		if b.Affinity != nil {
+			ob.Affinity = *b.Affinity
+		}
+		if host := getHostAffinity(b); host != "" && !nodes.Contains(host) {
+			ob.Reason = GCReasonNodeNotFound
+		} else if pool, err := c.blockReaderWriter.getPoolForIP(cnet.IP{IP: b.CIDR.IP}, allPools); err != nil {
+			return nil, err
+		} else if pool == nil {
+			ob.Reason = GCReasonNoPool
+		}
+		if ob.Reason != "" {
+			report.OrphanedBlocks = append(report.OrphanedBlocks, ob)
+			orphanedBlocks[ob.CIDR] = b
+		}
+	}
+
+	emptyHandles := map[string]*model.KVPair{}
+	for _, kvp := range handles.KVPairs {
+		handleID := kvp.Key.(model.IPAMHandleKey).HandleID
+		if _, ok := allocsByHandle[handleID]; ok || strings.ToLower(handleID) == WindowsReservedHandle {
+			continue
+		}
+		emptyHandles[handleID] = kvp
+		report.LeakedHandles = append(report.LeakedHandles, LeakedHandle{HandleID: handleID, Reason: GCReasonNoAllocations})
+	}
+	for handleID, allocs := range allocsByHandle {
+		if h, ok := leakedHandle(handleID, allocs); ok {
+			report.LeakedHandles = append(report.LeakedHandles, h)
+		}
+	}
+	sort.Slice(report.LeakedHandles, func(i, j int) bool {
+		return report.LeakedHandles[i].HandleID < report.LeakedHandles[j].HandleID
+	})
+	sort.Slice(report.OrphanedBlocks, func(i, j int) bool {
+		return report.OrphanedBlocks[i].CIDR < report.OrphanedBlocks[j].CIDR
+	})
+	logCtx.WithFields(log.Fields{
+		"leakedHandles":  len(report.LeakedHandles),
+		"orphanedBlocks": len(report.OrphanedBlocks),
+	}).Info("IPAM garbage collection found leaks")
+
+	if !args.Release {
+		return report, nil
+	}
+
+	// Release the leaked addresses before the blocks, so that the blocks they empty are deleted.
+	for i := range report.LeakedHandles {
+		h := &report.LeakedHandles[i]
+		if kvp, ok := emptyHandles[h.HandleID]; ok {
+			err = c.blockReaderWriter.deleteHandle(ctx, kvp)
+		} else {
+			err = c.releaseLeakedAllocations(ctx, h.HandleID, allocsByHandle[h.HandleID])
+		}
+		if err != nil {
+			logCtx.WithError(err).WithField("handle", h.HandleID).Warn("Failed to release leaked handle")
+			h.Error = err.Error()
+			continue
+		}
+		logCtx.WithFields(log.Fields{"handle": h.HandleID, "ips": h.IPs, "reason": h.Reason}).Info("Released leaked handle")
+		h.Released = true
+	}
+	for i := range report.OrphanedBlocks {
+		ob := &report.OrphanedBlocks[i]
+		if ob.Affinity == "" {
+			continue
+		}
+		// The blocks of deleted nodes lose their affinity even if addresses are still allocated
+		// from them, the other orphaned blocks only once they are empty.
+		err = c.ReleaseBlockAffinity(ctx, orphanedBlocks[ob.CIDR], ob.Reason != GCReasonNodeNotFound)
+		if err != nil {
+			logCtx.WithError(err).WithField("block", ob.CIDR).Warn("Failed to release orphaned block")
+			ob.Error = err.Error()
+			continue
+		}
+		logCtx.WithFields(log.Fields{"block": ob.CIDR, "affinity": ob.Affinity, "reason": ob.Reason}).Info("Released orphaned block")
+		ob.Released = true
+	}
+	return report, nil
+}
+
+// listNodeNames returns the names of the nodes.
+func (c ipamClient) listNodeNames(ctx context.Context) (set.Set[string], error) {
+	kvps, err := c.client.List(ctx, model.ResourceListOptions{Kind: libapiv3.KindNode}, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodes := set.New[string]()
+	for _, kvp := range kvps.KVPairs {
+		nodes.Add(kvp.Value.(*libapiv3.Node).Name)
+	}
+	return nodes, nil
+}
+
+// listPodIPs returns the addresses of the workload endpoints of each pod, keyed by
+// "namespace/pod".
+func (c ipamClient) listPodIPs(ctx context.Context) (map[string]set.Set[string], error) {
+	kvps, err := c.client.List(ctx, model.ResourceListOptions{Kind: libapiv3.KindWorkloadEndpoint}, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workload endpoints: %w", err)
+	}
+	podIPs := map[string]set.Set[string]{}
+	for _, kvp := range kvps.KVPairs {
+		wep := kvp.Value.(*libapiv3.WorkloadEndpoint)
+		if wep.Spec.Pod == "" {
+			continue
+		}
+		pod := wep.Namespace + "/" + wep.Spec.Pod
+		if podIPs[pod] == nil {
+			podIPs[pod] = set.New[string]()
+		}
+		for _, addr := range wep.Spec.IPNetworks {
+			ip, _, err := cnet.ParseCIDROrIP(addr)
+			if err != nil {
+				log.WithError(err).WithField("workloadEndpoint", wep.Name).Warn("Failed to parse workload endpoint address")
+				continue
+			}
+			podIPs[pod].Add(ip.String())
+		}
+	}
+	return podIPs, nil
+}
+
+// releaseLeakedAllocations releases the addresses of a leaked handle.  The sequence numbers make
+// sure that an address reallocated since it was found leaked is not released.
+func (c ipamClient) releaseLeakedAllocations(ctx context.Context, handleID string, allocs []*gcAllocation) error {
+	var opts []ReleaseOptions
+	for _, a := range allocs {
+		seqNum := a.seqNum
+		opts = append(opts, ReleaseOptions{Address: a.ip, Handle: handleID, SequenceNumber: &seqNum})
+	}
+	_, err := c.ReleaseIPs(ctx, opts...)
+	return err
+}
+
+// leakedHandle returns the leaked handle of the given allocations, if they are all leaked.
+func leakedHandle(handleID string, allocs []*gcAllocation) (LeakedHandle, bool) {
+	h := LeakedHandle{
+		HandleID:  handleID,
+		Node:      allocs[0].attrs[AttributeNode],
+		Namespace: allocs[0].attrs[AttributeNamespace],
+		Pod:       allocs[0].attrs[AttributePod],
+		Reason:    allocs[0].reason,
+	}
+	for _, a := range allocs {
+		if a.reason == "" {
+			return LeakedHandle{}, false
+		}
+		h.IPs = append(h.IPs, a.ip)
+	}
+	return h, true
+}
+
+// withinGracePeriod returns whether the address was allocated less than the grace period ago.
+func (a *gcAllocation) withinGracePeriod(now time.Time, gracePeriod time.Duration) bool {
+	ts := a.attrs[AttributeTimestamp]
+	if gracePeriod == 0 || ts == "" {
+		return false
+	}
+	t, err := time.Parse(timestampLayout, ts)
+	if err != nil {
+		log.WithError(err).WithField("ip", a.ip).Debug("Failed to parse allocation timestamp")
+		return false
+	}
+	return now.Sub(t) < gracePeriod
+}
+
+// leakReason returns why the address is leaked, or an empty string if it is in use, or if its
+// owner is not known.
+func (a *gcAllocation) leakReason(nodes set.Set[string], podIPs map[string]set.Set[string]) string {
+	if node := a.attrs[AttributeNode]; node != "" && !nodes.Contains(node) {
+		return GCReasonNodeNotFound
+	}
+	pod := a.attrs[AttributePod]
+	if pod == "" {
+		// Not a pod address, for example a tunnel address, which is in use while its node exists.
+		return ""
+	}
+	ips, ok := podIPs[a.attrs[AttributeNamespace]+"/"+pod]
+	if !ok {
+		return GCReasonWorkloadNotFound
+	}
+	// The sticky addresses are the pod's while it exists, even between its sandboxes.
+	if !ips.Contains(a.ip) && a.attrs[AttributeSticky] != "true" {
+		return GCReasonIPNotInUse
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+var _ = DescribeTable("IPAM garbage collection leak reasons",
+	func(ip string, attrs map[string]string, expected string) {
+		nodes := set.From("node1")
+		podIPs := map[string]set.Set[string]{
+			"default/pod1": set.From("10.0.0.1", "fd00::1"),
+		}
+		a := &gcAllocation{ip: ip, attrs: attrs}
+		Expect(a.leakReason(nodes, podIPs)).To(Equal(expected))
+	},
+	Entry("pod IP in use", "10.0.0.1",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod1"}, ""),
+	Entry("pod IPv6 in use", "fd00::1",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod1"}, ""),
+	Entry("node deleted", "10.0.0.1",
+		map[string]string{AttributeNode: "node2", AttributeNamespace: "default", AttributePod: "pod1"}, GCReasonNodeNotFound),
+	Entry("pod deleted", "10.0.0.2",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod2"}, GCReasonWorkloadNotFound),
+	Entry("pod in another namespace", "10.0.0.1",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "other", AttributePod: "pod1"}, GCReasonWorkloadNotFound),
+	Entry("pod no longer using the IP", "10.0.0.3",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod1"}, GCReasonIPNotInUse),
+	Entry("sticky IP of an existing pod", "10.0.0.3",
+		map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod1", AttributeSticky: "true"}, ""),
+	Entry("tunnel address of an existing node", "10.0.0.4",
+		map[string]string{AttributeNode: "node1", AttributeType: AttributeTypeVXLAN}, ""),
+	Entry("tunnel address of a deleted node", "10.0.0.4",
+		map[string]string{AttributeNode: "node2", AttributeType: AttributeTypeVXLAN}, GCReasonNodeNotFound),
+	Entry("unknown owner", "10.0.0.5", nil, ""),
+)
+
+var _ = Describe("IPAM garbage collection", func() {
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	It("should only report the allocations older than the grace period", func() {
+		old := &gcAllocation{attrs: map[string]string{AttributeTimestamp: now.Add(-time.Hour).String()}}
+		recent := &gcAllocation{attrs: map[string]string{AttributeTimestamp: now.Add(-time.Minute).String()}}
+		unknown := &gcAllocation{attrs: map[string]string{}}
+
+		Expect(old.withinGracePeriod(now, 15*time.Minute)).To(BeFalse())
+		Expect(recent.withinGracePeriod(now, 15*time.Minute)).To(BeTrue())
+		Expect(recent.withinGracePeriod(now, 0)).To(BeFalse())
+		Expect(unknown.withinGracePeriod(now, 15*time.Minute)).To(BeFalse())
+	})
+
+	It("should only report a handle if all of its allocations are leaked", func() {
+		attrs := map[string]string{AttributeNode: "node1", AttributeNamespace: "default", AttributePod: "pod1"}
+		leaked := []*gcAllocation{
+			{ip: "10.0.0.1", attrs: attrs, reason: GCReasonWorkloadNotFound},
+			{ip: "fd00::1", attrs: attrs, reason: GCReasonWorkloadNotFound},
+		}
+		h, ok := leakedHandle("handle1", leaked)
+		Expect(ok).To(BeTrue())
+		Expect(h).To(Equal(LeakedHandle{
+			HandleID:  "handle1",
+			IPs:       []string{"10.0.0.1", "fd00::1"},
+			Node:      "node1",
+			Namespace: "default",
+			Pod:       "pod1",
+			Reason:    GCReasonWorkloadNotFound,
+		}))
+
+		_, ok = leakedHandle("handle1", append(leaked, &gcAllocation{ip: "10.0.0.2", attrs: attrs}))
+		Expect(ok).To(BeFalse())
+	})
+})
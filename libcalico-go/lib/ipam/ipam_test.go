@@ -54,11 +54,12 @@ type ipPoolAccessor struct {
 }
 
 type pool struct {
-	cidr         string
-	blockSize    int
-	enabled      bool
-	nodeSelector string
-	allowedUses  []v3.IPPoolAllowedUse
+	cidr              string
+	blockSize         int
+	enabled           bool
+	nodeSelector      string
+	namespaceSelector string
+	allowedUses       []v3.IPPoolAllowedUse
 }
 
 func (i *ipPoolAccessor) GetEnabledPools(ipVersion int) ([]v3.IPPool, error) {
@@ -84,9 +85,10 @@ func (i *ipPoolAccessor) getPools(sorted []string, ipVersion int, caller string)
 		c := cnet.MustParseCIDR(p)
 		if (ipVersion == 0) || (c.Version() == ipVersion) {
 			pool := v3.IPPool{Spec: v3.IPPoolSpec{
-				CIDR:         p,
-				NodeSelector: i.pools[p].nodeSelector,
-				AllowedUses:  i.pools[p].allowedUses,
+				CIDR:              p,
+				NodeSelector:      i.pools[p].nodeSelector,
+				NamespaceSelector: i.pools[p].namespaceSelector,
+				AllowedUses:       i.pools[p].allowedUses,
 			}}
 			if len(pool.Spec.AllowedUses) == 0 {
 				pool.Spec.AllowedUses = []v3.IPPoolAllowedUse{v3.IPPoolAllowedUseWorkload, v3.IPPoolAllowedUseTunnel}
@@ -2350,6 +2352,84 @@ var _ = testutils.E2eDatastoreDescribe("IPAM tests", testutils.DatastoreAll, fun
 		})
 	})
 
+	Describe("IPAM ClaimBlocks", func() {
+		block1 := cnet.MustParseNetwork("10.0.0.0/26")
+		block2 := cnet.MustParseNetwork("10.0.0.64/26")
+		block3 := cnet.MustParseNetwork("10.0.0.128/26")
+
+		BeforeEach(func() {
+			bc.Clean()
+			deleteAllPools()
+			applyNode(bc, kc, "host-a", nil)
+			applyNode(bc, kc, "host-b", nil)
+			applyPool("10.0.0.0/24", true, "")
+		})
+
+		affineBlocks := func(host string) []string {
+			affs, err := bc.List(context.Background(), model.BlockAffinityListOptions{Host: host, IPVersion: 4}, "")
+			Expect(err).NotTo(HaveOccurred())
+			var blocks []string
+			for _, kvp := range affs.KVPairs {
+				blocks = append(blocks, kvp.Key.(model.BlockAffinityKey).CIDR.String())
+			}
+			return blocks
+		}
+
+		It("should claim a number of new blocks", func() {
+			_, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-b", Blocks: []cnet.IPNet{block1}})
+			Expect(err).NotTo(HaveOccurred())
+
+			blocks, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", NumBlocks: 3, IPVersion: 4})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blocks).To(HaveLen(3))
+			Expect(blocks).NotTo(ContainElement(block1))
+			Expect(affineBlocks("host-a")).To(HaveLen(3))
+		})
+
+		It("should release the claimed blocks if there are not enough free blocks", func() {
+			_, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-b", Blocks: []cnet.IPNet{block1}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", NumBlocks: 4, IPVersion: 4})
+			Expect(err).To(BeAssignableToTypeOf(noFreeBlocksError("")))
+			Expect(affineBlocks("host-a")).To(BeEmpty())
+		})
+
+		It("should claim the given blocks", func() {
+			blocks, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", Blocks: []cnet.IPNet{block1, block2}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blocks).To(Equal([]cnet.IPNet{block1, block2}))
+			Expect(affineBlocks("host-a")).To(ConsistOf(block1.String(), block2.String()))
+
+			// Claiming blocks that the host has already claimed succeeds.
+			blocks, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", Blocks: []cnet.IPNet{block2, block3}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blocks).To(Equal([]cnet.IPNet{block2, block3}))
+			Expect(affineBlocks("host-a")).To(ConsistOf(block1.String(), block2.String(), block3.String()))
+		})
+
+		It("should not claim any of the given blocks if one is claimed by another host", func() {
+			_, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", Blocks: []cnet.IPNet{block1}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-b", Blocks: []cnet.IPNet{block3, block1}})
+			Expect(err).To(BeAssignableToTypeOf(errBlockClaimConflict{}))
+			Expect(affineBlocks("host-b")).To(BeEmpty())
+		})
+
+		It("should reject invalid arguments", func() {
+			_, err := ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", Blocks: []cnet.IPNet{cnet.MustParseNetwork("10.0.0.0/25")}})
+			Expect(err).To(BeAssignableToTypeOf(invalidSizeError("")))
+			_, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", Blocks: []cnet.IPNet{cnet.MustParseNetwork("20.0.0.0/26")}})
+			Expect(err).To(HaveOccurred())
+			_, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a"})
+			Expect(err).To(HaveOccurred())
+			_, err = ic.ClaimBlocks(context.Background(), ClaimBlocksArgs{Hostname: "host-a", NumBlocks: 1, IPVersion: 6, Pools: []cnet.IPNet{cnet.MustParseNetwork("10.0.0.0/24")}})
+			Expect(err).To(HaveOccurred())
+			Expect(affineBlocks("host-a")).To(BeEmpty())
+		})
+	})
+
 	Describe("IPAM findOrClaimBlock test", func() {
 		host := "host-a"
 		pool1 := cnet.MustParseNetwork("10.0.0.0/24")
@@ -3243,7 +3323,7 @@ var _ = DescribeTable("determinePools tests IPV4",
 		}
 
 		// Call determinePools
-		pools, _, err := ic.(*ipamClient).determinePools(context.Background(), reqPools, 4, node, 32)
+		pools, _, err := ic.(*ipamClient).determinePools(context.Background(), reqPools, 4, node, nil, 32)
 
 		// Assert on any returned error.
 		if expectErr {
@@ -3312,7 +3392,7 @@ var _ = DescribeTable("determinePools tests IPV6",
 		}
 
 		// Call determinePools
-		pools, _, err := ic.(*ipamClient).determinePools(context.Background(), reqPools, 6, node, 128)
+		pools, _, err := ic.(*ipamClient).determinePools(context.Background(), reqPools, 6, node, nil, 128)
 
 		// Assert on any returned error.
 		if expectErr {
@@ -3349,6 +3429,38 @@ var _ = DescribeTable("determinePools tests IPV6",
 	Entry("pool1 disabled, pool2 mismatching node selector, pool2 requested", false, true, "", `foo != "bar"`, false, true, []string{v6Pool2CIDR}, false),
 )
 
+// Tests for the pools with a namespace selector.
+var _ = DescribeTable("determinePools tests namespace selector",
+	func(pool1NSSelector string, pool1NodeSelector string, nsLabels map[string]string, requestPool2 bool, expectation []string) {
+		ipPools.pools = map[string]pool{
+			v4Pool1CIDR: {enabled: true, namespaceSelector: pool1NSSelector, nodeSelector: pool1NodeSelector},
+			v4Pool2CIDR: {enabled: true},
+		}
+		ic := NewIPAMClient(nil, ipPools, &fakeReservations{})
+		node := libapiv3.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}}}
+
+		reqPools := []cnet.IPNet{}
+		if requestPool2 {
+			reqPools = append(reqPools, cnet.MustParseCIDR(v4Pool2CIDR))
+		}
+
+		pools, _, err := ic.(*ipamClient).determinePools(context.Background(), reqPools, 4, node, nsLabels, 32)
+		Expect(err).NotTo(HaveOccurred())
+
+		actual := []string{}
+		for _, pool := range pools {
+			actual = append(actual, pool.Spec.CIDR)
+		}
+		Expect(actual).To(Equal(expectation))
+	},
+	Entry("No namespace selector", "", "", map[string]string{"tenant": "a"}, false, []string{v4Pool1CIDR, v4Pool2CIDR}),
+	Entry("Matching namespace selector", `tenant == "a"`, "", map[string]string{"tenant": "a"}, false, []string{v4Pool1CIDR}),
+	Entry("Mismatching namespace selector", `tenant == "b"`, "", map[string]string{"tenant": "a"}, false, []string{v4Pool2CIDR}),
+	Entry("No namespace labels", `tenant == "a"`, "", nil, false, []string{v4Pool2CIDR}),
+	Entry("Matching namespace selector, mismatching node selector", `tenant == "a"`, `foo != "bar"`, map[string]string{"tenant": "a"}, false, []string{v4Pool2CIDR}),
+	Entry("Matching namespace selector, pool2 requested", `tenant == "a"`, "", map[string]string{"tenant": "a"}, true, []string{v4Pool2CIDR}),
+)
+
 // assignIPutil is a utility function to help with assigning a single IP address to a hostname passed in.
 func assignIPutil(ic Interface, assignIP net.IP, host string) {
 	if len(assignIP) != 0 {
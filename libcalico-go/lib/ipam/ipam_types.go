@@ -17,6 +17,7 @@ package ipam
 import (
 	"fmt"
 	"net"
+	"time"
 
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
@@ -86,6 +87,11 @@ type AutoAssignArgs struct {
 	// The intended use for the IP address.  Used to filter the available IP pools on their AllowedUses field.
 	// This field is required.
 	IntendedUse v3.IPPoolAllowedUse
+
+	// If specified, the labels of the namespace of the pod the addresses are for.  When no pools are
+	// requested, the pools whose namespace selector matches them are used in preference to the
+	// pools without a namespace selector.
+	NamespaceLabels map[string]string
 }
 
 // IPAMConfig contains global configuration options for Calico IPAM.
@@ -174,6 +180,32 @@ type BlockArgs struct {
 	HostReservedAttrIPv6s *HostReservedAttr
 }
 
+// ClaimBlocksArgs defines the set of arguments for claiming a batch of blocks for a host.
+// Exactly one of NumBlocks and Blocks must be specified.
+type ClaimBlocksArgs struct {
+	// If specified, the hostname of the host which will claim the blocks.
+	// If not specified, this will default to the value provided by os.Hostname.
+	Hostname string
+
+	// The number of new blocks to claim from the pools that select the host.
+	NumBlocks int
+
+	// The IP version of the blocks to claim when NumBlocks is specified, 4 or 6.
+	IPVersion int
+
+	// If specified, the previously configured pools from which to claim the
+	// blocks when NumBlocks is specified.  If not specified, this defaults to all
+	// the pools of the IP version.
+	Pools []cnet.IPNet
+
+	// The CIDRs of the blocks to claim.  Each must be exactly one block of a
+	// configured pool.
+	Blocks []cnet.IPNet
+
+	// If specified, the attributes of reserved addresses in the claimed blocks.
+	HostReservedAttr *HostReservedAttr
+}
+
 type ReleaseOptions struct {
 	// Address to release.
 	Address string
@@ -192,3 +224,80 @@ func (opts *ReleaseOptions) AsNetIP() (*cnet.IP, error) {
 	}
 	return nil, fmt.Errorf("failed to parse IP: %s", opts.Address)
 }
+
+// GCArgs defines the set of arguments for an IPAM garbage collection.
+type GCArgs struct {
+	// If true, release the leaked handles and their IP addresses, and the affinities of the
+	// orphaned blocks that are empty once the leaked addresses are released.  Otherwise the
+	// garbage collection only reports them.
+	Release bool
+
+	// The minimum age of the allocations that may be reported as leaked, so that the addresses of
+	// the pods that are still being set up are not.  Allocations without a timestamp are always
+	// eligible.
+	GracePeriod time.Duration
+}
+
+// The reasons for which the garbage collection reports a leaked handle or an orphaned block.
+const (
+	// The handle's addresses are allocated to a node that no longer exists, or the block is
+	// affine to one.
+	GCReasonNodeNotFound = "NodeNotFound"
+	// The handle's addresses are allocated to a pod with no workload endpoint.
+	GCReasonWorkloadNotFound = "WorkloadNotFound"
+	// The handle's addresses are allocated to a pod whose workload endpoints do not use them.
+	GCReasonIPNotInUse = "IPNotInUse"
+	// No block has an address allocated with the handle.
+	GCReasonNoAllocations = "NoAllocations"
+	// The block is not within any IP pool.
+	GCReasonNoPool = "NoPool"
+)
+
+// GCReport is the result of an IPAM garbage collection.
+type GCReport struct {
+	// The time the garbage collection started.
+	Timestamp time.Time `json:"timestamp"`
+
+	// The number of blocks and allocated addresses checked.
+	NumBlocks      int `json:"numBlocks"`
+	NumAllocations int `json:"numAllocations"`
+
+	// The handles whose addresses are all leaked, sorted by handle ID.
+	LeakedHandles []LeakedHandle `json:"leakedHandles"`
+
+	// The blocks that are affine to nodes that no longer exist, or not within any IP pool,
+	// sorted by CIDR.
+	OrphanedBlocks []OrphanedBlock `json:"orphanedBlocks"`
+}
+
+// LeakedHandle is a handle found by the garbage collection whose addresses are no longer used.
+type LeakedHandle struct {
+	HandleID string   `json:"handleID"`
+	IPs      []string `json:"ips,omitempty"`
+
+	// The owner of the addresses, from their allocation attributes.
+	Node      string `json:"node,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+
+	Reason string `json:"reason"`
+
+	// Released is true if the garbage collection released the handle and its addresses, Error
+	// holds the reason it failed to otherwise.
+	Released bool   `json:"released"`
+	Error    string `json:"error,omitempty"`
+}
+
+// OrphanedBlock is a block found by the garbage collection that no node should hold.
+type OrphanedBlock struct {
+	CIDR           string `json:"cidr"`
+	Affinity       string `json:"affinity,omitempty"`
+	NumAllocations int    `json:"numAllocations"`
+
+	Reason string `json:"reason"`
+
+	// Released is true if the garbage collection released the block's affinity, Error holds the
+	// reason it failed to otherwise.
+	Released bool   `json:"released"`
+	Error    string `json:"error,omitempty"`
+}
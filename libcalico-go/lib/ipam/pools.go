@@ -43,3 +43,20 @@ func SelectsNode(pool v3.IPPool, n libapiv3.Node) (bool, error) {
 	// Return whether or not the selector matches.
 	return sel.Evaluate(n.Labels), nil
 }
+
+// SelectsNamespace determines whether or not the IPPool's namespaceSelector
+// matches the labels of a namespace.  No labels never match.
+func SelectsNamespace(pool v3.IPPool, labels map[string]string) (bool, error) {
+	if labels == nil {
+		return false, nil
+	}
+	// No namespace selector means that the pool matches the namespace.
+	if len(pool.Spec.NamespaceSelector) == 0 {
+		return true, nil
+	}
+	sel, err := selector.Parse(pool.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return sel.Evaluate(labels), nil
+}
@@ -95,6 +95,69 @@ func (f *Formatter) Format(entry *log.Entry) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// structuredTimeFormat is the time format of the structured formatters: RFC 3339 with milliseconds.
+const structuredTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// LogfmtFormatter formats logs as logfmt, for log pipelines that parse the fields of the logs
+// rather than the free-form message.  The file name, line number, PID and component are
+// fields like any other.
+//
+// Example:
+//
+//	time="2017-01-05T09:17:48.238Z" level=info msg="Skipping configuration of interface
+//	because it is oper down." component=felix file=endpoint_mgr.go ifaceName=cali1234 line=434 pid=85386
+type LogfmtFormatter struct {
+	// If specified, added to the logs as the component field.
+	Component string
+}
+
+func (f *LogfmtFormatter) Format(entry *log.Entry) ([]byte, error) {
+	tf := &log.TextFormatter{
+		DisableColors:   true,
+		FullTimestamp:   true,
+		TimestampFormat: structuredTimeFormat,
+	}
+	return tf.Format(structuredEntry(entry, f.Component))
+}
+
+// JSONFormatter formats logs as JSON objects, one per line, with the same fields as the
+// LogfmtFormatter.
+type JSONFormatter struct {
+	// If specified, added to the logs as the component field.
+	Component string
+}
+
+func (f *JSONFormatter) Format(entry *log.Entry) ([]byte, error) {
+	jf := &log.JSONFormatter{
+		TimestampFormat: structuredTimeFormat,
+	}
+	return jf.Format(structuredEntry(entry, f.Component))
+}
+
+// structuredEntry returns a copy of the entry for the structured formatters, with the
+// reserved fields of the ContextHook replaced by the file and line fields.
+func structuredEntry(entry *log.Entry, component string) *log.Entry {
+	data := make(log.Fields, len(entry.Data)+2)
+	for k, v := range entry.Data {
+		switch k {
+		case fieldFileName:
+			data["file"] = v
+		case fieldLineNumber:
+			data["line"] = v
+		case FieldForceFlush:
+		default:
+			data[k] = v
+		}
+	}
+	data["pid"] = os.Getpid()
+	if component != "" {
+		data["component"] = component
+	}
+	e := *entry
+	e.Data = data
+	return &e
+}
+
 // FormatForSyslog formats logs in a way tailored for syslog.  It avoids logging information that is
 // already included in the syslog metadata such as timestamp and PID.  The log level _is_ included
 // because syslog doesn't seem to output it by default and it's very useful.
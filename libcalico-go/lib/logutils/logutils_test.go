@@ -130,6 +130,50 @@ var _ = DescribeTable("Formatter",
 		"WARNING foo.go 123: The answer is 42. a=10 b=\"foobar\" c=2017-03-15 11:22:33.123 +0000 UTC err=an error\n"),
 )
 
+var _ = DescribeTable("Structured formatters",
+	func(entry log.Entry, expectedLogfmt, expectedJSON string) {
+		out, err := (&LogfmtFormatter{Component: "felix"}).Format(&entry)
+		Expect(err).NotTo(HaveOccurred())
+		expectedLogfmt = strings.Replace(expectedLogfmt, "<PID>", fmt.Sprintf("%v", os.Getpid()), 1)
+		Expect(string(out)).To(Equal(expectedLogfmt))
+
+		out, err = (&JSONFormatter{}).Format(&entry)
+		Expect(err).NotTo(HaveOccurred())
+		expectedJSON = strings.Replace(expectedJSON, "<PID>", fmt.Sprintf("%v", os.Getpid()), 1)
+		Expect(string(out)).To(MatchJSON(expectedJSON))
+	},
+	Entry("Basic",
+		log.Entry{
+			Level: log.InfoLevel,
+			Time:  theTime(),
+			Data: log.Fields{
+				"__file__":  "foo.go",
+				"__line__":  123,
+				"__flush__": true, // Internal value, should be ignored.
+			},
+			Message: "The answer is 42.",
+		},
+		`time="2017-03-15T11:22:33.123Z" level=info msg="The answer is 42." component=felix file=foo.go line=123 pid=<PID>`+"\n",
+		`{"time":"2017-03-15T11:22:33.123Z","level":"info","msg":"The answer is 42.","file":"foo.go","line":123,"pid":<PID>}`,
+	),
+	Entry("With fields",
+		log.Entry{
+			Level: log.WarnLevel,
+			Time:  theTime(),
+			Data: log.Fields{
+				"__file__": "foo.go",
+				"__line__": 123,
+				"ipFamily": 4,
+				"service":  "default/svc:http",
+				"err":      errors.New("an error"),
+			},
+			Message: "The answer is 42.",
+		},
+		`time="2017-03-15T11:22:33.123Z" level=warning msg="The answer is 42." component=felix err="an error" file=foo.go ipFamily=4 line=123 pid=<PID> service="default/svc:http"`+"\n",
+		`{"time":"2017-03-15T11:22:33.123Z","level":"warning","msg":"The answer is 42.","err":"an error","file":"foo.go","ipFamily":4,"line":123,"pid":<PID>,"service":"default/svc:http"}`,
+	),
+)
+
 func theTime() time.Time {
 	theTime, err := time.Parse("2006-01-02 15:04:05.000", "2017-03-15 11:22:33.123")
 	if err != nil {
@@ -36,4 +36,27 @@ type ListOptions struct {
 	// as a mechanism for enumerating endpoints within a Pod (since the name construction for a
 	// Workload endpoint is hierarchically constructed).
 	Prefix bool
+
+	// A Kubernetes label selector that restricts the List or Watch to the resources with
+	// matching labels.
+	// +optional
+	LabelSelector string
+
+	// A Kubernetes field selector that restricts the List or Watch to the resources with
+	// matching fields.  All the resource types support metadata.name and metadata.namespace,
+	// and some support fields of their spec, for example spec.node for WorkloadEndpoints.
+	// +optional
+	FieldSelector string
+
+	// The maximum number of resources to List in a page.  When set, the List returns up to
+	// Limit resources, and the continue token of the next page in the metadata of the list.
+	// The datastore may return fewer resources, or none, in a page that is not the last one.
+	// Not used by Watch.
+	// +optional
+	Limit int64
+
+	// The continue token of the next page of a List, from the metadata of the previous page.
+	// The other options must be the same as for the first page.  Not used by Watch.
+	// +optional
+	Continue string
 }
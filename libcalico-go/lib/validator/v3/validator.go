@@ -109,6 +109,8 @@ var (
 	protocolPortsMsg        = "rules that specify ports must set protocol to TCP or UDP or SCTP"
 	protocolIcmpMsg         = "rules that specify ICMP fields must set protocol to ICMP"
 	protocolAndHTTPMsg      = "rules that specify HTTP fields must set protocol to TCP or empty"
+	logActionMsg            = "rules that specify log options must set action to Log"
+	logSyslogFacilityMsg    = "rules that specify a syslog facility must set the log target to Syslog"
 	globalSelectorEntRule   = fmt.Sprintf("%v can only be used in an EntityRule namespaceSelector", globalSelector)
 	globalSelectorOnly      = fmt.Sprintf("%v cannot be combined with other selectors", globalSelector)
 
@@ -117,6 +119,8 @@ var (
 	filterActionRegex  = regexp.MustCompile("^(Accept|Reject)$")
 	matchOperatorRegex = regexp.MustCompile("^(Equal|In|NotEqual|NotIn)$")
 
+	logRateLimitRegex = regexp.MustCompile("^[1-9][0-9]*/(second|minute|hour|day)$")
+
 	ipv4LinkLocalNet = net.IPNet{
 		IP:   net.ParseIP("169.254.0.0"),
 		Mask: net.CIDRMask(16, 32),
@@ -199,7 +203,10 @@ func init() {
 	registerFieldValidator("routeSource", validateRouteSource)
 	registerFieldValidator("wireguardPublicKey", validateWireguardPublicKey)
 	registerFieldValidator("IP:port", validateIPPort)
+	registerFieldValidator("hostname:port", validateHostnamePort)
+	registerFieldValidator("domain", validateDomain)
 	registerFieldValidator("reachableBy", validateReachableByField)
+	registerFieldValidator("logRateLimit", RegexValidator("LogRateLimit", logRateLimitRegex))
 
 	// Register filter action and match operator validators (used in BGPFilter)
 	registerFieldValidator("filterAction", RegexValidator("FilterAction", filterActionRegex))
@@ -223,6 +230,7 @@ func init() {
 	registerFieldValidator("cidrs", validateCIDRs)
 
 	registerStructValidator(validate, validateProtocol, numorstring.Protocol{})
+	registerStructValidator(validate, validateDSCP, numorstring.DSCP{})
 	registerStructValidator(validate, validateProtoPort, api.ProtoPort{})
 	registerStructValidator(validate, validatePort, numorstring.Port{})
 	registerStructValidator(validate, validateEndpointPort, api.EndpointPort{})
@@ -235,6 +243,7 @@ func init() {
 	registerStructValidator(validate, validateObjectMeta, metav1.ObjectMeta{})
 	registerStructValidator(validate, validateHTTPRule, api.HTTPMatch{})
 	registerStructValidator(validate, validateFelixConfigSpec, api.FelixConfigurationSpec{})
+	registerStructValidator(validate, validateFelixConfiguration, api.FelixConfiguration{})
 	registerStructValidator(validate, validateWorkloadEndpointSpec, libapi.WorkloadEndpointSpec{})
 	registerStructValidator(validate, validateHostEndpointSpec, api.HostEndpointSpec{})
 	registerStructValidator(validate, validateRule, api.Rule{})
@@ -244,6 +253,8 @@ func init() {
 	registerStructValidator(validate, validateBGPFilterRuleV6, api.BGPFilterRuleV6{})
 	registerStructValidator(validate, validateNetworkPolicy, api.NetworkPolicy{})
 	registerStructValidator(validate, validateGlobalNetworkPolicy, api.GlobalNetworkPolicy{})
+	registerStructValidator(validate, validateStagedNetworkPolicy, api.StagedNetworkPolicy{})
+	registerStructValidator(validate, validateStagedGlobalNetworkPolicy, api.StagedGlobalNetworkPolicy{})
 	registerStructValidator(validate, validateGlobalNetworkSet, api.GlobalNetworkSet{})
 	registerStructValidator(validate, validateNetworkSet, api.NetworkSet{})
 	registerStructValidator(validate, validateRuleMetadata, api.RuleMetadata{})
@@ -252,6 +263,7 @@ func init() {
 	registerStructValidator(validate, validateBGPConfigurationSpec, api.BGPConfigurationSpec{})
 	registerStructValidator(validate, validateBlockAffinitySpec, libapi.BlockAffinitySpec{})
 	registerStructValidator(validate, validateHealthTimeoutOverride, api.HealthTimeoutOverride{})
+	registerStructValidator(validate, validateAutoHostEndpointConfig, api.AutoHostEndpointConfig{})
 }
 
 // reason returns the provided error reason prefixed with an identifier that
@@ -581,6 +593,17 @@ func validateProtocol(structLevel validator.StructLevel) {
 	}
 }
 
+func validateDSCP(structLevel validator.StructLevel) {
+	d := structLevel.Current().Interface().(numorstring.DSCP)
+	log.Debugf("Validate DSCP: %v %s %d", d.Type, d.StrVal, d.NumVal)
+
+	// The DSCP field may be an integer 0-63, or one of the standard names.
+	if _, err := d.NumValue(); err != nil {
+		structLevel.ReportError(reflect.ValueOf(d.String()),
+			"DSCP", "", reason(err.Error()), "")
+	}
+}
+
 // validateIPv4Network validates the field is a valid (strictly masked) IPv4 network.
 // An IP address is valid, and assumed to be fully masked (i.e /32)
 func validateIPv4Network(fl validator.FieldLevel) bool {
@@ -745,6 +768,30 @@ func validateIPPort(fl validator.FieldLevel) bool {
 	return ok
 }
 
+// validateHostnamePort validates a DNS name with an optional port, given as <name> or <name>:<port>
+func validateHostnamePort(fl validator.FieldLevel) bool {
+	host := fl.Field().String()
+	if h, portStr, err := net.SplitHostPort(host); err == nil {
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port < 1 {
+			log.Debugf("PeerHostname value has invalid port.")
+			return false
+		}
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		log.Debugf("PeerHostname value is an IP address, PeerIP should be used instead.")
+		return false
+	}
+	return len(k8svalidation.IsDNS1123Subdomain(host)) == 0
+}
+
+// validateDomain validates a domain name of a policy rule, which may have a leading "*." wildcard.
+func validateDomain(fl validator.FieldLevel) bool {
+	domain := strings.TrimPrefix(fl.Field().String(), "*.")
+	return len(k8svalidation.IsDNS1123Subdomain(domain)) == 0
+}
+
 // processIPPort processes the IP and Port given in either <IPv4>:<port> or [<IPv6>]:<port> or <IP> format
 // and return the IP, port and a bool if the format is as expected
 func processIPPort(ipPort string) (string, int, bool) {
@@ -959,6 +1006,17 @@ func validateFelixConfigSpec(structLevel validator.StructLevel) {
 	}
 }
 
+func validateFelixConfiguration(structLevel validator.StructLevel) {
+	fc := structLevel.Current().Interface().(api.FelixConfiguration)
+
+	// The global and per-node resources apply to the nodes that their name selects, only the
+	// other resources are overrides for the nodes that match a node selector.
+	if fc.Spec.NodeSelector != "" && (fc.Name == "default" || strings.HasPrefix(fc.Name, "node.")) {
+		structLevel.ReportError(reflect.ValueOf(fc.Spec.NodeSelector),
+			"Spec.NodeSelector", "", reason("cannot be set on the default and node.<nodename> resources"), "")
+	}
+}
+
 func validateWorkloadEndpointSpec(structLevel validator.StructLevel) {
 	w := structLevel.Current().Interface().(libapi.WorkloadEndpointSpec)
 
@@ -1049,6 +1107,11 @@ func validateHostEndpointSpec(structLevel validator.StructLevel) {
 		structLevel.ReportError(reflect.ValueOf(h.Node),
 			"InterfaceName", "", reason("no node has been specified"), "")
 	}
+	// The zone is added to the labels of the host endpoint.
+	if h.Zone != "" && len(k8svalidation.IsValidLabelValue(h.Zone)) != 0 {
+		structLevel.ReportError(reflect.ValueOf(h.Zone),
+			"Zone", "", reason("zone must be a valid label value"), "")
+	}
 }
 
 func validateIPPoolSpec(structLevel validator.StructLevel) {
@@ -1133,15 +1196,28 @@ func validateIPPoolSpec(structLevel validator.StructLevel) {
 	}
 
 	// Allowed use must be one of the enums.
+	egressSNAT := false
 	for _, a := range pool.AllowedUses {
 		switch a {
 		case api.IPPoolAllowedUseWorkload, api.IPPoolAllowedUseTunnel:
 			continue
+		case api.IPPoolAllowedUseEgressSNAT:
+			egressSNAT = true
 		default:
 			structLevel.ReportError(reflect.ValueOf(pool.AllowedUses),
 				"IPpool.AllowedUses", "", reason("unknown use: "+string(a)), "")
 		}
 	}
+
+	// Egress SNAT pools are dedicated to that use.
+	if egressSNAT && len(pool.AllowedUses) > 1 {
+		structLevel.ReportError(reflect.ValueOf(pool.AllowedUses),
+			"IPpool.AllowedUses", "", reason("EgressSNAT cannot be combined with other uses"), "")
+	}
+	if pool.EgressSNATSelector != "" && !egressSNAT {
+		structLevel.ReportError(reflect.ValueOf(pool.EgressSNATSelector),
+			"IPpool.EgressSNATSelector", "", reason("egressSNATSelector requires allowedUses [EgressSNAT]"), "")
+	}
 }
 
 func vxLanModeEnabled(mode api.VXLANMode) bool {
@@ -1187,6 +1263,17 @@ func validateRule(structLevel validator.StructLevel) {
 		}
 	}
 
+	// Check that the log options are only set on log rules.
+	if rule.Log != nil {
+		if rule.Action != api.Log {
+			structLevel.ReportError(reflect.ValueOf(rule.Log), "Log", "", reason(logActionMsg), "")
+		}
+		if rule.Log.SyslogFacility != "" && rule.Log.Target != api.LogTargetSyslog {
+			structLevel.ReportError(reflect.ValueOf(rule.Log.SyslogFacility), "Log.SyslogFacility", "",
+				reason(logSyslogFacilityMsg), "")
+		}
+	}
+
 	// Check that HTTP must not use non-TCP protocols
 	if rule.HTTP != nil && rule.Protocol != nil {
 		tcp := numorstring.ProtocolFromString("TCP")
@@ -1200,6 +1287,20 @@ func validateRule(structLevel validator.StructLevel) {
 	if rule.ICMP != nil && (rule.Protocol == nil || (*rule.Protocol != icmp && *rule.Protocol != icmpv6)) {
 		structLevel.ReportError(reflect.ValueOf(rule.ICMP), "ICMP", "", reason(protocolIcmpMsg), "")
 	}
+	if len(rule.ICMPs) > 0 {
+		if rule.Protocol == nil || (*rule.Protocol != icmp && *rule.Protocol != icmpv6) {
+			structLevel.ReportError(reflect.ValueOf(rule.ICMPs), "ICMPs", "", reason(protocolIcmpMsg), "")
+		}
+		if rule.ICMP != nil {
+			structLevel.ReportError(reflect.ValueOf(rule.ICMPs), "ICMPs", "", reason("cannot be combined with ICMP"), "")
+		}
+		for _, fields := range rule.ICMPs {
+			if fields.Type == nil {
+				structLevel.ReportError(reflect.ValueOf(rule.ICMPs), "ICMPs", "", reason("each entry must specify an ICMP type"), "")
+				break
+			}
+		}
+	}
 
 	// Check that the IPVersion of the protocol matches the IPVersion of the ICMP protocol.
 	if (rule.Protocol != nil && *rule.Protocol == icmp) || (rule.NotProtocol != nil && *rule.NotProtocol == icmp) {
@@ -1240,6 +1341,17 @@ func validateRule(structLevel validator.StructLevel) {
 		seenV6 = seenV6 || v6
 	}
 
+	// Domains are only allowed in the destination of Allow rules.  An Allow rule fails closed
+	// if Felix has not learned the IPs of the domains yet, a Deny rule would fail open.
+	if len(rule.Source.Domains) != 0 {
+		structLevel.ReportError(reflect.ValueOf(rule.Source.Domains),
+			"Source.Domains", "", reason("not allowed in rule source"), "")
+	}
+	if len(rule.Destination.Domains) != 0 && rule.Action != api.Allow {
+		structLevel.ReportError(reflect.ValueOf(rule.Destination.Domains),
+			"Destination.Domains", "", reason("only valid for Allow rules"), "")
+	}
+
 	scanNets(rule.Source.Nets, "Source.Nets")
 	scanNets(rule.Source.NotNets, "Source.NotNets")
 	scanNets(rule.Destination.Nets, "Destination.Nets")
@@ -1309,6 +1421,23 @@ func validateEntityRule(structLevel validator.StructLevel) {
 				"Services field", "", reason("cannot specify Nets/NotNets and Services on the same rule"), "")
 		}
 	}
+
+	if len(rule.Domains) != 0 {
+		// The rule matches the IPs of the domains, which can't be combined with other
+		// destination matches.
+		if rule.Selector != "" || rule.NotSelector != "" || rule.NamespaceSelector != "" {
+			structLevel.ReportError(reflect.ValueOf(rule.Domains),
+				"Domains field", "", reason("cannot specify selectors and Domains on the same rule"), "")
+		}
+		if len(rule.Nets) != 0 || len(rule.NotNets) != 0 {
+			structLevel.ReportError(reflect.ValueOf(rule.Domains),
+				"Domains field", "", reason("cannot specify Nets/NotNets and Domains on the same rule"), "")
+		}
+		if rule.Services != nil || rule.ServiceAccounts != nil {
+			structLevel.ReportError(reflect.ValueOf(rule.Domains),
+				"Domains field", "", reason("cannot specify Services/ServiceAccounts and Domains on the same rule"), "")
+		}
+	}
 }
 
 func validateIPAMConfigSpec(structLevel validator.StructLevel) {
@@ -1342,6 +1471,10 @@ func validateBGPPeerSpec(structLevel validator.StructLevel) {
 		structLevel.ReportError(reflect.ValueOf(ps.PeerIP), "PeerIP", "",
 			reason("PeerIP field must be empty when PeerSelector is specified"), "")
 	}
+	if ps.PeerHostname != "" && (ps.PeerIP != "" || ps.PeerSelector != "") {
+		structLevel.ReportError(reflect.ValueOf(ps.PeerHostname), "PeerHostname", "",
+			reason("PeerHostname field must be empty when PeerIP or PeerSelector is specified"), "")
+	}
 	if uint32(ps.ASNumber) != 0 && ps.PeerSelector != "" {
 		structLevel.ReportError(reflect.ValueOf(ps.ASNumber), "ASNumber", "",
 			reason("ASNumber field must be empty when PeerSelector is specified"), "")
@@ -1497,7 +1630,17 @@ func validateObjectMeta(structLevel validator.StructLevel) {
 }
 
 func validateNetworkPolicy(structLevel validator.StructLevel) {
-	np := structLevel.Current().Interface().(api.NetworkPolicy)
+	validateNetworkPolicyFields(structLevel, structLevel.Current().Interface().(api.NetworkPolicy))
+}
+
+// validateStagedNetworkPolicy applies the NetworkPolicy validation to a staged policy, so
+// that a staged policy can always be enforced unchanged.
+func validateStagedNetworkPolicy(structLevel validator.StructLevel) {
+	snp := structLevel.Current().Interface().(api.StagedNetworkPolicy)
+	validateNetworkPolicyFields(structLevel, api.NetworkPolicy{ObjectMeta: snp.ObjectMeta, Spec: snp.Spec})
+}
+
+func validateNetworkPolicyFields(structLevel validator.StructLevel, np api.NetworkPolicy) {
 	spec := np.Spec
 
 	// Check (and disallow) any repeats in Types field.
@@ -1561,6 +1704,12 @@ func validateNetworkPolicy(structLevel validator.StructLevel) {
 				reason("not allowed in ingress rule destination"), "",
 			)
 		}
+		if len(r.Destination.Domains) != 0 {
+			structLevel.ReportError(
+				reflect.ValueOf(r.Destination.Domains), "Domains", "",
+				reason("not allowed in ingress rules"), "",
+			)
+		}
 	}
 
 	// Check that the selector doesn't have the global() selector which is only
@@ -1619,7 +1768,17 @@ func validateGlobalNetworkSet(structLevel validator.StructLevel) {
 }
 
 func validateGlobalNetworkPolicy(structLevel validator.StructLevel) {
-	gnp := structLevel.Current().Interface().(api.GlobalNetworkPolicy)
+	validateGlobalNetworkPolicyFields(structLevel, structLevel.Current().Interface().(api.GlobalNetworkPolicy))
+}
+
+// validateStagedGlobalNetworkPolicy applies the GlobalNetworkPolicy validation to a staged
+// policy.
+func validateStagedGlobalNetworkPolicy(structLevel validator.StructLevel) {
+	sgnp := structLevel.Current().Interface().(api.StagedGlobalNetworkPolicy)
+	validateGlobalNetworkPolicyFields(structLevel, api.GlobalNetworkPolicy{ObjectMeta: sgnp.ObjectMeta, Spec: sgnp.Spec})
+}
+
+func validateGlobalNetworkPolicyFields(structLevel validator.StructLevel, gnp api.GlobalNetworkPolicy) {
 	spec := gnp.Spec
 
 	// Check the name is within the max length.
@@ -1707,6 +1866,12 @@ func validateGlobalNetworkPolicy(structLevel validator.StructLevel) {
 				reason("not allowed in ingress rule destination"), "",
 			)
 		}
+		if len(r.Destination.Domains) != 0 {
+			structLevel.ReportError(
+				reflect.ValueOf(r.Destination.Domains), "Domains", "",
+				reason("not allowed in ingress rules"), "",
+			)
+		}
 	}
 
 	// If a ServiceSelector is specified by name, we also need a namespace. At a global scope,
@@ -1944,6 +2109,19 @@ func validateHealthTimeoutOverride(structLevel validator.StructLevel) {
 	}
 }
 
+func validateAutoHostEndpointConfig(structLevel validator.StructLevel) {
+	c := structLevel.Current().Interface().(api.AutoHostEndpointConfig)
+	names := map[string]bool{}
+	for _, t := range c.Templates {
+		// The host endpoints of two templates with the same name would clash.
+		if names[t.GenerateName] {
+			structLevel.ReportError(reflect.ValueOf(t.GenerateName), "AutoHostEndpointConfig.Templates", "",
+				reason("template generateName must be unique: "+t.GenerateName), "")
+		}
+		names[t.GenerateName] = true
+	}
+}
+
 func isCommunityDefined(community string, communityKVPairs []api.Community) bool {
 	for _, val := range communityKVPairs {
 		if val.Name == community {
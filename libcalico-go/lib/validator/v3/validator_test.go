@@ -99,6 +99,9 @@ func init() {
 	bpfConnectTimeLBEnabled = api.BPFConnectTimeLBEnabled
 	bpfConnectTimeLBDisabled = api.BPFConnectTimeLBDisabled
 	bpfConnectTimeLBBadVal = api.BPFConnectTimeLBType("badVal")
+	bpfMapShrinkRefuse := api.BPFMapShrinkPolicyRefuse
+	bpfMapShrinkTruncate := api.BPFMapShrinkPolicyTruncate
+	bpfMapShrinkBadVal := api.BPFMapShrinkPolicyType("badVal")
 
 	iptablesBackendLegacy := api.IptablesBackend(api.IptablesBackendLegacy)
 	iptablesBackendNFTables := api.IptablesBackend(api.IptablesBackendNFTables)
@@ -163,6 +166,75 @@ func init() {
 		Entry("should reject unknown action", api.Rule{Action: "allowfoo"}, false),
 		Entry("should reject rule with no action", api.Rule{}, false),
 
+		// (API) Rule log options.
+		Entry("should accept log options on a log rule", api.Rule{
+			Action: "Log",
+			Log: &api.RuleLog{
+				Target:         api.LogTargetSyslog,
+				SyslogFacility: "local3",
+				RateLimit:      "10/minute",
+				RateLimitBurst: &V4,
+			},
+		}, true),
+		Entry("should reject log options on an allow rule", api.Rule{
+			Action: "Allow",
+			Log:    &api.RuleLog{Target: api.LogTargetJournal},
+		}, false),
+		Entry("should reject an unknown log target", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{Target: "Printer"},
+		}, false),
+		Entry("should reject a syslog facility without the Syslog target", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{Target: api.LogTargetFile, SyslogFacility: "local0"},
+		}, false),
+		Entry("should reject an unknown syslog facility", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{Target: api.LogTargetSyslog, SyslogFacility: "local8"},
+		}, false),
+		Entry("should reject a malformed log rate limit", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{RateLimit: "10/fortnight"},
+		}, false),
+		Entry("should reject a zero log rate limit", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{RateLimit: "0/second"},
+		}, false),
+		Entry("should reject a zero log rate limit burst", api.Rule{
+			Action: "Log",
+			Log:    &api.RuleLog{RateLimit: "1/second", RateLimitBurst: &V0},
+		}, false),
+
+		// (API) Rule domains.
+		Entry("should accept domains in an allow rule destination", api.Rule{
+			Action:      "Allow",
+			Destination: api.EntityRule{Domains: []string{"api.github.com", "*.github.com"}},
+		}, true),
+		Entry("should reject domains in a deny rule", api.Rule{
+			Action:      "Deny",
+			Destination: api.EntityRule{Domains: []string{"api.github.com"}},
+		}, false),
+		Entry("should reject domains in a rule source", api.Rule{
+			Action: "Allow",
+			Source: api.EntityRule{Domains: []string{"api.github.com"}},
+		}, false),
+		Entry("should reject an invalid domain", api.Rule{
+			Action:      "Allow",
+			Destination: api.EntityRule{Domains: []string{"api_github.com"}},
+		}, false),
+		Entry("should reject a wildcard that isn't a leading label", api.Rule{
+			Action:      "Allow",
+			Destination: api.EntityRule{Domains: []string{"api.*.com"}},
+		}, false),
+		Entry("should reject domains with nets", api.Rule{
+			Action:      "Allow",
+			Destination: api.EntityRule{Domains: []string{"api.github.com"}, Nets: []string{netv4_1}},
+		}, false),
+		Entry("should reject domains with a selector", api.Rule{
+			Action:      "Allow",
+			Destination: api.EntityRule{Domains: []string{"api.github.com"}, Selector: "has(foo)"},
+		}, false),
+
 		// (API model) EndpointPorts.
 		Entry("should accept EndpointPort with tcp protocol", libapiv3.WorkloadEndpointPort{
 			Name:     "a-valid-port",
@@ -357,6 +429,22 @@ func init() {
 			},
 			true,
 		),
+		Entry("should accept HostEndpointSpec with a zone",
+			api.HostEndpointSpec{
+				InterfaceName: "eth0",
+				Node:          "node01",
+				Zone:          "dmz",
+			},
+			true,
+		),
+		Entry("should reject HostEndpointSpec with a zone that is not a label value",
+			api.HostEndpointSpec{
+				InterfaceName: "eth0",
+				Node:          "node01",
+				Zone:          "dmz zone",
+			},
+			false,
+		),
 
 		Entry("should accept GlobalNetworkSetSpec with CIDRs and IPs",
 			api.GlobalNetworkSetSpec{
@@ -687,6 +775,22 @@ func init() {
 				Spec: api.IPPoolSpec{CIDR: netv4_3, NodeSelector: "this is not valid selector syntax"},
 			}, false,
 		),
+		Entry("should allow a valid namespaceSelector",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "pool.name",
+				},
+				Spec: api.IPPoolSpec{CIDR: netv4_3, NamespaceSelector: `tenant == "a"`},
+			}, true,
+		),
+		Entry("should disallow a invalid namespaceSelector",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "pool.name",
+				},
+				Spec: api.IPPoolSpec{CIDR: netv4_3, NamespaceSelector: "this is not valid selector syntax"},
+			}, false,
+		),
 
 		// (API) Interface.
 		Entry("should accept a valid interface", libapiv3.WorkloadEndpointSpec{InterfaceName: "Valid_Iface.0-9"}, true),
@@ -753,6 +857,13 @@ func init() {
 		Entry("should accept a valid BPFExternalServiceMode value 'Tunnel'", api.FelixConfigurationSpec{BPFExternalServiceMode: "Tunnel"}, true),
 		Entry("should accept a valid BPFExternalServiceMode value 'DSR'", api.FelixConfigurationSpec{BPFExternalServiceMode: "DSR"}, true),
 
+		Entry("should accept BPFMapShrinkPolicy 'Refuse'",
+			api.FelixConfigurationSpec{BPFMapShrinkPolicy: &bpfMapShrinkRefuse}, true),
+		Entry("should accept BPFMapShrinkPolicy 'Truncate'",
+			api.FelixConfigurationSpec{BPFMapShrinkPolicy: &bpfMapShrinkTruncate}, true),
+		Entry("should reject an invalid BPFMapShrinkPolicy value 'badVal'",
+			api.FelixConfigurationSpec{BPFMapShrinkPolicy: &bpfMapShrinkBadVal}, false),
+
 		Entry("should reject a negative BPFExtToServiceConnmark value", api.FelixConfigurationSpec{BPFExtToServiceConnmark: &Vneg1}, false),
 		Entry("should reject a gte 32bit BPFExtToServiceConnmark value", api.FelixConfigurationSpec{BPFExtToServiceConnmark: &V100000000}, false),
 		Entry("should accept a zero BPFExtToServiceConnmark value", api.FelixConfigurationSpec{BPFExtToServiceConnmark: &V0}, true),
@@ -1047,6 +1158,43 @@ func init() {
 					},
 				},
 			}, false),
+		Entry("should accept egress SNAT IP pool with a selector",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR:               netv4_4,
+					AllowedUses:        []api.IPPoolAllowedUse{api.IPPoolAllowedUseEgressSNAT},
+					EgressSNATSelector: "projectcalico.org/namespace == 'tenant-a'",
+				},
+			}, true),
+		Entry("should reject egress SNAT IP pool used for workloads",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR: netv4_4,
+					AllowedUses: []api.IPPoolAllowedUse{
+						api.IPPoolAllowedUseEgressSNAT,
+						api.IPPoolAllowedUseWorkload,
+					},
+				},
+			}, false),
+		Entry("should reject egress SNAT selector on a workload IP pool",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR:               netv4_4,
+					EgressSNATSelector: "all()",
+				},
+			}, false),
+		Entry("should reject egress SNAT IP pool with an invalid selector",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR:               netv4_4,
+					AllowedUses:        []api.IPPoolAllowedUse{api.IPPoolAllowedUseEgressSNAT},
+					EgressSNATSelector: "tenant ==",
+				},
+			}, false),
 
 		// (API) IPReservation
 		Entry("should accept IPReservation with an IP",
@@ -1135,6 +1283,27 @@ func init() {
 				Action:   "Allow",
 				Protocol: protocolFromString("SCTP"),
 			}, true),
+		Entry("should accept Rule with DSCP as a number",
+			api.Rule{
+				Action: "Allow",
+				DSCP:   dscpFromInt(63),
+			}, true),
+		Entry("should accept Rule with DSCP and NotDSCP as names",
+			api.Rule{
+				Action:  "Allow",
+				DSCP:    dscpFromString("af41"),
+				NotDSCP: dscpFromString("EF"),
+			}, true),
+		Entry("should reject Rule with DSCP out of range",
+			api.Rule{
+				Action: "Allow",
+				DSCP:   dscpFromInt(64),
+			}, false),
+		Entry("should reject Rule with unknown NotDSCP name",
+			api.Rule{
+				Action:  "Allow",
+				NotDSCP: dscpFromString("AF99"),
+			}, false),
 		Entry("should accept Rule with source ports and protocol type 6",
 			api.Rule{
 				Action:   "Allow",
@@ -1275,6 +1444,39 @@ func init() {
 					Type: &V0,
 				},
 			}, true),
+		Entry("should accept Rule with multiple icmpv6 types and codes",
+			api.Rule{
+				Action:   "Allow",
+				Protocol: protocolFromString("ICMPv6"),
+				ICMPs: []api.ICMPFields{
+					{Type: &V128},
+					{Type: &V0, Code: &V0},
+				},
+			}, true),
+		Entry("should reject Rule with multiple icmp types and no protocol",
+			api.Rule{
+				Action: "Allow",
+				ICMPs:  []api.ICMPFields{{Type: &V128}},
+			}, false),
+		Entry("should reject Rule with both icmp and multiple icmp types",
+			api.Rule{
+				Action:   "Allow",
+				Protocol: protocolFromString("ICMP"),
+				ICMP:     &api.ICMPFields{Type: &V0},
+				ICMPs:    []api.ICMPFields{{Type: &V128}},
+			}, false),
+		Entry("should reject Rule with an icmp type entry without type",
+			api.Rule{
+				Action:   "Allow",
+				Protocol: protocolFromString("ICMP"),
+				ICMPs:    []api.ICMPFields{{Type: &V128}, {}},
+			}, false),
+		Entry("should reject Rule with an invalid icmp type entry",
+			api.Rule{
+				Action:   "Allow",
+				Protocol: protocolFromString("ICMP"),
+				ICMPs:    []api.ICMPFields{{Type: &V255}},
+			}, false),
 		Entry("should reject Rule with mismatched ipversion for icmp",
 			api.Rule{
 				Action:    "Allow",
@@ -1798,6 +2000,19 @@ func init() {
 			Node:         "my-node",
 			NodeSelector: "has(mylabel)",
 		}, false),
+		Entry("should accept BGPPeerSpec with PeerHostname", api.BGPPeerSpec{PeerHostname: "tor-1.example.com"}, true),
+		Entry("should accept BGPPeerSpec with PeerHostname and port", api.BGPPeerSpec{PeerHostname: "tor-1.example.com:1179"}, true),
+		Entry("should reject BGPPeerSpec with PeerHostname and bad port", api.BGPPeerSpec{PeerHostname: "tor-1.example.com:0"}, false),
+		Entry("should reject BGPPeerSpec with invalid PeerHostname", api.BGPPeerSpec{PeerHostname: "tor_1.example.com"}, false),
+		Entry("should reject BGPPeerSpec with an IP as PeerHostname", api.BGPPeerSpec{PeerHostname: ipv4_1}, false),
+		Entry("should reject BGPPeerSpec with both PeerHostname and PeerIP", api.BGPPeerSpec{
+			PeerHostname: "tor-1.example.com",
+			PeerIP:       ipv4_1,
+		}, false),
+		Entry("should reject BGPPeerSpec with both PeerHostname and PeerSelector", api.BGPPeerSpec{
+			PeerHostname: "tor-1.example.com",
+			PeerSelector: "has(mylabel)",
+		}, false),
 		Entry("should reject BGPPeerSpec with both PeerIP and PeerSelector", api.BGPPeerSpec{
 			PeerIP:       ipv4_1,
 			PeerSelector: "has(mylabel)",
@@ -1924,6 +2139,9 @@ func init() {
 		Entry("should accept node with IPv6 BGP", libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{IPv6Address: netv6_1}}, true),
 		Entry("should accept node with tunnel IP in BGP", libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{IPv4IPIPTunnelAddr: "10.0.0.1"}}, true),
 		Entry("should accept node with no BGP", libapiv3.NodeSpec{}, true),
+		Entry("should accept node with interfaces", libapiv3.NodeSpec{Interfaces: []libapiv3.NodeInterface{{Name: "eth0", Addresses: []string{"10.0.0.1", "fd00::1"}}}}, true),
+		Entry("should reject node with an invalid interface name", libapiv3.NodeSpec{Interfaces: []libapiv3.NodeInterface{{Name: "eth0@if3"}}}, false),
+		Entry("should reject node with an invalid interface address", libapiv3.NodeSpec{Interfaces: []libapiv3.NodeInterface{{Name: "eth0", Addresses: []string{"10.0.0.0/8"}}}}, false),
 		Entry("should reject node with an empty BGP", libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{}}, false),
 		Entry("should reject node with IPv6 address in IPv4 field", libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{IPv4Address: netv6_1}}, false),
 		Entry("should reject node with IPv4 address in IPv6 field", libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{IPv6Address: netv4_1}}, false),
@@ -1971,6 +2189,48 @@ func init() {
 		Entry("should reject an invalid BPFHostNetworkedNATWithoutCTLB value 'BadVal'", api.FelixConfigurationSpec{BPFHostNetworkedNATWithoutCTLB: &bpfHostNetworkedNatBadVal}, false),
 		Entry("should reject an invalid BPFConnectTimeLoadBalancing value 'BadVal'", api.FelixConfigurationSpec{BPFConnectTimeLoadBalancing: &bpfConnectTimeLBBadVal}, false),
 
+		// Staged policies are validated like the policies they stage.
+		Entry("allow valid staged global policy name", &api.StagedGlobalNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "thing"}}, true),
+		Entry("disallow staged global policy name with mixed case characters", &api.StagedGlobalNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "tHiNg"}}, false),
+		Entry("should reject StagedGlobalNetworkPolicy with both PreDNAT and DoNotTrack",
+			&api.StagedGlobalNetworkPolicy{
+				ObjectMeta: v1.ObjectMeta{Name: "thing"},
+				Spec: api.GlobalNetworkPolicySpec{
+					PreDNAT:        true,
+					DoNotTrack:     true,
+					ApplyOnForward: true,
+				},
+			}, false,
+		),
+		Entry("allow valid staged policy name", &api.StagedNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "thing", Namespace: "default"}}, true),
+		Entry("disallow staged policy name with invalid character", &api.StagedNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "t~!s.h.i.ng", Namespace: "default"}}, false),
+
+		// FelixConfiguration validation.
+		Entry("should accept a node selector on an override FelixConfiguration",
+			&api.FelixConfiguration{
+				ObjectMeta: v1.ObjectMeta{Name: "gpu-nodes"},
+				Spec:       api.FelixConfigurationSpec{NodeSelector: "pool == 'gpu'"},
+			}, true,
+		),
+		Entry("should reject an invalid node selector on a FelixConfiguration",
+			&api.FelixConfiguration{
+				ObjectMeta: v1.ObjectMeta{Name: "gpu-nodes"},
+				Spec:       api.FelixConfigurationSpec{NodeSelector: "pool == "},
+			}, false,
+		),
+		Entry("should reject a node selector on the default FelixConfiguration",
+			&api.FelixConfiguration{
+				ObjectMeta: v1.ObjectMeta{Name: "default"},
+				Spec:       api.FelixConfigurationSpec{NodeSelector: "all()"},
+			}, false,
+		),
+		Entry("should reject a node selector on a per-node FelixConfiguration",
+			&api.FelixConfiguration{
+				ObjectMeta: v1.ObjectMeta{Name: "node.node1"},
+				Spec:       api.FelixConfigurationSpec{NodeSelector: "all()"},
+			}, false,
+		),
+
 		// GlobalNetworkPolicy validation.
 		Entry("disallow name with invalid character", &api.GlobalNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "t~!s.h.i.ng"}}, false),
 		Entry("disallow name with mixed case characters", &api.GlobalNetworkPolicy{ObjectMeta: v1.ObjectMeta{Name: "tHiNg"}}, false),
@@ -2536,6 +2796,19 @@ func init() {
 				},
 			}, true,
 		),
+		Entry("disallow domains in an ingress rule",
+			&api.NetworkPolicy{
+				ObjectMeta: v1.ObjectMeta{Name: "thing"},
+				Spec: api.NetworkPolicySpec{
+					Ingress: []api.Rule{
+						{
+							Action:      "Allow",
+							Destination: api.EntityRule{Domains: []string{"api.github.com"}},
+						},
+					},
+				},
+			}, false,
+		),
 		Entry("disallow a Service match in an egress rule source",
 			&api.NetworkPolicy{
 				ObjectMeta: v1.ObjectMeta{Name: "thing"},
@@ -3020,6 +3293,37 @@ func init() {
 		Entry("should accept empty host endpoint auto create",
 			api.NodeControllerConfig{HostEndpoint: &api.AutoHostEndpointConfig{}}, true,
 		),
+		Entry("should accept host endpoint templates",
+			api.AutoHostEndpointConfig{
+				AutoCreate:                "Enabled",
+				CreateDefaultHostEndpoint: "Disabled",
+				Templates: []api.AutoHostEndpointTemplate{{
+					GenerateName:     "data",
+					InterfacePattern: "^(eth|ens)[0-9]+$",
+					InterfaceCIDRs:   []string{"10.0.0.0/8"},
+					NodeSelector:     "has(storage)",
+					Labels:           map[string]string{"network": "data"},
+				}},
+			}, true,
+		),
+		Entry("should not accept a host endpoint template without a name",
+			api.AutoHostEndpointConfig{Templates: []api.AutoHostEndpointTemplate{{InterfacePattern: "eth0"}}}, false,
+		),
+		Entry("should not accept a host endpoint template with an invalid interface pattern",
+			api.AutoHostEndpointConfig{Templates: []api.AutoHostEndpointTemplate{{GenerateName: "data", InterfacePattern: "eth["}}}, false,
+		),
+		Entry("should not accept a host endpoint template with an invalid CIDR",
+			api.AutoHostEndpointConfig{Templates: []api.AutoHostEndpointTemplate{{GenerateName: "data", InterfaceCIDRs: []string{"10.0.0.0/33"}}}}, false,
+		),
+		Entry("should not accept a host endpoint template with an invalid node selector",
+			api.AutoHostEndpointConfig{Templates: []api.AutoHostEndpointTemplate{{GenerateName: "data", NodeSelector: "has("}}}, false,
+		),
+		Entry("should not accept host endpoint templates with the same name",
+			api.AutoHostEndpointConfig{Templates: []api.AutoHostEndpointTemplate{{GenerateName: "data"}, {GenerateName: "data"}}}, false,
+		),
+		Entry("should not accept an invalid default host endpoint setting",
+			api.AutoHostEndpointConfig{CreateDefaultHostEndpoint: "Maybe"}, false,
+		),
 		Entry("should accept valid reconciliation period on policy",
 			api.PolicyControllerConfig{ReconcilerPeriod: &v1.Duration{Duration: time.Second * 330}}, true,
 		),
@@ -3121,6 +3425,17 @@ func init() {
 		Entry("should accept a valid BPFForceTrackPacketsFromIfaces value 'docker+'", api.FelixConfigurationSpec{BPFForceTrackPacketsFromIfaces: &[]string{"docker+"}}, true),
 		Entry("should accept a valid BPFForceTrackPacketsFromIfaces value 'docker0,docker1'", api.FelixConfigurationSpec{BPFForceTrackPacketsFromIfaces: &[]string{"docker0", "docker1"}}, true),
 		Entry("should reject invalid BPFForceTrackPacketsFromIfaces value 'cali-123,cali@456'", api.FelixConfigurationSpec{BPFForceTrackPacketsFromIfaces: &[]string{"cali-123", "cali@456"}}, false),
+		Entry("should accept a valid egress proxy config", api.FelixConfigurationSpec{
+			EgressProxyAddress:      "10.0.0.1",
+			EgressProxySelector:     "egress == 'proxied'",
+			EgressProxyExcludeCIDRs: &[]string{"10.96.0.0/12"},
+		}, true),
+		Entry("should reject an egress proxy hostname", api.FelixConfigurationSpec{EgressProxyAddress: "proxy.example.com"}, false),
+		Entry("should reject an invalid egress proxy selector", api.FelixConfigurationSpec{EgressProxySelector: "egress == "}, false),
+		Entry("should reject an invalid egress proxy exclude CIDR", api.FelixConfigurationSpec{EgressProxyExcludeCIDRs: &[]string{"10.96.0.0/40"}}, false),
+		Entry("should accept a valid L7 proxy selector", api.FelixConfigurationSpec{L7ProxySelector: "l7 == 'enforced'"}, true),
+		Entry("should reject an invalid L7 proxy selector", api.FelixConfigurationSpec{L7ProxySelector: "l7 == "}, false),
+		Entry("should reject an L7 proxy port of 0", api.FelixConfigurationSpec{L7ProxyPort: &V0}, false),
 	)
 }
 
@@ -3134,6 +3449,16 @@ func protocolFromInt(i uint8) *numorstring.Protocol {
 	return &p
 }
 
+func dscpFromString(s string) *numorstring.DSCP {
+	d := numorstring.DSCPFromString(s)
+	return &d
+}
+
+func dscpFromInt(i uint8) *numorstring.DSCP {
+	d := numorstring.DSCPFromInt(i)
+	return &d
+}
+
 func mustParsePortRange(min, max uint16) numorstring.Port {
 	p, err := numorstring.PortFromRange(min, max)
 	if err != nil {
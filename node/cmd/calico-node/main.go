@@ -17,6 +17,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	confdConfig "github.com/projectcalico/calico/confd/pkg/config"
@@ -25,7 +26,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/felix/bpf/bpfdefs"
 	felix "github.com/projectcalico/calico/felix/daemon"
+	felixshutdown "github.com/projectcalico/calico/felix/shutdown"
 	"github.com/projectcalico/calico/libcalico-go/lib/logutils"
 
 	"github.com/projectcalico/calico/node/buildinfo"
@@ -50,6 +53,7 @@ var runInit = flagSet.Bool("init", false, "Do privileged initialisation of a new
 var bestEffort = flagSet.Bool("best-effort", false, "Used in combination with the init flag. Report errors but do not fail if an error occurs during initialisation.")
 var runStartup = flagSet.Bool("startup", false, "Do non-privileged start-up routine.")
 var runShutdown = flagSet.Bool("shutdown", false, "Do shutdown routine.")
+var felixShutdownMode = flagSet.String("felix-shutdown-mode", "", "Set what the running Felix does with the dataplane when it is terminated: Retain or Cleanup.")
 var monitorAddrs = flagSet.Bool("monitor-addresses", false, "Monitor change in node IP addresses")
 var runAllocateTunnelAddrs = flagSet.Bool("allocate-tunnel-addrs", false, "Configure tunnel addresses for this node")
 var allocateTunnelAddrsRunOnce = flagSet.Bool("allocate-tunnel-addrs-run-once", false, "Run allocate-tunnel-addrs in oneshot mode")
@@ -140,6 +144,13 @@ func main() {
 	} else if *runShutdown {
 		logrus.SetFormatter(&logutils.Formatter{Component: "shutdown"})
 		shutdown.Run()
+	} else if *felixShutdownMode != "" {
+		socketPath := filepath.Join(bpfdefs.DefaultRuntimeDir, felixshutdown.SocketName)
+		if err := felixshutdown.SetMode(socketPath, *felixShutdownMode); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	} else if *monitorAddrs {
 		logrus.SetFormatter(&logutils.Formatter{Component: "monitor-addresses"})
 		startup.ConfigureLogging()
@@ -1479,6 +1479,16 @@ func (c shimClient) NetworkPolicies() client.NetworkPolicyInterface {
 	return c.client.NetworkPolicies()
 }
 
+// StagedGlobalNetworkPolicies returns an interface for managing staged policy resources.
+func (c shimClient) StagedGlobalNetworkPolicies() client.StagedGlobalNetworkPolicyInterface {
+	return c.client.StagedGlobalNetworkPolicies()
+}
+
+// StagedNetworkPolicies returns an interface for managing staged policy resources.
+func (c shimClient) StagedNetworkPolicies() client.StagedNetworkPolicyInterface {
+	return c.client.StagedNetworkPolicies()
+}
+
 // GlobalNetworkPolicies returns an interface for managing policy resources.
 func (c shimClient) GlobalNetworkPolicies() client.GlobalNetworkPolicyInterface {
 	return c.client.GlobalNetworkPolicies()
@@ -1453,8 +1453,13 @@ var _ = Describe("with server requiring TLS", func() {
 		requiredClientCN     string
 		requiredClientURISAN string
 		serverCertName       string
+		serverCAFile         string
 	)
 
+	BeforeEach(func() {
+		serverCAFile = filepath.Join(certDir, "ca.crt")
+	})
+
 	// Each client we create gets recorded here for cleanup.
 	type clientState struct {
 		clientCxt      context.Context
@@ -1517,9 +1522,11 @@ var _ = Describe("with server requiring TLS", func() {
 				DropInterval: 50 * time.Millisecond,
 				KeyFile:      filepath.Join(certDir, serverCertName+".key"),
 				CertFile:     filepath.Join(certDir, serverCertName+".crt"),
-				CAFile:       filepath.Join(certDir, "ca.crt"),
+				CAFile:       serverCAFile,
 				ClientCN:     requiredClientCN,
 				ClientURISAN: requiredClientURISAN,
+
+				CertResyncInterval: 100 * time.Millisecond,
 			})
 		cache.Start(cacheCxt)
 		serverCxt, serverCancel = context.WithCancel(context.Background())
@@ -1702,6 +1709,99 @@ var _ = Describe("with server requiring TLS", func() {
 		It("TLS connection with good CN and URI should fail", testTLSGoodCNURI(false))
 	})
 
+	Describe("with rotated CA bundle", func() {
+		var rotationDir string
+
+		readCert := func(name string) []byte {
+			data, err := os.ReadFile(filepath.Join(certDir, name))
+			Expect(err).NotTo(HaveOccurred())
+			return data
+		}
+
+		writeBundle := func(path string, certNames ...string) {
+			var bundle []byte
+			for _, n := range certNames {
+				bundle = append(bundle, readCert(n)...)
+			}
+			Expect(os.WriteFile(path+".tmp", bundle, 0644)).To(Succeed())
+			Expect(os.Rename(path+".tmp", path)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			requiredClientCN = ""
+			requiredClientURISAN = clientURISAN
+			serverCertName = "server"
+
+			var err error
+			rotationDir, err = os.MkdirTemp("", "typhafv-rotation")
+			Expect(err).NotTo(HaveOccurred())
+			serverCAFile = filepath.Join(rotationDir, "ca.crt")
+			writeBundle(serverCAFile, "ca.crt")
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(rotationDir)
+		})
+
+		sendUpdates := func() {
+			decoupler.OnStatusUpdated(api.ResyncInProgress)
+			decoupler.OnUpdates([]api.Update{configFoobarBazzBiff})
+			decoupler.OnStatusUpdated(api.InSync)
+		}
+
+		It("should close connections whose client certificate is no longer trusted", func() {
+			clientState := createClient(&syncclient.Options{
+				KeyFile:      filepath.Join(certDir, "gooduri.key"),
+				CertFile:     filepath.Join(certDir, "gooduri.crt"),
+				CAFile:       filepath.Join(certDir, "ca.crt"),
+				ServerURISAN: serverURISAN,
+			})
+			defer clientState.recorderCancel()
+			defer clientState.clientCancel()
+			Expect(clientState.startErr).NotTo(HaveOccurred())
+			sendUpdates()
+			Eventually(clientState.recorder.Status).Should(Equal(api.InSync))
+
+			connectionClosed := make(chan struct{})
+			go func() {
+				defer close(connectionClosed)
+				clientState.client.Finished.Wait()
+			}()
+			Consistently(connectionClosed, "300ms").ShouldNot(BeClosed())
+
+			// Rotate the trusted CA out of the bundle.
+			writeBundle(serverCAFile, "untrusted.crt")
+			Eventually(connectionClosed, "5s").Should(BeClosed())
+		})
+
+		It("should accept clients signed by a CA that was added to the bundle", func() {
+			writeBundle(serverCAFile, "ca.crt", "untrusted.crt")
+			clientBundle := filepath.Join(rotationDir, "client-ca.crt")
+			writeBundle(clientBundle, "ca.crt", "untrusted.crt")
+
+			Eventually(func() error {
+				clientState := createClient(&syncclient.Options{
+					KeyFile:      filepath.Join(certDir, "client-untrusted.key"),
+					CertFile:     filepath.Join(certDir, "client-untrusted.crt"),
+					CAFile:       clientBundle,
+					ServerURISAN: serverURISAN,
+				})
+				defer clientState.recorderCancel()
+				defer clientState.clientCancel()
+				if clientState.startErr != nil {
+					return clientState.startErr
+				}
+				sendUpdates()
+				for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+					if clientState.recorder.Status() == api.InSync {
+						return nil
+					}
+				}
+				return fmt.Errorf("client didn't sync, status %v", clientState.recorder.Status())
+			}, "5s", "200ms").ShouldNot(HaveOccurred())
+		})
+	})
+
 	Describe("handshake", func() {
 		BeforeEach(func() {
 			requiredClientCN = clientCN
@@ -127,7 +127,11 @@ type Config struct {
 	// specified, they _all_ must be - except that either ClientCN or ClientURISAN may be left
 	// unset - and Typha will then only accept secure (TLS) connections.  Each connecting client
 	// (Felix) must present a certificate signed by a CA in CAFile, and with CN matching
-	// ClientCN or URI SAN matching ClientURISAN.
+	// ClientCN or URI SAN matching ClientURISAN.  If ClientURISAN is a SPIFFE ID, the client
+	// certificate must also be a valid X.509-SVID.  The files are reloaded when they change (for
+	// example, when the SPIRE agent rotates them) and connections whose client certificate has
+	// expired, or is no longer signed by a CA in CAFile, are closed so that the client
+	// re-handshakes with its current certificate.
 	ServerKeyFile  string `config:"file(must-exist);;local"`
 	ServerCertFile string `config:"file(must-exist);;local"`
 	CAFile         string `config:"file(must-exist);;local"`
@@ -152,6 +156,7 @@ type Config struct {
 	K8sNamespace                          string        `config:"string;kube-system"`
 	K8sServiceName                        string        `config:"string;calico-typha"`
 	K8sPortName                           string        `config:"string;calico-typha"`
+	K8sEndpointSlicesOnly                 bool          `config:"bool;false"`
 
 	// State tracking.
 
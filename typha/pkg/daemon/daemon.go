@@ -415,7 +415,7 @@ func (t *TyphaDaemon) Start(cxt context.Context) {
 	t.Server.Start(cxt)
 	if t.ConfigParams.ConnectionRebalancingMode == "kubernetes" {
 		log.Info("Kubernetes connection rebalancing is enabled, starting k8s poll goroutine.")
-		k8sAPI := k8s.NewK8sAPI(t.nodeCounter)
+		k8sAPI := k8s.NewK8sAPI(t.nodeCounter, t.ConfigParams.K8sEndpointSlicesOnly)
 		ticker := jitter.NewTicker(
 			t.ConfigParams.K8sServicePollIntervalSecs,
 			t.ConfigParams.K8sServicePollIntervalSecs/10)
@@ -308,6 +308,16 @@ func (b *mockDatastore) NetworkPolicies() clientv3.NetworkPolicyInterface {
 	panic("not implemented")
 }
 
+// StagedGlobalNetworkPolicies returns an interface for managing staged global network policy resources.
+func (b *mockDatastore) StagedGlobalNetworkPolicies() clientv3.StagedGlobalNetworkPolicyInterface {
+	panic("not implemented")
+}
+
+// StagedNetworkPolicies returns an interface for managing staged namespaced network policy resources.
+func (b *mockDatastore) StagedNetworkPolicies() clientv3.StagedNetworkPolicyInterface {
+	panic("not implemented")
+}
+
 // IPPools returns an interface for managing IP pool resources.
 func (b *mockDatastore) IPPools() clientv3.IPPoolInterface {
 	panic("not implemented")
@@ -21,9 +21,12 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
@@ -32,6 +35,17 @@ import (
 
 var ErrServiceNotReady = errors.New("Kubernetes service missing IP or port")
 
+// Address families for WithAddressFamilyPreference.
+const (
+	AddressFamilyAny  = "Any"
+	AddressFamilyIPv4 = "IPv4"
+	AddressFamilyIPv6 = "IPv6"
+)
+
+// familyFailureHoldOff is how long a family is deprioritised after a failed connection to one of
+// its addresses.
+const familyFailureHoldOff = time.Minute
+
 type Typha struct {
 	Addr     string
 	IP       string
@@ -46,6 +60,26 @@ func (t Typha) dedupeKey() string {
 	return fmt.Sprintf("%s/%s/%s", t.Addr, t.IP, node)
 }
 
+// family returns the address family of the Typha, or "" if its address is a hostname.
+func (t Typha) family() string {
+	ip := t.IP
+	if ip == "" {
+		host, _, err := net.SplitHostPort(t.Addr)
+		if err != nil {
+			return ""
+		}
+		ip = host
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if parsed.To4() != nil {
+		return AddressFamilyIPv4
+	}
+	return AddressFamilyIPv6
+}
+
 type Discoverer struct {
 	addrOverride       string
 	nodeName           string
@@ -53,14 +87,22 @@ type Discoverer struct {
 	k8sServiceName     string
 	k8sNamespace       string
 	k8sServicePortName string
+	endpointSlicesOnly bool
 	inCluster          bool
 	filters            []func(typhaAddresses []Typha) ([]Typha, error)
+	familyPreference   string
 
 	allKnownAddrs []Typha
+
+	now                func() time.Time
+	familyFailuresLock sync.Mutex
+	familyFailures     map[string]time.Time
 }
 
 type Option func(opts *Discoverer)
 
+// WithAddrOverride sets the address of Typha, which disables discovery through Kubernetes.  addr
+// may be a comma-separated list of addresses, which are tried in order.
 func WithAddrOverride(addr string) Option {
 	return func(d *Discoverer) {
 		d.addrOverride = addr
@@ -94,6 +136,14 @@ func WithKubeServicePortNameOverride(portName string) Option {
 	}
 }
 
+// WithEndpointSlicesOnly stops discovery from falling back to the legacy Endpoints API when the
+// EndpointSlice API is unavailable.
+func WithEndpointSlicesOnly(slicesOnly bool) Option {
+	return func(d *Discoverer) {
+		d.endpointSlicesOnly = slicesOnly
+	}
+}
+
 // WithNodeAffinity help discovery preference by supplying nodeName to determine which endpoints are local to node
 func WithNodeAffinity(nodeName string) Option {
 	return func(d *Discoverer) {
@@ -101,6 +151,15 @@ func WithNodeAffinity(nodeName string) Option {
 	}
 }
 
+// WithAddressFamilyPreference puts the discovered Typha addresses of the given family, "IPv4" or
+// "IPv6", before those of the other family, still keeping the local endpoints first.  "Any" or ""
+// keeps the discovered order.
+func WithAddressFamilyPreference(family string) Option {
+	return func(d *Discoverer) {
+		d.familyPreference = family
+	}
+}
+
 func WithPostDiscoveryFilter(f func(typhaAddresses []Typha) ([]Typha, error)) Option {
 	return func(d *Discoverer) {
 		d.AddPostDiscoveryFilter(f)
@@ -110,6 +169,8 @@ func WithPostDiscoveryFilter(f func(typhaAddresses []Typha) ([]Typha, error)) Op
 func New(opts ...Option) *Discoverer {
 	d := &Discoverer{
 		k8sServicePortName: "calico-typha",
+		now:                time.Now,
+		familyFailures:     map[string]time.Time{},
 	}
 
 	for _, o := range opts {
@@ -148,6 +209,27 @@ func (d *Discoverer) LoadTyphaAddrs() (ts []Typha, err error) {
 	return
 }
 
+// ReportConnectionFailure records that connecting to the Typha failed.  Until the hold-off expires
+// or a connection to the same family succeeds, LoadTyphaAddrs puts the addresses of its family
+// after the others, so that the next attempts fall back to the other family when one degrades.
+func (d *Discoverer) ReportConnectionFailure(t Typha) {
+	family := t.family()
+	if family == "" {
+		return
+	}
+	d.familyFailuresLock.Lock()
+	defer d.familyFailuresLock.Unlock()
+	d.familyFailures[family] = d.now()
+}
+
+// ReportConnectionSuccess records that connecting to the Typha succeeded, clearing any recent
+// failure of its family.
+func (d *Discoverer) ReportConnectionSuccess(t Typha) {
+	d.familyFailuresLock.Lock()
+	defer d.familyFailuresLock.Unlock()
+	delete(d.familyFailures, t.family())
+}
+
 func (d *Discoverer) CachedTyphaAddrs() []Typha {
 	return d.allKnownAddrs
 }
@@ -162,8 +244,16 @@ func (d *Discoverer) discoverTyphaAddrs() ([]Typha, error) {
 	}
 
 	if d.addrOverride != "" {
-		// Explicit address; trumps other sources of config.
-		return []Typha{{Addr: d.addrOverride}}, nil
+		// Explicit address(es); trumps other sources of config.  The configured order is the
+		// preference order so only the recent failures reorder the list.
+		var addresses []Typha
+		for _, addr := range strings.Split(d.addrOverride, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addresses = append(addresses, Typha{Addr: addr})
+			}
+		}
+		d.sortByFamily(addresses, AddressFamilyAny)
+		return addresses, nil
 	}
 
 	// If we get here, we need to look up the Typha service using the k8s API.
@@ -186,8 +276,8 @@ func (d *Discoverer) discoverTyphaAddrs() ([]Typha, error) {
 
 	// If we get here, we need to look up the Typha service endpoints using the k8s API.
 	logrus.Info("(Re)discovering Typha endpoints using the Kubernetes API...")
-	epClient := d.k8sClient.CoreV1().Endpoints(d.k8sNamespace)
-	eps, err := epClient.Get(context.Background(), d.k8sServiceName, v1.GetOptions{})
+	eps, err := ListEndpoints(context.Background(), d.k8sClient, d.k8sNamespace, d.k8sServiceName,
+		d.k8sServicePortName, d.endpointSlicesOnly)
 	if err != nil {
 		logrus.WithError(err).Error("Unable to get Typha service endpoints from Kubernetes.")
 		return nil, err
@@ -198,29 +288,14 @@ func (d *Discoverer) discoverTyphaAddrs() ([]Typha, error) {
 		local, remote, addresses []Typha
 	)
 
-	for _, subset := range eps.Subsets {
-		var portForOurVersion int32
-		for _, port := range subset.Ports {
-			if port.Name == d.k8sServicePortName {
-				portForOurVersion = port.Port
-				break
-			}
-		}
-
-		if portForOurVersion == 0 {
-			continue
-		}
-
-		// If we get here, this endpoint supports the typha port we're looking for.
-		for _, h := range subset.Addresses {
-			typhaAddr := net.JoinHostPort(h.IP, fmt.Sprint(portForOurVersion))
-			if h.NodeName != nil && *h.NodeName == d.nodeName { // is local
-				local = append(local, Typha{Addr: typhaAddr, IP: h.IP, NodeName: h.NodeName})
-			} else {
-				remote = append(remote, Typha{Addr: typhaAddr, IP: h.IP, NodeName: h.NodeName})
-			}
-			candidates++
+	for _, ep := range eps {
+		typhaAddr := net.JoinHostPort(ep.IP, fmt.Sprint(ep.Port))
+		if ep.NodeName != nil && *ep.NodeName == d.nodeName { // is local
+			local = append(local, Typha{Addr: typhaAddr, IP: ep.IP, NodeName: ep.NodeName})
+		} else {
+			remote = append(remote, Typha{Addr: typhaAddr, IP: ep.IP, NodeName: ep.NodeName})
 		}
+		candidates++
 	}
 
 	// return results with local endpoints first on the list
@@ -231,6 +306,8 @@ func (d *Discoverer) discoverTyphaAddrs() ([]Typha, error) {
 
 	shuffleInPlace(local)
 	shuffleInPlace(remote)
+	d.sortByFamily(local, d.familyPreference)
+	d.sortByFamily(remote, d.familyPreference)
 
 	addresses = append(local, remote...)
 
@@ -323,6 +400,35 @@ func (d *ConnectionAttemptTracker) pickNextTypha() (Typha, error) {
 	return Typha{}, ErrTriedAllAddrs
 }
 
+// sortByFamily stably sorts the addresses: first those of the preferred family, then those of the
+// other family and, last, those of a family with a recent connection failure.
+func (d *Discoverer) sortByFamily(ts []Typha, preference string) {
+	d.familyFailuresLock.Lock()
+	failed := map[string]bool{}
+	for family, failureTime := range d.familyFailures {
+		if d.now().Sub(failureTime) < familyFailureHoldOff {
+			failed[family] = true
+		} else {
+			delete(d.familyFailures, family)
+		}
+	}
+	d.familyFailuresLock.Unlock()
+
+	rank := func(t Typha) int {
+		family := t.family()
+		if failed[family] {
+			return 2
+		}
+		if preference != "" && preference != AddressFamilyAny && family != preference {
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(ts, func(i, j int) bool {
+		return rank(ts[i]) < rank(ts[j])
+	})
+}
+
 func shuffleInPlace(s []Typha) {
 	rand.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
 }
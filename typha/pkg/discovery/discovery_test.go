@@ -18,62 +18,52 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var _ = Describe("Typha address discovery", func() {
 	var (
-		endpoints                     *v1.Endpoints
+		endpointSlices                []*discoveryv1.EndpointSlice
 		k8sClient                     *fake.Clientset
 		localNodeName, remoteNodeName string
 		noTyphas                      []Typha
 	)
 
 	refreshClient := func() {
-		k8sClient = fake.NewSimpleClientset(endpoints)
+		var objs []runtime.Object
+		for _, s := range endpointSlices {
+			objs = append(objs, s)
+		}
+		k8sClient = fake.NewSimpleClientset(objs...)
 	}
 
 	BeforeEach(func() {
 		localNodeName = "felix-local"
 		remoteNodeName = "felix-remote"
 
-		endpoints = &v1.Endpoints{
-			TypeMeta: metav1.TypeMeta{
-				APIVersion: "v1",
-				Kind:       "Endpoints",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "calico-typha-service",
-				Namespace: "kube-system",
-			},
-			Subsets: []v1.EndpointSubset{
-				{
-					Addresses: []v1.EndpointAddress{
-						{IP: "10.0.0.4", NodeName: &localNodeName},
-					},
-					NotReadyAddresses: []v1.EndpointAddress{},
-					Ports: []v1.EndpointPort{
-						{Name: "calico-typha-v2", Port: 8157, Protocol: v1.ProtocolUDP},
-					},
-				},
-				{
-					Addresses: []v1.EndpointAddress{
-						{IP: "10.0.0.2", NodeName: &remoteNodeName},
-					},
-					NotReadyAddresses: []v1.EndpointAddress{
-						{IP: "10.0.0.5", NodeName: &remoteNodeName},
-					},
-					Ports: []v1.EndpointPort{
-						{Name: "calico-typha-v2", Port: 8157, Protocol: v1.ProtocolUDP},
-						{Name: "calico-typha", Port: 8156, Protocol: v1.ProtocolTCP},
-					},
+		endpointSlices = []*discoveryv1.EndpointSlice{
+			typhaEndpointSlice("calico-typha-service-1",
+				[]discoveryv1.EndpointPort{typhaPort("calico-typha-v2", 8157, v1.ProtocolUDP)},
+				typhaEndpoint("10.0.0.4", &localNodeName, true),
+			),
+			typhaEndpointSlice("calico-typha-service-2",
+				[]discoveryv1.EndpointPort{
+					typhaPort("calico-typha-v2", 8157, v1.ProtocolUDP),
+					typhaPort("calico-typha", 8156, v1.ProtocolTCP),
 				},
-			},
+				typhaEndpoint("10.0.0.2", &remoteNodeName, true),
+				typhaEndpoint("10.0.0.5", &remoteNodeName, false),
+			),
 		}
 
 		refreshClient()
@@ -152,7 +142,8 @@ var _ = Describe("Typha address discovery", func() {
 	})
 
 	It("should bracket an IPv6 Typha address", func() {
-		endpoints.Subsets[1].Addresses[0].IP = "fd5f:65af::2"
+		endpointSlices[1].AddressType = discoveryv1.AddressTypeIPv6
+		endpointSlices[1].Endpoints[0].Addresses[0] = "fd5f:65af::2"
 		refreshClient()
 		typhaAddr, err := DiscoverTyphaAddrs(
 			WithKubeService("kube-system", "calico-typha-service"),
@@ -165,7 +156,7 @@ var _ = Describe("Typha address discovery", func() {
 	})
 
 	It("should error if no Typhas", func() {
-		endpoints.Subsets = nil
+		endpointSlices = nil
 		refreshClient()
 		_, err := DiscoverTyphaAddrs(
 			WithKubeService("kube-system", "calico-typha-service"),
@@ -176,37 +167,20 @@ var _ = Describe("Typha address discovery", func() {
 	})
 
 	It("should shuffle local and remote endpoints and have local first", func() {
-		endpoints.Subsets = append(endpoints.Subsets, []v1.EndpointSubset{
+		endpointSlices = append(endpointSlices,
 			// Unrealistic, but have multiple endpoints on the same node, just with different IPs. This is to
 			// test the local and remote endpoint shuffling.
-			{
-				Addresses: []v1.EndpointAddress{
-					{IP: "10.0.0.5", NodeName: &localNodeName},
-				},
-				NotReadyAddresses: []v1.EndpointAddress{},
-				Ports: []v1.EndpointPort{
-					{Name: "calico-typha-v2", Port: 8157, Protocol: v1.ProtocolUDP},
-				},
-			},
-			{
-				Addresses: []v1.EndpointAddress{
-					{IP: "10.0.0.6", NodeName: &localNodeName},
-				},
-				NotReadyAddresses: []v1.EndpointAddress{},
-				Ports: []v1.EndpointPort{
-					{Name: "calico-typha-v2", Port: 8157, Protocol: v1.ProtocolUDP},
-				},
-			},
-			{
-				Addresses: []v1.EndpointAddress{
-					{IP: "10.0.0.3"},
-					{IP: "10.0.0.7", NodeName: &remoteNodeName},
-				},
-				Ports: []v1.EndpointPort{
-					{Name: "calico-typha-v2", Port: 8157, Protocol: v1.ProtocolUDP},
-				},
-			},
-		}...)
+			typhaEndpointSlice("calico-typha-service-3",
+				[]discoveryv1.EndpointPort{typhaPort("calico-typha-v2", 8157, v1.ProtocolUDP)},
+				typhaEndpoint("10.0.0.5", &localNodeName, true),
+				typhaEndpoint("10.0.0.6", &localNodeName, true),
+			),
+			typhaEndpointSlice("calico-typha-service-4",
+				[]discoveryv1.EndpointPort{typhaPort("calico-typha-v2", 8157, v1.ProtocolUDP)},
+				typhaEndpoint("10.0.0.3", nil, true),
+				typhaEndpoint("10.0.0.7", &remoteNodeName, true),
+			),
+		)
 		refreshClient()
 
 		typhaAddr, err := DiscoverTyphaAddrs(
@@ -252,8 +226,192 @@ var _ = Describe("Typha address discovery", func() {
 		Expect(shuffledLocal).To(BeTrue())
 		Expect(shuffledRemote).To(BeTrue())
 	})
+
+	It("should ignore endpoints that appear in more than one slice", func() {
+		endpointSlices = append(endpointSlices, typhaEndpointSlice("calico-typha-service-3",
+			[]discoveryv1.EndpointPort{typhaPort("calico-typha", 8156, v1.ProtocolTCP)},
+			typhaEndpoint("10.0.0.2", &remoteNodeName, true),
+		))
+		refreshClient()
+		typhaAddr, err := DiscoverTyphaAddrs(
+			WithKubeService("kube-system", "calico-typha-service"),
+			WithKubeClient(k8sClient),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typhaAddr).To(Equal([]Typha{
+			{Addr: "10.0.0.2:8156", IP: "10.0.0.2", NodeName: &remoteNodeName},
+		}))
+	})
+
+	It("should ignore slices of other services", func() {
+		other := typhaEndpointSlice("other-service-1",
+			[]discoveryv1.EndpointPort{typhaPort("calico-typha", 8156, v1.ProtocolTCP)},
+			typhaEndpoint("10.0.0.9", &remoteNodeName, true),
+		)
+		other.Labels[discoveryv1.LabelServiceName] = "other-service"
+		endpointSlices = append(endpointSlices, other)
+		refreshClient()
+		typhaAddr, err := DiscoverTyphaAddrs(
+			WithKubeService("kube-system", "calico-typha-service"),
+			WithKubeClient(k8sClient),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typhaAddr).To(Equal([]Typha{
+			{Addr: "10.0.0.2:8156", IP: "10.0.0.2", NodeName: &remoteNodeName},
+		}))
+	})
+
+	Describe("with the EndpointSlice API forbidden", func() {
+		BeforeEach(func() {
+			k8sClient = fake.NewSimpleClientset(&v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "calico-typha-service",
+					Namespace: "kube-system",
+				},
+				Subsets: []v1.EndpointSubset{{
+					Addresses: []v1.EndpointAddress{{IP: "10.0.0.8", NodeName: &remoteNodeName}},
+					Ports:     []v1.EndpointPort{{Name: "calico-typha", Port: 8156, Protocol: v1.ProtocolTCP}},
+				}},
+			})
+			k8sClient.PrependReactor("list", "endpointslices", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, kerrors.NewForbidden(discoveryv1.Resource("endpointslices"), "", errors.New("no RBAC"))
+			})
+		})
+
+		It("should fall back to the Endpoints API", func() {
+			typhaAddr, err := DiscoverTyphaAddrs(
+				WithKubeService("kube-system", "calico-typha-service"),
+				WithKubeClient(k8sClient),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{
+				{Addr: "10.0.0.8:8156", IP: "10.0.0.8", NodeName: &remoteNodeName},
+			}))
+		})
+
+		It("should not fall back in EndpointSlice-only mode", func() {
+			_, err := DiscoverTyphaAddrs(
+				WithKubeService("kube-system", "calico-typha-service"),
+				WithKubeClient(k8sClient),
+				WithEndpointSlicesOnly(true),
+			)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	It("should return a list of configured addresses in order", func() {
+		typhaAddr, err := DiscoverTyphaAddrs(WithAddrOverride("10.0.0.1:8080,[fd00::1]:8080, typha:8080"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typhaAddr).To(Equal([]Typha{
+			{Addr: "10.0.0.1:8080"},
+			{Addr: "[fd00::1]:8080"},
+			{Addr: "typha:8080"},
+		}))
+	})
+
+	Describe("with dual-stack endpoints", func() {
+		BeforeEach(func() {
+			v6Slice := typhaEndpointSlice("calico-typha-service-3",
+				[]discoveryv1.EndpointPort{typhaPort("calico-typha", 8156, v1.ProtocolTCP)},
+				typhaEndpoint("fd5f:65af::4", &localNodeName, true),
+				typhaEndpoint("fd5f:65af::2", &remoteNodeName, true),
+			)
+			v6Slice.AddressType = discoveryv1.AddressTypeIPv6
+			endpointSlices[0].Ports = []discoveryv1.EndpointPort{typhaPort("calico-typha", 8156, v1.ProtocolTCP)}
+			endpointSlices = append(endpointSlices, v6Slice)
+			refreshClient()
+		})
+
+		var (
+			local4  = func() Typha { return Typha{Addr: "10.0.0.4:8156", IP: "10.0.0.4", NodeName: &localNodeName} }
+			local6  = func() Typha { return Typha{Addr: "[fd5f:65af::4]:8156", IP: "fd5f:65af::4", NodeName: &localNodeName} }
+			remote4 = func() Typha { return Typha{Addr: "10.0.0.2:8156", IP: "10.0.0.2", NodeName: &remoteNodeName} }
+			remote6 = func() Typha { return Typha{Addr: "[fd5f:65af::2]:8156", IP: "fd5f:65af::2", NodeName: &remoteNodeName} }
+		)
+
+		newDiscoverer := func(family string) *Discoverer {
+			return New(
+				WithKubeService("kube-system", "calico-typha-service"),
+				WithKubeClient(k8sClient),
+				WithNodeAffinity(localNodeName),
+				WithAddressFamilyPreference(family),
+			)
+		}
+
+		It("should put the preferred family first, after the local endpoints", func() {
+			typhaAddr, err := newDiscoverer(AddressFamilyIPv6).LoadTyphaAddrs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{local6(), local4(), remote6(), remote4()}))
+
+			typhaAddr, err = newDiscoverer(AddressFamilyIPv4).LoadTyphaAddrs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{local4(), local6(), remote4(), remote6()}))
+		})
+
+		It("should deprioritise a family after a connection failure", func() {
+			now := time.Now()
+			d := newDiscoverer(AddressFamilyIPv6)
+			d.now = func() time.Time { return now }
+
+			d.ReportConnectionFailure(local6())
+			typhaAddr, err := d.LoadTyphaAddrs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{local4(), local6(), remote4(), remote6()}))
+
+			By("restoring the preference after the hold-off")
+			now = now.Add(familyFailureHoldOff)
+			typhaAddr, err = d.LoadTyphaAddrs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{local6(), local4(), remote6(), remote4()}))
+
+			By("restoring the preference after a successful connection")
+			d.ReportConnectionFailure(remote6())
+			d.ReportConnectionSuccess(local6())
+			typhaAddr, err = d.LoadTyphaAddrs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(typhaAddr).To(Equal([]Typha{local6(), local4(), remote6(), remote4()}))
+		})
+	})
+
+	It("should deprioritise the family of a failed configured address", func() {
+		d := New(WithAddrOverride("[fd00::1]:8080,10.0.0.1:8080,typha:8080"))
+		d.ReportConnectionFailure(Typha{Addr: "[fd00::1]:8080"})
+		d.ReportConnectionFailure(Typha{Addr: "typha:8080"})
+		typhaAddr, err := d.LoadTyphaAddrs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typhaAddr).To(Equal([]Typha{
+			{Addr: "10.0.0.1:8080"},
+			{Addr: "typha:8080"},
+			{Addr: "[fd00::1]:8080"},
+		}))
+	})
 })
 
+func typhaEndpointSlice(name string, ports []discoveryv1.EndpointPort, eps ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "calico-typha-service"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   eps,
+		Ports:       ports,
+	}
+}
+
+func typhaPort(name string, port int32, proto v1.Protocol) discoveryv1.EndpointPort {
+	return discoveryv1.EndpointPort{Name: &name, Port: &port, Protocol: &proto}
+}
+
+func typhaEndpoint(ip string, nodeName *string, ready bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		NodeName:   nodeName,
+	}
+}
+
 func DiscoverTyphaAddrs(opts ...Option) ([]Typha, error) {
 	discoverer := New(opts...)
 	return discoverer.LoadTyphaAddrs()
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+// endpointSlicePageSize limits the number of EndpointSlices fetched per request so that a
+// service that is split across hundreds of slices doesn't need one huge response.
+const endpointSlicePageSize = 100
+
+// Endpoint is a ready backend of a Kubernetes service.
+type Endpoint struct {
+	IP       string
+	Port     int32
+	NodeName *string
+}
+
+// ListEndpoints returns the ready endpoints of the given service that serve the named port.
+//
+// The endpoints are read from the service's EndpointSlices.  That includes services whose
+// Endpoints are maintained by hand, since Kubernetes mirrors those into EndpointSlices.  Unless
+// endpointSlicesOnly is set, the legacy Endpoints API is used instead if the EndpointSlice API
+// is forbidden or missing, for example because the RBAC rules have not been updated yet.
+func ListEndpoints(
+	ctx context.Context,
+	client kubernetes.Interface,
+	namespace, serviceName, portName string,
+	endpointSlicesOnly bool,
+) ([]Endpoint, error) {
+	eps, err := listEndpointSliceEndpoints(ctx, client, namespace, serviceName, portName)
+	if err != nil && !endpointSlicesOnly && (kerrors.IsForbidden(err) || kerrors.IsNotFound(err)) {
+		logrus.WithError(err).Warn("Unable to list EndpointSlices, falling back to the Endpoints API.")
+		return listLegacyEndpoints(ctx, client, namespace, serviceName, portName)
+	}
+	return eps, err
+}
+
+func listEndpointSliceEndpoints(
+	ctx context.Context,
+	client kubernetes.Interface,
+	namespace, serviceName, portName string,
+) ([]Endpoint, error) {
+	sliceClient := client.DiscoveryV1().EndpointSlices(namespace)
+	opts := v1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, serviceName),
+		Limit:         endpointSlicePageSize,
+	}
+
+	// The same endpoint can briefly appear in two slices while the endpoint slice controller
+	// moves it between them.
+	seen := set.New[string]()
+	var eps []Endpoint
+	for {
+		slices, err := sliceClient.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, slice := range slices.Items {
+			if slice.AddressType == discoveryv1.AddressTypeFQDN {
+				continue
+			}
+
+			var port int32
+			for _, p := range slice.Ports {
+				if p.Name != nil && *p.Name == portName && p.Port != nil {
+					port = *p.Port
+					break
+				}
+			}
+			if port == 0 {
+				continue
+			}
+
+			for _, ep := range slice.Endpoints {
+				if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+					continue
+				}
+				for _, ip := range ep.Addresses {
+					key := fmt.Sprintf("%s/%d", ip, port)
+					if seen.Contains(key) {
+						continue
+					}
+					seen.Add(key)
+					eps = append(eps, Endpoint{IP: ip, Port: port, NodeName: ep.NodeName})
+				}
+			}
+		}
+		if slices.Continue == "" {
+			return eps, nil
+		}
+		opts.Continue = slices.Continue
+	}
+}
+
+func listLegacyEndpoints(
+	ctx context.Context,
+	client kubernetes.Interface,
+	namespace, serviceName, portName string,
+) ([]Endpoint, error) {
+	endpoints, err := client.CoreV1().Endpoints(namespace).Get(ctx, serviceName, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var eps []Endpoint
+	for _, subset := range endpoints.Subsets {
+		var port int32
+		for _, p := range subset.Ports {
+			if p.Name == portName {
+				port = p.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		for _, h := range subset.Addresses {
+			eps = append(eps, Endpoint{IP: h.IP, Port: port, NodeName: h.NodeName})
+		}
+	}
+	return eps, nil
+}
@@ -19,21 +19,22 @@ import (
 	"os"
 
 	log "github.com/sirupsen/logrus"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
 	"github.com/projectcalico/calico/libcalico-go/lib/winutils"
 	"github.com/projectcalico/calico/typha/pkg/calc"
+	"github.com/projectcalico/calico/typha/pkg/discovery"
 )
 
-func NewK8sAPI(nc *calc.NodeCounter) *RealK8sAPI {
-	return &RealK8sAPI{nodeCounter: nc}
+func NewK8sAPI(nc *calc.NodeCounter, endpointSlicesOnly bool) *RealK8sAPI {
+	return &RealK8sAPI{nodeCounter: nc, endpointSlicesOnly: endpointSlicesOnly}
 }
 
 type RealK8sAPI struct {
-	cachedClientSet *kubernetes.Clientset
-	nodeCounter     *calc.NodeCounter
+	cachedClientSet    *kubernetes.Clientset
+	nodeCounter        *calc.NodeCounter
+	endpointSlicesOnly bool
 }
 
 func (r *RealK8sAPI) clientSet() (*kubernetes.Clientset, error) {
@@ -60,27 +61,15 @@ func (r *RealK8sAPI) GetNumTyphas(ctx context.Context, namespace, serviceName, p
 		return 0, err
 	}
 
-	epClient := clientSet.CoreV1().Endpoints(namespace)
-	ep, err := epClient.Get(ctx, serviceName, metav1.GetOptions{})
+	eps, err := discovery.ListEndpoints(ctx, clientSet, namespace, serviceName, portName, r.endpointSlicesOnly)
 	if err != nil {
-		log.WithError(err).Error("Failed to get Typha endpoint from Kubernetes")
+		log.WithError(err).Error("Failed to get Typha endpoints from Kubernetes")
 		return 0, err
 	}
 
 	ips := set.New[string]()
-	for _, s := range ep.Subsets {
-		found := false
-		for _, port := range s.Ports {
-			if port.Name == portName {
-				found = true
-				break
-			}
-		}
-		if found {
-			for _, ip := range s.Addresses {
-				ips.Add(ip.IP)
-			}
-		}
+	for _, ep := range eps {
+		ips.Add(ep.IP)
 	}
 	return ips.Len(), nil
 }
@@ -203,9 +203,11 @@ func (s *SyncerClient) Start(cxt context.Context) error {
 		err = s.connect(cxt, addr)
 		if err != nil {
 			s.logCxt.WithError(err).Warnf("Failed to connect to typha endpoint %s.  Will try another if available...", addr.Addr)
+			s.discoverer.ReportConnectionFailure(addr)
 			time.Sleep(100 * time.Millisecond) // Avoid tight loop.
 		} else {
 			s.logCxt.Infof("Successfully connected to Typha at %s.", addr.Addr)
+			s.discoverer.ReportConnectionSuccess(addr)
 			break
 		}
 	}
@@ -28,11 +28,12 @@ import (
 
 // TyphaConfig specifies the sync-client connection parameters
 type TyphaConfig struct {
-	Addr           string
-	K8sServiceName string
-	K8sNamespace   string
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
+	Addr                  string
+	K8sServiceName        string
+	K8sNamespace          string
+	K8sEndpointSlicesOnly bool
+	ReadTimeout           time.Duration
+	WriteTimeout          time.Duration
 
 	// Client-side TLS config for communication with Typha.  If any of these are
 	// specified, they _all_ must be - except that either CN or URISAN may be left unset.
@@ -28,6 +28,7 @@ var _ = Describe("Test TyphaConfig", func() {
 	BeforeEach(func() {
 		os.Setenv("FELIX_TYPHACAFILE", "cafile")
 		os.Setenv("FELIX_TYPHAREADTIMEOUT", "100")
+		os.Setenv("FELIX_TYPHAK8SENDPOINTSLICESONLY", "true")
 
 	})
 
@@ -35,5 +36,6 @@ var _ = Describe("Test TyphaConfig", func() {
 		typhaConfig := syncclientutils.ReadTyphaConfig([]string{"FELIX_"})
 		Expect(typhaConfig.CAFile).To(Equal("cafile"))
 		Expect(typhaConfig.ReadTimeout.Seconds()).To(Equal(100.))
+		Expect(typhaConfig.K8sEndpointSlicesOnly).To(BeTrue())
 	})
 })
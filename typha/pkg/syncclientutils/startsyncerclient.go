@@ -43,6 +43,7 @@ func MustStartSyncerClientIfTyphaConfigured(
 		discovery.WithAddrOverride(typhaConfig.Addr),
 		discovery.WithInClusterKubeClient(), /* defer creation of a client until its needed. */
 		discovery.WithKubeService(typhaConfig.K8sNamespace, typhaConfig.K8sServiceName),
+		discovery.WithEndpointSlicesOnly(typhaConfig.K8sEndpointSlicesOnly),
 	)
 	typhaAddrs, err := discoverer.LoadTyphaAddrs()
 	if err != nil {
@@ -192,6 +192,7 @@ func (s *SnappySnapshotCache) writeDataToSnapshot(snap *snapshot) {
 		context.Background(),
 		s.logCtx.WithField("destination", "compressed in-memory cache"),
 		snap.crumb,
+		"", // Shared by all clients so we can't prioritise a particular node's resources.
 		writeMsg,
 		1000, // Allow bigger messages in the snapshot.
 	)
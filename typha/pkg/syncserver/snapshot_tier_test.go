@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncserver
+
+import (
+	"context"
+
+	"github.com/google/btree"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/typha/pkg/snapcache"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+var _ = DescribeTable("snapshotTierForKey",
+	func(hostname, key string, expected snapshotTier) {
+		Expect(snapshotTierForKey(hostname, key)).To(Equal(expected))
+	},
+	Entry("ready flag", "node1", "/calico/v1/Ready", snapshotTierConfig),
+	Entry("global config", "node1", "/calico/v1/config/LogSeverityScreen", snapshotTierConfig),
+	Entry("local host config", "node1", "/calico/v1/host/node1/config/Foo", snapshotTierConfig),
+	Entry("local host endpoint", "node1", "/calico/v1/host/node1/endpoint/eth0", snapshotTierConfig),
+	Entry("local workload endpoint", "node1", "/calico/v1/host/node1/workload/k8s/ns%2fpod/endpoint/eth0", snapshotTierConfig),
+	Entry("policy", "node1", "/calico/v1/policy/tier/default/policy/allow-all", snapshotTierPolicy),
+	Entry("profile", "node1", "/calico/v1/policy/profile/kns.default", snapshotTierPolicy),
+	Entry("IP pool", "node1", "/calico/v1/ipam/v4/pool/10.0.0.0-16", snapshotTierPolicy),
+	Entry("remote host endpoint", "node1", "/calico/v1/host/node2/endpoint/eth0", snapshotTierPolicy),
+	Entry("remote host config", "node1", "/calico/v1/host/node2/config/Foo", snapshotTierPolicy),
+	Entry("host with name prefix", "node1", "/calico/v1/host/node10/metadata", snapshotTierEndpoints),
+	Entry("remote workload endpoint", "node1", "/calico/v1/host/node2/workload/k8s/ns%2fpod/endpoint/eth0", snapshotTierEndpoints),
+	Entry("shared snapshot workload endpoint", "", "/calico/v1/host/node1/workload/k8s/ns%2fpod/endpoint/eth0", snapshotTierEndpoints),
+	Entry("shared snapshot host endpoint", "", "/calico/v1/host/node1/endpoint/eth0", snapshotTierPolicy),
+	Entry("network set", "node1", "/calico/v1/netset/foo", snapshotTierBulk),
+	Entry("IPAM block", "node1", "/calico/ipam/v2/assignment/ipv4/block/10.0.0.0-26", snapshotTierBulk),
+	Entry("v3 resource", "node1", "/calico/resources/v3/projectcalico.org/nodes/node1", snapshotTierBulk),
+)
+
+var _ = Describe("writeSnapshotMessages", func() {
+	It("should send KVs in tier order", func() {
+		kvs := btree.NewG[syncproto.SerializedUpdate](2, func(a, b syncproto.SerializedUpdate) bool { return a.Key < b.Key })
+		for _, k := range []string{
+			"/calico/v1/netset/foo",
+			"/calico/v1/host/node2/workload/k8s/ns%2fpod/endpoint/eth0",
+			"/calico/v1/host/node2/endpoint/eth0",
+			"/calico/v1/host/node1/workload/k8s/ns%2fpod/endpoint/eth0",
+			"/calico/v1/policy/tier/default/policy/allow-all",
+			"/calico/v1/config/LogSeverityScreen",
+			"/calico/ipam/v2/assignment/ipv4/block/10.0.0.0-26",
+		} {
+			kvs.ReplaceOrInsert(syncproto.SerializedUpdate{Key: k})
+		}
+
+		var keys []string
+		err := writeSnapshotMessages(
+			context.Background(),
+			log.WithField("test", true),
+			&snapcache.Breadcrumb{KVs: kvs},
+			"node1",
+			func(msg any) error {
+				for _, kv := range msg.(syncproto.MsgKVs).KVs {
+					keys = append(keys, kv.Key)
+				}
+				return nil
+			},
+			2,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(Equal([]string{
+			"/calico/v1/config/LogSeverityScreen",
+			"/calico/v1/host/node1/workload/k8s/ns%2fpod/endpoint/eth0",
+			"/calico/v1/host/node2/endpoint/eth0",
+			"/calico/v1/policy/tier/default/policy/allow-all",
+			"/calico/v1/host/node2/workload/k8s/ns%2fpod/endpoint/eth0",
+			"/calico/ipam/v2/assignment/ipv4/block/10.0.0.0-26",
+			"/calico/v1/netset/foo",
+		}))
+	})
+})
@@ -18,7 +18,6 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/gob"
 	"errors"
 	"fmt"
@@ -65,6 +64,10 @@ var (
 		Name: "typha_connections_dropped",
 		Help: "Total number of connections dropped due to rebalancing.",
 	})
+	counterNumConnectionsRecycled = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "typha_connections_recycled",
+		Help: "Total number of connections closed because the client certificate was no longer trusted.",
+	})
 	gaugeNumConnections = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "typha_connections_active",
 		Help: "Number of open client connections, including connections that are still in the handshake.",
@@ -85,6 +88,7 @@ var (
 func init() {
 	prometheus.MustRegister(counterNumConnectionsAccepted)
 	prometheus.MustRegister(counterNumConnectionsDropped)
+	prometheus.MustRegister(counterNumConnectionsRecycled)
 	prometheus.MustRegister(gaugeNumConnections)
 
 	prometheus.MustRegister(gaugeVecNumConnectionsStreaming)
@@ -155,6 +159,10 @@ type Config struct {
 	ClientURISAN                   string
 	WriteBufferSize                int
 
+	// CertResyncInterval is how often the TLS files are reloaded, in addition to reloading them when
+	// they change, and how often established connections are checked against the current CA bundle.
+	CertResyncInterval time.Duration
+
 	// DebugLogWrites tells the server to wrap each connection with a Writer that
 	// logs every write.  Intended only for use in tests!
 	DebugLogWrites bool
@@ -248,6 +256,9 @@ func (c *Config) ApplyDefaults() {
 		}).Info("Defaulting ShutdownTimeout.")
 		c.ShutdownTimeout = defaultShutdownTimeout
 	}
+	if c.CertResyncInterval <= 0 {
+		c.CertResyncInterval = tlsutils.DefaultCertResyncInterval
+	}
 	if c.MaxConns <= 0 {
 		log.WithFields(log.Fields{
 			"value":   c.MaxConns,
@@ -341,34 +352,45 @@ func (s *Server) serve(cxt context.Context) {
 	if s.config.requiringTLS() {
 		pwd, _ := os.Getwd()
 		logCxt.WithField("pwd", pwd).Info("Opening TLS listen socket")
-		cert, tlsErr := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
+		certs, tlsErr := tlsutils.NewCertWatcher(s.config.CertFile, s.config.KeyFile, s.config.CAFile)
 		if tlsErr != nil {
 			logCxt.WithFields(log.Fields{
 				"certFile": s.config.CertFile,
 				"keyFile":  s.config.KeyFile,
-			}).WithError(tlsErr).Panic("Failed to load certificate and key")
+				"caFile":   s.config.CAFile,
+			}).WithError(tlsErr).Panic("Failed to load certificate, key and CA data")
 		}
+		certs.ResyncInterval = s.config.CertResyncInterval
 		tlsConfig := calicotls.NewTLSConfig()
-		tlsConfig.Certificates = []tls.Certificate{cert}
 
 		// Arrange for server to verify the clients' certificates.
 		logCxt.Info("Will verify client certificates")
 		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
-		caPEMBlock, tlsErr := os.ReadFile(s.config.CAFile)
-		if tlsErr != nil {
-			logCxt.WithError(tlsErr).Panic("Failed to read CA data")
-		}
-		tlsConfig.ClientCAs = x509.NewCertPool()
-		ok := tlsConfig.ClientCAs.AppendCertsFromPEM(caPEMBlock)
-		if !ok {
-			logCxt.Panic("Failed to add CA data to pool")
-		}
-		tlsConfig.VerifyPeerCertificate = tlsutils.CertificateVerifier(
+		tlsConfig.VerifyPeerCertificate = tlsutils.DynamicCertificateVerifier(
 			logCxt,
-			tlsConfig.ClientCAs,
+			certs.Roots,
 			s.config.ClientCN,
 			s.config.ClientURISAN,
 		)
+		// The certificate and CA bundle are fetched per-handshake so that rotated files
+		// take effect for new connections without a restart.
+		tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := tlsConfig.Clone()
+			c.GetConfigForClient = nil
+			c.Certificates = []tls.Certificate{*certs.Certificate()}
+			c.ClientCAs = certs.Roots()
+			return c, nil
+		}
+		// tls.Listen insists on a certificate being configured up front.
+		tlsConfig.Certificates = []tls.Certificate{*certs.Certificate()}
+
+		s.Finished.Add(1)
+		go func() {
+			defer s.Finished.Done()
+			certs.Run(cxt)
+		}()
+		s.Finished.Add(1)
+		go s.recycleUntrustedConnections(cxt, certs)
 
 		laddr := fmt.Sprintf("0.0.0.0:%v", s.config.ListenPort())
 		l, err = tls.Listen("tcp", laddr, tlsConfig)
@@ -635,6 +657,50 @@ func (s *Server) TerminateRandomConnection(logCtx *log.Entry, reason string) boo
 	return false
 }
 
+// recycleUntrustedConnections closes TLS connections whose client certificate would no longer
+// be accepted, either because it has expired or because the CA bundle was rotated.  The client
+// then reconnects and re-handshakes with its current certificate.
+func (s *Server) recycleUntrustedConnections(cxt context.Context, certs *tlsutils.CertWatcher) {
+	defer s.Finished.Done()
+	logCxt := log.WithField("thread", "certRecycler")
+	rotatedC := make(chan struct{}, 1)
+	certs.OnChange(func() {
+		select {
+		case rotatedC <- struct{}{}:
+		default:
+		}
+	})
+	ticker := time.NewTicker(certs.ResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cxt.Done():
+			return
+		case <-rotatedC:
+		case <-ticker.C:
+		}
+		roots := certs.Roots()
+		s.lock.Lock()
+		for connID, conn := range s.connIDToConn {
+			tlsConn, ok := conn.conn.(*tls.Conn)
+			if !ok {
+				continue
+			}
+			state := tlsConn.ConnectionState()
+			if !state.HandshakeComplete {
+				continue
+			}
+			if err := tlsutils.VerifyPeerChain(state.PeerCertificates, roots); err != nil {
+				logCxt.WithError(err).WithField("connID", connID).Info(
+					"Closing connection; reason: client certificate no longer trusted.")
+				conn.cancelCxt()
+				counterNumConnectionsRecycled.Inc()
+			}
+		}
+		s.lock.Unlock()
+	}
+}
+
 func (s *Server) reportHealth() {
 	if s.config.HealthAggregator != nil {
 		s.config.HealthAggregator.Report(healthName, &health.HealthReport{Live: true})
@@ -686,6 +752,7 @@ type connection struct {
 	readC                chan interface{}
 
 	logCxt                       *log.Entry
+	clientHostname               string
 	chosenCompression            syncproto.CompressionAlgorithm
 	clientSupportsDecoderRestart bool
 
@@ -914,6 +981,7 @@ func (h *connection) doHandshake() error {
 		syncerType = syncproto.SyncerTypeFelix
 	}
 	h.syncerType = syncerType
+	h.clientHostname = hello.Hostname
 	h.logCxt = h.logCxt.WithField("type", syncerType)
 	h.perSyncerConnMetrics = h.allMetrics[syncerType]
 	desiredSyncerCache := h.allCaches[syncerType]
@@ -1210,6 +1278,7 @@ func (h *connection) streamSnapshotToClient(logCxt *log.Entry, breadcrumb *snapc
 		h.cxt,
 		h.logCxt.WithField("destination", "direct to client"),
 		breadcrumb,
+		h.clientHostname,
 		h.sendMsg,
 		h.config.MaxMessageSize,
 	)
@@ -1221,11 +1290,56 @@ func (h *connection) streamSnapshotToClient(logCxt *log.Entry, breadcrumb *snapc
 	return nil
 }
 
+// snapshotTier is the priority with which a KV is sent as part of a snapshot; lower tiers are sent first.
+type snapshotTier int
+
+const (
+	// snapshotTierConfig covers the ready flag, global config and everything that belongs to the client's
+	// own node (its config, host endpoints and workload endpoints).
+	snapshotTierConfig snapshotTier = iota
+	// snapshotTierPolicy covers tiers, policies, profiles, IP pools and the host endpoints and per-host
+	// config of all nodes.  Together with the config tier, that's enough for a client to program its
+	// host endpoints and failsafe rules.
+	snapshotTierPolicy
+	// snapshotTierEndpoints covers the remaining per-host resources, such as remote workload endpoints.
+	snapshotTierEndpoints
+	// snapshotTierBulk covers everything else, such as network sets, IPAM blocks and services.
+	snapshotTierBulk
+
+	numSnapshotTiers
+)
+
+// snapshotTierForKey returns the snapshot tier for the given (default path) key.  hostname is the name of
+// the client's node, it may be empty if the snapshot is shared by all clients.
+func snapshotTierForKey(hostname, key string) snapshotTier {
+	switch {
+	case key == "/calico/v1/Ready",
+		strings.HasPrefix(key, "/calico/v1/config/"):
+		return snapshotTierConfig
+	case strings.HasPrefix(key, "/calico/v1/policy/"),
+		strings.HasPrefix(key, "/calico/v1/ipam/"):
+		return snapshotTierPolicy
+	case strings.HasPrefix(key, "/calico/v1/host/"):
+		host, rest, _ := strings.Cut(strings.TrimPrefix(key, "/calico/v1/host/"), "/")
+		if hostname != "" && host == hostname {
+			return snapshotTierConfig
+		}
+		if strings.HasPrefix(rest, "endpoint/") || strings.HasPrefix(rest, "config/") {
+			return snapshotTierPolicy
+		}
+		return snapshotTierEndpoints
+	}
+	return snapshotTierBulk
+}
+
 // writeSnapshotMessages chunks the given breadcrumb up into syncproto.MsgKVs objects and calls writeMsg for each one.
+// The KVs are sent in snapshotTier order; KVs that belong to the given hostname are prioritised.  Pass an empty
+// hostname when the snapshot is to be shared between clients.
 func writeSnapshotMessages(
 	ctx context.Context,
 	logCxt *log.Entry,
 	breadcrumb *snapcache.Breadcrumb,
+	hostname string,
 	writeMsg func(any) error,
 	maxMsgSize int,
 ) (err error) {
@@ -1254,23 +1368,31 @@ func writeSnapshotMessages(
 		return err
 	}
 
-	breadcrumb.KVs.Ascend(func(entry syncproto.SerializedUpdate) bool {
-		if ctx.Err() != nil {
-			err = ctx.Err()
-			return false
-		}
-		kvs = append(kvs, entry)
-		if len(kvs) >= maxMsgSize {
-			// Buffer is full, send the next batch.
-			err = writeKVs()
-			if err != nil {
+	// Send the snapshot in priority order so that the resources that a (re)starting client needs to
+	// protect its host and give its pods connectivity arrive before the bulk of the datastore.  Within
+	// each tier, we keep the (deterministic) key order of the btree.
+	for tier := snapshotTier(0); tier < numSnapshotTiers; tier++ {
+		breadcrumb.KVs.Ascend(func(entry syncproto.SerializedUpdate) bool {
+			if ctx.Err() != nil {
+				err = ctx.Err()
 				return false
 			}
+			if snapshotTierForKey(hostname, entry.Key) != tier {
+				return true
+			}
+			kvs = append(kvs, entry)
+			if len(kvs) >= maxMsgSize {
+				// Buffer is full, send the next batch.
+				err = writeKVs()
+				if err != nil {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return
 		}
-		return true
-	})
-	if err != nil {
-		return
 	}
 
 	err = writeKVs()
@@ -47,6 +47,7 @@ var _ = Describe("With zero config", func() {
 			ShutdownMaxDropInterval:        time.Second,
 			MaxConns:                       math.MaxInt32,
 			Port:                           5473,
+			CertResyncInterval:             30 * time.Second,
 		}))
 	})
 	It("should convert random port to 0", func() {
@@ -0,0 +1,226 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// certReloadDebounce gives whoever is rotating the files (the SPIRE agent, the kubelet
+	// updating a mounted secret) a moment to finish writing all of them before we reload.
+	certReloadDebounce = 500 * time.Millisecond
+	// DefaultCertResyncInterval is how often CertWatcher reloads the files even if it hasn't
+	// seen a file system event, in case an event was missed.
+	DefaultCertResyncInterval = 30 * time.Second
+)
+
+// CertWatcher holds a key pair and a CA bundle that were loaded from files and reloads them
+// whenever the files change.  This allows certificates that are rotated on disk, such as
+// SPIFFE X.509-SVIDs written by the SPIRE agent, to be picked up without a restart.
+type CertWatcher struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	ResyncInterval time.Duration
+
+	lock     sync.RWMutex
+	raw      [][]byte
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+	onChange []func()
+}
+
+func NewCertWatcher(certFile, keyFile, caFile string) (*CertWatcher, error) {
+	w := &CertWatcher{
+		certFile:       certFile,
+		keyFile:        keyFile,
+		caFile:         caFile,
+		ResyncInterval: DefaultCertResyncInterval,
+	}
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// OnChange registers a callback that is called, from the watcher's goroutine, after a
+// changed key pair or CA bundle has been loaded.
+func (w *CertWatcher) OnChange(f func()) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.onChange = append(w.onChange, f)
+}
+
+// Reload reads the files and, if their content has changed, replaces the current key pair
+// and CA bundle.  If the files cannot be loaded, the previous material is kept.
+func (w *CertWatcher) Reload() (changed bool, err error) {
+	var raw [][]byte
+	for _, f := range []string{w.certFile, w.keyFile, w.caFile} {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return false, err
+		}
+		raw = append(raw, data)
+	}
+
+	w.lock.RLock()
+	unchanged := w.raw != nil
+	for i := range w.raw {
+		unchanged = unchanged && bytes.Equal(raw[i], w.raw[i])
+	}
+	w.lock.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(raw[0], raw[1])
+	if err != nil {
+		return false, fmt.Errorf("failed to load certificate and key: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(raw[2]) {
+		return false, errors.New("failed to add CA data to pool")
+	}
+
+	w.lock.Lock()
+	w.raw = raw
+	w.cert = &cert
+	w.roots = roots
+	w.lock.Unlock()
+	return true, nil
+}
+
+// Certificate returns the current key pair.
+func (w *CertWatcher) Certificate() *tls.Certificate {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.cert
+}
+
+// Roots returns the current CA bundle.
+func (w *CertWatcher) Roots() *x509.CertPool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.roots
+}
+
+// GetCertificate is suitable for use as tls.Config.GetCertificate.
+func (w *CertWatcher) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return w.Certificate(), nil
+}
+
+// GetClientCertificate is suitable for use as tls.Config.GetClientCertificate.
+func (w *CertWatcher) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return w.Certificate(), nil
+}
+
+// Run watches the directories that contain the files and reloads them after they change,
+// until the context is done.  Directories are watched, rather than the files themselves,
+// because mounted secrets are updated by swapping a symlink.
+func (w *CertWatcher) Run(ctx context.Context) {
+	logCxt := log.WithFields(log.Fields{
+		"certFile": w.certFile,
+		"keyFile":  w.keyFile,
+		"caFile":   w.caFile,
+	})
+
+	var events chan fsnotify.Event
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logCxt.WithError(err).Warn("Failed to create file watcher, falling back to periodic reload.")
+	} else {
+		defer fsWatcher.Close()
+		events = fsWatcher.Events
+		dirs := map[string]bool{}
+		for _, f := range []string{w.certFile, w.keyFile, w.caFile} {
+			dirs[filepath.Dir(f)] = true
+		}
+		for dir := range dirs {
+			if err := fsWatcher.Add(dir); err != nil {
+				logCxt.WithError(err).WithField("dir", dir).Warn(
+					"Failed to watch directory, relying on periodic reload.")
+			}
+		}
+	}
+
+	resyncTicker := time.NewTicker(w.ResyncInterval)
+	defer resyncTicker.Stop()
+	var debounceC <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-events:
+			if debounceC == nil {
+				debounceC = time.After(certReloadDebounce)
+			}
+			continue
+		case <-debounceC:
+			debounceC = nil
+		case <-resyncTicker.C:
+		}
+
+		changed, err := w.Reload()
+		if err != nil {
+			logCxt.WithError(err).Warn("Failed to reload TLS certificates, continuing to use the old ones.")
+			continue
+		}
+		if !changed {
+			continue
+		}
+		logCxt.Info("Loaded rotated TLS certificates.")
+		w.lock.RLock()
+		callbacks := w.onChange
+		w.lock.RUnlock()
+		for _, f := range callbacks {
+			f()
+		}
+	}
+}
+
+// VerifyPeerChain re-verifies a peer certificate chain, as obtained from
+// tls.ConnectionState.PeerCertificates, against the given CA bundle at the current time.
+// It is used to find established connections that would no longer pass the handshake
+// because the CA bundle was rotated or the peer's certificate has expired.
+func VerifyPeerChain(certs []*x509.Certificate, roots *x509.CertPool) error {
+	if len(certs) == 0 {
+		return errors.New("no peer certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
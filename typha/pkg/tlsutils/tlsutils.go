@@ -21,9 +21,11 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"math/big"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -32,11 +34,18 @@ import (
 // Common code for verifying whether a peer certificate has a required Common Name and/or a required
 // URI SAN.
 func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, requiredURISAN string) func([][]byte, [][]*x509.Certificate) error {
+	log.WithField("roots", roots).Debug("Using static CA bundle")
+	return DynamicCertificateVerifier(logCxt, func() *x509.CertPool { return roots }, requiredCN, requiredURISAN)
+}
+
+// DynamicCertificateVerifier is like CertificateVerifier but looks up the trusted CAs on each
+// verification, so that a rotated CA bundle takes effect for new connections.
+func DynamicCertificateVerifier(logCxt *log.Entry, getRoots func() *x509.CertPool, requiredCN, requiredURISAN string) func([][]byte, [][]*x509.Certificate) error {
 	log.WithFields(log.Fields{
-		"roots":          roots,
 		"requiredCN":     requiredCN,
 		"requiredURISAN": requiredURISAN,
 	}).Info("Make certificate verifier")
+	requireSVID := strings.HasPrefix(requiredURISAN, spiffeScheme+"://")
 	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 		if len(verifiedChains) == 0 {
 			// We haven't yet verified that the peer certificate is signed by a trusted
@@ -54,7 +63,7 @@ func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, re
 			}
 
 			opts := x509.VerifyOptions{
-				Roots:         roots,
+				Roots:         getRoots(),
 				Intermediates: x509.NewCertPool(),
 			}
 
@@ -91,6 +100,16 @@ func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, re
 			}
 		}
 
+		var svidErr error
+		if requiredURIFound && requireSVID {
+			// The required identity is a SPIFFE ID, so it only counts if the peer
+			// presented a valid X.509-SVID.
+			if svidErr = validateX509SVID(leafCert); svidErr != nil {
+				logCxt.WithError(svidErr).Info("Ignoring URI SAN of invalid X.509-SVID")
+				requiredURIFound = false
+			}
+		}
+
 		if requiredCN != "" && requiredURISAN != "" {
 			if !(requiredCNFound || requiredURIFound) {
 				return errors.New("Peer certificate does not have required CN or URI SAN")
@@ -100,6 +119,9 @@ func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, re
 				return errors.New("Peer certificate does not have required CN")
 			}
 		} else if requiredURISAN != "" {
+			if svidErr != nil {
+				return svidErr
+			}
 			if !requiredURIFound {
 				return errors.New("Peer certificate does not have required URI SAN")
 			}
@@ -111,6 +133,26 @@ func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, re
 	}
 }
 
+const spiffeScheme = "spiffe"
+
+// validateX509SVID checks the constraints that the SPIFFE X.509-SVID specification puts on a
+// leaf certificate: it must carry exactly one URI SAN, that SAN must be a SPIFFE ID, and the
+// certificate must not be a CA.
+func validateX509SVID(leaf *x509.Certificate) error {
+	if len(leaf.URIs) != 1 {
+		return fmt.Errorf("Peer certificate is not a valid X.509-SVID: it has %d URI SANs, expected exactly 1", len(leaf.URIs))
+	}
+	id := leaf.URIs[0]
+	if id.Scheme != spiffeScheme || id.Host == "" || id.User != nil || id.Port() != "" ||
+		id.RawQuery != "" || id.Fragment != "" {
+		return fmt.Errorf("Peer certificate is not a valid X.509-SVID: %q is not a SPIFFE ID", id)
+	}
+	if leaf.IsCA {
+		return errors.New("Peer certificate is not a valid X.509-SVID: it is a CA certificate")
+	}
+	return nil
+}
+
 // The following certificate generators panic if they hit any error.  This is a bit poor, but OK in
 // practice because they are only used by test code.
 func PanicIfErr(err error) {